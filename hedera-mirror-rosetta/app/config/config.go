@@ -28,6 +28,7 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
 	"github.com/hashgraph/hedera-sdk-go/v2"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -99,10 +100,39 @@ func LoadConfig() (*Config, error) {
 		rosettaConfig.Nodes = nodeMap
 	}
 
+	if err := validateSystemAccounts(rosettaConfig.SystemAccounts); err != nil {
+		return nil, err
+	}
+
+	for _, version := range rosettaConfig.Http.SpecVersions {
+		if !types.IsSpecVersionSupported(version) {
+			return nil, errors.Errorf("Unsupported rosetta spec version %s, the requests and responses of which are "+
+				"not implemented", version)
+		}
+	}
+
+	if rosettaConfig.Admin.Enabled && rosettaConfig.Admin.Token == "" {
+		return nil, errors.Errorf("Admin token must be set when the admin endpoints are enabled")
+	}
+
+	if rosettaConfig.Signer.Enabled && !rosettaConfig.Admin.Enabled {
+		return nil, errors.Errorf("Admin endpoints must be enabled for the server-side signer, which only signs the " +
+			"requests with the admin token")
+	}
+
 	var password = rosettaConfig.Db.Password
+	var secret = rosettaConfig.Notifier.Secret
+	var token = rosettaConfig.Admin.Token
+	var signerKeys = rosettaConfig.Signer.Keys
 	rosettaConfig.Db.Password = "<omitted>"
+	rosettaConfig.Notifier.Secret = "<omitted>"
+	rosettaConfig.Admin.Token = "<omitted>"
+	rosettaConfig.Signer.Keys = nil
 	log.Infof("Using configuration: %+v", rosettaConfig)
 	rosettaConfig.Db.Password = password
+	rosettaConfig.Notifier.Secret = secret
+	rosettaConfig.Admin.Token = token
+	rosettaConfig.Signer.Keys = signerKeys
 
 	return rosettaConfig, nil
 }
@@ -142,6 +172,32 @@ func mergeExternalConfigFile(v *viper.Viper) error {
 	return nil
 }
 
+func validateSystemAccounts(systemAccounts SystemAccounts) error {
+	accounts := []string{
+		systemAccounts.FeeCollection,
+		systemAccounts.NodeReward,
+		systemAccounts.StakingReward,
+		systemAccounts.Treasury,
+	}
+	seen := make(map[string]bool)
+	for _, account := range accounts {
+		if account == "" {
+			continue
+		}
+
+		if _, err := hedera.AccountIDFromString(account); err != nil {
+			return errors.Errorf("Invalid system account %s: %s", account, err)
+		}
+
+		if seen[account] {
+			return errors.Errorf("Duplicate system account %s", account)
+		}
+		seen[account] = true
+	}
+
+	return nil
+}
+
 func nodeMapDecodeHookFunc(from, to reflect.Type, data interface{}) (interface{}, error) {
 	if to != reflect.TypeOf(NodeMap{}) {
 		return data, nil
@@ -40,6 +40,36 @@ hedera:
     rosetta:
       nodes:
         "192.168.0.1:50211": 0.3`
+	invalidYamlDuplicateSystemAccount = `
+hedera:
+  mirror:
+    rosetta:
+      systemAccounts:
+        nodeReward: 0.0.800`
+	invalidYamlIncorrectSystemAccount = `
+hedera:
+  mirror:
+    rosetta:
+      systemAccounts:
+        treasury: 2`
+	invalidYamlAdminTokenMissing = `
+hedera:
+  mirror:
+    rosetta:
+      admin:
+        enabled: true`
+	invalidYamlSignerWithoutAdmin = `
+hedera:
+  mirror:
+    rosetta:
+      signer:
+        enabled: true`
+	invalidYamlUnsupportedSpecVersion = `
+hedera:
+  mirror:
+    rosetta:
+      http:
+        specVersions: [1.5.0]`
 	testConfigFilename = "application.yml"
 	yml1               = `
 hedera:
@@ -160,6 +190,11 @@ func TestLoadCustomConfigInvalidYaml(t *testing.T) {
 		{name: "invalid yaml", content: invalidYaml},
 		{name: "invalid yaml from cwd", content: invalidYaml, fromCwd: true},
 		{name: "incorrect account id", content: invalidYamlIncorrectAccountId},
+		{name: "duplicate system account", content: invalidYamlDuplicateSystemAccount},
+		{name: "incorrect system account", content: invalidYamlIncorrectSystemAccount},
+		{name: "admin token missing", content: invalidYamlAdminTokenMissing},
+		{name: "signer without admin", content: invalidYamlSignerWithoutAdmin},
+		{name: "unsupported spec version", content: invalidYamlUnsupportedSpecVersion},
 	}
 
 	for _, tt := range tests {
@@ -293,6 +328,15 @@ func TestNodeMapDecodeHookFunc(t *testing.T) {
 	}
 }
 
+func TestSystemAccountsToMap(t *testing.T) {
+	systemAccounts := SystemAccounts{FeeCollection: "0.0.98", StakingReward: "0.0.800"}
+	expected := map[string]string{
+		"0.0.98":  SystemAccountFeeCollection,
+		"0.0.800": SystemAccountStakingReward,
+	}
+	assert.Equal(t, expected, systemAccounts.ToMap())
+}
+
 func createYamlConfigFile(content string, t *testing.T) (string, string) {
 	tempDir, err := ioutil.TempDir("", "rosetta")
 	if err != nil {
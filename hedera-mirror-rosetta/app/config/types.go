@@ -22,44 +22,256 @@ package config
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/hashgraph/hedera-sdk-go/v2"
 )
 
-const EntityCacheKey = "entity"
+const (
+	AliasCacheKey       = "alias"
+	EntityCacheKey      = "entity"
+	TransactionCacheKey = "transaction"
+)
+
+const (
+	SystemAccountFeeCollection = "fee_collection"
+	SystemAccountNodeReward    = "node_reward"
+	SystemAccountStakingReward = "staking_reward"
+	SystemAccountTreasury      = "treasury"
+)
+
+// dsnValueEscaper escapes the backslashes and single quotes in a quoted DSN value
+var dsnValueEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
 
 type Config struct {
-	Cache       map[string]Cache
-	Db          Db
-	Feature     Feature
-	Http        Http
-	Log         Log
-	Network     string
-	Nodes       NodeMap
-	NodeVersion string `yaml:"nodeVersion"`
-	Online      bool
-	Port        uint16
-	Realm       int64
-	Shard       int64
+	// AccountIdentifierFormat is the format of the account identifiers, either DOTTED or STRUCTURED
+	AccountIdentifierFormat string `yaml:"accountIdentifierFormat"`
+	Admin                   Admin
+	AutoDiscovery           bool `yaml:"autoDiscovery"`
+	Backend                 Backend
+	BalanceReplay           BalanceReplay `yaml:"balanceReplay"`
+	Block                   Block
+	Cache                   map[string]Cache
+	Construction            Construction
+	Db                      Db
+	Feature                 Feature
+	Grpc                    Grpc
+	// HbarCurrency is the representation of the hbar currency, either HBAR with 8 decimals or TINYBAR with 0 decimals
+	HbarCurrency        string `yaml:"hbarCurrency"`
+	Http                Http
+	InvariantCheck      bool `yaml:"invariantCheck"`
+	Log                 Log
+	Network             string
+	NetworkParameters   NetworkParameters `yaml:"networkParameters"`
+	Nodes               NodeMap
+	NodeVersion         string `yaml:"nodeVersion"`
+	Notifier            Notifier
+	Online              bool
+	OperationTypeNaming string `yaml:"operationTypeNaming"`
+	Pagination          Pagination
+	Port                uint16
+	Realm               int64
+	Redaction           Redaction
+	Shard               int64
+	Signer              Signer
+	Slo                 Slo
+	Stream              Stream
+	Submit              Submit
+	// SuppressEmptyOperations suppresses the zero-amount transfer operations and the metadata-only operations
+	SuppressEmptyOperations bool           `yaml:"suppressEmptyOperations"`
+	SystemAccounts          SystemAccounts `yaml:"systemAccounts"`
+	Usage                   Usage
+}
+
+// Admin configures the admin endpoints, which require the token as the bearer token
+type Admin struct {
+	Enabled bool
+	Token   string
+}
+
+// Backend configures the persistence backend the rosetta API is served from in online mode
+type Backend struct {
+	RecordStream BackendRecordStream `yaml:"recordStream"`
+	Rest         BackendRest
+	// Type is the type of the backend, either postgres or rest. Other backends can be added by implementing
+	// interfaces.Backend
+	Type string
+}
+
+// BackendRecordStream configures the experimental fallback serving the most recent blocks read directly from the
+// record stream files in the S3 or GCS bucket while the backend lags behind
+type BackendRecordStream struct {
+	// AccessKey and SecretKey are the HMAC credentials of the bucket, the requests are anonymous if not set
+	AccessKey string `yaml:"accessKey"`
+	// BucketName is the name of the bucket, e.g., hedera-mainnet-streams
+	BucketName string `yaml:"bucketName"`
+	// CloudProvider is the provider of the bucket, either S3 or GCP
+	CloudProvider string `yaml:"cloudProvider"`
+	Enabled       bool
+	// EndpointOverride overrides the endpoint of the cloud provider, e.g., for an S3 compatible storage
+	EndpointOverride string `yaml:"endpointOverride"`
+	// GcpProjectId is the project billed for the requests to a requester pays GCS bucket
+	GcpProjectId string `yaml:"gcpProjectId"`
+	// MaxBlocks is the max number of blocks read from the bucket ahead of the backend
+	MaxBlocks int `yaml:"maxBlocks"`
+	// NodeAccountId is the account id of the node whose record stream files are read
+	NodeAccountId string `yaml:"nodeAccountId"`
+	// PollInterval is the interval between the polls of the bucket for new record stream files
+	PollInterval time.Duration `yaml:"pollInterval"`
+	Region       string
+	SecretKey    string `yaml:"secretKey"`
+	// Timeout is the timeout of a request to the bucket
+	Timeout time.Duration
+}
+
+// BackendRest configures the backend reading from the mirror node REST API, for deployments without database access
+type BackendRest struct {
+	// Backoff is the backoff before the first retry of a rate limited or failed request without a Retry-After header,
+	// doubled for each following retry
+	Backoff time.Duration
+	// BaseUrl is the url of the mirror node REST API, e.g., https://mainnet-public.mirrornode.hedera.com
+	BaseUrl string `yaml:"baseUrl"`
+	// CacheMaxSize is the max number of cached responses, the responses are not cached if not positive
+	CacheMaxSize int `yaml:"cacheMaxSize"`
+	// CacheTtl is the time to cache a response
+	CacheTtl time.Duration `yaml:"cacheTtl"`
+	// MaxAttempts is the max number of attempts of a request rate limited with 429, failed with 5xx, or a network error
+	MaxAttempts int `yaml:"maxAttempts"`
+	// RequestsPerSecond is the max rate of the requests sent to the REST API, 0 for unlimited
+	RequestsPerSecond float64 `yaml:"requestsPerSecond"`
+	// Timeout is the timeout of a request
+	Timeout time.Duration
+}
+
+// BalanceReplay bounds the replay of an account's transfers after the nearest balance snapshot when computing its
+// historical balance, so the balance of an account with millions of transfers can't tie up the database for minutes
+type BalanceReplay struct {
+	// MaxTransfers is the max number of the account's crypto and token transfers replayed, 0 for unlimited
+	MaxTransfers int64 `yaml:"maxTransfers"`
+	// MaxWindow is the max time between the nearest balance snapshot and the requested block, 0 for unlimited
+	MaxWindow time.Duration `yaml:"maxWindow"`
+}
+
+type Block struct {
+	// BuildTimeout is the timeout of building a /block response, which is shared by the concurrent requests of the
+	// same block and detached from their cancellation
+	BuildTimeout time.Duration `yaml:"buildTimeout"`
+	Cache        BlockCache
+	// FastEncoding encodes the /block and /block/transaction responses with the fast block encoder instead of
+	// encoding/json, the responses are the same
+	FastEncoding    bool     `yaml:"fastEncoding"`
+	MaxOperations   int64    `yaml:"maxOperations"`
+	TrackedAccounts []string `yaml:"trackedAccounts"`
+}
+
+type BlockCache struct {
+	Enabled bool
+	// MaxEntries is the max number of cached blocks, 0 for unlimited
+	MaxEntries int `yaml:"maxEntries"`
+	// MaxSize is the max total size in bytes of the cached blocks, 0 for unlimited
+	MaxSize int64 `yaml:"maxSize"`
+	Path    string
 }
 
 type Cache struct {
-	MaxSize int `yaml:"maxSize"`
+	// InvalidationInterval is the interval to poll the changed entries, only used by the alias cache
+	InvalidationInterval time.Duration `yaml:"invalidationInterval"`
+	MaxSize              int           `yaml:"maxSize"`
+	// NegativeTtl is the time to cache the not found results, only used by the alias cache
+	NegativeTtl time.Duration `yaml:"negativeTtl"`
+}
+
+// Construction configures the construction API
+type Construction struct {
+	// FrozenTime is the valid start in nanoseconds since the epoch of the transactions built without one set in the
+	// metadata, so the payloads are reproducible in tests. 0 to use the current time
+	FrozenTime        int64             `yaml:"frozenTime"`
+	SubmissionWatcher SubmissionWatcher `yaml:"submissionWatcher"`
 }
 
 type Db struct {
-	Host             string
-	Name             string
-	Password         string
-	Pool             Pool
-	Port             uint16
-	StatementTimeout uint `yaml:"statementTimeout"`
-	Username         string
+	Events         DbEvents
+	FaultInjection DbFaultInjection `yaml:"faultInjection"`
+	Host           string
+	// IndexCheckInterval is the interval between the checks of the indexes the queries depend on. The indexes are only
+	// checked at startup if not positive
+	IndexCheckInterval time.Duration `yaml:"indexCheckInterval"`
+	Name               string
+	Password           string
+	Pool               Pool
+	Port               uint16
+	RangeSplit         DbRangeSplit `yaml:"rangeSplit"`
+	Replica            DbReplica
+	Retry              DbRetry
+	// Schema is the comma separated list of schemas set as the search_path of the connections, so multiple networks or
+	// environments can share one database cluster with isolated schemas. The user's default search_path if empty
+	Schema string
+	// SlowQueryThreshold is the duration above which a query attempt is logged with its parameters and counted as
+	// slow. The slow query log is disabled if not positive
+	SlowQueryThreshold time.Duration `yaml:"slowQueryThreshold"`
+	StatementTimeout   uint          `yaml:"statementTimeout"`
+	Username           string
+}
+
+// DbEvents configures the hooks the lifecycle events of the database connections are emitted to, i.e., connected,
+// failover detected, reconnected, and replica promoted
+type DbEvents struct {
+	Log     bool
+	Metrics bool
+	// Secret is the shared secret to sign the webhook requests with, the requests are not signed if empty
+	Secret  string
+	Timeout time.Duration
+	// Webhook is the url the events are posted to as json, disabled if empty
+	Webhook string
+}
+
+// DbFaultInjection configures the faults injected into the queries. It only takes effect in a binary built with the
+// faultinjection build tag
+type DbFaultInjection struct {
+	ErrorRate         float64       `yaml:"errorRate"`
+	Latency           time.Duration `yaml:"latency"`
+	PartialResultRate float64       `yaml:"partialResultRate"`
+}
+
+// IsEnabled returns true if any fault is configured
+func (f DbFaultInjection) IsEnabled() bool {
+	return f.ErrorRate > 0 || f.Latency > 0 || f.PartialResultRate > 0
+}
+
+// DbRangeSplit configures splitting the queries of wide timestamp ranges into smaller ranges aligned with the
+// partition boundaries, so each query only touches a single partition or compressed chunk
+type DbRangeSplit struct {
+	MaxConcurrency int `yaml:"maxConcurrency"`
+	// PartitionInterval is the interval of the time partitions in nanoseconds, 0 disables the split
+	PartitionInterval int64 `yaml:"partitionInterval"`
+}
+
+// DbReplica configures the streaming replica the queries are routed to as long as its replay lag is within the
+// threshold. The replica shares the other connection settings of the primary
+type DbReplica struct {
+	CheckInterval time.Duration `yaml:"checkInterval"`
+	// Host is the host of the replica, the replica is disabled if empty
+	Host string
+	// MaxLag is the max number of bytes of WAL the replica can lag behind the primary
+	MaxLag uint64 `yaml:"maxLag"`
+	Port   uint16
+}
+
+// IsEnabled returns if the replica is configured
+func (r DbReplica) IsEnabled() bool {
+	return r.Host != ""
+}
+
+// DbRetry configures the retries of queries failed with transient errors
+type DbRetry struct {
+	MaxAttempts int           `yaml:"maxAttempts"`
+	MaxBackoff  time.Duration `yaml:"maxBackoff"`
+	MinBackoff  time.Duration `yaml:"minBackoff"`
 }
 
 func (db Db) GetDsn() string {
-	return fmt.Sprintf(
+	dsn := fmt.Sprintf(
 		"host=%s port=%d user=%s dbname=%s password=%s sslmode=disable",
 		db.Host,
 		db.Port,
@@ -67,27 +279,207 @@ func (db Db) GetDsn() string {
 		db.Name,
 		db.Password,
 	)
+	if db.Schema != "" {
+		dsn += fmt.Sprintf(" search_path='%s'", dsnValueEscaper.Replace(db.Schema))
+	}
+	return dsn
+}
+
+// GetReplicaConfig returns the connection config of the replica derived from the connection config of the primary
+func (db Db) GetReplicaConfig() Db {
+	replicaConfig := db
+	replicaConfig.Host = db.Replica.Host
+	replicaConfig.Port = db.Replica.Port
+	replicaConfig.Replica = DbReplica{}
+	return replicaConfig
 }
 
 type Feature struct {
 	SubNetworkIdentifier bool `yaml:"subNetworkIdentifier"`
 }
 
+// Grpc configures the gRPC server of the data API
+type Grpc struct {
+	Enabled bool
+	Port    uint16
+}
+
 type Http struct {
-	IdleTimeout       time.Duration `yaml:"idleTimeout"`
-	ReadTimeout       time.Duration `yaml:"readTimeout"`
-	ReadHeaderTimeout time.Duration `yaml:"readHeaderTimeout"`
-	WriteTimeout      time.Duration `yaml:"writeTimeout"`
+	EndpointTimeouts      map[string]time.Duration `yaml:"endpointTimeouts"`
+	IdleTimeout           time.Duration            `yaml:"idleTimeout"`
+	MaxConcurrentRequests int                      `yaml:"maxConcurrentRequests"`
+	ReadTimeout           time.Duration            `yaml:"readTimeout"`
+	ReadHeaderTimeout     time.Duration            `yaml:"readHeaderTimeout"`
+	RetryAfter            time.Duration            `yaml:"retryAfter"`
+	SpecVersions          []string                 `yaml:"specVersions"`
+	WriteTimeout          time.Duration            `yaml:"writeTimeout"`
 }
 
 type Log struct {
-	Level string
+	Format   string
+	Level    string
+	Levels   map[string]string
+	Sampling LogSampling
+}
+
+type LogSampling struct {
+	Enabled    bool
+	Initial    int
+	Interval   time.Duration
+	Thereafter int
+}
+
+// NetworkParameters configures the network parameters exposed in the /network/options metadata. In online mode, the
+// values set in the application properties system file of the network take precedence
+type NetworkParameters struct {
+	// LedgerId is the hex encoded ledger id. If empty, it's derived from the network name for the public networks
+	LedgerId          string `yaml:"ledgerId"`
+	MaxMemoSize       int64  `yaml:"maxMemoSize"`
+	MaxTokenTransfers int64  `yaml:"maxTokenTransfers"`
+	MaxTransfers      int64  `yaml:"maxTransfers"`
 }
 
 type NodeMap map[string]hedera.AccountID
 
+// Notifier configures the webhook notifications of the transactions of the tracked accounts
+type Notifier struct {
+	Accounts     []string
+	Backoff      time.Duration
+	Enabled      bool
+	MaxAttempts  int           `yaml:"maxAttempts"`
+	PollInterval time.Duration `yaml:"pollInterval"`
+	Secret       string
+	Timeout      time.Duration
+	Webhooks     []string
+}
+
+// Pagination configures the opaque cursor of the paginated endpoints, i.e., /search/transactions and the list /call
+// methods
+type Pagination struct {
+	// CursorTtl is how long the cursor returned with a page can be used to get the next page
+	CursorTtl time.Duration `yaml:"cursorTtl"`
+}
+
 type Pool struct {
 	MaxIdleConnections int `yaml:"maxIdleConnections"`
 	MaxLifetime        int `yaml:"maxLifetime"`
 	MaxOpenConnections int `yaml:"maxOpenConnections"`
 }
+
+// SystemAccounts holds the network's special accounts in shard.realm.num format
+type SystemAccounts struct {
+	FeeCollection string `yaml:"feeCollection"`
+	NodeReward    string `yaml:"nodeReward"`
+	StakingReward string `yaml:"stakingReward"`
+	Treasury      string
+}
+
+// ToMap returns a map of the non-empty system accounts with the account as the key and its role as the value
+func (s SystemAccounts) ToMap() map[string]string {
+	accounts := make(map[string]string)
+	for account, role := range map[string]string{
+		s.FeeCollection: SystemAccountFeeCollection,
+		s.NodeReward:    SystemAccountNodeReward,
+		s.StakingReward: SystemAccountStakingReward,
+		s.Treasury:      SystemAccountTreasury,
+	} {
+		if account != "" {
+			accounts[account] = role
+		}
+	}
+	return accounts
+}
+
+// Redaction configures the privacy-sensitive metadata stripped from the rosetta API responses for the deployments subject
+// to data minimization policies. The accounts and the amounts are always kept intact
+type Redaction struct {
+	// Aliases strips the aliases and the evm addresses of the accounts
+	Aliases bool
+	// Memos strips the transaction, the account, and the token memos
+	Memos bool
+	// TopicMessages strips the HCS message contents, running hashes, and chunk info
+	TopicMessages bool `yaml:"topicMessages"`
+}
+
+// IsEnabled returns true if any metadata is redacted
+func (r Redaction) IsEnabled() bool {
+	return r.Aliases || r.Memos || r.TopicMessages
+}
+
+// Pkcs11 configures the HSM token the PKCS#11 signer logs in to
+type Pkcs11 struct {
+	// Library is the path of the PKCS#11 module of the HSM
+	Library    string
+	Pin        string
+	TokenLabel string `yaml:"tokenLabel"`
+}
+
+// Signer configures the server-side signing of /construction/combine
+type Signer struct {
+	Enabled bool
+	// Keys maps the accounts in shard.realm.num format to their keys. The key is the hex encoded ED25519 private key
+	// for the local signer, the key id or ARN for the AWS KMS signer, the crypto key version resource name for the GCP
+	// KMS signer, and the key pair label for the PKCS#11 signer
+	Keys   map[string]string
+	Pkcs11 Pkcs11
+	Type   string
+}
+
+// Slo configures the availability service level objectives of the rosetta API endpoints. A request counts against the
+// error budget of its endpoint if it's rejected or fails with a server-side error
+type Slo struct {
+	Enabled bool
+	// Target is the availability target of the endpoints without their own target, e.g., 0.999
+	Target float64
+	// Targets are the availability targets keyed by the endpoint path
+	Targets map[string]float64
+	// Windows are the sliding windows the success ratios and burn rates are computed over
+	Windows []time.Duration
+}
+
+// Stream configures the server-sent events stream of the new blocks
+type Stream struct {
+	Enabled        bool
+	KeepAlive      time.Duration `yaml:"keepAlive"`
+	MaxDuration    time.Duration `yaml:"maxDuration"`
+	MaxSubscribers int           `yaml:"maxSubscribers"`
+	PollInterval   time.Duration `yaml:"pollInterval"`
+}
+
+type Submit struct {
+	// CertificatePinning submits the transactions over TLS and verifies the node certificates against the certificate
+	// hashes in the address book. Only supported in online mode
+	CertificatePinning bool          `yaml:"certificatePinning"`
+	ConnectTimeout     time.Duration `yaml:"connectTimeout"`
+	// KeepAliveTime is the interval of the keepalive pings on the idle node connections, 0 to disable the pings
+	KeepAliveTime    time.Duration `yaml:"keepAliveTime"`
+	KeepAliveTimeout time.Duration `yaml:"keepAliveTimeout"`
+	// Proxy is the HTTP or SOCKS5 proxy url of the node connections
+	Proxy          string
+	RequestTimeout time.Duration `yaml:"requestTimeout"`
+}
+
+// SubmissionWatcher configures the tracking of the transactions submitted via /construction/submit
+type SubmissionWatcher struct {
+	Enabled bool
+	// MaxSize is the max number of tracked transactions, a submitted transaction is not tracked once reached
+	MaxSize      int           `yaml:"maxSize"`
+	PollInterval time.Duration `yaml:"pollInterval"`
+	// Retention is the time to keep the final status of a tracked transaction
+	Retention time.Duration
+}
+
+// Usage configures the per-client accounting of the requests and bytes served, so the hosted rosetta providers can bill
+// or quota their consumers
+type Usage struct {
+	// ApiKeyHeader is the request header identifying the client by its api key. The clients without the header are
+	// identified by their ip address
+	ApiKeyHeader string `yaml:"apiKeyHeader"`
+	// CsvPath is the file the usage of each export interval is appended to, the csv export is disabled if empty
+	CsvPath string `yaml:"csvPath"`
+	Enabled bool
+	// ExportInterval is how often the usage is exported to the metrics and the csv file
+	ExportInterval time.Duration `yaml:"exportInterval"`
+	// MaxClients is the max number of clients tracked separately, the usage of further clients is accounted to other
+	MaxClients int `yaml:"maxClients"`
+}
@@ -22,6 +22,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -43,3 +44,75 @@ func TestDbGetDsn(t *testing.T) {
 
 	assert.Equal(t, expected, db.GetDsn())
 }
+
+func TestDbGetDsnWithSchema(t *testing.T) {
+	tests := []struct {
+		schema   string
+		expected string
+	}{
+		{schema: "testnet", expected: " search_path='testnet'"},
+		{schema: "testnet, public", expected: " search_path='testnet, public'"},
+		{schema: `it's\`, expected: ` search_path='it\'s\\'`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.schema, func(t *testing.T) {
+			// given
+			db := Db{Host: "127.0.0.1", Name: "mirror_node", Port: 5432, Schema: tt.schema, Username: "mirror_user"}
+			expected := "host=127.0.0.1 port=5432 user=mirror_user dbname=mirror_node password= sslmode=disable" +
+				tt.expected
+
+			// when
+			actual := db.GetDsn()
+
+			// then
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
+
+func TestDbGetReplicaConfig(t *testing.T) {
+	// given
+	db := Db{
+		Host:     "primary",
+		Name:     "mirror_node",
+		Password: "mirror_user_pass",
+		Port:     5432,
+		Replica:  DbReplica{CheckInterval: time.Second, Host: "replica", MaxLag: 1024, Port: 5433},
+		Username: "mirror_user",
+	}
+	expected := Db{
+		Host:     "replica",
+		Name:     "mirror_node",
+		Password: "mirror_user_pass",
+		Port:     5433,
+		Username: "mirror_user",
+	}
+
+	// when
+	actual := db.GetReplicaConfig()
+
+	// then
+	assert.Equal(t, expected, actual)
+	assert.False(t, actual.Replica.IsEnabled())
+	assert.True(t, db.Replica.IsEnabled())
+}
+
+func TestDbFaultInjectionIsEnabled(t *testing.T) {
+	var tests = []struct {
+		name     string
+		faults   DbFaultInjection
+		expected bool
+	}{
+		{name: "disabled"},
+		{name: "error", faults: DbFaultInjection{ErrorRate: 0.1}, expected: true},
+		{name: "latency", faults: DbFaultInjection{Latency: time.Second}, expected: true},
+		{name: "partial result", faults: DbFaultInjection{PartialResultRate: 0.1}, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.faults.IsEnabled())
+		})
+	}
+}
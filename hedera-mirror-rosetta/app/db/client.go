@@ -24,12 +24,16 @@ import (
 	"context"
 	"time"
 
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	log "github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
 type client struct {
 	db               *gorm.DB
+	monitor          *connectionMonitor
+	retry            config.DbRetry
 	statementTimeout uint
 }
 
@@ -54,8 +58,44 @@ func (d *client) GetDbWithContext(ctx context.Context) (*gorm.DB, context.Cancel
 	return d.db.WithContext(childCtx), cancel
 }
 
-func NewDbClient(db *gorm.DB, statementTimeout uint) interfaces.DbClient {
-	return &client{db: db, statementTimeout: statementTimeout}
+func (d *client) Query(ctx context.Context, name string, query func(db *gorm.DB) error) error {
+	maxAttempts := d.retry.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	phase := getQueryPhase(ctx)
+	phase.start(name)
+	defer phase.end(name)
+
+	backoff := d.retry.MinBackoff
+	for attempt := 1; ; attempt++ {
+		// each attempt has its own statement timeout
+		db, cancel := d.GetDbWithContext(ctx)
+		err := query(withSqlTrace(ctx, name, db))
+		cancel()
+		d.monitor.observe(err)
+
+		// a query failing because the request is cancelled, e.g., the client closed the connection, isn't retried
+		if attempt >= maxAttempts || !isTransientError(err) || (ctx != nil && ctx.Err() != nil) {
+			return err
+		}
+
+		delay := jitter(backoff)
+		log.Warnf("Query %s failed with transient error, retrying in %s (attempt %d of %d): %s", name, delay,
+			attempt, maxAttempts, err)
+		if sleep(ctx, delay) != nil {
+			return err
+		}
+
+		if backoff *= 2; backoff > d.retry.MaxBackoff {
+			backoff = d.retry.MaxBackoff
+		}
+	}
+}
+
+func NewDbClient(db *gorm.DB, statementTimeout uint, retry config.DbRetry) interfaces.DbClient {
+	return &client{db: db, retry: retry, statementTimeout: statementTimeout}
 }
 
 func noop() {
@@ -21,6 +21,7 @@
 package db
 
 import (
+	"context"
 	"time"
 
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
@@ -31,13 +32,39 @@ import (
 	"gorm.io/gorm"
 )
 
-// ConnectToDb establishes connection to the Postgres Database
-func ConnectToDb(dbConfig config.Db) interfaces.DbClient {
+// ConnectToDb establishes connection to the Postgres Database. If the replica is configured, the queries are routed to
+// it as long as its replay lag is within the threshold. The lifecycle events of the connections are emitted to the
+// configured hooks followed by the extra hooks
+func ConnectToDb(dbConfig config.Db, hooks ...EventHook) interfaces.DbClient {
+	eventHooks := newEventHooks(dbConfig.Events, hooks...)
+	primary := connect(dbConfig, TargetPrimary, eventHooks)
+	if primary == nil || !dbConfig.Replica.IsEnabled() {
+		return primary
+	}
+
+	replica := connect(dbConfig.GetReplicaConfig(), TargetReplica, eventHooks)
+	if replica == nil {
+		log.Warn("Failed to connect to the replica, routing all queries to the primary")
+		return primary
+	}
+
+	log.Infof("Routing queries to the replica %s:%d with max lag of %d bytes", dbConfig.Replica.Host,
+		dbConfig.Replica.Port, dbConfig.Replica.MaxLag)
+	client := newReplicaClient(primary, replica, dbConfig.Replica)
+	client.hooks = eventHooks
+	go client.run(context.Background())
+	return client
+}
+
+func connect(dbConfig config.Db, target string, hooks eventHooks) interfaces.DbClient {
+	monitor := &connectionMonitor{hooks: hooks, host: dbConfig.Host, port: dbConfig.Port, target: target}
 	db, err := gorm.Open(postgres.Open(dbConfig.GetDsn()), &gorm.Config{Logger: gormlogrus.New()})
 	if err != nil {
 		log.Warn(err)
+		monitor.observe(err)
 	} else {
 		log.Info("Successfully connected to database")
+		hooks.emit(monitor.newEvent(EventConnected, nil))
 	}
 
 	sqlDb, err := db.DB()
@@ -50,5 +77,9 @@ func ConnectToDb(dbConfig config.Db) interfaces.DbClient {
 	sqlDb.SetConnMaxLifetime(time.Duration(dbConfig.Pool.MaxLifetime) * time.Minute)
 	sqlDb.SetMaxOpenConns(dbConfig.Pool.MaxOpenConnections)
 
-	return NewDbClient(db, dbConfig.StatementTimeout)
+	dbClient := withSlowQueryLog(
+		&client{db: db, monitor: monitor, retry: dbConfig.Retry, statementTimeout: dbConfig.StatementTimeout},
+		dbConfig.SlowQueryThreshold,
+	)
+	return withFaultInjection(dbClient, dbConfig.FaultInjection)
 }
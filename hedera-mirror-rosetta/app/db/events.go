@@ -0,0 +1,233 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package db
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/jackc/pgconn"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	EventConnected        EventType = "connected"
+	EventFailoverDetected EventType = "failover_detected"
+	EventReconnected      EventType = "reconnected"
+	EventReplicaPromoted  EventType = "replica_promoted"
+
+	TargetPrimary = "primary"
+	TargetReplica = "replica"
+
+	eventSignatureHeader = "X-Rosetta-Signature"
+	eventSignaturePrefix = "sha256="
+)
+
+var eventCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "hedera_mirror_rosetta_db_events_total",
+	Help: "Number of database lifecycle events by event type and target.",
+}, []string{"type", "target"})
+
+func init() {
+	register := prometheus.WrapRegistererWith(
+		prometheus.Labels{"application": "hedera-mirror-rosetta"},
+		prometheus.DefaultRegisterer,
+	)
+	register.MustRegister(eventCounter)
+}
+
+// EventType is the type of a database lifecycle event
+type EventType string
+
+// Event is a database lifecycle event, e.g., the primary failed over, so operators can correlate the rosetta latency
+// with the database events
+type Event struct {
+	// Error is the error which caused the event, empty if none
+	Error     string    `json:"error,omitempty"`
+	Host      string    `json:"host"`
+	Port      uint16    `json:"port"`
+	Target    string    `json:"target"`
+	Timestamp time.Time `json:"timestamp"`
+	Type      EventType `json:"type"`
+}
+
+// EventHook handles the database lifecycle events. OnEvent must not block since it's called on the query path
+type EventHook interface {
+	OnEvent(event Event)
+}
+
+// eventHooks emits an event to all of its hooks
+type eventHooks []EventHook
+
+func (h eventHooks) emit(event Event) {
+	for _, hook := range h {
+		hook.OnEvent(event)
+	}
+}
+
+// newEventHooks returns the configured event hooks followed by the extra hooks
+func newEventHooks(eventsConfig config.DbEvents, extra ...EventHook) eventHooks {
+	hooks := make(eventHooks, 0, 3+len(extra))
+	if eventsConfig.Log {
+		hooks = append(hooks, logHook{})
+	}
+	if eventsConfig.Metrics {
+		hooks = append(hooks, metricsHook{})
+	}
+	if eventsConfig.Webhook != "" {
+		hooks = append(hooks, newWebhookHook(eventsConfig))
+	}
+	return append(hooks, extra...)
+}
+
+// logHook logs the events, the failovers and promotions as warnings
+type logHook struct{}
+
+func (logHook) OnEvent(event Event) {
+	entry := log.WithFields(log.Fields{"host": event.Host, "port": event.Port, "target": event.Target})
+	switch event.Type {
+	case EventFailoverDetected:
+		entry.Warnf("Database failover detected: %s", event.Error)
+	case EventReplicaPromoted:
+		entry.Warn("Database replica is no longer in recovery, it's likely promoted to primary")
+	case EventReconnected:
+		entry.Info("Reconnected to database")
+	default:
+		entry.Infof("Database event %s", event.Type)
+	}
+}
+
+// metricsHook counts the events in the hedera_mirror_rosetta_db_events_total metric
+type metricsHook struct{}
+
+func (metricsHook) OnEvent(event Event) {
+	eventCounter.WithLabelValues(string(event.Type), event.Target).Inc()
+}
+
+// webhookHook posts the events as json to the webhook. The body is signed with HMAC-SHA256 using the secret if set,
+// and the hex encoded signature is sent in the X-Rosetta-Signature header. The events are posted asynchronously and
+// failed deliveries are logged and dropped
+type webhookHook struct {
+	httpClient *http.Client
+	secret     string
+	webhook    string
+}
+
+func newWebhookHook(eventsConfig config.DbEvents) *webhookHook {
+	return &webhookHook{
+		httpClient: &http.Client{Timeout: eventsConfig.Timeout},
+		secret:     eventsConfig.Secret,
+		webhook:    eventsConfig.Webhook,
+	}
+}
+
+func (w *webhookHook) OnEvent(event Event) {
+	go func() {
+		if err := w.post(context.Background(), event); err != nil {
+			log.Warnf("Failed to post database event %s to %s: %s", event.Type, w.webhook, err)
+		}
+	}()
+}
+
+func (w *webhookHook) post(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, w.webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	if w.secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.secret))
+		mac.Write(body)
+		request.Header.Set(eventSignatureHeader, eventSignaturePrefix+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	response, err := w.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", response.StatusCode)
+	}
+	return nil
+}
+
+// connectionMonitor tracks whether the connections to a database are healthy based on the query results. It emits a
+// failover detected event on the first connection error after a success, and a reconnected event on the first success
+// after that
+type connectionMonitor struct {
+	failed int32
+	hooks  eventHooks
+	host   string
+	port   uint16
+	target string
+}
+
+func (m *connectionMonitor) observe(err error) {
+	if m == nil || len(m.hooks) == 0 {
+		return
+	}
+
+	if err == nil {
+		if atomic.CompareAndSwapInt32(&m.failed, 1, 0) {
+			m.hooks.emit(m.newEvent(EventReconnected, nil))
+		}
+	} else if isConnectionError(err) && atomic.CompareAndSwapInt32(&m.failed, 0, 1) {
+		m.hooks.emit(m.newEvent(EventFailoverDetected, err))
+	}
+}
+
+func (m *connectionMonitor) newEvent(eventType EventType, err error) Event {
+	event := Event{Host: m.host, Port: m.port, Target: m.target, Timestamp: time.Now(), Type: eventType}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	return event
+}
+
+// isConnectionError returns true if the error is a transient error caused by the loss of the connections to the
+// database, i.e., excluding the serialization failures and the deadlocks
+func isConnectionError(err error) bool {
+	if !isTransientError(err) {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	return !errors.As(err, &pgErr) || (pgErr.Code != "40001" && pgErr.Code != "40P01")
+}
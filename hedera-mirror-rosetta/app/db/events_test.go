@@ -0,0 +1,214 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package db
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type recordingHook struct {
+	mutex  sync.Mutex
+	events []Event
+}
+
+func (h *recordingHook) OnEvent(event Event) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.events = append(h.events, event)
+}
+
+func (h *recordingHook) types() []EventType {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	types := make([]EventType, 0, len(h.events))
+	for _, event := range h.events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func TestIsConnectionError(t *testing.T) {
+	var tests = []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "nil", err: nil},
+		{name: "generic", err: errPermanent},
+		{name: "serialization_failure", err: errTransient},
+		{name: "deadlock_detected", err: &pgconn.PgError{Code: "40P01"}},
+		{name: "connection_failure", err: &pgconn.PgError{Code: "08006"}, expected: true},
+		{name: "admin_shutdown", err: &pgconn.PgError{Code: "57P01"}, expected: true},
+		{name: "io.EOF", err: io.EOF, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isConnectionError(tt.err))
+		})
+	}
+}
+
+func TestQueryEmitsFailoverEvents(t *testing.T) {
+	// given
+	hook := &recordingHook{}
+	monitor := &connectionMonitor{hooks: eventHooks{hook}, host: "db", port: 5432, target: TargetPrimary}
+	dbClient := &client{db: newOfflineDb(t), monitor: monitor, retry: retryConfig}
+	results := []error{&pgconn.PgError{Code: "57P01"}, io.EOF, nil, nil, errTransient, io.EOF, nil}
+
+	// when
+	for _, result := range results {
+		err := result
+		_ = dbClient.Query(context.Background(), "test", func(db *gorm.DB) error {
+			return err
+		})
+	}
+
+	// then
+	assert.Equal(t, []EventType{
+		EventFailoverDetected,
+		EventReconnected,
+		EventFailoverDetected,
+		EventReconnected,
+	}, hook.types())
+	assert.Equal(t, "db", hook.events[0].Host)
+	assert.Equal(t, uint16(5432), hook.events[0].Port)
+	assert.Equal(t, TargetPrimary, hook.events[0].Target)
+	assert.NotEmpty(t, hook.events[0].Error)
+	assert.Empty(t, hook.events[1].Error)
+}
+
+func TestCheckPromoted(t *testing.T) {
+	// given
+	hook := &recordingHook{}
+	replicaClient := newReplicaClient(nil, nil, config.DbReplica{Host: "replica", Port: 5433})
+	replicaClient.hooks = eventHooks{hook}
+
+	// when
+	for _, notInRecovery := range []bool{false, true, true, false, true} {
+		replicaClient.checkPromoted(notInRecovery)
+	}
+
+	// then
+	assert.Equal(t, []EventType{EventReplicaPromoted, EventReplicaPromoted}, hook.types())
+	assert.Equal(t, "replica", hook.events[0].Host)
+	assert.Equal(t, TargetReplica, hook.events[0].Target)
+}
+
+func TestNewEventHooks(t *testing.T) {
+	extra := &recordingHook{}
+	var tests = []struct {
+		name     string
+		config   config.DbEvents
+		expected int
+	}{
+		{name: "none", expected: 1},
+		{name: "log", config: config.DbEvents{Log: true}, expected: 2},
+		{name: "all", config: config.DbEvents{Log: true, Metrics: true, Webhook: "http://localhost"}, expected: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hooks := newEventHooks(tt.config, extra)
+			assert.Len(t, hooks, tt.expected)
+			assert.Equal(t, extra, hooks[len(hooks)-1])
+		})
+	}
+}
+
+func TestWebhookHook(t *testing.T) {
+	var tests = []struct {
+		name   string
+		secret string
+	}{
+		{name: "signed", secret: "secret"},
+		{name: "unsigned"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			received := make(chan *http.Request, 1)
+			bodies := make(chan []byte, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				received <- r
+				bodies <- body
+			}))
+			defer server.Close()
+			hook := newWebhookHook(config.DbEvents{Secret: tt.secret, Timeout: time.Second, Webhook: server.URL})
+			event := Event{Host: "db", Target: TargetPrimary, Timestamp: time.Unix(1, 0).UTC(), Type: EventConnected}
+
+			// when
+			hook.OnEvent(event)
+
+			// then
+			var request *http.Request
+			var body []byte
+			select {
+			case request = <-received:
+				body = <-bodies
+			case <-time.After(5 * time.Second):
+				require.Fail(t, "webhook not called")
+			}
+
+			var actual Event
+			require.NoError(t, json.Unmarshal(body, &actual))
+			assert.Equal(t, event, actual)
+			assert.Equal(t, http.MethodPost, request.Method)
+
+			signature := request.Header.Get(eventSignatureHeader)
+			if tt.secret == "" {
+				assert.Empty(t, signature)
+				return
+			}
+
+			mac := hmac.New(sha256.New, []byte(tt.secret))
+			mac.Write(body)
+			assert.Equal(t, eventSignaturePrefix+hex.EncodeToString(mac.Sum(nil)), signature)
+		})
+	}
+}
+
+func TestWebhookHookUnexpectedStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	hook := newWebhookHook(config.DbEvents{Timeout: time.Second, Webhook: server.URL})
+
+	assert.Error(t, hook.post(context.Background(), Event{Type: EventConnected}))
+}
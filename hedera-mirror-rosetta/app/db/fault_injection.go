@@ -0,0 +1,88 @@
+//go:build faultinjection
+
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"math/rand"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	errInjectedFault         = fmt.Errorf("injected fault: %w", driver.ErrBadConn)
+	errInjectedPartialResult = fmt.Errorf("injected partial result: %w", io.ErrUnexpectedEOF)
+)
+
+// faultInjectingClient wraps a DbClient and injects latency, connection errors, and partial results into each query
+// attempt, so the retries, the statement timeout, and the error handling of the callers can be exercised
+type faultInjectingClient struct {
+	interfaces.DbClient
+	faults config.DbFaultInjection
+	random func() float64
+}
+
+func (f *faultInjectingClient) Query(ctx context.Context, name string, query func(db *gorm.DB) error) error {
+	return f.DbClient.Query(ctx, name, func(db *gorm.DB) error {
+		if f.faults.Latency > 0 {
+			if err := sleep(db.Statement.Context, f.faults.Latency); err != nil {
+				return err
+			}
+		}
+
+		if f.random() < f.faults.ErrorRate {
+			return errInjectedFault
+		}
+
+		if err := query(db); err != nil {
+			return err
+		}
+
+		// the rows have been read into the destination, but the connection is lost before the result is complete
+		if f.random() < f.faults.PartialResultRate {
+			return errInjectedPartialResult
+		}
+
+		return nil
+	})
+}
+
+// NewFaultInjectingDbClient returns a DbClient which injects the configured faults into the queries of the client
+func NewFaultInjectingDbClient(client interfaces.DbClient, faults config.DbFaultInjection) interfaces.DbClient {
+	return &faultInjectingClient{DbClient: client, faults: faults, random: rand.Float64}
+}
+
+func withFaultInjection(client interfaces.DbClient, faults config.DbFaultInjection) interfaces.DbClient {
+	if !faults.IsEnabled() {
+		return client
+	}
+
+	log.Warnf("Injecting faults into database queries: %+v", faults)
+	return NewFaultInjectingDbClient(client, faults)
+}
@@ -0,0 +1,35 @@
+//go:build !faultinjection
+
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package db
+
+import (
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithFaultInjectionDisabled(t *testing.T) {
+	dbClient := NewDbClient(newOfflineDb(t), 0, retryConfig)
+	assert.Same(t, dbClient, withFaultInjection(dbClient, config.DbFaultInjection{ErrorRate: 1}))
+}
@@ -0,0 +1,105 @@
+//go:build faultinjection
+
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestFaultInjectingClientQuery(t *testing.T) {
+	var tests = []struct {
+		name             string
+		faults           config.DbFaultInjection
+		expectedAttempts int
+		expectedErr      error
+	}{
+		{name: "no fault", expectedAttempts: 1},
+		{name: "error", faults: config.DbFaultInjection{ErrorRate: 1}, expectedErr: errInjectedFault},
+		{name: "partial result", faults: config.DbFaultInjection{PartialResultRate: 1}, expectedAttempts: 3,
+			expectedErr: errInjectedPartialResult},
+		{name: "latency", faults: config.DbFaultInjection{Latency: time.Minute},
+			expectedErr: context.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			dbClient := NewFaultInjectingDbClient(NewDbClient(newOfflineDb(t), 0, retryConfig), tt.faults)
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			attempts := 0
+
+			// when
+			err := dbClient.Query(ctx, "test", func(db *gorm.DB) error {
+				attempts++
+				return nil
+			})
+
+			// then
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Equal(t, tt.expectedAttempts, attempts)
+		})
+	}
+}
+
+func TestFaultInjectingClientQueryRecovers(t *testing.T) {
+	// given
+	faults := config.DbFaultInjection{ErrorRate: 0.5}
+	dbClient := &faultInjectingClient{
+		DbClient: NewDbClient(newOfflineDb(t), 0, retryConfig),
+		faults:   faults,
+		random:   sequence(0.1, 0.9, 0.9),
+	}
+	attempts := 0
+
+	// when
+	err := dbClient.Query(context.Background(), "test", func(db *gorm.DB) error {
+		attempts++
+		return nil
+	})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestWithFaultInjection(t *testing.T) {
+	dbClient := NewDbClient(newOfflineDb(t), 0, retryConfig)
+	assert.Same(t, dbClient, withFaultInjection(dbClient, config.DbFaultInjection{}))
+	assert.IsType(t, &faultInjectingClient{}, withFaultInjection(dbClient, config.DbFaultInjection{ErrorRate: 0.1}))
+}
+
+func sequence(values ...float64) func() float64 {
+	index := 0
+	return func() float64 {
+		value := values[index]
+		index++
+		return value
+	}
+}
@@ -0,0 +1,91 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package db
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+type queryPhaseKey struct{}
+
+// QueryPhase tracks the names of the queries currently run on behalf of a request, so a stuck request can be told
+// apart from a slow query
+type QueryPhase struct {
+	mutex   sync.Mutex
+	queries map[string]int
+}
+
+// WithQueryPhase returns a copy of the context carrying the query phase, which is updated by the queries run with the
+// context
+func WithQueryPhase(ctx context.Context, phase *QueryPhase) context.Context {
+	return context.WithValue(ctx, queryPhaseKey{}, phase)
+}
+
+// Queries returns the sorted names of the queries currently running, empty if none
+func (p *QueryPhase) Queries() []string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	queries := make([]string, 0, len(p.queries))
+	for name := range p.queries {
+		queries = append(queries, name)
+	}
+	sort.Strings(queries)
+	return queries
+}
+
+func (p *QueryPhase) start(name string) {
+	if p == nil {
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.queries == nil {
+		p.queries = make(map[string]int)
+	}
+	p.queries[name]++
+}
+
+func (p *QueryPhase) end(name string) {
+	if p == nil {
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.queries[name]--; p.queries[name] <= 0 {
+		delete(p.queries, name)
+	}
+}
+
+// getQueryPhase returns the query phase carried by the context, nil if it's not set
+func getQueryPhase(ctx context.Context) *QueryPhase {
+	if ctx == nil {
+		return nil
+	}
+
+	phase, _ := ctx.Value(queryPhaseKey{}).(*QueryPhase)
+	return phase
+}
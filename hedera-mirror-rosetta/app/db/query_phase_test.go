@@ -0,0 +1,55 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestQueryPhase(t *testing.T) {
+	// given
+	phase := &QueryPhase{}
+	ctx := WithQueryPhase(context.Background(), phase)
+	dbClient := NewDbClient(newOfflineDb(t), 0, retryConfig)
+	var during []string
+
+	// when
+	err := dbClient.Query(ctx, "outer", func(*gorm.DB) error {
+		return dbClient.Query(ctx, "inner", func(*gorm.DB) error {
+			during = phase.Queries()
+			return nil
+		})
+	})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"inner", "outer"}, during)
+	assert.Empty(t, phase.Queries())
+}
+
+func TestQueryPhaseNotSet(t *testing.T) {
+	dbClient := NewDbClient(newOfflineDb(t), 0, retryConfig)
+	assert.NoError(t, dbClient.Query(context.Background(), "test", func(*gorm.DB) error { return nil }))
+	assert.Nil(t, getQueryPhase(nil))
+}
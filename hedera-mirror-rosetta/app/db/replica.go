@@ -0,0 +1,256 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultReplicaCheckInterval = time.Second
+	selectCurrentWalLsn         = "select pg_current_wal_lsn()::text"
+	// selectLastWalReplayLsn selects the last WAL location replayed by the replica, null if not in recovery
+	selectLastWalReplayLsn = "select pg_last_wal_replay_lsn()::text"
+)
+
+type dbClientPinKey struct{}
+
+// dbClientPin holds the db client the queries run with a context are pinned to, chosen by the first query
+type dbClientPin struct {
+	once   sync.Once
+	client interfaces.DbClient
+}
+
+// WithDbClientPinning returns a copy of the context in which the queries are pinned to the database the first query is
+// routed to, so all queries run on behalf of a request see the same database even if the routing changes meanwhile
+func WithDbClientPinning(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dbClientPinKey{}, &dbClientPin{})
+}
+
+// replicaClient routes the queries to the replica as long as its replay lag is within the threshold, and to the
+// primary otherwise. Once routed to the primary, the queries are only routed back to the replica after it has replayed
+// past the primary's WAL location when the lag was last measured, so the results never go backwards in time
+type replicaClient struct {
+	config  config.DbReplica
+	hooks   eventHooks
+	primary interfaces.DbClient
+	replica interfaces.DbClient
+	// promoted is true once the replica is found no longer in recovery, only accessed by checkLag
+	promoted bool
+
+	mutex sync.RWMutex
+	// catchUpLsn is the WAL location the replica has to replay before the queries are routed back to it
+	catchUpLsn uint64
+	// seeded is true once catchUpLsn is set from the current WAL location of the primary
+	seeded     bool
+	usePrimary bool
+}
+
+func (r *replicaClient) GetDb() *gorm.DB {
+	return r.current().GetDb()
+}
+
+func (r *replicaClient) GetDbWithContext(ctx context.Context) (*gorm.DB, context.CancelFunc) {
+	return r.pinned(ctx).GetDbWithContext(ctx)
+}
+
+func (r *replicaClient) Query(ctx context.Context, name string, query func(db *gorm.DB) error) error {
+	return r.pinned(ctx).Query(ctx, name, query)
+}
+
+// newReplicaClient returns a replicaClient which routes the queries between the primary and the replica based on the
+// replay lag of the replica. The queries are routed to the primary until the replica has replayed past the current WAL
+// location of the primary when the lag is first measured, since the queries already served by the primary may have
+// seen anything up to it
+func newReplicaClient(primary, replica interfaces.DbClient, replicaConfig config.DbReplica) *replicaClient {
+	return &replicaClient{config: replicaConfig, primary: primary, replica: replica, usePrimary: true}
+}
+
+// run measures the replay lag of the replica periodically until the context is done
+func (r *replicaClient) run(ctx context.Context) {
+	r.checkLag(ctx)
+
+	interval := r.config.CheckInterval
+	if interval <= 0 {
+		interval = defaultReplicaCheckInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.checkLag(ctx)
+		}
+	}
+}
+
+func (r *replicaClient) checkLag(ctx context.Context) {
+	// get the replay location first so the lag is never underestimated
+	replayLsn, err := getLsn(ctx, r.replica, "selectLastWalReplayLsn", selectLastWalReplayLsn)
+	if err != nil {
+		log.Warnf("Failed to get the WAL replay location of the replica: %s", err)
+	} else {
+		r.checkPromoted(replayLsn == nil)
+	}
+
+	currentLsn, err := getLsn(ctx, r.primary, "selectCurrentWalLsn", selectCurrentWalLsn)
+	if err != nil {
+		log.Warnf("Failed to get the current WAL location of the primary: %s", err)
+	}
+
+	r.update(currentLsn, replayLsn)
+}
+
+// checkPromoted emits a replica promoted event when the replica is first found no longer in recovery
+func (r *replicaClient) checkPromoted(notInRecovery bool) {
+	if notInRecovery && !r.promoted {
+		r.hooks.emit(Event{
+			Host:      r.config.Host,
+			Port:      r.config.Port,
+			Target:    TargetReplica,
+			Timestamp: time.Now(),
+			Type:      EventReplicaPromoted,
+		})
+	}
+	r.promoted = notInRecovery
+}
+
+func (r *replicaClient) current() interfaces.DbClient {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if r.usePrimary {
+		return r.primary
+	}
+
+	return r.replica
+}
+
+// pinned returns the db client the context is pinned to, chosen by the current routing if it's the first query run with
+// the context. It returns the db client of the current routing if the context has no pinning
+func (r *replicaClient) pinned(ctx context.Context) interfaces.DbClient {
+	if ctx == nil {
+		return r.current()
+	}
+
+	pin, ok := ctx.Value(dbClientPinKey{}).(*dbClientPin)
+	if !ok {
+		return r.current()
+	}
+
+	pin.once.Do(func() { pin.client = r.current() })
+	return pin.client
+}
+
+// update routes the queries based on the current WAL location of the primary and the WAL replay location of the
+// replica, either is nil if unknown
+func (r *replicaClient) update(currentLsn, replayLsn *uint64) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if replayLsn == nil {
+		if !r.usePrimary {
+			log.Warn("Routing queries to the primary since the replay lag of the replica is unknown")
+		}
+		if currentLsn != nil {
+			r.catchUpLsn = *currentLsn
+			r.seeded = true
+		}
+		r.usePrimary = true
+		return
+	}
+
+	if currentLsn == nil {
+		// the lag can't be measured, keep routing to the replica only if it's in use
+		return
+	}
+
+	var lag uint64
+	if *currentLsn > *replayLsn {
+		lag = *currentLsn - *replayLsn
+	}
+
+	if !r.seeded {
+		// the replica has to catch up with the primary first, which may have served queries up to its current location
+		r.catchUpLsn = *currentLsn
+		r.seeded = true
+		return
+	}
+
+	if r.usePrimary {
+		if *replayLsn >= r.catchUpLsn && lag <= r.config.MaxLag {
+			log.Infof("Routing queries to the replica which lags %d bytes behind the primary", lag)
+			r.usePrimary = false
+		} else {
+			r.catchUpLsn = *currentLsn
+		}
+		return
+	}
+
+	if lag > r.config.MaxLag {
+		log.Warnf("Routing queries to the primary since the replica lags %d bytes behind", lag)
+		r.catchUpLsn = *currentLsn
+		r.usePrimary = true
+	}
+}
+
+// getLsn gets the WAL location by the query, nil if the query returns null
+func getLsn(ctx context.Context, dbClient interfaces.DbClient, name, query string) (*uint64, error) {
+	var lsnText sql.NullString
+	if err := dbClient.Query(ctx, name, func(db *gorm.DB) error {
+		return db.Raw(query).Row().Scan(&lsnText)
+	}); err != nil {
+		return nil, err
+	}
+
+	if !lsnText.Valid {
+		return nil, nil
+	}
+
+	lsn, err := parseLsn(lsnText.String)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lsn, nil
+}
+
+// parseLsn parses the text form of a pg_lsn, i.e., two hexadecimal numbers of up to 32 bits separated by a slash
+func parseLsn(text string) (uint64, error) {
+	var high, low uint32
+	if n, err := fmt.Sscanf(text, "%X/%X", &high, &low); err != nil || n != 2 {
+		return 0, fmt.Errorf("invalid pg_lsn %q", text)
+	}
+
+	return uint64(high)<<32 | uint64(low), nil
+}
@@ -0,0 +1,201 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLsn(t *testing.T) {
+	var tests = []struct {
+		text        string
+		expected    uint64
+		expectError bool
+	}{
+		{text: "0/0", expected: 0},
+		{text: "0/16B3748", expected: 0x16B3748},
+		{text: "1A/FFFFFFFF", expected: 0x1AFFFFFFFF},
+		{text: "ffffffff/ffffffff", expected: 0xFFFFFFFFFFFFFFFF},
+		{text: "", expectError: true},
+		{text: "16B3748", expectError: true},
+		{text: "x/1", expectError: true},
+		{text: "100000000/0", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			actual, err := parseLsn(tt.text)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestReplicaClientUpdate(t *testing.T) {
+	type lsns struct {
+		current *uint64
+		replay  *uint64
+	}
+
+	var tests = []struct {
+		name               string
+		updates            []lsns
+		expectUsePrimary   bool
+		expectedCatchUpLsn uint64
+	}{
+		{name: "initial", expectUsePrimary: true},
+		{
+			name:               "seeds catch up lsn",
+			updates:            []lsns{{lsn(1100), lsn(1000)}},
+			expectUsePrimary:   true,
+			expectedCatchUpLsn: 1100,
+		},
+		{
+			name:               "within max lag",
+			updates:            []lsns{{lsn(1000), lsn(900)}, {lsn(1100), lsn(1000)}},
+			expectedCatchUpLsn: 1000,
+		},
+		{
+			name:               "replica ahead",
+			updates:            []lsns{{lsn(1000), lsn(900)}, {lsn(1000), lsn(1010)}},
+			expectedCatchUpLsn: 1000,
+		},
+		{
+			name:               "exceeds max lag",
+			updates:            []lsns{{lsn(1000), lsn(900)}, {lsn(1101), lsn(1000)}},
+			expectUsePrimary:   true,
+			expectedCatchUpLsn: 1101,
+		},
+		{
+			name:               "falls behind",
+			updates:            []lsns{{lsn(1000), lsn(900)}, {lsn(1100), lsn(1000)}, {lsn(1300), lsn(1100)}},
+			expectUsePrimary:   true,
+			expectedCatchUpLsn: 1300,
+		},
+		{
+			name:               "within max lag but not caught up",
+			updates:            []lsns{{lsn(1300), lsn(1100)}, {lsn(1400), lsn(1299)}},
+			expectUsePrimary:   true,
+			expectedCatchUpLsn: 1400,
+		},
+		{
+			name:               "caught up",
+			updates:            []lsns{{lsn(1300), lsn(1100)}, {lsn(1400), lsn(1300)}},
+			expectedCatchUpLsn: 1300,
+		},
+		{
+			name:               "replay unknown",
+			updates:            []lsns{{lsn(1000), lsn(900)}, {lsn(1100), lsn(1000)}, {lsn(1200), nil}},
+			expectUsePrimary:   true,
+			expectedCatchUpLsn: 1200,
+		},
+		{
+			name:               "replay unknown seeds catch up lsn",
+			updates:            []lsns{{lsn(1200), nil}, {lsn(1300), lsn(1200)}},
+			expectedCatchUpLsn: 1200,
+		},
+		{
+			name:               "current unknown",
+			updates:            []lsns{{lsn(1000), lsn(900)}, {lsn(1100), lsn(1000)}, {nil, lsn(1000)}},
+			expectedCatchUpLsn: 1000,
+		},
+		{
+			name:               "current unknown when routed to primary",
+			updates:            []lsns{{nil, lsn(1000)}, {lsn(1000), lsn(1000)}},
+			expectUsePrimary:   true,
+			expectedCatchUpLsn: 1000,
+		},
+		{
+			name:               "both unknown",
+			updates:            []lsns{{lsn(1000), lsn(900)}, {lsn(1100), lsn(1000)}, {nil, nil}},
+			expectUsePrimary:   true,
+			expectedCatchUpLsn: 1000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			primary := NewDbClient(newOfflineDb(t), 0, retryConfig)
+			replica := NewDbClient(newOfflineDb(t), 0, retryConfig)
+			client := newReplicaClient(primary, replica, config.DbReplica{MaxLag: 100})
+			expected := replica
+			if tt.expectUsePrimary {
+				expected = primary
+			}
+
+			// when
+			for _, update := range tt.updates {
+				client.update(update.current, update.replay)
+			}
+
+			// then
+			assert.Same(t, expected, client.current())
+			assert.Equal(t, tt.expectedCatchUpLsn, client.catchUpLsn)
+		})
+	}
+}
+
+func TestReplicaClientCheckLagDbConnectionError(t *testing.T) {
+	// given
+	primary := NewDbClient(newOfflineDb(t), 0, config.DbRetry{})
+	replica := NewDbClient(newOfflineDb(t), 0, config.DbRetry{})
+	client := newReplicaClient(primary, replica, config.DbReplica{MaxLag: 100})
+	client.update(lsn(1000), lsn(1000))
+	client.update(lsn(1000), lsn(1000))
+
+	// when
+	client.checkLag(context.Background())
+
+	// then
+	assert.Same(t, primary, client.current())
+	assert.Same(t, primary.GetDb(), client.GetDb())
+}
+
+func TestReplicaClientPinning(t *testing.T) {
+	// given
+	primary := NewDbClient(newOfflineDb(t), 0, retryConfig)
+	replica := NewDbClient(newOfflineDb(t), 0, retryConfig)
+	client := newReplicaClient(primary, replica, config.DbReplica{MaxLag: 100})
+	ctx := WithDbClientPinning(context.Background())
+	assert.Same(t, primary, client.pinned(ctx))
+
+	// when
+	client.update(lsn(1000), lsn(1000))
+	client.update(lsn(1000), lsn(1000))
+
+	// then
+	assert.Same(t, primary, client.pinned(ctx))
+	assert.Same(t, replica, client.pinned(WithDbClientPinning(context.Background())))
+	assert.Same(t, replica, client.pinned(context.Background()))
+	assert.Same(t, replica, client.pinned(nil))
+}
+
+func lsn(value uint64) *uint64 {
+	return &value
+}
@@ -0,0 +1,169 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+var (
+	errPermanent = errors.New("permanent")
+	errTransient = &pgconn.PgError{Code: "40001"}
+	retryConfig  = config.DbRetry{MaxAttempts: 3, MaxBackoff: 2 * time.Millisecond, MinBackoff: time.Millisecond}
+)
+
+func TestIsTransientError(t *testing.T) {
+	var tests = []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "nil", err: nil},
+		{name: "context.Canceled", err: context.Canceled},
+		{name: "context.DeadlineExceeded", err: fmt.Errorf("wrapped %w", context.DeadlineExceeded)},
+		{name: "generic", err: errPermanent},
+		{name: "unique_violation", err: &pgconn.PgError{Code: "23505"}},
+		{name: "syntax_error", err: &pgconn.PgError{Code: "42601"}},
+		{name: "serialization_failure", err: errTransient, expected: true},
+		{name: "deadlock_detected", err: &pgconn.PgError{Code: "40P01"}, expected: true},
+		{name: "connection_failure", err: &pgconn.PgError{Code: "08006"}, expected: true},
+		{name: "admin_shutdown", err: fmt.Errorf("wrapped %w", &pgconn.PgError{Code: "57P01"}), expected: true},
+		{name: "driver.ErrBadConn", err: driver.ErrBadConn, expected: true},
+		{name: "io.EOF", err: io.EOF, expected: true},
+		{name: "io.ErrUnexpectedEOF", err: io.ErrUnexpectedEOF, expected: true},
+		{name: "ECONNREFUSED", err: fmt.Errorf("dial: %w", syscall.ECONNREFUSED), expected: true},
+		{name: "ECONNRESET", err: syscall.ECONNRESET, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isTransientError(tt.err))
+		})
+	}
+}
+
+func TestQuery(t *testing.T) {
+	var tests = []struct {
+		name             string
+		errors           []error
+		retry            config.DbRetry
+		expectedAttempts int
+		expectedErr      error
+	}{
+		{name: "success", errors: []error{nil}, retry: retryConfig, expectedAttempts: 1},
+		{name: "permanent error", errors: []error{errPermanent}, retry: retryConfig, expectedAttempts: 1,
+			expectedErr: errPermanent},
+		{name: "transient then success", errors: []error{errTransient, nil}, retry: retryConfig,
+			expectedAttempts: 2},
+		{name: "transient then permanent", errors: []error{errTransient, errPermanent}, retry: retryConfig,
+			expectedAttempts: 2, expectedErr: errPermanent},
+		{name: "attempts exhausted", errors: []error{errTransient, errTransient, errTransient, nil},
+			retry: retryConfig, expectedAttempts: 3, expectedErr: errTransient},
+		{name: "retry disabled", errors: []error{errTransient, nil}, expectedAttempts: 1,
+			expectedErr: errTransient},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			dbClient := NewDbClient(newOfflineDb(t), 0, tt.retry)
+			attempts := 0
+
+			// when
+			err := dbClient.Query(context.Background(), "test", func(db *gorm.DB) error {
+				assert.NotNil(t, db)
+				err := tt.errors[attempts]
+				attempts++
+				return err
+			})
+
+			// then
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedAttempts, attempts)
+		})
+	}
+}
+
+func TestQueryContextCanceledDuringBackoff(t *testing.T) {
+	// given
+	retry := config.DbRetry{MaxAttempts: 3, MaxBackoff: time.Minute, MinBackoff: time.Minute}
+	dbClient := NewDbClient(newOfflineDb(t), 0, retry)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	attempts := 0
+
+	// when
+	err := dbClient.Query(ctx, "test", func(db *gorm.DB) error {
+		attempts++
+		return errTransient
+	})
+
+	// then
+	assert.Equal(t, errTransient, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestQueryContextCanceledDuringQuery(t *testing.T) {
+	// given
+	dbClient := NewDbClient(newOfflineDb(t), 0, retryConfig)
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+
+	// when
+	err := dbClient.Query(ctx, "test", func(db *gorm.DB) error {
+		attempts++
+		// the connection is closed when the query is cancelled
+		cancel()
+		return io.ErrUnexpectedEOF
+	})
+
+	// then
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestJitter(t *testing.T) {
+	assert.Equal(t, time.Duration(0), jitter(0))
+	for i := 0; i < 100; i++ {
+		actual := jitter(time.Second)
+		assert.GreaterOrEqual(t, actual, 500*time.Millisecond)
+		assert.LessOrEqual(t, actual, time.Second)
+	}
+}
+
+func newOfflineDb(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(postgres.Open("host=127.0.0.1 port=1"), &gorm.Config{DisableAutomaticPing: true})
+	assert.NoError(t, err)
+	return db
+}
@@ -0,0 +1,144 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package db
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const noEndpoint = "none"
+
+type endpointKey struct{}
+
+var slowQueryCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "hedera_mirror_rosetta_db_slow_queries_total",
+	Help: "Number of database queries slower than the slow query threshold.",
+}, []string{"query"})
+
+func init() {
+	register := prometheus.WrapRegistererWith(
+		prometheus.Labels{"application": "hedera-mirror-rosetta"},
+		prometheus.DefaultRegisterer,
+	)
+	register.MustRegister(slowQueryCounter)
+}
+
+// WithEndpoint returns a copy of the context carrying the endpoint of the request, which is logged with the slow
+// queries run on behalf of the request
+func WithEndpoint(ctx context.Context, endpoint string) context.Context {
+	return context.WithValue(ctx, endpointKey{}, endpoint)
+}
+
+// GetEndpoint returns the endpoint of the request the context belongs to, empty if it's not set
+func GetEndpoint(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	endpoint, _ := ctx.Value(endpointKey{}).(string)
+	return endpoint
+}
+
+// statementRecorder is a gorm logger which passes the traces to the wrapped logger and records the slowest statement
+// of a query attempt, so it can be logged with its parameters if the attempt is slow
+type statementRecorder struct {
+	logger.Interface
+	mutex   sync.Mutex
+	slowest time.Duration
+	sql     func() (string, int64)
+}
+
+func (r *statementRecorder) LogMode(level logger.LogLevel) logger.Interface {
+	r.Interface = r.Interface.LogMode(level)
+	return r
+}
+
+func (r *statementRecorder) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	r.Interface.Trace(ctx, begin, fc, err)
+
+	elapsed := time.Since(begin)
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.sql == nil || elapsed > r.slowest {
+		r.slowest = elapsed
+		r.sql = fc
+	}
+}
+
+// getSql returns the slowest statement recorded with its parameters inlined, empty if none is recorded
+func (r *statementRecorder) getSql() string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.sql == nil {
+		return ""
+	}
+
+	sql, _ := r.sql()
+	return sql
+}
+
+// slowQueryLoggingClient wraps a DbClient and logs each query attempt which takes longer than the threshold with its
+// name, duration, the endpoint of the request, and the slowest statement with the parameters. The slow attempts are
+// also counted per query name
+type slowQueryLoggingClient struct {
+	interfaces.DbClient
+	threshold time.Duration
+}
+
+func (s *slowQueryLoggingClient) Query(ctx context.Context, name string, query func(db *gorm.DB) error) error {
+	return s.DbClient.Query(ctx, name, func(db *gorm.DB) error {
+		recorder := &statementRecorder{Interface: db.Logger}
+		start := time.Now()
+		err := query(db.Session(&gorm.Session{Logger: recorder}))
+
+		if elapsed := time.Since(start); elapsed > s.threshold {
+			endpoint := GetEndpoint(ctx)
+			if endpoint == "" {
+				endpoint = noEndpoint
+			}
+
+			slowQueryCounter.WithLabelValues(name).Inc()
+			log.Warnf("Slow query %s took %s for endpoint %s: %s", name, elapsed, endpoint, recorder.getSql())
+		}
+
+		return err
+	})
+}
+
+// NewSlowQueryLoggingDbClient returns a DbClient which logs the queries of the client slower than the threshold
+func NewSlowQueryLoggingDbClient(client interfaces.DbClient, threshold time.Duration) interfaces.DbClient {
+	return &slowQueryLoggingClient{DbClient: client, threshold: threshold}
+}
+
+func withSlowQueryLog(client interfaces.DbClient, threshold time.Duration) interfaces.DbClient {
+	if threshold <= 0 {
+		return client
+	}
+
+	return NewSlowQueryLoggingDbClient(client, threshold)
+}
@@ -0,0 +1,120 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package db
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestGetEndpoint(t *testing.T) {
+	assert.Equal(t, "/block", GetEndpoint(WithEndpoint(context.Background(), "/block")))
+	assert.Empty(t, GetEndpoint(context.Background()))
+	assert.Empty(t, GetEndpoint(nil))
+}
+
+func TestWithSlowQueryLog(t *testing.T) {
+	client := NewDbClient(newOfflineDb(t), 0, config.DbRetry{})
+
+	assert.Same(t, client, withSlowQueryLog(client, 0))
+	assert.IsType(t, &slowQueryLoggingClient{}, withSlowQueryLog(client, time.Second))
+}
+
+func TestStatementRecorderSlowest(t *testing.T) {
+	// given
+	recorder := &statementRecorder{Interface: logger.Discard}
+	now := time.Now()
+
+	// when
+	recorder.Trace(context.Background(), now.Add(-time.Millisecond), sqlFunc("select 1"), nil)
+	recorder.Trace(context.Background(), now.Add(-time.Second), sqlFunc("select 2"), nil)
+	recorder.Trace(context.Background(), now, sqlFunc("select 3"), nil)
+
+	// then
+	assert.Equal(t, "select 2", recorder.getSql())
+	assert.Empty(t, (&statementRecorder{Interface: logger.Discard}).getSql())
+}
+
+func TestSlowQueryLoggingClientQuery(t *testing.T) {
+	var tests = []struct {
+		name     string
+		ctx      context.Context
+		delay    time.Duration
+		endpoint string
+		slow     bool
+	}{
+		{name: "fast", ctx: context.Background()},
+		{name: "slow", ctx: WithEndpoint(context.Background(), "/block"), delay: 5 * time.Millisecond,
+			endpoint: "/block", slow: true},
+		{name: "slowWithoutEndpoint", ctx: context.Background(), delay: 5 * time.Millisecond,
+			endpoint: noEndpoint, slow: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			buf := bytes.NewBuffer(nil)
+			log.SetOutput(buf)
+			defer log.SetOutput(os.Stdout)
+
+			name := "selectSlowQueryTest" + tt.name
+			client := NewSlowQueryLoggingDbClient(
+				NewDbClient(newOfflineDb(t), 0, config.DbRetry{}),
+				2*time.Millisecond,
+			)
+
+			// when
+			err := client.Query(tt.ctx, name, func(db *gorm.DB) error {
+				begin := time.Now()
+				time.Sleep(tt.delay)
+				db.Logger.Trace(db.Statement.Context, begin, sqlFunc("select * from block where index = 5"), nil)
+				return nil
+			})
+
+			// then
+			assert.NoError(t, err)
+			if tt.slow {
+				assert.Equal(t, float64(1), testutil.ToFloat64(slowQueryCounter.WithLabelValues(name)))
+				assert.Contains(t, buf.String(), "Slow query "+name)
+				assert.Contains(t, buf.String(), "for endpoint "+tt.endpoint)
+				assert.Contains(t, buf.String(), "select * from block where index = 5")
+			} else {
+				assert.Zero(t, testutil.ToFloat64(slowQueryCounter.WithLabelValues(name)))
+				assert.NotContains(t, buf.String(), "Slow query")
+			}
+		})
+	}
+}
+
+func sqlFunc(sql string) func() (string, int64) {
+	return func() (string, int64) {
+		return sql, 1
+	}
+}
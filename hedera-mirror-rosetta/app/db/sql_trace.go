@@ -0,0 +1,87 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package db
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type sqlTraceKey struct{}
+
+// WithSqlTrace returns a copy of the context with the SQL trace enabled, so every statement of the queries run with
+// the context is logged
+func WithSqlTrace(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sqlTraceKey{}, true)
+}
+
+// IsSqlTraceEnabled returns true if the SQL trace is enabled in the context
+func IsSqlTraceEnabled(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+
+	enabled, _ := ctx.Value(sqlTraceKey{}).(bool)
+	return enabled
+}
+
+// sqlTraceLogger is a gorm logger which passes the traces to the wrapped logger and logs each statement with its
+// parameters, duration, and number of rows
+type sqlTraceLogger struct {
+	logger.Interface
+	endpoint string
+	name     string
+}
+
+func (l *sqlTraceLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return &sqlTraceLogger{Interface: l.Interface.LogMode(level), endpoint: l.endpoint, name: l.name}
+}
+
+func (l *sqlTraceLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	l.Interface.Trace(ctx, begin, fc, err)
+
+	elapsed := time.Since(begin)
+	sql, rows := fc()
+	if err != nil {
+		log.Infof("SQL trace of query %s for endpoint %s failed in %s: %s: %s", l.name, l.endpoint, elapsed, sql, err)
+		return
+	}
+
+	log.Infof("SQL trace of query %s for endpoint %s took %s with %d rows: %s", l.name, l.endpoint, elapsed, rows, sql)
+}
+
+// withSqlTrace returns a session of the gorm.DB tracing the statements if the SQL trace is enabled in the context,
+// otherwise the gorm.DB as is
+func withSqlTrace(ctx context.Context, name string, db *gorm.DB) *gorm.DB {
+	if !IsSqlTraceEnabled(ctx) {
+		return db
+	}
+
+	endpoint := GetEndpoint(ctx)
+	if endpoint == "" {
+		endpoint = noEndpoint
+	}
+
+	return db.Session(&gorm.Session{Logger: &sqlTraceLogger{Interface: db.Logger, endpoint: endpoint, name: name}})
+}
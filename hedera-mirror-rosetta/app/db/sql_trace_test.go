@@ -0,0 +1,109 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package db
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestIsSqlTraceEnabled(t *testing.T) {
+	assert.True(t, IsSqlTraceEnabled(WithSqlTrace(context.Background())))
+	assert.False(t, IsSqlTraceEnabled(context.Background()))
+	assert.False(t, IsSqlTraceEnabled(nil))
+}
+
+func TestSqlTraceLoggerTrace(t *testing.T) {
+	var tests = []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{name: "success", expected: "took"},
+		{name: "failure", err: errors.New("canceling statement"), expected: "failed in"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			buf := bytes.NewBuffer(nil)
+			log.SetOutput(buf)
+			defer log.SetOutput(os.Stdout)
+			traceLogger := &sqlTraceLogger{Interface: logger.Discard, endpoint: "/block", name: "selectBlock"}
+
+			// when
+			traceLogger.LogMode(logger.Info).Trace(context.Background(), time.Now(), sqlFunc("select 1"), tt.err)
+
+			// then
+			assert.Contains(t, buf.String(), "SQL trace of query selectBlock for endpoint /block "+tt.expected)
+			assert.Contains(t, buf.String(), "select 1")
+			if tt.err != nil {
+				assert.Contains(t, buf.String(), tt.err.Error())
+			}
+		})
+	}
+}
+
+func TestClientQuerySqlTrace(t *testing.T) {
+	var tests = []struct {
+		name     string
+		ctx      context.Context
+		endpoint string
+	}{
+		{name: "disabled", ctx: WithEndpoint(context.Background(), "/block")},
+		{name: "enabled", ctx: WithSqlTrace(WithEndpoint(context.Background(), "/block")), endpoint: "/block"},
+		{name: "enabledWithoutEndpoint", ctx: WithSqlTrace(context.Background()), endpoint: noEndpoint},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			buf := bytes.NewBuffer(nil)
+			log.SetOutput(buf)
+			defer log.SetOutput(os.Stdout)
+			client := NewDbClient(newOfflineDb(t), 0, config.DbRetry{})
+
+			// when
+			err := client.Query(tt.ctx, "selectSqlTraceTest", func(db *gorm.DB) error {
+				db.Logger.Trace(db.Statement.Context, time.Now(), sqlFunc("select * from block where index = 5"), nil)
+				return nil
+			})
+
+			// then
+			assert.NoError(t, err)
+			if tt.endpoint != "" {
+				assert.Contains(t, buf.String(), "SQL trace of query selectSqlTraceTest for endpoint "+tt.endpoint)
+				assert.Contains(t, buf.String(), "select * from block where index = 5")
+			} else {
+				assert.NotContains(t, buf.String(), "SQL trace")
+			}
+		})
+	}
+}
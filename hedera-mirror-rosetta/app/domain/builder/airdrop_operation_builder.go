@@ -0,0 +1,56 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package builder
+
+import "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+
+// airdropOperationBuilder builds an operation per airdrop state change made by a TokenAirdrop, TokenCancelAirdrop, or
+// TokenClaimAirdrop transaction, with the pending transfer as metadata. The account of the operation is the receiver
+// of a claimed airdrop, and the sender otherwise. The operations have no amount since the tokens only move when a
+// pending airdrop is claimed, which is covered by the token transfers of the claim
+type airdropOperationBuilder struct{}
+
+func (b *airdropOperationBuilder) build(
+	transaction Transaction,
+	operations types.OperationSlice,
+	_ int,
+) types.OperationSlice {
+	for _, airdrop := range transaction.PendingAirdrops {
+		accountId := airdrop.SenderAccountId
+		if airdrop.State == types.AirdropStateClaimed {
+			accountId = airdrop.ReceiverAccountId
+		}
+
+		operations = append(operations, types.Operation{
+			AccountId: types.NewAccountIdFromEntityId(accountId),
+			Index:     int64(len(operations)),
+			Metadata:  airdrop.ToMetadata(),
+			Status:    transaction.getResult(),
+			Type:      transaction.getOperationType(),
+		})
+	}
+
+	return operations
+}
+
+func newAirdropOperationBuilder() transactionOperationBuilder {
+	return &airdropOperationBuilder{}
+}
@@ -0,0 +1,139 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	typeTokenAirdrop       int32 = 58
+	typeTokenCancelAirdrop int32 = 59
+	typeTokenClaimAirdrop  int32 = 60
+)
+
+func TestAirdropOperationBuilderBuild(t *testing.T) {
+	fungible := domain.Token{Decimals: tokenDecimals, TokenId: tokenId1, Type: domain.TokenTypeFungibleCommon}
+	nft := domain.Token{TokenId: tokenId3, Type: domain.TokenTypeNonFungibleUnique}
+	tests := []struct {
+		name              string
+		state             string
+		transactionType   int32
+		expectedAccountId types.AccountId
+		expectedType      string
+	}{
+		{
+			name:              "airdrop",
+			state:             types.AirdropStatePending,
+			transactionType:   typeTokenAirdrop,
+			expectedAccountId: firstAccountId,
+			expectedType:      "TOKENAIRDROP",
+		},
+		{
+			name:              "cancel",
+			state:             types.AirdropStateCancelled,
+			transactionType:   typeTokenCancelAirdrop,
+			expectedAccountId: firstAccountId,
+			expectedType:      "TOKENCANCELAIRDROP",
+		},
+		{
+			name:              "claim",
+			state:             types.AirdropStateClaimed,
+			transactionType:   typeTokenClaimAirdrop,
+			expectedAccountId: secondAccountId,
+			expectedType:      "TOKENCLAIMAIRDROP",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			airdrops := []types.PendingAirdrop{
+				{
+					Amount:            100,
+					ReceiverAccountId: secondEntityId,
+					SenderAccountId:   firstEntityId,
+					State:             tt.state,
+					Timestamp:         1,
+					Token:             fungible,
+				},
+				{
+					ReceiverAccountId: secondEntityId,
+					SenderAccountId:   firstEntityId,
+					SerialNumber:      2,
+					State:             tt.state,
+					Timestamp:         1,
+					Token:             nft,
+				},
+			}
+			transaction := Transaction{
+				PayerAccountId:  firstEntityId,
+				PendingAirdrops: airdrops,
+				Result:          transactionResultSuccess,
+				Type:            tt.transactionType,
+			}
+			operations := types.OperationSlice{{Index: 0, Type: types.OperationTypeFee}}
+			expected := types.OperationSlice{
+				{Index: 0, Type: types.OperationTypeFee},
+				{
+					AccountId: tt.expectedAccountId,
+					Index:     1,
+					Metadata:  airdrops[0].ToMetadata(),
+					Status:    resultSuccess,
+					Type:      tt.expectedType,
+				},
+				{
+					AccountId: tt.expectedAccountId,
+					Index:     2,
+					Metadata:  airdrops[1].ToMetadata(),
+					Status:    resultSuccess,
+					Type:      tt.expectedType,
+				},
+			}
+
+			// when
+			actual := newAirdropOperationBuilder().build(transaction, operations, 0)
+
+			// then
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
+
+func TestAirdropOperationBuilderBuildNoop(t *testing.T) {
+	// given
+	transaction := Transaction{
+		PayerAccountId: firstEntityId,
+		Result:         transactionResultSuccess,
+		Type:           typeTokenAirdrop,
+	}
+	operations := types.OperationSlice{{Index: 0, Type: types.OperationTypeFee}}
+
+	// when
+	actual := newAirdropOperationBuilder().build(transaction, operations, 0)
+
+	// then
+	assert.Equal(t, types.OperationSlice{{Index: 0, Type: types.OperationTypeFee}}, actual)
+}
@@ -0,0 +1,109 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package builder
+
+import (
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	log "github.com/sirupsen/logrus"
+)
+
+type compositeOperationBuilder struct {
+	builders                []transactionOperationBuilder
+	suppressEmptyOperations bool
+}
+
+func (c *compositeOperationBuilder) Build(transactions []Transaction) types.OperationSlice {
+	operations := make(types.OperationSlice, 0, estimateOperationCount(transactions))
+	for _, transaction := range transactions {
+		start := len(operations)
+		for _, builder := range c.builders {
+			operations = builder.build(transaction, operations, start)
+		}
+	}
+
+	if c.suppressEmptyOperations {
+		operations = filterEmptyOperations(operations)
+	}
+
+	return operations
+}
+
+func (c *compositeOperationBuilder) addBuilder(builder transactionOperationBuilder) {
+	c.builders = append(c.builders, builder)
+}
+
+// estimateOperationCount returns an upper bound of the number of operations built from the transfers of the
+// transactions plus one operation per transaction, so the operations slice is allocated once in the common case
+func estimateOperationCount(transactions []Transaction) int {
+	count := 0
+	for _, transaction := range transactions {
+		count += 1 + len(transaction.CryptoTransfers) + len(transaction.NonFeeTransfers) +
+			len(transaction.TokenTransfers) + 2*len(transaction.NftTransfers)
+	}
+	return count
+}
+
+// filterEmptyOperations removes the operations without an amount or with a zero amount, and reindexes the remaining
+// operations
+func filterEmptyOperations(operations types.OperationSlice) types.OperationSlice {
+	filtered := make(types.OperationSlice, 0, len(operations))
+	for _, operation := range operations {
+		if operation.Amount == nil || operation.Amount.GetValue() == 0 {
+			continue
+		}
+
+		operation.Index = int64(len(filtered))
+		filtered = append(filtered, operation)
+	}
+	return filtered
+}
+
+// NewOperationBuilder creates an OperationBuilder which builds the transfer operations, followed by the token, the
+// schedule, the hollow account completion, and the airdrop operations of each transaction, and marks the operations of
+// the token rejections and the deleted accounts
+func NewOperationBuilder(systemAccounts config.SystemAccounts, suppressEmptyOperations bool) OperationBuilder {
+	c := &compositeOperationBuilder{suppressEmptyOperations: suppressEmptyOperations}
+	c.addBuilder(newTransferOperationBuilder(toSystemAccountMap(systemAccounts)))
+	c.addBuilder(newTokenOperationBuilder())
+	c.addBuilder(newScheduleOperationBuilder())
+	c.addBuilder(newHollowAccountOperationBuilder())
+	c.addBuilder(newAirdropOperationBuilder())
+	c.addBuilder(newTokenRejectOperationBuilder())
+	c.addBuilder(newDeletedEntityOperationBuilder())
+
+	return c
+}
+
+// toSystemAccountMap returns the roles of the system accounts by their encoded entity ids, invalid accounts are skipped
+func toSystemAccountMap(systemAccounts config.SystemAccounts) map[int64]string {
+	systemAccountMap := make(map[int64]string)
+	for account, role := range systemAccounts.ToMap() {
+		entityId, err := domain.EntityIdFromString(account)
+		if err != nil {
+			log.Warnf("Ignoring invalid %s system account %s: %s", role, account, err)
+			continue
+		}
+		systemAccountMap[entityId.EncodedId] = role
+	}
+	return systemAccountMap
+}
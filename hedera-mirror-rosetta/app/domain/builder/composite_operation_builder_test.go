@@ -0,0 +1,234 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	resultFail                  = "INSUFFICIENT_ACCOUNT_BALANCE"
+	resultSuccess               = "SUCCESS"
+	transactionResultFail int32 = 28
+	typeCryptoTransfer    int32 = 14
+)
+
+var (
+	firstEntityId               = domain.MustDecodeEntityId(12345)
+	secondEntityId              = domain.MustDecodeEntityId(54321)
+	thirdEntityId               = domain.MustDecodeEntityId(54350)
+	nodeEntityId                = domain.MustDecodeEntityId(3)
+	feeCollectorEntityId        = domain.MustDecodeEntityId(98)
+	firstAccountId              = types.NewAccountIdFromEntityId(firstEntityId)
+	secondAccountId             = types.NewAccountIdFromEntityId(secondEntityId)
+	nodeAccountId               = types.NewAccountIdFromEntityId(nodeEntityId)
+	feeCollectorAccountId       = types.NewAccountIdFromEntityId(feeCollectorEntityId)
+	scheduleId                  = domain.MustDecodeEntityId(56000)
+	tokenId1                    = domain.MustDecodeEntityId(25636)
+	tokenId2                    = domain.MustDecodeEntityId(26700)
+	tokenId3                    = domain.MustDecodeEntityId(26750) // nft
+	tokenDecimals         int64 = 10
+)
+
+func TestNewOperationBuilder(t *testing.T) {
+	// when
+	actual := NewOperationBuilder(config.SystemAccounts{FeeCollection: "0.0.98", Treasury: "invalid"}, true)
+
+	// then
+	composite := actual.(*compositeOperationBuilder)
+	assert.True(t, composite.suppressEmptyOperations)
+	assert.IsType(t, &transferOperationBuilder{}, composite.builders[0])
+	assert.IsType(t, &tokenOperationBuilder{}, composite.builders[1])
+	assert.IsType(t, &scheduleOperationBuilder{}, composite.builders[2])
+	assert.IsType(t, &hollowAccountOperationBuilder{}, composite.builders[3])
+	assert.IsType(t, &airdropOperationBuilder{}, composite.builders[4])
+	assert.IsType(t, &tokenRejectOperationBuilder{}, composite.builders[5])
+	assert.IsType(t, &deletedEntityOperationBuilder{}, composite.builders[6])
+	assert.Len(t, composite.builders, 7)
+	assert.Equal(
+		t,
+		map[int64]string{feeCollectorEntityId.EncodedId: config.SystemAccountFeeCollection},
+		composite.builders[0].(*transferOperationBuilder).systemAccounts,
+	)
+}
+
+func TestCompositeOperationBuilderBuild(t *testing.T) {
+	// given
+	transactions := []Transaction{
+		{
+			CryptoTransfers: []HbarTransfer{
+				{AccountId: firstEntityId, Amount: -5},
+				{AccountId: nodeEntityId, Amount: 5},
+			},
+			PayerAccountId: firstEntityId,
+			Result:         transactionResultSuccess,
+			Schedule:       schedule,
+			Type:           42,
+		},
+		{
+			CryptoTransfers: []HbarTransfer{
+				{AccountId: firstEntityId, Amount: -10},
+				{AccountId: secondEntityId, Amount: 10},
+			},
+			NonFeeTransfers: []HbarTransfer{
+				{AccountId: firstEntityId, Amount: -10},
+				{AccountId: secondEntityId, Amount: 10},
+			},
+			PayerAccountId: firstEntityId,
+			Result:         transactionResultSuccess,
+			Schedule:       schedule,
+			Scheduled:      true,
+			Type:           typeCryptoTransfer,
+		},
+	}
+	scheduleMetadata := types.NewScheduleFromDomain(schedule).ToMetadata()
+	expected := types.OperationSlice{
+		{
+			AccountId: nodeAccountId,
+			Amount:    &types.HbarAmount{Value: 5},
+			Index:     0,
+			Status:    resultSuccess,
+			Type:      types.OperationTypeFee,
+		},
+		{
+			AccountId: firstAccountId,
+			Amount:    &types.HbarAmount{Value: -5},
+			Index:     1,
+			Status:    resultSuccess,
+			Type:      types.OperationTypeFee,
+		},
+		{
+			AccountId: firstAccountId,
+			Index:     2,
+			Metadata:  scheduleMetadata,
+			Status:    resultSuccess,
+			Type:      types.TransactionTypes[42],
+		},
+		{
+			AccountId: firstAccountId,
+			Amount:    &types.HbarAmount{Value: -10},
+			Index:     3,
+			Metadata:  map[string]interface{}{"schedule": scheduleMetadata},
+			Status:    resultSuccess,
+			Type:      types.OperationTypeCryptoTransfer,
+		},
+		{
+			AccountId: secondAccountId,
+			Amount:    &types.HbarAmount{Value: 10},
+			Index:     4,
+			Metadata:  map[string]interface{}{"schedule": scheduleMetadata},
+			Status:    resultSuccess,
+			Type:      types.OperationTypeCryptoTransfer,
+		},
+	}
+
+	// when
+	actual := NewOperationBuilder(config.SystemAccounts{}, false).Build(transactions)
+
+	// then
+	assert.Equal(t, expected, actual)
+}
+
+func TestCompositeOperationBuilderBuildSuppressEmptyOperations(t *testing.T) {
+	// given
+	transactions := []Transaction{
+		{
+			CryptoTransfers: []HbarTransfer{
+				{AccountId: firstEntityId, Amount: -5},
+				{AccountId: nodeEntityId, Amount: 5},
+			},
+			NonFeeTransfers: []HbarTransfer{{AccountId: firstEntityId}},
+			PayerAccountId:  firstEntityId,
+			Result:          transactionResultSuccess,
+			Token: domain.Token{
+				Decimals: tokenDecimals,
+				TokenId:  tokenId1,
+				Type:     domain.TokenTypeFungibleCommon,
+			},
+			Type: 29,
+		},
+	}
+	expected := types.OperationSlice{
+		{
+			AccountId: nodeAccountId,
+			Amount:    &types.HbarAmount{Value: 5},
+			Index:     0,
+			Status:    resultSuccess,
+			Type:      types.OperationTypeFee,
+		},
+		{
+			AccountId: firstAccountId,
+			Amount:    &types.HbarAmount{Value: -5},
+			Index:     1,
+			Status:    resultSuccess,
+			Type:      types.OperationTypeFee,
+		},
+	}
+
+	// when
+	actual := NewOperationBuilder(config.SystemAccounts{}, true).Build(transactions)
+
+	// then
+	assert.Equal(t, expected, actual)
+}
+
+func TestFilterEmptyOperations(t *testing.T) {
+	// given
+	operations := types.OperationSlice{
+		{AccountId: firstAccountId, Index: 0, Type: types.OperationTypeTokenCreate},
+		{AccountId: firstAccountId, Amount: &types.HbarAmount{}, Index: 1, Type: types.OperationTypeCryptoTransfer},
+		{AccountId: firstAccountId, Amount: &types.HbarAmount{Value: -5}, Index: 2, Type: types.OperationTypeFee},
+		{AccountId: nodeAccountId, Amount: &types.HbarAmount{Value: 5}, Index: 3, Type: types.OperationTypeFee},
+	}
+	expected := types.OperationSlice{
+		{AccountId: firstAccountId, Amount: &types.HbarAmount{Value: -5}, Index: 0, Type: types.OperationTypeFee},
+		{AccountId: nodeAccountId, Amount: &types.HbarAmount{Value: 5}, Index: 1, Type: types.OperationTypeFee},
+	}
+
+	// when
+	actual := filterEmptyOperations(operations)
+
+	// then
+	assert.Equal(t, expected, actual)
+}
+
+func TestEstimateOperationCount(t *testing.T) {
+	// given
+	transactions := []Transaction{
+		{
+			CryptoTransfers: []HbarTransfer{{AccountId: firstEntityId}, {AccountId: secondEntityId}},
+			NftTransfers:    []domain.NftTransfer{{ReceiverAccountId: &firstEntityId}},
+			NonFeeTransfers: []HbarTransfer{{AccountId: firstEntityId}},
+		},
+		{TokenTransfers: []TokenTransfer{{AccountId: firstEntityId}}},
+	}
+
+	// when
+	actual := estimateOperationCount(transactions)
+
+	// then
+	assert.Equal(t, 8, actual)
+}
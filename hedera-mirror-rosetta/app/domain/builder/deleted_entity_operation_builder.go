@@ -0,0 +1,60 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package builder
+
+import "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+
+const (
+	metadataKeyDeleted          = "deleted"
+	metadataKeyDeletedTimestamp = "deleted_timestamp"
+)
+
+// deletedEntityOperationBuilder marks the operations of a transaction whose account is deleted at or before the
+// transaction, e.g., the sweep of the remaining balance of a deleted account, with the deleted timestamp
+type deletedEntityOperationBuilder struct{}
+
+func (b *deletedEntityOperationBuilder) build(
+	transaction Transaction,
+	operations types.OperationSlice,
+	start int,
+) types.OperationSlice {
+	if len(transaction.DeletedEntities) == 0 {
+		return operations
+	}
+
+	for i := start; i < len(operations); i++ {
+		deletedTimestamp, ok := transaction.DeletedEntities[operations[i].AccountId.GetId()]
+		if !ok {
+			continue
+		}
+
+		if operations[i].Metadata == nil {
+			operations[i].Metadata = make(map[string]interface{})
+		}
+		operations[i].Metadata[metadataKeyDeleted] = true
+		operations[i].Metadata[metadataKeyDeletedTimestamp] = deletedTimestamp
+	}
+
+	return operations
+}
+
+func newDeletedEntityOperationBuilder() transactionOperationBuilder {
+	return &deletedEntityOperationBuilder{}
+}
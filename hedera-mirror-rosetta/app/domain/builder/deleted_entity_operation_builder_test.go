@@ -0,0 +1,68 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package builder
+
+import (
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeletedEntityOperationBuilderBuild(t *testing.T) {
+	// given
+	transaction := Transaction{
+		DeletedEntities: map[int64]int64{firstEntityId.EncodedId: 100},
+		Type:            typeCryptoTransfer,
+	}
+	operations := types.OperationSlice{
+		{AccountId: firstAccountId, Index: 0, Type: types.OperationTypeCryptoTransfer},
+		{AccountId: firstAccountId, Index: 1, Type: types.OperationTypeCryptoTransfer},
+		{AccountId: secondAccountId, Index: 2, Type: types.OperationTypeCryptoTransfer},
+		{AccountId: firstAccountId, Index: 3, Metadata: map[string]interface{}{"foo": "bar"},
+			Type: types.OperationTypeFee},
+	}
+	expected := types.OperationSlice{
+		{AccountId: firstAccountId, Index: 0, Type: types.OperationTypeCryptoTransfer},
+		{AccountId: firstAccountId, Index: 1, Metadata: map[string]interface{}{"deleted": true,
+			"deleted_timestamp": int64(100)}, Type: types.OperationTypeCryptoTransfer},
+		{AccountId: secondAccountId, Index: 2, Type: types.OperationTypeCryptoTransfer},
+		{AccountId: firstAccountId, Index: 3, Metadata: map[string]interface{}{"deleted": true,
+			"deleted_timestamp": int64(100), "foo": "bar"}, Type: types.OperationTypeFee},
+	}
+
+	// when
+	actual := newDeletedEntityOperationBuilder().build(transaction, operations, 1)
+
+	// then
+	assert.Equal(t, expected, actual)
+}
+
+func TestDeletedEntityOperationBuilderBuildNoDeletedEntity(t *testing.T) {
+	// given
+	operations := types.OperationSlice{{AccountId: firstAccountId, Index: 0, Type: types.OperationTypeCryptoTransfer}}
+	expected := types.OperationSlice{{AccountId: firstAccountId, Index: 0, Type: types.OperationTypeCryptoTransfer}}
+
+	// when
+	actual := newDeletedEntityOperationBuilder().build(Transaction{Type: typeCryptoTransfer}, operations, 0)
+
+	// then
+	assert.Equal(t, expected, actual)
+}
@@ -0,0 +1,141 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package builder
+
+import (
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-protobufs-go/services"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+)
+
+// FeeBreakdownBuilder builds the breakdown of the fee charged to the payer of a transaction
+type FeeBreakdownBuilder interface {
+	// Build builds the fee breakdown of the transactions sharing the same hash, e.g., a parent and its child records.
+	// Returns nil if none of the transactions is charged a fee
+	Build(transactions []Transaction) *types.FeeBreakdown
+}
+
+type feeBreakdownBuilder struct {
+	systemAccounts map[int64]string
+}
+
+func (b *feeBreakdownBuilder) Build(transactions []Transaction) *types.FeeBreakdown {
+	var breakdown *types.FeeBreakdown
+	for _, transaction := range transactions {
+		if transaction.ChargedTxFee == 0 {
+			continue
+		}
+
+		current, err := b.buildFromRecord(transaction)
+		if err != nil {
+			log.Warnf("Failed to decode transaction record, approximating fee breakdown from transfers: %s", err)
+		}
+		if current == nil {
+			current = b.buildFromTransfers(transaction)
+		}
+
+		if breakdown == nil {
+			breakdown = &types.FeeBreakdown{}
+		}
+		breakdown.Add(*current)
+	}
+
+	return breakdown
+}
+
+// buildFromRecord builds the fee breakdown from the transfer list and the transaction fee in the transaction record.
+// Returns nil if the record bytes aren't available
+func (b *feeBreakdownBuilder) buildFromRecord(transaction Transaction) (*types.FeeBreakdown, error) {
+	if len(transaction.RecordBytes) == 0 {
+		return nil, nil
+	}
+
+	var record services.TransactionRecord
+	if err := proto.Unmarshal(transaction.RecordBytes, &record); err != nil {
+		return nil, err
+	}
+
+	accountAmounts := record.GetTransferList().GetAccountAmounts()
+	transfers := make([]HbarTransfer, 0, len(accountAmounts))
+	for _, accountAmount := range accountAmounts {
+		account := accountAmount.GetAccountID()
+		accountId, err := domain.EntityIdOf(account.GetShardNum(), account.GetRealmNum(), account.GetAccountNum())
+		if err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, HbarTransfer{AccountId: accountId, Amount: accountAmount.GetAmount()})
+	}
+
+	breakdown := &types.FeeBreakdown{
+		Source:   types.FeeBreakdownSourceRecord,
+		TotalFee: int64(record.GetTransactionFee()),
+	}
+	nonFeeTransferMap := aggregateNonFeeTransfers(transaction.NonFeeTransfers)
+	for _, transfer := range getFeeHbarTransfers(transfers, nonFeeTransferMap) {
+		if transfer.Amount <= 0 {
+			continue
+		}
+
+		if isNodeAccount(transaction, transfer.AccountId) {
+			breakdown.NodeFee += transfer.Amount
+			continue
+		}
+
+		switch b.systemAccounts[transfer.AccountId.EncodedId] {
+		case config.SystemAccountFeeCollection:
+			breakdown.NetworkFee += transfer.Amount
+		case config.SystemAccountNodeReward, config.SystemAccountStakingReward:
+			breakdown.ServiceFee += transfer.Amount
+		}
+	}
+
+	return breakdown, nil
+}
+
+// buildFromTransfers approximates the fee breakdown when the record bytes aren't available. The node fee is the fee
+// credited to the node account, and the rest of the charged fee is attributed to the network fee
+func (b *feeBreakdownBuilder) buildFromTransfers(transaction Transaction) *types.FeeBreakdown {
+	breakdown := &types.FeeBreakdown{
+		Source:   types.FeeBreakdownSourceTransfers,
+		TotalFee: transaction.ChargedTxFee,
+	}
+	nonFeeTransferMap := aggregateNonFeeTransfers(transaction.NonFeeTransfers)
+	for _, transfer := range getFeeHbarTransfers(transaction.CryptoTransfers, nonFeeTransferMap) {
+		if transfer.Amount > 0 && isNodeAccount(transaction, transfer.AccountId) {
+			breakdown.NodeFee += transfer.Amount
+		}
+	}
+	breakdown.NetworkFee = breakdown.TotalFee - breakdown.NodeFee
+
+	return breakdown
+}
+
+func isNodeAccount(transaction Transaction, accountId domain.EntityId) bool {
+	return transaction.NodeAccountId != nil && transaction.NodeAccountId.EncodedId == accountId.EncodedId
+}
+
+// NewFeeBreakdownBuilder creates a FeeBreakdownBuilder which attributes the fees credited to the system accounts
+func NewFeeBreakdownBuilder(systemAccounts config.SystemAccounts) FeeBreakdownBuilder {
+	return &feeBreakdownBuilder{systemAccounts: toSystemAccountMap(systemAccounts)}
+}
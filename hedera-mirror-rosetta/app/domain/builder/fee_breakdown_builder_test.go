@@ -0,0 +1,209 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-protobufs-go/services"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	feeBreakdownSystemAccounts = config.SystemAccounts{
+		FeeCollection: "0.0.98",
+		NodeReward:    "0.0.801",
+		StakingReward: "0.0.800",
+	}
+	nodeRewardEntityId    = domain.MustDecodeEntityId(801)
+	stakingRewardEntityId = domain.MustDecodeEntityId(800)
+)
+
+func TestFeeBreakdownBuilderBuild(t *testing.T) {
+	recordBytes := getRecordBytes(t, 20, map[int64]int64{
+		firstEntityId.EncodedId:         -120,
+		secondEntityId.EncodedId:        100,
+		nodeEntityId.EncodedId:          5,
+		feeCollectorEntityId.EncodedId:  10,
+		nodeRewardEntityId.EncodedId:    2,
+		stakingRewardEntityId.EncodedId: 3,
+	})
+	cryptoTransfers := []HbarTransfer{
+		{AccountId: firstEntityId, Amount: -120},
+		{AccountId: secondEntityId, Amount: 100},
+		{AccountId: nodeEntityId, Amount: 5},
+		{AccountId: feeCollectorEntityId, Amount: 10},
+		{AccountId: nodeRewardEntityId, Amount: 2},
+		{AccountId: stakingRewardEntityId, Amount: 3},
+	}
+	nonFeeTransfers := []HbarTransfer{
+		{AccountId: firstEntityId, Amount: -100},
+		{AccountId: secondEntityId, Amount: 100},
+	}
+	fromRecord := types.FeeBreakdown{
+		NetworkFee: 10,
+		NodeFee:    5,
+		ServiceFee: 5,
+		Source:     types.FeeBreakdownSourceRecord,
+		TotalFee:   20,
+	}
+	fromTransfers := types.FeeBreakdown{
+		NetworkFee: 15,
+		NodeFee:    5,
+		Source:     types.FeeBreakdownSourceTransfers,
+		TotalFee:   20,
+	}
+
+	var tests = []struct {
+		name         string
+		transactions []Transaction
+		expected     *types.FeeBreakdown
+	}{
+		{
+			name: "record",
+			transactions: []Transaction{
+				{
+					ChargedTxFee:    20,
+					CryptoTransfers: cryptoTransfers,
+					NodeAccountId:   &nodeEntityId,
+					NonFeeTransfers: nonFeeTransfers,
+					RecordBytes:     recordBytes,
+				},
+			},
+			expected: &fromRecord,
+		},
+		{
+			name: "transfers",
+			transactions: []Transaction{
+				{
+					ChargedTxFee:    20,
+					CryptoTransfers: cryptoTransfers,
+					NodeAccountId:   &nodeEntityId,
+					NonFeeTransfers: nonFeeTransfers,
+				},
+			},
+			expected: &fromTransfers,
+		},
+		{
+			name: "invalid record",
+			transactions: []Transaction{
+				{
+					ChargedTxFee:    20,
+					CryptoTransfers: cryptoTransfers,
+					NodeAccountId:   &nodeEntityId,
+					NonFeeTransfers: nonFeeTransfers,
+					RecordBytes:     []byte{0xff},
+				},
+			},
+			expected: &fromTransfers,
+		},
+		{
+			name: "no node account",
+			transactions: []Transaction{
+				{ChargedTxFee: 20, CryptoTransfers: cryptoTransfers, NonFeeTransfers: nonFeeTransfers},
+			},
+			expected: &types.FeeBreakdown{NetworkFee: 20, Source: types.FeeBreakdownSourceTransfers, TotalFee: 20},
+		},
+		{
+			name: "parent and child",
+			transactions: []Transaction{
+				{
+					ChargedTxFee:    20,
+					CryptoTransfers: cryptoTransfers,
+					NodeAccountId:   &nodeEntityId,
+					NonFeeTransfers: nonFeeTransfers,
+					RecordBytes:     recordBytes,
+				},
+				{CryptoTransfers: []HbarTransfer{{AccountId: secondEntityId, Amount: -1}}},
+			},
+			expected: &fromRecord,
+		},
+		{
+			name: "record and duplicate without record",
+			transactions: []Transaction{
+				{
+					ChargedTxFee:    20,
+					CryptoTransfers: cryptoTransfers,
+					NodeAccountId:   &nodeEntityId,
+					NonFeeTransfers: nonFeeTransfers,
+					RecordBytes:     recordBytes,
+				},
+				{
+					ChargedTxFee: 6,
+					CryptoTransfers: []HbarTransfer{
+						{AccountId: firstEntityId, Amount: -6},
+						{AccountId: nodeEntityId, Amount: 6},
+					},
+					NodeAccountId: &nodeEntityId,
+				},
+			},
+			expected: &types.FeeBreakdown{
+				NetworkFee: 10,
+				NodeFee:    11,
+				ServiceFee: 5,
+				Source:     types.FeeBreakdownSourceTransfers,
+				TotalFee:   26,
+			},
+		},
+		{
+			name:         "no fee",
+			transactions: []Transaction{{CryptoTransfers: cryptoTransfers, NodeAccountId: &nodeEntityId}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			builder := NewFeeBreakdownBuilder(feeBreakdownSystemAccounts)
+
+			// when
+			actual := builder.Build(tt.transactions)
+
+			// then
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func getRecordBytes(t *testing.T, transactionFee uint64, transfers map[int64]int64) []byte {
+	accountAmounts := make([]*services.AccountAmount, 0, len(transfers))
+	for encodedId, amount := range transfers {
+		accountId := domain.MustDecodeEntityId(encodedId)
+		accountAmounts = append(accountAmounts, &services.AccountAmount{
+			AccountID: &services.AccountID{
+				ShardNum: accountId.ShardNum,
+				RealmNum: accountId.RealmNum,
+				Account:  &services.AccountID_AccountNum{AccountNum: accountId.EntityNum},
+			},
+			Amount: amount,
+		})
+	}
+	recordBytes, err := proto.Marshal(&services.TransactionRecord{
+		TransactionFee: transactionFee,
+		TransferList:   &services.TransferList{AccountAmounts: accountAmounts},
+	})
+	assert.NoError(t, err)
+	return recordBytes
+}
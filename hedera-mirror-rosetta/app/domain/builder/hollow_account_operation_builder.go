@@ -0,0 +1,58 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package builder
+
+import (
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// hollowAccountOperationBuilder builds the operation of the key assignment when a transaction completes its payer as a
+// hollow account, i.e., an account auto created with an evm address alias and an empty key
+type hollowAccountOperationBuilder struct{}
+
+func (b *hollowAccountOperationBuilder) build(
+	transaction Transaction,
+	operations types.OperationSlice,
+	_ int,
+) types.OperationSlice {
+	if len(transaction.HollowAccountKey) == 0 {
+		return operations
+	}
+
+	key, err := types.NewKeyFromBytes(transaction.HollowAccountKey)
+	if err != nil {
+		log.Warnf("Failed to parse the key of the completed hollow account %s: %s", transaction.PayerAccountId.String(), err)
+		return operations
+	}
+
+	return append(operations, types.Operation{
+		AccountId: types.NewAccountIdFromEntityId(transaction.PayerAccountId),
+		Index:     int64(len(operations)),
+		Metadata:  key.ToMetadata(),
+		Status:    transaction.getResult(),
+		Type:      types.OperationTypeHollowAccountCompletion,
+	})
+}
+
+func newHollowAccountOperationBuilder() transactionOperationBuilder {
+	return &hollowAccountOperationBuilder{}
+}
@@ -0,0 +1,89 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// ecdsaSecp256k1Key is the protobuf-encoded ECDSA secp256k1 key 0x03d9a822b91df7850274273a338c152e7bcfa2036b24cd9e3b29d07efd949b387a
+var ecdsaSecp256k1Key = []byte{
+	0x3a, 0x21, 0x03, 0xd9, 0xa8, 0x22, 0xb9, 0x1d, 0xf7, 0x85, 0x02, 0x74, 0x27, 0x3a, 0x33, 0x8c, 0x15, 0x2e, 0x7b,
+	0xcf, 0xa2, 0x03, 0x6b, 0x24, 0xcd, 0x9e, 0x3b, 0x29, 0xd0, 0x7e, 0xfd, 0x94, 0x9b, 0x38, 0x7a,
+}
+
+func TestHollowAccountOperationBuilderBuild(t *testing.T) {
+	// given
+	transaction := Transaction{
+		HollowAccountKey: ecdsaSecp256k1Key,
+		PayerAccountId:   firstEntityId,
+		Result:           transactionResultSuccess,
+		Type:             typeCryptoTransfer,
+	}
+	operations := types.OperationSlice{{Index: 0, Type: types.OperationTypeFee}}
+	key, _ := types.NewKeyFromBytes(ecdsaSecp256k1Key)
+	expected := append(operations, types.Operation{
+		AccountId: firstAccountId,
+		Index:     1,
+		Metadata:  key.ToMetadata(),
+		Status:    resultSuccess,
+		Type:      types.OperationTypeHollowAccountCompletion,
+	})
+
+	// when
+	actual := newHollowAccountOperationBuilder().build(transaction, operations, 0)
+
+	// then
+	assert.Equal(t, expected, actual)
+	assert.Equal(t, types.KeyTypeEcdsaSecp256k1, actual[1].Metadata["key_type"])
+}
+
+func TestHollowAccountOperationBuilderBuildNoop(t *testing.T) {
+	tests := []struct {
+		name string
+		key  []byte
+	}{
+		{name: "no hollow account key"},
+		{name: "invalid key", key: []byte{0xff}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			transaction := Transaction{
+				HollowAccountKey: tt.key,
+				PayerAccountId:   firstEntityId,
+				Result:           transactionResultSuccess,
+				Type:             typeCryptoTransfer,
+			}
+			operations := types.OperationSlice{{Index: 0, Type: types.OperationTypeFee}}
+
+			// when
+			actual := newHollowAccountOperationBuilder().build(transaction, operations, 0)
+
+			// then
+			assert.Equal(t, types.OperationSlice{{Index: 0, Type: types.OperationTypeFee}}, actual)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package builder
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+)
+
+// InvariantChecker checks the invariants the transfers of a transaction must hold, to catch importer data bugs early
+type InvariantChecker interface {
+	// Check returns the invariants violated by the transactions sharing the same hash. Each transaction is checked on
+	// its own since a child record has its own transfer list
+	Check(transactions []Transaction) []string
+}
+
+type invariantChecker struct{}
+
+func (c *invariantChecker) Check(transactions []Transaction) []string {
+	violations := make([]string, 0)
+	for _, transaction := range transactions {
+		violations = append(violations, checkHbarTransfers(transaction)...)
+		violations = append(violations, checkTokenTransfers(transaction)...)
+	}
+	return violations
+}
+
+// checkHbarTransfers checks the hbar transfers, which include the fees, sum to zero
+func checkHbarTransfers(transaction Transaction) []string {
+	sum := int64(0)
+	for _, transfer := range transaction.CryptoTransfers {
+		sum += transfer.Amount
+	}
+
+	if sum == 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("hbar transfers of %s transaction sum to %d instead of 0",
+		transaction.getOperationType(), sum)}
+}
+
+// checkTokenTransfers checks the transfers of each fungible token sum to zero, except for a successful transaction
+// which changes the total supply. The transfers of a mint sum to a positive amount, the transfers of a burn, a wipe,
+// or a dissociate from a deleted token sum to a negative amount, and the transfers of a token create sum to the
+// initial supply of the token
+func checkTokenTransfers(transaction Transaction) []string {
+	sums := make(map[domain.EntityId]int64)
+	for _, transfer := range transaction.TokenTransfers {
+		// same as the operations, the wiped amount of a deleted nft class is ignored
+		if transfer.Type != domain.TokenTypeFungibleCommon {
+			continue
+		}
+		sums[transfer.TokenId] += transfer.Amount
+	}
+
+	tokenIds := make([]domain.EntityId, 0, len(sums))
+	for tokenId := range sums {
+		tokenIds = append(tokenIds, tokenId)
+	}
+	sort.Slice(tokenIds, func(i, j int) bool { return tokenIds[i].EncodedId < tokenIds[j].EncodedId })
+
+	operationType := transaction.getOperationType()
+	success := transaction.Result == transactionResultSuccess
+	violations := make([]string, 0)
+	for _, tokenId := range tokenIds {
+		sum := sums[tokenId]
+		expected, ok := "0", sum == 0
+		if success {
+			switch operationType {
+			case types.OperationTypeTokenMint:
+				expected, ok = "a positive amount", sum > 0
+			case types.OperationTypeTokenBurn, types.OperationTypeTokenDissociate, types.OperationTypeTokenWipe:
+				expected, ok = "a negative amount", sum < 0
+			case types.OperationTypeTokenCreate:
+				if tokenId == transaction.Token.TokenId {
+					initialSupply := transaction.Token.InitialSupply
+					expected, ok = fmt.Sprintf("the initial supply %d", initialSupply), sum == initialSupply
+				}
+			}
+		}
+
+		if !ok {
+			violations = append(violations, fmt.Sprintf("token %s transfers of %s transaction sum to %d instead of %s",
+				tokenId.String(), operationType, sum, expected))
+		}
+	}
+	return violations
+}
+
+// NewInvariantChecker creates an InvariantChecker
+func NewInvariantChecker() InvariantChecker {
+	return &invariantChecker{}
+}
@@ -0,0 +1,225 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package builder
+
+import (
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	typeTokenBurn   int32 = 38
+	typeTokenCreate int32 = 29
+	typeTokenMint   int32 = 37
+)
+
+func TestInvariantCheckerCheck(t *testing.T) {
+	feeTransfers := []HbarTransfer{
+		{AccountId: firstEntityId, Amount: -15},
+		{AccountId: nodeEntityId, Amount: 5},
+		{AccountId: feeCollectorEntityId, Amount: 10},
+	}
+	tokenTransfer := func(tokenId, accountId domain.EntityId, amount int64) TokenTransfer {
+		return TokenTransfer{
+			AccountId: accountId,
+			Amount:    amount,
+			TokenId:   tokenId,
+			Type:      domain.TokenTypeFungibleCommon,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		transaction Transaction
+		expected    []string
+	}{
+		{
+			name: "balanced crypto transfer",
+			transaction: Transaction{
+				CryptoTransfers: append([]HbarTransfer{
+					{AccountId: firstEntityId, Amount: -100},
+					{AccountId: secondEntityId, Amount: 100},
+				}, feeTransfers...),
+				Result: transactionResultSuccess,
+				TokenTransfers: []TokenTransfer{
+					tokenTransfer(tokenId1, firstEntityId, -20),
+					tokenTransfer(tokenId1, secondEntityId, 20),
+				},
+				Type: typeCryptoTransfer,
+			},
+			expected: []string{},
+		},
+		{
+			name: "unbalanced hbar transfers",
+			transaction: Transaction{
+				CryptoTransfers: append([]HbarTransfer{{AccountId: secondEntityId, Amount: 1}}, feeTransfers...),
+				Result:          transactionResultSuccess,
+				Type:            typeCryptoTransfer,
+			},
+			expected: []string{"hbar transfers of CRYPTOTRANSFER transaction sum to 1 instead of 0"},
+		},
+		{
+			name: "unbalanced token transfers",
+			transaction: Transaction{
+				CryptoTransfers: feeTransfers,
+				Result:          transactionResultSuccess,
+				TokenTransfers: []TokenTransfer{
+					tokenTransfer(tokenId2, firstEntityId, -20),
+					tokenTransfer(tokenId2, secondEntityId, 21),
+					tokenTransfer(tokenId1, firstEntityId, -20),
+					tokenTransfer(tokenId1, secondEntityId, 20),
+				},
+				Type: typeCryptoTransfer,
+			},
+			expected: []string{"token 0.0.26700 transfers of CRYPTOTRANSFER transaction sum to 1 instead of 0"},
+		},
+		{
+			name: "wiped nft class ignored",
+			transaction: Transaction{
+				CryptoTransfers: feeTransfers,
+				Result:          transactionResultSuccess,
+				TokenTransfers: []TokenTransfer{
+					{AccountId: firstEntityId, Amount: -2, TokenId: tokenId3, Type: domain.TokenTypeNonFungibleUnique},
+				},
+				Type: 41,
+			},
+			expected: []string{},
+		},
+		{
+			name: "mint",
+			transaction: Transaction{
+				CryptoTransfers: feeTransfers,
+				Result:          transactionResultSuccess,
+				TokenTransfers:  []TokenTransfer{tokenTransfer(tokenId1, firstEntityId, 50)},
+				Type:            typeTokenMint,
+			},
+			expected: []string{},
+		},
+		{
+			name: "mint with negative amount",
+			transaction: Transaction{
+				CryptoTransfers: feeTransfers,
+				Result:          transactionResultSuccess,
+				TokenTransfers:  []TokenTransfer{tokenTransfer(tokenId1, firstEntityId, -50)},
+				Type:            typeTokenMint,
+			},
+			expected: []string{
+				"token 0.0.25636 transfers of TOKENMINT transaction sum to -50 instead of a positive amount",
+			},
+		},
+		{
+			name: "failed mint with token transfers",
+			transaction: Transaction{
+				CryptoTransfers: feeTransfers,
+				Result:          transactionResultFail,
+				TokenTransfers:  []TokenTransfer{tokenTransfer(tokenId1, firstEntityId, 50)},
+				Type:            typeTokenMint,
+			},
+			expected: []string{"token 0.0.25636 transfers of TOKENMINT transaction sum to 50 instead of 0"},
+		},
+		{
+			name: "burn",
+			transaction: Transaction{
+				CryptoTransfers: feeTransfers,
+				Result:          transactionResultSuccess,
+				TokenTransfers:  []TokenTransfer{tokenTransfer(tokenId1, firstEntityId, -50)},
+				Type:            typeTokenBurn,
+			},
+			expected: []string{},
+		},
+		{
+			name: "burn with positive amount",
+			transaction: Transaction{
+				CryptoTransfers: feeTransfers,
+				Result:          transactionResultSuccess,
+				TokenTransfers:  []TokenTransfer{tokenTransfer(tokenId1, firstEntityId, 50)},
+				Type:            typeTokenBurn,
+			},
+			expected: []string{
+				"token 0.0.25636 transfers of TOKENBURN transaction sum to 50 instead of a negative amount",
+			},
+		},
+		{
+			name: "token create",
+			transaction: Transaction{
+				CryptoTransfers: feeTransfers,
+				Result:          transactionResultSuccess,
+				Token:           domain.Token{InitialSupply: 100, TokenId: tokenId1},
+				TokenTransfers:  []TokenTransfer{tokenTransfer(tokenId1, firstEntityId, 100)},
+				Type:            typeTokenCreate,
+			},
+			expected: []string{},
+		},
+		{
+			name: "token create with mismatched initial supply",
+			transaction: Transaction{
+				CryptoTransfers: feeTransfers,
+				Result:          transactionResultSuccess,
+				Token:           domain.Token{InitialSupply: 100, TokenId: tokenId1},
+				TokenTransfers:  []TokenTransfer{tokenTransfer(tokenId1, firstEntityId, 90)},
+				Type:            typeTokenCreate,
+			},
+			expected: []string{
+				"token 0.0.25636 transfers of TOKENCREATION transaction sum to 90 instead of the initial supply 100",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			checker := NewInvariantChecker()
+
+			// when
+			actual := checker.Check([]Transaction{tt.transaction})
+
+			// then
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestInvariantCheckerCheckMultipleTransactions(t *testing.T) {
+	// given
+	transactions := []Transaction{
+		{
+			CryptoTransfers: []HbarTransfer{{AccountId: firstEntityId, Amount: -1}},
+			Result:          transactionResultSuccess,
+			Type:            typeCryptoTransfer,
+		},
+		{
+			CryptoTransfers: []HbarTransfer{{AccountId: firstEntityId, Amount: 1}},
+			Result:          transactionResultSuccess,
+			Type:            typeCryptoTransfer,
+		},
+	}
+	expected := []string{
+		"hbar transfers of CRYPTOTRANSFER transaction sum to -1 instead of 0",
+		"hbar transfers of CRYPTOTRANSFER transaction sum to 1 instead of 0",
+	}
+
+	// when
+	actual := NewInvariantChecker().Check(transactions)
+
+	// then
+	assert.Equal(t, expected, actual)
+}
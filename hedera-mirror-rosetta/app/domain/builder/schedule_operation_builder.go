@@ -0,0 +1,73 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package builder
+
+import (
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+)
+
+// scheduleOperationBuilder builds the operation of a ScheduleCreate, ScheduleDelete, or ScheduleSign transaction, and
+// adds the schedule metadata to the operations of a scheduled transaction
+type scheduleOperationBuilder struct{}
+
+func (b *scheduleOperationBuilder) build(
+	transaction Transaction,
+	operations types.OperationSlice,
+	start int,
+) types.OperationSlice {
+	schedule := transaction.Schedule
+	if schedule.ScheduleId.IsZero() {
+		return operations
+	}
+
+	if transaction.Scheduled {
+		addScheduleMetadata(operations[start:], schedule)
+		return operations
+	}
+
+	// only for ScheduleCreate, ScheduleDelete, and ScheduleSign, the entity id is the schedule id
+	return append(operations, types.Operation{
+		AccountId: types.NewAccountIdFromEntityId(transaction.PayerAccountId),
+		Index:     int64(len(operations)),
+		Metadata:  types.NewScheduleFromDomain(schedule).ToMetadata(),
+		Status:    transaction.getResult(),
+		Type:      transaction.getOperationType(),
+	})
+}
+
+func addScheduleMetadata(operations types.OperationSlice, schedule domain.Schedule) {
+	metadata := types.NewScheduleFromDomain(schedule).ToMetadata()
+	for i := range operations {
+		if operations[i].Type == types.OperationTypeFee {
+			continue
+		}
+
+		if operations[i].Metadata == nil {
+			operations[i].Metadata = make(map[string]interface{})
+		}
+		operations[i].Metadata["schedule"] = metadata
+	}
+}
+
+func newScheduleOperationBuilder() transactionOperationBuilder {
+	return &scheduleOperationBuilder{}
+}
@@ -0,0 +1,99 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+var schedule = domain.Schedule{
+	ConsensusTimestamp: 100,
+	CreatorAccountId:   firstEntityId,
+	PayerAccountId:     firstEntityId,
+	ScheduleId:         scheduleId,
+}
+
+func TestScheduleOperationBuilderBuild(t *testing.T) {
+	// given
+	transaction := Transaction{PayerAccountId: firstEntityId, Result: transactionResultSuccess, Schedule: schedule,
+		Type: 42}
+	operations := types.OperationSlice{{Index: 0, Type: types.OperationTypeFee}}
+	expected := append(operations, types.Operation{
+		AccountId: firstAccountId,
+		Index:     1,
+		Metadata:  types.NewScheduleFromDomain(schedule).ToMetadata(),
+		Status:    resultSuccess,
+		Type:      types.TransactionTypes[42],
+	})
+
+	// when
+	actual := newScheduleOperationBuilder().build(transaction, operations, 0)
+
+	// then
+	assert.Equal(t, expected, actual)
+}
+
+func TestScheduleOperationBuilderBuildScheduled(t *testing.T) {
+	// given
+	transaction := Transaction{
+		PayerAccountId: firstEntityId,
+		Result:         transactionResultSuccess,
+		Schedule:       schedule,
+		Scheduled:      true,
+		Type:           typeCryptoTransfer,
+	}
+	operations := types.OperationSlice{
+		{AccountId: secondAccountId, Index: 0, Type: types.OperationTypeCryptoTransfer},
+		{AccountId: firstAccountId, Index: 1, Metadata: map[string]interface{}{"foo": "bar"},
+			Type: types.OperationTypeCryptoTransfer},
+		{AccountId: nodeAccountId, Index: 2, Type: types.OperationTypeFee},
+	}
+	metadata := types.NewScheduleFromDomain(schedule).ToMetadata()
+	expected := types.OperationSlice{
+		{AccountId: secondAccountId, Index: 0, Type: types.OperationTypeCryptoTransfer},
+		{AccountId: firstAccountId, Index: 1, Metadata: map[string]interface{}{"foo": "bar", "schedule": metadata},
+			Type: types.OperationTypeCryptoTransfer},
+		{AccountId: nodeAccountId, Index: 2, Type: types.OperationTypeFee},
+	}
+
+	// when
+	actual := newScheduleOperationBuilder().build(transaction, operations, 1)
+
+	// then
+	assert.Equal(t, expected, actual)
+}
+
+func TestScheduleOperationBuilderBuildNoSchedule(t *testing.T) {
+	// given
+	transaction := Transaction{PayerAccountId: firstEntityId, Result: transactionResultSuccess, Scheduled: true,
+		Type: typeCryptoTransfer}
+	operations := types.OperationSlice{{Index: 0, Type: types.OperationTypeCryptoTransfer}}
+
+	// when
+	actual := newScheduleOperationBuilder().build(transaction, operations, 0)
+
+	// then
+	assert.Equal(t, types.OperationSlice{{Index: 0, Type: types.OperationTypeCryptoTransfer}}, actual)
+}
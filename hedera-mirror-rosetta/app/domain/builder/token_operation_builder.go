@@ -0,0 +1,55 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package builder
+
+import "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+
+// tokenOperationBuilder builds the operation of a TokenCreate, TokenDeletion, or TokenUpdate transaction, the only
+// transactions with the token set
+type tokenOperationBuilder struct{}
+
+func (b *tokenOperationBuilder) build(
+	transaction Transaction,
+	operations types.OperationSlice,
+	_ int,
+) types.OperationSlice {
+	token := transaction.Token
+	if token.TokenId.IsZero() {
+		return operations
+	}
+
+	metadata := make(map[string]interface{})
+	metadata["currency"] = types.Token{Token: token}.ToRosettaCurrency()
+	metadata["freeze_default"] = token.FreezeDefault
+	metadata["initial_supply"] = token.InitialSupply
+
+	return append(operations, types.Operation{
+		AccountId: types.NewAccountIdFromEntityId(transaction.PayerAccountId),
+		Index:     int64(len(operations)),
+		Metadata:  metadata,
+		Status:    transaction.getResult(),
+		Type:      transaction.getOperationType(),
+	})
+}
+
+func newTokenOperationBuilder() transactionOperationBuilder {
+	return &tokenOperationBuilder{}
+}
@@ -0,0 +1,71 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenOperationBuilderBuild(t *testing.T) {
+	// given
+	token := domain.Token{
+		Decimals:      tokenDecimals,
+		FreezeDefault: true,
+		InitialSupply: 1000,
+		TokenId:       tokenId1,
+		Type:          domain.TokenTypeFungibleCommon,
+	}
+	transaction := Transaction{PayerAccountId: firstEntityId, Result: transactionResultFail, Token: token, Type: 29}
+	operations := types.OperationSlice{{Index: 0, Type: types.OperationTypeFee}}
+	expected := append(operations, types.Operation{
+		AccountId: firstAccountId,
+		Index:     1,
+		Metadata: map[string]interface{}{
+			"currency":       types.Token{Token: token}.ToRosettaCurrency(),
+			"freeze_default": true,
+			"initial_supply": int64(1000),
+		},
+		Status: resultFail,
+		Type:   types.OperationTypeTokenCreate,
+	})
+
+	// when
+	actual := newTokenOperationBuilder().build(transaction, operations, 0)
+
+	// then
+	assert.Equal(t, expected, actual)
+}
+
+func TestTokenOperationBuilderBuildNoToken(t *testing.T) {
+	// given
+	transaction := Transaction{PayerAccountId: firstEntityId, Result: transactionResultSuccess, Type: 29}
+	operations := types.OperationSlice{{Index: 0, Type: types.OperationTypeFee}}
+
+	// when
+	actual := newTokenOperationBuilder().build(transaction, operations, 0)
+
+	// then
+	assert.Equal(t, operations, actual)
+}
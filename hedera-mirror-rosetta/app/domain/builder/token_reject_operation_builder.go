@@ -0,0 +1,75 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package builder
+
+import (
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+)
+
+const (
+	metadataKeyTokenReject  = "token_reject"
+	tokenRejectRoleOwner    = "OWNER"
+	tokenRejectRoleTreasury = "TREASURY"
+)
+
+// tokenRejectOperationBuilder marks the token and nft transfer operations of a HIP-904 TokenReject transaction with
+// the role of the account in the rejection, i.e., the owner returning the tokens or the treasury receiving them back.
+// The transfers themselves are in the record, so the balances computed from the transfers stay intact
+type tokenRejectOperationBuilder struct{}
+
+func (b *tokenRejectOperationBuilder) build(
+	transaction Transaction,
+	operations types.OperationSlice,
+	start int,
+) types.OperationSlice {
+	if transaction.Type != int32(domain.TransactionTypeTokenReject) {
+		return operations
+	}
+
+	operationType := transaction.getOperationType()
+	for i := start; i < len(operations); i++ {
+		operation := &operations[i]
+		if operation.Type != operationType {
+			continue
+		}
+
+		if _, ok := operation.Amount.(*types.TokenAmount); !ok {
+			continue
+		}
+
+		role := tokenRejectRoleTreasury
+		if operation.Amount.GetValue() < 0 {
+			role = tokenRejectRoleOwner
+		}
+
+		if operation.Metadata == nil {
+			operation.Metadata = make(map[string]interface{})
+		}
+		operation.Metadata[metadataKeyTokenReject] = role
+	}
+
+	return operations
+}
+
+func newTokenRejectOperationBuilder() transactionOperationBuilder {
+	return &tokenRejectOperationBuilder{}
+}
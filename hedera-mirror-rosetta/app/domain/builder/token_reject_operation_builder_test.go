@@ -0,0 +1,106 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const typeTokenReject int32 = 57
+
+func TestTokenRejectOperationBuilderBuild(t *testing.T) {
+	// given
+	owner := firstEntityId
+	treasury := secondEntityId
+	transaction := Transaction{
+		CryptoTransfers: []HbarTransfer{
+			{AccountId: owner, Amount: -15},
+			{AccountId: nodeEntityId, Amount: 5},
+			{AccountId: feeCollectorEntityId, Amount: 10},
+		},
+		NftTransfers: []domain.NftTransfer{
+			{ReceiverAccountId: &treasury, SenderAccountId: &owner, SerialNumber: 1, TokenId: tokenId3},
+		},
+		PayerAccountId: owner,
+		Result:         transactionResultSuccess,
+		TokenTransfers: []TokenTransfer{
+			{AccountId: owner, Amount: -20, Decimals: tokenDecimals, TokenId: tokenId1, Type: domain.TokenTypeFungibleCommon},
+			{AccountId: treasury, Amount: 20, Decimals: tokenDecimals, TokenId: tokenId1, Type: domain.TokenTypeFungibleCommon},
+		},
+		Type: typeTokenReject,
+	}
+	operationBuilder := NewOperationBuilder(config.SystemAccounts{}, false)
+
+	// when
+	actual := operationBuilder.Build([]Transaction{transaction})
+
+	// then
+	roles := make(map[string]int)
+	for _, operation := range actual {
+		role, ok := operation.Metadata[metadataKeyTokenReject]
+		if operation.Type == types.OperationTypeFee {
+			assert.False(t, ok)
+			continue
+		}
+
+		assert.Equal(t, "TOKENREJECT", operation.Type)
+		assert.IsType(t, &types.TokenAmount{}, operation.Amount)
+		if operation.AccountId.GetId() == owner.EncodedId {
+			assert.Equal(t, tokenRejectRoleOwner, role)
+		} else {
+			assert.Equal(t, treasury.EncodedId, operation.AccountId.GetId())
+			assert.Equal(t, tokenRejectRoleTreasury, role)
+		}
+		roles[role.(string)]++
+	}
+	assert.Equal(t, map[string]int{tokenRejectRoleOwner: 2, tokenRejectRoleTreasury: 2}, roles)
+}
+
+func TestTokenRejectOperationBuilderBuildNoop(t *testing.T) {
+	// given
+	operations := types.OperationSlice{
+		{
+			AccountId: firstAccountId,
+			Amount:    types.NewTokenAmount(domain.Token{TokenId: tokenId1}, -20),
+			Index:     0,
+			Type:      types.OperationTypeCryptoTransfer,
+		},
+	}
+	expected := types.OperationSlice{
+		{
+			AccountId: firstAccountId,
+			Amount:    types.NewTokenAmount(domain.Token{TokenId: tokenId1}, -20),
+			Index:     0,
+			Type:      types.OperationTypeCryptoTransfer,
+		},
+	}
+
+	// when
+	actual := newTokenRejectOperationBuilder().build(Transaction{Type: typeCryptoTransfer}, operations, 0)
+
+	// then
+	assert.Equal(t, expected, actual)
+}
@@ -0,0 +1,106 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package builder
+
+import (
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+)
+
+type transfer interface {
+	getAccountId() domain.EntityId
+	getAmount() types.Amount
+	getIsApproval() bool
+}
+
+type HbarTransfer struct {
+	AccountId  domain.EntityId `json:"account_id"`
+	Amount     int64           `json:"amount"`
+	IsApproval bool            `json:"is_approval"`
+}
+
+func (t HbarTransfer) getAccountId() domain.EntityId {
+	return t.AccountId
+}
+
+func (t HbarTransfer) getAmount() types.Amount {
+	return &types.HbarAmount{Value: t.Amount}
+}
+
+func (t HbarTransfer) getIsApproval() bool {
+	return t.IsApproval
+}
+
+type singleNftTransfer struct {
+	accountId    domain.EntityId
+	isApproval   bool
+	receiver     bool
+	serialNumber int64
+	tokenId      domain.EntityId
+}
+
+func (n singleNftTransfer) getAccountId() domain.EntityId {
+	return n.accountId
+}
+
+func (n singleNftTransfer) getAmount() types.Amount {
+	amount := int64(1)
+	if !n.receiver {
+		amount = -1
+	}
+
+	return &types.TokenAmount{
+		SerialNumbers: []int64{n.serialNumber},
+		TokenId:       n.tokenId,
+		Type:          domain.TokenTypeNonFungibleUnique,
+		Value:         amount,
+	}
+}
+
+func (n singleNftTransfer) getIsApproval() bool {
+	return n.isApproval
+}
+
+type TokenTransfer struct {
+	AccountId  domain.EntityId `json:"account_id"`
+	Amount     int64           `json:"amount"`
+	Decimals   int64           `json:"decimals"`
+	IsApproval bool            `json:"is_approval"`
+	TokenId    domain.EntityId `json:"token_id"`
+	Type       string          `json:"type"`
+}
+
+func (t TokenTransfer) getAccountId() domain.EntityId {
+	return t.AccountId
+}
+
+func (t TokenTransfer) getAmount() types.Amount {
+	return &types.TokenAmount{
+		Decimals: t.Decimals,
+		TokenId:  t.TokenId,
+		Type:     t.Type,
+		Value:    t.Amount,
+	}
+}
+
+func (t TokenTransfer) getIsApproval() bool {
+	return t.IsApproval
+}
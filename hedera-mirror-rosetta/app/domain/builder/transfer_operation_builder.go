@@ -0,0 +1,347 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package builder
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+)
+
+const (
+	metadataKeyIsApproval          = "is_approval"
+	metadataKeySystemAccount       = "system_account"
+	transactionResultSuccess int32 = 22
+
+	// maxPooledTransfersCapacity is the largest transfers buffer returned to the pool, so a rare transaction with a
+	// huge transfer list doesn't pin its buffer
+	maxPooledTransfersCapacity = 4096
+)
+
+// transfersPool pools the scratch buffers the transfers are collected in before they are turned into operations
+var transfersPool = sync.Pool{
+	New: func() interface{} {
+		transfers := make([]transfer, 0, 64)
+		return &transfers
+	},
+}
+
+func acquireTransfers() *[]transfer {
+	return transfersPool.Get().(*[]transfer)
+}
+
+// releaseTransfers clears the buffer so the pool doesn't keep the boxed transfers alive, and returns it to the pool
+func releaseTransfers(buffer *[]transfer) {
+	transfers := *buffer
+	for i := range transfers {
+		transfers[i] = nil
+	}
+
+	if cap(transfers) > maxPooledTransfersCapacity {
+		return
+	}
+
+	*buffer = transfers[:0]
+	transfersPool.Put(buffer)
+}
+
+// transferOperationBuilder builds the hbar, fungible token, and nft transfer operations, and the fee operations
+type transferOperationBuilder struct {
+	systemAccounts map[int64]string
+}
+
+func (b *transferOperationBuilder) build(
+	transaction Transaction,
+	operations types.OperationSlice,
+	_ int,
+) types.OperationSlice {
+	sortHbarTransfers(transaction.CryptoTransfers)
+	sortHbarTransfers(transaction.NonFeeTransfers)
+	sortTokenTransfers(transaction.TokenTransfers)
+	sortNftTransfers(transaction.NftTransfers)
+
+	feeHbarTransfers, nonFeeTransfers := categorizeHbarTransfers(transaction.CryptoTransfers,
+		transaction.NonFeeTransfers)
+	markApprovedHbarTransfers(transaction.CryptoTransfers, nonFeeTransfers)
+
+	transactionResult := transaction.getResult()
+	operationType := transaction.getOperationType()
+	success := types.TransactionResults[transactionResultSuccess]
+
+	operations = appendHbarTransferOperations(transactionResult, operationType, nonFeeTransfers, operations)
+	// crypto transfers are always successful regardless of the transaction result
+	feeStart := len(operations)
+	operations = appendHbarTransferOperations(success, types.OperationTypeFee, feeHbarTransfers, operations)
+	b.addSystemAccountMetadata(operations[feeStart:])
+	operations = appendTokenTransferOperations(transactionResult, operationType, transaction.TokenTransfers, operations)
+	return appendNftTransferOperations(transactionResult, operationType, transaction.NftTransfers, operations)
+}
+
+// addSystemAccountMetadata marks the fee operations credited to or debited from a system account, e.g., the fee
+// collection account, with the role of the system account
+func (b *transferOperationBuilder) addSystemAccountMetadata(operations types.OperationSlice) {
+	for i := range operations {
+		role, ok := b.systemAccounts[operations[i].AccountId.GetId()]
+		if !ok {
+			continue
+		}
+
+		if operations[i].Metadata == nil {
+			operations[i].Metadata = make(map[string]interface{})
+		}
+		operations[i].Metadata[metadataKeySystemAccount] = role
+	}
+}
+
+func appendHbarTransferOperations(
+	transactionResult string,
+	operationType string,
+	hbarTransfers []HbarTransfer,
+	operations types.OperationSlice,
+) types.OperationSlice {
+	buffer := acquireTransfers()
+	defer releaseTransfers(buffer)
+
+	for _, hbarTransfer := range hbarTransfers {
+		*buffer = append(*buffer, hbarTransfer)
+	}
+
+	return appendTransferOperations(transactionResult, operationType, *buffer, operations)
+}
+
+func appendNftTransferOperations(
+	transactionResult string,
+	operationType string,
+	nftTransfers []domain.NftTransfer,
+	operations types.OperationSlice,
+) types.OperationSlice {
+	buffer := acquireTransfers()
+	defer releaseTransfers(buffer)
+
+	for _, nftTransfer := range nftTransfers {
+		*buffer = appendSingleNftTransfers(*buffer, nftTransfer)
+	}
+
+	return appendTransferOperations(transactionResult, operationType, *buffer, operations)
+}
+
+func appendTokenTransferOperations(
+	transactionResult string,
+	operationType string,
+	tokenTransfers []TokenTransfer,
+	operations types.OperationSlice,
+) types.OperationSlice {
+	buffer := acquireTransfers()
+	defer releaseTransfers(buffer)
+
+	for _, tokenTransfer := range tokenTransfers {
+		// The wiped amount of a deleted NFT class by a TokenDissociate is presented as tokenTransferList and
+		// saved to token_transfer table, filter it
+		if tokenTransfer.Type != domain.TokenTypeFungibleCommon {
+			continue
+		}
+
+		*buffer = append(*buffer, tokenTransfer)
+	}
+
+	return appendTransferOperations(transactionResult, operationType, *buffer, operations)
+}
+
+func appendTransferOperations(
+	transactionResult string,
+	operationType string,
+	transfers []transfer,
+	operations types.OperationSlice,
+) types.OperationSlice {
+	for _, transfer := range transfers {
+		var metadata map[string]interface{}
+		transferOperationType := operationType
+		if transfer.getIsApproval() {
+			// the transfer is spent from the owner's allowance granted to the payer
+			metadata = map[string]interface{}{metadataKeyIsApproval: true}
+			transferOperationType = types.OperationTypeApprovedTransfer
+		}
+
+		operations = append(operations, types.Operation{
+			AccountId: types.NewAccountIdFromEntityId(transfer.getAccountId()),
+			Amount:    transfer.getAmount(),
+			Index:     int64(len(operations)),
+			Metadata:  metadata,
+			Status:    transactionResult,
+			Type:      transferOperationType,
+		})
+	}
+	return operations
+}
+
+func categorizeHbarTransfers(hbarTransfers, nonFeeTransfers []HbarTransfer) (
+	feeHbarTransfers, adjustedNonFeeTransfers []HbarTransfer,
+) {
+	entityIds := make(map[int64]struct{})
+	for _, transfer := range hbarTransfers {
+		entityIds[transfer.AccountId.EncodedId] = struct{}{}
+	}
+
+	adjustedNonFeeTransfers = make([]HbarTransfer, 0, len(nonFeeTransfers))
+	for _, nonFeeTransfer := range nonFeeTransfers {
+		entityId := nonFeeTransfer.AccountId.EncodedId
+		// skip non fee transfer whose entity id is not in the transaction record's transfer list
+		if _, ok := entityIds[entityId]; ok {
+			adjustedNonFeeTransfers = append(adjustedNonFeeTransfers, nonFeeTransfer)
+		}
+	}
+
+	nonFeeTransferMap := aggregateNonFeeTransfers(nonFeeTransfers)
+	return getFeeHbarTransfers(hbarTransfers, nonFeeTransferMap), adjustedNonFeeTransfers
+}
+
+func aggregateNonFeeTransfers(nonFeeTransfers []HbarTransfer) map[int64]int64 {
+	nonFeeTransferMap := make(map[int64]int64)
+
+	// the original transfer list from the transaction body
+	for _, transfer := range nonFeeTransfers {
+		// the original transfer list may have multiple entries for one entity, so accumulate it
+		nonFeeTransferMap[transfer.AccountId.EncodedId] += transfer.Amount
+	}
+
+	return nonFeeTransferMap
+}
+
+// markApprovedHbarTransfers marks the non fee debits whose crypto transfer in the record is approved, since the flag
+// may only be set on the crypto transfer rows
+func markApprovedHbarTransfers(cryptoTransfers, nonFeeTransfers []HbarTransfer) {
+	approved := make(map[int64]bool)
+	for _, transfer := range cryptoTransfers {
+		if transfer.IsApproval {
+			approved[transfer.AccountId.EncodedId] = true
+		}
+	}
+
+	for i := range nonFeeTransfers {
+		if nonFeeTransfers[i].Amount < 0 && approved[nonFeeTransfers[i].AccountId.EncodedId] {
+			nonFeeTransfers[i].IsApproval = true
+		}
+	}
+}
+
+func getFeeHbarTransfers(cryptoTransfers []HbarTransfer, nonFeeTransferMap map[int64]int64) []HbarTransfer {
+	cryptoTransferMap := make(map[int64]HbarTransfer)
+	accountIds := make([]int64, 0)
+	for _, transfer := range cryptoTransfers {
+		accountId := transfer.AccountId.EncodedId
+		if _, ok := cryptoTransferMap[accountId]; !ok {
+			accountIds = append(accountIds, accountId)
+		}
+		cryptoTransferMap[accountId] = HbarTransfer{
+			AccountId: transfer.AccountId,
+			Amount:    transfer.Amount + cryptoTransferMap[accountId].Amount,
+		}
+	}
+
+	adjusted := make([]HbarTransfer, 0, len(cryptoTransfers))
+	for _, accountId := range accountIds {
+		aggregated := cryptoTransferMap[accountId]
+		amount := aggregated.Amount - nonFeeTransferMap[accountId]
+		if amount != 0 {
+			adjusted = append(adjusted, HbarTransfer{
+				AccountId: aggregated.AccountId,
+				Amount:    amount,
+			})
+		}
+	}
+
+	return adjusted
+}
+
+// sortHbarTransfers sorts the hbar transfers by account and amount, so the operations built from them don't depend on
+// the order the rows are returned by the database
+func sortHbarTransfers(transfers []HbarTransfer) {
+	sort.Slice(transfers, func(i, j int) bool {
+		if transfers[i].AccountId.EncodedId != transfers[j].AccountId.EncodedId {
+			return transfers[i].AccountId.EncodedId < transfers[j].AccountId.EncodedId
+		}
+		return transfers[i].Amount < transfers[j].Amount
+	})
+}
+
+// sortTokenTransfers sorts the token transfers by account, token, and amount
+func sortTokenTransfers(transfers []TokenTransfer) {
+	sort.Slice(transfers, func(i, j int) bool {
+		if transfers[i].AccountId.EncodedId != transfers[j].AccountId.EncodedId {
+			return transfers[i].AccountId.EncodedId < transfers[j].AccountId.EncodedId
+		}
+		if transfers[i].TokenId.EncodedId != transfers[j].TokenId.EncodedId {
+			return transfers[i].TokenId.EncodedId < transfers[j].TokenId.EncodedId
+		}
+		return transfers[i].Amount < transfers[j].Amount
+	})
+}
+
+// sortNftTransfers sorts the nft transfers by token, serial number, sender, and receiver. A missing sender or receiver
+// sorts first
+func sortNftTransfers(transfers []domain.NftTransfer) {
+	encodedId := func(entityId *domain.EntityId) int64 {
+		if entityId == nil {
+			return -1
+		}
+		return entityId.EncodedId
+	}
+	sort.Slice(transfers, func(i, j int) bool {
+		if transfers[i].TokenId.EncodedId != transfers[j].TokenId.EncodedId {
+			return transfers[i].TokenId.EncodedId < transfers[j].TokenId.EncodedId
+		}
+		if transfers[i].SerialNumber != transfers[j].SerialNumber {
+			return transfers[i].SerialNumber < transfers[j].SerialNumber
+		}
+		if sender := encodedId(transfers[i].SenderAccountId); sender != encodedId(transfers[j].SenderAccountId) {
+			return sender < encodedId(transfers[j].SenderAccountId)
+		}
+		return encodedId(transfers[i].ReceiverAccountId) < encodedId(transfers[j].ReceiverAccountId)
+	})
+}
+
+// appendSingleNftTransfers appends the receiver and the sender side of the nft transfer, if present, to transfers
+func appendSingleNftTransfers(transfers []transfer, nftTransfer domain.NftTransfer) []transfer {
+	if nftTransfer.ReceiverAccountId != nil {
+		transfers = append(transfers, singleNftTransfer{
+			accountId:    *nftTransfer.ReceiverAccountId,
+			receiver:     true,
+			serialNumber: nftTransfer.SerialNumber,
+			tokenId:      nftTransfer.TokenId,
+		})
+	}
+
+	if nftTransfer.SenderAccountId != nil {
+		transfers = append(transfers, singleNftTransfer{
+			accountId:    *nftTransfer.SenderAccountId,
+			isApproval:   nftTransfer.IsApproval,
+			serialNumber: nftTransfer.SerialNumber,
+			tokenId:      nftTransfer.TokenId,
+		})
+	}
+	return transfers
+}
+
+func newTransferOperationBuilder(systemAccounts map[int64]string) transactionOperationBuilder {
+	return &transferOperationBuilder{systemAccounts: systemAccounts}
+}
@@ -0,0 +1,372 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package builder
+
+import (
+	"testing"
+	"testing/quick"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransferOperationBuilderBuild(t *testing.T) {
+	// given
+	builder := newTransferOperationBuilder(map[int64]string{
+		feeCollectorEntityId.EncodedId: config.SystemAccountFeeCollection,
+	})
+	transaction := Transaction{
+		CryptoTransfers: []HbarTransfer{
+			{AccountId: feeCollectorEntityId, Amount: 10},
+			{AccountId: firstEntityId, Amount: -15},
+			{AccountId: nodeEntityId, Amount: 5},
+		},
+		NftTransfers: []domain.NftTransfer{
+			{ReceiverAccountId: &secondEntityId, SenderAccountId: &firstEntityId, SerialNumber: 1, TokenId: tokenId3},
+		},
+		PayerAccountId: firstEntityId,
+		Result:         transactionResultFail,
+		TokenTransfers: []TokenTransfer{
+			{AccountId: secondEntityId, Amount: 1, TokenId: tokenId2, Type: domain.TokenTypeNonFungibleUnique},
+			{AccountId: firstEntityId, Amount: -10, Decimals: tokenDecimals, TokenId: tokenId1,
+				Type: domain.TokenTypeFungibleCommon},
+		},
+		Type: typeCryptoTransfer,
+	}
+	expected := types.OperationSlice{
+		{
+			AccountId: nodeAccountId,
+			Amount:    &types.HbarAmount{Value: 5},
+			Index:     1,
+			Status:    resultSuccess,
+			Type:      types.OperationTypeFee,
+		},
+		{
+			AccountId: feeCollectorAccountId,
+			Amount:    &types.HbarAmount{Value: 10},
+			Index:     2,
+			Metadata:  map[string]interface{}{metadataKeySystemAccount: config.SystemAccountFeeCollection},
+			Status:    resultSuccess,
+			Type:      types.OperationTypeFee,
+		},
+		{
+			AccountId: firstAccountId,
+			Amount:    &types.HbarAmount{Value: -15},
+			Index:     3,
+			Status:    resultSuccess,
+			Type:      types.OperationTypeFee,
+		},
+		{
+			AccountId: firstAccountId,
+			Amount: &types.TokenAmount{
+				Decimals: tokenDecimals,
+				TokenId:  tokenId1,
+				Type:     domain.TokenTypeFungibleCommon,
+				Value:    -10,
+			},
+			Index:  4,
+			Status: resultFail,
+			Type:   types.OperationTypeCryptoTransfer,
+		},
+		{
+			AccountId: secondAccountId,
+			Amount: &types.TokenAmount{
+				SerialNumbers: []int64{1},
+				TokenId:       tokenId3,
+				Type:          domain.TokenTypeNonFungibleUnique,
+				Value:         1,
+			},
+			Index:  5,
+			Status: resultFail,
+			Type:   types.OperationTypeCryptoTransfer,
+		},
+		{
+			AccountId: firstAccountId,
+			Amount: &types.TokenAmount{
+				SerialNumbers: []int64{1},
+				TokenId:       tokenId3,
+				Type:          domain.TokenTypeNonFungibleUnique,
+				Value:         -1,
+			},
+			Index:  6,
+			Status: resultFail,
+			Type:   types.OperationTypeCryptoTransfer,
+		},
+	}
+	operations := types.OperationSlice{{Index: 0, Type: types.OperationTypeCryptoTransfer}}
+
+	// when
+	actual := builder.build(transaction, operations, 1)
+
+	// then
+	assert.Equal(t, operations[0], actual[0])
+	assert.Equal(t, expected, actual[1:])
+}
+
+func TestCategorizeHbarTransfers(t *testing.T) {
+	tests := []struct {
+		name                     string
+		hbarTransfers            []HbarTransfer
+		nonFeeTransfers          []HbarTransfer
+		expectedFeeHbarTransfers []HbarTransfer
+		expectedNonFeeTransfers  []HbarTransfer
+	}{
+		{
+			name:                     "empty",
+			expectedFeeHbarTransfers: []HbarTransfer{},
+			expectedNonFeeTransfers:  []HbarTransfer{},
+		},
+		{
+			name: "empty non fee transfers",
+			hbarTransfers: []HbarTransfer{
+				{firstEntityId, -65, false},
+				{nodeEntityId, 15, false},
+				{feeCollectorEntityId, 50, false},
+			},
+			expectedFeeHbarTransfers: []HbarTransfer{
+				{firstEntityId, -65, false},
+				{nodeEntityId, 15, false},
+				{feeCollectorEntityId, 50, false},
+			},
+			expectedNonFeeTransfers: []HbarTransfer{},
+		},
+		{
+			name: "simple transfer lists",
+			hbarTransfers: []HbarTransfer{
+				{firstEntityId, -165, false},
+				{secondEntityId, 100, false},
+				{nodeEntityId, 15, false},
+				{feeCollectorEntityId, 50, false},
+			},
+			nonFeeTransfers: []HbarTransfer{
+				{firstEntityId, -100, false},
+				{secondEntityId, 100, false},
+			},
+			expectedFeeHbarTransfers: []HbarTransfer{
+				{firstEntityId, -65, false},
+				{nodeEntityId, 15, false},
+				{feeCollectorEntityId, 50, false},
+			},
+			expectedNonFeeTransfers: []HbarTransfer{
+				{firstEntityId, -100, false},
+				{secondEntityId, 100, false},
+			},
+		},
+		{
+			name: "non fee transfer not in transaction record",
+			hbarTransfers: []HbarTransfer{
+				{firstEntityId, -100499210447, false},
+				{secondEntityId, 99999999958, false},
+				{nodeEntityId, 2558345, false},
+				{feeCollectorEntityId, 496652144, false},
+			},
+			nonFeeTransfers: []HbarTransfer{
+				{firstEntityId, -100000000000, false},
+				{thirdEntityId, 100000000000, false},
+			},
+			expectedFeeHbarTransfers: []HbarTransfer{
+				{firstEntityId, -499210447, false},
+				{secondEntityId, 99999999958, false},
+				{nodeEntityId, 2558345, false},
+				{feeCollectorEntityId, 496652144, false},
+			},
+			expectedNonFeeTransfers: []HbarTransfer{
+				{firstEntityId, -100000000000, false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actualFeeHbarTransfers, actualAdjustedNonFeeTransfers := categorizeHbarTransfers(tt.hbarTransfers, tt.nonFeeTransfers)
+			assert.Equal(t, tt.expectedFeeHbarTransfers, actualFeeHbarTransfers)
+			assert.Equal(t, tt.expectedNonFeeTransfers, actualAdjustedNonFeeTransfers)
+		})
+	}
+}
+
+func TestAddSystemAccountMetadata(t *testing.T) {
+	// given
+	builder := &transferOperationBuilder{systemAccounts: map[int64]string{
+		feeCollectorEntityId.EncodedId: config.SystemAccountFeeCollection,
+		800:                            config.SystemAccountStakingReward,
+	}}
+	stakingRewardAccountId := types.NewAccountIdFromEntityId(domain.MustDecodeEntityId(800))
+	operations := types.OperationSlice{
+		{AccountId: firstAccountId, Amount: &types.HbarAmount{Value: -20}, Type: types.OperationTypeFee},
+		{AccountId: nodeAccountId, Amount: &types.HbarAmount{Value: 5}, Type: types.OperationTypeFee},
+		{AccountId: feeCollectorAccountId, Amount: &types.HbarAmount{Value: 10}, Type: types.OperationTypeFee},
+		{
+			AccountId: stakingRewardAccountId,
+			Amount:    &types.HbarAmount{Value: 5},
+			Metadata:  map[string]interface{}{"foo": "bar"},
+			Type:      types.OperationTypeFee,
+		},
+	}
+	expected := types.OperationSlice{
+		{AccountId: firstAccountId, Amount: &types.HbarAmount{Value: -20}, Type: types.OperationTypeFee},
+		{AccountId: nodeAccountId, Amount: &types.HbarAmount{Value: 5}, Type: types.OperationTypeFee},
+		{
+			AccountId: feeCollectorAccountId,
+			Amount:    &types.HbarAmount{Value: 10},
+			Metadata:  map[string]interface{}{metadataKeySystemAccount: config.SystemAccountFeeCollection},
+			Type:      types.OperationTypeFee,
+		},
+		{
+			AccountId: stakingRewardAccountId,
+			Amount:    &types.HbarAmount{Value: 5},
+			Metadata: map[string]interface{}{
+				"foo":                    "bar",
+				metadataKeySystemAccount: config.SystemAccountStakingReward,
+			},
+			Type: types.OperationTypeFee,
+		},
+	}
+
+	// when
+	builder.addSystemAccountMetadata(operations)
+
+	// then
+	assert.Equal(t, expected, operations)
+}
+
+func TestMarkApprovedHbarTransfers(t *testing.T) {
+	// given
+	cryptoTransfers := []HbarTransfer{
+		{firstEntityId, -115, true},
+		{secondEntityId, 100, false},
+		{nodeEntityId, 15, false},
+	}
+	nonFeeTransfers := []HbarTransfer{
+		{firstEntityId, -100, false},
+		{secondEntityId, 100, false},
+	}
+	expected := []HbarTransfer{
+		{firstEntityId, -100, true},
+		{secondEntityId, 100, false},
+	}
+
+	// when
+	markApprovedHbarTransfers(cryptoTransfers, nonFeeTransfers)
+
+	// then
+	assert.Equal(t, expected, nonFeeTransfers)
+}
+
+func TestSortHbarTransfers(t *testing.T) {
+	property := func(accounts []uint8, amounts []int8) bool {
+		// given
+		transfers := make([]HbarTransfer, 0, len(accounts))
+		for i, account := range accounts {
+			amount := int64(0)
+			if i < len(amounts) {
+				amount = int64(amounts[i])
+			}
+			transfers = append(transfers, HbarTransfer{domain.MustDecodeEntityId(int64(account)), amount, false})
+		}
+		expected := make([]HbarTransfer, len(transfers))
+		copy(expected, transfers)
+
+		// when
+		sortHbarTransfers(transfers)
+
+		// then
+		for i := 1; i < len(transfers); i++ {
+			previous, current := transfers[i-1], transfers[i]
+			if previous.AccountId.EncodedId > current.AccountId.EncodedId ||
+				(previous.AccountId == current.AccountId && previous.Amount > current.Amount) {
+				return false
+			}
+		}
+		return assert.ElementsMatch(t, expected, transfers)
+	}
+
+	assert.NoError(t, quick.Check(property, nil))
+}
+
+func TestSortNftTransfers(t *testing.T) {
+	// given
+	transfers := []domain.NftTransfer{
+		{ReceiverAccountId: &secondEntityId, SenderAccountId: &firstEntityId, SerialNumber: 2, TokenId: tokenId3},
+		{ReceiverAccountId: &secondEntityId, SerialNumber: 1, TokenId: tokenId3},
+		{ReceiverAccountId: &firstEntityId, SenderAccountId: &secondEntityId, SerialNumber: 2, TokenId: tokenId3},
+		{SenderAccountId: &firstEntityId, SerialNumber: 5, TokenId: tokenId2},
+		{ReceiverAccountId: &thirdEntityId, SenderAccountId: &firstEntityId, SerialNumber: 2, TokenId: tokenId3},
+	}
+	expected := []domain.NftTransfer{
+		transfers[3],
+		transfers[1],
+		transfers[0],
+		transfers[4],
+		transfers[2],
+	}
+
+	// when
+	sortNftTransfers(transfers)
+
+	// then
+	assert.Equal(t, expected, transfers)
+}
+
+func TestReleaseTransfers(t *testing.T) {
+	// given
+	transfers := make([]transfer, 0, 4)
+	transfers = append(transfers, HbarTransfer{AccountId: firstEntityId}, HbarTransfer{AccountId: secondEntityId})
+	buffer := &transfers
+
+	// when
+	releaseTransfers(buffer)
+
+	// then
+	assert.Empty(t, *buffer)
+	assert.Equal(t, []transfer{nil, nil}, (*buffer)[:2])
+}
+
+func TestAppendHbarTransferOperationsReusesBuffer(t *testing.T) {
+	// given
+	hbarTransfers := []HbarTransfer{{AccountId: firstEntityId, Amount: -5}, {AccountId: secondEntityId, Amount: 5}}
+	expected := types.OperationSlice{
+		{
+			AccountId: firstAccountId,
+			Amount:    &types.HbarAmount{Value: -5},
+			Status:    types.TransactionResults[22],
+			Type:      types.OperationTypeCryptoTransfer,
+		},
+		{
+			AccountId: secondAccountId,
+			Amount:    &types.HbarAmount{Value: 5},
+			Index:     1,
+			Status:    types.TransactionResults[22],
+			Type:      types.OperationTypeCryptoTransfer,
+		},
+	}
+
+	for i := 0; i < 3; i++ {
+		// when
+		actual := appendHbarTransferOperations(types.TransactionResults[22], types.OperationTypeCryptoTransfer,
+			hbarTransfers, make(types.OperationSlice, 0))
+
+		// then
+		assert.Equal(t, expected, actual)
+	}
+}
@@ -0,0 +1,107 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHbarTransferGetAccount(t *testing.T) {
+	hbarTransfer := HbarTransfer{AccountId: firstEntityId}
+	assert.Equal(t, firstEntityId, hbarTransfer.getAccountId())
+}
+
+func TestHbarTransferGetAmount(t *testing.T) {
+	hbarTransfer := HbarTransfer{Amount: 10}
+	assert.Equal(t, &types.HbarAmount{Value: 10}, hbarTransfer.getAmount())
+}
+
+func TestSingleNftTransferGetAccount(t *testing.T) {
+	singleNftTransfer := singleNftTransfer{accountId: firstEntityId}
+	assert.Equal(t, firstEntityId, singleNftTransfer.getAccountId())
+}
+
+func TestSingleNftTransferGetAmount(t *testing.T) {
+	tests := []struct {
+		name     string
+		receiver bool
+		amount   int64
+	}{
+		{"receiver", true, 1},
+		{"sender", false, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			singleNftTransfer := singleNftTransfer{
+				accountId:    firstEntityId,
+				receiver:     tt.receiver,
+				serialNumber: 1,
+				tokenId:      tokenId1,
+			}
+
+			expected := &types.TokenAmount{
+				SerialNumbers: []int64{1},
+				TokenId:       tokenId1,
+				Type:          domain.TokenTypeNonFungibleUnique,
+				Value:         tt.amount,
+			}
+
+			assert.Equal(t, expected, singleNftTransfer.getAmount())
+		})
+	}
+}
+
+func TestTokenTransferGetAccount(t *testing.T) {
+	tokenTransfer := TokenTransfer{AccountId: firstEntityId}
+	assert.Equal(t, firstEntityId, tokenTransfer.getAccountId())
+}
+
+func TestTokenTransferGetAmount(t *testing.T) {
+	tokenTransfer := TokenTransfer{Amount: 10, Decimals: 3, TokenId: tokenId1, Type: domain.TokenTypeFungibleCommon}
+	expected := &types.TokenAmount{Decimals: 3, TokenId: tokenId1, Type: domain.TokenTypeFungibleCommon, Value: 10}
+	assert.Equal(t, expected, tokenTransfer.getAmount())
+}
+
+func TestAppendSingleNftTransfers(t *testing.T) {
+	// given
+	nftTransfer := domain.NftTransfer{
+		IsApproval:        true,
+		ReceiverAccountId: &secondEntityId,
+		SenderAccountId:   &firstEntityId,
+		SerialNumber:      2,
+		TokenId:           tokenId3,
+	}
+	expected := []transfer{
+		singleNftTransfer{accountId: secondEntityId, receiver: true, serialNumber: 2, tokenId: tokenId3},
+		singleNftTransfer{accountId: firstEntityId, isApproval: true, serialNumber: 2, tokenId: tokenId3},
+	}
+
+	// when
+	actual := appendSingleNftTransfers(nil, nftTransfer)
+
+	// then
+	assert.Equal(t, expected, actual)
+}
@@ -0,0 +1,73 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package builder
+
+import (
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+)
+
+// OperationBuilder builds the rosetta operations of a transaction from its decoded records
+type OperationBuilder interface {
+	// Build builds the operations of the transactions sharing the same hash, e.g., a parent and its child records.
+	// The transactions must be ordered by consensus timestamp so the operation indices are stable
+	Build(transactions []Transaction) types.OperationSlice
+}
+
+// Transaction is the decoded transaction record the operations are built from. RecordBytes is the raw transaction
+// record, only available when the importer is configured to persist it. DeletedEntities are the deleted timestamps of
+// the entities involved in the transfers which are deleted at or before the transaction, by encoded entity id.
+// HollowAccountKey is the protobuf-encoded key assigned to the payer if the transaction completes the payer as a hollow
+// account. PendingAirdrops are the airdrop state changes made by a TokenAirdrop, TokenCancelAirdrop, or
+// TokenClaimAirdrop transaction
+type Transaction struct {
+	ChargedTxFee     int64
+	CryptoTransfers  []HbarTransfer
+	DeletedEntities  map[int64]int64
+	HollowAccountKey []byte
+	NftTransfers     []domain.NftTransfer
+	NodeAccountId    *domain.EntityId
+	NonFeeTransfers  []HbarTransfer
+	PayerAccountId   domain.EntityId
+	PendingAirdrops  []types.PendingAirdrop
+	RecordBytes      []byte
+	Result           int32
+	Schedule         domain.Schedule
+	Scheduled        bool
+	Token            domain.Token
+	TokenTransfers   []TokenTransfer
+	Type             int32
+}
+
+func (t Transaction) getOperationType() string {
+	return types.TransactionTypes[t.Type]
+}
+
+func (t Transaction) getResult() string {
+	return types.TransactionResults[t.Result]
+}
+
+// transactionOperationBuilder builds one kind of operations of a transaction
+type transactionOperationBuilder interface {
+	// build appends the operations built from the transaction to operations. The operations of the transaction
+	// built by the previous builders start at index start
+	build(transaction Transaction, operations types.OperationSlice, start int) types.OperationSlice
+}
@@ -0,0 +1,460 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package recordfile
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha512"
+	"encoding/binary"
+	"hash"
+	"io"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	digestTypeSha384          = 0x58ff811b
+	hashAlgorithmSha384       = 1
+	hashClassId               = 0xf422da83a251741e
+	hashClassVersion          = 1
+	hashLength                = sha512.Size384
+	maxFileSize               = 256 * 1024 * 1024
+	prevHashMarker            = 1
+	recordMarker              = 2
+	recordStreamObjectClassId = 0xe370929ba5429d8b
+	recordStreamObjectVersion = 1
+)
+
+// RecordFile is a record stream file, i.e., a block, parsed from its raw bytes. The block hash is the hash of the
+// record file the mirror node stores in record_file.hash, which is defined by the version of the record file:
+//
+//   - v1: the SHA-384 hash of the file
+//   - v2: the SHA-384 hash of the header followed by the SHA-384 hash of the body
+//   - v5 and v6: the end object running hash, which chains the hash of each record stream object to the start object
+//     running hash
+//
+// The block hash of a v1 or v2 record file is also its file hash, while the file hash of a v5 or v6 record file is the
+// SHA-384 hash of the decompressed file
+type RecordFile struct {
+	// EndRunningHash is the end object running hash stored in a v5 or v6 file
+	EndRunningHash []byte
+	// FileHash is the SHA-384 hash of the decompressed file, or the hash of a v2 file
+	FileHash         []byte
+	HapiVersionMajor int32
+	HapiVersionMinor int32
+	HapiVersionPatch int32
+	// Hash is the block hash recomputed from the file, i.e., the file hash of a v1 or v2 file, or the end object running
+	// hash recomputed from the start object running hash and the record stream items of a v5 or v6 file
+	Hash []byte
+	// Index is the block number, only v6 files have it
+	Index int64
+	Items []Item
+	// PrevHash is the hash of the previous record file, which is the start object running hash of a v5 or v6 file
+	PrevHash []byte
+	Version  int32
+}
+
+// Item is a record stream item, i.e., a serialized transaction and its serialized transaction record
+type Item struct {
+	Record      []byte
+	Transaction []byte
+}
+
+// Parse parses the record file of version 1, 2, 5, or 6 from its raw bytes, which are decompressed first if gzipped,
+// and recomputes the block hash of the file
+func Parse(data []byte) (*RecordFile, error) {
+	data, err := decompress(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < 4 {
+		return nil, errors.New("Record file is too short")
+	}
+
+	var recordFile *RecordFile
+	version := int32(binary.BigEndian.Uint32(data))
+	switch version {
+	case 1, 2:
+		recordFile, err = parsePreV5(data)
+	case 5:
+		recordFile, err = parseV5(data)
+	case 6:
+		recordFile, err = parseV6(data[4:])
+	default:
+		return nil, errors.Errorf("Unsupported record file version %d", version)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(recordFile.Items) == 0 {
+		return nil, errors.New("No record stream items in record file")
+	}
+
+	fileHash := sha512.Sum384(data)
+	recordFile.FileHash = fileHash[:]
+	switch version {
+	case 1:
+		recordFile.Hash = recordFile.FileHash
+	case 2:
+		recordFile.FileHash = calculateV2Hash(data)
+		recordFile.Hash = recordFile.FileHash
+	default:
+		recordFile.Hash = calculateRunningHash(recordFile.PrevHash, recordFile.Items)
+	}
+	recordFile.Version = version
+	return recordFile, nil
+}
+
+// Verify returns an error if the recomputed end object running hash doesn't match the one stored in the file. A v1 or
+// v2 file has no hash of its own to verify against
+func (r *RecordFile) Verify() error {
+	if r.Version < 5 {
+		return nil
+	}
+
+	if !bytes.Equal(r.Hash, r.EndRunningHash) {
+		return errors.Errorf("Recomputed end running hash %x doesn't match end running hash %x in record file",
+			r.Hash, r.EndRunningHash)
+	}
+
+	return nil
+}
+
+// calculateRunningHash chains the hash of each record stream object to the running hash. The running hash is the
+// SHA-384 hash of the previous running hash and the object hash, each prefixed with the class id and the version of
+// the hash in little-endian. The object hash is the SHA-384 hash of the object serialized as in a v5 record file
+func calculateRunningHash(startRunningHash []byte, items []Item) []byte {
+	runningHash := startRunningHash
+	for _, item := range items {
+		objectDigest := sha512.New384()
+		writeInt64(objectDigest, binary.BigEndian, recordStreamObjectClassId)
+		writeInt32(objectDigest, binary.BigEndian, recordStreamObjectVersion)
+		writeInt32(objectDigest, binary.BigEndian, uint32(len(item.Record)))
+		objectDigest.Write(item.Record)
+		writeInt32(objectDigest, binary.BigEndian, uint32(len(item.Transaction)))
+		objectDigest.Write(item.Transaction)
+
+		runningDigest := sha512.New384()
+		writeHash(runningDigest, runningHash)
+		writeHash(runningDigest, objectDigest.Sum(nil))
+		runningHash = runningDigest.Sum(nil)
+	}
+
+	return runningHash
+}
+
+// calculateV2Hash returns the SHA-384 hash of the header followed by the SHA-384 hash of the body of the v2 file
+func calculateV2Hash(data []byte) []byte {
+	headerLength := 4 + 4 + 1 + hashLength
+	bodyHash := sha512.Sum384(data[headerLength:])
+	digest := sha512.New384()
+	digest.Write(data[:headerLength])
+	digest.Write(bodyHash[:])
+	return digest.Sum(nil)
+}
+
+func decompress(data []byte) ([]byte, error) {
+	if len(data) < 2 || data[0] != 0x1f || data[1] != 0x8b {
+		return data, nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to decompress record file")
+	}
+	defer reader.Close()
+
+	decompressed, err := io.ReadAll(io.LimitReader(reader, maxFileSize+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to decompress record file")
+	}
+
+	if len(decompressed) > maxFileSize {
+		return nil, errors.New("Decompressed record file is too large")
+	}
+
+	return decompressed, nil
+}
+
+// parsePreV5 parses the v1 or v2 record file, which is the header with the hash of the previous file followed by the
+// transaction and record pairs, all serialized in big-endian
+func parsePreV5(data []byte) (*RecordFile, error) {
+	reader := &v5Reader{data: data}
+	reader.readInt32() // version
+	reader.readInt32() // hapi version
+	if reader.readByte() != prevHashMarker && reader.err == nil {
+		return nil, errors.New("Invalid previous hash marker in record file")
+	}
+
+	recordFile := &RecordFile{PrevHash: reader.readBytes(hashLength)}
+	for reader.err == nil && len(reader.data) != 0 {
+		if reader.readByte() != recordMarker {
+			return nil, errors.New("Invalid record marker in record file")
+		}
+
+		transaction := reader.readLengthAndBytes()
+		record := reader.readLengthAndBytes()
+		recordFile.Items = append(recordFile.Items, Item{Record: record, Transaction: transaction})
+	}
+
+	if reader.err != nil {
+		return nil, errors.Wrap(reader.err, "Invalid record file")
+	}
+
+	return recordFile, nil
+}
+
+// parseV5 parses the v5 record file, which is the header followed by the start object running hash, the record stream
+// objects, and the end object running hash, all serialized in big-endian
+func parseV5(data []byte) (*RecordFile, error) {
+	reader := &v5Reader{data: data}
+	reader.readInt32() // version
+	recordFile := &RecordFile{
+		HapiVersionMajor: reader.readInt32(),
+		HapiVersionMinor: reader.readInt32(),
+		HapiVersionPatch: reader.readInt32(),
+	}
+	reader.readInt32() // object stream version
+
+	recordFile.PrevHash = reader.readHashObject()
+	for reader.err == nil && !reader.isHashObject() {
+		if reader.readInt64() != recordStreamObjectClassId || reader.readInt32() != recordStreamObjectVersion {
+			return nil, errors.New("Invalid record stream object in v5 record file")
+		}
+
+		record := reader.readLengthAndBytes()
+		transaction := reader.readLengthAndBytes()
+		recordFile.Items = append(recordFile.Items, Item{Record: record, Transaction: transaction})
+	}
+	recordFile.EndRunningHash = reader.readHashObject()
+
+	if reader.err != nil {
+		return nil, errors.Wrap(reader.err, "Invalid v5 record file")
+	}
+
+	if len(reader.data) != 0 {
+		return nil, errors.New("Extra data in v5 record file")
+	}
+
+	return recordFile, nil
+}
+
+// parseV6 parses the RecordStreamFile protobuf message of the v6 record file following the version. The message is
+// decoded field by field since its generated protobuf type isn't part of the protobuf module in use
+func parseV6(data []byte) (*RecordFile, error) {
+	recordFile := &RecordFile{}
+	err := consumeMessage(data, func(number protowire.Number, value []byte, varint uint64) error {
+		var err error
+		switch number {
+		case 1: // hapi_proto_version
+			err = consumeMessage(value, func(number protowire.Number, _ []byte, varint uint64) error {
+				switch number {
+				case 1:
+					recordFile.HapiVersionMajor = int32(varint)
+				case 2:
+					recordFile.HapiVersionMinor = int32(varint)
+				case 3:
+					recordFile.HapiVersionPatch = int32(varint)
+				}
+				return nil
+			})
+		case 2: // start_object_running_hash
+			recordFile.PrevHash, err = parseHashObject(value)
+		case 3: // record_stream_items
+			var item Item
+			err = consumeMessage(value, func(number protowire.Number, value []byte, _ uint64) error {
+				switch number {
+				case 1:
+					item.Transaction = value
+				case 2:
+					item.Record = value
+				}
+				return nil
+			})
+			recordFile.Items = append(recordFile.Items, item)
+		case 4: // end_object_running_hash
+			recordFile.EndRunningHash, err = parseHashObject(value)
+		case 5: // block_number
+			recordFile.Index = int64(varint)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid v6 record file")
+	}
+
+	if recordFile.PrevHash == nil || recordFile.EndRunningHash == nil {
+		return nil, errors.New("Missing object running hash in v6 record file")
+	}
+
+	return recordFile, nil
+}
+
+// parseHashObject parses the HashObject protobuf message, only the SHA-384 hash algorithm is supported
+func parseHashObject(data []byte) ([]byte, error) {
+	var algorithm uint64
+	var value []byte
+	err := consumeMessage(data, func(number protowire.Number, bytesValue []byte, varint uint64) error {
+		switch number {
+		case 1:
+			algorithm = varint
+		case 3:
+			value = bytesValue
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if algorithm != hashAlgorithmSha384 || len(value) != hashLength {
+		return nil, errors.Errorf("Unsupported hash object with algorithm %d and length %d", algorithm, len(value))
+	}
+
+	return value, nil
+}
+
+// consumeMessage calls handle with the number and the value of each field of the serialized protobuf message. The
+// value of a length-delimited field is passed as bytes and the value of a varint field as varint
+func consumeMessage(data []byte, handle func(protowire.Number, []byte, uint64) error) error {
+	for len(data) > 0 {
+		number, wireType, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		var bytesValue []byte
+		var varint uint64
+		switch wireType {
+		case protowire.BytesType:
+			bytesValue, n = protowire.ConsumeBytes(data)
+		case protowire.VarintType:
+			varint, n = protowire.ConsumeVarint(data)
+		default:
+			n = protowire.ConsumeFieldValue(number, wireType, data)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		if err := handle(number, bytesValue, varint); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeHash(digest hash.Hash, value []byte) {
+	writeInt64(digest, binary.LittleEndian, hashClassId)
+	writeInt32(digest, binary.LittleEndian, hashClassVersion)
+	digest.Write(value)
+}
+
+func writeInt32(digest hash.Hash, order binary.ByteOrder, value uint32) {
+	buf := make([]byte, 4)
+	order.PutUint32(buf, value)
+	digest.Write(buf)
+}
+
+func writeInt64(digest hash.Hash, order binary.ByteOrder, value uint64) {
+	buf := make([]byte, 8)
+	order.PutUint64(buf, value)
+	digest.Write(buf)
+}
+
+// v5Reader reads the big-endian fields of a v5 or an older record file, the first error is kept and the reads after it
+// are no-op
+type v5Reader struct {
+	data []byte
+	err  error
+}
+
+func (r *v5Reader) isHashObject() bool {
+	return len(r.data) >= 8 && binary.BigEndian.Uint64(r.data) == hashClassId
+}
+
+func (r *v5Reader) readByte() byte {
+	value := r.readBytes(1)
+	if value == nil {
+		return 0
+	}
+
+	return value[0]
+}
+
+func (r *v5Reader) readBytes(length int) []byte {
+	if r.err != nil {
+		return nil
+	}
+
+	if length < 0 || length > len(r.data) {
+		r.err = io.ErrUnexpectedEOF
+		return nil
+	}
+
+	value := r.data[:length]
+	r.data = r.data[length:]
+	return value
+}
+
+func (r *v5Reader) readHashObject() []byte {
+	if r.readInt64() != hashClassId || r.readInt32() != hashClassVersion || r.readInt32() != digestTypeSha384 {
+		if r.err == nil {
+			r.err = errors.New("Invalid hash object")
+		}
+		return nil
+	}
+
+	if r.readInt32() != hashLength && r.err == nil {
+		r.err = errors.New("Invalid hash length")
+	}
+
+	return r.readBytes(hashLength)
+}
+
+func (r *v5Reader) readInt32() int32 {
+	value := r.readBytes(4)
+	if value == nil {
+		return 0
+	}
+
+	return int32(binary.BigEndian.Uint32(value))
+}
+
+func (r *v5Reader) readInt64() uint64 {
+	value := r.readBytes(8)
+	if value == nil {
+		return 0
+	}
+
+	return binary.BigEndian.Uint64(value)
+}
+
+func (r *v5Reader) readLengthAndBytes() []byte {
+	return r.readBytes(int(r.readInt32()))
+}
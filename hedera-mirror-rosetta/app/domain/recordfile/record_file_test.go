@@ -0,0 +1,232 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package recordfile
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name             string
+		fileHash         string
+		hapiVersionMinor int32
+		hash             string
+		index            int64
+		itemCount        int
+		prevHash         string
+		version          int32
+	}{
+		{
+			name:      "2019-07-01T14_13_00.317763Z.rcd",
+			fileHash:  "333d6940254659533fd6b939033e59c57fe8f4ff78375d1e687c032918aa0b7b8179c7fd403754274a8c91e0b6c0195a",
+			hash:      "333d6940254659533fd6b939033e59c57fe8f4ff78375d1e687c032918aa0b7b8179c7fd403754274a8c91e0b6c0195a",
+			itemCount: 15,
+			prevHash:  "f423447a3d5a531a07426070e511555283daae063706242590949116f717a0524e4dd18f9d64e66c73982d475401db04",
+			version:   1,
+		},
+		{
+			name:      "2019-07-01T14_29_00.302068Z.rcd",
+			fileHash:  "1faf198f8fdbefa59bde191f214d73acdc4f5c0f434677a7edf9591b129e21aea90a5b3119d2802cee522e7be6bc8830",
+			hash:      "1faf198f8fdbefa59bde191f214d73acdc4f5c0f434677a7edf9591b129e21aea90a5b3119d2802cee522e7be6bc8830",
+			itemCount: 69,
+			prevHash:  "333d6940254659533fd6b939033e59c57fe8f4ff78375d1e687c032918aa0b7b8179c7fd403754274a8c91e0b6c0195a",
+			version:   1,
+		},
+		{
+			name:      "2019-08-30T18_10_00.419072Z.rcd",
+			fileHash:  "591558e059bd1629ee386c4e35a6875b4c67a096718f5d225772a651042715189414df7db5588495efb2a85dc4a0ffda",
+			hash:      "591558e059bd1629ee386c4e35a6875b4c67a096718f5d225772a651042715189414df7db5588495efb2a85dc4a0ffda",
+			itemCount: 19,
+			prevHash:  hex.EncodeToString(make([]byte, hashLength)),
+			version:   2,
+		},
+		{
+			name:      "2019-08-30T18_10_05.249678Z.rcd",
+			fileHash:  "5ed51baeff204eb6a2a68b76bbaadcb9b6e7074676c1746b99681d075bef009e8d57699baaa6342feec4e83726582d36",
+			hash:      "5ed51baeff204eb6a2a68b76bbaadcb9b6e7074676c1746b99681d075bef009e8d57699baaa6342feec4e83726582d36",
+			itemCount: 15,
+			prevHash:  "591558e059bd1629ee386c4e35a6875b4c67a096718f5d225772a651042715189414df7db5588495efb2a85dc4a0ffda",
+			version:   2,
+		},
+		{
+			name:             "2021-01-11T22_09_24.063739000Z.rcd",
+			fileHash:         "e8adaac05a62a655a3c476b43f1383f6c5f5bba4bfa6c7b087dc4ee3a9089e232b5d5977bde7fba858fd56987792ece3",
+			hapiVersionMinor: 9,
+			hash:             "151bd3358db59fc7936eff15f1cb6734354e444cf85549a5643e55c9c929cb500be712abccd588cd8d20eb92ca55ff49",
+			itemCount:        1,
+			prevHash:         hex.EncodeToString(make([]byte, hashLength)),
+			version:          5,
+		},
+		{
+			name:             "2021-01-11T22_09_34.097416003Z.rcd",
+			fileHash:         "06fb76873dcdc3a4fdb67202e64ed735feaf6a6bb80d4f57fd3511df49ef61fc69d7a2414315028b7d77e168169fad22",
+			hapiVersionMinor: 9,
+			hash:             "514e361089074cb06f984e5a943a20fba2a0d601b766f8adb432d03214c48c3ff14898e6b78292520340f484e820ea84",
+			itemCount:        1,
+			prevHash:         "151bd3358db59fc7936eff15f1cb6734354e444cf85549a5643e55c9c929cb500be712abccd588cd8d20eb92ca55ff49",
+			version:          5,
+		},
+		{
+			name:             "2022-07-13T08_46_08.041986003Z.rcd.gz",
+			fileHash:         "69a4354de5aeb12fbc989ae086fc291cfc1b61415391b4a46915b32aef722a511c4ceba60ecda72d527cc4866a4d235b",
+			hapiVersionMinor: 28,
+			hash:             "a6c241fad2c636f68a6aa0da9293245a5ef0ebef345cd139858068ff7998716cefe0fd3afa0d21304725507061975279",
+			index:            5,
+			itemCount:        1,
+			prevHash:         "13d2594b9e9dbb73dad0cad67a96ad7a0e249af8693aa894003876c9ddd5534b3143e4d785e04fc0c461945a03e85178",
+			version:          6,
+		},
+		{
+			name:             "2022-07-13T08_46_11.304284003Z.rcd.gz",
+			fileHash:         "ed518c8d05f470d4540db35ea8665ab158f9aeb0bcaa3332d171c1efba119da52c1ee510df599269b022d963d4d1e474",
+			hapiVersionMinor: 28,
+			hash:             "3064b824b8b9f9ece011f88a26c030c9f6b822f30fbcabcc7240a220ea42bbdbf305db415a4e41307d0630d5cefe4550",
+			index:            6,
+			itemCount:        2,
+			prevHash:         "a6c241fad2c636f68a6aa0da9293245a5ef0ebef345cd139858068ff7998716cefe0fd3afa0d21304725507061975279",
+			version:          6,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			recordFile, err := Parse(readTestFile(t, tt.name))
+
+			// then
+			require.NoError(t, err)
+			assert.NoError(t, recordFile.Verify())
+			assert.Equal(t, tt.fileHash, hex.EncodeToString(recordFile.FileHash))
+			assert.Equal(t, tt.hash, hex.EncodeToString(recordFile.Hash))
+			if tt.version >= 5 {
+				assert.Equal(t, tt.hash, hex.EncodeToString(recordFile.EndRunningHash))
+			} else {
+				assert.Nil(t, recordFile.EndRunningHash)
+			}
+			assert.Equal(t, int32(0), recordFile.HapiVersionMajor)
+			assert.Equal(t, tt.hapiVersionMinor, recordFile.HapiVersionMinor)
+			assert.Equal(t, tt.index, recordFile.Index)
+			assert.Len(t, recordFile.Items, tt.itemCount)
+			assert.Equal(t, tt.prevHash, hex.EncodeToString(recordFile.PrevHash))
+			assert.Equal(t, tt.version, recordFile.Version)
+			for _, item := range recordFile.Items {
+				assert.NotEmpty(t, item.Record)
+				assert.NotEmpty(t, item.Transaction)
+			}
+		})
+	}
+}
+
+func TestParseUncompressedV6(t *testing.T) {
+	// given
+	reader, err := gzip.NewReader(bytes.NewReader(readTestFile(t, "2022-07-13T08_46_08.041986003Z.rcd.gz")))
+	require.NoError(t, err)
+	data := new(bytes.Buffer)
+	_, err = data.ReadFrom(reader)
+	require.NoError(t, err)
+
+	// when
+	recordFile, err := Parse(data.Bytes())
+
+	// then
+	require.NoError(t, err)
+	assert.NoError(t, recordFile.Verify())
+	assert.Equal(t, int64(5), recordFile.Index)
+}
+
+func TestParseTamperedV5(t *testing.T) {
+	// given
+	data := readTestFile(t, "2021-01-11T22_09_34.097416003Z.rcd")
+	// flip a byte of the transaction at the end of the record stream object, right before the end running hash
+	data[len(data)-69] ^= 0xff
+
+	// when
+	recordFile, err := Parse(data)
+
+	// then
+	require.NoError(t, err)
+	assert.Error(t, recordFile.Verify())
+}
+
+func TestParseTamperedV2(t *testing.T) {
+	// given
+	data := readTestFile(t, "2019-08-30T18_10_05.249678Z.rcd")
+	data[len(data)-1] ^= 0xff
+
+	// when
+	recordFile, err := Parse(data)
+
+	// then
+	require.NoError(t, err)
+	assert.NoError(t, recordFile.Verify())
+	assert.NotEqual(t, "5ed51baeff204eb6a2a68b76bbaadcb9b6e7074676c1746b99681d075bef009e8d57699baaa6342feec4e83726582d36",
+		hex.EncodeToString(recordFile.Hash))
+}
+
+func TestParseInvalid(t *testing.T) {
+	v2 := readTestFile(t, "2019-08-30T18_10_05.249678Z.rcd")
+	v2InvalidMarker := append([]byte{}, v2...)
+	v2InvalidMarker[57] = 3
+	v5 := readTestFile(t, "2021-01-11T22_09_24.063739000Z.rcd")
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: []byte{}},
+		{name: "too short", data: []byte{0, 0, 5}},
+		{name: "unsupported version", data: []byte{0, 0, 0, 7, 1, 2, 3}},
+		{name: "truncated v2", data: v2[:len(v2)-10]},
+		{name: "invalid previous hash marker v2", data: []byte{0, 0, 0, 2, 0, 0, 0, 3, 2}},
+		{name: "invalid record marker v2", data: v2InvalidMarker},
+		{name: "v2 without items", data: v2[:57]},
+		{name: "truncated v5", data: v5[:len(v5)-10]},
+		{name: "extra data v5", data: append(append([]byte{}, v5...), 1)},
+		{name: "invalid v6", data: []byte{0, 0, 0, 6, 0x12, 0x05}},
+		{name: "v6 without running hashes", data: []byte{0, 0, 0, 6, 0x28, 0x01}},
+		{name: "invalid gzip", data: []byte{0x1f, 0x8b, 0x00}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			recordFile, err := Parse(tt.data)
+
+			// then
+			assert.Error(t, err)
+			assert.Nil(t, recordFile)
+		})
+	}
+}
+
+func readTestFile(t *testing.T, name string) []byte {
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	return data
+}
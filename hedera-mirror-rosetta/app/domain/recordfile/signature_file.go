@@ -0,0 +1,201 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package recordfile
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/hex"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/tools"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	fileHashMarker        = 4
+	signatureChecksumBase = 101
+	signatureClassId      = 0x13dc4b399b245c69
+	signatureClassVersion = 1
+	signatureMarker       = 3
+	// signatureTypeRsa is the SHA384withRSA signature type of both the v5 signature object and the v6 SignatureObject
+	signatureTypeRsa = 1
+)
+
+// SignatureFile is the signature file of a record file written by a node, which has the node's signature of the file
+// hash of the record file
+type SignatureFile struct {
+	// FileHash is the file hash of the record file the node signed
+	FileHash []byte
+	// FileSignature is the SHA384withRSA signature of the file hash
+	FileSignature []byte
+	Version       int32
+}
+
+// ParseSignatureFile parses the signature file of version 2, 5, or 6 from its raw bytes. The signature files of the v1
+// and v2 record files are of version 2. Only the file hash and its signature are kept, the metadata hash of a v5 or v6
+// signature file and its signature are ignored
+func ParseSignatureFile(data []byte) (*SignatureFile, error) {
+	if len(data) == 0 {
+		return nil, errors.New("Signature file is empty")
+	}
+
+	var signatureFile *SignatureFile
+	var err error
+	switch data[0] {
+	case fileHashMarker:
+		signatureFile, err = parseSignatureFileV2(data)
+	case 5:
+		signatureFile, err = parseSignatureFileV5(data[1:])
+	case 6:
+		signatureFile, err = parseSignatureFileV6(data[1:])
+	default:
+		return nil, errors.Errorf("Unsupported signature file version %d", data[0])
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(signatureFile.FileSignature) == 0 {
+		return nil, errors.New("Missing file signature in signature file")
+	}
+
+	return signatureFile, nil
+}
+
+// Verify returns an error if the signature file isn't of the file hash, or the signature doesn't verify with the
+// public key of the node
+func (s *SignatureFile) Verify(fileHash []byte, publicKey *rsa.PublicKey) error {
+	if !bytes.Equal(s.FileHash, fileHash) {
+		return errors.Errorf("File hash %x in signature file doesn't match file hash %x of record file", s.FileHash,
+			fileHash)
+	}
+
+	digest := sha512.Sum384(s.FileHash)
+	if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA384, digest[:], s.FileSignature); err != nil {
+		return errors.Wrap(err, "Invalid file signature")
+	}
+
+	return nil
+}
+
+// ParsePublicKey parses the hex encoded DER of a node's RSA public key in the address book
+func ParsePublicKey(hexKey string) (*rsa.PublicKey, error) {
+	der, err := hex.DecodeString(tools.SafeRemoveHexPrefix(hexKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid public key")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid public key")
+	}
+
+	publicKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("Public key is not an RSA public key")
+	}
+
+	return publicKey, nil
+}
+
+// parseSignatureFileV2 parses the v2 signature file, which is the file hash and the length prefixed signature, each
+// preceded by its marker
+func parseSignatureFileV2(data []byte) (*SignatureFile, error) {
+	reader := &v5Reader{data: data}
+	reader.readByte() // file hash marker
+	fileHash := reader.readBytes(hashLength)
+	if reader.readByte() != signatureMarker && reader.err == nil {
+		return nil, errors.New("Invalid signature marker in v2 signature file")
+	}
+	signature := reader.readLengthAndBytes()
+
+	if reader.err != nil {
+		return nil, errors.Wrap(reader.err, "Invalid v2 signature file")
+	}
+
+	return &SignatureFile{FileHash: fileHash, FileSignature: signature, Version: 2}, nil
+}
+
+// parseSignatureFileV5 parses the v5 signature file following the version, which is the object stream signature
+// version followed by the file hash object, its signature object, the metadata hash object, and its signature object
+func parseSignatureFileV5(data []byte) (*SignatureFile, error) {
+	reader := &v5Reader{data: data}
+	reader.readInt32() // object stream signature version
+	fileHash := reader.readHashObject()
+	if reader.readInt64() != signatureClassId || reader.readInt32() != signatureClassVersion ||
+		reader.readInt32() != signatureTypeRsa {
+		if reader.err == nil {
+			return nil, errors.New("Invalid signature object in v5 signature file")
+		}
+	}
+
+	length := reader.readInt32()
+	if checksum := reader.readInt32(); checksum != signatureChecksumBase-length && reader.err == nil {
+		return nil, errors.New("Invalid signature checksum in v5 signature file")
+	}
+	signature := reader.readBytes(int(length))
+
+	if reader.err != nil {
+		return nil, errors.Wrap(reader.err, "Invalid v5 signature file")
+	}
+
+	return &SignatureFile{FileHash: fileHash, FileSignature: signature, Version: 5}, nil
+}
+
+// parseSignatureFileV6 parses the SignatureFile protobuf message of the v6 signature file following the version
+func parseSignatureFileV6(data []byte) (*SignatureFile, error) {
+	signatureFile := &SignatureFile{Version: 6}
+	err := consumeMessage(data, func(number protowire.Number, value []byte, _ uint64) error {
+		if number != 1 { // file_signature
+			return nil
+		}
+
+		var signatureType uint64
+		err := consumeMessage(value, func(number protowire.Number, value []byte, varint uint64) error {
+			var err error
+			switch number {
+			case 1: // type
+				signatureType = varint
+			case 4: // signature
+				signatureFile.FileSignature = value
+			case 5: // hash_object
+				signatureFile.FileHash, err = parseHashObject(value)
+			}
+			return err
+		})
+		if err == nil && signatureType != signatureTypeRsa {
+			err = errors.Errorf("Unsupported signature type %d", signatureType)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid v6 signature file")
+	}
+
+	if signatureFile.FileHash == nil {
+		return nil, errors.New("Missing file hash in v6 signature file")
+	}
+
+	return signatureFile, nil
+}
@@ -0,0 +1,198 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package recordfile
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestParseSignatureFile(t *testing.T) {
+	// given
+	privateKey := newTestRsaKey(t)
+	fileHash := sha512.Sum384([]byte("record file"))
+	signature := signFileHash(t, privateKey, fileHash[:])
+	tests := []struct {
+		name    string
+		data    []byte
+		version int32
+	}{
+		{name: "v2", data: newSignatureFileV2(fileHash[:], signature), version: 2},
+		{name: "v5", data: newSignatureFileV5(fileHash[:], signature), version: 5},
+		{name: "v6", data: newSignatureFileV6(fileHash[:], signature, signatureTypeRsa), version: 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			signatureFile, err := ParseSignatureFile(tt.data)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, &SignatureFile{FileHash: fileHash[:], FileSignature: signature, Version: tt.version},
+				signatureFile)
+			assert.NoError(t, signatureFile.Verify(fileHash[:], &privateKey.PublicKey))
+		})
+	}
+}
+
+func TestParseSignatureFileInvalid(t *testing.T) {
+	fileHash := sha512.Sum384([]byte("record file"))
+	invalidChecksum := newSignatureFileV5(fileHash[:], []byte{1, 2})
+	invalidChecksum[len(invalidChecksum)-3]++
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty"},
+		{name: "unsupported version", data: []byte{7}},
+		{name: "truncated v2", data: newSignatureFileV2(fileHash[:], []byte{1, 2})[:55]},
+		{name: "invalid v2 signature marker", data: append(append([]byte{4}, fileHash[:]...), 1)},
+		{name: "no signature", data: newSignatureFileV2(fileHash[:], []byte{})},
+		{name: "invalid v5 checksum", data: invalidChecksum},
+		{name: "truncated v5", data: newSignatureFileV5(fileHash[:], []byte{1, 2})[:40]},
+		{name: "unsupported v6 signature type", data: newSignatureFileV6(fileHash[:], []byte{1, 2}, 2)},
+		{name: "missing v6 file hash", data: newSignatureFileV6(nil, []byte{1, 2}, signatureTypeRsa)},
+		{name: "invalid v6 message", data: []byte{6, 0xff}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signatureFile, err := ParseSignatureFile(tt.data)
+			assert.Error(t, err)
+			assert.Nil(t, signatureFile)
+		})
+	}
+}
+
+func TestSignatureFileVerifyFails(t *testing.T) {
+	// given
+	privateKey := newTestRsaKey(t)
+	fileHash := sha512.Sum384([]byte("record file"))
+	otherFileHash := sha512.Sum384([]byte("other record file"))
+	signatureFile := &SignatureFile{FileHash: fileHash[:], FileSignature: signFileHash(t, privateKey, fileHash[:])}
+
+	// when, then
+	assert.Error(t, signatureFile.Verify(otherFileHash[:], &privateKey.PublicKey))
+	assert.Error(t, signatureFile.Verify(fileHash[:], &newTestRsaKey(t).PublicKey))
+}
+
+func TestParsePublicKey(t *testing.T) {
+	// given
+	privateKey := newTestRsaKey(t)
+	der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+
+	// when
+	publicKey, err := ParsePublicKey("0x" + hex.EncodeToString(der))
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, &privateKey.PublicKey, publicKey)
+}
+
+func TestParsePublicKeyInvalid(t *testing.T) {
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ecdsaDer, err := x509.MarshalPKIXPublicKey(&ecdsaKey.PublicKey)
+	require.NoError(t, err)
+
+	for _, hexKey := range []string{"", "xyz", "308201a2", hex.EncodeToString(ecdsaDer)} {
+		publicKey, err := ParsePublicKey(hexKey)
+		assert.Error(t, err)
+		assert.Nil(t, publicKey)
+	}
+}
+
+func newTestRsaKey(t *testing.T) *rsa.PrivateKey {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return privateKey
+}
+
+func signFileHash(t *testing.T, privateKey *rsa.PrivateKey, fileHash []byte) []byte {
+	digest := sha512.Sum384(fileHash)
+	signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA384, digest[:])
+	require.NoError(t, err)
+	return signature
+}
+
+func newSignatureFileV2(fileHash, signature []byte) []byte {
+	buffer := bytes.NewBuffer([]byte{fileHashMarker})
+	buffer.Write(fileHash)
+	buffer.WriteByte(signatureMarker)
+	writeBigEndian(buffer, uint32(len(signature)))
+	buffer.Write(signature)
+	return buffer.Bytes()
+}
+
+func newSignatureFileV5(fileHash, signature []byte) []byte {
+	buffer := bytes.NewBuffer([]byte{5})
+	writeBigEndian(buffer, uint32(1))
+	writeBigEndian(buffer, uint64(hashClassId))
+	writeBigEndian(buffer, uint32(hashClassVersion))
+	writeBigEndian(buffer, uint32(digestTypeSha384))
+	writeBigEndian(buffer, uint32(hashLength))
+	buffer.Write(fileHash)
+	writeBigEndian(buffer, uint64(signatureClassId))
+	writeBigEndian(buffer, uint32(signatureClassVersion))
+	writeBigEndian(buffer, uint32(signatureTypeRsa))
+	writeBigEndian(buffer, uint32(len(signature)))
+	writeBigEndian(buffer, uint32(signatureChecksumBase-len(signature)))
+	buffer.Write(signature)
+	return buffer.Bytes()
+}
+
+func newSignatureFileV6(fileHash, signature []byte, signatureType uint64) []byte {
+	var signatureObject []byte
+	signatureObject = protowire.AppendTag(signatureObject, 1, protowire.VarintType)
+	signatureObject = protowire.AppendVarint(signatureObject, signatureType)
+	signatureObject = protowire.AppendTag(signatureObject, 4, protowire.BytesType)
+	signatureObject = protowire.AppendBytes(signatureObject, signature)
+	if fileHash != nil {
+		var hashObject []byte
+		hashObject = protowire.AppendTag(hashObject, 1, protowire.VarintType)
+		hashObject = protowire.AppendVarint(hashObject, hashAlgorithmSha384)
+		hashObject = protowire.AppendTag(hashObject, 3, protowire.BytesType)
+		hashObject = protowire.AppendBytes(hashObject, fileHash)
+		signatureObject = protowire.AppendTag(signatureObject, 5, protowire.BytesType)
+		signatureObject = protowire.AppendBytes(signatureObject, hashObject)
+	}
+
+	data := protowire.AppendTag([]byte{6}, 1, protowire.BytesType)
+	return protowire.AppendBytes(data, signatureObject)
+}
+
+func writeBigEndian(buffer *bytes.Buffer, value interface{}) {
+	_ = binary.Write(buffer, binary.BigEndian, value)
+}
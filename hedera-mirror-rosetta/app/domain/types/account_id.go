@@ -22,6 +22,7 @@ package types
 
 import (
 	"encoding/hex"
+	"strconv"
 	"strings"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
@@ -31,11 +32,40 @@ import (
 	"github.com/pkg/errors"
 )
 
+const (
+	AccountIdentifierFormatDotted     = "DOTTED"
+	AccountIdentifierFormatStructured = "STRUCTURED"
+
+	metadataKeyEvmAddress = "evm_address"
+	metadataKeyNum        = "num"
+	metadataKeyRealm      = "realm"
+	metadataKeyShard      = "shard"
+)
+
+// structuredAccountIdentifier is true if the addresses of non-alias accounts are the account numbers and the shard,
+// realm, and num are added as metadata, false if the addresses are in the shard.realm.num form
+var structuredAccountIdentifier bool
+
+// SetAccountIdentifierFormat sets the format of the account identifiers exposed by the API. It's not safe to call it
+// concurrently with ToRosetta and should only be called once at startup
+func SetAccountIdentifierFormat(format string) error {
+	switch strings.ToUpper(format) {
+	case "", AccountIdentifierFormatDotted:
+		structuredAccountIdentifier = false
+	case AccountIdentifierFormatStructured:
+		structuredAccountIdentifier = true
+	default:
+		return errors.Errorf("Unsupported account identifier format %s", format)
+	}
+	return nil
+}
+
 type AccountId struct {
-	accountId domain.EntityId
-	alias     []byte
-	aliasKey  *hedera.PublicKey
-	curveType types.CurveType
+	accountId  domain.EntityId
+	alias      []byte
+	aliasKey   *hedera.PublicKey
+	curveType  types.CurveType
+	evmAddress []byte
 }
 
 // GetAlias returns the Hedera network alias
@@ -53,6 +83,17 @@ func (a AccountId) GetCurveType() types.CurveType {
 	return a.curveType
 }
 
+// GetEvmAddress returns the evm address of the contract account
+func (a AccountId) GetEvmAddress() []byte {
+	if len(a.evmAddress) == 0 {
+		return nil
+	}
+
+	evmAddress := make([]byte, len(a.evmAddress))
+	copy(evmAddress, a.evmAddress)
+	return evmAddress
+}
+
 func (a AccountId) GetId() int64 {
 	return a.accountId.EncodedId
 }
@@ -72,8 +113,27 @@ func (a AccountId) String() string {
 	return a.accountId.String()
 }
 
+// ToRosetta returns the rosetta AccountIdentifier. For a contract account, the evm address is added as metadata. In
+// the structured format, the address of a non-alias account is its account number, and its shard, realm, and num are
+// added as metadata
 func (a AccountId) ToRosetta() *types.AccountIdentifier {
-	return &types.AccountIdentifier{Address: a.String()}
+	accountIdentifier := &types.AccountIdentifier{Address: a.String()}
+	metadata := make(map[string]interface{})
+	if len(a.evmAddress) != 0 {
+		metadata[metadataKeyEvmAddress] = tools.SafeAddHexPrefix(hex.EncodeToString(a.evmAddress))
+	}
+
+	if structuredAccountIdentifier && !a.HasAlias() {
+		accountIdentifier.Address = strconv.FormatInt(a.accountId.EntityNum, 10)
+		metadata[metadataKeyNum] = a.accountId.EntityNum
+		metadata[metadataKeyRealm] = a.accountId.RealmNum
+		metadata[metadataKeyShard] = a.accountId.ShardNum
+	}
+
+	if len(metadata) != 0 {
+		accountIdentifier.Metadata = metadata
+	}
+	return accountIdentifier
 }
 
 func (a AccountId) ToSdkAccountId() hedera.AccountID {
@@ -104,8 +164,22 @@ func NewAccountIdFromAlias(alias []byte, shard, realm int64) (zero AccountId, _
 }
 
 // NewAccountIdFromEntity creates AccountId from the entity. If the entity has a network alias, the function will parse
-// it to the rosetta format
+// it to the rosetta format. If the entity is a contract, the evm address is set to the create2 evm address if exists,
+// otherwise the address derived from shard.realm.num
 func NewAccountIdFromEntity(entity domain.Entity) (zero AccountId, _ error) {
+	if entity.Type == domain.EntityTypeContract {
+		evmAddress := entity.EvmAddress
+		if len(evmAddress) == 0 {
+			contractId := hedera.ContractID{
+				Shard:    uint64(entity.Id.ShardNum),
+				Realm:    uint64(entity.Id.RealmNum),
+				Contract: uint64(entity.Id.EntityNum),
+			}
+			evmAddress, _ = hex.DecodeString(contractId.ToSolidityAddress())
+		}
+		return AccountId{accountId: entity.Id, evmAddress: evmAddress}, nil
+	}
+
 	if len(entity.Alias) == 0 {
 		return NewAccountIdFromEntityId(entity.Id), nil
 	}
@@ -175,7 +249,8 @@ func NewAccountIdFromSdkAccountId(accountId hedera.AccountID) (zero AccountId, _
 }
 
 // NewAccountIdFromString creates AccountId from the address string. If the address is in the shard.realm.num form,
-// shard and realm are ignored. The only valid form of the alias address is the hex string of the raw public key bytes.
+// shard and realm are ignored. If the address is an account number as in the structured format, the account is in the
+// shard and realm. The only valid form of the alias address is the hex string of the raw public key bytes.
 func NewAccountIdFromString(address string, shard, realm int64) (zero AccountId, _ error) {
 	if strings.Contains(address, ".") {
 		entityId, err := domain.EntityIdFromString(address)
@@ -185,6 +260,14 @@ func NewAccountIdFromString(address string, shard, realm int64) (zero AccountId,
 		return AccountId{accountId: entityId}, nil
 	}
 
+	if num, err := strconv.ParseInt(address, 10, 64); err == nil {
+		entityId, err := domain.EntityIdOf(shard, realm, num)
+		if err != nil {
+			return zero, err
+		}
+		return AccountId{accountId: entityId}, nil
+	}
+
 	if !strings.HasPrefix(address, tools.HexPrefix) {
 		return zero, errors.Errorf("Invalid Account Alias")
 	}
@@ -26,6 +26,7 @@ import (
 	"testing"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
 	"github.com/hashgraph/hedera-protobufs-go/services"
 	"github.com/hashgraph/hedera-sdk-go/v2"
@@ -58,7 +59,9 @@ var (
 		curveType: types.Edwards25519,
 	}
 
-	nonAliasAccountId = AccountId{accountId: domain.MustDecodeEntityId(125)}
+	contractEvmAddress = hexutil.MustDecode("0x71a8c2b29b5b7bcfb1a2c8ec4ad89b4a5b7da4b6")
+	contractAccountId  = AccountId{accountId: domain.MustDecodeEntityId(130), evmAddress: contractEvmAddress}
+	nonAliasAccountId  = AccountId{accountId: domain.MustDecodeEntityId(125)}
 
 	zeroAccountId AccountId
 	zeroCurveType types.CurveType
@@ -266,6 +269,14 @@ func TestAccountIdToRosetta(t *testing.T) {
 			input:    nonAliasAccountId,
 			expected: &types.AccountIdentifier{Address: "0.0.125"},
 		},
+		{
+			name:  "Contract",
+			input: contractAccountId,
+			expected: &types.AccountIdentifier{
+				Address:  "0.0.130",
+				Metadata: map[string]interface{}{"evm_address": "0x71a8c2b29b5b7bcfb1a2c8ec4ad89b4a5b7da4b6"},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -275,6 +286,87 @@ func TestAccountIdToRosetta(t *testing.T) {
 	}
 }
 
+func TestAccountIdToRosettaStructured(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    AccountId
+		expected *types.AccountIdentifier
+	}{
+		{
+			name:     "Ed25519 Alias",
+			input:    ed25519AliasAccountId,
+			expected: &types.AccountIdentifier{Address: ed25519AliasString},
+		},
+		{
+			name:  "Non-alias",
+			input: NewAccountIdFromEntityId(domain.EntityId{ShardNum: 1, RealmNum: 2, EntityNum: 125}),
+			expected: &types.AccountIdentifier{
+				Address:  "125",
+				Metadata: map[string]interface{}{"num": int64(125), "realm": int64(2), "shard": int64(1)},
+			},
+		},
+		{
+			name:  "Contract",
+			input: contractAccountId,
+			expected: &types.AccountIdentifier{
+				Address: "130",
+				Metadata: map[string]interface{}{
+					"evm_address": "0x71a8c2b29b5b7bcfb1a2c8ec4ad89b4a5b7da4b6",
+					"num":         int64(130),
+					"realm":       int64(0),
+					"shard":       int64(0),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			t.Cleanup(resetAccountIdentifierFormat)
+			assert.NoError(t, SetAccountIdentifierFormat(AccountIdentifierFormatStructured))
+
+			// when
+			actual := tt.input.ToRosetta()
+
+			// then
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestSetAccountIdentifierFormat(t *testing.T) {
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{format: "", expected: "0.0.125"},
+		{format: "dotted", expected: "0.0.125"},
+		{format: AccountIdentifierFormatDotted, expected: "0.0.125"},
+		{format: "structured", expected: "125"},
+		{format: AccountIdentifierFormatStructured, expected: "125"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			t.Cleanup(resetAccountIdentifierFormat)
+
+			// when
+			err := SetAccountIdentifierFormat(tt.format)
+
+			// then
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, nonAliasAccountId.ToRosetta().Address)
+		})
+	}
+}
+
+func TestSetAccountIdentifierFormatUnsupported(t *testing.T) {
+	t.Cleanup(resetAccountIdentifierFormat)
+	assert.Error(t, SetAccountIdentifierFormat("foobar"))
+	assert.Equal(t, "0.0.125", nonAliasAccountId.ToRosetta().Address)
+}
+
 func TestAccountIdToSdkAccountId(t *testing.T) {
 	pubKey := ed25519PublicKey
 	tests := []struct {
@@ -345,6 +437,36 @@ func TestNewAccountIdFromStringShardRealmAccount(t *testing.T) {
 	}
 }
 
+func TestNewAccountIdFromStringAccountNum(t *testing.T) {
+	tests := []struct {
+		input     string
+		shard     int64
+		realm     int64
+		expectErr bool
+		expected  string
+	}{
+		{input: "125", expected: "0.0.125"},
+		{input: "125", shard: 1, realm: 2, expected: "1.2.125"},
+		{input: "-125", expectErr: true},
+		{input: "125", shard: -1, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		name := fmt.Sprintf("num:'%s',shard:%d,realm:%d", tt.input, tt.shard, tt.realm)
+		t.Run(name, func(t *testing.T) {
+			accountId, err := NewAccountIdFromString(tt.input, tt.shard, tt.realm)
+			if !tt.expectErr {
+				assert.Nil(t, err)
+				assert.Equal(t, tt.expected, accountId.String())
+				assert.Nil(t, accountId.GetAlias())
+			} else {
+				assert.NotNil(t, err)
+				assert.Equal(t, zeroAccountId, accountId)
+			}
+		})
+	}
+}
+
 func TestNewAccountIdFromStringAlias(t *testing.T) {
 	tests := []struct {
 		input            string
@@ -469,6 +591,7 @@ func TestNewAccountIdFromEntity(t *testing.T) {
 		expectedAccountString string
 		expectedAlias         []byte
 		expectedCurveType     types.CurveType
+		expectedEvmAddress    []byte
 		expectedId            int64
 	}{
 		{
@@ -495,6 +618,22 @@ func TestNewAccountIdFromEntity(t *testing.T) {
 			expectedCurveType:     types.Edwards25519,
 			expectedId:            150,
 		},
+		{
+			input: domain.Entity{
+				EvmAddress: contractEvmAddress,
+				Id:         domain.MustDecodeEntityId(160),
+				Type:       domain.EntityTypeContract,
+			},
+			expectedAccountString: "0.0.160",
+			expectedEvmAddress:    contractEvmAddress,
+			expectedId:            160,
+		},
+		{
+			input:                 domain.Entity{Id: domain.MustDecodeEntityId(281483566645258), Type: domain.EntityTypeContract},
+			expectedAccountString: "1.2.10",
+			expectedEvmAddress:    hexutil.MustDecode("0x000000010000000000000002000000000000000a"),
+			expectedId:            281483566645258,
+		},
 	}
 
 	for _, tt := range tests {
@@ -504,6 +643,7 @@ func TestNewAccountIdFromEntity(t *testing.T) {
 			assert.Equal(t, tt.expectedAccountString, accountId.String())
 			assert.Equal(t, tt.expectedAlias, accountId.GetAlias())
 			assert.Equal(t, tt.expectedCurveType, accountId.GetCurveType())
+			assert.Equal(t, tt.expectedEvmAddress, accountId.GetEvmAddress())
 			assert.Equal(t, tt.expectedId, accountId.GetId())
 		})
 	}
@@ -665,3 +805,7 @@ func getKeyListAlias() []byte {
 	keyListAlias, _ := proto.Marshal(&keyList)
 	return keyListAlias
 }
+
+func resetAccountIdentifierFormat() {
+	_ = SetAccountIdentifierFormat(AccountIdentifierFormatDotted)
+}
@@ -0,0 +1,59 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package types
+
+import (
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	log "github.com/sirupsen/logrus"
+)
+
+// AccountInfo is domain level struct used to represent the current key, auto renew period and expiration of an account
+type AccountInfo struct {
+	AutoRenewPeriod     *int64
+	ExpirationTimestamp *int64
+	Id                  domain.EntityId
+	Key                 []byte
+}
+
+// ToMetadata returns the account info as metadata. The key, the human-readable key, and the key type are
+// omitted if the account doesn't have a key or the key can't be parsed
+func (a AccountInfo) ToMetadata() map[string]interface{} {
+	metadata := map[string]interface{}{
+		"account_id": a.Id.String(),
+		"exists":     true,
+	}
+	if a.AutoRenewPeriod != nil {
+		metadata["auto_renew_period"] = *a.AutoRenewPeriod
+	}
+	if a.ExpirationTimestamp != nil {
+		metadata["expiration_timestamp"] = *a.ExpirationTimestamp
+	}
+	if len(a.Key) != 0 {
+		key, err := NewKeyFromBytes(a.Key)
+		if err != nil {
+			log.Warnf("Failed to parse key of account %s: %s", a.Id.String(), err)
+		} else {
+			for k, v := range key.ToMetadata() {
+				metadata[k] = v
+			}
+		}
+	}
+	return metadata
+}
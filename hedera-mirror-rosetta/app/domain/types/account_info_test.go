@@ -0,0 +1,82 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package types
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-protobufs-go/services"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestAccountInfoToMetadata(t *testing.T) {
+	// given
+	keyBytes, _ := proto.Marshal(&services.Key{Key: &services.Key_Ed25519{Ed25519: ed25519RawKey}})
+	autoRenewPeriod := int64(7776000)
+	expirationTimestamp := int64(1700000000000000000)
+	accountInfo := AccountInfo{
+		AutoRenewPeriod:     &autoRenewPeriod,
+		ExpirationTimestamp: &expirationTimestamp,
+		Id:                  domain.MustDecodeEntityId(1001),
+		Key:                 keyBytes,
+	}
+	expected := map[string]interface{}{
+		"account_id":           "0.0.1001",
+		"auto_renew_period":    autoRenewPeriod,
+		"exists":               true,
+		"expiration_timestamp": expirationTimestamp,
+		"key":                  "0x" + hex.EncodeToString(keyBytes),
+		"key_readable":         hex.EncodeToString(ed25519RawKey),
+		"key_type":             KeyTypeEd25519,
+	}
+
+	// when
+	actual := accountInfo.ToMetadata()
+
+	// then
+	assert.Equal(t, expected, actual)
+}
+
+func TestAccountInfoToMetadataWithoutOptionalFields(t *testing.T) {
+	tests := []struct {
+		name string
+		key  []byte
+	}{
+		{name: "empty key", key: []byte{}},
+		{name: "invalid key", key: []byte{0x1, 0x2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			accountInfo := AccountInfo{Id: domain.MustDecodeEntityId(1001), Key: tt.key}
+			expected := map[string]interface{}{"account_id": "0.0.1001", "exists": true}
+
+			// when
+			actual := accountInfo.ToMetadata()
+
+			// then
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
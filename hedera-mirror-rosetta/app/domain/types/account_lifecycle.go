@@ -0,0 +1,69 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package types
+
+import (
+	"encoding/hex"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/tools"
+)
+
+const (
+	AccountLifecycleStateCompleted = "COMPLETED"
+	AccountLifecycleStateHollow    = "HOLLOW"
+)
+
+// AccountLifecycle is domain level struct used to represent the lifecycle of a hollow account, i.e., an account auto
+// created with an evm address alias and an empty key, which is completed once a transaction it pays for sets its key.
+// AliasCollisions is the number of other accounts sharing the evm address
+type AccountLifecycle struct {
+	AliasCollisions    int64
+	CompletedTimestamp *int64
+	CreatedTimestamp   *int64
+	EvmAddress         []byte
+}
+
+// GetState returns the lifecycle state of the account, either HOLLOW or COMPLETED
+func (l AccountLifecycle) GetState() string {
+	if l.CompletedTimestamp != nil {
+		return AccountLifecycleStateCompleted
+	}
+	return AccountLifecycleStateHollow
+}
+
+// ToMetadata returns the lifecycle as metadata. The completed timestamp is only set once the account is completed, and
+// the alias collisions only when other accounts share the evm address
+func (l AccountLifecycle) ToMetadata() map[string]interface{} {
+	metadata := map[string]interface{}{
+		"evm_address": tools.SafeAddHexPrefix(hex.EncodeToString(l.EvmAddress)),
+		"state":       l.GetState(),
+	}
+	if l.AliasCollisions > 0 {
+		metadata["alias_collisions"] = l.AliasCollisions
+	}
+	if l.CompletedTimestamp != nil {
+		metadata["completed_timestamp"] = *l.CompletedTimestamp
+	}
+	if l.CreatedTimestamp != nil {
+		metadata["created_timestamp"] = *l.CreatedTimestamp
+	}
+	return metadata
+}
@@ -0,0 +1,70 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountLifecycleToMetadata(t *testing.T) {
+	completedTimestamp := int64(200)
+	createdTimestamp := int64(100)
+	evmAddress := []byte{0x71, 0xa8, 0xc2, 0xb2}
+	tests := []struct {
+		name      string
+		lifecycle AccountLifecycle
+		expected  map[string]interface{}
+	}{
+		{
+			name:      "hollow",
+			lifecycle: AccountLifecycle{CreatedTimestamp: &createdTimestamp, EvmAddress: evmAddress},
+			expected: map[string]interface{}{
+				"created_timestamp": createdTimestamp,
+				"evm_address":       "0x71a8c2b2",
+				"state":             AccountLifecycleStateHollow,
+			},
+		},
+		{
+			name: "completed with alias collisions",
+			lifecycle: AccountLifecycle{
+				AliasCollisions:    2,
+				CompletedTimestamp: &completedTimestamp,
+				CreatedTimestamp:   &createdTimestamp,
+				EvmAddress:         evmAddress,
+			},
+			expected: map[string]interface{}{
+				"alias_collisions":    int64(2),
+				"completed_timestamp": completedTimestamp,
+				"created_timestamp":   createdTimestamp,
+				"evm_address":         "0x71a8c2b2",
+				"state":               AccountLifecycleStateCompleted,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.lifecycle.ToMetadata())
+		})
+	}
+}
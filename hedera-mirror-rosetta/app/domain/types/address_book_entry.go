@@ -31,7 +31,11 @@ import (
 type AddressBookEntry struct {
 	NodeId    int64
 	AccountId domain.EntityId
+	// CertHash is the hex encoded SHA-384 hash of the node's PEM encoded TLS certificate
+	CertHash  string
 	Endpoints []string
+	// PublicKey is the hex encoded DER of the node's RSA public key, which signs the record stream files
+	PublicKey string
 }
 
 // AddressBookEntries is domain level struct used to represent an array of AddressBookEntry
@@ -0,0 +1,71 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package types
+
+import (
+	"encoding/hex"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/tools"
+)
+
+// AliasAccount is domain level struct used to represent an account ever associated with an alias or an evm address,
+// and the transaction which created it
+type AliasAccount struct {
+	AccountId               domain.EntityId
+	CreatedTimestamp        *int64
+	CreationTransactionHash []byte
+	CreationTransactionType *int32
+	Deleted                 bool
+}
+
+// ToMetadata returns the account identifier, whether the account is deleted, and the consensus timestamp, hash, and
+// type of the creation transaction if known as metadata
+func (a AliasAccount) ToMetadata() map[string]interface{} {
+	metadata := map[string]interface{}{
+		"account_identifier": NewAccountIdFromEntityId(a.AccountId).ToRosetta(),
+		"deleted":            a.Deleted,
+	}
+	if a.CreatedTimestamp == nil {
+		return metadata
+	}
+
+	creationTransaction := map[string]interface{}{"consensus_timestamp": *a.CreatedTimestamp}
+	if len(a.CreationTransactionHash) != 0 {
+		creationTransaction["hash"] = tools.SafeAddHexPrefix(hex.EncodeToString(a.CreationTransactionHash))
+	}
+	if a.CreationTransactionType != nil {
+		creationTransaction["type"] = TransactionTypes[*a.CreationTransactionType]
+	}
+	metadata["creation_transaction"] = creationTransaction
+	return metadata
+}
+
+// AliasAccounts is a list of the accounts ever associated with an alias or an evm address
+type AliasAccounts []AliasAccount
+
+// ToMetadata returns the metadata of the accounts in order
+func (a AliasAccounts) ToMetadata() map[string]interface{} {
+	accounts := make([]map[string]interface{}, 0, len(a))
+	for _, account := range a {
+		accounts = append(accounts, account.ToMetadata())
+	}
+	return map[string]interface{}{"accounts": accounts}
+}
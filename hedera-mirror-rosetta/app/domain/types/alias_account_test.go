@@ -0,0 +1,77 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package types
+
+import (
+	"testing"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAliasAccountsToMetadata(t *testing.T) {
+	// given
+	createdTimestamp := int64(100)
+	transactionType := int32(14)
+	accounts := AliasAccounts{
+		{
+			AccountId:               domain.MustDecodeEntityId(1001),
+			CreatedTimestamp:        &createdTimestamp,
+			CreationTransactionHash: []byte{0x1, 0xab},
+			CreationTransactionType: &transactionType,
+			Deleted:                 true,
+		},
+		{AccountId: domain.MustDecodeEntityId(1002), CreatedTimestamp: &createdTimestamp},
+		{AccountId: domain.MustDecodeEntityId(1003)},
+	}
+	expected := map[string]interface{}{
+		"accounts": []map[string]interface{}{
+			{
+				"account_identifier": &types.AccountIdentifier{Address: "0.0.1001"},
+				"creation_transaction": map[string]interface{}{
+					"consensus_timestamp": createdTimestamp,
+					"hash":                "0x01ab",
+					"type":                TransactionTypes[transactionType],
+				},
+				"deleted": true,
+			},
+			{
+				"account_identifier":   &types.AccountIdentifier{Address: "0.0.1002"},
+				"creation_transaction": map[string]interface{}{"consensus_timestamp": createdTimestamp},
+				"deleted":              false,
+			},
+			{
+				"account_identifier": &types.AccountIdentifier{Address: "0.0.1003"},
+				"deleted":            false,
+			},
+		},
+	}
+
+	// when
+	actual := accounts.ToMetadata()
+
+	// then
+	assert.Equal(t, expected, actual)
+}
+
+func TestAliasAccountsToMetadataEmpty(t *testing.T) {
+	assert.Equal(t, map[string]interface{}{"accounts": []map[string]interface{}{}}, AliasAccounts{}.ToMetadata())
+}
@@ -22,8 +22,10 @@ package types
 
 import (
 	"encoding/base64"
+	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
@@ -55,6 +57,31 @@ func (a AmountSlice) ToRosetta() []*types.Amount {
 	return rosettaAmounts
 }
 
+// SetHbarCurrency sets the representation of the hbar currency in the balances, the blocks, and the construction
+// requests, either HBAR with 8 decimals or TINYBAR with 0 decimals. The amount values are in tinybars either way. It's
+// not safe to call it concurrently with the amount functions and should only be called once at startup
+func SetHbarCurrency(representation string) error {
+	switch strings.ToUpper(representation) {
+	case "", HbarCurrencyHbar:
+		CurrencyHbar = newHbarCurrency(HbarCurrencyHbar, hbarDecimals)
+	case HbarCurrencyTinybar:
+		CurrencyHbar = newHbarCurrency(HbarCurrencyTinybar, tinybarDecimals)
+	default:
+		return fmt.Errorf("Unsupported hbar currency %s", representation)
+	}
+	return nil
+}
+
+func newHbarCurrency(symbol string, decimals int32) *types.Currency {
+	return &types.Currency{
+		Symbol:   symbol,
+		Decimals: decimals,
+		Metadata: map[string]interface{}{
+			"issuer": Blockchain,
+		},
+	}
+}
+
 type HbarAmount struct {
 	Value int64
 }
@@ -119,7 +146,7 @@ func (t *TokenAmount) SetSerialNumbers(serialNumbers []int64) *TokenAmount {
 	return t
 }
 
-// ToRosetta returns Rosetta type Amount with the token's currency
+// ToRosetta returns Rosetta type Amount with the token's currency, see Token.ToRosettaCurrency
 func (t *TokenAmount) ToRosetta() *types.Amount {
 	amount := types.Amount{
 		Value: strconv.FormatInt(t.Value, 10),
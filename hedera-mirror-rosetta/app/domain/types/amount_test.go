@@ -61,6 +61,64 @@ func TestAmountSliceToRosetta(t *testing.T) {
 	assert.Equal(t, expected, actual)
 }
 
+func TestSetHbarCurrency(t *testing.T) {
+	var tests = []struct {
+		representation string
+		decimals       int32
+		symbol         string
+	}{
+		{representation: "", decimals: 8, symbol: "HBAR"},
+		{representation: "hbar", decimals: 8, symbol: "HBAR"},
+		{representation: "TINYBAR", decimals: 0, symbol: "TINYBAR"},
+		{representation: "tinybar", decimals: 0, symbol: "TINYBAR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.representation, func(t *testing.T) {
+			// given
+			t.Cleanup(func() { _ = SetHbarCurrency(HbarCurrencyHbar) })
+
+			// when
+			err := SetHbarCurrency(tt.representation)
+
+			// then
+			assert.NoError(t, err)
+			expected := &types.Currency{
+				Decimals: tt.decimals,
+				Metadata: map[string]interface{}{"issuer": Blockchain},
+				Symbol:   tt.symbol,
+			}
+			assert.Equal(t, expected, CurrencyHbar)
+			hbarAmount := &HbarAmount{Value: 400}
+			assert.Equal(t, &types.Amount{Value: "400", Currency: expected}, hbarAmount.ToRosetta())
+			assert.Equal(t, int64(tt.decimals), hbarAmount.GetDecimals())
+			assert.Equal(t, tt.symbol, hbarAmount.GetSymbol())
+		})
+	}
+}
+
+func TestSetHbarCurrencyInvalid(t *testing.T) {
+	assert.Error(t, SetHbarCurrency("WEIBAR"))
+	assert.Equal(t, HbarCurrencyHbar, CurrencyHbar.Symbol)
+}
+
+func TestNewAmountTinybar(t *testing.T) {
+	// given
+	t.Cleanup(func() { _ = SetHbarCurrency(HbarCurrencyHbar) })
+	assert.NoError(t, SetHbarCurrency(HbarCurrencyTinybar))
+	tinybar := &types.Currency{Symbol: "TINYBAR", Decimals: 0, Metadata: map[string]interface{}{"issuer": Blockchain}}
+	hbar := &types.Currency{Symbol: "HBAR", Decimals: 8, Metadata: map[string]interface{}{"issuer": Blockchain}}
+
+	// when
+	actual, err := NewAmount(&types.Amount{Value: "400", Currency: tinybar})
+	_, hbarErr := NewAmount(&types.Amount{Value: "400", Currency: hbar})
+
+	// then
+	assert.Nil(t, err)
+	assert.Equal(t, &HbarAmount{Value: 400}, actual)
+	assert.NotNil(t, hbarErr)
+}
+
 func TestHbarAmountGetValue(t *testing.T) {
 	assert.Equal(t, int64(400), hbarAmount.GetValue())
 }
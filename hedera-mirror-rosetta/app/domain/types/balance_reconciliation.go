@@ -0,0 +1,96 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package types
+
+import (
+	"encoding/hex"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/tools"
+)
+
+// BalanceSnapshot is domain level struct used to represent the hbar balance of an account in a balance snapshot. The
+// timestamp is the consensus timestamp of the balance file plus its time offset
+type BalanceSnapshot struct {
+	Balance   int64
+	Timestamp int64
+}
+
+// ReconciliationTransaction is domain level struct used to represent the net hbar change of an account by a
+// transaction
+type ReconciliationTransaction struct {
+	Amount             int64
+	ConsensusTimestamp int64
+	Hash               []byte
+	Type               *int32
+}
+
+// BalanceReconciliation is domain level struct used to represent the hbar balance of an account in the nearest balance
+// snapshot compared to the balance computed from the previous snapshot and the crypto transfers in between.
+// Transactions are the earliest of the TransactionCount transactions which changed the balance between the snapshots
+type BalanceReconciliation struct {
+	AccountId        domain.EntityId
+	Nearest          BalanceSnapshot
+	Previous         BalanceSnapshot
+	TransactionCount int64
+	Transactions     []ReconciliationTransaction
+	TransferChange   int64
+}
+
+// ComputedBalance returns the balance computed from the previous snapshot and the crypto transfers in between
+func (b BalanceReconciliation) ComputedBalance() int64 {
+	return b.Previous.Balance + b.TransferChange
+}
+
+// Delta returns the balance in the nearest snapshot minus the computed balance, 0 if they reconcile
+func (b BalanceReconciliation) Delta() int64 {
+	return b.Nearest.Balance - b.ComputedBalance()
+}
+
+// ToMetadata returns the balances in the two snapshots, the computed balance, the delta, and the contributing
+// transactions as metadata
+func (b BalanceReconciliation) ToMetadata() map[string]interface{} {
+	transactions := make([]map[string]interface{}, 0, len(b.Transactions))
+	for _, transaction := range b.Transactions {
+		metadata := map[string]interface{}{
+			"amount":              (&HbarAmount{Value: transaction.Amount}).ToRosetta(),
+			"consensus_timestamp": transaction.ConsensusTimestamp,
+		}
+		if len(transaction.Hash) != 0 {
+			metadata["hash"] = tools.SafeAddHexPrefix(hex.EncodeToString(transaction.Hash))
+		}
+		if transaction.Type != nil {
+			metadata["type"] = TransactionTypes[*transaction.Type]
+		}
+		transactions = append(transactions, metadata)
+	}
+
+	return map[string]interface{}{
+		"account_identifier":          NewAccountIdFromEntityId(b.AccountId).ToRosetta(),
+		"computed_balance":            (&HbarAmount{Value: b.ComputedBalance()}).ToRosetta(),
+		"delta":                       (&HbarAmount{Value: b.Delta()}).ToRosetta(),
+		"previous_snapshot_balance":   (&HbarAmount{Value: b.Previous.Balance}).ToRosetta(),
+		"previous_snapshot_timestamp": b.Previous.Timestamp,
+		"snapshot_balance":            (&HbarAmount{Value: b.Nearest.Balance}).ToRosetta(),
+		"snapshot_timestamp":          b.Nearest.Timestamp,
+		"transaction_count":           b.TransactionCount,
+		"transactions":                transactions,
+	}
+}
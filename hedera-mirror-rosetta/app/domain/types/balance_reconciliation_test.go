@@ -0,0 +1,118 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package types
+
+import (
+	"testing"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBalanceReconciliationDelta(t *testing.T) {
+	tests := []struct {
+		name             string
+		reconciliation   BalanceReconciliation
+		expectedComputed int64
+		expectedDelta    int64
+	}{
+		{
+			name: "reconciled",
+			reconciliation: BalanceReconciliation{
+				Nearest:        BalanceSnapshot{Balance: 150},
+				Previous:       BalanceSnapshot{Balance: 100},
+				TransferChange: 50,
+			},
+			expectedComputed: 150,
+		},
+		{
+			name: "missing transfers",
+			reconciliation: BalanceReconciliation{
+				Nearest:        BalanceSnapshot{Balance: 150},
+				Previous:       BalanceSnapshot{Balance: 100},
+				TransferChange: 20,
+			},
+			expectedComputed: 120,
+			expectedDelta:    30,
+		},
+		{
+			name: "extra transfers",
+			reconciliation: BalanceReconciliation{
+				Nearest:        BalanceSnapshot{Balance: 100},
+				Previous:       BalanceSnapshot{Balance: 100},
+				TransferChange: 10,
+			},
+			expectedComputed: 110,
+			expectedDelta:    -10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedComputed, tt.reconciliation.ComputedBalance())
+			assert.Equal(t, tt.expectedDelta, tt.reconciliation.Delta())
+		})
+	}
+}
+
+func TestBalanceReconciliationToMetadata(t *testing.T) {
+	// given
+	transactionType := int32(14)
+	reconciliation := BalanceReconciliation{
+		AccountId:        domain.MustDecodeEntityId(1001),
+		Nearest:          BalanceSnapshot{Balance: 150, Timestamp: 200},
+		Previous:         BalanceSnapshot{Balance: 100, Timestamp: 100},
+		TransactionCount: 3,
+		Transactions: []ReconciliationTransaction{
+			{Amount: 30, ConsensusTimestamp: 120, Hash: []byte{0x1, 0xab}, Type: &transactionType},
+			{Amount: -10, ConsensusTimestamp: 130},
+		},
+		TransferChange: 40,
+	}
+	expected := map[string]interface{}{
+		"account_identifier":          &types.AccountIdentifier{Address: "0.0.1001"},
+		"computed_balance":            (&HbarAmount{Value: 140}).ToRosetta(),
+		"delta":                       (&HbarAmount{Value: 10}).ToRosetta(),
+		"previous_snapshot_balance":   (&HbarAmount{Value: 100}).ToRosetta(),
+		"previous_snapshot_timestamp": int64(100),
+		"snapshot_balance":            (&HbarAmount{Value: 150}).ToRosetta(),
+		"snapshot_timestamp":          int64(200),
+		"transaction_count":           int64(3),
+		"transactions": []map[string]interface{}{
+			{
+				"amount":              (&HbarAmount{Value: 30}).ToRosetta(),
+				"consensus_timestamp": int64(120),
+				"hash":                "0x01ab",
+				"type":                TransactionTypes[transactionType],
+			},
+			{
+				"amount":              (&HbarAmount{Value: -10}).ToRosetta(),
+				"consensus_timestamp": int64(130),
+			},
+		},
+	}
+
+	// when
+	actual := reconciliation.ToMetadata()
+
+	// then
+	assert.Equal(t, expected, actual)
+}
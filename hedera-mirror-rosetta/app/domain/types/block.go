@@ -27,6 +27,17 @@ import (
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/tools"
 )
 
+const (
+	metadataKeyConsensusEndNanos   = "consensus_end_nanos"
+	metadataKeyConsensusStartNanos = "consensus_start_nanos"
+	metadataKeyHbarMoved           = "hbar_moved"
+	metadataKeyOperationCount      = "operation_count"
+	metadataKeyTokensTouched       = "tokens_touched"
+	metadataKeyTransactionCount    = "transaction_count"
+
+	transactionResultSuccess = 22
+)
+
 // Block is domain level struct used to represent Block conceptual mapping in Hedera
 type Block struct {
 	ConsensusEndNanos   int64
@@ -35,7 +46,11 @@ type Block struct {
 	Index               int64
 	ParentHash          string
 	ParentIndex         int64
-	Transactions        []*Transaction
+	// RecordStream is true if the block is read from the record stream ahead of the backend, it lacks the details only
+	// the backend has and must not be persisted
+	RecordStream bool
+	// Transactions is nil if the transactions of the block are not loaded, e.g., for an oversized block
+	Transactions []*Transaction
 }
 
 // ToRosetta returns Rosetta type Block from the current domain type Block
@@ -53,7 +68,46 @@ func (b *Block) ToRosetta() *types.Block {
 		},
 		Timestamp:    b.GetTimestampMillis(),
 		Transactions: transactions,
+		Metadata:     b.GetMetadata(),
+	}
+}
+
+// GetMetadata returns the exact consensus start and end timestamps in nanoseconds of the block as metadata, since the
+// rosetta block timestamp is in milliseconds. If the transactions are loaded, the metadata also has the number of
+// transactions and operations, the hbar moved, i.e., the sum of the successful hbar credits including the fees in
+// tinybars, and the number of distinct tokens in the operations, which serve as cheap integrity checksums of the block
+func (b *Block) GetMetadata() map[string]interface{} {
+	metadata := map[string]interface{}{
+		metadataKeyConsensusEndNanos:   b.ConsensusEndNanos,
+		metadataKeyConsensusStartNanos: b.ConsensusStartNanos,
+	}
+	if b.Transactions == nil {
+		return metadata
 	}
+
+	success := TransactionResults[transactionResultSuccess]
+	hbarMoved := int64(0)
+	operationCount := 0
+	tokens := make(map[int64]struct{})
+	for _, transaction := range b.Transactions {
+		operationCount += len(transaction.Operations)
+		for _, operation := range transaction.Operations {
+			switch amount := operation.Amount.(type) {
+			case *HbarAmount:
+				if operation.Status == success && amount.Value > 0 {
+					hbarMoved += amount.Value
+				}
+			case *TokenAmount:
+				tokens[amount.TokenId.EncodedId] = struct{}{}
+			}
+		}
+	}
+
+	metadata[metadataKeyHbarMoved] = hbarMoved
+	metadata[metadataKeyOperationCount] = operationCount
+	metadata[metadataKeyTokensTouched] = len(tokens)
+	metadata[metadataKeyTransactionCount] = len(b.Transactions)
+	return metadata
 }
 
 func (b *Block) GetRosettaBlockIdentifier() *types.BlockIdentifier {
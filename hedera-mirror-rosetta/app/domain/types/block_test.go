@@ -24,6 +24,7 @@ import (
 	"testing"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -55,6 +56,14 @@ func expectedBlock() *types.Block {
 			Hash:  "0xsomeparenthash",
 		},
 		Timestamp: int64(10),
+		Metadata: map[string]interface{}{
+			"consensus_end_nanos":   int64(12300000),
+			"consensus_start_nanos": int64(10000000),
+			"hbar_moved":            int64(0),
+			"operation_count":       0,
+			"tokens_touched":        0,
+			"transaction_count":     1,
+		},
 		Transactions: []*types.Transaction{
 			{
 				TransactionIdentifier: &types.TransactionIdentifier{Hash: "somehash"},
@@ -73,6 +82,68 @@ func TestToRosettaBlock(t *testing.T) {
 	assert.Equal(t, expectedBlock(), rosettaBlockResult)
 }
 
+func TestGetMetadata(t *testing.T) {
+	// given:
+	block := &Block{ConsensusStartNanos: 1645564815001002003, ConsensusEndNanos: 1645564817999999999}
+	expected := map[string]interface{}{
+		"consensus_end_nanos":   int64(1645564817999999999),
+		"consensus_start_nanos": int64(1645564815001002003),
+	}
+
+	// when:
+	actual := block.GetMetadata()
+
+	// then:
+	assert.Equal(t, expected, actual)
+}
+
+func TestGetMetadataWithTransactions(t *testing.T) {
+	// given:
+	otherTokenId := domain.MustDecodeEntityId(1581)
+	success := TransactionResults[22]
+	failure := TransactionResults[11]
+	block := &Block{
+		ConsensusStartNanos: 1645564815001002003,
+		ConsensusEndNanos:   1645564817999999999,
+		Transactions: []*Transaction{
+			{
+				Hash: "0x1",
+				Operations: OperationSlice{
+					{Amount: &HbarAmount{Value: -150}, Status: success},
+					{Amount: &HbarAmount{Value: 100}, Status: success},
+					{Amount: &HbarAmount{Value: 50}, Status: success},
+					{Amount: &TokenAmount{TokenId: tokenId, Value: -10}, Status: success},
+					{Amount: &TokenAmount{TokenId: tokenId, Value: 10}, Status: success},
+				},
+			},
+			{
+				Hash: "0x2",
+				Operations: OperationSlice{
+					{Amount: &HbarAmount{Value: -20}, Status: success},
+					{Amount: &HbarAmount{Value: 20}, Status: success},
+					{Amount: &HbarAmount{Value: 500}, Status: failure},
+					{Amount: &TokenAmount{TokenId: otherTokenId, Value: 1}, Status: failure},
+				},
+			},
+			{Hash: "0x3", Operations: OperationSlice{}},
+		},
+	}
+	expected := map[string]interface{}{
+		"consensus_end_nanos":   int64(1645564817999999999),
+		"consensus_start_nanos": int64(1645564815001002003),
+		"hbar_moved":            int64(170),
+		"operation_count":       9,
+		"tokens_touched":        2,
+		"transaction_count":     3,
+	}
+
+	// when:
+	actual := block.GetMetadata()
+
+	// then:
+	assert.Equal(t, expected, actual)
+}
+
 func TestGetTimestampMillis(t *testing.T) {
 	// given:
 	exampleBlock := exampleBlock()
@@ -20,8 +20,6 @@
 
 package types
 
-import "github.com/coinbase/rosetta-sdk-go/types"
-
 const (
 	OperationTypeCryptoCreateAccount = "CRYPTOCREATEACCOUNT"
 	OperationTypeCryptoTransfer      = "CRYPTOTRANSFER"
@@ -38,14 +36,44 @@ const (
 	OperationTypeTokenUpdate         = "TOKENUPDATE"
 	OperationTypeTokenWipe           = "TOKENWIPE"
 
-	OperationTypeFee = "FEE"
+	OperationTypeApprovedTransfer        = "APPROVED_TRANSFER"
+	OperationTypeFee                     = "FEE"
+	OperationTypeHollowAccountCompletion = "HOLLOW_ACCOUNT_COMPLETION"
+)
+
+const (
+	CallMethodAccountBalances        = "account_balances"
+	CallMethodAliasAccounts          = "alias_accounts"
+	CallMethodBalanceReconciliation  = "balance_reconciliation"
+	CallMethodBlockByTimestamp       = "block_by_timestamp"
+	CallMethodBlockTransactionCount  = "block_transaction_count"
+	CallMethodDecodedTransaction     = "decoded_transaction"
+	CallMethodEntityStake            = "entity_stake"
+	CallMethodNftInfo                = "nft_info"
+	CallMethodNftSerials             = "nft_serials"
+	CallMethodNodeStakes             = "node_stakes"
+	CallMethodPayoutTransactions     = "payout_transactions"
+	CallMethodPendingAirdrops        = "pending_airdrops"
+	CallMethodPreviewTransaction     = "preview_transaction"
+	CallMethodRebroadcastTransaction = "rebroadcast_transaction"
+	CallMethodScheduleInfo           = "schedule_info"
+	CallMethodStakingRewardHistory   = "staking_reward_history"
+	CallMethodSubmissionStatus       = "submission_status"
+	CallMethodTokenBalances          = "token_balances"
+	CallMethodTokenHolders           = "token_holders"
+	CallMethodTopicMessage           = "topic_message"
+	CallMethodTransactionStatistics  = "transaction_statistics"
+	CallMethodVerifyBlock            = "verify_block"
 )
 
 const (
 	Blockchain = "Hedera"
 
-	currencySymbol   = "HBAR"
-	currencyDecimals = 8
+	HbarCurrencyHbar    = "HBAR"
+	HbarCurrencyTinybar = "TINYBAR"
+
+	hbarDecimals    = 8
+	tinybarDecimals = 0
 )
 
 var TransactionResults = map[int32]string{
@@ -382,16 +410,15 @@ var TransactionTypes = map[int32]string{
 	50: "ETHEREUMTRANSACTION",
 	51: "NODESTAKEUPDATE",
 	52: "UTILPRNG",
+	57: "TOKENREJECT",
+	58: "TOKENAIRDROP",
+	59: "TOKENCANCELAIRDROP",
+	60: "TOKENCLAIMAIRDROP",
 }
 
 var (
-	CurrencyHbar = &types.Currency{
-		Symbol:   currencySymbol,
-		Decimals: currencyDecimals,
-		Metadata: map[string]interface{}{
-			"issuer": Blockchain,
-		},
-	}
+	// CurrencyHbar is the currency of the hbar amounts, set by SetHbarCurrency
+	CurrencyHbar = newHbarCurrency(HbarCurrencyHbar, hbarDecimals)
 
 	SupportedOperationTypes = []string{
 		OperationTypeCryptoCreateAccount,
@@ -409,4 +436,29 @@ var (
 		OperationTypeTokenUpdate,
 		OperationTypeTokenWipe,
 	}
+
+	SupportedCallMethods = []string{
+		CallMethodAccountBalances,
+		CallMethodAliasAccounts,
+		CallMethodBalanceReconciliation,
+		CallMethodBlockByTimestamp,
+		CallMethodBlockTransactionCount,
+		CallMethodDecodedTransaction,
+		CallMethodEntityStake,
+		CallMethodNftInfo,
+		CallMethodNftSerials,
+		CallMethodNodeStakes,
+		CallMethodPayoutTransactions,
+		CallMethodPendingAirdrops,
+		CallMethodPreviewTransaction,
+		CallMethodRebroadcastTransaction,
+		CallMethodScheduleInfo,
+		CallMethodStakingRewardHistory,
+		CallMethodSubmissionStatus,
+		CallMethodTokenBalances,
+		CallMethodTokenHolders,
+		CallMethodTopicMessage,
+		CallMethodTransactionStatistics,
+		CallMethodVerifyBlock,
+	}
 )
@@ -0,0 +1,103 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package types
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/tools"
+	"github.com/pkg/errors"
+)
+
+const (
+	cursorFieldSeparator = ":"
+	cursorFieldCount     = 3
+	transactionHashSize  = 48
+)
+
+// Cursor is the opaque pagination token shared by the paginated endpoints. It marks the last item returned by its
+// position in the listing, i.e., the consensus timestamp of a transaction, the serial number of an nft, or the encoded
+// id of an account, and the hash of a transaction, so a listing can be resumed deterministically from the next item
+type Cursor struct {
+	// Hash is the hash of the last transaction returned, empty if the items aren't transactions
+	Hash     string
+	Position int64
+	issuedAt int64
+}
+
+// Encode returns the base64 url encoded cursor
+func (c Cursor) Encode() string {
+	raw := strings.Join([]string{
+		strconv.FormatInt(c.Position, 10),
+		tools.SafeRemoveHexPrefix(c.Hash),
+		strconv.FormatInt(c.issuedAt, 10),
+	}, cursorFieldSeparator)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// NewCursor creates a Cursor pointing at the item at the position with the transaction hash, issued at now
+func NewCursor(position int64, hash string) Cursor {
+	return Cursor{Hash: hash, Position: position, issuedAt: time.Now().UnixNano()}
+}
+
+// NewCursorFromString decodes and validates the encoded cursor. A cursor issued more than ttl ago or issued in the
+// future is rejected
+func NewCursorFromString(encoded string, ttl time.Duration) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.Errorf("Invalid cursor encoding")
+	}
+
+	fields := strings.Split(string(data), cursorFieldSeparator)
+	if len(fields) != cursorFieldCount {
+		return nil, errors.Errorf("Invalid cursor format")
+	}
+
+	position, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil || position <= 0 {
+		return nil, errors.Errorf("Invalid cursor position")
+	}
+
+	var hash string
+	if fields[1] != "" {
+		hashBytes, err := hex.DecodeString(fields[1])
+		if err != nil || len(hashBytes) != transactionHashSize {
+			return nil, errors.Errorf("Invalid cursor transaction hash")
+		}
+		hash = tools.SafeAddHexPrefix(hex.EncodeToString(hashBytes))
+	}
+
+	issuedAt, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return nil, errors.Errorf("Invalid cursor issued at timestamp")
+	}
+
+	now := time.Now().UnixNano()
+	if issuedAt > now || now-issuedAt > ttl.Nanoseconds() {
+		return nil, errors.Errorf("Cursor expired")
+	}
+
+	return &Cursor{Hash: hash, Position: position, issuedAt: issuedAt}, nil
+}
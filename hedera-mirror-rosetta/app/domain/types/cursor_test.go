@@ -0,0 +1,109 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package types
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	cursorTtl         = time.Minute
+	cursorTransaction = "0x0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f202122232425262728292a2b2c2d2e2f30"
+)
+
+func TestCursorEncodeDecode(t *testing.T) {
+	// given
+	cursor := NewCursor(1000, cursorTransaction)
+
+	// when
+	actual, err := NewCursorFromString(cursor.Encode(), cursorTtl)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, &cursor, actual)
+}
+
+func TestCursorEncodeDecodeWithoutHexPrefix(t *testing.T) {
+	// given
+	cursor := NewCursor(1000, strings.TrimPrefix(cursorTransaction, "0x"))
+
+	// when
+	actual, err := NewCursorFromString(cursor.Encode(), cursorTtl)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), actual.Position)
+	assert.Equal(t, cursorTransaction, actual.Hash)
+}
+
+func TestCursorEncodeDecodeWithoutHash(t *testing.T) {
+	// given
+	cursor := NewCursor(1000, "")
+
+	// when
+	actual, err := NewCursorFromString(cursor.Encode(), cursorTtl)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, &cursor, actual)
+}
+
+func TestNewCursorFromStringFail(t *testing.T) {
+	now := time.Now()
+	hash := strings.TrimPrefix(cursorTransaction, "0x")
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+	issued := func(timestamp time.Time) string {
+		return Cursor{Hash: hash, Position: 1000, issuedAt: timestamp.UnixNano()}.Encode()
+	}
+
+	tests := []struct {
+		name    string
+		encoded string
+	}{
+		{name: "empty", encoded: ""},
+		{name: "invalid base64", encoded: "!!!"},
+		{name: "padded base64", encoded: base64.URLEncoding.EncodeToString([]byte("1000:" + hash + ":1"))},
+		{name: "missing field", encoded: encode("1000:" + hash)},
+		{name: "extra field", encoded: encode("1000:" + hash + ":1:1")},
+		{name: "invalid position", encoded: encode("abc:" + hash + ":1")},
+		{name: "zero position", encoded: encode("0:" + hash + ":1")},
+		{name: "invalid hash", encoded: encode("1000:xyz:1")},
+		{name: "short hash", encoded: encode("1000:0102:1")},
+		{name: "invalid issued at", encoded: encode("1000:" + hash + ":abc")},
+		{name: "expired", encoded: issued(now.Add(-cursorTtl - time.Second))},
+		{name: "issued in future", encoded: issued(now.Add(time.Minute))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := NewCursorFromString(tt.encoded, cursorTtl)
+			assert.Error(t, err)
+			assert.Nil(t, actual)
+		})
+	}
+}
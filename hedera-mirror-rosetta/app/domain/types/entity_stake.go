@@ -0,0 +1,60 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package types
+
+import "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+
+// EntityStake is domain level struct used to represent the stake of an account, i.e., its own balance and the balance
+// of the accounts staked to it, its staking settings, and its estimated pending reward
+type EntityStake struct {
+	AccountId        domain.EntityId
+	Balance          int64
+	DeclineReward    bool
+	PendingReward    int64
+	StakedNodeId     *int64
+	StakedToMe       int64
+	StakePeriodStart *int64
+}
+
+// IsStakedToNode returns true if the account stakes to a node
+func (s EntityStake) IsStakedToNode() bool {
+	return s.StakedNodeId != nil && *s.StakedNodeId >= 0
+}
+
+// ToMetadata returns the entity stake as metadata. The stake total is the sum of the balance and the staked to me
+// amount, i.e., what the account contributes to the stake of its staked node. Same as the staking history, the staked
+// node id and the stake period start are only set when the account stakes to a node
+func (s EntityStake) ToMetadata() map[string]interface{} {
+	metadata := map[string]interface{}{
+		"account_identifier": NewAccountIdFromEntityId(s.AccountId).ToRosetta(),
+		"decline_reward":     s.DeclineReward,
+		"pending_reward":     (&HbarAmount{Value: s.PendingReward}).ToRosetta(),
+		"stake_total":        (&HbarAmount{Value: s.Balance + s.StakedToMe}).ToRosetta(),
+		"staked_to_me":       (&HbarAmount{Value: s.StakedToMe}).ToRosetta(),
+	}
+	if s.IsStakedToNode() {
+		metadata["staked_node_id"] = *s.StakedNodeId
+		if s.StakePeriodStart != nil {
+			metadata["stake_period_start"] = *s.StakePeriodStart
+		}
+	}
+	return metadata
+}
@@ -0,0 +1,84 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package types
+
+import (
+	"testing"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntityStakeToMetadata(t *testing.T) {
+	nodeId := int64(3)
+	noNode := int64(-1)
+	stakePeriodStart := int64(19200)
+	accountIdentifier := &types.AccountIdentifier{Address: "0.0.1001"}
+	tests := []struct {
+		name     string
+		stake    EntityStake
+		expected map[string]interface{}
+	}{
+		{
+			name: "not staked",
+			stake: EntityStake{
+				AccountId:    domain.MustDecodeEntityId(1001),
+				Balance:      100,
+				StakedNodeId: &noNode,
+				StakedToMe:   50,
+			},
+			expected: map[string]interface{}{
+				"account_identifier": accountIdentifier,
+				"decline_reward":     false,
+				"pending_reward":     (&HbarAmount{}).ToRosetta(),
+				"stake_total":        (&HbarAmount{Value: 150}).ToRosetta(),
+				"staked_to_me":       (&HbarAmount{Value: 50}).ToRosetta(),
+			},
+		},
+		{
+			name: "staked to node",
+			stake: EntityStake{
+				AccountId:        domain.MustDecodeEntityId(1001),
+				Balance:          100,
+				DeclineReward:    true,
+				PendingReward:    300,
+				StakedNodeId:     &nodeId,
+				StakePeriodStart: &stakePeriodStart,
+			},
+			expected: map[string]interface{}{
+				"account_identifier": accountIdentifier,
+				"decline_reward":     true,
+				"pending_reward":     (&HbarAmount{Value: 300}).ToRosetta(),
+				"stake_period_start": stakePeriodStart,
+				"stake_total":        (&HbarAmount{Value: 100}).ToRosetta(),
+				"staked_node_id":     nodeId,
+				"staked_to_me":       (&HbarAmount{}).ToRosetta(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.stake.ToMetadata())
+		})
+	}
+}
@@ -0,0 +1,57 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package types
+
+import (
+	"github.com/hashgraph/hedera-protobufs-go/services"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+// ExchangeRate is the exchange rate between hbars and USD cents, i.e., HbarEquiv hbars are worth CentEquiv cents
+type ExchangeRate struct {
+	CentEquiv int32
+	HbarEquiv int32
+}
+
+// TinycentsToTinybars converts the amount in USD tinycents to tinybars, rounded down the same way the network does
+func (e ExchangeRate) TinycentsToTinybars(tinycents int64) int64 {
+	return tinycents * int64(e.HbarEquiv) / int64(e.CentEquiv)
+}
+
+// NewExchangeRateFromBytes creates the current ExchangeRate from the protobuf-encoded exchange rate file content
+func NewExchangeRateFromBytes(data []byte) (*ExchangeRate, error) {
+	if len(data) == 0 {
+		return nil, errors.Errorf("Empty exchange rate set provided")
+	}
+
+	var exchangeRateSet services.ExchangeRateSet
+	if err := proto.Unmarshal(data, &exchangeRateSet); err != nil {
+		return nil, err
+	}
+
+	currentRate := exchangeRateSet.GetCurrentRate()
+	if currentRate.GetCentEquiv() <= 0 || currentRate.GetHbarEquiv() <= 0 {
+		return nil, errors.Errorf("Invalid current exchange rate")
+	}
+
+	return &ExchangeRate{CentEquiv: currentRate.GetCentEquiv(), HbarEquiv: currentRate.GetHbarEquiv()}, nil
+}
@@ -0,0 +1,82 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package types
+
+import (
+	"testing"
+
+	"github.com/hashgraph/hedera-protobufs-go/services"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestExchangeRateTinycentsToTinybars(t *testing.T) {
+	// 1 hbar = 12 cents, i.e., 100000000 tinybars = 1200000000 tinycents
+	exchangeRate := ExchangeRate{CentEquiv: 12, HbarEquiv: 1}
+
+	assert.Equal(t, int64(0), exchangeRate.TinycentsToTinybars(0))
+	assert.Equal(t, int64(0), exchangeRate.TinycentsToTinybars(11))
+	assert.Equal(t, int64(83333), exchangeRate.TinycentsToTinybars(1000000))
+	assert.Equal(t, int64(100000000), exchangeRate.TinycentsToTinybars(1200000000))
+}
+
+func TestNewExchangeRateFromBytes(t *testing.T) {
+	// given
+	data, err := proto.Marshal(&services.ExchangeRateSet{
+		CurrentRate: &services.ExchangeRate{CentEquiv: 12, HbarEquiv: 1},
+		NextRate:    &services.ExchangeRate{CentEquiv: 15, HbarEquiv: 1},
+	})
+	assert.NoError(t, err)
+
+	// when
+	actual, err := NewExchangeRateFromBytes(data)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, &ExchangeRate{CentEquiv: 12, HbarEquiv: 1}, actual)
+}
+
+func TestNewExchangeRateFromBytesFail(t *testing.T) {
+	noCurrentRate, _ := proto.Marshal(&services.ExchangeRateSet{
+		NextRate: &services.ExchangeRate{CentEquiv: 15, HbarEquiv: 1},
+	})
+	zeroCentEquiv, _ := proto.Marshal(&services.ExchangeRateSet{
+		CurrentRate: &services.ExchangeRate{CentEquiv: 0, HbarEquiv: 1},
+	})
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "nil", data: nil},
+		{name: "empty", data: []byte{}},
+		{name: "invalid", data: []byte{0x1, 0x2, 0x3}},
+		{name: "no current rate", data: noCurrentRate},
+		{name: "zero cent equiv", data: zeroCentEquiv},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exchangeRate, err := NewExchangeRateFromBytes(tt.data)
+			assert.Error(t, err)
+			assert.Nil(t, exchangeRate)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package types
+
+const (
+	FeeBreakdownSourceRecord    = "record"
+	FeeBreakdownSourceTransfers = "transfers"
+)
+
+// FeeBreakdown is the split of the transaction fee charged to the payer by its recipients. The node fee is paid to the
+// node which submitted the transaction, the network fee to the fee collection account, and the service fee to the node
+// reward and the staking reward accounts
+type FeeBreakdown struct {
+	NetworkFee int64
+	NodeFee    int64
+	ServiceFee int64
+	Source     string
+	TotalFee   int64
+}
+
+// Add adds the fees of other to the fee breakdown. The source is downgraded to FeeBreakdownSourceTransfers if either
+// is an approximation
+func (f *FeeBreakdown) Add(other FeeBreakdown) {
+	f.NetworkFee += other.NetworkFee
+	f.NodeFee += other.NodeFee
+	f.ServiceFee += other.ServiceFee
+	f.TotalFee += other.TotalFee
+	if f.Source == "" || other.Source == FeeBreakdownSourceTransfers {
+		f.Source = other.Source
+	}
+}
+
+// ToMetadata returns the fee breakdown as transaction metadata, the fees are in tinybars
+func (f FeeBreakdown) ToMetadata() map[string]interface{} {
+	return map[string]interface{}{
+		"network_fee": f.NetworkFee,
+		"node_fee":    f.NodeFee,
+		"service_fee": f.ServiceFee,
+		"source":      f.Source,
+		"total_fee":   f.TotalFee,
+	}
+}
@@ -0,0 +1,74 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeeBreakdownAdd(t *testing.T) {
+	var tests = []struct {
+		name           string
+		source         string
+		otherSource    string
+		expectedSource string
+	}{
+		{"empty", "", FeeBreakdownSourceRecord, FeeBreakdownSourceRecord},
+		{"both record", FeeBreakdownSourceRecord, FeeBreakdownSourceRecord, FeeBreakdownSourceRecord},
+		{"record and transfers", FeeBreakdownSourceRecord, FeeBreakdownSourceTransfers, FeeBreakdownSourceTransfers},
+		{"transfers and record", FeeBreakdownSourceTransfers, FeeBreakdownSourceRecord, FeeBreakdownSourceTransfers},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			breakdown := FeeBreakdown{NetworkFee: 1, NodeFee: 2, ServiceFee: 3, Source: tt.source, TotalFee: 6}
+			other := FeeBreakdown{NetworkFee: 4, NodeFee: 5, ServiceFee: 6, Source: tt.otherSource, TotalFee: 15}
+			expected := FeeBreakdown{NetworkFee: 5, NodeFee: 7, ServiceFee: 9, Source: tt.expectedSource, TotalFee: 21}
+
+			// when
+			breakdown.Add(other)
+
+			// then
+			assert.Equal(t, expected, breakdown)
+		})
+	}
+}
+
+func TestFeeBreakdownToMetadata(t *testing.T) {
+	// given
+	breakdown := FeeBreakdown{NetworkFee: 10, NodeFee: 5, ServiceFee: 3, Source: FeeBreakdownSourceRecord, TotalFee: 18}
+	expected := map[string]interface{}{
+		"network_fee": int64(10),
+		"node_fee":    int64(5),
+		"service_fee": int64(3),
+		"source":      FeeBreakdownSourceRecord,
+		"total_fee":   int64(18),
+	}
+
+	// when
+	actual := breakdown.ToMetadata()
+
+	// then
+	assert.Equal(t, expected, actual)
+}
@@ -0,0 +1,107 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package types
+
+import (
+	"github.com/hashgraph/hedera-protobufs-go/services"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+// feeDivisorFactor is the factor the fee components are scaled by, i.e., the components are in 1/1000 tinycents
+const feeDivisorFactor = 1000
+
+// FeeSchedule maps the hedera functionality of a transaction to the fee data of its subtypes in the current fee
+// schedule of the network
+type FeeSchedule map[services.HederaFunctionality][]*services.FeeData
+
+// NewFeeScheduleFromBytes creates the current FeeSchedule from the protobuf-encoded fee schedule file content
+func NewFeeScheduleFromBytes(data []byte) (FeeSchedule, error) {
+	if len(data) == 0 {
+		return nil, errors.Errorf("Empty fee schedule provided")
+	}
+
+	var feeSchedules services.CurrentAndNextFeeSchedule
+	if err := proto.Unmarshal(data, &feeSchedules); err != nil {
+		return nil, err
+	}
+
+	transactionFeeSchedules := feeSchedules.GetCurrentFeeSchedule().GetTransactionFeeSchedule()
+	if len(transactionFeeSchedules) == 0 {
+		return nil, errors.Errorf("No current fee schedule")
+	}
+
+	feeSchedule := make(FeeSchedule)
+	for _, transactionFeeSchedule := range transactionFeeSchedules {
+		fees := transactionFeeSchedule.GetFees()
+		if len(fees) == 0 && transactionFeeSchedule.GetFeeData() != nil {
+			// the fee schedule of the network before the subtypes were introduced
+			fees = []*services.FeeData{transactionFeeSchedule.GetFeeData()}
+		}
+		feeSchedule[transactionFeeSchedule.GetHederaFunctionality()] = fees
+	}
+
+	return feeSchedule, nil
+}
+
+// MinFeeTinycents returns the estimated minimum fee in tinycents of a transaction of the hedera functionality with the
+// size in bytes and the number of signatures, false if the functionality has no fee in the fee schedule. The estimate
+// is the smallest fee of the subtypes, each the sum of the node, network, and service fees charged for the constant,
+// the bytes, and the signatures usage, so it's a lower bound of the fee the network charges
+func (f FeeSchedule) MinFeeTinycents(
+	functionality services.HederaFunctionality,
+	bytes int64,
+	signatures int64,
+) (int64, bool) {
+	var minFee int64
+	found := false
+	for _, feeData := range f[functionality] {
+		fee := getComponentFeeTinycents(feeData.GetNodedata(), bytes, signatures) +
+			getComponentFeeTinycents(feeData.GetNetworkdata(), bytes, signatures) +
+			getComponentFeeTinycents(feeData.GetServicedata(), bytes, signatures)
+		if !found || fee < minFee {
+			minFee = fee
+			found = true
+		}
+	}
+
+	return minFee, found
+}
+
+// getComponentFeeTinycents returns the fee of the component in tinycents the same way the network does, i.e., the
+// usage fee is bounded by the min and the max fee of the component and scaled down by feeDivisorFactor
+func getComponentFeeTinycents(components *services.FeeComponents, bytes int64, signatures int64) int64 {
+	if components == nil {
+		return 0
+	}
+
+	fee := components.GetConstant() + components.GetBpt()*bytes + components.GetVpt()*signatures
+	if fee < components.GetMin() {
+		fee = components.GetMin()
+	}
+	if components.GetMax() > 0 && fee > components.GetMax() {
+		fee = components.GetMax()
+	}
+
+	if fee > 0 && fee < feeDivisorFactor {
+		return 1
+	}
+	return fee / feeDivisorFactor
+}
@@ -0,0 +1,131 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package types
+
+import (
+	"testing"
+
+	"github.com/hashgraph/hedera-protobufs-go/services"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNewFeeScheduleFromBytes(t *testing.T) {
+	// given
+	transferFee := &services.FeeData{Nodedata: &services.FeeComponents{Constant: 1000}}
+	legacyFee := &services.FeeData{Servicedata: &services.FeeComponents{Constant: 2000}}
+	data := feeSchedulesBytes(
+		&services.TransactionFeeSchedule{
+			HederaFunctionality: services.HederaFunctionality_CryptoTransfer,
+			Fees:                []*services.FeeData{transferFee},
+		},
+		&services.TransactionFeeSchedule{
+			HederaFunctionality: services.HederaFunctionality_TokenMint,
+			FeeData:             legacyFee,
+		},
+	)
+
+	// when
+	actual, err := NewFeeScheduleFromBytes(data)
+
+	// then
+	assert.NoError(t, err)
+	assert.Len(t, actual, 2)
+	assert.True(t, proto.Equal(transferFee, actual[services.HederaFunctionality_CryptoTransfer][0]))
+	assert.True(t, proto.Equal(legacyFee, actual[services.HederaFunctionality_TokenMint][0]))
+}
+
+func TestNewFeeScheduleFromBytesFail(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "nil", data: nil},
+		{name: "empty", data: []byte{}},
+		{name: "invalid", data: []byte{0x1, 0x2, 0x3}},
+		{name: "no current fee schedule", data: feeSchedulesBytes()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feeSchedule, err := NewFeeScheduleFromBytes(tt.data)
+			assert.Error(t, err)
+			assert.Nil(t, feeSchedule)
+		})
+	}
+}
+
+func TestFeeScheduleMinFeeTinycents(t *testing.T) {
+	// given
+	feeSchedule := FeeSchedule{
+		services.HederaFunctionality_CryptoTransfer: {
+			{
+				SubType:     services.SubType_TOKEN_FUNGIBLE_COMMON,
+				Nodedata:    &services.FeeComponents{Constant: 900000, Bpt: 1000, Vpt: 10000},
+				Networkdata: &services.FeeComponents{Constant: 1000000, Bpt: 2000, Vpt: 20000},
+				Servicedata: &services.FeeComponents{Constant: 5000000},
+			},
+			{
+				SubType:     services.SubType_DEFAULT,
+				Nodedata:    &services.FeeComponents{Constant: 90000, Bpt: 1000, Vpt: 10000},
+				Networkdata: &services.FeeComponents{Constant: 100000, Bpt: 2000, Vpt: 20000, Max: 200000},
+			},
+		},
+		services.HederaFunctionality_TokenMint: {
+			{Servicedata: &services.FeeComponents{Constant: 100, Min: 2500}},
+		},
+	}
+	tests := []struct {
+		name          string
+		functionality services.HederaFunctionality
+		expected      int64
+		found         bool
+	}{
+		// node 90000 + 100 * 1000 + 2 * 10000, network 100000 + 100 * 2000 + 2 * 20000 bounded by 200000
+		{name: "smallest subtype", functionality: services.HederaFunctionality_CryptoTransfer, expected: 410, found: true},
+		{name: "min fee", functionality: services.HederaFunctionality_TokenMint, expected: 2, found: true},
+		{name: "unknown", functionality: services.HederaFunctionality_TokenBurn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			actual, found := feeSchedule.MinFeeTinycents(tt.functionality, 100, 2)
+
+			// then
+			assert.Equal(t, tt.expected, actual)
+			assert.Equal(t, tt.found, found)
+		})
+	}
+}
+
+func TestGetComponentFeeTinycents(t *testing.T) {
+	assert.Zero(t, getComponentFeeTinycents(nil, 100, 2))
+	assert.Zero(t, getComponentFeeTinycents(&services.FeeComponents{}, 100, 2))
+	assert.Equal(t, int64(1), getComponentFeeTinycents(&services.FeeComponents{Constant: 1}, 100, 2))
+	assert.Equal(t, int64(3), getComponentFeeTinycents(&services.FeeComponents{Constant: 3999}, 100, 2))
+}
+
+func feeSchedulesBytes(transactionFeeSchedules ...*services.TransactionFeeSchedule) []byte {
+	data, _ := proto.Marshal(&services.CurrentAndNextFeeSchedule{
+		CurrentFeeSchedule: &services.FeeSchedule{TransactionFeeSchedule: transactionFeeSchedules},
+	})
+	return data
+}
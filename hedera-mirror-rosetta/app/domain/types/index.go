@@ -0,0 +1,45 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package types
+
+// Index is a database index with the columns of its key in order. An expression in the key has an empty column name.
+// A partial index only covers the rows matching its predicate
+type Index struct {
+	Columns []string
+	Name    string
+	Partial bool
+	Table   string
+}
+
+// HasLeadingColumns returns true if the key of the index starts with the columns, in which case the index can serve
+// the lookups and the range scans on them
+func (i Index) HasLeadingColumns(columns []string) bool {
+	if len(columns) == 0 || len(columns) > len(i.Columns) {
+		return false
+	}
+
+	for j, column := range columns {
+		if i.Columns[j] != column {
+			return false
+		}
+	}
+
+	return true
+}
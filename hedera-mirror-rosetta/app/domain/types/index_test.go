@@ -0,0 +1,50 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexHasLeadingColumns(t *testing.T) {
+	index := Index{
+		Columns: []string{"entity_id", "consensus_timestamp"},
+		Name:    "crypto_transfer__entity_id_consensus_timestamp",
+		Table:   "crypto_transfer",
+	}
+
+	var tests = []struct {
+		columns  []string
+		expected bool
+	}{
+		{[]string{"entity_id"}, true},
+		{[]string{"entity_id", "consensus_timestamp"}, true},
+		{[]string{"consensus_timestamp"}, false},
+		{[]string{"entity_id", "consensus_timestamp", "amount"}, false},
+		{[]string{}, false},
+		{nil, false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, index.HasLeadingColumns(tt.columns), "columns %v", tt.columns)
+	}
+}
@@ -0,0 +1,147 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package types
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/tools"
+	"github.com/hashgraph/hedera-protobufs-go/services"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	KeyTypeContractId            = "CONTRACT_ID"
+	KeyTypeDelegatableContractId = "DELEGATABLE_CONTRACT_ID"
+	KeyTypeEcdsa384              = "ECDSA_384"
+	KeyTypeEcdsaSecp256k1        = "ECDSA_SECP256K1"
+	KeyTypeEd25519               = "ED25519"
+	KeyTypeKeyList               = "KEY_LIST"
+	KeyTypeRsa3072               = "RSA_3072"
+	KeyTypeThresholdKey          = "THRESHOLD_KEY"
+	KeyTypeUnknown               = "UNKNOWN"
+)
+
+// Key wraps the protobuf-encoded key of an entity
+type Key struct {
+	bytes []byte
+	key   *services.Key
+}
+
+// GetType returns the type of the top level key
+func (k Key) GetType() string {
+	return getKeyType(k.key)
+}
+
+// String returns the human-readable representation of the key. A primitive key is represented as its raw hex
+// encoded bytes, and a complex key is represented as its type followed by its child keys
+func (k Key) String() string {
+	return formatKey(k.key)
+}
+
+// ToMetadata returns the protobuf-encoded key, the human-readable key, and the key type as metadata
+func (k Key) ToMetadata() map[string]interface{} {
+	return map[string]interface{}{
+		"key":          tools.SafeAddHexPrefix(hex.EncodeToString(k.bytes)),
+		"key_readable": k.String(),
+		"key_type":     k.GetType(),
+	}
+}
+
+// NewKeyFromBytes creates a Key from the protobuf-encoded key bytes
+func NewKeyFromBytes(data []byte) (*Key, error) {
+	if len(data) == 0 {
+		return nil, errors.Errorf("Empty key provided")
+	}
+
+	var key services.Key
+	if err := proto.Unmarshal(data, &key); err != nil {
+		return nil, err
+	}
+
+	return &Key{bytes: data, key: &key}, nil
+}
+
+func formatContractId(contractId *services.ContractID) string {
+	return fmt.Sprintf("%d.%d.%d", contractId.GetShardNum(), contractId.GetRealmNum(), contractId.GetContractNum())
+}
+
+func formatKey(key *services.Key) string {
+	switch value := key.GetKey().(type) {
+	case *services.Key_ContractID:
+		return fmt.Sprintf("%s(%s)", KeyTypeContractId, formatContractId(value.ContractID))
+	case *services.Key_DelegatableContractId:
+		return fmt.Sprintf("%s(%s)", KeyTypeDelegatableContractId, formatContractId(value.DelegatableContractId))
+	case *services.Key_ECDSA_384:
+		return hex.EncodeToString(value.ECDSA_384)
+	case *services.Key_ECDSASecp256K1:
+		return hex.EncodeToString(value.ECDSASecp256K1)
+	case *services.Key_Ed25519:
+		return hex.EncodeToString(value.Ed25519)
+	case *services.Key_KeyList:
+		return fmt.Sprintf("%s[%s]", KeyTypeKeyList, formatKeyList(value.KeyList))
+	case *services.Key_RSA_3072:
+		return hex.EncodeToString(value.RSA_3072)
+	case *services.Key_ThresholdKey:
+		return fmt.Sprintf(
+			"%s(%d)[%s]",
+			KeyTypeThresholdKey,
+			value.ThresholdKey.GetThreshold(),
+			formatKeyList(value.ThresholdKey.GetKeys()),
+		)
+	default:
+		return KeyTypeUnknown
+	}
+}
+
+func formatKeyList(keyList *services.KeyList) string {
+	keys := make([]string, 0, len(keyList.GetKeys()))
+	for _, key := range keyList.GetKeys() {
+		keys = append(keys, formatKey(key))
+	}
+	return strings.Join(keys, ", ")
+}
+
+func getKeyType(key *services.Key) string {
+	switch key.GetKey().(type) {
+	case *services.Key_ContractID:
+		return KeyTypeContractId
+	case *services.Key_DelegatableContractId:
+		return KeyTypeDelegatableContractId
+	case *services.Key_ECDSA_384:
+		return KeyTypeEcdsa384
+	case *services.Key_ECDSASecp256K1:
+		return KeyTypeEcdsaSecp256k1
+	case *services.Key_Ed25519:
+		return KeyTypeEd25519
+	case *services.Key_KeyList:
+		return KeyTypeKeyList
+	case *services.Key_RSA_3072:
+		return KeyTypeRsa3072
+	case *services.Key_ThresholdKey:
+		return KeyTypeThresholdKey
+	default:
+		return KeyTypeUnknown
+	}
+}
@@ -0,0 +1,117 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package types
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/hashgraph/hedera-protobufs-go/services"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	ecdsaSecp256k1RawKey = []byte{
+		0x02, 0x1b, 0x2c, 0x3d, 0x4e, 0x5f, 0x60, 0x71, 0x82, 0x93, 0xa4, 0xb5, 0xc6, 0xd7, 0xe8, 0xf9, 0x0a,
+		0x1b, 0x2c, 0x3d, 0x4e, 0x5f, 0x60, 0x71, 0x82, 0x93, 0xa4, 0xb5, 0xc6, 0xd7, 0xe8, 0xf9, 0x0a,
+	}
+	ed25519RawKey = []byte{
+		0x1b, 0x2c, 0x3d, 0x4e, 0x5f, 0x60, 0x71, 0x82, 0x93, 0xa4, 0xb5, 0xc6, 0xd7, 0xe8, 0xf9, 0x0a,
+		0x1b, 0x2c, 0x3d, 0x4e, 0x5f, 0x60, 0x71, 0x82, 0x93, 0xa4, 0xb5, 0xc6, 0xd7, 0xe8, 0xf9, 0x0a,
+	}
+)
+
+func TestNewKeyFromBytes(t *testing.T) {
+	ed25519Key := &services.Key{Key: &services.Key_Ed25519{Ed25519: ed25519RawKey}}
+	ecdsaKey := &services.Key{Key: &services.Key_ECDSASecp256K1{ECDSASecp256K1: ecdsaSecp256k1RawKey}}
+	contractKey := &services.Key{Key: &services.Key_ContractID{ContractID: &services.ContractID{
+		Contract: &services.ContractID_ContractNum{ContractNum: 1001},
+	}}}
+	keyList := &services.Key{Key: &services.Key_KeyList{KeyList: &services.KeyList{
+		Keys: []*services.Key{ed25519Key, contractKey},
+	}}}
+	thresholdKey := &services.Key{Key: &services.Key_ThresholdKey{ThresholdKey: &services.ThresholdKey{
+		Threshold: 1,
+		Keys:      &services.KeyList{Keys: []*services.Key{ecdsaKey, keyList}},
+	}}}
+
+	ed25519Hex := hex.EncodeToString(ed25519RawKey)
+	ecdsaHex := hex.EncodeToString(ecdsaSecp256k1RawKey)
+	tests := []struct {
+		name             string
+		key              *services.Key
+		expectedReadable string
+		expectedType     string
+	}{
+		{name: "ed25519", key: ed25519Key, expectedReadable: ed25519Hex, expectedType: KeyTypeEd25519},
+		{name: "ecdsaSecp256k1", key: ecdsaKey, expectedReadable: ecdsaHex, expectedType: KeyTypeEcdsaSecp256k1},
+		{name: "contractId", key: contractKey, expectedReadable: "CONTRACT_ID(0.0.1001)", expectedType: KeyTypeContractId},
+		{
+			name:             "keyList",
+			key:              keyList,
+			expectedReadable: "KEY_LIST[" + ed25519Hex + ", CONTRACT_ID(0.0.1001)]",
+			expectedType:     KeyTypeKeyList,
+		},
+		{
+			name:             "thresholdKey",
+			key:              thresholdKey,
+			expectedReadable: "THRESHOLD_KEY(1)[" + ecdsaHex + ", KEY_LIST[" + ed25519Hex + ", CONTRACT_ID(0.0.1001)]]",
+			expectedType:     KeyTypeThresholdKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := proto.Marshal(tt.key)
+			assert.NoError(t, err)
+
+			key, err := NewKeyFromBytes(data)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedType, key.GetType())
+			assert.Equal(t, tt.expectedReadable, key.String())
+			assert.Equal(t, map[string]interface{}{
+				"key":          "0x" + hex.EncodeToString(data),
+				"key_readable": tt.expectedReadable,
+				"key_type":     tt.expectedType,
+			}, key.ToMetadata())
+		})
+	}
+}
+
+func TestNewKeyFromBytesFail(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "nil", data: nil},
+		{name: "empty", data: []byte{}},
+		{name: "invalid", data: []byte{0x1, 0x2, 0x3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := NewKeyFromBytes(tt.data)
+			assert.Error(t, err)
+			assert.Nil(t, key)
+		})
+	}
+}
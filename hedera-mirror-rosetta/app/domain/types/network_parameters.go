@@ -0,0 +1,96 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package types
+
+import (
+	"strconv"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/tools"
+	"github.com/hashgraph/hedera-protobufs-go/services"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	propertyLedgerId          = "ledger.id"
+	propertyMaxMemoSize       = "hedera.transaction.maxMemoUtf8Bytes"
+	propertyMaxTokenTransfers = "ledger.tokenTransfers.maxLen"
+	propertyMaxTransfers      = "ledger.transfers.maxLen"
+)
+
+// NetworkParameters is domain level struct used to represent the network parameters clients need to build valid
+// transactions
+type NetworkParameters struct {
+	LedgerId           string
+	MaxMemoSize        int64
+	MaxTokenTransfers  int64
+	MaxTransactionSize int64
+	MaxTransfers       int64
+}
+
+// WithProperties returns a copy of the network parameters overridden by the values set in the protobuf-encoded
+// application properties file content. A property with a malformed value is ignored
+func (n NetworkParameters) WithProperties(data []byte) (NetworkParameters, error) {
+	if len(data) == 0 {
+		return n, errors.Errorf("Empty application properties provided")
+	}
+
+	var properties services.ServicesConfigurationList
+	if err := proto.Unmarshal(data, &properties); err != nil {
+		return n, err
+	}
+
+	for _, setting := range properties.GetNameValue() {
+		switch setting.GetName() {
+		case propertyLedgerId:
+			if ledgerId := tools.SafeRemoveHexPrefix(setting.GetValue()); ledgerId != "" {
+				n.LedgerId = tools.SafeAddHexPrefix(ledgerId)
+			}
+		case propertyMaxMemoSize:
+			setIntProperty(&n.MaxMemoSize, setting.GetValue())
+		case propertyMaxTokenTransfers:
+			setIntProperty(&n.MaxTokenTransfers, setting.GetValue())
+		case propertyMaxTransfers:
+			setIntProperty(&n.MaxTransfers, setting.GetValue())
+		}
+	}
+
+	return n, nil
+}
+
+// ToMetadata returns the network parameters as metadata. The ledger id is omitted if it's unknown
+func (n NetworkParameters) ToMetadata() map[string]interface{} {
+	metadata := map[string]interface{}{
+		"max_memo_size":        n.MaxMemoSize,
+		"max_token_transfers":  n.MaxTokenTransfers,
+		"max_transaction_size": n.MaxTransactionSize,
+		"max_transfers":        n.MaxTransfers,
+	}
+	if n.LedgerId != "" {
+		metadata["ledger_id"] = n.LedgerId
+	}
+	return metadata
+}
+
+func setIntProperty(field *int64, value string) {
+	if parsed, err := strconv.ParseInt(value, 10, 64); err == nil {
+		*field = parsed
+	}
+}
@@ -0,0 +1,128 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package types
+
+import (
+	"testing"
+
+	"github.com/hashgraph/hedera-protobufs-go/services"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+var defaultNetworkParameters = NetworkParameters{
+	LedgerId:           "0x01",
+	MaxMemoSize:        100,
+	MaxTokenTransfers:  10,
+	MaxTransactionSize: 6144,
+	MaxTransfers:       10,
+}
+
+func TestNetworkParametersWithProperties(t *testing.T) {
+	// given
+	data, _ := proto.Marshal(&services.ServicesConfigurationList{
+		NameValue: []*services.Setting{
+			{Name: "contracts.chainId", Value: "296"},
+			{Name: propertyLedgerId, Value: "0x02"},
+			{Name: propertyMaxMemoSize, Value: "128"},
+			{Name: propertyMaxTokenTransfers, Value: "not a number"},
+			{Name: propertyMaxTransfers, Value: "20"},
+		},
+	})
+	expected := NetworkParameters{
+		LedgerId:           "0x02",
+		MaxMemoSize:        128,
+		MaxTokenTransfers:  10,
+		MaxTransactionSize: 6144,
+		MaxTransfers:       20,
+	}
+
+	// when
+	actual, err := defaultNetworkParameters.WithProperties(data)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+	assert.Equal(t, int64(100), defaultNetworkParameters.MaxMemoSize)
+}
+
+func TestNetworkParametersWithPropertiesFail(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: []byte{}},
+		{name: "invalid", data: []byte{0x1, 0x2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			actual, err := defaultNetworkParameters.WithProperties(tt.data)
+
+			// then
+			assert.Error(t, err)
+			assert.Equal(t, defaultNetworkParameters, actual)
+		})
+	}
+}
+
+func TestNetworkParametersToMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		ledgerId string
+		expected map[string]interface{}
+	}{
+		{
+			name:     "with ledger id",
+			ledgerId: "0x01",
+			expected: map[string]interface{}{
+				"ledger_id":            "0x01",
+				"max_memo_size":        int64(100),
+				"max_token_transfers":  int64(10),
+				"max_transaction_size": int64(6144),
+				"max_transfers":        int64(10),
+			},
+		},
+		{
+			name: "without ledger id",
+			expected: map[string]interface{}{
+				"max_memo_size":        int64(100),
+				"max_token_transfers":  int64(10),
+				"max_transaction_size": int64(6144),
+				"max_transfers":        int64(10),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			networkParameters := defaultNetworkParameters
+			networkParameters.LedgerId = tt.ledgerId
+
+			// when
+			actual := networkParameters.ToMetadata()
+
+			// then
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package types
+
+import "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+
+// NodeStakes is domain level struct used to represent the stake of each node and the network stake in a staking period
+type NodeStakes struct {
+	EpochDay      int64
+	Network       *domain.NetworkStake
+	Nodes         []domain.NodeStake
+	StakingPeriod int64
+}
+
+// ToMetadata returns the stake, the reward rate, and the min / max stake of each node as metadata, and the network
+// stake if it's recorded for the staking period. The stakes are in tinybars and the reward rates are in tinybars per
+// whole hbar
+func (n NodeStakes) ToMetadata() map[string]interface{} {
+	nodes := make([]map[string]interface{}, 0, len(n.Nodes))
+	for _, node := range n.Nodes {
+		nodes = append(nodes, map[string]interface{}{
+			"max_stake":          node.MaxStake,
+			"min_stake":          node.MinStake,
+			"node_id":            node.NodeId,
+			"reward_rate":        node.RewardRate,
+			"stake":              node.Stake,
+			"stake_not_rewarded": node.StakeNotRewarded,
+			"stake_rewarded":     node.StakeRewarded,
+		})
+	}
+
+	metadata := map[string]interface{}{
+		"epoch_day":      n.EpochDay,
+		"nodes":          nodes,
+		"staking_period": n.StakingPeriod,
+	}
+	if n.Network != nil {
+		metadata["network"] = map[string]interface{}{
+			"max_staking_reward_rate_per_hbar": n.Network.MaxStakingRewardRatePerHbar,
+			"stake_total":                      n.Network.StakeTotal,
+			"staking_period_duration":          n.Network.StakingPeriodDuration,
+			"staking_reward_rate":              n.Network.StakingRewardRate,
+			"staking_start_threshold":          n.Network.StakingStartThreshold,
+		}
+	}
+	return metadata
+}
@@ -0,0 +1,112 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package types
+
+import (
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeStakesToMetadata(t *testing.T) {
+	nodes := []domain.NodeStake{
+		{
+			EpochDay:         19200,
+			MaxStake:         5000,
+			MinStake:         1000,
+			NodeId:           3,
+			RewardRate:       10,
+			Stake:            2000,
+			StakeNotRewarded: 500,
+			StakeRewarded:    1500,
+		},
+	}
+	expectedNodes := []map[string]interface{}{
+		{
+			"max_stake":          int64(5000),
+			"min_stake":          int64(1000),
+			"node_id":            int64(3),
+			"reward_rate":        int64(10),
+			"stake":              int64(2000),
+			"stake_not_rewarded": int64(500),
+			"stake_rewarded":     int64(1500),
+		},
+	}
+	tests := []struct {
+		name       string
+		nodeStakes NodeStakes
+		expected   map[string]interface{}
+	}{
+		{
+			name:       "without network stake",
+			nodeStakes: NodeStakes{EpochDay: 19200, Nodes: nodes, StakingPeriod: 100},
+			expected: map[string]interface{}{
+				"epoch_day":      int64(19200),
+				"nodes":          expectedNodes,
+				"staking_period": int64(100),
+			},
+		},
+		{
+			name: "with network stake",
+			nodeStakes: NodeStakes{
+				EpochDay: 19200,
+				Network: &domain.NetworkStake{
+					EpochDay:                    19200,
+					MaxStakingRewardRatePerHbar: 17808,
+					StakeTotal:                  2000,
+					StakingPeriod:               100,
+					StakingPeriodDuration:       1440,
+					StakingRewardRate:           100000,
+					StakingStartThreshold:       25000000,
+				},
+				Nodes:         nodes,
+				StakingPeriod: 100,
+			},
+			expected: map[string]interface{}{
+				"epoch_day": int64(19200),
+				"network": map[string]interface{}{
+					"max_staking_reward_rate_per_hbar": int64(17808),
+					"stake_total":                      int64(2000),
+					"staking_period_duration":          int64(1440),
+					"staking_reward_rate":              int64(100000),
+					"staking_start_threshold":          int64(25000000),
+				},
+				"nodes":          expectedNodes,
+				"staking_period": int64(100),
+			},
+		},
+		{
+			name:       "no nodes",
+			nodeStakes: NodeStakes{EpochDay: 19200, StakingPeriod: 100},
+			expected: map[string]interface{}{
+				"epoch_day":      int64(19200),
+				"nodes":          []map[string]interface{}{},
+				"staking_period": int64(100),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.nodeStakes.ToMetadata())
+		})
+	}
+}
@@ -48,7 +48,7 @@ func (o Operation) ToRosetta() *types.Operation {
 		Metadata:            o.Metadata,
 		OperationIdentifier: &types.OperationIdentifier{Index: o.Index},
 		Status:              status,
-		Type:                o.Type,
+		Type:                ToOperationTypeName(o.Type),
 	}
 }
 
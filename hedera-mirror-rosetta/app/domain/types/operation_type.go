@@ -0,0 +1,103 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package types
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	OperationTypeNamingHapi    = "HAPI"
+	OperationTypeNamingRosetta = "ROSETTA"
+)
+
+// rosettaOperationTypeNames maps the HAPI operation type names to the Rosetta-conventional names. The mapping must be
+// bijective so incoming operation types can be translated back
+var rosettaOperationTypeNames = map[string]string{
+	OperationTypeCryptoCreateAccount: "CREATE_ACCOUNT",
+	OperationTypeCryptoTransfer:      "TRANSFER",
+	OperationTypeTokenAssociate:      "TOKEN_ASSOCIATE",
+	OperationTypeTokenBurn:           "BURN",
+	OperationTypeTokenCreate:         "TOKEN_CREATE",
+	OperationTypeTokenDelete:         "TOKEN_DELETE",
+	OperationTypeTokenDissociate:     "TOKEN_DISSOCIATE",
+	OperationTypeTokenFreeze:         "TOKEN_FREEZE",
+	OperationTypeTokenGrantKyc:       "TOKEN_GRANT_KYC",
+	OperationTypeTokenMint:           "MINT",
+	OperationTypeTokenRevokeKyc:      "TOKEN_REVOKE_KYC",
+	OperationTypeTokenUnfreeze:       "TOKEN_UNFREEZE",
+	OperationTypeTokenUpdate:         "TOKEN_UPDATE",
+	OperationTypeTokenWipe:           "TOKEN_WIPE",
+}
+
+var (
+	// operationTypeNames maps internal operation types to the names exposed by the API, empty for the HAPI naming
+	operationTypeNames = map[string]string{}
+	// operationTypesByName maps the names exposed by the API back to the internal operation types
+	operationTypesByName = map[string]string{}
+)
+
+// SetOperationTypeNaming sets the naming scheme of the operation types exposed by the API. It's not safe to call it
+// concurrently with the translation functions and should only be called once at startup
+func SetOperationTypeNaming(naming string) error {
+	names := map[string]string{}
+	switch strings.ToUpper(naming) {
+	case "", OperationTypeNamingHapi:
+	case OperationTypeNamingRosetta:
+		names = rosettaOperationTypeNames
+	default:
+		return errors.Errorf("Unsupported operation type naming %s", naming)
+	}
+
+	operationTypeNames = names
+	operationTypesByName = make(map[string]string, len(names))
+	for operationType, name := range names {
+		operationTypesByName[name] = operationType
+	}
+	return nil
+}
+
+// ToOperationTypeName translates the internal operation type to the name exposed by the API
+func ToOperationTypeName(operationType string) string {
+	if name, ok := operationTypeNames[operationType]; ok {
+		return name
+	}
+	return operationType
+}
+
+// ToOperationTypeNames translates the internal operation types to the names exposed by the API
+func ToOperationTypeNames(operationTypes []string) []string {
+	names := make([]string, 0, len(operationTypes))
+	for _, operationType := range operationTypes {
+		names = append(names, ToOperationTypeName(operationType))
+	}
+	return names
+}
+
+// FromOperationTypeName translates the operation type name exposed by the API to the internal operation type
+func FromOperationTypeName(name string) string {
+	if operationType, ok := operationTypesByName[name]; ok {
+		return operationType
+	}
+	return name
+}
@@ -0,0 +1,98 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package types
+
+import (
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetOperationTypeNaming(t *testing.T) {
+	tests := []struct {
+		naming        string
+		operationType string
+		expected      string
+	}{
+		{naming: "", operationType: OperationTypeCryptoTransfer, expected: OperationTypeCryptoTransfer},
+		{naming: "hapi", operationType: OperationTypeTokenMint, expected: OperationTypeTokenMint},
+		{naming: OperationTypeNamingHapi, operationType: OperationTypeFee, expected: OperationTypeFee},
+		{naming: "rosetta", operationType: OperationTypeCryptoTransfer, expected: "TRANSFER"},
+		{naming: OperationTypeNamingRosetta, operationType: OperationTypeTokenBurn, expected: "BURN"},
+		{naming: OperationTypeNamingRosetta, operationType: OperationTypeTokenMint, expected: "MINT"},
+		{naming: OperationTypeNamingRosetta, operationType: OperationTypeFee, expected: OperationTypeFee},
+		{naming: OperationTypeNamingRosetta, operationType: "CONTRACTCALL", expected: "CONTRACTCALL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.naming+"-"+tt.operationType, func(t *testing.T) {
+			t.Cleanup(resetOperationTypeNaming)
+
+			// when
+			err := SetOperationTypeNaming(tt.naming)
+
+			// then
+			assert.NoError(t, err)
+			name := ToOperationTypeName(tt.operationType)
+			assert.Equal(t, tt.expected, name)
+			assert.Equal(t, tt.operationType, FromOperationTypeName(name))
+		})
+	}
+}
+
+func TestSetOperationTypeNamingUnsupported(t *testing.T) {
+	t.Cleanup(resetOperationTypeNaming)
+	assert.Error(t, SetOperationTypeNaming("foobar"))
+	assert.Equal(t, OperationTypeCryptoTransfer, ToOperationTypeName(OperationTypeCryptoTransfer))
+}
+
+func TestRosettaOperationTypeNamesBijective(t *testing.T) {
+	names := make(map[string]bool)
+	for _, name := range rosettaOperationTypeNames {
+		assert.False(t, names[name], "duplicate name %s", name)
+		names[name] = true
+	}
+	for _, operationType := range SupportedOperationTypes {
+		assert.Contains(t, rosettaOperationTypeNames, operationType)
+	}
+}
+
+func TestToOperationTypeNames(t *testing.T) {
+	t.Cleanup(resetOperationTypeNaming)
+	assert.NoError(t, SetOperationTypeNaming(OperationTypeNamingRosetta))
+	assert.Equal(
+		t,
+		[]string{"TRANSFER", OperationTypeFee},
+		ToOperationTypeNames([]string{OperationTypeCryptoTransfer, OperationTypeFee}),
+	)
+}
+
+func TestOperationToRosettaWithRosettaNaming(t *testing.T) {
+	t.Cleanup(resetOperationTypeNaming)
+	assert.NoError(t, SetOperationTypeNaming(OperationTypeNamingRosetta))
+	operation := Operation{AccountId: AccountId{accountId: domain.MustDecodeEntityId(1)}, Type: OperationTypeTokenBurn}
+	assert.Equal(t, "BURN", operation.ToRosetta().Type)
+}
+
+func resetOperationTypeNaming() {
+	_ = SetOperationTypeNaming(OperationTypeNamingHapi)
+}
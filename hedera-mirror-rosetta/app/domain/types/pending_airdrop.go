@@ -0,0 +1,66 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package types
+
+import "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+
+const (
+	AirdropStateCancelled = "CANCELLED"
+	AirdropStateClaimed   = "CLAIMED"
+	AirdropStatePending   = "PENDING"
+)
+
+// PendingAirdrop is domain level struct used to represent a HIP-904 pending token airdrop, i.e., a token transfer held
+// until the receiver claims it or the sender cancels it. Timestamp is the consensus timestamp of the transaction which
+// changed the airdrop to its state. Amount is the total pending amount of a fungible token, and SerialNumber is the
+// serial of a non-fungible token
+type PendingAirdrop struct {
+	Amount            int64
+	ReceiverAccountId domain.EntityId
+	SenderAccountId   domain.EntityId
+	SerialNumber      int64
+	State             string
+	Timestamp         int64
+	Token             domain.Token
+}
+
+// IsNft returns true if the airdrop is of a serial of a non-fungible token
+func (p PendingAirdrop) IsNft() bool {
+	return p.Token.Type == domain.TokenTypeNonFungibleUnique
+}
+
+// ToMetadata returns the pending airdrop as metadata, with the amount of a fungible token or the serial number of a
+// non-fungible token
+func (p PendingAirdrop) ToMetadata() map[string]interface{} {
+	metadata := map[string]interface{}{
+		"currency":            Token{Token: p.Token}.ToRosettaCurrency(),
+		"receiver_account_id": p.ReceiverAccountId.String(),
+		"sender_account_id":   p.SenderAccountId.String(),
+		"state":               p.State,
+		"timestamp":           p.Timestamp,
+	}
+	if p.IsNft() {
+		metadata["serial_number"] = p.SerialNumber
+	} else {
+		metadata["amount"] = p.Amount
+	}
+	return metadata
+}
@@ -0,0 +1,92 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package types
+
+import (
+	"testing"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPendingAirdropToMetadata(t *testing.T) {
+	receiver := domain.MustDecodeEntityId(2001)
+	sender := domain.MustDecodeEntityId(2002)
+	tokenId := domain.MustDecodeEntityId(3001)
+	tests := []struct {
+		name     string
+		airdrop  PendingAirdrop
+		expected map[string]interface{}
+	}{
+		{
+			name: "fungible",
+			airdrop: PendingAirdrop{
+				Amount:            100,
+				ReceiverAccountId: receiver,
+				SenderAccountId:   sender,
+				State:             AirdropStatePending,
+				Timestamp:         10,
+				Token:             domain.Token{Decimals: 2, TokenId: tokenId, Type: domain.TokenTypeFungibleCommon},
+			},
+			expected: map[string]interface{}{
+				"amount": int64(100),
+				"currency": &types.Currency{
+					Symbol:   "0.0.3001",
+					Decimals: 2,
+					Metadata: map[string]interface{}{MetadataKeyType: domain.TokenTypeFungibleCommon},
+				},
+				"receiver_account_id": "0.0.2001",
+				"sender_account_id":   "0.0.2002",
+				"state":               AirdropStatePending,
+				"timestamp":           int64(10),
+			},
+		},
+		{
+			name: "non-fungible",
+			airdrop: PendingAirdrop{
+				ReceiverAccountId: receiver,
+				SenderAccountId:   sender,
+				SerialNumber:      5,
+				State:             AirdropStateClaimed,
+				Timestamp:         20,
+				Token:             domain.Token{TokenId: tokenId, Type: domain.TokenTypeNonFungibleUnique},
+			},
+			expected: map[string]interface{}{
+				"currency": &types.Currency{
+					Symbol:   "0.0.3001",
+					Metadata: map[string]interface{}{MetadataKeyType: domain.TokenTypeNonFungibleUnique},
+				},
+				"receiver_account_id": "0.0.2001",
+				"sender_account_id":   "0.0.2002",
+				"serial_number":       int64(5),
+				"state":               AirdropStateClaimed,
+				"timestamp":           int64(20),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.airdrop.ToMetadata())
+		})
+	}
+}
@@ -0,0 +1,154 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package types
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-protobufs-go/services"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// RawTransaction is domain level struct used to represent a transaction as stored by the mirror node, including the
+// raw protobuf transaction bytes if stored
+type RawTransaction struct {
+	domain.Transaction
+}
+
+// ToMetadata returns the consensus timestamp, the transaction record, and the decoded transaction body if the
+// transaction bytes are stored. When the importer persists the record bytes, the stored transaction record with the
+// exact fee breakdown in its transfer list is returned. Otherwise, the transaction record is rebuilt from the stored
+// columns and only has the receipt status, the transaction hash, the consensus timestamp, the transaction id, the
+// memo, the transaction fee, and the parent consensus timestamp
+func (t RawTransaction) ToMetadata() (map[string]interface{}, error) {
+	transactionRecord, err := t.getTransactionRecord()
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := protoToMap(transactionRecord)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := map[string]interface{}{
+		"consensus_timestamp": t.ConsensusTimestamp,
+		"transaction_record":  record,
+	}
+
+	if len(t.TransactionBytes) != 0 {
+		body, err := t.getTransactionBody()
+		if err != nil {
+			return nil, err
+		}
+
+		if metadata["transaction_body"], err = protoToMap(body); err != nil {
+			return nil, err
+		}
+	}
+
+	return metadata, nil
+}
+
+func (t RawTransaction) getTransactionBody() (*services.TransactionBody, error) {
+	var transaction services.Transaction
+	if err := proto.Unmarshal(t.TransactionBytes, &transaction); err != nil {
+		return nil, errors.Wrap(err, "Failed to unmarshal transaction")
+	}
+
+	// old transactions may have the deprecated body bytes instead of the signed transaction bytes
+	bodyBytes := transaction.GetBodyBytes()
+	if len(transaction.GetSignedTransactionBytes()) != 0 {
+		var signedTransaction services.SignedTransaction
+		if err := proto.Unmarshal(transaction.GetSignedTransactionBytes(), &signedTransaction); err != nil {
+			return nil, errors.Wrap(err, "Failed to unmarshal signed transaction")
+		}
+		bodyBytes = signedTransaction.GetBodyBytes()
+	}
+
+	var body services.TransactionBody
+	if err := proto.Unmarshal(bodyBytes, &body); err != nil {
+		return nil, errors.Wrap(err, "Failed to unmarshal transaction body")
+	}
+
+	return &body, nil
+}
+
+func (t RawTransaction) getTransactionRecord() (*services.TransactionRecord, error) {
+	if len(t.TransactionRecordBytes) != 0 {
+		var record services.TransactionRecord
+		if err := proto.Unmarshal(t.TransactionRecordBytes, &record); err != nil {
+			return nil, errors.Wrap(err, "Failed to unmarshal transaction record")
+		}
+		return &record, nil
+	}
+
+	payer := t.PayerAccountId
+	record := &services.TransactionRecord{
+		ConsensusTimestamp: toProtoTimestamp(t.ConsensusTimestamp),
+		Memo:               string(t.Memo),
+		Receipt:            &services.TransactionReceipt{Status: services.ResponseCodeEnum(t.Result)},
+		TransactionFee:     uint64(t.ChargedTxFee),
+		TransactionHash:    t.TransactionHash,
+		TransactionID: &services.TransactionID{
+			AccountID: &services.AccountID{
+				ShardNum: payer.ShardNum,
+				RealmNum: payer.RealmNum,
+				Account:  &services.AccountID_AccountNum{AccountNum: payer.EntityNum},
+			},
+			Nonce:                 t.Nonce,
+			Scheduled:             t.Scheduled,
+			TransactionValidStart: toProtoTimestamp(t.ValidStartNs),
+		},
+	}
+
+	if t.ParentConsensusTimestamp != 0 {
+		record.ParentConsensusTimestamp = toProtoTimestamp(t.ParentConsensusTimestamp)
+	}
+
+	return record, nil
+}
+
+// protoToMap converts the protobuf message to a map with the canonical protobuf json mapping
+func protoToMap(message proto.Message) (map[string]interface{}, error) {
+	data, err := protojson.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]interface{})
+	if err = json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func toProtoTimestamp(nanos int64) *services.Timestamp {
+	return &services.Timestamp{
+		Seconds: nanos / int64(time.Second),
+		Nanos:   int32(nanos % int64(time.Second)),
+	}
+}
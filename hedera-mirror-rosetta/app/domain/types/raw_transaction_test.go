@@ -0,0 +1,148 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package types
+
+import (
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-protobufs-go/services"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRawTransactionToMetadata(t *testing.T) {
+	// given
+	body := &services.TransactionBody{Memo: "foobar", TransactionFee: 100}
+	bodyBytes, _ := proto.Marshal(body)
+	signedTransactionBytes, _ := proto.Marshal(&services.SignedTransaction{BodyBytes: bodyBytes})
+	transactionBytes, _ := proto.Marshal(&services.Transaction{SignedTransactionBytes: signedTransactionBytes})
+	deprecatedTransactionBytes, _ := proto.Marshal(&services.Transaction{BodyBytes: bodyBytes})
+	expectedBody := map[string]interface{}{"memo": "foobar", "transactionFee": "100"}
+
+	tests := []struct {
+		name             string
+		transactionBytes []byte
+		expectedBody     map[string]interface{}
+	}{
+		{name: "signed transaction bytes", transactionBytes: transactionBytes, expectedBody: expectedBody},
+		{name: "deprecated body bytes", transactionBytes: deprecatedTransactionBytes, expectedBody: expectedBody},
+		{name: "transaction bytes not stored"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rawTransaction := RawTransaction{domain.Transaction{
+				ConsensusTimestamp:       1000000123,
+				ChargedTxFee:             17,
+				Memo:                     []byte("foobar"),
+				Nonce:                    1,
+				ParentConsensusTimestamp: 1000000122,
+				PayerAccountId:           domain.MustDecodeEntityId(1001),
+				Result:                   22,
+				TransactionBytes:         tt.transactionBytes,
+				TransactionHash:          []byte{1, 2, 3},
+				ValidStartNs:             1000000000,
+			}}
+			expected := map[string]interface{}{
+				"consensus_timestamp": int64(1000000123),
+				"transaction_record": map[string]interface{}{
+					"consensusTimestamp":       map[string]interface{}{"seconds": "1", "nanos": float64(123)},
+					"memo":                     "foobar",
+					"parentConsensusTimestamp": map[string]interface{}{"seconds": "1", "nanos": float64(122)},
+					"receipt":                  map[string]interface{}{"status": "SUCCESS"},
+					"transactionFee":           "17",
+					"transactionHash":          "AQID",
+					"transactionID": map[string]interface{}{
+						"accountID":             map[string]interface{}{"accountNum": "1001"},
+						"nonce":                 float64(1),
+						"transactionValidStart": map[string]interface{}{"seconds": "1"},
+					},
+				},
+			}
+			if tt.expectedBody != nil {
+				expected["transaction_body"] = tt.expectedBody
+			}
+
+			// when
+			actual, err := rawTransaction.ToMetadata()
+
+			// then
+			assert.NoError(t, err)
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
+
+func TestRawTransactionToMetadataDecodeFailure(t *testing.T) {
+	rawTransaction := RawTransaction{domain.Transaction{TransactionBytes: []byte{0xff, 0xff}}}
+	actual, err := rawTransaction.ToMetadata()
+	assert.Error(t, err)
+	assert.Nil(t, actual)
+}
+
+func TestRawTransactionToMetadataWithRecordBytes(t *testing.T) {
+	// given
+	record := &services.TransactionRecord{
+		Memo:           "foobar",
+		TransactionFee: 17,
+		TransferList: &services.TransferList{
+			AccountAmounts: []*services.AccountAmount{
+				{AccountID: &services.AccountID{Account: &services.AccountID_AccountNum{AccountNum: 98}}, Amount: 17},
+			},
+		},
+	}
+	recordBytes, _ := proto.Marshal(record)
+	rawTransaction := RawTransaction{domain.Transaction{
+		ConsensusTimestamp:     1000000123,
+		ChargedTxFee:           20,
+		TransactionRecordBytes: recordBytes,
+	}}
+	expected := map[string]interface{}{
+		"consensus_timestamp": int64(1000000123),
+		"transaction_record": map[string]interface{}{
+			"memo":           "foobar",
+			"transactionFee": "17",
+			"transferList": map[string]interface{}{
+				"accountAmounts": []interface{}{
+					map[string]interface{}{
+						"accountID": map[string]interface{}{"accountNum": "98"},
+						"amount":    "17",
+					},
+				},
+			},
+		},
+	}
+
+	// when
+	actual, err := rawTransaction.ToMetadata()
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestRawTransactionToMetadataRecordDecodeFailure(t *testing.T) {
+	rawTransaction := RawTransaction{domain.Transaction{TransactionRecordBytes: []byte{0xff, 0xff}}}
+	actual, err := rawTransaction.ToMetadata()
+	assert.Error(t, err)
+	assert.Nil(t, actual)
+}
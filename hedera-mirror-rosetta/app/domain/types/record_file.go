@@ -0,0 +1,82 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package types
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/recordfile"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/tools"
+)
+
+// RecordFile is domain level struct used to represent the record file of a block as stored by the mirror node,
+// including the raw bytes if available
+type RecordFile struct {
+	domain.RecordFile
+}
+
+// Verify recomputes the block hash and the file hash from the raw bytes, and returns the stored and the recomputed
+// hashes and whether they match. The file hash is only compared if stored, and the end running hash of a v5 or v6 record
+// file is also verified against the one in the file. The record file is verified only if all the checks pass, and the
+// reason is returned if the raw bytes can't be parsed
+func (r RecordFile) Verify() map[string]interface{} {
+	metadata := map[string]interface{}{
+		"name":        r.Name,
+		"stored_hash": tools.SafeAddHexPrefix(r.Hash),
+		"verified":    false,
+	}
+
+	parsed, err := recordfile.Parse(r.Bytes)
+	if err != nil {
+		metadata["reason"] = err.Error()
+		return metadata
+	}
+
+	hash := hex.EncodeToString(parsed.Hash)
+	verified := hashEquals(r.Hash, hash)
+	metadata["hash"] = tools.SafeAddHexPrefix(hash)
+	metadata["hash_matches"] = verified
+	metadata["version"] = parsed.Version
+
+	if r.FileHash != "" {
+		fileHash := hex.EncodeToString(parsed.FileHash)
+		fileHashMatches := hashEquals(r.FileHash, fileHash)
+		metadata["file_hash"] = tools.SafeAddHexPrefix(fileHash)
+		metadata["file_hash_matches"] = fileHashMatches
+		metadata["stored_file_hash"] = tools.SafeAddHexPrefix(r.FileHash)
+		verified = verified && fileHashMatches
+	}
+
+	if parsed.Version >= 5 {
+		runningHashMatches := parsed.Verify() == nil
+		metadata["end_running_hash"] = tools.SafeAddHexPrefix(hex.EncodeToString(parsed.EndRunningHash))
+		metadata["end_running_hash_matches"] = runningHashMatches
+		verified = verified && runningHashMatches
+	}
+
+	metadata["verified"] = verified
+	return metadata
+}
+
+func hashEquals(stored, recomputed string) bool {
+	return strings.EqualFold(tools.SafeRemoveHexPrefix(stored), recomputed)
+}
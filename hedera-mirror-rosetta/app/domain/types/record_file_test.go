@@ -0,0 +1,149 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package types
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	recordFileV2Hash     = "5ed51baeff204eb6a2a68b76bbaadcb9b6e7074676c1746b99681d075bef009e8d57699baaa6342feec4e83726582d36"
+	recordFileV2Name     = "2019-08-30T18_10_05.249678Z.rcd"
+	recordFileV5Hash     = "514e361089074cb06f984e5a943a20fba2a0d601b766f8adb432d03214c48c3ff14898e6b78292520340f484e820ea84"
+	recordFileV5Name     = "2021-01-11T22_09_34.097416003Z.rcd"
+	recordFileV6FileHash = "69a4354de5aeb12fbc989ae086fc291cfc1b61415391b4a46915b32aef722a511c4ceba60ecda72d527cc4866a4d235b"
+	recordFileV6Hash     = "a6c241fad2c636f68a6aa0da9293245a5ef0ebef345cd139858068ff7998716cefe0fd3afa0d21304725507061975279"
+	recordFileV6Name     = "2022-07-13T08_46_08.041986003Z.rcd.gz"
+)
+
+func readRecordFile(t *testing.T, name string) []byte {
+	data, err := os.ReadFile(filepath.Join("..", "recordfile", "testdata", name))
+	require.NoError(t, err)
+	return data
+}
+
+func TestRecordFileVerify(t *testing.T) {
+	// given
+	recordFile := RecordFile{domain.RecordFile{
+		Bytes:    readRecordFile(t, recordFileV6Name),
+		FileHash: recordFileV6FileHash,
+		Hash:     strings.ToUpper(recordFileV6Hash),
+		Name:     recordFileV6Name,
+	}}
+
+	// when
+	actual := recordFile.Verify()
+
+	// then
+	assert.Equal(t, map[string]interface{}{
+		"end_running_hash":         "0x" + recordFileV6Hash,
+		"end_running_hash_matches": true,
+		"file_hash":                "0x" + recordFileV6FileHash,
+		"file_hash_matches":        true,
+		"hash":                     "0x" + recordFileV6Hash,
+		"hash_matches":             true,
+		"name":                     recordFileV6Name,
+		"stored_file_hash":         "0x" + recordFileV6FileHash,
+		"stored_hash":              "0x" + strings.ToUpper(recordFileV6Hash),
+		"verified":                 true,
+		"version":                  int32(6),
+	}, actual)
+}
+
+func TestRecordFileVerifyPreV5(t *testing.T) {
+	// given
+	recordFile := RecordFile{domain.RecordFile{
+		Bytes: readRecordFile(t, recordFileV2Name),
+		Hash:  recordFileV2Hash,
+		Name:  recordFileV2Name,
+	}}
+
+	// when
+	actual := recordFile.Verify()
+
+	// then
+	assert.Equal(t, map[string]interface{}{
+		"hash":         "0x" + recordFileV2Hash,
+		"hash_matches": true,
+		"name":         recordFileV2Name,
+		"stored_hash":  "0x" + recordFileV2Hash,
+		"verified":     true,
+		"version":      int32(2),
+	}, actual)
+}
+
+func TestRecordFileVerifyHashMismatch(t *testing.T) {
+	// given
+	recordFile := RecordFile{domain.RecordFile{
+		Bytes:    readRecordFile(t, recordFileV6Name),
+		FileHash: recordFileV5Hash,
+		Hash:     recordFileV5Hash,
+		Name:     recordFileV6Name,
+	}}
+
+	// when
+	actual := recordFile.Verify()
+
+	// then
+	assert.Equal(t, false, actual["hash_matches"])
+	assert.Equal(t, false, actual["file_hash_matches"])
+	assert.Equal(t, true, actual["end_running_hash_matches"])
+	assert.Equal(t, false, actual["verified"])
+}
+
+func TestRecordFileVerifyCorrupted(t *testing.T) {
+	// given
+	data := readRecordFile(t, recordFileV5Name)
+	// flip a byte of the last transaction so the recomputed end running hash no longer matches the one in the file
+	data[len(data)-69] ^= 0xff
+	recordFile := RecordFile{domain.RecordFile{Bytes: data, Hash: recordFileV5Hash, Name: recordFileV5Name}}
+
+	// when
+	actual := recordFile.Verify()
+
+	// then
+	assert.Equal(t, false, actual["hash_matches"])
+	assert.Equal(t, false, actual["end_running_hash_matches"])
+	assert.Equal(t, "0x"+recordFileV5Hash, actual["end_running_hash"])
+	assert.Equal(t, false, actual["verified"])
+}
+
+func TestRecordFileVerifyInvalid(t *testing.T) {
+	// given
+	recordFile := RecordFile{domain.RecordFile{Bytes: []byte{0, 0, 0, 7}, Hash: recordFileV5Hash, Name: "invalid"}}
+
+	// when
+	actual := recordFile.Verify()
+
+	// then
+	assert.Equal(t, map[string]interface{}{
+		"name":        "invalid",
+		"reason":      "Unsupported record file version 7",
+		"stored_hash": "0x" + recordFileV5Hash,
+		"verified":    false,
+	}, actual)
+}
@@ -0,0 +1,56 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package types
+
+import "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+
+// Schedule is domain level struct used to represent a schedule entity and its long-term scheduled transaction
+// properties
+type Schedule struct {
+	ExecutedTimestamp *int64
+	ExpirationTime    *int64
+	ScheduleId        domain.EntityId
+	WaitForExpiry     bool
+}
+
+// ToMetadata returns the schedule id, the expiration time if set, and whether the scheduled transaction waits for
+// the expiration time to execute as metadata. The executed timestamp is not included since it may change after the
+// schedule is created
+func (s Schedule) ToMetadata() map[string]interface{} {
+	metadata := map[string]interface{}{
+		"schedule_id":     s.ScheduleId.String(),
+		"wait_for_expiry": s.WaitForExpiry,
+	}
+	if s.ExpirationTime != nil {
+		metadata["expiration_time"] = *s.ExpirationTime
+	}
+	return metadata
+}
+
+// NewScheduleFromDomain creates a Schedule from the persistence domain schedule
+func NewScheduleFromDomain(schedule domain.Schedule) *Schedule {
+	return &Schedule{
+		ExecutedTimestamp: schedule.ExecutedTimestamp,
+		ExpirationTime:    schedule.ExpirationTime,
+		ScheduleId:        schedule.ScheduleId,
+		WaitForExpiry:     schedule.WaitForExpiry,
+	}
+}
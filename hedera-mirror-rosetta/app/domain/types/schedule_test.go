@@ -0,0 +1,87 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package types
+
+import (
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleToMetadata(t *testing.T) {
+	expirationTime := int64(1700000000000000000)
+	tests := []struct {
+		name     string
+		schedule Schedule
+		expected map[string]interface{}
+	}{
+		{
+			name:     "short term",
+			schedule: Schedule{ScheduleId: domain.MustDecodeEntityId(5001)},
+			expected: map[string]interface{}{"schedule_id": "0.0.5001", "wait_for_expiry": false},
+		},
+		{
+			name: "long term",
+			schedule: Schedule{
+				ExecutedTimestamp: &expirationTime,
+				ExpirationTime:    &expirationTime,
+				ScheduleId:        domain.MustDecodeEntityId(5001),
+				WaitForExpiry:     true,
+			},
+			expected: map[string]interface{}{
+				"expiration_time": expirationTime,
+				"schedule_id":     "0.0.5001",
+				"wait_for_expiry": true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.schedule.ToMetadata())
+		})
+	}
+}
+
+func TestNewScheduleFromDomain(t *testing.T) {
+	executedTimestamp := int64(100)
+	expirationTime := int64(200)
+	expected := &Schedule{
+		ExecutedTimestamp: &executedTimestamp,
+		ExpirationTime:    &expirationTime,
+		ScheduleId:        domain.MustDecodeEntityId(5001),
+		WaitForExpiry:     true,
+	}
+
+	actual := NewScheduleFromDomain(domain.Schedule{
+		ConsensusTimestamp: 50,
+		CreatorAccountId:   domain.MustDecodeEntityId(1001),
+		ExecutedTimestamp:  &executedTimestamp,
+		ExpirationTime:     &expirationTime,
+		PayerAccountId:     domain.MustDecodeEntityId(1001),
+		ScheduleId:         domain.MustDecodeEntityId(5001),
+		TransactionBody:    []byte{0x1},
+		WaitForExpiry:      true,
+	})
+
+	assert.Equal(t, expected, actual)
+}
@@ -0,0 +1,88 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SchemaVersion is the dotted numeric version of a flyway migration, e.g., 1.54.3
+type SchemaVersion []int
+
+// Compare returns -1, 0, or 1 if the version is older than, the same as, or newer than the other version. The missing
+// trailing parts are treated as 0, so 1.54 is the same as 1.54.0
+func (v SchemaVersion) Compare(other SchemaVersion) int {
+	for i := 0; i < len(v) || i < len(other); i++ {
+		var a, b int
+		if i < len(v) {
+			a = v[i]
+		}
+		if i < len(other) {
+			b = other[i]
+		}
+
+		if a < b {
+			return -1
+		} else if a > b {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+func (v SchemaVersion) String() string {
+	parts := make([]string, 0, len(v))
+	for _, part := range v {
+		parts = append(parts, strconv.Itoa(part))
+	}
+	return strings.Join(parts, ".")
+}
+
+// ParseSchemaVersion parses the dotted numeric version string
+func ParseSchemaVersion(version string) (SchemaVersion, error) {
+	if version == "" {
+		return nil, fmt.Errorf("empty schema version")
+	}
+
+	parts := strings.Split(version, ".")
+	schemaVersion := make(SchemaVersion, 0, len(parts))
+	for _, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return nil, fmt.Errorf("invalid schema version %s", version)
+		}
+		schemaVersion = append(schemaVersion, number)
+	}
+
+	return schemaVersion, nil
+}
+
+// MustParseSchemaVersion parses the dotted numeric version string and panics if it's invalid
+func MustParseSchemaVersion(version string) SchemaVersion {
+	schemaVersion, err := ParseSchemaVersion(version)
+	if err != nil {
+		panic(err)
+	}
+	return schemaVersion
+}
@@ -0,0 +1,85 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSchemaVersion(t *testing.T) {
+	var tests = []struct {
+		version  string
+		expected SchemaVersion
+	}{
+		{version: "1", expected: SchemaVersion{1}},
+		{version: "1.54.3", expected: SchemaVersion{1, 54, 3}},
+		{version: "1.64.1.2", expected: SchemaVersion{1, 64, 1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			actual, err := ParseSchemaVersion(tt.version)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+			assert.Equal(t, tt.version, actual.String())
+		})
+	}
+}
+
+func TestParseSchemaVersionInvalid(t *testing.T) {
+	for _, version := range []string{"", "1..2", "1.a", "1.-2", "v1.2", "1.2."} {
+		t.Run(version, func(t *testing.T) {
+			actual, err := ParseSchemaVersion(version)
+			assert.Error(t, err)
+			assert.Nil(t, actual)
+		})
+	}
+}
+
+func TestMustParseSchemaVersion(t *testing.T) {
+	assert.Equal(t, SchemaVersion{1, 54, 3}, MustParseSchemaVersion("1.54.3"))
+	assert.Panics(t, func() { MustParseSchemaVersion("invalid") })
+}
+
+func TestSchemaVersionCompare(t *testing.T) {
+	var tests = []struct {
+		name     string
+		version  string
+		other    string
+		expected int
+	}{
+		{name: "equal", version: "1.54.3", other: "1.54.3"},
+		{name: "equal with trailing zero", version: "1.54", other: "1.54.0"},
+		{name: "older", version: "1.54.3", other: "1.54.10", expected: -1},
+		{name: "older with fewer parts", version: "1.64", other: "1.64.1.2", expected: -1},
+		{name: "newer", version: "1.65.0", other: "1.54.3", expected: 1},
+		{name: "newer with more parts", version: "1.64.1.2", other: "1.64.1", expected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := MustParseSchemaVersion(tt.version).Compare(MustParseSchemaVersion(tt.other))
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package types
+
+import (
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// TransactionSearch is domain level struct used to represent the filter of a transaction search, a transaction matches
+// when it matches all the filters set
+type TransactionSearch struct {
+	// AccountId is the encoded id of the account the transaction transfers to or from, 0 to match any account
+	AccountId int64
+	// After is the consensus timestamp the matching transactions are after
+	After int64
+	// End is the consensus timestamp the matching transactions are at or before
+	End int64
+	// Hash is the hash of the transaction, nil to match any hash
+	Hash  []byte
+	Limit int
+}
+
+// TransactionKey is domain level struct used to represent a transaction by its consensus timestamp and hash
+type TransactionKey struct {
+	ConsensusTimestamp int64
+	Hash               string
+}
+
+// SearchTransactionsRequest is the rosetta /search/transactions request with the opaque cursor returned with the
+// previous page in place of the offset
+type SearchTransactionsRequest struct {
+	types.SearchTransactionsRequest
+	Cursor *string `json:"cursor,omitempty"`
+}
+
+// SearchTransactionsResponse is the rosetta /search/transactions response with the opaque cursor to get the next page
+// in place of the next offset
+type SearchTransactionsResponse struct {
+	types.SearchTransactionsResponse
+	NextCursor *string `json:"next_cursor,omitempty"`
+}
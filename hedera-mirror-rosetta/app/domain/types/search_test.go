@@ -0,0 +1,64 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchTransactionsRequestUnmarshal(t *testing.T) {
+	// given
+	data := `{"network_identifier":{"blockchain":"Hedera","network":"testnet"},"limit":10,"cursor":"abc"}`
+
+	// when
+	var actual SearchTransactionsRequest
+	err := json.Unmarshal([]byte(data), &actual)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "testnet", actual.NetworkIdentifier.Network)
+	assert.Equal(t, int64(10), *actual.Limit)
+	assert.Equal(t, "abc", *actual.Cursor)
+}
+
+func TestSearchTransactionsResponseMarshal(t *testing.T) {
+	// given
+	nextCursor := "abc"
+	response := SearchTransactionsResponse{
+		SearchTransactionsResponse: types.SearchTransactionsResponse{
+			Transactions: []*types.BlockTransaction{},
+			TotalCount:   1,
+		},
+		NextCursor: &nextCursor,
+	}
+
+	// when
+	actual, err := json.Marshal(response)
+
+	// then
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"transactions":[],"total_count":1,"next_cursor":"abc"}`, string(actual))
+}
@@ -0,0 +1,41 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package types
+
+import "context"
+
+type serverSigningKey struct{}
+
+// WithServerSigning returns a copy of the context which allows the server-side signer to co-sign the transaction of
+// the request
+func WithServerSigning(ctx context.Context) context.Context {
+	return context.WithValue(ctx, serverSigningKey{}, true)
+}
+
+// IsServerSigningAllowed returns true if the server-side signer is allowed to co-sign the transaction of the request
+func IsServerSigningAllowed(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+
+	allowed, _ := ctx.Value(serverSigningKey{}).(bool)
+	return allowed
+}
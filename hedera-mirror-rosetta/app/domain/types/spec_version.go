@@ -0,0 +1,56 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package types
+
+import (
+	"context"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+)
+
+type specVersionKey struct{}
+
+// supportedSpecVersions are the rosetta spec versions the requests and responses of which are implemented. A spec
+// version is only added together with its version-specific request and response handling, so an integrator selecting
+// it is never served the responses of another version
+var supportedSpecVersions = map[string]bool{rTypes.RosettaAPIVersion: true}
+
+// IsSpecVersionSupported returns true if the requests and responses of the rosetta spec version are implemented
+func IsSpecVersionSupported(version string) bool {
+	return supportedSpecVersions[version]
+}
+
+// WithSpecVersion returns a copy of the context with the rosetta spec version negotiated for the request
+func WithSpecVersion(ctx context.Context, version string) context.Context {
+	return context.WithValue(ctx, specVersionKey{}, version)
+}
+
+// GetSpecVersion returns the rosetta spec version negotiated for the request, or the version of the pinned
+// rosetta-sdk-go types if none is set in the context
+func GetSpecVersion(ctx context.Context) string {
+	if ctx != nil {
+		if version, ok := ctx.Value(specVersionKey{}).(string); ok && version != "" {
+			return version
+		}
+	}
+
+	return rTypes.RosettaAPIVersion
+}
@@ -0,0 +1,45 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package types
+
+import (
+	"context"
+	"testing"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSpecVersion(t *testing.T) {
+	ctx := WithSpecVersion(context.Background(), "1.5.0")
+	assert.Equal(t, "1.5.0", GetSpecVersion(ctx))
+}
+
+func TestGetSpecVersionDefault(t *testing.T) {
+	assert.Equal(t, rTypes.RosettaAPIVersion, GetSpecVersion(context.Background()))
+	assert.Equal(t, rTypes.RosettaAPIVersion, GetSpecVersion(WithSpecVersion(context.Background(), "")))
+}
+
+func TestIsSpecVersionSupported(t *testing.T) {
+	assert.True(t, IsSpecVersionSupported(rTypes.RosettaAPIVersion))
+	assert.False(t, IsSpecVersionSupported("1.5.0"))
+	assert.False(t, IsSpecVersionSupported(""))
+}
@@ -0,0 +1,92 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package types
+
+import "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+
+// StakingPeriod is domain level struct used to represent the reward rate of a node in a staking period
+type StakingPeriod struct {
+	EpochDay      int64
+	NodeId        int64
+	RewardRate    int64
+	StakeRewarded int64
+	StakingPeriod int64
+}
+
+// StakingReward is domain level struct used to represent a staking reward paid to an account
+type StakingReward struct {
+	Amount             int64
+	ConsensusTimestamp int64
+}
+
+// StakingHistory is domain level struct used to represent the staking settings of an account, its pending reward, the
+// reward rate of the staked node in the recent staking periods, and the recent staking rewards paid to the account
+type StakingHistory struct {
+	AccountId        domain.EntityId
+	DeclineReward    bool
+	PendingReward    int64
+	Periods          []StakingPeriod
+	Rewards          []StakingReward
+	StakedNodeId     *int64
+	StakePeriodStart *int64
+}
+
+// IsStakedToNode returns true if the account stakes to a node
+func (s StakingHistory) IsStakedToNode() bool {
+	return s.StakedNodeId != nil && *s.StakedNodeId >= 0
+}
+
+// ToMetadata returns the staking history as metadata. The staked node id and the stake period start are only set
+// when the account stakes to a node
+func (s StakingHistory) ToMetadata() map[string]interface{} {
+	periods := make([]map[string]interface{}, 0, len(s.Periods))
+	for _, period := range s.Periods {
+		periods = append(periods, map[string]interface{}{
+			"epoch_day":      period.EpochDay,
+			"node_id":        period.NodeId,
+			"reward_rate":    period.RewardRate,
+			"stake_rewarded": period.StakeRewarded,
+			"staking_period": period.StakingPeriod,
+		})
+	}
+
+	rewards := make([]map[string]interface{}, 0, len(s.Rewards))
+	for _, reward := range s.Rewards {
+		rewards = append(rewards, map[string]interface{}{
+			"amount":              (&HbarAmount{Value: reward.Amount}).ToRosetta(),
+			"consensus_timestamp": reward.ConsensusTimestamp,
+		})
+	}
+
+	metadata := map[string]interface{}{
+		"account_identifier": NewAccountIdFromEntityId(s.AccountId).ToRosetta(),
+		"decline_reward":     s.DeclineReward,
+		"pending_reward":     (&HbarAmount{Value: s.PendingReward}).ToRosetta(),
+		"periods":            periods,
+		"rewards":            rewards,
+	}
+	if s.IsStakedToNode() {
+		metadata["staked_node_id"] = *s.StakedNodeId
+		if s.StakePeriodStart != nil {
+			metadata["stake_period_start"] = *s.StakePeriodStart
+		}
+	}
+	return metadata
+}
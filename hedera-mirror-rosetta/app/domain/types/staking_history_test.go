@@ -0,0 +1,121 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package types
+
+import (
+	"testing"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStakingHistoryIsStakedToNode(t *testing.T) {
+	nodeId := int64(3)
+	noNode := int64(-1)
+	zero := int64(0)
+	tests := []struct {
+		name         string
+		stakedNodeId *int64
+		expected     bool
+	}{
+		{name: "nil", expected: false},
+		{name: "no node", stakedNodeId: &noNode, expected: false},
+		{name: "node 0", stakedNodeId: &zero, expected: true},
+		{name: "node 3", stakedNodeId: &nodeId, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, StakingHistory{StakedNodeId: tt.stakedNodeId}.IsStakedToNode())
+		})
+	}
+}
+
+func TestStakingHistoryToMetadata(t *testing.T) {
+	nodeId := int64(3)
+	noNode := int64(-1)
+	stakePeriodStart := int64(19200)
+	accountIdentifier := &types.AccountIdentifier{Address: "0.0.1001"}
+	tests := []struct {
+		name     string
+		history  StakingHistory
+		expected map[string]interface{}
+	}{
+		{
+			name:    "not staked",
+			history: StakingHistory{AccountId: domain.MustDecodeEntityId(1001), StakedNodeId: &noNode},
+			expected: map[string]interface{}{
+				"account_identifier": accountIdentifier,
+				"decline_reward":     false,
+				"pending_reward":     (&HbarAmount{}).ToRosetta(),
+				"periods":            []map[string]interface{}{},
+				"rewards":            []map[string]interface{}{},
+			},
+		},
+		{
+			name: "staked to node",
+			history: StakingHistory{
+				AccountId:     domain.MustDecodeEntityId(1001),
+				PendingReward: 300,
+				Periods: []StakingPeriod{
+					{
+						EpochDay:      19201,
+						NodeId:        3,
+						RewardRate:    10,
+						StakeRewarded: 1000,
+						StakingPeriod: 1658966400000000000,
+					},
+				},
+				Rewards:          []StakingReward{{Amount: 200, ConsensusTimestamp: 1658966400000000010}},
+				StakedNodeId:     &nodeId,
+				StakePeriodStart: &stakePeriodStart,
+			},
+			expected: map[string]interface{}{
+				"account_identifier": accountIdentifier,
+				"decline_reward":     false,
+				"pending_reward":     (&HbarAmount{Value: 300}).ToRosetta(),
+				"periods": []map[string]interface{}{
+					{
+						"epoch_day":      int64(19201),
+						"node_id":        int64(3),
+						"reward_rate":    int64(10),
+						"stake_rewarded": int64(1000),
+						"staking_period": int64(1658966400000000000),
+					},
+				},
+				"rewards": []map[string]interface{}{
+					{
+						"amount":              (&HbarAmount{Value: 200}).ToRosetta(),
+						"consensus_timestamp": int64(1658966400000000010),
+					},
+				},
+				"stake_period_start": stakePeriodStart,
+				"staked_node_id":     nodeId,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.history.ToMetadata())
+		})
+	}
+}
@@ -0,0 +1,79 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package types
+
+import (
+	"github.com/hashgraph/hedera-protobufs-go/services"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+var hederaFunctionalityOperationTypes = map[services.HederaFunctionality]string{
+	services.HederaFunctionality_CryptoCreate:               OperationTypeCryptoCreateAccount,
+	services.HederaFunctionality_CryptoTransfer:             OperationTypeCryptoTransfer,
+	services.HederaFunctionality_TokenAccountWipe:           OperationTypeTokenWipe,
+	services.HederaFunctionality_TokenAssociateToAccount:    OperationTypeTokenAssociate,
+	services.HederaFunctionality_TokenBurn:                  OperationTypeTokenBurn,
+	services.HederaFunctionality_TokenCreate:                OperationTypeTokenCreate,
+	services.HederaFunctionality_TokenDelete:                OperationTypeTokenDelete,
+	services.HederaFunctionality_TokenDissociateFromAccount: OperationTypeTokenDissociate,
+	services.HederaFunctionality_TokenFreezeAccount:         OperationTypeTokenFreeze,
+	services.HederaFunctionality_TokenGrantKycToAccount:     OperationTypeTokenGrantKyc,
+	services.HederaFunctionality_TokenMint:                  OperationTypeTokenMint,
+	services.HederaFunctionality_TokenRevokeKycFromAccount:  OperationTypeTokenRevokeKyc,
+	services.HederaFunctionality_TokenUnfreezeAccount:       OperationTypeTokenUnfreeze,
+	services.HederaFunctionality_TokenUpdate:                OperationTypeTokenUpdate,
+}
+
+// Throttles maps an operation type to the network throttle of the corresponding transaction in transactions per
+// second. When a transaction belongs to multiple throttle groups, the most restrictive one applies
+type Throttles map[string]float64
+
+// NewThrottlesFromBytes creates Throttles from the protobuf-encoded throttle definitions file content
+func NewThrottlesFromBytes(data []byte) (Throttles, error) {
+	if len(data) == 0 {
+		return nil, errors.Errorf("Empty throttle definitions provided")
+	}
+
+	var definitions services.ThrottleDefinitions
+	if err := proto.Unmarshal(data, &definitions); err != nil {
+		return nil, err
+	}
+
+	throttles := make(Throttles)
+	for _, bucket := range definitions.GetThrottleBuckets() {
+		for _, group := range bucket.GetThrottleGroups() {
+			tps := float64(group.GetMilliOpsPerSec()) / 1000
+			for _, functionality := range group.GetOperations() {
+				operationType, ok := hederaFunctionalityOperationTypes[functionality]
+				if !ok {
+					continue
+				}
+
+				if current, ok := throttles[operationType]; !ok || tps < current {
+					throttles[operationType] = tps
+				}
+			}
+		}
+	}
+
+	return throttles, nil
+}
@@ -0,0 +1,108 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package types
+
+import (
+	"testing"
+
+	"github.com/hashgraph/hedera-protobufs-go/services"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNewThrottlesFromBytes(t *testing.T) {
+	// given
+	definitions := &services.ThrottleDefinitions{
+		ThrottleBuckets: []*services.ThrottleBucket{
+			{
+				Name:          "ThroughputLimits",
+				BurstPeriodMs: 1000,
+				ThrottleGroups: []*services.ThrottleGroup{
+					{
+						Operations: []services.HederaFunctionality{
+							services.HederaFunctionality_CryptoCreate,
+							services.HederaFunctionality_CryptoTransfer,
+							services.HederaFunctionality_TokenMint,
+						},
+						MilliOpsPerSec: 10500000,
+					},
+					{
+						Operations:     []services.HederaFunctionality{services.HederaFunctionality_FileCreate},
+						MilliOpsPerSec: 2000,
+					},
+				},
+			},
+			{
+				Name:          "CreationLimits",
+				BurstPeriodMs: 1000,
+				ThrottleGroups: []*services.ThrottleGroup{
+					{
+						Operations:     []services.HederaFunctionality{services.HederaFunctionality_CryptoCreate},
+						MilliOpsPerSec: 2000,
+					},
+				},
+			},
+			{
+				Name:          "PriorityReservations",
+				BurstPeriodMs: 1000,
+				ThrottleGroups: []*services.ThrottleGroup{
+					{
+						Operations:     []services.HederaFunctionality{services.HederaFunctionality_TokenMint},
+						MilliOpsPerSec: 50500,
+					},
+				},
+			},
+		},
+	}
+	data, err := proto.Marshal(definitions)
+	assert.NoError(t, err)
+	expected := Throttles{
+		OperationTypeCryptoCreateAccount: 2,
+		OperationTypeCryptoTransfer:      10500,
+		OperationTypeTokenMint:           50.5,
+	}
+
+	// when
+	actual, err := NewThrottlesFromBytes(data)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestNewThrottlesFromBytesFail(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "nil", data: nil},
+		{name: "empty", data: []byte{}},
+		{name: "invalid", data: []byte{0x1, 0x2, 0x3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			throttles, err := NewThrottlesFromBytes(tt.data)
+			assert.Error(t, err)
+			assert.Nil(t, throttles)
+		})
+	}
+}
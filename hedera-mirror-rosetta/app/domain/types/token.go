@@ -21,8 +21,11 @@
 package types
 
 import (
+	"encoding/hex"
+
 	"github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/tools"
 	"github.com/hashgraph/hedera-sdk-go/v2"
 )
 
@@ -31,6 +34,45 @@ type Token struct {
 	domain.Token
 }
 
+// Nft is domain level struct used to represent a serial of a non-fungible token collection
+type Nft struct {
+	domain.Nft
+}
+
+// ToMetadata returns the token id, serial number, owner, metadata bytes in hex, mint timestamp, burn timestamp if
+// burned, spender and delegating spender if set of the nft
+func (n Nft) ToMetadata() map[string]interface{} {
+	metadata := map[string]interface{}{
+		"deleted":       n.Deleted != nil && *n.Deleted,
+		"metadata":      tools.SafeAddHexPrefix(hex.EncodeToString(n.Metadata)),
+		"serial_number": n.SerialNumber,
+		"token_id":      n.TokenId.String(),
+	}
+	if n.AccountId != nil && !n.AccountId.IsZero() {
+		metadata["account_id"] = n.AccountId.String()
+	}
+	if n.CreatedTimestamp != nil {
+		metadata["mint_timestamp"] = *n.CreatedTimestamp
+	}
+	if n.Deleted != nil && *n.Deleted {
+		// the nft is last modified when it's burned or wiped
+		metadata["burn_timestamp"] = n.ModifiedTimestamp
+	}
+	if n.Spender != nil {
+		metadata["spender"] = n.Spender.String()
+	}
+	if n.DelegatingSpender != nil {
+		metadata["delegating_spender"] = n.DelegatingSpender.String()
+	}
+	return metadata
+}
+
+// TokenHolder is an account holding a token with its balance
+type TokenHolder struct {
+	AccountId domain.EntityId
+	Balance   int64
+}
+
 func (t Token) ToHederaTokenId() *hedera.TokenID {
 	return &hedera.TokenID{
 		Shard: uint64(t.TokenId.ShardNum),
@@ -39,6 +81,8 @@ func (t Token) ToHederaTokenId() *hedera.TokenID {
 	}
 }
 
+// ToRosettaCurrency returns the Rosetta currency of the token. Only the immutable attributes are included so the
+// currency of a historical balance or operation is the same regardless of later token updates
 func (t Token) ToRosettaCurrency() *types.Currency {
 	return &types.Currency{
 		Symbol:   t.TokenId.String(),
@@ -64,3 +64,61 @@ func TestTokenToRosettaCurrency(t *testing.T) {
 	// then
 	assert.Equal(t, expected, actual)
 }
+
+func TestNftToMetadata(t *testing.T) {
+	accountId := domain.MustDecodeEntityId(1001)
+	createdTimestamp := int64(100)
+	deleted := true
+	spender := domain.MustDecodeEntityId(1002)
+	tests := []struct {
+		name     string
+		nft      Nft
+		expected map[string]interface{}
+	}{
+		{
+			name: "owned",
+			nft: Nft{domain.Nft{
+				AccountId:         &accountId,
+				CreatedTimestamp:  &createdTimestamp,
+				Metadata:          []byte{0x1, 0x2},
+				ModifiedTimestamp: 150,
+				SerialNumber:      1,
+				Spender:           &spender,
+				TokenId:           domain.MustDecodeEntityId(2001),
+			}},
+			expected: map[string]interface{}{
+				"account_id":     "0.0.1001",
+				"deleted":        false,
+				"metadata":       "0x0102",
+				"mint_timestamp": createdTimestamp,
+				"serial_number":  int64(1),
+				"spender":        "0.0.1002",
+				"token_id":       "0.0.2001",
+			},
+		},
+		{
+			name: "burned",
+			nft: Nft{domain.Nft{
+				CreatedTimestamp:  &createdTimestamp,
+				Deleted:           &deleted,
+				ModifiedTimestamp: 200,
+				SerialNumber:      2,
+				TokenId:           domain.MustDecodeEntityId(2001),
+			}},
+			expected: map[string]interface{}{
+				"burn_timestamp": int64(200),
+				"deleted":        true,
+				"metadata":       "0x",
+				"mint_timestamp": createdTimestamp,
+				"serial_number":  int64(2),
+				"token_id":       "0.0.2001",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.nft.ToMetadata())
+		})
+	}
+}
@@ -0,0 +1,280 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package types
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"sort"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/tools"
+	"github.com/hashgraph/hedera-protobufs-go/services"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	runningHashVersion2 = 2
+	runningHashVersion3 = 3
+)
+
+// javaByteArrayClassDescriptor is the java object serialization class descriptor of byte[]: TC_CLASSDESC, the class
+// name "[B", the serialVersionUID, the SC_SERIALIZABLE flag, zero fields, TC_ENDBLOCKDATA, and TC_NULL super class
+var javaByteArrayClassDescriptor = []byte{
+	0x72, 0x00, 0x02, 0x5b, 0x42, 0xac, 0xf3, 0x17, 0xf8, 0x06, 0x08, 0x54, 0xe0, 0x02, 0x00, 0x00, 0x78, 0x70,
+}
+
+// TopicMessageChunk is a chunk of a HCS message with the running hash of the previous message in the topic
+type TopicMessageChunk struct {
+	domain.TopicMessage
+	PreviousRunningHash []byte
+}
+
+// getChunkNum returns the chunk number, or 1 if the message is not chunked
+func (c TopicMessageChunk) getChunkNum() int32 {
+	if c.ChunkNum == nil {
+		return 1
+	}
+	return *c.ChunkNum
+}
+
+// getChunkTotal returns the total number of chunks, or 1 if the message is not chunked
+func (c TopicMessageChunk) getChunkTotal() int32 {
+	if c.ChunkTotal == nil {
+		return 1
+	}
+	return *c.ChunkTotal
+}
+
+// verifyRunningHash recomputes the running hash of the chunk from the running hash of the previous message in the
+// topic the same way as the consensus nodes do. It returns nil if the running hash can't be verified because either the
+// previous running hash is unknown or the running hash version is not supported
+func (c TopicMessageChunk) verifyRunningHash() *bool {
+	previousRunningHash := c.PreviousRunningHash
+	if c.SequenceNumber == 1 {
+		previousRunningHash = make([]byte, sha512.Size384)
+	}
+
+	if len(previousRunningHash) == 0 {
+		return nil
+	}
+
+	var primitives []int64
+	message := c.Message
+	switch c.RunningHashVersion {
+	case runningHashVersion2:
+		primitives = []int64{runningHashVersion2}
+	case runningHashVersion3:
+		if c.PayerAccountId == nil {
+			return nil
+		}
+		primitives = []int64{
+			runningHashVersion3,
+			c.PayerAccountId.ShardNum,
+			c.PayerAccountId.RealmNum,
+			c.PayerAccountId.EntityNum,
+		}
+		messageHash := sha512.Sum384(c.Message)
+		message = messageHash[:]
+	default:
+		return nil
+	}
+
+	primitives = append(primitives, c.TopicId.ShardNum, c.TopicId.RealmNum, c.TopicId.EntityNum)
+
+	// the running hash is the SHA-384 hash of the java object serialization output of the fields
+	blockData := new(bytes.Buffer)
+	for _, value := range primitives {
+		_ = binary.Write(blockData, binary.BigEndian, value)
+	}
+	_ = binary.Write(blockData, binary.BigEndian, c.ConsensusTimestamp/1e9)
+	_ = binary.Write(blockData, binary.BigEndian, int32(c.ConsensusTimestamp%1e9))
+	_ = binary.Write(blockData, binary.BigEndian, c.SequenceNumber)
+
+	buffer := new(bytes.Buffer)
+	// stream magic and version
+	buffer.Write([]byte{0xac, 0xed, 0x00, 0x05})
+	writeJavaByteArray(buffer, previousRunningHash, true)
+	// TC_BLOCKDATA with the length, the primitives always fit in a short block
+	buffer.Write([]byte{0x77, byte(blockData.Len())})
+	buffer.Write(blockData.Bytes())
+	writeJavaByteArray(buffer, message, false)
+
+	runningHash := sha512.Sum384(buffer.Bytes())
+	verified := bytes.Equal(runningHash[:], c.RunningHash)
+	return &verified
+}
+
+// TopicMessage is domain level struct used to represent a HCS message reassembled from its chunks
+type TopicMessage struct {
+	Chunks               []TopicMessageChunk
+	ChunkTotal           int32
+	InitialTransactionId *services.TransactionID
+	TopicId              domain.EntityId
+}
+
+// IsComplete returns true if all chunks of the message are present
+func (t TopicMessage) IsComplete() bool {
+	return int32(len(t.Chunks)) == t.ChunkTotal
+}
+
+// GetMessage returns the message reassembled from the chunks in the order of the chunk number, or nil if the message
+// is incomplete
+func (t TopicMessage) GetMessage() []byte {
+	if !t.IsComplete() {
+		return nil
+	}
+
+	message := make([]byte, 0)
+	for _, chunk := range t.Chunks {
+		message = append(message, chunk.Message...)
+	}
+	return message
+}
+
+// ToMetadata returns the topic id, the total number of chunks, the chunks with their running hash verification result,
+// and the reassembled message if complete as metadata. The message is verified only if the running hashes of all chunks
+// are verified, and running_hash_verified is not set if any chunk can't be verified
+func (t TopicMessage) ToMetadata() map[string]interface{} {
+	chunks := make([]map[string]interface{}, 0, len(t.Chunks))
+	var verified *bool
+	allVerified := true
+	for _, chunk := range t.Chunks {
+		chunkMetadata := map[string]interface{}{
+			"chunk_num":           chunk.getChunkNum(),
+			"consensus_timestamp": chunk.ConsensusTimestamp,
+			"running_hash":        tools.SafeAddHexPrefix(hex.EncodeToString(chunk.RunningHash)),
+			"sequence_number":     chunk.SequenceNumber,
+		}
+
+		chunkVerified := chunk.verifyRunningHash()
+		if chunkVerified != nil {
+			chunkMetadata["running_hash_verified"] = *chunkVerified
+			if !*chunkVerified {
+				verified = chunkVerified
+			}
+		} else {
+			allVerified = false
+		}
+
+		chunks = append(chunks, chunkMetadata)
+	}
+
+	if verified == nil && allVerified && len(t.Chunks) != 0 {
+		verified = &allVerified
+	}
+
+	metadata := map[string]interface{}{
+		"chunk_total": t.ChunkTotal,
+		"chunks":      chunks,
+		"complete":    t.IsComplete(),
+		"topic_id":    t.TopicId.String(),
+	}
+
+	if t.InitialTransactionId != nil {
+		if initialTransactionId, err := protoToMap(t.InitialTransactionId); err == nil {
+			metadata["initial_transaction_id"] = initialTransactionId
+		}
+	}
+
+	if message := t.GetMessage(); message != nil {
+		metadata["message"] = tools.SafeAddHexPrefix(hex.EncodeToString(message))
+	}
+
+	if verified != nil {
+		metadata["running_hash_verified"] = *verified
+	}
+
+	return metadata
+}
+
+// NewTopicMessage reassembles a HCS message from its chunks. Duplicate chunks are ignored and only the first chunk of
+// each chunk number in consensus order is kept
+func NewTopicMessage(chunks []TopicMessageChunk) *TopicMessage {
+	if len(chunks) == 0 {
+		return nil
+	}
+
+	sorted := make([]TopicMessageChunk, len(chunks))
+	copy(sorted, chunks)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].getChunkNum() != sorted[j].getChunkNum() {
+			return sorted[i].getChunkNum() < sorted[j].getChunkNum()
+		}
+		return sorted[i].ConsensusTimestamp < sorted[j].ConsensusTimestamp
+	})
+
+	unique := make([]TopicMessageChunk, 0, len(sorted))
+	for _, chunk := range sorted {
+		if len(unique) != 0 && unique[len(unique)-1].getChunkNum() == chunk.getChunkNum() {
+			continue
+		}
+		unique = append(unique, chunk)
+	}
+
+	first := unique[0]
+	topicMessage := &TopicMessage{
+		Chunks:     unique,
+		ChunkTotal: first.getChunkTotal(),
+		TopicId:    first.TopicId,
+	}
+
+	if transactionId, err := GetInitialTransactionId(first.TopicMessage); err != nil {
+		log.Warnf("Failed to unmarshal initial transaction id of topic message %d: %s", first.ConsensusTimestamp, err)
+	} else {
+		topicMessage.InitialTransactionId = transactionId
+	}
+
+	return topicMessage
+}
+
+// GetInitialTransactionId returns the decoded initial transaction id of the chunked topic message, or nil if the
+// message is not chunked
+func GetInitialTransactionId(topicMessage domain.TopicMessage) (*services.TransactionID, error) {
+	if len(topicMessage.InitialTransactionId) == 0 {
+		return nil, nil
+	}
+
+	var transactionId services.TransactionID
+	if err := proto.Unmarshal(topicMessage.InitialTransactionId, &transactionId); err != nil {
+		return nil, err
+	}
+
+	return &transactionId, nil
+}
+
+// writeJavaByteArray writes the byte array in the java object serialization format. The class descriptor is written
+// for the first byte array in the stream, and referenced by its handle afterwards
+func writeJavaByteArray(buffer *bytes.Buffer, data []byte, first bool) {
+	// TC_ARRAY
+	buffer.WriteByte(0x75)
+	if first {
+		buffer.Write(javaByteArrayClassDescriptor)
+	} else {
+		// TC_REFERENCE to the class descriptor which is the first object with the base handle
+		buffer.Write([]byte{0x71, 0x00, 0x7e, 0x00, 0x00})
+	}
+	_ = binary.Write(buffer, binary.BigEndian, int32(len(data)))
+	buffer.Write(data)
+}
@@ -0,0 +1,298 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package types
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-protobufs-go/services"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	topicId            = domain.MustDecodeEntityId(3001)
+	topicPayerId       = domain.MustDecodeEntityId(1001)
+	initialTransaction = &services.TransactionID{
+		AccountID:             &services.AccountID{Account: &services.AccountID_AccountNum{AccountNum: 1001}},
+		TransactionValidStart: &services.Timestamp{Seconds: 1, Nanos: 100},
+	}
+)
+
+func TestNewTopicMessage(t *testing.T) {
+	// given
+	initialTransactionId, _ := proto.Marshal(initialTransaction)
+	chunks := []TopicMessageChunk{
+		newTopicMessageChunk(2, 3, 1000000300, []byte{3, 4}, initialTransactionId),
+		newTopicMessageChunk(1, 3, 1000000200, []byte{1, 2}, initialTransactionId),
+		// duplicate chunk reaching consensus later is ignored
+		newTopicMessageChunk(1, 3, 1000000500, []byte{0xff}, initialTransactionId),
+		newTopicMessageChunk(3, 3, 1000000400, []byte{5}, initialTransactionId),
+	}
+
+	// when
+	actual := NewTopicMessage(chunks)
+
+	// then
+	assert.Equal(t, int32(3), actual.ChunkTotal)
+	assert.Equal(t, topicId, actual.TopicId)
+	assert.True(t, proto.Equal(initialTransaction, actual.InitialTransactionId))
+	assert.Equal(t, []TopicMessageChunk{chunks[1], chunks[0], chunks[3]}, actual.Chunks)
+	assert.True(t, actual.IsComplete())
+	assert.Equal(t, []byte{1, 2, 3, 4, 5}, actual.GetMessage())
+	assert.Equal(
+		t,
+		map[string]interface{}{
+			"accountID":             map[string]interface{}{"accountNum": "1001"},
+			"transactionValidStart": map[string]interface{}{"seconds": "1", "nanos": float64(100)},
+		},
+		actual.ToMetadata()["initial_transaction_id"],
+	)
+}
+
+func TestNewTopicMessageIncomplete(t *testing.T) {
+	// given
+	initialTransactionId, _ := proto.Marshal(initialTransaction)
+	chunks := []TopicMessageChunk{
+		newTopicMessageChunk(1, 3, 1000000200, []byte{1, 2}, initialTransactionId),
+		newTopicMessageChunk(3, 3, 1000000400, []byte{5}, initialTransactionId),
+	}
+
+	// when
+	actual := NewTopicMessage(chunks)
+
+	// then
+	assert.False(t, actual.IsComplete())
+	assert.Nil(t, actual.GetMessage())
+	metadata := actual.ToMetadata()
+	assert.Equal(t, false, metadata["complete"])
+	assert.NotContains(t, metadata, "message")
+}
+
+func TestNewTopicMessageEmpty(t *testing.T) {
+	assert.Nil(t, NewTopicMessage([]TopicMessageChunk{}))
+}
+
+func TestTopicMessageToMetadata(t *testing.T) {
+	// given
+	chunk := TopicMessageChunk{TopicMessage: domain.TopicMessage{
+		ConsensusTimestamp: 1000000200,
+		Message:            []byte{1, 2},
+		PayerAccountId:     &topicPayerId,
+		RunningHashVersion: 3,
+		SequenceNumber:     1,
+		TopicId:            topicId,
+	}}
+	chunk.RunningHash = getRunningHash(chunk, make([]byte, 48))
+	expected := map[string]interface{}{
+		"chunk_total": int32(1),
+		"chunks": []map[string]interface{}{
+			{
+				"chunk_num":             int32(1),
+				"consensus_timestamp":   int64(1000000200),
+				"running_hash":          "0x" + hex.EncodeToString(chunk.RunningHash),
+				"running_hash_verified": true,
+				"sequence_number":       int64(1),
+			},
+		},
+		"complete":              true,
+		"message":               "0x0102",
+		"running_hash_verified": true,
+		"topic_id":              "0.0.3001",
+	}
+
+	// when
+	actual := NewTopicMessage([]TopicMessageChunk{chunk}).ToMetadata()
+
+	// then
+	assert.Equal(t, expected, actual)
+}
+
+func TestTopicMessageChunkVerifyRunningHash(t *testing.T) {
+	previousRunningHash := sha512.Sum384([]byte("previous"))
+	tests := []struct {
+		name                string
+		payerAccountId      *domain.EntityId
+		previousRunningHash []byte
+		runningHashVersion  int16
+		sequenceNumber      int64
+		tamper              bool
+		expected            *bool
+	}{
+		{
+			name:                "version 2",
+			previousRunningHash: previousRunningHash[:],
+			runningHashVersion:  2,
+			sequenceNumber:      2,
+			expected:            boolPtr(true),
+		},
+		{
+			name:                "version 3",
+			payerAccountId:      &topicPayerId,
+			previousRunningHash: previousRunningHash[:],
+			runningHashVersion:  3,
+			sequenceNumber:      2,
+			expected:            boolPtr(true),
+		},
+		{
+			name:               "first message",
+			payerAccountId:     &topicPayerId,
+			runningHashVersion: 3,
+			sequenceNumber:     1,
+			expected:           boolPtr(true),
+		},
+		{
+			name:                "mismatch",
+			payerAccountId:      &topicPayerId,
+			previousRunningHash: previousRunningHash[:],
+			runningHashVersion:  3,
+			sequenceNumber:      2,
+			tamper:              true,
+			expected:            boolPtr(false),
+		},
+		{
+			name:               "previous running hash unknown",
+			payerAccountId:     &topicPayerId,
+			runningHashVersion: 3,
+			sequenceNumber:     2,
+		},
+		{
+			name:                "version 3 without payer",
+			previousRunningHash: previousRunningHash[:],
+			runningHashVersion:  3,
+			sequenceNumber:      2,
+		},
+		{
+			name:                "unsupported version",
+			previousRunningHash: previousRunningHash[:],
+			runningHashVersion:  1,
+			sequenceNumber:      2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			chunk := TopicMessageChunk{
+				TopicMessage: domain.TopicMessage{
+					ConsensusTimestamp: 1000000200,
+					Message:            []byte("foobar"),
+					PayerAccountId:     tt.payerAccountId,
+					RunningHashVersion: tt.runningHashVersion,
+					SequenceNumber:     tt.sequenceNumber,
+					TopicId:            topicId,
+				},
+				PreviousRunningHash: tt.previousRunningHash,
+			}
+			previous := tt.previousRunningHash
+			if tt.sequenceNumber == 1 {
+				previous = make([]byte, 48)
+			}
+			chunk.RunningHash = getRunningHash(chunk, previous)
+			if tt.tamper {
+				chunk.Message = []byte("tampered")
+			}
+
+			// when
+			actual := chunk.verifyRunningHash()
+
+			// then
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func boolPtr(value bool) *bool {
+	return &value
+}
+
+// getRunningHash computes the expected running hash with the explicit java object serialization layout
+func getRunningHash(chunk TopicMessageChunk, previousRunningHash []byte) []byte {
+	if chunk.PayerAccountId == nil && chunk.RunningHashVersion == 3 {
+		return nil
+	}
+
+	long := func(value int64) []byte {
+		data := make([]byte, 8)
+		binary.BigEndian.PutUint64(data, uint64(value))
+		return data
+	}
+	integer := func(value int32) []byte {
+		data := make([]byte, 4)
+		binary.BigEndian.PutUint32(data, uint32(value))
+		return data
+	}
+
+	data := []byte{0xac, 0xed, 0x00, 0x05}
+	// byte[] with the class descriptor
+	data = append(data, 0x75, 0x72, 0x00, 0x02, '[', 'B', 0xac, 0xf3, 0x17, 0xf8, 0x06, 0x08, 0x54, 0xe0, 0x02, 0x00,
+		0x00, 0x78, 0x70)
+	data = append(data, integer(int32(len(previousRunningHash)))...)
+	data = append(data, previousRunningHash...)
+
+	block := long(int64(chunk.RunningHashVersion))
+	message := chunk.Message
+	if chunk.RunningHashVersion == 3 {
+		block = append(block, long(chunk.PayerAccountId.ShardNum)...)
+		block = append(block, long(chunk.PayerAccountId.RealmNum)...)
+		block = append(block, long(chunk.PayerAccountId.EntityNum)...)
+		messageHash := sha512.Sum384(chunk.Message)
+		message = messageHash[:]
+	}
+	block = append(block, long(chunk.TopicId.ShardNum)...)
+	block = append(block, long(chunk.TopicId.RealmNum)...)
+	block = append(block, long(chunk.TopicId.EntityNum)...)
+	block = append(block, long(chunk.ConsensusTimestamp/1e9)...)
+	block = append(block, integer(int32(chunk.ConsensusTimestamp%1e9))...)
+	block = append(block, long(chunk.SequenceNumber)...)
+	data = append(data, 0x77, byte(len(block)))
+	data = append(data, block...)
+
+	// byte[] referencing the class descriptor
+	data = append(data, 0x75, 0x71, 0x00, 0x7e, 0x00, 0x00)
+	data = append(data, integer(int32(len(message)))...)
+	data = append(data, message...)
+
+	hash := sha512.Sum384(data)
+	return hash[:]
+}
+
+func newTopicMessageChunk(
+	chunkNum, chunkTotal int32,
+	consensusTimestamp int64,
+	message, initialTransactionId []byte,
+) TopicMessageChunk {
+	return TopicMessageChunk{TopicMessage: domain.TopicMessage{
+		ChunkNum:             &chunkNum,
+		ChunkTotal:           &chunkTotal,
+		ConsensusTimestamp:   consensusTimestamp,
+		InitialTransactionId: initialTransactionId,
+		Message:              message,
+		PayerAccountId:       &topicPayerId,
+		RunningHashVersion:   3,
+		SequenceNumber:       consensusTimestamp,
+		TopicId:              topicId,
+	}}
+}
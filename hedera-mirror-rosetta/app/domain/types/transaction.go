@@ -21,23 +21,46 @@
 package types
 
 import (
+	"encoding/hex"
+
 	"github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/tools"
 )
 
 // Transaction is domain level struct used to represent Transaction conceptual mapping in Hedera
 type Transaction struct {
-	EntityId   *domain.EntityId
-	Hash       string
-	Operations OperationSlice
+	EntityId               *domain.EntityId
+	FeeBreakdown           *FeeBreakdown
+	Hash                   string
+	Operations             OperationSlice
+	TransactionBytes       []byte
+	TransactionRecordBytes []byte
 }
 
 // ToRosetta returns Rosetta type Transaction from the current domain type Transaction
 func (t *Transaction) ToRosetta() *types.Transaction {
 	operations := t.Operations.ToRosetta()
-	var metadata map[string]interface{}
+	metadata := make(map[string]interface{})
 	if t.EntityId != nil {
-		metadata = map[string]interface{}{"entity_id": t.EntityId.String()}
+		metadata["entity_id"] = t.EntityId.String()
+	}
+
+	if t.FeeBreakdown != nil {
+		metadata["fee_breakdown"] = t.FeeBreakdown.ToMetadata()
+	}
+
+	// the raw bytes are only available when the importer is configured to persist them
+	if len(t.TransactionBytes) != 0 {
+		metadata["transaction_bytes"] = tools.SafeAddHexPrefix(hex.EncodeToString(t.TransactionBytes))
+	}
+
+	if len(t.TransactionRecordBytes) != 0 {
+		metadata["record_bytes"] = tools.SafeAddHexPrefix(hex.EncodeToString(t.TransactionRecordBytes))
+	}
+
+	if len(metadata) == 0 {
+		metadata = nil
 	}
 
 	return &types.Transaction{
@@ -0,0 +1,86 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package types
+
+import (
+	"sort"
+	"strconv"
+)
+
+// TransactionTypeCount is domain level struct used to represent the number of transactions of a type with a result
+type TransactionTypeCount struct {
+	Count  int64
+	Result int16
+	Type   int16
+}
+
+// TransactionStatistics is domain level struct used to represent the number of transactions per type and result in
+// the timestamp range [Start, End]
+type TransactionStatistics struct {
+	Counts []TransactionTypeCount
+	End    int64
+	Start  int64
+}
+
+// ToMetadata returns the total number of transactions, and the number of transactions of each type with the number of
+// each result, ordered by the type name, as metadata. An unknown type or result is named by its proto id
+func (s TransactionStatistics) ToMetadata() map[string]interface{} {
+	var total int64
+	typeCounts := make(map[string]int64)
+	resultCounts := make(map[string]map[string]int64)
+	for _, count := range s.Counts {
+		name := getNameOrId(TransactionTypes, int32(count.Type))
+		if resultCounts[name] == nil {
+			resultCounts[name] = make(map[string]int64)
+		}
+		resultCounts[name][getNameOrId(TransactionResults, int32(count.Result))] += count.Count
+		typeCounts[name] += count.Count
+		total += count.Count
+	}
+
+	names := make([]string, 0, len(typeCounts))
+	for name := range typeCounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	transactionTypes := make([]map[string]interface{}, 0, len(names))
+	for _, name := range names {
+		transactionTypes = append(transactionTypes, map[string]interface{}{
+			"count":   typeCounts[name],
+			"results": resultCounts[name],
+			"type":    name,
+		})
+	}
+
+	return map[string]interface{}{
+		"end":               s.End,
+		"start":             s.Start,
+		"transaction_count": total,
+		"transaction_types": transactionTypes,
+	}
+}
+
+func getNameOrId(names map[int32]string, id int32) string {
+	if name, ok := names[id]; ok {
+		return name
+	}
+	return strconv.Itoa(int(id))
+}
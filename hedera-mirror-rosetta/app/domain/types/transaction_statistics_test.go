@@ -0,0 +1,70 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionStatisticsToMetadata(t *testing.T) {
+	// given
+	statistics := TransactionStatistics{
+		Counts: []TransactionTypeCount{
+			{Count: 5, Result: 22, Type: 14},
+			{Count: 2, Result: 10, Type: 14},
+			{Count: 3, Result: 22, Type: 11},
+			{Count: 1, Result: 9999, Type: 9999},
+		},
+		End:   200,
+		Start: 100,
+	}
+	expected := map[string]interface{}{
+		"end":               int64(200),
+		"start":             int64(100),
+		"transaction_count": int64(11),
+		"transaction_types": []map[string]interface{}{
+			{"count": int64(1), "results": map[string]int64{"9999": 1}, "type": "9999"},
+			{"count": int64(3), "results": map[string]int64{TransactionResults[22]: 3}, "type": TransactionTypes[11]},
+			{
+				"count":   int64(7),
+				"results": map[string]int64{TransactionResults[10]: 2, TransactionResults[22]: 5},
+				"type":    TransactionTypes[14],
+			},
+		},
+	}
+
+	// when
+	actual := statistics.ToMetadata()
+
+	// then
+	assert.Equal(t, expected, actual)
+}
+
+func TestTransactionStatisticsToMetadataEmpty(t *testing.T) {
+	expected := map[string]interface{}{
+		"end":               int64(2),
+		"start":             int64(1),
+		"transaction_count": int64(0),
+		"transaction_types": []map[string]interface{}{},
+	}
+	assert.Equal(t, expected, TransactionStatistics{End: 2, Start: 1}.ToMetadata())
+}
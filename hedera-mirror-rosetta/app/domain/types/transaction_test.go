@@ -91,3 +91,34 @@ func TestToRosettaTransactionNoEntityId(t *testing.T) {
 	// then
 	assert.Equal(t, expected, actual)
 }
+
+func TestToRosettaTransactionWithRawBytes(t *testing.T) {
+	// given
+	expected := expectedTransaction()
+	expected.Metadata["transaction_bytes"] = "0x0102"
+	expected.Metadata["record_bytes"] = "0x0304"
+
+	// when
+	transaction := exampleTransaction()
+	transaction.TransactionBytes = []byte{1, 2}
+	transaction.TransactionRecordBytes = []byte{3, 4}
+	actual := transaction.ToRosetta()
+
+	// then
+	assert.Equal(t, expected, actual)
+}
+
+func TestToRosettaTransactionWithFeeBreakdown(t *testing.T) {
+	// given
+	feeBreakdown := &FeeBreakdown{NetworkFee: 10, NodeFee: 5, Source: FeeBreakdownSourceTransfers, TotalFee: 15}
+	expected := expectedTransaction()
+	expected.Metadata["fee_breakdown"] = feeBreakdown.ToMetadata()
+
+	// when
+	transaction := exampleTransaction()
+	transaction.FeeBreakdown = feeBreakdown
+	actual := transaction.ToRosetta()
+
+	// then
+	assert.Equal(t, expected, actual)
+}
@@ -27,11 +27,13 @@ import (
 const (
 	AccountNotFound                   = "Account not found"
 	BlockNotFound                     = "Block not found"
+	CallMethodUnsupported             = "Call method unsupported"
 	CreateAccountDbIdFailed           = "An error occurred while creating Account ID from encoded DB ID: %x"
 	EmptyOperations                   = "Empty operations provided"
 	EndpointNotSupportedInOfflineMode = "Endpoint not supported in offline mode"
 	InvalidAccount                    = "Invalid Account provided"
 	InvalidAmount                     = "Invalid Amount provided"
+	InvalidCallParameters             = "Invalid call parameters"
 	InvalidOperationsAmount           = "Invalid Operations amount provided"
 	InvalidOperationsTotalAmount      = "Operations total amount must be 0"
 	InvalidPublicKey                  = "Invalid Public Key provided"
@@ -65,6 +67,23 @@ const (
 	InvalidCurrency                   = "Invalid currency"
 	InvalidCurveType                  = "Invalid curve type"
 	InvalidOptions                    = "Invalid options"
+	TransactionSizeExceeded           = "Transaction size exceeded"
+	MaxTransactionFeeTooLow           = "Max transaction fee too low"
+	ScheduleNotFound                  = "Schedule not found"
+	TooManyConcurrentRequests         = "Too many concurrent requests"
+	NftNotFound                       = "Nft not found"
+	TopicMessageNotFound              = "Topic message not found"
+	EndpointTimeout                   = "Endpoint timeout"
+	NodeStakeNotFound                 = "Node stake not found"
+	ServerSigningFailed               = "Server-side signing failed"
+	NodeCertificateVerificationFailed = "Node certificate verification failed"
+	LedgerIdMismatch                  = "Ledger id mismatch"
+	HistoryPruned                     = "History pruned"
+	BalanceSnapshotNotFound           = "Balance snapshot not found"
+	UnsupportedSpecVersion            = "Unsupported rosetta spec version"
+	BalanceReplayBoundExceeded        = "Balance replay bound exceeded"
+	RecordFileNotAvailable            = "Record file not available"
+	ServerSigningNotAllowed           = "Server-side signing not allowed"
 	InternalServerError               = "Internal Server Error"
 )
 
@@ -108,6 +127,25 @@ var (
 	ErrEndpointNotSupportedInOfflineMode = newError(EndpointNotSupportedInOfflineMode, 136, false)
 	ErrInvalidCurveType                  = newError(InvalidCurveType, 137, false)
 	ErrInvalidOptions                    = newError(InvalidOptions, 138, false)
+	ErrCallMethodUnsupported             = newError(CallMethodUnsupported, 139, false)
+	ErrInvalidCallParameters             = newError(InvalidCallParameters, 140, false)
+	ErrTransactionSizeExceeded           = newError(TransactionSizeExceeded, 141, false)
+	ErrMaxTransactionFeeTooLow           = newError(MaxTransactionFeeTooLow, 142, false)
+	ErrScheduleNotFound                  = newError(ScheduleNotFound, 143, true)
+	ErrTooManyConcurrentRequests         = newError(TooManyConcurrentRequests, 144, true)
+	ErrNftNotFound                       = newError(NftNotFound, 145, false)
+	ErrTopicMessageNotFound              = newError(TopicMessageNotFound, 146, true)
+	ErrEndpointTimeout                   = newError(EndpointTimeout, 147, true)
+	ErrNodeStakeNotFound                 = newError(NodeStakeNotFound, 148, true)
+	ErrServerSigningFailed               = newError(ServerSigningFailed, 149, true)
+	ErrNodeCertificateVerificationFailed = newError(NodeCertificateVerificationFailed, 150, true)
+	ErrLedgerIdMismatch                  = newError(LedgerIdMismatch, 151, false)
+	ErrHistoryPruned                     = newError(HistoryPruned, 152, false)
+	ErrBalanceSnapshotNotFound           = newError(BalanceSnapshotNotFound, 153, true)
+	ErrUnsupportedSpecVersion            = newError(UnsupportedSpecVersion, 154, false)
+	ErrBalanceReplayBoundExceeded        = newError(BalanceReplayBoundExceeded, 155, false)
+	ErrRecordFileNotAvailable            = newError(RecordFileNotAvailable, 156, false)
+	ErrServerSigningNotAllowed           = newError(ServerSigningNotAllowed, 157, false)
 	ErrInternalServerError               = newError(InternalServerError, 500, true)
 
 	Errors = make([]*types.Error, 0)
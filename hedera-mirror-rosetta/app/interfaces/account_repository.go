@@ -30,21 +30,70 @@ import (
 // AccountRepository Interface that all AccountRepository structs must implement
 type AccountRepository interface {
 
-	// GetAccountAlias returns the alias info of the account if exists. The same accountId is returned if the account
-	// doesn't have an alias
+	// FindAccountsByAlias returns the accounts whose current or historical alias or evm address matches the alias or
+	// the evm address, with their creation transactions, ordered by the created timestamp. A nil alias or evm address
+	// matches nothing
+	FindAccountsByAlias(ctx context.Context, alias, evmAddress []byte) (types.AliasAccounts, *rTypes.Error)
+
+	// GetAccountAlias returns the alias info of the account if exists, or the evm address info if the account is a
+	// contract. The same accountId is returned if the account doesn't have an alias and isn't a contract
 	GetAccountAlias(ctx context.Context, accountId types.AccountId) (types.AccountId, *rTypes.Error)
 
+	// GetAccountLifecycle returns the lifecycle of the account if it's a hollow account or a completed hollow account.
+	// nil is returned if the account doesn't exist or has never been a hollow account
+	GetAccountLifecycle(ctx context.Context, accountId types.AccountId) (*types.AccountLifecycle, *rTypes.Error)
+
+	// GetAccountInfo returns the current key, auto renew period, and expiration of the account. nil is returned if
+	// the account doesn't exist or is deleted
+	GetAccountInfo(ctx context.Context, accountId types.AccountId) (*types.AccountInfo, *rTypes.Error)
+
 	// GetAccountId returns the `shard.realm.num` format of the account from its alias if exists
 	GetAccountId(ctx context.Context, accountId types.AccountId) (types.AccountId, *rTypes.Error)
 
 	// RetrieveBalanceAtBlock returns the hbar balance and token balances of the account at a given block (provided by
-	// consensusEnd timestamp).
+	// consensusEnd timestamp), the account id in the `shard.realm.num` format, and the current protobuf-encoded key of
+	// the account, which is nil if the account doesn't exist, is deleted, or doesn't have a key.
 	// balance = balanceAtLatestBalanceSnapshot + balanceChangeBetweenSnapshotAndBlock
 	// if the account is deleted at T1 and T1 <= consensusEnd, the balance is calculated as
 	// balance = balanceAtLatestBalanceSnapshotBeforeT1 + balanceChangeBetweenSnapshotAndT1
 	RetrieveBalanceAtBlock(ctx context.Context, accountId types.AccountId, consensusEnd int64) (
 		types.AmountSlice,
 		string,
+		[]byte,
+		*rTypes.Error,
+	)
+
+	// RetrieveBalanceReconciliation returns the hbar balance of the account in the latest balance snapshot at or
+	// before the timestamp and in the snapshot before it, and the net change of the account by the crypto transfers
+	// between the two snapshots with the earliest limit contributing transactions. ErrBalanceSnapshotNotFound is
+	// returned if there are fewer than two snapshots at or before the timestamp
+	RetrieveBalanceReconciliation(ctx context.Context, accountId int64, timestamp int64, limit int) (
+		*types.BalanceReconciliation,
+		*rTypes.Error,
+	)
+
+	// RetrieveAllBalancesAtBlock returns the non-zero hbar and token balances of all accounts, keyed by the encoded
+	// account id, at a given block (provided by consensusEnd timestamp) with one set-based query. The hbar balance is the
+	// first amount of an account, followed by the token balances ordered by token id
+	RetrieveAllBalancesAtBlock(ctx context.Context, consensusEnd int64) (map[int64]types.AmountSlice, *rTypes.Error)
+
+	// RetrieveGenesisAccounts returns the encoded ids, in ascending order, of the accounts in the range
+	// [minAccountId, maxAccountId] with a non-zero hbar balance in the genesis balance snapshot
+	RetrieveGenesisAccounts(ctx context.Context, minAccountId, maxAccountId int64) ([]int64, *rTypes.Error)
+
+	// RetrieveHbarBalancesAtBlock returns the hbar balances of the accounts, keyed by the encoded account id, at a given
+	// block (provided by consensusEnd timestamp) with one set-based query
+	RetrieveHbarBalancesAtBlock(ctx context.Context, accountIds []int64, consensusEnd int64) (
+		map[int64]types.HbarAmount,
+		*rTypes.Error,
+	)
+
+	// RetrieveTokenBalancesAtBlock returns the balances, ordered by token id, of the tokens of the account at a given
+	// block (provided by consensusEnd timestamp), interpolated from the token balances in the latest balance snapshot
+	// and the transfers of the tokens after it. The tokens which don't exist or are created before the genesis balance
+	// snapshot are omitted, and a dissociated token has a zero balance
+	RetrieveTokenBalancesAtBlock(ctx context.Context, accountId int64, tokenIds []int64, consensusEnd int64) (
+		types.AmountSlice,
 		*rTypes.Error,
 	)
 }
@@ -0,0 +1,58 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package interfaces
+
+// Backend is a persistence backend providing the repositories the rosetta API is served from. Postgres is the built-in
+// backend, other backends can be plugged in by implementing Backend
+type Backend interface {
+
+	// AccountRepository returns the account repository
+	AccountRepository() AccountRepository
+
+	// AddressBookEntryRepository returns the address book entry repository
+	AddressBookEntryRepository() AddressBookEntryRepository
+
+	// BlockRepository returns the block repository
+	BlockRepository() BlockRepository
+
+	// FileDataRepository returns the file data repository
+	FileDataRepository() FileDataRepository
+
+	// IndexRepository returns the index repository, or nil if the backend has no database indexes to advise on
+	IndexRepository() IndexRepository
+
+	// ScheduleRepository returns the schedule repository
+	ScheduleRepository() ScheduleRepository
+
+	// SchemaVersionRepository returns the schema version repository
+	SchemaVersionRepository() SchemaVersionRepository
+
+	// StakingRepository returns the staking repository
+	StakingRepository() StakingRepository
+
+	// TokenRepository returns the token repository
+	TokenRepository() TokenRepository
+
+	// TopicMessageRepository returns the topic message repository
+	TopicMessageRepository() TopicMessageRepository
+
+	// TransactionRepository returns the transaction repository
+	TransactionRepository() TransactionRepository
+}
@@ -0,0 +1,36 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package interfaces
+
+import rTypes "github.com/coinbase/rosetta-sdk-go/types"
+
+// BlockCache Interface that all persistent block caches must implement
+type BlockCache interface {
+
+	// Get returns the cached block response with the block index
+	Get(index int64) (*rTypes.BlockResponse, bool)
+
+	// Set caches the block response with the block index
+	Set(index int64, response *rTypes.BlockResponse)
+
+	// Close closes the underlying storage of the cache
+	Close() error
+}
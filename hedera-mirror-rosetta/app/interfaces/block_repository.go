@@ -39,9 +39,20 @@ type BlockRepository interface {
 	// FindByIndex retrieves a block by given index
 	FindByIndex(ctx context.Context, index int64) (*types.Block, *rTypes.Error)
 
+	// FindByTimestamp retrieves the block containing the consensus timestamp
+	FindByTimestamp(ctx context.Context, timestamp int64) (*types.Block, *rTypes.Error)
+
+	// FindRecordFileByIndex retrieves the record file of the block at the index as stored by the mirror node, including
+	// its raw bytes if stored
+	FindRecordFileByIndex(ctx context.Context, index int64) (*types.RecordFile, *rTypes.Error)
+
 	// RetrieveGenesis retrieves the genesis block
 	RetrieveGenesis(ctx context.Context) (*types.Block, *rTypes.Error)
 
+	// RetrieveOldest retrieves the oldest block retained in the database. It's the genesis block unless the older
+	// blocks are pruned by the data retention
+	RetrieveOldest(ctx context.Context) (*types.Block, *rTypes.Error)
+
 	// RetrieveLatest retrieves the second-latest block. It's required to hide the latest block so account service can
 	// add 0-amount genesis token balance to a block for tokens with first transfer to the account in the next block
 	RetrieveLatest(ctx context.Context) (*types.Block, *rTypes.Error)
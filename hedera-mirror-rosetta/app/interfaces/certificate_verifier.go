@@ -0,0 +1,36 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package interfaces
+
+import (
+	"context"
+
+	"github.com/hashgraph/hedera-sdk-go/v2"
+)
+
+// CertificateVerifier verifies the TLS certificates of the consensus nodes in the TLS handshake of the connections the
+// transactions are submitted over
+type CertificateVerifier interface {
+
+	// Verify verifies one of the raw certificates the node presents matches the certificate hash of the node account in
+	// the address book
+	Verify(ctx context.Context, nodeAccountId hedera.AccountID, rawCerts [][]byte) error
+}
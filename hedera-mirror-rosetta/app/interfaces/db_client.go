@@ -34,4 +34,8 @@ type DbClient interface {
 
 	// GetDbWithContext returns the gorm.DB instance with the context and the cancel function
 	GetDbWithContext(ctx context.Context) (*gorm.DB, context.CancelFunc)
+
+	// Query runs the query function with the gorm.DB instance bound to the context, and retries it with jittered
+	// exponential backoff if it fails with a transient error. The name identifies the query in the retry logs
+	Query(ctx context.Context, name string, query func(db *gorm.DB) error) error
 }
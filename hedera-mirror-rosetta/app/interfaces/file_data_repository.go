@@ -0,0 +1,38 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package interfaces
+
+import (
+	"context"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// FileDataRepository Interface that all FileDataRepository structs must implement
+type FileDataRepository interface {
+
+	// GetLatestContent returns the latest content of the file, i.e., the data of its last create or update
+	// transaction followed by the data of the append transactions after it
+	GetLatestContent(ctx context.Context, fileId int64) ([]byte, *rTypes.Error)
+
+	// GetLatestTimestamp returns the consensus timestamp of the last transaction changing the file, 0 if there is none
+	GetLatestTimestamp(ctx context.Context, fileId int64) (int64, *rTypes.Error)
+}
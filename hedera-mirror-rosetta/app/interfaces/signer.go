@@ -0,0 +1,41 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package interfaces
+
+import (
+	"context"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-sdk-go/v2"
+)
+
+// Signer signs the transactions on the server side with the keys of the accounts it holds, e.g., local keys or keys in
+// a KMS or an HSM
+type Signer interface {
+
+	// Sign signs the message with the ED25519 or ECDSA secp256k1 key of the account and returns the public key and the
+	// signature. ok is false if the signer doesn't hold the key of the account
+	Sign(ctx context.Context, accountId types.AccountId, message []byte) (
+		publicKey hedera.PublicKey,
+		signature []byte,
+		ok bool,
+		err error,
+	)
+}
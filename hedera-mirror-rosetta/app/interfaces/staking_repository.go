@@ -0,0 +1,44 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package interfaces
+
+import (
+	"context"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+)
+
+// StakingRepository Interface that all StakingRepository structs must implement
+type StakingRepository interface {
+
+	// FindEntityStake returns the balance, the staking settings, the sum of the balance of the accounts staked to it,
+	// and the pending reward of the account
+	FindEntityStake(ctx context.Context, accountId int64) (*types.EntityStake, *rTypes.Error)
+
+	// FindNodeStakes returns the stake of each node and the network stake in the staking period of the epoch day, or
+	// in the latest staking period if the epoch day is nil
+	FindNodeStakes(ctx context.Context, epochDay *int64) (*types.NodeStakes, *rTypes.Error)
+
+	// FindStakingHistory returns the staking settings and the pending reward of the account, the reward rate of the
+	// staked node in at most limit most recent staking periods, and at most limit most recent staking rewards paid to
+	// the account
+	FindStakingHistory(ctx context.Context, accountId int64, limit int) (*types.StakingHistory, *rTypes.Error)
+}
@@ -0,0 +1,53 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package interfaces
+
+import (
+	"context"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+)
+
+// TokenRepository Interface that all TokenRepository structs must implement
+type TokenRepository interface {
+
+	// Find returns the token with the token id
+	Find(ctx context.Context, tokenId int64) (*types.Token, *rTypes.Error)
+
+	// FindNft returns the nft with the token id and the serial number
+	FindNft(ctx context.Context, tokenId, serialNumber int64) (*types.Nft, *rTypes.Error)
+
+	// FindNfts returns at most limit nfts of the token in ascending order of the serial number after afterSerialNumber
+	FindNfts(ctx context.Context, tokenId, afterSerialNumber int64, limit int) ([]types.Nft, *rTypes.Error)
+
+	// FindPendingAirdrops returns at most limit pending airdrops the account is either the receiver or the sender of,
+	// oldest first. No airdrop is returned if the token airdrop table doesn't exist
+	FindPendingAirdrops(ctx context.Context, accountId int64, limit int) ([]types.PendingAirdrop, *rTypes.Error)
+
+	// FindHolders returns the consensus timestamp of the latest balance snapshot and at most limit accounts holding at
+	// least minBalance of the token in the snapshot, in ascending order of the account id after afterAccountId
+	FindHolders(ctx context.Context, tokenId, minBalance, afterAccountId int64, limit int) (
+		int64,
+		[]types.TokenHolder,
+		*rTypes.Error,
+	)
+}
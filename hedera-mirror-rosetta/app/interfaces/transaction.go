@@ -20,7 +20,11 @@
 
 package interfaces
 
-import "github.com/hashgraph/hedera-sdk-go/v2"
+import (
+	"time"
+
+	"github.com/hashgraph/hedera-sdk-go/v2"
+)
 
 // Transaction defines the transaction methods used by constructor service
 type Transaction interface {
@@ -46,6 +50,9 @@ type Transaction interface {
 	// GetTransactionID returns the transaction id
 	GetTransactionID() hedera.TransactionID
 
+	// GetTransactionValidDuration returns the duration the transaction is valid for after its valid start
+	GetTransactionValidDuration() time.Duration
+
 	// String encodes the Transaction to a string
 	String() string
 
@@ -30,12 +30,40 @@ import (
 // TransactionRepository Interface that all TransactionRepository structs must implement
 type TransactionRepository interface {
 
+	// CountBetween returns the number of transactions and the estimated number of operations between the provided
+	// start and end timestamp inclusively
+	CountBetween(ctx context.Context, start, end int64) (int64, int64, *rTypes.Error)
+
+	// CountOperationsBetween returns the estimated number of operations between the provided start and end timestamp
+	// inclusively from the transfers alone, cheap enough to run before the transactions are loaded
+	CountOperationsBetween(ctx context.Context, start, end int64) (int64, *rTypes.Error)
+
+	// CountByTypeAndResult returns the number of transactions of each type and result between the provided start and
+	// end timestamp inclusively
+	CountByTypeAndResult(ctx context.Context, start, end int64) (*types.TransactionStatistics, *rTypes.Error)
+
 	// FindBetween retrieves all Transaction between the provided start and end timestamp inclusively
 	FindBetween(ctx context.Context, start, end int64) ([]*types.Transaction, *rTypes.Error)
 
+	// FindHashesBetween retrieves the unique hashes of the transactions between the provided start and end timestamp
+	// inclusively, in chronological order
+	FindHashesBetween(ctx context.Context, start, end int64) ([]string, *rTypes.Error)
+
+	// FindKeysBySearch retrieves the consensus timestamp and hash of at most search.Limit transactions matching the
+	// search in chronological order, and the total number of transactions matching the search regardless of
+	// search.After
+	FindKeysBySearch(ctx context.Context, search types.TransactionSearch) ([]types.TransactionKey, int64, *rTypes.Error)
+
 	// FindByHashInBlock retrieves a transaction by its hash in the block identified by [consensusStart, consensusEnd]
 	FindByHashInBlock(ctx context.Context, hash string, consensusStart, consensusEnd int64) (
 		*types.Transaction,
 		*rTypes.Error,
 	)
+
+	// FindRawByHashInBlock retrieves the first transaction as stored by the mirror node by its hash in the block
+	// identified by [consensusStart, consensusEnd]
+	FindRawByHashInBlock(ctx context.Context, hash string, consensusStart, consensusEnd int64) (
+		*types.RawTransaction,
+		*rTypes.Error,
+	)
 }
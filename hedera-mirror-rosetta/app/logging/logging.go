@@ -0,0 +1,303 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package logging
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	FormatJson = "json"
+	FormatText = "text"
+
+	appDir     = "/app/"
+	moduleName = "hedera-mirror-rosetta"
+)
+
+var (
+	// configured is the formatter installed by Configure, its levels can be changed at runtime by SetLevel
+	configured      *filteringFormatter
+	configuredMutex sync.Mutex
+)
+
+// Levels holds the root log level and the per subsystem log levels
+type Levels struct {
+	Level  string            `json:"level"`
+	Levels map[string]string `json:"levels"`
+}
+
+// Configure configures the global logger with the format, the root and per subsystem levels, and the sampling of
+// high-volume debug and trace logs
+func Configure(logConfig config.Log) {
+	rootLevel := parseLevel(logConfig.Level, log.InfoLevel)
+	levels := make(map[string]log.Level, len(logConfig.Levels))
+	for subsystem, level := range logConfig.Levels {
+		levels[strings.ToLower(subsystem)] = parseLevel(level, rootLevel)
+	}
+
+	formatter := &filteringFormatter{
+		formatter: newFormatter(logConfig.Format),
+		levels:    levels,
+		rootLevel: rootLevel,
+	}
+	if logConfig.Sampling.Enabled {
+		formatter.sampler = newSampler(logConfig.Sampling)
+	}
+
+	configuredMutex.Lock()
+	defer configuredMutex.Unlock()
+
+	configured = formatter
+	log.SetFormatter(formatter)
+	log.SetOutput(os.Stdout)
+	formatter.apply()
+}
+
+// GetLevels returns the current root and per subsystem log levels
+func GetLevels() Levels {
+	configuredMutex.Lock()
+	defer configuredMutex.Unlock()
+
+	if configured == nil {
+		return Levels{Level: log.GetLevel().String(), Levels: map[string]string{}}
+	}
+
+	return configured.getLevels()
+}
+
+// SetLevel changes the log level of the subsystem at runtime, or the root level if the subsystem is empty. An empty
+// level removes the subsystem's level so its entries are filtered by the root level again
+func SetLevel(subsystem, level string) error {
+	configuredMutex.Lock()
+	defer configuredMutex.Unlock()
+
+	if configured == nil {
+		return errors.Errorf("Logging is not configured")
+	}
+
+	subsystem = strings.ToLower(subsystem)
+	if level == "" {
+		if subsystem == "" {
+			return errors.Errorf("Level is required for the root logger")
+		}
+
+		configured.removeLevel(subsystem)
+	} else {
+		logLevel, err := log.ParseLevel(strings.ToLower(level))
+		if err != nil {
+			return err
+		}
+
+		configured.setLevel(subsystem, logLevel)
+	}
+
+	configured.apply()
+	return nil
+}
+
+// filteringFormatter wraps a formatter and drops entries whose level is not enabled for the subsystem the entry is
+// logged from, or which are dropped by the sampler
+type filteringFormatter struct {
+	formatter log.Formatter
+	levels    map[string]log.Level
+	mutex     sync.RWMutex
+	rootLevel log.Level
+	sampler   *sampler
+}
+
+func (f *filteringFormatter) Format(entry *log.Entry) ([]byte, error) {
+	if !f.isEnabled(entry) {
+		return nil, nil
+	}
+
+	if f.sampler != nil && entry.Level >= log.DebugLevel && !f.sampler.sample(entry) {
+		return nil, nil
+	}
+
+	return f.formatter.Format(entry)
+}
+
+// apply sets the logger level to the most verbose level so entries of a subsystem with a more verbose level than the
+// root level reach the formatter, which then filters the entries per subsystem
+func (f *filteringFormatter) apply() {
+	f.mutex.RLock()
+	hasSubsystemLevels := len(f.levels) != 0
+	f.mutex.RUnlock()
+
+	loggerLevel := f.getMaxLevel()
+	log.SetLevel(loggerLevel)
+	log.SetReportCaller(loggerLevel >= log.DebugLevel || hasSubsystemLevels || f.sampler != nil)
+}
+
+func (f *filteringFormatter) getLevels() Levels {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	levels := make(map[string]string, len(f.levels))
+	for subsystem, level := range f.levels {
+		levels[subsystem] = level.String()
+	}
+
+	return Levels{Level: f.rootLevel.String(), Levels: levels}
+}
+
+func (f *filteringFormatter) getMaxLevel() log.Level {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	maxLevel := f.rootLevel
+	for _, level := range f.levels {
+		if level > maxLevel {
+			maxLevel = level
+		}
+	}
+
+	return maxLevel
+}
+
+func (f *filteringFormatter) removeLevel(subsystem string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	delete(f.levels, subsystem)
+}
+
+func (f *filteringFormatter) setLevel(subsystem string, level log.Level) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if subsystem == "" {
+		f.rootLevel = level
+	} else {
+		f.levels[subsystem] = level
+	}
+}
+
+func (f *filteringFormatter) isEnabled(entry *log.Entry) bool {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	level := f.rootLevel
+	if entry.HasCaller() {
+		if subsystemLevel, ok := f.levels[getSubsystem(entry.Caller.File)]; ok {
+			level = subsystemLevel
+		}
+	}
+
+	return entry.Level <= level
+}
+
+// sampler logs the first initial entries from the same call site in each interval, and every thereafter-th entry
+// after that
+type sampler struct {
+	counts     map[string]int
+	initial    int
+	interval   time.Duration
+	mutex      sync.Mutex
+	resetAt    time.Time
+	thereafter int
+}
+
+func (s *sampler) sample(entry *log.Entry) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !entry.Time.Before(s.resetAt) {
+		s.counts = make(map[string]int)
+		s.resetAt = entry.Time.Add(s.interval)
+	}
+
+	key := entry.Message
+	if entry.HasCaller() {
+		key = fmt.Sprintf("%s:%d", entry.Caller.File, entry.Caller.Line)
+	}
+
+	s.counts[key]++
+	count := s.counts[key]
+	if count <= s.initial {
+		return true
+	}
+
+	return s.thereafter > 0 && (count-s.initial)%s.thereafter == 0
+}
+
+func newSampler(samplingConfig config.LogSampling) *sampler {
+	return &sampler{
+		counts:     make(map[string]int),
+		initial:    samplingConfig.Initial,
+		interval:   samplingConfig.Interval,
+		thereafter: samplingConfig.Thereafter,
+	}
+}
+
+func callerPrettyfier(frame *runtime.Frame) (function string, file string) {
+	parts := strings.Split(frame.File, moduleName)
+	relativeFilepath := parts[len(parts)-1]
+	// remove function name, show file path relative to project root
+	return "", fmt.Sprintf("%s:%d", relativeFilepath, frame.Line)
+}
+
+// getSubsystem returns the top level package under app the file belongs to, e.g., "persistence" for
+// "/hedera-mirror-rosetta/app/persistence/account.go"
+func getSubsystem(file string) string {
+	index := strings.LastIndex(file, appDir)
+	if index == -1 {
+		return ""
+	}
+
+	relativePath := file[index+len(appDir):]
+	if index = strings.Index(relativePath, "/"); index == -1 {
+		return ""
+	}
+
+	return relativePath[:index]
+}
+
+func newFormatter(format string) log.Formatter {
+	if strings.ToLower(format) == FormatJson {
+		return &log.JSONFormatter{CallerPrettyfier: callerPrettyfier}
+	}
+
+	return &log.TextFormatter{ // Use logfmt for easy parsing by Loki
+		CallerPrettyfier: callerPrettyfier,
+		DisableColors:    true,
+		FullTimestamp:    true,
+	}
+}
+
+func parseLevel(level string, defaultLevel log.Level) log.Level {
+	logLevel, err := log.ParseLevel(strings.ToLower(level))
+	if err != nil {
+		// if invalid, use the default
+		return defaultLevel
+	}
+
+	return logLevel
+}
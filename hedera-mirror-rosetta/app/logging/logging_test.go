@@ -0,0 +1,307 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	middlewareFile  = "/build/hedera-mirror-rosetta/app/middleware/trace.go"
+	persistenceFile = "/build/hedera-mirror-rosetta/app/persistence/account.go"
+	servicesFile    = "/build/hedera-mirror-rosetta/app/services/construction/common.go"
+)
+
+func TestConfigureJsonFormat(t *testing.T) {
+	defer resetLogger()
+	Configure(config.Log{Format: FormatJson, Level: "info"})
+
+	buf := bytes.NewBuffer(nil)
+	log.SetOutput(buf)
+	log.Info("hello")
+
+	var data map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &data))
+	assert.Equal(t, "hello", data["msg"])
+	assert.Equal(t, "info", data["level"])
+}
+
+func TestConfigureTextFormat(t *testing.T) {
+	defer resetLogger()
+	Configure(config.Log{Level: "info"})
+
+	buf := bytes.NewBuffer(nil)
+	log.SetOutput(buf)
+	log.Info("hello")
+	log.Debug("debug")
+
+	assert.Contains(t, buf.String(), "level=info msg=hello")
+	assert.NotContains(t, buf.String(), "debug")
+}
+
+func TestConfigureLoggerLevel(t *testing.T) {
+	tests := []struct {
+		name         string
+		logConfig    config.Log
+		expected     log.Level
+		reportCaller bool
+	}{
+		{name: "default", logConfig: config.Log{Level: "info"}, expected: log.InfoLevel},
+		{name: "invalid", logConfig: config.Log{Level: "foobar"}, expected: log.InfoLevel},
+		{name: "debug", logConfig: config.Log{Level: "DEBUG"}, expected: log.DebugLevel, reportCaller: true},
+		{
+			name: "subsystem more verbose",
+			logConfig: config.Log{
+				Level:  "warn",
+				Levels: map[string]string{"persistence": "trace", "services": "error"},
+			},
+			expected:     log.TraceLevel,
+			reportCaller: true,
+		},
+		{
+			name:         "subsystem less verbose",
+			logConfig:    config.Log{Level: "info", Levels: map[string]string{"persistence": "error"}},
+			expected:     log.InfoLevel,
+			reportCaller: true,
+		},
+		{
+			name:         "sampling",
+			logConfig:    config.Log{Level: "info", Sampling: config.LogSampling{Enabled: true}},
+			expected:     log.InfoLevel,
+			reportCaller: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer resetLogger()
+			Configure(tt.logConfig)
+			assert.Equal(t, tt.expected, log.GetLevel())
+			assert.Equal(t, tt.reportCaller, log.StandardLogger().ReportCaller)
+		})
+	}
+}
+
+func TestSetLevel(t *testing.T) {
+	tests := []struct {
+		name           string
+		subsystem      string
+		level          string
+		expectedLevels Levels
+		expected       log.Level
+		reportCaller   bool
+	}{
+		{
+			name:           "root",
+			level:          "warn",
+			expectedLevels: Levels{Level: "warning", Levels: map[string]string{"services": "error"}},
+			expected:       log.WarnLevel,
+			reportCaller:   true,
+		},
+		{
+			name:           "root more verbose",
+			level:          "Debug",
+			expectedLevels: Levels{Level: "debug", Levels: map[string]string{"services": "error"}},
+			expected:       log.DebugLevel,
+			reportCaller:   true,
+		},
+		{
+			name:      "subsystem",
+			subsystem: "Persistence",
+			level:     "trace",
+			expectedLevels: Levels{
+				Level:  "info",
+				Levels: map[string]string{"persistence": "trace", "services": "error"},
+			},
+			expected:     log.TraceLevel,
+			reportCaller: true,
+		},
+		{
+			name:           "remove subsystem",
+			subsystem:      "services",
+			expectedLevels: Levels{Level: "info", Levels: map[string]string{}},
+			expected:       log.InfoLevel,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer resetLogger()
+			Configure(config.Log{Level: "info", Levels: map[string]string{"services": "error"}})
+
+			assert.NoError(t, SetLevel(tt.subsystem, tt.level))
+			assert.Equal(t, tt.expectedLevels, GetLevels())
+			assert.Equal(t, tt.expected, log.GetLevel())
+			assert.Equal(t, tt.reportCaller, log.StandardLogger().ReportCaller)
+		})
+	}
+}
+
+func TestSetLevelError(t *testing.T) {
+	tests := []struct {
+		name      string
+		subsystem string
+		level     string
+	}{
+		{name: "invalid level", level: "foobar"},
+		{name: "invalid subsystem level", subsystem: "persistence", level: "foobar"},
+		{name: "empty root level"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer resetLogger()
+			Configure(config.Log{Level: "info"})
+
+			assert.Error(t, SetLevel(tt.subsystem, tt.level))
+			assert.Equal(t, Levels{Level: "info", Levels: map[string]string{}}, GetLevels())
+			assert.Equal(t, log.InfoLevel, log.GetLevel())
+		})
+	}
+}
+
+func TestSetLevelNotConfigured(t *testing.T) {
+	defer resetLogger()
+	resetLogger()
+
+	assert.Error(t, SetLevel("", "debug"))
+	assert.Equal(t, Levels{Level: "info", Levels: map[string]string{}}, GetLevels())
+}
+
+func TestFilteringFormatterSubsystemLevels(t *testing.T) {
+	formatter := &filteringFormatter{
+		formatter: &log.TextFormatter{DisableTimestamp: true},
+		levels:    map[string]log.Level{"persistence": log.DebugLevel, "services": log.ErrorLevel},
+		rootLevel: log.InfoLevel,
+	}
+
+	tests := []struct {
+		file     string
+		level    log.Level
+		expected bool
+	}{
+		{file: persistenceFile, level: log.DebugLevel, expected: true},
+		{file: persistenceFile, level: log.TraceLevel},
+		{file: servicesFile, level: log.ErrorLevel, expected: true},
+		{file: servicesFile, level: log.WarnLevel},
+		{file: middlewareFile, level: log.InfoLevel, expected: true},
+		{file: middlewareFile, level: log.DebugLevel},
+		{file: "", level: log.InfoLevel, expected: true},
+		{file: "", level: log.DebugLevel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file+" "+tt.level.String(), func(t *testing.T) {
+			data, err := formatter.Format(newEntry(tt.file, 10, tt.level))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, len(data) != 0)
+		})
+	}
+}
+
+func TestFilteringFormatterSampling(t *testing.T) {
+	formatter := &filteringFormatter{
+		formatter: &log.TextFormatter{DisableTimestamp: true},
+		levels:    map[string]log.Level{},
+		rootLevel: log.DebugLevel,
+		sampler:   newSampler(config.LogSampling{Initial: 2, Interval: time.Minute, Thereafter: 3}),
+	}
+
+	logged := 0
+	for i := 0; i < 10; i++ {
+		if data, _ := formatter.Format(newEntry(persistenceFile, 10, log.DebugLevel)); len(data) != 0 {
+			logged++
+		}
+	}
+	// first 2, then the 5th and 8th
+	assert.Equal(t, 4, logged)
+
+	// info level entries are never sampled
+	for i := 0; i < 10; i++ {
+		data, _ := formatter.Format(newEntry(persistenceFile, 10, log.InfoLevel))
+		assert.NotEmpty(t, data)
+	}
+
+	// entries from a different call site are counted separately
+	data, _ := formatter.Format(newEntry(persistenceFile, 20, log.DebugLevel))
+	assert.NotEmpty(t, data)
+}
+
+func TestSamplerReset(t *testing.T) {
+	s := newSampler(config.LogSampling{Initial: 1, Interval: time.Second})
+	entry := newEntry(persistenceFile, 10, log.DebugLevel)
+
+	assert.True(t, s.sample(entry))
+	assert.False(t, s.sample(entry))
+
+	entry.Time = entry.Time.Add(time.Second)
+	assert.True(t, s.sample(entry))
+}
+
+func TestGetSubsystem(t *testing.T) {
+	tests := []struct {
+		file     string
+		expected string
+	}{
+		{file: persistenceFile, expected: "persistence"},
+		{file: servicesFile, expected: "services"},
+		{file: "/build/hedera-mirror-rosetta/app/main.go"},
+		{file: "/build/hedera-mirror-rosetta/main.go"},
+		{file: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			assert.Equal(t, tt.expected, getSubsystem(tt.file))
+		})
+	}
+}
+
+func newEntry(file string, line int, level log.Level) *log.Entry {
+	logger := log.New()
+	logger.SetLevel(log.TraceLevel)
+	entry := log.NewEntry(logger)
+	entry.Level = level
+	entry.Message = "message"
+	entry.Time = time.Unix(100, 0)
+	if file != "" {
+		logger.ReportCaller = true
+		entry.Caller = &runtime.Frame{File: file, Line: line}
+	}
+	return entry
+}
+
+func resetLogger() {
+	configured = nil
+	log.SetFormatter(&log.TextFormatter{})
+	log.SetLevel(log.InfoLevel)
+	log.SetOutput(os.Stdout)
+	log.SetReportCaller(false)
+}
@@ -0,0 +1,89 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/server"
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/redaction"
+)
+
+const accountBalanceRoute = "AccountBalance"
+
+// accountController serves the account API with the rosetta-sdk-go AccountAPIController, except the /account/balance
+// responses are encoded with the sensitive keys stripped from their metadata by the redactor
+type accountController struct {
+	server.Router
+	asserter *asserter.Asserter
+	redactor *redaction.Redactor
+	service  server.AccountAPIServicer
+}
+
+// NewAccountController constructs a new account controller. It's the rosetta-sdk-go AccountAPIController if the
+// redactor is nil
+func NewAccountController(
+	service server.AccountAPIServicer,
+	asserter *asserter.Asserter,
+	redactor *redaction.Redactor,
+) server.Router {
+	router := server.NewAccountAPIController(service, asserter)
+	if redactor == nil {
+		return router
+	}
+	return &accountController{Router: router, asserter: asserter, redactor: redactor, service: service}
+}
+
+// Routes returns the account controller routes
+func (c *accountController) Routes() server.Routes {
+	routes := c.Router.Routes()
+	for i := range routes {
+		if routes[i].Name == accountBalanceRoute {
+			routes[i].HandlerFunc = c.AccountBalance
+		}
+	}
+	return routes
+}
+
+// AccountBalance handles the /account/balance requests
+func (c *accountController) AccountBalance(w http.ResponseWriter, r *http.Request) {
+	request := &rTypes.AccountBalanceRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		server.EncodeJSONResponse(&rTypes.Error{Message: err.Error()}, http.StatusInternalServerError, w)
+		return
+	}
+
+	if err := c.asserter.AccountBalanceRequest(request); err != nil {
+		server.EncodeJSONResponse(&rTypes.Error{Message: err.Error()}, http.StatusInternalServerError, w)
+		return
+	}
+
+	response, rErr := c.service.AccountBalance(r.Context(), request)
+	if rErr != nil {
+		server.EncodeJSONResponse(rErr, http.StatusInternalServerError, w)
+		return
+	}
+
+	writeJSONResponse(w, response, c.redactor)
+}
@@ -0,0 +1,182 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package middleware
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/coinbase/rosetta-sdk-go/server"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/logging"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	authorizationHeader = "Authorization"
+	bearerPrefix        = "Bearer "
+	logLevelPath        = "/admin/log/level"
+)
+
+type adminError struct {
+	Message string `json:"message"`
+}
+
+// logLevelRequest changes the log level of the subsystem, or the root level if the subsystem is empty
+type logLevelRequest struct {
+	Level     string `json:"level"`
+	Subsystem string `json:"subsystem"`
+}
+
+// adminController serves the admin endpoints, authenticated by the configured bearer token
+type adminController struct {
+	errorBudget *ErrorBudget
+	inFlight    *InFlightRequests
+	info        http.HandlerFunc
+	token       []byte
+}
+
+// NewAdminController creates a new admin controller with the admin config. The SLO summary is served if the error
+// budget isn't nil, the in-flight requests are served if the tracker isn't nil, and the build info is served if the
+// info handler isn't nil
+func NewAdminController(
+	adminConfig config.Admin,
+	errorBudget *ErrorBudget,
+	inFlight *InFlightRequests,
+	info http.HandlerFunc,
+) server.Router {
+	return &adminController{
+		errorBudget: errorBudget,
+		inFlight:    inFlight,
+		info:        info,
+		token:       []byte(adminConfig.Token),
+	}
+}
+
+// Routes returns the admin controller routes
+func (c *adminController) Routes() server.Routes {
+	routes := server.Routes{
+		{
+			"getLogLevel",
+			"GET",
+			logLevelPath,
+			c.authenticate(c.GetLogLevel),
+		},
+		{
+			"setLogLevel",
+			"PUT",
+			logLevelPath,
+			c.authenticate(c.SetLogLevel),
+		},
+	}
+
+	if c.errorBudget != nil {
+		routes = append(routes, server.Route{
+			"getSlo",
+			"GET",
+			sloPath,
+			c.authenticate(c.GetSlo),
+		})
+	}
+
+	if c.inFlight != nil {
+		routes = append(routes, server.Route{
+			"getInFlightRequests",
+			"GET",
+			inFlightPath,
+			c.authenticate(c.GetInFlightRequests),
+		})
+	}
+
+	if c.info != nil {
+		routes = append(routes, server.Route{
+			"getInfo",
+			"GET",
+			infoPath,
+			c.authenticate(c.info),
+		})
+	}
+
+	return routes
+}
+
+// GetInFlightRequests serves the requests currently executing, the longest running first
+func (c *adminController) GetInFlightRequests(w http.ResponseWriter, _ *http.Request) {
+	writeAdminResponse(w, http.StatusOK, c.inFlight.List())
+}
+
+// GetLogLevel serves the current root and per subsystem log levels
+func (c *adminController) GetLogLevel(w http.ResponseWriter, _ *http.Request) {
+	writeAdminResponse(w, http.StatusOK, logging.GetLevels())
+}
+
+// GetSlo serves the availability of each endpoint against its target over each window of the error budget
+func (c *adminController) GetSlo(w http.ResponseWriter, _ *http.Request) {
+	writeAdminResponse(w, http.StatusOK, c.errorBudget.Summary())
+}
+
+// SetLogLevel changes the log level of a subsystem or the root log level, and serves the log levels after the change
+func (c *adminController) SetLogLevel(w http.ResponseWriter, r *http.Request) {
+	var request logLevelRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeAdminResponse(w, http.StatusBadRequest, adminError{Message: "Invalid request body: " + err.Error()})
+		return
+	}
+
+	if err := logging.SetLevel(request.Subsystem, request.Level); err != nil {
+		writeAdminResponse(w, http.StatusBadRequest, adminError{Message: err.Error()})
+		return
+	}
+
+	log.Warnf("Changed log level of subsystem '%s' to '%s'", request.Subsystem, request.Level)
+	writeAdminResponse(w, http.StatusOK, logging.GetLevels())
+}
+
+func (c *adminController) authenticate(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !isAuthorized(r, c.token) {
+			log.Warnf("Rejected unauthorized %s %s", r.Method, r.URL.Path)
+			writeAdminResponse(w, http.StatusUnauthorized, adminError{Message: "Unauthorized"})
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// isAuthorized returns true if the request carries the admin token as the bearer token. No request is authorized if
+// the admin token is empty
+func isAuthorized(r *http.Request, adminToken []byte) bool {
+	authorization := r.Header.Get(authorizationHeader)
+	token := []byte(strings.TrimPrefix(authorization, bearerPrefix))
+	return len(adminToken) != 0 && strings.HasPrefix(authorization, bearerPrefix) &&
+		subtle.ConstantTimeCompare(token, adminToken) == 1
+}
+
+func writeAdminResponse(w http.ResponseWriter, status int, response interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Errorf("Failed to encode admin response: %s", err)
+	}
+}
@@ -0,0 +1,187 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/coinbase/rosetta-sdk-go/server"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/logging"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const adminToken = "foobar"
+
+func TestAdminGetLogLevel(t *testing.T) {
+	// given
+	configureLogging(t, config.Log{Level: "info", Levels: map[string]string{"persistence": "debug"}})
+	router := server.NewRouter(NewAdminController(config.Admin{Enabled: true, Token: adminToken}, nil, nil, nil))
+	expected := logging.Levels{Level: "info", Levels: map[string]string{"persistence": "debug"}}
+
+	// when
+	recorder := serveAdminRequest(router, "GET", "Bearer "+adminToken, "")
+
+	// then
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, expected, getLogLevels(t, recorder))
+}
+
+func TestAdminSetLogLevel(t *testing.T) {
+	var tests = []struct {
+		name     string
+		body     string
+		expected logging.Levels
+		level    log.Level
+	}{
+		{
+			name:     "root",
+			body:     `{"level": "debug"}`,
+			expected: logging.Levels{Level: "debug", Levels: map[string]string{"services": "warning"}},
+			level:    log.DebugLevel,
+		},
+		{
+			name: "subsystem",
+			body: `{"level": "TRACE", "subsystem": "Persistence"}`,
+			expected: logging.Levels{
+				Level:  "info",
+				Levels: map[string]string{"persistence": "trace", "services": "warning"},
+			},
+			level: log.TraceLevel,
+		},
+		{
+			name:     "remove subsystem",
+			body:     `{"subsystem": "services"}`,
+			expected: logging.Levels{Level: "info", Levels: map[string]string{}},
+			level:    log.InfoLevel,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			configureLogging(t, config.Log{Level: "info", Levels: map[string]string{"services": "warn"}})
+			router := server.NewRouter(NewAdminController(config.Admin{Enabled: true, Token: adminToken}, nil, nil, nil))
+
+			// when
+			recorder := serveAdminRequest(router, "PUT", "Bearer "+adminToken, tt.body)
+
+			// then
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, tt.expected, getLogLevels(t, recorder))
+			assert.Equal(t, tt.expected, logging.GetLevels())
+			assert.Equal(t, tt.level, log.GetLevel())
+		})
+	}
+}
+
+func TestAdminSetLogLevelBadRequest(t *testing.T) {
+	var tests = []struct {
+		name string
+		body string
+	}{
+		{name: "invalid json", body: "foobar"},
+		{name: "invalid level", body: `{"level": "foobar"}`},
+		{name: "root level missing", body: `{}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			configureLogging(t, config.Log{Level: "info"})
+			router := server.NewRouter(NewAdminController(config.Admin{Enabled: true, Token: adminToken}, nil, nil, nil))
+
+			// when
+			recorder := serveAdminRequest(router, "PUT", "Bearer "+adminToken, tt.body)
+
+			// then
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), "message")
+			assert.Equal(t, logging.Levels{Level: "info", Levels: map[string]string{}}, logging.GetLevels())
+		})
+	}
+}
+
+func TestAdminUnauthorized(t *testing.T) {
+	var tests = []struct {
+		name          string
+		authorization string
+		token         string
+	}{
+		{name: "missing", token: adminToken},
+		{name: "wrong token", authorization: "Bearer foo", token: adminToken},
+		{name: "not bearer", authorization: adminToken, token: adminToken},
+		{name: "token not configured", authorization: "Bearer "},
+	}
+
+	for _, tt := range tests {
+		for _, method := range []string{"GET", "PUT"} {
+			t.Run(tt.name+" "+method, func(t *testing.T) {
+				// given
+				configureLogging(t, config.Log{Level: "info"})
+				router := server.NewRouter(NewAdminController(config.Admin{Enabled: true, Token: tt.token}, nil, nil, nil))
+
+				// when
+				recorder := serveAdminRequest(router, method, tt.authorization, `{"level": "debug"}`)
+
+				// then
+				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+				assert.Equal(t, log.InfoLevel, log.GetLevel())
+			})
+		}
+	}
+}
+
+// configureLogging configures the global logger and restores the previous logger settings after the test
+func configureLogging(t *testing.T, logConfig config.Log) {
+	logger := log.StandardLogger()
+	formatter, level, output, reportCaller := logger.Formatter, logger.GetLevel(), logger.Out, logger.ReportCaller
+	t.Cleanup(func() {
+		log.SetFormatter(formatter)
+		log.SetLevel(level)
+		log.SetOutput(output)
+		log.SetReportCaller(reportCaller)
+	})
+	logging.Configure(logConfig)
+}
+
+func getLogLevels(t *testing.T, recorder *httptest.ResponseRecorder) logging.Levels {
+	var actual logging.Levels
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &actual))
+	assert.Contains(t, recorder.Header().Get("Content-Type"), "application/json")
+	return actual
+}
+
+func serveAdminRequest(router http.Handler, method, authorization, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, "http://localhost"+logLevelPath, strings.NewReader(body))
+	if authorization != "" {
+		request.Header.Set(authorizationHeader, authorization)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
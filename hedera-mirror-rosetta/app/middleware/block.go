@@ -0,0 +1,271 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/server"
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/redaction"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	excludeFieldCurrencyMetadata    = "currency_metadata"
+	excludeFieldOperationMetadata   = "operation_metadata"
+	excludeFieldTransactionMetadata = "transaction_metadata"
+)
+
+// requestMetadata is the optional metadata of the block API requests, which isn't in the rosetta spec. The fields in
+// exclude_fields are left out of the response to cut its size for clients that only need the amounts and the accounts
+type requestMetadata struct {
+	Metadata *struct {
+		ExcludeFields []string `json:"exclude_fields"`
+	} `json:"metadata,omitempty"`
+}
+
+// getFieldMask returns the field mask of the request metadata, or an error if a field can't be excluded
+func (m requestMetadata) getFieldMask() (fieldMask, *rTypes.Error) {
+	mask := fieldMask{}
+	if m.Metadata == nil {
+		return mask, nil
+	}
+
+	for _, field := range m.Metadata.ExcludeFields {
+		switch field {
+		case excludeFieldCurrencyMetadata:
+			mask.currencyMetadata = true
+		case excludeFieldOperationMetadata:
+			mask.operationMetadata = true
+		case excludeFieldTransactionMetadata:
+			mask.transactionMetadata = true
+		default:
+			return mask, errors.AddErrorDetails(errors.ErrInvalidArgument, "exclude_fields", field)
+		}
+	}
+
+	return mask, nil
+}
+
+// blockController serves the block API same as the rosetta-sdk-go BlockAPIController, except the metadata of the
+// fields in the field mask of the request is excluded, and with the fast encoding the successful responses are encoded
+// with the blockEncoder to cut the encoding time and the allocations of large blocks. The responses are always encoded
+// with the blockEncoder if redaction is enabled, since it strips the redacted keys while encoding the metadata
+type blockController struct {
+	asserter     *asserter.Asserter
+	fastEncoding bool
+	redactor     *redaction.Redactor
+	service      server.BlockAPIServicer
+}
+
+// NewBlockController constructs a new block controller, with the fast response encoding if fastEncoding is true
+func NewBlockController(
+	service server.BlockAPIServicer,
+	asserter *asserter.Asserter,
+	fastEncoding bool,
+	redactor *redaction.Redactor,
+) server.Router {
+	return &blockController{asserter: asserter, fastEncoding: fastEncoding, redactor: redactor, service: service}
+}
+
+// Routes returns the block controller routes
+func (c *blockController) Routes() server.Routes {
+	return server.Routes{
+		{
+			"Block",
+			http.MethodPost,
+			"/block",
+			c.Block,
+		},
+		{
+			"BlockTransaction",
+			http.MethodPost,
+			"/block/transaction",
+			c.BlockTransaction,
+		},
+	}
+}
+
+// Block handles the /block requests
+func (c *blockController) Block(w http.ResponseWriter, r *http.Request) {
+	request := &struct {
+		rTypes.BlockRequest
+		requestMetadata
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		server.EncodeJSONResponse(&rTypes.Error{Message: err.Error()}, http.StatusInternalServerError, w)
+		return
+	}
+
+	if err := c.asserter.BlockRequest(&request.BlockRequest); err != nil {
+		server.EncodeJSONResponse(&rTypes.Error{Message: err.Error()}, http.StatusInternalServerError, w)
+		return
+	}
+
+	mask, rErr := request.getFieldMask()
+	if rErr != nil {
+		server.EncodeJSONResponse(rErr, http.StatusInternalServerError, w)
+		return
+	}
+
+	response, rErr := c.service.Block(r.Context(), &request.BlockRequest)
+	if rErr != nil {
+		server.EncodeJSONResponse(rErr, http.StatusInternalServerError, w)
+		return
+	}
+
+	if c.fastEncoding || c.redactor != nil {
+		encoder := getBlockEncoder()
+		defer putBlockEncoder(encoder)
+		encoder.mask = mask
+		encoder.redactor = c.redactor
+		if err := encoder.encodeBlockResponse(response); err == nil {
+			writeEncodedResponse(w, encoder.buf)
+			return
+		}
+	}
+
+	writeJSONResponse(w, maskBlockResponse(response, mask), c.redactor)
+}
+
+// BlockTransaction handles the /block/transaction requests
+func (c *blockController) BlockTransaction(w http.ResponseWriter, r *http.Request) {
+	request := &struct {
+		rTypes.BlockTransactionRequest
+		requestMetadata
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		server.EncodeJSONResponse(&rTypes.Error{Message: err.Error()}, http.StatusInternalServerError, w)
+		return
+	}
+
+	if err := c.asserter.BlockTransactionRequest(&request.BlockTransactionRequest); err != nil {
+		server.EncodeJSONResponse(&rTypes.Error{Message: err.Error()}, http.StatusInternalServerError, w)
+		return
+	}
+
+	mask, rErr := request.getFieldMask()
+	if rErr != nil {
+		server.EncodeJSONResponse(rErr, http.StatusInternalServerError, w)
+		return
+	}
+
+	response, rErr := c.service.BlockTransaction(r.Context(), &request.BlockTransactionRequest)
+	if rErr != nil {
+		server.EncodeJSONResponse(rErr, http.StatusInternalServerError, w)
+		return
+	}
+
+	if c.fastEncoding || c.redactor != nil {
+		encoder := getBlockEncoder()
+		defer putBlockEncoder(encoder)
+		encoder.mask = mask
+		encoder.redactor = c.redactor
+		if err := encoder.encodeBlockTransactionResponse(response); err == nil {
+			writeEncodedResponse(w, encoder.buf)
+			return
+		}
+	}
+
+	writeJSONResponse(w, maskBlockTransactionResponse(response, mask), c.redactor)
+}
+
+// maskBlockResponse returns a copy of the block response without the metadata of the fields in the mask. The
+// response itself isn't modified since it can be cached
+func maskBlockResponse(response *rTypes.BlockResponse, mask fieldMask) *rTypes.BlockResponse {
+	if response == nil || response.Block == nil || mask == (fieldMask{}) {
+		return response
+	}
+
+	block := *response.Block
+	if block.Transactions != nil {
+		block.Transactions = make([]*rTypes.Transaction, 0, len(response.Block.Transactions))
+		for _, transaction := range response.Block.Transactions {
+			block.Transactions = append(block.Transactions, maskTransaction(transaction, mask))
+		}
+	}
+
+	return &rTypes.BlockResponse{Block: &block, OtherTransactions: response.OtherTransactions}
+}
+
+// maskBlockTransactionResponse returns a copy of the block transaction response without the metadata of the fields in
+// the mask
+func maskBlockTransactionResponse(
+	response *rTypes.BlockTransactionResponse,
+	mask fieldMask,
+) *rTypes.BlockTransactionResponse {
+	if response == nil || mask == (fieldMask{}) {
+		return response
+	}
+
+	return &rTypes.BlockTransactionResponse{Transaction: maskTransaction(response.Transaction, mask)}
+}
+
+// maskTransaction returns a copy of the transaction without the metadata of the fields in the mask
+func maskTransaction(transaction *rTypes.Transaction, mask fieldMask) *rTypes.Transaction {
+	if transaction == nil {
+		return nil
+	}
+
+	masked := *transaction
+	if mask.transactionMetadata {
+		masked.Metadata = nil
+	}
+
+	if transaction.Operations != nil {
+		masked.Operations = make([]*rTypes.Operation, 0, len(transaction.Operations))
+		for _, operation := range transaction.Operations {
+			if operation == nil {
+				masked.Operations = append(masked.Operations, nil)
+				continue
+			}
+
+			maskedOperation := *operation
+			if mask.operationMetadata {
+				maskedOperation.Metadata = nil
+			}
+
+			if mask.currencyMetadata && operation.Amount != nil && operation.Amount.Currency != nil {
+				amount := *operation.Amount
+				currency := *amount.Currency
+				currency.Metadata = nil
+				amount.Currency = &currency
+				maskedOperation.Amount = &amount
+			}
+
+			masked.Operations = append(masked.Operations, &maskedOperation)
+		}
+	}
+
+	return &masked
+}
+
+func writeEncodedResponse(w http.ResponseWriter, body []byte) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		log.Debugf("Failed to write the response: %s", err)
+	}
+}
@@ -0,0 +1,452 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package middleware
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"sync"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/redaction"
+)
+
+var blockEncoderPool = sync.Pool{
+	New: func() interface{} {
+		return &blockEncoder{}
+	},
+}
+
+// fieldMask holds the heavy fields the client asked to exclude from the block API responses
+type fieldMask struct {
+	currencyMetadata    bool
+	operationMetadata   bool
+	transactionMetadata bool
+}
+
+// blockEncoder encodes the block API responses to the same JSON as encoding/json, by appending the known fields of the
+// rosetta types to a reused buffer instead of reflecting over them. Only the values in the metadata maps which aren't
+// strings, integers, or booleans, and the strings which need escaping, are encoded with encoding/json. The metadata of
+// the fields in the mask is omitted, and the keys stripped by the redactor are left out of the metadata
+type blockEncoder struct {
+	buf      []byte
+	keys     []string
+	mask     fieldMask
+	redactor *redaction.Redactor
+}
+
+func getBlockEncoder() *blockEncoder {
+	e := blockEncoderPool.Get().(*blockEncoder)
+	e.buf = e.buf[:0]
+	e.mask = fieldMask{}
+	e.redactor = nil
+	return e
+}
+
+func putBlockEncoder(e *blockEncoder) {
+	blockEncoderPool.Put(e)
+}
+
+// encodeBlockResponse encodes the block response followed by a newline, same as json.Encoder
+func (e *blockEncoder) encodeBlockResponse(response *rTypes.BlockResponse) error {
+	if response == nil {
+		e.buf = append(e.buf, "null\n"...)
+		return nil
+	}
+
+	e.buf = append(e.buf, '{')
+	first := true
+	if response.Block != nil {
+		e.buf = append(e.buf, `"block":`...)
+		if err := e.writeBlock(response.Block); err != nil {
+			return err
+		}
+		first = false
+	}
+
+	if len(response.OtherTransactions) != 0 {
+		if !first {
+			e.buf = append(e.buf, ',')
+		}
+		e.buf = append(e.buf, `"other_transactions":[`...)
+		for i, transactionIdentifier := range response.OtherTransactions {
+			if i != 0 {
+				e.buf = append(e.buf, ',')
+			}
+			e.writeTransactionIdentifier(transactionIdentifier)
+		}
+		e.buf = append(e.buf, ']')
+	}
+
+	e.buf = append(e.buf, "}\n"...)
+	return nil
+}
+
+// encodeBlockTransactionResponse encodes the block transaction response followed by a newline, same as json.Encoder
+func (e *blockEncoder) encodeBlockTransactionResponse(response *rTypes.BlockTransactionResponse) error {
+	if response == nil {
+		e.buf = append(e.buf, "null\n"...)
+		return nil
+	}
+
+	e.buf = append(e.buf, `{"transaction":`...)
+	if err := e.writeTransaction(response.Transaction); err != nil {
+		return err
+	}
+
+	e.buf = append(e.buf, "}\n"...)
+	return nil
+}
+
+func (e *blockEncoder) writeBlock(block *rTypes.Block) error {
+	e.buf = append(e.buf, `{"block_identifier":`...)
+	e.writeBlockIdentifier(block.BlockIdentifier)
+	e.buf = append(e.buf, `,"parent_block_identifier":`...)
+	e.writeBlockIdentifier(block.ParentBlockIdentifier)
+	e.buf = append(e.buf, `,"timestamp":`...)
+	e.buf = strconv.AppendInt(e.buf, block.Timestamp, 10)
+	e.buf = append(e.buf, `,"transactions":`...)
+	if block.Transactions == nil {
+		e.buf = append(e.buf, "null"...)
+	} else {
+		e.buf = append(e.buf, '[')
+		for i, transaction := range block.Transactions {
+			if i != 0 {
+				e.buf = append(e.buf, ',')
+			}
+			if err := e.writeTransaction(transaction); err != nil {
+				return err
+			}
+		}
+		e.buf = append(e.buf, ']')
+	}
+
+	if err := e.writeMetadataField(block.Metadata); err != nil {
+		return err
+	}
+
+	e.buf = append(e.buf, '}')
+	return nil
+}
+
+func (e *blockEncoder) writeBlockIdentifier(blockIdentifier *rTypes.BlockIdentifier) {
+	if blockIdentifier == nil {
+		e.buf = append(e.buf, "null"...)
+		return
+	}
+
+	e.buf = append(e.buf, `{"index":`...)
+	e.buf = strconv.AppendInt(e.buf, blockIdentifier.Index, 10)
+	e.buf = append(e.buf, `,"hash":`...)
+	e.writeString(blockIdentifier.Hash)
+	e.buf = append(e.buf, '}')
+}
+
+func (e *blockEncoder) writeTransaction(transaction *rTypes.Transaction) error {
+	if transaction == nil {
+		e.buf = append(e.buf, "null"...)
+		return nil
+	}
+
+	e.buf = append(e.buf, `{"transaction_identifier":`...)
+	e.writeTransactionIdentifier(transaction.TransactionIdentifier)
+	e.buf = append(e.buf, `,"operations":`...)
+	if transaction.Operations == nil {
+		e.buf = append(e.buf, "null"...)
+	} else {
+		e.buf = append(e.buf, '[')
+		for i, operation := range transaction.Operations {
+			if i != 0 {
+				e.buf = append(e.buf, ',')
+			}
+			if err := e.writeOperation(operation); err != nil {
+				return err
+			}
+		}
+		e.buf = append(e.buf, ']')
+	}
+
+	if len(transaction.RelatedTransactions) != 0 {
+		e.buf = append(e.buf, `,"related_transactions":[`...)
+		for i, relatedTransaction := range transaction.RelatedTransactions {
+			if i != 0 {
+				e.buf = append(e.buf, ',')
+			}
+			if err := e.writeRelatedTransaction(relatedTransaction); err != nil {
+				return err
+			}
+		}
+		e.buf = append(e.buf, ']')
+	}
+
+	if !e.mask.transactionMetadata {
+		if err := e.writeMetadataField(transaction.Metadata); err != nil {
+			return err
+		}
+	}
+
+	e.buf = append(e.buf, '}')
+	return nil
+}
+
+func (e *blockEncoder) writeTransactionIdentifier(transactionIdentifier *rTypes.TransactionIdentifier) {
+	if transactionIdentifier == nil {
+		e.buf = append(e.buf, "null"...)
+		return
+	}
+
+	e.buf = append(e.buf, `{"hash":`...)
+	e.writeString(transactionIdentifier.Hash)
+	e.buf = append(e.buf, '}')
+}
+
+func (e *blockEncoder) writeOperation(operation *rTypes.Operation) error {
+	if operation == nil {
+		e.buf = append(e.buf, "null"...)
+		return nil
+	}
+
+	e.buf = append(e.buf, `{"operation_identifier":`...)
+	e.writeOperationIdentifier(operation.OperationIdentifier)
+	if len(operation.RelatedOperations) != 0 {
+		e.buf = append(e.buf, `,"related_operations":[`...)
+		for i, operationIdentifier := range operation.RelatedOperations {
+			if i != 0 {
+				e.buf = append(e.buf, ',')
+			}
+			e.writeOperationIdentifier(operationIdentifier)
+		}
+		e.buf = append(e.buf, ']')
+	}
+
+	e.buf = append(e.buf, `,"type":`...)
+	e.writeString(operation.Type)
+	if operation.Status != nil {
+		e.buf = append(e.buf, `,"status":`...)
+		e.writeString(*operation.Status)
+	}
+
+	if operation.Account != nil {
+		e.buf = append(e.buf, `,"account":`...)
+		if err := e.writeAccountIdentifier(operation.Account); err != nil {
+			return err
+		}
+	}
+
+	if operation.Amount != nil {
+		e.buf = append(e.buf, `,"amount":`...)
+		if err := e.writeAmount(operation.Amount); err != nil {
+			return err
+		}
+	}
+
+	if operation.CoinChange != nil {
+		e.buf = append(e.buf, `,"coin_change":{"coin_identifier":`...)
+		if operation.CoinChange.CoinIdentifier == nil {
+			e.buf = append(e.buf, "null"...)
+		} else {
+			e.buf = append(e.buf, `{"identifier":`...)
+			e.writeString(operation.CoinChange.CoinIdentifier.Identifier)
+			e.buf = append(e.buf, '}')
+		}
+		e.buf = append(e.buf, `,"coin_action":`...)
+		e.writeString(string(operation.CoinChange.CoinAction))
+		e.buf = append(e.buf, '}')
+	}
+
+	if !e.mask.operationMetadata {
+		if err := e.writeMetadataField(operation.Metadata); err != nil {
+			return err
+		}
+	}
+
+	e.buf = append(e.buf, '}')
+	return nil
+}
+
+func (e *blockEncoder) writeOperationIdentifier(operationIdentifier *rTypes.OperationIdentifier) {
+	if operationIdentifier == nil {
+		e.buf = append(e.buf, "null"...)
+		return
+	}
+
+	e.buf = append(e.buf, `{"index":`...)
+	e.buf = strconv.AppendInt(e.buf, operationIdentifier.Index, 10)
+	if operationIdentifier.NetworkIndex != nil {
+		e.buf = append(e.buf, `,"network_index":`...)
+		e.buf = strconv.AppendInt(e.buf, *operationIdentifier.NetworkIndex, 10)
+	}
+	e.buf = append(e.buf, '}')
+}
+
+func (e *blockEncoder) writeAccountIdentifier(accountIdentifier *rTypes.AccountIdentifier) error {
+	e.buf = append(e.buf, `{"address":`...)
+	e.writeString(accountIdentifier.Address)
+	if subAccount := accountIdentifier.SubAccount; subAccount != nil {
+		e.buf = append(e.buf, `,"sub_account":{"address":`...)
+		e.writeString(subAccount.Address)
+		if err := e.writeMetadataField(subAccount.Metadata); err != nil {
+			return err
+		}
+		e.buf = append(e.buf, '}')
+	}
+
+	if err := e.writeMetadataField(accountIdentifier.Metadata); err != nil {
+		return err
+	}
+
+	e.buf = append(e.buf, '}')
+	return nil
+}
+
+func (e *blockEncoder) writeAmount(amount *rTypes.Amount) error {
+	e.buf = append(e.buf, `{"value":`...)
+	e.writeString(amount.Value)
+	e.buf = append(e.buf, `,"currency":`...)
+	if currency := amount.Currency; currency == nil {
+		e.buf = append(e.buf, "null"...)
+	} else {
+		e.buf = append(e.buf, `{"symbol":`...)
+		e.writeString(currency.Symbol)
+		e.buf = append(e.buf, `,"decimals":`...)
+		e.buf = strconv.AppendInt(e.buf, int64(currency.Decimals), 10)
+		if !e.mask.currencyMetadata {
+			if err := e.writeMetadataField(currency.Metadata); err != nil {
+				return err
+			}
+		}
+		e.buf = append(e.buf, '}')
+	}
+
+	if err := e.writeMetadataField(amount.Metadata); err != nil {
+		return err
+	}
+
+	e.buf = append(e.buf, '}')
+	return nil
+}
+
+func (e *blockEncoder) writeRelatedTransaction(relatedTransaction *rTypes.RelatedTransaction) error {
+	if relatedTransaction == nil {
+		e.buf = append(e.buf, "null"...)
+		return nil
+	}
+
+	e.buf = append(e.buf, '{')
+	if network := relatedTransaction.NetworkIdentifier; network != nil {
+		e.buf = append(e.buf, `"network_identifier":{"blockchain":`...)
+		e.writeString(network.Blockchain)
+		e.buf = append(e.buf, `,"network":`...)
+		e.writeString(network.Network)
+		if subNetwork := network.SubNetworkIdentifier; subNetwork != nil {
+			e.buf = append(e.buf, `,"sub_network_identifier":{"network":`...)
+			e.writeString(subNetwork.Network)
+			if err := e.writeMetadataField(subNetwork.Metadata); err != nil {
+				return err
+			}
+			e.buf = append(e.buf, '}')
+		}
+		e.buf = append(e.buf, "},"...)
+	}
+
+	e.buf = append(e.buf, `"transaction_identifier":`...)
+	e.writeTransactionIdentifier(relatedTransaction.TransactionIdentifier)
+	e.buf = append(e.buf, `,"direction":`...)
+	e.writeString(string(relatedTransaction.Direction))
+	e.buf = append(e.buf, '}')
+	return nil
+}
+
+// writeMetadataField writes the metadata field unless the metadata is empty, since it's always tagged omitempty and
+// never the first field. The redacted keys are left out, the metadata with only redacted keys is written as an empty
+// object, same as the redactor
+func (e *blockEncoder) writeMetadataField(metadata map[string]interface{}) error {
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	e.buf = append(e.buf, `,"metadata":{`...)
+	e.keys = e.keys[:0]
+	for key := range metadata {
+		if !e.redactor.IsRedacted(key) {
+			e.keys = append(e.keys, key)
+		}
+	}
+	sort.Strings(e.keys)
+
+	for i, key := range e.keys {
+		if i != 0 {
+			e.buf = append(e.buf, ',')
+		}
+		e.writeString(key)
+		e.buf = append(e.buf, ':')
+		if err := e.writeValue(metadata[key]); err != nil {
+			return err
+		}
+	}
+
+	e.buf = append(e.buf, '}')
+	return nil
+}
+
+func (e *blockEncoder) writeValue(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		e.buf = append(e.buf, "null"...)
+	case string:
+		e.writeString(v)
+	case bool:
+		e.buf = strconv.AppendBool(e.buf, v)
+	case int:
+		e.buf = strconv.AppendInt(e.buf, int64(v), 10)
+	case int32:
+		e.buf = strconv.AppendInt(e.buf, int64(v), 10)
+	case int64:
+		e.buf = strconv.AppendInt(e.buf, v, 10)
+	case uint64:
+		e.buf = strconv.AppendUint(e.buf, v, 10)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		// the nested objects and arrays are in the metadata subtree, so the redacted keys are stripped at any depth
+		e.buf = append(e.buf, e.redactor.RedactMetadata(data)...)
+	}
+
+	return nil
+}
+
+// writeString writes the quoted string, the strings with characters escaped by encoding/json are encoded with it
+func (e *blockEncoder) writeString(s string) {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c < 0x20 || c >= 0x80 || c == '"' || c == '\\' || c == '<' || c == '>' || c == '&' {
+			// marshaling a string never fails
+			data, _ := json.Marshal(s)
+			e.buf = append(e.buf, data...)
+			return
+		}
+	}
+
+	e.buf = append(e.buf, '"')
+	e.buf = append(e.buf, s...)
+	e.buf = append(e.buf, '"')
+}
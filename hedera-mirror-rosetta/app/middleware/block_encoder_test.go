@@ -0,0 +1,392 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/redaction"
+	"github.com/stretchr/testify/assert"
+)
+
+type metadataValue struct {
+	Name string `json:"name"`
+}
+
+func TestBlockEncoderEncodeBlockResponse(t *testing.T) {
+	var tests = []struct {
+		name     string
+		response *rTypes.BlockResponse
+	}{
+		{name: "nil"},
+		{name: "empty", response: &rTypes.BlockResponse{}},
+		{name: "empty block", response: &rTypes.BlockResponse{Block: &rTypes.Block{}}},
+		{
+			name: "empty transactions",
+			response: &rTypes.BlockResponse{
+				Block: &rTypes.Block{Transactions: []*rTypes.Transaction{}, Metadata: map[string]interface{}{}},
+			},
+		},
+		{
+			name: "other transactions only",
+			response: &rTypes.BlockResponse{
+				OtherTransactions: []*rTypes.TransactionIdentifier{{Hash: "0x1"}, nil, {Hash: "0x2"}},
+			},
+		},
+		{name: "block", response: newBlockResponse(3, 4)},
+		{
+			name: "block with other transactions",
+			response: func() *rTypes.BlockResponse {
+				response := newBlockResponse(1, 1)
+				response.OtherTransactions = []*rTypes.TransactionIdentifier{{Hash: "0x1"}}
+				return response
+			}(),
+		},
+		{name: "block with all fields", response: &rTypes.BlockResponse{Block: newBlockWithAllFields()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			expected := encodeWithEncodingJson(t, tt.response)
+			encoder := getBlockEncoder()
+			defer putBlockEncoder(encoder)
+
+			// when
+			err := encoder.encodeBlockResponse(tt.response)
+
+			// then
+			assert.NoError(t, err)
+			assert.Equal(t, expected, string(encoder.buf))
+		})
+	}
+}
+
+func TestBlockEncoderEncodeBlockTransactionResponse(t *testing.T) {
+	var tests = []struct {
+		name     string
+		response *rTypes.BlockTransactionResponse
+	}{
+		{name: "nil"},
+		{name: "nil transaction", response: &rTypes.BlockTransactionResponse{}},
+		{
+			name:     "transaction",
+			response: &rTypes.BlockTransactionResponse{Transaction: newBlockWithAllFields().Transactions[0]},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			expected := encodeWithEncodingJson(t, tt.response)
+			encoder := getBlockEncoder()
+			defer putBlockEncoder(encoder)
+
+			// when
+			err := encoder.encodeBlockTransactionResponse(tt.response)
+
+			// then
+			assert.NoError(t, err)
+			assert.Equal(t, expected, string(encoder.buf))
+		})
+	}
+}
+
+func TestBlockEncoderFieldMask(t *testing.T) {
+	var tests = []struct {
+		name string
+		mask fieldMask
+	}{
+		{name: "currency metadata", mask: fieldMask{currencyMetadata: true}},
+		{name: "operation metadata", mask: fieldMask{operationMetadata: true}},
+		{name: "transaction metadata", mask: fieldMask{transactionMetadata: true}},
+		{name: "all", mask: fieldMask{currencyMetadata: true, operationMetadata: true, transactionMetadata: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			response := &rTypes.BlockResponse{Block: newBlockWithAllFields()}
+			expected := encodeWithEncodingJson(t, maskBlockResponse(response, tt.mask))
+			encoder := getBlockEncoder()
+			defer putBlockEncoder(encoder)
+			encoder.mask = tt.mask
+
+			// when
+			err := encoder.encodeBlockResponse(response)
+
+			// then
+			assert.NoError(t, err)
+			assert.Equal(t, expected, string(encoder.buf))
+			assert.Equal(t, newBlockWithAllFields(), response.Block)
+		})
+	}
+}
+
+func TestBlockEncoderRedaction(t *testing.T) {
+	var tests = []struct {
+		name      string
+		redaction config.Redaction
+	}{
+		{name: "aliases", redaction: config.Redaction{Aliases: true}},
+		{name: "memos", redaction: config.Redaction{Memos: true}},
+		{name: "topic messages", redaction: config.Redaction{TopicMessages: true}},
+		{name: "all", redaction: config.Redaction{Aliases: true, Memos: true, TopicMessages: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			redactor := redaction.NewRedactor(tt.redaction)
+			response := &rTypes.BlockResponse{Block: newBlockWithRedactedMetadata()}
+			expected := redactor.Redact([]byte(encodeWithEncodingJson(t, response)))
+			encoder := getBlockEncoder()
+			defer putBlockEncoder(encoder)
+			encoder.redactor = redactor
+
+			// when
+			err := encoder.encodeBlockResponse(response)
+
+			// then
+			assert.NoError(t, err)
+			assert.JSONEq(t, string(expected), string(encoder.buf))
+			assert.Equal(t, newBlockWithRedactedMetadata(), response.Block)
+		})
+	}
+}
+
+func TestBlockEncoderUnsupportedMetadataValue(t *testing.T) {
+	// given
+	response := newBlockResponse(1, 1)
+	response.Block.Metadata = map[string]interface{}{"channel": make(chan int)}
+	encoder := getBlockEncoder()
+	defer putBlockEncoder(encoder)
+
+	// when
+	err := encoder.encodeBlockResponse(response)
+
+	// then
+	assert.Error(t, err)
+}
+
+func TestBlockEncoderReused(t *testing.T) {
+	// given
+	first := newBlockResponse(2, 2)
+	second := newBlockResponse(1, 1)
+	encoder := getBlockEncoder()
+	encoder.mask = fieldMask{operationMetadata: true}
+	encoder.redactor = redaction.NewRedactor(config.Redaction{Memos: true})
+	assert.NoError(t, encoder.encodeBlockResponse(first))
+	putBlockEncoder(encoder)
+
+	// when
+	encoder = getBlockEncoder()
+	defer putBlockEncoder(encoder)
+	err := encoder.encodeBlockResponse(second)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, encodeWithEncodingJson(t, second), string(encoder.buf))
+}
+
+func BenchmarkEncodeBlockResponse(b *testing.B) {
+	response := newBlockResponse(1000, 10)
+
+	b.Run("encoding/json", func(b *testing.B) {
+		b.ReportAllocs()
+		buf := &bytes.Buffer{}
+		for i := 0; i < b.N; i++ {
+			buf.Reset()
+			if err := json.NewEncoder(buf).Encode(response); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("blockEncoder", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			encoder := getBlockEncoder()
+			if err := encoder.encodeBlockResponse(response); err != nil {
+				b.Fatal(err)
+			}
+			putBlockEncoder(encoder)
+		}
+	})
+}
+
+func encodeWithEncodingJson(t *testing.T, value interface{}) string {
+	buf := &bytes.Buffer{}
+	assert.NoError(t, json.NewEncoder(buf).Encode(value))
+	return buf.String()
+}
+
+// newBlockResponse returns a block response with the typical fields of the blocks of the server
+func newBlockResponse(transactions, operationsPerTransaction int) *rTypes.BlockResponse {
+	status := "SUCCESS"
+	block := &rTypes.Block{
+		BlockIdentifier:       &rTypes.BlockIdentifier{Index: 101, Hash: "0xabcdef01"},
+		ParentBlockIdentifier: &rTypes.BlockIdentifier{Index: 100, Hash: "0xabcdef00"},
+		Timestamp:             1660000000123,
+		Transactions:          make([]*rTypes.Transaction, 0, transactions),
+		Metadata:              map[string]interface{}{"consensus_start_nanos": "1660000000123000000"},
+	}
+
+	for i := 0; i < transactions; i++ {
+		operations := make([]*rTypes.Operation, 0, operationsPerTransaction)
+		for j := 0; j < operationsPerTransaction; j++ {
+			operations = append(operations, &rTypes.Operation{
+				OperationIdentifier: &rTypes.OperationIdentifier{Index: int64(j)},
+				Type:                "CRYPTOTRANSFER",
+				Status:              &status,
+				Account:             &rTypes.AccountIdentifier{Address: fmt.Sprintf("0.0.%d", 1000+j)},
+				Amount: &rTypes.Amount{
+					Value:    fmt.Sprintf("%d", (j+1)*100),
+					Currency: &rTypes.Currency{Symbol: "HBAR", Decimals: 8},
+				},
+			})
+		}
+
+		block.Transactions = append(block.Transactions, &rTypes.Transaction{
+			TransactionIdentifier: &rTypes.TransactionIdentifier{Hash: fmt.Sprintf("0x%064x", i)},
+			Operations:            operations,
+			Metadata:              map[string]interface{}{"memo": "transfer", "consensus_timestamp": int64(i)},
+		})
+	}
+
+	return &rTypes.BlockResponse{Block: block}
+}
+
+// newBlockWithRedactedMetadata returns a block with the keys stripped by each redaction category in the metadata, at
+// the top level and nested
+func newBlockWithRedactedMetadata() *rTypes.Block {
+	block := newBlockResponse(1, 1).Block
+	transaction := block.Transactions[0]
+	transaction.Metadata = map[string]interface{}{
+		"memo":      "secret",
+		"scheduled": map[string]interface{}{"memo": "secret", "payer": "0.0.2"},
+		"size":      100,
+	}
+	operation := transaction.Operations[0]
+	operation.Account.Metadata = map[string]interface{}{"evm_address": "0xsecret"}
+	operation.Metadata = map[string]interface{}{
+		"chunks":  []interface{}{map[string]interface{}{"running_hash": "0xsecret", "sequence_number": 1}},
+		"message": "0xsecret",
+		"topic":   "0.0.5",
+	}
+	return block
+}
+
+// newBlockWithAllFields returns a block with every field, and the metadata values and strings handled by each path
+// of the encoder
+func newBlockWithAllFields() *rTypes.Block {
+	status := "SUCCESS"
+	networkIndex := int64(5)
+	metadata := map[string]interface{}{
+		"bool":         true,
+		"bytes":        []byte{0x1, 0x2},
+		"escape":       "<a href=\"x\">&</a>\\\n\t ",
+		"float":        1.5e21,
+		"int":          math.MinInt64 / 2,
+		"int32":        int32(-7),
+		"int64":        int64(math.MaxInt64),
+		"invalid_utf8": "\xff",
+		"list":         []interface{}{1, "a", nil},
+		"map":          map[string]interface{}{"b": 1, "a": "<"},
+		"nil":          nil,
+		"pointer":      &networkIndex,
+		"raw":          json.RawMessage(`{"z": 1, "a": [ 1, 2 ]}`),
+		"struct":       metadataValue{Name: "name"},
+		"uint64":       uint64(math.MaxUint64),
+		"unicode":      "héllo 世界",
+		"<key>":        "value",
+	}
+
+	return &rTypes.Block{
+		BlockIdentifier:       &rTypes.BlockIdentifier{Index: 1, Hash: "0x01"},
+		ParentBlockIdentifier: nil,
+		Timestamp:             -1,
+		Transactions: []*rTypes.Transaction{
+			{
+				TransactionIdentifier: &rTypes.TransactionIdentifier{Hash: "0x\"quoted\""},
+				Operations: []*rTypes.Operation{
+					{
+						OperationIdentifier: &rTypes.OperationIdentifier{Index: 0, NetworkIndex: &networkIndex},
+						RelatedOperations:   []*rTypes.OperationIdentifier{{Index: 1}, nil},
+						Type:                "TOKENCREATION",
+						Status:              &status,
+						Account: &rTypes.AccountIdentifier{
+							Address:    "0.0.1001",
+							SubAccount: &rTypes.SubAccountIdentifier{Address: "sub", Metadata: metadata},
+							Metadata:   metadata,
+						},
+						Amount: &rTypes.Amount{
+							Value:    "-100",
+							Currency: &rTypes.Currency{Symbol: "0.0.5001", Decimals: 2, Metadata: metadata},
+							Metadata: metadata,
+						},
+						CoinChange: &rTypes.CoinChange{
+							CoinIdentifier: &rTypes.CoinIdentifier{Identifier: "coin"},
+							CoinAction:     rTypes.CoinCreated,
+						},
+						Metadata: metadata,
+					},
+					nil,
+					{
+						OperationIdentifier: nil,
+						Type:                "",
+						Amount:              &rTypes.Amount{Value: "1"},
+						CoinChange:          &rTypes.CoinChange{CoinAction: rTypes.CoinSpent},
+					},
+				},
+				RelatedTransactions: []*rTypes.RelatedTransaction{
+					{
+						NetworkIdentifier: &rTypes.NetworkIdentifier{
+							Blockchain: "Hedera",
+							Network:    "testnet",
+							SubNetworkIdentifier: &rTypes.SubNetworkIdentifier{
+								Network:  "shard 0",
+								Metadata: metadata,
+							},
+						},
+						TransactionIdentifier: &rTypes.TransactionIdentifier{Hash: "0x02"},
+						Direction:             rTypes.Forward,
+					},
+					{
+						NetworkIdentifier: &rTypes.NetworkIdentifier{Blockchain: "Hedera", Network: "mainnet"},
+						Direction:         rTypes.Backward,
+					},
+					{TransactionIdentifier: &rTypes.TransactionIdentifier{Hash: "0x03"}},
+					nil,
+				},
+				Metadata: metadata,
+			},
+			nil,
+			{TransactionIdentifier: &rTypes.TransactionIdentifier{Hash: "0x04"}, Operations: []*rTypes.Operation{}},
+		},
+		Metadata: metadata,
+	}
+}
@@ -0,0 +1,266 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	rosettaAsserter "github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/server"
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/redaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	networkIdentifierJson       = `"network_identifier":{"blockchain":"Hedera","network":"testnet"}`
+	blockRequestBody            = `{` + networkIdentifierJson + `,"block_identifier":{"index":1}}`
+	blockTransactionRequestBody = `{` + networkIdentifierJson +
+		`,"block_identifier":{"index":1,"hash":"0x01"},"transaction_identifier":{"hash":"0x02"}}`
+)
+
+// stubBlockAPIService returns the configured response and error
+type stubBlockAPIService struct {
+	blockResponse            *rTypes.BlockResponse
+	blockTransactionResponse *rTypes.BlockTransactionResponse
+	err                      *rTypes.Error
+}
+
+func (s *stubBlockAPIService) Block(context.Context, *rTypes.BlockRequest) (*rTypes.BlockResponse, *rTypes.Error) {
+	return s.blockResponse, s.err
+}
+
+func (s *stubBlockAPIService) BlockTransaction(context.Context, *rTypes.BlockTransactionRequest) (
+	*rTypes.BlockTransactionResponse,
+	*rTypes.Error,
+) {
+	return s.blockTransactionResponse, s.err
+}
+
+func TestBlockController(t *testing.T) {
+	block := newBlockWithAllFields()
+	var tests = []struct {
+		name    string
+		path    string
+		body    string
+		service *stubBlockAPIService
+	}{
+		{
+			name:    "block",
+			path:    "/block",
+			body:    blockRequestBody,
+			service: &stubBlockAPIService{blockResponse: &rTypes.BlockResponse{Block: block}},
+		},
+		{
+			name:    "block error",
+			path:    "/block",
+			body:    blockRequestBody,
+			service: &stubBlockAPIService{err: errors.ErrBlockNotFound},
+		},
+		{
+			name:    "block invalid json",
+			path:    "/block",
+			body:    "{",
+			service: &stubBlockAPIService{},
+		},
+		{
+			name:    "block invalid request",
+			path:    "/block",
+			body:    `{` + networkIdentifierJson + `}`,
+			service: &stubBlockAPIService{},
+		},
+		{
+			name: "block unsupported metadata value",
+			path: "/block",
+			body: blockRequestBody,
+			service: &stubBlockAPIService{blockResponse: &rTypes.BlockResponse{
+				Block: &rTypes.Block{Metadata: map[string]interface{}{"channel": make(chan int)}},
+			}},
+		},
+		{
+			name: "block transaction",
+			path: "/block/transaction",
+			body: blockTransactionRequestBody,
+			service: &stubBlockAPIService{
+				blockTransactionResponse: &rTypes.BlockTransactionResponse{Transaction: block.Transactions[0]},
+			},
+		},
+		{
+			name:    "block transaction error",
+			path:    "/block/transaction",
+			body:    blockTransactionRequestBody,
+			service: &stubBlockAPIService{err: errors.ErrTransactionNotFound},
+		},
+		{
+			name:    "block transaction invalid json",
+			path:    "/block/transaction",
+			body:    "{",
+			service: &stubBlockAPIService{},
+		},
+		{
+			name:    "block transaction invalid request",
+			path:    "/block/transaction",
+			body:    `{` + networkIdentifierJson + `}`,
+			service: &stubBlockAPIService{},
+		},
+	}
+
+	asserter := newTestAsserter(t)
+	for _, tt := range tests {
+		for _, fastEncoding := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s fastEncoding %t", tt.name, fastEncoding), func(t *testing.T) {
+				// given
+				expected := serveBlockRequest(server.NewBlockAPIController(tt.service, asserter), tt.path, tt.body)
+
+				// when
+				actual := serveBlockRequest(NewBlockController(tt.service, asserter, fastEncoding, nil), tt.path, tt.body)
+
+				// then
+				assert.Equal(t, expected.Code, actual.Code)
+				assert.Equal(t, expected.Header(), actual.Header())
+				assert.Equal(t, expected.Body.String(), actual.Body.String())
+			})
+		}
+	}
+}
+
+func TestBlockControllerFieldMask(t *testing.T) {
+	asserter := newTestAsserter(t)
+	metadata := `,"metadata":{"exclude_fields":["currency_metadata","operation_metadata","transaction_metadata"]}}`
+	for _, fastEncoding := range []bool{false, true} {
+		t.Run(fmt.Sprintf("fastEncoding %t", fastEncoding), func(t *testing.T) {
+			// given
+			block := newBlockWithAllFields()
+			service := &stubBlockAPIService{
+				blockResponse:            &rTypes.BlockResponse{Block: block},
+				blockTransactionResponse: &rTypes.BlockTransactionResponse{Transaction: block.Transactions[0]},
+			}
+			expectedBlock := newBlockWithAllFields()
+			for _, transaction := range expectedBlock.Transactions {
+				if transaction == nil {
+					continue
+				}
+				transaction.Metadata = nil
+				for _, operation := range transaction.Operations {
+					if operation != nil {
+						operation.Metadata = nil
+						if operation.Amount != nil && operation.Amount.Currency != nil {
+							operation.Amount.Currency.Metadata = nil
+						}
+					}
+				}
+			}
+			controller := NewBlockController(service, asserter, fastEncoding, nil)
+
+			// when
+			blockRecorder := serveBlockRequest(controller, "/block", strings.TrimSuffix(blockRequestBody, "}")+metadata)
+			blockTransactionRecorder := serveBlockRequest(controller, "/block/transaction",
+				strings.TrimSuffix(blockTransactionRequestBody, "}")+metadata)
+
+			// then
+			assert.Equal(t, http.StatusOK, blockRecorder.Code)
+			assert.Equal(t, encodeWithEncodingJson(t, &rTypes.BlockResponse{Block: expectedBlock}),
+				blockRecorder.Body.String())
+			assert.Equal(t, http.StatusOK, blockTransactionRecorder.Code)
+			assert.Equal(t,
+				encodeWithEncodingJson(t, &rTypes.BlockTransactionResponse{Transaction: expectedBlock.Transactions[0]}),
+				blockTransactionRecorder.Body.String(),
+			)
+			assert.Equal(t, newBlockWithAllFields(), block)
+		})
+	}
+}
+
+func TestBlockControllerRedaction(t *testing.T) {
+	asserter := newTestAsserter(t)
+	redactor := redaction.NewRedactor(config.Redaction{Aliases: true, Memos: true, TopicMessages: true})
+	for _, fastEncoding := range []bool{false, true} {
+		t.Run(fmt.Sprintf("fastEncoding %t", fastEncoding), func(t *testing.T) {
+			// given
+			block := newBlockWithRedactedMetadata()
+			service := &stubBlockAPIService{
+				blockResponse:            &rTypes.BlockResponse{Block: block},
+				blockTransactionResponse: &rTypes.BlockTransactionResponse{Transaction: block.Transactions[0]},
+			}
+			expectedBlock := redactor.Redact([]byte(encodeWithEncodingJson(t, &rTypes.BlockResponse{Block: block})))
+			expectedBlockTransaction := redactor.Redact([]byte(encodeWithEncodingJson(t,
+				&rTypes.BlockTransactionResponse{Transaction: block.Transactions[0]})))
+			controller := NewBlockController(service, asserter, fastEncoding, redactor)
+
+			// when
+			blockRecorder := serveBlockRequest(controller, "/block", blockRequestBody)
+			blockTransactionRecorder := serveBlockRequest(controller, "/block/transaction", blockTransactionRequestBody)
+
+			// then
+			assert.Equal(t, http.StatusOK, blockRecorder.Code)
+			assert.JSONEq(t, string(expectedBlock), blockRecorder.Body.String())
+			assert.NotContains(t, blockRecorder.Body.String(), "secret")
+			assert.Equal(t, http.StatusOK, blockTransactionRecorder.Code)
+			assert.JSONEq(t, string(expectedBlockTransaction), blockTransactionRecorder.Body.String())
+			assert.NotContains(t, blockTransactionRecorder.Body.String(), "secret")
+			assert.Equal(t, newBlockWithRedactedMetadata(), block)
+		})
+	}
+}
+
+func TestBlockControllerFieldMaskInvalidField(t *testing.T) {
+	// given
+	service := &stubBlockAPIService{blockResponse: &rTypes.BlockResponse{Block: newBlockWithAllFields()}}
+	body := strings.TrimSuffix(blockRequestBody, "}") + `,"metadata":{"exclude_fields":["amount"]}}`
+	expected := errors.AddErrorDetails(errors.ErrInvalidArgument, "exclude_fields", "amount")
+
+	// when
+	recorder := serveBlockRequest(NewBlockController(service, newTestAsserter(t), true, nil), "/block", body)
+
+	// then
+	actual := &rTypes.Error{}
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), actual))
+	assert.Equal(t, expected, actual)
+}
+
+func newTestAsserter(t *testing.T) *rosettaAsserter.Asserter {
+	asserter, err := rosettaAsserter.NewServer(
+		[]string{"CRYPTOTRANSFER"},
+		true,
+		[]*rTypes.NetworkIdentifier{{Blockchain: "Hedera", Network: "testnet"}},
+		[]string{"get_topic_message"},
+		false,
+		"",
+	)
+	require.NoError(t, err)
+	return asserter
+}
+
+func serveBlockRequest(router server.Router, path, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "http://localhost"+path, strings.NewReader(body))
+	recorder := httptest.NewRecorder()
+	server.NewRouter(router).ServeHTTP(recorder, request)
+	return recorder
+}
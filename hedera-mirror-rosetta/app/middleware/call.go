@@ -0,0 +1,88 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/server"
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/redaction"
+)
+
+const callRoute = "Call"
+
+// callController serves the call API with the rosetta-sdk-go CallAPIController, except the /call responses are encoded
+// with the sensitive keys stripped from their result by the redactor
+type callController struct {
+	server.Router
+	asserter *asserter.Asserter
+	redactor *redaction.Redactor
+	service  server.CallAPIServicer
+}
+
+// NewCallController constructs a new call controller. It's the rosetta-sdk-go CallAPIController if the redactor is nil
+func NewCallController(
+	service server.CallAPIServicer,
+	asserter *asserter.Asserter,
+	redactor *redaction.Redactor,
+) server.Router {
+	router := server.NewCallAPIController(service, asserter)
+	if redactor == nil {
+		return router
+	}
+	return &callController{Router: router, asserter: asserter, redactor: redactor, service: service}
+}
+
+// Routes returns the call controller routes
+func (c *callController) Routes() server.Routes {
+	routes := c.Router.Routes()
+	for i := range routes {
+		if routes[i].Name == callRoute {
+			routes[i].HandlerFunc = c.Call
+		}
+	}
+	return routes
+}
+
+// Call handles the /call requests
+func (c *callController) Call(w http.ResponseWriter, r *http.Request) {
+	request := &rTypes.CallRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		server.EncodeJSONResponse(&rTypes.Error{Message: err.Error()}, http.StatusInternalServerError, w)
+		return
+	}
+
+	if err := c.asserter.CallRequest(request); err != nil {
+		server.EncodeJSONResponse(&rTypes.Error{Message: err.Error()}, http.StatusInternalServerError, w)
+		return
+	}
+
+	response, rErr := c.service.Call(r.Context(), request)
+	if rErr != nil {
+		server.EncodeJSONResponse(rErr, http.StatusInternalServerError, w)
+		return
+	}
+
+	writeJSONResponse(w, response, c.redactor)
+}
@@ -0,0 +1,106 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/server"
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+)
+
+const constructionCombineRoute = "ConstructionCombine"
+
+// constructionController serves the construction API with the rosetta-sdk-go ConstructionAPIController, except a
+// /construction/combine request allowed to be co-signed by the server-side signer can have no signatures, so the server
+// can be the only signer, e.g., of a transaction whose fee payer is the only required signer
+type constructionController struct {
+	server.Router
+	asserter *asserter.Asserter
+	service  server.ConstructionAPIServicer
+}
+
+// NewConstructionController constructs a new construction controller
+func NewConstructionController(service server.ConstructionAPIServicer, asserter *asserter.Asserter) server.Router {
+	return &constructionController{
+		Router:   server.NewConstructionAPIController(service, asserter),
+		asserter: asserter,
+		service:  service,
+	}
+}
+
+// Routes returns the construction controller routes
+func (c *constructionController) Routes() server.Routes {
+	routes := c.Router.Routes()
+	for i := range routes {
+		if routes[i].Name == constructionCombineRoute {
+			routes[i].HandlerFunc = c.ConstructionCombine
+		}
+	}
+	return routes
+}
+
+// ConstructionCombine handles the /construction/combine requests
+func (c *constructionController) ConstructionCombine(w http.ResponseWriter, r *http.Request) {
+	request := &rTypes.ConstructionCombineRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		server.EncodeJSONResponse(&rTypes.Error{Message: err.Error()}, http.StatusInternalServerError, w)
+		return
+	}
+
+	if err := c.assertConstructionCombineRequest(r.Context(), request); err != nil {
+		server.EncodeJSONResponse(&rTypes.Error{Message: err.Error()}, http.StatusInternalServerError, w)
+		return
+	}
+
+	response, rErr := c.service.ConstructionCombine(r.Context(), request)
+	if rErr != nil {
+		server.EncodeJSONResponse(rErr, http.StatusInternalServerError, w)
+		return
+	}
+
+	server.EncodeJSONResponse(response, http.StatusOK, w)
+}
+
+// assertConstructionCombineRequest asserts the request the same as the asserter, except the signatures can be empty if
+// the server-side signing is allowed
+func (c *constructionController) assertConstructionCombineRequest(
+	ctx context.Context,
+	request *rTypes.ConstructionCombineRequest,
+) error {
+	if len(request.Signatures) != 0 || !types.IsServerSigningAllowed(ctx) {
+		return c.asserter.ConstructionCombineRequest(request)
+	}
+
+	if err := c.asserter.ValidSupportedNetwork(request.NetworkIdentifier); err != nil {
+		return err
+	}
+
+	if len(request.UnsignedTransaction) == 0 {
+		return asserter.ErrConstructionCombineRequestUnsignedTxEmpty
+	}
+
+	return nil
+}
@@ -0,0 +1,168 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	rosettaAsserter "github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/server"
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	combineRequestWithoutSignatures  = `{` + networkIdentifierJson + `,"unsigned_transaction":"0x1234","signatures":[]}`
+	combineRequestWithoutTransaction = `{` + networkIdentifierJson + `,"unsigned_transaction":"","signatures":[]}`
+)
+
+// stubConstructionAPIService records the combine request and returns the configured response and error, the other
+// methods aren't implemented
+type stubConstructionAPIService struct {
+	server.ConstructionAPIServicer
+	err      *rTypes.Error
+	request  *rTypes.ConstructionCombineRequest
+	response *rTypes.ConstructionCombineResponse
+}
+
+func (s *stubConstructionAPIService) ConstructionCombine(
+	_ context.Context,
+	request *rTypes.ConstructionCombineRequest,
+) (*rTypes.ConstructionCombineResponse, *rTypes.Error) {
+	s.request = request
+	return s.response, s.err
+}
+
+func TestConstructionControllerRoutes(t *testing.T) {
+	// given
+	controller := NewConstructionController(&stubConstructionAPIService{}, newTestAsserter(t))
+	expected := server.NewConstructionAPIController(&stubConstructionAPIService{}, newTestAsserter(t)).Routes()
+
+	// when
+	actual := controller.Routes()
+
+	// then
+	require.Len(t, actual, len(expected))
+	for i := range expected {
+		assert.Equal(t, expected[i].Name, actual[i].Name)
+		assert.Equal(t, expected[i].Method, actual[i].Method)
+		assert.Equal(t, expected[i].Pattern, actual[i].Pattern)
+	}
+}
+
+func TestConstructionControllerCombineWithoutSignatures(t *testing.T) {
+	// given
+	service := &stubConstructionAPIService{
+		response: &rTypes.ConstructionCombineResponse{SignedTransaction: "0x5678"},
+	}
+
+	// when
+	recorder := serveCombineRequest(t, service, combineRequestWithoutSignatures, true)
+
+	// then
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"signed_transaction":"0x5678"}`, recorder.Body.String())
+	require.NotNil(t, service.request)
+	assert.Equal(t, "0x1234", service.request.UnsignedTransaction)
+	assert.Empty(t, service.request.Signatures)
+}
+
+func TestConstructionControllerCombineError(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		serverSigning bool
+		serviceErr    *rTypes.Error
+		expected      string
+	}{
+		{
+			name:     "signatures empty without server signing",
+			body:     combineRequestWithoutSignatures,
+			expected: rosettaAsserter.ErrSignaturesEmpty.Error(),
+		},
+		{
+			name:          "unsigned transaction empty",
+			body:          combineRequestWithoutTransaction,
+			serverSigning: true,
+			expected:      rosettaAsserter.ErrConstructionCombineRequestUnsignedTxEmpty.Error(),
+		},
+		{
+			name:          "unsupported network",
+			body:          `{"network_identifier":{"blockchain":"Hedera","network":"mainnet"},"unsigned_transaction":"0x1"}`,
+			serverSigning: true,
+			expected:      "",
+		},
+		{
+			name:          "invalid json",
+			body:          "{",
+			serverSigning: true,
+			expected:      "",
+		},
+		{
+			name:          "service error",
+			body:          combineRequestWithoutSignatures,
+			serverSigning: true,
+			serviceErr:    errors.ErrNoSignature,
+			expected:      errors.ErrNoSignature.Message,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			service := &stubConstructionAPIService{err: tt.serviceErr}
+
+			// when
+			recorder := serveCombineRequest(t, service, tt.body, tt.serverSigning)
+
+			// then
+			assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+			if tt.expected != "" {
+				assert.Contains(t, recorder.Body.String(), tt.expected)
+			}
+			if tt.serviceErr == nil {
+				assert.Nil(t, service.request)
+			}
+		})
+	}
+}
+
+func serveCombineRequest(
+	t *testing.T,
+	service server.ConstructionAPIServicer,
+	body string,
+	serverSigning bool,
+) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "http://localhost/construction/combine", strings.NewReader(body))
+	if serverSigning {
+		request = request.WithContext(types.WithServerSigning(request.Context()))
+	}
+	recorder := httptest.NewRecorder()
+	server.NewRouter(NewConstructionController(service, newTestAsserter(t))).ServeHTTP(recorder, request)
+	return recorder
+}
@@ -0,0 +1,58 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ClientDisconnectMiddleware makes sure the context of a request is cancelled as soon as the client closes the
+// connection, so the in-flight database queries and transaction submission of an abandoned request, e.g., one
+// rosetta-cli retried after its client timeout, stop instead of running to completion. The http server only watches
+// the connection for a close once the request body is read to EOF, which the json decoding of the handlers doesn't
+// guarantee, so the body is drained before the request is handled
+func ClientDisconnectMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Body != nil && r.Body != http.NoBody {
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				log.Warnf("Failed to read the body of %s %s: %s", r.Method, r.URL.Path, err)
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		start := time.Now()
+		next.ServeHTTP(w, r)
+
+		if r.Context().Err() == context.Canceled {
+			log.Infof("Client closed the connection, cancelled %s %s after %s", r.Method, r.URL.Path,
+				time.Since(start))
+		}
+	})
+}
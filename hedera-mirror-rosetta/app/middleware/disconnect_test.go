@@ -0,0 +1,116 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientDisconnectMiddlewareCancelsContext(t *testing.T) {
+	// given
+	started := make(chan struct{})
+	cancelled := make(chan error, 1)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the handler doesn't read the request body, like a json decoder which stops before EOF
+		close(started)
+		select {
+		case <-r.Context().Done():
+			cancelled <- r.Context().Err()
+		case <-time.After(5 * time.Second):
+			cancelled <- nil
+		}
+	})
+	httpServer := httptest.NewServer(ClientDisconnectMiddleware(handler))
+	defer httpServer.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	request, err := http.NewRequestWithContext(ctx, "POST", httpServer.URL+"/block", strings.NewReader(`{}`))
+	require.NoError(t, err)
+
+	// when
+	go func() {
+		<-started
+		cancel()
+	}()
+	_, err = http.DefaultClient.Do(request)
+
+	// then
+	assert.True(t, errors.Is(err, context.Canceled))
+	select {
+	case err = <-cancelled:
+		assert.Equal(t, context.Canceled, err)
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "handler not finished")
+	}
+}
+
+func TestClientDisconnectMiddlewareKeepsBody(t *testing.T) {
+	// given
+	var body string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		body = string(data)
+		w.WriteHeader(http.StatusOK)
+	})
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest("POST", "http://localhost/block", strings.NewReader(`{"foo": "bar"}`))
+
+	// when
+	ClientDisconnectMiddleware(handler).ServeHTTP(recorder, request)
+
+	// then
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, `{"foo": "bar"}`, body)
+}
+
+func TestClientDisconnectMiddlewareBodyReadError(t *testing.T) {
+	// given
+	called := false
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest("POST", "http://localhost/block", io.NopCloser(&errorReader{}))
+
+	// when
+	ClientDisconnectMiddleware(handler).ServeHTTP(recorder, request)
+
+	// then
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.False(t, called)
+}
+
+type errorReader struct{}
+
+func (e *errorReader) Read([]byte) (int, error) {
+	return 0, io.ErrUnexpectedEOF
+}
@@ -21,17 +21,22 @@
 package middleware
 
 import (
+	"strings"
 	"time"
 
 	"github.com/coinbase/rosetta-sdk-go/server"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence"
 	"github.com/hellofresh/health-go/v4"
+	healthHttp "github.com/hellofresh/health-go/v4/checks/http"
 	"github.com/hellofresh/health-go/v4/checks/postgres"
 )
 
 const (
 	livenessPath  = "/health/liveness"
 	readinessPath = "/health/readiness"
+	// restReadinessPath is the readiness endpoint of the mirror node REST API
+	restReadinessPath = "/health/readiness"
 )
 
 // healthController holds data used to response to health probes
@@ -40,19 +45,28 @@ type healthController struct {
 	readinessHealth *health.Health
 }
 
-// NewHealthController creates a new HealthController object
-func NewHealthController(dbConfig config.Db) (server.Router, error) {
+// NewHealthController creates a new HealthController object. The readiness checks the mirror node REST API if it's
+// the configured backend, otherwise the database
+func NewHealthController(backendConfig config.Backend, dbConfig config.Db) (server.Router, error) {
 	livenessHealth, err := health.New()
 	if err != nil {
 		return nil, err
 	}
 
-	readinessHealth, err := health.New(health.WithChecks(health.Config{
+	readinessCheck := health.Config{
 		Name:      "postgresql",
 		Timeout:   time.Second * 10,
 		SkipOnErr: false,
 		Check:     postgres.New(postgres.Config{DSN: dbConfig.GetDsn()}),
-	}))
+	}
+	if strings.ToLower(backendConfig.Type) == persistence.BackendTypeRest {
+		readinessCheck.Name = "rest"
+		readinessCheck.Check = healthHttp.New(healthHttp.Config{
+			URL: strings.TrimSuffix(backendConfig.Rest.BaseUrl, "/") + restReadinessPath,
+		})
+	}
+
+	readinessHealth, err := health.New(health.WithChecks(readinessCheck))
 
 	if err != nil {
 		return nil, err
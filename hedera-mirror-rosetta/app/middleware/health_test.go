@@ -33,7 +33,7 @@ import (
 )
 
 func TestLiveness(t *testing.T) {
-	healthController, err := NewHealthController(config.Db{})
+	healthController, err := NewHealthController(config.Backend{}, config.Db{})
 	require.NoError(t, err)
 
 	req := httptest.NewRequest("GET", "http://localhost"+livenessPath, nil)
@@ -56,7 +56,7 @@ func TestReadiness(t *testing.T) {
 	}{{
 		status: health.StatusUnavailable,
 	}} {
-		healthController, err := NewHealthController(config.Db{})
+		healthController, err := NewHealthController(config.Backend{}, config.Db{})
 		require.NoError(t, err)
 
 		req := httptest.NewRequest("GET", "http://localhost"+readinessPath, nil)
@@ -78,3 +78,34 @@ func TestReadiness(t *testing.T) {
 		require.Equal(t, httpStatus, tracingResponseWriter.statusCode)
 	}
 }
+
+func TestReadinessRestBackend(t *testing.T) {
+	for _, tc := range []struct {
+		restStatus int
+		status     health.Status
+	}{{
+		restStatus: http.StatusOK,
+		status:     health.StatusOK,
+	}, {
+		restStatus: http.StatusServiceUnavailable,
+		status:     health.StatusUnavailable,
+	}} {
+		restApi := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, restReadinessPath, r.URL.Path)
+			w.WriteHeader(tc.restStatus)
+		}))
+		backendConfig := config.Backend{Rest: config.BackendRest{BaseUrl: restApi.URL}, Type: "rest"}
+		healthController, err := NewHealthController(backendConfig, config.Db{})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest("GET", "http://localhost"+readinessPath, nil)
+		recorder := httptest.NewRecorder()
+		healthController.Routes()[1].HandlerFunc.ServeHTTP(recorder, req)
+		restApi.Close()
+
+		var check health.Check
+		err = json.Unmarshal(recorder.Body.Bytes(), &check)
+		require.NoError(t, err)
+		require.Equal(t, tc.status, check.Status)
+	}
+}
@@ -0,0 +1,188 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/db"
+)
+
+const inFlightPath = "/admin/requests"
+
+// InFlightRequest is an executing request listed by the in-flight request dashboard
+type InFlightRequest struct {
+	// Account is the address of the account identifier of the request, empty if none
+	Account string `json:"account,omitempty"`
+	// Block is the block index or hash of the request, or the block range of /events/blocks and /search/transactions,
+	// empty if none
+	Block    string `json:"block,omitempty"`
+	Elapsed  string `json:"elapsed"`
+	Endpoint string `json:"endpoint"`
+	Id       uint64 `json:"id"`
+	// Queries are the names of the database queries currently running on behalf of the request
+	Queries []string  `json:"queries"`
+	Start   time.Time `json:"start"`
+}
+
+// inFlightRequestBody is the subset of the rosetta request fields identifying what a request works on
+type inFlightRequestBody struct {
+	AccountIdentifier *struct {
+		Address string `json:"address"`
+	} `json:"account_identifier"`
+	BlockIdentifier *struct {
+		Hash  *string `json:"hash"`
+		Index *int64  `json:"index"`
+	} `json:"block_identifier"`
+	Limit    *int64 `json:"limit"`
+	MaxBlock *int64 `json:"max_block"`
+	Offset   *int64 `json:"offset"`
+}
+
+type inFlightEntry struct {
+	account  string
+	block    string
+	endpoint string
+	phase    *db.QueryPhase
+	start    time.Time
+}
+
+// InFlightRequests tracks the requests currently executing, so a stuck request can be debugged without attaching a
+// profiler
+type InFlightRequests struct {
+	mutex    sync.Mutex
+	nextId   uint64
+	requests map[uint64]*inFlightEntry
+}
+
+// NewInFlightRequests creates an empty InFlightRequests
+func NewInFlightRequests() *InFlightRequests {
+	return &InFlightRequests{requests: make(map[uint64]*inFlightEntry)}
+}
+
+// List returns the requests currently executing, the longest running first
+func (f *InFlightRequests) List() []InFlightRequest {
+	now := time.Now()
+	f.mutex.Lock()
+	requests := make([]InFlightRequest, 0, len(f.requests))
+	for id, entry := range f.requests {
+		requests = append(requests, InFlightRequest{
+			Account:  entry.account,
+			Block:    entry.block,
+			Elapsed:  now.Sub(entry.start).String(),
+			Endpoint: entry.endpoint,
+			Id:       id,
+			Queries:  entry.phase.Queries(),
+			Start:    entry.start,
+		})
+	}
+	f.mutex.Unlock()
+
+	sort.Slice(requests, func(i, j int) bool {
+		if !requests[i].Start.Equal(requests[j].Start) {
+			return requests[i].Start.Before(requests[j].Start)
+		}
+		return requests[i].Id < requests[j].Id
+	})
+	return requests
+}
+
+func (f *InFlightRequests) add(entry *inFlightEntry) uint64 {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.nextId++
+	f.requests[f.nextId] = entry
+	return f.nextId
+}
+
+func (f *InFlightRequests) remove(id uint64) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	delete(f.requests, id)
+}
+
+// InFlightMiddleware tracks the requests while they execute. The internal endpoints aren't tracked, and the request is
+// served as is if the tracker is nil
+func InFlightMiddleware(next http.Handler, inFlight *InFlightRequests) http.Handler {
+	if inFlight == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if internalPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		entry := &inFlightEntry{endpoint: r.URL.Path, phase: &db.QueryPhase{}, start: time.Now()}
+		entry.account, entry.block = parseInFlightRequestBody(r)
+		id := inFlight.add(entry)
+		defer inFlight.remove(id)
+
+		next.ServeHTTP(w, r.WithContext(db.WithQueryPhase(r.Context(), entry.phase)))
+	})
+}
+
+// parseInFlightRequestBody returns the account and the block of the request body, and restores the body so it can be
+// read again by the handler
+func parseInFlightRequestBody(r *http.Request) (string, string) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return "", ""
+	}
+
+	body, err := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return "", ""
+	}
+
+	var request inFlightRequestBody
+	if json.Unmarshal(body, &request) != nil {
+		return "", ""
+	}
+
+	var account, block string
+	if request.AccountIdentifier != nil {
+		account = request.AccountIdentifier.Address
+	}
+
+	switch {
+	case request.BlockIdentifier != nil && request.BlockIdentifier.Index != nil:
+		block = fmt.Sprintf("%d", *request.BlockIdentifier.Index)
+	case request.BlockIdentifier != nil && request.BlockIdentifier.Hash != nil:
+		block = *request.BlockIdentifier.Hash
+	case request.MaxBlock != nil:
+		block = fmt.Sprintf("..%d", *request.MaxBlock)
+	case request.Offset != nil && request.Limit != nil:
+		block = fmt.Sprintf("%d..%d", *request.Offset, *request.Offset+*request.Limit-1)
+	case request.Offset != nil:
+		block = fmt.Sprintf("%d..", *request.Offset)
+	}
+
+	return account, block
+}
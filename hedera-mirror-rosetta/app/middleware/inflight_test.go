@@ -0,0 +1,172 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/server"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestInFlightMiddleware(t *testing.T) {
+	// given
+	gormDb, err := gorm.Open(postgres.Open("host=127.0.0.1 port=1"), &gorm.Config{DisableAutomaticPing: true})
+	require.NoError(t, err)
+	dbClient := db.NewDbClient(gormDb, 0, config.DbRetry{})
+	inFlight := NewInFlightRequests()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	bodies := make(chan string, 1)
+	handler := InFlightMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies <- string(body)
+		_ = dbClient.Query(r.Context(), "selectBlock", func(*gorm.DB) error {
+			close(started)
+			<-release
+			return nil
+		})
+	}), inFlight)
+	requestBody := `{"account_identifier": {"address": "0.0.100"}, "block_identifier": {"index": 10}}`
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.ServeHTTP(
+			httptest.NewRecorder(),
+			httptest.NewRequest(http.MethodPost, "http://localhost/account/balance", strings.NewReader(requestBody)),
+		)
+	}()
+	<-started
+	router := server.NewRouter(NewAdminController(config.Admin{Enabled: true, Token: adminToken}, nil, inFlight, nil))
+	request := httptest.NewRequest(http.MethodGet, "http://localhost"+inFlightPath, nil)
+	request.Header.Set(authorizationHeader, "Bearer "+adminToken)
+	recorder := httptest.NewRecorder()
+
+	// when
+	router.ServeHTTP(recorder, request)
+	close(release)
+	<-done
+
+	// then
+	var actual []InFlightRequest
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &actual))
+	require.Len(t, actual, 1)
+	assert.Equal(t, "0.0.100", actual[0].Account)
+	assert.Equal(t, "10", actual[0].Block)
+	assert.Equal(t, "/account/balance", actual[0].Endpoint)
+	assert.Equal(t, []string{"selectBlock"}, actual[0].Queries)
+	assert.NotEmpty(t, actual[0].Elapsed)
+	assert.Equal(t, requestBody, <-bodies)
+	assert.Empty(t, inFlight.List())
+}
+
+func TestInFlightMiddlewareBlock(t *testing.T) {
+	var tests = []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{name: "empty"},
+		{name: "invalid json", body: "foobar"},
+		{name: "block hash", body: `{"block_identifier": {"hash": "0x1234"}}`, expected: "0x1234"},
+		{name: "events", body: `{"offset": 5, "limit": 10}`, expected: "5..14"},
+		{name: "events without limit", body: `{"offset": 5}`, expected: "5.."},
+		{name: "search", body: `{"max_block": 20}`, expected: "..20"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			inFlight := NewInFlightRequests()
+			var actual []InFlightRequest
+			handler := InFlightMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				actual = inFlight.List()
+			}), inFlight)
+
+			// when
+			handler.ServeHTTP(
+				httptest.NewRecorder(),
+				httptest.NewRequest(http.MethodPost, "http://localhost/block", strings.NewReader(tt.body)),
+			)
+
+			// then
+			require.Len(t, actual, 1)
+			assert.Equal(t, tt.expected, actual[0].Block)
+			assert.Empty(t, actual[0].Queries)
+		})
+	}
+}
+
+func TestInFlightMiddlewareInternalPath(t *testing.T) {
+	// given
+	inFlight := NewInFlightRequests()
+	var actual []InFlightRequest
+	handler := InFlightMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actual = inFlight.List()
+	}), inFlight)
+
+	// when
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost"+metricsPath, nil))
+
+	// then
+	assert.Empty(t, actual)
+}
+
+func TestInFlightRequestsListOrder(t *testing.T) {
+	// given
+	inFlight := NewInFlightRequests()
+	now := time.Now()
+	inFlight.add(&inFlightEntry{endpoint: "/block", phase: &db.QueryPhase{}, start: now})
+	inFlight.add(&inFlightEntry{endpoint: "/network/status", phase: &db.QueryPhase{}, start: now.Add(-time.Second)})
+
+	// when
+	actual := inFlight.List()
+
+	// then
+	require.Len(t, actual, 2)
+	assert.Equal(t, "/network/status", actual[0].Endpoint)
+	assert.Equal(t, "/block", actual[1].Endpoint)
+}
+
+func TestAdminGetInFlightRequestsDisabled(t *testing.T) {
+	// given
+	router := server.NewRouter(NewAdminController(config.Admin{Enabled: true, Token: adminToken}, nil, nil, nil))
+	request := httptest.NewRequest(http.MethodGet, "http://localhost"+inFlightPath, nil)
+	request.Header.Set(authorizationHeader, "Bearer "+adminToken)
+	recorder := httptest.NewRecorder()
+
+	// when
+	router.ServeHTTP(recorder, request)
+
+	// then
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
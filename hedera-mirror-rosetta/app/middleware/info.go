@@ -0,0 +1,97 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	log "github.com/sirupsen/logrus"
+)
+
+const infoPath = "/admin/info"
+
+// BuildInfo holds the build information embedded at build time via ldflags
+type BuildInfo struct {
+	BuildTime string `json:"build_time"`
+	GitCommit string `json:"git_commit"`
+	Version   string `json:"version"`
+}
+
+// ToMetadata returns the build info as a metadata map
+func (b BuildInfo) ToMetadata() map[string]interface{} {
+	return map[string]interface{}{
+		"build_time": b.BuildTime,
+		"git_commit": b.GitCommit,
+	}
+}
+
+type info struct {
+	BuildInfo
+	Application    string `json:"application"`
+	NodeVersion    string `json:"node_version"`
+	RosettaVersion string `json:"rosetta_version"`
+	SchemaVersion  string `json:"schema_version,omitempty"`
+}
+
+// infoHandler holds data used to serve build info requests
+type infoHandler struct {
+	info              info
+	schemaVersionRepo interfaces.SchemaVersionRepository
+}
+
+// NewInfoHandler creates the handler of the build info, which the admin controller serves behind the admin
+// authentication. The schema version repository is nil in offline mode
+func NewInfoHandler(
+	buildInfo BuildInfo,
+	version *rTypes.Version,
+	schemaVersionRepo interfaces.SchemaVersionRepository,
+) http.HandlerFunc {
+	handler := &infoHandler{
+		info: info{
+			BuildInfo:      buildInfo,
+			Application:    application,
+			NodeVersion:    version.NodeVersion,
+			RosettaVersion: version.RosettaVersion,
+		},
+		schemaVersionRepo: schemaVersionRepo,
+	}
+	return handler.Info
+}
+
+// Info serves the build info as json. The current schema version is read for each request so it reflects the migrations
+// applied by importer upgrades, and is omitted if it can't be read
+func (c *infoHandler) Info(w http.ResponseWriter, r *http.Request) {
+	response := c.info
+	if c.schemaVersionRepo != nil {
+		if schemaVersion, err := c.schemaVersionRepo.RetrieveLatest(r.Context()); err == nil {
+			response.SchemaVersion = schemaVersion.String()
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Errorf("Failed to encode build info: %s", err)
+	}
+}
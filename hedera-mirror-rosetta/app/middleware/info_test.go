@@ -0,0 +1,120 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/coinbase/rosetta-sdk-go/server"
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	infoBuildInfo = BuildInfo{BuildTime: "2022-08-01T00:00:00Z", GitCommit: "abcdef0", Version: "v0.62.0"}
+	infoVersion   = &rTypes.Version{NodeVersion: "0.27.0", RosettaVersion: "1.4.12"}
+)
+
+func TestInfo(t *testing.T) {
+	infoHandler := NewInfoHandler(infoBuildInfo, infoVersion, nil)
+
+	actual := getInfo(t, infoHandler)
+
+	assert.Equal(t, expectedInfo(), actual)
+}
+
+func TestInfoUnauthorized(t *testing.T) {
+	router := newInfoRouter(NewInfoHandler(infoBuildInfo, infoVersion, nil))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost"+infoPath, nil))
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assert.NotContains(t, recorder.Body.String(), infoBuildInfo.GitCommit)
+}
+
+func TestInfoWithSchemaVersion(t *testing.T) {
+	schemaVersionRepo := &mocks.MockSchemaVersionRepository{}
+	schemaVersionRepo.On("RetrieveLatest").Return(types.MustParseSchemaVersion("1.65.4"), mocks.NilError)
+	infoHandler := NewInfoHandler(infoBuildInfo, infoVersion, schemaVersionRepo)
+	expected := expectedInfo()
+	expected["schema_version"] = "1.65.4"
+
+	actual := getInfo(t, infoHandler)
+
+	assert.Equal(t, expected, actual)
+	schemaVersionRepo.AssertExpectations(t)
+}
+
+func TestInfoSchemaVersionError(t *testing.T) {
+	schemaVersionRepo := &mocks.MockSchemaVersionRepository{}
+	schemaVersionRepo.On("RetrieveLatest").Return(mocks.NilSchemaVersion, errors.ErrDatabaseError)
+	infoHandler := NewInfoHandler(infoBuildInfo, infoVersion, schemaVersionRepo)
+
+	actual := getInfo(t, infoHandler)
+
+	assert.Equal(t, expectedInfo(), actual)
+	schemaVersionRepo.AssertExpectations(t)
+}
+
+func TestBuildInfoToMetadata(t *testing.T) {
+	buildInfo := BuildInfo{BuildTime: "2022-08-01T00:00:00Z", GitCommit: "abcdef0", Version: "v0.62.0"}
+	assert.Equal(t, map[string]interface{}{
+		"build_time": "2022-08-01T00:00:00Z",
+		"git_commit": "abcdef0",
+	}, buildInfo.ToMetadata())
+}
+
+func expectedInfo() map[string]interface{} {
+	return map[string]interface{}{
+		"application":     application,
+		"build_time":      "2022-08-01T00:00:00Z",
+		"git_commit":      "abcdef0",
+		"node_version":    "0.27.0",
+		"rosetta_version": "1.4.12",
+		"version":         "v0.62.0",
+	}
+}
+
+func getInfo(t *testing.T, infoHandler http.HandlerFunc) map[string]interface{} {
+	request := httptest.NewRequest(http.MethodGet, "http://localhost"+infoPath, nil)
+	request.Header.Set(authorizationHeader, "Bearer "+adminToken)
+	recorder := httptest.NewRecorder()
+	newInfoRouter(infoHandler).ServeHTTP(recorder, request)
+
+	var actual map[string]interface{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &actual))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Header().Get("Content-Type"), "application/json")
+	return actual
+}
+
+func newInfoRouter(infoHandler http.HandlerFunc) http.Handler {
+	return server.NewRouter(NewAdminController(config.Admin{Enabled: true, Token: adminToken}, nil, nil, infoHandler))
+}
@@ -0,0 +1,124 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package middleware
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const retryAfterHeader = "Retry-After"
+
+// limitedPathPrefixes are the path prefixes of the data endpoints which query the database
+var limitedPathPrefixes = []string{"/account/", "/block", "/call"}
+
+// ConcurrencyLimiter limits the number of concurrent requests to the data endpoints. It's shared by the http and the
+// gRPC servers so the limit holds for the requests of both
+type ConcurrencyLimiter struct {
+	semaphore chan struct{}
+}
+
+// NewConcurrencyLimiter creates a concurrency limiter, or nil if maxConcurrentRequests is not positive
+func NewConcurrencyLimiter(maxConcurrentRequests int) *ConcurrencyLimiter {
+	if maxConcurrentRequests <= 0 {
+		return nil
+	}
+
+	return &ConcurrencyLimiter{semaphore: make(chan struct{}, maxConcurrentRequests)}
+}
+
+// TryAcquire acquires a slot without waiting, returns false if the limit is reached. A nil limiter always succeeds
+func (l *ConcurrencyLimiter) TryAcquire() bool {
+	if l == nil {
+		return true
+	}
+
+	select {
+	case l.semaphore <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// Release releases the slot acquired by TryAcquire
+func (l *ConcurrencyLimiter) Release() {
+	if l != nil {
+		<-l.semaphore
+	}
+}
+
+// Limit returns the max number of concurrent requests
+func (l *ConcurrencyLimiter) Limit() int {
+	if l == nil {
+		return 0
+	}
+
+	return cap(l.semaphore)
+}
+
+// ConcurrencyLimitMiddleware limits the number of concurrent requests to the data endpoints with the limiter. Requests
+// above the limit are rejected immediately with 503 and a retriable rosetta error, so a surge of requests can't
+// exhaust the db pool. A nil limiter disables the limit
+func ConcurrencyLimitMiddleware(next http.Handler, limiter *ConcurrencyLimiter, retryAfter time.Duration) http.Handler {
+	if limiter == nil {
+		return next
+	}
+
+	retryAfterSeconds := strconv.Itoa(int(math.Ceil(retryAfter.Seconds())))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isLimitedPath(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !limiter.TryAcquire() {
+			log.Warnf("Rejected %s %s with %d concurrent requests in flight", r.Method, r.URL.Path, limiter.Limit())
+			w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+			w.Header().Set(retryAfterHeader, retryAfterSeconds)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			if err := json.NewEncoder(w).Encode(errors.ErrTooManyConcurrentRequests); err != nil {
+				log.Errorf("Failed to encode error response: %s", err)
+			}
+			return
+		}
+
+		defer limiter.Release()
+		next.ServeHTTP(w, r)
+	})
+}
+
+func isLimitedPath(path string) bool {
+	for _, prefix := range limitedPathPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,91 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrencyLimitMiddleware(t *testing.T) {
+	// given
+	blocked := make(chan struct{})
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/block" {
+			started <- struct{}{}
+			<-blocked
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	limited := ConcurrencyLimitMiddleware(handler, NewConcurrencyLimiter(1), 1500*time.Millisecond)
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		limited.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "http://localhost/block", nil))
+	}()
+	<-started
+
+	// when
+	rejected := httptest.NewRecorder()
+	limited.ServeHTTP(rejected, httptest.NewRequest("POST", "http://localhost/block/transaction", nil))
+	unlimited := httptest.NewRecorder()
+	limited.ServeHTTP(unlimited, httptest.NewRequest("POST", "http://localhost/network/status", nil))
+	close(blocked)
+	wg.Wait()
+	accepted := httptest.NewRecorder()
+	limited.ServeHTTP(accepted, httptest.NewRequest("POST", "http://localhost/account/balance", nil))
+
+	// then
+	rosettaError := &types.Error{}
+	assert.Equal(t, http.StatusServiceUnavailable, rejected.Code)
+	assert.Equal(t, "2", rejected.Header().Get(retryAfterHeader))
+	assert.NoError(t, json.Unmarshal(rejected.Body.Bytes(), rosettaError))
+	assert.Equal(t, errors.ErrTooManyConcurrentRequests, rosettaError)
+	assert.True(t, rosettaError.Retriable)
+	assert.Equal(t, http.StatusOK, unlimited.Code)
+	assert.Equal(t, http.StatusOK, accepted.Code)
+}
+
+func TestConcurrencyLimitMiddlewareDisabled(t *testing.T) {
+	// given
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	limited := ConcurrencyLimitMiddleware(handler, NewConcurrencyLimiter(0), time.Second)
+	recorder := httptest.NewRecorder()
+
+	// when
+	limited.ServeHTTP(recorder, httptest.NewRequest("POST", "http://localhost/block", nil))
+
+	// then
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
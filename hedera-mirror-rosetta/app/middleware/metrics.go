@@ -90,13 +90,14 @@ func (c *metricsController) Routes() server.Routes {
 	}
 }
 
-// MetricsMiddleware instruments HTTP requests with request metrics
-func MetricsMiddleware(next http.Handler) http.Handler {
+// MetricsMiddleware instruments HTTP requests with request metrics. The route of a request is matched with router,
+// since next may be the router wrapped by other middlewares
+func MetricsMiddleware(next http.Handler, router http.Handler) http.Handler {
 	return middleware.Instrument{
 		Duration:         requestDurationHistogram,
 		InflightRequests: requestInflightGauge,
 		RequestBodySize:  requestBytesHistogram,
 		ResponseBodySize: responseBytesHistogram,
-		RouteMatcher:     next.(middleware.RouteMatcher),
+		RouteMatcher:     router.(middleware.RouteMatcher),
 	}.Wrap(next)
 }
@@ -23,11 +23,22 @@ package middleware
 import (
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/coinbase/rosetta-sdk-go/server"
 	"github.com/stretchr/testify/require"
 )
 
+type blockRouter struct {
+	handler http.HandlerFunc
+}
+
+func (r blockRouter) Routes() server.Routes {
+	return server.Routes{{Name: "block", Method: "POST", Pattern: "/block", HandlerFunc: r.handler}}
+}
+
 func TestMetrics(t *testing.T) {
 	metricsController := NewMetricsController()
 	request := httptest.NewRequest("GET", "http://localhost"+metricsPath, nil)
@@ -40,3 +51,38 @@ func TestMetrics(t *testing.T) {
 	require.Contains(t, responseWriter.Header().Get("Content-Type"), "text/plain")
 	require.Contains(t, response, "promhttp_metric_handler_requests_total")
 }
+
+func TestMetricsMiddlewareCountsRejectedRequests(t *testing.T) {
+	// given
+	blocked := make(chan struct{})
+	started := make(chan struct{})
+	router := server.NewRouter(blockRouter{handler: func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-blocked
+		w.WriteHeader(http.StatusOK)
+	}})
+	handler := MetricsMiddleware(ConcurrencyLimitMiddleware(router, NewConcurrencyLimiter(1), time.Second), router)
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "http://localhost/block", nil))
+	}()
+	<-started
+
+	// when
+	rejected := httptest.NewRecorder()
+	handler.ServeHTTP(rejected, httptest.NewRequest("POST", "http://localhost/block", nil))
+	close(blocked)
+	wg.Wait()
+
+	// then
+	require.Equal(t, http.StatusServiceUnavailable, rejected.Code)
+	recorder := httptest.NewRecorder()
+	NewMetricsController().Routes()[0].HandlerFunc.ServeHTTP(
+		recorder,
+		httptest.NewRequest("GET", "http://localhost"+metricsPath, nil),
+	)
+	require.Contains(t, recorder.Body.String(), `status_code="503"`)
+}
@@ -0,0 +1,47 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/coinbase/rosetta-sdk-go/server"
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/redaction"
+)
+
+// writeJSONResponse encodes the successful response followed by a newline with the sensitive keys stripped from its
+// metadata by the redactor, same as server.EncodeJSONResponse if the redactor is nil
+func writeJSONResponse(w http.ResponseWriter, response interface{}, redactor *redaction.Redactor) {
+	if redactor == nil {
+		server.EncodeJSONResponse(response, http.StatusOK, w)
+		return
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		server.EncodeJSONResponse(&rTypes.Error{Message: err.Error()}, http.StatusInternalServerError, w)
+		return
+	}
+
+	writeEncodedResponse(w, append(redactor.Redact(data), '\n'))
+}
@@ -0,0 +1,259 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/coinbase/rosetta-sdk-go/server"
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/redaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	accountBalanceRequestBody = `{` + networkIdentifierJson + `,"account_identifier":{"address":"0.0.100"}}`
+	callRequestBody           = `{` + networkIdentifierJson + `,"method":"get_topic_message","parameters":{}}`
+)
+
+// stubAccountAPIService returns the configured balance response and error, the other methods aren't implemented
+type stubAccountAPIService struct {
+	server.AccountAPIServicer
+	err      *rTypes.Error
+	response *rTypes.AccountBalanceResponse
+}
+
+func (s *stubAccountAPIService) AccountBalance(context.Context, *rTypes.AccountBalanceRequest) (
+	*rTypes.AccountBalanceResponse,
+	*rTypes.Error,
+) {
+	return s.response, s.err
+}
+
+// stubCallAPIService returns the configured response and error
+type stubCallAPIService struct {
+	err      *rTypes.Error
+	response *rTypes.CallResponse
+}
+
+func (s *stubCallAPIService) Call(context.Context, *rTypes.CallRequest) (*rTypes.CallResponse, *rTypes.Error) {
+	return s.response, s.err
+}
+
+func TestAccountController(t *testing.T) {
+	// given
+	service := &stubAccountAPIService{response: &rTypes.AccountBalanceResponse{
+		BlockIdentifier: &rTypes.BlockIdentifier{Index: 1, Hash: "0x01"},
+		Balances:        []*rTypes.Amount{{Value: "100", Currency: &rTypes.Currency{Symbol: "HBAR", Decimals: 8}}},
+		Metadata: map[string]interface{}{
+			"lifecycle": map[string]interface{}{"evm_address": "0xsecret", "deleted": false},
+			"memo":      "secret",
+		},
+	}}
+	redactor := redaction.NewRedactor(config.Redaction{Aliases: true, Memos: true})
+	expected := `{
+		"block_identifier": {"index": 1, "hash": "0x01"},
+		"balances": [{"value": "100", "currency": {"symbol": "HBAR", "decimals": 8}}],
+		"metadata": {"lifecycle": {"deleted": false}}
+	}`
+
+	// when
+	recorder := serveBlockRequest(
+		NewAccountController(service, newTestAsserter(t), redactor),
+		"/account/balance",
+		accountBalanceRequestBody,
+	)
+
+	// then
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, expected, recorder.Body.String())
+	assert.Equal(t, "secret", service.response.Metadata["memo"])
+}
+
+func TestCallController(t *testing.T) {
+	tests := []struct {
+		name      string
+		redaction config.Redaction
+		expected  string
+	}{
+		{
+			name:     "disabled",
+			expected: `{"result": {"message": "0x02", "memo": "secret", "topic_id": "0.0.5"}, "idempotent": true}`,
+		},
+		{
+			name:      "memos",
+			redaction: config.Redaction{Memos: true},
+			expected:  `{"result": {"message": "0x02", "topic_id": "0.0.5"}, "idempotent": true}`,
+		},
+		{
+			name:      "topic messages",
+			redaction: config.Redaction{TopicMessages: true},
+			expected:  `{"result": {"memo": "secret", "topic_id": "0.0.5"}, "idempotent": true}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			service := &stubCallAPIService{response: &rTypes.CallResponse{
+				Result:     map[string]interface{}{"memo": "secret", "message": "0x02", "topic_id": "0.0.5"},
+				Idempotent: true,
+			}}
+			controller := NewCallController(service, newTestAsserter(t), redaction.NewRedactor(tt.redaction))
+
+			// when
+			recorder := serveBlockRequest(controller, "/call", callRequestBody)
+
+			// then
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.JSONEq(t, tt.expected, recorder.Body.String())
+		})
+	}
+}
+
+func TestRedactedControllerRoutes(t *testing.T) {
+	// given
+	redactor := redaction.NewRedactor(config.Redaction{Memos: true})
+	tests := []struct {
+		name       string
+		controller server.Router
+		expected   server.Router
+	}{
+		{
+			name:       "account",
+			controller: NewAccountController(&stubAccountAPIService{}, newTestAsserter(t), redactor),
+			expected:   server.NewAccountAPIController(&stubAccountAPIService{}, newTestAsserter(t)),
+		},
+		{
+			name:       "call",
+			controller: NewCallController(&stubCallAPIService{}, newTestAsserter(t), redactor),
+			expected:   server.NewCallAPIController(&stubCallAPIService{}, newTestAsserter(t)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			actual := tt.controller.Routes()
+
+			// then
+			expected := tt.expected.Routes()
+			require.Len(t, actual, len(expected))
+			for i := range expected {
+				assert.Equal(t, expected[i].Name, actual[i].Name)
+				assert.Equal(t, expected[i].Method, actual[i].Method)
+				assert.Equal(t, expected[i].Pattern, actual[i].Pattern)
+			}
+		})
+	}
+}
+
+func TestRedactedControllerError(t *testing.T) {
+	redactor := redaction.NewRedactor(config.Redaction{Memos: true})
+	tests := []struct {
+		name     string
+		router   server.Router
+		path     string
+		body     string
+		expected *rTypes.Error
+	}{
+		{
+			name:   "account invalid json",
+			router: NewAccountController(&stubAccountAPIService{}, newTestAsserter(t), redactor),
+			path:   "/account/balance",
+			body:   "{",
+		},
+		{
+			name:   "account invalid request",
+			router: NewAccountController(&stubAccountAPIService{}, newTestAsserter(t), redactor),
+			path:   "/account/balance",
+			body:   `{` + networkIdentifierJson + `}`,
+		},
+		{
+			name: "account service error",
+			router: NewAccountController(
+				&stubAccountAPIService{err: errors.ErrAccountNotFound},
+				newTestAsserter(t),
+				redactor,
+			),
+			path:     "/account/balance",
+			body:     accountBalanceRequestBody,
+			expected: errors.ErrAccountNotFound,
+		},
+		{
+			name:   "call invalid json",
+			router: NewCallController(&stubCallAPIService{}, newTestAsserter(t), redactor),
+			path:   "/call",
+			body:   "{",
+		},
+		{
+			name:   "call invalid request",
+			router: NewCallController(&stubCallAPIService{}, newTestAsserter(t), redactor),
+			path:   "/call",
+			body:   `{` + networkIdentifierJson + `}`,
+		},
+		{
+			name: "call service error",
+			router: NewCallController(
+				&stubCallAPIService{err: errors.ErrCallMethodUnsupported},
+				newTestAsserter(t),
+				redactor,
+			),
+			path:     "/call",
+			body:     callRequestBody,
+			expected: errors.ErrCallMethodUnsupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			recorder := serveBlockRequest(tt.router, tt.path, tt.body)
+
+			// then
+			assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+			if tt.expected != nil {
+				actual := &rTypes.Error{}
+				assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), actual))
+				assert.Equal(t, tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWriteJSONResponseUnsupportedValue(t *testing.T) {
+	// given
+	redactor := redaction.NewRedactor(config.Redaction{Memos: true})
+	service := &stubCallAPIService{response: &rTypes.CallResponse{
+		Result: map[string]interface{}{"channel": make(chan int)},
+	}}
+
+	// when
+	recorder := serveBlockRequest(NewCallController(service, newTestAsserter(t), redactor), "/call", callRequestBody)
+
+	// then
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
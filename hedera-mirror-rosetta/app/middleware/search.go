@@ -0,0 +1,93 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/server"
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/redaction"
+)
+
+// SearchAPIServicer is the service of the /search/transactions endpoint, which takes the cursor of the next page in
+// place of the offset
+type SearchAPIServicer interface {
+	SearchTransactions(ctx context.Context, request *types.SearchTransactionsRequest) (
+		*types.SearchTransactionsResponse,
+		*rTypes.Error,
+	)
+}
+
+// searchController serves the search API same as the rosetta-sdk-go SearchAPIController, except the request has the
+// cursor field and the response has the next_cursor field, and the metadata of the response is redacted
+type searchController struct {
+	asserter *asserter.Asserter
+	redactor *redaction.Redactor
+	service  SearchAPIServicer
+}
+
+// NewSearchController constructs a new search controller
+func NewSearchController(
+	service SearchAPIServicer,
+	asserter *asserter.Asserter,
+	redactor *redaction.Redactor,
+) server.Router {
+	return &searchController{asserter: asserter, redactor: redactor, service: service}
+}
+
+// Routes returns the search controller routes
+func (c *searchController) Routes() server.Routes {
+	return server.Routes{
+		{
+			"SearchTransactions",
+			http.MethodPost,
+			"/search/transactions",
+			c.SearchTransactions,
+		},
+	}
+}
+
+// SearchTransactions handles the /search/transactions requests
+func (c *searchController) SearchTransactions(w http.ResponseWriter, r *http.Request) {
+	request := &types.SearchTransactionsRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		server.EncodeJSONResponse(&rTypes.Error{Message: err.Error()}, http.StatusInternalServerError, w)
+		return
+	}
+
+	if err := c.asserter.SearchTransactionsRequest(&request.SearchTransactionsRequest); err != nil {
+		server.EncodeJSONResponse(&rTypes.Error{Message: err.Error()}, http.StatusInternalServerError, w)
+		return
+	}
+
+	response, rErr := c.service.SearchTransactions(r.Context(), request)
+	if rErr != nil {
+		server.EncodeJSONResponse(rErr, http.StatusInternalServerError, w)
+		return
+	}
+
+	writeJSONResponse(w, response, c.redactor)
+}
@@ -0,0 +1,140 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/redaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const searchTransactionsRequestBody = `{` + networkIdentifierJson + `,"limit":1,"cursor":"abc"}`
+
+// stubSearchAPIService records the request and returns the configured response and error
+type stubSearchAPIService struct {
+	err      *rTypes.Error
+	request  *types.SearchTransactionsRequest
+	response *types.SearchTransactionsResponse
+}
+
+func (s *stubSearchAPIService) SearchTransactions(_ context.Context, request *types.SearchTransactionsRequest) (
+	*types.SearchTransactionsResponse,
+	*rTypes.Error,
+) {
+	s.request = request
+	return s.response, s.err
+}
+
+func TestSearchController(t *testing.T) {
+	// given
+	nextCursor := "def"
+	service := &stubSearchAPIService{response: &types.SearchTransactionsResponse{
+		SearchTransactionsResponse: rTypes.SearchTransactionsResponse{
+			Transactions: []*rTypes.BlockTransaction{},
+			TotalCount:   2,
+		},
+		NextCursor: &nextCursor,
+	}}
+
+	// when
+	recorder := serveBlockRequest(
+		NewSearchController(service, newTestAsserter(t), nil),
+		"/search/transactions",
+		searchTransactionsRequestBody,
+	)
+
+	// then
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"transactions":[],"total_count":2,"next_cursor":"def"}`, recorder.Body.String())
+	require.NotNil(t, service.request)
+	assert.Equal(t, "abc", *service.request.Cursor)
+	assert.Equal(t, int64(1), *service.request.Limit)
+}
+
+func TestSearchControllerRedaction(t *testing.T) {
+	// given
+	block := newBlockWithRedactedMetadata()
+	service := &stubSearchAPIService{response: &types.SearchTransactionsResponse{
+		SearchTransactionsResponse: rTypes.SearchTransactionsResponse{
+			Transactions: []*rTypes.BlockTransaction{
+				{BlockIdentifier: block.BlockIdentifier, Transaction: block.Transactions[0]},
+			},
+			TotalCount: 1,
+		},
+	}}
+	redactor := redaction.NewRedactor(config.Redaction{Aliases: true, Memos: true, TopicMessages: true})
+
+	// when
+	recorder := serveBlockRequest(
+		NewSearchController(service, newTestAsserter(t), redactor),
+		"/search/transactions",
+		searchTransactionsRequestBody,
+	)
+
+	// then
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, string(redactor.Redact([]byte(encodeWithEncodingJson(t, service.response)))),
+		recorder.Body.String())
+	assert.NotContains(t, recorder.Body.String(), "secret")
+	assert.Equal(t, newBlockWithRedactedMetadata(), block)
+}
+
+func TestSearchControllerError(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected *rTypes.Error
+	}{
+		{name: "invalid json", body: "{"},
+		{name: "invalid request", body: `{` + networkIdentifierJson + `,"limit":-1}`},
+		{name: "service error", body: searchTransactionsRequestBody, expected: errors.ErrInvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			service := &stubSearchAPIService{err: errors.ErrInvalidArgument}
+
+			// when
+			recorder := serveBlockRequest(NewSearchController(service, newTestAsserter(t), nil), "/search/transactions",
+				tt.body)
+
+			// then
+			assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+			if tt.expected != nil {
+				actual := &rTypes.Error{}
+				assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), actual))
+				assert.Equal(t, tt.expected, actual)
+			} else {
+				assert.Nil(t, service.request)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	log "github.com/sirupsen/logrus"
+)
+
+const combinePath = "/construction/combine"
+
+// ServerSigningMiddleware allows the server-side signer to co-sign the transaction of a /construction/combine request
+// only if the request has the admin token as the bearer token. An unauthorized request is combined with just its own
+// signatures
+func ServerSigningMiddleware(next http.Handler, adminConfig config.Admin) http.Handler {
+	if !adminConfig.Enabled {
+		return next
+	}
+
+	token := []byte(adminConfig.Token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != combinePath || r.Header.Get(authorizationHeader) == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !isAuthorized(r, token) {
+			log.Warnf("Ignoring unauthorized server-side signing of %s %s", r.Method, r.URL.Path)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(types.WithServerSigning(r.Context())))
+	})
+}
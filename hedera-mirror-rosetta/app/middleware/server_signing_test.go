@@ -0,0 +1,73 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerSigningMiddleware(t *testing.T) {
+	var tests = []struct {
+		name          string
+		adminConfig   config.Admin
+		authorization string
+		path          string
+		expected      bool
+	}{
+		{name: "authorized", adminConfig: config.Admin{Enabled: true, Token: adminToken},
+			authorization: "Bearer " + adminToken, path: combinePath, expected: true},
+		{name: "admin disabled", adminConfig: config.Admin{Token: adminToken},
+			authorization: "Bearer " + adminToken, path: combinePath},
+		{name: "other path", adminConfig: config.Admin{Enabled: true, Token: adminToken},
+			authorization: "Bearer " + adminToken, path: "/construction/submit"},
+		{name: "no authorization", adminConfig: config.Admin{Enabled: true, Token: adminToken}, path: combinePath},
+		{name: "unauthorized", adminConfig: config.Admin{Enabled: true, Token: adminToken},
+			authorization: "Bearer foo", path: combinePath},
+		{name: "token not configured", adminConfig: config.Admin{Enabled: true},
+			authorization: "Bearer ", path: combinePath},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var allowed bool
+			handler := ServerSigningMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				allowed = types.IsServerSigningAllowed(r.Context())
+			}), tt.adminConfig)
+			request := httptest.NewRequest("POST", tt.path, nil)
+			if tt.authorization != "" {
+				request.Header.Set(authorizationHeader, tt.authorization)
+			}
+
+			// when
+			handler.ServeHTTP(httptest.NewRecorder(), request)
+
+			// then
+			assert.Equal(t, tt.expected, allowed)
+		})
+	}
+}
@@ -0,0 +1,322 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// sloBucketsPerWindow is the number of buckets of the shortest window, which sets the resolution of the windows
+	sloBucketsPerWindow = 30
+	// sloMaxErrorBodySize is the max size of the error response body read to tell the server-side errors apart
+	sloMaxErrorBodySize = 4096
+	sloPath             = "/admin/slo"
+)
+
+var (
+	// sloEndpoints are the rosetta API endpoints with an availability target by default
+	sloEndpoints = []string{
+		"/account/balance",
+		"/account/coins",
+		"/block",
+		"/block/transaction",
+		"/call",
+		"/construction/combine",
+		"/construction/derive",
+		"/construction/hash",
+		"/construction/metadata",
+		"/construction/parse",
+		"/construction/payloads",
+		"/construction/preprocess",
+		"/construction/submit",
+		"/mempool",
+		"/mempool/transaction",
+		"/network/list",
+		"/network/options",
+		"/network/status",
+	}
+
+	// serverSideErrorCodes are the codes of the rosetta errors caused by the server rather than the request
+	serverSideErrorCodes = map[int32]bool{
+		errors.ErrDatabaseError.Code:             true,
+		errors.ErrEndpointTimeout.Code:           true,
+		errors.ErrInternalServerError.Code:       true,
+		errors.ErrNodeIsStarting.Code:            true,
+		errors.ErrTooManyConcurrentRequests.Code: true,
+	}
+
+	sloBurnRateGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "hedera_mirror_rosetta_slo_burn_rate",
+		Help: "Rate the error budget of the endpoint is consumed at over the window, 1 exhausts it at the end of the window.",
+	}, []string{"route", "window"})
+
+	sloSuccessRatioGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "hedera_mirror_rosetta_slo_success_ratio",
+		Help: "Ratio of the requests to the endpoint served without a server-side error over the window.",
+	}, []string{"route", "window"})
+
+	sloTargetGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "hedera_mirror_rosetta_slo_target",
+		Help: "Availability target of the endpoint.",
+	}, []string{"route"})
+)
+
+func init() {
+	register := prometheus.WrapRegistererWith(prometheus.Labels{"application": application}, prometheus.DefaultRegisterer)
+	register.MustRegister(sloBurnRateGauge)
+	register.MustRegister(sloSuccessRatioGauge)
+	register.MustRegister(sloTargetGauge)
+}
+
+// sloBucket counts the requests and the failed requests of an interval of the windows
+type sloBucket struct {
+	failed   uint64
+	index    int64
+	requests uint64
+}
+
+// endpointSlo holds the buckets of an endpoint in a ring covering the longest window
+type endpointSlo struct {
+	buckets []sloBucket
+	mutex   sync.Mutex
+	target  float64
+}
+
+func (e *endpointSlo) record(index int64, failed bool) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	bucket := &e.buckets[index%int64(len(e.buckets))]
+	if bucket.index != index {
+		*bucket = sloBucket{index: index}
+	}
+	bucket.requests++
+	if failed {
+		bucket.failed++
+	}
+}
+
+// count returns the number of requests and failed requests in the last count buckets up to the bucket of the index
+func (e *endpointSlo) count(index int64, count int) (requests, failed uint64) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	for i := index; i > index-int64(count) && i >= 0; i-- {
+		if bucket := e.buckets[i%int64(len(e.buckets))]; bucket.index == i {
+			requests += bucket.requests
+			failed += bucket.failed
+		}
+	}
+	return requests, failed
+}
+
+// WindowSummary is the availability of an endpoint over a window
+type WindowSummary struct {
+	BurnRate     float64 `json:"burn_rate"`
+	Failed       uint64  `json:"failed"`
+	Requests     uint64  `json:"requests"`
+	SuccessRatio float64 `json:"success_ratio"`
+}
+
+// EndpointSummary is the availability of an endpoint against its target over each window
+type EndpointSummary struct {
+	Target  float64                  `json:"target"`
+	Windows map[string]WindowSummary `json:"windows"`
+}
+
+// ErrorBudget tracks the success ratios of the endpoints in sliding windows against their availability targets
+type ErrorBudget struct {
+	bucketDuration time.Duration
+	endpoints      map[string]*endpointSlo
+	now            func() time.Time
+	windows        []time.Duration
+}
+
+// NewErrorBudget creates the error budget of the endpoints, or returns nil if the SLO tracking is disabled
+func NewErrorBudget(sloConfig config.Slo) *ErrorBudget {
+	windows := make([]time.Duration, 0, len(sloConfig.Windows))
+	for _, window := range sloConfig.Windows {
+		if window > 0 {
+			windows = append(windows, window)
+		}
+	}
+
+	if !sloConfig.Enabled || len(windows) == 0 {
+		return nil
+	}
+
+	sort.Slice(windows, func(i, j int) bool { return windows[i] < windows[j] })
+	bucketDuration := windows[0] / sloBucketsPerWindow
+	if bucketDuration <= 0 {
+		bucketDuration = 1
+	}
+	bucketCount := int(windows[len(windows)-1]/bucketDuration) + 1
+
+	targets := make(map[string]float64, len(sloEndpoints)+len(sloConfig.Targets))
+	for _, endpoint := range sloEndpoints {
+		targets[endpoint] = sloConfig.Target
+	}
+	for endpoint, target := range sloConfig.Targets {
+		targets[endpoint] = target
+	}
+
+	endpoints := make(map[string]*endpointSlo, len(targets))
+	for endpoint, target := range targets {
+		endpoints[endpoint] = &endpointSlo{buckets: make([]sloBucket, bucketCount), target: target}
+		sloTargetGauge.WithLabelValues(endpoint).Set(target)
+	}
+
+	return &ErrorBudget{bucketDuration: bucketDuration, endpoints: endpoints, now: time.Now, windows: windows}
+}
+
+// Run updates the burn rate and success ratio metrics of the endpoints every bucket until the context is done
+func (b *ErrorBudget) Run(ctx context.Context) {
+	ticker := time.NewTicker(b.bucketDuration)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for endpoint, summary := range b.Summary() {
+				for window, windowSummary := range summary.Windows {
+					sloBurnRateGauge.WithLabelValues(endpoint, window).Set(windowSummary.BurnRate)
+					sloSuccessRatioGauge.WithLabelValues(endpoint, window).Set(windowSummary.SuccessRatio)
+				}
+			}
+		}
+	}
+}
+
+// Summary returns the availability of each endpoint against its target over each window. The success ratio is 1 and
+// the burn rate is 0 for a window without requests
+func (b *ErrorBudget) Summary() map[string]EndpointSummary {
+	index := b.bucketIndex()
+	summaries := make(map[string]EndpointSummary, len(b.endpoints))
+	for endpoint, slo := range b.endpoints {
+		windows := make(map[string]WindowSummary, len(b.windows))
+		for _, window := range b.windows {
+			requests, failed := slo.count(index, int(window/b.bucketDuration))
+			summary := WindowSummary{Failed: failed, Requests: requests, SuccessRatio: 1}
+			if requests != 0 {
+				errorRatio := float64(failed) / float64(requests)
+				summary.SuccessRatio = 1 - errorRatio
+				if slo.target < 1 {
+					summary.BurnRate = errorRatio / (1 - slo.target)
+				}
+			}
+			windows[window.String()] = summary
+		}
+		summaries[endpoint] = EndpointSummary{Target: slo.target, Windows: windows}
+	}
+
+	return summaries
+}
+
+func (b *ErrorBudget) bucketIndex() int64 {
+	return b.now().UnixNano() / int64(b.bucketDuration)
+}
+
+// sloResponseWriter stores the status code and the start of the error response body, so the server-side errors can be
+// told apart from the errors caused by the request
+type sloResponseWriter struct {
+	http.ResponseWriter
+	body       []byte
+	statusCode int
+}
+
+func (w *sloResponseWriter) WriteHeader(code int) {
+	w.statusCode = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *sloResponseWriter) Write(data []byte) (int, error) {
+	if w.statusCode == http.StatusInternalServerError && len(w.body) < sloMaxErrorBodySize {
+		end := sloMaxErrorBodySize - len(w.body)
+		if end > len(data) {
+			end = len(data)
+		}
+		w.body = append(w.body, data[:end]...)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// isFailed returns true if the request is rejected or fails with a server-side error. Since the rosetta errors are all
+// served with 500, a 500 response only fails if its rosetta error is a server-side error or it isn't a rosetta error
+func (w *sloResponseWriter) isFailed() bool {
+	if w.statusCode < http.StatusInternalServerError {
+		return false
+	}
+
+	if w.statusCode != http.StatusInternalServerError {
+		return true
+	}
+
+	rosettaError := &rTypes.Error{}
+	if err := json.Unmarshal(w.body, rosettaError); err != nil || rosettaError.Message == "" {
+		return true
+	}
+
+	return serverSideErrorCodes[rosettaError.Code]
+}
+
+// ErrorBudgetMiddleware records the outcome of the requests to the endpoints with an availability target in the error
+// budget, except the requests cancelled by the client. A nil error budget disables the tracking
+func ErrorBudgetMiddleware(next http.Handler, errorBudget *ErrorBudget) http.Handler {
+	if errorBudget == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slo, ok := errorBudget.endpoints[r.URL.Path]
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		sloWriter := &sloResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(sloWriter, r)
+		if r.Context().Err() == context.Canceled {
+			// the request abandoned by the client doesn't count
+			return
+		}
+
+		failed := sloWriter.isFailed()
+		slo.record(errorBudget.bucketIndex(), failed)
+		if failed {
+			log.Debugf("Counted %s %s with status %d against the error budget", r.Method, r.URL.Path,
+				sloWriter.statusCode)
+		}
+	})
+}
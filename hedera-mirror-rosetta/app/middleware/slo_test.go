@@ -0,0 +1,233 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/server"
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var defaultSloConfig = config.Slo{
+	Enabled: true,
+	Target:  0.9,
+	Targets: map[string]float64{"/call": 0.5},
+	Windows: []time.Duration{time.Hour, 5 * time.Minute},
+}
+
+func TestNewErrorBudgetDisabled(t *testing.T) {
+	tests := []struct {
+		name      string
+		sloConfig config.Slo
+	}{
+		{name: "disabled", sloConfig: config.Slo{Target: 0.9, Windows: []time.Duration{time.Minute}}},
+		{name: "no windows", sloConfig: config.Slo{Enabled: true, Target: 0.9}},
+		{name: "non-positive windows", sloConfig: config.Slo{Enabled: true, Target: 0.9, Windows: []time.Duration{0}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Nil(t, NewErrorBudget(tt.sloConfig))
+		})
+	}
+}
+
+func TestErrorBudgetMiddleware(t *testing.T) {
+	// given
+	now := time.Unix(1000000, 0)
+	errorBudget := NewErrorBudget(defaultSloConfig)
+	errorBudget.now = func() time.Time { return now }
+	handler := ErrorBudgetMiddleware(newSloTestHandler(), errorBudget)
+
+	// when
+	serveSloRequest(handler, "/block?status=200")
+	serveSloRequest(handler, "/block?status=503")
+	now = now.Add(10 * time.Minute)
+	serveSloRequest(handler, "/block?status=200")
+	serveSloRequest(handler, "/block?status=200")
+	serveSloRequest(handler, "/block?error=database")
+	serveSloRequest(handler, "/block?error=not_found")
+	serveSloRequest(handler, "/call?error=database")
+	serveSloRequest(handler, "/call?status=200")
+	serveSloRequest(handler, "/stream?status=500")
+	actual := errorBudget.Summary()
+
+	// then
+	assert.Equal(t, EndpointSummary{
+		Target: 0.9,
+		Windows: map[string]WindowSummary{
+			"5m0s":   {BurnRate: 2.5, Failed: 1, Requests: 4, SuccessRatio: 0.75},
+			"1h0m0s": roundWindowSummary(WindowSummary{BurnRate: 10.0 / 3, Failed: 2, Requests: 6, SuccessRatio: 2.0 / 3}),
+		},
+	}, roundSummary(actual["/block"]))
+	assert.Equal(t, EndpointSummary{
+		Target: 0.5,
+		Windows: map[string]WindowSummary{
+			"5m0s":   {BurnRate: 1, Failed: 1, Requests: 2, SuccessRatio: 0.5},
+			"1h0m0s": {BurnRate: 1, Failed: 1, Requests: 2, SuccessRatio: 0.5},
+		},
+	}, roundSummary(actual["/call"]))
+	assert.Equal(t, EndpointSummary{
+		Target: 0.9,
+		Windows: map[string]WindowSummary{
+			"5m0s":   {SuccessRatio: 1},
+			"1h0m0s": {SuccessRatio: 1},
+		},
+	}, actual["/network/status"])
+	assert.NotContains(t, actual, "/stream")
+}
+
+func TestErrorBudgetMiddlewareWindowExpired(t *testing.T) {
+	// given
+	now := time.Unix(1000000, 0)
+	errorBudget := NewErrorBudget(defaultSloConfig)
+	errorBudget.now = func() time.Time { return now }
+	handler := ErrorBudgetMiddleware(newSloTestHandler(), errorBudget)
+	serveSloRequest(handler, "/block?status=503")
+
+	// when
+	now = now.Add(2 * time.Hour)
+	serveSloRequest(handler, "/block?status=200")
+	actual := errorBudget.Summary()["/block"]
+
+	// then
+	assert.Equal(t, WindowSummary{Requests: 1, SuccessRatio: 1}, actual.Windows["5m0s"])
+	assert.Equal(t, WindowSummary{Requests: 1, SuccessRatio: 1}, actual.Windows["1h0m0s"])
+}
+
+func TestErrorBudgetMiddlewareClientCancelled(t *testing.T) {
+	// given
+	errorBudget := NewErrorBudget(defaultSloConfig)
+	handler := ErrorBudgetMiddleware(newSloTestHandler(), errorBudget)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	request := httptest.NewRequest(http.MethodPost, "http://localhost/block?error=database", nil).WithContext(ctx)
+
+	// when
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	// then
+	assert.Equal(t, uint64(0), errorBudget.Summary()["/block"].Windows["5m0s"].Requests)
+}
+
+func TestErrorBudgetMiddlewareDisabled(t *testing.T) {
+	// given
+	handler := ErrorBudgetMiddleware(newSloTestHandler(), nil)
+	recorder := httptest.NewRecorder()
+
+	// when
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "http://localhost/block?status=503", nil))
+
+	// then
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+}
+
+func TestAdminGetSlo(t *testing.T) {
+	// given
+	errorBudget := NewErrorBudget(defaultSloConfig)
+	ErrorBudgetMiddleware(newSloTestHandler(), errorBudget).ServeHTTP(
+		httptest.NewRecorder(),
+		httptest.NewRequest(http.MethodPost, "http://localhost/block?status=503", nil),
+	)
+	router := server.NewRouter(NewAdminController(config.Admin{Enabled: true, Token: adminToken}, errorBudget, nil, nil))
+
+	// when
+	request := httptest.NewRequest(http.MethodGet, "http://localhost"+sloPath, nil)
+	request.Header.Set(authorizationHeader, "Bearer "+adminToken)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	unauthorizedRecorder := httptest.NewRecorder()
+	router.ServeHTTP(unauthorizedRecorder, httptest.NewRequest(http.MethodGet, "http://localhost"+sloPath, nil))
+
+	// then
+	actual := make(map[string]EndpointSummary)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &actual))
+	assert.Equal(t, errorBudget.Summary(), actual)
+	assert.Equal(t, http.StatusUnauthorized, unauthorizedRecorder.Code)
+}
+
+func TestAdminGetSloDisabled(t *testing.T) {
+	// given
+	router := server.NewRouter(NewAdminController(config.Admin{Enabled: true, Token: adminToken}, nil, nil, nil))
+	request := httptest.NewRequest(http.MethodGet, "http://localhost"+sloPath, nil)
+	request.Header.Set(authorizationHeader, "Bearer "+adminToken)
+	recorder := httptest.NewRecorder()
+
+	// when
+	router.ServeHTTP(recorder, request)
+
+	// then
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+// newSloTestHandler responds with the rosetta error in the error query parameter with 500, otherwise with the status
+// code in the status query parameter
+func newSloTestHandler() http.Handler {
+	rosettaErrors := map[string]*rTypes.Error{
+		"database":  errors.ErrDatabaseError,
+		"not_found": errors.ErrBlockNotFound,
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rosettaError, ok := rosettaErrors[r.URL.Query().Get("error")]; ok {
+			server.EncodeJSONResponse(rosettaError, http.StatusInternalServerError, w)
+			return
+		}
+
+		status := http.StatusOK
+		switch r.URL.Query().Get("status") {
+		case "500":
+			status = http.StatusInternalServerError
+		case "503":
+			status = http.StatusServiceUnavailable
+		}
+		w.WriteHeader(status)
+	})
+}
+
+func serveSloRequest(handler http.Handler, target string) {
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "http://localhost"+target, nil))
+}
+
+// roundSummary rounds the ratios of the summary so the floating point results can be compared
+func roundSummary(summary EndpointSummary) EndpointSummary {
+	for window, windowSummary := range summary.Windows {
+		summary.Windows[window] = roundWindowSummary(windowSummary)
+	}
+	return summary
+}
+
+func roundWindowSummary(summary WindowSummary) WindowSummary {
+	summary.BurnRate = math.Round(summary.BurnRate*1e9) / 1e9
+	summary.SuccessRatio = math.Round(summary.SuccessRatio*1e9) / 1e9
+	return summary
+}
@@ -0,0 +1,99 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const specVersionHeader = "Rosetta-Version"
+
+// specVersionPathPrefix matches the spec version path prefix, e.g., /v1.4.13/block
+var specVersionPathPrefix = regexp.MustCompile(`^/v(\d+(?:\.\d+)*)(/.*)?$`)
+
+// SpecVersionMiddleware negotiates the rosetta spec version of the request, selected either by the Rosetta-Version
+// header or by a /v{version} path prefix which is stripped before routing. The version of the pinned rosetta-sdk-go
+// types is served if none is selected, so existing integrators are unaffected, and the configured spec versions,
+// which are checked to be implemented when the config is loaded, are served alongside it so integrators can migrate
+// gradually. The negotiated version is set in the request context and echoed in the Rosetta-Version response header.
+// A request selecting an unsupported version, or conflicting versions, is rejected with 400
+func SpecVersionMiddleware(next http.Handler, specVersions []string) http.Handler {
+	supported := map[string]bool{rTypes.RosettaAPIVersion: true}
+	for _, version := range specVersions {
+		supported[version] = true
+	}
+
+	supportedVersions := make([]string, 0, len(supported))
+	for version := range supported {
+		supportedVersions = append(supportedVersions, version)
+	}
+	sort.Strings(supportedVersions)
+	supportedVersionsDetail := strings.Join(supportedVersions, ",")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version := r.Header.Get(specVersionHeader)
+		if matches := specVersionPathPrefix.FindStringSubmatch(r.URL.Path); matches != nil {
+			if version != "" && version != matches[1] {
+				writeSpecVersionError(w, r, version+","+matches[1], supportedVersionsDetail)
+				return
+			}
+
+			version = matches[1]
+			r = r.Clone(r.Context())
+			r.URL.Path = matches[2]
+			if r.URL.Path == "" {
+				r.URL.Path = "/"
+			}
+			r.URL.RawPath = ""
+		}
+
+		if version == "" {
+			version = rTypes.RosettaAPIVersion
+		}
+
+		if !supported[version] {
+			writeSpecVersionError(w, r, version, supportedVersionsDetail)
+			return
+		}
+
+		w.Header().Set(specVersionHeader, version)
+		next.ServeHTTP(w, r.WithContext(types.WithSpecVersion(r.Context(), version)))
+	})
+}
+
+func writeSpecVersionError(w http.ResponseWriter, r *http.Request, version, supportedVersions string) {
+	log.Warnf("Rejected %s %s with unsupported rosetta spec version %s", r.Method, r.URL.Path, version)
+	rosettaError := errors.AddErrorDetails(errors.ErrUnsupportedSpecVersion, "supported_versions", supportedVersions)
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusBadRequest)
+	if err := json.NewEncoder(w).Encode(rosettaError); err != nil {
+		log.Errorf("Failed to encode error response: %s", err)
+	}
+}
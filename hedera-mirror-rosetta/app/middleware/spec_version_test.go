@@ -0,0 +1,126 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpecVersionMiddleware(t *testing.T) {
+	tests := []struct {
+		name            string
+		header          string
+		path            string
+		expectedPath    string
+		expectedVersion string
+	}{
+		{name: "default", path: "/block", expectedPath: "/block", expectedVersion: rTypes.RosettaAPIVersion},
+		{name: "header", header: "1.5.0", path: "/block", expectedPath: "/block", expectedVersion: "1.5.0"},
+		{name: "path prefix", path: "/v1.5.0/block", expectedPath: "/block", expectedVersion: "1.5.0"},
+		{
+			name:            "pinned path prefix",
+			path:            "/v" + rTypes.RosettaAPIVersion + "/network/status",
+			expectedPath:    "/network/status",
+			expectedVersion: rTypes.RosettaAPIVersion,
+		},
+		{name: "path prefix only", path: "/v1.5.0", expectedPath: "/", expectedVersion: "1.5.0"},
+		{
+			name:            "header and path prefix",
+			header:          "1.5.0",
+			path:            "/v1.5.0/block",
+			expectedPath:    "/block",
+			expectedVersion: "1.5.0",
+		},
+		{name: "not a version", path: "/version/block", expectedPath: "/version/block", expectedVersion: rTypes.RosettaAPIVersion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var actualPath, actualVersion string
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				actualPath = r.URL.Path
+				actualVersion = types.GetSpecVersion(r.Context())
+				w.WriteHeader(http.StatusOK)
+			})
+			request := httptest.NewRequest("POST", "http://localhost"+tt.path, nil)
+			if tt.header != "" {
+				request.Header.Set(specVersionHeader, tt.header)
+			}
+			recorder := httptest.NewRecorder()
+
+			// when
+			SpecVersionMiddleware(handler, []string{"1.5.0"}).ServeHTTP(recorder, request)
+
+			// then
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, tt.expectedPath, actualPath)
+			assert.Equal(t, tt.expectedVersion, actualVersion)
+			assert.Equal(t, tt.expectedVersion, recorder.Header().Get(specVersionHeader))
+		})
+	}
+}
+
+func TestSpecVersionMiddlewareUnsupported(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		path   string
+	}{
+		{name: "header", header: "1.6.0", path: "/block"},
+		{name: "path prefix", path: "/v1.6.0/block"},
+		{name: "conflicting", header: "1.5.0", path: "/v" + rTypes.RosettaAPIVersion + "/block"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			called := false
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			})
+			request := httptest.NewRequest("POST", "http://localhost"+tt.path, nil)
+			if tt.header != "" {
+				request.Header.Set(specVersionHeader, tt.header)
+			}
+			recorder := httptest.NewRecorder()
+
+			// when
+			SpecVersionMiddleware(handler, []string{"1.5.0"}).ServeHTTP(recorder, request)
+
+			// then
+			rosettaError := &rTypes.Error{}
+			assert.False(t, called)
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), rosettaError))
+			assert.Equal(t, errors.ErrUnsupportedSpecVersion.Code, rosettaError.Code)
+			assert.Equal(t, rTypes.RosettaAPIVersion+",1.5.0", rosettaError.Details["supported_versions"])
+		})
+	}
+}
@@ -0,0 +1,57 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/db"
+	log "github.com/sirupsen/logrus"
+)
+
+const sqlTraceHeader = "X-Sql-Trace"
+
+// SqlTraceMiddleware enables the SQL trace for the request if it has the X-Sql-Trace header set to true and the admin
+// token as the bearer token, so the statements of just that request are logged with their parameters and timing. An
+// unauthorized request is served without the trace
+func SqlTraceMiddleware(next http.Handler, adminConfig config.Admin) http.Handler {
+	if !adminConfig.Enabled {
+		return next
+	}
+
+	token := []byte(adminConfig.Token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if enabled, _ := strconv.ParseBool(r.Header.Get(sqlTraceHeader)); !enabled {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !isAuthorized(r, token) {
+			log.Warnf("Ignoring unauthorized SQL trace of %s %s", r.Method, r.URL.Path)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		log.Infof("Tracing SQL of %s %s", r.Method, r.URL.Path)
+		next.ServeHTTP(w, r.WithContext(db.WithSqlTrace(r.Context())))
+	})
+}
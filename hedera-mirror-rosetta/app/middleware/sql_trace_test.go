@@ -0,0 +1,74 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSqlTraceMiddleware(t *testing.T) {
+	var tests = []struct {
+		name          string
+		adminConfig   config.Admin
+		authorization string
+		header        string
+		expected      bool
+	}{
+		{name: "enabled", adminConfig: config.Admin{Enabled: true, Token: adminToken},
+			authorization: "Bearer " + adminToken, header: "true", expected: true},
+		{name: "admin disabled", adminConfig: config.Admin{Token: adminToken},
+			authorization: "Bearer " + adminToken, header: "true"},
+		{name: "no header", adminConfig: config.Admin{Enabled: true, Token: adminToken},
+			authorization: "Bearer " + adminToken},
+		{name: "header false", adminConfig: config.Admin{Enabled: true, Token: adminToken},
+			authorization: "Bearer " + adminToken, header: "false"},
+		{name: "unauthorized", adminConfig: config.Admin{Enabled: true, Token: adminToken},
+			authorization: "Bearer foo", header: "true"},
+		{name: "token not configured", adminConfig: config.Admin{Enabled: true},
+			authorization: "Bearer ", header: "true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var traced bool
+			handler := SqlTraceMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				traced = db.IsSqlTraceEnabled(r.Context())
+			}), tt.adminConfig)
+			request := httptest.NewRequest("POST", "/block", nil)
+			request.Header.Set(authorizationHeader, tt.authorization)
+			if tt.header != "" {
+				request.Header.Set(sqlTraceHeader, tt.header)
+			}
+
+			// when
+			handler.ServeHTTP(httptest.NewRecorder(), request)
+
+			// then
+			assert.Equal(t, tt.expected, traced)
+		})
+	}
+}
@@ -0,0 +1,246 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/server"
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/redaction"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	blockEventType                 = "block"
+	defaultBlockStreamPollInterval = time.Second
+	blockStreamPath                = "/stream/blocks"
+	lastEventIdHeader              = "Last-Event-ID"
+	startIndexQueryParam           = "start_index"
+)
+
+// blockEvent is the data of a block event, the full block can be retrieved with the /block endpoint
+type blockEvent struct {
+	BlockIdentifier       *rTypes.BlockIdentifier `json:"block_identifier"`
+	ParentBlockIdentifier *rTypes.BlockIdentifier `json:"parent_block_identifier"`
+	Timestamp             int64                   `json:"timestamp"`
+}
+
+// blockStreamController holds data used to stream the new blocks to the subscribers
+type blockStreamController struct {
+	blockRepo   interfaces.BlockRepository
+	config      config.Stream
+	redactor    *redaction.Redactor
+	subscribers int32
+}
+
+// NewBlockStreamController constructs a new BlockStreamController object. The block events are redacted with the same
+// redactor as the http responses
+func NewBlockStreamController(
+	blockRepo interfaces.BlockRepository,
+	streamConfig config.Stream,
+	redactor *redaction.Redactor,
+) server.Router {
+	if streamConfig.PollInterval <= 0 {
+		streamConfig.PollInterval = defaultBlockStreamPollInterval
+	}
+	return &blockStreamController{blockRepo: blockRepo, config: streamConfig, redactor: redactor}
+}
+
+// Routes returns the block stream controller routes
+func (c *blockStreamController) Routes() server.Routes {
+	return server.Routes{
+		{
+			"blockStream",
+			"GET",
+			blockStreamPath,
+			c.Stream,
+		},
+	}
+}
+
+// Stream pushes the new blocks to the subscriber as server-sent events with the block index as the event id. The
+// stream starts from the block after the Last-Event-ID header, the start_index query parameter, or the latest block,
+// in that order of precedence, and ends after the configured max duration so the subscriber reconnects and resumes
+func (c *blockStreamController) Stream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		log.Error("Streaming is not supported by the response writer")
+		writeStreamError(w, http.StatusInternalServerError, errors.ErrInternalServerError)
+		return
+	}
+
+	nextIndex, err := getStartIndex(r)
+	if err != nil {
+		writeStreamError(w, http.StatusBadRequest, errors.AddErrorDetails(errors.ErrInvalidArgument, "reason", err.Error()))
+		return
+	}
+
+	if c.config.MaxSubscribers > 0 {
+		defer atomic.AddInt32(&c.subscribers, -1)
+		if atomic.AddInt32(&c.subscribers, 1) > int32(c.config.MaxSubscribers) {
+			log.Warnf("Rejected block stream subscriber with %d subscribers", c.config.MaxSubscribers)
+			writeStreamError(w, http.StatusServiceUnavailable, errors.ErrTooManyConcurrentRequests)
+			return
+		}
+	}
+
+	ctx := r.Context()
+	if c.config.MaxDuration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.MaxDuration)
+		defer cancel()
+	}
+
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if _, err = fmt.Fprintf(w, "retry: %d\n\n", c.config.PollInterval.Milliseconds()); err != nil {
+		return
+	}
+	flusher.Flush()
+
+	ticker := time.NewTicker(c.config.PollInterval)
+	defer ticker.Stop()
+
+	lastWrite := time.Now()
+	for {
+		sent, err := c.sendNewBlocks(ctx, w, &nextIndex)
+		if err != nil {
+			log.Debugf("Failed to write block event: %s", err)
+			return
+		}
+
+		if sent != 0 {
+			flusher.Flush()
+			lastWrite = time.Now()
+		} else if c.config.KeepAlive > 0 && time.Since(lastWrite) >= c.config.KeepAlive {
+			if _, err = fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+			lastWrite = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendNewBlocks writes the events of the blocks from nextIndex to the latest block and advances nextIndex past the last
+// block sent. A negative nextIndex starts from the latest block. Only the error writing to the subscriber is returned,
+// the failed queries are retried in the next poll
+func (c *blockStreamController) sendNewBlocks(ctx context.Context, w http.ResponseWriter, nextIndex *int64) (
+	int,
+	error,
+) {
+	latest, rErr := c.blockRepo.RetrieveLatest(ctx)
+	if rErr != nil {
+		if ctx.Err() == nil {
+			log.Errorf("Failed to retrieve the latest block: %s", rErr.Message)
+		}
+		return 0, nil
+	}
+
+	if *nextIndex < 0 {
+		*nextIndex = latest.Index
+	}
+
+	sent := 0
+	for index := *nextIndex; index <= latest.Index; index++ {
+		block := latest
+		if index != latest.Index {
+			if block, rErr = c.blockRepo.FindByIndex(ctx, index); rErr != nil {
+				if ctx.Err() == nil {
+					log.Errorf("Failed to find block %d: %s", index, rErr.Message)
+				}
+				break
+			}
+		}
+
+		if err := writeBlockEvent(w, block, c.redactor); err != nil {
+			return sent, err
+		}
+
+		*nextIndex = index + 1
+		sent++
+	}
+
+	return sent, nil
+}
+
+// getStartIndex returns the index of the first block to stream, or -1 to start from the latest block
+func getStartIndex(r *http.Request) (int64, error) {
+	if lastEventId := r.Header.Get(lastEventIdHeader); lastEventId != "" {
+		index, err := strconv.ParseInt(lastEventId, 10, 64)
+		if err != nil || index < 0 {
+			return 0, fmt.Errorf("invalid %s header %s", lastEventIdHeader, lastEventId)
+		}
+		return index + 1, nil
+	}
+
+	if startIndex := r.URL.Query().Get(startIndexQueryParam); startIndex != "" {
+		index, err := strconv.ParseInt(startIndex, 10, 64)
+		if err != nil || index < 0 {
+			return 0, fmt.Errorf("invalid %s query parameter %s", startIndexQueryParam, startIndex)
+		}
+		return index, nil
+	}
+
+	return -1, nil
+}
+
+func writeBlockEvent(w http.ResponseWriter, block *types.Block, redactor *redaction.Redactor) error {
+	rosettaBlock := block.ToRosetta()
+	data, err := json.Marshal(blockEvent{
+		BlockIdentifier:       rosettaBlock.BlockIdentifier,
+		ParentBlockIdentifier: rosettaBlock.ParentBlockIdentifier,
+		Timestamp:             rosettaBlock.Timestamp,
+	})
+	if err != nil {
+		return err
+	}
+
+	data = redactor.Redact(data)
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", block.Index, blockEventType, data)
+	return err
+}
+
+func writeStreamError(w http.ResponseWriter, statusCode int, rosettaError *rTypes.Error) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(rosettaError); err != nil {
+		log.Errorf("Failed to encode error response: %s", err)
+	}
+}
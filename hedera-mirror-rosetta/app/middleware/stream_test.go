@@ -0,0 +1,244 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package middleware
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sseEvent struct {
+	data      string
+	eventType string
+	id        string
+}
+
+func TestBlockStream(t *testing.T) {
+	// given
+	blockRepo := &mocks.MockBlockRepository{}
+	blockRepo.On("RetrieveLatest").Return(newStreamBlock(10), mocks.NilError).Once()
+	blockRepo.On("RetrieveLatest").Return(newStreamBlock(12), mocks.NilError)
+	blockRepo.On("FindByIndex").Return(newStreamBlock(11), mocks.NilError).Once()
+	server := newBlockStreamServer(blockRepo, config.Stream{PollInterval: 10 * time.Millisecond})
+	defer server.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// when
+	response := getBlockStream(t, ctx, server.URL+blockStreamPath, "")
+	defer response.Body.Close()
+	events := readEvents(t, response.Body, 3)
+
+	// then
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "text/event-stream", response.Header.Get("Content-Type"))
+	assert.Equal(t, "no-cache", response.Header.Get("Cache-Control"))
+	for i, event := range events {
+		index := int64(10 + i)
+		assert.Equal(t, strconv.FormatInt(index, 10), event.id)
+		assert.Equal(t, blockEventType, event.eventType)
+		assert.JSONEq(t, toBlockEventJson(t, newStreamBlock(index)), event.data)
+	}
+	blockRepo.AssertNumberOfCalls(t, "FindByIndex", 1)
+}
+
+func TestBlockStreamResume(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		lastEventId string
+		query       string
+	}{
+		{name: "LastEventId", lastEventId: "10"},
+		{name: "StartIndex", query: "?start_index=11"},
+		{name: "LastEventIdTakesPrecedence", lastEventId: "10", query: "?start_index=5"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			blockRepo := &mocks.MockBlockRepository{}
+			blockRepo.On("RetrieveLatest").Return(newStreamBlock(12), mocks.NilError)
+			blockRepo.On("FindByIndex").Return(newStreamBlock(11), mocks.NilError).Once()
+			server := newBlockStreamServer(blockRepo, config.Stream{PollInterval: 10 * time.Millisecond})
+			defer server.Close()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			// when
+			response := getBlockStream(t, ctx, server.URL+blockStreamPath+tt.query, tt.lastEventId)
+			defer response.Body.Close()
+			events := readEvents(t, response.Body, 2)
+
+			// then
+			assert.Equal(t, "11", events[0].id)
+			assert.Equal(t, "12", events[1].id)
+			blockRepo.AssertNumberOfCalls(t, "FindByIndex", 1)
+		})
+	}
+}
+
+func TestBlockStreamInvalidStartIndex(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		lastEventId string
+		query       string
+	}{
+		{name: "LastEventIdNotNumber", lastEventId: "abc"},
+		{name: "LastEventIdNegative", lastEventId: "-1"},
+		{name: "StartIndexNotNumber", query: "?start_index=abc"},
+		{name: "StartIndexNegative", query: "?start_index=-1"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			controller := NewBlockStreamController(&mocks.MockBlockRepository{}, config.Stream{}, nil)
+			request := httptest.NewRequest("GET", "http://localhost"+blockStreamPath+tt.query, nil)
+			if tt.lastEventId != "" {
+				request.Header.Set(lastEventIdHeader, tt.lastEventId)
+			}
+			recorder := httptest.NewRecorder()
+
+			// when
+			controller.Routes()[0].HandlerFunc.ServeHTTP(recorder, request)
+
+			// then
+			rosettaError := &rTypes.Error{}
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), rosettaError))
+			assert.Equal(t, errors.ErrInvalidArgument.Code, rosettaError.Code)
+		})
+	}
+}
+
+func TestBlockStreamTooManySubscribers(t *testing.T) {
+	// given
+	controller := NewBlockStreamController(&mocks.MockBlockRepository{}, config.Stream{MaxSubscribers: 1}, nil)
+	controller.(*blockStreamController).subscribers = 1
+	recorder := httptest.NewRecorder()
+
+	// when
+	controller.Routes()[0].HandlerFunc.ServeHTTP(recorder, httptest.NewRequest("GET", blockStreamPath, nil))
+
+	// then
+	rosettaError := &rTypes.Error{}
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), rosettaError))
+	assert.Equal(t, errors.ErrTooManyConcurrentRequests, rosettaError)
+	assert.Equal(t, int32(1), controller.(*blockStreamController).subscribers)
+}
+
+func TestBlockStreamMaxDurationAndKeepAlive(t *testing.T) {
+	// given
+	blockRepo := &mocks.MockBlockRepository{}
+	blockRepo.On("RetrieveLatest").Return(mocks.NilBlock, errors.ErrDatabaseError)
+	controller := NewBlockStreamController(blockRepo, config.Stream{
+		KeepAlive:      20 * time.Millisecond,
+		MaxDuration:    100 * time.Millisecond,
+		MaxSubscribers: 1,
+		PollInterval:   10 * time.Millisecond,
+	}, nil)
+	recorder := httptest.NewRecorder()
+
+	// when
+	controller.Routes()[0].HandlerFunc.ServeHTTP(recorder, httptest.NewRequest("GET", blockStreamPath, nil))
+
+	// then
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.True(t, strings.HasPrefix(recorder.Body.String(), "retry: 10\n\n"))
+	assert.Contains(t, recorder.Body.String(), ": keepalive\n\n")
+	assert.NotContains(t, recorder.Body.String(), "id:")
+	assert.Equal(t, int32(0), controller.(*blockStreamController).subscribers)
+}
+
+func getBlockStream(t *testing.T, ctx context.Context, url, lastEventId string) *http.Response {
+	request, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	require.NoError(t, err)
+	if lastEventId != "" {
+		request.Header.Set(lastEventIdHeader, lastEventId)
+	}
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	return response
+}
+
+func newBlockStreamServer(blockRepo *mocks.MockBlockRepository, streamConfig config.Stream) *httptest.Server {
+	controller := NewBlockStreamController(blockRepo, streamConfig, nil)
+	return httptest.NewServer(TracingMiddleware(controller.Routes()[0].HandlerFunc))
+}
+
+func newStreamBlock(index int64) *types.Block {
+	return &types.Block{
+		ConsensusEndNanos:   index*10 + 9,
+		ConsensusStartNanos: index * 10,
+		Hash:                strconv.FormatInt(index, 16),
+		Index:               index,
+		ParentHash:          strconv.FormatInt(index-1, 16),
+		ParentIndex:         index - 1,
+	}
+}
+
+// readEvents reads the count of events from the stream, it blocks until the events are flushed by the server
+func readEvents(t *testing.T, body io.Reader, count int) []sseEvent {
+	events := make([]sseEvent, 0, count)
+	event := sseEvent{}
+	scanner := bufio.NewScanner(body)
+	for len(events) < count && scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if event.id != "" {
+				events = append(events, event)
+			}
+			event = sseEvent{}
+		case strings.HasPrefix(line, "data: "):
+			event.data = strings.TrimPrefix(line, "data: ")
+		case strings.HasPrefix(line, "event: "):
+			event.eventType = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "id: "):
+			event.id = strings.TrimPrefix(line, "id: ")
+		}
+	}
+	require.Len(t, events, count)
+	return events
+}
+
+func toBlockEventJson(t *testing.T, block *types.Block) string {
+	rosettaBlock := block.ToRosetta()
+	data, err := json.Marshal(map[string]interface{}{
+		"block_identifier":        rosettaBlock.BlockIdentifier,
+		"parent_block_identifier": rosettaBlock.ParentBlockIdentifier,
+		"timestamp":               rosettaBlock.Timestamp,
+	})
+	require.NoError(t, err)
+	return string(data)
+}
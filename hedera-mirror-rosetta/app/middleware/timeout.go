@@ -0,0 +1,55 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// EndpointTimeoutMiddleware sets a deadline on the context of the requests to the endpoints with a configured timeout,
+// keyed by the endpoint path, e.g., /block. The services stop the in-flight database queries once the deadline is
+// exceeded and respond with a retriable error or a degraded response. A non-positive timeout is ignored
+func EndpointTimeoutMiddleware(next http.Handler, timeouts map[string]time.Duration) http.Handler {
+	effective := make(map[string]time.Duration)
+	for path, timeout := range timeouts {
+		if timeout > 0 {
+			effective[path] = timeout
+		}
+	}
+
+	if len(effective) == 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timeout, ok := effective[r.URL.Path]
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
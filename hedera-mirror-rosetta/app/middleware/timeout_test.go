@@ -0,0 +1,68 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndpointTimeoutMiddleware(t *testing.T) {
+	// given
+	deadlines := make(map[string]bool)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadline, ok := r.Context().Deadline()
+		deadlines[r.URL.Path] = ok
+		if ok {
+			assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, 5*time.Second)
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	timeouts := map[string]time.Duration{"/block": time.Minute, "/network/status": 0}
+	wrapped := EndpointTimeoutMiddleware(handler, timeouts)
+
+	// when
+	for _, path := range []string{"/block", "/block/transaction", "/network/status"} {
+		wrapped.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "http://localhost"+path, nil))
+	}
+
+	// then
+	assert.Equal(t, map[string]bool{"/block": true, "/block/transaction": false, "/network/status": false}, deadlines)
+}
+
+func TestEndpointTimeoutMiddlewareDisabled(t *testing.T) {
+	// given
+	hasDeadline := true
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, hasDeadline = r.Context().Deadline()
+	})
+	wrapped := EndpointTimeoutMiddleware(handler, nil)
+
+	// when
+	wrapped.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "http://localhost/block", nil))
+
+	// then
+	assert.False(t, hasDeadline)
+}
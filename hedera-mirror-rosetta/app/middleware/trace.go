@@ -26,6 +26,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/db"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -34,7 +35,7 @@ const (
 	xRealIpHeader       = "X-Real-IP"
 )
 
-var internalPaths = map[string]bool{livenessPath: true, metricsPath: true, readinessPath: true}
+var internalPaths = map[string]bool{infoPath: true, livenessPath: true, metricsPath: true, readinessPath: true}
 
 // tracingResponseWriter wraps a regular ResponseWriter in order to store the HTTP status code
 type tracingResponseWriter struct {
@@ -57,15 +58,24 @@ func (w *tracingResponseWriter) Write(data []byte) (n int, err error) {
 	return w.ResponseWriter.Write(data)
 }
 
-// TracingMiddleware traces requests to the log
+// Flush sends the buffered data to the client if supported by the wrapped ResponseWriter, required by the block stream
+func (w *tracingResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// TracingMiddleware traces requests to the log. The endpoint is added to the request context, so the slow queries run
+// on behalf of the request are logged with it, and the queries are pinned to the same database for the request
 func TracingMiddleware(inner http.Handler) http.Handler {
 	return http.HandlerFunc(func(responseWriter http.ResponseWriter, request *http.Request) {
 		start := time.Now()
 		clientIpAddress := getClientIpAddress(request)
 		path := request.URL.RequestURI()
 		tracingResponseWriter := newTracingResponseWriter(responseWriter)
+		ctx := db.WithDbClientPinning(db.WithEndpoint(request.Context(), request.URL.Path))
 
-		inner.ServeHTTP(tracingResponseWriter, request)
+		inner.ServeHTTP(tracingResponseWriter, request.WithContext(ctx))
 
 		message := fmt.Sprintf("%s %s %s (%d) in %s",
 			clientIpAddress, request.Method, path, tracingResponseWriter.statusCode, time.Since(start))
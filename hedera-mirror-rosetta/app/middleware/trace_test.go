@@ -29,7 +29,9 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/db"
 	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -102,3 +104,18 @@ func TestTrace(t *testing.T) {
 		log.SetLevel(level)
 	}
 }
+
+func TestTraceSetsEndpoint(t *testing.T) {
+	// given
+	var actual string
+	handler := TracingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actual = db.GetEndpoint(r.Context())
+	}))
+	req := httptest.NewRequest("POST", "http://localhost/block?foo=bar", nil)
+
+	// when
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// then
+	assert.Equal(t, "/block", actual)
+}
@@ -0,0 +1,286 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultUsageExportInterval = time.Minute
+	// usageApiKeyHashLength is the number of hex characters of the api key hash identifying a client, so the api keys
+	// themselves never show up in the metrics or the csv file
+	usageApiKeyHashLength = 16
+	usageOtherClient      = "other"
+)
+
+var (
+	usageCsvHeader = []string{"timestamp", "client", "requests", "request_bytes", "response_bytes"}
+
+	usageRequestBytesCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "hedera_mirror_rosetta_usage_request_bytes",
+		Help: "Total size (in bytes) of the requests received from the client.",
+	}, []string{"client"})
+
+	usageRequestsCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "hedera_mirror_rosetta_usage_requests",
+		Help: "Total number of requests served to the client.",
+	}, []string{"client"})
+
+	usageResponseBytesCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "hedera_mirror_rosetta_usage_response_bytes",
+		Help: "Total size (in bytes) of the responses sent to the client.",
+	}, []string{"client"})
+)
+
+func init() {
+	register := prometheus.WrapRegistererWith(prometheus.Labels{"application": application}, prometheus.DefaultRegisterer)
+	register.MustRegister(usageRequestBytesCounter)
+	register.MustRegister(usageRequestsCounter)
+	register.MustRegister(usageResponseBytesCounter)
+}
+
+// ClientUsage is the usage of a client over an export interval
+type ClientUsage struct {
+	RequestBytes  uint64
+	Requests      uint64
+	ResponseBytes uint64
+}
+
+// UsageTracker accounts the requests and bytes served per client, identified by the hash of its api key or by its ip
+// address, and periodically exports the usage since the last export
+type UsageTracker struct {
+	apiKeyHeader   string
+	clients        map[string]bool
+	csvPath        string
+	exportInterval time.Duration
+	maxClients     int
+	mutex          sync.Mutex
+	now            func() time.Time
+	usage          map[string]*ClientUsage
+}
+
+// NewUsageTracker creates the usage tracker, or returns nil if the usage accounting is disabled
+func NewUsageTracker(usageConfig config.Usage) *UsageTracker {
+	if !usageConfig.Enabled {
+		return nil
+	}
+
+	exportInterval := usageConfig.ExportInterval
+	if exportInterval <= 0 {
+		exportInterval = defaultUsageExportInterval
+	}
+
+	return &UsageTracker{
+		apiKeyHeader:   usageConfig.ApiKeyHeader,
+		clients:        make(map[string]bool),
+		csvPath:        usageConfig.CsvPath,
+		exportInterval: exportInterval,
+		maxClients:     usageConfig.MaxClients,
+		now:            time.Now,
+		usage:          make(map[string]*ClientUsage),
+	}
+}
+
+// Run exports the usage every export interval until the context is done, then exports the remaining usage
+func (u *UsageTracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(u.exportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			u.export()
+			return
+		case <-ticker.C:
+			u.export()
+		}
+	}
+}
+
+// export adds the usage since the last export to the metrics and appends it to the csv file if configured
+func (u *UsageTracker) export() {
+	u.mutex.Lock()
+	usage := u.usage
+	u.usage = make(map[string]*ClientUsage)
+	u.mutex.Unlock()
+
+	if len(usage) == 0 {
+		return
+	}
+
+	clients := make([]string, 0, len(usage))
+	for client, clientUsage := range usage {
+		clients = append(clients, client)
+		usageRequestBytesCounter.WithLabelValues(client).Add(float64(clientUsage.RequestBytes))
+		usageRequestsCounter.WithLabelValues(client).Add(float64(clientUsage.Requests))
+		usageResponseBytesCounter.WithLabelValues(client).Add(float64(clientUsage.ResponseBytes))
+	}
+
+	if u.csvPath == "" {
+		return
+	}
+
+	sort.Strings(clients)
+	if err := u.appendCsv(clients, usage); err != nil {
+		log.Errorf("Failed to export the usage of %d clients to %s: %s", len(clients), u.csvPath, err)
+	}
+}
+
+func (u *UsageTracker) appendCsv(clients []string, usage map[string]*ClientUsage) error {
+	file, err := os.OpenFile(u.csvPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(file)
+	if info.Size() == 0 {
+		if err = writer.Write(usageCsvHeader); err != nil {
+			return err
+		}
+	}
+
+	timestamp := u.now().UTC().Format(time.RFC3339)
+	for _, client := range clients {
+		clientUsage := usage[client]
+		record := []string{
+			timestamp,
+			client,
+			strconv.FormatUint(clientUsage.Requests, 10),
+			strconv.FormatUint(clientUsage.RequestBytes, 10),
+			strconv.FormatUint(clientUsage.ResponseBytes, 10),
+		}
+		if err = writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// getClient returns the client of the request, the hash of its api key if present, otherwise its ip address
+func (u *UsageTracker) getClient(r *http.Request) string {
+	if u.apiKeyHeader != "" {
+		if apiKey := r.Header.Get(u.apiKeyHeader); apiKey != "" {
+			hash := sha256.Sum256([]byte(apiKey))
+			return "key:" + hex.EncodeToString(hash[:])[:usageApiKeyHashLength]
+		}
+	}
+
+	return getClientIpAddress(r)
+}
+
+// record adds a request to the usage of the client. Once max clients are tracked, the usage of a new client is
+// accounted to the other client
+func (u *UsageTracker) record(client string, requestBytes, responseBytes uint64) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	if !u.clients[client] {
+		if u.maxClients > 0 && len(u.clients) >= u.maxClients {
+			client = usageOtherClient
+		} else {
+			u.clients[client] = true
+		}
+	}
+
+	clientUsage, ok := u.usage[client]
+	if !ok {
+		clientUsage = &ClientUsage{}
+		u.usage[client] = clientUsage
+	}
+	clientUsage.RequestBytes += requestBytes
+	clientUsage.Requests++
+	clientUsage.ResponseBytes += responseBytes
+}
+
+// usageRequestBody counts the bytes read from the request body
+type usageRequestBody struct {
+	io.ReadCloser
+	bytes uint64
+}
+
+func (b *usageRequestBody) Read(data []byte) (int, error) {
+	n, err := b.ReadCloser.Read(data)
+	b.bytes += uint64(n)
+	return n, err
+}
+
+// usageResponseWriter counts the bytes written to the response body
+type usageResponseWriter struct {
+	http.ResponseWriter
+	bytes uint64
+}
+
+func (w *usageResponseWriter) Write(data []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(data)
+	w.bytes += uint64(n)
+	return n, err
+}
+
+// Flush sends the buffered data to the client if supported by the wrapped ResponseWriter, required by the block stream
+func (w *usageResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// UsageMiddleware accounts the requests and the request and response bytes to the client of each request. The internal
+// endpoints aren't accounted, and the request is served as is if the tracker is nil
+func UsageMiddleware(next http.Handler, usage *UsageTracker) http.Handler {
+	if usage == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if internalPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		requestBody := &usageRequestBody{ReadCloser: r.Body}
+		r.Body = requestBody
+		usageWriter := &usageResponseWriter{ResponseWriter: w}
+		next.ServeHTTP(usageWriter, r)
+		usage.record(usage.getClient(r), requestBody.bytes, usageWriter.bytes)
+	})
+}
@@ -0,0 +1,170 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	usageTestApiKeyHeader = "X-Api-Key"
+	// usageTestApiKeyClient is the client of the api key "secret"
+	usageTestApiKeyClient = "key:2bb80d537b1da3e3"
+)
+
+func newUsageTestHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.ReadAll(r.Body)
+		_, _ = w.Write([]byte("0123456789"))
+	})
+}
+
+func serveUsageRequest(handler http.Handler, path, body, apiKey string) {
+	request := httptest.NewRequest("POST", "http://localhost"+path, strings.NewReader(body))
+	request.RemoteAddr = "10.0.0.1:1234"
+	if apiKey != "" {
+		request.Header.Set(usageTestApiKeyHeader, apiKey)
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+}
+
+func TestNewUsageTrackerDisabled(t *testing.T) {
+	assert.Nil(t, NewUsageTracker(config.Usage{ApiKeyHeader: usageTestApiKeyHeader}))
+}
+
+func TestNewUsageTrackerDefaultExportInterval(t *testing.T) {
+	// when
+	actual := NewUsageTracker(config.Usage{Enabled: true})
+
+	// then
+	assert.Equal(t, defaultUsageExportInterval, actual.exportInterval)
+}
+
+func TestUsageMiddleware(t *testing.T) {
+	// given
+	usage := NewUsageTracker(config.Usage{ApiKeyHeader: usageTestApiKeyHeader, Enabled: true, MaxClients: 2})
+	handler := UsageMiddleware(newUsageTestHandler(), usage)
+
+	// when
+	serveUsageRequest(handler, "/block", "{}", "")
+	serveUsageRequest(handler, "/account/balance", "{\"a\":1}", "")
+	serveUsageRequest(handler, "/block", "{}", "secret")
+	serveUsageRequest(handler, "/block", "{}", "another")
+	serveUsageRequest(handler, metricsPath, "", "")
+
+	// then
+	assert.Equal(t, map[string]*ClientUsage{
+		"10.0.0.1":            {RequestBytes: 9, Requests: 2, ResponseBytes: 20},
+		usageTestApiKeyClient: {RequestBytes: 2, Requests: 1, ResponseBytes: 10},
+		usageOtherClient:      {RequestBytes: 2, Requests: 1, ResponseBytes: 10},
+	}, usage.usage)
+}
+
+func TestUsageMiddlewareDisabled(t *testing.T) {
+	// given
+	handler := newUsageTestHandler()
+
+	// when
+	actual := UsageMiddleware(handler, nil)
+
+	// then
+	assert.NotNil(t, actual)
+	serveUsageRequest(actual, "/block", "{}", "")
+}
+
+func TestUsageTrackerExport(t *testing.T) {
+	// given
+	csvPath := filepath.Join(t.TempDir(), "usage.csv")
+	usage := NewUsageTracker(config.Usage{
+		ApiKeyHeader: usageTestApiKeyHeader,
+		CsvPath:      csvPath,
+		Enabled:      true,
+	})
+	usage.now = func() time.Time { return time.Unix(1000000, 0) }
+	handler := UsageMiddleware(newUsageTestHandler(), usage)
+	client := "10.0.0.1"
+	requests := testutil.ToFloat64(usageRequestsCounter.WithLabelValues(client))
+	responseBytes := testutil.ToFloat64(usageResponseBytesCounter.WithLabelValues(client))
+
+	// when
+	serveUsageRequest(handler, "/block", "{}", "")
+	serveUsageRequest(handler, "/block", "{}", "secret")
+	usage.export()
+	serveUsageRequest(handler, "/block", "{}", "")
+	usage.export()
+	usage.export()
+
+	// then
+	assert.Empty(t, usage.usage)
+	assert.Equal(t, requests+2, testutil.ToFloat64(usageRequestsCounter.WithLabelValues(client)))
+	assert.Equal(t, responseBytes+20, testutil.ToFloat64(usageResponseBytesCounter.WithLabelValues(client)))
+	actual, err := os.ReadFile(csvPath)
+	require.NoError(t, err)
+	assert.Equal(t, "timestamp,client,requests,request_bytes,response_bytes\n"+
+		"1970-01-12T13:46:40Z,10.0.0.1,1,2,10\n"+
+		"1970-01-12T13:46:40Z,"+usageTestApiKeyClient+",1,2,10\n"+
+		"1970-01-12T13:46:40Z,10.0.0.1,1,2,10\n", string(actual))
+}
+
+func TestUsageTrackerExportCsvError(t *testing.T) {
+	// given
+	usage := NewUsageTracker(config.Usage{CsvPath: t.TempDir(), Enabled: true})
+	usage.record("10.0.0.2", 1, 1)
+
+	// when
+	usage.export()
+
+	// then
+	assert.Empty(t, usage.usage)
+}
+
+func TestUsageTrackerRun(t *testing.T) {
+	// given
+	usage := NewUsageTracker(config.Usage{Enabled: true, ExportInterval: time.Millisecond})
+	usage.record("10.0.0.3", 1, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	// when
+	go func() {
+		usage.Run(ctx)
+		close(done)
+	}()
+
+	// then
+	assert.Eventually(t, func() bool {
+		return testutil.ToFloat64(usageRequestsCounter.WithLabelValues("10.0.0.3")) == 1
+	}, time.Second, time.Millisecond)
+	cancel()
+	<-done
+}
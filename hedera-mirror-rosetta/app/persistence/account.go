@@ -27,8 +27,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 
 	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
 	hErrors "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
@@ -86,6 +88,23 @@ const (
                                   order by t.token_id, ta.modified_timestamp desc
                                 ) as associations
                               ) as token_associations`
+	// countTransfersBetween counts the account's crypto and token transfers in the timestamp range (start, end], up to
+	// the limit of each kind
+	countTransfersBetween = `select count(*) from (
+                               (
+                                 select 1 from crypto_transfer
+                                 where consensus_timestamp > @start and consensus_timestamp <= @end and
+                                   entity_id = @account_id
+                                 limit @limit
+                               )
+                               union all
+                               (
+                                 select 1 from token_transfer
+                                 where consensus_timestamp > @start and consensus_timestamp <= @end and
+                                   account_id = @account_id
+                                 limit @limit
+                               )
+                             ) as transfers`
 	latestBalanceBeforeConsensus = "with" + genesisTimestampCte + `, abm as (
                                       select consensus_timestamp as max, time_offset
                                       from account_balance_file
@@ -111,18 +130,287 @@ const (
                                     from abm
                                     left join account_balance ab
                                       on ab.consensus_timestamp = abm.max and ab.account_id = @account_id`
-	selectCryptoEntityWithAliasById = "select alias, id from entity where id = @id"
-	selectCryptoEntityByAlias       = `select id, deleted, timestamp_range
+	// selectHbarBalancesAtTimestamp selects the hbar balances of a set of accounts at the timestamp in one query. The
+	// balance of each account is its balance in the latest balance snapshot at or before the timestamp plus the sum of
+	// its crypto transfers after the snapshot till the timestamp. Note no row is returned if there's no snapshot
+	selectHbarBalancesAtTimestamp = `with abf as (
+                                       select consensus_timestamp, time_offset
+                                       from account_balance_file
+                                       where consensus_timestamp <= @timestamp
+                                       order by consensus_timestamp desc
+                                       limit 1
+                                     )
+                                     select
+                                       a.id,
+                                       coalesce((
+                                         select balance
+                                         from account_balance ab
+                                         where ab.consensus_timestamp = abf.consensus_timestamp and ab.account_id = a.id
+                                       ), 0) + coalesce((
+                                         select sum(amount)
+                                         from crypto_transfer ct
+                                         where
+                                           ct.consensus_timestamp > abf.consensus_timestamp + abf.time_offset and
+                                           ct.consensus_timestamp <= @timestamp and
+                                           ct.entity_id = a.id and
+                                           (ct.errata is null or ct.errata <> 'DELETE')
+                                       ), 0) as balance
+                                     from unnest(@account_ids::bigint[]) as a(id)
+                                     cross join abf`
+	// selectGenesisAccountsInRange selects the accounts in the id range with a non-zero hbar balance in the genesis
+	// balance snapshot. A single null row is returned if there are no such accounts, and no row if there's no snapshot
+	selectGenesisAccountsInRange = `with genesis as (
+                                      select consensus_timestamp
+                                      from account_balance_file
+                                      order by consensus_timestamp
+                                      limit 1
+                                    )
+                                    select ab.account_id
+                                    from genesis
+                                    left join account_balance ab
+                                      on ab.consensus_timestamp = genesis.consensus_timestamp and
+                                        ab.account_id between @min_account_id and @max_account_id and
+                                        ab.balance <> 0
+                                    order by ab.account_id`
+	// selectAllBalancesAtTimestamp selects the non-zero hbar and token balances of all accounts at the timestamp in one
+	// query, ordered by account and token. A balance is the one in the latest balance snapshot at or before the
+	// timestamp plus the sum of the transfers after the snapshot till the timestamp. Same as the per account balance,
+	// the tokens created at or before the genesis balance snapshot and the dissociated tokens are excluded, and the hbar
+	// balance has token id 0
+	selectAllBalancesAtTimestamp = "with" + genesisTimestampCte + `, abf as (
+                                      select consensus_timestamp, time_offset
+                                      from account_balance_file
+                                      where consensus_timestamp <= @timestamp
+                                      order by consensus_timestamp desc
+                                      limit 1
+                                    ), hbar_change as (
+                                      select ab.account_id, ab.balance as value
+                                      from account_balance ab
+                                      join abf on ab.consensus_timestamp = abf.consensus_timestamp
+                                      union all
+                                      select ct.entity_id, ct.amount
+                                      from crypto_transfer ct
+                                      join abf on ct.consensus_timestamp > abf.consensus_timestamp + abf.time_offset
+                                      where ct.consensus_timestamp <= @timestamp and
+                                        (ct.errata is null or ct.errata <> 'DELETE')
+                                    ), token_change as (
+                                      select tb.account_id, tb.token_id, tb.balance as value
+                                      from token_balance tb
+                                      join abf on tb.consensus_timestamp = abf.consensus_timestamp
+                                      union all
+                                      select tt.account_id, tt.token_id, tt.amount
+                                      from token_transfer tt
+                                      join abf on tt.consensus_timestamp > abf.consensus_timestamp + abf.time_offset
+                                      join token t on t.token_id = tt.token_id and t.type = 'FUNGIBLE_COMMON'
+                                      where tt.consensus_timestamp <= @timestamp
+                                      union all
+                                      select nt.receiver_account_id, nt.token_id, 1
+                                      from nft_transfer nt
+                                      join abf on nt.consensus_timestamp > abf.consensus_timestamp + abf.time_offset
+                                      where nt.consensus_timestamp <= @timestamp and nt.receiver_account_id is not null
+                                      union all
+                                      select nt.sender_account_id, nt.token_id, -1
+                                      from nft_transfer nt
+                                      join abf on nt.consensus_timestamp > abf.consensus_timestamp + abf.time_offset
+                                      where nt.consensus_timestamp <= @timestamp and nt.sender_account_id is not null
+                                    ), dissociated as (
+                                      select account_id, token_id
+                                      from (
+                                        select distinct on (account_id, token_id) account_id, token_id, associated
+                                        from token_account
+                                        where modified_timestamp <= @timestamp
+                                        order by account_id, token_id, modified_timestamp desc
+                                      ) latest
+                                      where not associated
+                                    )
+                                    select
+                                      account_id,
+                                      0 as token_id,
+                                      0 as decimals,
+                                      '' as type,
+                                      sum(value)::bigint as value
+                                    from hbar_change
+                                    group by account_id
+                                    having sum(value) <> 0
+                                    union all
+                                    select tc.account_id, tc.token_id, t.decimals, t.type::text, sum(tc.value)::bigint
+                                    from token_change tc
+                                    join token t on t.token_id = tc.token_id
+                                    join genesis on t.created_timestamp > genesis.timestamp
+                                    where not exists (
+                                      select from dissociated d
+                                      where d.account_id = tc.account_id and d.token_id = tc.token_id
+                                    )
+                                    group by tc.account_id, tc.token_id, t.decimals, t.type
+                                    having sum(tc.value) <> 0
+                                    order by account_id, token_id`
+	// selectTokenBalancesAtTimestamp selects the balances of a set of tokens of an account at the timestamp in one query,
+	// interpolated from the token balances in the latest balance snapshot at or before the timestamp and the account's
+	// transfers of the tokens after the snapshot, so the cost is bounded by the transfers since the snapshot instead of
+	// the account's whole transfer history. A row is returned for each distinct token id, with an empty type if the
+	// token doesn't exist or is created at or before the genesis balance snapshot. Note no row is returned if there's no
+	// snapshot
+	selectTokenBalancesAtTimestamp = "with" + genesisTimestampCte + `, abf as (
+                                        select consensus_timestamp, time_offset
+                                        from account_balance_file
+                                        where consensus_timestamp <= @timestamp
+                                        order by consensus_timestamp desc
+                                        limit 1
+                                      ), token_change as (
+                                        select tb.token_id, tb.balance as value
+                                        from token_balance tb
+                                        join abf on tb.consensus_timestamp = abf.consensus_timestamp
+                                        where tb.account_id = @account_id and tb.token_id = any(@token_ids::bigint[])
+                                        union all
+                                        select tt.token_id, tt.amount
+                                        from token_transfer tt
+                                        join abf on tt.consensus_timestamp > abf.consensus_timestamp + abf.time_offset
+                                        join token t on t.token_id = tt.token_id and t.type = 'FUNGIBLE_COMMON'
+                                        where tt.consensus_timestamp <= @timestamp and tt.account_id = @account_id and
+                                          tt.token_id = any(@token_ids::bigint[])
+                                        union all
+                                        select nt.token_id, 1
+                                        from nft_transfer nt
+                                        join abf on nt.consensus_timestamp > abf.consensus_timestamp + abf.time_offset
+                                        where nt.consensus_timestamp <= @timestamp and
+                                          nt.receiver_account_id = @account_id and
+                                          nt.token_id = any(@token_ids::bigint[])
+                                        union all
+                                        select nt.token_id, -1
+                                        from nft_transfer nt
+                                        join abf on nt.consensus_timestamp > abf.consensus_timestamp + abf.time_offset
+                                        where nt.consensus_timestamp <= @timestamp and
+                                          nt.sender_account_id = @account_id and
+                                          nt.token_id = any(@token_ids::bigint[])
+                                      ), association as (
+                                        select distinct on (token_id) token_id, associated
+                                        from token_account
+                                        where account_id = @account_id and token_id = any(@token_ids::bigint[]) and
+                                          modified_timestamp <= @timestamp
+                                        order by token_id, modified_timestamp desc
+                                      )
+                                      select
+                                        r.token_id,
+                                        coalesce(t.decimals, 0) as decimals,
+                                        coalesce(t.type::text, '') as type,
+                                        case
+                                          when a.associated is false then 0
+                                          else coalesce((
+                                            select sum(tc.value) from token_change tc where tc.token_id = r.token_id
+                                          ), 0)
+                                        end::bigint as value
+                                      from (select distinct unnest(@token_ids::bigint[]) as token_id) as r
+                                      cross join abf
+                                      left join (
+                                        token t join genesis on t.created_timestamp > genesis.timestamp
+                                      ) on t.token_id = r.token_id
+                                      left join association a on a.token_id = r.token_id
+                                      order by r.token_id`
+	// selectAccountsByAlias selects the accounts whose current or historical alias or evm address matches, and the
+	// transaction which created each account
+	selectAccountsByAlias = `with aliased as (
+                               select id from entity where alias = @alias or evm_address = @evm_address
+                               union
+                               select id from entity_history where alias = @alias or evm_address = @evm_address
+                             )
+                             select
+                               e.id,
+                               e.created_timestamp,
+                               coalesce(e.deleted, false) as deleted,
+                               t.transaction_hash,
+                               t.type as transaction_type
+                             from aliased a
+                             join entity e on e.id = a.id
+                             left join transaction t on t.consensus_timestamp = e.created_timestamp
+                             order by e.created_timestamp, e.id`
+	// selectBalanceSnapshotsAtTimestamp selects the hbar balance of the account in the latest two balance snapshots at
+	// or before the timestamp, the latest first. The timestamp of a snapshot includes the time offset of the file
+	selectBalanceSnapshotsAtTimestamp = `select
+                                           abf.consensus_timestamp + abf.time_offset as timestamp,
+                                           coalesce((
+                                             select balance
+                                             from account_balance ab
+                                             where ab.consensus_timestamp = abf.consensus_timestamp and
+                                               ab.account_id = @account_id
+                                           ), 0) as balance
+                                         from account_balance_file abf
+                                         where abf.consensus_timestamp <= @timestamp
+                                         order by abf.consensus_timestamp desc
+                                         limit 2`
+	// selectAccountLifecycle selects the lifecycle of a hollow account, i.e., an account created with an evm address
+	// alias and an empty key, either still hollow or completed later by a transaction setting its key
+	selectAccountLifecycle = `with account as (
+                                select created_timestamp, evm_address, id, key, lower(timestamp_range) as timestamp
+                                from entity
+                                where %s and type = 'ACCOUNT' and evm_address is not null
+                              ), history as (
+                                select h.key, lower(h.timestamp_range) as timestamp
+                                from entity_history h
+                                join account a on a.id = h.id
+                                union all
+                                select key, timestamp from account
+                              ), hollow as (
+                                select min(timestamp) as timestamp from history
+                                where key is null or key in ('', '\x3200')
+                              )
+                              select
+                                a.created_timestamp,
+                                a.evm_address,
+                                (
+                                  select min(timestamp) from history
+                                  where key is not null and key not in ('', '\x3200') and timestamp > hollow.timestamp
+                                ) as completed_timestamp,
+                                (
+                                  select count(*) from entity e
+                                  where e.evm_address = a.evm_address and e.id <> a.id
+                                ) as alias_collisions
+                              from account a, hollow
+                              where hollow.timestamp is not null`
+	// selectReconciliationTransactions selects the net hbar change of the account by each transaction in the timestamp
+	// range, the earliest first, with the total change and the number of transactions in the range
+	selectReconciliationTransactions = `select
+                                          ct.consensus_timestamp,
+                                          sum(ct.amount)::bigint as amount,
+                                          t.transaction_hash as hash,
+                                          t.type,
+                                          (sum(sum(ct.amount)) over ())::bigint as total_change,
+                                          count(*) over () as transaction_count
+                                        from crypto_transfer ct
+                                        left join transaction t on t.consensus_timestamp = ct.consensus_timestamp
+                                        where ct.consensus_timestamp > @start and ct.consensus_timestamp <= @end and
+                                          ct.entity_id = @account_id and (ct.errata is null or ct.errata <> 'DELETE')
+                                        group by ct.consensus_timestamp, t.transaction_hash, t.type
+                                        order by ct.consensus_timestamp
+                                        limit @limit`
+	selectCryptoEntityWithAliasById = "select alias, evm_address, id, type from entity where id = @id"
+	// selectCryptoEntityByAlias selects the entity owning the alias at the timestamp, with the current key of the
+	// entity unless it's deleted
+	selectCryptoEntityByAlias = `select id, deleted, case when deleted is not true then key end as key,
+                                   timestamp_range
                                  from entity
                                  where alias = @alias and timestamp_range @> @consensus_end
                                  union all
-                                 select id, deleted, timestamp_range
-                                 from entity_history
-                                 where alias = @alias and timestamp_range @> @consensus_end
+                                 select eh.id, eh.deleted, case when e.deleted is not true then e.key end as key,
+                                   eh.timestamp_range
+                                 from entity_history eh
+                                 left join entity e on e.id = eh.id
+                                 where eh.alias = @alias and eh.timestamp_range @> @consensus_end
                                  order by timestamp_range desc`
 	selectCurrentCryptoEntityByAlias = `select id from entity
                                  where alias = @alias and (deleted is null or deleted is false)`
-	selectCryptoEntityById = `select id, deleted, timestamp_range
+	selectCurrentCryptoEntityInfo = `select
+                                   id,
+                                   key,
+                                   auto_renew_period,
+                                   coalesce(expiration_timestamp, created_timestamp + auto_renew_period * 1000000000)
+                                     as expiration_timestamp
+                                 from entity `
+	selectCurrentCryptoEntityInfoByAlias = selectCurrentCryptoEntityInfo +
+		"where alias = @alias and (deleted is null or deleted is false)"
+	selectCurrentCryptoEntityInfoById = selectCurrentCryptoEntityInfo +
+		"where id = @id and (deleted is null or deleted is false)"
+	// selectCryptoEntityById selects the entity with the current key unless it's deleted
+	selectCryptoEntityById = `select id, deleted, case when deleted is not true then key end as key, timestamp_range
                               from entity
                               where type in ('ACCOUNT', 'CONTRACT') and id = @id`
 	selectNftTransfersForAccount = "with" + genesisTimestampCte + `
@@ -135,12 +423,49 @@ const (
                                     order by consensus_timestamp`
 )
 
+type accountLifecycle struct {
+	AliasCollisions    int64
+	CompletedTimestamp *int64
+	CreatedTimestamp   *int64
+	EvmAddress         []byte
+}
+
+type aliasAccount struct {
+	Id               int64
+	CreatedTimestamp *int64
+	Deleted          bool
+	TransactionHash  []byte
+	TransactionType  *int32
+}
+
+type reconciliationTransaction struct {
+	ConsensusTimestamp int64
+	Amount             int64
+	Hash               []byte
+	Type               *int32
+	TotalChange        int64
+	TransactionCount   int64
+}
+
 type accountBalanceChange struct {
 	TokenAssociations string
 	TokenValues       string
 	Value             int64
 }
 
+type accountTokenBalance struct {
+	AccountId int64
+	TokenId   int64
+	Decimals  int64
+	Type      string
+	Value     int64
+}
+
+type accountHbarBalance struct {
+	Id      int64
+	Balance int64
+}
+
 type combinedAccountBalance struct {
 	ConsensusTimestamp int64
 	Balance            int64
@@ -156,23 +481,67 @@ type tokenAssociation struct {
 
 // accountRepository struct that has connection to the Database
 type accountRepository struct {
-	dbClient interfaces.DbClient
+	balanceReplay config.BalanceReplay
+	dbClient      interfaces.DbClient
 }
 
-// NewAccountRepository creates an instance of a accountRepository struct
-func NewAccountRepository(dbClient interfaces.DbClient) interfaces.AccountRepository {
-	return &accountRepository{dbClient}
+// NewAccountRepository creates an instance of a accountRepository struct. The replay of the transfers after the
+// nearest balance snapshot when computing a historical balance is bounded by the balance replay config
+func NewAccountRepository(
+	dbClient interfaces.DbClient,
+	balanceReplay config.BalanceReplay,
+) interfaces.AccountRepository {
+	return &accountRepository{balanceReplay: balanceReplay, dbClient: dbClient}
+}
+
+func (ar *accountRepository) FindAccountsByAlias(ctx context.Context, alias, evmAddress []byte) (
+	types.AliasAccounts,
+	*rTypes.Error,
+) {
+	rows := make([]aliasAccount, 0)
+	if err := ar.dbClient.Query(ctx, "selectAccountsByAlias", func(db *gorm.DB) error {
+		return db.Raw(
+			selectAccountsByAlias,
+			sql.Named("alias", nullableBytes(alias)),
+			sql.Named("evm_address", nullableBytes(evmAddress)),
+		).Scan(&rows).Error
+	}); err != nil {
+		log.Errorf(
+			databaseErrorFormat,
+			hErrors.ErrDatabaseError.Message,
+			fmt.Sprintf("%v looking for accounts with alias 0x%x or evm address 0x%x", err, alias, evmAddress),
+		)
+		return nil, hErrors.ErrDatabaseError
+	}
+
+	accounts := make(types.AliasAccounts, 0, len(rows))
+	for _, row := range rows {
+		accountId, err := domain.DecodeEntityId(row.Id)
+		if err != nil {
+			log.Errorf("Failed to decode account id %d: %s", row.Id, err)
+			return nil, hErrors.ErrInternalServerError
+		}
+
+		accounts = append(accounts, types.AliasAccount{
+			AccountId:               accountId,
+			CreatedTimestamp:        row.CreatedTimestamp,
+			CreationTransactionHash: row.TransactionHash,
+			CreationTransactionType: row.TransactionType,
+			Deleted:                 row.Deleted,
+		})
+	}
+
+	return accounts, nil
 }
 
 func (ar *accountRepository) GetAccountAlias(ctx context.Context, accountId types.AccountId) (
 	zero types.AccountId,
 	_ *rTypes.Error,
 ) {
-	db, cancel := ar.dbClient.GetDbWithContext(ctx)
-	defer cancel()
-
 	var entity domain.Entity
-	if err := db.Raw(selectCryptoEntityWithAliasById, sql.Named("id", accountId.GetId())).First(&entity).Error; err != nil {
+	if err := ar.dbClient.Query(ctx, "selectCryptoEntityWithAliasById", func(db *gorm.DB) error {
+		return db.Raw(selectCryptoEntityWithAliasById, sql.Named("id", accountId.GetId())).First(&entity).Error
+	}); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return accountId, nil
 		}
@@ -180,7 +549,7 @@ func (ar *accountRepository) GetAccountAlias(ctx context.Context, accountId type
 		return zero, hErrors.ErrDatabaseError
 	}
 
-	if len(entity.Alias) == 0 {
+	if len(entity.Alias) == 0 && entity.Type != domain.EntityTypeContract {
 		return accountId, nil
 	}
 
@@ -191,6 +560,63 @@ func (ar *accountRepository) GetAccountAlias(ctx context.Context, accountId type
 	return zero, hErrors.ErrInternalServerError
 }
 
+func (ar *accountRepository) GetAccountLifecycle(ctx context.Context, accountId types.AccountId) (
+	*types.AccountLifecycle,
+	*rTypes.Error,
+) {
+	query, arg := fmt.Sprintf(selectAccountLifecycle, "id = @id"), sql.Named("id", accountId.GetId())
+	if accountId.HasAlias() {
+		query, arg = fmt.Sprintf(selectAccountLifecycle, "alias = @alias"), sql.Named("alias", accountId.GetAlias())
+	}
+
+	var lifecycle accountLifecycle
+	if err := ar.dbClient.Query(ctx, "selectAccountLifecycle", func(db *gorm.DB) error {
+		return db.Raw(query, arg).First(&lifecycle).Error
+	}); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		log.Errorf(databaseErrorFormat, hErrors.ErrDatabaseError.Message, err)
+		return nil, hErrors.ErrDatabaseError
+	}
+
+	return &types.AccountLifecycle{
+		AliasCollisions:    lifecycle.AliasCollisions,
+		CompletedTimestamp: lifecycle.CompletedTimestamp,
+		CreatedTimestamp:   lifecycle.CreatedTimestamp,
+		EvmAddress:         lifecycle.EvmAddress,
+	}, nil
+}
+
+func (ar *accountRepository) GetAccountInfo(ctx context.Context, accountId types.AccountId) (
+	*types.AccountInfo,
+	*rTypes.Error,
+) {
+	query, arg := selectCurrentCryptoEntityInfoById, sql.Named("id", accountId.GetId())
+	if accountId.HasAlias() {
+		query, arg = selectCurrentCryptoEntityInfoByAlias, sql.Named("alias", accountId.GetAlias())
+	}
+
+	var entity domain.Entity
+	if err := ar.dbClient.Query(ctx, "selectCurrentCryptoEntityInfo", func(db *gorm.DB) error {
+		return db.Raw(query, arg).First(&entity).Error
+	}); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, hErrors.ErrDatabaseError
+	}
+
+	return &types.AccountInfo{
+		AutoRenewPeriod:     entity.AutoRenewPeriod,
+		ExpirationTimestamp: entity.ExpirationTimestamp,
+		Id:                  entity.Id,
+		Key:                 entity.Key,
+	}, nil
+}
+
 func (ar *accountRepository) GetAccountId(ctx context.Context, accountId types.AccountId) (
 	zero types.AccountId,
 	_ *rTypes.Error,
@@ -199,11 +625,10 @@ func (ar *accountRepository) GetAccountId(ctx context.Context, accountId types.A
 		return accountId, nil
 	}
 
-	db, cancel := ar.dbClient.GetDbWithContext(ctx)
-	defer cancel()
-
 	var entity domain.Entity
-	if err := db.Raw(selectCurrentCryptoEntityByAlias, sql.Named("alias", accountId.GetAlias())).First(&entity).Error; err != nil {
+	if err := ar.dbClient.Query(ctx, "selectCurrentCryptoEntityByAlias", func(db *gorm.DB) error {
+		return db.Raw(selectCurrentCryptoEntityByAlias, sql.Named("alias", accountId.GetAlias())).First(&entity).Error
+	}); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return zero, hErrors.ErrAccountNotFound
 		}
@@ -218,11 +643,11 @@ func (ar *accountRepository) RetrieveBalanceAtBlock(
 	ctx context.Context,
 	accountId types.AccountId,
 	consensusEnd int64,
-) (types.AmountSlice, string, *rTypes.Error) {
+) (types.AmountSlice, string, []byte, *rTypes.Error) {
 	var entityIdString string
 	entity, err := ar.getCryptoEntity(ctx, accountId, consensusEnd)
 	if err != nil {
-		return nil, entityIdString, err
+		return nil, entityIdString, nil, err
 	}
 
 	balanceChangeEndTimestamp := consensusEnd
@@ -247,7 +672,11 @@ func (ar *accountRepository) RetrieveBalanceAtBlock(
 		balanceSnapshotEndTimestamp,
 	)
 	if err != nil {
-		return nil, entityIdString, err
+		return nil, entityIdString, nil, err
+	}
+
+	if err = ar.checkBalanceReplayBounds(ctx, id, snapshotTimestamp, balanceChangeEndTimestamp); err != nil {
+		return nil, entityIdString, nil, err
 	}
 
 	hbarValue, tokenValues, tokenAssociationMap, err := ar.getBalanceChange(
@@ -257,7 +686,7 @@ func (ar *accountRepository) RetrieveBalanceAtBlock(
 		balanceChangeEndTimestamp,
 	)
 	if err != nil {
-		return nil, entityIdString, err
+		return nil, entityIdString, nil, err
 	}
 
 	hbarAmount.Value += hbarValue
@@ -277,7 +706,7 @@ func (ar *accountRepository) RetrieveBalanceAtBlock(
 		nftAssociationMap,
 	)
 	if err != nil {
-		return nil, entityIdString, err
+		return nil, entityIdString, nil, err
 	}
 
 	amounts := make(types.AmountSlice, 0, 1+len(ftAmounts)+len(nftAmounts))
@@ -285,20 +714,267 @@ func (ar *accountRepository) RetrieveBalanceAtBlock(
 	amounts = append(amounts, ftAmounts...)
 	amounts = append(amounts, nftAmounts...)
 
+	var key []byte
 	if entity != nil {
 		// return the entity id string in the format of 'shard.realm.num'
 		entityIdString = entity.Id.String()
+		key = entity.Key
+	}
+	return amounts, entityIdString, key, nil
+}
+
+func (ar *accountRepository) RetrieveBalanceReconciliation(
+	ctx context.Context,
+	accountId int64,
+	timestamp int64,
+	limit int,
+) (*types.BalanceReconciliation, *rTypes.Error) {
+	entityId, err := domain.DecodeEntityId(accountId)
+	if err != nil {
+		return nil, hErrors.ErrInvalidAccount
 	}
-	return amounts, entityIdString, nil
+
+	snapshots := make([]types.BalanceSnapshot, 0, 2)
+	if err := ar.dbClient.Query(ctx, "selectBalanceSnapshotsAtTimestamp", func(db *gorm.DB) error {
+		return db.Raw(
+			selectBalanceSnapshotsAtTimestamp,
+			sql.Named("account_id", accountId),
+			sql.Named("timestamp", timestamp),
+		).Scan(&snapshots).Error
+	}); err != nil {
+		log.Errorf(
+			databaseErrorFormat,
+			hErrors.ErrDatabaseError.Message,
+			fmt.Sprintf(
+				"%v looking for account %s's balance snapshots at or before %d",
+				err,
+				entityId.String(),
+				timestamp,
+			),
+		)
+		return nil, hErrors.ErrDatabaseError
+	}
+
+	if len(snapshots) < 2 {
+		return nil, hErrors.ErrBalanceSnapshotNotFound
+	}
+
+	rows := make([]reconciliationTransaction, 0)
+	if err := ar.dbClient.Query(ctx, "selectReconciliationTransactions", func(db *gorm.DB) error {
+		return db.Raw(
+			selectReconciliationTransactions,
+			sql.Named("account_id", accountId),
+			sql.Named("start", snapshots[1].Timestamp),
+			sql.Named("end", snapshots[0].Timestamp),
+			sql.Named("limit", limit),
+		).Scan(&rows).Error
+	}); err != nil {
+		log.Errorf(
+			databaseErrorFormat,
+			hErrors.ErrDatabaseError.Message,
+			fmt.Sprintf("%v looking for account %s's transfers between balance snapshots", err, entityId.String()),
+		)
+		return nil, hErrors.ErrDatabaseError
+	}
+
+	reconciliation := &types.BalanceReconciliation{
+		AccountId:    entityId,
+		Nearest:      snapshots[0],
+		Previous:     snapshots[1],
+		Transactions: make([]types.ReconciliationTransaction, 0, len(rows)),
+	}
+	for _, row := range rows {
+		reconciliation.TransactionCount = row.TransactionCount
+		reconciliation.TransferChange = row.TotalChange
+		reconciliation.Transactions = append(reconciliation.Transactions, types.ReconciliationTransaction{
+			Amount:             row.Amount,
+			ConsensusTimestamp: row.ConsensusTimestamp,
+			Hash:               row.Hash,
+			Type:               row.Type,
+		})
+	}
+
+	return reconciliation, nil
+}
+
+func (ar *accountRepository) RetrieveGenesisAccounts(ctx context.Context, minAccountId, maxAccountId int64) (
+	[]int64,
+	*rTypes.Error,
+) {
+	rows := make([]sql.NullInt64, 0)
+	if err := ar.dbClient.Query(ctx, "selectGenesisAccountsInRange", func(db *gorm.DB) error {
+		return db.Raw(
+			selectGenesisAccountsInRange,
+			sql.Named("min_account_id", minAccountId),
+			sql.Named("max_account_id", maxAccountId),
+		).Scan(&rows).Error
+	}); err != nil {
+		log.Errorf(
+			databaseErrorFormat,
+			hErrors.ErrDatabaseError.Message,
+			fmt.Sprintf("%v looking for genesis accounts in [%d, %d]", err, minAccountId, maxAccountId),
+		)
+		return nil, hErrors.ErrDatabaseError
+	}
+
+	if len(rows) == 0 {
+		return nil, hErrors.ErrNodeIsStarting
+	}
+
+	accountIds := make([]int64, 0, len(rows))
+	for _, row := range rows {
+		if row.Valid {
+			accountIds = append(accountIds, row.Int64)
+		}
+	}
+
+	return accountIds, nil
+}
+
+func (ar *accountRepository) RetrieveHbarBalancesAtBlock(
+	ctx context.Context,
+	accountIds []int64,
+	consensusEnd int64,
+) (map[int64]types.HbarAmount, *rTypes.Error) {
+	ids := pgtype.Int8Array{}
+	if err := ids.Set(accountIds); err != nil {
+		return nil, hErrors.ErrInternalServerError
+	}
+
+	balances := make([]accountHbarBalance, 0, len(accountIds))
+	if err := ar.dbClient.Query(ctx, "selectHbarBalancesAtTimestamp", func(db *gorm.DB) error {
+		return db.Raw(
+			selectHbarBalancesAtTimestamp,
+			sql.Named("account_ids", ids),
+			sql.Named("timestamp", consensusEnd),
+		).Scan(&balances).Error
+	}); err != nil {
+		log.Errorf(
+			databaseErrorFormat,
+			hErrors.ErrDatabaseError.Message,
+			fmt.Sprintf("%v looking for %d accounts' hbar balances at %d", err, len(accountIds), consensusEnd),
+		)
+		return nil, hErrors.ErrDatabaseError
+	}
+
+	if len(balances) == 0 && len(accountIds) != 0 {
+		return nil, hErrors.ErrNodeIsStarting
+	}
+
+	result := make(map[int64]types.HbarAmount, len(balances))
+	for _, balance := range balances {
+		result[balance.Id] = types.HbarAmount{Value: balance.Balance}
+	}
+
+	return result, nil
+}
+
+func (ar *accountRepository) RetrieveAllBalancesAtBlock(ctx context.Context, consensusEnd int64) (
+	map[int64]types.AmountSlice,
+	*rTypes.Error,
+) {
+	balances := make([]accountTokenBalance, 0)
+	if err := ar.dbClient.Query(ctx, "selectAllBalancesAtTimestamp", func(db *gorm.DB) error {
+		return db.Raw(selectAllBalancesAtTimestamp, sql.Named("timestamp", consensusEnd)).Scan(&balances).Error
+	}); err != nil {
+		log.Errorf(
+			databaseErrorFormat,
+			hErrors.ErrDatabaseError.Message,
+			fmt.Sprintf("%v looking for all accounts' balances at %d", err, consensusEnd),
+		)
+		return nil, hErrors.ErrDatabaseError
+	}
+
+	if len(balances) == 0 {
+		return nil, hErrors.ErrNodeIsStarting
+	}
+
+	result := make(map[int64]types.AmountSlice)
+	for _, balance := range balances {
+		var amount types.Amount
+		if balance.TokenId == 0 {
+			amount = &types.HbarAmount{Value: balance.Value}
+		} else {
+			tokenId, err := domain.DecodeEntityId(balance.TokenId)
+			if err != nil {
+				log.Errorf("Failed to decode token id %d: %s", balance.TokenId, err)
+				return nil, hErrors.ErrInternalServerError
+			}
+
+			amount = &types.TokenAmount{
+				Decimals: balance.Decimals,
+				TokenId:  tokenId,
+				Type:     balance.Type,
+				Value:    balance.Value,
+			}
+		}
+		result[balance.AccountId] = append(result[balance.AccountId], amount)
+	}
+
+	return result, nil
+}
+
+func (ar *accountRepository) RetrieveTokenBalancesAtBlock(
+	ctx context.Context,
+	accountId int64,
+	tokenIds []int64,
+	consensusEnd int64,
+) (types.AmountSlice, *rTypes.Error) {
+	ids := pgtype.Int8Array{}
+	if err := ids.Set(tokenIds); err != nil {
+		return nil, hErrors.ErrInternalServerError
+	}
+
+	balances := make([]accountTokenBalance, 0, len(tokenIds))
+	if err := ar.dbClient.Query(ctx, "selectTokenBalancesAtTimestamp", func(db *gorm.DB) error {
+		return db.Raw(
+			selectTokenBalancesAtTimestamp,
+			sql.Named("account_id", accountId),
+			sql.Named("timestamp", consensusEnd),
+			sql.Named("token_ids", ids),
+		).Scan(&balances).Error
+	}); err != nil {
+		log.Errorf(
+			databaseErrorFormat,
+			hErrors.ErrDatabaseError.Message,
+			fmt.Sprintf("%v looking for account %d's balances of %d tokens at %d", err, accountId, len(tokenIds),
+				consensusEnd),
+		)
+		return nil, hErrors.ErrDatabaseError
+	}
+
+	if len(balances) == 0 && len(tokenIds) != 0 {
+		return nil, hErrors.ErrNodeIsStarting
+	}
+
+	amounts := make(types.AmountSlice, 0, len(balances))
+	for _, balance := range balances {
+		if balance.Type == "" {
+			// the token doesn't exist or is created before the genesis balance snapshot
+			continue
+		}
+
+		tokenId, err := domain.DecodeEntityId(balance.TokenId)
+		if err != nil {
+			log.Errorf("Failed to decode token id %d: %s", balance.TokenId, err)
+			return nil, hErrors.ErrInternalServerError
+		}
+
+		amounts = append(amounts, &types.TokenAmount{
+			Decimals: balance.Decimals,
+			TokenId:  tokenId,
+			Type:     balance.Type,
+			Value:    balance.Value,
+		})
+	}
+
+	return amounts, nil
 }
 
 func (ar *accountRepository) getCryptoEntity(ctx context.Context, accountId types.AccountId, consensusEnd int64) (
 	*domain.Entity,
 	*rTypes.Error,
 ) {
-	db, cancel := ar.dbClient.GetDbWithContext(ctx)
-	defer cancel()
-
 	var query string
 	var args []interface{}
 	var notFoundError *rTypes.Error
@@ -316,7 +992,9 @@ func (ar *accountRepository) getCryptoEntity(ctx context.Context, accountId type
 	}
 
 	entities := make([]domain.Entity, 0)
-	if err := db.Raw(query, args...).Scan(&entities).Error; err != nil {
+	if err := ar.dbClient.Query(ctx, "selectCryptoEntity", func(db *gorm.DB) error {
+		return db.Raw(query, args...).Scan(&entities).Error
+	}); err != nil {
 		log.Errorf(
 			databaseErrorFormat,
 			hErrors.ErrDatabaseError.Message,
@@ -333,22 +1011,75 @@ func (ar *accountRepository) getCryptoEntity(ctx context.Context, accountId type
 	return &entities[0], nil
 }
 
+// nullableBytes returns nil for an empty byte slice so it's bound as a sql null, which matches no row
+func nullableBytes(data []byte) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	return data
+}
+
+// checkBalanceReplayBounds returns ErrBalanceReplayBoundExceeded with the guidance in the details if replaying the
+// account's transfers in (snapshotTimestamp, end] exceeds the max window or the max number of transfers. The transfers
+// are counted with a limit so the check itself stays cheap
+func (ar *accountRepository) checkBalanceReplayBounds(
+	ctx context.Context,
+	accountId int64,
+	snapshotTimestamp int64,
+	end int64,
+) *rTypes.Error {
+	maxWindow := ar.balanceReplay.MaxWindow
+	if maxWindow > 0 && end-snapshotTimestamp > int64(maxWindow) {
+		reason := fmt.Sprintf("The block is more than %s after the nearest balance snapshot", maxWindow)
+		return newBalanceReplayBoundExceededError(reason, snapshotTimestamp)
+	}
+
+	maxTransfers := ar.balanceReplay.MaxTransfers
+	if maxTransfers <= 0 {
+		return nil
+	}
+
+	var count int64
+	if err := ar.dbClient.Query(ctx, "countTransfersBetween", func(db *gorm.DB) error {
+		return db.Raw(
+			countTransfersBetween,
+			sql.Named("account_id", accountId),
+			sql.Named("start", snapshotTimestamp),
+			sql.Named("end", end),
+			sql.Named("limit", maxTransfers+1),
+		).Row().Scan(&count)
+	}); err != nil {
+		log.Errorf(
+			databaseErrorFormat,
+			hErrors.ErrDatabaseError.Message,
+			fmt.Sprintf("%v counting account %d's transfers in (%d, %d]", err, accountId, snapshotTimestamp, end),
+		)
+		return hErrors.ErrDatabaseError
+	}
+
+	if count > maxTransfers {
+		reason := fmt.Sprintf("The account has more than %d transfers after the nearest balance snapshot", maxTransfers)
+		return newBalanceReplayBoundExceededError(reason, snapshotTimestamp)
+	}
+
+	return nil
+}
+
 func (ar *accountRepository) getLatestBalanceSnapshot(ctx context.Context, accountId, timestamp int64) (
 	int64,
 	*types.HbarAmount,
 	map[string]map[int64]*types.TokenAmount,
 	*rTypes.Error,
 ) {
-	db, cancel := ar.dbClient.GetDbWithContext(ctx)
-	defer cancel()
-
 	// gets the most recent balance at or before timestamp
 	cb := &combinedAccountBalance{}
-	if err := db.Raw(
-		latestBalanceBeforeConsensus,
-		sql.Named("account_id", accountId),
-		sql.Named("timestamp", timestamp),
-	).First(cb).Error; err != nil {
+	if err := ar.dbClient.Query(ctx, "latestBalanceBeforeConsensus", func(db *gorm.DB) error {
+		return db.Raw(
+			latestBalanceBeforeConsensus,
+			sql.Named("account_id", accountId),
+			sql.Named("timestamp", timestamp),
+		).First(cb).Error
+	}); err != nil {
 		log.Errorf(
 			databaseErrorFormat,
 			hErrors.ErrDatabaseError.Message,
@@ -385,17 +1116,16 @@ func (ar *accountRepository) getBalanceChange(ctx context.Context, accountId, co
 	map[string]map[int64]tokenAssociation,
 	*rTypes.Error,
 ) {
-	db, cancel := ar.dbClient.GetDbWithContext(ctx)
-	defer cancel()
-
 	change := &accountBalanceChange{}
 	// gets the balance change from the Balance snapshot until the target block
-	if err := db.Raw(
-		balanceChangeBetween,
-		sql.Named("account_id", accountId),
-		sql.Named("start", consensusStart),
-		sql.Named("end", consensusEnd),
-	).First(change).Error; err != nil {
+	if err := ar.dbClient.Query(ctx, "balanceChangeBetween", func(db *gorm.DB) error {
+		return db.Raw(
+			balanceChangeBetween,
+			sql.Named("account_id", accountId),
+			sql.Named("start", consensusStart),
+			sql.Named("end", consensusEnd),
+		).First(change).Error
+	}); err != nil {
 		log.Errorf(
 			databaseErrorFormat,
 			hErrors.ErrDatabaseError.Message,
@@ -437,16 +1167,15 @@ func (ar *accountRepository) getNftBalance(
 	tokenAmountMap map[int64]*types.TokenAmount,
 	tokenAssociationMap map[int64]tokenAssociation,
 ) (types.AmountSlice, *rTypes.Error) {
-	db, cancel := ar.dbClient.GetDbWithContext(ctx)
-	defer cancel()
-
 	nftTransfers := make([]domain.NftTransfer, 0)
-	if err := db.Raw(
-		selectNftTransfersForAccount,
-		sql.Named("account_id", accountId),
-		sql.Named("start", consensusStart),
-		sql.Named("end", consensusEnd),
-	).Scan(&nftTransfers).Error; err != nil {
+	if err := ar.dbClient.Query(ctx, "selectNftTransfersForAccount", func(db *gorm.DB) error {
+		return db.Raw(
+			selectNftTransfersForAccount,
+			sql.Named("account_id", accountId),
+			sql.Named("start", consensusStart),
+			sql.Named("end", consensusEnd),
+		).Scan(&nftTransfers).Error
+	}); err != nil {
 		log.Errorf(
 			databaseErrorFormat,
 			hErrors.ErrDatabaseError.Message,
@@ -534,3 +1263,15 @@ func getInclusiveInt8Range(lower, upper int64) pgtype.Int8range {
 		Status:    pgtype.Present,
 	}
 }
+
+// newBalanceReplayBoundExceededError returns ErrBalanceReplayBoundExceeded with the reason, the timestamp of the
+// nearest balance snapshot, and the guidance on how to get the balance within the bounds
+func newBalanceReplayBoundExceededError(reason string, snapshotTimestamp int64) *rTypes.Error {
+	rErr := hErrors.AddErrorDetails(hErrors.ErrBalanceReplayBoundExceeded, "reason", reason)
+	rErr = hErrors.AddErrorDetails(rErr, "snapshot_timestamp", strconv.FormatInt(snapshotTimestamp, 10))
+	return hErrors.AddErrorDetails(
+		rErr,
+		"guidance",
+		"Query a block closer to the snapshot timestamp, or a block shortly after the next balance snapshot",
+	)
+}
@@ -0,0 +1,284 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+	"time"
+
+	cache "github.com/Code-Hex/go-generics-cache"
+	"github.com/Code-Hex/go-generics-cache/policy/lru"
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	hErrors "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultAliasCacheInvalidationInterval = 5 * time.Second
+
+	transactionTypeContractCreate = 8
+	transactionTypeContractUpdate = 9
+	transactionTypeCryptoCreate   = 11
+	transactionTypeCryptoDelete   = 12
+	transactionTypeCryptoUpdate   = 15
+	transactionTypeContractDelete = 22
+
+	selectLatestTransactionTimestamp = "select coalesce(max(consensus_timestamp), 0) from transaction"
+	// selectEntityTransactionsAfter selects the transactions which may change the alias or the evm address of an
+	// account, or the account an alias resolves to
+	selectEntityTransactionsAfter = `select consensus_timestamp, entity_id, type
+                                   from transaction
+                                   where consensus_timestamp > @timestamp and type in @types
+                                   order by consensus_timestamp`
+)
+
+var entityTransactionTypes = []int16{
+	transactionTypeContractCreate,
+	transactionTypeContractUpdate,
+	transactionTypeCryptoCreate,
+	transactionTypeCryptoDelete,
+	transactionTypeCryptoUpdate,
+	transactionTypeContractDelete,
+}
+
+type entityTransaction struct {
+	ConsensusTimestamp int64
+	EntityId           *int64
+	Type               int16
+}
+
+// accountIdCacheEntry is the cached result of resolving an alias to the account. A negative entry caches the account
+// not found error until it expires
+type accountIdCacheEntry struct {
+	accountId types.AccountId
+	err       *rTypes.Error
+	expiresAt time.Time
+}
+
+func (e accountIdCacheEntry) isExpired(now time.Time) bool {
+	return e.err != nil && !now.Before(e.expiresAt)
+}
+
+// cachedAccountRepository caches the alias lookups of the wrapped account repository. The entries of the accounts
+// created, updated, or deleted since are invalidated by polling the transactions, and the negative entries also expire
+// after the configured ttl
+type cachedAccountRepository struct {
+	// generation is incremented on each invalidation, so a lookup racing with an invalidation doesn't cache the
+	// result read before it. It's the first field to be 64-bit aligned for the atomic operations
+	generation int64
+	interfaces.AccountRepository
+	accountAliases *cache.Cache[int64, types.AccountId]
+	accountIds     *cache.Cache[string, accountIdCacheEntry]
+	dbClient       interfaces.DbClient
+	lastTimestamp  int64
+	negativeTtl    time.Duration
+	now            func() time.Time
+}
+
+func (c *cachedAccountRepository) GetAccountAlias(ctx context.Context, accountId types.AccountId) (
+	types.AccountId,
+	*rTypes.Error,
+) {
+	if accountId.HasAlias() {
+		return c.AccountRepository.GetAccountAlias(ctx, accountId)
+	}
+
+	if accountAlias, ok := c.accountAliases.Get(accountId.GetId()); ok {
+		return accountAlias, nil
+	}
+
+	generation := atomic.LoadInt64(&c.generation)
+	accountAlias, err := c.AccountRepository.GetAccountAlias(ctx, accountId)
+	if err == nil && generation == atomic.LoadInt64(&c.generation) {
+		c.accountAliases.Set(accountId.GetId(), accountAlias)
+	}
+	return accountAlias, err
+}
+
+func (c *cachedAccountRepository) GetAccountId(ctx context.Context, accountId types.AccountId) (
+	types.AccountId,
+	*rTypes.Error,
+) {
+	if !accountId.HasAlias() {
+		return accountId, nil
+	}
+
+	key := string(accountId.GetAlias())
+	if entry, ok := c.accountIds.Get(key); ok {
+		if !entry.isExpired(c.now()) {
+			return entry.accountId, entry.err
+		}
+		c.accountIds.Delete(key)
+	}
+
+	generation := atomic.LoadInt64(&c.generation)
+	found, err := c.AccountRepository.GetAccountId(ctx, accountId)
+	if generation != atomic.LoadInt64(&c.generation) {
+		return found, err
+	}
+
+	if err == nil {
+		c.accountIds.Set(key, accountIdCacheEntry{accountId: found})
+	} else if err == hErrors.ErrAccountNotFound && c.negativeTtl > 0 {
+		c.accountIds.Set(key, accountIdCacheEntry{accountId: found, err: err, expiresAt: c.now().Add(c.negativeTtl)})
+	}
+	return found, err
+}
+
+// invalidate removes the entries of the entities of the transactions. The negative entries are removed if any account
+// is created, since the new account may have one of the aliases
+func (c *cachedAccountRepository) invalidate(transactions []entityTransaction) {
+	atomic.AddInt64(&c.generation, 1)
+	entityIds := make(map[int64]bool)
+	accountCreated := false
+	for _, transaction := range transactions {
+		if transaction.Type == transactionTypeCryptoCreate {
+			accountCreated = true
+		}
+
+		if transaction.EntityId != nil {
+			entityIds[*transaction.EntityId] = true
+			c.accountAliases.Delete(*transaction.EntityId)
+		}
+	}
+
+	if len(entityIds) == 0 && !accountCreated {
+		return
+	}
+
+	for _, key := range c.accountIds.Keys() {
+		entry, ok := c.accountIds.Get(key)
+		if !ok {
+			continue
+		}
+
+		if (entry.err == nil && entityIds[entry.accountId.GetId()]) || (entry.err != nil && accountCreated) {
+			c.accountIds.Delete(key)
+		}
+	}
+}
+
+// pollInvalidations invalidates the entries changed by the new transactions every interval until the context is done
+func (c *cachedAccountRepository) pollInvalidations(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.refresh(ctx); err != nil {
+				log.Warnf("Failed to invalidate the account alias cache: %s", err)
+			}
+		}
+	}
+}
+
+// refresh invalidates the entries changed by the transactions after the last seen transaction. On the first call, the
+// caches are cleared and the latest transaction is the starting point
+func (c *cachedAccountRepository) refresh(ctx context.Context) error {
+	if c.lastTimestamp == 0 {
+		var latest int64
+		if err := c.dbClient.Query(ctx, "selectLatestTransactionTimestamp", func(db *gorm.DB) error {
+			return db.Raw(selectLatestTransactionTimestamp).Scan(&latest).Error
+		}); err != nil {
+			return err
+		}
+
+		atomic.AddInt64(&c.generation, 1)
+		purge(c.accountAliases)
+		purge(c.accountIds)
+		c.lastTimestamp = latest
+		return nil
+	}
+
+	transactions := make([]entityTransaction, 0)
+	if err := c.dbClient.Query(ctx, "selectEntityTransactionsAfter", func(db *gorm.DB) error {
+		return db.Raw(
+			selectEntityTransactionsAfter,
+			sql.Named("timestamp", c.lastTimestamp),
+			sql.Named("types", entityTransactionTypes),
+		).Scan(&transactions).Error
+	}); err != nil {
+		return err
+	}
+
+	if len(transactions) != 0 {
+		c.invalidate(transactions)
+		c.lastTimestamp = transactions[len(transactions)-1].ConsensusTimestamp
+	}
+	return nil
+}
+
+// NewCachedAccountRepository wraps the account repository with the alias lookup caches, and starts polling the
+// transactions to invalidate the changed entries until the context is done. The account repository is returned as is
+// if the cache max size is not positive
+func NewCachedAccountRepository(
+	ctx context.Context,
+	accountRepo interfaces.AccountRepository,
+	dbClient interfaces.DbClient,
+	cacheConfig config.Cache,
+) interfaces.AccountRepository {
+	if cacheConfig.MaxSize <= 0 {
+		return accountRepo
+	}
+
+	cachedRepo := newCachedAccountRepository(accountRepo, dbClient, cacheConfig)
+	if err := cachedRepo.refresh(ctx); err != nil {
+		log.Warnf("Failed to get the latest transaction timestamp for the account alias cache: %s", err)
+	}
+	interval := cacheConfig.InvalidationInterval
+	if interval <= 0 {
+		interval = defaultAliasCacheInvalidationInterval
+	}
+	go cachedRepo.pollInvalidations(ctx, interval)
+	return cachedRepo
+}
+
+func newCachedAccountRepository(
+	accountRepo interfaces.AccountRepository,
+	dbClient interfaces.DbClient,
+	cacheConfig config.Cache,
+) *cachedAccountRepository {
+	return &cachedAccountRepository{
+		AccountRepository: accountRepo,
+		accountAliases:    cache.New(cache.AsLRU[int64, types.AccountId](lru.WithCapacity(cacheConfig.MaxSize))),
+		accountIds: cache.New(
+			cache.AsLRU[string, accountIdCacheEntry](lru.WithCapacity(cacheConfig.MaxSize)),
+		),
+		dbClient:    dbClient,
+		negativeTtl: cacheConfig.NegativeTtl,
+		now:         time.Now,
+	}
+}
+
+func purge[K comparable, V any](c *cache.Cache[K, V]) {
+	for _, key := range c.Keys() {
+		c.Delete(key)
+	}
+}
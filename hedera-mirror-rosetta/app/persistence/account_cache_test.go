@@ -0,0 +1,256 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package persistence
+
+import (
+	"testing"
+	"time"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	hErrors "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	tdomain "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/domain"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+var (
+	aliasCacheConfig = config.Cache{MaxSize: 10, NegativeTtl: time.Minute}
+	cachedAccountId  = types.NewAccountIdFromEntityId(domain.MustDecodeEntityId(1001))
+	cachedAliasId, _ = types.NewAccountIdFromAlias(account4Alias, 0, 0)
+	otherAccountId   = types.NewAccountIdFromEntityId(domain.MustDecodeEntityId(1002))
+)
+
+func TestCachedAccountRepositoryGetAccountId(t *testing.T) {
+	// given
+	accountRepo := &mocks.MockAccountRepository{}
+	accountRepo.On("GetAccountId", mock.Anything, cachedAliasId).Return(cachedAccountId, mocks.NilError)
+	repo := newCachedAccountRepository(accountRepo, nil, aliasCacheConfig)
+
+	// when
+	actual, err := repo.GetAccountId(defaultContext, cachedAliasId)
+	actualAgain, errAgain := repo.GetAccountId(defaultContext, cachedAliasId)
+
+	// then
+	assert.Nil(t, err)
+	assert.Equal(t, cachedAccountId, actual)
+	assert.Nil(t, errAgain)
+	assert.Equal(t, cachedAccountId, actualAgain)
+	accountRepo.AssertNumberOfCalls(t, "GetAccountId", 1)
+}
+
+func TestCachedAccountRepositoryGetAccountIdNonAlias(t *testing.T) {
+	// given
+	accountRepo := &mocks.MockAccountRepository{}
+	repo := newCachedAccountRepository(accountRepo, nil, aliasCacheConfig)
+
+	// when
+	actual, err := repo.GetAccountId(defaultContext, cachedAccountId)
+
+	// then
+	assert.Nil(t, err)
+	assert.Equal(t, cachedAccountId, actual)
+	accountRepo.AssertNotCalled(t, "GetAccountId", mock.Anything, mock.Anything)
+}
+
+func TestCachedAccountRepositoryGetAccountIdNegative(t *testing.T) {
+	// given
+	now := time.Unix(100, 0)
+	accountRepo := &mocks.MockAccountRepository{}
+	accountRepo.On("GetAccountId", mock.Anything, cachedAliasId).Return(types.AccountId{}, hErrors.ErrAccountNotFound)
+	repo := newCachedAccountRepository(accountRepo, nil, aliasCacheConfig)
+	repo.now = func() time.Time { return now }
+
+	// when
+	_, err := repo.GetAccountId(defaultContext, cachedAliasId)
+	_, errCached := repo.GetAccountId(defaultContext, cachedAliasId)
+	now = now.Add(aliasCacheConfig.NegativeTtl)
+	_, errExpired := repo.GetAccountId(defaultContext, cachedAliasId)
+
+	// then
+	assert.Equal(t, hErrors.ErrAccountNotFound, err)
+	assert.Equal(t, hErrors.ErrAccountNotFound, errCached)
+	assert.Equal(t, hErrors.ErrAccountNotFound, errExpired)
+	accountRepo.AssertNumberOfCalls(t, "GetAccountId", 2)
+}
+
+func TestCachedAccountRepositoryGetAccountIdNotCached(t *testing.T) {
+	var tests = []struct {
+		name        string
+		err         *rTypes.Error
+		negativeTtl time.Duration
+	}{
+		{name: "database error", err: hErrors.ErrDatabaseError, negativeTtl: time.Minute},
+		{name: "negative caching disabled", err: hErrors.ErrAccountNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			accountRepo := &mocks.MockAccountRepository{}
+			accountRepo.On("GetAccountId", mock.Anything, cachedAliasId).Return(types.AccountId{}, tt.err)
+			repo := newCachedAccountRepository(accountRepo, nil, config.Cache{MaxSize: 10, NegativeTtl: tt.negativeTtl})
+
+			// when
+			_, err := repo.GetAccountId(defaultContext, cachedAliasId)
+			_, errAgain := repo.GetAccountId(defaultContext, cachedAliasId)
+
+			// then
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.err, errAgain)
+			accountRepo.AssertNumberOfCalls(t, "GetAccountId", 2)
+		})
+	}
+}
+
+func TestCachedAccountRepositoryGetAccountAlias(t *testing.T) {
+	// given
+	accountRepo := &mocks.MockAccountRepository{}
+	accountRepo.On("GetAccountAlias").Return(cachedAliasId, mocks.NilError)
+	repo := newCachedAccountRepository(accountRepo, nil, aliasCacheConfig)
+
+	// when
+	actual, err := repo.GetAccountAlias(defaultContext, cachedAccountId)
+	actualAgain, errAgain := repo.GetAccountAlias(defaultContext, cachedAccountId)
+
+	// then
+	assert.Nil(t, err)
+	assert.Equal(t, cachedAliasId, actual)
+	assert.Nil(t, errAgain)
+	assert.Equal(t, cachedAliasId, actualAgain)
+	accountRepo.AssertNumberOfCalls(t, "GetAccountAlias", 1)
+}
+
+func TestCachedAccountRepositoryInvalidate(t *testing.T) {
+	var tests = []struct {
+		name                  string
+		accountId             types.AccountId
+		transactionType       int16
+		expectAliasCached     bool
+		expectAccountIdCached bool
+		expectNotFoundCached  bool
+	}{
+		{
+			name:                 "crypto update",
+			accountId:            cachedAccountId,
+			transactionType:      transactionTypeCryptoUpdate,
+			expectNotFoundCached: true,
+		},
+		{
+			name:                  "crypto create",
+			accountId:             otherAccountId,
+			transactionType:       transactionTypeCryptoCreate,
+			expectAliasCached:     true,
+			expectAccountIdCached: true,
+		},
+		{
+			name:                  "other contract delete",
+			accountId:             otherAccountId,
+			transactionType:       transactionTypeContractDelete,
+			expectAliasCached:     true,
+			expectAccountIdCached: true,
+			expectNotFoundCached:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			notFoundAlias, _ := types.NewAccountIdFromAlias(account3Alias, 0, 0)
+			accountRepo := &mocks.MockAccountRepository{}
+			accountRepo.On("GetAccountAlias").Return(cachedAliasId, mocks.NilError)
+			accountRepo.On("GetAccountId", mock.Anything, cachedAliasId).Return(cachedAccountId, mocks.NilError)
+			accountRepo.On("GetAccountId", mock.Anything, notFoundAlias).
+				Return(types.AccountId{}, hErrors.ErrAccountNotFound)
+			repo := newCachedAccountRepository(accountRepo, nil, aliasCacheConfig)
+			_, _ = repo.GetAccountAlias(defaultContext, cachedAccountId)
+			_, _ = repo.GetAccountId(defaultContext, cachedAliasId)
+			_, _ = repo.GetAccountId(defaultContext, notFoundAlias)
+
+			// when
+			repo.invalidate([]entityTransaction{entityTransactionOf(1, tt.accountId, tt.transactionType)})
+
+			// then
+			assert.Equal(t, tt.expectAliasCached, repo.accountAliases.Contains(cachedAccountId.GetId()))
+			assert.Equal(t, tt.expectAccountIdCached, repo.accountIds.Contains(string(cachedAliasId.GetAlias())))
+			assert.Equal(t, tt.expectNotFoundCached, repo.accountIds.Contains(string(notFoundAlias.GetAlias())))
+		})
+	}
+}
+
+func TestNewCachedAccountRepositoryDisabled(t *testing.T) {
+	accountRepo := &mocks.MockAccountRepository{}
+	assert.Same(t, accountRepo, NewCachedAccountRepository(defaultContext, accountRepo, nil, config.Cache{}))
+}
+
+// run the suite
+func TestCachedAccountRepositorySuite(t *testing.T) {
+	suite.Run(t, new(cachedAccountRepositorySuite))
+}
+
+type cachedAccountRepositorySuite struct {
+	integrationTest
+	suite.Suite
+}
+
+func (suite *cachedAccountRepositorySuite) TestRefresh() {
+	// given
+	tdomain.NewTransactionBuilder(dbClient, 2, 100).ConsensusTimestamp(100).Persist()
+	accountRepo := &mocks.MockAccountRepository{}
+	accountRepo.On("GetAccountAlias").Return(cachedAliasId, mocks.NilError)
+	repo := newCachedAccountRepository(accountRepo, dbClient, aliasCacheConfig)
+	assert.NoError(suite.T(), repo.refresh(defaultContext))
+	_, _ = repo.GetAccountAlias(defaultContext, cachedAccountId)
+	tdomain.NewTransactionBuilder(dbClient, 2, 200).
+		ConsensusTimestamp(200).
+		EntityId(cachedAccountId.GetId()).
+		Type(transactionTypeCryptoUpdate).
+		Persist()
+
+	// when
+	err := repo.refresh(defaultContext)
+
+	// then
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(200), repo.lastTimestamp)
+	assert.False(suite.T(), repo.accountAliases.Contains(cachedAccountId.GetId()))
+}
+
+func (suite *cachedAccountRepositorySuite) TestRefreshDbConnectionError() {
+	// given
+	repo := newCachedAccountRepository(&mocks.MockAccountRepository{}, invalidDbClient, aliasCacheConfig)
+
+	// when
+	err := repo.refresh(defaultContext)
+
+	// then
+	assert.Error(suite.T(), err)
+	assert.Zero(suite.T(), repo.lastTimestamp)
+}
+
+func entityTransactionOf(timestamp int64, accountId types.AccountId, transactionType int16) entityTransaction {
+	entityId := accountId.GetId()
+	return entityTransaction{ConsensusTimestamp: timestamp, EntityId: &entityId, Type: transactionType}
+}
@@ -22,16 +22,21 @@ package persistence
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/db"
 	tdomain "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/domain"
 	"github.com/hashgraph/hedera-sdk-go/v2"
+	"github.com/jackc/pgtype"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"github.com/thanhpk/randstr"
@@ -46,6 +51,11 @@ const (
 	account5
 )
 
+const (
+	contract1 = int64(9100) + iota
+	contract2
+)
+
 const (
 	encodedTokenId1 = int64(1000) + iota
 	encodedTokenId2
@@ -71,6 +81,8 @@ const (
 	account3CreatedTimestamp = consensusTimestamp + 100
 	account4CreatedTimestamp = consensusTimestamp + 110
 	account5CreatedTimestamp = consensusTimestamp + 120
+
+	account3AutoRenewPeriod int64 = 7776000
 )
 
 var (
@@ -90,6 +102,8 @@ var (
 	account3Alias = hexutil.MustDecode("0x3a2103d9a822b91df7850274273a338c152e7bcfa2036b24cd9e3b29d07efd949b387a")
 	account4Alias = hexutil.MustDecode("0x12205a081255a92b7c262bc2ea3ab7114b8a815345b3cc40f800b2b40914afecc44e")
 	account5Alias = randstr.Bytes(48)
+	// contract1 has create2 evm address, contract2 doesn't
+	contract1EvmAddress = hexutil.MustDecode("0x71a8c2b29b5b7bcfb1a2c8ec4ad89b4a5b7da4b6")
 )
 
 // run the suite
@@ -318,13 +332,20 @@ func (suite *accountRepositorySuite) SetupTest() {
 	// accounts for GetAccountAlias tests
 	tdomain.NewEntityBuilder(dbClient, account3, account3CreatedTimestamp, domain.EntityTypeAccount).
 		Alias(suite.account3Alias).
+		AutoRenewPeriod(account3AutoRenewPeriod).
+		Key(account3Alias).
 		Persist()
 	tdomain.NewEntityBuilder(dbClient, account4, account4CreatedTimestamp, domain.EntityTypeAccount).
 		Alias(suite.account4Alias).
+		Key(account4Alias).
 		Persist()
 	tdomain.NewEntityBuilder(dbClient, account5, account5CreatedTimestamp, domain.EntityTypeAccount).
 		Alias(suite.account5Alias).
 		Persist()
+	tdomain.NewEntityBuilder(dbClient, contract1, account5CreatedTimestamp, domain.EntityTypeContract).
+		EvmAddress(contract1EvmAddress).
+		Persist()
+	tdomain.NewEntityBuilder(dbClient, contract2, account5CreatedTimestamp, domain.EntityTypeContract).Persist()
 }
 
 func (suite *accountRepositorySuite) TestGetAccountAlias() {
@@ -336,7 +357,7 @@ func (suite *accountRepositorySuite) TestGetAccountAlias() {
 		{encodedId: account4, expected: fmt.Sprintf("0.0.%d", account4)},
 	}
 
-	repo := NewAccountRepository(dbClient)
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
 
 	for _, tt := range tests {
 		name := fmt.Sprintf("%d", tt.encodedId)
@@ -349,10 +370,33 @@ func (suite *accountRepositorySuite) TestGetAccountAlias() {
 	}
 }
 
+func (suite *accountRepositorySuite) TestGetAccountAliasContract() {
+	tests := []struct {
+		encodedId          int64
+		expectedEvmAddress []byte
+	}{
+		{encodedId: contract1, expectedEvmAddress: contract1EvmAddress},
+		{encodedId: contract2, expectedEvmAddress: hexutil.MustDecode("0x000000000000000000000000000000000000238d")},
+	}
+
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
+
+	for _, tt := range tests {
+		name := fmt.Sprintf("%d", tt.encodedId)
+		suite.T().Run(name, func(t *testing.T) {
+			accountId := types.NewAccountIdFromEntityId(domain.MustDecodeEntityId(tt.encodedId))
+			actual, err := repo.GetAccountAlias(defaultContext, accountId)
+			assert.Nil(t, err)
+			assert.Equal(t, fmt.Sprintf("0.0.%d", tt.encodedId), actual.String())
+			assert.Equal(t, tt.expectedEvmAddress, actual.GetEvmAddress())
+		})
+	}
+}
+
 func (suite *accountRepositorySuite) TestGetAccountAliasDbConnectionError() {
 	// given
 	accountId := types.NewAccountIdFromEntityId(domain.MustDecodeEntityId(account3))
-	repo := NewAccountRepository(invalidDbClient)
+	repo := NewAccountRepository(invalidDbClient, config.BalanceReplay{})
 
 	// when
 	actual, err := repo.GetAccountAlias(defaultContext, accountId)
@@ -365,7 +409,7 @@ func (suite *accountRepositorySuite) TestGetAccountAliasDbConnectionError() {
 func (suite *accountRepositorySuite) TestGetAccountId() {
 	// given
 	aliasAccountId, _ := types.NewAccountIdFromAlias(account4Alias, 0, 0)
-	repo := NewAccountRepository(dbClient)
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
 
 	// when
 	actual, err := repo.GetAccountId(defaultContext, aliasAccountId)
@@ -378,7 +422,7 @@ func (suite *accountRepositorySuite) TestGetAccountId() {
 func (suite *accountRepositorySuite) TestGetAccountIdNumericAccount() {
 	// given
 	accountId := types.NewAccountIdFromEntityId(domain.MustDecodeEntityId(account1))
-	repo := NewAccountRepository(dbClient)
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
 
 	// when
 	actual, err := repo.GetAccountId(defaultContext, accountId)
@@ -391,7 +435,7 @@ func (suite *accountRepositorySuite) TestGetAccountIdNumericAccount() {
 func (suite *accountRepositorySuite) TestGetAccountIdDbConnectionError() {
 	// given
 	aliasAccountId, _ := types.NewAccountIdFromAlias(account4Alias, 0, 0)
-	repo := NewAccountRepository(invalidDbClient)
+	repo := NewAccountRepository(invalidDbClient, config.BalanceReplay{})
 
 	// when
 	actual, err := repo.GetAccountId(defaultContext, aliasAccountId)
@@ -401,12 +445,140 @@ func (suite *accountRepositorySuite) TestGetAccountIdDbConnectionError() {
 	assert.Equal(suite.T(), types.AccountId{}, actual)
 }
 
+func (suite *accountRepositorySuite) TestGetAccountInfo() {
+	autoRenewPeriod := account3AutoRenewPeriod
+	expirationTimestamp := account3CreatedTimestamp + account3AutoRenewPeriod*int64(time.Second)
+	tests := []struct {
+		encodedId int64
+		expected  *types.AccountInfo
+	}{
+		{
+			encodedId: account3,
+			expected: &types.AccountInfo{
+				AutoRenewPeriod:     &autoRenewPeriod,
+				ExpirationTimestamp: &expirationTimestamp,
+				Id:                  domain.MustDecodeEntityId(account3),
+				Key:                 account3Alias,
+			},
+		},
+		{
+			encodedId: account4,
+			expected:  &types.AccountInfo{Id: domain.MustDecodeEntityId(account4), Key: account4Alias},
+		},
+		{encodedId: account5 + 1},
+	}
+
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
+
+	for _, tt := range tests {
+		name := fmt.Sprintf("%d", tt.encodedId)
+		suite.T().Run(name, func(t *testing.T) {
+			accountId := types.NewAccountIdFromEntityId(domain.MustDecodeEntityId(tt.encodedId))
+			actual, err := repo.GetAccountInfo(defaultContext, accountId)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func (suite *accountRepositorySuite) TestGetAccountLifecycle() {
+	// given
+	completedAccount := account5 + 10
+	hollowAccount := account5 + 11
+	collidingAccount := account5 + 12
+	evmAddress := hexutil.MustDecode("0x71a8c2b2ae4a9c3fcbc6e8b1d4d9fbbe8d2e3f10")
+	createdTimestamp := account5CreatedTimestamp + 100
+	completedTimestamp := createdTimestamp + 200
+	tdomain.NewEntityBuilder(dbClient, completedAccount, createdTimestamp, domain.EntityTypeAccount).
+		EvmAddress(evmAddress).
+		Historical(true).
+		Key([]byte{}).
+		TimestampRange(createdTimestamp, completedTimestamp).
+		Persist()
+	tdomain.NewEntityBuilder(dbClient, completedAccount, createdTimestamp, domain.EntityTypeAccount).
+		EvmAddress(evmAddress).
+		Key(account3Alias).
+		ModifiedTimestamp(completedTimestamp).
+		Persist()
+	tdomain.NewEntityBuilder(dbClient, hollowAccount, createdTimestamp, domain.EntityTypeAccount).
+		Alias(evmAddress).
+		EvmAddress(evmAddress).
+		Persist()
+	tdomain.NewEntityBuilder(dbClient, collidingAccount, createdTimestamp, domain.EntityTypeAccount).
+		EvmAddress(evmAddress).
+		Key(account4Alias).
+		Persist()
+	tests := []struct {
+		encodedId int64
+		expected  *types.AccountLifecycle
+	}{
+		{
+			encodedId: completedAccount,
+			expected: &types.AccountLifecycle{
+				AliasCollisions:    2,
+				CompletedTimestamp: &completedTimestamp,
+				CreatedTimestamp:   &createdTimestamp,
+				EvmAddress:         evmAddress,
+			},
+		},
+		{
+			encodedId: hollowAccount,
+			expected: &types.AccountLifecycle{
+				AliasCollisions:  2,
+				CreatedTimestamp: &createdTimestamp,
+				EvmAddress:       evmAddress,
+			},
+		},
+		{encodedId: collidingAccount},
+		{encodedId: account3},
+		{encodedId: account5 + 20},
+	}
+
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
+
+	for _, tt := range tests {
+		name := fmt.Sprintf("%d", tt.encodedId)
+		suite.T().Run(name, func(t *testing.T) {
+			accountId := types.NewAccountIdFromEntityId(domain.MustDecodeEntityId(tt.encodedId))
+			actual, err := repo.GetAccountLifecycle(defaultContext, accountId)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func (suite *accountRepositorySuite) TestGetAccountLifecycleDbConnectionError() {
+	// given
+	accountId := types.NewAccountIdFromEntityId(domain.MustDecodeEntityId(account3))
+	repo := NewAccountRepository(invalidDbClient, config.BalanceReplay{})
+
+	// when
+	actual, err := repo.GetAccountLifecycle(defaultContext, accountId)
+
+	// then
+	assert.NotNil(suite.T(), err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *accountRepositorySuite) TestGetAccountInfoDbConnectionError() {
+	// given
+	accountId := types.NewAccountIdFromEntityId(domain.MustDecodeEntityId(account3))
+	repo := NewAccountRepository(invalidDbClient, config.BalanceReplay{})
+
+	// when
+	actual, err := repo.GetAccountInfo(defaultContext, accountId)
+
+	// then
+	assert.NotNil(suite.T(), err)
+	assert.Nil(suite.T(), actual)
+}
+
 func (suite *accountRepositorySuite) TestRetrieveBalanceAtBlock() {
 	// given
 	// tokens created at or before first account balance snapshot will not show up in account balance response
 	// transfers before or at the snapshot timestamp should not affect balance calculation
 	accountId := suite.accountId
-	repo := NewAccountRepository(dbClient)
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
 
 	hbarAmount := &types.HbarAmount{Value: initialAccountBalance + sum(cryptoTransferAmounts)}
 	token1Amount := types.NewTokenAmount(token1, sum(token1TransferAmounts[:2]))
@@ -417,7 +589,7 @@ func (suite *accountRepositorySuite) TestRetrieveBalanceAtBlock() {
 
 	// when
 	// query
-	actualAmounts, accountIdString, err := repo.RetrieveBalanceAtBlock(defaultContext, accountId, consensusTimestamp)
+	actualAmounts, accountIdString, _, err := repo.RetrieveBalanceAtBlock(defaultContext, accountId, consensusTimestamp)
 
 	// then
 	assert.Nil(suite.T(), err)
@@ -426,7 +598,7 @@ func (suite *accountRepositorySuite) TestRetrieveBalanceAtBlock() {
 
 	// when
 	// query at dissociateTimestamp, balances for token2 and token3 should be 0
-	actualAmounts, accountIdString, err = repo.RetrieveBalanceAtBlock(defaultContext, accountId, dissociateTimestamp)
+	actualAmounts, accountIdString, _, err = repo.RetrieveBalanceAtBlock(defaultContext, accountId, dissociateTimestamp)
 	token2Amount = types.NewTokenAmount(token2, 0)
 	token3Amount = types.NewTokenAmount(token3, 0)
 	expectedAmounts = types.AmountSlice{hbarAmount, token1Amount, token2Amount, token3Amount, token4Amount}
@@ -437,6 +609,70 @@ func (suite *accountRepositorySuite) TestRetrieveBalanceAtBlock() {
 	assert.ElementsMatch(suite.T(), expectedAmounts, actualAmounts)
 }
 
+func (suite *accountRepositorySuite) TestRetrieveBalanceAtBlockKey() {
+	tests := []struct {
+		encodedId int64
+		expected  []byte
+	}{
+		{encodedId: account1, expected: nil},
+		{encodedId: account3, expected: account3Alias},
+		{encodedId: account4, expected: account4Alias},
+		{encodedId: account5 + 1, expected: nil},
+	}
+
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
+
+	for _, tt := range tests {
+		name := fmt.Sprintf("%d", tt.encodedId)
+		suite.T().Run(name, func(t *testing.T) {
+			accountId := types.NewAccountIdFromEntityId(domain.MustDecodeEntityId(tt.encodedId))
+			_, _, actual, err := repo.RetrieveBalanceAtBlock(defaultContext, accountId, thirdSnapshotTimestamp+10)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func (suite *accountRepositorySuite) TestRetrieveBalanceAtBlockWithinReplayBounds() {
+	// given
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{MaxTransfers: 1000, MaxWindow: time.Hour})
+
+	// when
+	actualAmounts, _, _, err := repo.RetrieveBalanceAtBlock(defaultContext, suite.accountId, consensusTimestamp)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.NotEmpty(suite.T(), actualAmounts)
+}
+
+func (suite *accountRepositorySuite) TestRetrieveBalanceAtBlockReplayBoundExceeded() {
+	tests := []struct {
+		name          string
+		balanceReplay config.BalanceReplay
+	}{
+		{name: "max transfers", balanceReplay: config.BalanceReplay{MaxTransfers: 1}},
+		{name: "max window", balanceReplay: config.BalanceReplay{MaxWindow: time.Nanosecond}},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			// given
+			repo := NewAccountRepository(dbClient, tt.balanceReplay)
+
+			// when
+			actualAmounts, _, _, err := repo.RetrieveBalanceAtBlock(defaultContext, suite.accountId, consensusTimestamp)
+
+			// then
+			assert.Nil(t, actualAmounts)
+			assert.NotNil(t, err)
+			assert.Equal(t, errors.ErrBalanceReplayBoundExceeded.Code, err.Code)
+			assert.Equal(t, fmt.Sprintf("%d", firstSnapshotTimestamp), err.Details["snapshot_timestamp"])
+			assert.NotEmpty(t, err.Details["guidance"])
+			assert.NotEmpty(t, err.Details["reason"])
+		})
+	}
+}
+
 func (suite *accountRepositorySuite) TestRetrieveBalanceAtBlockAfterSecondSnapshot() {
 	// given
 	// remove any transfers in db. with the balance info in the second snapshot, this test verifies the account balance
@@ -487,10 +723,10 @@ func (suite *accountRepositorySuite) TestRetrieveBalanceAtBlockAfterSecondSnapsh
 	token3Amount := types.NewTokenAmount(token3, 3)
 	token4Amount := types.NewTokenAmount(token4, 0)
 	expectedAmount := types.AmountSlice{hbarAmount, token1Amount, token2Amount, token3Amount, token4Amount}
-	repo := NewAccountRepository(dbClient)
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
 
 	// when
-	actualAmounts, accountIdString, err := repo.RetrieveBalanceAtBlock(
+	actualAmounts, accountIdString, _, err := repo.RetrieveBalanceAtBlock(
 		defaultContext,
 		accountId,
 		secondSnapshotTimestamp+6,
@@ -516,13 +752,13 @@ func (suite *accountRepositorySuite) TestRetrieveBalanceAtBlockForDeletedAccount
 		types.NewTokenAmount(token3, 0),
 		types.NewTokenAmount(token4, 0),
 	}
-	repo := NewAccountRepository(dbClient)
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
 
 	// when
 	// account is deleted before the third account balance file, so there is no balance info in the file. querying the
 	// account balance for a timestamp after the third account balance file should then return the balance at the time
 	// the account is deleted
-	actualAmounts, accountIdString, err := repo.RetrieveBalanceAtBlock(
+	actualAmounts, accountIdString, key, err := repo.RetrieveBalanceAtBlock(
 		defaultContext,
 		accountId,
 		thirdSnapshotTimestamp+10,
@@ -532,6 +768,7 @@ func (suite *accountRepositorySuite) TestRetrieveBalanceAtBlockForDeletedAccount
 	assert.Nil(suite.T(), err)
 	assert.Equal(suite.T(), suite.accountIdString, accountIdString)
 	assert.ElementsMatch(suite.T(), expectedAmounts, actualAmounts)
+	assert.Nil(suite.T(), key)
 }
 
 func (suite *accountRepositorySuite) TestRetrieveBalanceAtBlockAtAccountDeletionTime() {
@@ -548,10 +785,10 @@ func (suite *accountRepositorySuite) TestRetrieveBalanceAtBlockAtAccountDeletion
 		types.NewTokenAmount(token3, 0),
 		types.NewTokenAmount(token4, 0),
 	}
-	repo := NewAccountRepository(dbClient)
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
 
 	// when
-	actualAmounts, accountIdString, err := repo.RetrieveBalanceAtBlock(
+	actualAmounts, accountIdString, _, err := repo.RetrieveBalanceAtBlock(
 		defaultContext,
 		accountId,
 		accountDeleteTimestamp,
@@ -573,10 +810,10 @@ func (suite *accountRepositorySuite) TestRetrieveBalanceAtBlockNoAccountEntity()
 	token3Amount := types.NewTokenAmount(token3, 2)
 	token4Amount := types.NewTokenAmount(token4, 0)
 	expectedAmounts := types.AmountSlice{hbarAmount, token1Amount, token2Amount, token3Amount, token4Amount}
-	repo := NewAccountRepository(dbClient)
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
 
 	// when
-	actualAmounts, accountIdString, err := repo.RetrieveBalanceAtBlock(
+	actualAmounts, accountIdString, _, err := repo.RetrieveBalanceAtBlock(
 		defaultContext,
 		accountId,
 		consensusTimestamp,
@@ -592,14 +829,14 @@ func (suite *accountRepositorySuite) TestRetrieveBalanceAtBlockNoTokenEntity() {
 	// given
 	accountId := suite.accountId
 	db.ExecSql(dbClient, truncateTokenSql)
-	repo := NewAccountRepository(dbClient)
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
 
 	// no token entities, so only hbar balance
 	hbarAmount := &types.HbarAmount{Value: initialAccountBalance + sum(cryptoTransferAmounts)}
 	expectedAmounts := types.AmountSlice{hbarAmount}
 
 	// when
-	actualAmounts, accountIdString, err := repo.RetrieveBalanceAtBlock(
+	actualAmounts, accountIdString, _, err := repo.RetrieveBalanceAtBlock(
 		defaultContext,
 		accountId,
 		consensusTimestamp,
@@ -623,10 +860,10 @@ func (suite *accountRepositorySuite) TestRetrieveBalanceAtBlockNoInitialBalance(
 	token4Amount := types.NewTokenAmount(token4, 0)
 	expectedAmounts := types.AmountSlice{hbarAmount, token1Amount, token2Amount, token3Amount, token4Amount}
 
-	repo := NewAccountRepository(dbClient)
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
 
 	// when
-	actualAmounts, accountIdString, err := repo.RetrieveBalanceAtBlock(
+	actualAmounts, accountIdString, _, err := repo.RetrieveBalanceAtBlock(
 		defaultContext,
 		accountId,
 		consensusTimestamp,
@@ -642,10 +879,10 @@ func (suite *accountRepositorySuite) TestRetrieveBalanceAtBlockNoAccountBalanceF
 	// given
 	db.ExecSql(dbClient, truncateAccountBalanceFileSql)
 	accountId := suite.accountId
-	repo := NewAccountRepository(dbClient)
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
 
 	// when
-	actualAmounts, accountIdString, err := repo.RetrieveBalanceAtBlock(
+	actualAmounts, accountIdString, _, err := repo.RetrieveBalanceAtBlock(
 		defaultContext,
 		accountId,
 		consensusTimestamp,
@@ -660,10 +897,10 @@ func (suite *accountRepositorySuite) TestRetrieveBalanceAtBlockNoAccountBalanceF
 func (suite *accountRepositorySuite) TestRetrieveBalanceAtBlockDbConnectionError() {
 	// given
 	accountId := suite.accountId
-	repo := NewAccountRepository(invalidDbClient)
+	repo := NewAccountRepository(invalidDbClient, config.BalanceReplay{})
 
 	// when
-	actualAmounts, accountIdString, err := repo.RetrieveBalanceAtBlock(
+	actualAmounts, accountIdString, _, err := repo.RetrieveBalanceAtBlock(
 		defaultContext,
 		accountId,
 		consensusTimestamp,
@@ -675,6 +912,418 @@ func (suite *accountRepositorySuite) TestRetrieveBalanceAtBlockDbConnectionError
 	assert.Nil(suite.T(), actualAmounts)
 }
 
+func (suite *accountRepositorySuite) TestRetrieveAllBalancesAtBlock() {
+	// given
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
+	expected := map[int64]types.AmountSlice{
+		account1: {
+			&types.HbarAmount{Value: initialAccountBalance + sum(cryptoTransferAmounts)},
+			&types.TokenAmount{
+				TokenId: token1.TokenId,
+				Type:    domain.TokenTypeFungibleCommon,
+				Value:   token1TransferAmounts[0] + token1TransferAmounts[1],
+			},
+			&types.TokenAmount{
+				TokenId: token2.TokenId,
+				Type:    domain.TokenTypeFungibleCommon,
+				Value:   sum(token2TransferAmounts),
+			},
+			&types.TokenAmount{
+				TokenId: token3.TokenId,
+				Type:    domain.TokenTypeNonFungibleUnique,
+				Value:   int64(len(token3ReceivedSerials) - len(token3SentSerials)),
+			},
+		},
+		// the treasury has no balance in the snapshot, so only the nft transfers to and from account1 count
+		treasury: {
+			&types.TokenAmount{
+				TokenId: token3.TokenId,
+				Type:    domain.TokenTypeNonFungibleUnique,
+				Value:   int64(len(token3SentSerials) - len(token3ReceivedSerials)),
+			},
+		},
+	}
+
+	// when
+	actual, err := repo.RetrieveAllBalancesAtBlock(defaultContext, consensusTimestamp)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+}
+
+func (suite *accountRepositorySuite) TestRetrieveAllBalancesAtBlockAfterDissociate() {
+	// given
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
+
+	// when
+	actual, err := repo.RetrieveAllBalancesAtBlock(defaultContext, dissociateTimestamp)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Len(suite.T(), actual[account1], 2)
+	assert.Equal(suite.T(), token1.TokenId, actual[account1][1].(*types.TokenAmount).TokenId)
+}
+
+func (suite *accountRepositorySuite) TestRetrieveAllBalancesAtBlockNoAccountBalanceFile() {
+	// given
+	db.ExecSql(dbClient, truncateAccountBalanceFileSql)
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
+
+	// when
+	actual, err := repo.RetrieveAllBalancesAtBlock(defaultContext, consensusTimestamp)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrNodeIsStarting, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *accountRepositorySuite) TestRetrieveAllBalancesAtBlockDbConnectionError() {
+	// given
+	repo := NewAccountRepository(invalidDbClient, config.BalanceReplay{})
+
+	// when
+	actual, err := repo.RetrieveAllBalancesAtBlock(defaultContext, consensusTimestamp)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *accountRepositorySuite) TestRetrieveBalanceReconciliation() {
+	// given
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
+	expected := &types.BalanceReconciliation{
+		AccountId:        domain.MustDecodeEntityId(account1),
+		Nearest:          types.BalanceSnapshot{Timestamp: thirdSnapshotTimestamp},
+		Previous:         types.BalanceSnapshot{Balance: initialAccountBalance, Timestamp: firstSnapshotTimestamp},
+		TransactionCount: 4,
+		Transactions: []types.ReconciliationTransaction{
+			{Amount: cryptoTransferAmounts[0], ConsensusTimestamp: firstSnapshotTimestamp + 1},
+			{Amount: cryptoTransferAmounts[1], ConsensusTimestamp: firstSnapshotTimestamp + 5},
+		},
+		TransferChange: -initialAccountBalance,
+	}
+
+	// when
+	actual, err := repo.RetrieveBalanceReconciliation(defaultContext, account1, thirdSnapshotTimestamp, 2)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+	assert.Zero(suite.T(), actual.Delta())
+}
+
+func (suite *accountRepositorySuite) TestRetrieveBalanceReconciliationSnapshotNotFound() {
+	// given
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
+
+	// when
+	actual, err := repo.RetrieveBalanceReconciliation(defaultContext, account1, thirdSnapshotTimestamp-1, 2)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrBalanceSnapshotNotFound, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *accountRepositorySuite) TestRetrieveBalanceReconciliationDbConnectionError() {
+	// given
+	repo := NewAccountRepository(invalidDbClient, config.BalanceReplay{})
+
+	// when
+	actual, err := repo.RetrieveBalanceReconciliation(defaultContext, account1, thirdSnapshotTimestamp, 2)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *accountRepositorySuite) TestRetrieveGenesisAccounts() {
+	// given
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
+
+	// when
+	actual, err := repo.RetrieveGenesisAccounts(defaultContext, 0, math.MaxInt64)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), []int64{account1}, actual)
+}
+
+func (suite *accountRepositorySuite) TestRetrieveGenesisAccountsOutOfRange() {
+	// given
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
+
+	// when
+	actual, err := repo.RetrieveGenesisAccounts(defaultContext, 1, 1000)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Empty(suite.T(), actual)
+}
+
+func (suite *accountRepositorySuite) TestRetrieveGenesisAccountsNoAccountBalanceFile() {
+	// given
+	db.ExecSql(dbClient, truncateAccountBalanceFileSql)
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
+
+	// when
+	actual, err := repo.RetrieveGenesisAccounts(defaultContext, 0, math.MaxInt64)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrNodeIsStarting, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *accountRepositorySuite) TestRetrieveGenesisAccountsDbConnectionError() {
+	// given
+	repo := NewAccountRepository(invalidDbClient, config.BalanceReplay{})
+
+	// when
+	actual, err := repo.RetrieveGenesisAccounts(defaultContext, 0, math.MaxInt64)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *accountRepositorySuite) TestRetrieveHbarBalancesAtBlock() {
+	// given
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
+	expected := map[int64]types.HbarAmount{
+		account1: {Value: initialAccountBalance + sum(cryptoTransferAmounts)},
+		account2: {},
+	}
+
+	// when
+	actual, err := repo.RetrieveHbarBalancesAtBlock(defaultContext, []int64{account1, account2}, consensusTimestamp)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+}
+
+func (suite *accountRepositorySuite) TestRetrieveHbarBalancesAtBlockForDeletedAccount() {
+	// given
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
+	expected := map[int64]types.HbarAmount{account1: {}}
+
+	// when
+	actual, err := repo.RetrieveHbarBalancesAtBlock(defaultContext, []int64{account1}, thirdSnapshotTimestamp+10)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+}
+
+func (suite *accountRepositorySuite) TestRetrieveHbarBalancesAtBlockNoAccountBalanceFile() {
+	// given
+	db.ExecSql(dbClient, truncateAccountBalanceFileSql)
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
+
+	// when
+	actual, err := repo.RetrieveHbarBalancesAtBlock(defaultContext, []int64{account1}, consensusTimestamp)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrNodeIsStarting, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *accountRepositorySuite) TestRetrieveHbarBalancesAtBlockDbConnectionError() {
+	// given
+	repo := NewAccountRepository(invalidDbClient, config.BalanceReplay{})
+
+	// when
+	actual, err := repo.RetrieveHbarBalancesAtBlock(defaultContext, []int64{account1}, consensusTimestamp)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *accountRepositorySuite) TestRetrieveTokenBalancesAtBlock() {
+	// given
+	// token5 is created before the first snapshot and the token with id 999 doesn't exist, so both are omitted
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
+	tokenIds := []int64{999, encodedTokenId4, encodedTokenId3, encodedTokenId2, encodedTokenId1, encodedTokenId5}
+	expected := types.AmountSlice{
+		types.NewTokenAmount(token1, sum(token1TransferAmounts[:2])),
+		types.NewTokenAmount(token2, sum(token2TransferAmounts)),
+		types.NewTokenAmount(token3, int64(len(token3ReceivedSerials)-len(token3SentSerials))),
+		types.NewTokenAmount(token4, 0),
+	}
+
+	// when
+	actual, err := repo.RetrieveTokenBalancesAtBlock(defaultContext, account1, tokenIds, consensusTimestamp)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+}
+
+func (suite *accountRepositorySuite) TestRetrieveTokenBalancesAtBlockAfterDissociate() {
+	// given
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
+	tokenIds := []int64{encodedTokenId1, encodedTokenId2, encodedTokenId3, encodedTokenId2}
+	expected := types.AmountSlice{
+		types.NewTokenAmount(token1, sum(token1TransferAmounts[:2])),
+		types.NewTokenAmount(token2, 0),
+		types.NewTokenAmount(token3, 0),
+	}
+
+	// when
+	actual, err := repo.RetrieveTokenBalancesAtBlock(defaultContext, account1, tokenIds, dissociateTimestamp)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+}
+
+func (suite *accountRepositorySuite) TestRetrieveTokenBalancesAtBlockFromSnapshot() {
+	// given
+	// the token1 balance in the snapshot is interpolated with the transfer after it
+	snapshotTimestamp := dissociateTimestamp + 10
+	tdomain.NewAccountBalanceFileBuilder(dbClient, snapshotTimestamp).
+		AddTokenBalance(account1, encodedTokenId1, 1000).
+		Persist()
+	tdomain.NewTokenTransferBuilder(dbClient).
+		AccountId(account1).
+		Amount(-100).
+		Timestamp(snapshotTimestamp + 1).
+		TokenId(encodedTokenId1).
+		Persist()
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
+	expected := types.AmountSlice{types.NewTokenAmount(token1, 900)}
+
+	// when
+	actual, err := repo.RetrieveTokenBalancesAtBlock(
+		defaultContext,
+		account1,
+		[]int64{encodedTokenId1},
+		snapshotTimestamp+1,
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+}
+
+func (suite *accountRepositorySuite) TestRetrieveTokenBalancesAtBlockNoAccountBalanceFile() {
+	// given
+	db.ExecSql(dbClient, truncateAccountBalanceFileSql)
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
+
+	// when
+	actual, err := repo.RetrieveTokenBalancesAtBlock(
+		defaultContext,
+		account1,
+		[]int64{encodedTokenId1},
+		consensusTimestamp,
+	)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrNodeIsStarting, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *accountRepositorySuite) TestRetrieveTokenBalancesAtBlockDbConnectionError() {
+	// given
+	repo := NewAccountRepository(invalidDbClient, config.BalanceReplay{})
+
+	// when
+	actual, err := repo.RetrieveTokenBalancesAtBlock(
+		defaultContext,
+		account1,
+		[]int64{encodedTokenId1},
+		consensusTimestamp,
+	)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+// selectTokenBalancesByReplay is the baseline of BenchmarkRetrieveTokenBalancesAtBlock, which replays all the transfers
+// of the tokens of the account since genesis
+const selectTokenBalancesByReplay = `select token_id, sum(amount)::bigint as value
+                                     from token_transfer
+                                     where account_id = @account_id and token_id = any(@token_ids::bigint[]) and
+                                       consensus_timestamp <= @timestamp
+                                     group by token_id
+                                     order by token_id`
+
+// BenchmarkRetrieveTokenBalancesAtBlock compares the latency of computing the historical balances of a token-heavy
+// account by replaying its token transfers since genesis and by interpolating the nearest token balance snapshot with
+// the transfers after it, run with "go test -run ^$ -bench RetrieveTokenBalancesAtBlock ./app/persistence/"
+func BenchmarkRetrieveTokenBalancesAtBlock(b *testing.B) {
+	const (
+		tokenCount        = 100
+		transfersPerToken = 200
+	)
+
+	db.CleanupDb(dbResource.GetDb())
+	tdomain.NewAccountBalanceFileBuilder(dbClient, 1).Persist()
+	tokenIds := make([]int64, 0, tokenCount)
+	for i := int64(0); i < tokenCount; i++ {
+		tokenId := encodedTokenId1 + i
+		tdomain.NewTokenBuilder(dbClient, tokenId, 2, treasury).Persist()
+		tokenIds = append(tokenIds, tokenId)
+	}
+
+	// all but the last transfer of each token happen before the latest snapshot
+	timestamp := int64(10)
+	snapshot := tdomain.NewAccountBalanceFileBuilder(dbClient, timestamp+tokenCount*(transfersPerToken-1))
+	for i := 0; i < transfersPerToken; i++ {
+		if i == transfersPerToken-1 {
+			for _, tokenId := range tokenIds {
+				snapshot.AddTokenBalance(account1, tokenId, transfersPerToken-1)
+			}
+			snapshot.Persist()
+		}
+
+		for _, tokenId := range tokenIds {
+			timestamp++
+			tdomain.NewTokenTransferBuilder(dbClient).
+				AccountId(account1).
+				Amount(1).
+				Timestamp(timestamp).
+				TokenId(tokenId).
+				Persist()
+		}
+	}
+	ids := pgtype.Int8Array{}
+	if err := ids.Set(tokenIds); err != nil {
+		b.Fatal(err)
+	}
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
+
+	b.Run("replay", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			balances := make([]accountTokenBalance, 0, tokenCount)
+			if err := dbClient.GetDb().
+				Raw(
+					selectTokenBalancesByReplay,
+					sql.Named("account_id", account1),
+					sql.Named("timestamp", timestamp),
+					sql.Named("token_ids", ids),
+				).
+				Scan(&balances).
+				Error; err != nil || len(balances) != tokenCount {
+				b.Fatalf("Failed to replay the balances of %d tokens: %v", tokenCount, err)
+			}
+		}
+	})
+
+	b.Run("snapshot", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			amounts, err := repo.RetrieveTokenBalancesAtBlock(defaultContext, account1, tokenIds, timestamp)
+			if err != nil || len(amounts) != tokenCount || amounts[0].GetValue() != transfersPerToken {
+				b.Fatalf("Failed to interpolate the balances of %d tokens: %v", tokenCount, err)
+			}
+		}
+	})
+}
+
 func sum(amounts []int64) int64 {
 	var value int64
 	for _, amount := range amounts {
@@ -743,7 +1392,7 @@ func (suite *accountRepositoryWithAliasSuite) TestGetAccountAlias() {
 		{encodedId: account4, expectedAlias: account4Alias},
 	}
 
-	repo := NewAccountRepository(dbClient)
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
 
 	for _, tt := range tests {
 		name := fmt.Sprintf("%d", tt.encodedId)
@@ -756,9 +1405,78 @@ func (suite *accountRepositoryWithAliasSuite) TestGetAccountAlias() {
 	}
 }
 
+func (suite *accountRepositoryWithAliasSuite) TestFindAccountsByAlias() {
+	// given
+	transaction := tdomain.NewTransactionBuilder(dbClient, account4, account1CreatedTimestamp-1).Persist()
+	createdTimestamp1 := account1CreatedTimestamp
+	createdTimestamp2 := account2CreatedTimestamp
+	transactionType := int32(transaction.Type)
+	expected := types.AliasAccounts{
+		{
+			AccountId:        domain.MustDecodeEntityId(account2),
+			CreatedTimestamp: &createdTimestamp2,
+			Deleted:          true,
+		},
+		{
+			AccountId:               domain.MustDecodeEntityId(account1),
+			CreatedTimestamp:        &createdTimestamp1,
+			CreationTransactionHash: transaction.TransactionHash,
+			CreationTransactionType: &transactionType,
+		},
+	}
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
+
+	// when
+	actual, err := repo.FindAccountsByAlias(defaultContext, suite.accountAlias, nil)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+}
+
+func (suite *accountRepositoryWithAliasSuite) TestFindAccountsByAliasEvmAddress() {
+	// given
+	createdTimestamp := account5CreatedTimestamp
+	expected := types.AliasAccounts{
+		{AccountId: domain.MustDecodeEntityId(contract1), CreatedTimestamp: &createdTimestamp},
+	}
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
+
+	// when
+	actual, err := repo.FindAccountsByAlias(defaultContext, nil, contract1EvmAddress)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+}
+
+func (suite *accountRepositoryWithAliasSuite) TestFindAccountsByAliasNotFound() {
+	// given
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
+
+	// when
+	actual, err := repo.FindAccountsByAlias(defaultContext, randstr.Bytes(34), randstr.Bytes(20))
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Empty(suite.T(), actual)
+}
+
+func (suite *accountRepositoryWithAliasSuite) TestFindAccountsByAliasDbConnectionError() {
+	// given
+	repo := NewAccountRepository(invalidDbClient, config.BalanceReplay{})
+
+	// when
+	actual, err := repo.FindAccountsByAlias(defaultContext, suite.accountAlias, nil)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
 func (suite *accountRepositoryWithAliasSuite) TestGetAccountAliasThrowWhenInvalidAlias() {
 	accountId := types.NewAccountIdFromEntityId(domain.MustDecodeEntityId(account5))
-	repo := NewAccountRepository(dbClient)
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
 	actual, err := repo.GetAccountAlias(defaultContext, accountId)
 	assert.NotNil(suite.T(), err)
 	assert.Equal(suite.T(), types.AccountId{}, actual)
@@ -768,7 +1486,7 @@ func (suite *accountRepositoryWithAliasSuite) TestGetAccountId() {
 	// given
 	aliasAccountId, err := types.NewAccountIdFromAlias(account4Alias, 0, 0)
 	assert.NoError(suite.T(), err)
-	repo := NewAccountRepository(dbClient)
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
 	expected := types.NewAccountIdFromEntityId(domain.MustDecodeEntityId(account4))
 
 	// when
@@ -779,6 +1497,20 @@ func (suite *accountRepositoryWithAliasSuite) TestGetAccountId() {
 	assert.Equal(suite.T(), expected, actual)
 }
 
+func (suite *accountRepositoryWithAliasSuite) TestRetrieveBalanceAtBlockKeyByAlias() {
+	// given
+	aliasAccountId, err := types.NewAccountIdFromAlias(account4Alias, 0, 0)
+	assert.NoError(suite.T(), err)
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
+
+	// when
+	_, _, actual, rErr := repo.RetrieveBalanceAtBlock(defaultContext, aliasAccountId, account4CreatedTimestamp)
+
+	// then
+	assert.Nil(suite.T(), rErr)
+	assert.Equal(suite.T(), account4Alias, actual)
+}
+
 func (suite *accountRepositoryWithAliasSuite) TestGetAccountIdDeleted() {
 	// given
 	tdomain.NewEntityBuilder(dbClient, account4, 1, domain.EntityTypeAccount).
@@ -786,7 +1518,7 @@ func (suite *accountRepositoryWithAliasSuite) TestGetAccountIdDeleted() {
 		ModifiedTimestamp(accountDeleteTimestamp).
 		Persist()
 	aliasAccountId, _ := types.NewAccountIdFromAlias(account4Alias, 0, 0)
-	repo := NewAccountRepository(dbClient)
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
 
 	// when
 	actual, rErr := repo.GetAccountId(defaultContext, aliasAccountId)
@@ -801,10 +1533,10 @@ func (suite *accountRepositoryWithAliasSuite) TestRetrieveBalanceAtBlockNoAccoun
 	// mapping, no balance info for the account can be retrieved
 	// given
 	db.ExecSql(dbClient, truncateEntitySql)
-	repo := NewAccountRepository(dbClient)
+	repo := NewAccountRepository(dbClient, config.BalanceReplay{})
 
 	// when
-	actualAmounts, accountIdString, err := repo.RetrieveBalanceAtBlock(
+	actualAmounts, accountIdString, _, err := repo.RetrieveBalanceAtBlock(
 		defaultContext,
 		suite.accountId,
 		consensusTimestamp,
@@ -815,3 +1547,9 @@ func (suite *accountRepositoryWithAliasSuite) TestRetrieveBalanceAtBlockNoAccoun
 	assert.Empty(suite.T(), accountIdString)
 	assert.Nil(suite.T(), actualAmounts)
 }
+
+func TestNullableBytes(t *testing.T) {
+	assert.Nil(t, nullableBytes(nil))
+	assert.Nil(t, nullableBytes([]byte{}))
+	assert.Equal(t, []byte{1}, nullableBytes([]byte{1}))
+}
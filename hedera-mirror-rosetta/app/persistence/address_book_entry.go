@@ -30,7 +30,9 @@ import (
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/tools"
 	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
 const (
@@ -39,6 +41,8 @@ const (
 	latestNodeServiceEndpoints       = `select
                                     abe.node_id,
                                     abe.node_account_id,
+                                    abe.node_cert_hash,
+                                    abe.public_key,
                                     string_agg(ip_address_v4 || ':' || port::text, ','
                                       order by ip_address_v4,port) endpoints
                                   from (
@@ -47,12 +51,14 @@ const (
                                   join address_book_entry abe on abe.consensus_timestamp = current.max
                                   left join address_book_service_endpoint abse
                                     on abse.consensus_timestamp = current.max and abse.node_id = abe.node_id 
-                                  group by abe.node_id, abe.node_account_id`
+                                  group by abe.node_id, abe.node_account_id, abe.node_cert_hash, abe.public_key`
 )
 
 type nodeServiceEndpoint struct {
 	NodeId        int64
 	NodeAccountId domain.EntityId
+	NodeCertHash  []byte
+	PublicKey     string
 	Endpoints     string
 }
 
@@ -64,7 +70,9 @@ func (n nodeServiceEndpoint) toAddressBookEntry() types.AddressBookEntry {
 	return types.AddressBookEntry{
 		NodeId:    n.NodeId,
 		AccountId: n.NodeAccountId,
+		CertHash:  strings.TrimSpace(string(n.NodeCertHash)),
 		Endpoints: endpoints,
+		PublicKey: tools.SafeRemoveHexPrefix(strings.TrimSpace(n.PublicKey)),
 	}
 }
 
@@ -74,16 +82,15 @@ type addressBookEntryRepository struct {
 }
 
 func (aber *addressBookEntryRepository) Entries(ctx context.Context) (*types.AddressBookEntries, *rTypes.Error) {
-	db, cancel := aber.dbClient.GetDbWithContext(ctx)
-	defer cancel()
-
 	nodes := make([]nodeServiceEndpoint, 0)
 	// address book file 101 has service endpoints for nodes, resort to file 102 if 101 doesn't exist
 	for _, fileId := range []int64{fileId101, fileId102} {
-		if err := db.Raw(
-			latestNodeServiceEndpoints,
-			sql.Named("file_id", fileId),
-		).Scan(&nodes).Error; err != nil {
+		if err := aber.dbClient.Query(ctx, "latestNodeServiceEndpoints", func(db *gorm.DB) error {
+			return db.Raw(
+				latestNodeServiceEndpoints,
+				sql.Named("file_id", fileId),
+			).Scan(&nodes).Error
+		}); err != nil {
 			log.Error("Failed to get latest node service endpoints", err)
 			return nil, errors.ErrDatabaseError
 		}
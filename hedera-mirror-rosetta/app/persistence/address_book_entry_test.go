@@ -21,6 +21,7 @@
 package persistence
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
@@ -76,8 +77,14 @@ func (suite *addressBookEntryRepositorySuite) TestEntries() {
 
 	expected := &types.AddressBookEntries{
 		Entries: []types.AddressBookEntry{
-			{0, accountId80, []string{"192.168.0.1:50211", "192.168.0.1:50217", "192.168.0.10:50211"}},
-			{1, accountId70, []string{"192.168.1.10:50211"}},
+			{
+				0,
+				accountId80,
+				getNodeCertHash(0),
+				[]string{"192.168.0.1:50211", "192.168.0.1:50217", "192.168.0.10:50211"},
+				getNodePublicKey(0),
+			},
+			{1, accountId70, getNodeCertHash(1), []string{"192.168.1.10:50211"}, getNodePublicKey(1)},
 		},
 	}
 	repo := NewAddressBookEntryRepository(dbClient)
@@ -111,8 +118,8 @@ func (suite *addressBookEntryRepositorySuite) TestEntriesNoServiceEndpoints() {
 
 	expected := &types.AddressBookEntries{
 		Entries: []types.AddressBookEntry{
-			{0, accountId80, []string{}},
-			{1, accountId70, []string{}},
+			{0, accountId80, getNodeCertHash(0), []string{}, getNodePublicKey(0)},
+			{1, accountId70, getNodeCertHash(1), []string{}, getNodePublicKey(1)},
 		},
 	}
 	repo := NewAddressBookEntryRepository(dbClient)
@@ -139,8 +146,8 @@ func (suite *addressBookEntryRepositorySuite) TestEntriesNoFile101() {
 
 	expected := &types.AddressBookEntries{
 		Entries: []types.AddressBookEntry{
-			{0, accountId70, []string{}},
-			{1, accountId80, []string{}},
+			{0, accountId70, getNodeCertHash(0), []string{}, getNodePublicKey(0)},
+			{1, accountId80, getNodeCertHash(1), []string{}, getNodePublicKey(1)},
 		},
 	}
 	repo := NewAddressBookEntryRepository(dbClient)
@@ -196,5 +203,17 @@ func getAddressBookEntry(
 		ConsensusTimestamp: consensusTimestamp,
 		NodeId:             nodeId,
 		NodeAccountId:      nodeAccountId,
+		NodeCertHash:       []byte(getNodeCertHash(nodeId)),
+		PublicKey:          getNodePublicKey(nodeId),
 	}
 }
+
+// getNodePublicKey returns a fake hex encoded DER of the node's public key
+func getNodePublicKey(nodeId int64) string {
+	return fmt.Sprintf("308201a2%04d", nodeId)
+}
+
+// getNodeCertHash returns a fake hex encoded SHA-384 hash of the node's certificate
+func getNodeCertHash(nodeId int64) string {
+	return fmt.Sprintf("%096d", nodeId)
+}
@@ -0,0 +1,159 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package persistence
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/db"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/recordstream"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/rest"
+	"github.com/pkg/errors"
+)
+
+const (
+	BackendTypePostgres = "postgres"
+	BackendTypeRest     = "rest"
+)
+
+// postgresBackend is the backend with the repositories reading from the mirror node database
+type postgresBackend struct {
+	accountRepo          interfaces.AccountRepository
+	addressBookEntryRepo interfaces.AddressBookEntryRepository
+	blockRepo            interfaces.BlockRepository
+	fileDataRepo         interfaces.FileDataRepository
+	indexRepo            interfaces.IndexRepository
+	scheduleRepo         interfaces.ScheduleRepository
+	schemaVersionRepo    interfaces.SchemaVersionRepository
+	stakingRepo          interfaces.StakingRepository
+	tokenRepo            interfaces.TokenRepository
+	topicMessageRepo     interfaces.TopicMessageRepository
+	transactionRepo      interfaces.TransactionRepository
+}
+
+func (b *postgresBackend) AccountRepository() interfaces.AccountRepository {
+	return b.accountRepo
+}
+
+func (b *postgresBackend) AddressBookEntryRepository() interfaces.AddressBookEntryRepository {
+	return b.addressBookEntryRepo
+}
+
+func (b *postgresBackend) BlockRepository() interfaces.BlockRepository {
+	return b.blockRepo
+}
+
+func (b *postgresBackend) FileDataRepository() interfaces.FileDataRepository {
+	return b.fileDataRepo
+}
+
+func (b *postgresBackend) IndexRepository() interfaces.IndexRepository {
+	return b.indexRepo
+}
+
+func (b *postgresBackend) ScheduleRepository() interfaces.ScheduleRepository {
+	return b.scheduleRepo
+}
+
+func (b *postgresBackend) SchemaVersionRepository() interfaces.SchemaVersionRepository {
+	return b.schemaVersionRepo
+}
+
+func (b *postgresBackend) StakingRepository() interfaces.StakingRepository {
+	return b.stakingRepo
+}
+
+func (b *postgresBackend) TokenRepository() interfaces.TokenRepository {
+	return b.tokenRepo
+}
+
+func (b *postgresBackend) TopicMessageRepository() interfaces.TopicMessageRepository {
+	return b.topicMessageRepo
+}
+
+func (b *postgresBackend) TransactionRepository() interfaces.TransactionRepository {
+	return b.transactionRepo
+}
+
+// NewBackend creates the persistence backend of the configured type, either the mirror node database or the mirror node
+// REST API for deployments without database access. Other backends can be added by implementing interfaces.Backend. The
+// backend is wrapped with the experimental record stream fallback if enabled
+func NewBackend(ctx context.Context, rosettaConfig *config.Config) (interfaces.Backend, error) {
+	var backend interfaces.Backend
+	switch strings.ToLower(rosettaConfig.Backend.Type) {
+	case BackendTypePostgres:
+		backend = newPostgresBackend(ctx, db.ConnectToDb(rosettaConfig.Db), rosettaConfig)
+	case BackendTypeRest:
+		backend = rest.NewBackend(rosettaConfig.Backend.Rest, rosettaConfig)
+	default:
+		return nil, errors.Errorf("Unsupported backend type %s", rosettaConfig.Backend.Type)
+	}
+
+	return wrapBackend(ctx, backend, rosettaConfig)
+}
+
+// wrapBackend wraps the backend with the record stream fallback if enabled
+func wrapBackend(
+	ctx context.Context,
+	backend interfaces.Backend,
+	rosettaConfig *config.Config,
+) (interfaces.Backend, error) {
+	if !rosettaConfig.Backend.RecordStream.Enabled {
+		return backend, nil
+	}
+
+	return recordstream.NewBackend(ctx, backend, rosettaConfig.Backend.RecordStream, rosettaConfig)
+}
+
+// newPostgresBackend creates the postgres backend. The account repository is wrapped with the alias lookup caches,
+// which are invalidated until the context is done
+func newPostgresBackend(
+	ctx context.Context,
+	dbClient interfaces.DbClient,
+	rosettaConfig *config.Config,
+) interfaces.Backend {
+	return &postgresBackend{
+		accountRepo: NewCachedAccountRepository(
+			ctx,
+			NewAccountRepository(dbClient, rosettaConfig.BalanceReplay),
+			dbClient,
+			rosettaConfig.Cache[config.AliasCacheKey],
+		),
+		addressBookEntryRepo: NewAddressBookEntryRepository(dbClient),
+		blockRepo:            NewBlockRepository(dbClient),
+		fileDataRepo:         NewFileDataRepository(dbClient),
+		indexRepo:            NewIndexRepository(dbClient),
+		scheduleRepo:         NewScheduleRepository(dbClient),
+		schemaVersionRepo:    NewSchemaVersionRepository(dbClient),
+		stakingRepo:          NewStakingRepository(dbClient),
+		tokenRepo:            NewTokenRepository(dbClient),
+		topicMessageRepo:     NewTopicMessageRepository(dbClient),
+		transactionRepo: NewTransactionRepository(
+			dbClient,
+			rosettaConfig.SystemAccounts,
+			rosettaConfig.SuppressEmptyOperations,
+			rosettaConfig.InvariantCheck,
+			rosettaConfig.Db.RangeSplit,
+		),
+	}
+}
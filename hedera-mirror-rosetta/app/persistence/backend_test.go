@@ -0,0 +1,99 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package persistence
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBackendUnsupportedType(t *testing.T) {
+	// when
+	backend, err := NewBackend(context.Background(), &config.Config{Backend: config.Backend{Type: "unknown"}})
+
+	// then
+	assert.Error(t, err)
+	assert.Nil(t, backend)
+}
+
+func TestNewPostgresBackend(t *testing.T) {
+	// when
+	backend := newPostgresBackend(context.Background(), invalidDbClient, &config.Config{})
+
+	// then
+	assert.IsType(t, &accountRepository{}, backend.AccountRepository())
+	assert.IsType(t, &addressBookEntryRepository{}, backend.AddressBookEntryRepository())
+	assert.IsType(t, &blockRepository{}, backend.BlockRepository())
+	assert.IsType(t, &fileDataRepository{}, backend.FileDataRepository())
+	assert.IsType(t, &indexRepository{}, backend.IndexRepository())
+	assert.IsType(t, &scheduleRepository{}, backend.ScheduleRepository())
+	assert.IsType(t, &schemaVersionRepository{}, backend.SchemaVersionRepository())
+	assert.IsType(t, &stakingRepository{}, backend.StakingRepository())
+	assert.IsType(t, &tokenRepository{}, backend.TokenRepository())
+	assert.IsType(t, &topicMessageRepository{}, backend.TopicMessageRepository())
+	assert.IsType(t, &transactionRepository{}, backend.TransactionRepository())
+}
+
+func TestNewRestBackend(t *testing.T) {
+	// when
+	backend, err := NewBackend(context.Background(), &config.Config{Backend: config.Backend{Type: BackendTypeRest}})
+
+	// then
+	assert.NoError(t, err)
+	assert.NotNil(t, backend.BlockRepository())
+	assert.Nil(t, backend.IndexRepository())
+}
+
+func TestNewBackendWithRecordStream(t *testing.T) {
+	// given
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rosettaConfig := &config.Config{Backend: config.Backend{
+		RecordStream: config.BackendRecordStream{BucketName: "bucket", CloudProvider: "S3", Enabled: true},
+		Type:         BackendTypeRest,
+	}}
+
+	// when
+	backend, err := NewBackend(ctx, rosettaConfig)
+
+	// then
+	assert.NoError(t, err)
+	assert.NotNil(t, backend.BlockRepository())
+	assert.NotNil(t, backend.TransactionRepository())
+	assert.Nil(t, backend.IndexRepository())
+}
+
+func TestNewBackendWithInvalidRecordStream(t *testing.T) {
+	// given
+	rosettaConfig := &config.Config{Backend: config.Backend{
+		RecordStream: config.BackendRecordStream{CloudProvider: "S3", Enabled: true},
+		Type:         BackendTypeRest,
+	}}
+
+	// when
+	backend, err := NewBackend(context.Background(), rosettaConfig)
+
+	// then
+	assert.Error(t, err)
+	assert.Nil(t, backend)
+}
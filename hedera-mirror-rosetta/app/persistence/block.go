@@ -24,7 +24,8 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"sync"
+	"strconv"
+	"sync/atomic"
 
 	rTypes "github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
@@ -35,8 +36,6 @@ import (
 )
 
 const (
-	genesisConsensusStartUnset = -1
-
 	// selectLatestWithIndex - Selects the latest record block
 	selectLatestWithIndex string = `select consensus_start,
                                            consensus_end,
@@ -81,6 +80,21 @@ const (
                             order by rf.consensus_end
                             limit 1`
 
+	// selectOldestWithIndex - Selects the oldest retained record block
+	selectOldestWithIndex string = `select
+                                      consensus_start,
+                                      coalesce((
+                                        select c.consensus_start - 1
+                                        from record_file c
+                                        where c.index = p.index + 1
+                                      ), consensus_end) as consensus_end,
+                                      hash,
+                                      index,
+                                      prev_hash
+                                    from record_file p
+                                    order by index
+                                    limit 1`
+
 	// selectRecordBlockByIndex - Selects the record block by its index
 	selectRecordBlockByIndex string = `select consensus_start,
                                              coalesce((
@@ -93,6 +107,38 @@ const (
                                              prev_hash
                                       from record_file
                                       where index = @index`
+
+	// selectRecordFileByIndex - Selects the record file with its raw bytes by index
+	selectRecordFileByIndex string = `select bytes, consensus_end, consensus_start, file_hash, hash, index, name,
+                                        prev_hash, version
+                                      from record_file
+                                      where index = @index`
+
+	// selectRecordBlockByTimestamp - Selects the record block containing the timestamp. A block ends right before the
+	// next block starts, so a timestamp between the consensus end of a record file and the consensus start of the next
+	// belongs to the former
+	selectRecordBlockByTimestamp string = `with next as (
+                                           select consensus_start, index
+                                           from record_file
+                                           where consensus_end >= @timestamp
+                                           order by consensus_end
+                                           limit 1
+                                         )
+                                         select p.consensus_start,
+                                                coalesce((
+                                                  select c.consensus_start - 1
+                                                  from record_file c
+                                                  where c.index = p.index + 1
+                                                ), p.consensus_end) as consensus_end,
+                                                p.hash,
+                                                p.index,
+                                                p.prev_hash
+                                         from record_file p
+                                         join next
+                                           on p.index = case
+                                             when next.consensus_start <= @timestamp then next.index
+                                             else next.index - 1
+                                           end`
 )
 
 type recordBlock struct {
@@ -127,14 +173,14 @@ func (rb *recordBlock) ToBlock(genesisBlock recordBlock) *types.Block {
 
 // blockRepository struct that has connection to the Database
 type blockRepository struct {
-	dbClient     interfaces.DbClient
-	genesisBlock recordBlock
-	once         sync.Once
+	dbClient interfaces.DbClient
+	// genesisBlock holds the genesis recordBlock once it's fetched, it's safe for concurrent access
+	genesisBlock atomic.Value
 }
 
 // NewBlockRepository creates an instance of a blockRepository struct
 func NewBlockRepository(dbClient interfaces.DbClient) interfaces.BlockRepository {
-	return &blockRepository{dbClient: dbClient, genesisBlock: recordBlock{ConsensusStart: genesisConsensusStartUnset}}
+	return &blockRepository{dbClient: dbClient}
 }
 
 func (br *blockRepository) FindByHash(ctx context.Context, hash string) (*types.Block, *rTypes.Error) {
@@ -142,11 +188,12 @@ func (br *blockRepository) FindByHash(ctx context.Context, hash string) (*types.
 		return nil, hErrors.ErrInvalidArgument
 	}
 
-	if err := br.initGenesisRecordFile(ctx); err != nil {
+	genesisBlock, err := br.initGenesisRecordFile(ctx)
+	if err != nil {
 		return nil, err
 	}
 
-	return br.findBlockByHash(ctx, hash)
+	return br.findBlockByHash(ctx, hash, genesisBlock)
 }
 
 func (br *blockRepository) FindByIdentifier(ctx context.Context, index int64, hash string) (
@@ -157,12 +204,15 @@ func (br *blockRepository) FindByIdentifier(ctx context.Context, index int64, ha
 		return nil, hErrors.ErrInvalidArgument
 	}
 
-	if err := br.initGenesisRecordFile(ctx); err != nil {
+	genesisBlock, err := br.initGenesisRecordFile(ctx)
+	if err != nil {
 		return nil, err
 	}
 
-	block, err := br.findBlockByHash(ctx, hash)
-	if err != nil {
+	block, err := br.findBlockByHash(ctx, hash, genesisBlock)
+	if err == hErrors.ErrBlockNotFound {
+		return nil, br.getBlockNotFoundError(ctx, index, genesisBlock)
+	} else if err != nil {
 		return nil, err
 	}
 
@@ -178,92 +228,215 @@ func (br *blockRepository) FindByIndex(ctx context.Context, index int64) (*types
 		return nil, hErrors.ErrInvalidArgument
 	}
 
-	if err := br.initGenesisRecordFile(ctx); err != nil {
+	genesisBlock, err := br.initGenesisRecordFile(ctx)
+	if err != nil {
 		return nil, err
 	}
 
-	return br.findBlockByIndex(ctx, index)
+	return br.findBlockByIndex(ctx, index, genesisBlock)
+}
+
+func (br *blockRepository) FindByTimestamp(ctx context.Context, timestamp int64) (*types.Block, *rTypes.Error) {
+	if timestamp < 0 {
+		return nil, hErrors.ErrInvalidArgument
+	}
+
+	genesisBlock, rErr := br.initGenesisRecordFile(ctx)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	if timestamp < genesisBlock.ConsensusStart {
+		return nil, hErrors.ErrBlockNotFound
+	}
+
+	rb := &recordBlock{}
+	if err := br.dbClient.Query(ctx, "selectRecordBlockByTimestamp", func(db *gorm.DB) error {
+		return db.Raw(selectRecordBlockByTimestamp, sql.Named("timestamp", timestamp)).First(rb).Error
+	}); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, br.getTimestampNotFoundError(ctx, timestamp)
+		}
+		return nil, handleDatabaseError(err, hErrors.ErrBlockNotFound)
+	}
+
+	return rb.ToBlock(genesisBlock), nil
 }
 
 func (br *blockRepository) RetrieveGenesis(ctx context.Context) (*types.Block, *rTypes.Error) {
-	if err := br.initGenesisRecordFile(ctx); err != nil {
+	genesisBlock, err := br.initGenesisRecordFile(ctx)
+	if err != nil {
 		return nil, err
 	}
-	return br.genesisBlock.ToBlock(br.genesisBlock), nil
+	return genesisBlock.ToBlock(genesisBlock), nil
 }
 
 func (br *blockRepository) RetrieveLatest(ctx context.Context) (*types.Block, *rTypes.Error) {
-	if err := br.initGenesisRecordFile(ctx); err != nil {
-		return nil, err
+	genesisBlock, rErr := br.initGenesisRecordFile(ctx)
+	if rErr != nil {
+		return nil, rErr
 	}
 
-	db, cancel := br.dbClient.GetDbWithContext(ctx)
-	defer cancel()
-
 	rb := &recordBlock{}
-	if err := db.Raw(selectLatestWithIndex).First(rb).Error; err != nil {
+	if err := br.dbClient.Query(ctx, "selectLatestWithIndex", func(db *gorm.DB) error {
+		return db.Raw(selectLatestWithIndex).First(rb).Error
+	}); err != nil {
 		return nil, handleDatabaseError(err, hErrors.ErrBlockNotFound)
 	}
 
-	if rb.Index < br.genesisBlock.Index {
+	if rb.Index < genesisBlock.Index {
 		return nil, hErrors.ErrBlockNotFound
 	}
 
-	return rb.ToBlock(br.genesisBlock), nil
+	return rb.ToBlock(genesisBlock), nil
 }
 
-func (br *blockRepository) findBlockByIndex(ctx context.Context, index int64) (*types.Block, *rTypes.Error) {
-	if index < br.genesisBlock.Index {
-		return nil, hErrors.ErrBlockNotFound
+func (br *blockRepository) FindRecordFileByIndex(ctx context.Context, index int64) (
+	*types.RecordFile,
+	*rTypes.Error,
+) {
+	if index < 0 {
+		return nil, hErrors.ErrInvalidArgument
 	}
 
-	db, cancel := br.dbClient.GetDbWithContext(ctx)
-	defer cancel()
+	recordFile := &types.RecordFile{}
+	if err := br.dbClient.Query(ctx, "selectRecordFileByIndex", func(db *gorm.DB) error {
+		return db.Raw(selectRecordFileByIndex, sql.Named("index", index)).First(&recordFile.RecordFile).Error
+	}); err != nil {
+		return nil, handleDatabaseError(err, hErrors.ErrBlockNotFound)
+	}
+
+	return recordFile, nil
+}
+
+func (br *blockRepository) RetrieveOldest(ctx context.Context) (*types.Block, *rTypes.Error) {
+	genesisBlock, rErr := br.initGenesisRecordFile(ctx)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	rb, rErr := br.retrieveOldestRecordFile(ctx)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	if rb.Index <= genesisBlock.Index {
+		return genesisBlock.ToBlock(genesisBlock), nil
+	}
+
+	return rb.ToBlock(genesisBlock), nil
+}
+
+func (br *blockRepository) findBlockByIndex(ctx context.Context, index int64, genesisBlock recordBlock) (
+	*types.Block,
+	*rTypes.Error,
+) {
+	if index < genesisBlock.Index {
+		return nil, hErrors.ErrBlockNotFound
+	}
 
 	rb := &recordBlock{}
-	if err := db.Raw(selectRecordBlockByIndex, sql.Named("index", index)).First(rb).Error; err != nil {
+	if err := br.dbClient.Query(ctx, "selectRecordBlockByIndex", func(db *gorm.DB) error {
+		return db.Raw(selectRecordBlockByIndex, sql.Named("index", index)).First(rb).Error
+	}); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, br.getBlockNotFoundError(ctx, index, genesisBlock)
+		}
 		return nil, handleDatabaseError(err, hErrors.ErrBlockNotFound)
 	}
 
-	return rb.ToBlock(br.genesisBlock), nil
+	return rb.ToBlock(genesisBlock), nil
 }
 
-func (br *blockRepository) findBlockByHash(ctx context.Context, hash string) (*types.Block, *rTypes.Error) {
-	db, cancel := br.dbClient.GetDbWithContext(ctx)
-	defer cancel()
-
+func (br *blockRepository) findBlockByHash(ctx context.Context, hash string, genesisBlock recordBlock) (
+	*types.Block,
+	*rTypes.Error,
+) {
 	rb := &recordBlock{}
-	if err := db.Raw(selectByHashWithIndex, sql.Named("hash", hash)).First(rb).Error; err != nil {
+	if err := br.dbClient.Query(ctx, "selectByHashWithIndex", func(db *gorm.DB) error {
+		return db.Raw(selectByHashWithIndex, sql.Named("hash", hash)).First(rb).Error
+	}); err != nil {
 		return nil, handleDatabaseError(err, hErrors.ErrBlockNotFound)
 	}
 
-	if rb.Index < br.genesisBlock.Index {
+	if rb.Index < genesisBlock.Index {
 		log.Errorf("The block with hash %s is before the genesis block", hash)
 		return nil, hErrors.ErrBlockNotFound
 	}
 
-	return rb.ToBlock(br.genesisBlock), nil
+	return rb.ToBlock(genesisBlock), nil
 }
 
-func (br *blockRepository) initGenesisRecordFile(ctx context.Context) *rTypes.Error {
-	if br.genesisBlock.ConsensusStart != genesisConsensusStartUnset {
-		return nil
+// getBlockNotFoundError returns ErrHistoryPruned with the index of the oldest retained block if the block at the index
+// is pruned by the data retention, otherwise ErrBlockNotFound
+func (br *blockRepository) getBlockNotFoundError(
+	ctx context.Context,
+	index int64,
+	genesisBlock recordBlock,
+) *rTypes.Error {
+	if index < genesisBlock.Index {
+		return hErrors.ErrBlockNotFound
 	}
 
-	db, cancel := br.dbClient.GetDbWithContext(ctx)
-	defer cancel()
+	oldest, rErr := br.retrieveOldestRecordFile(ctx)
+	if rErr != nil {
+		return rErr
+	}
 
-	var rb recordBlock
-	if err := db.Raw(selectGenesis).First(&rb).Error; err != nil {
-		return handleDatabaseError(err, hErrors.ErrNodeIsStarting)
+	if index < oldest.Index {
+		oldestIndex := strconv.FormatInt(oldest.Index, 10)
+		return hErrors.AddErrorDetails(hErrors.ErrHistoryPruned, "oldest_block_index", oldestIndex)
 	}
 
-	br.once.Do(func() {
-		br.genesisBlock = rb
-	})
+	return hErrors.ErrBlockNotFound
+}
+
+// getTimestampNotFoundError returns ErrHistoryPruned with the index of the oldest retained block if the block
+// containing the timestamp is pruned by the data retention, otherwise ErrBlockNotFound
+func (br *blockRepository) getTimestampNotFoundError(ctx context.Context, timestamp int64) *rTypes.Error {
+	oldest, rErr := br.retrieveOldestRecordFile(ctx)
+	if rErr != nil {
+		return rErr
+	}
 
-	log.Infof("Fetched genesis record file, index - %d", br.genesisBlock.Index)
-	return nil
+	if timestamp < oldest.ConsensusStart {
+		oldestIndex := strconv.FormatInt(oldest.Index, 10)
+		return hErrors.AddErrorDetails(hErrors.ErrHistoryPruned, "oldest_block_index", oldestIndex)
+	}
+
+	return hErrors.ErrBlockNotFound
+}
+
+// retrieveOldestRecordFile returns the oldest retained recordBlock
+func (br *blockRepository) retrieveOldestRecordFile(ctx context.Context) (*recordBlock, *rTypes.Error) {
+	rb := &recordBlock{}
+	if err := br.dbClient.Query(ctx, "selectOldestWithIndex", func(db *gorm.DB) error {
+		return db.Raw(selectOldestWithIndex).First(rb).Error
+	}); err != nil {
+		return nil, handleDatabaseError(err, hErrors.ErrBlockNotFound)
+	}
+
+	return rb, nil
+}
+
+// initGenesisRecordFile returns the genesis recordBlock, and fetches it if it's not cached yet. Concurrent callers may
+// fetch it at the same time, only the first one is stored so every caller sees the same genesis recordBlock
+func (br *blockRepository) initGenesisRecordFile(ctx context.Context) (recordBlock, *rTypes.Error) {
+	if genesisBlock, ok := br.genesisBlock.Load().(recordBlock); ok {
+		return genesisBlock, nil
+	}
+
+	var rb recordBlock
+	if err := br.dbClient.Query(ctx, "selectGenesis", func(db *gorm.DB) error {
+		return db.Raw(selectGenesis).First(&rb).Error
+	}); err != nil {
+		return recordBlock{}, handleDatabaseError(err, hErrors.ErrNodeIsStarting)
+	}
+
+	if br.genesisBlock.CompareAndSwap(nil, rb) {
+		log.Infof("Fetched genesis record file, index - %d", rb.Index)
+	}
+	return br.genesisBlock.Load().(recordBlock), nil
 }
 
 func handleDatabaseError(err error, recordNotFoundErr *rTypes.Error) *rTypes.Error {
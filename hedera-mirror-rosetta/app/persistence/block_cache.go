@@ -0,0 +1,225 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package persistence
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	log "github.com/sirupsen/logrus"
+	bolt "go.etcd.io/bbolt"
+)
+
+const blockCacheOpenTimeout = 5 * time.Second
+
+const blockBucketPrefix = "block_"
+
+// blockResponseShape is the configuration which shapes the /block responses. A change of any of it changes the name of
+// the bucket, so a restart with a different configuration never serves the stale responses
+type blockResponseShape struct {
+	AccountIdentifierFormat string
+	HbarCurrency            string
+	MaxOperations           int64
+	OperationTypeNaming     string
+	SuppressEmptyOperations bool
+	SystemAccounts          config.SystemAccounts
+}
+
+// diskBlockCache is a bbolt backed block cache which stores the json serialized rosetta block responses keyed by the
+// big endian block index, so the cache stays warm across restarts. The responses are stored in the bucket of the
+// current response shaping configuration, and the buckets of the other configurations are dropped on open. Once the
+// max number of entries or the max size is exceeded, the blocks with the lowest indexes are evicted
+type diskBlockCache struct {
+	bucket     []byte
+	db         *bolt.DB
+	entries    int
+	maxEntries int
+	maxSize    int64
+	// entries and size are only accessed in the read-write transactions, which bbolt serializes
+	size int64
+}
+
+func (c *diskBlockCache) Get(index int64) (*rTypes.BlockResponse, bool) {
+	var data []byte
+	err := c.db.View(func(tx *bolt.Tx) error {
+		if value := tx.Bucket(c.bucket).Get(blockCacheKey(index)); value != nil {
+			// the value is only valid during the transaction
+			data = append([]byte{}, value...)
+		}
+		return nil
+	})
+	if err != nil || data == nil {
+		return nil, false
+	}
+
+	// decode numbers in metadata as json.Number to keep the precision of large integers such as nanosecond timestamps
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	response := &rTypes.BlockResponse{}
+	if err = decoder.Decode(response); err != nil {
+		log.Errorf("Failed to unmarshal cached block %d: %s", index, err)
+		return nil, false
+	}
+
+	return response, true
+}
+
+func (c *diskBlockCache) Set(index int64, response *rTypes.BlockResponse) {
+	data, err := json.Marshal(response)
+	if err != nil {
+		log.Errorf("Failed to marshal block %d: %s", index, err)
+		return
+	}
+
+	var entries int
+	var size int64
+	err = c.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(c.bucket)
+		key := blockCacheKey(index)
+		entries, size = c.entries, c.size
+		if value := bucket.Get(key); value != nil {
+			entries--
+			size -= int64(len(value))
+		}
+
+		if err := bucket.Put(key, data); err != nil {
+			return err
+		}
+		entries++
+		size += int64(len(data))
+
+		// the keys are big endian block indexes, so the first keys are the lowest indexes
+		cursor := bucket.Cursor()
+		for key, value := cursor.First(); key != nil && c.isFull(entries, size); key, value = cursor.Next() {
+			if err := cursor.Delete(); err != nil {
+				return err
+			}
+			entries--
+			size -= int64(len(value))
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Errorf("Failed to cache block %d: %s", index, err)
+		return
+	}
+
+	c.entries, c.size = entries, size
+}
+
+func (c *diskBlockCache) Close() error {
+	return c.db.Close()
+}
+
+func (c *diskBlockCache) isFull(entries int, size int64) bool {
+	return (c.maxEntries > 0 && entries > c.maxEntries) || (c.maxSize > 0 && size > c.maxSize)
+}
+
+// NewDiskBlockCache creates a disk-backed block cache at the configured path
+func NewDiskBlockCache(rosettaConfig *config.Config) (interfaces.BlockCache, error) {
+	cacheConfig := rosettaConfig.Block.Cache
+	db, err := bolt.Open(cacheConfig.Path, 0600, &bolt.Options{Timeout: blockCacheOpenTimeout})
+	if err != nil {
+		return nil, err
+	}
+
+	cache := &diskBlockCache{
+		bucket:     getBlockBucket(rosettaConfig),
+		db:         db,
+		maxEntries: cacheConfig.MaxEntries,
+		maxSize:    cacheConfig.MaxSize,
+	}
+	if err = cache.init(); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	log.Infof("Opened disk-backed block cache at %s with %d blocks of %d bytes", cacheConfig.Path, cache.entries,
+		cache.size)
+	return cache, nil
+}
+
+// init drops the buckets of the other response shaping configurations, creates the bucket if it doesn't exist, and
+// counts its entries and size
+func (c *diskBlockCache) init() error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		var staleBuckets [][]byte
+		err := tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+			if !bytes.Equal(name, c.bucket) {
+				staleBuckets = append(staleBuckets, append([]byte{}, name...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, name := range staleBuckets {
+			if err = tx.DeleteBucket(name); err != nil {
+				return err
+			}
+			log.Infof("Dropped the stale block cache bucket %s", name)
+		}
+
+		bucket, err := tx.CreateBucketIfNotExists(c.bucket)
+		if err != nil {
+			return err
+		}
+
+		return bucket.ForEach(func(_, value []byte) error {
+			c.entries++
+			c.size += int64(len(value))
+			return nil
+		})
+	})
+}
+
+// getBlockBucket returns the bucket name with the hash of the current response shaping configuration
+func getBlockBucket(rosettaConfig *config.Config) []byte {
+	shape := blockResponseShape{
+		AccountIdentifierFormat: strings.ToUpper(rosettaConfig.AccountIdentifierFormat),
+		HbarCurrency:            types.CurrencyHbar.Symbol,
+		MaxOperations:           rosettaConfig.Block.MaxOperations,
+		OperationTypeNaming:     strings.ToUpper(rosettaConfig.OperationTypeNaming),
+		SuppressEmptyOperations: rosettaConfig.SuppressEmptyOperations,
+		SystemAccounts:          rosettaConfig.SystemAccounts,
+	}
+	// json marshals the struct fields in the declaration order, so the hash is stable
+	data, _ := json.Marshal(shape)
+	hash := sha256.Sum256(data)
+	return []byte(blockBucketPrefix + hex.EncodeToString(hash[:8]))
+}
+
+func blockCacheKey(index int64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(index))
+	return key
+}
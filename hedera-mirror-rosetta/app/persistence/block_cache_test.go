@@ -0,0 +1,219 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package persistence
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestDiskBlockCache(t *testing.T) {
+	// given
+	path := filepath.Join(t.TempDir(), "block-cache.db")
+	blockCache, err := NewDiskBlockCache(newBlockCacheConfig(path, 0, 0))
+	assert.NoError(t, err)
+	response := &rTypes.BlockResponse{
+		Block: &rTypes.Block{
+			BlockIdentifier:       &rTypes.BlockIdentifier{Index: 10, Hash: "0x0a"},
+			ParentBlockIdentifier: &rTypes.BlockIdentifier{Index: 9, Hash: "0x09"},
+			Timestamp:             100,
+			Transactions:          []*rTypes.Transaction{},
+			Metadata: map[string]interface{}{
+				"consensus_end_nanos":   int64(1645564817999999999),
+				"consensus_start_nanos": int64(1645564815001002003),
+			},
+		},
+	}
+	expected, _ := json.Marshal(response)
+
+	// when
+	blockCache.Set(10, response)
+	actual, found := blockCache.Get(10)
+	_, missing := blockCache.Get(11)
+
+	// then
+	assert.True(t, found)
+	assertJsonEqual(t, expected, actual)
+	assert.False(t, missing)
+
+	// when reopened
+	assert.NoError(t, blockCache.Close())
+	blockCache, err = NewDiskBlockCache(newBlockCacheConfig(path, 0, 0))
+	assert.NoError(t, err)
+	actual, found = blockCache.Get(10)
+
+	// then
+	assert.True(t, found)
+	assertJsonEqual(t, expected, actual)
+	assert.NoError(t, blockCache.Close())
+}
+
+func TestDiskBlockCacheHbarCurrency(t *testing.T) {
+	// given
+	t.Cleanup(func() { _ = types.SetHbarCurrency(types.HbarCurrencyHbar) })
+	path := filepath.Join(t.TempDir(), "block-cache.db")
+	blockCache, err := NewDiskBlockCache(newBlockCacheConfig(path, 0, 0))
+	assert.NoError(t, err)
+	blockCache.Set(10, &rTypes.BlockResponse{})
+	assert.NoError(t, blockCache.Close())
+
+	// when
+	assert.NoError(t, types.SetHbarCurrency(types.HbarCurrencyTinybar))
+	blockCache, err = NewDiskBlockCache(newBlockCacheConfig(path, 0, 0))
+	assert.NoError(t, err)
+	_, found := blockCache.Get(10)
+
+	// then
+	assert.False(t, found)
+	assert.NoError(t, blockCache.Close())
+}
+
+func TestDiskBlockCacheResponseShapingConfigChanged(t *testing.T) {
+	tests := []struct {
+		name   string
+		update func(rosettaConfig *config.Config)
+	}{
+		{name: "account identifier format", update: func(rosettaConfig *config.Config) {
+			rosettaConfig.AccountIdentifierFormat = types.AccountIdentifierFormatStructured
+		}},
+		{name: "max operations", update: func(rosettaConfig *config.Config) {
+			rosettaConfig.Block.MaxOperations = 100
+		}},
+		{name: "operation type naming", update: func(rosettaConfig *config.Config) {
+			rosettaConfig.OperationTypeNaming = types.OperationTypeNamingRosetta
+		}},
+		{name: "suppress empty operations", update: func(rosettaConfig *config.Config) {
+			rosettaConfig.SuppressEmptyOperations = true
+		}},
+		{name: "system accounts", update: func(rosettaConfig *config.Config) {
+			rosettaConfig.SystemAccounts.Treasury = "0.0.2"
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			path := filepath.Join(t.TempDir(), "block-cache.db")
+			rosettaConfig := newBlockCacheConfig(path, 0, 0)
+			blockCache, err := NewDiskBlockCache(rosettaConfig)
+			require.NoError(t, err)
+			blockCache.Set(10, &rTypes.BlockResponse{})
+			require.NoError(t, blockCache.Close())
+
+			// when
+			tt.update(rosettaConfig)
+			blockCache, err = NewDiskBlockCache(rosettaConfig)
+			require.NoError(t, err)
+			_, found := blockCache.Get(10)
+
+			// then
+			assert.False(t, found)
+			assert.Equal(t, 1, countBlockCacheBuckets(t, blockCache))
+			assert.NoError(t, blockCache.Close())
+		})
+	}
+}
+
+func TestDiskBlockCacheMaxEntries(t *testing.T) {
+	// given
+	path := filepath.Join(t.TempDir(), "block-cache.db")
+	blockCache, err := NewDiskBlockCache(newBlockCacheConfig(path, 2, 0))
+	require.NoError(t, err)
+	blockCache.Set(11, &rTypes.BlockResponse{})
+	blockCache.Set(10, &rTypes.BlockResponse{})
+	blockCache.Set(11, &rTypes.BlockResponse{})
+	require.NoError(t, blockCache.Close())
+
+	// when reopened
+	blockCache, err = NewDiskBlockCache(newBlockCacheConfig(path, 2, 0))
+	require.NoError(t, err)
+	blockCache.Set(12, &rTypes.BlockResponse{})
+
+	// then
+	assertCachedBlocks(t, blockCache, map[int64]bool{10: false, 11: true, 12: true})
+	assert.NoError(t, blockCache.Close())
+}
+
+func TestDiskBlockCacheMaxSize(t *testing.T) {
+	// given
+	response := &rTypes.BlockResponse{}
+	data, _ := json.Marshal(response)
+	path := filepath.Join(t.TempDir(), "block-cache.db")
+	blockCache, err := NewDiskBlockCache(newBlockCacheConfig(path, 0, int64(len(data)*3)))
+	require.NoError(t, err)
+
+	// when
+	for index := int64(10); index < 15; index++ {
+		blockCache.Set(index, response)
+	}
+
+	// then
+	assertCachedBlocks(t, blockCache, map[int64]bool{10: false, 11: false, 12: true, 13: true, 14: true})
+	assert.NoError(t, blockCache.Close())
+}
+
+func assertCachedBlocks(t *testing.T, blockCache interfaces.BlockCache, expected map[int64]bool) {
+	for index, expectedFound := range expected {
+		_, found := blockCache.Get(index)
+		assert.Equal(t, expectedFound, found, "block %d", index)
+	}
+}
+
+func assertJsonEqual(t *testing.T, expected []byte, actual *rTypes.BlockResponse) {
+	data, err := json.Marshal(actual)
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(data))
+}
+
+func TestNewDiskBlockCacheInvalidPath(t *testing.T) {
+	blockCache, err := NewDiskBlockCache(newBlockCacheConfig(t.TempDir(), 0, 0))
+	assert.Error(t, err)
+	assert.Nil(t, blockCache)
+}
+
+func countBlockCacheBuckets(t *testing.T, blockCache interfaces.BlockCache) int {
+	count := 0
+	err := blockCache.(*diskBlockCache).db.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(_ []byte, _ *bolt.Bucket) error {
+			count++
+			return nil
+		})
+	})
+	require.NoError(t, err)
+	return count
+}
+
+func newBlockCacheConfig(path string, maxEntries int, maxSize int64) *config.Config {
+	return &config.Config{
+		Block: config.Block{
+			Cache: config.BlockCache{Enabled: true, MaxEntries: maxEntries, MaxSize: maxSize, Path: path},
+		},
+	}
+}
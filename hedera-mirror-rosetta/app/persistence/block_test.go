@@ -21,16 +21,23 @@
 package persistence
 
 import (
+	"context"
 	"fmt"
 	"math"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
 
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/db"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
 )
 
 const genesisBlockIndex int64 = 3
@@ -454,6 +461,179 @@ func (suite *blockRepositorySuite) TestFindByIndexDbConnectionError() {
 	assert.Nil(suite.T(), actual)
 }
 
+func (suite *blockRepositorySuite) TestFindRecordFileByIndex() {
+	// given
+	recordFile := &domain.RecordFile{
+		Bytes:          []byte{0x00, 0x00, 0x00, 0x06},
+		ConsensusStart: 146,
+		ConsensusEnd:   150,
+		FileHash:       "fourth_record_file_file_hash",
+		Hash:           "fourth_record_file_hash",
+		Index:          genesisBlockIndex + 3,
+		Name:           "fourth_record_file",
+		NodeAccountID:  nodeEntityId,
+		PrevHash:       "third_record_file_hash",
+		Version:        6,
+	}
+	db.CreateDbRecords(dbClient, recordFile)
+	expected := &types.RecordFile{}
+	expected.Bytes = recordFile.Bytes
+	expected.ConsensusStart = recordFile.ConsensusStart
+	expected.ConsensusEnd = recordFile.ConsensusEnd
+	expected.FileHash = recordFile.FileHash
+	expected.Hash = recordFile.Hash
+	expected.Index = recordFile.Index
+	expected.Name = recordFile.Name
+	expected.PrevHash = recordFile.PrevHash
+	expected.Version = recordFile.Version
+	repo := NewBlockRepository(dbClient)
+
+	// when
+	actual, err := repo.FindRecordFileByIndex(defaultContext, recordFile.Index)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+}
+
+func (suite *blockRepositorySuite) TestFindRecordFileByIndexWithoutBytes() {
+	// given
+	repo := NewBlockRepository(dbClient)
+
+	// when
+	actual, err := repo.FindRecordFileByIndex(defaultContext, genesisBlockIndex)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Empty(suite.T(), actual.Bytes)
+	assert.Equal(suite.T(), genesisRecordFile.Hash, actual.Hash)
+	assert.Equal(suite.T(), genesisRecordFile.Name, actual.Name)
+}
+
+func (suite *blockRepositorySuite) TestFindRecordFileByIndexInvalidIndex() {
+	// given
+	repo := NewBlockRepository(dbClient)
+
+	// when
+	actual, err := repo.FindRecordFileByIndex(defaultContext, -1)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrInvalidArgument, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *blockRepositorySuite) TestFindRecordFileByIndexNotFound() {
+	// given
+	repo := NewBlockRepository(dbClient)
+
+	// when
+	actual, err := repo.FindRecordFileByIndex(defaultContext, 1000)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrBlockNotFound, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *blockRepositorySuite) TestFindRecordFileByIndexDbConnectionError() {
+	// given
+	repo := NewBlockRepository(invalidDbClient)
+
+	// when
+	actual, err := repo.FindRecordFileByIndex(defaultContext, 0)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *blockRepositorySuite) TestFindByTimestamp() {
+	tests := []struct {
+		name      string
+		timestamp int64
+		expected  *types.Block
+	}{
+		{name: "genesis start", timestamp: expectedGenesisBlock.ConsensusStartNanos, expected: expectedGenesisBlock},
+		{name: "genesis record file end", timestamp: genesisRecordFile.ConsensusEnd, expected: expectedGenesisBlock},
+		{name: "between record files", timestamp: genesisRecordFile.ConsensusEnd + 1, expected: expectedGenesisBlock},
+		{name: "second block start", timestamp: expectedSecondBlock.ConsensusStartNanos, expected: expectedSecondBlock},
+		{name: "second block end", timestamp: expectedSecondBlock.ConsensusEndNanos, expected: expectedSecondBlock},
+		{name: "third block end", timestamp: expectedThirdBlock.ConsensusEndNanos, expected: expectedThirdBlock},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			// given
+			repo := NewBlockRepository(dbClient)
+
+			// when
+			actual, err := repo.FindByTimestamp(defaultContext, tt.timestamp)
+
+			// then
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func (suite *blockRepositorySuite) TestFindByTimestampNotFound() {
+	tests := []struct {
+		name      string
+		timestamp int64
+		expected  *rTypes.Error
+	}{
+		{name: "negative", timestamp: -1, expected: errors.ErrInvalidArgument},
+		{
+			name:      "before genesis block",
+			timestamp: expectedGenesisBlock.ConsensusStartNanos - 1,
+			expected:  errors.ErrBlockNotFound,
+		},
+		{name: "after latest", timestamp: expectedThirdBlock.ConsensusEndNanos + 1, expected: errors.ErrBlockNotFound},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			// given
+			repo := NewBlockRepository(dbClient)
+
+			// when
+			actual, err := repo.FindByTimestamp(defaultContext, tt.timestamp)
+
+			// then
+			assert.Equal(t, tt.expected, err)
+			assert.Nil(t, actual)
+		})
+	}
+}
+
+func (suite *blockRepositorySuite) TestFindByTimestampHistoryPruned() {
+	// given
+	repo := NewBlockRepository(dbClient)
+	_, err := repo.RetrieveGenesis(defaultContext)
+	assert.Nil(suite.T(), err)
+	db.ExecSql(dbClient, fmt.Sprintf("delete from record_file where index < %d", expectedThirdBlock.Index))
+	expected := errors.AddErrorDetails(errors.ErrHistoryPruned, "oldest_block_index",
+		strconv.FormatInt(expectedThirdBlock.Index, 10))
+
+	// when
+	actual, err := repo.FindByTimestamp(defaultContext, expectedSecondBlock.ConsensusStartNanos)
+
+	// then
+	assert.Equal(suite.T(), expected, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *blockRepositorySuite) TestFindByTimestampDbConnectionError() {
+	// given
+	repo := NewBlockRepository(invalidDbClient)
+
+	// when
+	actual, err := repo.FindByTimestamp(defaultContext, expectedSecondBlock.ConsensusStartNanos)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
 func (suite *blockRepositorySuite) TestRetrieveGenesis() {
 	// given
 	repo := NewBlockRepository(dbClient)
@@ -649,6 +829,92 @@ func (suite *blockRepositorySuite) TestRetrieveLatestDbConnectionError() {
 	assert.Nil(suite.T(), actual)
 }
 
+func (suite *blockRepositorySuite) TestRetrieveOldest() {
+	// given
+	repo := NewBlockRepository(dbClient)
+
+	// when
+	actual, err := repo.RetrieveOldest(defaultContext)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expectedGenesisBlock, actual)
+}
+
+func (suite *blockRepositorySuite) TestRetrieveOldestWithPrunedHistory() {
+	// given
+	repo := NewBlockRepository(dbClient)
+	_, err := repo.RetrieveGenesis(defaultContext)
+	assert.Nil(suite.T(), err)
+	db.ExecSql(dbClient, fmt.Sprintf("delete from record_file where index < %d", expectedThirdBlock.Index))
+
+	// when
+	actual, err := repo.RetrieveOldest(defaultContext)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expectedThirdBlock, actual)
+}
+
+func (suite *blockRepositorySuite) TestRetrieveOldestNoRecordFile() {
+	// given
+	db.ExecSql(dbClient, truncateRecordFileSql)
+	repo := NewBlockRepository(dbClient)
+
+	// when
+	actual, err := repo.RetrieveOldest(defaultContext)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrNodeIsStarting, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *blockRepositorySuite) TestRetrieveOldestDbConnectionError() {
+	// given
+	repo := NewBlockRepository(invalidDbClient)
+
+	// when
+	actual, err := repo.RetrieveOldest(defaultContext)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *blockRepositorySuite) TestFindByIndexHistoryPruned() {
+	// given
+	repo := NewBlockRepository(dbClient)
+	_, err := repo.RetrieveGenesis(defaultContext)
+	assert.Nil(suite.T(), err)
+	db.ExecSql(dbClient, fmt.Sprintf("delete from record_file where index < %d", expectedThirdBlock.Index))
+	expected := errors.AddErrorDetails(errors.ErrHistoryPruned, "oldest_block_index",
+		strconv.FormatInt(expectedThirdBlock.Index, 10))
+
+	// when
+	actual, err := repo.FindByIndex(defaultContext, expectedSecondBlock.Index)
+
+	// then
+	assert.Equal(suite.T(), expected, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *blockRepositorySuite) TestFindByIdentifierHistoryPruned() {
+	// given
+	repo := NewBlockRepository(dbClient)
+	_, err := repo.RetrieveGenesis(defaultContext)
+	assert.Nil(suite.T(), err)
+	db.ExecSql(dbClient, fmt.Sprintf("delete from record_file where index < %d", expectedThirdBlock.Index))
+	expected := errors.AddErrorDetails(errors.ErrHistoryPruned, "oldest_block_index",
+		strconv.FormatInt(expectedThirdBlock.Index, 10))
+
+	// when
+	actual, err := repo.FindByIdentifier(defaultContext, expectedSecondBlock.Index, expectedSecondBlock.Hash)
+
+	// then
+	assert.Equal(suite.T(), expected, err)
+	assert.Nil(suite.T(), actual)
+}
+
 func TestRecordFileToBlock(t *testing.T) {
 	genesisBlock := recordBlock{
 		ConsensusStart: 110,
@@ -706,3 +972,42 @@ func TestRecordFileToBlock(t *testing.T) {
 		})
 	}
 }
+
+func TestRetrieveGenesisConcurrently(t *testing.T) {
+	// given
+	client := &countingDbClient{}
+	repo := NewBlockRepository(client)
+	results := make([]*types.Block, 32)
+	var wg sync.WaitGroup
+
+	// when
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = repo.RetrieveGenesis(defaultContext)
+		}(i)
+	}
+	wg.Wait()
+	queries := atomic.LoadInt32(&client.queries)
+	actual, err := repo.RetrieveGenesis(defaultContext)
+
+	// then
+	assert.Nil(t, err)
+	for _, result := range results {
+		assert.NotNil(t, result)
+		assert.Equal(t, actual, result)
+	}
+	assert.Equal(t, queries, atomic.LoadInt32(&client.queries), "the cached genesis block should be reused")
+}
+
+// countingDbClient counts the queries and returns success without touching the result
+type countingDbClient struct {
+	interfaces.DbClient
+	queries int32
+}
+
+func (c *countingDbClient) Query(_ context.Context, _ string, _ func(db *gorm.DB) error) error {
+	atomic.AddInt32(&c.queries, 1)
+	return nil
+}
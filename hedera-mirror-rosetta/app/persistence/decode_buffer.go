@@ -0,0 +1,101 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package persistence
+
+import (
+	"sync"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/builder"
+)
+
+// maxPooledTransfersCapacity is the largest transfer slice kept when a decode buffer is returned to the pool, so a
+// rare transaction with a huge transfer list doesn't pin its memory
+const maxPooledTransfersCapacity = 4096
+
+// decodeBuffer holds the decoded transactions with the same hash while the rosetta transaction is constructed. The
+// transfer slices of the decoded transactions are kept across uses, so a block with thousands of transactions doesn't
+// allocate them again for every transaction
+type decodeBuffer struct {
+	transactions []builder.Transaction
+}
+
+var decodeBufferPool = sync.Pool{
+	New: func() interface{} {
+		return &decodeBuffer{}
+	},
+}
+
+func acquireDecodeBuffer() *decodeBuffer {
+	return decodeBufferPool.Get().(*decodeBuffer)
+}
+
+// next returns the next decoded transaction slot, reusing the transfer slices of a previous use if there is one
+func (b *decodeBuffer) next() *builder.Transaction {
+	if len(b.transactions) < cap(b.transactions) {
+		b.transactions = b.transactions[:len(b.transactions)+1]
+	} else {
+		b.transactions = append(b.transactions, builder.Transaction{})
+	}
+
+	return &b.transactions[len(b.transactions)-1]
+}
+
+// release drops everything but the transfer slices of the decoded transactions, so the pool doesn't keep the record
+// bytes and the other data of a finished request alive, and returns the buffer to the pool
+func (b *decodeBuffer) release() {
+	transactions := b.transactions[:cap(b.transactions)]
+	for i := range transactions {
+		transactions[i] = builder.Transaction{
+			CryptoTransfers: truncateForPool(transactions[i].CryptoTransfers),
+			NftTransfers:    truncateForPool(transactions[i].NftTransfers),
+			NonFeeTransfers: truncateForPool(transactions[i].NonFeeTransfers),
+			TokenTransfers:  truncateForPool(transactions[i].TokenTransfers),
+		}
+	}
+
+	b.transactions = transactions[:0]
+	decodeBufferPool.Put(b)
+}
+
+// resetSlice zeroes the elements of the slice up to its capacity and returns it truncated. The elements have to be
+// zeroed since json.Unmarshal decodes into the existing elements and leaves the absent fields untouched. A nil slice
+// is returned as an empty non-nil slice
+func resetSlice[T any](s []T) []T {
+	if s == nil {
+		return make([]T, 0)
+	}
+
+	var zero T
+	s = s[:cap(s)]
+	for i := range s {
+		s[i] = zero
+	}
+
+	return s[:0]
+}
+
+// truncateForPool returns the slice truncated to zero length, or nil if it's too large to keep in the pool
+func truncateForPool[T any](s []T) []T {
+	if cap(s) > maxPooledTransfersCapacity {
+		return nil
+	}
+
+	return s[:0]
+}
@@ -0,0 +1,120 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package persistence
+
+import (
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/builder"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeBufferNext(t *testing.T) {
+	// given
+	buffer := &decodeBuffer{}
+
+	// when
+	first := buffer.next()
+	first.Result = 22
+	second := buffer.next()
+
+	// then
+	assert.Len(t, buffer.transactions, 2)
+	assert.Equal(t, int32(22), buffer.transactions[0].Result)
+	assert.Same(t, second, &buffer.transactions[1])
+}
+
+func TestDecodeBufferReuseDoesNotLeakStaleFields(t *testing.T) {
+	// given
+	buffer := &decodeBuffer{}
+	previous := transaction{
+		CryptoTransfers: `[{"account_id": 98, "amount": 10, "is_approval": true}]`,
+		NftTransfers: `[{"receiver_account_id": 1001, "sender_account_id": 1002, "serial_number": 1,
+			"token_id": 2001}]`,
+		NonFeeTransfers:        "[]",
+		TokenTransfers:         "[]",
+		Token:                  "{}",
+		Schedule:               "{}",
+		TransactionRecordBytes: []byte{0x1},
+	}
+	current := transaction{
+		CryptoTransfers: `[{"account_id": 99, "amount": 20}]`,
+		NftTransfers:    `[{"receiver_account_id": 1003, "serial_number": 2, "token_id": 2001}]`,
+		NonFeeTransfers: "[]",
+		TokenTransfers:  "[]",
+		Token:           "{}",
+		Schedule:        "{}",
+	}
+	assert.NoError(t, previous.decodeInto(buffer.next()))
+	senderAccountId := buffer.transactions[0].NftTransfers[0].SenderAccountId
+	buffer.transactions = buffer.transactions[:0]
+
+	// when
+	err := current.decodeInto(buffer.next())
+
+	// then
+	assert.NoError(t, err)
+	actual := buffer.transactions[0]
+	assert.Equal(t, []builder.HbarTransfer{{AccountId: domain.MustDecodeEntityId(99), Amount: 20}},
+		actual.CryptoTransfers)
+	assert.Len(t, actual.NftTransfers, 1)
+	assert.Nil(t, actual.NftTransfers[0].SenderAccountId)
+	assert.Equal(t, domain.MustDecodeEntityId(1003), *actual.NftTransfers[0].ReceiverAccountId)
+	assert.Equal(t, domain.MustDecodeEntityId(1002), *senderAccountId)
+	assert.Nil(t, actual.RecordBytes)
+}
+
+func TestDecodeBufferRelease(t *testing.T) {
+	// given
+	buffer := &decodeBuffer{}
+	decoded := buffer.next()
+	decoded.CryptoTransfers = make([]builder.HbarTransfer, 2, 8)
+	decoded.NftTransfers = make([]domain.NftTransfer, 1, maxPooledTransfersCapacity+1)
+	decoded.RecordBytes = []byte{0x1}
+	decoded.Result = 22
+
+	// when
+	buffer.release()
+
+	// then
+	assert.Empty(t, buffer.transactions)
+	reused := buffer.transactions[:1][0]
+	assert.Equal(t, 8, cap(reused.CryptoTransfers))
+	assert.Empty(t, reused.CryptoTransfers)
+	assert.Nil(t, reused.NftTransfers)
+	assert.Nil(t, reused.RecordBytes)
+	assert.Zero(t, reused.Result)
+}
+
+func TestResetSlice(t *testing.T) {
+	// given
+	entityId := domain.MustDecodeEntityId(1001)
+	transfers := []domain.NftTransfer{{ReceiverAccountId: &entityId}, {SerialNumber: 1}}[:1]
+
+	// when
+	actual := resetSlice(transfers)
+
+	// then
+	assert.NotNil(t, actual)
+	assert.Empty(t, actual)
+	assert.Equal(t, []domain.NftTransfer{{}, {}}, actual[:2])
+	assert.Equal(t, []domain.NftTransfer{}, resetSlice([]domain.NftTransfer(nil)))
+}
@@ -24,6 +24,7 @@ import "github.com/jackc/pgtype"
 
 const (
 	EntityTypeAccount  = "ACCOUNT"
+	EntityTypeContract = "CONTRACT"
 	EntityTypeFile     = "FILE"
 	EntityTypeSchedule = "SCHEDULE"
 	EntityTypeToken    = "TOKEN"
@@ -37,8 +38,11 @@ type Entity struct {
 	Alias                         []byte
 	AutoRenewAccountId            *EntityId
 	AutoRenewPeriod               *int64
+	Balance                       *int64
 	CreatedTimestamp              *int64
+	DeclineReward                 bool
 	Deleted                       *bool
+	EvmAddress                    []byte
 	ExpirationTimestamp           *int64
 	Id                            EntityId
 	Key                           []byte
@@ -50,6 +54,9 @@ type Entity struct {
 	Realm                         int64
 	ReceiverSigRequired           *bool
 	Shard                         int64
+	StakedAccountId               *int64
+	StakedNodeId                  *int64
+	StakePeriodStart              *int64
 	SubmitKey                     []byte
 	TimestampRange                pgtype.Int8range
 	Type                          string
@@ -64,7 +64,7 @@ func (e *EntityId) Scan(value interface{}) error {
 	}
 
 	var err error
-	if *e, err = DecodeEntityId(encodedId); err != nil {
+	if *e, err = DecodeEntityIdCached(encodedId); err != nil {
 		return err
 	}
 
@@ -72,7 +72,7 @@ func (e *EntityId) Scan(value interface{}) error {
 }
 
 func (e *EntityId) String() string {
-	return fmt.Sprintf("%d.%d.%d", e.ShardNum, e.RealmNum, e.EntityNum)
+	return entityIdString(*e)
 }
 
 func (e *EntityId) UnmarshalJSON(data []byte) error {
@@ -89,7 +89,7 @@ func (e *EntityId) UnmarshalJSON(data []byte) error {
 			return err
 		}
 
-		entityId, err = DecodeEntityId(encodedId)
+		entityId, err = DecodeEntityIdCached(encodedId)
 	}
 
 	if err != nil {
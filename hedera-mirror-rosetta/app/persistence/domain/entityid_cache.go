@@ -0,0 +1,94 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package domain
+
+import (
+	"strconv"
+	"sync/atomic"
+)
+
+// entityIdCacheSize is the number of slots in the entity id cache, it must be a power of two
+const entityIdCacheSize = 4096
+
+// entityIdCacheEntry is a decoded entity id and its shard.realm.num string form
+type entityIdCacheEntry struct {
+	entityId EntityId
+	str      string
+}
+
+// entityIdCache is a fixed-size, direct-mapped cache of entity ids keyed by the encoded id. A block with thousands of
+// crypto transfers usually touches only a handful of accounts, so the same encoded ids are decoded and formatted over
+// and over. A colliding entity id simply replaces the entry in its slot, so the memory is bounded regardless of how
+// many distinct entities are seen
+var entityIdCache [entityIdCacheSize]atomic.Value
+
+// DecodeEntityIdCached decodes the encoded id like DecodeEntityId, returning the memoized result if present
+func DecodeEntityIdCached(encodedId int64) (EntityId, error) {
+	if entry := loadEntityIdCacheEntry(encodedId); entry != nil {
+		return entry.entityId, nil
+	}
+
+	entityId, err := DecodeEntityId(encodedId)
+	if err != nil {
+		return EntityId{}, err
+	}
+
+	storeEntityIdCacheEntry(entityId)
+	return entityId, nil
+}
+
+// entityIdString returns the shard.realm.num string form of the entity id, memoized by the encoded id
+func entityIdString(e EntityId) string {
+	if entry := loadEntityIdCacheEntry(e.EncodedId); entry != nil && entry.entityId == e {
+		return entry.str
+	}
+
+	// only cache an entity id whose fields agree with its encoded id, so DecodeEntityIdCached never returns a
+	// hand-built entity id with mismatching fields
+	if decoded, err := DecodeEntityId(e.EncodedId); err != nil || decoded != e {
+		return formatEntityId(e)
+	}
+
+	return storeEntityIdCacheEntry(e).str
+}
+
+func formatEntityId(e EntityId) string {
+	return strconv.FormatInt(e.ShardNum, 10) + "." + strconv.FormatInt(e.RealmNum, 10) + "." +
+		strconv.FormatInt(e.EntityNum, 10)
+}
+
+func entityIdCacheSlot(encodedId int64) *atomic.Value {
+	return &entityIdCache[uint64(encodedId)&(entityIdCacheSize-1)]
+}
+
+func loadEntityIdCacheEntry(encodedId int64) *entityIdCacheEntry {
+	entry, _ := entityIdCacheSlot(encodedId).Load().(*entityIdCacheEntry)
+	if entry == nil || entry.entityId.EncodedId != encodedId {
+		return nil
+	}
+
+	return entry
+}
+
+func storeEntityIdCacheEntry(e EntityId) *entityIdCacheEntry {
+	entry := &entityIdCacheEntry{entityId: e, str: formatEntityId(e)}
+	entityIdCacheSlot(e.EncodedId).Store(entry)
+	return entry
+}
@@ -0,0 +1,119 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package domain
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeEntityIdCached(t *testing.T) {
+	var tests = []struct {
+		encodedId int64
+		expected  EntityId
+	}{
+		{0, EntityId{}},
+		{98, EntityId{EntityNum: 98, EncodedId: 98}},
+		{98 + entityIdCacheSize, EntityId{EntityNum: 98 + entityIdCacheSize, EncodedId: 98 + entityIdCacheSize}},
+		{2814792716779530, EntityId{ShardNum: 10, RealmNum: 10, EntityNum: 10, EncodedId: 2814792716779530}},
+	}
+
+	for _, tt := range tests {
+		// when
+		first, err1 := DecodeEntityIdCached(tt.encodedId)
+		second, err2 := DecodeEntityIdCached(tt.encodedId)
+
+		// then
+		assert.NoError(t, err1)
+		assert.NoError(t, err2)
+		assert.Equal(t, tt.expected, first)
+		assert.Equal(t, tt.expected, second)
+		assert.Equal(t, formatEntityId(tt.expected), second.String())
+	}
+}
+
+func TestDecodeEntityIdCachedThrows(t *testing.T) {
+	// when
+	actual, err := DecodeEntityIdCached(-1)
+
+	// then
+	assert.Error(t, err)
+	assert.Equal(t, EntityId{}, actual)
+}
+
+func TestEntityIdStringSlotCollision(t *testing.T) {
+	// given
+	entityId := MustDecodeEntityId(1001)
+	colliding := MustDecodeEntityId(1001 + entityIdCacheSize)
+
+	// when, then
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, "0.0.1001", entityId.String())
+		assert.Equal(t, "0.0.5097", colliding.String())
+	}
+}
+
+func TestEntityIdStringMismatchedEncodedId(t *testing.T) {
+	// given
+	entityId := EntityId{EntityNum: 1002, EncodedId: 1003}
+
+	// when
+	actual := entityId.String()
+	decoded, err := DecodeEntityIdCached(1003)
+
+	// then
+	assert.Equal(t, "0.0.1002", actual)
+	assert.NoError(t, err)
+	assert.Equal(t, EntityId{EntityNum: 1003, EncodedId: 1003}, decoded)
+}
+
+func TestEntityIdStringConcurrent(t *testing.T) {
+	// given
+	wg := &sync.WaitGroup{}
+
+	// when, then
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(offset int64) {
+			defer wg.Done()
+			for encodedId := offset; encodedId < 2*entityIdCacheSize; encodedId += 8 {
+				entityId := MustDecodeEntityId(encodedId)
+				assert.Equal(t, formatEntityId(entityId), entityId.String())
+			}
+		}(int64(i))
+	}
+	wg.Wait()
+}
+
+func BenchmarkEntityIdString(b *testing.B) {
+	entityIds := make([]EntityId, 0, 16)
+	for i := int64(0); i < 16; i++ {
+		entityIds = append(entityIds, MustDecodeEntityId(1000+i))
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		entityId := entityIds[i%len(entityIds)]
+		_ = entityId.String()
+	}
+}
@@ -0,0 +1,43 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package domain
+
+const tableNameNetworkStake = "network_stake"
+
+type NetworkStake struct {
+	ConsensusTimestamp          int64 `gorm:"primaryKey"`
+	EpochDay                    int64
+	MaxStakingRewardRatePerHbar int64
+	NodeRewardFeeDenominator    int64
+	NodeRewardFeeNumerator      int64
+	StakeTotal                  int64
+	StakingPeriod               int64
+	StakingPeriodDuration       int64
+	StakingPeriodsStored        int64
+	StakingRewardFeeDenominator int64
+	StakingRewardFeeNumerator   int64
+	StakingRewardRate           int64
+	StakingStartThreshold       int64
+}
+
+// TableName returns network stake table name
+func (NetworkStake) TableName() string {
+	return tableNameNetworkStake
+}
@@ -25,10 +25,12 @@ const tableNameNft = "nft"
 type Nft struct {
 	AccountId         *EntityId
 	CreatedTimestamp  *int64
+	DelegatingSpender *EntityId
 	Deleted           *bool
 	ModifiedTimestamp int64
 	Metadata          []byte
-	SerialNumber      int64    `gorm:"primaryKey"`
+	SerialNumber      int64 `gorm:"primaryKey"`
+	Spender           *EntityId
 	TokenId           EntityId `gorm:"primaryKey"`
 }
 
@@ -24,6 +24,7 @@ const tableNameNftTransfer = "nft_transfer"
 
 type NftTransfer struct {
 	ConsensusTimestamp int64 `json:"consensus_timestamp"`
+	IsApproval         bool  `json:"is_approval"`
 	PayerAccountId     EntityId
 	ReceiverAccountId  *EntityId `json:"receiver_account_id"`
 	SenderAccountId    *EntityId `json:"sender_account_id"`
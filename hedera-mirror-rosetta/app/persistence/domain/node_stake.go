@@ -0,0 +1,40 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package domain
+
+const tableNameNodeStake = "node_stake"
+
+type NodeStake struct {
+	ConsensusTimestamp int64 `gorm:"primaryKey"`
+	EpochDay           int64
+	MaxStake           int64
+	MinStake           int64
+	NodeId             int64 `gorm:"primaryKey"`
+	RewardRate         int64
+	Stake              int64
+	StakeNotRewarded   int64
+	StakeRewarded      int64
+	StakingPeriod      int64
+}
+
+// TableName returns node stake table name
+func (NodeStake) TableName() string {
+	return tableNameNodeStake
+}
@@ -23,6 +23,8 @@ package domain
 const tableNameRecordFile = "record_file"
 
 type RecordFile struct {
+	// Bytes is the raw bytes of the record file, only stored if the importer is configured to persist them
+	Bytes            []byte
 	ConsensusStart   int64
 	ConsensusEnd     int64 `gorm:"primaryKey"`
 	Count            int64
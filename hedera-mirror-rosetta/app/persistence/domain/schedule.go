@@ -0,0 +1,39 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package domain
+
+const tableNameSchedule = "schedule"
+
+type Schedule struct {
+	ConsensusTimestamp int64    `json:"consensus_timestamp"`
+	CreatorAccountId   EntityId `json:"creator_account_id"`
+	ExecutedTimestamp  *int64   `json:"executed_timestamp"`
+	ExpirationTime     *int64   `json:"expiration_time"`
+	PayerAccountId     EntityId `json:"payer_account_id"`
+	ScheduleId         EntityId `gorm:"primaryKey" json:"schedule_id"`
+	TransactionBody    []byte   `json:"transaction_body"`
+	WaitForExpiry      bool     `json:"wait_for_expiry"`
+}
+
+// TableName returns schedule table name
+func (Schedule) TableName() string {
+	return tableNameSchedule
+}
@@ -28,6 +28,11 @@ const (
 	TransactionTypeTokenUpdate         int16 = 36
 	TransactionTypeTokenMint           int16 = 37
 	TransactionTypeTokenDissociate     int16 = 41
+	TransactionTypeScheduleCreate      int16 = 42
+	TransactionTypeTokenReject         int16 = 57
+	TransactionTypeTokenAirdrop        int16 = 58
+	TransactionTypeTokenCancelAirdrop  int16 = 59
+	TransactionTypeTokenClaimAirdrop   int16 = 60
 
 	transactionTableName = "transaction"
 )
@@ -48,6 +53,7 @@ type Transaction struct {
 	Scheduled                bool
 	TransactionBytes         []byte
 	TransactionHash          []byte
+	TransactionRecordBytes   []byte `gorm:"->"`
 	Type                     int16
 	ValidDurationSeconds     int64
 	ValidStartNs             int64
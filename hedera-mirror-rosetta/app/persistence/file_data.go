@@ -0,0 +1,93 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// selectLatestFileContent selects the concatenated data of the last file create / update transaction with
+	// non-empty content and the file append transactions after it
+	selectLatestFileContent = `with latest as (
+                               select max(consensus_timestamp) as timestamp
+                               from file_data
+                               where entity_id = @file_id and transaction_type in (17, 19) and length(file_data) > 0
+                             )
+                             select string_agg(file_data, ''::bytea order by consensus_timestamp) as file_data
+                             from file_data
+                             join latest on consensus_timestamp >= latest.timestamp
+                             where entity_id = @file_id and (consensus_timestamp = latest.timestamp or
+                               transaction_type = 16)`
+	// selectLatestFileTimestamp selects the consensus timestamp of the last transaction of the file, 0 if there's none
+	selectLatestFileTimestamp = `select coalesce(max(consensus_timestamp), 0) as timestamp
+                               from file_data
+                               where entity_id = @file_id`
+)
+
+type fileContent struct {
+	FileData []byte
+}
+
+type fileTimestamp struct {
+	Timestamp int64
+}
+
+// fileDataRepository struct that has connection to the Database
+type fileDataRepository struct {
+	dbClient interfaces.DbClient
+}
+
+func (fr *fileDataRepository) GetLatestContent(ctx context.Context, fileId int64) ([]byte, *rTypes.Error) {
+	var content fileContent
+	if err := fr.dbClient.Query(ctx, "selectLatestFileContent", func(db *gorm.DB) error {
+		return db.Raw(selectLatestFileContent, sql.Named("file_id", fileId)).Scan(&content).Error
+	}); err != nil {
+		log.Errorf(databaseErrorFormat, errors.ErrDatabaseError.Message, err)
+		return nil, errors.ErrDatabaseError
+	}
+
+	return content.FileData, nil
+}
+
+func (fr *fileDataRepository) GetLatestTimestamp(ctx context.Context, fileId int64) (int64, *rTypes.Error) {
+	var latest fileTimestamp
+	if err := fr.dbClient.Query(ctx, "selectLatestFileTimestamp", func(db *gorm.DB) error {
+		return db.Raw(selectLatestFileTimestamp, sql.Named("file_id", fileId)).Scan(&latest).Error
+	}); err != nil {
+		log.Errorf(databaseErrorFormat, errors.ErrDatabaseError.Message, err)
+		return 0, errors.ErrDatabaseError
+	}
+
+	return latest.Timestamp, nil
+}
+
+// NewFileDataRepository creates an instance of a fileDataRepository struct
+func NewFileDataRepository(dbClient interfaces.DbClient) interfaces.FileDataRepository {
+	return &fileDataRepository{dbClient}
+}
@@ -0,0 +1,147 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package persistence
+
+import (
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	fileId123                 int64 = 123
+	transactionTypeFileAppend int16 = 16
+	transactionTypeFileCreate int16 = 17
+	transactionTypeFileUpdate int16 = 19
+)
+
+// run the suite
+func TestFileDataRepositorySuite(t *testing.T) {
+	suite.Run(t, new(fileDataRepositorySuite))
+}
+
+type fileDataRepositorySuite struct {
+	integrationTest
+	suite.Suite
+}
+
+func (suite *fileDataRepositorySuite) TestGetLatestContent() {
+	// given
+	db.CreateDbRecords(
+		dbClient,
+		getFileData(10, fileId123, []byte{0x1}, transactionTypeFileCreate),
+		getFileData(11, fileId123, []byte{0x2}, transactionTypeFileAppend),
+		getFileData(20, fileId123, []byte{0x3}, transactionTypeFileUpdate),
+		getFileData(21, fileId101, []byte{0x4}, transactionTypeFileAppend),
+		getFileData(22, fileId123, []byte{0x5}, transactionTypeFileAppend),
+		getFileData(23, fileId123, []byte{}, transactionTypeFileUpdate),
+		getFileData(24, fileId123, []byte{0x6}, transactionTypeFileAppend),
+	)
+	repo := NewFileDataRepository(dbClient)
+
+	// when
+	actual, err := repo.GetLatestContent(defaultContext, fileId123)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), []byte{0x3, 0x5, 0x6}, actual)
+}
+
+func (suite *fileDataRepositorySuite) TestGetLatestContentNotFound() {
+	// given
+	db.CreateDbRecords(dbClient, getFileData(10, fileId101, []byte{0x1}, transactionTypeFileCreate))
+	repo := NewFileDataRepository(dbClient)
+
+	// when
+	actual, err := repo.GetLatestContent(defaultContext, fileId123)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Empty(suite.T(), actual)
+}
+
+func (suite *fileDataRepositorySuite) TestGetLatestContentDbConnectionError() {
+	// given
+	repo := NewFileDataRepository(invalidDbClient)
+
+	// when
+	actual, err := repo.GetLatestContent(defaultContext, fileId123)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *fileDataRepositorySuite) TestGetLatestTimestamp() {
+	// given
+	db.CreateDbRecords(
+		dbClient,
+		getFileData(10, fileId123, []byte{0x1}, transactionTypeFileCreate),
+		getFileData(11, fileId123, []byte{0x2}, transactionTypeFileAppend),
+		getFileData(12, fileId101, []byte{0x3}, transactionTypeFileAppend),
+	)
+	repo := NewFileDataRepository(dbClient)
+
+	// when
+	actual, err := repo.GetLatestTimestamp(defaultContext, fileId123)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), int64(11), actual)
+}
+
+func (suite *fileDataRepositorySuite) TestGetLatestTimestampNotFound() {
+	// given
+	db.CreateDbRecords(dbClient, getFileData(10, fileId101, []byte{0x1}, transactionTypeFileCreate))
+	repo := NewFileDataRepository(dbClient)
+
+	// when
+	actual, err := repo.GetLatestTimestamp(defaultContext, fileId123)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Zero(suite.T(), actual)
+}
+
+func (suite *fileDataRepositorySuite) TestGetLatestTimestampDbConnectionError() {
+	// given
+	repo := NewFileDataRepository(invalidDbClient)
+
+	// when
+	actual, err := repo.GetLatestTimestamp(defaultContext, fileId123)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Zero(suite.T(), actual)
+}
+
+func getFileData(consensusTimestamp, fileId int64, data []byte, transactionType int16) *domain.FileData {
+	return &domain.FileData{
+		ConsensusTimestamp: consensusTimestamp,
+		EntityId:           domain.MustDecodeEntityId(fileId),
+		FileData:           data,
+		TransactionType:    transactionType,
+	}
+}
@@ -0,0 +1,90 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package persistence
+
+import (
+	"context"
+	"strings"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	hErrors "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// selectIndexesByTables selects the valid indexes of the tables visible in the search_path with the comma separated
+// columns of the index key in order. An expression in the key has an empty column name. The index of a partitioned
+// table is the partitioned index on the parent table. An index left invalid by a failed concurrent build is skipped
+const selectIndexesByTables = `select
+                                 i.relname as name,
+                                 x.indpred is not null as partial,
+                                 t.relname as table_name,
+                                 string_agg(coalesce(a.attname, ''), ',' order by k.position) as columns
+                               from pg_index x
+                               join pg_class i on i.oid = x.indexrelid
+                               join pg_class t on t.oid = x.indrelid
+                               cross join lateral unnest(x.indkey::int2[]) with ordinality as k(attnum, position)
+                               left join pg_attribute a on a.attrelid = t.oid and a.attnum = k.attnum
+                               where t.relname in (?) and pg_table_is_visible(t.oid) and x.indisvalid
+                               group by i.relname, x.indpred is not null, t.relname
+                               order by t.relname, i.relname`
+
+// index maps to the index query
+type index struct {
+	Columns   string
+	Name      string
+	Partial   bool
+	TableName string
+}
+
+// indexRepository struct that has connection to the Database
+type indexRepository struct {
+	dbClient interfaces.DbClient
+}
+
+// FindByTables returns the indexes of the tables visible in the search_path
+func (ir *indexRepository) FindByTables(ctx context.Context, tables []string) ([]types.Index, *rTypes.Error) {
+	rows := make([]index, 0)
+	if err := ir.dbClient.Query(ctx, "selectIndexesByTables", func(db *gorm.DB) error {
+		return db.Raw(selectIndexesByTables, tables).Scan(&rows).Error
+	}); err != nil {
+		log.Errorf(databaseErrorFormat, hErrors.ErrDatabaseError.Message, err)
+		return nil, hErrors.ErrDatabaseError
+	}
+
+	indexes := make([]types.Index, 0, len(rows))
+	for _, row := range rows {
+		indexes = append(indexes, types.Index{
+			Columns: strings.Split(row.Columns, ","),
+			Name:    row.Name,
+			Partial: row.Partial,
+			Table:   row.TableName,
+		})
+	}
+
+	return indexes, nil
+}
+
+// NewIndexRepository creates an instance of a indexRepository struct
+func NewIndexRepository(dbClient interfaces.DbClient) interfaces.IndexRepository {
+	return &indexRepository{dbClient}
+}
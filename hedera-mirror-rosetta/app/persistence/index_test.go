@@ -0,0 +1,88 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package persistence
+
+import (
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// run the suite
+func TestIndexRepositorySuite(t *testing.T) {
+	suite.Run(t, new(indexRepositorySuite))
+}
+
+type indexRepositorySuite struct {
+	integrationTest
+	suite.Suite
+}
+
+func (suite *indexRepositorySuite) TestFindByTables() {
+	// given
+	repo := NewIndexRepository(dbClient)
+
+	// when
+	actual, err := repo.FindByTables(defaultContext, []string{"crypto_transfer", "transaction"})
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Contains(suite.T(), actual, types.Index{
+		Columns: []string{"consensus_timestamp"},
+		Name:    "crypto_transfer__consensus_timestamp",
+		Table:   "crypto_transfer",
+	})
+	assert.Contains(suite.T(), actual, types.Index{
+		Columns: []string{"entity_id", "consensus_timestamp"},
+		Name:    "crypto_transfer__entity_id_consensus_timestamp",
+		Partial: true,
+		Table:   "crypto_transfer",
+	})
+	for _, index := range actual {
+		assert.Contains(suite.T(), []string{"crypto_transfer", "transaction"}, index.Table)
+	}
+}
+
+func (suite *indexRepositorySuite) TestFindByTablesNoMatch() {
+	// given
+	repo := NewIndexRepository(dbClient)
+
+	// when
+	actual, err := repo.FindByTables(defaultContext, []string{"nonexistent_table"})
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Empty(suite.T(), actual)
+}
+
+func (suite *indexRepositorySuite) TestFindByTablesDbConnectionError() {
+	// given
+	repo := NewIndexRepository(invalidDbClient)
+
+	// when
+	actual, err := repo.FindByTables(defaultContext, []string{"crypto_transfer"})
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
@@ -0,0 +1,71 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	hErrors "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const selectTableExists = "select to_regclass(@table) is not null"
+
+// optionalTable detects whether a table only created by the importer migrations of a feature the network may not have
+// enabled yet exists, e.g., token_airdrop of HIP-904. Only the existence is cached, so the table is picked up once the
+// importer migrates the schema without a restart
+type optionalTable struct {
+	exists bool
+	mutex  sync.Mutex
+	name   string
+}
+
+func (o *optionalTable) isPresent(ctx context.Context, dbClient interfaces.DbClient) (bool, *rTypes.Error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if o.exists {
+		return true, nil
+	}
+
+	var exists bool
+	if err := dbClient.Query(ctx, "selectTableExists", func(db *gorm.DB) error {
+		return db.Raw(selectTableExists, sql.Named("table", o.name)).Scan(&exists).Error
+	}); err != nil {
+		log.Errorf(databaseErrorFormat, hErrors.ErrDatabaseError.Message, err)
+		return false, hErrors.ErrDatabaseError
+	}
+
+	if exists {
+		log.Infof("Detected optional table %s", o.name)
+	}
+	o.exists = exists
+	return exists, nil
+}
+
+func newOptionalTable(name string) *optionalTable {
+	return &optionalTable{name: name}
+}
@@ -0,0 +1,85 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/stdlib"
+	"gorm.io/gorm"
+)
+
+// namedToPositional rewrites the named parameters of the queries shared with gorm to the positional parameters of pgx
+var namedToPositional = strings.NewReplacer("@start", "$1", "@end", "$2", "@hash", "$3")
+
+// queryRows runs the query on the pgx connection underlying the gorm.DB and streams the rows to the scan function. It
+// avoids the reflection and the intermediate allocations of gorm, so it's used for the queries on the hot path. The
+// query is traced to the logger of the gorm.DB like the gorm queries
+func queryRows(db *gorm.DB, query string, args []interface{}, scan func(rows pgx.Rows) error) (err error) {
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	begin := time.Now()
+	rowCount := int64(0)
+	defer func() {
+		db.Logger.Trace(ctx, begin, func() (string, int64) {
+			return db.Dialector.Explain(query, args...), rowCount
+		}, err)
+	}()
+
+	sqlDb, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	conn, err := sqlDb.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn interface{}) error {
+		stdlibConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("unsupported driver connection %T", driverConn)
+		}
+
+		rows, err := stdlibConn.Conn().Query(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			if err = scan(rows); err != nil {
+				return err
+			}
+			rowCount++
+		}
+
+		return rows.Err()
+	})
+}
@@ -0,0 +1,78 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package recordstream
+
+import (
+	"context"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+)
+
+// backend wraps a backend to serve the blocks read from the record stream ahead of it
+type backend struct {
+	interfaces.Backend
+	blockRepo       interfaces.BlockRepository
+	transactionRepo interfaces.TransactionRepository
+}
+
+func (b *backend) BlockRepository() interfaces.BlockRepository {
+	return b.blockRepo
+}
+
+func (b *backend) TransactionRepository() interfaces.TransactionRepository {
+	return b.transactionRepo
+}
+
+// NewBackend wraps the backend with the experimental fallback reading the most recent record files directly from the
+// bucket while the backend lags behind. The bucket is polled until the context is done
+func NewBackend(
+	ctx context.Context,
+	wrapped interfaces.Backend,
+	recordStreamConfig config.BackendRecordStream,
+	rosettaConfig *config.Config,
+) (interfaces.Backend, error) {
+	client, err := newBucketClient(recordStreamConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := newTransactionDecoder(
+		wrapped.TokenRepository(),
+		rosettaConfig.SystemAccounts,
+		rosettaConfig.SuppressEmptyOperations,
+	)
+	stream := newRecordStream(
+		wrapped.AddressBookEntryRepository(),
+		wrapped.BlockRepository(),
+		client,
+		decoder,
+		recordStreamConfig.NodeAccountId,
+		recordStreamConfig.MaxBlocks,
+		recordStreamConfig.PollInterval,
+	)
+	go stream.run(ctx)
+
+	return &backend{
+		Backend:         wrapped,
+		blockRepo:       &blockRepository{BlockRepository: wrapped.BlockRepository(), stream: stream},
+		transactionRepo: &transactionRepository{TransactionRepository: wrapped.TransactionRepository(), stream: stream},
+	}, nil
+}
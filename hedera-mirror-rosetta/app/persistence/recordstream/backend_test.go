@@ -0,0 +1,100 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package recordstream
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	hErrors "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// testBackend is a backend with only the repositories the record stream fallback uses
+type testBackend struct {
+	interfaces.Backend
+	addressBookEntryRepo *mocks.MockAddressBookEntryRepository
+	blockRepo            *mocks.MockBlockRepository
+	tokenRepo            *mocks.MockTokenRepository
+	transactionRepo      *mocks.MockTransactionRepository
+}
+
+func (b *testBackend) AddressBookEntryRepository() interfaces.AddressBookEntryRepository {
+	return b.addressBookEntryRepo
+}
+
+func (b *testBackend) BlockRepository() interfaces.BlockRepository {
+	return b.blockRepo
+}
+
+func (b *testBackend) TokenRepository() interfaces.TokenRepository {
+	return b.tokenRepo
+}
+
+func (b *testBackend) TransactionRepository() interfaces.TransactionRepository {
+	return b.transactionRepo
+}
+
+func TestNewBackend(t *testing.T) {
+	// given
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	wrapped := &testBackend{
+		addressBookEntryRepo: &mocks.MockAddressBookEntryRepository{},
+		blockRepo:            &mocks.MockBlockRepository{},
+		tokenRepo:            &mocks.MockTokenRepository{},
+		transactionRepo:      &mocks.MockTransactionRepository{},
+	}
+	// the stream may still poll once since the timer fires right away
+	wrapped.blockRepo.On("RetrieveLatest").Return(mocks.NilBlock, hErrors.ErrBlockNotFound)
+	recordStreamConfig := config.BackendRecordStream{BucketName: "bucket", CloudProvider: "S3"}
+
+	// when
+	backend, err := NewBackend(ctx, wrapped, recordStreamConfig, &config.Config{})
+
+	// then
+	assert.NoError(t, err)
+	assert.IsType(t, &blockRepository{}, backend.BlockRepository())
+	assert.Same(t, wrapped.blockRepo, backend.BlockRepository().(*blockRepository).BlockRepository)
+	assert.IsType(t, &transactionRepository{}, backend.TransactionRepository())
+	assert.Same(t, wrapped.transactionRepo,
+		backend.TransactionRepository().(*transactionRepository).TransactionRepository)
+	assert.Same(t, wrapped.tokenRepo, backend.TokenRepository())
+}
+
+func TestNewBackendInvalidConfig(t *testing.T) {
+	// given
+	wrapped := &testBackend{
+		addressBookEntryRepo: &mocks.MockAddressBookEntryRepository{},
+		blockRepo:            &mocks.MockBlockRepository{},
+		tokenRepo:            &mocks.MockTokenRepository{},
+		transactionRepo:      &mocks.MockTransactionRepository{},
+	}
+
+	// when
+	backend, err := NewBackend(context.Background(), wrapped, config.BackendRecordStream{}, &config.Config{})
+
+	// then
+	assert.Error(t, err)
+	assert.Nil(t, backend)
+}
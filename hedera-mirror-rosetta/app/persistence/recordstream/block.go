@@ -0,0 +1,106 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package recordstream
+
+import (
+	"context"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	hErrors "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	log "github.com/sirupsen/logrus"
+)
+
+// blockRepository serves the latest block and the blocks the backend doesn't have yet from the record stream, and
+// delegates the rest to the block repository of the backend
+type blockRepository struct {
+	interfaces.BlockRepository
+	stream *recordStream
+}
+
+func (br *blockRepository) FindByHash(ctx context.Context, hash string) (*types.Block, *rTypes.Error) {
+	block, rErr := br.BlockRepository.FindByHash(ctx, hash)
+	if rErr == hErrors.ErrBlockNotFound {
+		if streamBlock := br.stream.findByHash(hash); streamBlock != nil {
+			return streamBlock, nil
+		}
+	}
+
+	return block, rErr
+}
+
+func (br *blockRepository) FindByIdentifier(ctx context.Context, index int64, hash string) (
+	*types.Block,
+	*rTypes.Error,
+) {
+	block, rErr := br.BlockRepository.FindByIdentifier(ctx, index, hash)
+	if rErr == hErrors.ErrBlockNotFound {
+		if streamBlock := br.stream.findByHash(hash); streamBlock != nil && streamBlock.Index == index {
+			return streamBlock, nil
+		}
+	}
+
+	return block, rErr
+}
+
+func (br *blockRepository) FindByIndex(ctx context.Context, index int64) (*types.Block, *rTypes.Error) {
+	block, rErr := br.BlockRepository.FindByIndex(ctx, index)
+	if rErr == hErrors.ErrBlockNotFound {
+		if streamBlock := br.stream.findByIndex(index); streamBlock != nil {
+			return streamBlock, nil
+		}
+	}
+
+	return block, rErr
+}
+
+// FindRecordFileByIndex gets the raw bytes of the record file from the bucket if the backend doesn't have them
+func (br *blockRepository) FindRecordFileByIndex(ctx context.Context, index int64) (
+	*types.RecordFile,
+	*rTypes.Error,
+) {
+	recordFile, rErr := br.BlockRepository.FindRecordFileByIndex(ctx, index)
+	if rErr != nil || len(recordFile.Bytes) != 0 || recordFile.Name == "" {
+		return recordFile, rErr
+	}
+
+	data, err := br.stream.client.get(ctx, br.stream.prefix+recordFile.Name)
+	if err != nil {
+		log.Warnf("Failed to get record file %s of block %d from the bucket: %s", recordFile.Name, index, err)
+		return recordFile, nil
+	}
+
+	recordFile.Bytes = data
+	return recordFile, nil
+}
+
+func (br *blockRepository) RetrieveLatest(ctx context.Context) (*types.Block, *rTypes.Error) {
+	block, rErr := br.BlockRepository.RetrieveLatest(ctx)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	if streamBlock := br.stream.latest(block); streamBlock != nil {
+		return streamBlock, nil
+	}
+
+	return block, nil
+}
@@ -0,0 +1,220 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package recordstream
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	hErrors "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestBlockRepository(t *testing.T) (*blockRepository, *mocks.MockBlockRepository) {
+	backendBlockRepo := &mocks.MockBlockRepository{}
+	return &blockRepository{BlockRepository: backendBlockRepo, stream: newTestPolledRecordStream(t)}, backendBlockRepo
+}
+
+func TestBlockRepositoryFindByHash(t *testing.T) {
+	// given
+	repo, backendBlockRepo := newTestBlockRepository(t)
+	backendBlockRepo.On("FindByHash").Return(mocks.NilBlock, hErrors.ErrBlockNotFound)
+
+	// when
+	block, rErr := repo.FindByHash(context.Background(), "0x"+block6Hash)
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Equal(t, int64(6), block.Index)
+}
+
+func TestBlockRepositoryFindByHashFromBackend(t *testing.T) {
+	// given
+	repo, backendBlockRepo := newTestBlockRepository(t)
+	backendBlockRepo.On("FindByHash").Return(block4, mocks.NilError)
+
+	// when
+	block, rErr := repo.FindByHash(context.Background(), block4.Hash)
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Equal(t, block4, block)
+}
+
+func TestBlockRepositoryFindByHashNotFound(t *testing.T) {
+	// given
+	repo, backendBlockRepo := newTestBlockRepository(t)
+	backendBlockRepo.On("FindByHash").Return(mocks.NilBlock, hErrors.ErrBlockNotFound)
+
+	// when
+	block, rErr := repo.FindByHash(context.Background(), "0x0123")
+
+	// then
+	assert.Equal(t, hErrors.ErrBlockNotFound, rErr)
+	assert.Nil(t, block)
+}
+
+func TestBlockRepositoryFindByIdentifier(t *testing.T) {
+	// given
+	repo, backendBlockRepo := newTestBlockRepository(t)
+	backendBlockRepo.On("FindByIdentifier").Return(mocks.NilBlock, hErrors.ErrBlockNotFound)
+
+	// when
+	block, rErr := repo.FindByIdentifier(context.Background(), 5, block5Hash)
+	mismatched, mismatchedErr := repo.FindByIdentifier(context.Background(), 6, block5Hash)
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Equal(t, block5Hash, block.Hash)
+	assert.Equal(t, hErrors.ErrBlockNotFound, mismatchedErr)
+	assert.Nil(t, mismatched)
+}
+
+func TestBlockRepositoryFindByIndex(t *testing.T) {
+	// given
+	repo, backendBlockRepo := newTestBlockRepository(t)
+	backendBlockRepo.On("FindByIndex").Return(mocks.NilBlock, hErrors.ErrBlockNotFound)
+
+	// when
+	block, rErr := repo.FindByIndex(context.Background(), 5)
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Equal(t, block5Hash, block.Hash)
+	assert.Equal(t, block6Start-1, block.ConsensusEndNanos)
+}
+
+func TestBlockRepositoryFindByIndexDatabaseError(t *testing.T) {
+	// given
+	repo, backendBlockRepo := newTestBlockRepository(t)
+	backendBlockRepo.On("FindByIndex").Return(mocks.NilBlock, hErrors.ErrDatabaseError)
+
+	// when
+	block, rErr := repo.FindByIndex(context.Background(), 5)
+
+	// then
+	assert.Equal(t, hErrors.ErrDatabaseError, rErr)
+	assert.Nil(t, block)
+}
+
+func TestBlockRepositoryRetrieveLatest(t *testing.T) {
+	// given
+	repo, backendBlockRepo := newTestBlockRepository(t)
+	backendBlockRepo.On("RetrieveLatest").Return(block4, mocks.NilError)
+
+	// when
+	block, rErr := repo.RetrieveLatest(context.Background())
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Equal(t, int64(6), block.Index)
+	assert.Equal(t, block6Hash, block.Hash)
+}
+
+func TestBlockRepositoryRetrieveLatestBackendCaughtUp(t *testing.T) {
+	// given
+	repo, backendBlockRepo := newTestBlockRepository(t)
+	block6 := repo.stream.findByIndex(6)
+	backendBlockRepo.On("RetrieveLatest").Return(block6, mocks.NilError)
+
+	// when
+	block, rErr := repo.RetrieveLatest(context.Background())
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Same(t, block6, block)
+}
+
+func TestBlockRepositoryRetrieveLatestError(t *testing.T) {
+	// given
+	repo, backendBlockRepo := newTestBlockRepository(t)
+	backendBlockRepo.On("RetrieveLatest").Return(mocks.NilBlock, hErrors.ErrDatabaseError)
+
+	// when
+	block, rErr := repo.RetrieveLatest(context.Background())
+
+	// then
+	assert.Equal(t, hErrors.ErrDatabaseError, rErr)
+	assert.Nil(t, block)
+}
+
+func TestBlockRepositoryFindRecordFileByIndex(t *testing.T) {
+	// given
+	repo, backendBlockRepo := newTestBlockRepository(t)
+	recordFile := &types.RecordFile{}
+	recordFile.Index = 5
+	recordFile.Name = block5Name
+	backendBlockRepo.On("FindRecordFileByIndex", int64(5)).Return(recordFile, mocks.NilError)
+
+	// when
+	actual, rErr := repo.FindRecordFileByIndex(context.Background(), 5)
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Equal(t, readTestRecordFile(t, block5Name), actual.Bytes)
+}
+
+func TestBlockRepositoryFindRecordFileByIndexFromBackend(t *testing.T) {
+	// given
+	repo, backendBlockRepo := newTestBlockRepository(t)
+	recordFile := &types.RecordFile{}
+	recordFile.Bytes = []byte{1, 2, 3}
+	recordFile.Index = 5
+	recordFile.Name = block5Name
+	backendBlockRepo.On("FindRecordFileByIndex", int64(5)).Return(recordFile, mocks.NilError)
+
+	// when
+	actual, rErr := repo.FindRecordFileByIndex(context.Background(), 5)
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Equal(t, []byte{1, 2, 3}, actual.Bytes)
+}
+
+func TestBlockRepositoryFindRecordFileByIndexNotInBucket(t *testing.T) {
+	// given
+	repo, backendBlockRepo := newTestBlockRepository(t)
+	recordFile := &types.RecordFile{}
+	recordFile.Index = 3
+	recordFile.Name = "2022-07-13T08_46_00.000000000Z.rcd.gz"
+	backendBlockRepo.On("FindRecordFileByIndex", int64(3)).Return(recordFile, mocks.NilError)
+
+	// when
+	actual, rErr := repo.FindRecordFileByIndex(context.Background(), 3)
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Empty(t, actual.Bytes)
+}
+
+func TestBlockRepositoryFindRecordFileByIndexError(t *testing.T) {
+	// given
+	repo, backendBlockRepo := newTestBlockRepository(t)
+	backendBlockRepo.On("FindRecordFileByIndex", int64(7)).Return(mocks.NilRecordFile, hErrors.ErrBlockNotFound)
+
+	// when
+	actual, rErr := repo.FindRecordFileByIndex(context.Background(), 7)
+
+	// then
+	assert.Equal(t, hErrors.ErrBlockNotFound, rErr)
+	assert.Nil(t, actual)
+}
@@ -0,0 +1,189 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package recordstream
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/pkg/errors"
+)
+
+const (
+	cloudProviderGcp = "gcp"
+	cloudProviderS3  = "s3"
+
+	defaultTimeout = 10 * time.Second
+	gcpEndpoint    = "https://storage.googleapis.com"
+	// gcpRegion is the region GCS expects in the signature of the requests authenticated with HMAC credentials
+	gcpRegion = "auto"
+	// maxObjectSize bounds the size of an object read into memory
+	maxObjectSize = 64 * 1024 * 1024
+)
+
+// listBucketResult is the response of the ListObjectsV2 request
+type listBucketResult struct {
+	Contents []struct {
+		Key string
+	}
+}
+
+// bucketClient lists and gets the objects of the bucket with the S3 REST API, which GCS also serves for
+// interoperability. The requests are signed if the HMAC credentials are configured, and the requester pays for them
+type bucketClient struct {
+	accessKey    string
+	bucketName   string
+	endpoint     string
+	gcpProjectId string
+	httpClient   *http.Client
+	now          func() time.Time
+	region       string
+	s3           bool
+	secretKey    string
+}
+
+func newBucketClient(recordStreamConfig config.BackendRecordStream) (*bucketClient, error) {
+	if recordStreamConfig.BucketName == "" {
+		return nil, errors.New("Missing record stream bucket name")
+	}
+
+	client := &bucketClient{
+		accessKey:    recordStreamConfig.AccessKey,
+		bucketName:   recordStreamConfig.BucketName,
+		endpoint:     strings.TrimSuffix(recordStreamConfig.EndpointOverride, "/"),
+		gcpProjectId: recordStreamConfig.GcpProjectId,
+		now:          time.Now,
+		region:       recordStreamConfig.Region,
+		secretKey:    recordStreamConfig.SecretKey,
+	}
+
+	switch strings.ToLower(recordStreamConfig.CloudProvider) {
+	case cloudProviderGcp:
+		client.region = gcpRegion
+		if client.endpoint == "" {
+			client.endpoint = gcpEndpoint
+		}
+	case cloudProviderS3:
+		client.s3 = true
+		if client.endpoint == "" {
+			client.endpoint = "https://s3." + client.region + ".amazonaws.com"
+		}
+	default:
+		return nil, errors.Errorf("Unsupported cloud provider %s", recordStreamConfig.CloudProvider)
+	}
+
+	timeout := recordStreamConfig.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	client.httpClient = &http.Client{Timeout: timeout}
+
+	return client, nil
+}
+
+// get gets the content of the object with the key
+func (c *bucketClient) get(ctx context.Context, key string) ([]byte, error) {
+	return c.send(ctx, "/"+c.bucketName+"/"+key, url.Values{})
+}
+
+// list lists the keys of the objects right under the prefix after startAfter in lexicographical order, at most
+// maxKeys. The objects in the sub directories of the prefix, e.g., the sidecar files, are not listed
+func (c *bucketClient) list(ctx context.Context, prefix, startAfter string, maxKeys int) ([]string, error) {
+	query := url.Values{
+		"delimiter":   {"/"},
+		"list-type":   {"2"},
+		"max-keys":    {strconv.Itoa(maxKeys)},
+		"prefix":      {prefix},
+		"start-after": {startAfter},
+	}
+	body, err := c.send(ctx, "/"+c.bucketName, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var result listBucketResult
+	if err = xml.Unmarshal(body, &result); err != nil {
+		return nil, errors.Wrap(err, "Failed to decode the object list of the bucket")
+	}
+
+	keys := make([]string, 0, len(result.Contents))
+	for _, content := range result.Contents {
+		keys = append(keys, content.Key)
+	}
+
+	return keys, nil
+}
+
+// send sends the GET request of the path style url of the bucket or the object, and returns the response body
+func (c *bucketClient) send(ctx context.Context, path string, query url.Values) ([]byte, error) {
+	if !c.s3 && c.gcpProjectId != "" {
+		query.Set("userProject", c.gcpProjectId)
+	}
+
+	requestUrl, err := url.Parse(c.endpoint)
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid bucket endpoint")
+	}
+	requestUrl.Path = requestUrl.Path + path
+	requestUrl.RawPath = uriEncode(requestUrl.Path, false)
+	requestUrl.RawQuery = canonicalQuery(query)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestUrl.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.s3 {
+		request.Header.Set("X-Amz-Request-Payer", "requester")
+	}
+
+	if c.accessKey != "" {
+		signV4(request, c.accessKey, c.secretKey, c.region, c.now())
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxObjectSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("Failed to get %s from the bucket with status %d", path, response.StatusCode)
+	}
+
+	if len(body) > maxObjectSize {
+		return nil, errors.Errorf("Object %s is too large", path)
+	}
+
+	return body, nil
+}
@@ -0,0 +1,235 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package recordstream
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testBucketName = "bucket"
+
+// bucketStub serves the ListObjectsV2 and GetObject requests of the S3 REST API from the objects keyed by the key
+type bucketStub struct {
+	mutex    sync.Mutex
+	objects  map[string][]byte
+	requests []*http.Request
+}
+
+func (s *bucketStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.requests = append(s.requests, r)
+
+	bucketPath := "/" + testBucketName
+	if r.URL.Path == bucketPath {
+		s.serveList(w, r)
+		return
+	}
+
+	object, ok := s.objects[strings.TrimPrefix(r.URL.Path, bucketPath+"/")]
+	if !ok || !strings.HasPrefix(r.URL.Path, bucketPath+"/") {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	_, _ = w.Write(object)
+}
+
+func (s *bucketStub) serveList(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	maxKeys, _ := strconv.Atoi(query.Get("max-keys"))
+	keys := make([]string, 0)
+	for key := range s.objects {
+		if strings.HasPrefix(key, query.Get("prefix")) && key > query.Get("start-after") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	if len(keys) > maxKeys {
+		keys = keys[:maxKeys]
+	}
+
+	var result listBucketResult
+	for _, key := range keys {
+		result.Contents = append(result.Contents, struct{ Key string }{Key: key})
+	}
+	body, _ := xml.Marshal(result)
+	_, _ = w.Write(body)
+}
+
+func (s *bucketStub) getRequests() []*http.Request {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]*http.Request{}, s.requests...)
+}
+
+func newTestBucketClient(t *testing.T, stub *bucketStub, recordStreamConfig config.BackendRecordStream) *bucketClient {
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+	recordStreamConfig.BucketName = testBucketName
+	recordStreamConfig.EndpointOverride = server.URL
+	client, err := newBucketClient(recordStreamConfig)
+	require.NoError(t, err)
+	return client
+}
+
+func TestNewBucketClient(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   config.BackendRecordStream
+		endpoint string
+		region   string
+		s3       bool
+	}{
+		{
+			name:     "s3",
+			config:   config.BackendRecordStream{BucketName: "b", CloudProvider: "S3", Region: "us-east-1"},
+			endpoint: "https://s3.us-east-1.amazonaws.com",
+			region:   "us-east-1",
+			s3:       true,
+		},
+		{
+			name:     "gcp",
+			config:   config.BackendRecordStream{BucketName: "b", CloudProvider: "GCP", Region: "us-east-1"},
+			endpoint: gcpEndpoint,
+			region:   gcpRegion,
+		},
+		{
+			name: "endpoint override",
+			config: config.BackendRecordStream{
+				BucketName:       "b",
+				CloudProvider:    "s3",
+				EndpointOverride: "http://localhost:9000/",
+				Region:           "us-east-1",
+			},
+			endpoint: "http://localhost:9000",
+			region:   "us-east-1",
+			s3:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			client, err := newBucketClient(tt.config)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tt.endpoint, client.endpoint)
+			assert.Equal(t, tt.region, client.region)
+			assert.Equal(t, tt.s3, client.s3)
+			assert.Equal(t, defaultTimeout, client.httpClient.Timeout)
+		})
+	}
+}
+
+func TestNewBucketClientInvalid(t *testing.T) {
+	for _, recordStreamConfig := range []config.BackendRecordStream{
+		{CloudProvider: "S3"},
+		{BucketName: "b", CloudProvider: "azure"},
+	} {
+		client, err := newBucketClient(recordStreamConfig)
+		assert.Error(t, err)
+		assert.Nil(t, client)
+	}
+}
+
+func TestBucketClientGet(t *testing.T) {
+	// given
+	stub := &bucketStub{objects: map[string][]byte{"a/b.rcd": {1, 2, 3}}}
+	client := newTestBucketClient(t, stub, config.BackendRecordStream{CloudProvider: "S3"})
+
+	// when
+	data, err := client.get(context.Background(), "a/b.rcd")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3}, data)
+	requests := stub.getRequests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "requester", requests[0].Header.Get("X-Amz-Request-Payer"))
+	assert.Empty(t, requests[0].Header.Get("Authorization"))
+}
+
+func TestBucketClientGetNotFound(t *testing.T) {
+	// given
+	client := newTestBucketClient(t, &bucketStub{}, config.BackendRecordStream{CloudProvider: "S3"})
+
+	// when
+	data, err := client.get(context.Background(), "a/b.rcd")
+
+	// then
+	assert.Error(t, err)
+	assert.Nil(t, data)
+}
+
+func TestBucketClientGetSigned(t *testing.T) {
+	// given
+	stub := &bucketStub{objects: map[string][]byte{"a/b.rcd": {1}}}
+	client := newTestBucketClient(t, stub, config.BackendRecordStream{
+		AccessKey:     exampleAccessKey,
+		CloudProvider: "GCP",
+		GcpProjectId:  "project",
+		SecretKey:     exampleSecretKey,
+	})
+
+	// when
+	_, err := client.get(context.Background(), "a/b.rcd")
+
+	// then
+	assert.NoError(t, err)
+	requests := stub.getRequests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "project", requests[0].URL.Query().Get("userProject"))
+	assert.Empty(t, requests[0].Header.Get("X-Amz-Request-Payer"))
+	assert.Contains(t, requests[0].Header.Get("Authorization"), "Credential="+exampleAccessKey+"/")
+	assert.Contains(t, requests[0].Header.Get("Authorization"), "/auto/s3/aws4_request")
+}
+
+func TestBucketClientList(t *testing.T) {
+	// given
+	stub := &bucketStub{objects: map[string][]byte{
+		"a/1.rcd":     {},
+		"a/1.rcd_sig": {},
+		"a/2.rcd":     {},
+		"a/3.rcd":     {},
+		"b/1.rcd":     {},
+	}}
+	client := newTestBucketClient(t, stub, config.BackendRecordStream{CloudProvider: "S3"})
+
+	// when
+	keys, err := client.list(context.Background(), "a/", "a/1.rcd", 2)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a/1.rcd_sig", "a/2.rcd"}, keys)
+}
@@ -0,0 +1,439 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package recordstream
+
+import (
+	"context"
+	"encoding/hex"
+	"time"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/builder"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/recordfile"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	hErrors "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/tools"
+	"github.com/hashgraph/hedera-protobufs-go/services"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+// successfulResults are the results the database backend treats as successful
+var successfulResults = map[services.ResponseCodeEnum]bool{
+	services.ResponseCodeEnum_FEE_SCHEDULE_FILE_PART_UPLOADED:        true,
+	services.ResponseCodeEnum_SUCCESS:                                true,
+	services.ResponseCodeEnum_SUCCESS_BUT_MISSING_EXPECTED_OPERATION: true,
+}
+
+// decodedItem is a record stream item decoded into the record the operations are built from
+type decodedItem struct {
+	consensusTimestamp int64
+	entityId           *domain.EntityId
+	hash               string
+	transaction        builder.Transaction
+}
+
+// transactionDecoder decodes the record stream items of a record file into the rosetta transactions with the same
+// builders as the database backend. The non-fee transfers are extracted from the transaction body the same way as the
+// importer, and the token decimals are looked up from the token repository of the backend
+type transactionDecoder struct {
+	feeBreakdownBuilder builder.FeeBreakdownBuilder
+	operationBuilder    builder.OperationBuilder
+	tokenRepository     interfaces.TokenRepository
+}
+
+func newTransactionDecoder(
+	tokenRepository interfaces.TokenRepository,
+	systemAccounts config.SystemAccounts,
+	suppressEmptyOperations bool,
+) *transactionDecoder {
+	return &transactionDecoder{
+		feeBreakdownBuilder: builder.NewFeeBreakdownBuilder(systemAccounts),
+		operationBuilder:    builder.NewOperationBuilder(systemAccounts, suppressEmptyOperations),
+		tokenRepository:     tokenRepository,
+	}
+}
+
+// decode decodes the items of a record file into the transactions grouped by hash in the order the hashes first
+// appear, and returns them with the consensus timestamps of the first and the last item. An error is returned if a
+// transfer of a token unknown to the backend can't be built, so the block isn't served with incomplete operations
+func (d *transactionDecoder) decode(ctx context.Context, items []recordfile.Item) (
+	[]*types.Transaction,
+	int64,
+	int64,
+	error,
+) {
+	tokens := make(map[int64]*types.Token)
+	hashes := make([]string, 0, len(items))
+	sameHashMap := make(map[string][]decodedItem)
+	var consensusStart, consensusEnd int64
+	for index, item := range items {
+		decoded, err := d.decodeItem(ctx, item, tokens)
+		if err != nil {
+			return nil, 0, 0, errors.Wrapf(err, "Failed to decode record stream item %d", index)
+		}
+
+		if index == 0 {
+			consensusStart = decoded.consensusTimestamp
+		}
+		consensusEnd = decoded.consensusTimestamp
+
+		if _, ok := sameHashMap[decoded.hash]; !ok {
+			hashes = append(hashes, decoded.hash)
+		}
+		sameHashMap[decoded.hash] = append(sameHashMap[decoded.hash], decoded)
+	}
+
+	transactions := make([]*types.Transaction, 0, len(hashes))
+	for _, hash := range hashes {
+		sameHashItems := sameHashMap[hash]
+		tResult := &types.Transaction{Hash: hash}
+		builderTransactions := make([]builder.Transaction, 0, len(sameHashItems))
+		for _, decoded := range sameHashItems {
+			if successfulResults[services.ResponseCodeEnum(decoded.transaction.Result)] {
+				tResult.EntityId = decoded.entityId
+			}
+			builderTransactions = append(builderTransactions, decoded.transaction)
+		}
+
+		tResult.FeeBreakdown = d.feeBreakdownBuilder.Build(builderTransactions)
+		tResult.Operations = d.operationBuilder.Build(builderTransactions)
+		transactions = append(transactions, tResult)
+	}
+
+	return transactions, consensusStart, consensusEnd, nil
+}
+
+func (d *transactionDecoder) decodeItem(
+	ctx context.Context,
+	item recordfile.Item,
+	tokens map[int64]*types.Token,
+) (decodedItem, error) {
+	var record services.TransactionRecord
+	if err := proto.Unmarshal(item.Record, &record); err != nil {
+		return decodedItem{}, errors.Wrap(err, "Failed to unmarshal transaction record")
+	}
+
+	body, err := getTransactionBody(item.Transaction)
+	if err != nil {
+		return decodedItem{}, err
+	}
+
+	payer, err := toAccountEntityId(body.GetTransactionID().GetAccountID())
+	if err != nil || payer == nil {
+		return decodedItem{}, errors.New("Invalid payer")
+	}
+
+	nodeAccountId, err := toAccountEntityId(body.GetNodeAccountID())
+	if err != nil {
+		return decodedItem{}, err
+	}
+
+	entityId, err := getEntityId(body, record.GetReceipt())
+	if err != nil {
+		return decodedItem{}, err
+	}
+
+	transaction := builder.Transaction{
+		ChargedTxFee:   int64(record.GetTransactionFee()),
+		NodeAccountId:  nodeAccountId,
+		PayerAccountId: *payer,
+		RecordBytes:    item.Record,
+		Result:         int32(record.GetReceipt().GetStatus()),
+		Scheduled:      record.GetTransactionID().GetScheduled(),
+		Type:           getTransactionType(body),
+	}
+
+	if transaction.CryptoTransfers, err = toHbarTransfers(record.GetTransferList().GetAccountAmounts()); err != nil {
+		return decodedItem{}, err
+	}
+
+	if transaction.NonFeeTransfers, err = getNonFeeTransfers(body, &record, *payer); err != nil {
+		return decodedItem{}, err
+	}
+
+	getToken := func(tokenId domain.EntityId) (*types.Token, error) {
+		if token, ok := tokens[tokenId.EncodedId]; ok {
+			return token, nil
+		}
+
+		token, rErr := d.tokenRepository.Find(ctx, tokenId.EncodedId)
+		if rErr != nil {
+			return nil, errors.Errorf("Failed to find token %s: %s", tokenId.String(), rErr.Message)
+		}
+		tokens[tokenId.EncodedId] = token
+		return token, nil
+	}
+
+	transaction.NftTransfers = make([]domain.NftTransfer, 0)
+	transaction.TokenTransfers = make([]builder.TokenTransfer, 0)
+	for _, tokenTransferList := range record.GetTokenTransferLists() {
+		protoTokenId := tokenTransferList.GetToken()
+		tokenId, err := domain.EntityIdOf(protoTokenId.GetShardNum(), protoTokenId.GetRealmNum(),
+			protoTokenId.GetTokenNum())
+		if err != nil {
+			return decodedItem{}, err
+		}
+
+		for _, transfer := range tokenTransferList.GetNftTransfers() {
+			receiver, err := toAccountEntityId(transfer.GetReceiverAccountID())
+			if err != nil {
+				return decodedItem{}, err
+			}
+
+			sender, err := toAccountEntityId(transfer.GetSenderAccountID())
+			if err != nil {
+				return decodedItem{}, err
+			}
+
+			transaction.NftTransfers = append(transaction.NftTransfers, domain.NftTransfer{
+				IsApproval:        transfer.GetIsApproval(),
+				PayerAccountId:    *payer,
+				ReceiverAccountId: receiver,
+				SenderAccountId:   sender,
+				SerialNumber:      transfer.GetSerialNumber(),
+				TokenId:           tokenId,
+			})
+		}
+
+		if len(tokenTransferList.GetTransfers()) == 0 {
+			continue
+		}
+
+		token, err := getToken(tokenId)
+		if err != nil {
+			return decodedItem{}, err
+		}
+
+		for _, transfer := range tokenTransferList.GetTransfers() {
+			accountId, err := toAccountEntityId(transfer.GetAccountID())
+			if err != nil || accountId == nil {
+				return decodedItem{}, errors.New("Invalid token transfer")
+			}
+
+			transaction.TokenTransfers = append(transaction.TokenTransfers, builder.TokenTransfer{
+				AccountId:  *accountId,
+				Amount:     transfer.GetAmount(),
+				Decimals:   token.Decimals,
+				IsApproval: transfer.GetIsApproval(),
+				TokenId:    tokenId,
+				Type:       token.Type,
+			})
+		}
+	}
+
+	// the token definition is only needed by the token create, delete, and update operations, and a token created in
+	// a block ahead of the backend isn't known yet
+	if entityId != nil && isTokenDefinitionType(transaction.Type) {
+		if token, rErr := d.tokenRepository.Find(ctx, entityId.EncodedId); rErr == nil {
+			transaction.Token = token.Token
+		} else if rErr != hErrors.ErrTokenNotFound {
+			return decodedItem{}, errors.Errorf("Failed to find token %s: %s", entityId.String(), rErr.Message)
+		}
+	}
+
+	return decodedItem{
+		consensusTimestamp: toNanos(record.GetConsensusTimestamp()),
+		entityId:           entityId,
+		hash:               tools.SafeAddHexPrefix(hex.EncodeToString(record.GetTransactionHash())),
+		transaction:        transaction,
+	}, nil
+}
+
+// getEntityId returns the entity created by the transaction from its receipt, or the token of a token delete or update
+// transaction
+func getEntityId(body *services.TransactionBody, receipt *services.TransactionReceipt) (*domain.EntityId, error) {
+	var shard, realm, num int64
+	switch {
+	case receipt.GetAccountID() != nil:
+		return toAccountEntityId(receipt.GetAccountID())
+	case receipt.GetContractID() != nil:
+		id := receipt.GetContractID()
+		shard, realm, num = id.GetShardNum(), id.GetRealmNum(), id.GetContractNum()
+	case receipt.GetFileID() != nil:
+		id := receipt.GetFileID()
+		shard, realm, num = id.GetShardNum(), id.GetRealmNum(), id.GetFileNum()
+	case receipt.GetScheduleID() != nil:
+		id := receipt.GetScheduleID()
+		shard, realm, num = id.GetShardNum(), id.GetRealmNum(), id.GetScheduleNum()
+	case receipt.GetTokenID() != nil:
+		id := receipt.GetTokenID()
+		shard, realm, num = id.GetShardNum(), id.GetRealmNum(), id.GetTokenNum()
+	case receipt.GetTopicID() != nil:
+		id := receipt.GetTopicID()
+		shard, realm, num = id.GetShardNum(), id.GetRealmNum(), id.GetTopicNum()
+	case body.GetTokenDeletion().GetToken() != nil:
+		id := body.GetTokenDeletion().GetToken()
+		shard, realm, num = id.GetShardNum(), id.GetRealmNum(), id.GetTokenNum()
+	case body.GetTokenUpdate().GetToken() != nil:
+		id := body.GetTokenUpdate().GetToken()
+		shard, realm, num = id.GetShardNum(), id.GetRealmNum(), id.GetTokenNum()
+	default:
+		return nil, nil
+	}
+
+	entityId, err := domain.EntityIdOf(shard, realm, num)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entityId, nil
+}
+
+// getNonFeeTransfers extracts the non-fee transfers the same way as the importer, i.e., the transfer list of a crypto
+// transfer, the initial balance of a crypto or contract create, and the amount of a contract call
+func getNonFeeTransfers(
+	body *services.TransactionBody,
+	record *services.TransactionRecord,
+	payer domain.EntityId,
+) ([]builder.HbarTransfer, error) {
+	var amount int64
+	var receiver *domain.EntityId
+	var err error
+	receipt := record.GetReceipt()
+	switch {
+	case body.GetCryptoTransfer() != nil:
+		return toHbarTransfers(body.GetCryptoTransfer().GetTransfers().GetAccountAmounts())
+	case body.GetCryptoCreateAccount() != nil:
+		amount = int64(body.GetCryptoCreateAccount().GetInitialBalance())
+		if receipt.GetStatus() == services.ResponseCodeEnum_SUCCESS {
+			receiver, err = toAccountEntityId(receipt.GetAccountID())
+		}
+	case body.GetContractCreateInstance() != nil:
+		amount = body.GetContractCreateInstance().GetInitialBalance()
+		if receipt.GetStatus() == services.ResponseCodeEnum_SUCCESS {
+			receiver, err = toContractEntityId(receipt.GetContractID())
+		}
+	case body.GetContractCall() != nil:
+		amount = body.GetContractCall().GetAmount()
+		contractId := receipt.GetContractID()
+		if contractId == nil {
+			contractId = body.GetContractCall().GetContractID()
+		}
+		receiver, err = toContractEntityId(contractId)
+	default:
+		return []builder.HbarTransfer{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	nonFeeTransfers := []builder.HbarTransfer{{AccountId: payer, Amount: -amount}}
+	if receiver != nil {
+		nonFeeTransfers = append(nonFeeTransfers, builder.HbarTransfer{AccountId: *receiver, Amount: amount})
+	}
+
+	return nonFeeTransfers, nil
+}
+
+func getTransactionBody(transactionBytes []byte) (*services.TransactionBody, error) {
+	var transaction services.Transaction
+	if err := proto.Unmarshal(transactionBytes, &transaction); err != nil {
+		return nil, errors.Wrap(err, "Failed to unmarshal transaction")
+	}
+
+	// old transactions may have the deprecated body bytes instead of the signed transaction bytes
+	bodyBytes := transaction.GetBodyBytes()
+	if len(transaction.GetSignedTransactionBytes()) != 0 {
+		var signedTransaction services.SignedTransaction
+		if err := proto.Unmarshal(transaction.GetSignedTransactionBytes(), &signedTransaction); err != nil {
+			return nil, errors.Wrap(err, "Failed to unmarshal signed transaction")
+		}
+		bodyBytes = signedTransaction.GetBodyBytes()
+	}
+
+	var body services.TransactionBody
+	if err := proto.Unmarshal(bodyBytes, &body); err != nil {
+		return nil, errors.Wrap(err, "Failed to unmarshal transaction body")
+	}
+
+	return &body, nil
+}
+
+// getTransactionType returns the type of the transaction, which is the field number of the data in the transaction
+// body, or 0 if unknown
+func getTransactionType(body *services.TransactionBody) int32 {
+	message := body.ProtoReflect()
+	field := message.WhichOneof(message.Descriptor().Oneofs().ByName("data"))
+	if field == nil {
+		return 0
+	}
+
+	return int32(field.Number())
+}
+
+func isTokenDefinitionType(transactionType int32) bool {
+	return transactionType == int32(domain.TransactionTypeTokenCreation) ||
+		transactionType == int32(domain.TransactionTypeTokenDeletion) ||
+		transactionType == int32(domain.TransactionTypeTokenUpdate)
+}
+
+// toAccountEntityId converts the account id to the entity id, nil if not set
+func toAccountEntityId(accountId *services.AccountID) (*domain.EntityId, error) {
+	if accountId == nil || accountId.GetAccountNum() == 0 {
+		return nil, nil
+	}
+
+	entityId, err := domain.EntityIdOf(accountId.GetShardNum(), accountId.GetRealmNum(), accountId.GetAccountNum())
+	if err != nil {
+		return nil, err
+	}
+
+	return &entityId, nil
+}
+
+// toContractEntityId converts the contract id to the entity id, nil if not set
+func toContractEntityId(contractId *services.ContractID) (*domain.EntityId, error) {
+	if contractId == nil || contractId.GetContractNum() == 0 {
+		return nil, nil
+	}
+
+	entityId, err := domain.EntityIdOf(contractId.GetShardNum(), contractId.GetRealmNum(), contractId.GetContractNum())
+	if err != nil {
+		return nil, err
+	}
+
+	return &entityId, nil
+}
+
+func toHbarTransfers(accountAmounts []*services.AccountAmount) ([]builder.HbarTransfer, error) {
+	transfers := make([]builder.HbarTransfer, 0, len(accountAmounts))
+	for _, accountAmount := range accountAmounts {
+		accountId, err := toAccountEntityId(accountAmount.GetAccountID())
+		if err != nil || accountId == nil {
+			return nil, errors.New("Invalid hbar transfer")
+		}
+
+		transfers = append(transfers, builder.HbarTransfer{
+			AccountId:  *accountId,
+			Amount:     accountAmount.GetAmount(),
+			IsApproval: accountAmount.GetIsApproval(),
+		})
+	}
+
+	return transfers, nil
+}
+
+func toNanos(timestamp *services.Timestamp) int64 {
+	return timestamp.GetSeconds()*int64(time.Second) + int64(timestamp.GetNanos())
+}
@@ -0,0 +1,313 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package recordstream
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/builder"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/recordfile"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	hErrors "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/mocks"
+	"github.com/hashgraph/hedera-protobufs-go/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	nodeAccount  = toAccountId(3)
+	payerAccount = toAccountId(1001)
+	token        = &types.Token{Token: domain.Token{
+		Decimals: 2,
+		TokenId:  domain.MustDecodeEntityId(2001),
+		Type:     domain.TokenTypeFungibleCommon,
+	}}
+)
+
+func toAccountId(num int64) *services.AccountID {
+	return &services.AccountID{Account: &services.AccountID_AccountNum{AccountNum: num}}
+}
+
+func newTestItem(
+	t *testing.T,
+	body *services.TransactionBody,
+	record *services.TransactionRecord,
+	consensusTimestamp int64,
+) recordfile.Item {
+	body.NodeAccountID = nodeAccount
+	body.TransactionID = &services.TransactionID{AccountID: payerAccount}
+	bodyBytes, err := proto.Marshal(body)
+	require.NoError(t, err)
+	signedTransactionBytes, err := proto.Marshal(&services.SignedTransaction{BodyBytes: bodyBytes})
+	require.NoError(t, err)
+	transactionBytes, err := proto.Marshal(&services.Transaction{SignedTransactionBytes: signedTransactionBytes})
+	require.NoError(t, err)
+
+	record.ConsensusTimestamp = &services.Timestamp{
+		Seconds: consensusTimestamp / 1_000_000_000,
+		Nanos:   int32(consensusTimestamp % 1_000_000_000),
+	}
+	if record.TransactionHash == nil {
+		record.TransactionHash = []byte{byte(consensusTimestamp)}
+	}
+	record.TransactionFee = 10
+	record.TransferList = &services.TransferList{AccountAmounts: []*services.AccountAmount{
+		{AccountID: payerAccount, Amount: -10},
+		{AccountID: toAccountId(98), Amount: 10},
+	}}
+	recordBytes, err := proto.Marshal(record)
+	require.NoError(t, err)
+
+	return recordfile.Item{Record: recordBytes, Transaction: transactionBytes}
+}
+
+func newTestDecoder(tokenRepo *mocks.MockTokenRepository) *transactionDecoder {
+	return newTransactionDecoder(tokenRepo, config.SystemAccounts{}, false)
+}
+
+func TestDecodeCryptoCreate(t *testing.T) {
+	// given
+	body := &services.TransactionBody{Data: &services.TransactionBody_CryptoCreateAccount{
+		CryptoCreateAccount: &services.CryptoCreateTransactionBody{InitialBalance: 500},
+	}}
+	record := &services.TransactionRecord{Receipt: &services.TransactionReceipt{
+		AccountID: toAccountId(1002),
+		Status:    services.ResponseCodeEnum_SUCCESS,
+	}}
+	items := []recordfile.Item{newTestItem(t, body, record, 100)}
+	decoder := newTestDecoder(&mocks.MockTokenRepository{})
+
+	// when
+	transactions, start, end, err := decoder.decode(context.Background(), items)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), start)
+	assert.Equal(t, int64(100), end)
+	require.Len(t, transactions, 1)
+	assert.Equal(t, "0x64", transactions[0].Hash)
+	assert.Equal(t, "0.0.1002", transactions[0].EntityId.String())
+	assert.NotEmpty(t, transactions[0].Operations)
+}
+
+func TestDecodeItemNonFeeTransfers(t *testing.T) {
+	contract := &services.ContractID{Contract: &services.ContractID_ContractNum{ContractNum: 1003}}
+	tests := []struct {
+		name     string
+		body     *services.TransactionBody
+		receipt  *services.TransactionReceipt
+		expected []builder.HbarTransfer
+	}{
+		{
+			name: "crypto transfer",
+			body: &services.TransactionBody{Data: &services.TransactionBody_CryptoTransfer{
+				CryptoTransfer: &services.CryptoTransferTransactionBody{Transfers: &services.TransferList{
+					AccountAmounts: []*services.AccountAmount{
+						{AccountID: payerAccount, Amount: -20},
+						{AccountID: toAccountId(1002), Amount: 20, IsApproval: true},
+					},
+				}},
+			}},
+			receipt: &services.TransactionReceipt{Status: services.ResponseCodeEnum_SUCCESS},
+			expected: []builder.HbarTransfer{
+				{AccountId: domain.MustDecodeEntityId(1001), Amount: -20},
+				{AccountId: domain.MustDecodeEntityId(1002), Amount: 20, IsApproval: true},
+			},
+		},
+		{
+			name: "failed crypto create",
+			body: &services.TransactionBody{Data: &services.TransactionBody_CryptoCreateAccount{
+				CryptoCreateAccount: &services.CryptoCreateTransactionBody{InitialBalance: 500},
+			}},
+			receipt:  &services.TransactionReceipt{Status: services.ResponseCodeEnum_INSUFFICIENT_PAYER_BALANCE},
+			expected: []builder.HbarTransfer{{AccountId: domain.MustDecodeEntityId(1001), Amount: -500}},
+		},
+		{
+			name: "contract create",
+			body: &services.TransactionBody{Data: &services.TransactionBody_ContractCreateInstance{
+				ContractCreateInstance: &services.ContractCreateTransactionBody{InitialBalance: 300},
+			}},
+			receipt: &services.TransactionReceipt{ContractID: contract, Status: services.ResponseCodeEnum_SUCCESS},
+			expected: []builder.HbarTransfer{
+				{AccountId: domain.MustDecodeEntityId(1001), Amount: -300},
+				{AccountId: domain.MustDecodeEntityId(1003), Amount: 300},
+			},
+		},
+		{
+			name: "contract call",
+			body: &services.TransactionBody{Data: &services.TransactionBody_ContractCall{
+				ContractCall: &services.ContractCallTransactionBody{Amount: 40, ContractID: contract},
+			}},
+			receipt: &services.TransactionReceipt{Status: services.ResponseCodeEnum_SUCCESS},
+			expected: []builder.HbarTransfer{
+				{AccountId: domain.MustDecodeEntityId(1001), Amount: -40},
+				{AccountId: domain.MustDecodeEntityId(1003), Amount: 40},
+			},
+		},
+		{
+			name: "consensus submit message",
+			body: &services.TransactionBody{Data: &services.TransactionBody_ConsensusSubmitMessage{
+				ConsensusSubmitMessage: &services.ConsensusSubmitMessageTransactionBody{},
+			}},
+			receipt:  &services.TransactionReceipt{Status: services.ResponseCodeEnum_SUCCESS},
+			expected: []builder.HbarTransfer{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			item := newTestItem(t, tt.body, &services.TransactionRecord{Receipt: tt.receipt}, 100)
+			decoder := newTestDecoder(&mocks.MockTokenRepository{})
+
+			// when
+			decoded, err := decoder.decodeItem(context.Background(), item, map[int64]*types.Token{})
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, decoded.transaction.NonFeeTransfers)
+			assert.Equal(t, domain.MustDecodeEntityId(1001), decoded.transaction.PayerAccountId)
+			assert.Equal(t, domain.MustDecodeEntityId(3), *decoded.transaction.NodeAccountId)
+			assert.Equal(t, int64(10), decoded.transaction.ChargedTxFee)
+			assert.Equal(t, int32(tt.receipt.Status), decoded.transaction.Result)
+		})
+	}
+}
+
+func TestDecodeTokenTransfers(t *testing.T) {
+	// given
+	tokenTransferList := &services.TokenTransferList{
+		Token: &services.TokenID{TokenNum: 2001},
+		Transfers: []*services.AccountAmount{
+			{AccountID: payerAccount, Amount: -5},
+			{AccountID: toAccountId(1002), Amount: 5},
+		},
+	}
+	body := &services.TransactionBody{Data: &services.TransactionBody_CryptoTransfer{
+		CryptoTransfer: &services.CryptoTransferTransactionBody{},
+	}}
+	items := []recordfile.Item{
+		newTestItem(t, body, &services.TransactionRecord{
+			Receipt:            &services.TransactionReceipt{Status: services.ResponseCodeEnum_SUCCESS},
+			TokenTransferLists: []*services.TokenTransferList{tokenTransferList},
+		}, 100),
+		newTestItem(t, body, &services.TransactionRecord{
+			Receipt:            &services.TransactionReceipt{Status: services.ResponseCodeEnum_SUCCESS},
+			TokenTransferLists: []*services.TokenTransferList{tokenTransferList},
+		}, 200),
+	}
+	tokenRepo := &mocks.MockTokenRepository{}
+	tokenRepo.On("Find").Return(token, mocks.NilError).Once()
+	decoder := newTestDecoder(tokenRepo)
+
+	// when
+	transactions, start, end, err := decoder.decode(context.Background(), items)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), start)
+	assert.Equal(t, int64(200), end)
+	require.Len(t, transactions, 2)
+	tokenRepo.AssertNumberOfCalls(t, "Find", 1)
+}
+
+func TestDecodeTokenTransfersUnknownToken(t *testing.T) {
+	// given
+	body := &services.TransactionBody{Data: &services.TransactionBody_CryptoTransfer{
+		CryptoTransfer: &services.CryptoTransferTransactionBody{},
+	}}
+	record := &services.TransactionRecord{
+		Receipt: &services.TransactionReceipt{Status: services.ResponseCodeEnum_SUCCESS},
+		TokenTransferLists: []*services.TokenTransferList{{
+			Token:     &services.TokenID{TokenNum: 2001},
+			Transfers: []*services.AccountAmount{{AccountID: payerAccount, Amount: -5}},
+		}},
+	}
+	tokenRepo := &mocks.MockTokenRepository{}
+	tokenRepo.On("Find").Return(mocks.NilToken, hErrors.ErrTokenNotFound)
+	decoder := newTestDecoder(tokenRepo)
+
+	// when
+	transactions, _, _, err := decoder.decode(context.Background(), []recordfile.Item{newTestItem(t, body, record, 100)})
+
+	// then
+	assert.Error(t, err)
+	assert.Nil(t, transactions)
+}
+
+func TestDecodeSameHash(t *testing.T) {
+	// given
+	body := &services.TransactionBody{Data: &services.TransactionBody_ScheduleSign{
+		ScheduleSign: &services.ScheduleSignTransactionBody{},
+	}}
+	hash := []byte{0xab}
+	items := []recordfile.Item{
+		newTestItem(t, body, &services.TransactionRecord{
+			Receipt:         &services.TransactionReceipt{Status: services.ResponseCodeEnum_SUCCESS},
+			TransactionHash: hash,
+		}, 100),
+		newTestItem(t, body, &services.TransactionRecord{
+			Receipt:         &services.TransactionReceipt{Status: services.ResponseCodeEnum_SUCCESS},
+			TransactionHash: []byte{0xcd},
+		}, 200),
+		newTestItem(t, body, &services.TransactionRecord{
+			Receipt:         &services.TransactionReceipt{Status: services.ResponseCodeEnum_SUCCESS},
+			TransactionHash: hash,
+		}, 300),
+	}
+	decoder := newTestDecoder(&mocks.MockTokenRepository{})
+
+	// when
+	transactions, _, _, err := decoder.decode(context.Background(), items)
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, transactions, 2)
+	assert.Equal(t, "0xab", transactions[0].Hash)
+	assert.Equal(t, "0xcd", transactions[1].Hash)
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	decoder := newTestDecoder(&mocks.MockTokenRepository{})
+	for _, item := range []recordfile.Item{
+		{Record: []byte{0xff}, Transaction: []byte{}},
+		{Record: []byte{}, Transaction: []byte{0xff}},
+		{Record: []byte{}, Transaction: []byte{}},
+	} {
+		transactions, _, _, err := decoder.decode(context.Background(), []recordfile.Item{item})
+		assert.Error(t, err)
+		assert.Nil(t, transactions)
+	}
+}
+
+func TestGetTransactionType(t *testing.T) {
+	assert.Equal(t, int32(14), getTransactionType(&services.TransactionBody{
+		Data: &services.TransactionBody_CryptoTransfer{},
+	}))
+	assert.Equal(t, int32(domain.TransactionTypeTokenCreation), getTransactionType(&services.TransactionBody{
+		Data: &services.TransactionBody_TokenCreation{},
+	}))
+	assert.Equal(t, int32(0), getTransactionType(&services.TransactionBody{}))
+}
@@ -0,0 +1,126 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package recordstream
+
+import (
+	"context"
+	"crypto/rsa"
+	"path"
+	"strings"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/recordfile"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const signatureFileSuffix = "_sig"
+
+// nodeKey is the public key of a node in the address book and the prefix of its record stream files in the bucket. The
+// public key is nil if it's invalid
+type nodeKey struct {
+	accountId string
+	prefix    string
+	publicKey *rsa.PublicKey
+}
+
+// signatureVerifier verifies the signature files of the record files written by the nodes in the current address book
+type signatureVerifier struct {
+	client *bucketClient
+	nodes  []nodeKey
+}
+
+// newSignatureVerifier creates a signatureVerifier with the public keys of the nodes in the current address book
+func newSignatureVerifier(
+	ctx context.Context,
+	addressBookEntryRepo interfaces.AddressBookEntryRepository,
+	client *bucketClient,
+) (*signatureVerifier, error) {
+	entries, rErr := addressBookEntryRepo.Entries(ctx)
+	if rErr != nil {
+		return nil, errors.Errorf("Failed to get the address book: %s", rErr.Message)
+	}
+
+	if len(entries.Entries) == 0 {
+		return nil, errors.New("The address book is empty")
+	}
+
+	nodes := make([]nodeKey, 0, len(entries.Entries))
+	for _, entry := range entries.Entries {
+		accountId := entry.AccountId.String()
+		publicKey, err := recordfile.ParsePublicKey(entry.PublicKey)
+		if err != nil {
+			log.Warnf("Ignoring the signatures of node %s with invalid public key: %s", accountId, err)
+		}
+
+		nodes = append(nodes, nodeKey{
+			accountId: accountId,
+			prefix:    "recordstreams/record" + accountId + "/",
+			publicKey: publicKey,
+		})
+	}
+
+	return &signatureVerifier{client: client, nodes: nodes}, nil
+}
+
+// verify returns an error unless at least a third of the nodes in the address book signed the file hash of the record
+// file with the key, the same consensus the mirror node requires before it imports a record file. Since a third of the
+// nodes always includes an honest node, a record file altered in the bucket or written by a faulty node is rejected.
+// The signature files are read until the quorum is reached or can no longer be reached
+func (v *signatureVerifier) verify(ctx context.Context, key string, fileHash []byte) error {
+	name := strings.TrimSuffix(path.Base(key), ".gz") + signatureFileSuffix
+	required := (len(v.nodes) + 2) / 3
+	signed := 0
+	for i, node := range v.nodes {
+		if signed+len(v.nodes)-i < required {
+			break
+		}
+
+		if node.publicKey == nil {
+			continue
+		}
+
+		if err := v.verifyNode(ctx, node, name, fileHash); err != nil {
+			log.Debugf("Failed to verify the signature of node %s of record file %s: %s", node.accountId, name, err)
+			continue
+		}
+
+		signed++
+		if signed == required {
+			return nil
+		}
+	}
+
+	return errors.Errorf("Only %d of %d nodes signed the record file, %d required", signed, len(v.nodes), required)
+}
+
+func (v *signatureVerifier) verifyNode(ctx context.Context, node nodeKey, name string, fileHash []byte) error {
+	data, err := v.client.get(ctx, node.prefix+name)
+	if err != nil {
+		return err
+	}
+
+	signatureFile, err := recordfile.ParseSignatureFile(data)
+	if err != nil {
+		return err
+	}
+
+	return signatureFile.Verify(fileHash, node.publicKey)
+}
@@ -0,0 +1,343 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package recordstream
+
+import (
+	"context"
+	"encoding/hex"
+	"path"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/recordfile"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/tools"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultMaxBlocks    = 10
+	defaultPollInterval = 2 * time.Second
+	recordFileSuffix    = ".rcd"
+	// recordFileTimeLayout is the layout of the consensus timestamp in the record file name, which is the ISO-8601
+	// instant with the colons replaced by underscores. The fraction always has 9 digits so the name formatted from a
+	// timestamp never sorts after the name of a later record file
+	recordFileTimeLayout = "2006-01-02T15:04:05.000000000Z"
+)
+
+// streamBlock is a block read from the bucket with its decoded transactions. consensusEnd is the consensus timestamp of
+// its last transaction, while the consensus end of the block extends to right before the start of the next block
+type streamBlock struct {
+	block        types.Block
+	consensusEnd int64
+	transactions []*types.Transaction
+}
+
+// recordStream polls the bucket for the record files following the latest block of the backend. A record file is only
+// accepted if it parses, its recomputed end running hash matches the one in the file, its start running hash is the
+// hash of the block before it, and its file hash is signed by at least a third of the nodes in the address book. The
+// hash chain only links the blocks to the latest block of the backend, it's the node signatures which establish that
+// the record files are the ones the network produced. The blocks the backend has caught up with are dropped
+type recordStream struct {
+	// blocks holds the []*streamBlock ahead of the backend in ascending order of the index, it's replaced on each poll
+	blocks               atomic.Value
+	addressBookEntryRepo interfaces.AddressBookEntryRepository
+	blockRepo            interfaces.BlockRepository
+	client               *bucketClient
+	decoder              *transactionDecoder
+	maxBlocks            int
+	pollInterval         time.Duration
+	prefix               string
+}
+
+func newRecordStream(
+	addressBookEntryRepo interfaces.AddressBookEntryRepository,
+	blockRepo interfaces.BlockRepository,
+	client *bucketClient,
+	decoder *transactionDecoder,
+	nodeAccountId string,
+	maxBlocks int,
+	pollInterval time.Duration,
+) *recordStream {
+	if maxBlocks <= 0 {
+		maxBlocks = defaultMaxBlocks
+	}
+
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+
+	stream := &recordStream{
+		addressBookEntryRepo: addressBookEntryRepo,
+		blockRepo:            blockRepo,
+		client:               client,
+		decoder:              decoder,
+		maxBlocks:            maxBlocks,
+		pollInterval:         pollInterval,
+		prefix:               "recordstreams/record" + nodeAccountId + "/",
+	}
+	stream.blocks.Store([]*streamBlock{})
+	return stream
+}
+
+// run polls the bucket every poll interval until the context is done
+func (s *recordStream) run(ctx context.Context) {
+	log.Infof("Reading the record files ahead of the backend from %s", s.prefix)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			s.poll(ctx)
+			timer.Reset(s.pollInterval)
+		}
+	}
+}
+
+// findBetween returns the block starting at start if it's ahead of the backend and all its transactions are before end
+func (s *recordStream) findBetween(start, end int64) *streamBlock {
+	for _, block := range s.getBlocks() {
+		if block.block.ConsensusStartNanos == start && block.consensusEnd <= end {
+			return block
+		}
+	}
+
+	return nil
+}
+
+// findByHash returns the block with the hash if it's ahead of the backend
+func (s *recordStream) findByHash(hash string) *types.Block {
+	hash = tools.SafeRemoveHexPrefix(hash)
+	for _, block := range s.getBlocks() {
+		if block.block.Hash == hash {
+			return copyBlock(block)
+		}
+	}
+
+	return nil
+}
+
+// findByIndex returns the block with the index if it's ahead of the backend
+func (s *recordStream) findByIndex(index int64) *types.Block {
+	for _, block := range s.getBlocks() {
+		if block.block.Index == index {
+			return copyBlock(block)
+		}
+	}
+
+	return nil
+}
+
+// latest returns the latest block if it's ahead of the latest block of the backend
+func (s *recordStream) latest(backendLatest *types.Block) *types.Block {
+	blocks := s.getBlocks()
+	if len(blocks) == 0 || blocks[len(blocks)-1].block.Index <= backendLatest.Index {
+		return nil
+	}
+
+	return copyBlock(blocks[len(blocks)-1])
+}
+
+func (s *recordStream) getBlocks() []*streamBlock {
+	return s.blocks.Load().([]*streamBlock)
+}
+
+// poll drops the blocks the backend has caught up with, then reads the record files following the last block until
+// there are max blocks ahead of the backend. The chain restarts from the latest block of the backend if the kept
+// blocks don't follow it
+func (s *recordStream) poll(ctx context.Context) {
+	backendLatest, rErr := s.blockRepo.RetrieveLatest(ctx)
+	if rErr != nil {
+		log.Debugf("Failed to retrieve the latest block of the backend: %s", rErr.Message)
+		return
+	}
+
+	blocks := make([]*streamBlock, 0, s.maxBlocks)
+	for _, block := range s.getBlocks() {
+		if block.block.Index > backendLatest.Index {
+			blocks = append(blocks, block)
+		}
+	}
+
+	if len(blocks) != 0 && (blocks[0].block.ParentIndex != backendLatest.Index ||
+		blocks[0].block.ParentHash != tools.SafeRemoveHexPrefix(backendLatest.Hash)) {
+		log.Warnf("Block %d read from the bucket doesn't follow the latest block %d of the backend, restarting",
+			blocks[0].block.Index, backendLatest.Index)
+		blocks = blocks[:0]
+	}
+
+	parent := *backendLatest
+	parent.Hash = tools.SafeRemoveHexPrefix(parent.Hash)
+	if len(blocks) != 0 {
+		parent = blocks[len(blocks)-1].block
+	}
+
+	if count := s.maxBlocks - len(blocks); count > 0 {
+		blocks = s.readBlocks(ctx, blocks, parent, count)
+	}
+
+	s.blocks.Store(blocks)
+}
+
+// readBlocks reads at most count blocks following the parent from the bucket and appends them to blocks. The reading
+// stops at the first record file which is missing, invalid, or doesn't follow the previous block
+func (s *recordStream) readBlocks(
+	ctx context.Context,
+	blocks []*streamBlock,
+	parent types.Block,
+	count int,
+) []*streamBlock {
+	startAfter := s.prefix + toRecordFileName(parent.ConsensusStartNanos)
+	// each record file has a signature file, and the sidecar directory may count as one key
+	keys, err := s.client.list(ctx, s.prefix, startAfter, count*2+1)
+	if err != nil {
+		log.Warnf("Failed to list the record files after block %d: %s", parent.Index, err)
+		return blocks
+	}
+
+	if len(keys) == 0 {
+		return blocks
+	}
+
+	verifier, err := newSignatureVerifier(ctx, s.addressBookEntryRepo, s.client)
+	if err != nil {
+		log.Warnf("Failed to get the node public keys to verify the record files after block %d: %s", parent.Index,
+			err)
+		return blocks
+	}
+
+	for _, key := range keys {
+		if len(blocks) == s.maxBlocks {
+			break
+		}
+
+		timestamp, ok := parseRecordFileTimestamp(key)
+		if !ok || timestamp <= parent.ConsensusStartNanos {
+			continue
+		}
+
+		block, err := s.readBlock(ctx, key, parent, verifier)
+		if err != nil {
+			log.Warnf("Failed to read block %d from record file %s: %s", parent.Index+1, key, err)
+			break
+		}
+
+		// the previous block ends right before the block starts
+		if len(blocks) != 0 {
+			previous := *blocks[len(blocks)-1]
+			previous.block.ConsensusEndNanos = block.block.ConsensusStartNanos - 1
+			blocks[len(blocks)-1] = &previous
+		}
+
+		log.Debugf("Read block %d from record file %s", block.block.Index, key)
+		blocks = append(blocks, block)
+		parent = block.block
+	}
+
+	return blocks
+}
+
+// readBlock gets, parses, and verifies the record file following the parent, and decodes its transactions
+func (s *recordStream) readBlock(
+	ctx context.Context,
+	key string,
+	parent types.Block,
+	verifier *signatureVerifier,
+) (*streamBlock, error) {
+	data, err := s.client.get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	recordFile, err := recordfile.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = recordFile.Verify(); err != nil {
+		return nil, err
+	}
+
+	if prevHash := hex.EncodeToString(recordFile.PrevHash); prevHash != parent.Hash {
+		return nil, errors.Errorf("previous hash %s doesn't match hash %s of block %d", prevHash, parent.Hash,
+			parent.Index)
+	}
+
+	index := parent.Index + 1
+	if recordFile.Version >= 6 && recordFile.Index != index {
+		return nil, errors.Errorf("block number %d doesn't follow block %d", recordFile.Index, parent.Index)
+	}
+
+	if err = verifier.verify(ctx, key, recordFile.FileHash); err != nil {
+		return nil, err
+	}
+
+	transactions, consensusStart, consensusEnd, err := s.decoder.decode(ctx, recordFile.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	return &streamBlock{
+		block: types.Block{
+			ConsensusEndNanos:   consensusEnd,
+			ConsensusStartNanos: consensusStart,
+			Hash:                hex.EncodeToString(recordFile.Hash),
+			Index:               index,
+			ParentHash:          parent.Hash,
+			ParentIndex:         parent.Index,
+			RecordStream:        true,
+		},
+		consensusEnd: consensusEnd,
+		transactions: transactions,
+	}, nil
+}
+
+func copyBlock(block *streamBlock) *types.Block {
+	copied := block.block
+	return &copied
+}
+
+// parseRecordFileTimestamp parses the consensus timestamp from the key of a record file, false if the key isn't of a
+// record file, e.g., a signature file
+func parseRecordFileTimestamp(key string) (int64, bool) {
+	name := strings.TrimSuffix(path.Base(key), ".gz")
+	if !strings.HasSuffix(name, recordFileSuffix) {
+		return 0, false
+	}
+
+	instant := strings.ReplaceAll(strings.TrimSuffix(name, recordFileSuffix), "_", ":")
+	timestamp, err := time.Parse(time.RFC3339Nano, instant)
+	if err != nil {
+		return 0, false
+	}
+
+	return timestamp.UnixNano(), true
+}
+
+// toRecordFileName formats the consensus timestamp as the name of a record file without the extension
+func toRecordFileName(timestamp int64) string {
+	return strings.ReplaceAll(time.Unix(0, timestamp).UTC().Format(recordFileTimeLayout), ":", "_")
+}
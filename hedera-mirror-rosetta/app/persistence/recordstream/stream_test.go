@@ -0,0 +1,442 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package recordstream
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/recordfile"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	hErrors "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	block4Hash    = "13d2594b9e9dbb73dad0cad67a96ad7a0e249af8693aa894003876c9ddd5534b3143e4d785e04fc0c461945a03e85178"
+	block4Start   = int64(1657701966000000000)
+	block5Hash    = "a6c241fad2c636f68a6aa0da9293245a5ef0ebef345cd139858068ff7998716cefe0fd3afa0d21304725507061975279"
+	block5Name    = "2022-07-13T08_46_08.041986003Z.rcd.gz"
+	block5SigName = "2022-07-13T08_46_08.041986003Z.rcd_sig"
+	block5Start   = int64(1657701968041986003)
+	block6Hash    = "3064b824b8b9f9ece011f88a26c030c9f6b822f30fbcabcc7240a220ea42bbdbf305db415a4e41307d0630d5cefe4550"
+	block6Name    = "2022-07-13T08_46_11.304284003Z.rcd.gz"
+	block6SigName = "2022-07-13T08_46_11.304284003Z.rcd_sig"
+	block6Start   = int64(1657701971304284003)
+	testdataDir   = "../../domain/recordfile/testdata"
+	testMaxBlocks = 10
+	testPrefix    = "recordstreams/record0.0.3/"
+)
+
+// testNodeKeys are the private keys of the nodes 0.0.3 to 0.0.6 in the address book of the tests
+var testNodeKeys = newTestNodeKeys(4)
+
+var block4 = &types.Block{
+	ConsensusEndNanos:   block5Start - 1,
+	ConsensusStartNanos: block4Start,
+	Hash:                "0x" + block4Hash,
+	Index:               4,
+	ParentHash:          "0x00",
+	ParentIndex:         3,
+}
+
+func readTestRecordFile(t *testing.T, name string) []byte {
+	data, err := os.ReadFile(filepath.Join(testdataDir, name))
+	require.NoError(t, err)
+	return data
+}
+
+func newTestNodeKeys(count int) []*rsa.PrivateKey {
+	keys := make([]*rsa.PrivateKey, 0, count)
+	for i := 0; i < count; i++ {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			panic(err)
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// newTestBucketStub returns the stub with the record files of blocks 5 and 6 and their signature files of all nodes
+// but the last one
+func newTestBucketStub(t *testing.T) *bucketStub {
+	objects := map[string][]byte{
+		testPrefix + "2022-07-13T08_46_06.000000000Z.rcd.gz": {},
+		testPrefix + block5Name:                              readTestRecordFile(t, block5Name),
+		testPrefix + block6Name:                              readTestRecordFile(t, block6Name),
+	}
+	for node, key := range testNodeKeys[:len(testNodeKeys)-1] {
+		objects[getTestNodePrefix(node)+block5SigName] = newTestSignatureFile(t, key, block5Name)
+		objects[getTestNodePrefix(node)+block6SigName] = newTestSignatureFile(t, key, block6Name)
+	}
+	return &bucketStub{objects: objects}
+}
+
+func getTestNodePrefix(node int) string {
+	accountId := domain.MustDecodeEntityId(int64(node + 3))
+	return "recordstreams/record" + accountId.String() + "/"
+}
+
+// newTestSignatureFile returns the v6 signature file of the test record file signed with the key
+func newTestSignatureFile(t *testing.T, key *rsa.PrivateKey, recordFileName string) []byte {
+	recordFile, err := recordfile.Parse(readTestRecordFile(t, recordFileName))
+	require.NoError(t, err)
+	digest := sha512.Sum384(recordFile.FileHash)
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA384, digest[:])
+	require.NoError(t, err)
+
+	var hashObject []byte
+	hashObject = protowire.AppendTag(hashObject, 1, protowire.VarintType)
+	hashObject = protowire.AppendVarint(hashObject, 1)
+	hashObject = protowire.AppendTag(hashObject, 3, protowire.BytesType)
+	hashObject = protowire.AppendBytes(hashObject, recordFile.FileHash)
+
+	var signatureObject []byte
+	signatureObject = protowire.AppendTag(signatureObject, 1, protowire.VarintType)
+	signatureObject = protowire.AppendVarint(signatureObject, 1)
+	signatureObject = protowire.AppendTag(signatureObject, 4, protowire.BytesType)
+	signatureObject = protowire.AppendBytes(signatureObject, signature)
+	signatureObject = protowire.AppendTag(signatureObject, 5, protowire.BytesType)
+	signatureObject = protowire.AppendBytes(signatureObject, hashObject)
+
+	data := protowire.AppendTag([]byte{6}, 1, protowire.BytesType)
+	return protowire.AppendBytes(data, signatureObject)
+}
+
+func newTestAddressBookEntryRepo(t *testing.T) *mocks.MockAddressBookEntryRepository {
+	entries := make([]types.AddressBookEntry, 0, len(testNodeKeys))
+	for node, key := range testNodeKeys {
+		der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+		require.NoError(t, err)
+		entries = append(entries, types.AddressBookEntry{
+			NodeId:    int64(node),
+			AccountId: domain.MustDecodeEntityId(int64(node + 3)),
+			PublicKey: hex.EncodeToString(der),
+		})
+	}
+
+	addressBookEntryRepo := &mocks.MockAddressBookEntryRepository{}
+	addressBookEntryRepo.On("Entries").Return(&types.AddressBookEntries{Entries: entries}, mocks.NilError)
+	return addressBookEntryRepo
+}
+
+func newTestRecordStream(t *testing.T, stub *bucketStub, blockRepo *mocks.MockBlockRepository) *recordStream {
+	return newTestRecordStreamWithAddressBook(t, stub, blockRepo, newTestAddressBookEntryRepo(t))
+}
+
+func newTestRecordStreamWithAddressBook(
+	t *testing.T,
+	stub *bucketStub,
+	blockRepo *mocks.MockBlockRepository,
+	addressBookEntryRepo *mocks.MockAddressBookEntryRepository,
+) *recordStream {
+	client := newTestBucketClient(t, stub, config.BackendRecordStream{CloudProvider: "S3"})
+	tokenRepo := &mocks.MockTokenRepository{}
+	tokenRepo.On("Find").Return(mocks.NilToken, hErrors.ErrTokenNotFound)
+	decoder := newTransactionDecoder(tokenRepo, config.SystemAccounts{}, false)
+	return newRecordStream(addressBookEntryRepo, blockRepo, client, decoder, "0.0.3", testMaxBlocks, time.Millisecond)
+}
+
+func TestRecordStreamPoll(t *testing.T) {
+	// given
+	blockRepo := &mocks.MockBlockRepository{}
+	blockRepo.On("RetrieveLatest").Return(block4, mocks.NilError)
+	stream := newTestRecordStream(t, newTestBucketStub(t), blockRepo)
+
+	// when
+	stream.poll(context.Background())
+
+	// then
+	blocks := stream.getBlocks()
+	require.Len(t, blocks, 2)
+	assert.Equal(t, types.Block{
+		ConsensusEndNanos:   block6Start - 1,
+		ConsensusStartNanos: block5Start,
+		Hash:                block5Hash,
+		Index:               5,
+		ParentHash:          block4Hash,
+		ParentIndex:         4,
+		RecordStream:        true,
+	}, blocks[0].block)
+	assert.Len(t, blocks[0].transactions, 1)
+	assert.Equal(t, block6Hash, blocks[1].block.Hash)
+	assert.Equal(t, int64(6), blocks[1].block.Index)
+	assert.Equal(t, block6Start, blocks[1].block.ConsensusStartNanos)
+	assert.Equal(t, blocks[1].consensusEnd, blocks[1].block.ConsensusEndNanos)
+	require.Len(t, blocks[1].transactions, 2)
+	assert.Equal(t, "0x3af126ef15641b785e3edb5018209959fc6c6b063deaa55a3331c5489cdbe31be4163671ecf6672d12aa855d4b3f8e38",
+		blocks[1].transactions[0].Hash)
+	assert.Equal(t, "0.0.1002", blocks[1].transactions[0].EntityId.String())
+	assert.Len(t, blocks[1].transactions[0].Operations, 3)
+	assert.Equal(t, "0.0.1003", blocks[1].transactions[1].EntityId.String())
+}
+
+func TestRecordStreamPollBackendCaughtUp(t *testing.T) {
+	// given
+	blockRepo := &mocks.MockBlockRepository{}
+	blockRepo.On("RetrieveLatest").Return(block4, mocks.NilError).Once()
+	stream := newTestRecordStream(t, newTestBucketStub(t), blockRepo)
+	stream.poll(context.Background())
+	block5 := *stream.findByIndex(5)
+	block5.ConsensusEndNanos = block6Start - 1
+	blockRepo.On("RetrieveLatest").Return(&block5, mocks.NilError).Once()
+
+	// when
+	stream.poll(context.Background())
+
+	// then
+	blocks := stream.getBlocks()
+	require.Len(t, blocks, 1)
+	assert.Equal(t, int64(6), blocks[0].block.Index)
+	assert.Nil(t, stream.findByIndex(5))
+	assert.Equal(t, int64(6), stream.latest(&block5).Index)
+}
+
+func TestRecordStreamPollMaxBlocks(t *testing.T) {
+	// given
+	blockRepo := &mocks.MockBlockRepository{}
+	blockRepo.On("RetrieveLatest").Return(block4, mocks.NilError)
+	stream := newTestRecordStream(t, newTestBucketStub(t), blockRepo)
+	stream.maxBlocks = 1
+
+	// when
+	stream.poll(context.Background())
+
+	// then
+	blocks := stream.getBlocks()
+	require.Len(t, blocks, 1)
+	assert.Equal(t, int64(5), blocks[0].block.Index)
+	assert.Equal(t, blocks[0].consensusEnd, blocks[0].block.ConsensusEndNanos)
+}
+
+func TestRecordStreamPollTamperedRecordFile(t *testing.T) {
+	// given
+	blockRepo := &mocks.MockBlockRepository{}
+	blockRepo.On("RetrieveLatest").Return(block4, mocks.NilError)
+	stub := newTestBucketStub(t)
+	stub.objects[testPrefix+block6Name] = readTestRecordFile(t, block5Name)
+	stream := newTestRecordStream(t, stub, blockRepo)
+
+	// when
+	stream.poll(context.Background())
+
+	// then
+	blocks := stream.getBlocks()
+	require.Len(t, blocks, 1)
+	assert.Equal(t, int64(5), blocks[0].block.Index)
+}
+
+func TestRecordStreamPollWithoutSignatureQuorum(t *testing.T) {
+	// given
+	blockRepo := &mocks.MockBlockRepository{}
+	blockRepo.On("RetrieveLatest").Return(block4, mocks.NilError)
+	stub := newTestBucketStub(t)
+	delete(stub.objects, getTestNodePrefix(1)+block6SigName)
+	delete(stub.objects, getTestNodePrefix(2)+block6SigName)
+	stream := newTestRecordStream(t, stub, blockRepo)
+
+	// when
+	stream.poll(context.Background())
+
+	// then
+	blocks := stream.getBlocks()
+	require.Len(t, blocks, 1)
+	assert.Equal(t, int64(5), blocks[0].block.Index)
+}
+
+func TestRecordStreamPollForgedSignatures(t *testing.T) {
+	// given
+	blockRepo := &mocks.MockBlockRepository{}
+	blockRepo.On("RetrieveLatest").Return(block4, mocks.NilError)
+	stub := newTestBucketStub(t)
+	forgingKey := newTestNodeKeys(1)[0]
+	for node := range testNodeKeys {
+		stub.objects[getTestNodePrefix(node)+block5SigName] = newTestSignatureFile(t, forgingKey, block5Name)
+	}
+	stream := newTestRecordStream(t, stub, blockRepo)
+
+	// when
+	stream.poll(context.Background())
+
+	// then
+	assert.Empty(t, stream.getBlocks())
+}
+
+func TestRecordStreamPollSignedDifferentFile(t *testing.T) {
+	// given
+	blockRepo := &mocks.MockBlockRepository{}
+	blockRepo.On("RetrieveLatest").Return(block4, mocks.NilError)
+	stub := newTestBucketStub(t)
+	for node, key := range testNodeKeys {
+		stub.objects[getTestNodePrefix(node)+block5SigName] = newTestSignatureFile(t, key, block6Name)
+	}
+	stream := newTestRecordStream(t, stub, blockRepo)
+
+	// when
+	stream.poll(context.Background())
+
+	// then
+	assert.Empty(t, stream.getBlocks())
+}
+
+func TestRecordStreamPollAddressBookError(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries *types.AddressBookEntries
+		rErr    *rTypes.Error
+	}{
+		{name: "error", entries: mocks.NilEntries, rErr: hErrors.ErrDatabaseError},
+		{name: "empty", entries: &types.AddressBookEntries{Entries: []types.AddressBookEntry{}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			blockRepo := &mocks.MockBlockRepository{}
+			blockRepo.On("RetrieveLatest").Return(block4, mocks.NilError)
+			addressBookEntryRepo := &mocks.MockAddressBookEntryRepository{}
+			addressBookEntryRepo.On("Entries").Return(tt.entries, tt.rErr)
+			stream := newTestRecordStreamWithAddressBook(t, newTestBucketStub(t), blockRepo, addressBookEntryRepo)
+
+			// when
+			stream.poll(context.Background())
+
+			// then
+			assert.Empty(t, stream.getBlocks())
+		})
+	}
+}
+
+func TestRecordStreamPollBackendError(t *testing.T) {
+	// given
+	blockRepo := &mocks.MockBlockRepository{}
+	blockRepo.On("RetrieveLatest").Return(mocks.NilBlock, hErrors.ErrDatabaseError)
+	stub := newTestBucketStub(t)
+	stream := newTestRecordStream(t, stub, blockRepo)
+
+	// when
+	stream.poll(context.Background())
+
+	// then
+	assert.Empty(t, stream.getBlocks())
+	assert.Empty(t, stub.getRequests())
+}
+
+func TestRecordStreamRun(t *testing.T) {
+	// given
+	blockRepo := &mocks.MockBlockRepository{}
+	blockRepo.On("RetrieveLatest").Return(block4, mocks.NilError)
+	stream := newTestRecordStream(t, newTestBucketStub(t), blockRepo)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// when
+	go stream.run(ctx)
+
+	// then
+	assert.Eventually(t, func() bool { return stream.latest(block4) != nil }, time.Second, time.Millisecond)
+	assert.Equal(t, block6Hash, stream.latest(block4).Hash)
+}
+
+func TestRecordStreamFind(t *testing.T) {
+	// given
+	blockRepo := &mocks.MockBlockRepository{}
+	blockRepo.On("RetrieveLatest").Return(block4, mocks.NilError)
+	stream := newTestRecordStream(t, newTestBucketStub(t), blockRepo)
+	stream.poll(context.Background())
+
+	// when, then
+	assert.Equal(t, int64(5), stream.findByHash("0x"+block5Hash).Index)
+	assert.Nil(t, stream.findByHash(block4Hash))
+	assert.Equal(t, block6Hash, stream.findByIndex(6).Hash)
+	assert.Nil(t, stream.findByIndex(7))
+	assert.NotNil(t, stream.findBetween(block5Start, block6Start-1))
+	assert.Nil(t, stream.findBetween(block5Start, block5Start-1))
+	assert.Nil(t, stream.findBetween(block4Start, block5Start-1))
+	assert.Nil(t, stream.latest(&types.Block{Index: 6}))
+}
+
+func TestParseRecordFileTimestamp(t *testing.T) {
+	tests := []struct {
+		key       string
+		timestamp int64
+		ok        bool
+	}{
+		{key: testPrefix + block5Name, timestamp: block5Start, ok: true},
+		{key: testPrefix + "2021-01-11T22_09_24.063739000Z.rcd", timestamp: 1610402964063739000, ok: true},
+		{key: testPrefix + "2022-07-13T08_46_08.041986003Z.rcd_sig"},
+		{key: testPrefix + "sidecar/"},
+		{key: testPrefix + "invalid.rcd"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			timestamp, ok := parseRecordFileTimestamp(tt.key)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.timestamp, timestamp)
+		})
+	}
+}
+
+func TestToRecordFileName(t *testing.T) {
+	assert.Equal(t, "2022-07-13T08_46_08.041986003Z", toRecordFileName(block5Start))
+	assert.Equal(t, "2022-07-13T08_46_06.000000000Z", toRecordFileName(block4Start))
+}
+
+func TestRecordStreamPollChainBroken(t *testing.T) {
+	// given
+	blockRepo := &mocks.MockBlockRepository{}
+	otherBlock4 := *block4
+	otherBlock4.Hash = "0x0123"
+	blockRepo.On("RetrieveLatest").Return(&otherBlock4, mocks.NilError)
+	stream := newTestRecordStream(t, newTestBucketStub(t), blockRepo)
+
+	// when
+	stream.poll(context.Background())
+
+	// then
+	assert.Empty(t, stream.getBlocks())
+}
+
+// newTestPolledRecordStream returns the record stream polled once with blocks 5 and 6 ahead of block 4 of the backend
+func newTestPolledRecordStream(t *testing.T) *recordStream {
+	blockRepo := &mocks.MockBlockRepository{}
+	blockRepo.On("RetrieveLatest").Return(block4, mocks.NilError)
+	stream := newTestRecordStream(t, newTestBucketStub(t), blockRepo)
+	stream.poll(context.Background())
+	require.Len(t, stream.getBlocks(), 2)
+	return stream
+}
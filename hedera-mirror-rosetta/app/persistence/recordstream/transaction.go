@@ -0,0 +1,115 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package recordstream
+
+import (
+	"context"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	hErrors "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/tools"
+)
+
+// transactionRepository serves the transactions of the blocks read from the record stream, and delegates the rest to
+// the transaction repository of the backend. A block read from the record stream is identified by its consensus start
+type transactionRepository struct {
+	interfaces.TransactionRepository
+	stream *recordStream
+}
+
+func (tr *transactionRepository) CountBetween(ctx context.Context, start, end int64) (int64, int64, *rTypes.Error) {
+	block := tr.stream.findBetween(start, end)
+	if block == nil {
+		return tr.TransactionRepository.CountBetween(ctx, start, end)
+	}
+
+	var operationCount int64
+	for _, transaction := range block.transactions {
+		operationCount += int64(len(transaction.Operations))
+	}
+
+	return int64(len(block.transactions)), operationCount, nil
+}
+
+func (tr *transactionRepository) CountOperationsBetween(ctx context.Context, start, end int64) (
+	int64,
+	*rTypes.Error,
+) {
+	block := tr.stream.findBetween(start, end)
+	if block == nil {
+		return tr.TransactionRepository.CountOperationsBetween(ctx, start, end)
+	}
+
+	var operationCount int64
+	for _, transaction := range block.transactions {
+		operationCount += int64(len(transaction.Operations))
+	}
+
+	return operationCount, nil
+}
+
+func (tr *transactionRepository) FindBetween(ctx context.Context, start, end int64) (
+	[]*types.Transaction,
+	*rTypes.Error,
+) {
+	block := tr.stream.findBetween(start, end)
+	if block == nil {
+		return tr.TransactionRepository.FindBetween(ctx, start, end)
+	}
+
+	return block.transactions, nil
+}
+
+func (tr *transactionRepository) FindHashesBetween(ctx context.Context, start, end int64) ([]string, *rTypes.Error) {
+	block := tr.stream.findBetween(start, end)
+	if block == nil {
+		return tr.TransactionRepository.FindHashesBetween(ctx, start, end)
+	}
+
+	hashes := make([]string, 0, len(block.transactions))
+	for _, transaction := range block.transactions {
+		hashes = append(hashes, transaction.Hash)
+	}
+
+	return hashes, nil
+}
+
+func (tr *transactionRepository) FindByHashInBlock(
+	ctx context.Context,
+	hash string,
+	consensusStart int64,
+	consensusEnd int64,
+) (*types.Transaction, *rTypes.Error) {
+	block := tr.stream.findBetween(consensusStart, consensusEnd)
+	if block == nil {
+		return tr.TransactionRepository.FindByHashInBlock(ctx, hash, consensusStart, consensusEnd)
+	}
+
+	hash = tools.SafeAddHexPrefix(hash)
+	for _, transaction := range block.transactions {
+		if transaction.Hash == hash {
+			return transaction, nil
+		}
+	}
+
+	return nil, hErrors.ErrTransactionNotFound
+}
@@ -0,0 +1,169 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package recordstream
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	hErrors "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const block6TransactionHash = "0x3af126ef15641b785e3edb5018209959fc6c6b063deaa55a3331c5489cdbe31be4163671ecf6672d12" +
+	"aa855d4b3f8e38"
+
+func newTestTransactionRepository(t *testing.T) (*transactionRepository, *mocks.MockTransactionRepository) {
+	backendTransactionRepo := &mocks.MockTransactionRepository{}
+	return &transactionRepository{TransactionRepository: backendTransactionRepo, stream: newTestPolledRecordStream(t)},
+		backendTransactionRepo
+}
+
+func TestTransactionRepositoryCountBetween(t *testing.T) {
+	// given
+	repo, _ := newTestTransactionRepository(t)
+	block6 := repo.stream.findByIndex(6)
+
+	// when
+	transactionCount, operationCount, rErr := repo.CountBetween(context.Background(), block6.ConsensusStartNanos,
+		block6.ConsensusEndNanos)
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Equal(t, int64(2), transactionCount)
+	assert.Equal(t, int64(3), operationCount)
+}
+
+func TestTransactionRepositoryCountBetweenFromBackend(t *testing.T) {
+	// given
+	repo, backendTransactionRepo := newTestTransactionRepository(t)
+	backendTransactionRepo.On("CountBetween").Return(int64(5), int64(10), mocks.NilError)
+
+	// when
+	transactionCount, operationCount, rErr := repo.CountBetween(context.Background(), block4.ConsensusStartNanos,
+		block4.ConsensusEndNanos)
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Equal(t, int64(5), transactionCount)
+	assert.Equal(t, int64(10), operationCount)
+}
+
+func TestTransactionRepositoryFindBetween(t *testing.T) {
+	// given
+	repo, _ := newTestTransactionRepository(t)
+	block6 := repo.stream.findByIndex(6)
+
+	// when
+	transactions, rErr := repo.FindBetween(context.Background(), block6.ConsensusStartNanos, block6.ConsensusEndNanos)
+
+	// then
+	assert.Nil(t, rErr)
+	require.Len(t, transactions, 2)
+	assert.Equal(t, block6TransactionHash, transactions[0].Hash)
+}
+
+func TestTransactionRepositoryFindBetweenFromBackend(t *testing.T) {
+	// given
+	repo, backendTransactionRepo := newTestTransactionRepository(t)
+	expected := []*types.Transaction{{Hash: "0x0123"}}
+	backendTransactionRepo.On("FindBetween").Return(expected, mocks.NilError)
+
+	// when
+	transactions, rErr := repo.FindBetween(context.Background(), block4.ConsensusStartNanos, block4.ConsensusEndNanos)
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Equal(t, expected, transactions)
+}
+
+func TestTransactionRepositoryCountOperationsBetween(t *testing.T) {
+	// given
+	repo, _ := newTestTransactionRepository(t)
+	block6 := repo.stream.findByIndex(6)
+
+	// when
+	operationCount, rErr := repo.CountOperationsBetween(context.Background(), block6.ConsensusStartNanos,
+		block6.ConsensusEndNanos)
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Equal(t, int64(3), operationCount)
+}
+
+func TestTransactionRepositoryFindHashesBetween(t *testing.T) {
+	// given
+	repo, _ := newTestTransactionRepository(t)
+	block6 := repo.stream.findByIndex(6)
+
+	// when
+	hashes, rErr := repo.FindHashesBetween(context.Background(), block6.ConsensusStartNanos, block6.ConsensusEndNanos)
+
+	// then
+	assert.Nil(t, rErr)
+	require.Len(t, hashes, 2)
+	assert.Equal(t, block6TransactionHash, hashes[0])
+}
+
+func TestTransactionRepositoryFindByHashInBlock(t *testing.T) {
+	// given
+	repo, _ := newTestTransactionRepository(t)
+	block6 := repo.stream.findByIndex(6)
+
+	// when
+	transaction, rErr := repo.FindByHashInBlock(context.Background(), block6TransactionHash[2:],
+		block6.ConsensusStartNanos, block6.ConsensusEndNanos)
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Equal(t, block6TransactionHash, transaction.Hash)
+	assert.Len(t, transaction.Operations, 3)
+}
+
+func TestTransactionRepositoryFindByHashInBlockNotFound(t *testing.T) {
+	// given
+	repo, _ := newTestTransactionRepository(t)
+	block6 := repo.stream.findByIndex(6)
+
+	// when
+	transaction, rErr := repo.FindByHashInBlock(context.Background(), "0x0123", block6.ConsensusStartNanos,
+		block6.ConsensusEndNanos)
+
+	// then
+	assert.Equal(t, hErrors.ErrTransactionNotFound, rErr)
+	assert.Nil(t, transaction)
+}
+
+func TestTransactionRepositoryFindByHashInBlockFromBackend(t *testing.T) {
+	// given
+	repo, backendTransactionRepo := newTestTransactionRepository(t)
+	backendTransactionRepo.On("FindByHashInBlock").Return(mocks.NilTransaction, hErrors.ErrTransactionNotFound)
+
+	// when
+	transaction, rErr := repo.FindByHashInBlock(context.Background(), block6TransactionHash, block4.ConsensusStartNanos,
+		block4.ConsensusEndNanos)
+
+	// then
+	assert.Equal(t, hErrors.ErrTransactionNotFound, rErr)
+	assert.Nil(t, transaction)
+}
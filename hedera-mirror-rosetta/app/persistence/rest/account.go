@@ -0,0 +1,320 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package rest
+
+import (
+	"context"
+	"encoding/base32"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	hErrors "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/tools"
+	"github.com/hashgraph/hedera-protobufs-go/services"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	keyTypeEcdsaSecp256k1  = "ECDSA_SECP256K1"
+	keyTypeEd25519         = "ED25519"
+	keyTypeProtobufEncoded = "ProtobufEncoded"
+)
+
+// accountRepository reads the accounts and their balances from the /accounts endpoint of the REST API. The balance
+// snapshots, the alias history, and the bulk balance queries aren't supported
+type accountRepository struct {
+	client          *client
+	tokenRepository interfaces.TokenRepository
+}
+
+func newAccountRepository(client *client, tokenRepository interfaces.TokenRepository) interfaces.AccountRepository {
+	return &accountRepository{client: client, tokenRepository: tokenRepository}
+}
+
+func (ar *accountRepository) FindAccountsByAlias(_ context.Context, _, _ []byte) (types.AliasAccounts, *rTypes.Error) {
+	return nil, hErrors.ErrNotImplemented
+}
+
+func (ar *accountRepository) GetAccountAlias(ctx context.Context, accountId types.AccountId) (
+	zero types.AccountId,
+	_ *rTypes.Error,
+) {
+	account, rErr := ar.getAccount(ctx, accountId, nil)
+	if rErr != nil {
+		if rErr == hErrors.ErrAccountNotFound {
+			return accountId, nil
+		}
+		return zero, rErr
+	}
+
+	entity, err := account.toEntity()
+	if err != nil {
+		log.Errorf("Invalid account %s: %s", account.Account, err)
+		return zero, hErrors.ErrInternalServerError
+	}
+
+	if len(entity.Alias) == 0 && entity.Type != domain.EntityTypeContract {
+		return accountId, nil
+	}
+
+	if accountAlias, err := types.NewAccountIdFromEntity(entity); err == nil {
+		return accountAlias, nil
+	}
+
+	return zero, hErrors.ErrInternalServerError
+}
+
+// GetAccountLifecycle returns nil since the REST API doesn't expose the hollow account lifecycle
+func (ar *accountRepository) GetAccountLifecycle(_ context.Context, _ types.AccountId) (
+	*types.AccountLifecycle,
+	*rTypes.Error,
+) {
+	return nil, nil
+}
+
+func (ar *accountRepository) GetAccountInfo(ctx context.Context, accountId types.AccountId) (
+	*types.AccountInfo,
+	*rTypes.Error,
+) {
+	account, rErr := ar.getAccount(ctx, accountId, nil)
+	if rErr != nil {
+		if rErr == hErrors.ErrAccountNotFound {
+			return nil, nil
+		}
+		return nil, rErr
+	}
+
+	if account.Deleted {
+		return nil, nil
+	}
+
+	entity, err := account.toEntity()
+	if err != nil {
+		log.Errorf("Invalid account %s: %s", account.Account, err)
+		return nil, hErrors.ErrInternalServerError
+	}
+
+	return &types.AccountInfo{
+		AutoRenewPeriod:     entity.AutoRenewPeriod,
+		ExpirationTimestamp: entity.ExpirationTimestamp,
+		Id:                  entity.Id,
+		Key:                 entity.Key,
+	}, nil
+}
+
+func (ar *accountRepository) GetAccountId(ctx context.Context, accountId types.AccountId) (
+	zero types.AccountId,
+	_ *rTypes.Error,
+) {
+	if !accountId.HasAlias() {
+		return accountId, nil
+	}
+
+	account, rErr := ar.getAccount(ctx, accountId, nil)
+	if rErr != nil {
+		return zero, rErr
+	}
+
+	id, err := domain.EntityIdFromString(account.Account)
+	if err != nil {
+		log.Errorf("Invalid account %s: %s", account.Account, err)
+		return zero, hErrors.ErrInternalServerError
+	}
+
+	return types.NewAccountIdFromEntityId(id), nil
+}
+
+// RetrieveBalanceAtBlock returns the hbar balance and the token balances of the account at the timestamp as computed
+// by the REST API. The nft balances are the number of nfts owned without the serial numbers. The key is read from the
+// same response, so it's the key as of the timestamp rather than the current key
+func (ar *accountRepository) RetrieveBalanceAtBlock(
+	ctx context.Context,
+	accountId types.AccountId,
+	consensusEnd int64,
+) (types.AmountSlice, string, []byte, *rTypes.Error) {
+	query := url.Values{"timestamp": {"lte:" + formatTimestamp(consensusEnd)}, "transactions": {"false"}}
+	account, rErr := ar.getAccount(ctx, accountId, query)
+	if rErr != nil {
+		if rErr == hErrors.ErrAccountNotFound && !accountId.HasAlias() {
+			// same as the database backend, an account which doesn't exist has a zero balance
+			return types.AmountSlice{&types.HbarAmount{}}, "", nil, nil
+		}
+		return nil, "", nil, rErr
+	}
+
+	var key []byte
+	if !account.Deleted {
+		var err error
+		if key, err = account.Key.toProtobuf(); err != nil {
+			log.Errorf("Invalid key of account %s: %s", account.Account, err)
+			return nil, "", nil, hErrors.ErrInternalServerError
+		}
+	}
+
+	amounts := types.AmountSlice{&types.HbarAmount{}}
+	if account.Balance == nil {
+		return amounts, account.Account, key, nil
+	}
+
+	amounts[0] = &types.HbarAmount{Value: account.Balance.Balance}
+	for _, tokenBalance := range account.Balance.Tokens {
+		tokenId, err := domain.EntityIdFromString(tokenBalance.TokenId)
+		if err != nil {
+			log.Errorf("Invalid token balance of account %s: %s", account.Account, err)
+			return nil, "", nil, hErrors.ErrInternalServerError
+		}
+
+		token, rErr := ar.tokenRepository.Find(ctx, tokenId.EncodedId)
+		if rErr != nil {
+			return nil, "", nil, rErr
+		}
+		amounts = append(amounts, types.NewTokenAmount(token.Token, tokenBalance.Balance))
+	}
+
+	return amounts, account.Account, key, nil
+}
+
+func (ar *accountRepository) RetrieveBalanceReconciliation(_ context.Context, _, _ int64, _ int) (
+	*types.BalanceReconciliation,
+	*rTypes.Error,
+) {
+	return nil, hErrors.ErrNotImplemented
+}
+
+func (ar *accountRepository) RetrieveAllBalancesAtBlock(_ context.Context, _ int64) (
+	map[int64]types.AmountSlice,
+	*rTypes.Error,
+) {
+	return nil, hErrors.ErrNotImplemented
+}
+
+func (ar *accountRepository) RetrieveGenesisAccounts(_ context.Context, _, _ int64) ([]int64, *rTypes.Error) {
+	return nil, hErrors.ErrNotImplemented
+}
+
+func (ar *accountRepository) RetrieveHbarBalancesAtBlock(_ context.Context, _ []int64, _ int64) (
+	map[int64]types.HbarAmount,
+	*rTypes.Error,
+) {
+	return nil, hErrors.ErrNotImplemented
+}
+
+func (ar *accountRepository) RetrieveTokenBalancesAtBlock(_ context.Context, _ int64, _ []int64, _ int64) (
+	types.AmountSlice,
+	*rTypes.Error,
+) {
+	return nil, hErrors.ErrNotImplemented
+}
+
+// getAccount gets the account by its id or alias. The account is never cached since its balance and key change
+func (ar *accountRepository) getAccount(ctx context.Context, accountId types.AccountId, query url.Values) (
+	*restAccount,
+	*rTypes.Error,
+) {
+	var account restAccount
+	if rErr := ar.client.get(ctx, "/accounts/"+toAccountPathParam(accountId), query, false, &account); rErr != nil {
+		if rErr == errNotFound {
+			return nil, hErrors.ErrAccountNotFound
+		}
+		return nil, rErr
+	}
+
+	return &account, nil
+}
+
+// toAccountPathParam returns the path param of the account in the REST API, shard.realm.num for an account without
+// alias, otherwise shard.realm.alias with the alias in base32
+func toAccountPathParam(accountId types.AccountId) string {
+	sdkAccountId := accountId.ToSdkAccountId()
+	if !accountId.HasAlias() {
+		return fmt.Sprintf("%d.%d.%d", sdkAccountId.Shard, sdkAccountId.Realm, sdkAccountId.Account)
+	}
+
+	alias := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(accountId.GetAlias())
+	return fmt.Sprintf("%d.%d.%s", sdkAccountId.Shard, sdkAccountId.Realm, alias)
+}
+
+// toEntity converts the account to the entity with the fields the account repository needs
+func (a restAccount) toEntity() (domain.Entity, error) {
+	id, err := domain.EntityIdFromString(a.Account)
+	if err != nil {
+		return domain.Entity{}, err
+	}
+
+	entity := domain.Entity{
+		AutoRenewPeriod: a.AutoRenewPeriod,
+		Deleted:         &a.Deleted,
+		Id:              id,
+		Type:            domain.EntityTypeAccount,
+	}
+
+	if a.Alias != nil && *a.Alias != "" {
+		if entity.Alias, err = base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(*a.Alias); err != nil {
+			return domain.Entity{}, err
+		}
+	}
+
+	if a.ExpiryTimestamp != nil && *a.ExpiryTimestamp != "" {
+		expirationTimestamp, err := parseTimestamp(*a.ExpiryTimestamp)
+		if err != nil {
+			return domain.Entity{}, err
+		}
+		entity.ExpirationTimestamp = &expirationTimestamp
+	}
+
+	if entity.Key, err = a.Key.toProtobuf(); err != nil {
+		return domain.Entity{}, err
+	}
+
+	return entity, nil
+}
+
+// toProtobuf returns the protobuf-encoded key, or an empty key if nil
+func (k *restKey) toProtobuf() ([]byte, error) {
+	if k == nil || k.Key == "" {
+		return []byte{}, nil
+	}
+
+	keyBytes, err := hex.DecodeString(tools.SafeRemoveHexPrefix(k.Key))
+	if err != nil {
+		return nil, err
+	}
+
+	var key services.Key
+	switch k.Type {
+	case keyTypeProtobufEncoded:
+		return keyBytes, nil
+	case keyTypeEd25519:
+		key.Key = &services.Key_Ed25519{Ed25519: keyBytes}
+	case keyTypeEcdsaSecp256k1:
+		key.Key = &services.Key_ECDSASecp256K1{ECDSASecp256K1: keyBytes}
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", k.Type)
+	}
+
+	return proto.Marshal(&key)
+}
@@ -0,0 +1,213 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package rest
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	hErrors "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	accountAlias       = "CIQAVKHCCBSMMHVLQ3RKTQLEKZNU46U2IFDBA3QKNTIDVDBZLIIQ5EQ"
+	accountAliasHex    = "12200aa8e21064c61eab86e2a9c164565b4e7a9a4146106e0a6cd03a8c395a110e92"
+	accountEd25519Key  = "0aa8e21064c61eab86e2a9c164565b4e7a9a4146106e0a6cd03a8c395a110e92"
+	accountWithKeyJson = `{"account":"0.0.1001","alias":null,"auto_renew_period":7776000,` +
+		`"balance":{"balance":100,"timestamp":"1.500000000","tokens":[{"token_id":"0.0.2000","balance":50}]},` +
+		`"deleted":false,"evm_address":null,"expiry_timestamp":"2.000000000",` +
+		`"key":{"_type":"ED25519","key":"` + accountEd25519Key + `"}}`
+	aliasAccountJson = `{"account":"0.0.1003","alias":"` + accountAlias + `","deleted":false,"key":null}`
+)
+
+func newAccountApiStub() *restApiStub {
+	return &restApiStub{responses: map[string]string{
+		"/api/v1/accounts/0.0.1001": accountWithKeyJson,
+		"/api/v1/accounts/0.0.1001?timestamp=lte%3A1.500000000&transactions=false": accountWithKeyJson,
+		"/api/v1/accounts/0.0." + accountAlias:                                     aliasAccountJson,
+		"/api/v1/accounts/0.0.1003":                                                aliasAccountJson,
+		"/api/v1/tokens/0.0.2000":                                                  tokenJson,
+	}}
+}
+
+func newTestAccountRepository(t *testing.T, stub *restApiStub) *accountRepository {
+	client := newTestClient(t, stub)
+	return newAccountRepository(client, newTokenRepository(client)).(*accountRepository)
+}
+
+func newAliasAccountId(t *testing.T) types.AccountId {
+	alias, err := hex.DecodeString(accountAliasHex)
+	require.NoError(t, err)
+	accountId, err := types.NewAccountIdFromAlias(alias, 0, 0)
+	require.NoError(t, err)
+	return accountId
+}
+
+func TestAccountRepositoryRetrieveBalanceAtBlock(t *testing.T) {
+	// given
+	repo := newTestAccountRepository(t, newAccountApiStub())
+	accountId := types.NewAccountIdFromEntityId(domain.MustDecodeEntityId(1001))
+
+	// when
+	amounts, entityId, key, rErr := repo.RetrieveBalanceAtBlock(context.Background(), accountId, 1_500_000_000)
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Equal(t, "0.0.1001", entityId)
+	assert.Equal(t, accountAliasHex, hex.EncodeToString(key))
+	assert.Equal(t, types.AmountSlice{
+		&types.HbarAmount{Value: 100},
+		&types.TokenAmount{
+			Decimals: 2,
+			TokenId:  domain.MustDecodeEntityId(2000),
+			Type:     domain.TokenTypeFungibleCommon,
+			Value:    50,
+		},
+	}, amounts)
+}
+
+func TestAccountRepositoryRetrieveBalanceAtBlockNotFound(t *testing.T) {
+	// given
+	repo := newTestAccountRepository(t, newAccountApiStub())
+	accountId := types.NewAccountIdFromEntityId(domain.MustDecodeEntityId(1002))
+
+	// when
+	amounts, entityId, key, rErr := repo.RetrieveBalanceAtBlock(context.Background(), accountId, 1_500_000_000)
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Empty(t, entityId)
+	assert.Empty(t, key)
+	assert.Equal(t, types.AmountSlice{&types.HbarAmount{}}, amounts)
+}
+
+func TestAccountRepositoryGetAccountId(t *testing.T) {
+	// given
+	repo := newTestAccountRepository(t, newAccountApiStub())
+
+	// when
+	actual, rErr := repo.GetAccountId(context.Background(), newAliasAccountId(t))
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Equal(t, types.NewAccountIdFromEntityId(domain.MustDecodeEntityId(1003)), actual)
+}
+
+func TestAccountRepositoryGetAccountAlias(t *testing.T) {
+	// given
+	repo := newTestAccountRepository(t, newAccountApiStub())
+	accountId := types.NewAccountIdFromEntityId(domain.MustDecodeEntityId(1003))
+
+	// when
+	actual, rErr := repo.GetAccountAlias(context.Background(), accountId)
+
+	// then
+	assert.Nil(t, rErr)
+	assert.True(t, actual.HasAlias())
+	assert.Equal(t, int64(1003), actual.GetId())
+}
+
+func TestAccountRepositoryGetAccountAliasWithoutAlias(t *testing.T) {
+	// given
+	repo := newTestAccountRepository(t, newAccountApiStub())
+	accountId := types.NewAccountIdFromEntityId(domain.MustDecodeEntityId(1001))
+
+	// when
+	actual, rErr := repo.GetAccountAlias(context.Background(), accountId)
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Equal(t, accountId, actual)
+}
+
+func TestAccountRepositoryGetAccountInfo(t *testing.T) {
+	// given
+	repo := newTestAccountRepository(t, newAccountApiStub())
+	accountId := types.NewAccountIdFromEntityId(domain.MustDecodeEntityId(1001))
+
+	// when
+	actual, rErr := repo.GetAccountInfo(context.Background(), accountId)
+
+	// then
+	require.Nil(t, rErr)
+	assert.Equal(t, int64(7776000), *actual.AutoRenewPeriod)
+	assert.Equal(t, int64(2_000_000_000), *actual.ExpirationTimestamp)
+	assert.Equal(t, domain.MustDecodeEntityId(1001), actual.Id)
+	assert.Equal(t, accountAliasHex, hex.EncodeToString(actual.Key))
+}
+
+func TestAccountRepositoryNotImplemented(t *testing.T) {
+	// given
+	repo := newTestAccountRepository(t, newAccountApiStub())
+	ctx := context.Background()
+
+	// when
+	_, findErr := repo.FindAccountsByAlias(ctx, nil, nil)
+	_, allBalancesErr := repo.RetrieveAllBalancesAtBlock(ctx, 1)
+	_, reconciliationErr := repo.RetrieveBalanceReconciliation(ctx, 1001, 1, 1)
+	_, genesisErr := repo.RetrieveGenesisAccounts(ctx, 1, 2)
+	_, hbarBalancesErr := repo.RetrieveHbarBalancesAtBlock(ctx, []int64{1001}, 1)
+	_, tokenBalancesErr := repo.RetrieveTokenBalancesAtBlock(ctx, 1001, []int64{2000}, 1)
+
+	// then
+	for _, rErr := range []interface{}{
+		findErr,
+		allBalancesErr,
+		reconciliationErr,
+		genesisErr,
+		hbarBalancesErr,
+		tokenBalancesErr,
+	} {
+		assert.Equal(t, hErrors.ErrNotImplemented, rErr)
+	}
+}
+
+func TestRestKeyToProtobuf(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		key      *restKey
+		expected string
+		err      bool
+	}{
+		{name: "nil", key: nil, expected: ""},
+		{name: "ed25519", key: &restKey{Key: accountEd25519Key, Type: keyTypeEd25519}, expected: accountAliasHex},
+		{name: "protobuf", key: &restKey{Key: accountAliasHex, Type: keyTypeProtobufEncoded}, expected: accountAliasHex},
+		{name: "unknown type", key: &restKey{Key: accountEd25519Key, Type: "RSA_3072"}, err: true},
+		{name: "invalid hex", key: &restKey{Key: "0xzz", Type: keyTypeEd25519}, err: true},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := tc.key.toProtobuf()
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, hex.EncodeToString(actual))
+		})
+	}
+}
@@ -0,0 +1,116 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package rest
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	hErrors "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/tools"
+	log "github.com/sirupsen/logrus"
+)
+
+// addressBookEntryRepository reads the current address book from the /network/nodes endpoint of the REST API
+type addressBookEntryRepository struct {
+	client *client
+}
+
+func newAddressBookEntryRepository(client *client) interfaces.AddressBookEntryRepository {
+	return &addressBookEntryRepository{client: client}
+}
+
+func (aber *addressBookEntryRepository) Entries(ctx context.Context) (*types.AddressBookEntries, *rTypes.Error) {
+	entries := make([]types.AddressBookEntry, 0)
+	var response restNodes
+	rErr := aber.client.get(ctx, "/network/nodes", url.Values{"limit": {"25"}, "order": {"asc"}}, false, &response)
+	for {
+		if rErr == errNotFound {
+			// same as the database backend, there are no entries until the address book is imported
+			break
+		} else if rErr != nil {
+			return nil, rErr
+		}
+
+		for _, node := range response.Nodes {
+			entry, err := node.toAddressBookEntry()
+			if err != nil {
+				log.Errorf("Invalid node %d in the address book: %s", node.NodeId, err)
+				return nil, hErrors.ErrInternalServerError
+			}
+			entries = append(entries, entry)
+		}
+
+		if response.Links.Next == nil {
+			break
+		}
+
+		next := *response.Links.Next
+		response = restNodes{}
+		rErr = aber.client.getResource(ctx, next, false, &response)
+	}
+
+	return &types.AddressBookEntries{Entries: entries}, nil
+}
+
+// toAddressBookEntry converts the node to the address book entry. The REST API serves the cert hash as the hex of the
+// hex string stored in the address book, and the endpoints are sorted the same way as the database backend
+func (n restNode) toAddressBookEntry() (types.AddressBookEntry, error) {
+	accountId, err := domain.EntityIdFromString(n.NodeAccountId)
+	if err != nil {
+		return types.AddressBookEntry{}, err
+	}
+
+	certHash, err := hex.DecodeString(tools.SafeRemoveHexPrefix(n.NodeCertHash))
+	if err != nil {
+		return types.AddressBookEntry{}, err
+	}
+
+	serviceEndpoints := make([]restServiceEndpoint, len(n.ServiceEndpoints))
+	copy(serviceEndpoints, n.ServiceEndpoints)
+	sort.Slice(serviceEndpoints, func(i, j int) bool {
+		if serviceEndpoints[i].IpAddressV4 != serviceEndpoints[j].IpAddressV4 {
+			return serviceEndpoints[i].IpAddressV4 < serviceEndpoints[j].IpAddressV4
+		}
+		return serviceEndpoints[i].Port < serviceEndpoints[j].Port
+	})
+
+	endpoints := make([]string, 0, len(serviceEndpoints))
+	for _, endpoint := range serviceEndpoints {
+		endpoints = append(endpoints, fmt.Sprintf("%s:%d", endpoint.IpAddressV4, endpoint.Port))
+	}
+
+	return types.AddressBookEntry{
+		NodeId:    n.NodeId,
+		AccountId: accountId,
+		CertHash:  strings.TrimSpace(string(certHash)),
+		Endpoints: endpoints,
+		PublicKey: tools.SafeRemoveHexPrefix(n.PublicKey),
+	}, nil
+}
@@ -0,0 +1,81 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package rest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	nodesUri     = "/api/v1/network/nodes?limit=25&order=asc"
+	nodesNextUri = "/api/v1/network/nodes?limit=25&node.id=gt:0&order=asc"
+)
+
+func TestAddressBookEntryRepositoryEntries(t *testing.T) {
+	// given
+	stub := &restApiStub{responses: map[string]string{
+		nodesUri: `{"nodes":[{"node_account_id":"0.0.3","node_cert_hash":"0x3031",` +
+			`"node_id":0,"public_key":"0x308201a2","service_endpoints":[{"ip_address_v4":"10.0.0.2","port":50211},` +
+			`{"ip_address_v4":"10.0.0.1","port":50212},{"ip_address_v4":"10.0.0.1","port":50211}]}],` +
+			`"links":{"next":"` + nodesNextUri + `"}}`,
+		nodesNextUri: `{"nodes":[{"node_account_id":"0.0.4","node_cert_hash":"0x",` +
+			`"node_id":1,"service_endpoints":[]}],"links":{"next":null}}`,
+	}}
+	repo := newAddressBookEntryRepository(newTestClient(t, stub))
+
+	// when
+	actual, rErr := repo.Entries(context.Background())
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Equal(t, &types.AddressBookEntries{Entries: []types.AddressBookEntry{
+		{
+			NodeId:    0,
+			AccountId: domain.MustDecodeEntityId(3),
+			CertHash:  "01",
+			Endpoints: []string{"10.0.0.1:50211", "10.0.0.1:50212", "10.0.0.2:50211"},
+			PublicKey: "308201a2",
+		},
+		{
+			NodeId:    1,
+			AccountId: domain.MustDecodeEntityId(4),
+			CertHash:  "",
+			Endpoints: []string{},
+		},
+	}}, actual)
+}
+
+func TestAddressBookEntryRepositoryEntriesNotFound(t *testing.T) {
+	// given
+	repo := newAddressBookEntryRepository(newTestClient(t, &restApiStub{}))
+
+	// when
+	actual, rErr := repo.Entries(context.Background())
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Equal(t, &types.AddressBookEntries{Entries: []types.AddressBookEntry{}}, actual)
+}
@@ -0,0 +1,101 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package rest
+
+import (
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+)
+
+// backend is the backend with the repositories reading from the mirror node REST API, for the deployments without
+// database access. The repositories without an equivalent REST API return ErrNotImplemented
+type backend struct {
+	accountRepo          interfaces.AccountRepository
+	addressBookEntryRepo interfaces.AddressBookEntryRepository
+	blockRepo            interfaces.BlockRepository
+	tokenRepo            interfaces.TokenRepository
+	transactionRepo      interfaces.TransactionRepository
+	unsupportedRepo      unsupportedRepository
+}
+
+func (b *backend) AccountRepository() interfaces.AccountRepository {
+	return b.accountRepo
+}
+
+func (b *backend) AddressBookEntryRepository() interfaces.AddressBookEntryRepository {
+	return b.addressBookEntryRepo
+}
+
+func (b *backend) BlockRepository() interfaces.BlockRepository {
+	return b.blockRepo
+}
+
+func (b *backend) FileDataRepository() interfaces.FileDataRepository {
+	return b.unsupportedRepo
+}
+
+// IndexRepository returns nil since the indexes of the mirror node database aren't visible through the REST API
+func (b *backend) IndexRepository() interfaces.IndexRepository {
+	return nil
+}
+
+func (b *backend) ScheduleRepository() interfaces.ScheduleRepository {
+	return b.unsupportedRepo
+}
+
+func (b *backend) SchemaVersionRepository() interfaces.SchemaVersionRepository {
+	return b.unsupportedRepo
+}
+
+func (b *backend) StakingRepository() interfaces.StakingRepository {
+	return b.unsupportedRepo
+}
+
+func (b *backend) TokenRepository() interfaces.TokenRepository {
+	return b.tokenRepo
+}
+
+func (b *backend) TopicMessageRepository() interfaces.TopicMessageRepository {
+	return b.unsupportedRepo
+}
+
+func (b *backend) TransactionRepository() interfaces.TransactionRepository {
+	return b.transactionRepo
+}
+
+// NewBackend creates the backend reading from the mirror node REST API at the configured base url. The repositories
+// share one client, so the response cache and the request rate limit apply to all of them
+func NewBackend(restConfig config.BackendRest, rosettaConfig *config.Config) interfaces.Backend {
+	client := newClient(restConfig)
+	tokenRepo := newTokenRepository(client)
+	return &backend{
+		accountRepo:          newAccountRepository(client, tokenRepo),
+		addressBookEntryRepo: newAddressBookEntryRepository(client),
+		blockRepo:            newBlockRepository(client),
+		tokenRepo:            tokenRepo,
+		transactionRepo: newTransactionRepository(
+			client,
+			tokenRepo,
+			rosettaConfig.SystemAccounts,
+			rosettaConfig.SuppressEmptyOperations,
+		),
+	}
+}
@@ -0,0 +1,311 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package rest
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	hErrors "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/tools"
+	log "github.com/sirupsen/logrus"
+)
+
+// recordBlock is a record file of the REST API with its consensus timestamps parsed. Like the database backend, a block
+// ends right before the next block starts
+type recordBlock struct {
+	ConsensusEnd   int64
+	ConsensusStart int64
+	Hash           string
+	Index          int64
+	PrevHash       string
+}
+
+func (rb recordBlock) toBlock(genesisBlock recordBlock) *types.Block {
+	parentHash := rb.PrevHash
+	parentIndex := rb.Index - 1
+	if rb.Index == genesisBlock.Index {
+		parentHash = rb.Hash
+		parentIndex = rb.Index
+	}
+
+	return &types.Block{
+		ConsensusEndNanos:   rb.ConsensusEnd,
+		ConsensusStartNanos: rb.ConsensusStart,
+		Hash:                rb.Hash,
+		Index:               rb.Index,
+		ParentHash:          parentHash,
+		ParentIndex:         parentIndex,
+	}
+}
+
+// blockRepository reads the blocks from the /blocks endpoints of the REST API. The oldest block served by the REST API
+// is the genesis block
+type blockRepository struct {
+	client *client
+	// genesisBlock holds the genesis recordBlock once it's fetched, it's safe for concurrent access
+	genesisBlock atomic.Value
+}
+
+// newBlockRepository creates the block repository reading from the REST API
+func newBlockRepository(client *client) interfaces.BlockRepository {
+	return &blockRepository{client: client}
+}
+
+func (br *blockRepository) FindByHash(ctx context.Context, hash string) (*types.Block, *rTypes.Error) {
+	if hash == "" {
+		return nil, hErrors.ErrInvalidArgument
+	}
+
+	genesisBlock, rErr := br.initGenesisBlock(ctx)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	return br.findBlock(ctx, tools.SafeAddHexPrefix(hash), genesisBlock)
+}
+
+func (br *blockRepository) FindByIdentifier(ctx context.Context, index int64, hash string) (
+	*types.Block,
+	*rTypes.Error,
+) {
+	if index < 0 || hash == "" {
+		return nil, hErrors.ErrInvalidArgument
+	}
+
+	block, rErr := br.FindByHash(ctx, hash)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	if block.Index != index {
+		return nil, hErrors.ErrBlockNotFound
+	}
+
+	return block, nil
+}
+
+func (br *blockRepository) FindByIndex(ctx context.Context, index int64) (*types.Block, *rTypes.Error) {
+	if index < 0 {
+		return nil, hErrors.ErrInvalidArgument
+	}
+
+	genesisBlock, rErr := br.initGenesisBlock(ctx)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	if index < genesisBlock.Index {
+		return nil, hErrors.ErrBlockNotFound
+	}
+
+	return br.findBlock(ctx, strconv.FormatInt(index, 10), genesisBlock)
+}
+
+func (br *blockRepository) FindByTimestamp(ctx context.Context, timestamp int64) (*types.Block, *rTypes.Error) {
+	if timestamp < 0 {
+		return nil, hErrors.ErrInvalidArgument
+	}
+
+	genesisBlock, rErr := br.initGenesisBlock(ctx)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	if timestamp < genesisBlock.ConsensusStart {
+		return nil, hErrors.ErrBlockNotFound
+	}
+
+	// the first record file ending at or after the timestamp, the timestamp belongs to the record file before it if
+	// it falls between the two record files
+	query := url.Values{"limit": {"1"}, "order": {"asc"}, "timestamp": {"gte:" + formatTimestamp(timestamp)}}
+	next, rErr := br.findFirstBlock(ctx, query, false)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	index := next.Index
+	if next.ConsensusStart > timestamp {
+		index--
+	}
+
+	return br.FindByIndex(ctx, index)
+}
+
+// FindRecordFileByIndex finds the name and the hash of the record file, the REST API doesn't serve the file hash and
+// the raw bytes of a record file
+func (br *blockRepository) FindRecordFileByIndex(ctx context.Context, index int64) (
+	*types.RecordFile,
+	*rTypes.Error,
+) {
+	if index < 0 {
+		return nil, hErrors.ErrInvalidArgument
+	}
+
+	var block restBlock
+	if rErr := br.client.get(ctx, "/blocks/"+strconv.FormatInt(index, 10), nil, true, &block); rErr != nil {
+		if rErr == errNotFound {
+			return nil, hErrors.ErrBlockNotFound
+		}
+		return nil, rErr
+	}
+
+	recordFile := &types.RecordFile{}
+	recordFile.Hash = tools.SafeRemoveHexPrefix(block.Hash)
+	recordFile.Index = block.Number
+	recordFile.Name = block.Name
+	return recordFile, nil
+}
+
+func (br *blockRepository) RetrieveGenesis(ctx context.Context) (*types.Block, *rTypes.Error) {
+	genesisBlock, rErr := br.initGenesisBlock(ctx)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	return genesisBlock.toBlock(genesisBlock), nil
+}
+
+func (br *blockRepository) RetrieveLatest(ctx context.Context) (*types.Block, *rTypes.Error) {
+	genesisBlock, rErr := br.initGenesisBlock(ctx)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	latest, rErr := br.findFirstBlock(ctx, url.Values{"limit": {"1"}, "order": {"desc"}}, false)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	return latest.toBlock(genesisBlock), nil
+}
+
+func (br *blockRepository) RetrieveOldest(ctx context.Context) (*types.Block, *rTypes.Error) {
+	return br.RetrieveGenesis(ctx)
+}
+
+// findBlock finds the block by its hash or index, with the consensus end right before the start of the next block
+func (br *blockRepository) findBlock(ctx context.Context, hashOrIndex string, genesisBlock recordBlock) (
+	*types.Block,
+	*rTypes.Error,
+) {
+	rb, rErr := br.getBlock(ctx, hashOrIndex)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	if rb.Index < genesisBlock.Index {
+		return nil, hErrors.ErrBlockNotFound
+	}
+
+	next, rErr := br.getBlock(ctx, strconv.FormatInt(rb.Index+1, 10))
+	if rErr == nil {
+		rb.ConsensusEnd = next.ConsensusStart - 1
+	} else if rErr != hErrors.ErrBlockNotFound {
+		return nil, rErr
+	}
+
+	return rb.toBlock(genesisBlock), nil
+}
+
+// findFirstBlock returns the first block of the list filtered by the query, with its own consensus end
+func (br *blockRepository) findFirstBlock(ctx context.Context, query url.Values, cacheable bool) (
+	recordBlock,
+	*rTypes.Error,
+) {
+	var response restBlocks
+	if rErr := br.client.get(ctx, "/blocks", query, cacheable, &response); rErr != nil {
+		if rErr == errNotFound {
+			return recordBlock{}, hErrors.ErrBlockNotFound
+		}
+		return recordBlock{}, rErr
+	}
+
+	if len(response.Blocks) == 0 {
+		return recordBlock{}, hErrors.ErrBlockNotFound
+	}
+
+	return toRecordBlock(response.Blocks[0])
+}
+
+// getBlock gets the record file by its hash or index, with its own consensus end
+func (br *blockRepository) getBlock(ctx context.Context, hashOrIndex string) (recordBlock, *rTypes.Error) {
+	var block restBlock
+	if rErr := br.client.get(ctx, "/blocks/"+hashOrIndex, nil, true, &block); rErr != nil {
+		if rErr == errNotFound {
+			return recordBlock{}, hErrors.ErrBlockNotFound
+		}
+		return recordBlock{}, rErr
+	}
+
+	return toRecordBlock(block)
+}
+
+func (br *blockRepository) initGenesisBlock(ctx context.Context) (recordBlock, *rTypes.Error) {
+	if genesisBlock, ok := br.genesisBlock.Load().(recordBlock); ok {
+		return genesisBlock, nil
+	}
+
+	oldest, rErr := br.findFirstBlock(ctx, url.Values{"limit": {"1"}, "order": {"asc"}}, false)
+	if rErr != nil {
+		if rErr == hErrors.ErrBlockNotFound {
+			return recordBlock{}, hErrors.ErrNodeIsStarting
+		}
+		return recordBlock{}, rErr
+	}
+
+	genesisBlock, rErr := br.findBlock(ctx, strconv.FormatInt(oldest.Index, 10), oldest)
+	if rErr != nil {
+		return recordBlock{}, rErr
+	}
+
+	oldest.ConsensusEnd = genesisBlock.ConsensusEndNanos
+	br.genesisBlock.Store(oldest)
+	log.Infof("Fetched genesis block %d from the mirror node REST API", oldest.Index)
+	return oldest, nil
+}
+
+func toRecordBlock(block restBlock) (recordBlock, *rTypes.Error) {
+	consensusStart, err := parseTimestamp(block.Timestamp.From)
+	if err != nil {
+		log.Errorf("Invalid consensus start of block %d: %s", block.Number, err)
+		return recordBlock{}, hErrors.ErrInternalServerError
+	}
+
+	consensusEnd, err := parseTimestamp(block.Timestamp.To)
+	if err != nil {
+		log.Errorf("Invalid consensus end of block %d: %s", block.Number, err)
+		return recordBlock{}, hErrors.ErrInternalServerError
+	}
+
+	return recordBlock{
+		ConsensusEnd:   consensusEnd,
+		ConsensusStart: consensusStart,
+		Hash:           tools.SafeRemoveHexPrefix(block.Hash),
+		Index:          block.Number,
+		PrevHash:       tools.SafeRemoveHexPrefix(block.PreviousHash),
+	}, nil
+}
@@ -0,0 +1,280 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package rest
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	hErrors "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	block5Json = `{"count":2,"hash":"0xa5","name":"1970-01-01T00_00_01.000000000Z.rcd","number":5,` +
+		`"previous_hash":"0x04","timestamp":{"from":"1.000000000","to":"1.500000000"}}`
+	block6Json = `{"count":1,"hash":"0xb6","number":6,"previous_hash":"0xa5",` +
+		`"timestamp":{"from":"2.000000000","to":"2.500000000"}}`
+)
+
+var (
+	block5 = &types.Block{
+		ConsensusEndNanos:   1_999_999_999,
+		ConsensusStartNanos: 1_000_000_000,
+		Hash:                "a5",
+		Index:               5,
+		ParentHash:          "a5",
+		ParentIndex:         5,
+	}
+	block6 = &types.Block{
+		ConsensusEndNanos:   2_500_000_000,
+		ConsensusStartNanos: 2_000_000_000,
+		Hash:                "b6",
+		Index:               6,
+		ParentHash:          "a5",
+		ParentIndex:         5,
+	}
+)
+
+func newBlockApiStub() *restApiStub {
+	return &restApiStub{responses: map[string]string{
+		"/api/v1/blocks?limit=1&order=asc":                             `{"blocks":[` + block5Json + `]}`,
+		"/api/v1/blocks?limit=1&order=desc":                            `{"blocks":[` + block6Json + `]}`,
+		"/api/v1/blocks?limit=1&order=asc&timestamp=gte%3A1.700000000": `{"blocks":[` + block6Json + `]}`,
+		"/api/v1/blocks?limit=1&order=asc&timestamp=gte%3A2.000000000": `{"blocks":[` + block6Json + `]}`,
+		"/api/v1/blocks?limit=1&order=asc&timestamp=gte%3A3.000000000": `{"blocks":[]}`,
+		"/api/v1/blocks/5":    block5Json,
+		"/api/v1/blocks/6":    block6Json,
+		"/api/v1/blocks/0xa5": block5Json,
+		"/api/v1/blocks/0xb6": block6Json,
+	}}
+}
+
+func newTestBlockRepository(t *testing.T, stub *restApiStub) *blockRepository {
+	return newBlockRepository(newTestClient(t, stub)).(*blockRepository)
+}
+
+func TestBlockRepositoryFindByHash(t *testing.T) {
+	// given
+	repo := newTestBlockRepository(t, newBlockApiStub())
+
+	// when
+	actual, rErr := repo.FindByHash(context.Background(), "0xb6")
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Equal(t, block6, actual)
+}
+
+func TestBlockRepositoryFindByHashNotFound(t *testing.T) {
+	// given
+	repo := newTestBlockRepository(t, newBlockApiStub())
+
+	// when
+	actual, rErr := repo.FindByHash(context.Background(), "c7")
+
+	// then
+	assert.Equal(t, hErrors.ErrBlockNotFound, rErr)
+	assert.Nil(t, actual)
+}
+
+func TestBlockRepositoryFindByIdentifier(t *testing.T) {
+	// given
+	repo := newTestBlockRepository(t, newBlockApiStub())
+
+	// when
+	actual, rErr := repo.FindByIdentifier(context.Background(), 5, "a5")
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Equal(t, block5, actual)
+}
+
+func TestBlockRepositoryFindByIdentifierMismatch(t *testing.T) {
+	// given
+	repo := newTestBlockRepository(t, newBlockApiStub())
+
+	// when
+	actual, rErr := repo.FindByIdentifier(context.Background(), 6, "a5")
+
+	// then
+	assert.Equal(t, hErrors.ErrBlockNotFound, rErr)
+	assert.Nil(t, actual)
+}
+
+func TestBlockRepositoryFindByIndex(t *testing.T) {
+	// given
+	repo := newTestBlockRepository(t, newBlockApiStub())
+
+	// when
+	actual, rErr := repo.FindByIndex(context.Background(), 5)
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Equal(t, block5, actual)
+}
+
+func TestBlockRepositoryFindByIndexBeforeGenesis(t *testing.T) {
+	// given
+	repo := newTestBlockRepository(t, newBlockApiStub())
+
+	// when
+	actual, rErr := repo.FindByIndex(context.Background(), 4)
+
+	// then
+	assert.Equal(t, hErrors.ErrBlockNotFound, rErr)
+	assert.Nil(t, actual)
+}
+
+func TestBlockRepositoryFindByIndexInvalid(t *testing.T) {
+	// given
+	repo := newTestBlockRepository(t, newBlockApiStub())
+
+	// when
+	actual, rErr := repo.FindByIndex(context.Background(), -1)
+
+	// then
+	assert.Equal(t, hErrors.ErrInvalidArgument, rErr)
+	assert.Nil(t, actual)
+}
+
+func TestBlockRepositoryFindRecordFileByIndex(t *testing.T) {
+	// given
+	repo := newTestBlockRepository(t, newBlockApiStub())
+	expected := &types.RecordFile{}
+	expected.Hash = "a5"
+	expected.Index = 5
+	expected.Name = "1970-01-01T00_00_01.000000000Z.rcd"
+
+	// when
+	actual, rErr := repo.FindRecordFileByIndex(context.Background(), 5)
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Equal(t, expected, actual)
+}
+
+func TestBlockRepositoryFindRecordFileByIndexNotFound(t *testing.T) {
+	// given
+	repo := newTestBlockRepository(t, newBlockApiStub())
+
+	// when
+	actual, rErr := repo.FindRecordFileByIndex(context.Background(), 7)
+
+	// then
+	assert.Equal(t, hErrors.ErrBlockNotFound, rErr)
+	assert.Nil(t, actual)
+}
+
+func TestBlockRepositoryFindRecordFileByIndexInvalid(t *testing.T) {
+	// given
+	repo := newTestBlockRepository(t, newBlockApiStub())
+
+	// when
+	actual, rErr := repo.FindRecordFileByIndex(context.Background(), -1)
+
+	// then
+	assert.Equal(t, hErrors.ErrInvalidArgument, rErr)
+	assert.Nil(t, actual)
+}
+
+func TestBlockRepositoryFindByTimestamp(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		timestamp int64
+		expected  *types.Block
+		rErr      *rTypes.Error
+	}{
+		{name: "between blocks", timestamp: 1_700_000_000, expected: block5},
+		{name: "block start", timestamp: 2_000_000_000, expected: block6},
+		{name: "before genesis", timestamp: 999_999_999, rErr: hErrors.ErrBlockNotFound},
+		{name: "after latest", timestamp: 3_000_000_000, rErr: hErrors.ErrBlockNotFound},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			repo := newTestBlockRepository(t, newBlockApiStub())
+
+			// when
+			actual, rErr := repo.FindByTimestamp(context.Background(), tc.timestamp)
+
+			// then
+			assert.Equal(t, tc.rErr, rErr)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestBlockRepositoryRetrieveGenesis(t *testing.T) {
+	// given
+	stub := newBlockApiStub()
+	repo := newTestBlockRepository(t, stub)
+
+	// when
+	actual, rErr := repo.RetrieveGenesis(context.Background())
+	_, _ = repo.RetrieveGenesis(context.Background())
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Equal(t, block5, actual)
+	// the oldest block, the genesis block, and the block after it, then the genesis block is kept
+	assert.Equal(t, int32(3), atomic.LoadInt32(&stub.requests))
+}
+
+func TestBlockRepositoryRetrieveGenesisNoBlocks(t *testing.T) {
+	// given
+	stub := &restApiStub{responses: map[string]string{"/api/v1/blocks?limit=1&order=asc": `{"blocks":[]}`}}
+	repo := newTestBlockRepository(t, stub)
+
+	// when
+	actual, rErr := repo.RetrieveGenesis(context.Background())
+
+	// then
+	assert.Equal(t, hErrors.ErrNodeIsStarting, rErr)
+	assert.Nil(t, actual)
+}
+
+func TestBlockRepositoryRetrieveLatest(t *testing.T) {
+	// given
+	repo := newTestBlockRepository(t, newBlockApiStub())
+
+	// when
+	actual, rErr := repo.RetrieveLatest(context.Background())
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Equal(t, block6, actual)
+}
+
+func TestBlockRepositoryRetrieveOldest(t *testing.T) {
+	// given
+	repo := newTestBlockRepository(t, newBlockApiStub())
+
+	// when
+	actual, rErr := repo.RetrieveOldest(context.Background())
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Equal(t, block5, actual)
+}
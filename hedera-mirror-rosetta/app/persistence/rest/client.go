@@ -0,0 +1,275 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Code-Hex/go-generics-cache"
+	"github.com/Code-Hex/go-generics-cache/policy/lru"
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	hErrors "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	apiPrefix          = "/api/v1"
+	defaultBackoff     = 500 * time.Millisecond
+	defaultMaxAttempts = 1
+	defaultTimeout     = 10 * time.Second
+	// maxResponseSize bounds the size of a response body read into memory
+	maxResponseSize = 16 * 1024 * 1024
+)
+
+// errNotFound is returned by the client when the REST API responds with 404, the repositories translate it to the
+// rosetta not found error of the resource
+var errNotFound = &rTypes.Error{Message: "Not found"}
+
+// client gets the resources from the mirror node REST API. The responses are cached, the requests are throttled to the
+// configured rate, and the requests rate limited with 429, failed with 5xx, or a network error are retried with the
+// backoff or after the Retry-After duration
+type client struct {
+	backoff     time.Duration
+	baseUrl     string
+	cache       *cache.Cache[string, []byte]
+	cacheTtl    time.Duration
+	httpClient  *http.Client
+	maxAttempts int
+	throttle    *throttle
+}
+
+func newClient(restConfig config.BackendRest) *client {
+	backoff := restConfig.Backoff
+	if backoff <= 0 {
+		backoff = defaultBackoff
+	}
+
+	maxAttempts := restConfig.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = defaultMaxAttempts
+	}
+
+	timeout := restConfig.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	var responseCache *cache.Cache[string, []byte]
+	if restConfig.CacheMaxSize > 0 {
+		responseCache = cache.New(cache.AsLRU[string, []byte](lru.WithCapacity(restConfig.CacheMaxSize)))
+	}
+
+	return &client{
+		backoff:     backoff,
+		baseUrl:     strings.TrimSuffix(restConfig.BaseUrl, "/"),
+		cache:       responseCache,
+		cacheTtl:    restConfig.CacheTtl,
+		httpClient:  &http.Client{Timeout: timeout},
+		maxAttempts: maxAttempts,
+		throttle:    newThrottle(restConfig.RequestsPerSecond),
+	}
+}
+
+// get gets the resource at the path with the query params and decodes it into result. The response is only cached if
+// cacheable, i.e., the resource doesn't change once it exists. errNotFound is returned if the resource doesn't exist
+func (c *client) get(
+	ctx context.Context,
+	path string,
+	query url.Values,
+	cacheable bool,
+	result interface{},
+) *rTypes.Error {
+	resource := apiPrefix + path
+	if len(query) != 0 {
+		resource += "?" + query.Encode()
+	}
+
+	return c.getResource(ctx, resource, cacheable, result)
+}
+
+// getResource gets the resource, which is the path with the encoded query params, e.g., the next link of a page
+func (c *client) getResource(ctx context.Context, resource string, cacheable bool, result interface{}) *rTypes.Error {
+	body, ok := c.getCached(resource)
+	if !ok {
+		var rErr *rTypes.Error
+		if body, rErr = c.fetch(ctx, resource); rErr != nil {
+			return rErr
+		}
+
+		if cacheable && c.cache != nil {
+			c.cache.Set(resource, body, cache.WithExpiration(c.cacheTtl))
+		}
+	}
+
+	if err := json.Unmarshal(body, result); err != nil {
+		log.Errorf("Failed to decode the response of %s: %s", resource, err)
+		return hErrors.ErrDatabaseError
+	}
+
+	return nil
+}
+
+func (c *client) getCached(resource string) ([]byte, bool) {
+	if c.cache == nil {
+		return nil, false
+	}
+
+	return c.cache.Get(resource)
+}
+
+// fetch sends the request of the resource until it succeeds, the resource isn't found, it fails with a non-retryable
+// status, or the max attempts are reached
+func (c *client) fetch(ctx context.Context, resource string) ([]byte, *rTypes.Error) {
+	backoff := c.backoff
+	for attempt := 1; ; attempt++ {
+		if err := c.throttle.wait(ctx); err != nil {
+			return nil, hErrors.ErrEndpointTimeout
+		}
+
+		body, retryAfter, err := c.fetchOnce(ctx, resource)
+		if err == nil {
+			return body, nil
+		}
+
+		if err == errNotFound {
+			return nil, errNotFound
+		}
+
+		if retryAfter < 0 || attempt >= c.maxAttempts {
+			log.Errorf("Failed to get %s from the mirror node REST API after %d attempts: %s", resource, attempt,
+				err.Message)
+			return nil, hErrors.ErrDatabaseError
+		}
+
+		if retryAfter == 0 {
+			retryAfter = backoff
+			backoff *= 2
+		}
+
+		log.Warnf("Retrying %s in %s: %s", resource, retryAfter, err.Message)
+		timer := time.NewTimer(retryAfter)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, hErrors.ErrEndpointTimeout
+		case <-timer.C:
+		}
+	}
+}
+
+// fetchOnce sends the request of the resource once. On failure, it returns the duration to wait before a retry, 0 to
+// use the backoff, or negative if the request shouldn't be retried
+func (c *client) fetchOnce(ctx context.Context, resource string) ([]byte, time.Duration, *rTypes.Error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseUrl+resource, nil)
+	if err != nil {
+		return nil, -1, &rTypes.Error{Message: err.Error()}
+	}
+	request.Header.Set("Accept", "application/json")
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, -1, &rTypes.Error{Message: ctx.Err().Error()}
+		}
+		return nil, 0, &rTypes.Error{Message: err.Error()}
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxResponseSize))
+	switch {
+	case response.StatusCode == http.StatusNotFound:
+		return nil, -1, errNotFound
+	case response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= http.StatusInternalServerError:
+		message := fmt.Sprintf("status %d", response.StatusCode)
+		return nil, parseRetryAfter(response.Header.Get("Retry-After")), &rTypes.Error{Message: message}
+	case response.StatusCode != http.StatusOK:
+		return nil, -1, &rTypes.Error{Message: fmt.Sprintf("status %d: %s", response.StatusCode, body)}
+	case err != nil:
+		return nil, 0, &rTypes.Error{Message: err.Error()}
+	}
+
+	return body, 0, nil
+}
+
+// parseRetryAfter returns the duration of the Retry-After header in seconds, or 0 if absent or not in seconds
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
+// throttle spaces the requests evenly to stay under the configured rate
+type throttle struct {
+	interval time.Duration
+	mutex    sync.Mutex
+	next     time.Time
+}
+
+// newThrottle creates the throttle of the rate, or returns nil if the rate is unlimited
+func newThrottle(requestsPerSecond float64) *throttle {
+	if requestsPerSecond <= 0 {
+		return nil
+	}
+
+	return &throttle{interval: time.Duration(float64(time.Second) / requestsPerSecond)}
+}
+
+// wait waits for the turn of the request, or returns the error of the context if it's done first
+func (t *throttle) wait(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+
+	t.mutex.Lock()
+	now := time.Now()
+	if t.next.Before(now) {
+		t.next = now
+	}
+	delay := t.next.Sub(now)
+	t.next = t.next.Add(t.interval)
+	t.mutex.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,255 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	hErrors "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// restApiStub serves the canned responses keyed by the request uri, and 404 for any other uri
+type restApiStub struct {
+	requests  int32
+	responses map[string]string
+}
+
+func (s *restApiStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	atomic.AddInt32(&s.requests, 1)
+	response, ok := s.responses[r.URL.RequestURI()]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"_status":{"messages":[{"message":"Not found"}]}}`))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(response))
+}
+
+func newTestClient(t *testing.T, handler http.Handler) *client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return newClient(config.BackendRest{
+		Backoff:      time.Millisecond,
+		BaseUrl:      server.URL + "/",
+		CacheMaxSize: 100,
+		CacheTtl:     time.Minute,
+		MaxAttempts:  3,
+	})
+}
+
+func TestClientGet(t *testing.T) {
+	// given
+	stub := &restApiStub{responses: map[string]string{"/api/v1/blocks/1": `{"number":1}`}}
+	client := newTestClient(t, stub)
+
+	// when
+	var block restBlock
+	rErr := client.get(context.Background(), "/blocks/1", nil, false, &block)
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Equal(t, int64(1), block.Number)
+}
+
+func TestClientGetCached(t *testing.T) {
+	// given
+	stub := &restApiStub{responses: map[string]string{"/api/v1/blocks/1": `{"number":1}`}}
+	client := newTestClient(t, stub)
+
+	// when
+	for i := 0; i < 3; i++ {
+		var block restBlock
+		require.Nil(t, client.get(context.Background(), "/blocks/1", nil, true, &block))
+	}
+
+	// then
+	assert.Equal(t, int32(1), atomic.LoadInt32(&stub.requests))
+}
+
+func TestClientGetNotCacheable(t *testing.T) {
+	// given
+	stub := &restApiStub{responses: map[string]string{"/api/v1/blocks/1": `{"number":1}`}}
+	client := newTestClient(t, stub)
+
+	// when
+	for i := 0; i < 3; i++ {
+		var block restBlock
+		require.Nil(t, client.get(context.Background(), "/blocks/1", nil, false, &block))
+	}
+
+	// then
+	assert.Equal(t, int32(3), atomic.LoadInt32(&stub.requests))
+}
+
+func TestClientGetNotFound(t *testing.T) {
+	// given
+	stub := &restApiStub{}
+	client := newTestClient(t, stub)
+
+	// when
+	var block restBlock
+	rErr := client.get(context.Background(), "/blocks/1", nil, true, &block)
+
+	// then
+	assert.Equal(t, errNotFound, rErr)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&stub.requests))
+}
+
+func TestClientGetRetry(t *testing.T) {
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusServiceUnavailable} {
+		status := status
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			// given
+			var requests int32
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&requests, 1) < 3 {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(status)
+					return
+				}
+				_, _ = w.Write([]byte(`{"number":1}`))
+			}))
+
+			// when
+			var block restBlock
+			rErr := client.get(context.Background(), "/blocks/1", nil, false, &block)
+
+			// then
+			assert.Nil(t, rErr)
+			assert.Equal(t, int64(1), block.Number)
+			assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+		})
+	}
+}
+
+func TestClientGetRetryExhausted(t *testing.T) {
+	// given
+	var requests int32
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+
+	// when
+	var block restBlock
+	rErr := client.get(context.Background(), "/blocks/1", nil, false, &block)
+
+	// then
+	assert.Equal(t, hErrors.ErrDatabaseError, rErr)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+}
+
+func TestClientGetBadRequest(t *testing.T) {
+	// given
+	var requests int32
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+
+	// when
+	var block restBlock
+	rErr := client.get(context.Background(), "/blocks/1", nil, false, &block)
+
+	// then
+	assert.Equal(t, hErrors.ErrDatabaseError, rErr)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
+
+func TestClientGetInvalidResponse(t *testing.T) {
+	// given
+	stub := &restApiStub{responses: map[string]string{"/api/v1/blocks/1": `{"number":`}}
+	client := newTestClient(t, stub)
+
+	// when
+	var block restBlock
+	rErr := client.get(context.Background(), "/blocks/1", nil, true, &block)
+
+	// then
+	assert.Equal(t, hErrors.ErrDatabaseError, rErr)
+}
+
+func TestClientGetContextDone(t *testing.T) {
+	// given
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// when
+	var block restBlock
+	rErr := client.get(ctx, "/blocks/1", nil, false, &block)
+
+	// then
+	assert.Equal(t, hErrors.ErrEndpointTimeout, rErr)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	assert.Equal(t, 2*time.Second, parseRetryAfter("2"))
+	assert.Equal(t, time.Duration(0), parseRetryAfter(""))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("-1"))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("Wed, 21 Oct 2015 07:28:00 GMT"))
+}
+
+func TestThrottle(t *testing.T) {
+	// given
+	throttle := newThrottle(100)
+	start := time.Now()
+
+	// when
+	for i := 0; i < 5; i++ {
+		require.NoError(t, throttle.wait(context.Background()))
+	}
+
+	// then
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+}
+
+func TestThrottleUnlimited(t *testing.T) {
+	assert.Nil(t, newThrottle(0))
+	assert.NoError(t, (*throttle)(nil).wait(context.Background()))
+}
+
+func TestThrottleContextDone(t *testing.T) {
+	// given
+	throttle := newThrottle(0.1)
+	require.NoError(t, throttle.wait(context.Background()))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// when
+	err := throttle.wait(ctx)
+
+	// then
+	assert.Error(t, err)
+}
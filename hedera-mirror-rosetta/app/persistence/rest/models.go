@@ -0,0 +1,204 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package rest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// links are the pagination links of a REST API list response
+type links struct {
+	Next *string `json:"next"`
+}
+
+// restInt is an integer the REST API serializes either as a json number or as a json string, e.g., the token decimals
+type restInt int64
+
+func (i *restInt) UnmarshalJSON(data []byte) error {
+	value := strings.Trim(string(data), `"`)
+	if value == "" || value == "null" {
+		*i = 0
+		return nil
+	}
+
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	*i = restInt(parsed)
+	return nil
+}
+
+type restBalance struct {
+	Balance   int64              `json:"balance"`
+	Timestamp string             `json:"timestamp"`
+	Tokens    []restTokenBalance `json:"tokens"`
+}
+
+type restTokenBalance struct {
+	Balance int64  `json:"balance"`
+	TokenId string `json:"token_id"`
+}
+
+type restKey struct {
+	Key  string `json:"key"`
+	Type string `json:"_type"`
+}
+
+type restAccount struct {
+	Account         string       `json:"account"`
+	Alias           *string      `json:"alias"`
+	AutoRenewPeriod *int64       `json:"auto_renew_period"`
+	Balance         *restBalance `json:"balance"`
+	Deleted         bool         `json:"deleted"`
+	EvmAddress      *string      `json:"evm_address"`
+	ExpiryTimestamp *string      `json:"expiry_timestamp"`
+	Key             *restKey     `json:"key"`
+}
+
+type restBlockTimestamp struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type restBlock struct {
+	Count        int64              `json:"count"`
+	Hash         string             `json:"hash"`
+	Name         string             `json:"name"`
+	Number       int64              `json:"number"`
+	PreviousHash string             `json:"previous_hash"`
+	Timestamp    restBlockTimestamp `json:"timestamp"`
+}
+
+type restBlocks struct {
+	Blocks []restBlock `json:"blocks"`
+	Links  links       `json:"links"`
+}
+
+type restServiceEndpoint struct {
+	IpAddressV4 string `json:"ip_address_v4"`
+	Port        int32  `json:"port"`
+}
+
+type restNode struct {
+	NodeAccountId    string                `json:"node_account_id"`
+	NodeCertHash     string                `json:"node_cert_hash"`
+	NodeId           int64                 `json:"node_id"`
+	PublicKey        string                `json:"public_key"`
+	ServiceEndpoints []restServiceEndpoint `json:"service_endpoints"`
+}
+
+type restNodes struct {
+	Links links      `json:"links"`
+	Nodes []restNode `json:"nodes"`
+}
+
+type restToken struct {
+	CreatedTimestamp  string  `json:"created_timestamp"`
+	Decimals          restInt `json:"decimals"`
+	FreezeDefault     bool    `json:"freeze_default"`
+	InitialSupply     restInt `json:"initial_supply"`
+	MaxSupply         restInt `json:"max_supply"`
+	ModifiedTimestamp string  `json:"modified_timestamp"`
+	Name              string  `json:"name"`
+	SupplyType        string  `json:"supply_type"`
+	Symbol            string  `json:"symbol"`
+	TokenId           string  `json:"token_id"`
+	TotalSupply       restInt `json:"total_supply"`
+	TreasuryAccountId string  `json:"treasury_account_id"`
+	Type              string  `json:"type"`
+}
+
+type restTransfer struct {
+	Account    string `json:"account"`
+	Amount     int64  `json:"amount"`
+	IsApproval bool   `json:"is_approval"`
+}
+
+type restTokenTransfer struct {
+	Account    string `json:"account"`
+	Amount     int64  `json:"amount"`
+	IsApproval bool   `json:"is_approval"`
+	TokenId    string `json:"token_id"`
+}
+
+type restNftTransfer struct {
+	IsApproval        bool    `json:"is_approval"`
+	ReceiverAccountId *string `json:"receiver_account_id"`
+	SenderAccountId   *string `json:"sender_account_id"`
+	SerialNumber      int64   `json:"serial_number"`
+	TokenId           string  `json:"token_id"`
+}
+
+type restTransaction struct {
+	ChargedTxFee           int64               `json:"charged_tx_fee"`
+	ConsensusTimestamp     string              `json:"consensus_timestamp"`
+	EntityId               *string             `json:"entity_id"`
+	Name                   string              `json:"name"`
+	NftTransfers           []restNftTransfer   `json:"nft_transfers"`
+	Node                   *string             `json:"node"`
+	Result                 string              `json:"result"`
+	Scheduled              bool                `json:"scheduled"`
+	StakingRewardTransfers []restTransfer      `json:"staking_reward_transfers"`
+	TokenTransfers         []restTokenTransfer `json:"token_transfers"`
+	TransactionHash        string              `json:"transaction_hash"`
+	TransactionId          string              `json:"transaction_id"`
+	Transfers              []restTransfer      `json:"transfers"`
+}
+
+type restTransactions struct {
+	Links        links             `json:"links"`
+	Transactions []restTransaction `json:"transactions"`
+}
+
+// parseTimestamp parses the seconds.nanoseconds timestamp of the REST API into nanoseconds since the epoch
+func parseTimestamp(timestamp string) (int64, error) {
+	parts := strings.SplitN(timestamp, ".", 2)
+	seconds, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, errors.Errorf("Invalid timestamp %s", timestamp)
+	}
+
+	var nanos int64
+	if len(parts) == 2 {
+		fraction := parts[1]
+		if len(fraction) == 0 || len(fraction) > 9 {
+			return 0, errors.Errorf("Invalid timestamp %s", timestamp)
+		}
+
+		fraction += strings.Repeat("0", 9-len(fraction))
+		if nanos, err = strconv.ParseInt(fraction, 10, 64); err != nil {
+			return 0, errors.Errorf("Invalid timestamp %s", timestamp)
+		}
+	}
+
+	return seconds*1_000_000_000 + nanos, nil
+}
+
+// formatTimestamp formats the nanoseconds since the epoch as the seconds.nanoseconds timestamp of the REST API
+func formatTimestamp(timestamp int64) string {
+	return fmt.Sprintf("%d.%09d", timestamp/1_000_000_000, timestamp%1_000_000_000)
+}
@@ -0,0 +1,132 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package rest
+
+import (
+	"context"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	hErrors "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	log "github.com/sirupsen/logrus"
+)
+
+// tokenRepository reads the tokens from the /tokens endpoint of the REST API. The nfts, the holders, and the pending
+// airdrops aren't supported
+type tokenRepository struct {
+	client *client
+}
+
+func newTokenRepository(client *client) interfaces.TokenRepository {
+	return &tokenRepository{client: client}
+}
+
+func (tr *tokenRepository) Find(ctx context.Context, tokenId int64) (*types.Token, *rTypes.Error) {
+	entityId, err := domain.DecodeEntityId(tokenId)
+	if err != nil {
+		return nil, hErrors.ErrInvalidToken
+	}
+
+	var token restToken
+	// the immutable fields such as the decimals and the type are the ones used, so the token is cacheable
+	if rErr := tr.client.get(ctx, "/tokens/"+entityId.String(), nil, true, &token); rErr != nil {
+		if rErr == errNotFound {
+			return nil, hErrors.ErrTokenNotFound
+		}
+		return nil, rErr
+	}
+
+	domainToken, err := token.toDomain()
+	if err != nil {
+		log.Errorf("Invalid token %s: %s", entityId.String(), err)
+		return nil, hErrors.ErrInternalServerError
+	}
+
+	return &types.Token{Token: domainToken}, nil
+}
+
+func (tr *tokenRepository) FindNft(_ context.Context, _, _ int64) (*types.Nft, *rTypes.Error) {
+	return nil, hErrors.ErrNotImplemented
+}
+
+func (tr *tokenRepository) FindNfts(_ context.Context, _, _ int64, _ int) ([]types.Nft, *rTypes.Error) {
+	return nil, hErrors.ErrNotImplemented
+}
+
+func (tr *tokenRepository) FindPendingAirdrops(_ context.Context, _ int64, _ int) (
+	[]types.PendingAirdrop,
+	*rTypes.Error,
+) {
+	return nil, hErrors.ErrNotImplemented
+}
+
+func (tr *tokenRepository) FindHolders(_ context.Context, _, _, _ int64, _ int) (
+	int64,
+	[]types.TokenHolder,
+	*rTypes.Error,
+) {
+	return 0, nil, hErrors.ErrNotImplemented
+}
+
+func (t restToken) toDomain() (domain.Token, error) {
+	tokenId, err := domain.EntityIdFromString(t.TokenId)
+	if err != nil {
+		return domain.Token{}, err
+	}
+
+	var treasuryAccountId domain.EntityId
+	if t.TreasuryAccountId != "" {
+		if treasuryAccountId, err = domain.EntityIdFromString(t.TreasuryAccountId); err != nil {
+			return domain.Token{}, err
+		}
+	}
+
+	var createdTimestamp, modifiedTimestamp int64
+	if t.CreatedTimestamp != "" {
+		if createdTimestamp, err = parseTimestamp(t.CreatedTimestamp); err != nil {
+			return domain.Token{}, err
+		}
+	}
+
+	if t.ModifiedTimestamp != "" {
+		if modifiedTimestamp, err = parseTimestamp(t.ModifiedTimestamp); err != nil {
+			return domain.Token{}, err
+		}
+	}
+
+	return domain.Token{
+		CreatedTimestamp:  createdTimestamp,
+		Decimals:          int64(t.Decimals),
+		FreezeDefault:     t.FreezeDefault,
+		InitialSupply:     int64(t.InitialSupply),
+		MaxSupply:         int64(t.MaxSupply),
+		ModifiedTimestamp: modifiedTimestamp,
+		Name:              t.Name,
+		SupplyType:        t.SupplyType,
+		Symbol:            t.Symbol,
+		TokenId:           tokenId,
+		TotalSupply:       int64(t.TotalSupply),
+		TreasuryAccountId: treasuryAccountId,
+		Type:              t.Type,
+	}, nil
+}
@@ -0,0 +1,88 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package rest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	hErrors "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenRepositoryFind(t *testing.T) {
+	// given
+	stub := &restApiStub{responses: map[string]string{"/api/v1/tokens/0.0.2000": tokenJson}}
+	client := newTestClient(t, stub)
+	repo := newTokenRepository(client)
+
+	// when
+	actual, rErr := repo.Find(context.Background(), 2000)
+
+	// then
+	require.Nil(t, rErr)
+	assert.Equal(t, &types.Token{Token: domain.Token{
+		CreatedTimestamp:  1_000_000_003,
+		Decimals:          2,
+		InitialSupply:     1000,
+		ModifiedTimestamp: 1_000_000_003,
+		Name:              "token",
+		SupplyType:        domain.TokenSupplyTypeInfinite,
+		Symbol:            "TKN",
+		TokenId:           domain.MustDecodeEntityId(2000),
+		TotalSupply:       1000,
+		TreasuryAccountId: domain.MustDecodeEntityId(1001),
+		Type:              domain.TokenTypeFungibleCommon,
+	}}, actual)
+}
+
+func TestTokenRepositoryFindNotFound(t *testing.T) {
+	// given
+	repo := newTokenRepository(newTestClient(t, &restApiStub{}))
+
+	// when
+	actual, rErr := repo.Find(context.Background(), 2000)
+
+	// then
+	assert.Equal(t, hErrors.ErrTokenNotFound, rErr)
+	assert.Nil(t, actual)
+}
+
+func TestTokenRepositoryNotImplemented(t *testing.T) {
+	// given
+	repo := newTokenRepository(newTestClient(t, &restApiStub{}))
+	ctx := context.Background()
+
+	// when
+	_, nftErr := repo.FindNft(ctx, 2000, 1)
+	_, nftsErr := repo.FindNfts(ctx, 2000, 0, 10)
+	_, airdropsErr := repo.FindPendingAirdrops(ctx, 1001, 10)
+	_, _, holdersErr := repo.FindHolders(ctx, 2000, 1, 0, 10)
+
+	// then
+	assert.Equal(t, hErrors.ErrNotImplemented, nftErr)
+	assert.Equal(t, hErrors.ErrNotImplemented, nftsErr)
+	assert.Equal(t, hErrors.ErrNotImplemented, airdropsErr)
+	assert.Equal(t, hErrors.ErrNotImplemented, holdersErr)
+}
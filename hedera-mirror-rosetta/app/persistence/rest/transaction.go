@@ -0,0 +1,590 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package rest
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+	"sort"
+	"strings"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/builder"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	hErrors "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/tools"
+	log "github.com/sirupsen/logrus"
+)
+
+const transactionsPageSize = "100"
+
+var (
+	// successfulResults are the names of the results the database backend treats as successful
+	successfulResults = map[string]bool{
+		"FEE_SCHEDULE_FILE_PART_UPLOADED":        true,
+		"SUCCESS":                                true,
+		"SUCCESS_BUT_MISSING_EXPECTED_OPERATION": true,
+	}
+	transactionResultCodes = reverseMap(types.TransactionResults)
+	transactionTypeCodes   = reverseMap(types.TransactionTypes)
+)
+
+// transactionRepository reads the transactions from the /transactions endpoint of the REST API and builds the
+// operations with the same builders as the database backend. The REST API doesn't expose the transfer list of the
+// transaction body, so the non-fee transfers are derived from the record transfers by taking out the transaction fee
+type transactionRepository struct {
+	client               *client
+	feeAccounts          []domain.EntityId
+	feeBreakdownBuilder  builder.FeeBreakdownBuilder
+	operationBuilder     builder.OperationBuilder
+	stakingRewardAccount *domain.EntityId
+	tokenRepository      interfaces.TokenRepository
+}
+
+func newTransactionRepository(
+	client *client,
+	tokenRepository interfaces.TokenRepository,
+	systemAccounts config.SystemAccounts,
+	suppressEmptyOperations bool,
+) interfaces.TransactionRepository {
+	feeAccounts := make([]domain.EntityId, 0, 3)
+	for _, account := range []string{
+		systemAccounts.FeeCollection,
+		systemAccounts.NodeReward,
+		systemAccounts.StakingReward,
+	} {
+		if accountId, err := parseNullableEntityId(&account); err == nil && accountId != nil {
+			feeAccounts = append(feeAccounts, *accountId)
+		}
+	}
+
+	stakingRewardAccount, _ := parseNullableEntityId(&systemAccounts.StakingReward)
+	return &transactionRepository{
+		client:               client,
+		feeAccounts:          feeAccounts,
+		feeBreakdownBuilder:  builder.NewFeeBreakdownBuilder(systemAccounts),
+		operationBuilder:     builder.NewOperationBuilder(systemAccounts, suppressEmptyOperations),
+		stakingRewardAccount: stakingRewardAccount,
+		tokenRepository:      tokenRepository,
+	}
+}
+
+func (tr *transactionRepository) CountBetween(ctx context.Context, start, end int64) (int64, int64, *rTypes.Error) {
+	transactions, rErr := tr.FindBetween(ctx, start, end)
+	if rErr != nil {
+		return 0, 0, rErr
+	}
+
+	var operationCount int64
+	for _, transaction := range transactions {
+		operationCount += int64(len(transaction.Operations))
+	}
+
+	return int64(len(transactions)), operationCount, nil
+}
+
+func (tr *transactionRepository) CountOperationsBetween(ctx context.Context, start, end int64) (int64, *rTypes.Error) {
+	restTransactions, rErr := tr.listTransactions(ctx, start, end)
+	if rErr != nil {
+		return 0, rErr
+	}
+
+	var operationCount int64
+	for _, transaction := range restTransactions {
+		operationCount += int64(len(transaction.Transfers) + len(transaction.TokenTransfers))
+		for _, nftTransfer := range transaction.NftTransfers {
+			if nftTransfer.ReceiverAccountId != nil {
+				operationCount++
+			}
+			if nftTransfer.SenderAccountId != nil {
+				operationCount++
+			}
+		}
+	}
+
+	return operationCount, nil
+}
+
+func (tr *transactionRepository) CountByTypeAndResult(ctx context.Context, start, end int64) (
+	*types.TransactionStatistics,
+	*rTypes.Error,
+) {
+	restTransactions, rErr := tr.listTransactions(ctx, start, end)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	type typeAndResult struct {
+		result int16
+		txType int16
+	}
+	countMap := make(map[typeAndResult]int64)
+	keys := make([]typeAndResult, 0)
+	for _, transaction := range restTransactions {
+		key := typeAndResult{
+			result: int16(transactionResultCodes[transaction.Result]),
+			txType: int16(transactionTypeCodes[transaction.Name]),
+		}
+		if _, ok := countMap[key]; !ok {
+			keys = append(keys, key)
+		}
+		countMap[key]++
+	}
+
+	counts := make([]types.TransactionTypeCount, 0, len(keys))
+	for _, key := range keys {
+		counts = append(counts, types.TransactionTypeCount{Count: countMap[key], Result: key.result, Type: key.txType})
+	}
+
+	return &types.TransactionStatistics{Counts: counts, End: end, Start: start}, nil
+}
+
+func (tr *transactionRepository) FindBetween(ctx context.Context, start, end int64) (
+	[]*types.Transaction,
+	*rTypes.Error,
+) {
+	restTransactions, rErr := tr.listTransactions(ctx, start, end)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	return tr.constructTransactions(ctx, restTransactions)
+}
+
+// FindKeysBySearch is not supported, the REST API can't list the transactions of an account up to a block with a
+// total count
+func (tr *transactionRepository) FindKeysBySearch(_ context.Context, _ types.TransactionSearch) (
+	[]types.TransactionKey,
+	int64,
+	*rTypes.Error,
+) {
+	return nil, 0, hErrors.ErrNotImplemented
+}
+
+func (tr *transactionRepository) FindHashesBetween(ctx context.Context, start, end int64) ([]string, *rTypes.Error) {
+	restTransactions, rErr := tr.listTransactions(ctx, start, end)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	seen := make(map[string]bool)
+	hashes := make([]string, 0, len(restTransactions))
+	for _, transaction := range restTransactions {
+		hash, err := transaction.getHashString()
+		if err != nil {
+			return nil, hErrors.ErrInternalServerError
+		}
+
+		if !seen[hash] {
+			seen[hash] = true
+			hashes = append(hashes, hash)
+		}
+	}
+
+	return hashes, nil
+}
+
+func (tr *transactionRepository) FindByHashInBlock(
+	ctx context.Context,
+	hashStr string,
+	consensusStart int64,
+	consensusEnd int64,
+) (*types.Transaction, *rTypes.Error) {
+	hash, err := hex.DecodeString(tools.SafeRemoveHexPrefix(hashStr))
+	if err != nil {
+		return nil, hErrors.ErrInvalidTransactionIdentifier
+	}
+
+	restTransactions, rErr := tr.listTransactions(ctx, consensusStart, consensusEnd)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	hashStr = tools.SafeAddHexPrefix(hex.EncodeToString(hash))
+	sameHashTransactions := make([]restTransaction, 0)
+	for _, transaction := range restTransactions {
+		if transactionHash, err := transaction.getHashString(); err == nil && transactionHash == hashStr {
+			sameHashTransactions = append(sameHashTransactions, transaction)
+		}
+	}
+
+	if len(sameHashTransactions) == 0 {
+		return nil, hErrors.ErrTransactionNotFound
+	}
+
+	transactions, rErr := tr.constructTransactions(ctx, sameHashTransactions)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	return transactions[0], nil
+}
+
+// FindRawByHashInBlock isn't supported since the REST API doesn't serve the transactions as stored by the mirror node
+func (tr *transactionRepository) FindRawByHashInBlock(_ context.Context, _ string, _, _ int64) (
+	*types.RawTransaction,
+	*rTypes.Error,
+) {
+	return nil, hErrors.ErrNotImplemented
+}
+
+// listTransactions lists the transactions between the start and end timestamp inclusively, following the next links,
+// in chronological order
+func (tr *transactionRepository) listTransactions(ctx context.Context, start, end int64) (
+	[]restTransaction,
+	*rTypes.Error,
+) {
+	if start > end {
+		return nil, hErrors.ErrStartMustNotBeAfterEnd
+	}
+
+	query := url.Values{
+		"limit":     {transactionsPageSize},
+		"order":     {"asc"},
+		"timestamp": {"gte:" + formatTimestamp(start), "lte:" + formatTimestamp(end)},
+	}
+	// the transactions in a closed timestamp range never change once the record file is imported
+	var response restTransactions
+	rErr := tr.client.get(ctx, "/transactions", query, true, &response)
+	transactions := make([]restTransaction, 0)
+	for {
+		if rErr == errNotFound {
+			break
+		} else if rErr != nil {
+			return nil, rErr
+		}
+
+		transactions = append(transactions, response.Transactions...)
+		if response.Links.Next == nil || len(response.Transactions) == 0 {
+			break
+		}
+
+		next := *response.Links.Next
+		response = restTransactions{}
+		rErr = tr.client.getResource(ctx, next, true, &response)
+	}
+
+	return transactions, nil
+}
+
+// constructTransactions groups the transactions by hash in the order the hashes first appear, and builds the rosetta
+// transaction of each group
+func (tr *transactionRepository) constructTransactions(ctx context.Context, restTransactions []restTransaction) (
+	[]*types.Transaction,
+	*rTypes.Error,
+) {
+	hashes := make([]string, 0)
+	sameHashMap := make(map[string][]restTransaction)
+	for _, transaction := range restTransactions {
+		hash, err := transaction.getHashString()
+		if err != nil {
+			log.Errorf("Invalid hash of transaction %s: %s", transaction.TransactionId, err)
+			return nil, hErrors.ErrInternalServerError
+		}
+
+		if _, ok := sameHashMap[hash]; !ok {
+			hashes = append(hashes, hash)
+		}
+		sameHashMap[hash] = append(sameHashMap[hash], transaction)
+	}
+
+	result := make([]*types.Transaction, 0, len(hashes))
+	for _, hash := range hashes {
+		transaction, rErr := tr.constructTransaction(ctx, hash, sameHashMap[hash])
+		if rErr != nil {
+			return nil, rErr
+		}
+		result = append(result, transaction)
+	}
+
+	return result, nil
+}
+
+func (tr *transactionRepository) constructTransaction(
+	ctx context.Context,
+	hash string,
+	sameHashTransactions []restTransaction,
+) (*types.Transaction, *rTypes.Error) {
+	// the REST API serves the transactions in chronological order, which keeps the operation indices stable
+	tResult := &types.Transaction{Hash: hash}
+	transactions := make([]builder.Transaction, 0, len(sameHashTransactions))
+	for _, restTransaction := range sameHashTransactions {
+		transaction, entityId, rErr := tr.decode(ctx, restTransaction)
+		if rErr != nil {
+			return nil, rErr
+		}
+
+		if successfulResults[restTransaction.Result] {
+			tResult.EntityId = entityId
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	tResult.FeeBreakdown = tr.feeBreakdownBuilder.Build(transactions)
+	tResult.Operations = tr.operationBuilder.Build(transactions)
+	return tResult, nil
+}
+
+// decode converts the transaction of the REST API to the record the operations are built from, and returns its entity
+// id if any
+func (tr *transactionRepository) decode(ctx context.Context, t restTransaction) (
+	builder.Transaction,
+	*domain.EntityId,
+	*rTypes.Error,
+) {
+	transaction := builder.Transaction{
+		ChargedTxFee: t.ChargedTxFee,
+		Result:       transactionResultCodes[t.Result],
+		Scheduled:    t.Scheduled,
+		Type:         transactionTypeCodes[t.Name],
+	}
+
+	invalid := func(field string, err error) (builder.Transaction, *domain.EntityId, *rTypes.Error) {
+		log.Errorf("Invalid %s of transaction %s: %s", field, t.TransactionId, err)
+		return builder.Transaction{}, nil, hErrors.ErrInternalServerError
+	}
+
+	var err error
+	payer := strings.SplitN(t.TransactionId, "-", 2)[0]
+	if transaction.PayerAccountId, err = domain.EntityIdFromString(payer); err != nil {
+		return invalid("payer", err)
+	}
+
+	if transaction.NodeAccountId, err = parseNullableEntityId(t.Node); err != nil {
+		return invalid("node", err)
+	}
+
+	entityId, err := parseNullableEntityId(t.EntityId)
+	if err != nil {
+		return invalid("entity id", err)
+	}
+
+	transaction.CryptoTransfers = make([]builder.HbarTransfer, 0, len(t.Transfers))
+	for _, transfer := range t.Transfers {
+		accountId, err := domain.EntityIdFromString(transfer.Account)
+		if err != nil {
+			return invalid("transfer", err)
+		}
+
+		transaction.CryptoTransfers = append(transaction.CryptoTransfers, builder.HbarTransfer{
+			AccountId:  accountId,
+			Amount:     transfer.Amount,
+			IsApproval: transfer.IsApproval,
+		})
+	}
+
+	if transaction.NonFeeTransfers, err = tr.getNonFeeTransfers(transaction, t.StakingRewardTransfers); err != nil {
+		return invalid("staking reward transfer", err)
+	}
+
+	tokens := make(map[string]*types.Token)
+	getToken := func(tokenId string) (*types.Token, *rTypes.Error) {
+		if token, ok := tokens[tokenId]; ok {
+			return token, nil
+		}
+
+		id, err := domain.EntityIdFromString(tokenId)
+		if err != nil {
+			return nil, hErrors.ErrInvalidToken
+		}
+
+		token, rErr := tr.tokenRepository.Find(ctx, id.EncodedId)
+		if rErr != nil {
+			return nil, rErr
+		}
+		tokens[tokenId] = token
+		return token, nil
+	}
+
+	transaction.TokenTransfers = make([]builder.TokenTransfer, 0, len(t.TokenTransfers))
+	for _, transfer := range t.TokenTransfers {
+		accountId, err := domain.EntityIdFromString(transfer.Account)
+		if err != nil {
+			return invalid("token transfer", err)
+		}
+
+		token, rErr := getToken(transfer.TokenId)
+		if rErr != nil {
+			return builder.Transaction{}, nil, rErr
+		}
+
+		transaction.TokenTransfers = append(transaction.TokenTransfers, builder.TokenTransfer{
+			AccountId:  accountId,
+			Amount:     transfer.Amount,
+			Decimals:   token.Decimals,
+			IsApproval: transfer.IsApproval,
+			TokenId:    token.TokenId,
+			Type:       token.Type,
+		})
+	}
+
+	transaction.NftTransfers = make([]domain.NftTransfer, 0, len(t.NftTransfers))
+	for _, transfer := range t.NftTransfers {
+		tokenId, err := domain.EntityIdFromString(transfer.TokenId)
+		if err != nil {
+			return invalid("nft transfer", err)
+		}
+
+		receiver, err := parseNullableEntityId(transfer.ReceiverAccountId)
+		if err != nil {
+			return invalid("nft transfer", err)
+		}
+
+		sender, err := parseNullableEntityId(transfer.SenderAccountId)
+		if err != nil {
+			return invalid("nft transfer", err)
+		}
+
+		transaction.NftTransfers = append(transaction.NftTransfers, domain.NftTransfer{
+			IsApproval:        transfer.IsApproval,
+			PayerAccountId:    transaction.PayerAccountId,
+			ReceiverAccountId: receiver,
+			SenderAccountId:   sender,
+			SerialNumber:      transfer.SerialNumber,
+			TokenId:           tokenId,
+		})
+	}
+
+	// the token definition is only needed by the token create, delete, and update operations
+	if entityId != nil && isTokenDefinitionType(transaction.Type) {
+		token, rErr := getToken(entityId.String())
+		if rErr != nil && rErr != hErrors.ErrTokenNotFound {
+			return builder.Transaction{}, nil, rErr
+		}
+
+		if token != nil {
+			transaction.Token = token.Token
+		}
+	}
+
+	return transaction, entityId, nil
+}
+
+// getNonFeeTransfers derives the non-fee transfers from the record transfers. The charged transaction fee is added
+// back to the payer and taken out of the credits to the node and the fee accounts, and the staking rewards are taken
+// out of the rewarded accounts and added back to the staking reward account
+func (tr *transactionRepository) getNonFeeTransfers(
+	transaction builder.Transaction,
+	stakingRewardTransfers []restTransfer,
+) ([]builder.HbarTransfer, error) {
+	amounts := make(map[int64]int64)
+	accounts := make(map[int64]domain.EntityId)
+	add := func(accountId domain.EntityId, amount int64) {
+		if _, ok := accounts[accountId.EncodedId]; !ok {
+			accounts[accountId.EncodedId] = accountId
+		}
+		amounts[accountId.EncodedId] += amount
+	}
+
+	for _, transfer := range transaction.CryptoTransfers {
+		add(transfer.AccountId, transfer.Amount)
+	}
+
+	remainingFee := transaction.ChargedTxFee
+	if remainingFee > 0 {
+		add(transaction.PayerAccountId, remainingFee)
+	}
+
+	feeAccounts := tr.feeAccounts
+	if transaction.NodeAccountId != nil {
+		feeAccounts = append([]domain.EntityId{*transaction.NodeAccountId}, feeAccounts...)
+	}
+	for _, accountId := range feeAccounts {
+		if remainingFee <= 0 {
+			break
+		}
+
+		if credit := amounts[accountId.EncodedId]; credit > 0 {
+			fee := credit
+			if fee > remainingFee {
+				fee = remainingFee
+			}
+			add(accountId, -fee)
+			remainingFee -= fee
+		}
+	}
+
+	for _, transfer := range stakingRewardTransfers {
+		accountId, err := domain.EntityIdFromString(transfer.Account)
+		if err != nil {
+			return nil, err
+		}
+
+		add(accountId, -transfer.Amount)
+		if tr.stakingRewardAccount != nil {
+			add(*tr.stakingRewardAccount, transfer.Amount)
+		}
+	}
+
+	nonFeeTransfers := make([]builder.HbarTransfer, 0, len(amounts))
+	for encodedId, amount := range amounts {
+		if amount != 0 {
+			nonFeeTransfers = append(nonFeeTransfers, builder.HbarTransfer{AccountId: accounts[encodedId], Amount: amount})
+		}
+	}
+	sort.Slice(nonFeeTransfers, func(i, j int) bool {
+		return nonFeeTransfers[i].AccountId.EncodedId < nonFeeTransfers[j].AccountId.EncodedId
+	})
+
+	return nonFeeTransfers, nil
+}
+
+// getHashString returns the hex string of the base64 transaction hash with the hex prefix
+func (t restTransaction) getHashString() (string, error) {
+	hash, err := base64.StdEncoding.DecodeString(t.TransactionHash)
+	if err != nil {
+		return "", err
+	}
+
+	return tools.SafeAddHexPrefix(hex.EncodeToString(hash)), nil
+}
+
+func isTokenDefinitionType(transactionType int32) bool {
+	return transactionType == int32(domain.TransactionTypeTokenCreation) ||
+		transactionType == int32(domain.TransactionTypeTokenDeletion) ||
+		transactionType == int32(domain.TransactionTypeTokenUpdate)
+}
+
+func parseNullableEntityId(entityId *string) (*domain.EntityId, error) {
+	if entityId == nil || *entityId == "" {
+		return nil, nil
+	}
+
+	id, err := domain.EntityIdFromString(*entityId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &id, nil
+}
+
+func reverseMap(m map[int32]string) map[string]int32 {
+	reversed := make(map[string]int32, len(m))
+	for key, value := range m {
+		reversed[value] = key
+	}
+	return reversed
+}
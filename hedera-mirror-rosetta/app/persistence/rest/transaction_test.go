@@ -0,0 +1,282 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package rest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/builder"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	hErrors "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// cryptoTransferJson transfers 100 tinybars from 0.0.1001 to 0.0.1002 with a fee of 10 tinybars, 2 to the node and
+	// 8 to the fee collection account, and pays 0.0.1001 a staking reward of 5 tinybars
+	cryptoTransferJson = `{"charged_tx_fee":10,"consensus_timestamp":"1.000000001","entity_id":null,` +
+		`"name":"CRYPTOTRANSFER","node":"0.0.3","result":"SUCCESS","scheduled":false,` +
+		`"staking_reward_transfers":[{"account":"0.0.1001","amount":5}],"token_transfers":[],` +
+		`"transaction_hash":"AQI=","transaction_id":"0.0.1001-1-0",` +
+		`"transfers":[{"account":"0.0.3","amount":2},{"account":"0.0.98","amount":8},` +
+		`{"account":"0.0.800","amount":-5},{"account":"0.0.1001","amount":-105},{"account":"0.0.1002","amount":100}]}`
+	// tokenTransferJson transfers 50 of the fungible token 0.0.2000 from 0.0.1001 to 0.0.1002 and fails
+	tokenTransferJson = `{"charged_tx_fee":10,"consensus_timestamp":"1.000000002","entity_id":null,` +
+		`"name":"CRYPTOTRANSFER","node":"0.0.3","result":"INSUFFICIENT_TOKEN_BALANCE","scheduled":false,` +
+		`"token_transfers":[],"transaction_hash":"AwQ=","transaction_id":"0.0.1001-2-0",` +
+		`"transfers":[{"account":"0.0.3","amount":2},{"account":"0.0.98","amount":8},` +
+		`{"account":"0.0.1001","amount":-10}]}`
+	// tokenCreateJson creates the fungible token 0.0.2000 with 0.0.1001 as the treasury
+	tokenCreateJson = `{"charged_tx_fee":10,"consensus_timestamp":"1.000000003","entity_id":"0.0.2000",` +
+		`"name":"TOKENCREATION","node":"0.0.3","result":"SUCCESS","scheduled":false,` +
+		`"token_transfers":[{"account":"0.0.1001","amount":1000,"token_id":"0.0.2000"}],` +
+		`"transaction_hash":"BQY=","transaction_id":"0.0.1001-3-0",` +
+		`"transfers":[{"account":"0.0.3","amount":2},{"account":"0.0.98","amount":8},` +
+		`{"account":"0.0.1001","amount":-10}]}`
+	tokenJson = `{"created_timestamp":"1.000000003","decimals":"2","initial_supply":"1000","max_supply":"0",` +
+		`"modified_timestamp":"1.000000003","name":"token","supply_type":"INFINITE","symbol":"TKN",` +
+		`"token_id":"0.0.2000","total_supply":"1000","treasury_account_id":"0.0.1001","type":"FUNGIBLE_COMMON"}`
+	transactionsUri = "/api/v1/transactions?limit=100&order=asc&timestamp=gte%3A1.000000000&" +
+		"timestamp=lte%3A1.999999999"
+	transactionsNextUri = "/api/v1/transactions?limit=100&order=asc&timestamp=gt%3A1.000000002&" +
+		"timestamp=lte%3A1.999999999"
+)
+
+var testSystemAccounts = config.SystemAccounts{
+	FeeCollection: "0.0.98",
+	NodeReward:    "0.0.801",
+	StakingReward: "0.0.800",
+	Treasury:      "0.0.2",
+}
+
+func newTransactionApiStub() *restApiStub {
+	return &restApiStub{responses: map[string]string{
+		transactionsUri: `{"transactions":[` + cryptoTransferJson + `,` + tokenTransferJson + `],` +
+			`"links":{"next":"` + transactionsNextUri + `"}}`,
+		transactionsNextUri:       `{"transactions":[` + tokenCreateJson + `],"links":{"next":null}}`,
+		"/api/v1/tokens/0.0.2000": tokenJson,
+	}}
+}
+
+func newTestTransactionRepository(t *testing.T, stub *restApiStub) *transactionRepository {
+	client := newTestClient(t, stub)
+	return newTransactionRepository(client, newTokenRepository(client), testSystemAccounts, false).(*transactionRepository)
+}
+
+func TestTransactionRepositoryFindBetween(t *testing.T) {
+	// given
+	repo := newTestTransactionRepository(t, newTransactionApiStub())
+
+	// when
+	actual, rErr := repo.FindBetween(context.Background(), 1_000_000_000, 1_999_999_999)
+
+	// then
+	require.Nil(t, rErr)
+	require.Len(t, actual, 3)
+	assert.Equal(t, []string{"0x0102", "0x0304", "0x0506"}, []string{actual[0].Hash, actual[1].Hash, actual[2].Hash})
+
+	// the crypto transfer operations of the non-fee transfers then the fee operations of the rest of the transfers, the
+	// fee and the staking reward of the payer are netted in one fee operation
+	operationTypes := make([]string, 0)
+	for _, operation := range actual[0].Operations {
+		operationTypes = append(operationTypes, operation.Type)
+	}
+	assert.Equal(t, []string{
+		types.OperationTypeCryptoTransfer,
+		types.OperationTypeCryptoTransfer,
+		types.OperationTypeFee,
+		types.OperationTypeFee,
+		types.OperationTypeFee,
+		types.OperationTypeFee,
+	}, operationTypes)
+	assert.Nil(t, actual[0].EntityId)
+	assert.NotNil(t, actual[0].FeeBreakdown)
+
+	// a failed transaction only has the fee operations
+	for _, operation := range actual[1].Operations {
+		assert.Equal(t, types.OperationTypeFee, operation.Type)
+	}
+
+	tokenId := domain.MustDecodeEntityId(2000)
+	assert.Equal(t, &tokenId, actual[2].EntityId)
+}
+
+func TestTransactionRepositoryFindBetweenStartAfterEnd(t *testing.T) {
+	// given
+	repo := newTestTransactionRepository(t, newTransactionApiStub())
+
+	// when
+	actual, rErr := repo.FindBetween(context.Background(), 2, 1)
+
+	// then
+	assert.Equal(t, hErrors.ErrStartMustNotBeAfterEnd, rErr)
+	assert.Nil(t, actual)
+}
+
+func TestTransactionRepositoryCountBetween(t *testing.T) {
+	// given
+	repo := newTestTransactionRepository(t, newTransactionApiStub())
+
+	// when
+	transactionCount, operationCount, rErr := repo.CountBetween(context.Background(), 1_000_000_000, 1_999_999_999)
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Equal(t, int64(3), transactionCount)
+	assert.Greater(t, operationCount, int64(3))
+}
+
+func TestTransactionRepositoryCountByTypeAndResult(t *testing.T) {
+	// given
+	repo := newTestTransactionRepository(t, newTransactionApiStub())
+
+	// when
+	actual, rErr := repo.CountByTypeAndResult(context.Background(), 1_000_000_000, 1_999_999_999)
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Equal(t, &types.TransactionStatistics{
+		Counts: []types.TransactionTypeCount{
+			{Count: 1, Result: 22, Type: 14},
+			{Count: 1, Result: 178, Type: 14},
+			{Count: 1, Result: 22, Type: 29},
+		},
+		End:   1_999_999_999,
+		Start: 1_000_000_000,
+	}, actual)
+}
+
+func TestTransactionRepositoryCountOperationsBetween(t *testing.T) {
+	// given
+	repo := newTestTransactionRepository(t, newTransactionApiStub())
+
+	// when
+	actual, rErr := repo.CountOperationsBetween(context.Background(), 1_000_000_000, 1_999_999_999)
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Equal(t, int64(12), actual)
+}
+
+func TestTransactionRepositoryFindKeysBySearch(t *testing.T) {
+	// given
+	repo := newTestTransactionRepository(t, newTransactionApiStub())
+
+	// when
+	actual, count, rErr := repo.FindKeysBySearch(context.Background(), types.TransactionSearch{End: 1_999_999_999})
+
+	// then
+	assert.Equal(t, hErrors.ErrNotImplemented, rErr)
+	assert.Nil(t, actual)
+	assert.Zero(t, count)
+}
+
+func TestTransactionRepositoryFindHashesBetween(t *testing.T) {
+	// given
+	repo := newTestTransactionRepository(t, newTransactionApiStub())
+
+	// when
+	actual, rErr := repo.FindHashesBetween(context.Background(), 1_000_000_000, 1_999_999_999)
+
+	// then
+	assert.Nil(t, rErr)
+	assert.Equal(t, []string{"0x0102", "0x0304", "0x0506"}, actual)
+}
+
+func TestTransactionRepositoryFindByHashInBlock(t *testing.T) {
+	// given
+	repo := newTestTransactionRepository(t, newTransactionApiStub())
+
+	// when
+	actual, rErr := repo.FindByHashInBlock(context.Background(), "0x0506", 1_000_000_000, 1_999_999_999)
+
+	// then
+	require.Nil(t, rErr)
+	assert.Equal(t, "0x0506", actual.Hash)
+}
+
+func TestTransactionRepositoryFindByHashInBlockNotFound(t *testing.T) {
+	// given
+	repo := newTestTransactionRepository(t, newTransactionApiStub())
+
+	// when
+	actual, rErr := repo.FindByHashInBlock(context.Background(), "0x0708", 1_000_000_000, 1_999_999_999)
+
+	// then
+	assert.Equal(t, hErrors.ErrTransactionNotFound, rErr)
+	assert.Nil(t, actual)
+}
+
+func TestTransactionRepositoryFindByHashInBlockInvalidHash(t *testing.T) {
+	// given
+	repo := newTestTransactionRepository(t, newTransactionApiStub())
+
+	// when
+	actual, rErr := repo.FindByHashInBlock(context.Background(), "0xzz", 1_000_000_000, 1_999_999_999)
+
+	// then
+	assert.Equal(t, hErrors.ErrInvalidTransactionIdentifier, rErr)
+	assert.Nil(t, actual)
+}
+
+func TestTransactionRepositoryFindRawByHashInBlock(t *testing.T) {
+	// given
+	repo := newTestTransactionRepository(t, newTransactionApiStub())
+
+	// when
+	actual, rErr := repo.FindRawByHashInBlock(context.Background(), "0x0102", 1_000_000_000, 1_999_999_999)
+
+	// then
+	assert.Equal(t, hErrors.ErrNotImplemented, rErr)
+	assert.Nil(t, actual)
+}
+
+func TestTransactionRepositoryGetNonFeeTransfers(t *testing.T) {
+	// given
+	repo := newTestTransactionRepository(t, newTransactionApiStub())
+	node := domain.MustDecodeEntityId(3)
+	transaction := builder.Transaction{
+		ChargedTxFee: 10,
+		CryptoTransfers: []builder.HbarTransfer{
+			{AccountId: node, Amount: 2},
+			{AccountId: domain.MustDecodeEntityId(98), Amount: 8},
+			{AccountId: domain.MustDecodeEntityId(800), Amount: -5},
+			{AccountId: domain.MustDecodeEntityId(1001), Amount: -105},
+			{AccountId: domain.MustDecodeEntityId(1002), Amount: 100},
+		},
+		NodeAccountId:  &node,
+		PayerAccountId: domain.MustDecodeEntityId(1001),
+	}
+
+	// when
+	actual, err := repo.getNonFeeTransfers(transaction, []restTransfer{{Account: "0.0.1001", Amount: 5}})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []builder.HbarTransfer{
+		{AccountId: domain.MustDecodeEntityId(1001), Amount: -100},
+		{AccountId: domain.MustDecodeEntityId(1002), Amount: 100},
+	}, actual)
+}
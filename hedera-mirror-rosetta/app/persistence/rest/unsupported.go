@@ -0,0 +1,68 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package rest
+
+import (
+	"context"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	hErrors "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+)
+
+// unsupportedRepository implements the repositories without an equivalent in the REST API, every method returns
+// ErrNotImplemented
+type unsupportedRepository struct{}
+
+func (unsupportedRepository) GetLatestContent(_ context.Context, _ int64) ([]byte, *rTypes.Error) {
+	return nil, hErrors.ErrNotImplemented
+}
+
+func (unsupportedRepository) GetLatestTimestamp(_ context.Context, _ int64) (int64, *rTypes.Error) {
+	return 0, hErrors.ErrNotImplemented
+}
+
+func (unsupportedRepository) FindByScheduleId(_ context.Context, _ int64) (*types.Schedule, *rTypes.Error) {
+	return nil, hErrors.ErrNotImplemented
+}
+
+func (unsupportedRepository) RetrieveLatest(_ context.Context) (types.SchemaVersion, *rTypes.Error) {
+	return types.SchemaVersion{}, hErrors.ErrNotImplemented
+}
+
+func (unsupportedRepository) FindEntityStake(_ context.Context, _ int64) (*types.EntityStake, *rTypes.Error) {
+	return nil, hErrors.ErrNotImplemented
+}
+
+func (unsupportedRepository) FindNodeStakes(_ context.Context, _ *int64) (*types.NodeStakes, *rTypes.Error) {
+	return nil, hErrors.ErrNotImplemented
+}
+
+func (unsupportedRepository) FindStakingHistory(_ context.Context, _ int64, _ int) (
+	*types.StakingHistory,
+	*rTypes.Error,
+) {
+	return nil, hErrors.ErrNotImplemented
+}
+
+func (unsupportedRepository) FindBySequenceNumber(_ context.Context, _, _ int64) (*types.TopicMessage, *rTypes.Error) {
+	return nil, hErrors.ErrNotImplemented
+}
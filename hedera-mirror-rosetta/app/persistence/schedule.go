@@ -0,0 +1,65 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const selectScheduleByScheduleId = `select * from schedule where schedule_id = @schedule_id`
+
+// scheduleRepository struct that has connection to the Database
+type scheduleRepository struct {
+	dbClient interfaces.DbClient
+}
+
+func (sr *scheduleRepository) FindByScheduleId(ctx context.Context, scheduleId int64) (
+	*types.Schedule,
+	*rTypes.Error,
+) {
+	schedules := make([]domain.Schedule, 0)
+	if err := sr.dbClient.Query(ctx, "selectScheduleByScheduleId", func(db *gorm.DB) error {
+		return db.Raw(selectScheduleByScheduleId, sql.Named("schedule_id", scheduleId)).Scan(&schedules).Error
+	}); err != nil {
+		log.Errorf(databaseErrorFormat, errors.ErrDatabaseError.Message, err)
+		return nil, errors.ErrDatabaseError
+	}
+
+	if len(schedules) == 0 {
+		return nil, errors.ErrScheduleNotFound
+	}
+
+	return types.NewScheduleFromDomain(schedules[0]), nil
+}
+
+// NewScheduleRepository creates an instance of a scheduleRepository struct
+func NewScheduleRepository(dbClient interfaces.DbClient) interfaces.ScheduleRepository {
+	return &scheduleRepository{dbClient}
+}
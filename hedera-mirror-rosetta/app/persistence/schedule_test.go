@@ -0,0 +1,114 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package persistence
+
+import (
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	scheduleId5001 int64 = 5001
+	scheduleId5002 int64 = 5002
+)
+
+// run the suite
+func TestScheduleRepositorySuite(t *testing.T) {
+	suite.Run(t, new(scheduleRepositorySuite))
+}
+
+type scheduleRepositorySuite struct {
+	integrationTest
+	suite.Suite
+}
+
+func (suite *scheduleRepositorySuite) TestFindByScheduleId() {
+	// given
+	executedTimestamp := int64(300)
+	expirationTime := int64(200)
+	db.CreateDbRecords(
+		dbClient,
+		getSchedule(100, scheduleId5001, &executedTimestamp, &expirationTime, true),
+		getSchedule(101, scheduleId5002, nil, nil, false),
+	)
+	repo := NewScheduleRepository(dbClient)
+	expected := &types.Schedule{
+		ExecutedTimestamp: &executedTimestamp,
+		ExpirationTime:    &expirationTime,
+		ScheduleId:        domain.MustDecodeEntityId(scheduleId5001),
+		WaitForExpiry:     true,
+	}
+
+	// when
+	actual, err := repo.FindByScheduleId(defaultContext, scheduleId5001)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+}
+
+func (suite *scheduleRepositorySuite) TestFindByScheduleIdNotFound() {
+	// given
+	db.CreateDbRecords(dbClient, getSchedule(101, scheduleId5002, nil, nil, false))
+	repo := NewScheduleRepository(dbClient)
+
+	// when
+	actual, err := repo.FindByScheduleId(defaultContext, scheduleId5001)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrScheduleNotFound, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *scheduleRepositorySuite) TestFindByScheduleIdDbConnectionError() {
+	// given
+	repo := NewScheduleRepository(invalidDbClient)
+
+	// when
+	actual, err := repo.FindByScheduleId(defaultContext, scheduleId5001)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func getSchedule(
+	consensusTimestamp, scheduleId int64,
+	executedTimestamp, expirationTime *int64,
+	waitForExpiry bool,
+) *domain.Schedule {
+	return &domain.Schedule{
+		ConsensusTimestamp: consensusTimestamp,
+		CreatorAccountId:   domain.MustDecodeEntityId(1001),
+		ExecutedTimestamp:  executedTimestamp,
+		ExpirationTime:     expirationTime,
+		PayerAccountId:     domain.MustDecodeEntityId(1001),
+		ScheduleId:         domain.MustDecodeEntityId(scheduleId),
+		TransactionBody:    []byte{0x1},
+		WaitForExpiry:      waitForExpiry,
+	}
+}
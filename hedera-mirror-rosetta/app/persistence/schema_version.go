@@ -0,0 +1,79 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package persistence
+
+import (
+	"context"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	hErrors "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// selectAppliedMigrationVersions selects the versions of the successfully applied versioned migrations. Repeatable
+// migrations have no version
+const selectAppliedMigrationVersions = `select version
+                                        from flyway_schema_history
+                                        where success and version is not null`
+
+// schemaVersionRepository struct that has connection to the Database
+type schemaVersionRepository struct {
+	dbClient interfaces.DbClient
+}
+
+// RetrieveLatest returns the max version of the applied migrations. The max is used instead of the last installed
+// since migrations can be applied out of order
+func (sr *schemaVersionRepository) RetrieveLatest(ctx context.Context) (types.SchemaVersion, *rTypes.Error) {
+	versions := make([]string, 0)
+	if err := sr.dbClient.Query(ctx, "selectAppliedMigrationVersions", func(db *gorm.DB) error {
+		return db.Raw(selectAppliedMigrationVersions).Scan(&versions).Error
+	}); err != nil {
+		log.Errorf(databaseErrorFormat, hErrors.ErrDatabaseError.Message, err)
+		return nil, hErrors.ErrDatabaseError
+	}
+
+	var latest types.SchemaVersion
+	for _, version := range versions {
+		schemaVersion, err := types.ParseSchemaVersion(version)
+		if err != nil {
+			log.Warnf("Ignoring migration with unsupported version: %s", err)
+			continue
+		}
+
+		if latest == nil || schemaVersion.Compare(latest) > 0 {
+			latest = schemaVersion
+		}
+	}
+
+	if latest == nil {
+		return nil, hErrors.ErrNodeIsStarting
+	}
+
+	return latest, nil
+}
+
+// NewSchemaVersionRepository creates an instance of a schemaVersionRepository struct
+func NewSchemaVersionRepository(dbClient interfaces.DbClient) interfaces.SchemaVersionRepository {
+	return &schemaVersionRepository{dbClient}
+}
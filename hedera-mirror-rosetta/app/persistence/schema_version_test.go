@@ -0,0 +1,94 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package persistence
+
+import (
+	"io/ioutil"
+	"regexp"
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const migrationPath = "../../../hedera-mirror-importer/src/main/resources/db/migration/v1"
+
+var migrationFilePattern = regexp.MustCompile(`^V([0-9.]+)__.+\.sql$`)
+
+// run the suite
+func TestSchemaVersionRepositorySuite(t *testing.T) {
+	suite.Run(t, new(schemaVersionRepositorySuite))
+}
+
+type schemaVersionRepositorySuite struct {
+	integrationTest
+	suite.Suite
+}
+
+func (suite *schemaVersionRepositorySuite) TestRetrieveLatest() {
+	// given
+	expected := getLatestMigrationFileVersion(suite.T())
+	repo := NewSchemaVersionRepository(dbClient)
+
+	// when
+	actual, err := repo.RetrieveLatest(defaultContext)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+}
+
+func (suite *schemaVersionRepositorySuite) TestRetrieveLatestDbConnectionError() {
+	// given
+	repo := NewSchemaVersionRepository(invalidDbClient)
+
+	// when
+	actual, err := repo.RetrieveLatest(defaultContext)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+// getLatestMigrationFileVersion returns the max version of the sql migration files applied to the test database
+func getLatestMigrationFileVersion(t *testing.T) types.SchemaVersion {
+	files, err := ioutil.ReadDir(migrationPath)
+	require.NoError(t, err)
+
+	var latest types.SchemaVersion
+	for _, file := range files {
+		matches := migrationFilePattern.FindStringSubmatch(file.Name())
+		if matches == nil {
+			continue
+		}
+
+		version := types.MustParseSchemaVersion(matches[1])
+		if latest == nil || version.Compare(latest) > 0 {
+			latest = version
+		}
+	}
+
+	require.NotNil(t, latest)
+	return latest
+}
@@ -0,0 +1,277 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// tinybarsPerHbar is the number of tinybars in one hbar, the node stake reward rate is in tinybars per whole hbar
+	tinybarsPerHbar = 100_000_000
+
+	// selectEntityStake selects the staking settings of the account and the sum of the balance of the accounts staked
+	// to it
+	selectEntityStake = `select
+                           e.balance,
+                           e.decline_reward,
+                           e.id,
+                           e.staked_node_id,
+                           e.stake_period_start,
+                           coalesce((
+                             select sum(s.balance)
+                             from entity s
+                             where s.staked_account_id = e.id and s.id <> e.id and
+                               s.type in ('ACCOUNT', 'CONTRACT') and (s.deleted is null or s.deleted is false)
+                           ), 0) as staked_to_me
+                         from entity e
+                         where e.id = @id and e.type in ('ACCOUNT', 'CONTRACT')`
+	selectStakingAccount = `select balance, decline_reward, id, staked_node_id, stake_period_start
+                            from entity
+                            where id = @id and type in ('ACCOUNT', 'CONTRACT')`
+	// selectNodeStakePeriods selects the reward rate of the node in the most recent staking periods. The node stake of
+	// a period may be recalculated, so only the latest row of each period is selected
+	selectNodeStakePeriods = `select distinct on (epoch_day)
+                                epoch_day, node_id, reward_rate, stake_rewarded, staking_period
+                              from node_stake
+                              where node_id = @node_id
+                              order by epoch_day desc, consensus_timestamp desc
+                              limit @limit`
+	// selectRewardRateSum selects the sum of the reward rate of the node in the completed staking periods after the
+	// stake period start of the account
+	selectRewardRateSum = `select coalesce(sum(reward_rate), 0)
+                           from (
+                             select distinct on (epoch_day) reward_rate
+                             from node_stake
+                             where node_id = @node_id and epoch_day > @stake_period_start
+                             order by epoch_day, consensus_timestamp desc
+                           ) as ns`
+	selectStakingRewards = `select amount, consensus_timestamp
+                            from staking_reward_transfer
+                            where account_id = @account_id
+                            order by consensus_timestamp desc
+                            limit @limit`
+	// selectNodeStakesInPeriod selects the stake of each node in a staking period. The node stake of a period may be
+	// recalculated, so only the latest row of each node is selected
+	selectNodeStakesInPeriod = `select distinct on (node_id) *
+                                from node_stake
+                                where epoch_day = `
+	selectLatestNodeStakes = selectNodeStakesInPeriod + `(select max(epoch_day) from node_stake)
+                                order by node_id, consensus_timestamp desc`
+	selectNodeStakesByEpochDay = selectNodeStakesInPeriod + `@epoch_day
+                                order by node_id, consensus_timestamp desc`
+	selectNetworkStakeByEpochDay = `select *
+                                    from network_stake
+                                    where epoch_day = @epoch_day
+                                    order by consensus_timestamp desc
+                                    limit 1`
+)
+
+type entityStake struct {
+	Balance          *int64
+	DeclineReward    bool
+	Id               domain.EntityId
+	StakedNodeId     *int64
+	StakedToMe       int64
+	StakePeriodStart *int64
+}
+
+// stakingRepository struct that has connection to the Database
+type stakingRepository struct {
+	dbClient interfaces.DbClient
+}
+
+func (sr *stakingRepository) FindStakingHistory(ctx context.Context, accountId int64, limit int) (
+	*types.StakingHistory,
+	*rTypes.Error,
+) {
+	entities := make([]domain.Entity, 0)
+	if err := sr.dbClient.Query(ctx, "selectStakingAccount", func(db *gorm.DB) error {
+		return db.Raw(selectStakingAccount, sql.Named("id", accountId)).Scan(&entities).Error
+	}); err != nil {
+		log.Errorf(databaseErrorFormat, errors.ErrDatabaseError.Message, err)
+		return nil, errors.ErrDatabaseError
+	}
+
+	if len(entities) == 0 {
+		return nil, errors.ErrAccountNotFound
+	}
+
+	entity := entities[0]
+	history := &types.StakingHistory{
+		AccountId:        entity.Id,
+		DeclineReward:    entity.DeclineReward,
+		Periods:          make([]types.StakingPeriod, 0),
+		Rewards:          make([]types.StakingReward, 0),
+		StakedNodeId:     entity.StakedNodeId,
+		StakePeriodStart: entity.StakePeriodStart,
+	}
+
+	if err := sr.dbClient.Query(ctx, "selectStakingRewards", func(db *gorm.DB) error {
+		return db.Raw(
+			selectStakingRewards,
+			sql.Named("account_id", accountId),
+			sql.Named("limit", limit),
+		).Scan(&history.Rewards).Error
+	}); err != nil {
+		log.Errorf(databaseErrorFormat, errors.ErrDatabaseError.Message, err)
+		return nil, errors.ErrDatabaseError
+	}
+
+	if !history.IsStakedToNode() {
+		return history, nil
+	}
+
+	nodeId := *entity.StakedNodeId
+	if err := sr.dbClient.Query(ctx, "selectNodeStakePeriods", func(db *gorm.DB) error {
+		return db.Raw(selectNodeStakePeriods, sql.Named("node_id", nodeId), sql.Named("limit", limit)).
+			Scan(&history.Periods).Error
+	}); err != nil {
+		log.Errorf(databaseErrorFormat, errors.ErrDatabaseError.Message, err)
+		return nil, errors.ErrDatabaseError
+	}
+
+	if entity.DeclineReward || entity.Balance == nil || entity.StakePeriodStart == nil {
+		return history, nil
+	}
+
+	pendingReward, rErr := sr.getPendingReward(ctx, nodeId, *entity.StakePeriodStart, *entity.Balance)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	history.PendingReward = pendingReward
+	return history, nil
+}
+
+func (sr *stakingRepository) FindEntityStake(ctx context.Context, accountId int64) (
+	*types.EntityStake,
+	*rTypes.Error,
+) {
+	stakes := make([]entityStake, 0)
+	if err := sr.dbClient.Query(ctx, "selectEntityStake", func(db *gorm.DB) error {
+		return db.Raw(selectEntityStake, sql.Named("id", accountId)).Scan(&stakes).Error
+	}); err != nil {
+		log.Errorf(databaseErrorFormat, errors.ErrDatabaseError.Message, err)
+		return nil, errors.ErrDatabaseError
+	}
+
+	if len(stakes) == 0 {
+		return nil, errors.ErrAccountNotFound
+	}
+
+	stake := stakes[0]
+	entityStake := &types.EntityStake{
+		AccountId:        stake.Id,
+		DeclineReward:    stake.DeclineReward,
+		StakedNodeId:     stake.StakedNodeId,
+		StakedToMe:       stake.StakedToMe,
+		StakePeriodStart: stake.StakePeriodStart,
+	}
+	if stake.Balance != nil {
+		entityStake.Balance = *stake.Balance
+	}
+
+	if !entityStake.IsStakedToNode() || stake.DeclineReward || stake.Balance == nil || stake.StakePeriodStart == nil {
+		return entityStake, nil
+	}
+
+	pendingReward, rErr := sr.getPendingReward(ctx, *stake.StakedNodeId, *stake.StakePeriodStart, *stake.Balance)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	entityStake.PendingReward = pendingReward
+	return entityStake, nil
+}
+
+func (sr *stakingRepository) FindNodeStakes(ctx context.Context, epochDay *int64) (*types.NodeStakes, *rTypes.Error) {
+	query, args := selectLatestNodeStakes, []interface{}{}
+	if epochDay != nil {
+		query, args = selectNodeStakesByEpochDay, []interface{}{sql.Named("epoch_day", *epochDay)}
+	}
+
+	nodes := make([]domain.NodeStake, 0)
+	if err := sr.dbClient.Query(ctx, "selectNodeStakesInPeriod", func(db *gorm.DB) error {
+		return db.Raw(query, args...).Scan(&nodes).Error
+	}); err != nil {
+		log.Errorf(databaseErrorFormat, errors.ErrDatabaseError.Message, err)
+		return nil, errors.ErrDatabaseError
+	}
+
+	if len(nodes) == 0 {
+		return nil, errors.ErrNodeStakeNotFound
+	}
+
+	networkStakes := make([]domain.NetworkStake, 0)
+	if err := sr.dbClient.Query(ctx, "selectNetworkStakeByEpochDay", func(db *gorm.DB) error {
+		return db.Raw(selectNetworkStakeByEpochDay, sql.Named("epoch_day", nodes[0].EpochDay)).
+			Scan(&networkStakes).Error
+	}); err != nil {
+		log.Errorf(databaseErrorFormat, errors.ErrDatabaseError.Message, err)
+		return nil, errors.ErrDatabaseError
+	}
+
+	nodeStakes := &types.NodeStakes{
+		EpochDay:      nodes[0].EpochDay,
+		Nodes:         nodes,
+		StakingPeriod: nodes[0].StakingPeriod,
+	}
+	// the network stake isn't recorded for the staking periods before the network_stake table was added
+	if len(networkStakes) != 0 {
+		nodeStakes.Network = &networkStakes[0]
+	}
+	return nodeStakes, nil
+}
+
+// getPendingReward returns the estimated reward of an account staked to the node since the stake period start. Only
+// whole hbars earn rewards, and the estimate assumes the balance is unchanged since the stake period start
+func (sr *stakingRepository) getPendingReward(ctx context.Context, nodeId, stakePeriodStart, balance int64) (
+	int64,
+	*rTypes.Error,
+) {
+	var rewardRateSum int64
+	if err := sr.dbClient.Query(ctx, "selectRewardRateSum", func(db *gorm.DB) error {
+		return db.Raw(
+			selectRewardRateSum,
+			sql.Named("node_id", nodeId),
+			sql.Named("stake_period_start", stakePeriodStart),
+		).Scan(&rewardRateSum).Error
+	}); err != nil {
+		log.Errorf(databaseErrorFormat, errors.ErrDatabaseError.Message, err)
+		return 0, errors.ErrDatabaseError
+	}
+
+	return rewardRateSum * (balance / tinybarsPerHbar), nil
+}
+
+// NewStakingRepository creates an instance of a stakingRepository struct
+func NewStakingRepository(dbClient interfaces.DbClient) interfaces.StakingRepository {
+	return &stakingRepository{dbClient}
+}
@@ -0,0 +1,415 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package persistence
+
+import (
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/db"
+	tdomain "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	stakingAccount7001    int64 = 7001
+	stakingAccount7002    int64 = 7002
+	stakingNodeId         int64 = 3
+	stakingPeriodStart    int64 = 19200
+	stakingTimestamp      int64 = 1658880000000000000
+	stakingDayNanos       int64 = 86_400_000_000_000
+	stakingHistoryLimit         = 2
+	stakingAccountBalance       = 1_050_000_000
+)
+
+// run the suite
+func TestStakingRepositorySuite(t *testing.T) {
+	suite.Run(t, new(stakingRepositorySuite))
+}
+
+type stakingRepositorySuite struct {
+	integrationTest
+	suite.Suite
+}
+
+func (suite *stakingRepositorySuite) TestFindEntityStake() {
+	// given
+	suite.persistStakingAccount(stakingAccount7001, false)
+	suite.persistNodeStakes()
+	// two accounts stake to account 7001, the deleted one and the one staked to another account are excluded
+	tdomain.NewEntityBuilder(dbClient, stakingAccount7002, stakingTimestamp, domain.EntityTypeAccount).
+		Balance(200_000_000).
+		StakedAccountId(stakingAccount7001).
+		Persist()
+	tdomain.NewEntityBuilder(dbClient, stakingAccount7002+1, stakingTimestamp, domain.EntityTypeContract).
+		Balance(300_000_000).
+		StakedAccountId(stakingAccount7001).
+		Persist()
+	tdomain.NewEntityBuilder(dbClient, stakingAccount7002+2, stakingTimestamp, domain.EntityTypeAccount).
+		Balance(400_000_000).
+		Deleted(true).
+		StakedAccountId(stakingAccount7001).
+		Persist()
+	tdomain.NewEntityBuilder(dbClient, stakingAccount7002+3, stakingTimestamp, domain.EntityTypeAccount).
+		Balance(500_000_000).
+		StakedAccountId(stakingAccount7002).
+		Persist()
+	repo := NewStakingRepository(dbClient)
+	nodeId := stakingNodeId
+	stakePeriodStart := stakingPeriodStart
+	expected := &types.EntityStake{
+		AccountId: domain.MustDecodeEntityId(stakingAccount7001),
+		Balance:   stakingAccountBalance,
+		// the reward rate of the recalculated period 19202 and the period 19201 times 10 whole hbars
+		PendingReward:    (12 + 20) * 10,
+		StakedNodeId:     &nodeId,
+		StakedToMe:       500_000_000,
+		StakePeriodStart: &stakePeriodStart,
+	}
+
+	// when
+	actual, err := repo.FindEntityStake(defaultContext, stakingAccount7001)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+}
+
+func (suite *stakingRepositorySuite) TestFindEntityStakeDeclineReward() {
+	// given
+	suite.persistStakingAccount(stakingAccount7001, true)
+	suite.persistNodeStakes()
+	repo := NewStakingRepository(dbClient)
+
+	// when
+	actual, err := repo.FindEntityStake(defaultContext, stakingAccount7001)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.True(suite.T(), actual.DeclineReward)
+	assert.Zero(suite.T(), actual.PendingReward)
+	assert.Zero(suite.T(), actual.StakedToMe)
+}
+
+func (suite *stakingRepositorySuite) TestFindEntityStakeNotStakedToNode() {
+	// given
+	tdomain.NewEntityBuilder(dbClient, stakingAccount7002, stakingTimestamp, domain.EntityTypeAccount).
+		Balance(stakingAccountBalance).
+		Persist()
+	suite.persistNodeStakes()
+	repo := NewStakingRepository(dbClient)
+	expected := &types.EntityStake{
+		AccountId: domain.MustDecodeEntityId(stakingAccount7002),
+		Balance:   stakingAccountBalance,
+	}
+
+	// when
+	actual, err := repo.FindEntityStake(defaultContext, stakingAccount7002)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+}
+
+func (suite *stakingRepositorySuite) TestFindEntityStakeNotFound() {
+	// given
+	tdomain.NewEntityBuilder(dbClient, stakingAccount7002, stakingTimestamp, domain.EntityTypeTopic).Persist()
+	repo := NewStakingRepository(dbClient)
+
+	// when
+	actual, err := repo.FindEntityStake(defaultContext, stakingAccount7002)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrAccountNotFound, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *stakingRepositorySuite) TestFindEntityStakeDbConnectionError() {
+	// given
+	repo := NewStakingRepository(invalidDbClient)
+
+	// when
+	actual, err := repo.FindEntityStake(defaultContext, stakingAccount7001)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *stakingRepositorySuite) TestFindNodeStakesLatest() {
+	// given
+	suite.persistNodeStakes()
+	networkStake := getNetworkStake(stakingPeriodStart + 2)
+	db.CreateDbRecords(dbClient, getNetworkStake(stakingPeriodStart+1), networkStake)
+	repo := NewStakingRepository(dbClient)
+	expected := &types.NodeStakes{
+		EpochDay: stakingPeriodStart + 2,
+		Network:  networkStake,
+		Nodes: []domain.NodeStake{
+			*getNodeStake(stakingNodeId, stakingPeriodStart+2, 1, 12),
+			*getNodeStake(stakingNodeId+1, stakingPeriodStart+2, 0, 50),
+		},
+		StakingPeriod: stakingTimestamp + 2*stakingDayNanos,
+	}
+
+	// when
+	actual, err := repo.FindNodeStakes(defaultContext, nil)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+}
+
+func (suite *stakingRepositorySuite) TestFindNodeStakesByEpochDayWithoutNetworkStake() {
+	// given
+	suite.persistNodeStakes()
+	db.CreateDbRecords(dbClient, getNetworkStake(stakingPeriodStart+2))
+	repo := NewStakingRepository(dbClient)
+	epochDay := stakingPeriodStart + 1
+	expected := &types.NodeStakes{
+		EpochDay:      epochDay,
+		Nodes:         []domain.NodeStake{*getNodeStake(stakingNodeId, epochDay, 0, 20)},
+		StakingPeriod: stakingTimestamp + stakingDayNanos,
+	}
+
+	// when
+	actual, err := repo.FindNodeStakes(defaultContext, &epochDay)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+}
+
+func (suite *stakingRepositorySuite) TestFindNodeStakesNotFound() {
+	// given
+	suite.persistNodeStakes()
+	repo := NewStakingRepository(dbClient)
+	epochDay := stakingPeriodStart + 3
+
+	// when
+	actual, err := repo.FindNodeStakes(defaultContext, &epochDay)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrNodeStakeNotFound, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *stakingRepositorySuite) TestFindNodeStakesNoNodeStake() {
+	// given
+	repo := NewStakingRepository(dbClient)
+
+	// when
+	actual, err := repo.FindNodeStakes(defaultContext, nil)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrNodeStakeNotFound, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *stakingRepositorySuite) TestFindNodeStakesDbConnectionError() {
+	// given
+	repo := NewStakingRepository(invalidDbClient)
+
+	// when
+	actual, err := repo.FindNodeStakes(defaultContext, nil)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *stakingRepositorySuite) TestFindStakingHistory() {
+	// given
+	suite.persistStakingAccount(stakingAccount7001, false)
+	suite.persistNodeStakes()
+	db.CreateDbRecords(
+		dbClient,
+		getStakingRewardTransfer(stakingAccount7001, 100, stakingTimestamp+1),
+		getStakingRewardTransfer(stakingAccount7001, 200, stakingTimestamp+stakingDayNanos+1),
+		getStakingRewardTransfer(stakingAccount7001, 300, stakingTimestamp+2*stakingDayNanos+1),
+		getStakingRewardTransfer(stakingAccount7002, 400, stakingTimestamp+2*stakingDayNanos+1),
+	)
+	repo := NewStakingRepository(dbClient)
+	nodeId := stakingNodeId
+	stakePeriodStart := stakingPeriodStart
+	expected := &types.StakingHistory{
+		AccountId: domain.MustDecodeEntityId(stakingAccount7001),
+		// the reward rate of the recalculated period 19202 and the period 19201 times 10 whole hbars
+		PendingReward: (12 + 20) * 10,
+		Periods: []types.StakingPeriod{
+			getStakingPeriod(stakingPeriodStart+2, 12),
+			getStakingPeriod(stakingPeriodStart+1, 20),
+		},
+		Rewards: []types.StakingReward{
+			{Amount: 300, ConsensusTimestamp: stakingTimestamp + 2*stakingDayNanos + 1},
+			{Amount: 200, ConsensusTimestamp: stakingTimestamp + stakingDayNanos + 1},
+		},
+		StakedNodeId:     &nodeId,
+		StakePeriodStart: &stakePeriodStart,
+	}
+
+	// when
+	actual, err := repo.FindStakingHistory(defaultContext, stakingAccount7001, stakingHistoryLimit)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+}
+
+func (suite *stakingRepositorySuite) TestFindStakingHistoryDeclineReward() {
+	// given
+	suite.persistStakingAccount(stakingAccount7001, true)
+	suite.persistNodeStakes()
+	repo := NewStakingRepository(dbClient)
+
+	// when
+	actual, err := repo.FindStakingHistory(defaultContext, stakingAccount7001, stakingHistoryLimit)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.True(suite.T(), actual.DeclineReward)
+	assert.Zero(suite.T(), actual.PendingReward)
+	assert.Len(suite.T(), actual.Periods, stakingHistoryLimit)
+	assert.Empty(suite.T(), actual.Rewards)
+}
+
+func (suite *stakingRepositorySuite) TestFindStakingHistoryNotStakedToNode() {
+	// given
+	tdomain.NewEntityBuilder(dbClient, stakingAccount7002, stakingTimestamp, domain.EntityTypeAccount).
+		Balance(stakingAccountBalance).
+		Persist()
+	suite.persistNodeStakes()
+	repo := NewStakingRepository(dbClient)
+	expected := &types.StakingHistory{
+		AccountId: domain.MustDecodeEntityId(stakingAccount7002),
+		Periods:   []types.StakingPeriod{},
+		Rewards:   []types.StakingReward{},
+	}
+
+	// when
+	actual, err := repo.FindStakingHistory(defaultContext, stakingAccount7002, stakingHistoryLimit)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+}
+
+func (suite *stakingRepositorySuite) TestFindStakingHistoryNotFound() {
+	// given
+	tdomain.NewEntityBuilder(dbClient, stakingAccount7002, stakingTimestamp, domain.EntityTypeTopic).Persist()
+	repo := NewStakingRepository(dbClient)
+
+	// when
+	actual, err := repo.FindStakingHistory(defaultContext, stakingAccount7002, stakingHistoryLimit)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrAccountNotFound, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *stakingRepositorySuite) TestFindStakingHistoryDbConnectionError() {
+	// given
+	repo := NewStakingRepository(invalidDbClient)
+
+	// when
+	actual, err := repo.FindStakingHistory(defaultContext, stakingAccount7001, stakingHistoryLimit)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *stakingRepositorySuite) persistStakingAccount(accountId int64, declineReward bool) {
+	tdomain.NewEntityBuilder(dbClient, accountId, stakingTimestamp, domain.EntityTypeAccount).
+		Balance(stakingAccountBalance).
+		DeclineReward(declineReward).
+		StakedNodeId(stakingNodeId, stakingPeriodStart).
+		Persist()
+}
+
+// persistNodeStakes persists the node stakes of the periods 19200 to 19202 with the period 19202 recalculated, and the
+// node stake of another node
+func (suite *stakingRepositorySuite) persistNodeStakes() {
+	db.CreateDbRecords(
+		dbClient,
+		getNodeStake(stakingNodeId, stakingPeriodStart, 0, 30),
+		getNodeStake(stakingNodeId, stakingPeriodStart+1, 0, 20),
+		getNodeStake(stakingNodeId, stakingPeriodStart+2, 0, 10),
+		getNodeStake(stakingNodeId, stakingPeriodStart+2, 1, 12),
+		getNodeStake(stakingNodeId+1, stakingPeriodStart+2, 0, 50),
+	)
+}
+
+func getNetworkStake(epochDay int64) *domain.NetworkStake {
+	stakingPeriod := stakingTimestamp + (epochDay-stakingPeriodStart)*stakingDayNanos
+	return &domain.NetworkStake{
+		ConsensusTimestamp:          stakingPeriod + 1,
+		EpochDay:                    epochDay,
+		MaxStakingRewardRatePerHbar: 17808,
+		NodeRewardFeeDenominator:    0,
+		NodeRewardFeeNumerator:      100,
+		StakeTotal:                  4_000_000_000,
+		StakingPeriod:               stakingPeriod,
+		StakingPeriodDuration:       1440,
+		StakingPeriodsStored:        365,
+		StakingRewardFeeDenominator: 100,
+		StakingRewardFeeNumerator:   100,
+		StakingRewardRate:           100_000_000_000,
+		StakingStartThreshold:       25_000_000_000_000_000,
+	}
+}
+
+func getNodeStake(nodeId, epochDay, delta, rewardRate int64) *domain.NodeStake {
+	stakingPeriod := stakingTimestamp + (epochDay-stakingPeriodStart)*stakingDayNanos
+	return &domain.NodeStake{
+		ConsensusTimestamp: stakingPeriod + 1 + delta,
+		EpochDay:           epochDay,
+		MaxStake:           5_000_000_000,
+		MinStake:           1_000_000_000,
+		NodeId:             nodeId,
+		RewardRate:         rewardRate,
+		Stake:              2_000_000_000,
+		StakeNotRewarded:   500_000_000,
+		StakeRewarded:      1_500_000_000,
+		StakingPeriod:      stakingPeriod,
+	}
+}
+
+func getStakingPeriod(epochDay, rewardRate int64) types.StakingPeriod {
+	return types.StakingPeriod{
+		EpochDay:      epochDay,
+		NodeId:        stakingNodeId,
+		RewardRate:    rewardRate,
+		StakeRewarded: 1_500_000_000,
+		StakingPeriod: stakingTimestamp + (epochDay-stakingPeriodStart)*stakingDayNanos,
+	}
+}
+
+func getStakingRewardTransfer(accountId, amount, consensusTimestamp int64) *domain.StakingRewardTransfer {
+	return &domain.StakingRewardTransfer{
+		AccountId:          domain.MustDecodeEntityId(accountId),
+		Amount:             amount,
+		ConsensusTimestamp: consensusTimestamp,
+		PayerAccountId:     domain.MustDecodeEntityId(800),
+	}
+}
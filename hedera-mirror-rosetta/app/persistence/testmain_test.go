@@ -24,6 +24,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/db"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
@@ -106,12 +107,12 @@ func addTransaction(
 
 func setup() {
 	dbResource = tdb.SetupDb(true)
-	dbClient = db.NewDbClient(dbResource.GetGormDb(), 0)
+	dbClient = db.NewDbClient(dbResource.GetGormDb(), 0, config.DbRetry{})
 
-	config := dbResource.GetDbConfig()
-	config.Password = "bad_password"
-	invalid, _ := gorm.Open(postgres.Open(config.GetDsn()), &gorm.Config{Logger: logger.Discard})
-	invalidDbClient = db.NewDbClient(invalid, 0)
+	dbConfig := dbResource.GetDbConfig()
+	dbConfig.Password = "bad_password"
+	invalid, _ := gorm.Open(postgres.Open(dbConfig.GetDsn()), &gorm.Config{Logger: logger.Discard})
+	invalidDbClient = db.NewDbClient(invalid, 0, config.DbRetry{})
 }
 
 func teardown() {
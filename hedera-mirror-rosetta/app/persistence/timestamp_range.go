@@ -0,0 +1,50 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package persistence
+
+// timestampRange is an inclusive range of consensus timestamps
+type timestampRange struct {
+	start int64
+	end   int64
+}
+
+// splitTimestampRange splits the inclusive timestamp range [start, end] into consecutive ranges aligned with the
+// boundaries of the partitions of the interval. The range is returned as is if the interval is not positive or the
+// range is within a single partition
+func splitTimestampRange(start, end, partitionInterval int64) []timestampRange {
+	if partitionInterval <= 0 || start >= end {
+		return []timestampRange{{start: start, end: end}}
+	}
+
+	ranges := make([]timestampRange, 0)
+	for start <= end {
+		// the start of the next partition
+		boundary := (start/partitionInterval + 1) * partitionInterval
+		if boundary > end || boundary <= start {
+			ranges = append(ranges, timestampRange{start: start, end: end})
+			break
+		}
+
+		ranges = append(ranges, timestampRange{start: start, end: boundary - 1})
+		start = boundary
+	}
+
+	return ranges
+}
@@ -0,0 +1,101 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package persistence
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitTimestampRange(t *testing.T) {
+	tests := []struct {
+		name              string
+		start             int64
+		end               int64
+		partitionInterval int64
+		expected          []timestampRange
+	}{
+		{
+			name:              "disabled",
+			start:             5,
+			end:               105,
+			partitionInterval: 0,
+			expected:          []timestampRange{{start: 5, end: 105}},
+		},
+		{
+			name:              "single partition",
+			start:             10,
+			end:               19,
+			partitionInterval: 10,
+			expected:          []timestampRange{{start: 10, end: 19}},
+		},
+		{
+			name:              "single timestamp",
+			start:             10,
+			end:               10,
+			partitionInterval: 10,
+			expected:          []timestampRange{{start: 10, end: 10}},
+		},
+		{
+			name:              "multiple partitions",
+			start:             5,
+			end:               31,
+			partitionInterval: 10,
+			expected: []timestampRange{
+				{start: 5, end: 9},
+				{start: 10, end: 19},
+				{start: 20, end: 29},
+				{start: 30, end: 31},
+			},
+		},
+		{
+			name:              "aligned",
+			start:             10,
+			end:               29,
+			partitionInterval: 10,
+			expected:          []timestampRange{{start: 10, end: 19}, {start: 20, end: 29}},
+		},
+		{
+			name:              "end at boundary",
+			start:             15,
+			end:               20,
+			partitionInterval: 10,
+			expected:          []timestampRange{{start: 15, end: 19}, {start: 20, end: 20}},
+		},
+		{
+			name:              "overflow",
+			start:             math.MaxInt64 - 5,
+			end:               math.MaxInt64,
+			partitionInterval: math.MaxInt64 / 2,
+			expected: []timestampRange{
+				{start: math.MaxInt64 - 5, end: math.MaxInt64 - 2},
+				{start: math.MaxInt64 - 1, end: math.MaxInt64},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, splitTimestampRange(tt.start, tt.end, tt.partitionInterval))
+		})
+	}
+}
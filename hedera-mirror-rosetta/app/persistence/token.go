@@ -0,0 +1,291 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const tableNameTokenAirdrop = "token_airdrop"
+
+const (
+	selectLatestAccountBalanceFile = `select consensus_timestamp, time_offset
+                                      from account_balance_file
+                                      order by consensus_timestamp desc
+                                      limit 1`
+	selectNftByTokenIdAndSerialNumber = `select * from nft
+                                         where token_id = @token_id and serial_number = @serial_number`
+	selectNftsByTokenId = `select * from nft
+                           where token_id = @token_id and serial_number > @after
+                           order by serial_number
+                           limit @limit`
+	// selectPendingAirdropsByAccountId selects the pending airdrops the account is either the receiver or the sender
+	// of, oldest first
+	selectPendingAirdropsByAccountId = `select
+                                          a.amount,
+                                          t.decimals,
+                                          a.receiver_account_id,
+                                          a.sender_account_id,
+                                          a.serial_number,
+                                          a.state::text as state,
+                                          lower(a.timestamp_range) as timestamp,
+                                          a.token_id,
+                                          t.type
+                                        from token_airdrop a
+                                        join token t on t.token_id = a.token_id
+                                        where (a.receiver_account_id = @account_id or
+                                            a.sender_account_id = @account_id) and
+                                          a.state = 'PENDING'
+                                        order by lower(a.timestamp_range), a.token_id, a.sender_account_id,
+                                          a.receiver_account_id, a.serial_number
+                                        limit @limit`
+	// selectPendingAirdropsInTimestampRange selects the airdrop state changes made by the transactions in the timestamp
+	// range, i.e., the versions of the airdrops starting in the range. An airdrop adding to an existing pending airdrop
+	// of the same sender, receiver, and token has the total pending amount
+	selectPendingAirdropsInTimestampRange = `with airdrop as (
+                                               select *
+                                               from token_airdrop
+                                               where lower(timestamp_range) >= @start and
+                                                 lower(timestamp_range) <= @end
+                                               union all
+                                               select *
+                                               from token_airdrop_history
+                                               where lower(timestamp_range) >= @start and
+                                                 lower(timestamp_range) <= @end
+                                             )
+                                             select
+                                               a.amount,
+                                               t.decimals,
+                                               a.receiver_account_id,
+                                               a.sender_account_id,
+                                               a.serial_number,
+                                               a.state::text as state,
+                                               lower(a.timestamp_range) as timestamp,
+                                               a.token_id,
+                                               t.type
+                                             from airdrop a
+                                             join token t on t.token_id = a.token_id
+                                             order by lower(a.timestamp_range), a.token_id, a.sender_account_id,
+                                               a.receiver_account_id, a.serial_number`
+	selectTokenById    = "select * from token where token_id = @token_id"
+	selectTokenHolders = `select account_id, balance
+                          from token_balance
+                          where
+                            consensus_timestamp = @timestamp and
+                            token_id = @token_id and
+                            balance >= @min_balance and
+                            account_id > @after
+                          order by account_id
+                          limit @limit`
+)
+
+type accountBalanceFile struct {
+	ConsensusTimestamp int64
+	TimeOffset         int64
+}
+
+type pendingAirdrop struct {
+	Amount            *int64
+	Decimals          int64
+	ReceiverAccountId domain.EntityId
+	SenderAccountId   domain.EntityId
+	SerialNumber      int64
+	State             string
+	Timestamp         int64
+	TokenId           domain.EntityId
+	Type              string
+}
+
+func (p pendingAirdrop) toPendingAirdrop() types.PendingAirdrop {
+	var amount int64
+	if p.Amount != nil {
+		amount = *p.Amount
+	}
+
+	return types.PendingAirdrop{
+		Amount:            amount,
+		ReceiverAccountId: p.ReceiverAccountId,
+		SenderAccountId:   p.SenderAccountId,
+		SerialNumber:      p.SerialNumber,
+		State:             p.State,
+		Timestamp:         p.Timestamp,
+		Token:             domain.Token{Decimals: p.Decimals, TokenId: p.TokenId, Type: p.Type},
+	}
+}
+
+type tokenHolder struct {
+	AccountId domain.EntityId
+	Balance   int64
+}
+
+// tokenRepository struct that has connection to the Database
+type tokenRepository struct {
+	dbClient     interfaces.DbClient
+	tokenAirdrop *optionalTable
+}
+
+func (tr *tokenRepository) Find(ctx context.Context, tokenId int64) (*types.Token, *rTypes.Error) {
+	tokens := make([]domain.Token, 0)
+	if err := tr.dbClient.Query(ctx, "selectTokenById", func(db *gorm.DB) error {
+		return db.Raw(selectTokenById, sql.Named("token_id", tokenId)).Scan(&tokens).Error
+	}); err != nil {
+		log.Errorf(databaseErrorFormat, errors.ErrDatabaseError.Message, err)
+		return nil, errors.ErrDatabaseError
+	}
+
+	if len(tokens) == 0 {
+		return nil, errors.ErrTokenNotFound
+	}
+
+	return &types.Token{Token: tokens[0]}, nil
+}
+
+func (tr *tokenRepository) FindNft(ctx context.Context, tokenId, serialNumber int64) (*types.Nft, *rTypes.Error) {
+	nfts := make([]domain.Nft, 0)
+	if err := tr.dbClient.Query(ctx, "selectNftByTokenIdAndSerialNumber", func(db *gorm.DB) error {
+		return db.Raw(
+			selectNftByTokenIdAndSerialNumber,
+			sql.Named("serial_number", serialNumber),
+			sql.Named("token_id", tokenId),
+		).Scan(&nfts).Error
+	}); err != nil {
+		log.Errorf(databaseErrorFormat, errors.ErrDatabaseError.Message, err)
+		return nil, errors.ErrDatabaseError
+	}
+
+	if len(nfts) == 0 {
+		return nil, errors.ErrNftNotFound
+	}
+
+	return &types.Nft{Nft: nfts[0]}, nil
+}
+
+func (tr *tokenRepository) FindNfts(ctx context.Context, tokenId, afterSerialNumber int64, limit int) (
+	[]types.Nft,
+	*rTypes.Error,
+) {
+	nfts := make([]domain.Nft, 0, limit)
+	if err := tr.dbClient.Query(ctx, "selectNftsByTokenId", func(db *gorm.DB) error {
+		return db.Raw(
+			selectNftsByTokenId,
+			sql.Named("after", afterSerialNumber),
+			sql.Named("limit", limit),
+			sql.Named("token_id", tokenId),
+		).Scan(&nfts).Error
+	}); err != nil {
+		log.Errorf(databaseErrorFormat, errors.ErrDatabaseError.Message, err)
+		return nil, errors.ErrDatabaseError
+	}
+
+	result := make([]types.Nft, 0, len(nfts))
+	for _, nft := range nfts {
+		result = append(result, types.Nft{Nft: nft})
+	}
+
+	return result, nil
+}
+
+func (tr *tokenRepository) FindHolders(
+	ctx context.Context,
+	tokenId, minBalance, afterAccountId int64,
+	limit int,
+) (int64, []types.TokenHolder, *rTypes.Error) {
+	files := make([]accountBalanceFile, 0)
+	if err := tr.dbClient.Query(ctx, "selectLatestAccountBalanceFile", func(db *gorm.DB) error {
+		return db.Raw(selectLatestAccountBalanceFile).Scan(&files).Error
+	}); err != nil {
+		log.Errorf(databaseErrorFormat, errors.ErrDatabaseError.Message, err)
+		return 0, nil, errors.ErrDatabaseError
+	}
+
+	if len(files) == 0 {
+		return 0, nil, errors.ErrNodeIsStarting
+	}
+
+	holders := make([]tokenHolder, 0, limit)
+	if err := tr.dbClient.Query(ctx, "selectTokenHolders", func(db *gorm.DB) error {
+		return db.Raw(
+			selectTokenHolders,
+			sql.Named("after", afterAccountId),
+			sql.Named("limit", limit),
+			sql.Named("min_balance", minBalance),
+			sql.Named("timestamp", files[0].ConsensusTimestamp),
+			sql.Named("token_id", tokenId),
+		).Scan(&holders).Error
+	}); err != nil {
+		log.Errorf(databaseErrorFormat, errors.ErrDatabaseError.Message, err)
+		return 0, nil, errors.ErrDatabaseError
+	}
+
+	result := make([]types.TokenHolder, 0, len(holders))
+	for _, holder := range holders {
+		result = append(result, types.TokenHolder{AccountId: holder.AccountId, Balance: holder.Balance})
+	}
+
+	return files[0].ConsensusTimestamp + files[0].TimeOffset, result, nil
+}
+
+func (tr *tokenRepository) FindPendingAirdrops(ctx context.Context, accountId int64, limit int) (
+	[]types.PendingAirdrop,
+	*rTypes.Error,
+) {
+	present, rErr := tr.tokenAirdrop.isPresent(ctx, tr.dbClient)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	if !present {
+		return []types.PendingAirdrop{}, nil
+	}
+
+	airdrops := make([]pendingAirdrop, 0, limit)
+	if err := tr.dbClient.Query(ctx, "selectPendingAirdropsByAccountId", func(db *gorm.DB) error {
+		return db.Raw(
+			selectPendingAirdropsByAccountId,
+			sql.Named("account_id", accountId),
+			sql.Named("limit", limit),
+		).Scan(&airdrops).Error
+	}); err != nil {
+		log.Errorf(databaseErrorFormat, errors.ErrDatabaseError.Message, err)
+		return nil, errors.ErrDatabaseError
+	}
+
+	result := make([]types.PendingAirdrop, 0, len(airdrops))
+	for _, airdrop := range airdrops {
+		result = append(result, airdrop.toPendingAirdrop())
+	}
+
+	return result, nil
+}
+
+// NewTokenRepository creates an instance of a tokenRepository struct
+func NewTokenRepository(dbClient interfaces.DbClient) interfaces.TokenRepository {
+	return &tokenRepository{dbClient: dbClient, tokenAirdrop: newOptionalTable(tableNameTokenAirdrop)}
+}
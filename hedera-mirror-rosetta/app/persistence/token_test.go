@@ -0,0 +1,378 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package persistence
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/db"
+	tdomain "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	holderAccount1 = int64(7001) + iota
+	holderAccount2
+	holderAccount3
+	holderToken
+	holderTokenTreasury
+)
+
+const (
+	// createTokenAirdropTablesSql creates the HIP-904 token airdrop tables, which the importer migrations in this tree
+	// don't create yet
+	createTokenAirdropTablesSql = `create type airdrop_state as enum ('CANCELLED', 'CLAIMED', 'PENDING');
+                                   create table token_airdrop (
+                                     amount              bigint,
+                                     receiver_account_id bigint        not null,
+                                     sender_account_id   bigint        not null,
+                                     serial_number       bigint        not null,
+                                     state               airdrop_state not null default 'PENDING',
+                                     timestamp_range     int8range     not null,
+                                     token_id            bigint        not null
+                                   );
+                                   create table token_airdrop_history (like token_airdrop including defaults);`
+	dropTokenAirdropTablesSql = `drop table if exists token_airdrop, token_airdrop_history;
+                                 drop type if exists airdrop_state;`
+	insertTokenAirdropSql = `insert into %s (amount, receiver_account_id, sender_account_id, serial_number, state,
+                               timestamp_range, token_id)
+                             values (?, ?, ?, ?, ?, ?, ?)`
+)
+
+// run the suite
+func TestTokenRepositorySuite(t *testing.T) {
+	suite.Run(t, new(tokenRepositorySuite))
+}
+
+type tokenRepositorySuite struct {
+	integrationTest
+	suite.Suite
+}
+
+func (suite *tokenRepositorySuite) TestFind() {
+	// given
+	token := tdomain.NewTokenBuilder(dbClient, holderToken, 100, holderTokenTreasury).Decimals(2).Persist()
+	repo := NewTokenRepository(dbClient)
+
+	// when
+	actual, err := repo.Find(defaultContext, holderToken)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), &types.Token{Token: token}, actual)
+}
+
+func (suite *tokenRepositorySuite) TestFindNotFound() {
+	// given
+	repo := NewTokenRepository(dbClient)
+
+	// when
+	actual, err := repo.Find(defaultContext, holderToken)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrTokenNotFound, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *tokenRepositorySuite) TestFindDbConnectionError() {
+	// given
+	repo := NewTokenRepository(invalidDbClient)
+
+	// when
+	actual, err := repo.Find(defaultContext, holderToken)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *tokenRepositorySuite) TestFindNft() {
+	// given
+	expected := tdomain.NewNftBuilder(dbClient, holderToken, 1, 100).
+		AccountId(holderAccount1).
+		Metadata([]byte{0x1}).
+		Spender(holderAccount2).
+		Persist()
+	tdomain.NewNftBuilder(dbClient, holderToken, 2, 100).AccountId(holderAccount1).Persist()
+	repo := NewTokenRepository(dbClient)
+
+	// when
+	actual, err := repo.FindNft(defaultContext, holderToken, 1)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), &types.Nft{Nft: expected}, actual)
+}
+
+func (suite *tokenRepositorySuite) TestFindNftNotFound() {
+	// given
+	repo := NewTokenRepository(dbClient)
+
+	// when
+	actual, err := repo.FindNft(defaultContext, holderToken, 1)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrNftNotFound, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *tokenRepositorySuite) TestFindNftDbConnectionError() {
+	// given
+	repo := NewTokenRepository(invalidDbClient)
+
+	// when
+	actual, err := repo.FindNft(defaultContext, holderToken, 1)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *tokenRepositorySuite) TestFindNfts() {
+	// given
+	nft1 := tdomain.NewNftBuilder(dbClient, holderToken, 1, 100).AccountId(holderAccount1).Persist()
+	nft2 := tdomain.NewNftBuilder(dbClient, holderToken, 2, 100).Deleted(true).ModifiedTimestamp(150).Persist()
+	nft3 := tdomain.NewNftBuilder(dbClient, holderToken, 3, 110).AccountId(holderAccount2).Persist()
+	tdomain.NewNftBuilder(dbClient, holderToken+100, 1, 100).AccountId(holderAccount1).Persist()
+	repo := NewTokenRepository(dbClient)
+
+	// when
+	page1, err1 := repo.FindNfts(defaultContext, holderToken, 0, 2)
+	page2, err2 := repo.FindNfts(defaultContext, holderToken, 2, 2)
+
+	// then
+	assert.Nil(suite.T(), err1)
+	assert.Nil(suite.T(), err2)
+	assert.Equal(suite.T(), []types.Nft{{Nft: nft1}, {Nft: nft2}}, page1)
+	assert.Equal(suite.T(), []types.Nft{{Nft: nft3}}, page2)
+}
+
+func (suite *tokenRepositorySuite) TestFindNftsDbConnectionError() {
+	// given
+	repo := NewTokenRepository(invalidDbClient)
+
+	// when
+	actual, err := repo.FindNfts(defaultContext, holderToken, 0, 10)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *tokenRepositorySuite) TestFindHolders() {
+	// given
+	suite.persistBalanceFiles()
+	repo := NewTokenRepository(dbClient)
+	expected := []types.TokenHolder{
+		{AccountId: domain.MustDecodeEntityId(holderAccount1), Balance: 10},
+		{AccountId: domain.MustDecodeEntityId(holderAccount3), Balance: 30},
+	}
+
+	// when
+	timestamp, actual, err := repo.FindHolders(defaultContext, holderToken, 1, 0, 10)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), int64(205), timestamp)
+	assert.Equal(suite.T(), expected, actual)
+}
+
+func (suite *tokenRepositorySuite) TestFindHoldersPaged() {
+	// given
+	suite.persistBalanceFiles()
+	repo := NewTokenRepository(dbClient)
+
+	// when
+	_, page1, err1 := repo.FindHolders(defaultContext, holderToken, 1, 0, 1)
+	_, page2, err2 := repo.FindHolders(defaultContext, holderToken, 1, holderAccount1, 1)
+	_, page3, err3 := repo.FindHolders(defaultContext, holderToken, 1, holderAccount3, 1)
+
+	// then
+	assert.Nil(suite.T(), err1)
+	assert.Nil(suite.T(), err2)
+	assert.Nil(suite.T(), err3)
+	holder1 := types.TokenHolder{AccountId: domain.MustDecodeEntityId(holderAccount1), Balance: 10}
+	holder3 := types.TokenHolder{AccountId: domain.MustDecodeEntityId(holderAccount3), Balance: 30}
+	assert.Equal(suite.T(), []types.TokenHolder{holder1}, page1)
+	assert.Equal(suite.T(), []types.TokenHolder{holder3}, page2)
+	assert.Empty(suite.T(), page3)
+}
+
+func (suite *tokenRepositorySuite) TestFindHoldersMinBalance() {
+	// given
+	suite.persistBalanceFiles()
+	repo := NewTokenRepository(dbClient)
+	expected := []types.TokenHolder{{AccountId: domain.MustDecodeEntityId(holderAccount3), Balance: 30}}
+
+	// when
+	_, actual, err := repo.FindHolders(defaultContext, holderToken, 11, 0, 10)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+}
+
+func (suite *tokenRepositorySuite) TestFindHoldersNoAccountBalanceFile() {
+	// given
+	repo := NewTokenRepository(dbClient)
+
+	// when
+	timestamp, actual, err := repo.FindHolders(defaultContext, holderToken, 1, 0, 10)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrNodeIsStarting, err)
+	assert.Zero(suite.T(), timestamp)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *tokenRepositorySuite) TestFindHoldersDbConnectionError() {
+	// given
+	repo := NewTokenRepository(invalidDbClient)
+
+	// when
+	timestamp, actual, err := repo.FindHolders(defaultContext, holderToken, 1, 0, 10)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Zero(suite.T(), timestamp)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *tokenRepositorySuite) TestFindPendingAirdrops() {
+	// given
+	db.ExecSql(dbClient, createTokenAirdropTablesSql)
+	defer db.ExecSql(dbClient, dropTokenAirdropTablesSql)
+	fungible := tdomain.NewTokenBuilder(dbClient, holderToken, 100, holderTokenTreasury).Decimals(2).Persist()
+	nft := tdomain.NewTokenBuilder(dbClient, holderToken+10, 100, holderTokenTreasury).
+		Type(domain.TokenTypeNonFungibleUnique).
+		Persist()
+	fungibleId := fungible.TokenId.EncodedId
+	insertTokenAirdrop(holderAccount1, holderAccount2, fungibleId, 0, 50, types.AirdropStatePending, 200, 0)
+	insertTokenAirdrop(holderAccount2, holderAccount1, nft.TokenId.EncodedId, 3, 0, types.AirdropStatePending, 150, 0)
+	insertTokenAirdrop(holderAccount1, holderAccount3, fungibleId, 0, 10, types.AirdropStateClaimed, 300, 0)
+	insertTokenAirdrop(holderAccount3, holderAccount2, fungibleId, 0, 10, types.AirdropStatePending, 300, 0)
+	expected := []types.PendingAirdrop{
+		{
+			ReceiverAccountId: domain.MustDecodeEntityId(holderAccount2),
+			SenderAccountId:   domain.MustDecodeEntityId(holderAccount1),
+			SerialNumber:      3,
+			State:             types.AirdropStatePending,
+			Timestamp:         150,
+			Token:             domain.Token{TokenId: nft.TokenId, Type: domain.TokenTypeNonFungibleUnique},
+		},
+		{
+			Amount:            50,
+			ReceiverAccountId: domain.MustDecodeEntityId(holderAccount1),
+			SenderAccountId:   domain.MustDecodeEntityId(holderAccount2),
+			State:             types.AirdropStatePending,
+			Timestamp:         200,
+			Token:             domain.Token{Decimals: 2, TokenId: fungible.TokenId, Type: domain.TokenTypeFungibleCommon},
+		},
+	}
+	repo := NewTokenRepository(dbClient)
+
+	// when
+	actual, err := repo.FindPendingAirdrops(defaultContext, holderAccount1, 10)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+
+	// when
+	actual, err = repo.FindPendingAirdrops(defaultContext, holderAccount1, 1)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected[:1], actual)
+}
+
+func (suite *tokenRepositorySuite) TestFindPendingAirdropsNoTable() {
+	// given
+	repo := NewTokenRepository(dbClient)
+
+	// when
+	actual, err := repo.FindPendingAirdrops(defaultContext, holderAccount1, 10)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Empty(suite.T(), actual)
+}
+
+func (suite *tokenRepositorySuite) TestFindPendingAirdropsDbConnectionError() {
+	// given
+	repo := NewTokenRepository(invalidDbClient)
+
+	// when
+	actual, err := repo.FindPendingAirdrops(defaultContext, holderAccount1, 10)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *tokenRepositorySuite) persistBalanceFiles() {
+	// the older snapshot should be ignored
+	tdomain.NewAccountBalanceFileBuilder(dbClient, 100).
+		AddTokenBalance(holderAccount2, holderToken, 50).
+		Persist()
+	// holderAccount2 has zero balance in the latest snapshot
+	tdomain.NewAccountBalanceFileBuilder(dbClient, 200).
+		TimeOffset(5).
+		AddTokenBalance(holderAccount1, holderToken, 10).
+		AddTokenBalance(holderAccount2, holderToken, 0).
+		AddTokenBalance(holderAccount3, holderToken, 30).
+		AddTokenBalance(holderAccount3, holderToken+100, 40).
+		Persist()
+}
+
+// insertTokenAirdrop inserts the airdrop into token_airdrop, or token_airdrop_history if the upper bound of its
+// timestamp range is set
+func insertTokenAirdrop(
+	sender, receiver, tokenId, serialNumber, amount int64,
+	state string,
+	lower, upper int64,
+) {
+	var nullableAmount interface{}
+	if amount != 0 {
+		nullableAmount = amount
+	}
+
+	table, timestampRange := "token_airdrop", fmt.Sprintf("[%d,)", lower)
+	if upper != 0 {
+		table, timestampRange = "token_airdrop_history", fmt.Sprintf("[%d,%d)", lower, upper)
+	}
+
+	dbClient.GetDb().Exec(
+		fmt.Sprintf(insertTokenAirdropSql, table),
+		nullableAmount,
+		receiver,
+		sender,
+		serialNumber,
+		state,
+		timestampRange,
+		tokenId,
+	)
+}
@@ -0,0 +1,113 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// selectTopicMessageWithPreviousRunningHash selects the topic message and the running hash of the previous message
+	// in the topic, which is needed to verify the running hash
+	selectTopicMessageWithPreviousRunningHash = `select tm.*, p.running_hash as previous_running_hash
+                                                 from topic_message tm
+                                                 left join topic_message p
+                                                   on p.topic_id = tm.topic_id and
+                                                      p.sequence_number = tm.sequence_number - 1
+                                                 where tm.topic_id = @topic_id and `
+	selectTopicMessageBySequenceNumber = selectTopicMessageWithPreviousRunningHash +
+		`tm.sequence_number = @sequence_number`
+	// selectTopicMessageChunks selects the chunks of a message by the initial transaction id. Since a chunk can't reach
+	// consensus before the valid start of the initial transaction, the search starts from the valid start
+	selectTopicMessageChunks = selectTopicMessageWithPreviousRunningHash +
+		`tm.consensus_timestamp >= @start and tm.initial_transaction_id = @initial_transaction_id
+         order by tm.consensus_timestamp
+         limit @limit`
+)
+
+// topicMessageRepository struct that has connection to the Database
+type topicMessageRepository struct {
+	dbClient interfaces.DbClient
+}
+
+func (tr *topicMessageRepository) FindBySequenceNumber(ctx context.Context, topicId, sequenceNumber int64) (
+	*types.TopicMessage,
+	*rTypes.Error,
+) {
+	chunks := make([]types.TopicMessageChunk, 0)
+	if err := tr.dbClient.Query(ctx, "selectTopicMessageBySequenceNumber", func(db *gorm.DB) error {
+		return db.Raw(
+			selectTopicMessageBySequenceNumber,
+			sql.Named("topic_id", topicId),
+			sql.Named("sequence_number", sequenceNumber),
+		).Scan(&chunks).Error
+	}); err != nil {
+		log.Errorf(databaseErrorFormat, errors.ErrDatabaseError.Message, err)
+		return nil, errors.ErrDatabaseError
+	}
+
+	if len(chunks) == 0 {
+		return nil, errors.ErrTopicMessageNotFound
+	}
+
+	chunk := chunks[0]
+	if chunk.ChunkTotal == nil || *chunk.ChunkTotal <= 1 {
+		return types.NewTopicMessage(chunks), nil
+	}
+
+	initialTransactionId, err := types.GetInitialTransactionId(chunk.TopicMessage)
+	if err != nil || initialTransactionId == nil {
+		// without the initial transaction id, the other chunks can't be found
+		log.Warnf("Failed to get initial transaction id of topic message %d: %v", chunk.ConsensusTimestamp, err)
+		return types.NewTopicMessage(chunks), nil
+	}
+
+	validStart := initialTransactionId.GetTransactionValidStart()
+	start := validStart.GetSeconds()*1e9 + int64(validStart.GetNanos())
+	allChunks := make([]types.TopicMessageChunk, 0, *chunk.ChunkTotal)
+	if err := tr.dbClient.Query(ctx, "selectTopicMessageChunks", func(db *gorm.DB) error {
+		return db.Raw(
+			selectTopicMessageChunks,
+			sql.Named("topic_id", topicId),
+			sql.Named("start", start),
+			sql.Named("initial_transaction_id", chunk.InitialTransactionId),
+			sql.Named("limit", *chunk.ChunkTotal),
+		).Scan(&allChunks).Error
+	}); err != nil {
+		log.Errorf(databaseErrorFormat, errors.ErrDatabaseError.Message, err)
+		return nil, errors.ErrDatabaseError
+	}
+
+	return types.NewTopicMessage(allChunks), nil
+}
+
+// NewTopicMessageRepository creates an instance of a topicMessageRepository struct
+func NewTopicMessageRepository(dbClient interfaces.DbClient) interfaces.TopicMessageRepository {
+	return &topicMessageRepository{dbClient}
+}
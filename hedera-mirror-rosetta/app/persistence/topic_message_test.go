@@ -0,0 +1,164 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package persistence
+
+import (
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/db"
+	"github.com/hashgraph/hedera-protobufs-go/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
+)
+
+const topicId3001 int64 = 3001
+
+// run the suite
+func TestTopicMessageRepositorySuite(t *testing.T) {
+	suite.Run(t, new(topicMessageRepositorySuite))
+}
+
+type topicMessageRepositorySuite struct {
+	integrationTest
+	suite.Suite
+}
+
+func (suite *topicMessageRepositorySuite) TestFindBySequenceNumber() {
+	// given
+	db.CreateDbRecords(
+		dbClient,
+		getTopicMessage(100, 1, nil, nil, nil),
+		getTopicMessage(101, 2, nil, nil, nil),
+	)
+	repo := NewTopicMessageRepository(dbClient)
+
+	// when
+	actual, err := repo.FindBySequenceNumber(defaultContext, topicId3001, 2)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.True(suite.T(), actual.IsComplete())
+	assert.Equal(suite.T(), []byte{101}, actual.GetMessage())
+	assert.Equal(suite.T(), []byte{100}, actual.Chunks[0].PreviousRunningHash)
+}
+
+func (suite *topicMessageRepositorySuite) TestFindBySequenceNumberChunked() {
+	// given
+	initialTransactionId, _ := proto.Marshal(&services.TransactionID{
+		AccountID:             &services.AccountID{Account: &services.AccountID_AccountNum{AccountNum: 1001}},
+		TransactionValidStart: &services.Timestamp{Nanos: 90},
+	})
+	otherTransactionId, _ := proto.Marshal(&services.TransactionID{
+		AccountID:             &services.AccountID{Account: &services.AccountID_AccountNum{AccountNum: 1002}},
+		TransactionValidStart: &services.Timestamp{Nanos: 90},
+	})
+	chunkTotal := int32(3)
+	db.CreateDbRecords(
+		dbClient,
+		getTopicMessage(100, 1, int32Ptr(1), &chunkTotal, initialTransactionId),
+		// interleaved chunk of another message
+		getTopicMessage(101, 2, int32Ptr(1), &chunkTotal, otherTransactionId),
+		getTopicMessage(102, 3, int32Ptr(2), &chunkTotal, initialTransactionId),
+		getTopicMessage(103, 4, int32Ptr(3), &chunkTotal, initialTransactionId),
+	)
+	repo := NewTopicMessageRepository(dbClient)
+
+	// when
+	actual, err := repo.FindBySequenceNumber(defaultContext, topicId3001, 3)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.True(suite.T(), actual.IsComplete())
+	assert.Equal(suite.T(), []byte{100, 102, 103}, actual.GetMessage())
+	assert.Equal(suite.T(), []byte{101}, actual.Chunks[1].PreviousRunningHash)
+}
+
+func (suite *topicMessageRepositorySuite) TestFindBySequenceNumberIncomplete() {
+	// given
+	initialTransactionId, _ := proto.Marshal(&services.TransactionID{
+		AccountID:             &services.AccountID{Account: &services.AccountID_AccountNum{AccountNum: 1001}},
+		TransactionValidStart: &services.Timestamp{Nanos: 90},
+	})
+	chunkTotal := int32(2)
+	db.CreateDbRecords(dbClient, getTopicMessage(100, 1, int32Ptr(1), &chunkTotal, initialTransactionId))
+	repo := NewTopicMessageRepository(dbClient)
+
+	// when
+	actual, err := repo.FindBySequenceNumber(defaultContext, topicId3001, 1)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.False(suite.T(), actual.IsComplete())
+	assert.Len(suite.T(), actual.Chunks, 1)
+	assert.Nil(suite.T(), actual.Chunks[0].PreviousRunningHash)
+}
+
+func (suite *topicMessageRepositorySuite) TestFindBySequenceNumberNotFound() {
+	// given
+	db.CreateDbRecords(dbClient, getTopicMessage(100, 1, nil, nil, nil))
+	repo := NewTopicMessageRepository(dbClient)
+
+	// when
+	actual, err := repo.FindBySequenceNumber(defaultContext, topicId3001, 2)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrTopicMessageNotFound, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *topicMessageRepositorySuite) TestFindBySequenceNumberDbConnectionError() {
+	// given
+	repo := NewTopicMessageRepository(invalidDbClient)
+
+	// when
+	actual, err := repo.FindBySequenceNumber(defaultContext, topicId3001, 1)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func getTopicMessage(
+	consensusTimestamp, sequenceNumber int64,
+	chunkNum, chunkTotal *int32,
+	initialTransactionId []byte,
+) *domain.TopicMessage {
+	payerAccountId := domain.MustDecodeEntityId(1001)
+	return &domain.TopicMessage{
+		ChunkNum:             chunkNum,
+		ChunkTotal:           chunkTotal,
+		ConsensusTimestamp:   consensusTimestamp,
+		InitialTransactionId: initialTransactionId,
+		Message:              []byte{byte(consensusTimestamp)},
+		PayerAccountId:       &payerAccountId,
+		RunningHash:          []byte{byte(consensusTimestamp)},
+		RunningHashVersion:   3,
+		SequenceNumber:       sequenceNumber,
+		TopicId:              domain.MustDecodeEntityId(topicId3001),
+	}
+}
+
+func int32Ptr(value int32) *int32 {
+	return &value
+}
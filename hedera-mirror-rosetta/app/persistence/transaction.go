@@ -25,15 +25,24 @@ import (
 	"database/sql"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
 
 	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/builder"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
 	hErrors "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/tools"
+	"github.com/jackc/pgx/v4"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+	"gorm.io/gorm"
 )
 
 const (
@@ -43,9 +52,73 @@ const (
 	transactionResultSuccessButMissingExpectedOperation int32 = 220
 )
 
+const (
+	columnTransactionBytes       = "transaction_bytes"
+	columnTransactionRecordBytes = "transaction_record_bytes"
+	// selectOptionalTransactionColumns selects which of the optional transaction bytes columns exist. The columns are
+	// only populated when the importer is configured to persist the transaction bytes and the record bytes
+	selectOptionalTransactionColumns = `select column_name
+                                        from information_schema.columns
+                                        where table_name = 'transaction' and column_name in (?)`
+	// selectTransactionBytesInTimestampRange selects the stored raw bytes of the transactions in the timestamp range,
+	// the placeholder is replaced with the existing optional columns
+	selectTransactionBytesInTimestampRange = `select consensus_timestamp, %s
+                                              from transaction
+                                              where consensus_timestamp >= @start and consensus_timestamp <= @end`
+)
+
 const (
 	andTransactionHashFilter  = " and transaction_hash = @hash"
 	orderByConsensusTimestamp = " order by consensus_timestamp"
+	// selectTransactionAndOperationCountInTimestampRange selects the number of unique transactions and the estimated
+	// number of operations, i.e., one per crypto transfer, token transfer, and nft transfer sender / receiver
+	selectTransactionAndOperationCountInTimestampRange = "with" + genesisTimestampCte + `select
+        (
+          select count(distinct transaction_hash)
+          from transaction
+          where consensus_timestamp >= @start and consensus_timestamp <= @end
+        ) as transaction_count,
+        (
+          select count(*)
+          from crypto_transfer
+          where consensus_timestamp >= @start and consensus_timestamp <= @end and
+            (errata is null or errata <> 'DELETE')
+        ) + (
+          select count(*)
+          from token_transfer tkt
+          join token tk on tk.token_id = tkt.token_id
+          join genesis on tk.created_timestamp > genesis.timestamp
+          where tkt.consensus_timestamp >= @start and tkt.consensus_timestamp <= @end
+        ) + (
+          select count(nftt.receiver_account_id) + count(nftt.sender_account_id)
+          from nft_transfer nftt
+          join token tk on tk.token_id = nftt.token_id
+          join genesis on tk.created_timestamp > genesis.timestamp
+          where nftt.consensus_timestamp >= @start and nftt.consensus_timestamp <= @end and serial_number <> -1
+        ) as operation_count`
+	// selectOperationCountInTimestampRange selects the estimated number of operations the same way as
+	// selectTransactionAndOperationCountInTimestampRange, except the token and nft transfers of the tokens created at
+	// or before the genesis balance snapshot are also counted, so it only scans the transfer tables by timestamp
+	selectOperationCountInTimestampRange = `select
+        (
+          select count(*)
+          from crypto_transfer
+          where consensus_timestamp >= @start and consensus_timestamp <= @end and
+            (errata is null or errata <> 'DELETE')
+        ) + (
+          select count(*)
+          from token_transfer
+          where consensus_timestamp >= @start and consensus_timestamp <= @end
+        ) + (
+          select count(receiver_account_id) + count(sender_account_id)
+          from nft_transfer
+          where consensus_timestamp >= @start and consensus_timestamp <= @end and serial_number <> -1
+        ) as operation_count`
+	// selectTransactionCountByTypeAndResultInTimestampRange selects the number of transactions of each type and result
+	selectTransactionCountByTypeAndResultInTimestampRange = `select type, result, count(*) as count
+      from transaction
+      where consensus_timestamp >= @start and consensus_timestamp <= @end
+      group by type, result`
 	// selectDissociateTokenTransfersInTimestampRange selects the token transfers and nft transfers for successful token
 	// dissociate which dissociates an account from tokens which are already deleted
 	selectDissociateTokenTransfersInTimestampRange = "with" + genesisTimestampCte + `
@@ -128,21 +201,25 @@ const (
       full outer join nft_xfer nx
         on fx.consensus_timestamp = nx.consensus_timestamp
       order by coalesce(fx.consensus_timestamp, nx.consensus_timestamp)`
-	// selectTransactionsInTimestampRange selects the transactions with its crypto transfers in json, non-fee transfers
-	// in json, token transfers in json, and optionally the token information when the transaction is token create,
-	// token delete, or token update. Note the three token transactions are the ones the entity_id in the transaction
-	// table is its related token id and require an extra rosetta operation
-	selectTransactionsInTimestampRange = "with" + genesisTimestampCte + `select
+	// selectTransactionColumns are the columns of the transaction table selected by the transaction queries
+	selectTransactionColumns = `
+                                            t.charged_tx_fee,
                                             t.consensus_timestamp,
                                             t.entity_id,
+                                            t.node_account_id,
                                             t.payer_account_id,
                                             t.result,
+                                            t.scheduled,
                                             t.transaction_hash as hash,
-                                            t.type,
+                                            t.type,`
+	// jsonTransferColumns are the crypto transfers, non-fee transfers, token transfers, and nft transfers of the
+	// transaction, each aggregated into a json array
+	jsonTransferColumns = `
                                             coalesce((
                                               select json_agg(json_build_object(
                                                 'account_id', entity_id,
-                                                'amount', amount) order by entity_id)
+                                                'amount', amount,
+                                                'is_approval', coalesce(is_approval, false)) order by entity_id)
                                               from crypto_transfer
                                               where consensus_timestamp = t.consensus_timestamp and
                                                 (errata is null or errata <> 'DELETE')
@@ -150,7 +227,8 @@ const (
                                             coalesce((
                                                   select json_agg(json_build_object(
                                                       'account_id', entity_id,
-                                                      'amount', amount
+                                                      'amount', amount,
+                                                      'is_approval', coalesce(is_approval, false)
                                                     ) order by entity_id)
                                                   from non_fee_transfer
                                                   where consensus_timestamp = t.consensus_timestamp
@@ -160,6 +238,7 @@ const (
                                                   'account_id', account_id,
                                                   'amount', amount,
                                                   'decimals', tk.decimals,
+                                                  'is_approval', coalesce(tkt.is_approval, false),
                                                   'token_id', tkt.token_id,
                                                   'type', tk.type
                                                 ) order by account_id, tk.token_id)
@@ -170,6 +249,7 @@ const (
                                             ), '[]') as token_transfers,
                                             coalesce((
                                               select json_agg(json_build_object(
+                                                  'is_approval', coalesce(nftt.is_approval, false),
                                                   'receiver_account_id', receiver_account_id,
                                                   'sender_account_id', sender_account_id,
                                                   'serial_number', serial_number,
@@ -179,7 +259,135 @@ const (
                                               join token tk on tk.token_id = nftt.token_id
                                               join genesis on tk.created_timestamp > genesis.timestamp
                                               where nftt.consensus_timestamp = t.consensus_timestamp and serial_number <> -1
-                                            ), '[]') as nft_transfers,
+                                            ), '[]') as nft_transfers,`
+	// transferArrayColumns are the same transfers as jsonTransferColumns, selected as the typed arrays aggregated by
+	// transferArrayJoins. They are scanned directly by pgx, which avoids encoding and decoding the transfers as json
+	transferArrayColumns = `
+                                            crypto_arrays.account_ids,
+                                            crypto_arrays.amounts,
+                                            crypto_arrays.is_approvals,
+                                            non_fee_arrays.account_ids,
+                                            non_fee_arrays.amounts,
+                                            non_fee_arrays.is_approvals,
+                                            token_arrays.account_ids,
+                                            token_arrays.amounts,
+                                            token_arrays.decimals,
+                                            token_arrays.is_approvals,
+                                            token_arrays.token_ids,
+                                            token_arrays.types,
+                                            nft_arrays.is_approvals,
+                                            nft_arrays.receiver_account_ids,
+                                            nft_arrays.sender_account_ids,
+                                            nft_arrays.serial_numbers,
+                                            nft_arrays.token_ids,`
+	// transferArrayJoins aggregates each kind of transfers of the transaction into one typed array per field. Every
+	// array of a kind is ordered by all its fields, so the arrays line up even when there are ties
+	transferArrayJoins = `
+                                          left join lateral (
+                                            select
+                                              array_agg(entity_id order by entity_id, amount, is_approval)
+                                                as account_ids,
+                                              array_agg(amount order by entity_id, amount, is_approval)
+                                                as amounts,
+                                              array_agg(is_approval order by entity_id, amount, is_approval)
+                                                as is_approvals
+                                            from (
+                                              select entity_id, amount, coalesce(is_approval, false) as is_approval
+                                              from crypto_transfer
+                                              where consensus_timestamp = t.consensus_timestamp and
+                                                (errata is null or errata <> 'DELETE')
+                                            ) as transfer
+                                          ) as crypto_arrays on true
+                                          left join lateral (
+                                            select
+                                              array_agg(entity_id order by entity_id, amount, is_approval)
+                                                as account_ids,
+                                              array_agg(amount order by entity_id, amount, is_approval)
+                                                as amounts,
+                                              array_agg(is_approval order by entity_id, amount, is_approval)
+                                                as is_approvals
+                                            from (
+                                              select entity_id, amount, coalesce(is_approval, false) as is_approval
+                                              from non_fee_transfer
+                                              where consensus_timestamp = t.consensus_timestamp
+                                            ) as transfer
+                                          ) as non_fee_arrays on true
+                                          left join lateral (
+                                            select
+                                              array_agg(account_id order by account_id, token_id, amount, is_approval)
+                                                as account_ids,
+                                              array_agg(amount order by account_id, token_id, amount, is_approval)
+                                                as amounts,
+                                              array_agg(decimals order by account_id, token_id, amount, is_approval)
+                                                as decimals,
+                                              array_agg(is_approval order by account_id, token_id, amount, is_approval)
+                                                as is_approvals,
+                                              array_agg(token_id order by account_id, token_id, amount, is_approval)
+                                                as token_ids,
+                                              array_agg(type order by account_id, token_id, amount, is_approval)
+                                                as types
+                                            from (
+                                              select
+                                                tkt.account_id,
+                                                tkt.amount,
+                                                tk.decimals,
+                                                coalesce(tkt.is_approval, false) as is_approval,
+                                                tkt.token_id,
+                                                tk.type::text as type
+                                              from token_transfer tkt
+                                              join token tk on tk.token_id = tkt.token_id
+                                              join genesis on tk.created_timestamp > genesis.timestamp
+                                              where tkt.consensus_timestamp = t.consensus_timestamp
+                                            ) as transfer
+                                          ) as token_arrays on true
+                                          left join lateral (
+                                            select
+                                              array_agg(is_approval order by token_id, serial_number, sender_account_id,
+                                                receiver_account_id, is_approval) as is_approvals,
+                                              array_agg(receiver_account_id order by token_id, serial_number,
+                                                sender_account_id, receiver_account_id, is_approval)
+                                                as receiver_account_ids,
+                                              array_agg(sender_account_id order by token_id, serial_number,
+                                                sender_account_id, receiver_account_id, is_approval)
+                                                as sender_account_ids,
+                                              array_agg(serial_number order by token_id, serial_number,
+                                                sender_account_id, receiver_account_id, is_approval) as serial_numbers,
+                                              array_agg(token_id order by token_id, serial_number, sender_account_id,
+                                                receiver_account_id, is_approval) as token_ids
+                                            from (
+                                              select
+                                                coalesce(nftt.is_approval, false) as is_approval,
+                                                nftt.receiver_account_id,
+                                                nftt.sender_account_id,
+                                                nftt.serial_number,
+                                                nftt.token_id
+                                              from nft_transfer nftt
+                                              join token tk on tk.token_id = nftt.token_id
+                                              join genesis on tk.created_timestamp > genesis.timestamp
+                                              where nftt.consensus_timestamp = t.consensus_timestamp and
+                                                nftt.serial_number <> -1
+                                            ) as transfer
+                                          ) as nft_arrays on true`
+	// deletedEntityColumns are the ids and the deleted timestamps of the entities involved in the transfers of the
+	// transaction which are deleted at or before the transaction, aggregated by deletedEntityJoin
+	deletedEntityColumns = `
+                                            deleted_entities.ids,
+                                            deleted_entities.timestamps,`
+	// deletedEntityJoin aggregates the deleted entities among the accounts of the transfer arrays, it must follow
+	// transferArrayJoins. The deleted timestamp is the lower bound of the current entity's timestamp range
+	deletedEntityJoin = `
+                                          left join lateral (
+                                            select
+                                              array_agg(e.id order by e.id) as ids,
+                                              array_agg(lower(e.timestamp_range) order by e.id) as timestamps
+                                            from entity e
+                                            where e.id = any(crypto_arrays.account_ids || token_arrays.account_ids ||
+                                                nft_arrays.receiver_account_ids || nft_arrays.sender_account_ids) and
+                                              e.deleted is true and
+                                              lower(e.timestamp_range) <= t.consensus_timestamp
+                                          ) as deleted_entities on true`
+	// tokenAndScheduleColumns are the token information and the schedule information in json
+	tokenAndScheduleColumns = `
                                             case
                                               when t.type in (29, 35, 36) then coalesce((
                                                   select json_build_object(
@@ -194,146 +402,446 @@ const (
                                                   where token_id = t.entity_id
                                                 ), '{}')
                                               else '{}'
-                                            end as token
+                                            end as token,
+                                            case
+                                              when t.type in (42, 43, 44) then coalesce((
+                                                  select json_build_object(
+                                                    'expiration_time', expiration_time,
+                                                    'schedule_id', schedule_id,
+                                                    'wait_for_expiry', wait_for_expiry
+                                                  )
+                                                  from schedule
+                                                  where schedule_id = t.entity_id
+                                                ), '{}')
+                                              when t.scheduled then coalesce((
+                                                  select json_build_object(
+                                                    'expiration_time', expiration_time,
+                                                    'schedule_id', schedule_id,
+                                                    'wait_for_expiry', wait_for_expiry
+                                                  )
+                                                  from schedule
+                                                  where executed_timestamp = t.consensus_timestamp
+                                                ), '{}')
+                                              else '{}'
+                                            end as schedule`
+	// hollowAccountKeyColumn is the key assigned to the payer by the transaction when the transaction completes the payer
+	// as a hollow account, i.e., an account with an evm address alias and an empty key, otherwise null. A hollow account
+	// is completed by the first transaction it pays for, which sets the key it signed the transaction with
+	hollowAccountKeyColumn = `,
+                                            (
+                                              select completed.key
+                                              from entity_history h
+                                              join lateral (
+                                                select key from entity
+                                                where id = h.id and lower(timestamp_range) = t.consensus_timestamp
+                                                union all
+                                                select key from entity_history
+                                                where id = h.id and lower(timestamp_range) = t.consensus_timestamp
+                                              ) as completed
+                                                on completed.key is not null and completed.key not in ('', '\x3200')
+                                              where h.id = t.payer_account_id and
+                                                upper(h.timestamp_range) = t.consensus_timestamp and
+                                                h.evm_address is not null and
+                                                (h.key is null or h.key in ('', '\x3200'))
+                                              limit 1
+                                            ) as hollow_account_key`
+	// selectTransactionsInTimestampRange selects the transactions with its crypto transfers in json, non-fee transfers
+	// in json, token transfers in json, and optionally the token information when the transaction is token create,
+	// token delete, or token update. Note the three token transactions are the ones the entity_id in the transaction
+	// table is its related token id and require an extra rosetta operation. Similarly, the schedule information is
+	// selected for schedule create, schedule delete, and schedule sign, whose entity_id is the schedule id, and for
+	// the executed scheduled transaction. The key of the completed hollow account is selected by hollowAccountKeyColumn
+	selectTransactionsInTimestampRange = "with" + genesisTimestampCte + "select" + selectTransactionColumns +
+		jsonTransferColumns + tokenAndScheduleColumns + hollowAccountKeyColumn + `
                                           from transaction t
                                           where consensus_timestamp >= @start and consensus_timestamp <= @end`
-	selectTransactionsByHashInTimestampRange  = selectTransactionsInTimestampRange + andTransactionHashFilter
-	selectTransactionsInTimestampRangeOrdered = selectTransactionsInTimestampRange + orderByConsensusTimestamp
+	// selectTransactionsWithTransferArraysInTimestampRange is selectTransactionsInTimestampRange with the transfers
+	// selected as typed arrays, and the deleted entities involved in the transfers
+	selectTransactionsWithTransferArraysInTimestampRange = "with" + genesisTimestampCte + "select" +
+		selectTransactionColumns + transferArrayColumns + deletedEntityColumns + tokenAndScheduleColumns +
+		hollowAccountKeyColumn + `
+                                          from transaction t` + transferArrayJoins + deletedEntityJoin + `
+                                          where t.consensus_timestamp >= @start and t.consensus_timestamp <= @end`
+	// selectTransactionHashesInTimestampRange selects the unique transaction hashes in chronological order of their
+	// first occurrence
+	selectTransactionHashesInTimestampRange = `select transaction_hash as hash
+                                               from transaction
+                                               where consensus_timestamp >= @start and consensus_timestamp <= @end
+                                               group by transaction_hash
+                                               order by min(consensus_timestamp)`
+	// selectTransactionKeysBySearch selects the consensus timestamp and hash of a page of the transactions matching a
+	// search, the placeholder is replaced with the filters of the search
+	selectTransactionKeysBySearch = `select consensus_timestamp, transaction_hash as hash
+                                     from transaction
+                                     where consensus_timestamp > @after and consensus_timestamp <= @end%s
+                                     order by consensus_timestamp
+                                     limit @limit`
+	// selectTransactionCountBySearch selects the total number of transactions matching a search, the placeholder is
+	// replaced with the filters of the search
+	selectTransactionCountBySearch = `select count(*) as count
+                                      from transaction
+                                      where consensus_timestamp <= @end%s`
+	// andAccountTransferFilter matches the transactions transferring hbar, fungible tokens, or nfts to or from the
+	// account
+	andAccountTransferFilter = ` and consensus_timestamp in (
+                                   select consensus_timestamp
+                                   from crypto_transfer
+                                   where entity_id = @account_id and consensus_timestamp <= @end
+                                   union all
+                                   select consensus_timestamp
+                                   from token_transfer
+                                   where account_id = @account_id and consensus_timestamp <= @end
+                                   union all
+                                   select consensus_timestamp
+                                   from nft_transfer
+                                   where (receiver_account_id = @account_id or sender_account_id = @account_id) and
+                                     consensus_timestamp <= @end
+                                 )`
+	selectTransactionsInTimestampRangeOrdered  = selectTransactionsInTimestampRange + orderByConsensusTimestamp
+	selectRawTransactionByHashInTimestampRange = `select *
+                                                   from transaction
+                                                   where consensus_timestamp >= @start and
+                                                     consensus_timestamp <= @end and
+                                                     transaction_hash = @hash
+                                                   order by consensus_timestamp
+                                                   limit 1`
+)
+
+var (
+	// pgxSelectTransactionsByHashInTimestampRange selects the transactions with the hash in the timestamp range, with
+	// the transfers as typed arrays and pgx parameters
+	pgxSelectTransactionsByHashInTimestampRange = namedToPositional.Replace(
+		selectTransactionsWithTransferArraysInTimestampRange + andTransactionHashFilter + orderByConsensusTimestamp,
+	)
+	// pgxSelectTransactionsInTimestampRangeOrderedLimit selects a batch of the transactions in the timestamp range,
+	// with the transfers as typed arrays and pgx parameters
+	pgxSelectTransactionsInTimestampRangeOrderedLimit = namedToPositional.Replace(
+		selectTransactionsWithTransferArraysInTimestampRange+orderByConsensusTimestamp,
+	) + " limit $3"
 )
 
 // transaction maps to the transaction query which returns the required transaction fields, CryptoTransfers json string,
-// NonFeeTransfers json string, TokenTransfers json string, and Token definition json string
+// NonFeeTransfers json string, TokenTransfers json string, Token definition json string, and Schedule json string. When
+// scanned by pgx, the transfers are the typed arrays in transferArrays instead of the json strings
 type transaction struct {
-	ConsensusTimestamp int64
-	EntityId           *domain.EntityId
-	Hash               []byte
-	PayerAccountId     domain.EntityId
-	Result             int16
-	Type               int16
-	CryptoTransfers    string
-	NftTransfers       string
-	NonFeeTransfers    string
-	TokenTransfers     string
-	Token              string
+	ChargedTxFee           int64
+	ConsensusTimestamp     int64
+	EntityId               *domain.EntityId
+	Hash                   []byte
+	NodeAccountId          *domain.EntityId
+	PayerAccountId         domain.EntityId
+	Result                 int16
+	Scheduled              bool
+	Type                   int16
+	CryptoTransfers        string
+	NftTransfers           string
+	NonFeeTransfers        string
+	TokenTransfers         string
+	Token                  string
+	Schedule               string
+	HollowAccountKey       []byte
+	PendingAirdrops        []types.PendingAirdrop `gorm:"-"`
+	TransactionBytes       []byte                 `gorm:"-"`
+	TransactionRecordBytes []byte                 `gorm:"-"`
+
+	deletedEntities deletedEntityArrays
+	transferArrays  transferArrays
 }
 
-func (t transaction) getHashString() string {
-	return tools.SafeAddHexPrefix(hex.EncodeToString(t.Hash))
+// transactionBytes maps to the optional raw bytes columns of a transaction
+type transactionBytes struct {
+	ConsensusTimestamp     int64
+	TransactionBytes       []byte
+	TransactionRecordBytes []byte
 }
 
-type transfer interface {
-	getAccountId() domain.EntityId
-	getAmount() types.Amount
-}
+// scanTransaction scans the current row of the transaction query into a transaction
+func scanTransaction(rows pgx.Rows) (*transaction, error) {
+	var chargedTxFee, entityId, nodeAccountId *int64
+	var payerAccountId int64
+	crypto := &hbarTransferArrays{}
+	nonFee := &hbarTransferArrays{}
+	token := &tokenTransferArrays{}
+	nft := &nftTransferArrays{}
+	t := &transaction{
+		transferArrays: transferArrays{crypto: crypto, nft: nft, nonFee: nonFee, token: token},
+	}
+	if err := rows.Scan(
+		&chargedTxFee,
+		&t.ConsensusTimestamp,
+		&entityId,
+		&nodeAccountId,
+		&payerAccountId,
+		&t.Result,
+		&t.Scheduled,
+		&t.Hash,
+		&t.Type,
+		&crypto.accountIds,
+		&crypto.amounts,
+		&crypto.isApprovals,
+		&nonFee.accountIds,
+		&nonFee.amounts,
+		&nonFee.isApprovals,
+		&token.accountIds,
+		&token.amounts,
+		&token.decimals,
+		&token.isApprovals,
+		&token.tokenIds,
+		&token.types,
+		&nft.isApprovals,
+		&nft.receiverAccountIds,
+		&nft.senderAccountIds,
+		&nft.serialNumbers,
+		&nft.tokenIds,
+		&t.deletedEntities.ids,
+		&t.deletedEntities.timestamps,
+		&t.Token,
+		&t.Schedule,
+		&t.HollowAccountKey,
+	); err != nil {
+		return nil, err
+	}
 
-type hbarTransfer struct {
-	AccountId domain.EntityId `json:"account_id"`
-	Amount    int64           `json:"amount"`
-}
+	if chargedTxFee != nil {
+		t.ChargedTxFee = *chargedTxFee
+	}
 
-func (t hbarTransfer) getAccountId() domain.EntityId {
-	return t.AccountId
-}
+	var err error
+	if t.EntityId, err = decodeNullableEntityId(entityId); err != nil {
+		return nil, err
+	}
+
+	if t.NodeAccountId, err = decodeNullableEntityId(nodeAccountId); err != nil {
+		return nil, err
+	}
+
+	if t.PayerAccountId, err = domain.DecodeEntityId(payerAccountId); err != nil {
+		return nil, err
+	}
 
-func (t hbarTransfer) getAmount() types.Amount {
-	return &types.HbarAmount{Value: t.Amount}
+	return t, nil
 }
 
-type singleNftTransfer struct {
-	accountId    domain.EntityId
-	receiver     bool
-	serialNumber int64
-	tokenId      domain.EntityId
+func decodeNullableEntityId(encodedId *int64) (*domain.EntityId, error) {
+	if encodedId == nil {
+		return nil, nil
+	}
+
+	entityId, err := domain.DecodeEntityId(*encodedId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entityId, nil
 }
 
-func (n singleNftTransfer) getAccountId() domain.EntityId {
-	return n.accountId
+// decode decodes the json columns of the transaction to the record the operations are built from
+func (t transaction) decode() (builder.Transaction, error) {
+	var decoded builder.Transaction
+	if err := t.decodeInto(&decoded); err != nil {
+		return builder.Transaction{}, err
+	}
+
+	return decoded, nil
 }
 
-func (n singleNftTransfer) getAmount() types.Amount {
-	amount := int64(1)
-	if !n.receiver {
-		amount = -1
+// decodeInto decodes the transaction into the target, reusing the target's transfer slices
+func (t transaction) decodeInto(decoded *builder.Transaction) error {
+	*decoded = builder.Transaction{
+		ChargedTxFee:     t.ChargedTxFee,
+		CryptoTransfers:  resetSlice(decoded.CryptoTransfers),
+		HollowAccountKey: t.HollowAccountKey,
+		NftTransfers:     resetSlice(decoded.NftTransfers),
+		NodeAccountId:    t.NodeAccountId,
+		NonFeeTransfers:  resetSlice(decoded.NonFeeTransfers),
+		PayerAccountId:   t.PayerAccountId,
+		PendingAirdrops:  t.PendingAirdrops,
+		RecordBytes:      t.TransactionRecordBytes,
+		Result:           int32(t.Result),
+		Scheduled:        t.Scheduled,
+		TokenTransfers:   resetSlice(decoded.TokenTransfers),
+		Type:             int32(t.Type),
+	}
+
+	if err := t.transferArrays.decodeInto(decoded); err != nil {
+		return err
+	}
+
+	var err error
+	if decoded.DeletedEntities, err = t.deletedEntities.toMap(); err != nil {
+		return err
 	}
 
-	return &types.TokenAmount{
-		SerialNumbers: []int64{n.serialNumber},
-		TokenId:       n.tokenId,
-		Type:          domain.TokenTypeNonFungibleUnique,
-		Value:         amount,
+	columns := []struct {
+		data   string
+		target interface{}
+		typed  bool
+	}{
+		{t.CryptoTransfers, &decoded.CryptoTransfers, t.transferArrays.crypto != nil},
+		{t.NonFeeTransfers, &decoded.NonFeeTransfers, t.transferArrays.nonFee != nil},
+		{t.TokenTransfers, &decoded.TokenTransfers, t.transferArrays.token != nil},
+		{t.NftTransfers, &decoded.NftTransfers, t.transferArrays.nft != nil},
+		{t.Token, &decoded.Token, false},
+		{t.Schedule, &decoded.Schedule, false},
 	}
+	for _, column := range columns {
+		if column.typed {
+			continue
+		}
+
+		if err := json.Unmarshal([]byte(column.data), column.target); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-type tokenTransfer struct {
-	AccountId domain.EntityId `json:"account_id"`
-	Amount    int64           `json:"amount"`
-	Decimals  int64           `json:"decimals"`
-	TokenId   domain.EntityId `json:"token_id"`
-	Type      string          `json:"type"`
+func (t transaction) getHashString() string {
+	return tools.SafeAddHexPrefix(hex.EncodeToString(t.Hash))
 }
 
-func (t tokenTransfer) getAccountId() domain.EntityId {
-	return t.AccountId
+type transactionAndOperationCount struct {
+	OperationCount   int64
+	TransactionCount int64
 }
 
-func (t tokenTransfer) getAmount() types.Amount {
-	return &types.TokenAmount{
-		Decimals: t.Decimals,
-		TokenId:  t.TokenId,
-		Type:     t.Type,
-		Value:    t.Amount,
-	}
+type searchCount struct {
+	Count int64
 }
 
 // transactionRepository struct that has connection to the Database
 type transactionRepository struct {
-	once     sync.Once
-	dbClient interfaces.DbClient
-	types    map[int]string
+	dbClient            interfaces.DbClient
+	feeBreakdownBuilder builder.FeeBreakdownBuilder
+	invariantChecker    builder.InvariantChecker // nil if the invariant check is disabled
+	operationBuilder    builder.OperationBuilder
+	rangeSplit          config.DbRangeSplit
+	tokenAirdrop        *optionalTable
+
+	// optionalColumns is the list of the optional raw bytes columns, nil until detected
+	optionalColumns      []string
+	optionalColumnsMutex sync.Mutex
 }
 
 // NewTransactionRepository creates an instance of a TransactionRepository struct
-func NewTransactionRepository(dbClient interfaces.DbClient) interfaces.TransactionRepository {
-	return &transactionRepository{dbClient: dbClient}
+func NewTransactionRepository(
+	dbClient interfaces.DbClient,
+	systemAccounts config.SystemAccounts,
+	suppressEmptyOperations bool,
+	invariantCheck bool,
+	rangeSplit config.DbRangeSplit,
+) interfaces.TransactionRepository {
+	var invariantChecker builder.InvariantChecker
+	if invariantCheck {
+		invariantChecker = builder.NewInvariantChecker()
+	}
+
+	return &transactionRepository{
+		dbClient:            dbClient,
+		feeBreakdownBuilder: builder.NewFeeBreakdownBuilder(systemAccounts),
+		invariantChecker:    invariantChecker,
+		operationBuilder:    builder.NewOperationBuilder(systemAccounts, suppressEmptyOperations),
+		rangeSplit:          rangeSplit,
+		tokenAirdrop:        newOptionalTable(tableNameTokenAirdrop),
+	}
 }
 
-func (tr *transactionRepository) FindBetween(ctx context.Context, start, end int64) (
-	[]*types.Transaction,
+func (tr *transactionRepository) CountBetween(ctx context.Context, start, end int64) (int64, int64, *rTypes.Error) {
+	if start > end {
+		return 0, 0, hErrors.ErrStartMustNotBeAfterEnd
+	}
+
+	var count transactionAndOperationCount
+	if err := tr.dbClient.Query(ctx, "selectTransactionAndOperationCountInTimestampRange", func(db *gorm.DB) error {
+		return db.Raw(
+			selectTransactionAndOperationCountInTimestampRange,
+			sql.Named("start", start),
+			sql.Named("end", end),
+		).First(&count).Error
+	}); err != nil {
+		log.Errorf(databaseErrorFormat, hErrors.ErrDatabaseError.Message, err)
+		return 0, 0, hErrors.ErrDatabaseError
+	}
+
+	return count.TransactionCount, count.OperationCount, nil
+}
+
+func (tr *transactionRepository) CountOperationsBetween(ctx context.Context, start, end int64) (int64, *rTypes.Error) {
+	if start > end {
+		return 0, hErrors.ErrStartMustNotBeAfterEnd
+	}
+
+	var count transactionAndOperationCount
+	if err := tr.dbClient.Query(ctx, "selectOperationCountInTimestampRange", func(db *gorm.DB) error {
+		return db.Raw(
+			selectOperationCountInTimestampRange,
+			sql.Named("start", start),
+			sql.Named("end", end),
+		).First(&count).Error
+	}); err != nil {
+		log.Errorf(databaseErrorFormat, hErrors.ErrDatabaseError.Message, err)
+		return 0, hErrors.ErrDatabaseError
+	}
+
+	return count.OperationCount, nil
+}
+
+func (tr *transactionRepository) CountByTypeAndResult(ctx context.Context, start, end int64) (
+	*types.TransactionStatistics,
 	*rTypes.Error,
 ) {
 	if start > end {
 		return nil, hErrors.ErrStartMustNotBeAfterEnd
 	}
 
-	db, cancel := tr.dbClient.GetDbWithContext(ctx)
-	defer cancel()
-
-	transactions := make([]*transaction, 0)
-	for start <= end {
-		transactionsBatch := make([]*transaction, 0)
-		err := db.
-			Raw(selectTransactionsInTimestampRangeOrdered, sql.Named("start", start), sql.Named("end", end)).
-			Limit(batchSize).
-			Find(&transactionsBatch).
-			Error
-		if err != nil {
-			log.Errorf(databaseErrorFormat, hErrors.ErrDatabaseError.Message, err)
-			return nil, hErrors.ErrDatabaseError
-		}
+	counts := make([]types.TransactionTypeCount, 0)
+	if err := tr.dbClient.Query(ctx, "selectTransactionCountByTypeAndResultInTimestampRange", func(db *gorm.DB) error {
+		return db.Raw(
+			selectTransactionCountByTypeAndResultInTimestampRange,
+			sql.Named("start", start),
+			sql.Named("end", end),
+		).Scan(&counts).Error
+	}); err != nil {
+		log.Errorf(databaseErrorFormat, hErrors.ErrDatabaseError.Message, err)
+		return nil, hErrors.ErrDatabaseError
+	}
 
-		transactions = append(transactions, transactionsBatch...)
+	return &types.TransactionStatistics{Counts: counts, End: end, Start: start}, nil
+}
 
-		if len(transactionsBatch) < batchSize {
-			break
-		}
+func (tr *transactionRepository) FindBetween(ctx context.Context, start, end int64) (
+	[]*types.Transaction,
+	*rTypes.Error,
+) {
+	if start > end {
+		return nil, hErrors.ErrStartMustNotBeAfterEnd
+	}
 
-		start = transactionsBatch[len(transactionsBatch)-1].ConsensusTimestamp + 1
+	var transactions []*transaction
+	var rErr *rTypes.Error
+	ranges := splitTimestampRange(start, end, tr.rangeSplit.PartitionInterval)
+	if len(ranges) == 1 {
+		transactions, rErr = tr.findTransactionsInRange(ctx, start, end)
+	} else {
+		transactions, rErr = tr.findTransactionsInRanges(ctx, ranges)
+	}
+	if rErr != nil {
+		return nil, rErr
 	}
 
 	if err := tr.processSuccessTokenDissociates(ctx, transactions, start, end); err != nil {
 		return nil, err
 	}
 
+	if err := tr.processPendingAirdrops(ctx, transactions, start, end); err != nil {
+		return nil, err
+	}
+
+	if err := tr.processTransactionBytes(ctx, transactions); err != nil {
+		return nil, err
+	}
+
 	hashes := make([]string, 0)
 	sameHashMap := make(map[string][]*transaction)
 	for _, t := range transactions {
@@ -358,6 +866,156 @@ func (tr *transactionRepository) FindBetween(ctx context.Context, start, end int
 	return res, nil
 }
 
+// findTransactionsInRange finds the transactions in the timestamp range in batches, ordered by consensus timestamp
+func (tr *transactionRepository) findTransactionsInRange(ctx context.Context, start, end int64) (
+	[]*transaction,
+	*rTypes.Error,
+) {
+	transactions := make([]*transaction, 0)
+	for start <= end {
+		var transactionsBatch []*transaction
+		if err := tr.dbClient.Query(ctx, "selectTransactionsInTimestampRangeOrdered", func(db *gorm.DB) error {
+			transactionsBatch = make([]*transaction, 0)
+			args := []interface{}{start, end, batchSize}
+			return queryRows(db, pgxSelectTransactionsInTimestampRangeOrderedLimit, args, func(rows pgx.Rows) error {
+				txn, err := scanTransaction(rows)
+				if err != nil {
+					return err
+				}
+
+				transactionsBatch = append(transactionsBatch, txn)
+				return nil
+			})
+		}); err != nil {
+			log.Errorf(databaseErrorFormat, hErrors.ErrDatabaseError.Message, err)
+			return nil, hErrors.ErrDatabaseError
+		}
+
+		transactions = append(transactions, transactionsBatch...)
+
+		if len(transactionsBatch) < batchSize {
+			break
+		}
+
+		start = transactionsBatch[len(transactionsBatch)-1].ConsensusTimestamp + 1
+	}
+
+	return transactions, nil
+}
+
+// findTransactionsInRanges concurrently finds the transactions in the consecutive timestamp ranges and merges them in
+// the order of the ranges. The remaining queries are canceled once one fails
+func (tr *transactionRepository) findTransactionsInRanges(ctx context.Context, ranges []timestampRange) (
+	[]*transaction,
+	*rTypes.Error,
+) {
+	var firstErr *rTypes.Error
+	var errOnce sync.Once
+	results := make([][]*transaction, len(ranges))
+	maxConcurrency := tr.rangeSplit.MaxConcurrency
+	if maxConcurrency < 1 {
+		maxConcurrency = 1
+	}
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(maxConcurrency)
+	for i := range ranges {
+		i := i
+		group.Go(func() error {
+			transactions, rErr := tr.findTransactionsInRange(groupCtx, ranges[i].start, ranges[i].end)
+			if rErr != nil {
+				errOnce.Do(func() { firstErr = rErr })
+				return errors.New(rErr.Message)
+			}
+
+			results[i] = transactions
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return nil, firstErr
+	}
+
+	count := 0
+	for _, transactions := range results {
+		count += len(transactions)
+	}
+
+	merged := make([]*transaction, 0, count)
+	for _, transactions := range results {
+		merged = append(merged, transactions...)
+	}
+	return merged, nil
+}
+
+func (tr *transactionRepository) FindHashesBetween(ctx context.Context, start, end int64) ([]string, *rTypes.Error) {
+	if start > end {
+		return nil, hErrors.ErrStartMustNotBeAfterEnd
+	}
+
+	transactions := make([]*transaction, 0)
+	if err := tr.dbClient.Query(ctx, "selectTransactionHashesInTimestampRange", func(db *gorm.DB) error {
+		return db.Raw(
+			selectTransactionHashesInTimestampRange,
+			sql.Named("start", start),
+			sql.Named("end", end),
+		).Find(&transactions).Error
+	}); err != nil {
+		log.Errorf(databaseErrorFormat, hErrors.ErrDatabaseError.Message, err)
+		return nil, hErrors.ErrDatabaseError
+	}
+
+	hashes := make([]string, 0, len(transactions))
+	for _, t := range transactions {
+		hashes = append(hashes, t.getHashString())
+	}
+	return hashes, nil
+}
+
+func (tr *transactionRepository) FindKeysBySearch(ctx context.Context, search types.TransactionSearch) (
+	[]types.TransactionKey,
+	int64,
+	*rTypes.Error,
+) {
+	filters := ""
+	args := []interface{}{
+		sql.Named("account_id", search.AccountId),
+		sql.Named("after", search.After),
+		sql.Named("end", search.End),
+		sql.Named("hash", search.Hash),
+		sql.Named("limit", search.Limit),
+	}
+	if search.AccountId != 0 {
+		filters += andAccountTransferFilter
+	}
+	if search.Hash != nil {
+		filters += andTransactionHashFilter
+	}
+
+	transactions := make([]*transaction, 0)
+	if err := tr.dbClient.Query(ctx, "selectTransactionKeysBySearch", func(db *gorm.DB) error {
+		return db.Raw(fmt.Sprintf(selectTransactionKeysBySearch, filters), args...).Find(&transactions).Error
+	}); err != nil {
+		log.Errorf(databaseErrorFormat, hErrors.ErrDatabaseError.Message, err)
+		return nil, 0, hErrors.ErrDatabaseError
+	}
+
+	var count searchCount
+	if err := tr.dbClient.Query(ctx, "selectTransactionCountBySearch", func(db *gorm.DB) error {
+		return db.Raw(fmt.Sprintf(selectTransactionCountBySearch, filters), args...).First(&count).Error
+	}); err != nil {
+		log.Errorf(databaseErrorFormat, hErrors.ErrDatabaseError.Message, err)
+		return nil, 0, hErrors.ErrDatabaseError
+	}
+
+	keys := make([]types.TransactionKey, 0, len(transactions))
+	for _, t := range transactions {
+		keys = append(keys, types.TransactionKey{ConsensusTimestamp: t.ConsensusTimestamp, Hash: t.getHashString()})
+	}
+	return keys, count.Count, nil
+}
+
 func (tr *transactionRepository) FindByHashInBlock(
 	ctx context.Context,
 	hashStr string,
@@ -370,15 +1028,19 @@ func (tr *transactionRepository) FindByHashInBlock(
 		return nil, hErrors.ErrInvalidTransactionIdentifier
 	}
 
-	db, cancel := tr.dbClient.GetDbWithContext(ctx)
-	defer cancel()
-
-	if err = db.Raw(
-		selectTransactionsByHashInTimestampRange,
-		sql.Named("hash", transactionHash),
-		sql.Named("start", consensusStart),
-		sql.Named("end", consensusEnd),
-	).Find(&transactions).Error; err != nil {
+	if err = tr.dbClient.Query(ctx, "selectTransactionsByHashInTimestampRange", func(db *gorm.DB) error {
+		transactions = make([]*transaction, 0)
+		args := []interface{}{consensusStart, consensusEnd, transactionHash}
+		return queryRows(db, pgxSelectTransactionsByHashInTimestampRange, args, func(rows pgx.Rows) error {
+			txn, err := scanTransaction(rows)
+			if err != nil {
+				return err
+			}
+
+			transactions = append(transactions, txn)
+			return nil
+		})
+	}); err != nil {
 		log.Errorf(databaseErrorFormat, hErrors.ErrDatabaseError.Message, err)
 		return nil, hErrors.ErrDatabaseError
 	}
@@ -387,6 +1049,32 @@ func (tr *transactionRepository) FindByHashInBlock(
 		return nil, hErrors.ErrTransactionNotFound
 	}
 
+	// only query the disappearing token transfers at the timestamp of each transaction with the hash instead of the
+	// whole block
+	for _, txn := range transactions {
+		if rErr := tr.processSuccessTokenDissociates(
+			ctx,
+			[]*transaction{txn},
+			txn.ConsensusTimestamp,
+			txn.ConsensusTimestamp,
+		); rErr != nil {
+			return nil, rErr
+		}
+
+		if rErr := tr.processPendingAirdrops(
+			ctx,
+			[]*transaction{txn},
+			txn.ConsensusTimestamp,
+			txn.ConsensusTimestamp,
+		); rErr != nil {
+			return nil, rErr
+		}
+	}
+
+	if rErr := tr.processTransactionBytes(ctx, transactions); rErr != nil {
+		return nil, rErr
+	}
+
 	transaction, rErr := tr.constructTransaction(transactions)
 	if rErr != nil {
 		return nil, rErr
@@ -395,152 +1083,150 @@ func (tr *transactionRepository) FindByHashInBlock(
 	return transaction, nil
 }
 
+func (tr *transactionRepository) FindRawByHashInBlock(
+	ctx context.Context,
+	hashStr string,
+	consensusStart int64,
+	consensusEnd int64,
+) (*types.RawTransaction, *rTypes.Error) {
+	transactionHash, err := hex.DecodeString(tools.SafeRemoveHexPrefix(hashStr))
+	if err != nil {
+		return nil, hErrors.ErrInvalidTransactionIdentifier
+	}
+
+	var transactions []domain.Transaction
+	if err = tr.dbClient.Query(ctx, "selectRawTransactionByHashInTimestampRange", func(db *gorm.DB) error {
+		return db.Raw(
+			selectRawTransactionByHashInTimestampRange,
+			sql.Named("hash", transactionHash),
+			sql.Named("start", consensusStart),
+			sql.Named("end", consensusEnd),
+		).Find(&transactions).Error
+	}); err != nil {
+		log.Errorf(databaseErrorFormat, hErrors.ErrDatabaseError.Message, err)
+		return nil, hErrors.ErrDatabaseError
+	}
+
+	if len(transactions) == 0 {
+		return nil, hErrors.ErrTransactionNotFound
+	}
+
+	return &types.RawTransaction{Transaction: transactions[0]}, nil
+}
+
 func (tr *transactionRepository) constructTransaction(sameHashTransactions []*transaction) (
 	*types.Transaction,
 	*rTypes.Error,
 ) {
+	// the operation indices must be stable across requests, so order the transactions with the same hash, e.g., the
+	// child records and the duplicates, by consensus timestamp
+	sort.SliceStable(sameHashTransactions, func(i, j int) bool {
+		return sameHashTransactions[i].ConsensusTimestamp < sameHashTransactions[j].ConsensusTimestamp
+	})
+
 	tResult := &types.Transaction{Hash: sameHashTransactions[0].getHashString()}
-	operations := make(types.OperationSlice, 0)
-	success := types.TransactionResults[transactionResultSuccess]
+	// the decoded transactions are only referenced until the operations and the fee breakdown are built
+	buffer := acquireDecodeBuffer()
+	defer buffer.release()
 
 	for _, transaction := range sameHashTransactions {
-		cryptoTransfers := make([]hbarTransfer, 0)
-		if err := json.Unmarshal([]byte(transaction.CryptoTransfers), &cryptoTransfers); err != nil {
-			return nil, hErrors.ErrInternalServerError
-		}
-
-		nonFeeTransfers := make([]hbarTransfer, 0)
-		if err := json.Unmarshal([]byte(transaction.NonFeeTransfers), &nonFeeTransfers); err != nil {
-			return nil, hErrors.ErrInternalServerError
-		}
-
-		tokenTransfers := make([]tokenTransfer, 0)
-		if err := json.Unmarshal([]byte(transaction.TokenTransfers), &tokenTransfers); err != nil {
-			return nil, hErrors.ErrInternalServerError
+		// the raw bytes of the first transaction with the hash are exposed
+		if len(tResult.TransactionBytes) == 0 && len(tResult.TransactionRecordBytes) == 0 {
+			tResult.TransactionBytes = transaction.TransactionBytes
+			tResult.TransactionRecordBytes = transaction.TransactionRecordBytes
 		}
 
-		nftTransfers := make([]domain.NftTransfer, 0)
-		if err := json.Unmarshal([]byte(transaction.NftTransfers), &nftTransfers); err != nil {
+		if err := transaction.decodeInto(buffer.next()); err != nil {
 			return nil, hErrors.ErrInternalServerError
 		}
 
-		token := domain.Token{}
-		if err := json.Unmarshal([]byte(transaction.Token), &token); err != nil {
-			return nil, hErrors.ErrInternalServerError
-		}
-
-		transactionResult := types.TransactionResults[int32(transaction.Result)]
-		transactionType := types.TransactionTypes[int32(transaction.Type)]
-
-		var feeHbarTransfers []hbarTransfer
-		feeHbarTransfers, nonFeeTransfers = categorizeHbarTransfers(cryptoTransfers, nonFeeTransfers)
-
-		operations = tr.appendHbarTransferOperations(transactionResult, transactionType, nonFeeTransfers, operations)
-		// crypto transfers are always successful regardless of the transaction result
-		operations = tr.appendHbarTransferOperations(success, types.OperationTypeFee, feeHbarTransfers, operations)
-		operations = tr.appendTokenTransferOperations(transactionResult, transactionType, tokenTransfers, operations)
-		operations = tr.appendNftTransferOperations(transactionResult, transactionType, nftTransfers, operations)
-
-		if !token.TokenId.IsZero() {
-			// only for TokenCreate, TokenDeletion, and TokenUpdate, TokenId is non-zero
-			operation := getTokenOperation(len(operations), token, transaction, transactionResult, transactionType)
-			operations = append(operations, operation)
-		}
-
 		if IsTransactionResultSuccessful(int32(transaction.Result)) {
 			tResult.EntityId = transaction.EntityId
 		}
 	}
 
-	tResult.Operations = operations
+	transactions := buffer.transactions
+	if tr.invariantChecker != nil {
+		for _, violation := range tr.invariantChecker.Check(transactions) {
+			log.Warnf("Invariant violated by transaction %s: %s", tResult.Hash, violation)
+		}
+	}
+
+	tResult.FeeBreakdown = tr.feeBreakdownBuilder.Build(transactions)
+	tResult.Operations = tr.operationBuilder.Build(transactions)
 	return tResult, nil
 }
 
-func (tr *transactionRepository) appendHbarTransferOperations(
-	transactionResult string,
-	operationType string,
-	hbarTransfers []hbarTransfer,
-	operations types.OperationSlice,
-) types.OperationSlice {
-	transfers := make([]transfer, 0, len(hbarTransfers))
-	for _, hbarTransfer := range hbarTransfers {
-		transfers = append(transfers, hbarTransfer)
-	}
+// getOptionalColumns returns the optional raw bytes columns which exist in the transaction table. The result is cached
+// once detected successfully
+func (tr *transactionRepository) getOptionalColumns(ctx context.Context) ([]string, *rTypes.Error) {
+	tr.optionalColumnsMutex.Lock()
+	defer tr.optionalColumnsMutex.Unlock()
 
-	return tr.appendTransferOperations(transactionResult, operationType, transfers, operations)
-}
+	if tr.optionalColumns != nil {
+		return tr.optionalColumns, nil
+	}
 
-func (tr *transactionRepository) appendNftTransferOperations(
-	transactionResult string,
-	operationType string,
-	nftTransfers []domain.NftTransfer,
-	operations types.OperationSlice,
-) types.OperationSlice {
-	transfers := make([]transfer, 0, 2*len(nftTransfers))
-	for _, nftTransfer := range nftTransfers {
-		transfers = append(transfers, getSingleNftTransfers(nftTransfer)...)
+	columns := make([]string, 0)
+	if err := tr.dbClient.Query(ctx, "selectOptionalTransactionColumns", func(db *gorm.DB) error {
+		return db.Raw(
+			selectOptionalTransactionColumns,
+			[]string{columnTransactionBytes, columnTransactionRecordBytes},
+		).Scan(&columns).Error
+	}); err != nil {
+		log.Errorf(databaseErrorFormat, hErrors.ErrDatabaseError.Message, err)
+		return nil, hErrors.ErrDatabaseError
 	}
 
-	return tr.appendTransferOperations(transactionResult, operationType, transfers, operations)
+	sort.Strings(columns)
+	log.Infof("Detected optional transaction columns %v", columns)
+	tr.optionalColumns = columns
+	return columns, nil
 }
 
-func (tr *transactionRepository) appendTokenTransferOperations(
-	transactionResult string,
-	operationType string,
-	tokenTransfers []tokenTransfer,
-	operations types.OperationSlice,
-) types.OperationSlice {
-	transfers := make([]transfer, 0, len(tokenTransfers))
-	for _, tokenTransfer := range tokenTransfers {
-		// The wiped amount of a deleted NFT class by a TokenDissociate is presented as tokenTransferList and
-		// saved to token_transfer table, filter it
-		if tokenTransfer.Type != domain.TokenTypeFungibleCommon {
-			continue
-		}
-
-		transfers = append(transfers, tokenTransfer)
+// processTransactionBytes sets the raw transaction bytes and record bytes of the transactions if the importer is
+// configured to persist them. Nothing is changed if the optional columns don't exist
+func (tr *transactionRepository) processTransactionBytes(ctx context.Context, transactions []*transaction) *rTypes.Error {
+	if len(transactions) == 0 {
+		return nil
 	}
 
-	return tr.appendTransferOperations(transactionResult, operationType, transfers, operations)
-}
+	columns, err := tr.getOptionalColumns(ctx)
+	if err != nil || len(columns) == 0 {
+		return err
+	}
 
-func (tr *transactionRepository) appendTransferOperations(
-	transactionResult string,
-	operationType string,
-	transfers []transfer,
-	operations types.OperationSlice,
-) types.OperationSlice {
-	for _, transfer := range transfers {
-		operations = append(operations, types.Operation{
-			AccountId: types.NewAccountIdFromEntityId(transfer.getAccountId()),
-			Amount:    transfer.getAmount(),
-			Index:     int64(len(operations)),
-			Status:    transactionResult,
-			Type:      operationType,
-		})
+	start := transactions[0].ConsensusTimestamp
+	end := start
+	for _, txn := range transactions[1:] {
+		if txn.ConsensusTimestamp < start {
+			start = txn.ConsensusTimestamp
+		} else if txn.ConsensusTimestamp > end {
+			end = txn.ConsensusTimestamp
+		}
+	}
+	query := fmt.Sprintf(selectTransactionBytesInTimestampRange, strings.Join(columns, ", "))
+	rows := make([]transactionBytes, 0)
+	if err := tr.dbClient.Query(ctx, "selectTransactionBytesInTimestampRange", func(db *gorm.DB) error {
+		return db.Raw(query, sql.Named("start", start), sql.Named("end", end)).Scan(&rows).Error
+	}); err != nil {
+		log.Errorf(databaseErrorFormat, hErrors.ErrDatabaseError.Message, err)
+		return hErrors.ErrDatabaseError
 	}
-	return operations
-}
 
-func categorizeHbarTransfers(hbarTransfers, nonFeeTransfers []hbarTransfer) (
-	feeHbarTransfers, adjustedNonFeeTransfers []hbarTransfer,
-) {
-	entityIds := make(map[int64]struct{})
-	for _, transfer := range hbarTransfers {
-		entityIds[transfer.AccountId.EncodedId] = struct{}{}
+	bytesMap := make(map[int64]transactionBytes, len(rows))
+	for _, row := range rows {
+		bytesMap[row.ConsensusTimestamp] = row
 	}
 
-	adjustedNonFeeTransfers = make([]hbarTransfer, 0, len(nonFeeTransfers))
-	for _, nonFeeTransfer := range nonFeeTransfers {
-		entityId := nonFeeTransfer.AccountId.EncodedId
-		// skip non fee transfer whose entity id is not in the transaction record's transfer list
-		if _, ok := entityIds[entityId]; ok {
-			adjustedNonFeeTransfers = append(adjustedNonFeeTransfers, nonFeeTransfer)
+	for _, txn := range transactions {
+		if row, ok := bytesMap[txn.ConsensusTimestamp]; ok {
+			txn.TransactionBytes = row.TransactionBytes
+			txn.TransactionRecordBytes = row.TransactionRecordBytes
 		}
 	}
 
-	nonFeeTransferMap := aggregateNonFeeTransfers(nonFeeTransfers)
-	return getFeeHbarTransfers(hbarTransfers, nonFeeTransferMap), adjustedNonFeeTransfers
+	return nil
 }
 
 func (tr *transactionRepository) processSuccessTokenDissociates(
@@ -560,15 +1246,14 @@ func (tr *transactionRepository) processSuccessTokenDissociates(
 		return nil
 	}
 
-	db, cancel := tr.dbClient.GetDbWithContext(ctx)
-	defer cancel()
-
 	tokenDissociateTransactions := make([]*transaction, 0)
-	if err := db.Raw(
-		selectDissociateTokenTransfersInTimestampRange,
-		sql.Named("start", start),
-		sql.Named("end", end),
-	).Scan(&tokenDissociateTransactions).Error; err != nil {
+	if err := tr.dbClient.Query(ctx, "selectDissociateTokenTransfersInTimestampRange", func(db *gorm.DB) error {
+		return db.Raw(
+			selectDissociateTokenTransfersInTimestampRange,
+			sql.Named("start", start),
+			sql.Named("end", end),
+		).Scan(&tokenDissociateTransactions).Error
+	}); err != nil {
 		log.Errorf(databaseErrorFormat, hErrors.ErrDatabaseError.Message, err)
 		return hErrors.ErrDatabaseError
 	}
@@ -580,6 +1265,9 @@ func (tr *transactionRepository) processSuccessTokenDissociates(
 		if transactions[t].ConsensusTimestamp == tokenDissociateTransactions[d].ConsensusTimestamp {
 			transactions[t].NftTransfers = tokenDissociateTransactions[d].NftTransfers
 			transactions[t].TokenTransfers = tokenDissociateTransactions[d].TokenTransfers
+			// the replaced transfers are json, so the typed arrays scanned by pgx, if any, must not be used
+			transactions[t].transferArrays.nft = nil
+			transactions[t].transferArrays.token = nil
 			d++
 		}
 	}
@@ -587,93 +1275,64 @@ func (tr *transactionRepository) processSuccessTokenDissociates(
 	return nil
 }
 
-func IsTransactionResultSuccessful(result int32) bool {
-	return result == transactionResultFeeScheduleFilePartUploaded ||
-		result == transactionResultSuccess ||
-		result == transactionResultSuccessButMissingExpectedOperation
-}
-
-func aggregateNonFeeTransfers(nonFeeTransfers []hbarTransfer) map[int64]int64 {
-	nonFeeTransferMap := make(map[int64]int64)
+// processPendingAirdrops sets the airdrop state changes made by the successful TokenAirdrop, TokenCancelAirdrop, and
+// TokenClaimAirdrop transactions. Nothing is changed if the token airdrop table doesn't exist
+func (tr *transactionRepository) processPendingAirdrops(
+	ctx context.Context,
+	transactions []*transaction,
+	start int64,
+	end int64,
+) *rTypes.Error {
+	hasSuccessAirdrop := false
+	for _, txn := range transactions {
+		if isAirdropTransactionType(txn.Type) && IsTransactionResultSuccessful(int32(txn.Result)) {
+			hasSuccessAirdrop = true
+			break
+		}
+	}
+	if !hasSuccessAirdrop {
+		return nil
+	}
 
-	// the original transfer list from the transaction body
-	for _, transfer := range nonFeeTransfers {
-		// the original transfer list may have multiple entries for one entity, so accumulate it
-		nonFeeTransferMap[transfer.AccountId.EncodedId] += transfer.Amount
+	present, rErr := tr.tokenAirdrop.isPresent(ctx, tr.dbClient)
+	if rErr != nil || !present {
+		return rErr
 	}
 
-	return nonFeeTransferMap
-}
+	airdrops := make([]pendingAirdrop, 0)
+	if err := tr.dbClient.Query(ctx, "selectPendingAirdropsInTimestampRange", func(db *gorm.DB) error {
+		return db.Raw(
+			selectPendingAirdropsInTimestampRange,
+			sql.Named("start", start),
+			sql.Named("end", end),
+		).Scan(&airdrops).Error
+	}); err != nil {
+		log.Errorf(databaseErrorFormat, hErrors.ErrDatabaseError.Message, err)
+		return hErrors.ErrDatabaseError
+	}
 
-func getFeeHbarTransfers(cryptoTransfers []hbarTransfer, nonFeeTransferMap map[int64]int64) []hbarTransfer {
-	cryptoTransferMap := make(map[int64]hbarTransfer)
-	accountIds := make([]int64, 0)
-	for _, transfer := range cryptoTransfers {
-		accountId := transfer.AccountId.EncodedId
-		if _, ok := cryptoTransferMap[accountId]; !ok {
-			accountIds = append(accountIds, accountId)
-		}
-		cryptoTransferMap[accountId] = hbarTransfer{
-			AccountId: transfer.AccountId,
-			Amount:    transfer.Amount + cryptoTransferMap[accountId].Amount,
-		}
+	airdropsMap := make(map[int64][]types.PendingAirdrop)
+	for _, airdrop := range airdrops {
+		airdropsMap[airdrop.Timestamp] = append(airdropsMap[airdrop.Timestamp], airdrop.toPendingAirdrop())
 	}
 
-	adjusted := make([]hbarTransfer, 0, len(cryptoTransfers))
-	for _, accountId := range accountIds {
-		aggregated := cryptoTransferMap[accountId]
-		amount := aggregated.Amount - nonFeeTransferMap[accountId]
-		if amount != 0 {
-			adjusted = append(adjusted, hbarTransfer{
-				AccountId: aggregated.AccountId,
-				Amount:    amount,
-			})
+	for _, txn := range transactions {
+		if isAirdropTransactionType(txn.Type) && IsTransactionResultSuccessful(int32(txn.Result)) {
+			txn.PendingAirdrops = airdropsMap[txn.ConsensusTimestamp]
 		}
 	}
 
-	return adjusted
+	return nil
 }
 
-func getSingleNftTransfers(nftTransfer domain.NftTransfer) []transfer {
-	transfers := make([]transfer, 0)
-	if nftTransfer.ReceiverAccountId != nil {
-		transfers = append(transfers, singleNftTransfer{
-			accountId:    *nftTransfer.ReceiverAccountId,
-			receiver:     true,
-			serialNumber: nftTransfer.SerialNumber,
-			tokenId:      nftTransfer.TokenId,
-		})
-	}
-
-	if nftTransfer.SenderAccountId != nil {
-		transfers = append(transfers, singleNftTransfer{
-			accountId:    *nftTransfer.SenderAccountId,
-			serialNumber: nftTransfer.SerialNumber,
-			tokenId:      nftTransfer.TokenId,
-		})
-	}
-	return transfers
+func isAirdropTransactionType(transactionType int16) bool {
+	return transactionType == domain.TransactionTypeTokenAirdrop ||
+		transactionType == domain.TransactionTypeTokenCancelAirdrop ||
+		transactionType == domain.TransactionTypeTokenClaimAirdrop
 }
 
-func getTokenOperation(
-	index int,
-	token domain.Token,
-	transaction *transaction,
-	transactionResult string,
-	operationType string,
-) types.Operation {
-	operation := types.Operation{
-		AccountId: types.NewAccountIdFromEntityId(transaction.PayerAccountId),
-		Index:     int64(index),
-		Status:    transactionResult,
-		Type:      operationType,
-	}
-
-	metadata := make(map[string]interface{})
-	metadata["currency"] = types.Token{Token: token}.ToRosettaCurrency()
-	metadata["freeze_default"] = token.FreezeDefault
-	metadata["initial_supply"] = token.InitialSupply
-	operation.Metadata = metadata
-
-	return operation
+func IsTransactionResultSuccessful(result int32) bool {
+	return result == transactionResultFeeScheduleFilePartUploaded ||
+		result == transactionResultSuccess ||
+		result == transactionResultSuccessButMissingExpectedOperation
 }
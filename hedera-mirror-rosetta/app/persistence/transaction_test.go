@@ -21,15 +21,26 @@
 package persistence
 
 import (
+	"bytes"
+	"database/sql"
 	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"strings"
 	"testing"
+	"testing/quick"
 
 	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/builder"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/tools"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/db"
 	tdomain "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/domain"
+	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"github.com/thanhpk/randstr"
@@ -38,6 +49,7 @@ import (
 const (
 	consensusStart int64 = 1000
 	consensusEnd   int64 = 1100
+	isApproval           = "is_approval"
 	resultSuccess        = "SUCCESS"
 )
 
@@ -57,149 +69,487 @@ var (
 	tokenId3                    = domain.MustDecodeEntityId(26750) // nft
 	tokenDecimals         int64 = 10
 	tokenInitialSupply    int64 = 50000
+	// system accounts are not configured in the integration tests so the fee operations have no metadata
+	systemAccounts = config.SystemAccounts{}
 )
 
-func TestCategorizeHbarTransfers(t *testing.T) {
+func TestConstructTransactionOperationOrderIsDeterministic(t *testing.T) {
+	repo := NewTransactionRepository(nil, systemAccounts, false, false, config.DbRangeSplit{}).(*transactionRepository)
+	property := func(seed int64) bool {
+		// given
+		random := rand.New(rand.NewSource(seed))
+		transactions := randomSameHashTransactions(random)
+		shuffled := make([]*transaction, 0, len(transactions))
+		for _, txn := range transactions {
+			copied := *txn
+			copied.CryptoTransfers = shuffleJsonArray(random, txn.CryptoTransfers)
+			copied.NonFeeTransfers = shuffleJsonArray(random, txn.NonFeeTransfers)
+			copied.TokenTransfers = shuffleJsonArray(random, txn.TokenTransfers)
+			copied.NftTransfers = shuffleJsonArray(random, txn.NftTransfers)
+			shuffled = append(shuffled, &copied)
+		}
+		random.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+		// when
+		expected, err1 := repo.constructTransaction(transactions)
+		actual, err2 := repo.constructTransaction(shuffled)
+
+		// then
+		if err1 != nil || err2 != nil || !assert.ObjectsAreEqual(expected, actual) {
+			return false
+		}
+
+		for i, operation := range actual.Operations {
+			if operation.Index != int64(i) {
+				return false
+			}
+		}
+		return true
+	}
+
+	assert.NoError(t, quick.Check(property, &quick.Config{MaxCount: 200}))
+}
+
+func TestConstructTransactionInvariantCheck(t *testing.T) {
 	tests := []struct {
-		name                     string
-		hbarTransfers            []hbarTransfer
-		nonFeeTransfers          []hbarTransfer
-		expectedFeeHbarTransfers []hbarTransfer
-		expectedNonFeeTransfers  []hbarTransfer
+		name           string
+		invariantCheck bool
 	}{
+		{name: "enabled", invariantCheck: true},
+		{name: "disabled", invariantCheck: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			buf := new(bytes.Buffer)
+			output := log.StandardLogger().Out
+			log.SetOutput(buf)
+			defer log.SetOutput(output)
+
+			repo := NewTransactionRepository(
+				nil,
+				systemAccounts,
+				false,
+				tt.invariantCheck,
+				config.DbRangeSplit{},
+			).(*transactionRepository)
+			txn := &transaction{
+				ConsensusTimestamp: consensusStart,
+				Hash:               randstr.Bytes(32),
+				PayerAccountId:     firstEntityId,
+				Result:             22,
+				Type:               14,
+				CryptoTransfers: fmt.Sprintf(`[{"account_id": %d, "amount": -100}, {"account_id": %d, "amount": 101}]`,
+					firstEntityId.EncodedId, secondEntityId.EncodedId),
+				NonFeeTransfers: "[]",
+				TokenTransfers:  "[]",
+				NftTransfers:    "[]",
+				Token:           "{}",
+				Schedule:        "{}",
+			}
+
+			// when
+			actual, err := repo.constructTransaction([]*transaction{txn})
+
+			// then
+			assert.Nil(t, err)
+			assert.Len(t, actual.Operations, 2)
+			message := fmt.Sprintf("Invariant violated by transaction %s: hbar transfers of CRYPTOTRANSFER "+
+				"transaction sum to 1 instead of 0", txn.getHashString())
+			assert.Equal(t, tt.invariantCheck, bytes.Contains(buf.Bytes(), []byte(message)))
+		})
+	}
+}
+
+func TestConstructTransactionApprovedTransfers(t *testing.T) {
+	// given
+	repo := NewTransactionRepository(nil, systemAccounts, false, false, config.DbRangeSplit{}).(*transactionRepository)
+	thirdAccountId := types.NewAccountIdFromEntityId(thirdEntityId)
+	txn := &transaction{
+		ConsensusTimestamp: consensusStart,
+		Hash:               randstr.Bytes(32),
+		PayerAccountId:     thirdEntityId,
+		Result:             22,
+		Type:               14,
+		CryptoTransfers: fmt.Sprintf(`[{"account_id": 3, "amount": 5}, {"account_id": 98, "amount": 10},
+			{"account_id": %[1]d, "amount": -100, "is_approval": true}, {"account_id": %[2]d, "amount": 100},
+			{"account_id": %[3]d, "amount": -15}]`, firstEntityId.EncodedId, secondEntityId.EncodedId,
+			thirdEntityId.EncodedId),
+		NonFeeTransfers: fmt.Sprintf(`[{"account_id": %d, "amount": -100}, {"account_id": %d, "amount": 100}]`,
+			firstEntityId.EncodedId, secondEntityId.EncodedId),
+		TokenTransfers: fmt.Sprintf(`[{"account_id": %[1]d, "amount": -10, "decimals": %[3]d, "is_approval": true,
+			"token_id": %[4]d, "type": "%[5]s"}, {"account_id": %[2]d, "amount": 10, "decimals": %[3]d,
+			"is_approval": false, "token_id": %[4]d, "type": "%[5]s"}]`, firstEntityId.EncodedId,
+			secondEntityId.EncodedId, tokenDecimals, tokenId1.EncodedId, domain.TokenTypeFungibleCommon),
+		NftTransfers: fmt.Sprintf(`[{"is_approval": true, "receiver_account_id": %d, "sender_account_id": %d,
+			"serial_number": 1, "token_id": %d}]`, secondEntityId.EncodedId, firstEntityId.EncodedId,
+			tokenId3.EncodedId),
+		Token:    "{}",
+		Schedule: "{}",
+	}
+	expected := types.OperationSlice{
 		{
-			name:                     "empty",
-			expectedFeeHbarTransfers: []hbarTransfer{},
-			expectedNonFeeTransfers:  []hbarTransfer{},
+			AccountId: firstAccountId,
+			Amount:    &types.HbarAmount{Value: -100},
+			Index:     0,
+			Metadata:  map[string]interface{}{isApproval: true},
+			Status:    resultSuccess,
+			Type:      types.OperationTypeApprovedTransfer,
 		},
 		{
-			name: "empty non fee transfers",
-			hbarTransfers: []hbarTransfer{
-				{firstEntityId, -65},
-				{nodeEntityId, 15},
-				{feeCollectorEntityId, 50},
-			},
-			expectedFeeHbarTransfers: []hbarTransfer{
-				{firstEntityId, -65},
-				{nodeEntityId, 15},
-				{feeCollectorEntityId, 50},
-			},
-			expectedNonFeeTransfers: []hbarTransfer{},
+			AccountId: secondAccountId,
+			Amount:    &types.HbarAmount{Value: 100},
+			Index:     1,
+			Status:    resultSuccess,
+			Type:      types.OperationTypeCryptoTransfer,
 		},
 		{
-			name: "simple transfer lists",
-			hbarTransfers: []hbarTransfer{
-				{firstEntityId, -165},
-				{secondEntityId, 100},
-				{nodeEntityId, 15},
-				{feeCollectorEntityId, 50},
-			},
-			nonFeeTransfers: []hbarTransfer{
-				{firstEntityId, -100},
-				{secondEntityId, 100},
-			},
-			expectedFeeHbarTransfers: []hbarTransfer{
-				{firstEntityId, -65},
-				{nodeEntityId, 15},
-				{feeCollectorEntityId, 50},
-			},
-			expectedNonFeeTransfers: []hbarTransfer{
-				{firstEntityId, -100},
-				{secondEntityId, 100},
-			},
+			AccountId: nodeAccountId,
+			Amount:    &types.HbarAmount{Value: 5},
+			Index:     2,
+			Status:    resultSuccess,
+			Type:      types.OperationTypeFee,
 		},
 		{
-			name: "non fee transfer not in transaction record",
-			hbarTransfers: []hbarTransfer{
-				{firstEntityId, -100499210447},
-				{secondEntityId, 99999999958},
-				{nodeEntityId, 2558345},
-				{feeCollectorEntityId, 496652144},
-			},
-			nonFeeTransfers: []hbarTransfer{
-				{firstEntityId, -100000000000},
-				{thirdEntityId, 100000000000},
-			},
-			expectedFeeHbarTransfers: []hbarTransfer{
-				{firstEntityId, -499210447},
-				{secondEntityId, 99999999958},
-				{nodeEntityId, 2558345},
-				{feeCollectorEntityId, 496652144},
-			},
-			expectedNonFeeTransfers: []hbarTransfer{
-				{firstEntityId, -100000000000},
-			},
+			AccountId: feeCollectorAccountId,
+			Amount:    &types.HbarAmount{Value: 10},
+			Index:     3,
+			Status:    resultSuccess,
+			Type:      types.OperationTypeFee,
+		},
+		{
+			AccountId: thirdAccountId,
+			Amount:    &types.HbarAmount{Value: -15},
+			Index:     4,
+			Status:    resultSuccess,
+			Type:      types.OperationTypeFee,
+		},
+		{
+			AccountId: firstAccountId,
+			Amount:    getFungibleTokenAmount(-10, tokenDecimals, tokenId1),
+			Index:     5,
+			Metadata:  map[string]interface{}{isApproval: true},
+			Status:    resultSuccess,
+			Type:      types.OperationTypeApprovedTransfer,
+		},
+		{
+			AccountId: secondAccountId,
+			Amount:    getFungibleTokenAmount(10, tokenDecimals, tokenId1),
+			Index:     6,
+			Status:    resultSuccess,
+			Type:      types.OperationTypeCryptoTransfer,
+		},
+		{
+			AccountId: secondAccountId,
+			Amount:    getNftTokenAmount(1, 1, tokenId3),
+			Index:     7,
+			Status:    resultSuccess,
+			Type:      types.OperationTypeCryptoTransfer,
+		},
+		{
+			AccountId: firstAccountId,
+			Amount:    getNftTokenAmount(-1, 1, tokenId3),
+			Index:     8,
+			Metadata:  map[string]interface{}{isApproval: true},
+			Status:    resultSuccess,
+			Type:      types.OperationTypeApprovedTransfer,
 		},
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			actualFeeHbarTransfers, actualAdjustedNonFeeTransfers := categorizeHbarTransfers(tt.hbarTransfers, tt.nonFeeTransfers)
-			assert.Equal(t, tt.expectedFeeHbarTransfers, actualFeeHbarTransfers)
-			assert.Equal(t, tt.expectedNonFeeTransfers, actualAdjustedNonFeeTransfers)
-		})
-	}
-}
+	// when
+	actual, err := repo.constructTransaction([]*transaction{txn})
 
-func TestTransactionGetHashString(t *testing.T) {
-	tx := transaction{Hash: []byte{1, 2, 3, 0xaa, 0xff}}
-	assert.Equal(t, "0x010203aaff", tx.getHashString())
+	// then
+	assert.Nil(t, err)
+	assert.Equal(t, expected, actual.Operations)
 }
 
-func TestHbarTransferGetAccount(t *testing.T) {
-	hbarTransfer := hbarTransfer{AccountId: firstEntityId}
-	assert.Equal(t, firstEntityId, hbarTransfer.getAccountId())
+func TestConstructTransactionDeletedEntity(t *testing.T) {
+	// given
+	repo := NewTransactionRepository(nil, systemAccounts, false, false, config.DbRangeSplit{}).(*transactionRepository)
+	txn := &transaction{
+		ConsensusTimestamp: consensusStart,
+		Hash:               randstr.Bytes(32),
+		PayerAccountId:     firstEntityId,
+		Result:             22,
+		Type:               12,
+		CryptoTransfers:    "[]",
+		NonFeeTransfers:    "[]",
+		TokenTransfers:     "[]",
+		NftTransfers:       "[]",
+		Token:              "{}",
+		Schedule:           "{}",
+		deletedEntities: deletedEntityArrays{
+			ids:        []int64{firstEntityId.EncodedId},
+			timestamps: []int64{consensusStart},
+		},
+		transferArrays: transferArrays{crypto: &hbarTransferArrays{
+			accountIds:  []int64{firstEntityId.EncodedId, secondEntityId.EncodedId},
+			amounts:     []int64{-100, 100},
+			isApprovals: []bool{false, false},
+		}},
+	}
+	expected := types.OperationSlice{
+		{
+			AccountId: firstAccountId,
+			Amount:    &types.HbarAmount{Value: -100},
+			Index:     0,
+			Metadata:  map[string]interface{}{"deleted": true, "deleted_timestamp": consensusStart},
+			Status:    resultSuccess,
+			Type:      types.OperationTypeFee,
+		},
+		{
+			AccountId: secondAccountId,
+			Amount:    &types.HbarAmount{Value: 100},
+			Index:     1,
+			Status:    resultSuccess,
+			Type:      types.OperationTypeFee,
+		},
+	}
+
+	// when
+	actual, err := repo.constructTransaction([]*transaction{txn})
+
+	// then
+	assert.Nil(t, err)
+	assert.Equal(t, expected, actual.Operations)
 }
 
-func TestHbarTransferGetAmount(t *testing.T) {
-	hbarTransfer := hbarTransfer{Amount: 10}
-	assert.Equal(t, &types.HbarAmount{Value: 10}, hbarTransfer.getAmount())
+func TestConstructTransactionSuppressEmptyOperations(t *testing.T) {
+	// given
+	repo := NewTransactionRepository(nil, systemAccounts, true, false, config.DbRangeSplit{}).(*transactionRepository)
+	txn := &transaction{
+		ConsensusTimestamp: consensusStart,
+		Hash:               randstr.Bytes(32),
+		PayerAccountId:     firstEntityId,
+		Result:             22,
+		Type:               29,
+		CryptoTransfers: fmt.Sprintf(`[{"account_id": 3, "amount": 5}, {"account_id": %d, "amount": -5}]`,
+			firstEntityId.EncodedId),
+		NonFeeTransfers: fmt.Sprintf(`[{"account_id": %d, "amount": 0}]`, firstEntityId.EncodedId),
+		TokenTransfers:  "[]",
+		NftTransfers:    "[]",
+		Token: fmt.Sprintf(`{"decimals": %d, "freeze_default": false, "initial_supply": 0, "token_id": %d,
+			"type": "%s"}`, tokenDecimals, tokenId1.EncodedId, domain.TokenTypeFungibleCommon),
+		Schedule: "{}",
+	}
+	expected := types.OperationSlice{
+		{
+			AccountId: nodeAccountId,
+			Amount:    &types.HbarAmount{Value: 5},
+			Index:     0,
+			Status:    resultSuccess,
+			Type:      types.OperationTypeFee,
+		},
+		{
+			AccountId: firstAccountId,
+			Amount:    &types.HbarAmount{Value: -5},
+			Index:     1,
+			Status:    resultSuccess,
+			Type:      types.OperationTypeFee,
+		},
+	}
+
+	// when
+	actual, err := repo.constructTransaction([]*transaction{txn})
+
+	// then
+	assert.Nil(t, err)
+	assert.Equal(t, expected, actual.Operations)
 }
 
-func TestSingleNftTransferGetAccount(t *testing.T) {
-	singleNftTransfer := singleNftTransfer{accountId: firstEntityId}
-	assert.Equal(t, firstEntityId, singleNftTransfer.getAccountId())
+func TestConstructTransactionFeeBreakdown(t *testing.T) {
+	// given
+	repo := NewTransactionRepository(nil, systemAccounts, false, false, config.DbRangeSplit{}).(*transactionRepository)
+	txn := &transaction{
+		ChargedTxFee:       15,
+		ConsensusTimestamp: consensusStart,
+		Hash:               randstr.Bytes(32),
+		NodeAccountId:      &nodeEntityId,
+		PayerAccountId:     firstEntityId,
+		Result:             22,
+		Type:               14,
+		CryptoTransfers: fmt.Sprintf(`[{"account_id": %d, "amount": 5}, {"account_id": %d, "amount": 10},
+			{"account_id": %d, "amount": -15}]`, nodeEntityId.EncodedId, feeCollectorEntityId.EncodedId,
+			firstEntityId.EncodedId),
+		NonFeeTransfers: "[]",
+		TokenTransfers:  "[]",
+		NftTransfers:    "[]",
+		Token:           "{}",
+		Schedule:        "{}",
+	}
+	expected := &types.FeeBreakdown{
+		NetworkFee: 10,
+		NodeFee:    5,
+		Source:     types.FeeBreakdownSourceTransfers,
+		TotalFee:   15,
+	}
+
+	// when
+	actual, err := repo.constructTransaction([]*transaction{txn})
+
+	// then
+	assert.Nil(t, err)
+	assert.Equal(t, expected, actual.FeeBreakdown)
 }
 
-func TestSingleNftTransferGetAmount(t *testing.T) {
-	tests := []struct {
-		name     string
-		receiver bool
-		amount   int64
-	}{
-		{"receiver", true, 1},
-		{"sender", false, -1},
+// randomSameHashTransactions returns the transactions sharing a hash, e.g., a parent and its child records, with
+// random transfers drawn from a small pool of accounts and amounts so there are ties on the account
+func randomSameHashTransactions(random *rand.Rand) []*transaction {
+	accounts := []int64{3, 98, 800, firstEntityId.EncodedId, secondEntityId.EncodedId, thirdEntityId.EncodedId}
+	tokens := []int64{tokenId1.EncodedId, tokenId2.EncodedId}
+	randomAccount := func() int64 { return accounts[random.Intn(len(accounts))] }
+	randomAmount := func() int64 { return int64(random.Intn(11) - 5) }
+	marshal := func(list []map[string]interface{}) string {
+		data, _ := json.Marshal(list)
+		return string(data)
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			singleNftTransfer := singleNftTransfer{
-				accountId:    firstEntityId,
-				receiver:     tt.receiver,
-				serialNumber: 1,
-				tokenId:      tokenId1,
+	hash := randstr.Bytes(32)
+	count := 1 + random.Intn(3)
+	transactions := make([]*transaction, 0, count)
+	for i := 0; i < count; i++ {
+		cryptoTransfers := make([]map[string]interface{}, 0)
+		nonFeeTransfers := make([]map[string]interface{}, 0)
+		for j := random.Intn(8); j > 0; j-- {
+			account, amount := randomAccount(), randomAmount()
+			cryptoTransfers = append(cryptoTransfers, map[string]interface{}{"account_id": account, "amount": amount})
+			if random.Intn(2) == 0 {
+				nonFeeTransfers = append(nonFeeTransfers, map[string]interface{}{
+					"account_id": account,
+					"amount":     amount,
+				})
 			}
+		}
 
-			expected := &types.TokenAmount{
-				SerialNumbers: []int64{1},
-				TokenId:       tokenId1,
-				Type:          domain.TokenTypeNonFungibleUnique,
-				Value:         tt.amount,
-			}
+		tokenTransfers := make([]map[string]interface{}, 0)
+		for j := random.Intn(5); j > 0; j-- {
+			tokenTransfers = append(tokenTransfers, map[string]interface{}{
+				"account_id": randomAccount(),
+				"amount":     randomAmount(),
+				"decimals":   tokenDecimals,
+				"token_id":   tokens[random.Intn(len(tokens))],
+				"type":       domain.TokenTypeFungibleCommon,
+			})
+		}
+
+		nftTransfers := make([]map[string]interface{}, 0)
+		for j := random.Intn(4); j > 0; j-- {
+			nftTransfers = append(nftTransfers, map[string]interface{}{
+				"receiver_account_id": randomAccount(),
+				"sender_account_id":   randomAccount(),
+				"serial_number":       1 + random.Intn(3),
+				"token_id":            tokenId3.EncodedId,
+			})
+		}
 
-			assert.Equal(t, expected, singleNftTransfer.getAmount())
+		transactions = append(transactions, &transaction{
+			ConsensusTimestamp: consensusStart + int64(i),
+			Hash:               hash,
+			PayerAccountId:     firstEntityId,
+			Result:             22,
+			Type:               14,
+			CryptoTransfers:    marshal(cryptoTransfers),
+			NftTransfers:       marshal(nftTransfers),
+			NonFeeTransfers:    marshal(nonFeeTransfers),
+			TokenTransfers:     marshal(tokenTransfers),
+			Token:              "{}",
+			Schedule:           "{}",
 		})
 	}
+
+	return transactions
+}
+
+func shuffleJsonArray(random *rand.Rand, data string) string {
+	list := make([]json.RawMessage, 0)
+	_ = json.Unmarshal([]byte(data), &list)
+	random.Shuffle(len(list), func(i, j int) { list[i], list[j] = list[j], list[i] })
+	shuffled, _ := json.Marshal(list)
+	return string(shuffled)
+}
+
+func TestConstructTransactionInvalidJson(t *testing.T) {
+	// given
+	repo := NewTransactionRepository(nil, systemAccounts, false, false, config.DbRangeSplit{}).(*transactionRepository)
+	txn := &transaction{
+		ConsensusTimestamp: consensusStart,
+		Hash:               randstr.Bytes(32),
+		PayerAccountId:     firstEntityId,
+		Result:             22,
+		Type:               14,
+		CryptoTransfers:    "[]",
+		NonFeeTransfers:    "[]",
+		TokenTransfers:     "{",
+		NftTransfers:       "[]",
+		Token:              "{}",
+		Schedule:           "{}",
+	}
+
+	// when
+	actual, err := repo.constructTransaction([]*transaction{txn})
+
+	// then
+	assert.Equal(t, errors.ErrInternalServerError, err)
+	assert.Nil(t, actual)
 }
 
-func TestTokenTransferGetAccount(t *testing.T) {
-	tokenTransfer := tokenTransfer{AccountId: firstEntityId}
-	assert.Equal(t, firstEntityId, tokenTransfer.getAccountId())
+func TestTransactionDecode(t *testing.T) {
+	// given
+	txn := transaction{
+		ChargedTxFee:           5,
+		NodeAccountId:          &nodeEntityId,
+		PayerAccountId:         firstEntityId,
+		Result:                 22,
+		Scheduled:              true,
+		TransactionRecordBytes: []byte{0x1},
+		Type:                   14,
+		CryptoTransfers: fmt.Sprintf(`[{"account_id": %d, "amount": -5, "is_approval": true}]`,
+			firstEntityId.EncodedId),
+		NonFeeTransfers: "[]",
+		TokenTransfers: fmt.Sprintf(`[{"account_id": %d, "amount": 10, "decimals": %d, "token_id": %d, "type": "%s"}]`,
+			secondEntityId.EncodedId, tokenDecimals, tokenId1.EncodedId, domain.TokenTypeFungibleCommon),
+		NftTransfers: fmt.Sprintf(`[{"receiver_account_id": %d, "serial_number": 1, "token_id": %d}]`,
+			secondEntityId.EncodedId, tokenId3.EncodedId),
+		Token:    "{}",
+		Schedule: "{}",
+	}
+	expected := builder.Transaction{
+		ChargedTxFee:    5,
+		CryptoTransfers: []builder.HbarTransfer{{AccountId: firstEntityId, Amount: -5, IsApproval: true}},
+		NftTransfers: []domain.NftTransfer{
+			{ReceiverAccountId: &secondEntityId, SerialNumber: 1, TokenId: tokenId3},
+		},
+		NodeAccountId:   &nodeEntityId,
+		NonFeeTransfers: []builder.HbarTransfer{},
+		PayerAccountId:  firstEntityId,
+		RecordBytes:     []byte{0x1},
+		Result:          22,
+		Scheduled:       true,
+		TokenTransfers: []builder.TokenTransfer{
+			{
+				AccountId: secondEntityId,
+				Amount:    10,
+				Decimals:  tokenDecimals,
+				TokenId:   tokenId1,
+				Type:      domain.TokenTypeFungibleCommon,
+			},
+		},
+		Type: 14,
+	}
+
+	// when
+	actual, err := txn.decode()
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
 }
 
-func TestTokenTransferGetAmount(t *testing.T) {
-	tokenTransfer := tokenTransfer{Amount: 10, Decimals: 3, TokenId: tokenId1, Type: domain.TokenTypeFungibleCommon}
-	assert.Equal(t, getFungibleTokenAmount(10, 3, tokenId1), tokenTransfer.getAmount())
+func TestTransactionGetHashString(t *testing.T) {
+	tx := transaction{Hash: []byte{1, 2, 3, 0xaa, 0xff}}
+	assert.Equal(t, "0x010203aaff", tx.getHashString())
 }
 
 func assertOperationIndexes(t *testing.T, operations types.OperationSlice) {
@@ -251,6 +601,46 @@ func assertTransactions(t *testing.T, expected, actual []*types.Transaction) {
 	}
 }
 
+func TestDecodeNullableEntityId(t *testing.T) {
+	negative := int64(-1)
+	zero := int64(0)
+	var tests = []struct {
+		name        string
+		encodedId   *int64
+		expected    *domain.EntityId
+		expectError bool
+	}{
+		{name: "null"},
+		{name: "zero", encodedId: &zero, expected: &domain.EntityId{}},
+		{name: "entity id", encodedId: &firstEntityId.EncodedId, expected: &firstEntityId},
+		{name: "negative", encodedId: &negative, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := decodeNullableEntityId(tt.encodedId)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestPgxTransactionQueries(t *testing.T) {
+	for _, query := range []string{
+		pgxSelectTransactionsByHashInTimestampRange,
+		pgxSelectTransactionsInTimestampRangeOrderedLimit,
+	} {
+		assert.NotContains(t, query, "@")
+	}
+
+	assert.Contains(t, pgxSelectTransactionsByHashInTimestampRange, "transaction_hash = $3")
+	assert.True(t, strings.HasSuffix(pgxSelectTransactionsInTimestampRangeOrderedLimit, "limit $3"))
+}
+
 func TestTransactionRepositorySuite(t *testing.T) {
 	suite.Run(t, new(transactionRepositorySuite))
 }
@@ -261,14 +651,180 @@ type transactionRepositorySuite struct {
 }
 
 func (suite *transactionRepositorySuite) TestNewTransactionRepository() {
-	t := NewTransactionRepository(dbClient)
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
 	assert.NotNil(suite.T(), t)
 }
 
+func (suite *transactionRepositorySuite) TestCountBetween() {
+	// given
+	expected := suite.setupDb(true)
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	transactionCount, operationCount, err := t.CountBetween(defaultContext, consensusStart, consensusEnd)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), int64(len(expected)), transactionCount)
+	assert.Equal(suite.T(), int64(39), operationCount)
+}
+
+func (suite *transactionRepositorySuite) TestCountBetweenNoTokenEntity() {
+	// given
+	expected := suite.setupDb(false)
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	transactionCount, operationCount, err := t.CountBetween(defaultContext, consensusStart, consensusEnd)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), int64(len(expected)), transactionCount)
+	assert.Equal(suite.T(), int64(37), operationCount)
+}
+
+func (suite *transactionRepositorySuite) TestCountBetweenThrowsWhenStartAfterEnd() {
+	// given
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	transactionCount, operationCount, err := t.CountBetween(defaultContext, consensusStart, consensusStart-1)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrStartMustNotBeAfterEnd, err)
+	assert.Zero(suite.T(), transactionCount)
+	assert.Zero(suite.T(), operationCount)
+}
+
+func (suite *transactionRepositorySuite) TestCountBetweenDbConnectionError() {
+	// given
+	t := NewTransactionRepository(invalidDbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	transactionCount, operationCount, err := t.CountBetween(defaultContext, consensusStart, consensusEnd)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Zero(suite.T(), transactionCount)
+	assert.Zero(suite.T(), operationCount)
+}
+
+func (suite *transactionRepositorySuite) TestCountOperationsBetween() {
+	for _, createTokenEntity := range []bool{true, false} {
+		suite.Run(fmt.Sprintf("createTokenEntity=%t", createTokenEntity), func() {
+			// given
+			suite.setupDb(createTokenEntity)
+			t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+			// when
+			operationCount, err := t.CountOperationsBetween(defaultContext, consensusStart, consensusEnd)
+
+			// then
+			assert.Nil(suite.T(), err)
+			assert.Equal(suite.T(), int64(39), operationCount)
+		})
+	}
+}
+
+func (suite *transactionRepositorySuite) TestCountOperationsBetweenThrowsWhenStartAfterEnd() {
+	// given
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	operationCount, err := t.CountOperationsBetween(defaultContext, consensusStart, consensusStart-1)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrStartMustNotBeAfterEnd, err)
+	assert.Zero(suite.T(), operationCount)
+}
+
+func (suite *transactionRepositorySuite) TestCountOperationsBetweenDbConnectionError() {
+	// given
+	t := NewTransactionRepository(invalidDbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	operationCount, err := t.CountOperationsBetween(defaultContext, consensusStart, consensusEnd)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Zero(suite.T(), operationCount)
+}
+
+func (suite *transactionRepositorySuite) TestCountByTypeAndResult() {
+	// given
+	for i, tx := range []struct {
+		result int16
+		txType int16
+	}{{22, 14}, {22, 14}, {10, 14}, {22, 11}} {
+		timestamp := consensusStart + int64(i)
+		tdomain.NewTransactionBuilder(dbClient, firstEntityId.EncodedId, timestamp-10).
+			ConsensusTimestamp(timestamp).
+			Result(tx.result).
+			Type(tx.txType).
+			Persist()
+	}
+	// outside the range
+	tdomain.NewTransactionBuilder(dbClient, firstEntityId.EncodedId, consensusEnd).
+		ConsensusTimestamp(consensusEnd + 1).
+		Persist()
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	actual, err := t.CountByTypeAndResult(defaultContext, consensusStart, consensusEnd)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), consensusStart, actual.Start)
+	assert.Equal(suite.T(), consensusEnd, actual.End)
+	assert.ElementsMatch(suite.T(), []types.TransactionTypeCount{
+		{Count: 2, Result: 22, Type: 14},
+		{Count: 1, Result: 10, Type: 14},
+		{Count: 1, Result: 22, Type: 11},
+	}, actual.Counts)
+}
+
+func (suite *transactionRepositorySuite) TestCountByTypeAndResultThrowsWhenStartAfterEnd() {
+	// given
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	actual, err := t.CountByTypeAndResult(defaultContext, consensusStart, consensusStart-1)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrStartMustNotBeAfterEnd, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *transactionRepositorySuite) TestCountByTypeAndResultDbConnectionError() {
+	// given
+	t := NewTransactionRepository(invalidDbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	actual, err := t.CountByTypeAndResult(defaultContext, consensusStart, consensusEnd)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
 func (suite *transactionRepositorySuite) TestFindBetween() {
 	// given
 	expected := suite.setupDb(true)
-	t := NewTransactionRepository(dbClient)
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	actual, err := t.FindBetween(defaultContext, consensusStart, consensusEnd)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assertTransactions(suite.T(), expected, actual)
+}
+
+func (suite *transactionRepositorySuite) TestFindBetweenWithRangeSplit() {
+	// given
+	expected := suite.setupDb(true)
+	rangeSplit := config.DbRangeSplit{MaxConcurrency: 2, PartitionInterval: 7}
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, rangeSplit)
 
 	// when
 	actual, err := t.FindBetween(defaultContext, consensusStart, consensusEnd)
@@ -337,128 +893,716 @@ func (suite *transactionRepositorySuite) TestFindBetweenTokenCreatedAtOrBeforeGe
 			},
 		},
 	}
-	t := NewTransactionRepository(dbClient)
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	actual, err := t.FindBetween(defaultContext, transaction.ConsensusTimestamp, transaction.ConsensusTimestamp)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.ElementsMatch(suite.T(), expected, actual)
+}
+
+func (suite *transactionRepositorySuite) TestFindBetweenScheduleTransactions() {
+	// given
+	scheduleId := int64(5001)
+	expirationTime := int64(500)
+	scheduleCreate := tdomain.NewTransactionBuilder(dbClient, account1, 100).
+		EntityId(scheduleId).
+		Type(domain.TransactionTypeScheduleCreate).
+		Persist()
+	scheduled := tdomain.NewTransactionBuilder(dbClient, account1, 200).Scheduled(true).Persist()
+	db.CreateDbRecords(
+		dbClient,
+		&domain.Schedule{
+			ConsensusTimestamp: scheduleCreate.ConsensusTimestamp,
+			CreatorAccountId:   domain.MustDecodeEntityId(account1),
+			ExecutedTimestamp:  &scheduled.ConsensusTimestamp,
+			ExpirationTime:     &expirationTime,
+			PayerAccountId:     domain.MustDecodeEntityId(account1),
+			ScheduleId:         domain.MustDecodeEntityId(scheduleId),
+			TransactionBody:    []byte{0x1},
+			WaitForExpiry:      true,
+		},
+		&domain.NonFeeTransfer{
+			Amount:             -10,
+			ConsensusTimestamp: scheduled.ConsensusTimestamp,
+			EntityId:           domain.MustDecodeEntityId(account1),
+			PayerAccountId:     domain.MustDecodeEntityId(account1),
+		},
+		&domain.NonFeeTransfer{
+			Amount:             10,
+			ConsensusTimestamp: scheduled.ConsensusTimestamp,
+			EntityId:           domain.MustDecodeEntityId(account2),
+			PayerAccountId:     domain.MustDecodeEntityId(account1),
+		},
+	)
+	transferTimestamp := scheduled.ConsensusTimestamp
+	tdomain.NewCryptoTransferBuilder(dbClient).Amount(-15).EntityId(account1).Timestamp(transferTimestamp).Persist()
+	tdomain.NewCryptoTransferBuilder(dbClient).Amount(10).EntityId(account2).Timestamp(transferTimestamp).Persist()
+	tdomain.NewCryptoTransferBuilder(dbClient).Amount(5).EntityId(3).Timestamp(transferTimestamp).Persist()
+
+	scheduleEntityId := domain.MustDecodeEntityId(scheduleId)
+	scheduleMetadata := map[string]interface{}{
+		"expiration_time": expirationTime,
+		"schedule_id":     scheduleEntityId.String(),
+		"wait_for_expiry": true,
+	}
+	expected := []*types.Transaction{
+		{
+			EntityId: &scheduleEntityId,
+			Hash:     tools.SafeAddHexPrefix(hex.EncodeToString(scheduleCreate.TransactionHash)),
+			Operations: types.OperationSlice{
+				{
+					AccountId: types.NewAccountIdFromEntityId(domain.MustDecodeEntityId(account1)),
+					Metadata:  scheduleMetadata,
+					Status:    resultSuccess,
+					Type:      "SCHEDULECREATE",
+				},
+			},
+		},
+		{
+			Hash: tools.SafeAddHexPrefix(hex.EncodeToString(scheduled.TransactionHash)),
+			Operations: types.OperationSlice{
+				{
+					AccountId: types.NewAccountIdFromEntityId(domain.MustDecodeEntityId(account1)),
+					Amount:    &types.HbarAmount{Value: -10},
+					Metadata:  map[string]interface{}{"schedule": scheduleMetadata},
+					Status:    resultSuccess,
+					Type:      types.OperationTypeCryptoTransfer,
+				},
+				{
+					AccountId: types.NewAccountIdFromEntityId(domain.MustDecodeEntityId(account2)),
+					Amount:    &types.HbarAmount{Value: 10},
+					Metadata:  map[string]interface{}{"schedule": scheduleMetadata},
+					Status:    resultSuccess,
+					Type:      types.OperationTypeCryptoTransfer,
+				},
+				{
+					AccountId: types.NewAccountIdFromEntityId(domain.MustDecodeEntityId(account1)),
+					Amount:    &types.HbarAmount{Value: -5},
+					Status:    resultSuccess,
+					Type:      types.OperationTypeFee,
+				},
+				{
+					AccountId: types.NewAccountIdFromEntityId(domain.MustDecodeEntityId(3)),
+					Amount:    &types.HbarAmount{Value: 5},
+					Status:    resultSuccess,
+					Type:      types.OperationTypeFee,
+				},
+			},
+		},
+	}
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	actual, err := t.FindBetween(defaultContext, scheduleCreate.ConsensusTimestamp, scheduled.ConsensusTimestamp)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assertTransactions(suite.T(), expected, actual)
+}
+
+func (suite *transactionRepositorySuite) TestFindBetweenHavingDisappearingTokenTransfer() {
+	// given
+	// the disappearing token/nft transfers are in the corresponding db table
+	genesisTimestamp := int64(100)
+	tdomain.NewAccountBalanceFileBuilder(dbClient, genesisTimestamp).Persist()
+
+	token1 := tdomain.NewTokenBuilder(dbClient, encodedTokenId1, genesisTimestamp+1, treasury).Persist()
+	tdomain.NewEntityBuilderFromToken(dbClient, token1).Deleted(true).ModifiedAfter(100).Persist()
+
+	token2 := tdomain.NewTokenBuilder(dbClient, encodedTokenId2, genesisTimestamp+2, treasury).
+		Type(domain.TokenTypeNonFungibleUnique).
+		Persist()
+	entity2 := tdomain.NewEntityBuilderFromToken(dbClient, token2).Deleted(true).ModifiedAfter(100).Persist()
+
+	// token accounts
+	dissociateTimestamp := entity2.GetModifiedTimestamp() + 100
+	tdomain.NewTokenAccountBuilder(dbClient, account1, encodedTokenId1, token1.CreatedTimestamp+1).
+		Associated(false, dissociateTimestamp).
+		Persist()
+	tdomain.NewTokenAccountBuilder(dbClient, account1, encodedTokenId2, token2.CreatedTimestamp+1).
+		Associated(false, dissociateTimestamp).
+		Persist()
+
+	// token1 received
+	tdomain.NewTokenTransferBuilder(dbClient).
+		AccountId(account1).
+		Amount(10).
+		TokenId(encodedTokenId1).
+		Timestamp(token1.CreatedTimestamp + 10).
+		Persist()
+	// token1 disappearing transfer at dissociation
+	tdomain.NewTokenTransferBuilder(dbClient).
+		AccountId(account1).
+		Amount(-10).
+		TokenId(encodedTokenId1).
+		Timestamp(dissociateTimestamp).
+		Persist()
+	// token2 disappearing transfers at dissociation
+	tdomain.NewNftTransferBuilder(dbClient).
+		SenderAccountId(account1).
+		TokenId(encodedTokenId2).
+		SerialNumber(1).
+		Timestamp(dissociateTimestamp).
+		Persist()
+	tdomain.NewNftTransferBuilder(dbClient).
+		SenderAccountId(account1).
+		TokenId(encodedTokenId2).
+		SerialNumber(2).
+		Timestamp(dissociateTimestamp).
+		Persist()
+
+	// nft marked as deleted at dissociate timestamp
+	tdomain.NewNftBuilder(dbClient, encodedTokenId2, 1, token2.CreatedTimestamp+10).
+		AccountId(account1).
+		Deleted(true).
+		ModifiedTimestamp(dissociateTimestamp).
+		Persist()
+	tdomain.NewNftBuilder(dbClient, encodedTokenId2, 2, token2.CreatedTimestamp+10).
+		AccountId(account1).
+		Deleted(true).
+		ModifiedTimestamp(dissociateTimestamp).
+		Persist()
+
+	// the dissociate transaction
+	transaction := tdomain.NewTransactionBuilder(dbClient, account1, dissociateTimestamp-10).
+		ConsensusTimestamp(dissociateTimestamp).
+		EntityId(account1).
+		Type(domain.TransactionTypeTokenDissociate).
+		Persist()
+
+	account1EntityId := domain.MustDecodeEntityId(account1)
+	account1Id := types.NewAccountIdFromEntityId(account1EntityId)
+	expected := []*types.Transaction{
+		{
+			EntityId: &account1EntityId,
+			Hash:     tools.SafeAddHexPrefix(hex.EncodeToString(transaction.TransactionHash)),
+			Operations: types.OperationSlice{
+				{
+					AccountId: account1Id,
+					Amount:    types.NewTokenAmount(token1, -10),
+					Type:      types.OperationTypeTokenDissociate,
+					Status:    resultSuccess,
+				},
+				{
+					AccountId: account1Id,
+					Amount:    types.NewTokenAmount(token2, -1).SetSerialNumbers([]int64{1}),
+					Index:     1,
+					Type:      types.OperationTypeTokenDissociate,
+					Status:    resultSuccess,
+				},
+				{
+					AccountId: account1Id,
+					Amount:    types.NewTokenAmount(token2, -1).SetSerialNumbers([]int64{2}),
+					Index:     2,
+					Type:      types.OperationTypeTokenDissociate,
+					Status:    resultSuccess,
+				},
+			},
+		},
+	}
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	actual, err := t.FindBetween(defaultContext, dissociateTimestamp, dissociateTimestamp)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.ElementsMatch(suite.T(), expected, actual)
+}
+
+func (suite *transactionRepositorySuite) TestFindBetweenMissingDisappearingTokenTransfer() {
+	// given
+	dissociateTimestamp, expected := suite.setupMissingDisappearingTokenTransfer()
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	actual, err := t.FindBetween(defaultContext, dissociateTimestamp, dissociateTimestamp)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.ElementsMatch(suite.T(), expected, actual)
+}
+
+func (suite *transactionRepositorySuite) TestFindByHashInBlockMissingDisappearingTokenTransfer() {
+	// given
+	dissociateTimestamp, expected := suite.setupMissingDisappearingTokenTransfer()
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	actual, err := t.FindByHashInBlock(defaultContext, expected[0].Hash, dissociateTimestamp-1, dissociateTimestamp+1)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected[0], actual)
+}
+
+func (suite *transactionRepositorySuite) TestProcessPendingAirdrops() {
+	// given
+	db.ExecSql(dbClient, createTokenAirdropTablesSql)
+	defer db.ExecSql(dbClient, dropTokenAirdropTablesSql)
+	token := tdomain.NewTokenBuilder(dbClient, encodedTokenId1, 1, treasury).Decimals(2).Persist()
+	tokenId := token.TokenId.EncodedId
+	// the airdrop at 100 is claimed at 200, and a new airdrop is cancelled at 300
+	insertTokenAirdrop(treasury, account1, tokenId, 0, 50, types.AirdropStatePending, 100, 200)
+	insertTokenAirdrop(treasury, account1, tokenId, 0, 50, types.AirdropStateClaimed, 200, 0)
+	insertTokenAirdrop(treasury, account2, tokenId, 0, 20, types.AirdropStatePending, 250, 300)
+	insertTokenAirdrop(treasury, account2, tokenId, 0, 20, types.AirdropStateCancelled, 300, 0)
+	transactions := []*transaction{
+		{ConsensusTimestamp: 100, Result: 22, Type: domain.TransactionTypeTokenAirdrop},
+		{ConsensusTimestamp: 200, Result: 22, Type: domain.TransactionTypeTokenClaimAirdrop},
+		{ConsensusTimestamp: 250, Result: 28, Type: domain.TransactionTypeTokenAirdrop},
+		{ConsensusTimestamp: 300, Result: 22, Type: domain.TransactionTypeTokenCancelAirdrop},
+	}
+	airdrop := func(receiver int64, amount int64, state string, timestamp int64) []types.PendingAirdrop {
+		return []types.PendingAirdrop{{
+			Amount:            amount,
+			ReceiverAccountId: domain.MustDecodeEntityId(receiver),
+			SenderAccountId:   domain.MustDecodeEntityId(treasury),
+			State:             state,
+			Timestamp:         timestamp,
+			Token:             domain.Token{Decimals: 2, TokenId: token.TokenId, Type: domain.TokenTypeFungibleCommon},
+		}}
+	}
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	err := t.(*transactionRepository).processPendingAirdrops(defaultContext, transactions, 100, 300)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), airdrop(account1, 50, types.AirdropStatePending, 100), transactions[0].PendingAirdrops)
+	assert.Equal(suite.T(), airdrop(account1, 50, types.AirdropStateClaimed, 200), transactions[1].PendingAirdrops)
+	assert.Nil(suite.T(), transactions[2].PendingAirdrops)
+	assert.Equal(suite.T(), airdrop(account2, 20, types.AirdropStateCancelled, 300), transactions[3].PendingAirdrops)
+}
+
+func (suite *transactionRepositorySuite) TestProcessPendingAirdropsNoTable() {
+	// given
+	transactions := []*transaction{{ConsensusTimestamp: 100, Result: 22, Type: domain.TransactionTypeTokenAirdrop}}
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	err := t.(*transactionRepository).processPendingAirdrops(defaultContext, transactions, 100, 100)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Nil(suite.T(), transactions[0].PendingAirdrops)
+}
+
+func (suite *transactionRepositorySuite) TestFindBetweenNoTokenEntity() {
+	// given
+	expected := suite.setupDb(false)
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	actual, err := t.FindBetween(defaultContext, consensusStart, consensusEnd)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assertTransactions(suite.T(), expected, actual)
+}
+
+func (suite *transactionRepositorySuite) TestFindBetweenThrowsWhenStartAfterEnd() {
+	// given
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	actual, err := t.FindBetween(defaultContext, consensusStart, consensusStart-1)
+
+	// then
+	assert.NotNil(suite.T(), err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *transactionRepositorySuite) TestFindBetweenDbConnectionError() {
+	// given
+	t := NewTransactionRepository(invalidDbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	actual, err := t.FindBetween(defaultContext, consensusStart, consensusEnd)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *transactionRepositorySuite) TestFindBetweenWithRangeSplitDbConnectionError() {
+	// given
+	rangeSplit := config.DbRangeSplit{MaxConcurrency: 2, PartitionInterval: 7}
+	t := NewTransactionRepository(invalidDbClient, systemAccounts, false, false, rangeSplit)
+
+	// when
+	actual, err := t.FindBetween(defaultContext, consensusStart, consensusEnd)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *transactionRepositorySuite) TestFindHashesBetween() {
+	// given
+	transactions := suite.setupDb(true)
+	expected := make([]string, 0, len(transactions))
+	for _, transaction := range transactions {
+		expected = append(expected, transaction.Hash)
+	}
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	actual, err := t.FindHashesBetween(defaultContext, consensusStart, consensusEnd)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.ElementsMatch(suite.T(), expected, actual)
+}
+
+func (suite *transactionRepositorySuite) TestFindHashesBetweenThrowsWhenStartAfterEnd() {
+	// given
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	actual, err := t.FindHashesBetween(defaultContext, consensusStart, consensusStart-1)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrStartMustNotBeAfterEnd, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *transactionRepositorySuite) TestFindHashesBetweenDbConnectionError() {
+	// given
+	t := NewTransactionRepository(invalidDbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	actual, err := t.FindHashesBetween(defaultContext, consensusStart, consensusEnd)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *transactionRepositorySuite) TestFindKeysBySearchByHash() {
+	// given
+	transactions := suite.setupDb(true)
+	hash, _ := hex.DecodeString(tools.SafeRemoveHexPrefix(transactions[0].Hash))
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	actual, count, err := t.FindKeysBySearch(
+		defaultContext,
+		types.TransactionSearch{End: consensusEnd, Hash: hash, Limit: 10},
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.NotEmpty(suite.T(), actual)
+	assert.Equal(suite.T(), int64(len(actual)), count)
+	for _, key := range actual {
+		assert.Equal(suite.T(), transactions[0].Hash, key.Hash)
+	}
+}
+
+func (suite *transactionRepositorySuite) TestFindKeysBySearchByAccountPaged() {
+	// given
+	suite.setupDb(true)
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
+	search := types.TransactionSearch{AccountId: firstEntityId.EncodedId, End: consensusEnd, Limit: 1}
+
+	// when
+	firstPage, firstCount, firstErr := t.FindKeysBySearch(defaultContext, search)
+	search.After = firstPage[0].ConsensusTimestamp
+	secondPage, secondCount, secondErr := t.FindKeysBySearch(defaultContext, search)
+
+	// then
+	assert.Nil(suite.T(), firstErr)
+	assert.Nil(suite.T(), secondErr)
+	assert.Len(suite.T(), firstPage, 1)
+	assert.Len(suite.T(), secondPage, 1)
+	assert.Greater(suite.T(), secondPage[0].ConsensusTimestamp, firstPage[0].ConsensusTimestamp)
+	assert.Greater(suite.T(), firstCount, int64(1))
+	assert.Equal(suite.T(), firstCount, secondCount)
+}
+
+func (suite *transactionRepositorySuite) TestFindKeysBySearchNoMatch() {
+	// given
+	suite.setupDb(true)
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	actual, count, err := t.FindKeysBySearch(
+		defaultContext,
+		types.TransactionSearch{AccountId: 1, End: consensusEnd, Limit: 10},
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Empty(suite.T(), actual)
+	assert.Zero(suite.T(), count)
+}
+
+func (suite *transactionRepositorySuite) TestFindKeysBySearchDbConnectionError() {
+	// given
+	t := NewTransactionRepository(invalidDbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	actual, count, err := t.FindKeysBySearch(defaultContext, types.TransactionSearch{End: consensusEnd, Limit: 10})
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+	assert.Zero(suite.T(), count)
+}
+
+func (suite *transactionRepositorySuite) TestFindByHashInBlock() {
+	// given
+	expected := suite.setupDb(true)
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	actual, err := t.FindByHashInBlock(defaultContext, expected[0].Hash, consensusStart, consensusEnd)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assertTransactions(suite.T(), []*types.Transaction{expected[0]}, []*types.Transaction{actual})
+}
+
+func (suite *transactionRepositorySuite) TestFindByHashInBlockNoTokenEntity() {
+	// given
+	expected := suite.setupDb(false)
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	actual, err := t.FindByHashInBlock(defaultContext, expected[1].Hash, consensusStart, consensusEnd)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assertTransactions(suite.T(), []*types.Transaction{expected[1]}, []*types.Transaction{actual})
+}
+
+func (suite *transactionRepositorySuite) TestFindByHashInBlockThrowsInvalidHash() {
+	// given
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	actual, err := t.FindByHashInBlock(defaultContext, "invalid hash", consensusStart, consensusEnd)
+
+	// then
+	assert.NotNil(suite.T(), err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *transactionRepositorySuite) TestFindByHashInBlockThrowsNotFound() {
+	// given
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	actual, err := t.FindByHashInBlock(defaultContext, "0x123456", consensusStart, consensusEnd)
+
+	// then
+	assert.NotNil(suite.T(), err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *transactionRepositorySuite) TestFindByHashInBlockDbConnectionError() {
+	// given
+	t := NewTransactionRepository(invalidDbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	actual, err := t.FindByHashInBlock(defaultContext, "0x123456", consensusStart, consensusEnd)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *transactionRepositorySuite) TestFindByHashInBlockWithTransactionBytes() {
+	// given
+	hash := randstr.Bytes(32)
+	transactionBytes := randstr.Bytes(64)
+	transaction := tdomain.NewTransactionBuilder(dbClient, firstEntityId.EncodedId, consensusStart).
+		TransactionBytes(transactionBytes).
+		TransactionHash(hash).
+		Persist()
+	tdomain.NewCryptoTransferBuilder(dbClient).
+		Amount(-10).
+		EntityId(firstEntityId.EncodedId).
+		Timestamp(transaction.ConsensusTimestamp).
+		Persist()
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
 
 	// when
-	actual, err := t.FindBetween(defaultContext, transaction.ConsensusTimestamp, transaction.ConsensusTimestamp)
+	actual, err := t.FindByHashInBlock(
+		defaultContext,
+		tools.SafeAddHexPrefix(hex.EncodeToString(hash)),
+		consensusStart,
+		consensusEnd,
+	)
 
 	// then
 	assert.Nil(suite.T(), err)
-	assert.ElementsMatch(suite.T(), expected, actual)
+	assert.Equal(suite.T(), transactionBytes, actual.TransactionBytes)
+	assert.Nil(suite.T(), actual.TransactionRecordBytes)
 }
 
-func (suite *transactionRepositorySuite) TestFindBetweenHavingDisappearingTokenTransfer() {
+func (suite *transactionRepositorySuite) TestFindByHashInBlockDeletedEntity() {
 	// given
-	// the disappearing token/nft transfers are in the corresponding db table
-	genesisTimestamp := int64(100)
-	tdomain.NewAccountBalanceFileBuilder(dbClient, genesisTimestamp).Persist()
-
-	token1 := tdomain.NewTokenBuilder(dbClient, encodedTokenId1, genesisTimestamp+1, treasury).Persist()
-	tdomain.NewEntityBuilderFromToken(dbClient, token1).Deleted(true).ModifiedAfter(100).Persist()
-
-	token2 := tdomain.NewTokenBuilder(dbClient, encodedTokenId2, genesisTimestamp+2, treasury).
-		Type(domain.TokenTypeNonFungibleUnique).
+	hash := randstr.Bytes(32)
+	transaction := tdomain.NewTransactionBuilder(dbClient, firstEntityId.EncodedId, consensusStart).
+		EntityId(firstEntityId.EncodedId).
+		TransactionHash(hash).
+		Type(12).
 		Persist()
-	entity2 := tdomain.NewEntityBuilderFromToken(dbClient, token2).Deleted(true).ModifiedAfter(100).Persist()
-
-	// token accounts
-	dissociateTimestamp := entity2.GetModifiedTimestamp() + 100
-	tdomain.NewTokenAccountBuilder(dbClient, account1, encodedTokenId1, token1.CreatedTimestamp+1).
-		Associated(false, dissociateTimestamp).
-		Persist()
-	tdomain.NewTokenAccountBuilder(dbClient, account1, encodedTokenId2, token2.CreatedTimestamp+1).
-		Associated(false, dissociateTimestamp).
+	deletedTimestamp := transaction.ConsensusTimestamp
+	tdomain.NewEntityBuilder(dbClient, firstEntityId.EncodedId, consensusStart-10, domain.EntityTypeAccount).
+		Deleted(true).
+		ModifiedTimestamp(deletedTimestamp).
 		Persist()
-
-	// token1 received
-	tdomain.NewTokenTransferBuilder(dbClient).
-		AccountId(account1).
-		Amount(10).
-		TokenId(encodedTokenId1).
-		Timestamp(token1.CreatedTimestamp + 10).
+	// deleted after the transaction
+	tdomain.NewEntityBuilder(dbClient, secondEntityId.EncodedId, consensusStart-10, domain.EntityTypeAccount).
+		Deleted(true).
+		ModifiedTimestamp(deletedTimestamp + 1).
 		Persist()
-	// token1 disappearing transfer at dissociation
-	tdomain.NewTokenTransferBuilder(dbClient).
-		AccountId(account1).
+	tdomain.NewCryptoTransferBuilder(dbClient).
 		Amount(-10).
-		TokenId(encodedTokenId1).
-		Timestamp(dissociateTimestamp).
-		Persist()
-	// token2 disappearing transfers at dissociation
-	tdomain.NewNftTransferBuilder(dbClient).
-		SenderAccountId(account1).
-		TokenId(encodedTokenId2).
-		SerialNumber(1).
-		Timestamp(dissociateTimestamp).
+		EntityId(firstEntityId.EncodedId).
+		Timestamp(deletedTimestamp).
 		Persist()
-	tdomain.NewNftTransferBuilder(dbClient).
-		SenderAccountId(account1).
-		TokenId(encodedTokenId2).
-		SerialNumber(2).
-		Timestamp(dissociateTimestamp).
+	tdomain.NewCryptoTransferBuilder(dbClient).
+		Amount(10).
+		EntityId(secondEntityId.EncodedId).
+		Timestamp(deletedTimestamp).
 		Persist()
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
+	expected := map[int64]map[string]interface{}{
+		firstEntityId.EncodedId:  {"deleted": true, "deleted_timestamp": deletedTimestamp},
+		secondEntityId.EncodedId: nil,
+	}
 
-	// nft marked as deleted at dissociate timestamp
-	tdomain.NewNftBuilder(dbClient, encodedTokenId2, 1, token2.CreatedTimestamp+10).
-		AccountId(account1).
-		Deleted(true).
-		ModifiedTimestamp(dissociateTimestamp).
+	// when
+	actual, err := t.FindByHashInBlock(
+		defaultContext,
+		tools.SafeAddHexPrefix(hex.EncodeToString(hash)),
+		consensusStart,
+		consensusEnd,
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Len(suite.T(), actual.Operations, 2)
+	for _, operation := range actual.Operations {
+		assert.Equal(suite.T(), expected[operation.AccountId.GetId()], operation.Metadata)
+	}
+}
+
+func (suite *transactionRepositorySuite) TestGetOptionalColumns() {
+	// given
+	t := NewTransactionRepository(
+		dbClient,
+		systemAccounts,
+		false,
+		false,
+		config.DbRangeSplit{},
+	).(*transactionRepository)
+
+	// when
+	actual, err := t.getOptionalColumns(defaultContext)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), []string{columnTransactionBytes}, actual)
+}
+
+func (suite *transactionRepositorySuite) TestGetOptionalColumnsDbConnectionError() {
+	// given
+	t := NewTransactionRepository(
+		invalidDbClient,
+		systemAccounts,
+		false,
+		false,
+		config.DbRangeSplit{},
+	).(*transactionRepository)
+
+	// when
+	actual, err := t.getOptionalColumns(defaultContext)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+	assert.Nil(suite.T(), t.optionalColumns)
+}
+
+func (suite *transactionRepositorySuite) TestFindRawByHashInBlock() {
+	// given
+	hash := randstr.Bytes(32)
+	transactionBytes := randstr.Bytes(64)
+	tdomain.NewTransactionBuilder(dbClient, firstEntityId.EncodedId, consensusStart).
+		TransactionBytes(transactionBytes).
+		TransactionHash(hash).
 		Persist()
-	tdomain.NewNftBuilder(dbClient, encodedTokenId2, 2, token2.CreatedTimestamp+10).
-		AccountId(account1).
-		Deleted(true).
-		ModifiedTimestamp(dissociateTimestamp).
+	// a failed duplicate transaction with the same hash
+	tdomain.NewTransactionBuilder(dbClient, firstEntityId.EncodedId, consensusStart).
+		ConsensusTimestamp(consensusStart + 10).
+		Result(11).
+		TransactionHash(hash).
 		Persist()
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
 
-	// the dissociate transaction
-	transaction := tdomain.NewTransactionBuilder(dbClient, account1, dissociateTimestamp-10).
-		ConsensusTimestamp(dissociateTimestamp).
-		EntityId(account1).
-		Type(domain.TransactionTypeTokenDissociate).
-		Persist()
+	// when
+	actual, err := t.FindRawByHashInBlock(
+		defaultContext,
+		tools.SafeAddHexPrefix(hex.EncodeToString(hash)),
+		consensusStart,
+		consensusEnd,
+	)
 
-	account1EntityId := domain.MustDecodeEntityId(account1)
-	account1Id := types.NewAccountIdFromEntityId(account1EntityId)
-	expected := []*types.Transaction{
-		{
-			EntityId: &account1EntityId,
-			Hash:     tools.SafeAddHexPrefix(hex.EncodeToString(transaction.TransactionHash)),
-			Operations: types.OperationSlice{
-				{
-					AccountId: account1Id,
-					Amount:    types.NewTokenAmount(token1, -10),
-					Type:      types.OperationTypeTokenDissociate,
-					Status:    resultSuccess,
-				},
-				{
-					AccountId: account1Id,
-					Amount:    types.NewTokenAmount(token2, -1).SetSerialNumbers([]int64{1}),
-					Index:     1,
-					Type:      types.OperationTypeTokenDissociate,
-					Status:    resultSuccess,
-				},
-				{
-					AccountId: account1Id,
-					Amount:    types.NewTokenAmount(token2, -1).SetSerialNumbers([]int64{2}),
-					Index:     2,
-					Type:      types.OperationTypeTokenDissociate,
-					Status:    resultSuccess,
-				},
-			},
-		},
-	}
-	t := NewTransactionRepository(dbClient)
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), consensusStart+1, actual.ConsensusTimestamp)
+	assert.Equal(suite.T(), firstEntityId, actual.PayerAccountId)
+	assert.Equal(suite.T(), int16(22), actual.Result)
+	assert.Equal(suite.T(), transactionBytes, actual.TransactionBytes)
+	assert.Equal(suite.T(), hash, actual.TransactionHash)
+}
+
+func (suite *transactionRepositorySuite) TestFindRawByHashInBlockThrowsInvalidHash() {
+	// given
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
 
 	// when
-	actual, err := t.FindBetween(defaultContext, dissociateTimestamp, dissociateTimestamp)
+	actual, err := t.FindRawByHashInBlock(defaultContext, "invalid hash", consensusStart, consensusEnd)
 
 	// then
-	assert.Nil(suite.T(), err)
-	assert.ElementsMatch(suite.T(), expected, actual)
+	assert.Equal(suite.T(), errors.ErrInvalidTransactionIdentifier, err)
+	assert.Nil(suite.T(), actual)
 }
 
-func (suite *transactionRepositorySuite) TestFindBetweenMissingDisappearingTokenTransfer() {
+func (suite *transactionRepositorySuite) TestFindRawByHashInBlockThrowsNotFound() {
+	// given
+	t := NewTransactionRepository(dbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	actual, err := t.FindRawByHashInBlock(defaultContext, "0x123456", consensusStart, consensusEnd)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrTransactionNotFound, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *transactionRepositorySuite) TestFindRawByHashInBlockDbConnectionError() {
 	// given
+	t := NewTransactionRepository(invalidDbClient, systemAccounts, false, false, config.DbRangeSplit{})
+
+	// when
+	actual, err := t.FindRawByHashInBlock(defaultContext, "0x123456", consensusStart, consensusEnd)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *transactionRepositorySuite) setupMissingDisappearingTokenTransfer() (int64, []*types.Transaction) {
 	// the disappearing token/nft transfers are missing
 	genesisTimestamp := int64(100)
 	tdomain.NewAccountBalanceFileBuilder(dbClient, genesisTimestamp).Persist()
@@ -534,113 +1678,8 @@ func (suite *transactionRepositorySuite) TestFindBetweenMissingDisappearingToken
 			},
 		},
 	}
-	t := NewTransactionRepository(dbClient)
-
-	// when
-	actual, err := t.FindBetween(defaultContext, dissociateTimestamp, dissociateTimestamp)
-
-	// then
-	assert.Nil(suite.T(), err)
-	assert.ElementsMatch(suite.T(), expected, actual)
-}
-
-func (suite *transactionRepositorySuite) TestFindBetweenNoTokenEntity() {
-	// given
-	expected := suite.setupDb(false)
-	t := NewTransactionRepository(dbClient)
-
-	// when
-	actual, err := t.FindBetween(defaultContext, consensusStart, consensusEnd)
-
-	// then
-	assert.Nil(suite.T(), err)
-	assertTransactions(suite.T(), expected, actual)
-}
-
-func (suite *transactionRepositorySuite) TestFindBetweenThrowsWhenStartAfterEnd() {
-	// given
-	t := NewTransactionRepository(dbClient)
-
-	// when
-	actual, err := t.FindBetween(defaultContext, consensusStart, consensusStart-1)
-
-	// then
-	assert.NotNil(suite.T(), err)
-	assert.Nil(suite.T(), actual)
-}
-
-func (suite *transactionRepositorySuite) TestFindBetweenDbConnectionError() {
-	// given
-	t := NewTransactionRepository(invalidDbClient)
-
-	// when
-	actual, err := t.FindBetween(defaultContext, consensusStart, consensusEnd)
-
-	// then
-	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
-	assert.Nil(suite.T(), actual)
-}
 
-func (suite *transactionRepositorySuite) TestFindByHashInBlock() {
-	// given
-	expected := suite.setupDb(true)
-	t := NewTransactionRepository(dbClient)
-
-	// when
-	actual, err := t.FindByHashInBlock(defaultContext, expected[0].Hash, consensusStart, consensusEnd)
-
-	// then
-	assert.Nil(suite.T(), err)
-	assertTransactions(suite.T(), []*types.Transaction{expected[0]}, []*types.Transaction{actual})
-}
-
-func (suite *transactionRepositorySuite) TestFindByHashInBlockNoTokenEntity() {
-	// given
-	expected := suite.setupDb(false)
-	t := NewTransactionRepository(dbClient)
-
-	// when
-	actual, err := t.FindByHashInBlock(defaultContext, expected[1].Hash, consensusStart, consensusEnd)
-
-	// then
-	assert.Nil(suite.T(), err)
-	assertTransactions(suite.T(), []*types.Transaction{expected[1]}, []*types.Transaction{actual})
-}
-
-func (suite *transactionRepositorySuite) TestFindByHashInBlockThrowsInvalidHash() {
-	// given
-	t := NewTransactionRepository(dbClient)
-
-	// when
-	actual, err := t.FindByHashInBlock(defaultContext, "invalid hash", consensusStart, consensusEnd)
-
-	// then
-	assert.NotNil(suite.T(), err)
-	assert.Nil(suite.T(), actual)
-}
-
-func (suite *transactionRepositorySuite) TestFindByHashInBlockThrowsNotFound() {
-	// given
-	t := NewTransactionRepository(dbClient)
-
-	// when
-	actual, err := t.FindByHashInBlock(defaultContext, "0x123456", consensusStart, consensusEnd)
-
-	// then
-	assert.NotNil(suite.T(), err)
-	assert.Nil(suite.T(), actual)
-}
-
-func (suite *transactionRepositorySuite) TestFindByHashInBlockDbConnectionError() {
-	// given
-	t := NewTransactionRepository(invalidDbClient)
-
-	// when
-	actual, err := t.FindByHashInBlock(defaultContext, "0x123456", consensusStart, consensusEnd)
-
-	// then
-	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
-	assert.Nil(suite.T(), actual)
+	return dissociateTimestamp, expected
 }
 
 func (suite *transactionRepositorySuite) setupDb(createTokenEntity bool) []*types.Transaction {
@@ -873,10 +1912,10 @@ func (suite *transactionRepositorySuite) setupDb(createTokenEntity bool) []*type
 			PayerAccountId: firstEntityId},
 	}
 	nftTransfers := []domain.NftTransfer{
-		{consensusTimestamp, firstEntityId, &firstEntityId, nil, 1, tokenId3},
-		{consensusTimestamp, firstEntityId, &firstEntityId, nil, 2, tokenId3},
-		{consensusTimestamp, firstEntityId, &firstEntityId, nil, 3, tokenId3},
-		{consensusTimestamp, firstEntityId, &firstEntityId, nil, 4, tokenId3},
+		{consensusTimestamp, false, firstEntityId, &firstEntityId, nil, 1, tokenId3},
+		{consensusTimestamp, false, firstEntityId, &firstEntityId, nil, 2, tokenId3},
+		{consensusTimestamp, false, firstEntityId, &firstEntityId, nil, 3, tokenId3},
+		{consensusTimestamp, false, firstEntityId, &firstEntityId, nil, 4, tokenId3},
 	}
 	addTransaction(dbClient, consensusTimestamp, &tokenId3, &nodeEntityId, firstEntityId, 22,
 		[]byte{0xaa, 0x11, 0x33}, domain.TransactionTypeTokenMint, validStartNs, cryptoTransfers, nil, nil,
@@ -914,7 +1953,7 @@ func (suite *transactionRepositorySuite) setupDb(createTokenEntity bool) []*type
 			PayerAccountId: firstEntityId},
 	}
 	nftTransfers = []domain.NftTransfer{
-		{consensusTimestamp, firstEntityId, &secondEntityId, &firstEntityId, 1, tokenId3},
+		{consensusTimestamp, false, firstEntityId, &secondEntityId, &firstEntityId, 1, tokenId3},
 	}
 	addTransaction(dbClient, consensusTimestamp, nil, &nodeEntityId, firstEntityId,
 		22, []byte{0xaa, 0x11, 0x66}, domain.TransactionTypeCryptoTransfer, validStartNs, cryptoTransfers, nil,
@@ -1024,3 +2063,56 @@ func getNftTokenAmount(amount, serialNumber int64, tokenId domain.EntityId) *typ
 		Value:         amount,
 	}
 }
+
+// BenchmarkFindTransactionsInRange compares the allocations and the latency of scanning a batch of transactions with
+// gorm and with pgx, run with "go test -run ^$ -bench FindTransactionsInRange ./app/persistence/"
+func BenchmarkFindTransactionsInRange(b *testing.B) {
+	db.CleanupDb(dbResource.GetDb())
+	tdomain.NewAccountBalanceFileBuilder(dbClient, 1).Persist()
+	start := int64(100)
+	for i := 0; i < batchSize; i++ {
+		txn := tdomain.NewTransactionBuilder(dbClient, firstEntityId.EncodedId, start+int64(i)*2).Persist()
+		tdomain.NewCryptoTransferBuilder(dbClient).
+			Amount(-10).
+			EntityId(firstEntityId.EncodedId).
+			Timestamp(txn.ConsensusTimestamp).
+			Persist()
+		tdomain.NewCryptoTransferBuilder(dbClient).
+			Amount(10).
+			EntityId(secondEntityId.EncodedId).
+			Timestamp(txn.ConsensusTimestamp).
+			Persist()
+	}
+	end := start + int64(batchSize)*2
+	repo := NewTransactionRepository(
+		dbClient,
+		systemAccounts,
+		false,
+		false,
+		config.DbRangeSplit{},
+	).(*transactionRepository)
+
+	b.Run("gorm", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			transactions := make([]*transaction, 0)
+			if err := dbClient.GetDb().
+				Raw(selectTransactionsInTimestampRangeOrdered, sql.Named("start", start), sql.Named("end", end)).
+				Limit(batchSize).
+				Find(&transactions).
+				Error; err != nil || len(transactions) != batchSize {
+				b.Fatalf("Failed to find %d transactions: %v", batchSize, err)
+			}
+		}
+	})
+
+	b.Run("pgx", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if transactions, err := repo.findTransactionsInRange(defaultContext, start, end); err != nil ||
+				len(transactions) != batchSize {
+				b.Fatalf("Failed to find %d transactions: %v", batchSize, err)
+			}
+		}
+	})
+}
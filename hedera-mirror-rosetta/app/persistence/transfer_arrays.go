@@ -0,0 +1,210 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package persistence
+
+import (
+	"errors"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/builder"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+)
+
+var (
+	errMismatchedDeletedEntityArrays = errors.New("deleted entity arrays have mismatched lengths")
+	errMismatchedTransferArrays      = errors.New("transfer arrays have mismatched lengths")
+)
+
+// transferArrays holds the transfers of a transaction scanned as typed arrays. A nil kind means the transfers of that
+// kind are in the transaction's json column instead
+type transferArrays struct {
+	crypto *hbarTransferArrays
+	nft    *nftTransferArrays
+	nonFee *hbarTransferArrays
+	token  *tokenTransferArrays
+}
+
+// decodeInto appends the transfers of every kind present to the target's transfer slices
+func (a transferArrays) decodeInto(decoded *builder.Transaction) (err error) {
+	if a.crypto != nil {
+		if decoded.CryptoTransfers, err = a.crypto.appendTo(decoded.CryptoTransfers); err != nil {
+			return err
+		}
+	}
+
+	if a.nonFee != nil {
+		if decoded.NonFeeTransfers, err = a.nonFee.appendTo(decoded.NonFeeTransfers); err != nil {
+			return err
+		}
+	}
+
+	if a.token != nil {
+		if decoded.TokenTransfers, err = a.token.appendTo(decoded.TokenTransfers); err != nil {
+			return err
+		}
+	}
+
+	if a.nft != nil {
+		if decoded.NftTransfers, err = a.nft.appendTo(decoded.NftTransfers); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hbarTransferArrays are the crypto transfers or the non-fee transfers of a transaction, one array per field
+type hbarTransferArrays struct {
+	accountIds  []int64
+	amounts     []int64
+	isApprovals []bool
+}
+
+func (a *hbarTransferArrays) appendTo(transfers []builder.HbarTransfer) ([]builder.HbarTransfer, error) {
+	count := len(a.accountIds)
+	if len(a.amounts) != count || len(a.isApprovals) != count {
+		return nil, errMismatchedTransferArrays
+	}
+
+	for i := 0; i < count; i++ {
+		accountId, err := domain.DecodeEntityIdCached(a.accountIds[i])
+		if err != nil {
+			return nil, err
+		}
+
+		transfers = append(transfers, builder.HbarTransfer{
+			AccountId:  accountId,
+			Amount:     a.amounts[i],
+			IsApproval: a.isApprovals[i],
+		})
+	}
+
+	return transfers, nil
+}
+
+// tokenTransferArrays are the token transfers of a transaction, one array per field
+type tokenTransferArrays struct {
+	accountIds  []int64
+	amounts     []int64
+	decimals    []int64
+	isApprovals []bool
+	tokenIds    []int64
+	types       []string
+}
+
+func (a *tokenTransferArrays) appendTo(transfers []builder.TokenTransfer) ([]builder.TokenTransfer, error) {
+	count := len(a.accountIds)
+	if len(a.amounts) != count || len(a.decimals) != count || len(a.isApprovals) != count ||
+		len(a.tokenIds) != count || len(a.types) != count {
+		return nil, errMismatchedTransferArrays
+	}
+
+	for i := 0; i < count; i++ {
+		accountId, err := domain.DecodeEntityIdCached(a.accountIds[i])
+		if err != nil {
+			return nil, err
+		}
+
+		tokenId, err := domain.DecodeEntityIdCached(a.tokenIds[i])
+		if err != nil {
+			return nil, err
+		}
+
+		transfers = append(transfers, builder.TokenTransfer{
+			AccountId:  accountId,
+			Amount:     a.amounts[i],
+			Decimals:   a.decimals[i],
+			IsApproval: a.isApprovals[i],
+			TokenId:    tokenId,
+			Type:       a.types[i],
+		})
+	}
+
+	return transfers, nil
+}
+
+// nftTransferArrays are the nft transfers of a transaction, one array per field. The receiver and the sender are
+// null for a burn and a mint respectively
+type nftTransferArrays struct {
+	isApprovals        []bool
+	receiverAccountIds []*int64
+	senderAccountIds   []*int64
+	serialNumbers      []int64
+	tokenIds           []int64
+}
+
+func (a *nftTransferArrays) appendTo(transfers []domain.NftTransfer) ([]domain.NftTransfer, error) {
+	count := len(a.tokenIds)
+	if len(a.isApprovals) != count || len(a.receiverAccountIds) != count || len(a.senderAccountIds) != count ||
+		len(a.serialNumbers) != count {
+		return nil, errMismatchedTransferArrays
+	}
+
+	for i := 0; i < count; i++ {
+		receiverAccountId, err := decodeNullableEntityId(a.receiverAccountIds[i])
+		if err != nil {
+			return nil, err
+		}
+
+		senderAccountId, err := decodeNullableEntityId(a.senderAccountIds[i])
+		if err != nil {
+			return nil, err
+		}
+
+		tokenId, err := domain.DecodeEntityIdCached(a.tokenIds[i])
+		if err != nil {
+			return nil, err
+		}
+
+		transfers = append(transfers, domain.NftTransfer{
+			IsApproval:        a.isApprovals[i],
+			ReceiverAccountId: receiverAccountId,
+			SenderAccountId:   senderAccountId,
+			SerialNumber:      a.serialNumbers[i],
+			TokenId:           tokenId,
+		})
+	}
+
+	return transfers, nil
+}
+
+// deletedEntityArrays are the ids and the deleted timestamps of the deleted entities involved in the transfers of a
+// transaction
+type deletedEntityArrays struct {
+	ids        []int64
+	timestamps []int64
+}
+
+// toMap returns the deleted timestamps by the encoded entity ids, or nil if there is no deleted entity
+func (a deletedEntityArrays) toMap() (map[int64]int64, error) {
+	if len(a.ids) != len(a.timestamps) {
+		return nil, errMismatchedDeletedEntityArrays
+	}
+
+	if len(a.ids) == 0 {
+		return nil, nil
+	}
+
+	deletedEntities := make(map[int64]int64, len(a.ids))
+	for i, id := range a.ids {
+		deletedEntities[id] = a.timestamps[i]
+	}
+
+	return deletedEntities, nil
+}
@@ -0,0 +1,286 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package persistence
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/builder"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionDecodeTransferArrays(t *testing.T) {
+	// given
+	txn := transaction{
+		PayerAccountId: firstEntityId,
+		Result:         22,
+		Type:           14,
+		Token:          "{}",
+		Schedule:       "{}",
+		transferArrays: transferArrays{
+			crypto: &hbarTransferArrays{
+				accountIds:  []int64{firstEntityId.EncodedId, secondEntityId.EncodedId},
+				amounts:     []int64{-5, 5},
+				isApprovals: []bool{true, false},
+			},
+			nft: &nftTransferArrays{
+				isApprovals:        []bool{false},
+				receiverAccountIds: []*int64{&secondEntityId.EncodedId},
+				senderAccountIds:   []*int64{nil},
+				serialNumbers:      []int64{1},
+				tokenIds:           []int64{tokenId3.EncodedId},
+			},
+			nonFee: &hbarTransferArrays{},
+			token: &tokenTransferArrays{
+				accountIds:  []int64{secondEntityId.EncodedId},
+				amounts:     []int64{10},
+				decimals:    []int64{tokenDecimals},
+				isApprovals: []bool{false},
+				tokenIds:    []int64{tokenId1.EncodedId},
+				types:       []string{domain.TokenTypeFungibleCommon},
+			},
+		},
+	}
+	expected := builder.Transaction{
+		CryptoTransfers: []builder.HbarTransfer{
+			{AccountId: firstEntityId, Amount: -5, IsApproval: true},
+			{AccountId: secondEntityId, Amount: 5},
+		},
+		NftTransfers:    []domain.NftTransfer{{ReceiverAccountId: &secondEntityId, SerialNumber: 1, TokenId: tokenId3}},
+		NonFeeTransfers: []builder.HbarTransfer{},
+		PayerAccountId:  firstEntityId,
+		Result:          22,
+		TokenTransfers: []builder.TokenTransfer{
+			{
+				AccountId: secondEntityId,
+				Amount:    10,
+				Decimals:  tokenDecimals,
+				TokenId:   tokenId1,
+				Type:      domain.TokenTypeFungibleCommon,
+			},
+		},
+		Type: 14,
+	}
+
+	// when
+	actual, err := txn.decode()
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestTransactionDecodeTransferArraysWithJsonFallback(t *testing.T) {
+	// given the token transfers and nft transfers replaced by a token dissociate, which are json
+	txn := transaction{
+		NftTransfers: fmt.Sprintf(`[{"sender_account_id": %d, "serial_number": 2, "token_id": %d}]`,
+			firstEntityId.EncodedId, tokenId3.EncodedId),
+		TokenTransfers: "[]",
+		Token:          "{}",
+		Schedule:       "{}",
+		transferArrays: transferArrays{
+			crypto: &hbarTransferArrays{
+				accountIds:  []int64{firstEntityId.EncodedId},
+				amounts:     []int64{-5},
+				isApprovals: []bool{false},
+			},
+			nonFee: &hbarTransferArrays{},
+		},
+	}
+	expected := builder.Transaction{
+		CryptoTransfers: []builder.HbarTransfer{{AccountId: firstEntityId, Amount: -5}},
+		NftTransfers:    []domain.NftTransfer{{SenderAccountId: &firstEntityId, SerialNumber: 2, TokenId: tokenId3}},
+		NonFeeTransfers: []builder.HbarTransfer{},
+		TokenTransfers:  []builder.TokenTransfer{},
+	}
+
+	// when
+	actual, err := txn.decode()
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestTransferArraysDecodeIntoThrows(t *testing.T) {
+	var tests = []struct {
+		name   string
+		arrays transferArrays
+	}{
+		{"hbarMismatchedLengths", transferArrays{crypto: &hbarTransferArrays{accountIds: []int64{1}}}},
+		{"hbarInvalidAccountId", transferArrays{nonFee: &hbarTransferArrays{
+			accountIds:  []int64{-1},
+			amounts:     []int64{1},
+			isApprovals: []bool{false},
+		}}},
+		{"tokenMismatchedLengths", transferArrays{token: &tokenTransferArrays{
+			accountIds: []int64{1},
+			amounts:    []int64{1},
+		}}},
+		{"tokenInvalidTokenId", transferArrays{token: &tokenTransferArrays{
+			accountIds:  []int64{1},
+			amounts:     []int64{1},
+			decimals:    []int64{0},
+			isApprovals: []bool{false},
+			tokenIds:    []int64{-1},
+			types:       []string{domain.TokenTypeFungibleCommon},
+		}}},
+		{"nftMismatchedLengths", transferArrays{nft: &nftTransferArrays{tokenIds: []int64{1}}}},
+		{"nftInvalidReceiver", transferArrays{nft: &nftTransferArrays{
+			isApprovals:        []bool{false},
+			receiverAccountIds: []*int64{new(int64)},
+			senderAccountIds:   []*int64{nil},
+			serialNumbers:      []int64{1},
+			tokenIds:           []int64{-1},
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, tt.arrays.decodeInto(&builder.Transaction{}))
+		})
+	}
+}
+
+func TestDeletedEntityArraysToMap(t *testing.T) {
+	var tests = []struct {
+		name     string
+		arrays   deletedEntityArrays
+		expected map[int64]int64
+	}{
+		{name: "empty"},
+		{
+			name:     "deleted",
+			arrays:   deletedEntityArrays{ids: []int64{1, 2}, timestamps: []int64{10, 20}},
+			expected: map[int64]int64{1: 10, 2: 20},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.arrays.toMap()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestDeletedEntityArraysToMapThrows(t *testing.T) {
+	actual, err := deletedEntityArrays{ids: []int64{1}}.toMap()
+	assert.ErrorIs(t, err, errMismatchedDeletedEntityArrays)
+	assert.Nil(t, actual)
+}
+
+func TestTransferArraysQuery(t *testing.T) {
+	for _, query := range []string{
+		selectTransactionsWithTransferArraysInTimestampRange,
+		selectTransactionsInTimestampRange,
+	} {
+		assert.Equal(t, strings.Count(query, "("), strings.Count(query, ")"))
+	}
+
+	// one array per scanned transfer field
+	assert.Equal(t, 17, strings.Count(transferArrayColumns, "_arrays."))
+	assert.Equal(t, 17, strings.Count(transferArrayJoins, "array_agg("))
+	assert.Equal(t, 2, strings.Count(deletedEntityColumns, "deleted_entities."))
+	assert.Equal(t, 2, strings.Count(deletedEntityJoin, "array_agg("))
+}
+
+// BenchmarkDecodeDenseTransfers compares the cpu time of decoding the transfers of a dense airdrop transaction from the
+// json columns and from the typed arrays, run with
+// "go test -run ^$ -bench DecodeDenseTransfers -cpuprofile cpu.out ./app/persistence/"
+func BenchmarkDecodeDenseTransfers(b *testing.B) {
+	const receivers = 1000
+	cryptoTransfers := []builder.HbarTransfer{{AccountId: firstEntityId, Amount: -100}}
+	tokenTransfers := []builder.TokenTransfer{{
+		AccountId: firstEntityId,
+		Amount:    -receivers,
+		TokenId:   tokenId1,
+		Type:      domain.TokenTypeFungibleCommon,
+	}}
+	for i := 0; i < receivers; i++ {
+		tokenTransfers = append(tokenTransfers, builder.TokenTransfer{
+			AccountId: domain.MustDecodeEntityId(int64(20000 + i%100)),
+			Amount:    1,
+			TokenId:   tokenId1,
+			Type:      domain.TokenTypeFungibleCommon,
+		})
+	}
+
+	// the json as aggregated by the database, with the encoded entity ids
+	tokenJson := make([]string, 0, len(tokenTransfers))
+	for _, transfer := range tokenTransfers {
+		tokenJson = append(tokenJson, fmt.Sprintf(
+			`{"account_id": %d, "amount": %d, "decimals": %d, "is_approval": %t, "token_id": %d, "type": "%s"}`,
+			transfer.AccountId.EncodedId, transfer.Amount, transfer.Decimals, transfer.IsApproval,
+			transfer.TokenId.EncodedId, transfer.Type))
+	}
+	jsonTxn := transaction{
+		CryptoTransfers: fmt.Sprintf(`[{"account_id": %d, "amount": %d, "is_approval": false}]`,
+			cryptoTransfers[0].AccountId.EncodedId, cryptoTransfers[0].Amount),
+		NftTransfers:    "[]",
+		NonFeeTransfers: "[]",
+		TokenTransfers:  "[" + strings.Join(tokenJson, ",") + "]",
+		Token:           "{}",
+		Schedule:        "{}",
+	}
+
+	token := &tokenTransferArrays{}
+	for _, transfer := range tokenTransfers {
+		token.accountIds = append(token.accountIds, transfer.AccountId.EncodedId)
+		token.amounts = append(token.amounts, transfer.Amount)
+		token.decimals = append(token.decimals, transfer.Decimals)
+		token.isApprovals = append(token.isApprovals, transfer.IsApproval)
+		token.tokenIds = append(token.tokenIds, transfer.TokenId.EncodedId)
+		token.types = append(token.types, transfer.Type)
+	}
+	arraysTxn := transaction{
+		Token:    "{}",
+		Schedule: "{}",
+		transferArrays: transferArrays{
+			crypto: &hbarTransferArrays{
+				accountIds:  []int64{firstEntityId.EncodedId},
+				amounts:     []int64{-100},
+				isApprovals: []bool{false},
+			},
+			nft:    &nftTransferArrays{},
+			nonFee: &hbarTransferArrays{},
+			token:  token,
+		},
+	}
+
+	for _, bm := range []struct {
+		name string
+		txn  transaction
+	}{{"json", jsonTxn}, {"arrays", arraysTxn}} {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			decoded := &builder.Transaction{}
+			for i := 0; i < b.N; i++ {
+				if err := bm.txn.decodeInto(decoded); err != nil || len(decoded.TokenTransfers) != receivers+1 {
+					b.Fatalf("Failed to decode the transfers: %v", err)
+				}
+			}
+		})
+	}
+}
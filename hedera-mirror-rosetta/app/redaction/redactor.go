@@ -0,0 +1,145 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package redaction
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	log "github.com/sirupsen/logrus"
+)
+
+// the metadata keys stripped by each redaction category, in both the snake case of the rosetta metadata and the camel
+// case of the protobuf json of the decoded transactions
+var (
+	aliasMetadataKeys        = []string{"alias", "evmAddress", "evm_address"}
+	memoMetadataKeys         = []string{"memo"}
+	topicMessageMetadataKeys = []string{
+		"chunkInfo",
+		"initial_transaction_id",
+		"message",
+		"runningHash",
+		"running_hash",
+	}
+)
+
+// redactedSubtreeKeys are the keys of the json objects whose subtrees are redacted, i.e., the metadata of any rosetta
+// object and the result of /call. Other fields such as the error messages in /network/options are kept
+var redactedSubtreeKeys = map[string]bool{"metadata": true, "result": true}
+
+// Redactor strips the privacy-sensitive keys from the metadata of the rosetta objects per the redaction config. It's
+// shared by the serializers of every output of the rosetta objects, i.e., the http responses, the gRPC data API, the
+// webhook notifications, and the block stream. A nil Redactor redacts nothing
+type Redactor struct {
+	keys map[string]bool
+}
+
+// NewRedactor creates a Redactor, nil if no metadata is redacted
+func NewRedactor(redaction config.Redaction) *Redactor {
+	if !redaction.IsEnabled() {
+		return nil
+	}
+
+	keys := make(map[string]bool)
+	addKeys := func(enabled bool, categoryKeys []string) {
+		if !enabled {
+			return
+		}
+		for _, key := range categoryKeys {
+			keys[key] = true
+		}
+	}
+	addKeys(redaction.Aliases, aliasMetadataKeys)
+	addKeys(redaction.Memos, memoMetadataKeys)
+	addKeys(redaction.TopicMessages, topicMessageMetadataKeys)
+	return &Redactor{keys: keys}
+}
+
+// IsRedacted returns true if the key is stripped from the metadata
+func (r *Redactor) IsRedacted(key string) bool {
+	return r != nil && r.keys[key]
+}
+
+// Redact returns the json with the sensitive keys stripped from the metadata subtrees, or the json unchanged if it's
+// not a json object or array
+func (r *Redactor) Redact(data []byte) []byte {
+	return r.redact(data, false)
+}
+
+// RedactMetadata returns the json encoded metadata with the sensitive keys stripped, or the json unchanged if it's not
+// a json object or array
+func (r *Redactor) RedactMetadata(metadata []byte) []byte {
+	return r.redact(metadata, true)
+}
+
+func (r *Redactor) redact(data []byte, inMetadata bool) []byte {
+	if r == nil || len(data) == 0 {
+		return data
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return data
+	}
+
+	if !r.walk(value, inMetadata) {
+		return data
+	}
+
+	redacted, err := json.Marshal(value)
+	if err != nil {
+		log.Errorf("Failed to encode the redacted json: %s", err)
+		return data
+	}
+
+	if bytes.HasSuffix(data, []byte{'\n'}) {
+		redacted = append(redacted, '\n')
+	}
+	return redacted
+}
+
+// walk strips the sensitive keys from the value in place if it's in a metadata subtree and descends into the nested
+// objects and arrays. It returns true if any key is stripped
+func (r *Redactor) walk(value interface{}, inMetadata bool) bool {
+	redacted := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if inMetadata && r.keys[key] {
+				delete(v, key)
+				redacted = true
+				continue
+			}
+			if r.walk(child, inMetadata || redactedSubtreeKeys[key]) {
+				redacted = true
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			if r.walk(child, inMetadata) {
+				redacted = true
+			}
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,100 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package redaction
+
+import (
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRedactor(t *testing.T) {
+	assert.Nil(t, NewRedactor(config.Redaction{}))
+	assert.NotNil(t, NewRedactor(config.Redaction{Memos: true}))
+}
+
+func TestRedactorIsRedacted(t *testing.T) {
+	// given
+	redactor := NewRedactor(config.Redaction{Memos: true})
+	var nilRedactor *Redactor
+
+	// then
+	assert.True(t, redactor.IsRedacted("memo"))
+	assert.False(t, redactor.IsRedacted("evm_address"))
+	assert.False(t, nilRedactor.IsRedacted("memo"))
+}
+
+func TestRedactorRedact(t *testing.T) {
+	// given
+	redactor := NewRedactor(config.Redaction{Aliases: true, Memos: true})
+	data := []byte(`{
+		"transaction": {
+			"operations": [{"account": {"address": "0.0.100", "metadata": {"evm_address": "0x01"}}}],
+			"metadata": {"memo": "secret", "size": 12345678901234567890}
+		},
+		"memo": "outside metadata"
+	}`)
+	expected := `{
+		"transaction": {
+			"operations": [{"account": {"address": "0.0.100", "metadata": {}}}],
+			"metadata": {"size": 12345678901234567890}
+		},
+		"memo": "outside metadata"
+	}`
+
+	// when
+	actual := redactor.Redact(data)
+
+	// then
+	assert.JSONEq(t, expected, string(actual))
+}
+
+func TestRedactorRedactMetadata(t *testing.T) {
+	// given
+	redactor := NewRedactor(config.Redaction{TopicMessages: true})
+	metadata := []byte(`{"message": "0x02", "chunks": [{"running_hash": "0x03", "sequence_number": 1}]}`)
+
+	// when
+	actual := redactor.RedactMetadata(metadata)
+
+	// then
+	assert.JSONEq(t, `{"chunks": [{"sequence_number": 1}]}`, string(actual))
+}
+
+func TestRedactorUnchanged(t *testing.T) {
+	var tests = []struct {
+		name     string
+		redactor *Redactor
+		data     string
+	}{
+		{name: "nil redactor", data: `{"metadata": {"memo": "secret"}}`},
+		{name: "empty", redactor: NewRedactor(config.Redaction{Memos: true})},
+		{name: "invalid json", redactor: NewRedactor(config.Redaction{Memos: true}), data: "{invalid"},
+		{name: "nothing to redact", redactor: NewRedactor(config.Redaction{Memos: true}), data: `{"size": 1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.data, string(tt.redactor.Redact([]byte(tt.data))))
+			assert.Equal(t, tt.data, string(tt.redactor.RedactMetadata([]byte(tt.data))))
+		})
+	}
+}
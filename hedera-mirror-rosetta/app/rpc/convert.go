@@ -0,0 +1,234 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package rpc
+
+import (
+	"encoding/json"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/rpc/pb"
+)
+
+// marshalMetadata encodes the rosetta metadata as JSON, so the values are kept as is. Empty metadata is encoded as nil
+func marshalMetadata(metadata map[string]interface{}) ([]byte, error) {
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+
+	return json.Marshal(metadata)
+}
+
+// unmarshalMetadata decodes the JSON encoded metadata. Empty metadata is decoded as nil
+func unmarshalMetadata(data []byte) (map[string]interface{}, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+func toRosettaAccountIdentifier(accountIdentifier *pb.AccountIdentifier) (*rTypes.AccountIdentifier, error) {
+	metadata, err := unmarshalMetadata(accountIdentifier.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rTypes.AccountIdentifier{Address: accountIdentifier.Address, Metadata: metadata}, nil
+}
+
+func toRosettaPartialBlockIdentifier(blockIdentifier *pb.PartialBlockIdentifier) *rTypes.PartialBlockIdentifier {
+	if blockIdentifier == nil {
+		return &rTypes.PartialBlockIdentifier{}
+	}
+
+	return &rTypes.PartialBlockIdentifier{Index: blockIdentifier.Index, Hash: blockIdentifier.Hash}
+}
+
+func toProtoAccountIdentifier(accountIdentifier *rTypes.AccountIdentifier) (*pb.AccountIdentifier, error) {
+	if accountIdentifier == nil {
+		return nil, nil
+	}
+
+	metadata, err := marshalMetadata(accountIdentifier.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.AccountIdentifier{Address: accountIdentifier.Address, Metadata: metadata}, nil
+}
+
+func toProtoAmount(amount *rTypes.Amount) (*pb.Amount, error) {
+	if amount == nil {
+		return nil, nil
+	}
+
+	metadata, err := marshalMetadata(amount.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	var currency *pb.Currency
+	if amount.Currency != nil {
+		currencyMetadata, err := marshalMetadata(amount.Currency.Metadata)
+		if err != nil {
+			return nil, err
+		}
+
+		currency = &pb.Currency{
+			Symbol:   amount.Currency.Symbol,
+			Decimals: amount.Currency.Decimals,
+			Metadata: currencyMetadata,
+		}
+	}
+
+	return &pb.Amount{Value: amount.Value, Currency: currency, Metadata: metadata}, nil
+}
+
+func toProtoAmounts(amounts []*rTypes.Amount) ([]*pb.Amount, error) {
+	protoAmounts := make([]*pb.Amount, 0, len(amounts))
+	for _, amount := range amounts {
+		protoAmount, err := toProtoAmount(amount)
+		if err != nil {
+			return nil, err
+		}
+		protoAmounts = append(protoAmounts, protoAmount)
+	}
+
+	return protoAmounts, nil
+}
+
+func toProtoBlock(block *rTypes.Block) (*pb.Block, error) {
+	if block == nil {
+		return nil, nil
+	}
+
+	metadata, err := marshalMetadata(block.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions := make([]*pb.Transaction, 0, len(block.Transactions))
+	for _, transaction := range block.Transactions {
+		protoTransaction, err := toProtoTransaction(transaction)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, protoTransaction)
+	}
+
+	return &pb.Block{
+		BlockIdentifier:       toProtoBlockIdentifier(block.BlockIdentifier),
+		ParentBlockIdentifier: toProtoBlockIdentifier(block.ParentBlockIdentifier),
+		Timestamp:             block.Timestamp,
+		Transactions:          transactions,
+		Metadata:              metadata,
+	}, nil
+}
+
+func toProtoBlockIdentifier(blockIdentifier *rTypes.BlockIdentifier) *pb.BlockIdentifier {
+	if blockIdentifier == nil {
+		return nil
+	}
+
+	return &pb.BlockIdentifier{Index: blockIdentifier.Index, Hash: blockIdentifier.Hash}
+}
+
+func toProtoOperation(operation *rTypes.Operation) (*pb.Operation, error) {
+	metadata, err := marshalMetadata(operation.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := toProtoAccountIdentifier(operation.Account)
+	if err != nil {
+		return nil, err
+	}
+
+	amount, err := toProtoAmount(operation.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	relatedOperations := make([]*pb.OperationIdentifier, 0, len(operation.RelatedOperations))
+	for _, related := range operation.RelatedOperations {
+		relatedOperations = append(relatedOperations, toProtoOperationIdentifier(related))
+	}
+
+	var status string
+	if operation.Status != nil {
+		status = *operation.Status
+	}
+
+	return &pb.Operation{
+		OperationIdentifier: toProtoOperationIdentifier(operation.OperationIdentifier),
+		RelatedOperations:   relatedOperations,
+		Type:                operation.Type,
+		Status:              status,
+		Account:             account,
+		Amount:              amount,
+		Metadata:            metadata,
+	}, nil
+}
+
+func toProtoOperationIdentifier(operationIdentifier *rTypes.OperationIdentifier) *pb.OperationIdentifier {
+	if operationIdentifier == nil {
+		return nil
+	}
+
+	return &pb.OperationIdentifier{Index: operationIdentifier.Index}
+}
+
+func toProtoTransaction(transaction *rTypes.Transaction) (*pb.Transaction, error) {
+	if transaction == nil {
+		return nil, nil
+	}
+
+	metadata, err := marshalMetadata(transaction.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	operations := make([]*pb.Operation, 0, len(transaction.Operations))
+	for _, operation := range transaction.Operations {
+		protoOperation, err := toProtoOperation(operation)
+		if err != nil {
+			return nil, err
+		}
+		operations = append(operations, protoOperation)
+	}
+
+	return &pb.Transaction{
+		TransactionIdentifier: toProtoTransactionIdentifier(transaction.TransactionIdentifier),
+		Operations:            operations,
+		Metadata:              metadata,
+	}, nil
+}
+
+func toProtoTransactionIdentifier(transactionIdentifier *rTypes.TransactionIdentifier) *pb.TransactionIdentifier {
+	if transactionIdentifier == nil {
+		return nil
+	}
+
+	return &pb.TransactionIdentifier{Hash: transactionIdentifier.Hash}
+}
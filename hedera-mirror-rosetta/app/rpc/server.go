@@ -0,0 +1,335 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package rpc
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/server"
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/db"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/middleware"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/redaction"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/rpc/pb"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// dataServer serves the data API over gRPC with the rosetta services, converting between the protobuf messages and the
+// rosetta models
+type dataServer struct {
+	pb.UnimplementedDataServiceServer
+	accountAPIService server.AccountAPIServicer
+	blockAPIService   server.BlockAPIServicer
+	network           *rTypes.NetworkIdentifier
+}
+
+const metadataFieldName = "metadata"
+
+// methodEndpoints maps the full method names of the calls to the paths of the http endpoints they implement
+var methodEndpoints = map[string]string{
+	"/" + pb.DataService_ServiceDesc.ServiceName + "/getAccountBalance":   "/account/balance",
+	"/" + pb.DataService_ServiceDesc.ServiceName + "/getBlock":            "/block",
+	"/" + pb.DataService_ServiceDesc.ServiceName + "/getBlockTransaction": "/block/transaction",
+}
+
+// NewServer creates a gRPC server serving the data API of the network. The calls share the concurrency limiter with
+// the http server and have the same timeouts as the http endpoints they implement, keyed by the endpoint path. The
+// metadata of the responses is redacted with the same redactor as the http responses
+func NewServer(
+	accountAPIService server.AccountAPIServicer,
+	blockAPIService server.BlockAPIServicer,
+	network *rTypes.NetworkIdentifier,
+	limiter *middleware.ConcurrencyLimiter,
+	endpointTimeouts map[string]time.Duration,
+	redactor *redaction.Redactor,
+) *grpc.Server {
+	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		tracingInterceptor,
+		newConcurrencyLimitInterceptor(limiter),
+		newTimeoutInterceptor(endpointTimeouts),
+		newRedactionInterceptor(redactor),
+	))
+	pb.RegisterDataServiceServer(grpcServer, &dataServer{
+		accountAPIService: accountAPIService,
+		blockAPIService:   blockAPIService,
+		network:           network,
+	})
+	return grpcServer
+}
+
+// GetBlock implements the /block endpoint
+func (s *dataServer) GetBlock(ctx context.Context, request *pb.BlockRequest) (*pb.BlockResponse, error) {
+	response, rErr := s.blockAPIService.Block(ctx, &rTypes.BlockRequest{
+		NetworkIdentifier: s.network,
+		BlockIdentifier:   toRosettaPartialBlockIdentifier(request.BlockIdentifier),
+	})
+	if rErr != nil {
+		return nil, toStatusError(rErr)
+	}
+
+	block, err := toProtoBlock(response.Block)
+	if err != nil {
+		return nil, toMarshallingStatusError(err)
+	}
+
+	otherTransactions := make([]*pb.TransactionIdentifier, 0, len(response.OtherTransactions))
+	for _, transactionIdentifier := range response.OtherTransactions {
+		otherTransactions = append(otherTransactions, toProtoTransactionIdentifier(transactionIdentifier))
+	}
+
+	return &pb.BlockResponse{Block: block, OtherTransactions: otherTransactions}, nil
+}
+
+// GetBlockTransaction implements the /block/transaction endpoint
+func (s *dataServer) GetBlockTransaction(ctx context.Context, request *pb.BlockTransactionRequest) (
+	*pb.BlockTransactionResponse,
+	error,
+) {
+	if request.BlockIdentifier == nil || request.TransactionIdentifier == nil {
+		return nil, toInvalidArgumentStatusError("block_identifier and transaction_identifier are required")
+	}
+
+	response, rErr := s.blockAPIService.BlockTransaction(ctx, &rTypes.BlockTransactionRequest{
+		NetworkIdentifier: s.network,
+		BlockIdentifier: &rTypes.BlockIdentifier{
+			Index: request.BlockIdentifier.Index,
+			Hash:  request.BlockIdentifier.Hash,
+		},
+		TransactionIdentifier: &rTypes.TransactionIdentifier{Hash: request.TransactionIdentifier.Hash},
+	})
+	if rErr != nil {
+		return nil, toStatusError(rErr)
+	}
+
+	transaction, err := toProtoTransaction(response.Transaction)
+	if err != nil {
+		return nil, toMarshallingStatusError(err)
+	}
+
+	return &pb.BlockTransactionResponse{Transaction: transaction}, nil
+}
+
+// GetAccountBalance implements the /account/balance endpoint
+func (s *dataServer) GetAccountBalance(ctx context.Context, request *pb.AccountBalanceRequest) (
+	*pb.AccountBalanceResponse,
+	error,
+) {
+	if request.AccountIdentifier == nil {
+		return nil, toInvalidArgumentStatusError("account_identifier is required")
+	}
+
+	accountIdentifier, err := toRosettaAccountIdentifier(request.AccountIdentifier)
+	if err != nil {
+		return nil, toInvalidArgumentStatusError("invalid account_identifier metadata")
+	}
+
+	var blockIdentifier *rTypes.PartialBlockIdentifier
+	if request.BlockIdentifier != nil {
+		blockIdentifier = toRosettaPartialBlockIdentifier(request.BlockIdentifier)
+	}
+
+	response, rErr := s.accountAPIService.AccountBalance(ctx, &rTypes.AccountBalanceRequest{
+		NetworkIdentifier: s.network,
+		AccountIdentifier: accountIdentifier,
+		BlockIdentifier:   blockIdentifier,
+	})
+	if rErr != nil {
+		return nil, toStatusError(rErr)
+	}
+
+	balances, err := toProtoAmounts(response.Balances)
+	if err != nil {
+		return nil, toMarshallingStatusError(err)
+	}
+
+	metadata, err := marshalMetadata(response.Metadata)
+	if err != nil {
+		return nil, toMarshallingStatusError(err)
+	}
+
+	return &pb.AccountBalanceResponse{
+		BlockIdentifier: toProtoBlockIdentifier(response.BlockIdentifier),
+		Balances:        balances,
+		Metadata:        metadata,
+	}, nil
+}
+
+// toStatusCode maps the rosetta error to the closest gRPC status code. The rest of the retriable errors are mapped to
+// Unavailable and the non-retriable ones to InvalidArgument
+func toStatusCode(rErr *rTypes.Error) codes.Code {
+	switch rErr.Code {
+	case errors.ErrAccountNotFound.Code, errors.ErrBlockNotFound.Code, errors.ErrNftNotFound.Code,
+		errors.ErrScheduleNotFound.Code, errors.ErrTokenNotFound.Code, errors.ErrTopicMessageNotFound.Code,
+		errors.ErrTransactionNotFound.Code:
+		return codes.NotFound
+	case errors.ErrEndpointNotSupportedInOfflineMode.Code, errors.ErrNotImplemented.Code:
+		return codes.Unimplemented
+	case errors.ErrEndpointTimeout.Code:
+		return codes.DeadlineExceeded
+	case errors.ErrInternalServerError.Code:
+		return codes.Internal
+	}
+
+	if rErr.Retriable {
+		return codes.Unavailable
+	}
+	return codes.InvalidArgument
+}
+
+// toStatusError converts the rosetta error to a gRPC status error with the rosetta error attached as the details
+func toStatusError(rErr *rTypes.Error) error {
+	st := status.New(toStatusCode(rErr), rErr.Message)
+	details, err := marshalMetadata(rErr.Details)
+	if err != nil {
+		log.Errorf("Failed to marshal error details: %s", err)
+	}
+
+	withDetails, err := st.WithDetails(&pb.Error{
+		Code:      rErr.Code,
+		Message:   rErr.Message,
+		Retriable: rErr.Retriable,
+		Details:   details,
+	})
+	if err != nil {
+		log.Errorf("Failed to attach error details: %s", err)
+		return st.Err()
+	}
+
+	return withDetails.Err()
+}
+
+func toInvalidArgumentStatusError(reason string) error {
+	return toStatusError(errors.AddErrorDetails(errors.ErrInvalidArgument, "reason", reason))
+}
+
+func toMarshallingStatusError(err error) error {
+	log.Errorf("Failed to convert response to protobuf: %s", err)
+	return toStatusError(errors.ErrInternalServerError)
+}
+
+// tracingInterceptor traces the calls to the log the same way as the http requests, and pins the queries of a call to
+// the same database
+func tracingInterceptor(
+	ctx context.Context,
+	request interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	start := time.Now()
+	response, err := handler(db.WithDbClientPinning(ctx), request)
+
+	clientIpAddress := ""
+	if p, ok := peer.FromContext(ctx); ok {
+		clientIpAddress = p.Addr.String()
+		if host, _, splitErr := net.SplitHostPort(clientIpAddress); splitErr == nil {
+			clientIpAddress = host
+		}
+	}
+	log.Infof("%s %s (%s) in %s", clientIpAddress, info.FullMethod, status.Code(err), time.Since(start))
+
+	return response, err
+}
+
+// newConcurrencyLimitInterceptor rejects the calls above the limit of the limiter immediately with Unavailable and a
+// retriable rosetta error
+func newConcurrencyLimitInterceptor(limiter *middleware.ConcurrencyLimiter) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		request interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if !limiter.TryAcquire() {
+			log.Warnf("Rejected %s with %d concurrent requests in flight", info.FullMethod, limiter.Limit())
+			return nil, toStatusError(errors.ErrTooManyConcurrentRequests)
+		}
+
+		defer limiter.Release()
+		return handler(ctx, request)
+	}
+}
+
+// newTimeoutInterceptor sets a deadline on the context of the calls whose http endpoint has a configured timeout. A
+// non-positive timeout is ignored
+func newTimeoutInterceptor(endpointTimeouts map[string]time.Duration) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		request interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		timeout := endpointTimeouts[methodEndpoints[info.FullMethod]]
+		if timeout <= 0 {
+			return handler(ctx, request)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return handler(ctx, request)
+	}
+}
+
+// newRedactionInterceptor strips the sensitive keys from the json encoded metadata of the successful responses with
+// the redactor. The error details are kept as is, the same as the http error responses
+func newRedactionInterceptor(redactor *redaction.Redactor) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		request interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		response, err := handler(ctx, request)
+		if err != nil || redactor == nil {
+			return response, err
+		}
+
+		if message, ok := response.(proto.Message); ok {
+			redactMetadata(redactor, message.ProtoReflect())
+		}
+		return response, nil
+	}
+}
+
+// redactMetadata redacts the metadata fields of the message and its nested messages in place
+func redactMetadata(redactor *redaction.Redactor, message protoreflect.Message) {
+	message.Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		switch {
+		case field.IsList() && field.Kind() == protoreflect.MessageKind:
+			list := value.List()
+			for i := 0; i < list.Len(); i++ {
+				redactMetadata(redactor, list.Get(i).Message())
+			}
+		case field.Kind() == protoreflect.MessageKind:
+			redactMetadata(redactor, value.Message())
+		case field.Name() == metadataFieldName && field.Kind() == protoreflect.BytesKind:
+			message.Set(field, protoreflect.ValueOfBytes(redactor.RedactMetadata(value.Bytes())))
+		}
+		return true
+	})
+}
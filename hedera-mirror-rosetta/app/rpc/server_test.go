@@ -0,0 +1,506 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/middleware"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/redaction"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/rpc/pb"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	blockHash       = "0x0a0b"
+	parentBlockHash = "0x0102"
+	transactionHash = "0xabcdef"
+)
+
+var network = &rTypes.NetworkIdentifier{Blockchain: "Hedera", Network: "testnet"}
+
+func TestDataServerSuite(t *testing.T) {
+	suite.Run(t, new(dataServerSuite))
+}
+
+type dataServerSuite struct {
+	suite.Suite
+	client                pb.DataServiceClient
+	clientConn            *grpc.ClientConn
+	grpcServer            *grpc.Server
+	mockAccountAPIService *mocks.MockAccountAPIService
+	mockBlockAPIService   *mocks.MockBlockAPIService
+}
+
+func (suite *dataServerSuite) SetupTest() {
+	suite.mockAccountAPIService = &mocks.MockAccountAPIService{}
+	suite.mockBlockAPIService = &mocks.MockBlockAPIService{}
+	suite.grpcServer = NewServer(
+		suite.mockAccountAPIService,
+		suite.mockBlockAPIService,
+		network,
+		nil,
+		map[string]time.Duration{"/block": time.Minute},
+		nil,
+	)
+
+	listener := bufconn.Listen(1024 * 1024)
+	go func() {
+		_ = suite.grpcServer.Serve(listener)
+	}()
+
+	clientConn, err := grpc.Dial(
+		"bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	suite.Require().NoError(err)
+	suite.clientConn = clientConn
+	suite.client = pb.NewDataServiceClient(clientConn)
+}
+
+func (suite *dataServerSuite) TearDownTest() {
+	_ = suite.clientConn.Close()
+	suite.grpcServer.Stop()
+}
+
+func (suite *dataServerSuite) TestGetBlock() {
+	// given
+	index := int64(10)
+	var actualRequest *rTypes.BlockRequest
+	suite.mockBlockAPIService.On("Block", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { actualRequest = args.Get(1).(*rTypes.BlockRequest) }).
+		Return(&rTypes.BlockResponse{
+			Block:             getRosettaBlock(),
+			OtherTransactions: []*rTypes.TransactionIdentifier{{Hash: "0xfedcba"}},
+		}, mocks.NilError)
+
+	// when
+	actual, err := suite.client.GetBlock(
+		context.Background(),
+		&pb.BlockRequest{BlockIdentifier: &pb.PartialBlockIdentifier{Index: &index}},
+	)
+
+	// then
+	suite.Require().NoError(err)
+	suite.Equal(&rTypes.BlockRequest{
+		NetworkIdentifier: network,
+		BlockIdentifier:   &rTypes.PartialBlockIdentifier{Index: &index},
+	}, actualRequest)
+	suite.assertBlock(actual.Block)
+	suite.Require().Len(actual.OtherTransactions, 1)
+	suite.Equal("0xfedcba", actual.OtherTransactions[0].Hash)
+}
+
+func (suite *dataServerSuite) TestGetBlockLatest() {
+	// given
+	var actualRequest *rTypes.BlockRequest
+	suite.mockBlockAPIService.On("Block", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { actualRequest = args.Get(1).(*rTypes.BlockRequest) }).
+		Return(&rTypes.BlockResponse{Block: getRosettaBlock()}, mocks.NilError)
+
+	// when
+	actual, err := suite.client.GetBlock(context.Background(), &pb.BlockRequest{})
+
+	// then
+	suite.Require().NoError(err)
+	suite.Equal(&rTypes.PartialBlockIdentifier{}, actualRequest.BlockIdentifier)
+	suite.assertBlock(actual.Block)
+	suite.Empty(actual.OtherTransactions)
+}
+
+func (suite *dataServerSuite) TestGetBlockNotFound() {
+	// given
+	suite.mockBlockAPIService.On("Block", mock.Anything, mock.Anything).
+		Return(mocks.NilBlockResponse, errors.ErrBlockNotFound)
+
+	// when
+	actual, err := suite.client.GetBlock(context.Background(), &pb.BlockRequest{})
+
+	// then
+	suite.Nil(actual)
+	suite.assertStatusError(err, codes.NotFound, errors.ErrBlockNotFound)
+}
+
+func (suite *dataServerSuite) TestGetBlockTransaction() {
+	// given
+	var actualRequest *rTypes.BlockTransactionRequest
+	suite.mockBlockAPIService.On("BlockTransaction", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { actualRequest = args.Get(1).(*rTypes.BlockTransactionRequest) }).
+		Return(&rTypes.BlockTransactionResponse{Transaction: getRosettaBlock().Transactions[0]}, mocks.NilError)
+
+	// when
+	actual, err := suite.client.GetBlockTransaction(context.Background(), &pb.BlockTransactionRequest{
+		BlockIdentifier:       &pb.BlockIdentifier{Index: 10, Hash: blockHash},
+		TransactionIdentifier: &pb.TransactionIdentifier{Hash: transactionHash},
+	})
+
+	// then
+	suite.Require().NoError(err)
+	suite.Equal(&rTypes.BlockTransactionRequest{
+		NetworkIdentifier:     network,
+		BlockIdentifier:       &rTypes.BlockIdentifier{Index: 10, Hash: blockHash},
+		TransactionIdentifier: &rTypes.TransactionIdentifier{Hash: transactionHash},
+	}, actualRequest)
+	suite.assertTransaction(actual.Transaction)
+}
+
+func (suite *dataServerSuite) TestGetBlockTransactionMissingIdentifier() {
+	for _, request := range []*pb.BlockTransactionRequest{
+		{},
+		{BlockIdentifier: &pb.BlockIdentifier{Index: 10, Hash: blockHash}},
+		{TransactionIdentifier: &pb.TransactionIdentifier{Hash: transactionHash}},
+	} {
+		// when
+		actual, err := suite.client.GetBlockTransaction(context.Background(), request)
+
+		// then
+		suite.Nil(actual)
+		suite.assertStatusError(err, codes.InvalidArgument, errors.ErrInvalidArgument)
+	}
+	suite.mockBlockAPIService.AssertNotCalled(suite.T(), "BlockTransaction", mock.Anything, mock.Anything)
+}
+
+func (suite *dataServerSuite) TestGetAccountBalance() {
+	// given
+	var actualRequest *rTypes.AccountBalanceRequest
+	suite.mockAccountAPIService.On("AccountBalance", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { actualRequest = args.Get(1).(*rTypes.AccountBalanceRequest) }).
+		Return(&rTypes.AccountBalanceResponse{
+			BlockIdentifier: &rTypes.BlockIdentifier{Index: 10, Hash: blockHash},
+			Balances: []*rTypes.Amount{
+				{Value: "100", Currency: &rTypes.Currency{Symbol: "HBAR", Decimals: 8}},
+				{
+					Value:    "20",
+					Currency: &rTypes.Currency{Symbol: "0.0.2001", Metadata: map[string]interface{}{"type": "FUNGIBLE_COMMON"}},
+				},
+			},
+			Metadata: map[string]interface{}{"account_id": "0.0.1001"},
+		}, mocks.NilError)
+
+	// when
+	actual, err := suite.client.GetAccountBalance(context.Background(), &pb.AccountBalanceRequest{
+		AccountIdentifier: &pb.AccountIdentifier{Address: "0.0.1001"},
+	})
+
+	// then
+	suite.Require().NoError(err)
+	suite.Equal(&rTypes.AccountBalanceRequest{
+		NetworkIdentifier: network,
+		AccountIdentifier: &rTypes.AccountIdentifier{Address: "0.0.1001"},
+	}, actualRequest)
+	suite.Equal(int64(10), actual.BlockIdentifier.Index)
+	suite.Equal(blockHash, actual.BlockIdentifier.Hash)
+	suite.Require().Len(actual.Balances, 2)
+	suite.Equal("100", actual.Balances[0].Value)
+	suite.Equal("HBAR", actual.Balances[0].Currency.Symbol)
+	suite.Equal(int32(8), actual.Balances[0].Currency.Decimals)
+	suite.Nil(actual.Balances[0].Currency.Metadata)
+	suite.Equal("20", actual.Balances[1].Value)
+	suite.JSONEq(`{"type": "FUNGIBLE_COMMON"}`, string(actual.Balances[1].Currency.Metadata))
+	suite.JSONEq(`{"account_id": "0.0.1001"}`, string(actual.Metadata))
+}
+
+func (suite *dataServerSuite) TestGetAccountBalanceWithBlockIdentifier() {
+	// given
+	hash := blockHash
+	var actualRequest *rTypes.AccountBalanceRequest
+	suite.mockAccountAPIService.On("AccountBalance", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { actualRequest = args.Get(1).(*rTypes.AccountBalanceRequest) }).
+		Return(&rTypes.AccountBalanceResponse{
+			BlockIdentifier: &rTypes.BlockIdentifier{Index: 10, Hash: blockHash},
+			Balances:        []*rTypes.Amount{},
+		}, mocks.NilError)
+
+	// when
+	actual, err := suite.client.GetAccountBalance(context.Background(), &pb.AccountBalanceRequest{
+		AccountIdentifier: &pb.AccountIdentifier{Address: "0x1234", Metadata: []byte(`{"key":"value"}`)},
+		BlockIdentifier:   &pb.PartialBlockIdentifier{Hash: &hash},
+	})
+
+	// then
+	suite.Require().NoError(err)
+	suite.Equal(&rTypes.AccountBalanceRequest{
+		NetworkIdentifier: network,
+		AccountIdentifier: &rTypes.AccountIdentifier{
+			Address:  "0x1234",
+			Metadata: map[string]interface{}{"key": "value"},
+		},
+		BlockIdentifier: &rTypes.PartialBlockIdentifier{Hash: &hash},
+	}, actualRequest)
+	suite.Empty(actual.Balances)
+	suite.Nil(actual.Metadata)
+}
+
+func (suite *dataServerSuite) TestGetAccountBalanceInvalidRequest() {
+	for _, request := range []*pb.AccountBalanceRequest{
+		{},
+		{AccountIdentifier: &pb.AccountIdentifier{Address: "0.0.1001", Metadata: []byte("{")}},
+	} {
+		// when
+		actual, err := suite.client.GetAccountBalance(context.Background(), request)
+
+		// then
+		suite.Nil(actual)
+		suite.assertStatusError(err, codes.InvalidArgument, errors.ErrInvalidArgument)
+	}
+	suite.mockAccountAPIService.AssertNotCalled(suite.T(), "AccountBalance", mock.Anything, mock.Anything)
+}
+
+func (suite *dataServerSuite) TestGetAccountBalanceError() {
+	// given
+	suite.mockAccountAPIService.On("AccountBalance", mock.Anything, mock.Anything).
+		Return(mocks.NilAccountBalanceResponse, errors.ErrDatabaseError)
+
+	// when
+	actual, err := suite.client.GetAccountBalance(context.Background(), &pb.AccountBalanceRequest{
+		AccountIdentifier: &pb.AccountIdentifier{Address: "0.0.1001"},
+	})
+
+	// then
+	suite.Nil(actual)
+	suite.assertStatusError(err, codes.Unavailable, errors.ErrDatabaseError)
+}
+
+func (suite *dataServerSuite) assertBlock(actual *pb.Block) {
+	suite.Require().NotNil(actual)
+	suite.Equal(&pb.BlockIdentifier{Index: 10, Hash: blockHash}, actual.BlockIdentifier)
+	suite.Equal(&pb.BlockIdentifier{Index: 9, Hash: parentBlockHash}, actual.ParentBlockIdentifier)
+	suite.Equal(int64(1659000000000), actual.Timestamp)
+	// the int64 nanos exceed the precision of a double and must be kept as is
+	suite.Equal(`{"consensus_end_nanos":1659000000999999999}`, string(actual.Metadata))
+	suite.Require().Len(actual.Transactions, 1)
+	suite.assertTransaction(actual.Transactions[0])
+}
+
+func (suite *dataServerSuite) assertTransaction(actual *pb.Transaction) {
+	suite.Require().NotNil(actual)
+	suite.Equal(transactionHash, actual.TransactionIdentifier.Hash)
+	suite.JSONEq(`{"memo": "test"}`, string(actual.Metadata))
+	suite.Require().Len(actual.Operations, 2)
+
+	operation := actual.Operations[0]
+	suite.Equal(int64(0), operation.OperationIdentifier.Index)
+	suite.Empty(operation.RelatedOperations)
+	suite.Equal("CRYPTOTRANSFER", operation.Type)
+	suite.Equal("SUCCESS", operation.Status)
+	suite.Equal("0.0.1001", operation.Account.Address)
+	suite.Equal("-10", operation.Amount.Value)
+	suite.Equal("HBAR", operation.Amount.Currency.Symbol)
+	suite.Nil(operation.Metadata)
+
+	operation = actual.Operations[1]
+	suite.Equal(int64(1), operation.OperationIdentifier.Index)
+	suite.Require().Len(operation.RelatedOperations, 1)
+	suite.Equal(int64(0), operation.RelatedOperations[0].Index)
+	suite.Equal("", operation.Status)
+	suite.Nil(operation.Account)
+	suite.Nil(operation.Amount)
+	suite.JSONEq(`{"key": "value"}`, string(operation.Metadata))
+}
+
+func (suite *dataServerSuite) assertStatusError(err error, expectedCode codes.Code, expected *rTypes.Error) {
+	st, ok := status.FromError(err)
+	suite.Require().True(ok)
+	suite.Equal(expectedCode, st.Code())
+	suite.Equal(expected.Message, st.Message())
+	suite.Require().Len(st.Details(), 1)
+	details := st.Details()[0].(*pb.Error)
+	suite.Equal(expected.Code, details.Code)
+	suite.Equal(expected.Message, details.Message)
+	suite.Equal(expected.Retriable, details.Retriable)
+}
+
+func TestToStatusCode(t *testing.T) {
+	tests := []struct {
+		rErr     *rTypes.Error
+		expected codes.Code
+	}{
+		{errors.ErrAccountNotFound, codes.NotFound},
+		{errors.ErrBlockNotFound, codes.NotFound},
+		{errors.ErrTransactionNotFound, codes.NotFound},
+		{errors.ErrNotImplemented, codes.Unimplemented},
+		{errors.ErrEndpointTimeout, codes.DeadlineExceeded},
+		{errors.ErrInternalServerError, codes.Internal},
+		{errors.ErrDatabaseError, codes.Unavailable},
+		{errors.ErrNodeIsStarting, codes.Unavailable},
+		{errors.ErrInvalidAccount, codes.InvalidArgument},
+		{errors.AddErrorDetails(errors.ErrInvalidArgument, "reason", "test"), codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rErr.Message, func(t *testing.T) {
+			assert.Equal(t, tt.expected, toStatusCode(tt.rErr))
+		})
+	}
+}
+
+func getRosettaBlock() *rTypes.Block {
+	status := "SUCCESS"
+	return &rTypes.Block{
+		BlockIdentifier:       &rTypes.BlockIdentifier{Index: 10, Hash: blockHash},
+		ParentBlockIdentifier: &rTypes.BlockIdentifier{Index: 9, Hash: parentBlockHash},
+		Timestamp:             1659000000000,
+		Transactions: []*rTypes.Transaction{
+			{
+				TransactionIdentifier: &rTypes.TransactionIdentifier{Hash: transactionHash},
+				Operations: []*rTypes.Operation{
+					{
+						OperationIdentifier: &rTypes.OperationIdentifier{Index: 0},
+						Type:                "CRYPTOTRANSFER",
+						Status:              &status,
+						Account:             &rTypes.AccountIdentifier{Address: "0.0.1001"},
+						Amount:              &rTypes.Amount{Value: "-10", Currency: &rTypes.Currency{Symbol: "HBAR"}},
+					},
+					{
+						OperationIdentifier: &rTypes.OperationIdentifier{Index: 1},
+						RelatedOperations:   []*rTypes.OperationIdentifier{{Index: 0}},
+						Type:                "CRYPTOTRANSFER",
+						Metadata:            map[string]interface{}{"key": "value"},
+					},
+				},
+				Metadata: map[string]interface{}{"memo": "test"},
+			},
+		},
+		Metadata: map[string]interface{}{"consensus_end_nanos": int64(1659000000999999999)},
+	}
+}
+
+func TestConcurrencyLimitInterceptor(t *testing.T) {
+	// given
+	limiter := middleware.NewConcurrencyLimiter(1)
+	interceptor := newConcurrencyLimitInterceptor(limiter)
+	info := &grpc.UnaryServerInfo{FullMethod: "/" + pb.DataService_ServiceDesc.ServiceName + "/getBlock"}
+	var rejectedErr error
+	handler := func(ctx context.Context, request interface{}) (interface{}, error) {
+		// a concurrent call is rejected while the limit is reached
+		_, rejectedErr = interceptor(ctx, request, info, func(context.Context, interface{}) (interface{}, error) {
+			return "unexpected", nil
+		})
+		return "response", nil
+	}
+
+	// when
+	actual, err := interceptor(context.Background(), nil, info, handler)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "response", actual)
+	assert.Equal(t, codes.Unavailable, status.Code(rejectedErr))
+	assert.True(t, limiter.TryAcquire())
+}
+
+func TestTimeoutInterceptor(t *testing.T) {
+	tests := []struct {
+		method      string
+		hasDeadline bool
+	}{
+		{method: "getBlock", hasDeadline: true},
+		{method: "getBlockTransaction", hasDeadline: false},
+		{method: "getAccountBalance", hasDeadline: false},
+	}
+
+	interceptor := newTimeoutInterceptor(map[string]time.Duration{"/block": time.Minute, "/account/balance": 0})
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			// given
+			info := &grpc.UnaryServerInfo{FullMethod: "/" + pb.DataService_ServiceDesc.ServiceName + "/" + tt.method}
+			var deadline time.Time
+			var hasDeadline bool
+			handler := func(ctx context.Context, request interface{}) (interface{}, error) {
+				deadline, hasDeadline = ctx.Deadline()
+				return nil, nil
+			}
+
+			// when
+			_, err := interceptor(context.Background(), nil, info, handler)
+
+			// then
+			assert.NoError(t, err)
+			assert.Equal(t, tt.hasDeadline, hasDeadline)
+			if tt.hasDeadline {
+				assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+			}
+		})
+	}
+}
+
+func TestRedactionInterceptor(t *testing.T) {
+	// given
+	interceptor := newRedactionInterceptor(redaction.NewRedactor(config.Redaction{Aliases: true, Memos: true}))
+	info := &grpc.UnaryServerInfo{FullMethod: "/" + pb.DataService_ServiceDesc.ServiceName + "/getBlock"}
+	handler := func(ctx context.Context, request interface{}) (interface{}, error) {
+		return &pb.BlockResponse{Block: &pb.Block{
+			Transactions: []*pb.Transaction{{
+				Operations: []*pb.Operation{{
+					Account: &pb.AccountIdentifier{
+						Address:  "0.0.100",
+						Metadata: []byte(`{"evm_address":"0x01","size":1}`),
+					},
+				}},
+				Metadata: []byte(`{"memo":"secret"}`),
+			}},
+			Metadata: []byte(`{"memo":"secret"}`),
+		}}, nil
+	}
+
+	// when
+	response, err := interceptor(context.Background(), nil, info, handler)
+
+	// then
+	assert.NoError(t, err)
+	block := response.(*pb.BlockResponse).Block
+	assert.JSONEq(t, `{}`, string(block.Metadata))
+	assert.JSONEq(t, `{}`, string(block.Transactions[0].Metadata))
+	assert.JSONEq(t, `{"size":1}`, string(block.Transactions[0].Operations[0].Account.Metadata))
+	assert.Equal(t, "0.0.100", block.Transactions[0].Operations[0].Account.Address)
+}
+
+func TestRedactionInterceptorDisabled(t *testing.T) {
+	// given
+	interceptor := newRedactionInterceptor(nil)
+	expected := &pb.AccountBalanceResponse{Metadata: []byte(`{"memo":"kept"}`)}
+	handler := func(ctx context.Context, request interface{}) (interface{}, error) {
+		return expected, nil
+	}
+
+	// when
+	response, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, `{"memo":"kept"}`, string(response.(*pb.AccountBalanceResponse).Metadata))
+}
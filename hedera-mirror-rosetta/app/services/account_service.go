@@ -28,6 +28,7 @@ import (
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	log "github.com/sirupsen/logrus"
 )
 
 // AccountAPIService implements the server.AccountAPIServicer interface.
@@ -74,14 +75,34 @@ func (a *AccountAPIService) AccountBalance(
 		return nil, rErr
 	}
 
-	balances, accountIdString, rErr := a.accountRepo.RetrieveBalanceAtBlock(ctx, accountId, block.ConsensusEndNanos)
+	balances, accountIdString, keyBytes, rErr := a.accountRepo.RetrieveBalanceAtBlock(
+		ctx,
+		accountId,
+		block.ConsensusEndNanos,
+	)
 	if rErr != nil {
 		return nil, rErr
 	}
 
-	var metadata map[string]interface{}
+	metadata := getAccountKeyMetadata(accountId, keyBytes)
+
 	if accountId.HasAlias() && accountIdString != "" {
-		metadata = map[string]interface{}{"account_id": accountIdString}
+		if metadata == nil {
+			metadata = make(map[string]interface{})
+		}
+		metadata["account_id"] = accountIdString
+	}
+
+	lifecycle, rErr := a.accountRepo.GetAccountLifecycle(ctx, accountId)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	if lifecycle != nil {
+		if metadata == nil {
+			metadata = make(map[string]interface{})
+		}
+		metadata["lifecycle"] = lifecycle.ToMetadata()
 	}
 	return &rTypes.AccountBalanceResponse{
 		BlockIdentifier: block.GetRosettaBlockIdentifier(),
@@ -90,6 +111,21 @@ func (a *AccountAPIService) AccountBalance(
 	}, nil
 }
 
+// getAccountKeyMetadata returns the account's key as metadata, or nil if the account doesn't have a key
+func getAccountKeyMetadata(accountId types.AccountId, keyBytes []byte) map[string]interface{} {
+	if len(keyBytes) == 0 {
+		return nil
+	}
+
+	key, err := types.NewKeyFromBytes(keyBytes)
+	if err != nil {
+		log.Warnf("Failed to parse key of account %s: %s", accountId, err)
+		return nil
+	}
+
+	return key.ToMetadata()
+}
+
 func (a *AccountAPIService) AccountCoins(
 	_ context.Context,
 	_ *rTypes.AccountCoinsRequest,
@@ -147,7 +147,8 @@ func (suite *accountServiceSuite) SetupTest() {
 func (suite *accountServiceSuite) TestAccountBalance() {
 	// given:
 	suite.mockBlockRepo.On("RetrieveLatest").Return(block(), mocks.NilError)
-	suite.mockAccountRepo.On("RetrieveBalanceAtBlock").Return(amount(), "", mocks.NilError)
+	suite.mockAccountRepo.On("RetrieveBalanceAtBlock").Return(amount(), "", []byte{}, mocks.NilError)
+	suite.mockAccountRepo.On("GetAccountLifecycle").Return(mocks.NilAccountLifecycle, mocks.NilError)
 
 	// when:
 	actual, err := suite.accountService.AccountBalance(
@@ -169,7 +170,8 @@ func (suite *accountServiceSuite) TestAliasAccountBalance() {
 	alias := ed25519AliasPrefix + hex.EncodeToString(pk.BytesRaw())
 	metadata := map[string]interface{}{"account_id": accountId}
 	suite.mockBlockRepo.On("RetrieveLatest").Return(block(), mocks.NilError)
-	suite.mockAccountRepo.On("RetrieveBalanceAtBlock").Return(amount(), accountId, mocks.NilError)
+	suite.mockAccountRepo.On("RetrieveBalanceAtBlock").Return(amount(), accountId, []byte{}, mocks.NilError)
+	suite.mockAccountRepo.On("GetAccountLifecycle").Return(mocks.NilAccountLifecycle, mocks.NilError)
 
 	// when:
 	actual, err := suite.accountService.AccountBalance(
@@ -184,10 +186,101 @@ func (suite *accountServiceSuite) TestAliasAccountBalance() {
 	suite.mockBlockRepo.AssertNotCalled(suite.T(), "FindByHash")
 }
 
+func (suite *accountServiceSuite) TestAccountBalanceWithKey() {
+	// given:
+	_, pk := tdomain.GenEd25519KeyPair()
+	key, _, _ := types.PublicKey{PublicKey: pk}.ToAlias()
+	metadata := map[string]interface{}{
+		"key":          "0x" + hex.EncodeToString(key),
+		"key_readable": hex.EncodeToString(pk.BytesRaw()),
+		"key_type":     types.KeyTypeEd25519,
+	}
+	suite.mockBlockRepo.On("RetrieveLatest").Return(block(), mocks.NilError)
+	suite.mockAccountRepo.On("RetrieveBalanceAtBlock").Return(amount(), "", key, mocks.NilError)
+	suite.mockAccountRepo.On("GetAccountLifecycle").Return(mocks.NilAccountLifecycle, mocks.NilError)
+
+	// when:
+	actual, err := suite.accountService.AccountBalance(
+		defaultContext,
+		getAccountBalanceRequest(accountBalanceRequestRemoveBlockIdentifier),
+	)
+
+	// then:
+	assert.Equal(suite.T(), expectedAccountBalanceResponse(accountBalanceResponseMetadata(metadata)), actual)
+	assert.Nil(suite.T(), err)
+}
+
+func (suite *accountServiceSuite) TestAccountBalanceWithInvalidKey() {
+	// given:
+	suite.mockBlockRepo.On("RetrieveLatest").Return(block(), mocks.NilError)
+	suite.mockAccountRepo.On("RetrieveBalanceAtBlock").Return(amount(), "", []byte{0x1, 0x2, 0x3}, mocks.NilError)
+	suite.mockAccountRepo.On("GetAccountLifecycle").Return(mocks.NilAccountLifecycle, mocks.NilError)
+
+	// when:
+	actual, err := suite.accountService.AccountBalance(
+		defaultContext,
+		getAccountBalanceRequest(accountBalanceRequestRemoveBlockIdentifier),
+	)
+
+	// then:
+	assert.Equal(suite.T(), expectedAccountBalanceResponse(), actual)
+	assert.Nil(suite.T(), err)
+}
+
+func (suite *accountServiceSuite) TestAccountBalanceWithLifecycle() {
+	// given:
+	completedTimestamp := int64(200)
+	createdTimestamp := int64(100)
+	lifecycle := &types.AccountLifecycle{
+		CompletedTimestamp: &completedTimestamp,
+		CreatedTimestamp:   &createdTimestamp,
+		EvmAddress:         []byte{0x1, 0x2, 0x3},
+	}
+	metadata := map[string]interface{}{
+		"lifecycle": map[string]interface{}{
+			"completed_timestamp": completedTimestamp,
+			"created_timestamp":   createdTimestamp,
+			"evm_address":         "0x010203",
+			"state":               types.AccountLifecycleStateCompleted,
+		},
+	}
+	suite.mockBlockRepo.On("RetrieveLatest").Return(block(), mocks.NilError)
+	suite.mockAccountRepo.On("RetrieveBalanceAtBlock").Return(amount(), "", []byte{}, mocks.NilError)
+	suite.mockAccountRepo.On("GetAccountLifecycle").Return(lifecycle, mocks.NilError)
+
+	// when:
+	actual, err := suite.accountService.AccountBalance(
+		defaultContext,
+		getAccountBalanceRequest(accountBalanceRequestRemoveBlockIdentifier),
+	)
+
+	// then:
+	assert.Equal(suite.T(), expectedAccountBalanceResponse(accountBalanceResponseMetadata(metadata)), actual)
+	assert.Nil(suite.T(), err)
+}
+
+func (suite *accountServiceSuite) TestAccountBalanceThrowsWhenGetAccountLifecycleFails() {
+	// given:
+	suite.mockBlockRepo.On("RetrieveLatest").Return(block(), mocks.NilError)
+	suite.mockAccountRepo.On("RetrieveBalanceAtBlock").Return(amount(), "", []byte{}, mocks.NilError)
+	suite.mockAccountRepo.On("GetAccountLifecycle").Return(mocks.NilAccountLifecycle, errors.ErrDatabaseError)
+
+	// when:
+	actual, err := suite.accountService.AccountBalance(
+		defaultContext,
+		getAccountBalanceRequest(accountBalanceRequestRemoveBlockIdentifier),
+	)
+
+	// then:
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
 func (suite *accountServiceSuite) TestAccountBalanceWithBlockIdentifier() {
 	// given:
 	suite.mockBlockRepo.On("FindByIdentifier").Return(block(), mocks.NilError)
-	suite.mockAccountRepo.On("RetrieveBalanceAtBlock").Return(amount(), "", mocks.NilError)
+	suite.mockAccountRepo.On("RetrieveBalanceAtBlock").Return(amount(), "", []byte{}, mocks.NilError)
+	suite.mockAccountRepo.On("GetAccountLifecycle").Return(mocks.NilAccountLifecycle, mocks.NilError)
 
 	// when:
 	actual, err := suite.accountService.AccountBalance(defaultContext, getAccountBalanceRequest())
@@ -229,7 +322,7 @@ func (suite *accountServiceSuite) TestAccountBalanceThrowsWhenRetrieveBlockFails
 func (suite *accountServiceSuite) TestAccountBalanceThrowsWhenRetrieveBalanceAtBlockFails() {
 	// given:
 	suite.mockBlockRepo.On("FindByIdentifier").Return(block(), mocks.NilError)
-	suite.mockAccountRepo.On("RetrieveBalanceAtBlock").Return(types.AmountSlice{}, "", &rTypes.Error{})
+	suite.mockAccountRepo.On("RetrieveBalanceAtBlock").Return(types.AmountSlice{}, "", []byte(nil), &rTypes.Error{})
 
 	// when:
 	actual, err := suite.accountService.AccountBalance(defaultContext, getAccountBalanceRequest())
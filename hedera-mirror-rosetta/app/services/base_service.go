@@ -69,6 +69,46 @@ func (b *BaseService) FindByHashInBlock(
 	return b.transactionRepo.FindByHashInBlock(ctx, identifier, consensusStart, consensusEnd)
 }
 
+func (b *BaseService) FindRawByHashInBlock(
+	ctx context.Context,
+	identifier string,
+	consensusStart int64,
+	consensusEnd int64,
+) (*types.RawTransaction, *rTypes.Error) {
+	if !b.IsOnline() {
+		return nil, errors.ErrInternalServerError
+	}
+
+	return b.transactionRepo.FindRawByHashInBlock(ctx, identifier, consensusStart, consensusEnd)
+}
+
+func (b *BaseService) CountBetween(ctx context.Context, start int64, end int64) (int64, int64, *rTypes.Error) {
+	if !b.IsOnline() {
+		return 0, 0, errors.ErrInternalServerError
+	}
+
+	return b.transactionRepo.CountBetween(ctx, start, end)
+}
+
+func (b *BaseService) CountOperationsBetween(ctx context.Context, start int64, end int64) (int64, *rTypes.Error) {
+	if !b.IsOnline() {
+		return 0, errors.ErrInternalServerError
+	}
+
+	return b.transactionRepo.CountOperationsBetween(ctx, start, end)
+}
+
+func (b *BaseService) CountByTypeAndResult(ctx context.Context, start int64, end int64) (
+	*types.TransactionStatistics,
+	*rTypes.Error,
+) {
+	if !b.IsOnline() {
+		return nil, errors.ErrInternalServerError
+	}
+
+	return b.transactionRepo.CountByTypeAndResult(ctx, start, end)
+}
+
 func (b *BaseService) FindBetween(ctx context.Context, start int64, end int64) ([]*types.Transaction, *rTypes.Error) {
 	if !b.IsOnline() {
 		return nil, errors.ErrInternalServerError
@@ -77,6 +117,26 @@ func (b *BaseService) FindBetween(ctx context.Context, start int64, end int64) (
 	return b.transactionRepo.FindBetween(ctx, start, end)
 }
 
+func (b *BaseService) FindHashesBetween(ctx context.Context, start int64, end int64) ([]string, *rTypes.Error) {
+	if !b.IsOnline() {
+		return nil, errors.ErrInternalServerError
+	}
+
+	return b.transactionRepo.FindHashesBetween(ctx, start, end)
+}
+
+func (b *BaseService) FindKeysBySearch(ctx context.Context, search types.TransactionSearch) (
+	[]types.TransactionKey,
+	int64,
+	*rTypes.Error,
+) {
+	if !b.IsOnline() {
+		return nil, 0, errors.ErrInternalServerError
+	}
+
+	return b.transactionRepo.FindKeysBySearch(ctx, search)
+}
+
 func (b *BaseService) FindByIdentifier(ctx context.Context, index int64, hash string) (*types.Block, *rTypes.Error) {
 	if !b.IsOnline() {
 		return nil, errors.ErrInternalServerError
@@ -85,6 +145,24 @@ func (b *BaseService) FindByIdentifier(ctx context.Context, index int64, hash st
 	return b.blockRepo.FindByIdentifier(ctx, index, hash)
 }
 
+// FindByTimestamp - Retrieves the Block containing the consensus timestamp
+func (b *BaseService) FindByTimestamp(ctx context.Context, timestamp int64) (*types.Block, *rTypes.Error) {
+	if !b.IsOnline() {
+		return nil, errors.ErrInternalServerError
+	}
+
+	return b.blockRepo.FindByTimestamp(ctx, timestamp)
+}
+
+// FindRecordFileByIndex - Retrieves the record file of the block at the index
+func (b *BaseService) FindRecordFileByIndex(ctx context.Context, index int64) (*types.RecordFile, *rTypes.Error) {
+	if !b.IsOnline() {
+		return nil, errors.ErrInternalServerError
+	}
+
+	return b.blockRepo.FindRecordFileByIndex(ctx, index)
+}
+
 // RetrieveBlock - Retrieves Block by a given PartialBlockIdentifier
 func (b *BaseService) RetrieveBlock(ctx context.Context, bIdentifier *rTypes.PartialBlockIdentifier) (
 	*types.Block,
@@ -122,3 +200,25 @@ func (b *BaseService) RetrieveLatest(ctx context.Context) (*types.Block, *rTypes
 
 	return b.blockRepo.RetrieveLatest(ctx)
 }
+
+func (b *BaseService) RetrieveOldest(ctx context.Context) (*types.Block, *rTypes.Error) {
+	if !b.IsOnline() {
+		return nil, errors.ErrInternalServerError
+	}
+
+	return b.blockRepo.RetrieveOldest(ctx)
+}
+
+// handleTimeout returns ErrEndpointTimeout if the request's deadline is exceeded, otherwise err as is. An error caused
+// by the deadline is usually a database error of a cancelled query, replace it so clients know to retry
+func handleTimeout(ctx context.Context, err *rTypes.Error) *rTypes.Error {
+	if err != nil && isDeadlineExceeded(ctx) {
+		return errors.ErrEndpointTimeout
+	}
+
+	return err
+}
+
+func isDeadlineExceeded(ctx context.Context) bool {
+	return ctx != nil && ctx.Err() == context.DeadlineExceeded
+}
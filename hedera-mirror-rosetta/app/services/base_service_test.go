@@ -212,6 +212,32 @@ func (suite *onlineBaseServiceSuite) TestRetrieveSecondLatestThrows() {
 	suite.mockBlockRepo.AssertExpectations(suite.T())
 }
 
+func (suite *onlineBaseServiceSuite) TestRetrieveOldest() {
+	// given:
+	suite.mockBlockRepo.On("RetrieveOldest").Return(block(), mocks.NilError)
+
+	// when:
+	res, e := suite.baseService.RetrieveOldest(defaultContext)
+
+	// then:
+	assert.Nil(suite.T(), e)
+	assert.Equal(suite.T(), block(), res)
+	suite.mockBlockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *onlineBaseServiceSuite) TestRetrieveOldestThrows() {
+	// given:
+	suite.mockBlockRepo.On("RetrieveOldest").Return(mocks.NilBlock, &rTypes.Error{})
+
+	// when:
+	res, e := suite.baseService.RetrieveOldest(defaultContext)
+
+	// then:
+	assert.Nil(suite.T(), res)
+	assert.NotNil(suite.T(), e)
+	suite.mockBlockRepo.AssertExpectations(suite.T())
+}
+
 func (suite *onlineBaseServiceSuite) TestRetrieveGenesis() {
 	// given:
 	suite.mockBlockRepo.On("RetrieveGenesis").Return(block(), mocks.NilError)
@@ -264,6 +290,60 @@ func (suite *onlineBaseServiceSuite) TestFindByIdentifierThrows() {
 	suite.mockBlockRepo.AssertExpectations(suite.T())
 }
 
+func (suite *onlineBaseServiceSuite) TestFindByTimestamp() {
+	// given:
+	suite.mockBlockRepo.On("FindByTimestamp", int64(100)).Return(block(), mocks.NilError)
+
+	// when:
+	res, e := suite.baseService.FindByTimestamp(defaultContext, 100)
+
+	// then:
+	assert.Nil(suite.T(), e)
+	assert.Equal(suite.T(), block(), res)
+	suite.mockBlockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *onlineBaseServiceSuite) TestFindByTimestampThrows() {
+	// given:
+	suite.mockBlockRepo.On("FindByTimestamp", int64(100)).Return(mocks.NilBlock, &rTypes.Error{})
+
+	// when:
+	res, e := suite.baseService.FindByTimestamp(defaultContext, 100)
+
+	// then:
+	assert.Nil(suite.T(), res)
+	assert.NotNil(suite.T(), e)
+	suite.mockBlockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *onlineBaseServiceSuite) TestFindRecordFileByIndex() {
+	// given:
+	recordFile := &types.RecordFile{}
+	recordFile.Index = 100
+	suite.mockBlockRepo.On("FindRecordFileByIndex", int64(100)).Return(recordFile, mocks.NilError)
+
+	// when:
+	res, e := suite.baseService.FindRecordFileByIndex(defaultContext, 100)
+
+	// then:
+	assert.Nil(suite.T(), e)
+	assert.Equal(suite.T(), recordFile, res)
+	suite.mockBlockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *onlineBaseServiceSuite) TestFindRecordFileByIndexThrows() {
+	// given:
+	suite.mockBlockRepo.On("FindRecordFileByIndex", int64(100)).Return(mocks.NilRecordFile, &rTypes.Error{})
+
+	// when:
+	res, e := suite.baseService.FindRecordFileByIndex(defaultContext, 100)
+
+	// then:
+	assert.Nil(suite.T(), res)
+	assert.NotNil(suite.T(), e)
+	suite.mockBlockRepo.AssertExpectations(suite.T())
+}
+
 func (suite *onlineBaseServiceSuite) TestFindByHashInBlock() {
 	// given:
 	suite.mockTransactionRepo.On("FindByHashInBlock").Return(transaction(), mocks.NilError)
@@ -290,6 +370,75 @@ func (suite *onlineBaseServiceSuite) TestFindByHashInBlockThrows() {
 	suite.mockBlockRepo.AssertExpectations(suite.T())
 }
 
+func (suite *onlineBaseServiceSuite) TestCountBetween() {
+	// given:
+	suite.mockTransactionRepo.On("CountBetween").Return(int64(2), int64(10), mocks.NilError)
+
+	// when:
+	transactionCount, operationCount, e := suite.baseService.CountBetween(defaultContext, 1, 2)
+
+	// then:
+	assert.Nil(suite.T(), e)
+	assert.Equal(suite.T(), int64(2), transactionCount)
+	assert.Equal(suite.T(), int64(10), operationCount)
+	suite.mockTransactionRepo.AssertExpectations(suite.T())
+}
+
+func (suite *onlineBaseServiceSuite) TestCountBetweenThrows() {
+	// given:
+	suite.mockTransactionRepo.On("CountBetween").Return(int64(0), int64(0), &rTypes.Error{})
+
+	// when:
+	transactionCount, operationCount, e := suite.baseService.CountBetween(defaultContext, 1, 2)
+
+	// then:
+	assert.NotNil(suite.T(), e)
+	assert.Zero(suite.T(), transactionCount)
+	assert.Zero(suite.T(), operationCount)
+	suite.mockTransactionRepo.AssertExpectations(suite.T())
+}
+
+func (suite *onlineBaseServiceSuite) TestCountOperationsBetween() {
+	// given:
+	suite.mockTransactionRepo.On("CountOperationsBetween").Return(int64(10), mocks.NilError)
+
+	// when:
+	operationCount, e := suite.baseService.CountOperationsBetween(defaultContext, 1, 2)
+
+	// then:
+	assert.Nil(suite.T(), e)
+	assert.Equal(suite.T(), int64(10), operationCount)
+	suite.mockTransactionRepo.AssertExpectations(suite.T())
+}
+
+func (suite *onlineBaseServiceSuite) TestCountOperationsBetweenThrows() {
+	// given:
+	suite.mockTransactionRepo.On("CountOperationsBetween").Return(int64(0), &rTypes.Error{})
+
+	// when:
+	operationCount, e := suite.baseService.CountOperationsBetween(defaultContext, 1, 2)
+
+	// then:
+	assert.NotNil(suite.T(), e)
+	assert.Zero(suite.T(), operationCount)
+	suite.mockTransactionRepo.AssertExpectations(suite.T())
+}
+
+func (suite *onlineBaseServiceSuite) TestFindKeysBySearch() {
+	// given:
+	expected := []types.TransactionKey{{ConsensusTimestamp: 1, Hash: "0x123"}}
+	suite.mockTransactionRepo.On("FindKeysBySearch").Return(expected, int64(5), mocks.NilError)
+
+	// when:
+	keys, count, e := suite.baseService.FindKeysBySearch(defaultContext, types.TransactionSearch{End: 2, Limit: 1})
+
+	// then:
+	assert.Nil(suite.T(), e)
+	assert.Equal(suite.T(), expected, keys)
+	assert.Equal(suite.T(), int64(5), count)
+	suite.mockTransactionRepo.AssertExpectations(suite.T())
+}
+
 func (suite *onlineBaseServiceSuite) TestFindBetween() {
 	// given:
 	suite.mockTransactionRepo.On("FindBetween").Return(transactions(), mocks.NilError)
@@ -316,6 +465,33 @@ func (suite *onlineBaseServiceSuite) TestFindBetweenThrows() {
 	suite.mockBlockRepo.AssertExpectations(suite.T())
 }
 
+func (suite *onlineBaseServiceSuite) TestFindHashesBetween() {
+	// given:
+	expected := []string{"0x123", "0x456"}
+	suite.mockTransactionRepo.On("FindHashesBetween").Return(expected, mocks.NilError)
+
+	// when:
+	res, e := suite.baseService.FindHashesBetween(defaultContext, 1, 2)
+
+	// then:
+	assert.Nil(suite.T(), e)
+	assert.Equal(suite.T(), expected, res)
+	suite.mockTransactionRepo.AssertExpectations(suite.T())
+}
+
+func (suite *onlineBaseServiceSuite) TestFindHashesBetweenThrows() {
+	// given:
+	suite.mockTransactionRepo.On("FindHashesBetween").Return([]string(nil), &rTypes.Error{})
+
+	// when:
+	res, e := suite.baseService.FindHashesBetween(defaultContext, 1, 2)
+
+	// then:
+	assert.Nil(suite.T(), res)
+	assert.NotNil(suite.T(), e)
+	suite.mockTransactionRepo.AssertExpectations(suite.T())
+}
+
 type offlineBaseServiceSuite struct {
 	suite.Suite
 	baseService BaseService
@@ -337,6 +513,32 @@ func (suite *offlineBaseServiceSuite) TestFindByHashInBlock() {
 	assert.Equal(suite.T(), errors.ErrInternalServerError, err)
 }
 
+func (suite *offlineBaseServiceSuite) TestFindHashesBetween() {
+	res, err := suite.baseService.FindHashesBetween(defaultContext, 1, 1)
+	assert.Nil(suite.T(), res)
+	assert.Equal(suite.T(), errors.ErrInternalServerError, err)
+}
+
+func (suite *offlineBaseServiceSuite) TestCountBetween() {
+	transactionCount, operationCount, err := suite.baseService.CountBetween(defaultContext, 1, 1)
+	assert.Zero(suite.T(), transactionCount)
+	assert.Zero(suite.T(), operationCount)
+	assert.Equal(suite.T(), errors.ErrInternalServerError, err)
+}
+
+func (suite *offlineBaseServiceSuite) TestCountOperationsBetween() {
+	operationCount, err := suite.baseService.CountOperationsBetween(defaultContext, 1, 1)
+	assert.Zero(suite.T(), operationCount)
+	assert.Equal(suite.T(), errors.ErrInternalServerError, err)
+}
+
+func (suite *offlineBaseServiceSuite) TestFindKeysBySearch() {
+	keys, count, err := suite.baseService.FindKeysBySearch(defaultContext, types.TransactionSearch{})
+	assert.Nil(suite.T(), keys)
+	assert.Zero(suite.T(), count)
+	assert.Equal(suite.T(), errors.ErrInternalServerError, err)
+}
+
 func (suite *offlineBaseServiceSuite) TestFindBetween() {
 	res, err := suite.baseService.FindBetween(defaultContext, 1, 1)
 	assert.Nil(suite.T(), res)
@@ -349,6 +551,18 @@ func (suite *offlineBaseServiceSuite) TestFindByIdentifier() {
 	assert.Equal(suite.T(), errors.ErrInternalServerError, err)
 }
 
+func (suite *offlineBaseServiceSuite) TestFindByTimestamp() {
+	res, err := suite.baseService.FindByTimestamp(defaultContext, 100)
+	assert.Nil(suite.T(), res)
+	assert.Equal(suite.T(), errors.ErrInternalServerError, err)
+}
+
+func (suite *offlineBaseServiceSuite) TestFindRecordFileByIndex() {
+	res, err := suite.baseService.FindRecordFileByIndex(defaultContext, 100)
+	assert.Nil(suite.T(), res)
+	assert.Equal(suite.T(), errors.ErrInternalServerError, err)
+}
+
 func (suite *offlineBaseServiceSuite) TestRetrieveBlock() {
 	res, err := suite.baseService.RetrieveBlock(
 		defaultContext,
@@ -369,3 +583,9 @@ func (suite *offlineBaseServiceSuite) TestRetrieveLatest() {
 	assert.Nil(suite.T(), res)
 	assert.Equal(suite.T(), errors.ErrInternalServerError, err)
 }
+
+func (suite *offlineBaseServiceSuite) TestRetrieveOldest() {
+	res, err := suite.baseService.RetrieveOldest(defaultContext)
+	assert.Nil(suite.T(), res)
+	assert.Equal(suite.T(), errors.ErrInternalServerError, err)
+}
@@ -22,6 +22,10 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
 
 	cache "github.com/Code-Hex/go-generics-cache"
 	"github.com/Code-Hex/go-generics-cache/policy/lru"
@@ -29,41 +33,274 @@ import (
 	rTypes "github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/tools"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
 )
 
+const (
+	metadataKeyFilteredOperations   = "filtered_operations"
+	metadataKeyFilteredTransactions = "filtered_transactions"
+)
+
+// blockTransactionKey identifies a transaction in a block
+type blockTransactionKey struct {
+	blockHash       string
+	blockIndex      int64
+	transactionHash string
+}
+
+// blockResult is the shared result of a deduplicated block construction
+type blockResult struct {
+	response *rTypes.BlockResponse
+	err      *rTypes.Error
+}
+
+// detachedContext keeps the values of the parent context without its deadline and cancellation
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
 // blockAPIService implements the server.BlockAPIServicer interface.
 type blockAPIService struct {
 	accountRepo interfaces.AccountRepository
 	BaseService
-	entityCache *cache.Cache[int64, types.AccountId]
+	blockCache       interfaces.BlockCache
+	blockGroup       singleflight.Group
+	buildTimeout     time.Duration
+	entityCache      *cache.Cache[int64, types.AccountId]
+	maxOperations    int64
+	transactionCache *cache.Cache[blockTransactionKey, *rTypes.Transaction]
+
+	// trackedAccounts are the accounts whose operations are included in /block responses, all operations are included
+	// if empty. trackedAddresses is the set of the rosetta addresses of the tracked accounts, nil until resolved
+	trackedAccounts      []types.AccountId
+	trackedAddresses     map[string]bool
+	trackedAddressesLock sync.Mutex
 }
 
 // NewBlockAPIService creates a new instance of a blockAPIService.
 func NewBlockAPIService(
 	accountRepo interfaces.AccountRepository,
 	baseService BaseService,
+	blockCache interfaces.BlockCache,
+	blockConfig config.Block,
 	entityCacheConfig config.Cache,
+	transactionCacheConfig config.Cache,
 ) server.BlockAPIServicer {
 	entityCache := cache.New(cache.AsLRU[int64, types.AccountId](lru.WithCapacity(entityCacheConfig.MaxSize)))
-	return &blockAPIService{accountRepo: accountRepo, BaseService: baseService, entityCache: entityCache}
+	transactionCache := cache.New(
+		cache.AsLRU[blockTransactionKey, *rTypes.Transaction](lru.WithCapacity(transactionCacheConfig.MaxSize)),
+	)
+	trackedAccounts := make([]types.AccountId, 0, len(blockConfig.TrackedAccounts))
+	for _, account := range blockConfig.TrackedAccounts {
+		entityId, err := domain.EntityIdFromString(account)
+		if err != nil {
+			log.Warnf("Ignoring invalid tracked account %s: %s", account, err)
+			continue
+		}
+		trackedAccounts = append(trackedAccounts, types.NewAccountIdFromEntityId(entityId))
+	}
+
+	return &blockAPIService{
+		accountRepo:      accountRepo,
+		BaseService:      baseService,
+		blockCache:       blockCache,
+		buildTimeout:     blockConfig.BuildTimeout,
+		entityCache:      entityCache,
+		maxOperations:    blockConfig.MaxOperations,
+		trackedAccounts:  trackedAccounts,
+		transactionCache: transactionCache,
+	}
 }
 
-// Block implements the /block endpoint.
+// Block implements the /block endpoint. If tracked accounts are configured, only the operations of the tracked
+// accounts are returned.
 func (s *blockAPIService) Block(
 	ctx context.Context,
 	request *rTypes.BlockRequest,
 ) (*rTypes.BlockResponse, *rTypes.Error) {
+	response, err := s.getBlock(ctx, request)
+	if err != nil || len(s.trackedAccounts) == 0 {
+		return response, err
+	}
+
+	trackedAddresses, err := s.getTrackedAddresses(ctx)
+	if err != nil {
+		return nil, handleTimeout(ctx, err)
+	}
+
+	return filterBlockResponse(response, trackedAddresses), nil
+}
+
+// getBlock returns the full block response from the block cache, or constructs it
+func (s *blockAPIService) getBlock(ctx context.Context, request *rTypes.BlockRequest) (
+	*rTypes.BlockResponse,
+	*rTypes.Error,
+) {
+	if response, found := s.getCachedBlock(request.BlockIdentifier); found {
+		return response, nil
+	}
+
 	block, err := s.RetrieveBlock(ctx, request.BlockIdentifier)
 	if err != nil {
-		return nil, err
+		return nil, handleTimeout(ctx, err)
+	}
+
+	// concurrent requests for the same block share a single construction of the block response
+	key := fmt.Sprintf("%d-%s", block.Index, block.Hash)
+	resultChan := s.blockGroup.DoChan(key, func() (interface{}, error) {
+		// the construction outlives the request starting it when shared, so it only keeps the request's values
+		buildCtx, cancel := detachContext(ctx, s.buildTimeout)
+		defer cancel()
+
+		response, err := s.constructBlockResponse(buildCtx, block)
+		// a block read from the record stream is replaced by the backend's once it catches up, so it's never cached
+		if err == nil && s.blockCache != nil && !block.RecordStream {
+			s.blockCache.Set(block.Index, response)
+		}
+		return blockResult{response: response, err: err}, nil
+	})
+
+	var done <-chan struct{}
+	if ctx != nil {
+		done = ctx.Done()
+	}
+
+	select {
+	case result := <-resultChan:
+		shared := result.Val.(blockResult)
+		return shared.response, handleTimeout(ctx, shared.err)
+	case <-done:
+		// don't wait for a construction shared with other requests once the request's own deadline is exceeded
+		return nil, handleTimeout(ctx, errors.ErrInternalServerError)
 	}
+}
+
+// detachContext returns a context with the values of ctx, which isn't canceled with ctx and times out after timeout
+// instead. There is no timeout if timeout is not positive
+func detachContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	detached := detachedContext{ctx}
+	if timeout <= 0 {
+		return context.WithCancel(detached)
+	}
+
+	return context.WithTimeout(detached, timeout)
+}
+
+// getTrackedAddresses returns the set of the rosetta addresses of the tracked accounts. An account with an alias is
+// presented with its alias as the address, so the aliases are looked up once and the result is reused
+func (s *blockAPIService) getTrackedAddresses(ctx context.Context) (map[string]bool, *rTypes.Error) {
+	s.trackedAddressesLock.Lock()
+	defer s.trackedAddressesLock.Unlock()
+
+	if s.trackedAddresses != nil {
+		return s.trackedAddresses, nil
+	}
+
+	trackedAddresses := make(map[string]bool)
+	for _, accountId := range s.trackedAccounts {
+		accountAlias, err := s.accountRepo.GetAccountAlias(ctx, accountId)
+		if err != nil {
+			return nil, err
+		}
 
-	if block.Transactions, err = s.FindBetween(ctx, block.ConsensusStartNanos, block.ConsensusEndNanos); err != nil {
+		trackedAddresses[accountId.ToRosetta().Address] = true
+		trackedAddresses[accountAlias.ToRosetta().Address] = true
+	}
+
+	s.trackedAddresses = trackedAddresses
+	return trackedAddresses, nil
+}
+
+// getCachedBlock returns the block response from the persistent block cache if enabled. Only requests with the block
+// index can be served from the cache, and the block hash if present must match the cached block's hash
+func (s *blockAPIService) getCachedBlock(identifier *rTypes.PartialBlockIdentifier) (*rTypes.BlockResponse, bool) {
+	if s.blockCache == nil || identifier == nil || identifier.Index == nil {
+		return nil, false
+	}
+
+	response, found := s.blockCache.Get(*identifier.Index)
+	if !found {
+		return nil, false
+	}
+
+	if identifier.Hash != nil {
+		expected := strings.ToLower(tools.SafeRemoveHexPrefix(*identifier.Hash))
+		if expected != strings.ToLower(tools.SafeRemoveHexPrefix(response.Block.BlockIdentifier.Hash)) {
+			return nil, false
+		}
+	}
+
+	return response, true
+}
+
+// constructBlockResponse queries the transactions in the block and builds the block response. When the estimated
+// number of operations of the block, counted before loading its transactions, or the actual number once loaded exceeds
+// maxOperations, only the transaction identifiers are returned in other_transactions, and clients should fetch each
+// transaction with /block/transaction
+func (s *blockAPIService) constructBlockResponse(ctx context.Context, block *types.Block) (
+	*rTypes.BlockResponse,
+	*rTypes.Error,
+) {
+	if s.maxOperations > 0 {
+		operationCount, err := s.CountOperationsBetween(ctx, block.ConsensusStartNanos, block.ConsensusEndNanos)
+		if err != nil {
+			return nil, err
+		}
+
+		if operationCount > s.maxOperations {
+			hashes, err := s.FindHashesBetween(ctx, block.ConsensusStartNanos, block.ConsensusEndNanos)
+			if err != nil {
+				return nil, err
+			}
+
+			return newOtherTransactionsResponse(block, hashes), nil
+		}
+	}
+
+	transactions, err := s.FindBetween(ctx, block.ConsensusStartNanos, block.ConsensusEndNanos)
+	if err != nil {
 		return nil, err
 	}
 
+	if s.maxOperations > 0 {
+		// the estimate doesn't count the operations without a transfer
+		operationCount := int64(0)
+		for _, transaction := range transactions {
+			operationCount += int64(len(transaction.Operations))
+		}
+
+		if operationCount > s.maxOperations {
+			hashes := make([]string, 0, len(transactions))
+			for _, transaction := range transactions {
+				hashes = append(hashes, transaction.Hash)
+			}
+			return newOtherTransactionsResponse(block, hashes), nil
+		}
+	}
+
+	block.Transactions = transactions
+
 	if err = s.updateOperationAccountAlias(ctx, block.Transactions...); err != nil {
 		return nil, err
 	}
@@ -71,12 +308,31 @@ func (s *blockAPIService) Block(
 	return &rTypes.BlockResponse{Block: block.ToRosetta()}, nil
 }
 
+// newOtherTransactionsResponse returns the block response of an oversized block with only the transaction identifiers
+func newOtherTransactionsResponse(block *types.Block, hashes []string) *rTypes.BlockResponse {
+	otherTransactions := make([]*rTypes.TransactionIdentifier, 0, len(hashes))
+	for _, hash := range hashes {
+		otherTransactions = append(otherTransactions, &rTypes.TransactionIdentifier{Hash: hash})
+	}
+	return &rTypes.BlockResponse{Block: block.ToRosetta(), OtherTransactions: otherTransactions}
+}
+
 // BlockTransaction implements the /block/transaction endpoint.
 func (s *blockAPIService) BlockTransaction(
 	ctx context.Context,
 	request *rTypes.BlockTransactionRequest,
 ) (*rTypes.BlockTransactionResponse, *rTypes.Error) {
 	h := tools.SafeRemoveHexPrefix(request.BlockIdentifier.Hash)
+	key := blockTransactionKey{
+		blockHash:       strings.ToLower(h),
+		blockIndex:      request.BlockIdentifier.Index,
+		transactionHash: strings.ToLower(tools.SafeRemoveHexPrefix(request.TransactionIdentifier.Hash)),
+	}
+	// a transaction in a block is immutable, so serve rosetta-cli's per transaction fetches from the cache
+	if cached, found := s.transactionCache.Get(key); found {
+		return &rTypes.BlockTransactionResponse{Transaction: cached}, nil
+	}
+
 	block, err := s.FindByIdentifier(ctx, request.BlockIdentifier.Index, h)
 	if err != nil {
 		return nil, err
@@ -96,7 +352,10 @@ func (s *blockAPIService) BlockTransaction(
 		return nil, err
 	}
 
-	return &rTypes.BlockTransactionResponse{Transaction: transaction.ToRosetta()}, nil
+	rosettaTransaction := transaction.ToRosetta()
+	s.transactionCache.Set(key, rosettaTransaction)
+
+	return &rTypes.BlockTransactionResponse{Transaction: rosettaTransaction}, nil
 }
 
 func (s *blockAPIService) updateOperationAccountAlias(
@@ -126,3 +385,55 @@ func (s *blockAPIService) updateOperationAccountAlias(
 
 	return nil
 }
+
+// filterBlockResponse returns a copy of the block response with only the operations of the tracked accounts. The kept
+// operations are re-indexed, and the transactions left without operations are removed. The number of the removed
+// operations and transactions are summarized in the block metadata, and the number of the removed operations of a
+// kept transaction in its metadata. The response itself is not modified since it may be cached or shared
+func filterBlockResponse(response *rTypes.BlockResponse, trackedAddresses map[string]bool) *rTypes.BlockResponse {
+	block := *response.Block
+	block.Transactions = make([]*rTypes.Transaction, 0, len(response.Block.Transactions))
+	filteredOperations := 0
+	filteredTransactions := 0
+	for _, transaction := range response.Block.Transactions {
+		operations := make([]*rTypes.Operation, 0)
+		for _, operation := range transaction.Operations {
+			if operation.Account == nil || !trackedAddresses[operation.Account.Address] {
+				continue
+			}
+
+			copied := *operation
+			copied.OperationIdentifier = &rTypes.OperationIdentifier{Index: int64(len(operations))}
+			copied.RelatedOperations = nil
+			operations = append(operations, &copied)
+		}
+
+		filteredOperations += len(transaction.Operations) - len(operations)
+		if len(operations) == 0 {
+			filteredTransactions++
+			continue
+		}
+
+		copied := *transaction
+		copied.Operations = operations
+		if len(operations) != len(transaction.Operations) {
+			copied.Metadata = copyMetadata(transaction.Metadata)
+			copied.Metadata[metadataKeyFilteredOperations] = len(transaction.Operations) - len(operations)
+		}
+		block.Transactions = append(block.Transactions, &copied)
+	}
+
+	block.Metadata = copyMetadata(response.Block.Metadata)
+	block.Metadata[metadataKeyFilteredOperations] = filteredOperations
+	block.Metadata[metadataKeyFilteredTransactions] = filteredTransactions
+
+	return &rTypes.BlockResponse{Block: &block, OtherTransactions: response.OtherTransactions}
+}
+
+func copyMetadata(metadata map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(metadata)+2)
+	for key, value := range metadata {
+		copied[key] = value
+	}
+	return copied
+}
@@ -21,7 +21,10 @@
 package services
 
 import (
+	"context"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/coinbase/rosetta-sdk-go/server"
 	rTypes "github.com/coinbase/rosetta-sdk-go/types"
@@ -29,6 +32,7 @@ import (
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/mocks"
 	"github.com/stretchr/testify/assert"
@@ -88,12 +92,23 @@ func expectedBlockResponse(transactions ...*rTypes.Transaction) *rTypes.BlockRes
 			},
 			Timestamp:    1,
 			Transactions: transactions,
-			Metadata:     nil,
+			Metadata: map[string]interface{}{
+				"consensus_end_nanos":   int64(20000000),
+				"consensus_start_nanos": int64(1000000),
+			},
 		},
 		OtherTransactions: nil,
 	}
 }
 
+func withBlockCounts(response *rTypes.BlockResponse, hbarMoved int64, operationCount int) *rTypes.BlockResponse {
+	response.Block.Metadata["hbar_moved"] = hbarMoved
+	response.Block.Metadata["operation_count"] = operationCount
+	response.Block.Metadata["tokens_touched"] = 0
+	response.Block.Metadata["transaction_count"] = len(response.Block.Transactions)
+	return response
+}
+
 func expectedTransaction(accountId types.AccountId, entityId *domain.EntityId, hash string) *rTypes.Transaction {
 	response := &rTypes.Transaction{
 		TransactionIdentifier: &rTypes.TransactionIdentifier{Hash: hash},
@@ -165,7 +180,14 @@ func (suite *blockServiceSuite) SetupTest() {
 	suite.mockTransactionRepo = &mocks.MockTransactionRepository{}
 
 	baseService := NewOnlineBaseService(suite.mockBlockRepo, suite.mockTransactionRepo)
-	suite.blockService = NewBlockAPIService(suite.mockAccountRepo, baseService, config.Cache{MaxSize: 1024})
+	suite.blockService = NewBlockAPIService(
+		suite.mockAccountRepo,
+		baseService,
+		nil,
+		config.Block{},
+		config.Cache{MaxSize: 1024},
+		config.Cache{MaxSize: 1024},
+	)
 }
 
 func (suite *blockServiceSuite) TestNewBlockAPIService() {
@@ -178,10 +200,10 @@ func (suite *blockServiceSuite) TestBlock() {
 		makeTransaction(nil, "123"),
 		makeTransaction(&entityId, "246"),
 	}
-	expected := expectedBlockResponse(
+	expected := withBlockCounts(expectedBlockResponse(
 		expectedTransaction(account, nil, "123"),
 		expectedTransaction(account, &entityId, "246"),
-	)
+	), 600, 2)
 	suite.mockAccountRepo.On("GetAccountAlias").Return(account, mocks.NilError)
 	suite.mockBlockRepo.On("FindByIdentifier").Return(block(), mocks.NilError)
 	suite.mockTransactionRepo.On("FindBetween").Return(exampleTransactions, mocks.NilError)
@@ -201,10 +223,10 @@ func (suite *blockServiceSuite) TestBlockWithAccountAlias() {
 		makeTransaction(nil, "123"),
 		makeTransaction(&entityId, "246"),
 	}
-	expected := expectedBlockResponse(
+	expected := withBlockCounts(expectedBlockResponse(
 		expectedTransaction(accountAlias, nil, "123"),
 		expectedTransaction(accountAlias, &entityId, "246"),
-	)
+	), 600, 2)
 	suite.mockAccountRepo.On("GetAccountAlias").Return(accountAlias, mocks.NilError)
 	suite.mockBlockRepo.On("FindByIdentifier").Return(block(), mocks.NilError)
 	suite.mockTransactionRepo.On("FindBetween").Return(exampleTransactions, mocks.NilError)
@@ -218,6 +240,74 @@ func (suite *blockServiceSuite) TestBlockWithAccountAlias() {
 	suite.mockAccountRepo.AssertNumberOfCalls(suite.T(), "GetAccountAlias", 1)
 }
 
+func (suite *blockServiceSuite) TestBlockConcurrentRequestsShareResult() {
+	// given:
+	exampleTransactions := []*types.Transaction{makeTransaction(nil, "123")}
+	expected := withBlockCounts(expectedBlockResponse(expectedTransaction(account, nil, "123")), 300, 1)
+	suite.mockAccountRepo.On("GetAccountAlias").Return(account, mocks.NilError)
+	suite.mockBlockRepo.On("FindByIdentifier").Return(block(), mocks.NilError)
+	suite.mockTransactionRepo.On("FindBetween").
+		After(100*time.Millisecond).
+		Return(exampleTransactions, mocks.NilError)
+
+	// when:
+	count := 5
+	responses := make([]*rTypes.BlockResponse, count)
+	errs := make([]*rTypes.Error, count)
+	wg := sync.WaitGroup{}
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			responses[i], errs[i] = suite.blockService.Block(nil, blockRequest())
+		}(i)
+	}
+	wg.Wait()
+
+	// then:
+	for i := 0; i < count; i++ {
+		assert.Nil(suite.T(), errs[i])
+		assert.Equal(suite.T(), expected, responses[i])
+	}
+	suite.mockBlockRepo.AssertNumberOfCalls(suite.T(), "FindByIdentifier", count)
+	suite.mockTransactionRepo.AssertNumberOfCalls(suite.T(), "FindBetween", 1)
+}
+
+func (suite *blockServiceSuite) TestBlockDeadlineExceeded() {
+	// given:
+	ctx, cancel := context.WithTimeout(defaultContext, 0)
+	defer cancel()
+	suite.mockBlockRepo.On("FindByIdentifier").Return(mocks.NilBlock, errors.ErrDatabaseError)
+
+	// when:
+	actual, e := suite.blockService.Block(ctx, blockRequest())
+
+	// then:
+	assert.Equal(suite.T(), errors.ErrEndpointTimeout, e)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *blockServiceSuite) TestBlockDeadlineExceededWhileConstructing() {
+	// given:
+	ctx, cancel := context.WithTimeout(defaultContext, 50*time.Millisecond)
+	defer cancel()
+	blocked := make(chan time.Time)
+	defer close(blocked)
+	suite.mockBlockRepo.On("FindByIdentifier").Return(block(), mocks.NilError)
+	suite.mockTransactionRepo.On("FindBetween").
+		WaitUntil(blocked).
+		Return([]*types.Transaction{}, mocks.NilError)
+
+	// when:
+	start := time.Now()
+	actual, e := suite.blockService.Block(ctx, blockRequest())
+
+	// then:
+	assert.Equal(suite.T(), errors.ErrEndpointTimeout, e)
+	assert.Nil(suite.T(), actual)
+	assert.Less(suite.T(), time.Since(start), time.Second)
+}
+
 func (suite *blockServiceSuite) TestBlockThrowsWhenAccountRepoFail() {
 	// given:
 	exampleTransactions := []*types.Transaction{
@@ -265,6 +355,98 @@ func (suite *blockServiceSuite) TestBlockThrowsWhenFindBetweenFails() {
 	assert.NotNil(suite.T(), err)
 }
 
+func (suite *blockServiceSuite) TestBlockWithinMaxOperations() {
+	// given:
+	blockService := suite.newBlockServiceWithMaxOperations(1)
+	exampleTransactions := []*types.Transaction{makeTransaction(nil, "123")}
+	expected := withBlockCounts(expectedBlockResponse(expectedTransaction(account, nil, "123")), 300, 1)
+	suite.mockAccountRepo.On("GetAccountAlias").Return(account, mocks.NilError)
+	suite.mockBlockRepo.On("FindByIdentifier").Return(block(), mocks.NilError)
+	suite.mockTransactionRepo.On("CountOperationsBetween").Return(int64(1), mocks.NilError)
+	suite.mockTransactionRepo.On("FindBetween").Return(exampleTransactions, mocks.NilError)
+
+	// when:
+	actual, e := blockService.Block(nil, blockRequest())
+
+	// then:
+	assert.Nil(suite.T(), e)
+	assert.Equal(suite.T(), expected, actual)
+	suite.mockTransactionRepo.AssertNotCalled(suite.T(), "CountBetween")
+	suite.mockTransactionRepo.AssertNotCalled(suite.T(), "FindHashesBetween")
+}
+
+func (suite *blockServiceSuite) TestBlockExceedsMaxOperations() {
+	// given:
+	blockService := suite.newBlockServiceWithMaxOperations(1)
+	expected := expectedBlockResponse()
+	expected.Block.Transactions = []*rTypes.Transaction{}
+	expected.OtherTransactions = []*rTypes.TransactionIdentifier{{Hash: "0x123"}, {Hash: "0x246"}}
+	suite.mockBlockRepo.On("FindByIdentifier").Return(block(), mocks.NilError)
+	suite.mockTransactionRepo.On("CountOperationsBetween").Return(int64(2), mocks.NilError)
+	suite.mockTransactionRepo.On("FindHashesBetween").Return([]string{"0x123", "0x246"}, mocks.NilError)
+
+	// when:
+	actual, e := blockService.Block(nil, blockRequest())
+
+	// then:
+	assert.Nil(suite.T(), e)
+	assert.Equal(suite.T(), expected, actual)
+	suite.mockAccountRepo.AssertNotCalled(suite.T(), "GetAccountAlias")
+	suite.mockTransactionRepo.AssertNotCalled(suite.T(), "FindBetween")
+}
+
+func (suite *blockServiceSuite) TestBlockExceedsMaxOperationsAfterLoading() {
+	// given:
+	blockService := suite.newBlockServiceWithMaxOperations(1)
+	exampleTransactions := []*types.Transaction{makeTransaction(nil, "0x123"), makeTransaction(nil, "0x246")}
+	expected := expectedBlockResponse()
+	expected.Block.Transactions = []*rTypes.Transaction{}
+	expected.OtherTransactions = []*rTypes.TransactionIdentifier{{Hash: "0x123"}, {Hash: "0x246"}}
+	suite.mockBlockRepo.On("FindByIdentifier").Return(block(), mocks.NilError)
+	suite.mockTransactionRepo.On("CountOperationsBetween").Return(int64(1), mocks.NilError)
+	suite.mockTransactionRepo.On("FindBetween").Return(exampleTransactions, mocks.NilError)
+
+	// when:
+	actual, e := blockService.Block(nil, blockRequest())
+
+	// then:
+	assert.Nil(suite.T(), e)
+	assert.Equal(suite.T(), expected, actual)
+	suite.mockAccountRepo.AssertNotCalled(suite.T(), "GetAccountAlias")
+	suite.mockTransactionRepo.AssertNotCalled(suite.T(), "FindHashesBetween")
+}
+
+func (suite *blockServiceSuite) TestBlockThrowsWhenCountOperationsBetweenFails() {
+	// given:
+	blockService := suite.newBlockServiceWithMaxOperations(1)
+	suite.mockBlockRepo.On("FindByIdentifier").Return(block(), mocks.NilError)
+	suite.mockTransactionRepo.On("CountOperationsBetween").Return(int64(0), errors.ErrDatabaseError)
+
+	// when:
+	actual, e := blockService.Block(nil, blockRequest())
+
+	// then:
+	assert.Equal(suite.T(), errors.ErrDatabaseError, e)
+	assert.Nil(suite.T(), actual)
+	suite.mockTransactionRepo.AssertNotCalled(suite.T(), "FindBetween")
+}
+
+func (suite *blockServiceSuite) TestBlockThrowsWhenFindHashesBetweenFails() {
+	// given:
+	blockService := suite.newBlockServiceWithMaxOperations(1)
+	suite.mockBlockRepo.On("FindByIdentifier").Return(block(), mocks.NilError)
+	suite.mockTransactionRepo.On("CountOperationsBetween").Return(int64(2), mocks.NilError)
+	suite.mockTransactionRepo.On("FindHashesBetween").Return([]string(nil), errors.ErrDatabaseError)
+
+	// when:
+	actual, e := blockService.Block(nil, blockRequest())
+
+	// then:
+	assert.Equal(suite.T(), errors.ErrDatabaseError, e)
+	assert.Nil(suite.T(), actual)
+	suite.mockTransactionRepo.AssertNotCalled(suite.T(), "FindBetween")
+}
+
 func (suite *blockServiceSuite) TestBlockTransaction() {
 	// given:
 	exampleTransaction := makeTransaction(nil, "somehash")
@@ -283,6 +465,43 @@ func (suite *blockServiceSuite) TestBlockTransaction() {
 	suite.mockAccountRepo.AssertNumberOfCalls(suite.T(), "GetAccountAlias", 1)
 }
 
+func (suite *blockServiceSuite) TestBlockTransactionCached() {
+	// given:
+	exampleTransaction := makeTransaction(nil, "somehash")
+	expected := &rTypes.BlockTransactionResponse{Transaction: expectedTransaction(account, nil, "somehash")}
+
+	suite.mockAccountRepo.On("GetAccountAlias").Return(account, mocks.NilError)
+	suite.mockBlockRepo.On("FindByIdentifier").Return(block(), mocks.NilError)
+	suite.mockTransactionRepo.On("FindByHashInBlock").Return(exampleTransaction, mocks.NilError)
+
+	// when:
+	actual1, err1 := suite.blockService.BlockTransaction(nil, transactionRequest())
+	actual2, err2 := suite.blockService.BlockTransaction(nil, transactionRequest())
+
+	// then:
+	assert.Equal(suite.T(), expected, actual1)
+	assert.Nil(suite.T(), err1)
+	assert.Equal(suite.T(), expected, actual2)
+	assert.Nil(suite.T(), err2)
+	suite.mockBlockRepo.AssertNumberOfCalls(suite.T(), "FindByIdentifier", 1)
+	suite.mockTransactionRepo.AssertNumberOfCalls(suite.T(), "FindByHashInBlock", 1)
+}
+
+func (suite *blockServiceSuite) TestBlockTransactionNotCachedOnError() {
+	// given:
+	suite.mockBlockRepo.On("FindByIdentifier").Return(block(), mocks.NilError)
+	suite.mockTransactionRepo.On("FindByHashInBlock").Return(mocks.NilTransaction, errors.ErrTransactionNotFound)
+
+	// when:
+	_, err1 := suite.blockService.BlockTransaction(nil, transactionRequest())
+	_, err2 := suite.blockService.BlockTransaction(nil, transactionRequest())
+
+	// then:
+	assert.Equal(suite.T(), errors.ErrTransactionNotFound, err1)
+	assert.Equal(suite.T(), errors.ErrTransactionNotFound, err2)
+	suite.mockTransactionRepo.AssertNumberOfCalls(suite.T(), "FindByHashInBlock", 2)
+}
+
 func (suite *blockServiceSuite) TestBlockTransactionWithAccountAlias() {
 	// given:
 	exampleTransaction := makeTransaction(nil, "somehash")
@@ -342,3 +561,293 @@ func (suite *blockServiceSuite) TestBlockTransactionThrowsWhenFindByHashInBlockF
 	assert.Nil(suite.T(), actual)
 	assert.NotNil(suite.T(), err)
 }
+
+func (suite *blockServiceSuite) TestBlockFromBlockCache() {
+	// given:
+	blockCache := &mocks.MockBlockCache{}
+	cached := expectedBlockResponse(expectedTransaction(account, nil, "123"))
+	cached.Block.BlockIdentifier.Index = 100
+	cached.Block.BlockIdentifier.Hash = "0xsomehashh"
+	blockCache.On("Get", int64(100)).Return(cached, true)
+	blockService := suite.newBlockServiceWithBlockCache(blockCache)
+
+	// when:
+	actual, err := blockService.Block(nil, blockRequest())
+
+	// then:
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), cached, actual)
+	blockCache.AssertNotCalled(suite.T(), "Set")
+	suite.mockBlockRepo.AssertNotCalled(suite.T(), "FindByIdentifier")
+	suite.mockTransactionRepo.AssertNotCalled(suite.T(), "FindBetween")
+}
+
+func (suite *blockServiceSuite) TestBlockBlockCacheMiss() {
+	// given:
+	blockCache := &mocks.MockBlockCache{}
+	expected := withBlockCounts(expectedBlockResponse(expectedTransaction(account, nil, "123")), 300, 1)
+	blockCache.On("Get", int64(100)).Return(mocks.NilBlockResponse, false)
+	blockCache.On("Set", int64(1), expected).Return()
+	suite.mockAccountRepo.On("GetAccountAlias").Return(account, mocks.NilError)
+	suite.mockBlockRepo.On("FindByIdentifier").Return(block(), mocks.NilError)
+	suite.mockTransactionRepo.On("FindBetween").
+		Return([]*types.Transaction{makeTransaction(nil, "123")}, mocks.NilError)
+	blockService := suite.newBlockServiceWithBlockCache(blockCache)
+
+	// when:
+	actual, err := blockService.Block(nil, blockRequest())
+
+	// then:
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+	blockCache.AssertExpectations(suite.T())
+}
+
+func (suite *blockServiceSuite) TestBlockBlockCacheHashMismatch() {
+	// given:
+	blockCache := &mocks.MockBlockCache{}
+	cached := expectedBlockResponse()
+	cached.Block.BlockIdentifier.Index = 100
+	blockCache.On("Get", int64(100)).Return(cached, true)
+	suite.mockBlockRepo.On("FindByIdentifier").Return(mocks.NilBlock, errors.ErrBlockNotFound)
+	blockService := suite.newBlockServiceWithBlockCache(blockCache)
+
+	// when:
+	actual, err := blockService.Block(nil, blockRequest())
+
+	// then:
+	assert.Equal(suite.T(), errors.ErrBlockNotFound, err)
+	assert.Nil(suite.T(), actual)
+	blockCache.AssertNotCalled(suite.T(), "Set")
+}
+
+func (suite *blockServiceSuite) TestBlockNotCachedOnError() {
+	// given:
+	blockCache := &mocks.MockBlockCache{}
+	blockCache.On("Get", int64(100)).Return(mocks.NilBlockResponse, false)
+	suite.mockBlockRepo.On("FindByIdentifier").Return(block(), mocks.NilError)
+	suite.mockTransactionRepo.On("FindBetween").Return([]*types.Transaction{}, errors.ErrDatabaseError)
+	blockService := suite.newBlockServiceWithBlockCache(blockCache)
+
+	// when:
+	actual, err := blockService.Block(nil, blockRequest())
+
+	// then:
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+	blockCache.AssertNotCalled(suite.T(), "Set")
+}
+
+func (suite *blockServiceSuite) TestBlockNotCachedFromRecordStream() {
+	// given:
+	blockCache := &mocks.MockBlockCache{}
+	blockCache.On("Get", int64(100)).Return(mocks.NilBlockResponse, false)
+	recordStreamBlock := block()
+	recordStreamBlock.RecordStream = true
+	expected := withBlockCounts(expectedBlockResponse(expectedTransaction(account, nil, "123")), 300, 1)
+	suite.mockAccountRepo.On("GetAccountAlias").Return(account, mocks.NilError)
+	suite.mockBlockRepo.On("FindByIdentifier").Return(recordStreamBlock, mocks.NilError)
+	suite.mockTransactionRepo.On("FindBetween").
+		Return([]*types.Transaction{makeTransaction(nil, "123")}, mocks.NilError)
+	blockService := suite.newBlockServiceWithBlockCache(blockCache)
+
+	// when:
+	actual, err := blockService.Block(nil, blockRequest())
+
+	// then:
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+	blockCache.AssertNotCalled(suite.T(), "Set")
+}
+
+func (suite *blockServiceSuite) TestBlockTrackedAccounts() {
+	// given:
+	blockCache := &mocks.MockBlockCache{}
+	blockCache.On("Get", int64(100)).Return(trackedAccountsBlockResponse(), true)
+	suite.mockAccountRepo.On("GetAccountAlias").Return(accountAlias, mocks.NilError)
+	blockService := suite.newBlockServiceWithTrackedAccounts(blockCache, "0.0.500", "invalid")
+	expected := expectedBlockResponse(
+		&rTypes.Transaction{
+			TransactionIdentifier: &rTypes.TransactionIdentifier{Hash: "0x1"},
+			Operations:            []*rTypes.Operation{trackedAccountsOperation(0, accountAlias, 5)},
+			Metadata:              map[string]interface{}{"entity_id": "0.0.1000", "filtered_operations": 1},
+		},
+		&rTypes.Transaction{
+			TransactionIdentifier: &rTypes.TransactionIdentifier{Hash: "0x3"},
+			Operations: []*rTypes.Operation{
+				trackedAccountsOperation(0, account, -1),
+				trackedAccountsOperation(1, account, 1),
+			},
+		},
+	)
+	expected.Block.BlockIdentifier.Index = 100
+	expected.Block.BlockIdentifier.Hash = "0xsomehashh"
+	expected.Block.Metadata["filtered_operations"] = 2
+	expected.Block.Metadata["filtered_transactions"] = 1
+
+	// when:
+	actual, err := blockService.Block(nil, blockRequest())
+	actualAgain, errAgain := blockService.Block(nil, blockRequest())
+
+	// then:
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+	assert.Nil(suite.T(), errAgain)
+	assert.Equal(suite.T(), expected, actualAgain)
+	suite.mockAccountRepo.AssertNumberOfCalls(suite.T(), "GetAccountAlias", 1)
+}
+
+func (suite *blockServiceSuite) TestBlockTrackedAccountsStructuredAccountIdentifier() {
+	// given:
+	assert.NoError(suite.T(), types.SetAccountIdentifierFormat(types.AccountIdentifierFormatStructured))
+	suite.T().Cleanup(func() { _ = types.SetAccountIdentifierFormat(types.AccountIdentifierFormatDotted) })
+	blockCache := &mocks.MockBlockCache{}
+	blockCache.On("Get", int64(100)).Return(trackedAccountsBlockResponse(), true)
+	suite.mockAccountRepo.On("GetAccountAlias").Return(accountAlias, mocks.NilError)
+	blockService := suite.newBlockServiceWithTrackedAccounts(blockCache, "0.0.500")
+
+	// when:
+	actual, err := blockService.Block(nil, blockRequest())
+
+	// then:
+	assert.Nil(suite.T(), err)
+	assert.Len(suite.T(), actual.Block.Transactions, 2)
+	assert.Equal(suite.T(), "500", actual.Block.Transactions[1].Operations[0].Account.Address)
+	assert.Equal(suite.T(), 2, actual.Block.Metadata["filtered_operations"])
+}
+
+func (suite *blockServiceSuite) TestBlockTrackedAccountsGetAccountAliasFails() {
+	// given:
+	blockCache := &mocks.MockBlockCache{}
+	blockCache.On("Get", int64(100)).Return(trackedAccountsBlockResponse(), true)
+	suite.mockAccountRepo.On("GetAccountAlias").Return(types.AccountId{}, errors.ErrDatabaseError)
+	blockService := suite.newBlockServiceWithTrackedAccounts(blockCache, "0.0.500")
+
+	// when:
+	actual, err := blockService.Block(nil, blockRequest())
+
+	// then:
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func TestFilterBlockResponseDoesNotModifyResponse(t *testing.T) {
+	// given:
+	response := trackedAccountsBlockResponse()
+
+	// when:
+	actual := filterBlockResponse(response, map[string]bool{})
+
+	// then:
+	assert.Equal(t, trackedAccountsBlockResponse(), response)
+	assert.Empty(t, actual.Block.Transactions)
+	assert.Equal(t, 5, actual.Block.Metadata["filtered_operations"])
+	assert.Equal(t, 3, actual.Block.Metadata["filtered_transactions"])
+}
+
+func trackedAccountsBlockResponse() *rTypes.BlockResponse {
+	otherAccount := types.NewAccountIdFromEntityId(entityId)
+	response := expectedBlockResponse(
+		&rTypes.Transaction{
+			TransactionIdentifier: &rTypes.TransactionIdentifier{Hash: "0x1"},
+			Operations: []*rTypes.Operation{
+				trackedAccountsOperation(0, otherAccount, -5),
+				trackedAccountsOperation(1, accountAlias, 5),
+			},
+			Metadata: map[string]interface{}{"entity_id": "0.0.1000"},
+		},
+		&rTypes.Transaction{
+			TransactionIdentifier: &rTypes.TransactionIdentifier{Hash: "0x2"},
+			Operations:            []*rTypes.Operation{trackedAccountsOperation(0, otherAccount, 0)},
+		},
+		&rTypes.Transaction{
+			TransactionIdentifier: &rTypes.TransactionIdentifier{Hash: "0x3"},
+			Operations: []*rTypes.Operation{
+				trackedAccountsOperation(0, account, -1),
+				trackedAccountsOperation(1, account, 1),
+			},
+		},
+	)
+	response.Block.BlockIdentifier.Index = 100
+	response.Block.BlockIdentifier.Hash = "0xsomehashh"
+	return response
+}
+
+func trackedAccountsOperation(index int64, accountId types.AccountId, amount int64) *rTypes.Operation {
+	return &rTypes.Operation{
+		OperationIdentifier: &rTypes.OperationIdentifier{Index: index},
+		Type:                types.TransactionTypes[14],
+		Status:              &statusSuccess,
+		Account:             accountId.ToRosetta(),
+		Amount:              (&types.HbarAmount{Value: amount}).ToRosetta(),
+	}
+}
+
+func (suite *blockServiceSuite) newBlockServiceWithBlockCache(blockCache interfaces.BlockCache) server.BlockAPIServicer {
+	baseService := NewOnlineBaseService(suite.mockBlockRepo, suite.mockTransactionRepo)
+	return NewBlockAPIService(
+		suite.mockAccountRepo,
+		baseService,
+		blockCache,
+		config.Block{},
+		config.Cache{MaxSize: 1024},
+		config.Cache{MaxSize: 1024},
+	)
+}
+
+func (suite *blockServiceSuite) newBlockServiceWithMaxOperations(maxOperations int64) server.BlockAPIServicer {
+	baseService := NewOnlineBaseService(suite.mockBlockRepo, suite.mockTransactionRepo)
+	return NewBlockAPIService(
+		suite.mockAccountRepo,
+		baseService,
+		nil,
+		config.Block{MaxOperations: maxOperations},
+		config.Cache{MaxSize: 1024},
+		config.Cache{MaxSize: 1024},
+	)
+}
+
+func (suite *blockServiceSuite) newBlockServiceWithTrackedAccounts(
+	blockCache interfaces.BlockCache,
+	trackedAccounts ...string,
+) server.BlockAPIServicer {
+	baseService := NewOnlineBaseService(suite.mockBlockRepo, suite.mockTransactionRepo)
+	return NewBlockAPIService(
+		suite.mockAccountRepo,
+		baseService,
+		blockCache,
+		config.Block{TrackedAccounts: trackedAccounts},
+		config.Cache{MaxSize: 1024},
+		config.Cache{MaxSize: 1024},
+	)
+}
+
+func TestDetachContext(t *testing.T) {
+	// given:
+	type key struct{}
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "value"))
+	cancel()
+
+	// when:
+	detached, detachedCancel := detachContext(ctx, time.Minute)
+	defer detachedCancel()
+
+	// then:
+	assert.Nil(t, detached.Err())
+	assert.Equal(t, "value", detached.Value(key{}))
+	deadline, ok := detached.Deadline()
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+}
+
+func TestDetachContextWithoutTimeout(t *testing.T) {
+	// when:
+	detached, cancel := detachContext(nil, 0)
+
+	// then:
+	_, ok := detached.Deadline()
+	assert.False(t, ok)
+	assert.Nil(t, detached.Err())
+	cancel()
+	assert.Equal(t, context.Canceled, detached.Err())
+}
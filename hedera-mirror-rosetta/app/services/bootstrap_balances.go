@@ -0,0 +1,100 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+)
+
+// BootstrapBalance is an entry of the rosetta-cli bootstrap balances file
+type BootstrapBalance struct {
+	AccountIdentifier *rTypes.AccountIdentifier `json:"account_identifier"`
+	Currency          *rTypes.Currency          `json:"currency"`
+	Value             string                    `json:"value"`
+}
+
+// ExportBootstrapBalances writes the non-zero balances of all accounts at the end of the block with the index, or the
+// latest block if index is nil, to the writer in the rosetta-cli bootstrap balances format. The balances are computed
+// from the balance snapshot nearest to the block plus the transfers in between, so rosetta-cli check:data can start
+// from the next block instead of syncing from genesis. It returns the block and the number of balances written
+func ExportBootstrapBalances(
+	ctx context.Context,
+	accountRepo interfaces.AccountRepository,
+	blockRepo interfaces.BlockRepository,
+	index *int64,
+	writer io.Writer,
+) (*types.Block, int, *rTypes.Error) {
+	var block *types.Block
+	var rErr *rTypes.Error
+	if index != nil {
+		block, rErr = blockRepo.FindByIndex(ctx, *index)
+	} else {
+		block, rErr = blockRepo.RetrieveLatest(ctx)
+	}
+	if rErr != nil {
+		return nil, 0, rErr
+	}
+
+	balances, rErr := accountRepo.RetrieveAllBalancesAtBlock(ctx, block.ConsensusEndNanos)
+	if rErr != nil {
+		return nil, 0, rErr
+	}
+
+	accountIds := make([]int64, 0, len(balances))
+	for accountId := range balances {
+		accountIds = append(accountIds, accountId)
+	}
+	sort.Slice(accountIds, func(i, j int) bool { return accountIds[i] < accountIds[j] })
+
+	bootstrapBalances := make([]BootstrapBalance, 0, len(accountIds))
+	for _, accountId := range accountIds {
+		entityId, err := domain.DecodeEntityId(accountId)
+		if err != nil {
+			return nil, 0, errors.AddErrorDetails(errors.ErrInternalServerError, "reason", err.Error())
+		}
+
+		accountIdentifier := types.NewAccountIdFromEntityId(entityId).ToRosetta()
+		for _, amount := range balances[accountId] {
+			rosettaAmount := amount.ToRosetta()
+			bootstrapBalances = append(bootstrapBalances, BootstrapBalance{
+				AccountIdentifier: accountIdentifier,
+				Currency:          rosettaAmount.Currency,
+				Value:             rosettaAmount.Value,
+			})
+		}
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(bootstrapBalances); err != nil {
+		return nil, 0, errors.AddErrorDetails(errors.ErrInternalServerError, "reason", err.Error())
+	}
+
+	return block, len(bootstrapBalances), nil
+}
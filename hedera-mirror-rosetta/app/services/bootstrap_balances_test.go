@@ -0,0 +1,222 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	hErrors "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestBootstrapBalancesSuite(t *testing.T) {
+	suite.Run(t, new(bootstrapBalancesSuite))
+}
+
+type bootstrapBalancesSuite struct {
+	suite.Suite
+	mockAccountRepo *mocks.MockAccountRepository
+	mockBlockRepo   *mocks.MockBlockRepository
+}
+
+func (suite *bootstrapBalancesSuite) SetupTest() {
+	suite.mockAccountRepo = &mocks.MockAccountRepository{}
+	suite.mockBlockRepo = &mocks.MockBlockRepository{}
+}
+
+func (suite *bootstrapBalancesSuite) TestExportBootstrapBalances() {
+	// given
+	block := &types.Block{ConsensusEndNanos: 200, Hash: "0x12345", Index: 10}
+	suite.mockBlockRepo.On("FindByIndex").Return(block, mocks.NilError)
+	suite.mockAccountRepo.On("RetrieveAllBalancesAtBlock").Return(map[int64]types.AmountSlice{
+		1002: {
+			&types.TokenAmount{
+				TokenId: domain.MustDecodeEntityId(2001),
+				Type:    domain.TokenTypeNonFungibleUnique,
+				Value:   2,
+			},
+		},
+		1001: {
+			&types.HbarAmount{Value: 100},
+			&types.TokenAmount{
+				Decimals: 3,
+				TokenId:  domain.MustDecodeEntityId(2000),
+				Type:     domain.TokenTypeFungibleCommon,
+				Value:    -5,
+			},
+		},
+	}, mocks.NilError)
+	index := int64(10)
+	buf := &bytes.Buffer{}
+	expected := []interface{}{
+		map[string]interface{}{
+			"account_identifier": map[string]interface{}{"address": "0.0.1001"},
+			"currency": map[string]interface{}{
+				"symbol":   "HBAR",
+				"decimals": float64(8),
+				"metadata": map[string]interface{}{"issuer": "Hedera"},
+			},
+			"value": "100",
+		},
+		map[string]interface{}{
+			"account_identifier": map[string]interface{}{"address": "0.0.1001"},
+			"currency": map[string]interface{}{
+				"symbol":   "0.0.2000",
+				"decimals": float64(3),
+				"metadata": map[string]interface{}{"type": domain.TokenTypeFungibleCommon},
+			},
+			"value": "-5",
+		},
+		map[string]interface{}{
+			"account_identifier": map[string]interface{}{"address": "0.0.1002"},
+			"currency": map[string]interface{}{
+				"symbol":   "0.0.2001",
+				"decimals": float64(0),
+				"metadata": map[string]interface{}{"type": domain.TokenTypeNonFungibleUnique},
+			},
+			"value": "2",
+		},
+	}
+
+	// when
+	actualBlock, count, err := ExportBootstrapBalances(
+		defaultContext,
+		suite.mockAccountRepo,
+		suite.mockBlockRepo,
+		&index,
+		buf,
+	)
+
+	// then
+	var actual []interface{}
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), block, actualBlock)
+	assert.Equal(suite.T(), 3, count)
+	assert.NoError(suite.T(), json.Unmarshal(buf.Bytes(), &actual))
+	assert.Equal(suite.T(), expected, actual)
+	suite.mockBlockRepo.AssertNotCalled(suite.T(), "RetrieveLatest")
+}
+
+func (suite *bootstrapBalancesSuite) TestExportBootstrapBalancesAtLatestBlock() {
+	// given
+	block := &types.Block{ConsensusEndNanos: 200, Hash: "0x12345", Index: 10}
+	suite.mockBlockRepo.On("RetrieveLatest").Return(block, mocks.NilError)
+	suite.mockAccountRepo.On("RetrieveAllBalancesAtBlock").Return(map[int64]types.AmountSlice{
+		1001: {&types.HbarAmount{Value: 100}},
+	}, mocks.NilError)
+	buf := &bytes.Buffer{}
+
+	// when
+	actualBlock, count, err := ExportBootstrapBalances(
+		defaultContext,
+		suite.mockAccountRepo,
+		suite.mockBlockRepo,
+		nil,
+		buf,
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), block, actualBlock)
+	assert.Equal(suite.T(), 1, count)
+	assert.Contains(suite.T(), buf.String(), `"address": "0.0.1001"`)
+	suite.mockBlockRepo.AssertNotCalled(suite.T(), "FindByIndex")
+}
+
+func (suite *bootstrapBalancesSuite) TestExportBootstrapBalancesFindBlockFails() {
+	// given
+	suite.mockBlockRepo.On("FindByIndex").Return(mocks.NilBlock, hErrors.ErrBlockNotFound)
+	index := int64(10)
+	buf := &bytes.Buffer{}
+
+	// when
+	actualBlock, count, err := ExportBootstrapBalances(
+		defaultContext,
+		suite.mockAccountRepo,
+		suite.mockBlockRepo,
+		&index,
+		buf,
+	)
+
+	// then
+	assert.Equal(suite.T(), hErrors.ErrBlockNotFound, err)
+	assert.Nil(suite.T(), actualBlock)
+	assert.Zero(suite.T(), count)
+	assert.Zero(suite.T(), buf.Len())
+	suite.mockAccountRepo.AssertNotCalled(suite.T(), "RetrieveAllBalancesAtBlock")
+}
+
+func (suite *bootstrapBalancesSuite) TestExportBootstrapBalancesRetrieveBalancesFails() {
+	// given
+	suite.mockBlockRepo.On("RetrieveLatest").Return(&types.Block{Index: 10}, mocks.NilError)
+	suite.mockAccountRepo.On("RetrieveAllBalancesAtBlock").
+		Return(map[int64]types.AmountSlice(nil), hErrors.ErrDatabaseError)
+	buf := &bytes.Buffer{}
+
+	// when
+	actualBlock, count, err := ExportBootstrapBalances(
+		defaultContext,
+		suite.mockAccountRepo,
+		suite.mockBlockRepo,
+		nil,
+		buf,
+	)
+
+	// then
+	assert.Equal(suite.T(), hErrors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actualBlock)
+	assert.Zero(suite.T(), count)
+	assert.Zero(suite.T(), buf.Len())
+}
+
+func (suite *bootstrapBalancesSuite) TestExportBootstrapBalancesWriteFails() {
+	// given
+	suite.mockBlockRepo.On("RetrieveLatest").Return(&types.Block{Index: 10}, mocks.NilError)
+	suite.mockAccountRepo.On("RetrieveAllBalancesAtBlock").Return(map[int64]types.AmountSlice{
+		1001: {&types.HbarAmount{Value: 100}},
+	}, mocks.NilError)
+
+	// when
+	actualBlock, count, err := ExportBootstrapBalances(
+		defaultContext,
+		suite.mockAccountRepo,
+		suite.mockBlockRepo,
+		nil,
+		failingWriter{},
+	)
+
+	// then
+	assert.Equal(suite.T(), hErrors.ErrInternalServerError.Code, err.Code)
+	assert.Nil(suite.T(), actualBlock)
+	assert.Zero(suite.T(), count)
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
@@ -0,0 +1,1307 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package services
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/server"
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/go-playground/validator/v10"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/tools"
+	"github.com/hashgraph/hedera-sdk-go/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultNftSerialsLimit      = 25
+	defaultPendingAirdropsLimit = 25
+	defaultReconciliationLimit  = 25
+	defaultStakingHistoryLimit  = 25
+	defaultTokenHoldersLimit    = 25
+	evmAddressLength            = 20
+	// maxPayoutReceivers is the max number of receivers of a payout transaction, the network allows at most 10 account
+	// amounts in the transfer list of a crypto transfer transaction, one of which is the sender
+	maxPayoutReceivers = 9
+	// maxConsensusDelay is the max delay after the valid start window of a transaction closes for the transaction
+	// accepted by a node at the end of the window to reach consensus
+	maxConsensusDelay = int64(time.Minute)
+	// maxStatisticsRange is the max timestamp range of the transaction statistics, so the aggregation stays bounded
+	maxStatisticsRange = int64(31 * 24 * time.Hour)
+)
+
+const (
+	rebroadcastStatusConfirmed   = "confirmed"
+	rebroadcastStatusExpired     = "expired"
+	rebroadcastStatusPending     = "pending"
+	rebroadcastStatusResubmitted = "resubmitted"
+)
+
+// callHandler handles a /call request of a specific method with the request parameters
+type callHandler func(ctx context.Context, parameters map[string]interface{}) (*rTypes.CallResponse, *rTypes.Error)
+
+type accountBalancesParameters struct {
+	AccountIds []string `json:"account_ids" validate:"required,min=1,max=1000,dive,required"`
+	Hash       *string  `json:"hash"`
+	Index      *int64   `json:"index" validate:"omitempty,gte=0"`
+}
+
+type aliasAccountsParameters struct {
+	EvmAddress *string `json:"evm_address" validate:"required_without=PublicKey,excluded_with=PublicKey"`
+	PublicKey  *string `json:"public_key"`
+}
+
+type balanceReconciliationParameters struct {
+	AccountId string `json:"account_id" validate:"required"`
+	Limit     *int   `json:"limit" validate:"omitempty,gte=1,lte=100"`
+	Timestamp *int64 `json:"timestamp" validate:"required,gte=0"`
+}
+
+type blockByTimestampParameters struct {
+	Timestamp *int64 `json:"timestamp" validate:"required,gte=0"`
+}
+
+type blockTransactionCountParameters struct {
+	Hash  *string `json:"hash"`
+	Index *int64  `json:"index" validate:"required,gte=0"`
+}
+
+type decodedTransactionParameters struct {
+	Hash            *string `json:"hash"`
+	Index           *int64  `json:"index" validate:"required,gte=0"`
+	TransactionHash string  `json:"transaction_hash" validate:"required"`
+}
+
+type entityStakeParameters struct {
+	AccountId string `json:"account_id" validate:"required"`
+}
+
+type nftInfoParameters struct {
+	SerialNumber *int64 `json:"serial_number" validate:"required,gte=1"`
+	TokenId      string `json:"token_id" validate:"required"`
+}
+
+type nftSerialsParameters struct {
+	Cursor  *string `json:"cursor"`
+	Limit   *int    `json:"limit" validate:"omitempty,gte=1,lte=100"`
+	TokenId string  `json:"token_id" validate:"required"`
+}
+
+type stakingRewardHistoryParameters struct {
+	AccountId string `json:"account_id" validate:"required"`
+	Limit     *int   `json:"limit" validate:"omitempty,gte=1,lte=100"`
+}
+
+type submissionStatusParameters struct {
+	TransactionHash string `json:"transaction_hash" validate:"required"`
+}
+
+type tokenBalancesParameters struct {
+	AccountId string   `json:"account_id" validate:"required"`
+	Hash      *string  `json:"hash"`
+	Index     *int64   `json:"index" validate:"omitempty,gte=0"`
+	TokenIds  []string `json:"token_ids" validate:"required,min=1,max=100,dive,required"`
+}
+
+type tokenHoldersParameters struct {
+	Cursor     *string `json:"cursor"`
+	Limit      *int    `json:"limit" validate:"omitempty,gte=1,lte=100"`
+	MinBalance *int64  `json:"min_balance" validate:"omitempty,gte=1"`
+	TokenId    string  `json:"token_id" validate:"required"`
+}
+
+type nodeStakesParameters struct {
+	EpochDay *int64 `json:"epoch_day" validate:"omitempty,gte=0"`
+}
+
+type payoutReceiver struct {
+	AccountId string `json:"account_id" validate:"required"`
+	Amount    string `json:"amount" validate:"required"`
+}
+
+type payoutTransactionsParameters struct {
+	MaxReceivers    *int             `json:"max_receivers" validate:"omitempty,gte=1,lte=9"`
+	Receivers       []payoutReceiver `json:"receivers" validate:"required,min=1,max=1000,dive"`
+	Sender          string           `json:"sender" validate:"required"`
+	TokenId         *string          `json:"token_id"`
+	ValidDuration   *int64           `json:"valid_duration" validate:"omitempty,gte=1,lte=180"`
+	ValidStartNanos *int64           `json:"valid_start_nanos" validate:"omitempty,gte=1"`
+}
+
+type pendingAirdropsParameters struct {
+	AccountId string `json:"account_id" validate:"required"`
+	Limit     *int   `json:"limit" validate:"omitempty,gte=1,lte=100"`
+}
+
+type previewTransactionParameters struct {
+	UnsignedTransaction string `json:"unsigned_transaction" validate:"required"`
+}
+
+type rebroadcastTransactionParameters struct {
+	SignedTransaction string `json:"signed_transaction" validate:"required"`
+}
+
+type scheduleInfoParameters struct {
+	ScheduleId string `json:"schedule_id" validate:"required"`
+}
+
+type transactionStatisticsParameters struct {
+	End   *int64 `json:"end" validate:"required,gte=0"`
+	Start *int64 `json:"start" validate:"required,gte=0"`
+}
+
+type topicMessageParameters struct {
+	SequenceNumber *int64 `json:"sequence_number" validate:"required,gte=1"`
+	TopicId        string `json:"topic_id" validate:"required"`
+}
+
+type verifyBlockParameters struct {
+	Hash  *string `json:"hash"`
+	Index *int64  `json:"index" validate:"required,gte=0"`
+}
+
+// accountBalanceChange is the balance changes of an account by the operations of a transaction, keyed by the currency
+// symbol
+type accountBalanceChange struct {
+	account    *rTypes.AccountIdentifier
+	changes    map[string]int64
+	currencies map[string]*rTypes.Currency
+}
+
+// project returns the current balance, the change, and the projected balance of each currency changed, ordered by the
+// currency symbol. The current balance of a currency the account doesn't hold is 0
+func (a *accountBalanceChange) project(amounts types.AmountSlice) []map[string]interface{} {
+	current := make(map[string]int64)
+	for _, amount := range amounts {
+		current[amount.GetSymbol()] = amount.GetValue()
+	}
+
+	symbols := make([]string, 0, len(a.changes))
+	for symbol := range a.changes {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	balances := make([]map[string]interface{}, 0, len(symbols))
+	for _, symbol := range symbols {
+		balances = append(balances, map[string]interface{}{
+			"balance":           strconv.FormatInt(current[symbol], 10),
+			"change":            strconv.FormatInt(a.changes[symbol], 10),
+			"currency":          a.currencies[symbol],
+			"projected_balance": strconv.FormatInt(current[symbol]+a.changes[symbol], 10),
+		})
+	}
+	return balances
+}
+
+// callAPIService implements the server.CallAPIServicer interface.
+type callAPIService struct {
+	BaseService
+	accountRepo            interfaces.AccountRepository
+	constructionAPIService server.ConstructionAPIServicer
+	cursorTtl              time.Duration
+	handlers               map[string]callHandler
+	scheduleRepo           interfaces.ScheduleRepository
+	stakingRepo            interfaces.StakingRepository
+	submissionWatcher      *SubmissionWatcher
+	systemShard            int64
+	systemRealm            int64
+	tokenRepo              interfaces.TokenRepository
+	topicMessageRepo       interfaces.TopicMessageRepository
+	validate               *validator.Validate
+}
+
+// Call implements the /call endpoint.
+func (c *callAPIService) Call(ctx context.Context, request *rTypes.CallRequest) (*rTypes.CallResponse, *rTypes.Error) {
+	if !c.IsOnline() {
+		return nil, errors.ErrEndpointNotSupportedInOfflineMode
+	}
+
+	handler, ok := c.handlers[request.Method]
+	if !ok {
+		return nil, errors.ErrCallMethodUnsupported
+	}
+
+	return handler(ctx, request.Parameters)
+}
+
+// accountBalances returns the hbar balances of up to 1000 accounts at the block, or the latest block if the block
+// identifier is not set, with one set-based query
+func (c *callAPIService) accountBalances(ctx context.Context, parameters map[string]interface{}) (
+	*rTypes.CallResponse,
+	*rTypes.Error,
+) {
+	var params accountBalancesParameters
+	if err := c.parseParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+
+	accountIds := make([]domain.EntityId, 0, len(params.AccountIds))
+	encodedIds := make([]int64, 0, len(params.AccountIds))
+	for _, address := range params.AccountIds {
+		// only the shard.realm.num form is supported since resolving aliases takes a query per account
+		accountId, err := domain.EntityIdFromString(address)
+		if err != nil {
+			return nil, errors.AddErrorDetails(errors.ErrInvalidCallParameters, "reason", err.Error())
+		}
+		accountIds = append(accountIds, accountId)
+		encodedIds = append(encodedIds, accountId.EncodedId)
+	}
+
+	block, err := c.RetrieveBlock(ctx, &rTypes.PartialBlockIdentifier{Hash: params.Hash, Index: params.Index})
+	if err != nil {
+		return nil, err
+	}
+
+	hbarAmounts, err := c.accountRepo.RetrieveHbarBalancesAtBlock(ctx, encodedIds, block.ConsensusEndNanos)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make([]map[string]interface{}, 0, len(accountIds))
+	for _, accountId := range accountIds {
+		hbarAmount := hbarAmounts[accountId.EncodedId]
+		balances = append(balances, map[string]interface{}{
+			"account_identifier": types.NewAccountIdFromEntityId(accountId).ToRosetta(),
+			"balance":            hbarAmount.ToRosetta(),
+		})
+	}
+
+	return &rTypes.CallResponse{
+		Result: map[string]interface{}{
+			"balances":         balances,
+			"block_identifier": block.GetRosettaBlockIdentifier(),
+		},
+		// the balances at a fixed block never change
+		Idempotent: params.Hash != nil || params.Index != nil,
+	}, nil
+}
+
+// aliasAccounts returns the accounts ever associated with a public key alias or an evm address, with the transaction
+// which created each account, so a deposit sent to an alias before the account existed can be attributed. The evm
+// address derived from an ECDSA secp256k1 public key is also matched
+func (c *callAPIService) aliasAccounts(ctx context.Context, parameters map[string]interface{}) (
+	*rTypes.CallResponse,
+	*rTypes.Error,
+) {
+	var params aliasAccountsParameters
+	if err := c.parseParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+
+	var alias, evmAddress []byte
+	var err error
+	if params.PublicKey != nil {
+		if alias, evmAddress, err = getAliasAndEvmAddress(*params.PublicKey); err != nil {
+			return nil, errors.AddErrorDetails(errors.ErrInvalidCallParameters, "reason", err.Error())
+		}
+	} else {
+		evmAddress, err = hex.DecodeString(tools.SafeRemoveHexPrefix(*params.EvmAddress))
+		if err != nil || len(evmAddress) != evmAddressLength {
+			return nil, errors.AddErrorDetails(errors.ErrInvalidCallParameters, "reason", "Invalid evm address")
+		}
+	}
+
+	accounts, rErr := c.accountRepo.FindAccountsByAlias(ctx, alias, evmAddress)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	// the result is not idempotent since an account may be created with the alias later
+	return &rTypes.CallResponse{Result: accounts.ToMetadata(), Idempotent: false}, nil
+}
+
+// balanceReconciliation compares the hbar balance of an account in the latest balance snapshot at or before the
+// timestamp with the balance computed from the previous snapshot and the crypto transfers in between, and returns the
+// delta and the earliest limit (defaults to 25) transactions which changed the balance between the snapshots
+func (c *callAPIService) balanceReconciliation(ctx context.Context, parameters map[string]interface{}) (
+	*rTypes.CallResponse,
+	*rTypes.Error,
+) {
+	var params balanceReconciliationParameters
+	if err := c.parseParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+
+	accountId, err := domain.EntityIdFromString(params.AccountId)
+	if err != nil {
+		return nil, errors.AddErrorDetails(errors.ErrInvalidCallParameters, "reason", err.Error())
+	}
+
+	limit := defaultReconciliationLimit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+
+	reconciliation, rErr := c.accountRepo.RetrieveBalanceReconciliation(
+		ctx,
+		accountId.EncodedId,
+		*params.Timestamp,
+		limit,
+	)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	// the result is not idempotent since a later balance snapshot may become the nearest one
+	return &rTypes.CallResponse{Result: reconciliation.ToMetadata(), Idempotent: false}, nil
+}
+
+// blockByTimestamp returns the identifier and the consensus start and end of the block containing the consensus
+// timestamp, so integrators can correlate external events with on-chain data
+func (c *callAPIService) blockByTimestamp(ctx context.Context, parameters map[string]interface{}) (
+	*rTypes.CallResponse,
+	*rTypes.Error,
+) {
+	var params blockByTimestampParameters
+	if err := c.parseParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+
+	block, err := c.FindByTimestamp(ctx, *params.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rTypes.CallResponse{
+		Result: map[string]interface{}{
+			"block_identifier": block.GetRosettaBlockIdentifier(),
+			"consensus_end":    block.ConsensusEndNanos,
+			"consensus_start":  block.ConsensusStartNanos,
+		},
+		Idempotent: true,
+	}, nil
+}
+
+// blockTransactionCount returns the number of transactions and the estimated number of operations in a block
+func (c *callAPIService) blockTransactionCount(ctx context.Context, parameters map[string]interface{}) (
+	*rTypes.CallResponse,
+	*rTypes.Error,
+) {
+	var params blockTransactionCountParameters
+	if err := c.parseParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+
+	block, err := c.RetrieveBlock(ctx, &rTypes.PartialBlockIdentifier{Hash: params.Hash, Index: params.Index})
+	if err != nil {
+		return nil, err
+	}
+
+	transactionCount, operationCount, err := c.CountBetween(ctx, block.ConsensusStartNanos, block.ConsensusEndNanos)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rTypes.CallResponse{
+		Result: map[string]interface{}{
+			"block_identifier":  block.GetRosettaBlockIdentifier(),
+			"operation_count":   operationCount,
+			"transaction_count": transactionCount,
+		},
+		Idempotent: true,
+	}, nil
+}
+
+// decodedTransaction returns the decoded protobuf transaction body and the transaction record in json of the first
+// transaction with the hash in the block, for debugging use cases the rosetta operation model can't serve. The block
+// index is required since looking up a transaction by its hash alone requires a full table scan
+func (c *callAPIService) decodedTransaction(ctx context.Context, parameters map[string]interface{}) (
+	*rTypes.CallResponse,
+	*rTypes.Error,
+) {
+	var params decodedTransactionParameters
+	if err := c.parseParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+
+	block, err := c.RetrieveBlock(ctx, &rTypes.PartialBlockIdentifier{Hash: params.Hash, Index: params.Index})
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, err := c.FindRawByHashInBlock(
+		ctx,
+		params.TransactionHash,
+		block.ConsensusStartNanos,
+		block.ConsensusEndNanos,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result, decodeErr := transaction.ToMetadata()
+	if decodeErr != nil {
+		log.Errorf("Failed to decode transaction %s: %s", params.TransactionHash, decodeErr)
+		return nil, errors.AddErrorDetails(errors.ErrTransactionDecodeFailed, "reason", decodeErr.Error())
+	}
+	result["block_identifier"] = block.GetRosettaBlockIdentifier()
+
+	return &rTypes.CallResponse{Result: result, Idempotent: true}, nil
+}
+
+// entityStake returns the stake of an account, i.e., the hbars staked to it by other accounts and the stake total it
+// contributes to its staked node, its staking settings, and the estimated pending reward it can claim, so a wallet can
+// show the claimable staking reward without computing it client side
+func (c *callAPIService) entityStake(ctx context.Context, parameters map[string]interface{}) (
+	*rTypes.CallResponse,
+	*rTypes.Error,
+) {
+	var params entityStakeParameters
+	if err := c.parseParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+
+	accountId, err := domain.EntityIdFromString(params.AccountId)
+	if err != nil {
+		return nil, errors.AddErrorDetails(errors.ErrInvalidCallParameters, "reason", err.Error())
+	}
+
+	stake, rErr := c.stakingRepo.FindEntityStake(ctx, accountId.EncodedId)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	// the result is not idempotent since the balances change and a new staking period may end
+	return &rTypes.CallResponse{Result: stake.ToMetadata(), Idempotent: false}, nil
+}
+
+// nftInfo returns the owner, the metadata bytes, the mint and burn timestamps, and the spender of a nft
+func (c *callAPIService) nftInfo(ctx context.Context, parameters map[string]interface{}) (
+	*rTypes.CallResponse,
+	*rTypes.Error,
+) {
+	var params nftInfoParameters
+	if err := c.parseParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+
+	tokenId, err := domain.EntityIdFromString(params.TokenId)
+	if err != nil {
+		return nil, errors.AddErrorDetails(errors.ErrInvalidCallParameters, "reason", err.Error())
+	}
+
+	nft, rErr := c.tokenRepo.FindNft(ctx, tokenId.EncodedId, *params.SerialNumber)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	// the result is not idempotent since the nft may be transferred or burned later
+	return &rTypes.CallResponse{Result: nft.ToMetadata(), Idempotent: false}, nil
+}
+
+// nftSerials returns a page of the nfts of a collection in ascending order of the serial number. The next field is set
+// to the cursor of the last nft of a full page, and should be passed as the cursor parameter to get the next page
+func (c *callAPIService) nftSerials(ctx context.Context, parameters map[string]interface{}) (
+	*rTypes.CallResponse,
+	*rTypes.Error,
+) {
+	var params nftSerialsParameters
+	if err := c.parseParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+
+	tokenId, err := domain.EntityIdFromString(params.TokenId)
+	if err != nil {
+		return nil, errors.AddErrorDetails(errors.ErrInvalidCallParameters, "reason", err.Error())
+	}
+
+	var after int64
+	cursor, rErr := c.parseCursor(params.Cursor)
+	if rErr != nil {
+		return nil, rErr
+	} else if cursor != nil {
+		after = cursor.Position
+	}
+
+	limit := defaultNftSerialsLimit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+
+	nfts, rErr := c.tokenRepo.FindNfts(ctx, tokenId.EncodedId, after, limit)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	nftList := make([]map[string]interface{}, 0, len(nfts))
+	for _, nft := range nfts {
+		nftList = append(nftList, nft.ToMetadata())
+	}
+
+	result := map[string]interface{}{"nfts": nftList}
+	if len(nfts) == limit {
+		result["next"] = types.NewCursor(nfts[len(nfts)-1].SerialNumber, "").Encode()
+	}
+
+	return &rTypes.CallResponse{Result: result, Idempotent: false}, nil
+}
+
+// nodeStakes returns the stake, the reward rate, and the min / max stake of each node, and the network stake in the
+// staking period of the epoch day, or the latest staking period if the epoch day is not set
+func (c *callAPIService) nodeStakes(ctx context.Context, parameters map[string]interface{}) (
+	*rTypes.CallResponse,
+	*rTypes.Error,
+) {
+	var params nodeStakesParameters
+	if err := c.parseParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+
+	nodeStakes, rErr := c.stakingRepo.FindNodeStakes(ctx, params.EpochDay)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	// the result is not idempotent since the node stakes of a staking period may be recalculated
+	return &rTypes.CallResponse{Result: nodeStakes.ToMetadata(), Idempotent: false}, nil
+}
+
+// payoutTransactions expands a payout template, i.e., one sender paying hbar or a fungible token to a list of
+// receivers, into crypto transfer transactions of at most max_receivers (default and max 9) receivers each, so every
+// transaction stays within the transfer list limit and the transaction size limit. Each transaction is constructed the same way as
+// /construction/payloads and returned with its signing payloads. When valid_start_nanos is set, the valid start of the
+// nth transaction is incremented by n nanoseconds so the transaction ids are unique
+func (c *callAPIService) payoutTransactions(ctx context.Context, parameters map[string]interface{}) (
+	*rTypes.CallResponse,
+	*rTypes.Error,
+) {
+	var params payoutTransactionsParameters
+	if err := c.parseParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+
+	newAmount := func(value int64) types.Amount { return &types.HbarAmount{Value: value} }
+	if params.TokenId != nil {
+		tokenId, err := domain.EntityIdFromString(*params.TokenId)
+		if err != nil {
+			return nil, errors.AddErrorDetails(errors.ErrInvalidCallParameters, "reason", err.Error())
+		}
+
+		token, rErr := c.tokenRepo.Find(ctx, tokenId.EncodedId)
+		if rErr != nil {
+			return nil, rErr
+		}
+
+		if token.Type != domain.TokenTypeFungibleCommon {
+			return nil, errors.AddErrorDetails(errors.ErrInvalidCallParameters, "reason", "token must be fungible")
+		}
+		newAmount = func(value int64) types.Amount { return types.NewTokenAmount(token.Token, value) }
+	}
+
+	amounts := make([]int64, 0, len(params.Receivers))
+	for _, receiver := range params.Receivers {
+		amount, err := tools.ToInt64(receiver.Amount)
+		if err != nil || amount <= 0 {
+			return nil, errors.AddErrorDetails(errors.ErrInvalidCallParameters, "reason",
+				fmt.Sprintf("invalid amount %s of receiver %s", receiver.Amount, receiver.AccountId))
+		}
+		amounts = append(amounts, amount)
+	}
+
+	maxReceivers := maxPayoutReceivers
+	if params.MaxReceivers != nil {
+		maxReceivers = *params.MaxReceivers
+	}
+
+	transactions := make([]*rTypes.ConstructionPayloadsResponse, 0, (len(amounts)+maxReceivers-1)/maxReceivers)
+	for start := 0; start < len(amounts); start += maxReceivers {
+		end := start + maxReceivers
+		if end > len(amounts) {
+			end = len(amounts)
+		}
+
+		total := int64(0)
+		operations := make([]*rTypes.Operation, 0, end-start+1)
+		for i := start; i < end; i++ {
+			// the sum of the amounts overflows if it wraps around to a non-positive value
+			if total += amounts[i]; total <= 0 {
+				return nil, errors.AddErrorDetails(errors.ErrInvalidCallParameters, "reason", "total amount overflows")
+			}
+			operations = append(operations, newPayoutOperation(i-start+1, params.Receivers[i].AccountId,
+				newAmount(amounts[i])))
+		}
+		operations = append([]*rTypes.Operation{newPayoutOperation(0, params.Sender, newAmount(-total))},
+			operations...)
+
+		metadata := make(map[string]interface{})
+		if params.ValidDuration != nil {
+			metadata[metadataKeyValidDurationSeconds] = strconv.FormatInt(*params.ValidDuration, 10)
+		}
+		if params.ValidStartNanos != nil {
+			validStartNanos := *params.ValidStartNanos + int64(len(transactions))
+			metadata[metadataKeyValidStartNanos] = strconv.FormatInt(validStartNanos, 10)
+		}
+
+		response, rErr := c.constructionAPIService.ConstructionPayloads(ctx, &rTypes.ConstructionPayloadsRequest{
+			Operations: operations,
+			Metadata:   metadata,
+		})
+		if rErr != nil {
+			return nil, rErr
+		}
+
+		transactionBytes, err := hex.DecodeString(tools.SafeRemoveHexPrefix(response.UnsignedTransaction))
+		if err != nil {
+			return nil, errors.ErrTransactionDecodeFailed
+		}
+		if rErr = validateTransactionSize(transactionBytes); rErr != nil {
+			return nil, rErr
+		}
+
+		transactions = append(transactions, response)
+	}
+
+	// the result is not idempotent since the node account id of each transaction is randomly picked
+	return &rTypes.CallResponse{Result: map[string]interface{}{"transactions": transactions}, Idempotent: false}, nil
+}
+
+// pendingAirdrops returns the pending airdrops the account is either the receiver or the sender of, oldest first. No
+// airdrop is returned if the network hasn't enabled HIP-904 token airdrops
+func (c *callAPIService) pendingAirdrops(ctx context.Context, parameters map[string]interface{}) (
+	*rTypes.CallResponse,
+	*rTypes.Error,
+) {
+	var params pendingAirdropsParameters
+	if err := c.parseParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+
+	accountId, err := domain.EntityIdFromString(params.AccountId)
+	if err != nil {
+		return nil, errors.AddErrorDetails(errors.ErrInvalidCallParameters, "reason", err.Error())
+	}
+
+	limit := defaultPendingAirdropsLimit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+
+	airdrops, rErr := c.tokenRepo.FindPendingAirdrops(ctx, accountId.EncodedId, limit)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	airdropList := make([]map[string]interface{}, 0, len(airdrops))
+	for _, airdrop := range airdrops {
+		airdropList = append(airdropList, airdrop.ToMetadata())
+	}
+
+	// the result is not idempotent since the pending airdrops may be claimed or cancelled later
+	return &rTypes.CallResponse{Result: map[string]interface{}{"pending_airdrops": airdropList}, Idempotent: false}, nil
+}
+
+// previewTransaction parses the unsigned transaction and returns its operations and the projected balances of the
+// accounts in the operations, i.e., the balances at the latest block plus the amounts of the operations, so a wallet
+// can render a confirmation screen. The transaction fee is not included since it's only known after consensus
+func (c *callAPIService) previewTransaction(ctx context.Context, parameters map[string]interface{}) (
+	*rTypes.CallResponse,
+	*rTypes.Error,
+) {
+	var params previewTransactionParameters
+	if err := c.parseParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+
+	parsed, rErr := c.constructionAPIService.ConstructionParse(ctx, &rTypes.ConstructionParseRequest{
+		Transaction: params.UnsignedTransaction,
+	})
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	accountChanges := make([]*accountBalanceChange, 0)
+	accountChangeMap := make(map[string]*accountBalanceChange)
+	for _, operation := range parsed.Operations {
+		if operation.Account == nil || operation.Amount == nil {
+			continue
+		}
+
+		value, err := strconv.ParseInt(operation.Amount.Value, 10, 64)
+		if err != nil {
+			return nil, errors.ErrInvalidOperationsAmount
+		}
+
+		accountChange, ok := accountChangeMap[operation.Account.Address]
+		if !ok {
+			accountChange = &accountBalanceChange{
+				account:    operation.Account,
+				changes:    make(map[string]int64),
+				currencies: make(map[string]*rTypes.Currency),
+			}
+			accountChanges = append(accountChanges, accountChange)
+			accountChangeMap[operation.Account.Address] = accountChange
+		}
+		accountChange.changes[operation.Amount.Currency.Symbol] += value
+		accountChange.currencies[operation.Amount.Currency.Symbol] = operation.Amount.Currency
+	}
+	// the order of the parsed operations isn't stable, so sort the accounts by the address
+	sort.Slice(accountChanges, func(i, j int) bool {
+		return accountChanges[i].account.Address < accountChanges[j].account.Address
+	})
+
+	block, rErr := c.RetrieveLatest(ctx)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	projected := make([]map[string]interface{}, 0, len(accountChanges))
+	for _, accountChange := range accountChanges {
+		accountId, err := types.NewAccountIdFromString(accountChange.account.Address, c.systemShard, c.systemRealm)
+		if err != nil {
+			return nil, errors.ErrInvalidAccount
+		}
+
+		amounts, _, _, rErr := c.accountRepo.RetrieveBalanceAtBlock(ctx, accountId, block.ConsensusEndNanos)
+		if rErr != nil {
+			return nil, rErr
+		}
+
+		projected = append(projected, map[string]interface{}{
+			"account_identifier": accountChange.account,
+			"balances":           accountChange.project(amounts),
+		})
+	}
+
+	return &rTypes.CallResponse{
+		Result: map[string]interface{}{
+			"accounts":         projected,
+			"block_identifier": block.GetRosettaBlockIdentifier(),
+			"operations":       parsed.Operations,
+		},
+		// the balances change with the new blocks
+		Idempotent: false,
+	}, nil
+}
+
+// rebroadcastTransaction looks up a previously signed transaction by its hash in its valid start window. If the
+// transaction hasn't reached consensus and its valid start window hasn't closed, it's resubmitted to the network. Note
+// the signed transaction bytes are bound to the node account id in the transaction body, so the transaction is
+// resubmitted to the same node. An expired transaction is reported as pending until the latest block is past the
+// window in which it can still reach consensus
+func (c *callAPIService) rebroadcastTransaction(ctx context.Context, parameters map[string]interface{}) (
+	*rTypes.CallResponse,
+	*rTypes.Error,
+) {
+	var params rebroadcastTransactionParameters
+	if err := c.parseParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+
+	transaction, rErr := unmarshallTransactionFromHexString(params.SignedTransaction)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	hashBytes, err := transaction.GetTransactionHash()
+	if err != nil {
+		return nil, errors.ErrTransactionHashFailed
+	}
+	hash := tools.SafeAddHexPrefix(hex.EncodeToString(hashBytes))
+
+	validStart := transaction.GetTransactionID().ValidStart
+	if validStart == nil {
+		return nil, errors.ErrInvalidTransaction
+	}
+	validStartNanos := validStart.UnixNano()
+	validEndNanos := validStartNanos + transaction.GetTransactionValidDuration().Nanoseconds()
+	consensusEndNanos := validEndNanos + maxConsensusDelay
+
+	result := map[string]interface{}{"hash": hash}
+	raw, rErr := c.FindRawByHashInBlock(ctx, hash, validStartNanos, consensusEndNanos)
+	if rErr == nil {
+		result["consensus_timestamp"] = raw.ConsensusTimestamp
+		result["status"] = rebroadcastStatusConfirmed
+		return &rTypes.CallResponse{Result: result, Idempotent: true}, nil
+	} else if rErr != errors.ErrTransactionNotFound {
+		return nil, rErr
+	}
+
+	if time.Now().UnixNano() >= validEndNanos {
+		block, rErr := c.RetrieveLatest(ctx)
+		if rErr != nil {
+			return nil, rErr
+		}
+
+		if block.ConsensusEndNanos > consensusEndNanos {
+			result["status"] = rebroadcastStatusExpired
+		} else {
+			result["status"] = rebroadcastStatusPending
+		}
+		return &rTypes.CallResponse{Result: result, Idempotent: false}, nil
+	}
+
+	if _, rErr = c.constructionAPIService.ConstructionSubmit(ctx, &rTypes.ConstructionSubmitRequest{
+		SignedTransaction: params.SignedTransaction,
+	}); rErr != nil {
+		return nil, rErr
+	}
+
+	result["status"] = rebroadcastStatusResubmitted
+	return &rTypes.CallResponse{Result: result, Idempotent: false}, nil
+}
+
+// scheduleInfo returns the expiration time, the wait_for_expiry flag, and the executed timestamp of a schedule
+func (c *callAPIService) scheduleInfo(ctx context.Context, parameters map[string]interface{}) (
+	*rTypes.CallResponse,
+	*rTypes.Error,
+) {
+	var params scheduleInfoParameters
+	if err := c.parseParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+
+	scheduleId, err := domain.EntityIdFromString(params.ScheduleId)
+	if err != nil {
+		return nil, errors.AddErrorDetails(errors.ErrInvalidCallParameters, "reason", err.Error())
+	}
+
+	schedule, rErr := c.scheduleRepo.FindByScheduleId(ctx, scheduleId.EncodedId)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	result := schedule.ToMetadata()
+	if schedule.ExecutedTimestamp != nil {
+		result["executed_timestamp"] = *schedule.ExecutedTimestamp
+	}
+
+	// the result is not idempotent since the schedule may execute or expire later
+	return &rTypes.CallResponse{Result: result, Idempotent: false}, nil
+}
+
+// stakingRewardHistory returns the staking settings of an account, the estimated pending reward, the reward rate of
+// the staked node in the most recent limit (defaults to 25) staking periods, and the most recent limit staking rewards
+// paid to the account
+func (c *callAPIService) stakingRewardHistory(ctx context.Context, parameters map[string]interface{}) (
+	*rTypes.CallResponse,
+	*rTypes.Error,
+) {
+	var params stakingRewardHistoryParameters
+	if err := c.parseParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+
+	accountId, err := domain.EntityIdFromString(params.AccountId)
+	if err != nil {
+		return nil, errors.AddErrorDetails(errors.ErrInvalidCallParameters, "reason", err.Error())
+	}
+
+	limit := defaultStakingHistoryLimit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+
+	history, rErr := c.stakingRepo.FindStakingHistory(ctx, accountId.EncodedId, limit)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	// the result is not idempotent since a new staking period may end or a reward may be paid later
+	return &rTypes.CallResponse{Result: history.ToMetadata(), Idempotent: false}, nil
+}
+
+// submissionStatus returns the status of a transaction submitted via /construction/submit, one of PENDING, SUCCESS,
+// FAILED, and EXPIRED, and once it appears in a block, its consensus timestamp and result code. Only the transactions
+// submitted when the submission watcher is enabled are tracked
+func (c *callAPIService) submissionStatus(_ context.Context, parameters map[string]interface{}) (
+	*rTypes.CallResponse,
+	*rTypes.Error,
+) {
+	var params submissionStatusParameters
+	if err := c.parseParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+
+	if c.submissionWatcher == nil {
+		return nil, errors.AddErrorDetails(errors.ErrTransactionNotFound, "reason", "submission watcher is disabled")
+	}
+
+	hash := tools.SafeAddHexPrefix(strings.ToLower(tools.SafeRemoveHexPrefix(params.TransactionHash)))
+	submission, ok := c.submissionWatcher.Get(hash)
+	if !ok {
+		return nil, errors.ErrTransactionNotFound
+	}
+
+	result := map[string]interface{}{
+		"hash":              submission.Hash,
+		"status":            submission.Status,
+		"valid_start_nanos": submission.ValidStartNanos,
+		"valid_until_nanos": submission.ValidEndNanos,
+	}
+	if submission.Result != "" {
+		result["consensus_timestamp"] = submission.ConsensusTimestamp
+		result["result"] = submission.Result
+	}
+
+	return &rTypes.CallResponse{Result: result, Idempotent: false}, nil
+}
+
+// tokenBalances returns the balances of a set of tokens of an account at a block, defaults to the latest block. Unlike
+// /account/balance, which computes the balances of all the account's tokens, only the requested tokens are reconstructed
+// from the nearest balance snapshot, so it's usable for accounts associated with a large number of tokens. The tokens
+// which don't exist are omitted
+func (c *callAPIService) tokenBalances(ctx context.Context, parameters map[string]interface{}) (
+	*rTypes.CallResponse,
+	*rTypes.Error,
+) {
+	var params tokenBalancesParameters
+	if err := c.parseParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+
+	// only the shard.realm.num form is supported, same as account_balances
+	accountId, err := domain.EntityIdFromString(params.AccountId)
+	if err != nil {
+		return nil, errors.AddErrorDetails(errors.ErrInvalidCallParameters, "reason", err.Error())
+	}
+
+	tokenIds := make([]int64, 0, len(params.TokenIds))
+	for _, tokenIdString := range params.TokenIds {
+		tokenId, err := domain.EntityIdFromString(tokenIdString)
+		if err != nil {
+			return nil, errors.AddErrorDetails(errors.ErrInvalidCallParameters, "reason", err.Error())
+		}
+		tokenIds = append(tokenIds, tokenId.EncodedId)
+	}
+
+	block, rErr := c.RetrieveBlock(ctx, &rTypes.PartialBlockIdentifier{Hash: params.Hash, Index: params.Index})
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	amounts, rErr := c.accountRepo.RetrieveTokenBalancesAtBlock(
+		ctx,
+		accountId.EncodedId,
+		tokenIds,
+		block.ConsensusEndNanos,
+	)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	return &rTypes.CallResponse{
+		Result: map[string]interface{}{
+			"account_identifier": types.NewAccountIdFromEntityId(accountId).ToRosetta(),
+			"balances":           amounts.ToRosetta(),
+			"block_identifier":   block.GetRosettaBlockIdentifier(),
+		},
+		// the balances at a fixed block never change
+		Idempotent: params.Hash != nil || params.Index != nil,
+	}, nil
+}
+
+// tokenHolders returns a page of the accounts holding at least min_balance (defaults to 1) of a fungible token in the
+// latest balance snapshot, in ascending order of the account id. The next field is set to the cursor of the last
+// account of a full page, and should be passed as the cursor parameter to get the next page
+func (c *callAPIService) tokenHolders(ctx context.Context, parameters map[string]interface{}) (
+	*rTypes.CallResponse,
+	*rTypes.Error,
+) {
+	var params tokenHoldersParameters
+	if err := c.parseParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+
+	tokenId, err := domain.EntityIdFromString(params.TokenId)
+	if err != nil {
+		return nil, errors.AddErrorDetails(errors.ErrInvalidCallParameters, "reason", err.Error())
+	}
+
+	var after int64
+	cursor, rErr := c.parseCursor(params.Cursor)
+	if rErr != nil {
+		return nil, rErr
+	} else if cursor != nil {
+		after = cursor.Position
+	}
+
+	limit := defaultTokenHoldersLimit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+
+	minBalance := int64(1)
+	if params.MinBalance != nil {
+		minBalance = *params.MinBalance
+	}
+
+	token, rErr := c.tokenRepo.Find(ctx, tokenId.EncodedId)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	if token.Type != domain.TokenTypeFungibleCommon {
+		return nil, errors.AddErrorDetails(errors.ErrInvalidToken, "reason", "Token is not fungible")
+	}
+
+	timestamp, holders, rErr := c.tokenRepo.FindHolders(ctx, tokenId.EncodedId, minBalance, after, limit)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	result := map[string]interface{}{"consensus_timestamp": timestamp}
+	holderList := make([]map[string]interface{}, 0, len(holders))
+	for _, holder := range holders {
+		holderList = append(holderList, map[string]interface{}{
+			"account_identifier": types.NewAccountIdFromEntityId(holder.AccountId).ToRosetta(),
+			"balance":            types.NewTokenAmount(token.Token, holder.Balance).ToRosetta(),
+		})
+	}
+	result["holders"] = holderList
+	if len(holders) == limit {
+		result["next"] = types.NewCursor(holders[len(holders)-1].AccountId.EncodedId, "").Encode()
+	}
+
+	return &rTypes.CallResponse{Result: result, Idempotent: false}, nil
+}
+
+// topicMessage returns the HCS message with the chunk of the sequence number in the topic. For a chunked message, the
+// complete message is reassembled from all the chunks sharing the initial transaction id, and the running hash of each
+// chunk is verified against the running hash of the previous message in the topic
+func (c *callAPIService) topicMessage(ctx context.Context, parameters map[string]interface{}) (
+	*rTypes.CallResponse,
+	*rTypes.Error,
+) {
+	var params topicMessageParameters
+	if err := c.parseParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+
+	topicId, err := domain.EntityIdFromString(params.TopicId)
+	if err != nil {
+		return nil, errors.AddErrorDetails(errors.ErrInvalidCallParameters, "reason", err.Error())
+	}
+
+	topicMessage, rErr := c.topicMessageRepo.FindBySequenceNumber(ctx, topicId.EncodedId, *params.SequenceNumber)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	// the result is not idempotent until all chunks of the message have reached consensus
+	return &rTypes.CallResponse{Result: topicMessage.ToMetadata(), Idempotent: topicMessage.IsComplete()}, nil
+}
+
+// transactionStatistics returns the number of transactions per type and result in the timestamp range [start, end]
+// with one aggregated query, for network analytics without exporting all blocks. The range can span at most 31 days
+func (c *callAPIService) transactionStatistics(ctx context.Context, parameters map[string]interface{}) (
+	*rTypes.CallResponse,
+	*rTypes.Error,
+) {
+	var params transactionStatisticsParameters
+	if err := c.parseParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+
+	start, end := *params.Start, *params.End
+	if start > end {
+		return nil, errors.AddErrorDetails(errors.ErrInvalidCallParameters, "reason", "start must not be after end")
+	}
+	if end-start > maxStatisticsRange {
+		return nil, errors.AddErrorDetails(
+			errors.ErrInvalidCallParameters,
+			"reason",
+			fmt.Sprintf("The range must not exceed %d nanoseconds", maxStatisticsRange),
+		)
+	}
+
+	latest, rErr := c.RetrieveLatest(ctx)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	statistics, rErr := c.CountByTypeAndResult(ctx, start, end)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	// the statistics of a range ending after the latest block grow as more transactions are imported
+	return &rTypes.CallResponse{
+		Result:     statistics.ToMetadata(),
+		Idempotent: end <= latest.ConsensusEndNanos,
+	}, nil
+}
+
+// verifyBlock recomputes the hash of the block from the raw bytes of its record file and compares it with the stored
+// hash, to catch the corruption of the imported record file
+func (c *callAPIService) verifyBlock(ctx context.Context, parameters map[string]interface{}) (
+	*rTypes.CallResponse,
+	*rTypes.Error,
+) {
+	var params verifyBlockParameters
+	if err := c.parseParameters(parameters, &params); err != nil {
+		return nil, err
+	}
+
+	block, err := c.RetrieveBlock(ctx, &rTypes.PartialBlockIdentifier{Hash: params.Hash, Index: params.Index})
+	if err != nil {
+		return nil, err
+	}
+
+	recordFile, err := c.FindRecordFileByIndex(ctx, block.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(recordFile.Bytes) == 0 {
+		return nil, errors.AddErrorDetails(errors.ErrRecordFileNotAvailable, "name", recordFile.Name)
+	}
+
+	result := recordFile.Verify()
+	result["block_identifier"] = block.GetRosettaBlockIdentifier()
+	return &rTypes.CallResponse{
+		Result:     result,
+		Idempotent: true,
+	}, nil
+}
+
+// getAliasAndEvmAddress returns the alias of the hex encoded public key, and the evm address derived from the key
+// if it's an ECDSA secp256k1 key
+func getAliasAndEvmAddress(publicKey string) ([]byte, []byte, error) {
+	key, err := hedera.PublicKeyFromString(tools.SafeRemoveHexPrefix(publicKey))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	alias, curveType, err := types.PublicKey{PublicKey: key}.ToAlias()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if curveType != rTypes.Secp256k1 {
+		return alias, nil, nil
+	}
+
+	evmAddress, err := hex.DecodeString(key.ToEthereumAddress())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return alias, evmAddress, nil
+}
+
+func newPayoutOperation(index int, address string, amount types.Amount) *rTypes.Operation {
+	return &rTypes.Operation{
+		OperationIdentifier: &rTypes.OperationIdentifier{Index: int64(index)},
+		Type:                types.OperationTypeCryptoTransfer,
+		Account:             &rTypes.AccountIdentifier{Address: address},
+		Amount:              amount.ToRosetta(),
+	}
+}
+
+func (c *callAPIService) parseParameters(parameters map[string]interface{}, out interface{}) *rTypes.Error {
+	data, err := json.Marshal(parameters)
+	if err != nil {
+		return errors.ErrInvalidCallParameters
+	}
+
+	if err = json.Unmarshal(data, out); err != nil {
+		log.Errorf("Failed to unmarshal call parameters: %s", err)
+		return errors.AddErrorDetails(errors.ErrInvalidCallParameters, "reason", err.Error())
+	}
+
+	if err = c.validate.Struct(out); err != nil {
+		log.Errorf("Failed to validate call parameters: %s", err)
+		return errors.AddErrorDetails(errors.ErrInvalidCallParameters, "reason", err.Error())
+	}
+
+	return nil
+}
+
+// parseCursor decodes the cursor parameter of a paginated method, nil if the parameter is not set
+func (c *callAPIService) parseCursor(encoded *string) (*types.Cursor, *rTypes.Error) {
+	if encoded == nil {
+		return nil, nil
+	}
+
+	cursor, err := types.NewCursorFromString(*encoded, c.cursorTtl)
+	if err != nil {
+		return nil, errors.AddErrorDetails(errors.ErrInvalidCallParameters, "reason", err.Error())
+	}
+
+	return cursor, nil
+}
+
+// NewCallAPIService creates a new instance of a callAPIService.
+func NewCallAPIService(
+	baseService BaseService,
+	accountRepo interfaces.AccountRepository,
+	constructionAPIService server.ConstructionAPIServicer,
+	scheduleRepo interfaces.ScheduleRepository,
+	stakingRepo interfaces.StakingRepository,
+	submissionWatcher *SubmissionWatcher,
+	tokenRepo interfaces.TokenRepository,
+	topicMessageRepo interfaces.TopicMessageRepository,
+	systemShard int64,
+	systemRealm int64,
+	cursorTtl time.Duration,
+) server.CallAPIServicer {
+	service := &callAPIService{
+		BaseService:            baseService,
+		accountRepo:            accountRepo,
+		constructionAPIService: constructionAPIService,
+		cursorTtl:              cursorTtl,
+		scheduleRepo:           scheduleRepo,
+		stakingRepo:            stakingRepo,
+		submissionWatcher:      submissionWatcher,
+		systemShard:            systemShard,
+		systemRealm:            systemRealm,
+		tokenRepo:              tokenRepo,
+		topicMessageRepo:       topicMessageRepo,
+		validate:               validator.New(),
+	}
+	service.handlers = map[string]callHandler{
+		types.CallMethodAccountBalances:        service.accountBalances,
+		types.CallMethodAliasAccounts:          service.aliasAccounts,
+		types.CallMethodBalanceReconciliation:  service.balanceReconciliation,
+		types.CallMethodBlockByTimestamp:       service.blockByTimestamp,
+		types.CallMethodBlockTransactionCount:  service.blockTransactionCount,
+		types.CallMethodDecodedTransaction:     service.decodedTransaction,
+		types.CallMethodEntityStake:            service.entityStake,
+		types.CallMethodNftInfo:                service.nftInfo,
+		types.CallMethodNftSerials:             service.nftSerials,
+		types.CallMethodNodeStakes:             service.nodeStakes,
+		types.CallMethodPayoutTransactions:     service.payoutTransactions,
+		types.CallMethodPendingAirdrops:        service.pendingAirdrops,
+		types.CallMethodPreviewTransaction:     service.previewTransaction,
+		types.CallMethodRebroadcastTransaction: service.rebroadcastTransaction,
+		types.CallMethodScheduleInfo:           service.scheduleInfo,
+		types.CallMethodStakingRewardHistory:   service.stakingRewardHistory,
+		types.CallMethodSubmissionStatus:       service.submissionStatus,
+		types.CallMethodTokenBalances:          service.tokenBalances,
+		types.CallMethodTokenHolders:           service.tokenHolders,
+		types.CallMethodTopicMessage:           service.topicMessage,
+		types.CallMethodTransactionStatistics:  service.transactionStatistics,
+		types.CallMethodVerifyBlock:            service.verifyBlock,
+	}
+	return service
+}
@@ -0,0 +1,2410 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/server"
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/services/construction"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/mocks"
+	"github.com/hashgraph/hedera-protobufs-go/services"
+	"github.com/hashgraph/hedera-sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/thanhpk/randstr"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	cursorTtl        = time.Minute
+	recordFileV1Hash = "333d6940254659533fd6b939033e59c57fe8f4ff78375d1e687c032918aa0b7b8179c7fd403754274a8c91e0b6c0195a"
+	recordFileV1Name = "2019-07-01T14_13_00.317763Z.rcd"
+)
+
+func TestCallServiceSuite(t *testing.T) {
+	suite.Run(t, new(callServiceSuite))
+}
+
+type callServiceSuite struct {
+	suite.Suite
+	callService          server.CallAPIServicer
+	constructionService  server.ConstructionAPIServicer
+	mockAccountRepo      *mocks.MockAccountRepository
+	mockBlockRepo        *mocks.MockBlockRepository
+	mockScheduleRepo     *mocks.MockScheduleRepository
+	mockStakingRepo      *mocks.MockStakingRepository
+	mockTokenRepo        *mocks.MockTokenRepository
+	mockTopicMessageRepo *mocks.MockTopicMessageRepository
+	mockTransactionRepo  *mocks.MockTransactionRepository
+	submissionWatcher    *SubmissionWatcher
+}
+
+func (suite *callServiceSuite) SetupTest() {
+	suite.mockAccountRepo = &mocks.MockAccountRepository{}
+	suite.mockBlockRepo = &mocks.MockBlockRepository{}
+	suite.mockScheduleRepo = &mocks.MockScheduleRepository{}
+	suite.mockStakingRepo = &mocks.MockStakingRepository{}
+	suite.mockTokenRepo = &mocks.MockTokenRepository{}
+	suite.mockTopicMessageRepo = &mocks.MockTopicMessageRepository{}
+	suite.mockTransactionRepo = &mocks.MockTransactionRepository{}
+
+	baseService := NewOnlineBaseService(suite.mockBlockRepo, suite.mockTransactionRepo)
+	suite.submissionWatcher = NewSubmissionWatcher(baseService, config.SubmissionWatcher{MaxSize: 10})
+	suite.constructionService, _ = NewConstructionAPIService(
+		nil,
+		baseService,
+		nil,
+		defaultNetwork,
+		singleNode,
+		config.Submit{},
+		0,
+		0,
+		construction.NewTransactionConstructor(),
+		nil,
+		suite.submissionWatcher,
+		nil,
+	)
+	suite.callService = NewCallAPIService(
+		baseService,
+		suite.mockAccountRepo,
+		suite.constructionService,
+		suite.mockScheduleRepo,
+		suite.mockStakingRepo,
+		suite.submissionWatcher,
+		suite.mockTokenRepo,
+		suite.mockTopicMessageRepo,
+		0,
+		0,
+		cursorTtl,
+	)
+}
+
+func (suite *callServiceSuite) TestCallOffline() {
+	// given
+	callService := NewCallAPIService(NewOfflineBaseService(), nil, nil, nil, nil, nil, nil, nil, 0, 0, cursorTtl)
+
+	// when
+	actual, err := callService.Call(defaultContext, callRequest(types.CallMethodBlockTransactionCount, nil))
+
+	// then
+	assert.Equal(suite.T(), errors.ErrEndpointNotSupportedInOfflineMode, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *callServiceSuite) TestCallUnsupportedMethod() {
+	// when
+	actual, err := suite.callService.Call(defaultContext, callRequest("foobar", nil))
+
+	// then
+	assert.Equal(suite.T(), errors.ErrCallMethodUnsupported, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *callServiceSuite) TestAccountBalances() {
+	// given
+	suite.mockBlockRepo.On("FindByIndex").Return(block(), mocks.NilError)
+	suite.mockAccountRepo.On("RetrieveHbarBalancesAtBlock").Return(
+		map[int64]types.HbarAmount{1001: {Value: 100}},
+		mocks.NilError,
+	)
+	expected := &rTypes.CallResponse{
+		Result: map[string]interface{}{
+			"balances": []map[string]interface{}{
+				{
+					"account_identifier": &rTypes.AccountIdentifier{Address: "0.0.1001"},
+					"balance":            (&types.HbarAmount{Value: 100}).ToRosetta(),
+				},
+				{
+					"account_identifier": &rTypes.AccountIdentifier{Address: "0.0.1002"},
+					"balance":            (&types.HbarAmount{}).ToRosetta(),
+				},
+			},
+			"block_identifier": block().GetRosettaBlockIdentifier(),
+		},
+		Idempotent: true,
+	}
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodAccountBalances, map[string]interface{}{
+			"account_ids": []string{"0.0.1001", "0.0.1002"},
+			"index":       1,
+		}),
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+	suite.mockAccountRepo.AssertExpectations(suite.T())
+}
+
+func (suite *callServiceSuite) TestAccountBalancesLatestBlock() {
+	// given
+	suite.mockBlockRepo.On("RetrieveLatest").Return(block(), mocks.NilError)
+	suite.mockAccountRepo.On("RetrieveHbarBalancesAtBlock").Return(map[int64]types.HbarAmount{}, mocks.NilError)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodAccountBalances, map[string]interface{}{"account_ids": []string{"0.0.1001"}}),
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.False(suite.T(), actual.Idempotent)
+	suite.mockBlockRepo.AssertNotCalled(suite.T(), "FindByIndex")
+}
+
+func (suite *callServiceSuite) TestAccountBalancesInvalidParameters() {
+	tooManyAccountIds := make([]string, 1001)
+	for i := range tooManyAccountIds {
+		tooManyAccountIds[i] = "0.0.1001"
+	}
+	tests := []struct {
+		name       string
+		parameters map[string]interface{}
+	}{
+		{name: "missing account_ids", parameters: map[string]interface{}{}},
+		{name: "empty account_ids", parameters: map[string]interface{}{"account_ids": []string{}}},
+		{name: "too many account_ids", parameters: map[string]interface{}{"account_ids": tooManyAccountIds}},
+		{name: "empty account id", parameters: map[string]interface{}{"account_ids": []string{""}}},
+		{name: "alias account id", parameters: map[string]interface{}{"account_ids": []string{"0x1234"}}},
+		{
+			name:       "negative index",
+			parameters: map[string]interface{}{"account_ids": []string{"0.0.1001"}, "index": -1},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			// when
+			actual, err := suite.callService.Call(defaultContext, callRequest(types.CallMethodAccountBalances, tt.parameters))
+
+			// then
+			assert.Equal(t, errors.ErrInvalidCallParameters.Code, err.Code)
+			assert.Nil(t, actual)
+		})
+	}
+	suite.mockAccountRepo.AssertNotCalled(suite.T(), "RetrieveHbarBalancesAtBlock")
+}
+
+func (suite *callServiceSuite) TestAccountBalancesDbError() {
+	// given
+	suite.mockBlockRepo.On("FindByIndex").Return(block(), mocks.NilError)
+	suite.mockAccountRepo.On("RetrieveHbarBalancesAtBlock").Return(
+		map[int64]types.HbarAmount(nil),
+		errors.ErrDatabaseError,
+	)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodAccountBalances, map[string]interface{}{
+			"account_ids": []string{"0.0.1001"},
+			"index":       1,
+		}),
+	)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *callServiceSuite) TestAliasAccounts() {
+	// given
+	ed25519Key, _ := hedera.PrivateKeyGenerateEd25519()
+	ed25519Alias, _, _ := types.PublicKey{PublicKey: ed25519Key.PublicKey()}.ToAlias()
+	ecdsaKey, _ := hedera.PrivateKeyGenerateEcdsa()
+	ecdsaAlias, _, _ := types.PublicKey{PublicKey: ecdsaKey.PublicKey()}.ToAlias()
+	ecdsaEvmAddress, _ := hex.DecodeString(ecdsaKey.PublicKey().ToEthereumAddress())
+	evmAddress := randstr.Bytes(20)
+	createdTimestamp := int64(100)
+	transactionType := int32(14)
+	accounts := types.AliasAccounts{{
+		AccountId:               domain.MustDecodeEntityId(1001),
+		CreatedTimestamp:        &createdTimestamp,
+		CreationTransactionHash: []byte{0x1, 0xab},
+		CreationTransactionType: &transactionType,
+	}}
+	tests := []struct {
+		name               string
+		parameters         map[string]interface{}
+		expectedAlias      []byte
+		expectedEvmAddress []byte
+	}{
+		{
+			name:          "ed25519 public key",
+			parameters:    map[string]interface{}{"public_key": ed25519Key.PublicKey().StringRaw()},
+			expectedAlias: ed25519Alias,
+		},
+		{
+			name:               "ecdsa public key",
+			parameters:         map[string]interface{}{"public_key": "0x" + ecdsaKey.PublicKey().StringRaw()},
+			expectedAlias:      ecdsaAlias,
+			expectedEvmAddress: ecdsaEvmAddress,
+		},
+		{
+			name:               "evm address",
+			parameters:         map[string]interface{}{"evm_address": "0x" + hex.EncodeToString(evmAddress)},
+			expectedEvmAddress: evmAddress,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			// given
+			suite.SetupTest()
+			suite.mockAccountRepo.On("FindAccountsByAlias", tt.expectedAlias, tt.expectedEvmAddress).
+				Return(accounts, mocks.NilError)
+			expected := &rTypes.CallResponse{Result: accounts.ToMetadata()}
+
+			// when
+			actual, err := suite.callService.Call(
+				defaultContext,
+				callRequest(types.CallMethodAliasAccounts, tt.parameters),
+			)
+
+			// then
+			assert.Nil(t, err)
+			assert.Equal(t, expected, actual)
+			suite.mockAccountRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func (suite *callServiceSuite) TestAliasAccountsInvalidParameters() {
+	ed25519Key, _ := hedera.PrivateKeyGenerateEd25519()
+	tests := []struct {
+		name       string
+		parameters map[string]interface{}
+	}{
+		{name: "missing public_key and evm_address", parameters: map[string]interface{}{}},
+		{name: "both public_key and evm_address", parameters: map[string]interface{}{
+			"evm_address": "0x" + hex.EncodeToString(randstr.Bytes(20)),
+			"public_key":  ed25519Key.PublicKey().StringRaw(),
+		}},
+		{name: "invalid public_key", parameters: map[string]interface{}{"public_key": "0x1234"}},
+		{name: "short evm_address", parameters: map[string]interface{}{"evm_address": "0x1234"}},
+		{name: "invalid evm_address", parameters: map[string]interface{}{"evm_address": "xyz"}},
+		{name: "wrong type", parameters: map[string]interface{}{"evm_address": 1234}},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			// when
+			actual, err := suite.callService.Call(
+				defaultContext,
+				callRequest(types.CallMethodAliasAccounts, tt.parameters),
+			)
+
+			// then
+			assert.Equal(t, errors.ErrInvalidCallParameters.Code, err.Code)
+			assert.Nil(t, actual)
+		})
+	}
+	suite.mockAccountRepo.AssertNotCalled(suite.T(), "FindAccountsByAlias")
+}
+
+func (suite *callServiceSuite) TestAliasAccountsDbError() {
+	// given
+	suite.mockAccountRepo.On("FindAccountsByAlias", []byte(nil), mock.Anything).
+		Return(types.AliasAccounts(nil), errors.ErrDatabaseError)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodAliasAccounts, map[string]interface{}{
+			"evm_address": hex.EncodeToString(randstr.Bytes(20)),
+		}),
+	)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *callServiceSuite) TestBalanceReconciliation() {
+	tests := []struct {
+		name          string
+		parameters    map[string]interface{}
+		expectedLimit int
+	}{
+		{
+			name:          "default limit",
+			parameters:    map[string]interface{}{"account_id": "0.0.1001", "timestamp": 200},
+			expectedLimit: defaultReconciliationLimit,
+		},
+		{
+			name:          "limit",
+			parameters:    map[string]interface{}{"account_id": "0.0.1001", "limit": 5, "timestamp": 200},
+			expectedLimit: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			// given
+			suite.SetupTest()
+			reconciliation := &types.BalanceReconciliation{
+				AccountId:      domain.MustDecodeEntityId(1001),
+				Nearest:        types.BalanceSnapshot{Balance: 150, Timestamp: 200},
+				Previous:       types.BalanceSnapshot{Balance: 100, Timestamp: 100},
+				TransferChange: 40,
+				Transactions:   []types.ReconciliationTransaction{},
+			}
+			suite.mockAccountRepo.On("RetrieveBalanceReconciliation", int64(1001), int64(200), tt.expectedLimit).
+				Return(reconciliation, mocks.NilError)
+			expected := &rTypes.CallResponse{Result: reconciliation.ToMetadata()}
+
+			// when
+			actual, err := suite.callService.Call(
+				defaultContext,
+				callRequest(types.CallMethodBalanceReconciliation, tt.parameters),
+			)
+
+			// then
+			assert.Nil(t, err)
+			assert.Equal(t, expected, actual)
+			suite.mockAccountRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func (suite *callServiceSuite) TestBalanceReconciliationInvalidParameters() {
+	tests := []struct {
+		name       string
+		parameters map[string]interface{}
+	}{
+		{name: "missing account_id", parameters: map[string]interface{}{"timestamp": 200}},
+		{name: "missing timestamp", parameters: map[string]interface{}{"account_id": "0.0.1001"}},
+		{name: "invalid account_id", parameters: map[string]interface{}{"account_id": "abc", "timestamp": 200}},
+		{name: "negative timestamp", parameters: map[string]interface{}{"account_id": "0.0.1001", "timestamp": -1}},
+		{
+			name:       "limit too large",
+			parameters: map[string]interface{}{"account_id": "0.0.1001", "limit": 101, "timestamp": 200},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			// when
+			actual, err := suite.callService.Call(
+				defaultContext,
+				callRequest(types.CallMethodBalanceReconciliation, tt.parameters),
+			)
+
+			// then
+			assert.Equal(t, errors.ErrInvalidCallParameters.Code, err.Code)
+			assert.Nil(t, actual)
+		})
+	}
+	suite.mockAccountRepo.AssertNotCalled(suite.T(), "RetrieveBalanceReconciliation")
+}
+
+func (suite *callServiceSuite) TestBalanceReconciliationSnapshotNotFound() {
+	// given
+	suite.mockAccountRepo.On("RetrieveBalanceReconciliation", mock.Anything, mock.Anything, mock.Anything).
+		Return((*types.BalanceReconciliation)(nil), errors.ErrBalanceSnapshotNotFound)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodBalanceReconciliation, map[string]interface{}{
+			"account_id": "0.0.1001",
+			"timestamp":  200,
+		}),
+	)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrBalanceSnapshotNotFound, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *callServiceSuite) TestBlockByTimestamp() {
+	// given
+	timestamp := block().ConsensusStartNanos + 1
+	suite.mockBlockRepo.On("FindByTimestamp", timestamp).Return(block(), mocks.NilError)
+	expected := &rTypes.CallResponse{
+		Result: map[string]interface{}{
+			"block_identifier": block().GetRosettaBlockIdentifier(),
+			"consensus_end":    block().ConsensusEndNanos,
+			"consensus_start":  block().ConsensusStartNanos,
+		},
+		Idempotent: true,
+	}
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodBlockByTimestamp, map[string]interface{}{"timestamp": timestamp}),
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+	suite.mockBlockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *callServiceSuite) TestBlockByTimestampInvalidParameters() {
+	tests := []struct {
+		name       string
+		parameters map[string]interface{}
+	}{
+		{name: "missing timestamp", parameters: map[string]interface{}{}},
+		{name: "negative timestamp", parameters: map[string]interface{}{"timestamp": -1}},
+		{name: "invalid timestamp", parameters: map[string]interface{}{"timestamp": "abc"}},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			// when
+			actual, err := suite.callService.Call(defaultContext, callRequest(types.CallMethodBlockByTimestamp, tt.parameters))
+
+			// then
+			assert.Equal(t, errors.ErrInvalidCallParameters.Code, err.Code)
+			assert.Nil(t, actual)
+		})
+	}
+	suite.mockBlockRepo.AssertNotCalled(suite.T(), "FindByTimestamp", mock.Anything)
+}
+
+func (suite *callServiceSuite) TestBlockByTimestampBlockNotFound() {
+	// given
+	suite.mockBlockRepo.On("FindByTimestamp", int64(100)).Return(mocks.NilBlock, errors.ErrBlockNotFound)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodBlockByTimestamp, map[string]interface{}{"timestamp": 100}),
+	)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrBlockNotFound, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *callServiceSuite) TestBlockTransactionCount() {
+	// given
+	suite.mockBlockRepo.On("FindByIndex").Return(block(), mocks.NilError)
+	suite.mockTransactionRepo.On("CountBetween").Return(int64(5), int64(20), mocks.NilError)
+	expected := &rTypes.CallResponse{
+		Result: map[string]interface{}{
+			"block_identifier":  block().GetRosettaBlockIdentifier(),
+			"operation_count":   int64(20),
+			"transaction_count": int64(5),
+		},
+		Idempotent: true,
+	}
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodBlockTransactionCount, map[string]interface{}{"index": 1}),
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+	suite.mockBlockRepo.AssertExpectations(suite.T())
+	suite.mockTransactionRepo.AssertExpectations(suite.T())
+}
+
+func (suite *callServiceSuite) TestBlockTransactionCountWithHash() {
+	// given
+	suite.mockBlockRepo.On("FindByIdentifier").Return(block(), mocks.NilError)
+	suite.mockTransactionRepo.On("CountBetween").Return(int64(5), int64(20), mocks.NilError)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodBlockTransactionCount, map[string]interface{}{"hash": "0x12345", "index": 1}),
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.NotNil(suite.T(), actual)
+	suite.mockBlockRepo.AssertNotCalled(suite.T(), "FindByIndex")
+}
+
+func (suite *callServiceSuite) TestBlockTransactionCountInvalidParameters() {
+	tests := []struct {
+		name       string
+		parameters map[string]interface{}
+	}{
+		{name: "missing index", parameters: map[string]interface{}{}},
+		{name: "negative index", parameters: map[string]interface{}{"index": -1}},
+		{name: "invalid index", parameters: map[string]interface{}{"index": "abc"}},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			// when
+			actual, err := suite.callService.Call(
+				defaultContext,
+				callRequest(types.CallMethodBlockTransactionCount, tt.parameters),
+			)
+
+			// then
+			assert.Equal(t, errors.ErrInvalidCallParameters.Code, err.Code)
+			assert.Nil(t, actual)
+		})
+	}
+}
+
+func (suite *callServiceSuite) TestBlockTransactionCountBlockNotFound() {
+	// given
+	suite.mockBlockRepo.On("FindByIndex").Return(mocks.NilBlock, errors.ErrBlockNotFound)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodBlockTransactionCount, map[string]interface{}{"index": 1}),
+	)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrBlockNotFound, err)
+	assert.Nil(suite.T(), actual)
+	suite.mockTransactionRepo.AssertNotCalled(suite.T(), "CountBetween")
+}
+
+func (suite *callServiceSuite) TestBlockTransactionCountDbError() {
+	// given
+	suite.mockBlockRepo.On("FindByIndex").Return(block(), mocks.NilError)
+	suite.mockTransactionRepo.On("CountBetween").Return(int64(0), int64(0), errors.ErrDatabaseError)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodBlockTransactionCount, map[string]interface{}{"index": 1}),
+	)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *callServiceSuite) TestDecodedTransaction() {
+	// given
+	bodyBytes, _ := proto.Marshal(&services.TransactionBody{Memo: "foobar"})
+	signedTransactionBytes, _ := proto.Marshal(&services.SignedTransaction{BodyBytes: bodyBytes})
+	transactionBytes, _ := proto.Marshal(&services.Transaction{SignedTransactionBytes: signedTransactionBytes})
+	rawTransaction := &types.RawTransaction{Transaction: domain.Transaction{
+		ConsensusTimestamp: 1500000,
+		PayerAccountId:     domain.MustDecodeEntityId(1001),
+		Result:             22,
+		TransactionBytes:   transactionBytes,
+		TransactionHash:    []byte{1, 2, 3},
+	}}
+	suite.mockBlockRepo.On("FindByIndex").Return(block(), mocks.NilError)
+	suite.mockTransactionRepo.On("FindRawByHashInBlock").Return(rawTransaction, mocks.NilError)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodDecodedTransaction, map[string]interface{}{
+			"index":            1,
+			"transaction_hash": "0x010203",
+		}),
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.True(suite.T(), actual.Idempotent)
+	assert.Equal(suite.T(), block().GetRosettaBlockIdentifier(), actual.Result["block_identifier"])
+	assert.Equal(suite.T(), int64(1500000), actual.Result["consensus_timestamp"])
+	assert.Equal(suite.T(), map[string]interface{}{"memo": "foobar"}, actual.Result["transaction_body"])
+	assert.Contains(suite.T(), actual.Result, "transaction_record")
+	suite.mockTransactionRepo.AssertExpectations(suite.T())
+}
+
+func (suite *callServiceSuite) TestDecodedTransactionInvalidParameters() {
+	tests := []struct {
+		name       string
+		parameters map[string]interface{}
+	}{
+		{name: "missing index", parameters: map[string]interface{}{"transaction_hash": "0x010203"}},
+		{name: "missing transaction hash", parameters: map[string]interface{}{"index": 1}},
+		{name: "negative index", parameters: map[string]interface{}{"index": -1, "transaction_hash": "0x010203"}},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			// when
+			actual, err := suite.callService.Call(
+				defaultContext,
+				callRequest(types.CallMethodDecodedTransaction, tt.parameters),
+			)
+
+			// then
+			assert.Equal(t, errors.ErrInvalidCallParameters.Code, err.Code)
+			assert.Nil(t, actual)
+		})
+	}
+}
+
+func (suite *callServiceSuite) TestDecodedTransactionNotFound() {
+	// given
+	suite.mockBlockRepo.On("FindByIndex").Return(block(), mocks.NilError)
+	suite.mockTransactionRepo.On("FindRawByHashInBlock").Return(mocks.NilRawTransaction, errors.ErrTransactionNotFound)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodDecodedTransaction, map[string]interface{}{
+			"index":            1,
+			"transaction_hash": "0x010203",
+		}),
+	)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrTransactionNotFound, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *callServiceSuite) TestDecodedTransactionDecodeFailed() {
+	// given
+	rawTransaction := &types.RawTransaction{Transaction: domain.Transaction{TransactionBytes: []byte{0xff, 0xff}}}
+	suite.mockBlockRepo.On("FindByIndex").Return(block(), mocks.NilError)
+	suite.mockTransactionRepo.On("FindRawByHashInBlock").Return(rawTransaction, mocks.NilError)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodDecodedTransaction, map[string]interface{}{
+			"index":            1,
+			"transaction_hash": "0x010203",
+		}),
+	)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrTransactionDecodeFailed.Code, err.Code)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *callServiceSuite) TestNodeStakes() {
+	// given
+	nodeStakes := &types.NodeStakes{
+		EpochDay:      19200,
+		Nodes:         []domain.NodeStake{{EpochDay: 19200, NodeId: 3, RewardRate: 10, Stake: 2000}},
+		StakingPeriod: 100,
+	}
+	tests := []struct {
+		name       string
+		parameters map[string]interface{}
+	}{
+		{name: "latest", parameters: map[string]interface{}{}},
+		{name: "epoch_day", parameters: map[string]interface{}{"epoch_day": 19200}},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			// given
+			suite.SetupTest()
+			suite.mockStakingRepo.On("FindNodeStakes").Return(nodeStakes, mocks.NilError)
+			expected := &rTypes.CallResponse{Result: nodeStakes.ToMetadata(), Idempotent: false}
+
+			// when
+			actual, err := suite.callService.Call(
+				defaultContext,
+				callRequest(types.CallMethodNodeStakes, tt.parameters),
+			)
+
+			// then
+			assert.Nil(t, err)
+			assert.Equal(t, expected, actual)
+			suite.mockStakingRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func (suite *callServiceSuite) TestNodeStakesInvalidParameters() {
+	tests := []struct {
+		name       string
+		parameters map[string]interface{}
+	}{
+		{name: "negative epoch_day", parameters: map[string]interface{}{"epoch_day": -1}},
+		{name: "wrong type", parameters: map[string]interface{}{"epoch_day": "19200"}},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			// when
+			actual, err := suite.callService.Call(
+				defaultContext,
+				callRequest(types.CallMethodNodeStakes, tt.parameters),
+			)
+
+			// then
+			assert.Equal(t, errors.ErrInvalidCallParameters.Code, err.Code)
+			assert.Nil(t, actual)
+		})
+	}
+	suite.mockStakingRepo.AssertNotCalled(suite.T(), "FindNodeStakes")
+}
+
+func (suite *callServiceSuite) TestNodeStakesNotFound() {
+	// given
+	suite.mockStakingRepo.On("FindNodeStakes").Return(mocks.NilNodeStakes, errors.ErrNodeStakeNotFound)
+
+	// when
+	actual, err := suite.callService.Call(defaultContext, callRequest(types.CallMethodNodeStakes, nil))
+
+	// then
+	assert.Equal(suite.T(), errors.ErrNodeStakeNotFound, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *callServiceSuite) TestPayoutTransactions() {
+	// given
+	receivers := make([]map[string]interface{}, 0)
+	for i := 1; i <= 10; i++ {
+		receivers = append(receivers, map[string]interface{}{
+			"account_id": fmt.Sprintf("0.0.%d", 2000+i),
+			"amount":     fmt.Sprintf("%d", i*100),
+		})
+	}
+	validStartNanos := int64(1659000000000000000)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodPayoutTransactions, map[string]interface{}{
+			"receivers":         receivers,
+			"sender":            "0.0.1001",
+			"valid_duration":    120,
+			"valid_start_nanos": validStartNanos,
+		}),
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.False(suite.T(), actual.Idempotent)
+	transactions := actual.Result["transactions"].([]*rTypes.ConstructionPayloadsResponse)
+	assert.Len(suite.T(), transactions, 2)
+	expectedSenderAmounts := []string{"-4500", "-1000"}
+	expectedOperationCounts := []int{10, 2}
+	for i, transaction := range transactions {
+		parsed, rErr := suite.constructionService.ConstructionParse(
+			defaultContext,
+			&rTypes.ConstructionParseRequest{Transaction: transaction.UnsignedTransaction},
+		)
+		assert.Nil(suite.T(), rErr)
+		assert.Len(suite.T(), parsed.Operations, expectedOperationCounts[i])
+		for _, operation := range parsed.Operations {
+			assert.Equal(suite.T(), types.CurrencyHbar, operation.Amount.Currency)
+			if operation.Account.Address == "0.0.1001" {
+				assert.Equal(suite.T(), expectedSenderAmounts[i], operation.Amount.Value)
+			}
+		}
+		assert.Len(suite.T(), transaction.Payloads, 1)
+		assert.Equal(suite.T(), "0.0.1001", transaction.Payloads[0].AccountIdentifier.Address)
+
+		sdkTransaction, rErr := unmarshallTransactionFromHexString(transaction.UnsignedTransaction)
+		assert.Nil(suite.T(), rErr)
+		validStart := sdkTransaction.GetTransactionID().ValidStart
+		assert.Equal(suite.T(), validStartNanos+int64(i), validStart.UnixNano())
+	}
+}
+
+func (suite *callServiceSuite) TestPayoutTransactionsToken() {
+	// given
+	suite.mockTokenRepo.On("Find").Return(fungibleToken(), mocks.NilError)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodPayoutTransactions, map[string]interface{}{
+			"max_receivers": 2,
+			"receivers": []map[string]interface{}{
+				{"account_id": "0.0.2001", "amount": "10"},
+				{"account_id": "0.0.2002", "amount": "20"},
+				{"account_id": "0.0.2003", "amount": "30"},
+			},
+			"sender":   "0.0.1001",
+			"token_id": "0.0.2001",
+		}),
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	transactions := actual.Result["transactions"].([]*rTypes.ConstructionPayloadsResponse)
+	assert.Len(suite.T(), transactions, 2)
+	expectedCurrency := types.NewTokenAmount(fungibleToken().Token, 0).ToRosetta().Currency
+	for _, transaction := range transactions {
+		parsed, rErr := suite.constructionService.ConstructionParse(
+			defaultContext,
+			&rTypes.ConstructionParseRequest{Transaction: transaction.UnsignedTransaction},
+		)
+		assert.Nil(suite.T(), rErr)
+		for _, operation := range parsed.Operations {
+			assert.Equal(suite.T(), expectedCurrency, operation.Amount.Currency)
+		}
+	}
+	suite.mockTokenRepo.AssertExpectations(suite.T())
+}
+
+func (suite *callServiceSuite) TestPayoutTransactionsNonFungibleToken() {
+	// given
+	token := fungibleToken()
+	token.Type = domain.TokenTypeNonFungibleUnique
+	suite.mockTokenRepo.On("Find").Return(token, mocks.NilError)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodPayoutTransactions, map[string]interface{}{
+			"receivers": []map[string]interface{}{{"account_id": "0.0.2001", "amount": "1"}},
+			"sender":    "0.0.1001",
+			"token_id":  "0.0.2001",
+		}),
+	)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrInvalidCallParameters.Code, err.Code)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *callServiceSuite) TestPayoutTransactionsTokenNotFound() {
+	// given
+	suite.mockTokenRepo.On("Find").Return(mocks.NilToken, errors.ErrTokenNotFound)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodPayoutTransactions, map[string]interface{}{
+			"receivers": []map[string]interface{}{{"account_id": "0.0.2001", "amount": "1"}},
+			"sender":    "0.0.1001",
+			"token_id":  "0.0.2001",
+		}),
+	)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrTokenNotFound, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *callServiceSuite) TestPayoutTransactionsInvalidParameters() {
+	receivers := []map[string]interface{}{{"account_id": "0.0.2001", "amount": "1"}}
+	tests := []struct {
+		name       string
+		parameters map[string]interface{}
+	}{
+		{name: "missing sender", parameters: map[string]interface{}{"receivers": receivers}},
+		{name: "missing receivers", parameters: map[string]interface{}{"sender": "0.0.1001"}},
+		{
+			name:       "empty receivers",
+			parameters: map[string]interface{}{"receivers": []interface{}{}, "sender": "0.0.1001"},
+		},
+		{
+			name: "missing receiver account_id",
+			parameters: map[string]interface{}{
+				"receivers": []map[string]interface{}{{"amount": "1"}},
+				"sender":    "0.0.1001",
+			},
+		},
+		{
+			name: "invalid amount",
+			parameters: map[string]interface{}{
+				"receivers": []map[string]interface{}{{"account_id": "0.0.2001", "amount": "abc"}},
+				"sender":    "0.0.1001",
+			},
+		},
+		{
+			name: "zero amount",
+			parameters: map[string]interface{}{
+				"receivers": []map[string]interface{}{{"account_id": "0.0.2001", "amount": "0"}},
+				"sender":    "0.0.1001",
+			},
+		},
+		{
+			name: "total amount overflows",
+			parameters: map[string]interface{}{
+				"receivers": []map[string]interface{}{
+					{"account_id": "0.0.2001", "amount": "9223372036854775807"},
+					{"account_id": "0.0.2002", "amount": "1"},
+				},
+				"sender": "0.0.1001",
+			},
+		},
+		{
+			name:       "max_receivers too large",
+			parameters: map[string]interface{}{"max_receivers": 10, "receivers": receivers, "sender": "0.0.1001"},
+		},
+		{
+			name:       "invalid token_id",
+			parameters: map[string]interface{}{"receivers": receivers, "sender": "0.0.1001", "token_id": "abc"},
+		},
+		{
+			name:       "valid_duration too large",
+			parameters: map[string]interface{}{"receivers": receivers, "sender": "0.0.1001", "valid_duration": 181},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			// when
+			actual, err := suite.callService.Call(
+				defaultContext,
+				callRequest(types.CallMethodPayoutTransactions, tt.parameters),
+			)
+
+			// then
+			assert.Equal(t, errors.ErrInvalidCallParameters.Code, err.Code)
+			assert.Nil(t, actual)
+		})
+	}
+	suite.mockTokenRepo.AssertNotCalled(suite.T(), "Find")
+}
+
+func (suite *callServiceSuite) TestPayoutTransactionsInvalidSender() {
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodPayoutTransactions, map[string]interface{}{
+			"receivers": []map[string]interface{}{{"account_id": "0.0.2001", "amount": "1"}},
+			"sender":    "foobar",
+		}),
+	)
+
+	// then
+	assert.NotNil(suite.T(), err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *callServiceSuite) TestPendingAirdrops() {
+	// given
+	airdrops := []types.PendingAirdrop{
+		{
+			Amount:            100,
+			ReceiverAccountId: domain.MustDecodeEntityId(1001),
+			SenderAccountId:   domain.MustDecodeEntityId(1002),
+			State:             types.AirdropStatePending,
+			Timestamp:         200,
+			Token: domain.Token{
+				Decimals: 2,
+				TokenId:  domain.MustDecodeEntityId(2001),
+				Type:     domain.TokenTypeFungibleCommon,
+			},
+		},
+	}
+	suite.mockTokenRepo.On("FindPendingAirdrops", int64(1001), 10).Return(airdrops, mocks.NilError)
+	expected := &rTypes.CallResponse{
+		Result: map[string]interface{}{
+			"pending_airdrops": []map[string]interface{}{airdrops[0].ToMetadata()},
+		},
+		Idempotent: false,
+	}
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodPendingAirdrops, map[string]interface{}{"account_id": "0.0.1001", "limit": 10}),
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+	suite.mockTokenRepo.AssertExpectations(suite.T())
+}
+
+func (suite *callServiceSuite) TestPendingAirdropsDefaultLimit() {
+	// given
+	suite.mockTokenRepo.On("FindPendingAirdrops", int64(1001), defaultPendingAirdropsLimit).
+		Return([]types.PendingAirdrop{}, mocks.NilError)
+	expected := &rTypes.CallResponse{
+		Result:     map[string]interface{}{"pending_airdrops": []map[string]interface{}{}},
+		Idempotent: false,
+	}
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodPendingAirdrops, map[string]interface{}{"account_id": "0.0.1001"}),
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+	suite.mockTokenRepo.AssertExpectations(suite.T())
+}
+
+func (suite *callServiceSuite) TestPendingAirdropsInvalidParameters() {
+	tests := []struct {
+		name       string
+		parameters map[string]interface{}
+	}{
+		{name: "missing account_id", parameters: map[string]interface{}{}},
+		{name: "invalid account_id", parameters: map[string]interface{}{"account_id": "abc"}},
+		{name: "limit too small", parameters: map[string]interface{}{"account_id": "0.0.1001", "limit": 0}},
+		{name: "limit too large", parameters: map[string]interface{}{"account_id": "0.0.1001", "limit": 101}},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			// when
+			actual, err := suite.callService.Call(defaultContext, callRequest(types.CallMethodPendingAirdrops, tt.parameters))
+
+			// then
+			assert.Equal(t, errors.ErrInvalidCallParameters.Code, err.Code)
+			assert.Nil(t, actual)
+		})
+	}
+	suite.mockTokenRepo.AssertNotCalled(suite.T(), "FindPendingAirdrops")
+}
+
+func (suite *callServiceSuite) TestPendingAirdropsDbError() {
+	// given
+	suite.mockTokenRepo.On("FindPendingAirdrops", int64(1001), defaultPendingAirdropsLimit).
+		Return([]types.PendingAirdrop(nil), errors.ErrDatabaseError)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodPendingAirdrops, map[string]interface{}{"account_id": "0.0.1001"}),
+	)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *callServiceSuite) TestPreviewTransaction() {
+	// given
+	token := fungibleToken().Token
+	sender := types.NewAccountIdFromEntityId(domain.MustDecodeEntityId(1001))
+	receiver := types.NewAccountIdFromEntityId(domain.MustDecodeEntityId(1002))
+	operations := types.OperationSlice{
+		{AccountId: sender, Amount: &types.HbarAmount{Value: -300}, Type: types.OperationTypeCryptoTransfer},
+		{AccountId: receiver, Amount: &types.HbarAmount{Value: 300}, Type: types.OperationTypeCryptoTransfer},
+		{AccountId: receiver, Amount: types.NewTokenAmount(token, -10), Type: types.OperationTypeCryptoTransfer},
+		{AccountId: sender, Amount: types.NewTokenAmount(token, 10), Type: types.OperationTypeCryptoTransfer},
+	}
+	payloads, rErr := suite.constructionService.ConstructionPayloads(
+		defaultContext,
+		&rTypes.ConstructionPayloadsRequest{Operations: operations.ToRosetta()},
+	)
+	assert.Nil(suite.T(), rErr)
+	suite.mockBlockRepo.On("RetrieveLatest").Return(block(), mocks.NilError)
+	suite.mockAccountRepo.On("RetrieveBalanceAtBlock").
+		Return(types.AmountSlice{&types.HbarAmount{Value: 1000}}, "", []byte(nil), mocks.NilError).Once()
+	suite.mockAccountRepo.On("RetrieveBalanceAtBlock").
+		Return(
+			types.AmountSlice{&types.HbarAmount{Value: 50}, types.NewTokenAmount(token, 25)},
+			"",
+			[]byte(nil),
+			mocks.NilError,
+		).
+		Once()
+	expected := []map[string]interface{}{
+		{
+			"account_identifier": sender.ToRosetta(),
+			"balances": []map[string]interface{}{
+				{
+					"balance":           "0",
+					"change":            "10",
+					"currency":          types.NewTokenAmount(token, 10).ToRosetta().Currency,
+					"projected_balance": "10",
+				},
+				{
+					"balance":           "1000",
+					"change":            "-300",
+					"currency":          types.CurrencyHbar,
+					"projected_balance": "700",
+				},
+			},
+		},
+		{
+			"account_identifier": receiver.ToRosetta(),
+			"balances": []map[string]interface{}{
+				{
+					"balance":           "25",
+					"change":            "-10",
+					"currency":          types.NewTokenAmount(token, -10).ToRosetta().Currency,
+					"projected_balance": "15",
+				},
+				{
+					"balance":           "50",
+					"change":            "300",
+					"currency":          types.CurrencyHbar,
+					"projected_balance": "350",
+				},
+			},
+		},
+	}
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodPreviewTransaction, map[string]interface{}{
+			"unsigned_transaction": payloads.UnsignedTransaction,
+		}),
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.False(suite.T(), actual.Idempotent)
+	assert.Equal(suite.T(), expected, actual.Result["accounts"])
+	assert.Equal(suite.T(), block().GetRosettaBlockIdentifier(), actual.Result["block_identifier"])
+	assert.Len(suite.T(), actual.Result["operations"], 4)
+	suite.mockAccountRepo.AssertNumberOfCalls(suite.T(), "RetrieveBalanceAtBlock", 2)
+}
+
+func (suite *callServiceSuite) TestPreviewTransactionInvalidParameters() {
+	for _, parameters := range []map[string]interface{}{
+		nil,
+		{"unsigned_transaction": ""},
+		{"unsigned_transaction": 1},
+	} {
+		// when
+		actual, err := suite.callService.Call(
+			defaultContext,
+			callRequest(types.CallMethodPreviewTransaction, parameters),
+		)
+
+		// then
+		assert.Equal(suite.T(), errors.ErrInvalidCallParameters.Code, err.Code)
+		assert.Nil(suite.T(), actual)
+	}
+}
+
+func (suite *callServiceSuite) TestPreviewTransactionInvalidTransaction() {
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodPreviewTransaction, map[string]interface{}{"unsigned_transaction": "0x6767"}),
+	)
+
+	// then
+	assert.NotNil(suite.T(), err)
+	assert.Nil(suite.T(), actual)
+	suite.mockBlockRepo.AssertNotCalled(suite.T(), "RetrieveLatest")
+}
+
+func (suite *callServiceSuite) TestPreviewTransactionDbError() {
+	// given
+	operations := types.OperationSlice{
+		getOperation(0, types.OperationTypeCryptoTransfer, defaultCryptoAccountId1, defaultSendAmount),
+		getOperation(1, types.OperationTypeCryptoTransfer, defaultCryptoAccountId2, defaultReceiveAmount),
+	}
+	payloads, rErr := suite.constructionService.ConstructionPayloads(
+		defaultContext,
+		&rTypes.ConstructionPayloadsRequest{Operations: operations.ToRosetta()},
+	)
+	assert.Nil(suite.T(), rErr)
+	suite.mockBlockRepo.On("RetrieveLatest").Return(block(), mocks.NilError)
+	suite.mockAccountRepo.On("RetrieveBalanceAtBlock").
+		Return(types.AmountSlice{}, "", []byte(nil), errors.ErrDatabaseError)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodPreviewTransaction, map[string]interface{}{
+			"unsigned_transaction": payloads.UnsignedTransaction,
+		}),
+	)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *callServiceSuite) TestRebroadcastTransactionConfirmed() {
+	// given
+	transaction := suite.rebroadcastTransaction(time.Now().Add(-time.Hour))
+	rawTransaction := &types.RawTransaction{Transaction: domain.Transaction{ConsensusTimestamp: 1000}}
+	suite.mockTransactionRepo.On("FindRawByHashInBlock").Return(rawTransaction, mocks.NilError)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodRebroadcastTransaction, map[string]interface{}{"signed_transaction": transaction}),
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.True(suite.T(), actual.Idempotent)
+	assert.Equal(suite.T(), int64(1000), actual.Result["consensus_timestamp"])
+	assert.NotEmpty(suite.T(), actual.Result["hash"])
+	assert.Equal(suite.T(), rebroadcastStatusConfirmed, actual.Result["status"])
+	suite.mockBlockRepo.AssertNotCalled(suite.T(), "RetrieveLatest")
+}
+
+func (suite *callServiceSuite) TestRebroadcastTransactionExpired() {
+	// given
+	transaction := suite.rebroadcastTransaction(time.Now().Add(-time.Hour))
+	latest := block()
+	latest.ConsensusEndNanos = time.Now().UnixNano()
+	suite.mockTransactionRepo.On("FindRawByHashInBlock").Return(mocks.NilRawTransaction, errors.ErrTransactionNotFound)
+	suite.mockBlockRepo.On("RetrieveLatest").Return(latest, mocks.NilError)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodRebroadcastTransaction, map[string]interface{}{"signed_transaction": transaction}),
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.False(suite.T(), actual.Idempotent)
+	assert.Nil(suite.T(), actual.Result["consensus_timestamp"])
+	assert.Equal(suite.T(), rebroadcastStatusExpired, actual.Result["status"])
+}
+
+func (suite *callServiceSuite) TestRebroadcastTransactionPending() {
+	// given
+	transaction := suite.rebroadcastTransaction(time.Now().Add(-time.Hour))
+	suite.mockTransactionRepo.On("FindRawByHashInBlock").Return(mocks.NilRawTransaction, errors.ErrTransactionNotFound)
+	suite.mockBlockRepo.On("RetrieveLatest").Return(block(), mocks.NilError)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodRebroadcastTransaction, map[string]interface{}{"signed_transaction": transaction}),
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), rebroadcastStatusPending, actual.Result["status"])
+}
+
+func (suite *callServiceSuite) TestRebroadcastTransactionResubmit() {
+	// given
+	transaction := suite.rebroadcastTransaction(time.Now())
+	suite.mockTransactionRepo.On("FindRawByHashInBlock").Return(mocks.NilRawTransaction, errors.ErrTransactionNotFound)
+	ctx, cancel := context.WithCancel(defaultContext)
+	cancel()
+	expected := errors.AddErrorDetails(errors.ErrTransactionSubmissionFailed, "reason", canceledReason)
+
+	// when
+	actual, err := suite.callService.Call(
+		ctx,
+		callRequest(types.CallMethodRebroadcastTransaction, map[string]interface{}{"signed_transaction": transaction}),
+	)
+
+	// then
+	assert.Equal(suite.T(), expected, err)
+	assert.Nil(suite.T(), actual)
+	suite.mockBlockRepo.AssertNotCalled(suite.T(), "RetrieveLatest")
+}
+
+func (suite *callServiceSuite) TestRebroadcastTransactionInvalidParameters() {
+	for _, parameters := range []map[string]interface{}{
+		nil,
+		{"signed_transaction": ""},
+		{"signed_transaction": 1},
+	} {
+		// when
+		actual, err := suite.callService.Call(
+			defaultContext,
+			callRequest(types.CallMethodRebroadcastTransaction, parameters),
+		)
+
+		// then
+		assert.Equal(suite.T(), errors.ErrInvalidCallParameters.Code, err.Code)
+		assert.Nil(suite.T(), actual)
+	}
+}
+
+func (suite *callServiceSuite) TestRebroadcastTransactionInvalidTransaction() {
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodRebroadcastTransaction, map[string]interface{}{"signed_transaction": "0x6767"}),
+	)
+
+	// then
+	assert.NotNil(suite.T(), err)
+	assert.Nil(suite.T(), actual)
+	suite.mockTransactionRepo.AssertNotCalled(suite.T(), "FindRawByHashInBlock")
+}
+
+func (suite *callServiceSuite) TestRebroadcastTransactionDbError() {
+	// given
+	transaction := suite.rebroadcastTransaction(time.Now())
+	suite.mockTransactionRepo.On("FindRawByHashInBlock").Return(mocks.NilRawTransaction, errors.ErrDatabaseError)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodRebroadcastTransaction, map[string]interface{}{"signed_transaction": transaction}),
+	)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *callServiceSuite) TestScheduleInfo() {
+	// given
+	executedTimestamp := int64(300)
+	expirationTime := int64(200)
+	suite.mockScheduleRepo.On("FindByScheduleId").Return(&types.Schedule{
+		ExecutedTimestamp: &executedTimestamp,
+		ExpirationTime:    &expirationTime,
+		ScheduleId:        domain.MustDecodeEntityId(5001),
+		WaitForExpiry:     true,
+	}, mocks.NilError)
+	expected := &rTypes.CallResponse{
+		Result: map[string]interface{}{
+			"executed_timestamp": executedTimestamp,
+			"expiration_time":    expirationTime,
+			"schedule_id":        "0.0.5001",
+			"wait_for_expiry":    true,
+		},
+	}
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodScheduleInfo, map[string]interface{}{"schedule_id": "0.0.5001"}),
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+	suite.mockScheduleRepo.AssertExpectations(suite.T())
+}
+
+func (suite *callServiceSuite) TestScheduleInfoInvalidParameters() {
+	tests := []struct {
+		name       string
+		parameters map[string]interface{}
+	}{
+		{name: "missing schedule_id", parameters: map[string]interface{}{}},
+		{name: "invalid schedule_id", parameters: map[string]interface{}{"schedule_id": "abc"}},
+		{name: "wrong type", parameters: map[string]interface{}{"schedule_id": 5001}},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			// when
+			actual, err := suite.callService.Call(defaultContext, callRequest(types.CallMethodScheduleInfo, tt.parameters))
+
+			// then
+			assert.Equal(t, errors.ErrInvalidCallParameters.Code, err.Code)
+			assert.Nil(t, actual)
+		})
+	}
+	suite.mockScheduleRepo.AssertNotCalled(suite.T(), "FindByScheduleId")
+}
+
+func (suite *callServiceSuite) TestScheduleInfoNotFound() {
+	// given
+	suite.mockScheduleRepo.On("FindByScheduleId").Return(mocks.NilSchedule, errors.ErrScheduleNotFound)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodScheduleInfo, map[string]interface{}{"schedule_id": "0.0.5001"}),
+	)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrScheduleNotFound, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *callServiceSuite) TestStakingRewardHistory() {
+	// given
+	nodeId := int64(3)
+	stakePeriodStart := int64(19200)
+	history := &types.StakingHistory{
+		AccountId:        domain.MustDecodeEntityId(1001),
+		PendingReward:    320,
+		Periods:          []types.StakingPeriod{{EpochDay: 19201, NodeId: nodeId, RewardRate: 32}},
+		Rewards:          []types.StakingReward{{Amount: 100, ConsensusTimestamp: 200}},
+		StakedNodeId:     &nodeId,
+		StakePeriodStart: &stakePeriodStart,
+	}
+	suite.mockStakingRepo.On("FindStakingHistory").Return(history, mocks.NilError)
+	expected := &rTypes.CallResponse{Result: history.ToMetadata(), Idempotent: false}
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(
+			types.CallMethodStakingRewardHistory,
+			map[string]interface{}{"account_id": "0.0.1001", "limit": 10},
+		),
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+	suite.mockStakingRepo.AssertExpectations(suite.T())
+}
+
+func (suite *callServiceSuite) TestStakingRewardHistoryInvalidParameters() {
+	tests := []struct {
+		name       string
+		parameters map[string]interface{}
+	}{
+		{name: "missing account_id", parameters: map[string]interface{}{}},
+		{name: "invalid account_id", parameters: map[string]interface{}{"account_id": "abc"}},
+		{name: "limit too small", parameters: map[string]interface{}{"account_id": "0.0.1001", "limit": 0}},
+		{name: "limit too large", parameters: map[string]interface{}{"account_id": "0.0.1001", "limit": 101}},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			// when
+			actual, err := suite.callService.Call(
+				defaultContext,
+				callRequest(types.CallMethodStakingRewardHistory, tt.parameters),
+			)
+
+			// then
+			assert.Equal(t, errors.ErrInvalidCallParameters.Code, err.Code)
+			assert.Nil(t, actual)
+		})
+	}
+	suite.mockStakingRepo.AssertNotCalled(suite.T(), "FindStakingHistory")
+}
+
+func (suite *callServiceSuite) TestStakingRewardHistoryAccountNotFound() {
+	// given
+	suite.mockStakingRepo.On("FindStakingHistory").Return(mocks.NilStakingHistory, errors.ErrAccountNotFound)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodStakingRewardHistory, map[string]interface{}{"account_id": "0.0.1001"}),
+	)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrAccountNotFound, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *callServiceSuite) TestSubmissionStatus() {
+	// given
+	suite.submissionWatcher.Track("0x1234ab", 1000, 2000)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodSubmissionStatus, map[string]interface{}{"transaction_hash": "1234AB"}),
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.False(suite.T(), actual.Idempotent)
+	assert.Equal(suite.T(), map[string]interface{}{
+		"hash":              "0x1234ab",
+		"status":            SubmissionStatusPending,
+		"valid_start_nanos": int64(1000),
+		"valid_until_nanos": int64(2000),
+	}, actual.Result)
+}
+
+func (suite *callServiceSuite) TestSubmissionStatusFinal() {
+	// given
+	rawTransaction := &types.RawTransaction{Transaction: domain.Transaction{ConsensusTimestamp: 1500, Result: 22}}
+	suite.mockBlockRepo.On("RetrieveLatest").Return(block(), mocks.NilError)
+	suite.mockTransactionRepo.On("FindRawByHashInBlock").Return(rawTransaction, mocks.NilError)
+	suite.submissionWatcher.Track("0x1234ab", 1000, 2000)
+	assert.Nil(suite.T(), suite.submissionWatcher.poll(defaultContext))
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodSubmissionStatus, map[string]interface{}{"transaction_hash": "0x1234ab"}),
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), int64(1500), actual.Result["consensus_timestamp"])
+	assert.Equal(suite.T(), "SUCCESS", actual.Result["result"])
+	assert.Equal(suite.T(), SubmissionStatusSuccess, actual.Result["status"])
+}
+
+func (suite *callServiceSuite) TestSubmissionStatusNotTracked() {
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodSubmissionStatus, map[string]interface{}{"transaction_hash": "0x1234ab"}),
+	)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrTransactionNotFound, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *callServiceSuite) TestSubmissionStatusWatcherDisabled() {
+	// given
+	callService := NewCallAPIService(
+		NewOnlineBaseService(suite.mockBlockRepo, suite.mockTransactionRepo),
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		0,
+		0,
+		cursorTtl,
+	)
+
+	// when
+	actual, err := callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodSubmissionStatus, map[string]interface{}{"transaction_hash": "0x1234ab"}),
+	)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrTransactionNotFound.Code, err.Code)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *callServiceSuite) TestSubmissionStatusInvalidParameters() {
+	for _, parameters := range []map[string]interface{}{
+		nil,
+		{"transaction_hash": ""},
+		{"transaction_hash": 1},
+	} {
+		// when
+		actual, err := suite.callService.Call(defaultContext, callRequest(types.CallMethodSubmissionStatus, parameters))
+
+		// then
+		assert.Equal(suite.T(), errors.ErrInvalidCallParameters.Code, err.Code)
+		assert.Nil(suite.T(), actual)
+	}
+}
+
+func (suite *callServiceSuite) TestTopicMessage() {
+	// given
+	chunkNum := int32(1)
+	chunkTotal := int32(2)
+	topicMessage := &types.TopicMessage{
+		Chunks: []types.TopicMessageChunk{{TopicMessage: domain.TopicMessage{
+			ChunkNum:           &chunkNum,
+			ChunkTotal:         &chunkTotal,
+			ConsensusTimestamp: 100,
+			Message:            []byte{1, 2},
+			SequenceNumber:     5,
+			TopicId:            domain.MustDecodeEntityId(3001),
+		}}},
+		ChunkTotal: chunkTotal,
+		TopicId:    domain.MustDecodeEntityId(3001),
+	}
+	suite.mockTopicMessageRepo.On("FindBySequenceNumber").Return(topicMessage, mocks.NilError)
+	expected := &rTypes.CallResponse{Result: topicMessage.ToMetadata(), Idempotent: false}
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodTopicMessage, map[string]interface{}{"topic_id": "0.0.3001", "sequence_number": 5}),
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+	suite.mockTopicMessageRepo.AssertExpectations(suite.T())
+}
+
+func (suite *callServiceSuite) TestTopicMessageComplete() {
+	// given
+	topicMessage := &types.TopicMessage{
+		Chunks: []types.TopicMessageChunk{{TopicMessage: domain.TopicMessage{
+			ConsensusTimestamp: 100,
+			Message:            []byte{1, 2},
+			SequenceNumber:     5,
+			TopicId:            domain.MustDecodeEntityId(3001),
+		}}},
+		ChunkTotal: 1,
+		TopicId:    domain.MustDecodeEntityId(3001),
+	}
+	suite.mockTopicMessageRepo.On("FindBySequenceNumber").Return(topicMessage, mocks.NilError)
+	expected := &rTypes.CallResponse{Result: topicMessage.ToMetadata(), Idempotent: true}
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodTopicMessage, map[string]interface{}{"topic_id": "0.0.3001", "sequence_number": 5}),
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+	suite.mockTopicMessageRepo.AssertExpectations(suite.T())
+}
+
+func (suite *callServiceSuite) TestTopicMessageInvalidParameters() {
+	tests := []struct {
+		name       string
+		parameters map[string]interface{}
+	}{
+		{name: "missing topic_id", parameters: map[string]interface{}{"sequence_number": 1}},
+		{name: "missing sequence_number", parameters: map[string]interface{}{"topic_id": "0.0.3001"}},
+		{name: "invalid topic_id", parameters: map[string]interface{}{"topic_id": "abc", "sequence_number": 1}},
+		{name: "zero sequence_number", parameters: map[string]interface{}{"topic_id": "0.0.3001", "sequence_number": 0}},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			// when
+			actual, err := suite.callService.Call(defaultContext, callRequest(types.CallMethodTopicMessage, tt.parameters))
+
+			// then
+			assert.Equal(t, errors.ErrInvalidCallParameters.Code, err.Code)
+			assert.Nil(t, actual)
+		})
+	}
+	suite.mockTopicMessageRepo.AssertNotCalled(suite.T(), "FindBySequenceNumber")
+}
+
+func (suite *callServiceSuite) TestTopicMessageNotFound() {
+	// given
+	suite.mockTopicMessageRepo.On("FindBySequenceNumber").Return(mocks.NilTopicMessage, errors.ErrTopicMessageNotFound)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodTopicMessage, map[string]interface{}{"topic_id": "0.0.3001", "sequence_number": 5}),
+	)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrTopicMessageNotFound, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *callServiceSuite) TestTransactionStatistics() {
+	tests := []struct {
+		name       string
+		end        int64
+		idempotent bool
+	}{
+		{name: "before latest block", end: 20000000, idempotent: true},
+		{name: "after latest block", end: 20000001},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			// given
+			suite.SetupTest()
+			statistics := &types.TransactionStatistics{
+				Counts: []types.TransactionTypeCount{{Count: 5, Result: 22, Type: 14}},
+				End:    tt.end,
+				Start:  1,
+			}
+			suite.mockBlockRepo.On("RetrieveLatest").Return(block(), mocks.NilError)
+			suite.mockTransactionRepo.On("CountByTypeAndResult").Return(statistics, mocks.NilError)
+			expected := &rTypes.CallResponse{Result: statistics.ToMetadata(), Idempotent: tt.idempotent}
+
+			// when
+			actual, err := suite.callService.Call(
+				defaultContext,
+				callRequest(types.CallMethodTransactionStatistics, map[string]interface{}{"start": 1, "end": tt.end}),
+			)
+
+			// then
+			assert.Nil(t, err)
+			assert.Equal(t, expected, actual)
+			suite.mockTransactionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func (suite *callServiceSuite) TestTransactionStatisticsInvalidParameters() {
+	tests := []struct {
+		name       string
+		parameters map[string]interface{}
+	}{
+		{name: "missing start", parameters: map[string]interface{}{"end": 10}},
+		{name: "missing end", parameters: map[string]interface{}{"start": 10}},
+		{name: "negative start", parameters: map[string]interface{}{"start": -1, "end": 10}},
+		{name: "start after end", parameters: map[string]interface{}{"start": 11, "end": 10}},
+		{name: "range too wide", parameters: map[string]interface{}{"start": 0, "end": maxStatisticsRange + 1}},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			// when
+			actual, err := suite.callService.Call(
+				defaultContext,
+				callRequest(types.CallMethodTransactionStatistics, tt.parameters),
+			)
+
+			// then
+			assert.Equal(t, errors.ErrInvalidCallParameters.Code, err.Code)
+			assert.Nil(t, actual)
+		})
+	}
+	suite.mockTransactionRepo.AssertNotCalled(suite.T(), "CountByTypeAndResult")
+}
+
+func (suite *callServiceSuite) TestTransactionStatisticsDbError() {
+	// given
+	suite.mockBlockRepo.On("RetrieveLatest").Return(block(), mocks.NilError)
+	suite.mockTransactionRepo.On("CountByTypeAndResult").Return(mocks.NilTransactionStatistics, errors.ErrDatabaseError)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodTransactionStatistics, map[string]interface{}{"start": 1, "end": 10}),
+	)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *callServiceSuite) TestVerifyBlock() {
+	// given
+	data, err := os.ReadFile(filepath.Join("..", "domain", "recordfile", "testdata", recordFileV1Name))
+	suite.Require().NoError(err)
+	recordFile := &types.RecordFile{}
+	recordFile.Bytes = data
+	recordFile.Hash = recordFileV1Hash
+	recordFile.FileHash = recordFileV1Hash
+	recordFile.Index = 1
+	recordFile.Name = recordFileV1Name
+	suite.mockBlockRepo.On("FindByIndex").Return(block(), mocks.NilError)
+	suite.mockBlockRepo.On("FindRecordFileByIndex", int64(1)).Return(recordFile, mocks.NilError)
+	expected := &rTypes.CallResponse{
+		Result: map[string]interface{}{
+			"block_identifier":  block().GetRosettaBlockIdentifier(),
+			"file_hash":         "0x" + recordFileV1Hash,
+			"file_hash_matches": true,
+			"hash":              "0x" + recordFileV1Hash,
+			"hash_matches":      true,
+			"name":              recordFileV1Name,
+			"stored_file_hash":  "0x" + recordFileV1Hash,
+			"stored_hash":       "0x" + recordFileV1Hash,
+			"verified":          true,
+			"version":           int32(1),
+		},
+		Idempotent: true,
+	}
+
+	// when
+	actual, rErr := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodVerifyBlock, map[string]interface{}{"index": 1}),
+	)
+
+	// then
+	assert.Nil(suite.T(), rErr)
+	assert.Equal(suite.T(), expected, actual)
+	suite.mockBlockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *callServiceSuite) TestVerifyBlockCorrupted() {
+	// given
+	recordFile := &types.RecordFile{}
+	recordFile.Bytes = []byte{0x00, 0x00, 0x00, 0x07}
+	recordFile.Hash = recordFileV1Hash
+	recordFile.Index = 1
+	recordFile.Name = recordFileV1Name
+	suite.mockBlockRepo.On("FindByIdentifier").Return(block(), mocks.NilError)
+	suite.mockBlockRepo.On("FindRecordFileByIndex", int64(1)).Return(recordFile, mocks.NilError)
+
+	// when
+	actual, rErr := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodVerifyBlock, map[string]interface{}{"hash": "0x12345", "index": 1}),
+	)
+
+	// then
+	assert.Nil(suite.T(), rErr)
+	assert.Equal(suite.T(), false, actual.Result["verified"])
+	assert.Contains(suite.T(), actual.Result, "reason")
+	assert.Equal(suite.T(), block().GetRosettaBlockIdentifier(), actual.Result["block_identifier"])
+	suite.mockBlockRepo.AssertNotCalled(suite.T(), "FindByIndex")
+}
+
+func (suite *callServiceSuite) TestVerifyBlockInvalidParameters() {
+	tests := []struct {
+		name       string
+		parameters map[string]interface{}
+	}{
+		{name: "missing index", parameters: map[string]interface{}{}},
+		{name: "negative index", parameters: map[string]interface{}{"index": -1}},
+		{name: "invalid index", parameters: map[string]interface{}{"index": "abc"}},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			// when
+			actual, err := suite.callService.Call(defaultContext, callRequest(types.CallMethodVerifyBlock, tt.parameters))
+
+			// then
+			assert.Equal(t, errors.ErrInvalidCallParameters.Code, err.Code)
+			assert.Nil(t, actual)
+		})
+	}
+}
+
+func (suite *callServiceSuite) TestVerifyBlockBlockNotFound() {
+	// given
+	suite.mockBlockRepo.On("FindByIndex").Return(mocks.NilBlock, errors.ErrBlockNotFound)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodVerifyBlock, map[string]interface{}{"index": 1}),
+	)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrBlockNotFound, err)
+	assert.Nil(suite.T(), actual)
+	suite.mockBlockRepo.AssertNotCalled(suite.T(), "FindRecordFileByIndex", mock.Anything)
+}
+
+func (suite *callServiceSuite) TestVerifyBlockRecordFileNotAvailable() {
+	// given
+	recordFile := &types.RecordFile{}
+	recordFile.Hash = recordFileV1Hash
+	recordFile.Index = 1
+	recordFile.Name = recordFileV1Name
+	suite.mockBlockRepo.On("FindByIndex").Return(block(), mocks.NilError)
+	suite.mockBlockRepo.On("FindRecordFileByIndex", int64(1)).Return(recordFile, mocks.NilError)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodVerifyBlock, map[string]interface{}{"index": 1}),
+	)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrRecordFileNotAvailable.Code, err.Code)
+	assert.Equal(suite.T(), recordFileV1Name, err.Details["name"])
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *callServiceSuite) TestVerifyBlockDbError() {
+	// given
+	suite.mockBlockRepo.On("FindByIndex").Return(block(), mocks.NilError)
+	suite.mockBlockRepo.On("FindRecordFileByIndex", int64(1)).Return(mocks.NilRecordFile, errors.ErrDatabaseError)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodVerifyBlock, map[string]interface{}{"index": 1}),
+	)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *callServiceSuite) TestEntityStake() {
+	// given
+	nodeId := int64(3)
+	stakePeriodStart := int64(19200)
+	stake := &types.EntityStake{
+		AccountId:        domain.MustDecodeEntityId(1001),
+		Balance:          1_000_000_000,
+		PendingReward:    320,
+		StakedNodeId:     &nodeId,
+		StakedToMe:       500_000_000,
+		StakePeriodStart: &stakePeriodStart,
+	}
+	suite.mockStakingRepo.On("FindEntityStake").Return(stake, mocks.NilError)
+	expected := &rTypes.CallResponse{Result: stake.ToMetadata(), Idempotent: false}
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodEntityStake, map[string]interface{}{"account_id": "0.0.1001"}),
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+	suite.mockStakingRepo.AssertExpectations(suite.T())
+}
+
+func (suite *callServiceSuite) TestEntityStakeInvalidParameters() {
+	tests := []struct {
+		name       string
+		parameters map[string]interface{}
+	}{
+		{name: "missing account_id", parameters: map[string]interface{}{}},
+		{name: "invalid account_id", parameters: map[string]interface{}{"account_id": "abc"}},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			// when
+			actual, err := suite.callService.Call(defaultContext, callRequest(types.CallMethodEntityStake, tt.parameters))
+
+			// then
+			assert.Equal(t, errors.ErrInvalidCallParameters.Code, err.Code)
+			assert.Nil(t, actual)
+		})
+	}
+	suite.mockStakingRepo.AssertNotCalled(suite.T(), "FindEntityStake")
+}
+
+func (suite *callServiceSuite) TestEntityStakeAccountNotFound() {
+	// given
+	suite.mockStakingRepo.On("FindEntityStake").Return(mocks.NilEntityStake, errors.ErrAccountNotFound)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodEntityStake, map[string]interface{}{"account_id": "0.0.1001"}),
+	)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrAccountNotFound, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *callServiceSuite) TestNftInfo() {
+	// given
+	nft := &types.Nft{Nft: domain.Nft{SerialNumber: 5, TokenId: domain.MustDecodeEntityId(2001)}}
+	suite.mockTokenRepo.On("FindNft", int64(2001), int64(5)).Return(nft, mocks.NilError)
+	expected := &rTypes.CallResponse{Result: nft.ToMetadata()}
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodNftInfo, map[string]interface{}{"serial_number": 5, "token_id": "0.0.2001"}),
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+	suite.mockTokenRepo.AssertExpectations(suite.T())
+}
+
+func (suite *callServiceSuite) TestNftInfoInvalidParameters() {
+	tests := []struct {
+		name       string
+		parameters map[string]interface{}
+	}{
+		{name: "missing token_id", parameters: map[string]interface{}{"serial_number": 1}},
+		{name: "invalid token_id", parameters: map[string]interface{}{"serial_number": 1, "token_id": "abc"}},
+		{name: "missing serial_number", parameters: map[string]interface{}{"token_id": "0.0.2001"}},
+		{name: "zero serial_number", parameters: map[string]interface{}{"serial_number": 0, "token_id": "0.0.2001"}},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			// when
+			actual, err := suite.callService.Call(defaultContext, callRequest(types.CallMethodNftInfo, tt.parameters))
+
+			// then
+			assert.Equal(t, errors.ErrInvalidCallParameters.Code, err.Code)
+			assert.Nil(t, actual)
+		})
+	}
+	suite.mockTokenRepo.AssertNotCalled(suite.T(), "FindNft")
+}
+
+func (suite *callServiceSuite) TestNftInfoNotFound() {
+	// given
+	suite.mockTokenRepo.On("FindNft", int64(2001), int64(5)).Return(mocks.NilNft, errors.ErrNftNotFound)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodNftInfo, map[string]interface{}{"serial_number": 5, "token_id": "0.0.2001"}),
+	)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrNftNotFound, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *callServiceSuite) TestNftSerials() {
+	// given
+	nfts := []types.Nft{
+		{Nft: domain.Nft{SerialNumber: 11, TokenId: domain.MustDecodeEntityId(2001)}},
+		{Nft: domain.Nft{SerialNumber: 12, TokenId: domain.MustDecodeEntityId(2001)}},
+	}
+	suite.mockTokenRepo.On("FindNfts", int64(2001), int64(10), 2).Return(nfts, mocks.NilError)
+	expected := &rTypes.CallResponse{
+		Result: map[string]interface{}{
+			"nfts": []map[string]interface{}{nfts[0].ToMetadata(), nfts[1].ToMetadata()},
+		},
+	}
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodNftSerials, map[string]interface{}{
+			"cursor":   types.NewCursor(10, "").Encode(),
+			"limit":    2,
+			"token_id": "0.0.2001",
+		}),
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assertNextCursor(suite.T(), actual, 12)
+	assert.Equal(suite.T(), expected, actual)
+	suite.mockTokenRepo.AssertExpectations(suite.T())
+}
+
+func (suite *callServiceSuite) TestNftSerialsDefaults() {
+	// given
+	suite.mockTokenRepo.On("FindNfts", int64(2001), int64(0), defaultNftSerialsLimit).
+		Return([]types.Nft{}, mocks.NilError)
+	expected := &rTypes.CallResponse{Result: map[string]interface{}{"nfts": []map[string]interface{}{}}}
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodNftSerials, map[string]interface{}{"token_id": "0.0.2001"}),
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+	suite.mockTokenRepo.AssertExpectations(suite.T())
+}
+
+func (suite *callServiceSuite) TestNftSerialsInvalidParameters() {
+	tests := []struct {
+		name       string
+		parameters map[string]interface{}
+	}{
+		{name: "missing token_id", parameters: map[string]interface{}{}},
+		{name: "invalid token_id", parameters: map[string]interface{}{"token_id": "abc"}},
+		{name: "invalid cursor", parameters: map[string]interface{}{"cursor": "10", "token_id": "0.0.2001"}},
+		{name: "expired cursor", parameters: map[string]interface{}{"cursor": expiredCursor(10), "token_id": "0.0.2001"}},
+		{name: "zero limit", parameters: map[string]interface{}{"limit": 0, "token_id": "0.0.2001"}},
+		{name: "limit too large", parameters: map[string]interface{}{"limit": 101, "token_id": "0.0.2001"}},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			// when
+			actual, err := suite.callService.Call(defaultContext, callRequest(types.CallMethodNftSerials, tt.parameters))
+
+			// then
+			assert.Equal(t, errors.ErrInvalidCallParameters.Code, err.Code)
+			assert.Nil(t, actual)
+		})
+	}
+	suite.mockTokenRepo.AssertNotCalled(suite.T(), "FindNfts")
+}
+
+func (suite *callServiceSuite) TestTokenBalances() {
+	// given
+	tokenId := domain.MustDecodeEntityId(2001)
+	amounts := types.AmountSlice{
+		&types.TokenAmount{Decimals: 2, TokenId: tokenId, Type: domain.TokenTypeFungibleCommon, Value: 150},
+	}
+	suite.mockBlockRepo.On("FindByIndex").Return(block(), mocks.NilError)
+	suite.mockAccountRepo.On("RetrieveTokenBalancesAtBlock").Return(amounts, mocks.NilError)
+	expected := &rTypes.CallResponse{
+		Result: map[string]interface{}{
+			"account_identifier": &rTypes.AccountIdentifier{Address: "0.0.1001"},
+			"balances":           amounts.ToRosetta(),
+			"block_identifier":   block().GetRosettaBlockIdentifier(),
+		},
+		Idempotent: true,
+	}
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodTokenBalances, map[string]interface{}{
+			"account_id": "0.0.1001",
+			"index":      1,
+			"token_ids":  []string{"0.0.2001", "0.0.2002"},
+		}),
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+	suite.mockAccountRepo.AssertExpectations(suite.T())
+}
+
+func (suite *callServiceSuite) TestTokenBalancesLatestBlock() {
+	// given
+	suite.mockBlockRepo.On("RetrieveLatest").Return(block(), mocks.NilError)
+	suite.mockAccountRepo.On("RetrieveTokenBalancesAtBlock").Return(types.AmountSlice{}, mocks.NilError)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodTokenBalances, map[string]interface{}{
+			"account_id": "0.0.1001",
+			"token_ids":  []string{"0.0.2001"},
+		}),
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.False(suite.T(), actual.Idempotent)
+	assert.Empty(suite.T(), actual.Result["balances"])
+	suite.mockBlockRepo.AssertNotCalled(suite.T(), "FindByIndex")
+}
+
+func (suite *callServiceSuite) TestTokenBalancesInvalidParameters() {
+	tooManyTokenIds := make([]string, 101)
+	for i := range tooManyTokenIds {
+		tooManyTokenIds[i] = "0.0.2001"
+	}
+	tests := []struct {
+		name       string
+		parameters map[string]interface{}
+	}{
+		{name: "missing account_id", parameters: map[string]interface{}{"token_ids": []string{"0.0.2001"}}},
+		{
+			name:       "alias account_id",
+			parameters: map[string]interface{}{"account_id": "0x1234", "token_ids": []string{"0.0.2001"}},
+		},
+		{name: "missing token_ids", parameters: map[string]interface{}{"account_id": "0.0.1001"}},
+		{name: "empty token_ids", parameters: map[string]interface{}{"account_id": "0.0.1001", "token_ids": []string{}}},
+		{
+			name:       "too many token_ids",
+			parameters: map[string]interface{}{"account_id": "0.0.1001", "token_ids": tooManyTokenIds},
+		},
+		{
+			name:       "invalid token id",
+			parameters: map[string]interface{}{"account_id": "0.0.1001", "token_ids": []string{"abc"}},
+		},
+		{
+			name: "negative index",
+			parameters: map[string]interface{}{
+				"account_id": "0.0.1001",
+				"index":      -1,
+				"token_ids":  []string{"0.0.2001"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			// when
+			actual, err := suite.callService.Call(defaultContext, callRequest(types.CallMethodTokenBalances, tt.parameters))
+
+			// then
+			assert.Equal(t, errors.ErrInvalidCallParameters.Code, err.Code)
+			assert.Nil(t, actual)
+		})
+	}
+	suite.mockAccountRepo.AssertNotCalled(suite.T(), "RetrieveTokenBalancesAtBlock")
+}
+
+func (suite *callServiceSuite) TestTokenBalancesDbError() {
+	// given
+	suite.mockBlockRepo.On("FindByIndex").Return(block(), mocks.NilError)
+	suite.mockAccountRepo.On("RetrieveTokenBalancesAtBlock").Return(types.AmountSlice(nil), errors.ErrDatabaseError)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodTokenBalances, map[string]interface{}{
+			"account_id": "0.0.1001",
+			"index":      1,
+			"token_ids":  []string{"0.0.2001"},
+		}),
+	)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *callServiceSuite) TestTokenHolders() {
+	// given
+	token := fungibleToken()
+	holders := []types.TokenHolder{
+		{AccountId: domain.MustDecodeEntityId(1001), Balance: 10},
+		{AccountId: domain.MustDecodeEntityId(1002), Balance: 20},
+	}
+	suite.mockTokenRepo.On("Find").Return(token, mocks.NilError)
+	suite.mockTokenRepo.On("FindHolders", int64(2001), int64(5), int64(1000), 2).
+		Return(int64(300), holders, mocks.NilError)
+	expected := &rTypes.CallResponse{
+		Result: map[string]interface{}{
+			"consensus_timestamp": int64(300),
+			"holders": []map[string]interface{}{
+				{
+					"account_identifier": &rTypes.AccountIdentifier{Address: "0.0.1001"},
+					"balance":            types.NewTokenAmount(token.Token, 10).ToRosetta(),
+				},
+				{
+					"account_identifier": &rTypes.AccountIdentifier{Address: "0.0.1002"},
+					"balance":            types.NewTokenAmount(token.Token, 20).ToRosetta(),
+				},
+			},
+		},
+	}
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodTokenHolders, map[string]interface{}{
+			"cursor":      types.NewCursor(1000, "").Encode(),
+			"limit":       2,
+			"min_balance": 5,
+			"token_id":    "0.0.2001",
+		}),
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assertNextCursor(suite.T(), actual, 1002)
+	assert.Equal(suite.T(), expected, actual)
+	suite.mockTokenRepo.AssertExpectations(suite.T())
+}
+
+func (suite *callServiceSuite) TestTokenHoldersDefaults() {
+	// given
+	holders := []types.TokenHolder{{AccountId: domain.MustDecodeEntityId(1001), Balance: 10}}
+	suite.mockTokenRepo.On("Find").Return(fungibleToken(), mocks.NilError)
+	suite.mockTokenRepo.On("FindHolders", int64(2001), int64(1), int64(0), defaultTokenHoldersLimit).
+		Return(int64(300), holders, mocks.NilError)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodTokenHolders, map[string]interface{}{"token_id": "0.0.2001"}),
+	)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Len(suite.T(), actual.Result["holders"], 1)
+	assert.NotContains(suite.T(), actual.Result, "next")
+	suite.mockTokenRepo.AssertExpectations(suite.T())
+}
+
+func (suite *callServiceSuite) TestTokenHoldersInvalidParameters() {
+	tests := []struct {
+		name       string
+		parameters map[string]interface{}
+	}{
+		{name: "missing token_id", parameters: map[string]interface{}{}},
+		{name: "invalid token_id", parameters: map[string]interface{}{"token_id": "abc"}},
+		{name: "invalid cursor", parameters: map[string]interface{}{"cursor": "abc", "token_id": "0.0.2001"}},
+		{name: "expired cursor", parameters: map[string]interface{}{"cursor": expiredCursor(1000), "token_id": "0.0.2001"}},
+		{name: "zero limit", parameters: map[string]interface{}{"limit": 0, "token_id": "0.0.2001"}},
+		{name: "limit too large", parameters: map[string]interface{}{"limit": 101, "token_id": "0.0.2001"}},
+		{name: "zero min_balance", parameters: map[string]interface{}{"min_balance": 0, "token_id": "0.0.2001"}},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			// when
+			actual, err := suite.callService.Call(defaultContext, callRequest(types.CallMethodTokenHolders, tt.parameters))
+
+			// then
+			assert.Equal(t, errors.ErrInvalidCallParameters.Code, err.Code)
+			assert.Nil(t, actual)
+		})
+	}
+	suite.mockTokenRepo.AssertNotCalled(suite.T(), "Find")
+}
+
+func (suite *callServiceSuite) TestTokenHoldersNonFungibleToken() {
+	// given
+	token := fungibleToken()
+	token.Type = domain.TokenTypeNonFungibleUnique
+	suite.mockTokenRepo.On("Find").Return(token, mocks.NilError)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodTokenHolders, map[string]interface{}{"token_id": "0.0.2001"}),
+	)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrInvalidToken.Code, err.Code)
+	assert.Nil(suite.T(), actual)
+	suite.mockTokenRepo.AssertNotCalled(suite.T(), "FindHolders")
+}
+
+func (suite *callServiceSuite) TestTokenHoldersTokenNotFound() {
+	// given
+	suite.mockTokenRepo.On("Find").Return(mocks.NilToken, errors.ErrTokenNotFound)
+
+	// when
+	actual, err := suite.callService.Call(
+		defaultContext,
+		callRequest(types.CallMethodTokenHolders, map[string]interface{}{"token_id": "0.0.2001"}),
+	)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrTokenNotFound, err)
+	assert.Nil(suite.T(), actual)
+}
+
+// assertNextCursor asserts the next field of the result is a valid cursor at the position, and removes the field
+func assertNextCursor(t *testing.T, actual *rTypes.CallResponse, position int64) {
+	require.NotNil(t, actual)
+	require.IsType(t, "", actual.Result["next"])
+	cursor, err := types.NewCursorFromString(actual.Result["next"].(string), cursorTtl)
+	require.NoError(t, err)
+	assert.Equal(t, position, cursor.Position)
+	delete(actual.Result, "next")
+}
+
+func expiredCursor(position int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d::1", position)))
+}
+
+func callRequest(method string, parameters map[string]interface{}) *rTypes.CallRequest {
+	return &rTypes.CallRequest{
+		NetworkIdentifier: &rTypes.NetworkIdentifier{Blockchain: types.Blockchain, Network: "testnet"},
+		Method:            method,
+		Parameters:        parameters,
+	}
+}
+
+func fungibleToken() *types.Token {
+	return &types.Token{Token: domain.Token{
+		Decimals: 2,
+		TokenId:  domain.MustDecodeEntityId(2001),
+		Type:     domain.TokenTypeFungibleCommon,
+	}}
+}
+
+func (suite *callServiceSuite) rebroadcastTransaction(validStart time.Time) string {
+	operations := types.OperationSlice{
+		getOperation(0, types.OperationTypeCryptoTransfer, defaultCryptoAccountId1, defaultSendAmount),
+		getOperation(1, types.OperationTypeCryptoTransfer, defaultCryptoAccountId2, defaultReceiveAmount),
+	}
+	payloads, err := suite.constructionService.ConstructionPayloads(
+		defaultContext,
+		&rTypes.ConstructionPayloadsRequest{
+			Metadata:   map[string]interface{}{"valid_start_nanos": strconv.FormatInt(validStart.UnixNano(), 10)},
+			Operations: operations.ToRosetta(),
+		},
+	)
+	assert.Nil(suite.T(), err)
+	return payloads.UnsignedTransaction
+}
@@ -0,0 +1,103 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package services
+
+import (
+	"context"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/tools"
+	"github.com/hashgraph/hedera-sdk-go/v2"
+)
+
+// addressBookCertificateVerifier pins the TLS certificates of the consensus nodes to the certificate hashes in the
+// latest address book. The nodes present self-signed certificates, so the certificate chain isn't verified and the
+// certificate is trusted only if its hash matches, the same way the SDK verifies the certificates with its built-in
+// address books. The address book is read from the database, so the custom nodes are verified as well
+type addressBookCertificateVerifier struct {
+	addressBookEntryRepo interfaces.AddressBookEntryRepository
+}
+
+// NewCertificateVerifier creates a CertificateVerifier which verifies the node certificates against the address book
+func NewCertificateVerifier(
+	addressBookEntryRepo interfaces.AddressBookEntryRepository,
+) interfaces.CertificateVerifier {
+	return &addressBookCertificateVerifier{addressBookEntryRepo: addressBookEntryRepo}
+}
+
+func (v *addressBookCertificateVerifier) Verify(
+	ctx context.Context,
+	nodeAccountId hedera.AccountID,
+	rawCerts [][]byte,
+) error {
+	expected, err := v.getCertHash(ctx, nodeAccountId)
+	if err != nil {
+		return err
+	}
+
+	for _, certificate := range rawCerts {
+		if strings.EqualFold(getCertHash(certificate), expected) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("certificate of node %s doesn't match the address book", nodeAccountId)
+}
+
+// getCertHash returns the certificate hash of the node account in the latest address book
+func (v *addressBookCertificateVerifier) getCertHash(ctx context.Context, nodeAccountId hedera.AccountID) (
+	string,
+	error,
+) {
+	encodedId, err := domain.EncodeEntityId(
+		int64(nodeAccountId.Shard),
+		int64(nodeAccountId.Realm),
+		int64(nodeAccountId.Account),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	entries, rErr := v.addressBookEntryRepo.Entries(ctx)
+	if rErr != nil {
+		return "", fmt.Errorf("failed to get the address book: %s", rErr.Message)
+	}
+
+	for _, entry := range entries.Entries {
+		if entry.AccountId.EncodedId == encodedId && entry.CertHash != "" {
+			return tools.SafeRemoveHexPrefix(entry.CertHash), nil
+		}
+	}
+
+	return "", fmt.Errorf("no certificate hash of node %s in the address book", nodeAccountId)
+}
+
+// getCertHash returns the hex encoded SHA-384 hash of the PEM encoded certificate
+func getCertHash(certificate []byte) string {
+	digest := sha512.Sum384(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate}))
+	return hex.EncodeToString(digest[:])
+}
@@ -0,0 +1,139 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/mocks"
+	"github.com/hashgraph/hedera-sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestCertificateVerifierSuite(t *testing.T) {
+	suite.Run(t, new(certificateVerifierSuite))
+}
+
+type certificateVerifierSuite struct {
+	suite.Suite
+	mockAddressBookEntryRepo *mocks.MockAddressBookEntryRepository
+	nodeAccountId            hedera.AccountID
+	server                   *httptest.Server
+	verifier                 *addressBookCertificateVerifier
+}
+
+func (suite *certificateVerifierSuite) SetupTest() {
+	suite.mockAddressBookEntryRepo = &mocks.MockAddressBookEntryRepository{}
+	suite.nodeAccountId = hedera.AccountID{Account: 3}
+	suite.server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	suite.verifier = NewCertificateVerifier(suite.mockAddressBookEntryRepo).(*addressBookCertificateVerifier)
+}
+
+func (suite *certificateVerifierSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+func (suite *certificateVerifierSuite) TestVerify() {
+	tests := []struct {
+		name     string
+		certHash string
+	}{
+		{name: "exact", certHash: getCertHash(suite.server.Certificate().Raw)},
+		{name: "hex prefix", certHash: "0x" + getCertHash(suite.server.Certificate().Raw)},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			// given
+			suite.mockAddressBookEntryRepo = &mocks.MockAddressBookEntryRepository{}
+			suite.verifier.addressBookEntryRepo = suite.mockAddressBookEntryRepo
+			suite.mockAddressBookEntryRepo.On("Entries").Return(addressBookEntries(tt.certHash), mocks.NilError)
+
+			// when
+			err := suite.verifier.Verify(defaultContext, suite.nodeAccountId, suite.rawCerts())
+
+			// then
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func (suite *certificateVerifierSuite) TestVerifyMismatch() {
+	// given
+	suite.mockAddressBookEntryRepo.On("Entries").Return(addressBookEntries(getCertHash([]byte{1})), mocks.NilError)
+
+	// when
+	err := suite.verifier.Verify(defaultContext, suite.nodeAccountId, suite.rawCerts())
+
+	// then
+	assert.Error(suite.T(), err)
+}
+
+func (suite *certificateVerifierSuite) TestVerifyNodeNotInAddressBook() {
+	// given
+	suite.mockAddressBookEntryRepo.On("Entries").
+		Return(addressBookEntries(getCertHash(suite.server.Certificate().Raw)), mocks.NilError)
+
+	// when
+	err := suite.verifier.Verify(defaultContext, hedera.AccountID{Account: 4}, suite.rawCerts())
+
+	// then
+	assert.Error(suite.T(), err)
+}
+
+func (suite *certificateVerifierSuite) TestVerifyNoCertHash() {
+	// given
+	suite.mockAddressBookEntryRepo.On("Entries").Return(addressBookEntries(""), mocks.NilError)
+
+	// when
+	err := suite.verifier.Verify(defaultContext, suite.nodeAccountId, suite.rawCerts())
+
+	// then
+	assert.Error(suite.T(), err)
+}
+
+func (suite *certificateVerifierSuite) TestVerifyDbError() {
+	// given
+	suite.mockAddressBookEntryRepo.On("Entries").Return(mocks.NilEntries, errors.ErrDatabaseError)
+
+	// when
+	err := suite.verifier.Verify(defaultContext, suite.nodeAccountId, suite.rawCerts())
+
+	// then
+	assert.Error(suite.T(), err)
+}
+
+func (suite *certificateVerifierSuite) rawCerts() [][]byte {
+	return [][]byte{suite.server.Certificate().Raw}
+}
+
+func addressBookEntries(certHash string) *types.AddressBookEntries {
+	return &types.AddressBookEntries{Entries: []types.AddressBookEntry{
+		{NodeId: 0, AccountId: domain.MustDecodeEntityId(3), CertHash: certHash},
+		{NodeId: 1, AccountId: domain.MustDecodeEntityId(5), CertHash: getCertHash([]byte{2})},
+	}}
+}
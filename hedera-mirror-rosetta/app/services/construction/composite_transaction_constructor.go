@@ -31,6 +31,12 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+// constructorOperationTypes maps the operation types built by the constructor of another operation type, e.g., the
+// approved transfer operations are part of a crypto transfer transaction
+var constructorOperationTypes = map[string]string{
+	types.OperationTypeApprovedTransfer: types.OperationTypeCryptoTransfer,
+}
+
 type transactionConstructorWithType interface {
 	BaseTransactionConstructor
 	GetDefaultMaxTransactionFee() types.HbarAmount
@@ -59,7 +65,7 @@ func (c *compositeTransactionConstructor) GetDefaultMaxTransactionFee(operationT
 	types.HbarAmount,
 	*rTypes.Error,
 ) {
-	h, ok := c.constructorsByOperationType[operationType]
+	h, ok := c.constructorsByOperationType[getConstructorOperationType(operationType)]
 	if !ok {
 		return types.HbarAmount{}, errors.ErrInvalidOperationType
 	}
@@ -106,9 +112,9 @@ func (c *compositeTransactionConstructor) validate(operations types.OperationSli
 		return nil, errors.ErrEmptyOperations
 	}
 
-	operationType := operations[0].Type
+	operationType := getConstructorOperationType(operations[0].Type)
 	for _, operation := range operations[1:] {
-		if operation.Type != operationType {
+		if getConstructorOperationType(operation.Type) != operationType {
 			return nil, errors.ErrMultipleOperationTypesPresent
 		}
 	}
@@ -122,6 +128,13 @@ func (c *compositeTransactionConstructor) validate(operations types.OperationSli
 	return h, nil
 }
 
+func getConstructorOperationType(operationType string) string {
+	if constructorOperationType, ok := constructorOperationTypes[operationType]; ok {
+		return constructorOperationType
+	}
+	return operationType
+}
+
 func NewTransactionConstructor() TransactionConstructor {
 	c := &compositeTransactionConstructor{
 		constructorsByOperationType:   make(map[string]transactionConstructorWithType),
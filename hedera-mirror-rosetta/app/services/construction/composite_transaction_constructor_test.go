@@ -142,6 +142,26 @@ func (suite *compositeTransactionConstructorSuite) TestConstructMixedOperations(
 	suite.mockConstructor.AssertExpectations(suite.T())
 }
 
+func (suite *compositeTransactionConstructorSuite) TestConstructApprovedTransferOperations() {
+	// given
+	operations := types.OperationSlice{
+		{Type: types.OperationTypeApprovedTransfer},
+		{Type: types.OperationTypeCryptoTransfer},
+	}
+	suite.mockConstructor.
+		On("Construct", defaultContext, operations).
+		Return(cryptoTransferTransaction, signers, mocks.NilError)
+
+	// when
+	actualTx, actualSigners, err := suite.constructor.Construct(defaultContext, operations)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), cryptoTransferTransaction, actualTx)
+	assert.Equal(suite.T(), signers, actualSigners)
+	suite.mockConstructor.AssertExpectations(suite.T())
+}
+
 func (suite *compositeTransactionConstructorSuite) TestGetDefaultMaxTransactionFee() {
 	// given
 	expected := types.HbarAmount{Value: 1000}
@@ -156,6 +176,20 @@ func (suite *compositeTransactionConstructorSuite) TestGetDefaultMaxTransactionF
 	suite.mockConstructor.AssertExpectations(suite.T())
 }
 
+func (suite *compositeTransactionConstructorSuite) TestGetDefaultMaxTransactionFeeApprovedTransfer() {
+	// given
+	expected := types.HbarAmount{Value: 1000}
+	suite.mockConstructor.On("GetDefaultMaxTransactionFee").Return(expected)
+
+	// when
+	actual, err := suite.constructor.GetDefaultMaxTransactionFee(types.OperationTypeApprovedTransfer)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+	suite.mockConstructor.AssertExpectations(suite.T())
+}
+
 func (suite *compositeTransactionConstructorSuite) TestGetDefaultMaxTransactionFeeInvalidOperationType() {
 	// given
 	// when
@@ -29,8 +29,11 @@ import (
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-protobufs-go/sdk"
+	"github.com/hashgraph/hedera-protobufs-go/services"
 	"github.com/hashgraph/hedera-sdk-go/v2"
 	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
 )
 
 type cryptoTransferTransactionConstructor struct {
@@ -38,21 +41,36 @@ type cryptoTransferTransactionConstructor struct {
 }
 
 type transfer struct {
-	account hedera.AccountID
-	amount  types.Amount
+	account  hedera.AccountID
+	amount   types.Amount
+	approved bool
 }
 
-type senderMap map[string]types.AccountId
+// senderSet is the set of the senders in the order of their first debit, so the signers and the default payer are
+// deterministic for the same operations
+type senderSet struct {
+	added   map[string]bool
+	senders []types.AccountId
+}
+
+func newSenderSet() *senderSet {
+	return &senderSet{added: make(map[string]bool), senders: make([]types.AccountId, 0)}
+}
 
-func (m senderMap) toSenders() []types.AccountId {
-	senders := make([]types.AccountId, 0, len(m))
-	for _, sender := range m {
-		senders = append(senders, sender)
+func (s *senderSet) add(sender types.AccountId) {
+	key := sender.String()
+	if !s.added[key] {
+		s.added[key] = true
+		s.senders = append(s.senders, sender)
 	}
-	return senders
+}
+
+func (s *senderSet) toSenders() []types.AccountId {
+	return s.senders
 }
 
 type nftTransfer struct {
+	approved bool
 	nftId    hedera.NftID
 	receiver hedera.AccountID
 	sender   hedera.AccountID
@@ -74,11 +92,17 @@ func (c *cryptoTransferTransactionConstructor) Construct(
 		switch amount := transfer.amount.(type) {
 		case *types.HbarAmount:
 			transaction.AddHbarTransfer(transfer.account, hedera.HbarFromTinybar(amount.Value))
+			if transfer.approved {
+				transaction.SetHbarTransferApproval(transfer.account, true)
+			}
 		case *types.TokenAmount:
 			tokenId, _ := hedera.TokenIDFromString(amount.TokenId.String())
 			if amount.Type == domain.TokenTypeFungibleCommon {
 				transaction.AddTokenTransferWithDecimals(tokenId, transfer.account, amount.Value,
 					uint32(amount.Decimals))
+				if transfer.approved {
+					transaction.SetTokenTransferApproval(tokenId, transfer.account, true)
+				}
 			} else {
 				// build nft transfers
 				nftId := hedera.NftID{SerialNumber: amount.SerialNumbers[0], TokenID: tokenId}
@@ -89,6 +113,7 @@ func (c *cryptoTransferTransactionConstructor) Construct(
 				if amount.Value == 1 {
 					nftTransfers[nftId].receiver = transfer.account
 				} else {
+					nftTransfers[nftId].approved = transfer.approved
 					nftTransfers[nftId].sender = transfer.account
 				}
 			}
@@ -96,7 +121,8 @@ func (c *cryptoTransferTransactionConstructor) Construct(
 	}
 
 	for _, nftTransfer := range nftTransfers {
-		transaction.AddNftTransfer(nftTransfer.nftId, nftTransfer.sender, nftTransfer.receiver)
+		transaction.AddApprovedNftTransfer(nftTransfer.nftId, nftTransfer.sender, nftTransfer.receiver,
+			nftTransfer.approved)
 	}
 
 	return transaction, senders, nil
@@ -130,10 +156,12 @@ func (c *cryptoTransferTransactionConstructor) Parse(_ context.Context, transact
 	}
 	operations := make(types.OperationSlice, 0, numOperations)
 
+	approvedHbarTransfers := getApprovedHbarTransfers(transferTransaction)
 	for accountId, hbarAmount := range hbarTransferMap {
 		var err *rTypes.Error
 		amount := &types.HbarAmount{Value: hbarAmount.AsTinybar()}
-		if operations, err = c.addOperation(accountId, amount, operations); err != nil {
+		approved := approvedHbarTransfers[accountId.String()]
+		if operations, err = c.addOperation(accountId, amount, approved, operations); err != nil {
 			return nil, nil, err
 		}
 	}
@@ -144,8 +172,9 @@ func (c *cryptoTransferTransactionConstructor) Parse(_ context.Context, transact
 			return nil, nil, err
 		}
 		for _, tokenTransfer := range tokenTransfers {
-			tokenAmount := types.NewTokenAmount(domainToken, tokenTransfer.Amount)
-			if operations, err = c.addOperation(tokenTransfer.AccountID, tokenAmount, operations); err != nil {
+			amount := types.NewTokenAmount(domainToken, tokenTransfer.Amount)
+			approved := tokenTransfer.IsApproved
+			if operations, err = c.addOperation(tokenTransfer.AccountID, amount, approved, operations); err != nil {
 				return nil, nil, err
 			}
 		}
@@ -157,26 +186,29 @@ func (c *cryptoTransferTransactionConstructor) Parse(_ context.Context, transact
 			return nil, nil, err
 		}
 		for _, nftTransfer := range nftTransfers {
+			receiver := nftTransfer.ReceiverAccountID
 			tokenAmount := types.NewTokenAmount(domainToken, 1).SetSerialNumbers([]int64{nftTransfer.SerialNumber})
-			if operations, err = c.addOperation(nftTransfer.ReceiverAccountID, tokenAmount, operations); err != nil {
+			if operations, err = c.addOperation(receiver, tokenAmount, false, operations); err != nil {
 				return nil, nil, err
 			}
 
+			sender := nftTransfer.SenderAccountID
 			tokenAmount = types.NewTokenAmount(domainToken, -1).SetSerialNumbers([]int64{nftTransfer.SerialNumber})
-			if operations, err = c.addOperation(nftTransfer.SenderAccountID, tokenAmount, operations); err != nil {
+			if operations, err = c.addOperation(sender, tokenAmount, nftTransfer.IsApproved, operations); err != nil {
 				return nil, nil, err
 			}
 		}
 	}
 
-	senderMap := senderMap{}
+	senders := newSenderSet()
 	for _, operation := range operations {
-		if operation.Amount.GetValue() < 0 {
-			senderMap[operation.AccountId.String()] = operation.AccountId
+		// the owner of an approved transfer doesn't sign, the allowance is spent by the payer
+		if operation.Amount.GetValue() < 0 && operation.Type != types.OperationTypeApprovedTransfer {
+			senders.add(operation.AccountId)
 		}
 	}
 
-	return operations, senderMap.toSenders(), nil
+	return operations, senders.toSenders(), nil
 }
 
 func (c *cryptoTransferTransactionConstructor) Preprocess(_ context.Context, operations types.OperationSlice) (
@@ -194,15 +226,20 @@ func (c *cryptoTransferTransactionConstructor) Preprocess(_ context.Context, ope
 func (c *cryptoTransferTransactionConstructor) addOperation(
 	sdkAccountId hedera.AccountID,
 	amount types.Amount,
+	approved bool,
 	operations types.OperationSlice,
 ) (types.OperationSlice, *rTypes.Error) {
 	accountId, err := types.NewAccountIdFromSdkAccountId(sdkAccountId)
 	if err != nil {
 		return nil, errors.ErrInvalidAccount
 	}
+	operationType := c.GetOperationType()
+	if approved {
+		operationType = types.OperationTypeApprovedTransfer
+	}
 	operation := types.Operation{
 		Index:     int64(len(operations)),
-		Type:      c.GetOperationType(),
+		Type:      operationType,
 		AccountId: accountId,
 		Amount:    amount,
 	}
@@ -214,12 +251,12 @@ func (c *cryptoTransferTransactionConstructor) preprocess(operations types.Opera
 	[]types.AccountId,
 	*rTypes.Error,
 ) {
-	if err := validateOperations(operations, 0, c.GetOperationType(), false); err != nil {
+	if err := c.validateOperations(operations); err != nil {
 		return nil, nil, err
 	}
 
 	nftValues := make(map[string][]int64)
-	senderMap := senderMap{}
+	senders := newSenderSet()
 	totalAmounts := make(map[string]int64)
 	transfers := make([]transfer, 0, len(operations))
 
@@ -230,10 +267,16 @@ func (c *cryptoTransferTransactionConstructor) preprocess(operations types.Opera
 			return nil, nil, errors.ErrInvalidOperationsAmount
 		}
 
-		transfers = append(transfers, transfer{account: accountId.ToSdkAccountId(), amount: amount})
+		approved := operation.Type == types.OperationTypeApprovedTransfer
+		if approved && amount.GetValue() > 0 {
+			// only the debit from the owner's account can be spent from an allowance
+			return nil, nil, errors.ErrInvalidOperationsAmount
+		}
 
-		if amount.GetValue() < 0 {
-			senderMap[accountId.String()] = accountId
+		transfers = append(transfers, transfer{account: accountId.ToSdkAccountId(), amount: amount, approved: approved})
+
+		if amount.GetValue() < 0 && !approved {
+			senders.add(accountId)
 		}
 
 		totalAmounts[amount.GetSymbol()] += amount.GetValue()
@@ -261,7 +304,70 @@ func (c *cryptoTransferTransactionConstructor) preprocess(operations types.Opera
 		}
 	}
 
-	return transfers, senderMap.toSenders(), nil
+	return transfers, senders.toSenders(), nil
+}
+
+// validateOperations validates the operations are either crypto transfers or approved transfers
+func (c *cryptoTransferTransactionConstructor) validateOperations(operations types.OperationSlice) *rTypes.Error {
+	if len(operations) == 0 {
+		return errors.ErrEmptyOperations
+	}
+
+	for _, operation := range operations {
+		if operation.Amount == nil {
+			return errors.ErrInvalidOperations
+		}
+
+		if operation.Type != c.GetOperationType() && operation.Type != types.OperationTypeApprovedTransfer {
+			return errors.ErrInvalidOperationType
+		}
+	}
+
+	return nil
+}
+
+// getApprovedHbarTransfers returns the accounts of the approved hbar transfers keyed by the account id string. The
+// sdk doesn't expose the approval of hbar transfers, so it's read from the body of the frozen transaction
+func getApprovedHbarTransfers(transaction *hedera.TransferTransaction) map[string]bool {
+	approved := make(map[string]bool)
+	transactionBytes, err := transaction.ToBytes()
+	if err != nil {
+		// the transaction is not frozen
+		return approved
+	}
+
+	var transactionList sdk.TransactionList
+	if err = proto.Unmarshal(transactionBytes, &transactionList); err != nil ||
+		len(transactionList.GetTransactionList()) == 0 {
+		return approved
+	}
+
+	var signedTransaction services.SignedTransaction
+	signedTransactionBytes := transactionList.GetTransactionList()[0].GetSignedTransactionBytes()
+	if err = proto.Unmarshal(signedTransactionBytes, &signedTransaction); err != nil {
+		return approved
+	}
+
+	var body services.TransactionBody
+	if err = proto.Unmarshal(signedTransaction.GetBodyBytes(), &body); err != nil {
+		return approved
+	}
+
+	for _, accountAmount := range body.GetCryptoTransfer().GetTransfers().GetAccountAmounts() {
+		if !accountAmount.GetIsApproval() {
+			continue
+		}
+
+		accountId := accountAmount.GetAccountID()
+		sdkAccountId := hedera.AccountID{
+			Shard:   uint64(accountId.GetShardNum()),
+			Realm:   uint64(accountId.GetRealmNum()),
+			Account: uint64(accountId.GetAccountNum()),
+		}
+		approved[sdkAccountId.String()] = true
+	}
+
+	return approved
 }
 
 func getDomainToken(token hedera.TokenID, tokenDecimals map[hedera.TokenID]uint32, tokenType string) (
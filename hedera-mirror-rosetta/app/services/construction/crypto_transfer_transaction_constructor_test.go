@@ -124,6 +124,81 @@ func (suite *cryptoTransferTransactionConstructorSuite) TestConstruct() {
 	}
 }
 
+func (suite *cryptoTransferTransactionConstructorSuite) TestConstructSignersInDebitOrder() {
+	// given
+	operations := types.OperationSlice{
+		{AccountId: accountIdB, Amount: &types.HbarAmount{Value: -15}, Type: types.OperationTypeCryptoTransfer},
+		{AccountId: accountIdA, Amount: &types.HbarAmount{Value: 15}, Type: types.OperationTypeCryptoTransfer},
+		{AccountId: accountIdA, Amount: types.NewTokenAmount(dbTokenA, -25), Type: types.OperationTypeCryptoTransfer},
+		{AccountId: accountIdB, Amount: types.NewTokenAmount(dbTokenA, 25), Type: types.OperationTypeCryptoTransfer},
+		{AccountId: accountIdB, Amount: types.NewTokenAmount(dbTokenB, -30), Type: types.OperationTypeCryptoTransfer},
+		{AccountId: accountIdA, Amount: types.NewTokenAmount(dbTokenB, 30), Type: types.OperationTypeCryptoTransfer},
+	}
+	h := newCryptoTransferTransactionConstructor()
+
+	for i := 0; i < 10; i++ {
+		// when
+		_, signers, err := h.Construct(defaultContext, operations)
+
+		// then
+		suite.Nil(err)
+		suite.Equal([]types.AccountId{accountIdB, accountIdA}, signers)
+	}
+}
+
+func (suite *cryptoTransferTransactionConstructorSuite) TestConstructParseApprovedTransfers() {
+	// given
+	operations := types.OperationSlice{
+		{AccountId: accountIdA, Amount: &types.HbarAmount{Value: -15}, Type: types.OperationTypeApprovedTransfer},
+		{AccountId: accountIdB, Amount: &types.HbarAmount{Value: 15}, Type: types.OperationTypeCryptoTransfer},
+		{AccountId: accountIdA, Amount: types.NewTokenAmount(dbTokenA, -25), Type: types.OperationTypeApprovedTransfer},
+		{AccountId: accountIdB, Amount: types.NewTokenAmount(dbTokenA, 25), Type: types.OperationTypeCryptoTransfer},
+		{AccountId: accountIdB, Amount: types.NewTokenAmount(dbTokenB, -30), Type: types.OperationTypeCryptoTransfer},
+		{AccountId: accountIdA, Amount: types.NewTokenAmount(dbTokenB, 30), Type: types.OperationTypeCryptoTransfer},
+		{
+			AccountId: accountIdA,
+			Amount:    types.NewTokenAmount(dbTokenC, -1).SetSerialNumbers(defaultSerialNumbers),
+			Type:      types.OperationTypeApprovedTransfer,
+		},
+		{
+			AccountId: accountIdB,
+			Amount:    types.NewTokenAmount(dbTokenC, 1).SetSerialNumbers(defaultSerialNumbers),
+			Type:      types.OperationTypeCryptoTransfer,
+		},
+	}
+	h := newCryptoTransferTransactionConstructor()
+
+	// when
+	tx, signers, err := h.Construct(defaultContext, operations)
+
+	// then
+	suite.Nil(err)
+	suite.ElementsMatch([]types.AccountId{accountIdB}, signers)
+	assertCryptoTransferTransaction(suite.T(), operations, tx)
+
+	// when
+	transferTransaction := tx.(*hedera.TransferTransaction)
+	_, freezeErr := transferTransaction.
+		SetNodeAccountIDs([]hedera.AccountID{{Account: 3}}).
+		SetTransactionID(hedera.TransactionIDGenerate(sdkAccountIdB)).
+		Freeze()
+	parsedOperations, parsedSigners, err := h.Parse(defaultContext, transferTransaction)
+
+	// then
+	suite.Nil(freezeErr)
+	suite.Nil(err)
+	suite.ElementsMatch([]types.AccountId{accountIdB}, parsedSigners)
+	expected := make([]string, 0, len(operations))
+	for _, operation := range operations {
+		expected = append(expected, operation.Type+"_"+operationTransferStringify(operation))
+	}
+	actual := make([]string, 0, len(parsedOperations))
+	for _, operation := range parsedOperations {
+		actual = append(actual, operation.Type+"_"+operationTransferStringify(operation))
+	}
+	suite.ElementsMatch(expected, actual)
+}
+
 func (suite *cryptoTransferTransactionConstructorSuite) TestParse() {
 	defaultGetTransaction := func() interfaces.Transaction {
 		return hedera.NewTransferTransaction().
@@ -281,6 +356,30 @@ func (suite *cryptoTransferTransactionConstructorSuite) TestPreprocess() {
 			transfers:   []transferOperation{{accountId: accountIdA, amount: &types.HbarAmount{}}},
 			expectError: true,
 		},
+		{
+			name: "ApprovedTransferSenderNotSigner",
+			operations: types.OperationSlice{
+				{
+					AccountId: accountIdA,
+					Amount:    &types.HbarAmount{Value: -15},
+					Type:      types.OperationTypeApprovedTransfer,
+				},
+				{AccountId: accountIdB, Amount: &types.HbarAmount{Value: 15}, Type: types.OperationTypeCryptoTransfer},
+			},
+			expectedSigners: []types.AccountId{},
+		},
+		{
+			name: "ApprovedTransferCredit",
+			operations: types.OperationSlice{
+				{AccountId: accountIdA, Amount: &types.HbarAmount{Value: -15}, Type: types.OperationTypeCryptoTransfer},
+				{
+					AccountId: accountIdB,
+					Amount:    &types.HbarAmount{Value: 15},
+					Type:      types.OperationTypeApprovedTransfer,
+				},
+			},
+			expectError: true,
+		},
 
 		{
 			name: "InvalidHbarSum",
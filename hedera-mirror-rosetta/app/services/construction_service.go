@@ -27,52 +27,103 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math/big"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/coinbase/rosetta-sdk-go/server"
 	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/services/construction"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/tools"
+	"github.com/hashgraph/hedera-protobufs-go/sdk"
 	"github.com/hashgraph/hedera-protobufs-go/services"
 	"github.com/hashgraph/hedera-sdk-go/v2"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
 )
 
 const (
-	maxValidDurationSeconds         = 180
-	defaultValidDurationSeconds     = maxValidDurationSeconds
-	metadataKeyAccountMap           = "account_map"
-	metadataKeyValidDurationSeconds = "valid_duration"
-	metadataKeyValidStartNanos      = "valid_start_nanos"
-	optionKeyAccountAliases         = "account_aliases"
-	optionKeyOperationType          = "operation_type"
+	maxValidDurationSeconds               = 180
+	defaultValidDurationSeconds           = maxValidDurationSeconds
+	metadataKeyAccountMap                 = "account_map"
+	metadataKeyIncludePayerInfo           = "include_payer_info"
+	metadataKeyInvalidSignatures          = "invalid_signatures"
+	metadataKeyPayer                      = "payer"
+	metadataKeyPayerInfo                  = "payer_info"
+	metadataKeyThrottleTps                = "throttle_tps"
+	metadataKeyValidDurationSeconds       = "valid_duration"
+	metadataKeyValidStartNanos            = "valid_start_nanos"
+	ecdsaCompressedPublicKeyLength        = 33
+	ecdsaPublicKeyLength                  = 65
+	ecdsaSignatureLength                  = 64 // r || s without the recovery id
+	exchangeRateFileNum             int64 = 112
+	feeScheduleFileNum              int64 = 111
+	maxTransactionSize                    = 6144
+	optionKeyAccountAliases               = "account_aliases"
+	optionKeyOperationType                = "operation_type"
+	optionKeyPayer                        = "payer"
+	optionKeyPayerInfo                    = "payer_info"
+	throttleDefinitionsFileNum      int64 = 123
 )
 
+// frozenTimeNanos is the valid start in nanoseconds of the transactions built without one set in the metadata when it's
+// not 0, so the construction payloads are reproducible in tests
+var frozenTimeNanos int64
+
+// SetFrozenTime freezes the time used as the valid start of the transactions built without one set in the metadata, 0
+// to use the current time. It's not safe to call it concurrently with ConstructionPayloads and should only be called
+// once at startup
+func SetFrozenTime(nanos int64) error {
+	if nanos < 0 {
+		return fmt.Errorf("invalid frozen time %d", nanos)
+	}
+	if nanos != 0 {
+		log.Warnf("Construction time is frozen at %d nanoseconds, it should only be used in tests", nanos)
+	}
+	frozenTimeNanos = nanos
+	return nil
+}
+
+// invalidSignature is a signature which fails the verification against its public key, an unsupported signature type
+// is left empty
+type invalidSignature struct {
+	PublicKey     string               `json:"public_key"`
+	SignatureType rTypes.SignatureType `json:"signature_type,omitempty"`
+}
+
 // constructionAPIService implements the server.ConstructionAPIServicer interface.
 type constructionAPIService struct {
 	BaseService
 	accountRepo              interfaces.AccountRepository
 	defaultMaxTransactionFee map[string]hedera.Hbar
-	hederaClient             *hedera.Client
+	exchangeRate             *systemFileCache[*types.ExchangeRate]
+	feeSchedule              *systemFileCache[types.FeeSchedule]
 	nodeAccountIds           []hedera.AccountID
 	nodeAccountIdsLen        *big.Int
+	signer                   interfaces.Signer
+	submissionWatcher        *SubmissionWatcher
+	submitter                *nodeSubmitter
 	systemShard              int64
 	systemRealm              int64
+	throttles                *systemFileCache[types.Throttles]
 	transactionHandler       construction.TransactionConstructor
 }
 
 // ConstructionCombine implements the /construction/combine endpoint.
 func (c *constructionAPIService) ConstructionCombine(
-	_ context.Context,
+	ctx context.Context,
 	request *rTypes.ConstructionCombineRequest,
 ) (*rTypes.ConstructionCombineResponse, *rTypes.Error) {
-	if len(request.Signatures) == 0 {
+	// the server-side signer can be the only signer of a request allowed to be co-signed by it
+	serverSigning := c.signer != nil && types.IsServerSigningAllowed(ctx)
+	if len(request.Signatures) == 0 && !serverSigning {
 		return nil, errors.ErrNoSignature
 	}
 
@@ -86,6 +137,7 @@ func (c *constructionAPIService) ConstructionCombine(
 		return nil, rErr
 	}
 
+	signedPublicKeys := make(map[string]bool)
 	for _, signature := range request.Signatures {
 		if signature.SignatureType != rTypes.Ed25519 {
 			return nil, errors.ErrInvalidSignatureType
@@ -103,6 +155,17 @@ func (c *constructionAPIService) ConstructionCombine(
 		if rErr = addSignature(transaction, pubKey, signature.Bytes); rErr != nil {
 			return nil, rErr
 		}
+		signedPublicKeys[pubKey.String()] = true
+	}
+
+	if serverSigning {
+		if rErr = c.addServerSignature(ctx, transaction, frozenBodyBytes, signedPublicKeys); rErr != nil {
+			return nil, rErr
+		}
+	}
+
+	if len(signedPublicKeys) == 0 {
+		return nil, errors.ErrNoSignature
 	}
 
 	transactionBytes, err := transaction.ToBytes()
@@ -110,11 +173,104 @@ func (c *constructionAPIService) ConstructionCombine(
 		return nil, errors.ErrTransactionMarshallingFailed
 	}
 
+	if rErr = validateTransactionSize(transactionBytes); rErr != nil {
+		return nil, rErr
+	}
+
+	if c.BaseService.IsOnline() {
+		if rErr = c.validateMaxTransactionFee(ctx, transaction); rErr != nil {
+			return nil, rErr
+		}
+	}
+
 	return &rTypes.ConstructionCombineResponse{
 		SignedTransaction: tools.SafeAddHexPrefix(hex.EncodeToString(transactionBytes)),
 	}, nil
 }
 
+// addServerSignature signs the transaction as the fee payer with the key the server-side signer holds of the payer,
+// unless the request already has a signature of the same key. The server only pays the fees, so a transaction with an
+// operation debiting or otherwise changing the payer account is refused
+func (c *constructionAPIService) addServerSignature(
+	ctx context.Context,
+	transaction interfaces.Transaction,
+	frozenBodyBytes []byte,
+	signedPublicKeys map[string]bool,
+) *rTypes.Error {
+	payer := transaction.GetTransactionID().AccountID
+	if payer == nil {
+		return errors.ErrInvalidTransaction
+	}
+	payerAccountId, err := types.NewAccountIdFromSdkAccountId(*payer)
+	if err != nil {
+		return errors.ErrInvalidAccount
+	}
+
+	operations, _, rErr := c.transactionHandler.Parse(ctx, transaction)
+	if rErr != nil {
+		return rErr
+	}
+
+	publicKey, signature, ok, err := c.signer.Sign(ctx, payerAccountId, frozenBodyBytes)
+	if err != nil {
+		log.Errorf("Failed to sign the transaction with the key of account %s: %s", payerAccountId, err)
+		return errors.ErrServerSigningFailed
+	}
+	if !ok || signedPublicKeys[publicKey.String()] {
+		return nil
+	}
+
+	for _, operation := range operations {
+		if operation.Amount != nil && operation.Amount.GetValue() > 0 {
+			continue
+		}
+
+		isPayer, rErr := c.isPayerAccount(ctx, operation.AccountId, payerAccountId)
+		if rErr != nil {
+			return rErr
+		}
+		if isPayer {
+			log.Warnf("Refused to sign as the fee payer %s with the %s operation of the payer account",
+				payerAccountId, operation.Type)
+			return errors.AddErrorDetails(errors.ErrServerSigningNotAllowed, "reason",
+				fmt.Sprintf("operation %d debits or changes the server-held payer account", operation.Index))
+		}
+	}
+
+	if rErr = addSignature(transaction, publicKey, signature); rErr != nil {
+		return rErr
+	}
+	signedPublicKeys[publicKey.String()] = true
+
+	return nil
+}
+
+// isPayerAccount returns true if the account is the payer account. An alias account is looked up in online mode, and
+// is assumed to be the payer in offline mode
+func (c *constructionAPIService) isPayerAccount(
+	ctx context.Context,
+	accountId types.AccountId,
+	payerAccountId types.AccountId,
+) (bool, *rTypes.Error) {
+	if !accountId.HasAlias() {
+		return accountId.GetId() == payerAccountId.GetId(), nil
+	}
+
+	if !c.IsOnline() {
+		return true, nil
+	}
+
+	resolved, rErr := c.accountRepo.GetAccountId(ctx, accountId)
+	if rErr != nil {
+		if rErr.Code == errors.ErrAccountNotFound.Code {
+			return false, nil
+		}
+		return false, rErr
+	}
+
+	return resolved.GetId() == payerAccountId.GetId(), nil
+}
+
 // ConstructionDerive implements the /construction/derive endpoint.
 func (c *constructionAPIService) ConstructionDerive(
 	_ context.Context,
@@ -177,6 +333,33 @@ func (c *constructionAPIService) ConstructionMetadata(
 		SuggestedFee: []*rTypes.Amount{maxFee.ToRosetta()},
 	}
 
+	if c.BaseService.IsOnline() {
+		throttleTps, err := c.getThrottleTps(ctx, operationType)
+		if err != nil {
+			return nil, err
+		}
+
+		if throttleTps != 0 {
+			response.Metadata[metadataKeyThrottleTps] = throttleTps
+		}
+	}
+
+	if options[optionKeyPayer] != nil {
+		payer, ok := options[optionKeyPayer].(string)
+		if !ok {
+			return nil, errors.ErrInvalidOptions
+		}
+		response.Metadata[metadataKeyPayer] = payer
+	}
+
+	if options[optionKeyPayerInfo] != nil {
+		payerInfo, err := c.getPayerInfo(ctx, options[optionKeyPayerInfo])
+		if err != nil {
+			return nil, err
+		}
+		response.Metadata[metadataKeyPayerInfo] = payerInfo
+	}
+
 	if options[optionKeyAccountAliases] == nil {
 		return response, nil
 	}
@@ -223,17 +406,34 @@ func (c *constructionAPIService) ConstructionParse(
 		return nil, err
 	}
 
+	response := &rTypes.ConstructionParseResponse{Operations: operations.ToRosetta()}
+	if payer, ok := getFeePayerFromTransaction(transaction, accounts); ok {
+		// the transaction fee is charged to the fee payer, which is not one of the accounts in the operations
+		response.Metadata = map[string]interface{}{metadataKeyPayer: payer.String()}
+		accounts = append([]types.AccountId{payer}, accounts...)
+	}
+
 	signers := make([]*rTypes.AccountIdentifier, 0, len(accounts))
 	if request.Signed {
 		for _, account := range accounts {
 			signers = append(signers, account.ToRosetta())
 		}
+
+		signedTransaction, rErr := getSignedTransaction(transaction)
+		if rErr != nil {
+			return nil, rErr
+		}
+
+		if invalidSignatures := getInvalidSignatures(signedTransaction); len(invalidSignatures) != 0 {
+			if response.Metadata == nil {
+				response.Metadata = make(map[string]interface{})
+			}
+			response.Metadata[metadataKeyInvalidSignatures] = invalidSignatures
+		}
 	}
+	response.AccountIdentifierSigners = signers
 
-	return &rTypes.ConstructionParseResponse{
-		Operations:               operations.ToRosetta(),
-		AccountIdentifierSigners: signers,
-	}, nil
+	return response, nil
 }
 
 // ConstructionPayloads implements the /construction/payloads endpoint.
@@ -250,6 +450,9 @@ func (c *constructionAPIService) ConstructionPayloads(
 	if rErr != nil {
 		return nil, rErr
 	}
+	if validStartNanos == 0 {
+		validStartNanos = frozenTimeNanos
+	}
 
 	operations, rErr := c.getOperationSlice(request.Operations)
 	if rErr != nil {
@@ -261,7 +464,19 @@ func (c *constructionAPIService) ConstructionPayloads(
 		return nil, rErr
 	}
 
-	// signers[0] is always the payer account id
+	// signers[0] is the payer account id unless a distinct fee payer is set in the metadata
+	feePayer, ok, rErr := c.getFeePayer(request.Metadata)
+	if rErr != nil {
+		return nil, rErr
+	}
+	if ok {
+		signers = prependSigner(signers, feePayer)
+	}
+	if len(signers) == 0 {
+		// all operations are approved transfers, the spender must be set as the payer in the metadata
+		return nil, errors.AddErrorDetails(errors.ErrInvalidOperations, "reason", "payer is required")
+	}
+
 	payer, rErr := c.getSdkPayerAccountId(signers[0], request.Metadata[metadataKeyAccountMap])
 	if rErr != nil {
 		return nil, rErr
@@ -269,7 +484,7 @@ func (c *constructionAPIService) ConstructionPayloads(
 
 	if rErr = updateTransaction(
 		transaction,
-		transactionSetNodeAccountId(c.getRandomNodeAccountId()),
+		transactionSetNodeAccountId(c.getNodeAccountId(validStartNanos)),
 		transactionSetTransactionId(payer, validStartNanos),
 		transactionSetValidDuration(validDurationSeconds),
 		transactionFreeze,
@@ -317,13 +532,27 @@ func (c *constructionAPIService) ConstructionPreprocess(
 		return nil, err
 	}
 
+	options := map[string]interface{}{optionKeyOperationType: operations[0].Type}
+	feePayer, ok, rErr := c.getFeePayer(request.Metadata)
+	if rErr != nil {
+		return nil, rErr
+	}
+	if ok {
+		options[optionKeyPayer] = feePayer.String()
+		signers = prependSigner(signers, feePayer)
+	}
+	if len(signers) == 0 {
+		// all operations are approved transfers, the spender must be set as the payer in the metadata
+		return nil, errors.AddErrorDetails(errors.ErrInvalidOperations, "reason", "payer is required")
+	}
+
 	requiredPublicKeys := make([]*rTypes.AccountIdentifier, 0, len(signers))
 	for _, signer := range signers {
-		requiredPublicKeys = append(requiredPublicKeys, &rTypes.AccountIdentifier{Address: signer.String()})
+		requiredPublicKeys = append(requiredPublicKeys, signer.ToRosetta())
 	}
 
 	response := &rTypes.ConstructionPreprocessResponse{
-		Options:            map[string]interface{}{optionKeyOperationType: operations[0].Type},
+		Options:            options,
 		RequiredPublicKeys: requiredPublicKeys,
 	}
 
@@ -333,12 +562,22 @@ func (c *constructionAPIService) ConstructionPreprocess(
 		response.Options[optionKeyAccountAliases] = fmt.Sprintf("%s", payer)
 	}
 
+	if request.Metadata != nil && request.Metadata[metadataKeyIncludePayerInfo] != nil {
+		includePayerInfo, ok := request.Metadata[metadataKeyIncludePayerInfo].(bool)
+		if !ok {
+			return nil, errors.ErrInvalidArgument
+		}
+		if includePayerInfo {
+			response.Options[optionKeyPayerInfo] = payer.String()
+		}
+	}
+
 	return response, nil
 }
 
 // ConstructionSubmit implements the /construction/submit endpoint.
 func (c *constructionAPIService) ConstructionSubmit(
-	_ context.Context,
+	ctx context.Context,
 	request *rTypes.ConstructionSubmitRequest,
 ) (*rTypes.TransactionIdentifierResponse, *rTypes.Error) {
 	if !c.IsOnline() {
@@ -356,11 +595,14 @@ func (c *constructionAPIService) ConstructionSubmit(
 	}
 
 	hash := tools.SafeAddHexPrefix(hex.EncodeToString(hashBytes))
-	log.Infof("Submitting transaction %s (hash %s) to node %s", transaction.GetTransactionID(),
-		hash, transaction.GetNodeAccountIDs()[0])
+	nodeAccountId := transaction.GetNodeAccountIDs()[0]
+	log.Infof("Submitting transaction %s (hash %s) to node %s", transaction.GetTransactionID(), hash, nodeAccountId)
+
+	if err = c.submitter.Submit(ctx, transaction); err != nil {
+		if _, ok := err.(*certificateVerificationError); ok {
+			return nil, errors.AddErrorDetails(errors.ErrNodeCertificateVerificationFailed, "reason", err.Error())
+		}
 
-	_, err = transaction.Execute(c.hederaClient)
-	if err != nil {
 		log.Errorf("Failed to execute transaction %s: %s", transaction.GetTransactionID(), err)
 		return nil, errors.AddErrorDetails(
 			errors.ErrTransactionSubmissionFailed,
@@ -369,6 +611,14 @@ func (c *constructionAPIService) ConstructionSubmit(
 		)
 	}
 
+	if c.submissionWatcher != nil {
+		if validStart := transaction.GetTransactionID().ValidStart; validStart != nil {
+			validStartNanos := validStart.UnixNano()
+			validEndNanos := validStartNanos + transaction.GetTransactionValidDuration().Nanoseconds()
+			c.submissionWatcher.Track(hash, validStartNanos, validEndNanos)
+		}
+	}
+
 	return &rTypes.TransactionIdentifierResponse{
 		TransactionIdentifier: &rTypes.TransactionIdentifier{Hash: hash},
 	}, nil
@@ -402,13 +652,65 @@ func (c *constructionAPIService) getOperationSlice(operations []*rTypes.Operatio
 			Amount:    amount,
 			Index:     operation.OperationIdentifier.Index,
 			Metadata:  operation.Metadata,
-			Type:      operation.Type,
+			Type:      types.FromOperationTypeName(operation.Type),
 		})
 	}
 
 	return operationSlice, nil
 }
 
+// getFeePayer returns the fee payer account set in the metadata, which pays the transaction fee instead of the first
+// signer, e.g., a treasury account sponsoring the fees of transfers from other accounts
+func (c *constructionAPIService) getFeePayer(metadata map[string]interface{}) (types.AccountId, bool, *rTypes.Error) {
+	if metadata == nil || metadata[metadataKeyPayer] == nil {
+		return types.AccountId{}, false, nil
+	}
+
+	address, ok := metadata[metadataKeyPayer].(string)
+	if !ok {
+		return types.AccountId{}, false, errors.ErrInvalidAccount
+	}
+
+	payer, err := types.NewAccountIdFromString(address, c.systemShard, c.systemRealm)
+	if err != nil {
+		return types.AccountId{}, false, errors.ErrInvalidAccount
+	}
+
+	return payer, true, nil
+}
+
+// getPayerInfo looks up the payer's current key, auto renew period, and expiration, so offline signers know which
+// signatures the payer account requires. The account is reported as not existing if it's not found or is deleted
+func (c *constructionAPIService) getPayerInfo(ctx context.Context, payerOption interface{}) (
+	map[string]interface{},
+	*rTypes.Error,
+) {
+	if !c.BaseService.IsOnline() {
+		return nil, errors.ErrEndpointNotSupportedInOfflineMode
+	}
+
+	address, ok := payerOption.(string)
+	if !ok {
+		return nil, errors.ErrInvalidOptions
+	}
+
+	payer, err := types.NewAccountIdFromString(address, c.systemShard, c.systemRealm)
+	if err != nil {
+		return nil, errors.ErrInvalidAccount
+	}
+
+	accountInfo, rErr := c.accountRepo.GetAccountInfo(ctx, payer)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	if accountInfo == nil {
+		return map[string]interface{}{"account_id": address, "exists": false}, nil
+	}
+
+	return accountInfo.ToMetadata(), nil
+}
+
 func (c *constructionAPIService) getSdkPayerAccountId(payerAccountId types.AccountId, accountMapMetadata interface{}) (
 	zero hedera.AccountID,
 	_ *rTypes.Error,
@@ -449,6 +751,16 @@ func (c *constructionAPIService) getSdkPayerAccountId(payerAccountId types.Accou
 	return payer, nil
 }
 
+// getNodeAccountId returns the node account id for the transaction with the valid start. The node is selected by the
+// valid start if it's set, so the same inputs always build the same transaction bytes, otherwise it's random
+func (c *constructionAPIService) getNodeAccountId(validStartNanos int64) hedera.AccountID {
+	if validStartNanos == 0 {
+		return c.getRandomNodeAccountId()
+	}
+
+	return c.nodeAccountIds[validStartNanos%int64(len(c.nodeAccountIds))]
+}
+
 func (c *constructionAPIService) getRandomNodeAccountId() hedera.AccountID {
 	index, err := rand.Int(rand.Reader, c.nodeAccountIdsLen)
 	if err != nil {
@@ -459,6 +771,70 @@ func (c *constructionAPIService) getRandomNodeAccountId() hedera.AccountID {
 	return c.nodeAccountIds[index.Int64()]
 }
 
+// getThrottleTps returns the network throttle of the operation type in transactions per second from the latest
+// throttle definitions file, or 0 if it's unknown
+func (c *constructionAPIService) getThrottleTps(ctx context.Context, operationType string) (float64, *rTypes.Error) {
+	throttles, _, rErr := c.throttles.get(ctx)
+	if rErr != nil {
+		return 0, rErr
+	}
+
+	return throttles[operationType], nil
+}
+
+// validateMaxTransactionFee validates the max transaction fee is not below the estimated minimum fee of the transaction
+// in the current fee schedule of the network at the current exchange rate. The validation is skipped if either the fee
+// schedule or the exchange rate is unknown
+func (c *constructionAPIService) validateMaxTransactionFee(
+	ctx context.Context,
+	transaction interfaces.Transaction,
+) *rTypes.Error {
+	functionality, ok := getHederaFunctionality(transaction)
+	if !ok {
+		return nil
+	}
+
+	feeSchedule, ok, rErr := c.feeSchedule.get(ctx)
+	if rErr != nil || !ok {
+		return rErr
+	}
+
+	exchangeRate, ok, rErr := c.exchangeRate.get(ctx)
+	if rErr != nil || !ok {
+		return rErr
+	}
+
+	signedTransaction, rErr := getSignedTransaction(transaction)
+	if rErr != nil {
+		return rErr
+	}
+
+	size := int64(proto.Size(signedTransaction))
+	signatures := int64(len(signedTransaction.GetSigMap().GetSigPair()))
+	minFeeTinycents, ok := feeSchedule.MinFeeTinycents(functionality, size, signatures)
+	if !ok {
+		return nil
+	}
+
+	// the sdk doesn't restore the max transaction fee of a deserialized transaction, so read it from the body
+	var body services.TransactionBody
+	if err := proto.Unmarshal(signedTransaction.GetBodyBytes(), &body); err != nil {
+		return errors.ErrTransactionUnmarshallingFailed
+	}
+
+	maxFee := int64(body.GetTransactionFee())
+	minFee := exchangeRate.TinycentsToTinybars(minFeeTinycents)
+	if maxFee < minFee {
+		return errors.AddErrorDetails(
+			errors.ErrMaxTransactionFeeTooLow,
+			"reason",
+			fmt.Sprintf("max transaction fee %d tinybars is below the estimated minimum fee %d tinybars", maxFee, minFee),
+		)
+	}
+
+	return nil
+}
+
 func (c *constructionAPIService) getIntMetadataValue(metadata map[string]interface{}, metadataKey string) (int64, *rTypes.Error) {
 	var metadataValue int64
 	if metadata != nil && metadata[metadataKey] != nil {
@@ -485,11 +861,16 @@ func isValidTransactionValidDuration(validDuration int64) bool {
 func NewConstructionAPIService(
 	accountRepo interfaces.AccountRepository,
 	baseService BaseService,
+	fileDataRepo interfaces.FileDataRepository,
 	network string,
 	nodes config.NodeMap,
+	submitConfig config.Submit,
 	systemShard int64,
 	systemRealm int64,
 	transactionConstructor construction.TransactionConstructor,
+	signer interfaces.Signer,
+	submissionWatcher *SubmissionWatcher,
+	certificateVerifier interfaces.CertificateVerifier,
 ) (server.ConstructionAPIServicer, error) {
 	var err error
 	var hederaClient *hedera.Client
@@ -506,27 +887,119 @@ func NewConstructionAPIService(
 		return nil, err
 	}
 
-	// disable SDK auto retry
-	hederaClient.SetMaxAttempts(1)
+	if certificateVerifier != nil {
+		// submit to the TLS endpoints of the nodes, whose certificates are pinned to the address book
+		log.Info("Submitting transactions over TLS with the node certificates pinned to the address book")
+		hederaClient.SetTransportSecurity(true)
+	}
+
+	submitter, err := newNodeSubmitter(hederaClient.GetNetwork(), submitConfig, certificateVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	exchangeRateFileId, err := domain.EncodeEntityId(systemShard, systemRealm, exchangeRateFileNum)
+	if err != nil {
+		return nil, err
+	}
+
+	feeScheduleFileId, err := domain.EncodeEntityId(systemShard, systemRealm, feeScheduleFileNum)
+	if err != nil {
+		return nil, err
+	}
+
+	throttleFileId, err := domain.EncodeEntityId(systemShard, systemRealm, throttleDefinitionsFileNum)
+	if err != nil {
+		return nil, err
+	}
 
 	networkMap := hederaClient.GetNetwork()
 	nodeAccountIds := make([]hedera.AccountID, 0, len(networkMap))
 	for _, nodeAccountId := range networkMap {
 		nodeAccountIds = append(nodeAccountIds, nodeAccountId)
 	}
+	// sort the node account ids since the order of the network map entries is random
+	sort.Slice(nodeAccountIds, func(i, j int) bool { return nodeAccountIds[i].Compare(nodeAccountIds[j]) < 0 })
+
+	exchangeRate := newSystemFileCache(fileDataRepo, exchangeRateFileId, "exchange rate", types.NewExchangeRateFromBytes)
+	feeSchedule := newSystemFileCache(fileDataRepo, feeScheduleFileId, "fee schedule", types.NewFeeScheduleFromBytes)
+	throttles := newSystemFileCache(fileDataRepo, throttleFileId, "throttle definitions", types.NewThrottlesFromBytes)
 
 	return &constructionAPIService{
 		accountRepo:        accountRepo,
 		BaseService:        baseService,
-		hederaClient:       hederaClient,
+		exchangeRate:       exchangeRate,
+		feeSchedule:        feeSchedule,
 		nodeAccountIds:     nodeAccountIds,
 		nodeAccountIdsLen:  big.NewInt(int64(len(nodeAccountIds))),
+		signer:             signer,
+		submissionWatcher:  submissionWatcher,
+		submitter:          submitter,
 		systemShard:        systemShard,
 		systemRealm:        systemRealm,
+		throttles:          throttles,
 		transactionHandler: transactionConstructor,
 	}, nil
 }
 
+// getHederaFunctionality returns the hedera functionality of the transaction in the network fee schedule
+func getHederaFunctionality(transaction interfaces.Transaction) (services.HederaFunctionality, bool) {
+	switch transaction.(type) {
+	// these transaction types are what the construction service supports
+	case *hedera.AccountCreateTransaction:
+		return services.HederaFunctionality_CryptoCreate, true
+	case *hedera.TokenAssociateTransaction:
+		return services.HederaFunctionality_TokenAssociateToAccount, true
+	case *hedera.TokenBurnTransaction:
+		return services.HederaFunctionality_TokenBurn, true
+	case *hedera.TokenCreateTransaction:
+		return services.HederaFunctionality_TokenCreate, true
+	case *hedera.TokenDeleteTransaction:
+		return services.HederaFunctionality_TokenDelete, true
+	case *hedera.TokenDissociateTransaction:
+		return services.HederaFunctionality_TokenDissociateFromAccount, true
+	case *hedera.TokenFreezeTransaction:
+		return services.HederaFunctionality_TokenFreezeAccount, true
+	case *hedera.TokenGrantKycTransaction:
+		return services.HederaFunctionality_TokenGrantKycToAccount, true
+	case *hedera.TokenMintTransaction:
+		return services.HederaFunctionality_TokenMint, true
+	case *hedera.TokenRevokeKycTransaction:
+		return services.HederaFunctionality_TokenRevokeKycFromAccount, true
+	case *hedera.TokenUnfreezeTransaction:
+		return services.HederaFunctionality_TokenUnfreezeAccount, true
+	case *hedera.TokenUpdateTransaction:
+		return services.HederaFunctionality_TokenUpdate, true
+	case *hedera.TokenWipeTransaction:
+		return services.HederaFunctionality_TokenAccountWipe, true
+	case *hedera.TransferTransaction:
+		return services.HederaFunctionality_CryptoTransfer, true
+	default:
+		return services.HederaFunctionality_NONE, false
+	}
+}
+
+// validateTransactionSize validates the size of each signed transaction in the serialized transaction list is within
+// the network limit
+func validateTransactionSize(transactionBytes []byte) *rTypes.Error {
+	var transactionList sdk.TransactionList
+	if err := proto.Unmarshal(transactionBytes, &transactionList); err != nil {
+		return errors.ErrTransactionMarshallingFailed
+	}
+
+	for _, transaction := range transactionList.GetTransactionList() {
+		if size := proto.Size(transaction); size > maxTransactionSize {
+			return errors.AddErrorDetails(
+				errors.ErrTransactionSizeExceeded,
+				"reason",
+				fmt.Sprintf("transaction size %d bytes exceeds the limit of %d bytes", size, maxTransactionSize),
+			)
+		}
+	}
+
+	return nil
+}
+
 func addSignature(transaction interfaces.Transaction, pubKey hedera.PublicKey, signature []byte) *rTypes.Error {
 	switch tx := transaction.(type) {
 	// these transaction types are what the construction service supports
@@ -565,15 +1038,94 @@ func addSignature(transaction interfaces.Transaction, pubKey hedera.PublicKey, s
 	return nil
 }
 
+// getFeePayerFromTransaction returns the payer of the transaction if it's not one of the accounts. Note the payer can't
+// be told apart from an alias account without the account map, so it's not returned if any account has an alias
+func getFeePayerFromTransaction(transaction interfaces.Transaction, accounts []types.AccountId) (
+	types.AccountId,
+	bool,
+) {
+	sdkPayer := transaction.GetTransactionID().AccountID
+	if sdkPayer == nil {
+		return types.AccountId{}, false
+	}
+
+	payer, err := types.NewAccountIdFromSdkAccountId(*sdkPayer)
+	if err != nil {
+		return types.AccountId{}, false
+	}
+
+	for _, account := range accounts {
+		if account.HasAlias() || account.String() == payer.String() {
+			return types.AccountId{}, false
+		}
+	}
+
+	return payer, true
+}
+
 func getFrozenTransactionBodyBytes(transaction interfaces.Transaction) ([]byte, *rTypes.Error) {
-	signedTransaction := services.SignedTransaction{}
-	if err := prototext.Unmarshal([]byte(transaction.String()), &signedTransaction); err != nil {
-		return nil, errors.ErrTransactionUnmarshallingFailed
+	signedTransaction, rErr := getSignedTransaction(transaction)
+	if rErr != nil {
+		return nil, rErr
 	}
 
 	return signedTransaction.BodyBytes, nil
 }
 
+// getInvalidSignatures verifies each signature in the signature map against its public key and returns the invalid
+// ones. The ED25519 signature is verified over the body bytes, and the ECDSA(secp256k1) signature is verified over the
+// keccak256 hash of the body bytes. Any other signature type is reported as invalid since the network rejects it
+func getInvalidSignatures(signedTransaction *services.SignedTransaction) []invalidSignature {
+	invalidSignatures := make([]invalidSignature, 0)
+	for _, sigPair := range signedTransaction.GetSigMap().GetSigPair() {
+		publicKey := sigPair.GetPubKeyPrefix()
+		var signatureType rTypes.SignatureType
+		var valid bool
+		switch signature := sigPair.GetSignature().(type) {
+		case *services.SignaturePair_Ed25519:
+			signatureType = rTypes.Ed25519
+			valid = len(publicKey) == ed25519.PublicKeySize &&
+				ed25519.Verify(publicKey, signedTransaction.BodyBytes, signature.Ed25519)
+		case *services.SignaturePair_ECDSASecp256K1:
+			hash := crypto.Keccak256(signedTransaction.BodyBytes)
+			signatureType = rTypes.Ecdsa
+			valid = len(signature.ECDSASecp256K1) == ecdsaSignatureLength &&
+				(len(publicKey) == ecdsaCompressedPublicKeyLength || len(publicKey) == ecdsaPublicKeyLength) &&
+				crypto.VerifySignature(publicKey, hash, signature.ECDSASecp256K1)
+		}
+
+		if !valid {
+			invalidSignatures = append(invalidSignatures, invalidSignature{
+				PublicKey:     tools.SafeAddHexPrefix(hex.EncodeToString(publicKey)),
+				SignatureType: signatureType,
+			})
+		}
+	}
+
+	return invalidSignatures
+}
+
+func getSignedTransaction(transaction interfaces.Transaction) (*services.SignedTransaction, *rTypes.Error) {
+	signedTransaction := &services.SignedTransaction{}
+	if err := prototext.Unmarshal([]byte(transaction.String()), signedTransaction); err != nil {
+		return nil, errors.ErrTransactionUnmarshallingFailed
+	}
+
+	return signedTransaction, nil
+}
+
+// prependSigner returns the signers with the signer moved or added to the front
+func prependSigner(signers []types.AccountId, signer types.AccountId) []types.AccountId {
+	result := make([]types.AccountId, 0, len(signers)+1)
+	result = append(result, signer)
+	for _, account := range signers {
+		if account.String() != signer.String() {
+			result = append(result, account)
+		}
+	}
+	return result
+}
+
 func unmarshallTransactionFromHexString(transactionString string) (interfaces.Transaction, *rTypes.Error) {
 	transactionBytes, err := hex.DecodeString(tools.SafeRemoveHexPrefix(transactionString))
 	if err != nil {
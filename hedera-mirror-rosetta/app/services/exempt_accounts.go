@@ -0,0 +1,115 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"sort"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+)
+
+// maxSystemAccountNum is the largest entity num reserved for the system accounts
+const maxSystemAccountNum = 1000
+
+// ExemptAccount is an entry of the rosetta-cli exempt accounts file
+type ExemptAccount struct {
+	AccountIdentifier *rTypes.AccountIdentifier `json:"account_identifier"`
+	Currency          *rTypes.Currency          `json:"currency"`
+}
+
+// ExportExemptAccounts writes the accounts with known non-transactional hbar balance changes to the writer in the
+// rosetta-cli exempt accounts format, so check:data skips reconciling them. The accounts are the configured system
+// accounts and the system accounts, i.e., 0.0.1 to 0.0.1000 of the shard and realm, with a genesis balance. When
+// includeGenesis is true, all accounts with a genesis balance are included, which is needed when check:data starts
+// from the genesis block without bootstrap balances. It returns the number of accounts written
+func ExportExemptAccounts(
+	ctx context.Context,
+	accountRepo interfaces.AccountRepository,
+	shard, realm int64,
+	systemAccounts config.SystemAccounts,
+	includeGenesis bool,
+	writer io.Writer,
+) (int, *rTypes.Error) {
+	minAccountId, maxAccountId := int64(0), int64(math.MaxInt64)
+	if !includeGenesis {
+		minEntityId, err := domain.EntityIdOf(shard, realm, 1)
+		if err != nil {
+			return 0, errors.AddErrorDetails(errors.ErrInvalidAccount, "reason", err.Error())
+		}
+		maxEntityId, err := domain.EntityIdOf(shard, realm, maxSystemAccountNum)
+		if err != nil {
+			return 0, errors.AddErrorDetails(errors.ErrInvalidAccount, "reason", err.Error())
+		}
+		minAccountId, maxAccountId = minEntityId.EncodedId, maxEntityId.EncodedId
+	}
+
+	genesisAccountIds, rErr := accountRepo.RetrieveGenesisAccounts(ctx, minAccountId, maxAccountId)
+	if rErr != nil {
+		return 0, rErr
+	}
+
+	accountIds := make(map[int64]bool, len(genesisAccountIds))
+	for _, accountId := range genesisAccountIds {
+		accountIds[accountId] = true
+	}
+	for account := range systemAccounts.ToMap() {
+		entityId, err := domain.EntityIdFromString(account)
+		if err != nil {
+			return 0, errors.AddErrorDetails(errors.ErrInvalidAccount, "reason", err.Error())
+		}
+		accountIds[entityId.EncodedId] = true
+	}
+
+	sortedAccountIds := make([]int64, 0, len(accountIds))
+	for accountId := range accountIds {
+		sortedAccountIds = append(sortedAccountIds, accountId)
+	}
+	sort.Slice(sortedAccountIds, func(i, j int) bool { return sortedAccountIds[i] < sortedAccountIds[j] })
+
+	exemptAccounts := make([]ExemptAccount, 0, len(sortedAccountIds))
+	for _, accountId := range sortedAccountIds {
+		entityId, err := domain.DecodeEntityId(accountId)
+		if err != nil {
+			return 0, errors.AddErrorDetails(errors.ErrInternalServerError, "reason", err.Error())
+		}
+
+		exemptAccounts = append(exemptAccounts, ExemptAccount{
+			AccountIdentifier: types.NewAccountIdFromEntityId(entityId).ToRosetta(),
+			Currency:          types.CurrencyHbar,
+		})
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(exemptAccounts); err != nil {
+		return 0, errors.AddErrorDetails(errors.ErrInternalServerError, "reason", err.Error())
+	}
+
+	return len(exemptAccounts), nil
+}
@@ -0,0 +1,156 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	hErrors "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+var exemptSystemAccounts = config.SystemAccounts{
+	FeeCollection: "0.0.98",
+	NodeReward:    "0.0.801",
+	StakingReward: "0.0.800",
+}
+
+func TestExemptAccountsSuite(t *testing.T) {
+	suite.Run(t, new(exemptAccountsSuite))
+}
+
+type exemptAccountsSuite struct {
+	suite.Suite
+	mockAccountRepo *mocks.MockAccountRepository
+}
+
+func (suite *exemptAccountsSuite) SetupTest() {
+	suite.mockAccountRepo = &mocks.MockAccountRepository{}
+}
+
+func (suite *exemptAccountsSuite) TestExportExemptAccounts() {
+	// given
+	suite.mockAccountRepo.On("RetrieveGenesisAccounts").Return([]int64{2, 98, 800}, mocks.NilError)
+	buf := &bytes.Buffer{}
+	hbar := map[string]interface{}{
+		"symbol":   "HBAR",
+		"decimals": float64(8),
+		"metadata": map[string]interface{}{"issuer": "Hedera"},
+	}
+	expected := make([]interface{}, 0)
+	for _, address := range []string{"0.0.2", "0.0.98", "0.0.800", "0.0.801"} {
+		expected = append(expected, map[string]interface{}{
+			"account_identifier": map[string]interface{}{"address": address},
+			"currency":           hbar,
+		})
+	}
+
+	// when
+	count, err := ExportExemptAccounts(defaultContext, suite.mockAccountRepo, 0, 0, exemptSystemAccounts, false, buf)
+
+	// then
+	var actual []interface{}
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 4, count)
+	assert.NoError(suite.T(), json.Unmarshal(buf.Bytes(), &actual))
+	assert.Equal(suite.T(), expected, actual)
+	suite.mockAccountRepo.AssertCalled(suite.T(), "RetrieveGenesisAccounts")
+}
+
+func (suite *exemptAccountsSuite) TestExportExemptAccountsSystemAccountRange() {
+	// given
+	suite.mockAccountRepo.On("RetrieveGenesisAccounts").Return([]int64{}, mocks.NilError)
+	buf := &bytes.Buffer{}
+
+	// when
+	count, err := ExportExemptAccounts(defaultContext, suite.mockAccountRepo, 0, 0, config.SystemAccounts{}, false, buf)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Zero(suite.T(), count)
+	assert.Equal(suite.T(), "[]\n", buf.String())
+}
+
+func (suite *exemptAccountsSuite) TestExportExemptAccountsIncludeGenesis() {
+	// given
+	suite.mockAccountRepo.On("RetrieveGenesisAccounts").Return([]int64{2, 1001, 1002}, mocks.NilError)
+	buf := &bytes.Buffer{}
+
+	// when
+	count, err := ExportExemptAccounts(defaultContext, suite.mockAccountRepo, 0, 0, exemptSystemAccounts, true, buf)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 6, count)
+	assert.Contains(suite.T(), buf.String(), `"address": "0.0.1002"`)
+}
+
+func (suite *exemptAccountsSuite) TestExportExemptAccountsInvalidShard() {
+	// given
+	buf := &bytes.Buffer{}
+
+	// when
+	count, err := ExportExemptAccounts(defaultContext, suite.mockAccountRepo, -1, 0, exemptSystemAccounts, false, buf)
+
+	// then
+	assert.Equal(suite.T(), hErrors.ErrInvalidAccount.Code, err.Code)
+	assert.Zero(suite.T(), count)
+	assert.Zero(suite.T(), buf.Len())
+	suite.mockAccountRepo.AssertNotCalled(suite.T(), "RetrieveGenesisAccounts")
+}
+
+func (suite *exemptAccountsSuite) TestExportExemptAccountsRetrieveGenesisAccountsFails() {
+	// given
+	suite.mockAccountRepo.On("RetrieveGenesisAccounts").Return([]int64(nil), hErrors.ErrNodeIsStarting)
+	buf := &bytes.Buffer{}
+
+	// when
+	count, err := ExportExemptAccounts(defaultContext, suite.mockAccountRepo, 0, 0, exemptSystemAccounts, false, buf)
+
+	// then
+	assert.Equal(suite.T(), hErrors.ErrNodeIsStarting, err)
+	assert.Zero(suite.T(), count)
+	assert.Zero(suite.T(), buf.Len())
+}
+
+func (suite *exemptAccountsSuite) TestExportExemptAccountsWriteFails() {
+	// given
+	suite.mockAccountRepo.On("RetrieveGenesisAccounts").Return([]int64{2}, mocks.NilError)
+
+	// when
+	count, err := ExportExemptAccounts(
+		defaultContext,
+		suite.mockAccountRepo,
+		0,
+		0,
+		exemptSystemAccounts,
+		false,
+		failingWriter{},
+	)
+
+	// then
+	assert.Equal(suite.T(), hErrors.ErrInternalServerError.Code, err.Code)
+	assert.Zero(suite.T(), count)
+}
@@ -0,0 +1,222 @@
+//go:build faultinjection
+
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/server"
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/db"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	tdb "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+var (
+	faultInjectionFaults = map[string]config.DbFaultInjection{
+		"error":          {ErrorRate: 1},
+		"partial result": {PartialResultRate: 1},
+	}
+	faultInjectionNode  = domain.MustDecodeEntityId(3)
+	faultInjectionRetry = config.DbRetry{MaxAttempts: 3, MaxBackoff: 2 * time.Millisecond, MinBackoff: time.Millisecond}
+)
+
+// run the suite
+func TestFaultInjectionSuite(t *testing.T) {
+	suite.Run(t, new(faultInjectionSuite))
+}
+
+// faultInjectionSuite runs the online services against a database client injected with faults, and verifies the
+// services fail with retriable rosetta errors
+type faultInjectionSuite struct {
+	suite.Suite
+	dbResource tdb.DbResource
+}
+
+func (suite *faultInjectionSuite) SetupSuite() {
+	suite.dbResource = tdb.SetupDb(true)
+	tdb.CreateDbRecords(
+		suite.newDbClient(config.DbFaultInjection{}),
+		&domain.AccountBalanceFile{
+			ConsensusTimestamp: 90,
+			Count:              10,
+			FileHash:           "account_balance_file_hash",
+			Name:               "account_balance_file",
+			NodeAccountId:      faultInjectionNode,
+		},
+		&domain.RecordFile{
+			ConsensusStart: 80,
+			ConsensusEnd:   100,
+			Hash:           "genesis_record_file_hash",
+			Index:          1,
+			Name:           "genesis_record_file",
+			NodeAccountID:  faultInjectionNode,
+			PrevHash:       "previous_record_file_hash",
+		},
+	)
+}
+
+func (suite *faultInjectionSuite) TearDownSuite() {
+	tdb.TearDownDb(suite.dbResource)
+}
+
+func (suite *faultInjectionSuite) TestAccountBalance() {
+	for name, faults := range faultInjectionFaults {
+		suite.Run(name, func() {
+			// given
+			dbClient := suite.newDbClient(faults)
+			service := NewAccountAPIService(
+				newFaultInjectionBaseService(dbClient),
+				persistence.NewAccountRepository(dbClient, config.BalanceReplay{}),
+				0,
+				0,
+			)
+
+			// when
+			actual, err := service.AccountBalance(
+				defaultContext,
+				getAccountBalanceRequest(accountBalanceRequestRemoveBlockIdentifier),
+			)
+
+			// then
+			assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+			assert.True(suite.T(), err.Retriable)
+			assert.Nil(suite.T(), actual)
+		})
+	}
+}
+
+func (suite *faultInjectionSuite) TestBlock() {
+	for name, faults := range faultInjectionFaults {
+		suite.Run(name, func() {
+			// given
+			service := newFaultInjectionBlockService(suite.newDbClient(faults))
+
+			// when
+			actual, err := service.Block(defaultContext, faultInjectionBlockRequest())
+
+			// then
+			assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+			assert.True(suite.T(), err.Retriable)
+			assert.Nil(suite.T(), actual)
+		})
+	}
+}
+
+func (suite *faultInjectionSuite) TestBlockLatency() {
+	// given
+	service := newFaultInjectionBlockService(suite.newDbClient(config.DbFaultInjection{Latency: time.Minute}))
+	ctx, cancel := context.WithTimeout(defaultContext, 100*time.Millisecond)
+	defer cancel()
+
+	// when
+	actual, err := service.Block(ctx, faultInjectionBlockRequest())
+
+	// then
+	assert.Equal(suite.T(), errors.ErrEndpointTimeout, err)
+	assert.True(suite.T(), err.Retriable)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *faultInjectionSuite) TestNetworkStatus() {
+	for name, faults := range faultInjectionFaults {
+		suite.Run(name, func() {
+			// given
+			service := newFaultInjectionNetworkService(suite.newDbClient(faults))
+
+			// when
+			actual, err := service.NetworkStatus(defaultContext, nil)
+
+			// then
+			assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+			assert.True(suite.T(), err.Retriable)
+			assert.Nil(suite.T(), actual)
+		})
+	}
+}
+
+func (suite *faultInjectionSuite) TestNetworkStatusLatency() {
+	// given
+	service := newFaultInjectionNetworkService(suite.newDbClient(config.DbFaultInjection{Latency: time.Minute}))
+	ctx, cancel := context.WithTimeout(defaultContext, 100*time.Millisecond)
+	defer cancel()
+
+	// when
+	actual, err := service.NetworkStatus(ctx, nil)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrEndpointTimeout, err)
+	assert.True(suite.T(), err.Retriable)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *faultInjectionSuite) newDbClient(faults config.DbFaultInjection) interfaces.DbClient {
+	dbClient := db.NewDbClient(suite.dbResource.GetGormDb(), 0, faultInjectionRetry)
+	return db.NewFaultInjectingDbClient(dbClient, faults)
+}
+
+func faultInjectionBlockRequest() *rTypes.BlockRequest {
+	index := int64(1)
+	return &rTypes.BlockRequest{BlockIdentifier: &rTypes.PartialBlockIdentifier{Index: &index}}
+}
+
+func newFaultInjectionBaseService(dbClient interfaces.DbClient) BaseService {
+	return NewOnlineBaseService(
+		persistence.NewBlockRepository(dbClient),
+		persistence.NewTransactionRepository(dbClient, config.SystemAccounts{}, false, false, config.DbRangeSplit{}),
+	)
+}
+
+func newFaultInjectionBlockService(dbClient interfaces.DbClient) server.BlockAPIServicer {
+	cacheConfig := config.Cache{MaxSize: 10}
+	return NewBlockAPIService(
+		persistence.NewAccountRepository(dbClient, config.BalanceReplay{}),
+		newFaultInjectionBaseService(dbClient),
+		nil,
+		config.Block{},
+		cacheConfig,
+		cacheConfig,
+	)
+}
+
+func newFaultInjectionNetworkService(dbClient interfaces.DbClient) server.NetworkAPIServicer {
+	networkService, _ := NewNetworkAPIService(
+		newFaultInjectionBaseService(dbClient),
+		persistence.NewAddressBookEntryRepository(dbClient),
+		persistence.NewFileDataRepository(dbClient),
+		&rTypes.NetworkIdentifier{Blockchain: "Hedera", Network: "testnet"},
+		config.NetworkParameters{},
+		0,
+		0,
+		&rTypes.Version{},
+	)
+	return networkService
+}
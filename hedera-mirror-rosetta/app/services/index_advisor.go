@@ -0,0 +1,131 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	log "github.com/sirupsen/logrus"
+)
+
+// RequiredIndex is an index the rosetta queries depend on. It's satisfied by any non-partial index on the table whose
+// key starts with the columns
+type RequiredIndex struct {
+	Columns []string
+	Table   string
+}
+
+// Ddl returns the statement to create the index, named after the importer's table__columns convention
+func (r RequiredIndex) Ddl() string {
+	return fmt.Sprintf("create index concurrently if not exists %s__%s on %s (%s);", r.Table,
+		strings.Join(r.Columns, "_"), r.Table, strings.Join(r.Columns, ", "))
+}
+
+func (r RequiredIndex) String() string {
+	return fmt.Sprintf("%s(%s)", r.Table, strings.Join(r.Columns, ", "))
+}
+
+// requiredIndexes are the indexes without which the block and transaction queries scan whole tables
+var requiredIndexes = []RequiredIndex{
+	{Columns: []string{"consensus_timestamp"}, Table: "crypto_transfer"},
+	{Columns: []string{"consensus_timestamp"}, Table: "token_transfer"},
+	{Columns: []string{"transaction_hash"}, Table: "transaction"},
+}
+
+// IndexAdvisor checks the database has the indexes the rosetta queries depend on and logs a warning with the DDL to
+// create each missing one. An index dropped or left invalid after startup is caught by the periodic checks
+type IndexAdvisor struct {
+	indexRepo interfaces.IndexRepository
+	interval  time.Duration
+}
+
+// NewIndexAdvisor creates an IndexAdvisor. A non-positive interval disables the periodic checks after the first one
+func NewIndexAdvisor(indexRepo interfaces.IndexRepository, interval time.Duration) *IndexAdvisor {
+	return &IndexAdvisor{indexRepo: indexRepo, interval: interval}
+}
+
+// Run checks the indexes immediately and then every interval until the context is done
+func (a *IndexAdvisor) Run(ctx context.Context) {
+	if a.interval <= 0 {
+		a.checkAndWarn(ctx)
+		return
+	}
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		a.checkAndWarn(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check returns the required indexes missing in the database
+func (a *IndexAdvisor) Check(ctx context.Context) ([]RequiredIndex, *rTypes.Error) {
+	tables := make([]string, 0, len(requiredIndexes))
+	for _, required := range requiredIndexes {
+		tables = append(tables, required.Table)
+	}
+
+	indexes, err := a.indexRepo.FindByTables(ctx, tables)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := make([]RequiredIndex, 0)
+	for _, required := range requiredIndexes {
+		found := false
+		for _, index := range indexes {
+			if index.Table == required.Table && !index.Partial && index.HasLeadingColumns(required.Columns) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			missing = append(missing, required)
+		}
+	}
+
+	return missing, nil
+}
+
+func (a *IndexAdvisor) checkAndWarn(ctx context.Context) {
+	missing, err := a.Check(ctx)
+	if err != nil {
+		log.Warnf("Failed to check the database indexes: %s", err.Message)
+		return
+	}
+
+	for _, required := range missing {
+		log.Warnf("Missing index on %s, queries on it will scan the whole table. Create it with: %s", required,
+			required.Ddl())
+	}
+}
@@ -0,0 +1,180 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+var (
+	cryptoTransferTimestampIndex = types.Index{
+		Columns: []string{"consensus_timestamp"},
+		Name:    "crypto_transfer__consensus_timestamp",
+		Table:   "crypto_transfer",
+	}
+	indexedTables               = []string{"crypto_transfer", "token_transfer", "transaction"}
+	tokenTransferTimestampIndex = types.Index{
+		Columns: []string{"consensus_timestamp", "token_id", "account_id"},
+		Name:    "token_transfer_pkey",
+		Table:   "token_transfer",
+	}
+	transactionHashIndex = types.Index{
+		Columns: []string{"transaction_hash"},
+		Name:    "transaction__transaction_hash",
+		Table:   "transaction",
+	}
+)
+
+func TestRequiredIndexDdl(t *testing.T) {
+	required := RequiredIndex{Columns: []string{"entity_id", "consensus_timestamp"}, Table: "crypto_transfer"}
+	assert.Equal(
+		t,
+		"create index concurrently if not exists crypto_transfer__entity_id_consensus_timestamp on crypto_transfer "+
+			"(entity_id, consensus_timestamp);",
+		required.Ddl(),
+	)
+	assert.Equal(t, "crypto_transfer(entity_id, consensus_timestamp)", required.String())
+}
+
+func TestIndexAdvisorSuite(t *testing.T) {
+	suite.Run(t, new(indexAdvisorSuite))
+}
+
+type indexAdvisorSuite struct {
+	suite.Suite
+	advisor       *IndexAdvisor
+	mockIndexRepo *mocks.MockIndexRepository
+}
+
+func (suite *indexAdvisorSuite) SetupTest() {
+	suite.mockIndexRepo = &mocks.MockIndexRepository{}
+	suite.advisor = NewIndexAdvisor(suite.mockIndexRepo, 0)
+}
+
+func (suite *indexAdvisorSuite) TestCheck() {
+	// given
+	suite.mockIndexRepo.On("FindByTables", indexedTables).Return(
+		[]types.Index{cryptoTransferTimestampIndex, tokenTransferTimestampIndex, transactionHashIndex},
+		mocks.NilError,
+	)
+
+	// when
+	actual, err := suite.advisor.Check(defaultContext)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Empty(suite.T(), actual)
+	suite.mockIndexRepo.AssertExpectations(suite.T())
+}
+
+func (suite *indexAdvisorSuite) TestCheckMissing() {
+	// given
+	partialIndex := transactionHashIndex
+	partialIndex.Partial = true
+	trailingColumnIndex := types.Index{
+		Columns: []string{"account_id", "consensus_timestamp"},
+		Name:    "token_transfer__account_timestamp",
+		Table:   "token_transfer",
+	}
+	otherTableIndex := types.Index{
+		Columns: []string{"consensus_timestamp"},
+		Name:    "transaction_pkey",
+		Table:   "transaction",
+	}
+	suite.mockIndexRepo.On("FindByTables", indexedTables).Return(
+		[]types.Index{cryptoTransferTimestampIndex, trailingColumnIndex, partialIndex, otherTableIndex},
+		mocks.NilError,
+	)
+	expected := []RequiredIndex{
+		{Columns: []string{"consensus_timestamp"}, Table: "token_transfer"},
+		{Columns: []string{"transaction_hash"}, Table: "transaction"},
+	}
+
+	// when
+	actual, err := suite.advisor.Check(defaultContext)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+}
+
+func (suite *indexAdvisorSuite) TestCheckDbError() {
+	// given
+	suite.mockIndexRepo.On("FindByTables", indexedTables).Return([]types.Index{}, errors.ErrDatabaseError)
+
+	// when
+	actual, err := suite.advisor.Check(defaultContext)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *indexAdvisorSuite) TestRunOnce() {
+	// given
+	suite.mockIndexRepo.On("FindByTables", indexedTables).Return([]types.Index{}, mocks.NilError)
+
+	// when
+	suite.advisor.Run(defaultContext)
+
+	// then
+	suite.mockIndexRepo.AssertNumberOfCalls(suite.T(), "FindByTables", 1)
+}
+
+func (suite *indexAdvisorSuite) TestRunPeriodically() {
+	// given
+	ctx, cancel := context.WithCancel(defaultContext)
+	calls := make(chan struct{}, 3)
+	suite.mockIndexRepo.On("FindByTables", indexedTables).
+		Return([]types.Index{}, errors.ErrDatabaseError).
+		Run(func(mock.Arguments) {
+			select {
+			case calls <- struct{}{}:
+			default:
+			}
+		})
+	advisor := NewIndexAdvisor(suite.mockIndexRepo, time.Millisecond)
+	done := make(chan struct{})
+
+	// when
+	go func() {
+		advisor.Run(ctx)
+		close(done)
+	}()
+	<-calls
+	<-calls
+	cancel()
+
+	// then
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		suite.T().Fatal("Index advisor didn't stop after the context is done")
+	}
+}
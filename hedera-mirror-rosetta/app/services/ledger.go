@@ -0,0 +1,141 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/tools"
+	"github.com/hashgraph/hedera-sdk-go/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+const applicationPropertiesFileNum int64 = 121
+
+// publicLedgerIds maps the name of a public network to its ledger id
+var publicLedgerIds = map[string]*hedera.LedgerID{
+	string(hedera.NetworkNameMainnet):    hedera.NewLedgerIDMainnet(),
+	string(hedera.NetworkNamePreviewnet): hedera.NewLedgerIDPreviewnet(),
+	string(hedera.NetworkNameTestnet):    hedera.NewLedgerIDTestnet(),
+}
+
+// CheckLedgerId returns an error if the ledger id in the application properties file of the network in the database
+// doesn't match the configured ledger id, or the ledger id of the public network when not configured. The check is
+// skipped if the ledger id is unknown or the file can't be read, e.g., the importer hasn't processed it yet
+func CheckLedgerId(
+	ctx context.Context,
+	fileDataRepo interfaces.FileDataRepository,
+	network string,
+	networkParameters config.NetworkParameters,
+	systemShard int64,
+	systemRealm int64,
+) error {
+	ledgerId, err := getLedgerId(networkParameters.LedgerId, network)
+	if err != nil {
+		return err
+	}
+
+	if ledgerId == "" {
+		log.Warn("The ledger id is unknown, skip the ledger id check")
+		return nil
+	}
+
+	applicationPropertiesFileId, err := domain.EncodeEntityId(systemShard, systemRealm, applicationPropertiesFileNum)
+	if err != nil {
+		return err
+	}
+
+	configured := types.NetworkParameters{LedgerId: ledgerId}
+	if _, rErr := getNetworkParameters(ctx, fileDataRepo, applicationPropertiesFileId, configured); rErr != nil {
+		return fmt.Errorf("%s: %s", rErr.Message, rErr.Details["reason"])
+	}
+
+	return nil
+}
+
+// getLedgerId returns the configured ledger id with the hex prefix, or the ledger id of the public network if not
+// configured. An empty string is returned if the ledger id is unknown. An error is returned if the configured ledger id
+// isn't hex encoded or doesn't match the ledger id of the public network
+func getLedgerId(ledgerId, network string) (string, error) {
+	publicLedgerId, isPublic := publicLedgerIds[network]
+	if ledgerId == "" {
+		if !isPublic {
+			return "", nil
+		}
+		return tools.SafeAddHexPrefix(hex.EncodeToString(publicLedgerId.ToBytes())), nil
+	}
+
+	ledgerIdBytes, err := hex.DecodeString(tools.SafeRemoveHexPrefix(ledgerId))
+	if err != nil || len(ledgerIdBytes) == 0 {
+		return "", fmt.Errorf("invalid ledger id %s", ledgerId)
+	}
+
+	if isPublic && !bytes.Equal(ledgerIdBytes, publicLedgerId.ToBytes()) {
+		return "", fmt.Errorf("ledger id %s doesn't match the %s network", ledgerId, network)
+	}
+
+	return tools.SafeAddHexPrefix(hex.EncodeToString(ledgerIdBytes)), nil
+}
+
+// getNetworkParameters returns the network parameters overridden by the values in the application properties file of
+// the network. ErrLedgerIdMismatch is returned if the ledger id in the file doesn't match the known ledger id. The
+// network parameters are returned as is if the file can't be read
+func getNetworkParameters(
+	ctx context.Context,
+	fileDataRepo interfaces.FileDataRepository,
+	applicationPropertiesFileId int64,
+	networkParameters types.NetworkParameters,
+) (types.NetworkParameters, *rTypes.Error) {
+	data, rErr := fileDataRepo.GetLatestContent(ctx, applicationPropertiesFileId)
+	if rErr != nil {
+		log.Warnf("Failed to get the application properties: %s", rErr.Message)
+		return networkParameters, nil
+	}
+
+	if len(data) == 0 {
+		return networkParameters, nil
+	}
+
+	actual, err := networkParameters.WithProperties(data)
+	if err != nil {
+		log.Warnf("Failed to parse the application properties: %s", err)
+		return networkParameters, nil
+	}
+
+	if networkParameters.LedgerId != "" && !strings.EqualFold(actual.LedgerId, networkParameters.LedgerId) {
+		return networkParameters, errors.AddErrorDetails(
+			errors.ErrLedgerIdMismatch,
+			"reason",
+			fmt.Sprintf("the ledger id of the network is %s, expected %s", actual.LedgerId, networkParameters.LedgerId),
+		)
+	}
+
+	return actual, nil
+}
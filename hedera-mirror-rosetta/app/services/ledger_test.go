@@ -0,0 +1,155 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package services
+
+import (
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/mocks"
+	"github.com/hashgraph/hedera-protobufs-go/services"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCheckLedgerId(t *testing.T) {
+	tests := []struct {
+		name        string
+		fileLedger  string
+		ledgerId    string
+		network     string
+		expectError bool
+	}{
+		{name: "match public network", fileLedger: "0x01", network: "testnet"},
+		{name: "match configured", fileLedger: "0x2a", ledgerId: "2A", network: "other"},
+		{name: "unknown ledger id", fileLedger: "0x2a", network: "other"},
+		{name: "no ledger id in file", network: "mainnet"},
+		{name: "mismatch public network", fileLedger: "0x00", network: "testnet", expectError: true},
+		{name: "mismatch configured", fileLedger: "0x2a", ledgerId: "0x2b", network: "other", expectError: true},
+		{name: "invalid configured", ledgerId: "xyz", network: "other", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			settings := []*services.Setting{{Name: "ledger.transfers.maxLen", Value: "10"}}
+			if tt.fileLedger != "" {
+				settings = append(settings, &services.Setting{Name: "ledger.id", Value: tt.fileLedger})
+			}
+			data, _ := proto.Marshal(&services.ServicesConfigurationList{NameValue: settings})
+			mockFileDataRepo := &mocks.MockFileDataRepository{}
+			mockFileDataRepo.On("GetLatestContent").Return(data, mocks.NilError)
+
+			// when
+			err := CheckLedgerId(
+				defaultContext,
+				mockFileDataRepo,
+				tt.network,
+				config.NetworkParameters{LedgerId: tt.ledgerId},
+				0,
+				0,
+			)
+
+			// then
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckLedgerIdSkippedWhenFileUnavailable(t *testing.T) {
+	// given
+	mockFileDataRepo := &mocks.MockFileDataRepository{}
+	mockFileDataRepo.On("GetLatestContent").Return([]byte{}, errors.ErrDatabaseError)
+
+	// when
+	err := CheckLedgerId(defaultContext, mockFileDataRepo, "mainnet", config.NetworkParameters{}, 0, 0)
+
+	// then
+	mockFileDataRepo.AssertExpectations(t)
+	assert.NoError(t, err)
+}
+
+func TestGetLedgerId(t *testing.T) {
+	tests := []struct {
+		ledgerId string
+		network  string
+		expected string
+	}{
+		{ledgerId: "0x00", network: "mainnet", expected: "0x00"},
+		{ledgerId: "2A", network: "demo", expected: "0x2a"},
+		{network: "mainnet", expected: "0x00"},
+		{network: "testnet", expected: "0x01"},
+		{network: "previewnet", expected: "0x02"},
+		{network: "demo", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.network+tt.ledgerId, func(t *testing.T) {
+			actual, err := getLedgerId(tt.ledgerId, tt.network)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestGetLedgerIdFail(t *testing.T) {
+	tests := []struct {
+		ledgerId string
+		network  string
+	}{
+		{ledgerId: "0x", network: "demo"},
+		{ledgerId: "0xzz", network: "demo"},
+		{ledgerId: "0x01", network: "mainnet"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.network+tt.ledgerId, func(t *testing.T) {
+			actual, err := getLedgerId(tt.ledgerId, tt.network)
+			assert.Error(t, err)
+			assert.Empty(t, actual)
+		})
+	}
+}
+
+func TestGetNetworkParametersLedgerIdMismatch(t *testing.T) {
+	// given
+	data, _ := proto.Marshal(&services.ServicesConfigurationList{
+		NameValue: []*services.Setting{{Name: "ledger.id", Value: "0x02"}},
+	})
+	mockFileDataRepo := &mocks.MockFileDataRepository{}
+	mockFileDataRepo.On("GetLatestContent").Return(data, mocks.NilError)
+	expected := errors.AddErrorDetails(
+		errors.ErrLedgerIdMismatch,
+		"reason",
+		"the ledger id of the network is 0x02, expected 0x01",
+	)
+
+	// when
+	_, err := getNetworkParameters(defaultContext, mockFileDataRepo, 121, types.NetworkParameters{LedgerId: "0x01"})
+
+	// then
+	assert.Equal(t, expected, err)
+}
@@ -0,0 +1,110 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package services
+
+import (
+	"context"
+	"fmt"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/hashgraph/hedera-sdk-go/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+const networkOther = "other"
+
+// publicNetworks are the networks whose well-known node endpoints are used to identify the network
+var publicNetworks = []string{
+	string(hedera.NetworkNameMainnet),
+	string(hedera.NetworkNamePreviewnet),
+	string(hedera.NetworkNameTestnet),
+}
+
+// DiscoveredNetwork is the network discovered from the database
+type DiscoveredNetwork struct {
+	GenesisTimestamp *int64
+	Network          string
+	Nodes            config.NodeMap
+}
+
+// DiscoverNetwork discovers the network name and the node list from the current address book in the database, and the
+// genesis timestamp if the genesis block is available. The network is identified by matching the node endpoints with
+// the well-known endpoints of the public networks, and is "other" for custom networks such as hedera-local-node
+func DiscoverNetwork(
+	ctx context.Context,
+	addressBookEntryRepo interfaces.AddressBookEntryRepository,
+	blockRepo interfaces.BlockRepository,
+) (*DiscoveredNetwork, *rTypes.Error) {
+	entries, err := addressBookEntryRepo.Entries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(config.NodeMap)
+	for _, entry := range entries.Entries {
+		accountId := hedera.AccountID{
+			Shard:   uint64(entry.AccountId.ShardNum),
+			Realm:   uint64(entry.AccountId.RealmNum),
+			Account: uint64(entry.AccountId.EntityNum),
+		}
+		for _, endpoint := range entry.Endpoints {
+			nodes[endpoint] = accountId
+		}
+	}
+
+	if len(nodes) == 0 {
+		return nil, errors.AddErrorDetails(errors.ErrNodeIsStarting, "reason", "No node endpoints in the address book")
+	}
+
+	discovered := &DiscoveredNetwork{Network: identifyNetwork(nodes), Nodes: nodes}
+	genesis := "unavailable"
+	if block, err := blockRepo.RetrieveGenesis(ctx); err == nil {
+		discovered.GenesisTimestamp = &block.ConsensusStartNanos
+		genesis = fmt.Sprintf("%d", block.ConsensusStartNanos)
+	}
+
+	log.Infof("Discovered network %s with %d node endpoints, genesis timestamp %s", discovered.Network, len(nodes),
+		genesis)
+	return discovered, nil
+}
+
+// identifyNetwork returns the name of the public network which has any of the node endpoints, or "other" if none
+func identifyNetwork(nodes config.NodeMap) string {
+	for _, name := range publicNetworks {
+		client, err := hedera.ClientForName(name)
+		if err != nil {
+			continue
+		}
+
+		publicNodes := client.GetNetwork()
+		_ = client.Close()
+		for endpoint, accountId := range nodes {
+			if publicAccountId, ok := publicNodes[endpoint]; ok && publicAccountId.String() == accountId.String() {
+				return name
+			}
+		}
+	}
+
+	return networkOther
+}
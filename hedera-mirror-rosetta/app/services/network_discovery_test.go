@@ -0,0 +1,117 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package services
+
+import (
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/mocks"
+	"github.com/hashgraph/hedera-sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestNetworkDiscoverySuite(t *testing.T) {
+	suite.Run(t, new(networkDiscoverySuite))
+}
+
+type networkDiscoverySuite struct {
+	suite.Suite
+	mockAddressBookEntryRepo *mocks.MockAddressBookEntryRepository
+	mockBlockRepo            *mocks.MockBlockRepository
+}
+
+func (suite *networkDiscoverySuite) SetupTest() {
+	suite.mockAddressBookEntryRepo = &mocks.MockAddressBookEntryRepository{}
+	suite.mockBlockRepo = &mocks.MockBlockRepository{}
+}
+
+func (suite *networkDiscoverySuite) TestDiscoverNetwork() {
+	// given
+	suite.mockAddressBookEntryRepo.On("Entries").Return(&types.AddressBookEntries{Entries: []types.AddressBookEntry{
+		{NodeId: 0, AccountId: domain.MustDecodeEntityId(3), Endpoints: []string{"127.0.0.1:50211"}},
+		{NodeId: 1, AccountId: domain.MustDecodeEntityId(4), Endpoints: []string{}},
+	}}, mocks.NilError)
+	suite.mockBlockRepo.On("RetrieveGenesis").Return(dummyGenesisBlock(), mocks.NilError)
+	genesisTimestamp := dummyGenesisBlock().ConsensusStartNanos
+	expected := &DiscoveredNetwork{
+		GenesisTimestamp: &genesisTimestamp,
+		Network:          "other",
+		Nodes:            config.NodeMap{"127.0.0.1:50211": hedera.AccountID{Account: 3}},
+	}
+
+	// when
+	actual, err := DiscoverNetwork(defaultContext, suite.mockAddressBookEntryRepo, suite.mockBlockRepo)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+}
+
+func (suite *networkDiscoverySuite) TestDiscoverNetworkPublicNetwork() {
+	// given
+	suite.mockAddressBookEntryRepo.On("Entries").Return(&types.AddressBookEntries{Entries: []types.AddressBookEntry{
+		{NodeId: 0, AccountId: domain.MustDecodeEntityId(3), Endpoints: []string{"34.94.106.61:50211"}},
+	}}, mocks.NilError)
+	suite.mockBlockRepo.On("RetrieveGenesis").Return(mocks.NilBlock, errors.ErrNodeIsStarting)
+	expected := &DiscoveredNetwork{
+		Network: "testnet",
+		Nodes:   config.NodeMap{"34.94.106.61:50211": hedera.AccountID{Account: 3}},
+	}
+
+	// when
+	actual, err := DiscoverNetwork(defaultContext, suite.mockAddressBookEntryRepo, suite.mockBlockRepo)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected, actual)
+}
+
+func (suite *networkDiscoverySuite) TestDiscoverNetworkNoEndpoints() {
+	// given
+	suite.mockAddressBookEntryRepo.On("Entries").Return(&types.AddressBookEntries{Entries: []types.AddressBookEntry{
+		{NodeId: 0, AccountId: domain.MustDecodeEntityId(3), Endpoints: []string{}},
+	}}, mocks.NilError)
+
+	// when
+	actual, err := DiscoverNetwork(defaultContext, suite.mockAddressBookEntryRepo, suite.mockBlockRepo)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrNodeIsStarting.Code, err.Code)
+	assert.Nil(suite.T(), actual)
+	suite.mockBlockRepo.AssertNotCalled(suite.T(), "RetrieveGenesis")
+}
+
+func (suite *networkDiscoverySuite) TestDiscoverNetworkEntriesError() {
+	// given
+	suite.mockAddressBookEntryRepo.On("Entries").Return(mocks.NilEntries, errors.ErrDatabaseError)
+
+	// when
+	actual, err := DiscoverNetwork(defaultContext, suite.mockAddressBookEntryRepo, suite.mockBlockRepo)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
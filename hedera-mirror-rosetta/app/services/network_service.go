@@ -22,23 +22,39 @@ package services
 
 import (
 	"context"
+	"sync"
 
 	"github.com/coinbase/rosetta-sdk-go/server"
 	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/tools"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	metadataKeyNetworkParameters = "network_parameters"
+	// syncStageStale is the sync stage of a network status served from the cache
+	syncStageStale = "stale"
 )
 
 // networkAPIService implements the server.NetworkAPIServicer interface.
 type networkAPIService struct {
 	BaseService
-	addressBookEntryRepo interfaces.AddressBookEntryRepository
-	network              *rTypes.NetworkIdentifier
-	operationTypes       []string
-	version              *rTypes.Version
+	addressBookEntryRepo        interfaces.AddressBookEntryRepository
+	applicationPropertiesFileId int64
+	callMethods                 []string
+	fileDataRepo                interfaces.FileDataRepository
+	lastStatus                  *rTypes.NetworkStatusResponse
+	network                     *rTypes.NetworkIdentifier
+	networkParameters           types.NetworkParameters
+	operationTypes              []string
+	statusLock                  sync.RWMutex
+	version                     *rTypes.Version
 }
 
 // NetworkList implements the /network/list endpoint.
@@ -49,11 +65,28 @@ func (n *networkAPIService) NetworkList(
 	return &rTypes.NetworkListResponse{NetworkIdentifiers: []*rTypes.NetworkIdentifier{n.network}}, nil
 }
 
-// NetworkOptions implements the /network/options endpoint.
+// NetworkOptions implements the /network/options endpoint. The network parameters clients need to build valid
+// transactions are added to the version metadata. In online mode, the request is rejected if the ledger id of the
+// network in the database doesn't match the configured ledger id. The rosetta version is the spec version negotiated
+// for the request
 func (n *networkAPIService) NetworkOptions(
-	_ context.Context,
+	ctx context.Context,
 	_ *rTypes.NetworkRequest,
 ) (*rTypes.NetworkOptionsResponse, *rTypes.Error) {
+	networkParameters := n.networkParameters
+	if n.IsOnline() {
+		var rErr *rTypes.Error
+		networkParameters, rErr = getNetworkParameters(
+			ctx,
+			n.fileDataRepo,
+			n.applicationPropertiesFileId,
+			n.networkParameters,
+		)
+		if rErr != nil {
+			return nil, rErr
+		}
+	}
+
 	operationStatuses := make([]*rTypes.OperationStatus, 0, len(types.TransactionResults))
 	for value, name := range types.TransactionResults {
 		operationStatuses = append(operationStatuses, &rTypes.OperationStatus{
@@ -62,18 +95,30 @@ func (n *networkAPIService) NetworkOptions(
 		})
 	}
 
+	version := *n.version
+	if specVersion := types.GetSpecVersion(ctx); specVersion != rTypes.RosettaAPIVersion {
+		version.RosettaVersion = specVersion
+	}
+	version.Metadata = make(map[string]interface{}, len(n.version.Metadata)+1)
+	for key, value := range n.version.Metadata {
+		version.Metadata[key] = value
+	}
+	version.Metadata[metadataKeyNetworkParameters] = networkParameters.ToMetadata()
+
 	return &rTypes.NetworkOptionsResponse{
-		Version: n.version,
+		Version: &version,
 		Allow: &rTypes.Allow{
 			OperationStatuses:       operationStatuses,
 			OperationTypes:          n.operationTypes,
 			Errors:                  errors.Errors,
 			HistoricalBalanceLookup: true,
+			CallMethods:             n.callMethods,
 		},
 	}, nil
 }
 
-// NetworkStatus implements the /network/status endpoint.
+// NetworkStatus implements the /network/status endpoint. If the database reads time out, the last successful status
+// is served marked as stale, so orchestrators polling the endpoint keep functioning during brief database outages
 func (n *networkAPIService) NetworkStatus(
 	ctx context.Context,
 	_ *rTypes.NetworkRequest,
@@ -82,6 +127,28 @@ func (n *networkAPIService) NetworkStatus(
 		return nil, errors.ErrEndpointNotSupportedInOfflineMode
 	}
 
+	status, err := n.getNetworkStatus(ctx)
+	if err != nil {
+		if !isDeadlineExceeded(ctx) {
+			return nil, err
+		}
+
+		if stale := n.getStaleNetworkStatus(); stale != nil {
+			log.Warnf("Serving stale network status at block %d since the database reads timed out: %s",
+				stale.CurrentBlockIdentifier.Index, err.Message)
+			return stale, nil
+		}
+
+		return nil, errors.ErrEndpointTimeout
+	}
+
+	n.statusLock.Lock()
+	defer n.statusLock.Unlock()
+	n.lastStatus = status
+	return status, nil
+}
+
+func (n *networkAPIService) getNetworkStatus(ctx context.Context) (*rTypes.NetworkStatusResponse, *rTypes.Error) {
 	genesisBlock, err := n.RetrieveGenesis(ctx)
 	if err != nil {
 		return nil, err
@@ -92,33 +159,95 @@ func (n *networkAPIService) NetworkStatus(
 		return nil, err
 	}
 
+	oldestBlock, err := n.RetrieveOldest(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	peers, err := n.addressBookEntryRepo.Entries(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return &rTypes.NetworkStatusResponse{
+	status := &rTypes.NetworkStatusResponse{
 		CurrentBlockIdentifier: currentBlock.GetRosettaBlockIdentifier(),
 		CurrentBlockTimestamp:  currentBlock.GetTimestampMillis(),
 		GenesisBlockIdentifier: genesisBlock.GetRosettaBlockIdentifier(),
 		Peers:                  peers.ToRosetta(),
-	}, nil
+	}
+	if oldestBlock.Index != genesisBlock.Index {
+		// the blocks before the oldest block are pruned by the data retention
+		status.OldestBlockIdentifier = oldestBlock.GetRosettaBlockIdentifier()
+	}
+	return status, nil
+}
+
+// getStaleNetworkStatus returns a copy of the last successful network status with the sync stage set to stale, or nil
+// if there is none
+func (n *networkAPIService) getStaleNetworkStatus() *rTypes.NetworkStatusResponse {
+	n.statusLock.RLock()
+	defer n.statusLock.RUnlock()
+
+	if n.lastStatus == nil {
+		return nil
+	}
+
+	stale := *n.lastStatus
+	currentIndex := stale.CurrentBlockIdentifier.Index
+	stage := syncStageStale
+	stale.SyncStatus = &rTypes.SyncStatus{CurrentIndex: &currentIndex, Stage: &stage}
+	return &stale
 }
 
 // NewNetworkAPIService creates a networkAPIService instance.
 func NewNetworkAPIService(
 	baseService BaseService,
 	addressBookEntryRepo interfaces.AddressBookEntryRepository,
+	fileDataRepo interfaces.FileDataRepository,
 	network *rTypes.NetworkIdentifier,
+	networkParameters config.NetworkParameters,
+	systemShard int64,
+	systemRealm int64,
 	version *rTypes.Version,
-) server.NetworkAPIServicer {
+) (server.NetworkAPIServicer, error) {
+	applicationPropertiesFileId, err := domain.EncodeEntityId(systemShard, systemRealm, applicationPropertiesFileNum)
+	if err != nil {
+		return nil, err
+	}
+
+	ledgerId, err := getLedgerId(networkParameters.LedgerId, network.Network)
+	if err != nil {
+		return nil, err
+	}
+
 	operationTypes := tools.GetStringValuesFromInt32StringMap(types.TransactionTypes)
-	operationTypes = append(operationTypes, types.OperationTypeFee)
-	return &networkAPIService{
-		BaseService:          baseService,
-		addressBookEntryRepo: addressBookEntryRepo,
-		operationTypes:       operationTypes,
-		network:              network,
-		version:              version,
+	operationTypes = append(
+		operationTypes,
+		types.OperationTypeApprovedTransfer,
+		types.OperationTypeFee,
+		types.OperationTypeHollowAccountCompletion,
+	)
+	operationTypes = types.ToOperationTypeNames(operationTypes)
+	// the /call endpoint is only available in online mode
+	callMethods := make([]string, 0)
+	if baseService.IsOnline() {
+		callMethods = types.SupportedCallMethods
 	}
+	return &networkAPIService{
+		BaseService:                 baseService,
+		addressBookEntryRepo:        addressBookEntryRepo,
+		applicationPropertiesFileId: applicationPropertiesFileId,
+		callMethods:                 callMethods,
+		fileDataRepo:                fileDataRepo,
+		operationTypes:              operationTypes,
+		network:                     network,
+		networkParameters: types.NetworkParameters{
+			LedgerId:           ledgerId,
+			MaxMemoSize:        networkParameters.MaxMemoSize,
+			MaxTokenTransfers:  networkParameters.MaxTokenTransfers,
+			MaxTransactionSize: maxTransactionSize,
+			MaxTransfers:       networkParameters.MaxTransfers,
+		},
+		version: version,
+	}, nil
 }
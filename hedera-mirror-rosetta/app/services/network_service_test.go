@@ -21,17 +21,37 @@
 package services
 
 import (
+	"context"
 	"testing"
 
 	"github.com/coinbase/rosetta-sdk-go/server"
 	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/tools"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/mocks"
+	"github.com/hashgraph/hedera-protobufs-go/services"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	defaultNetworkParameters = config.NetworkParameters{
+		LedgerId:          "0x01",
+		MaxMemoSize:       100,
+		MaxTokenTransfers: 10,
+		MaxTransfers:      10,
+	}
+	defaultNetworkParametersMetadata = map[string]interface{}{
+		"ledger_id":            "0x01",
+		"max_memo_size":        int64(100),
+		"max_token_transfers":  int64(10),
+		"max_transaction_size": int64(maxTransactionSize),
+		"max_transfers":        int64(10),
+	}
 )
 
 func dummyGenesisBlock() *types.Block {
@@ -56,7 +76,11 @@ func dummySecondLatestBlock() *types.Block {
 	}
 }
 
-func getNetworkAPIService(abr interfaces.AddressBookEntryRepository, base BaseService) server.NetworkAPIServicer {
+func getNetworkAPIService(
+	abr interfaces.AddressBookEntryRepository,
+	fdr interfaces.FileDataRepository,
+	base BaseService,
+) server.NetworkAPIServicer {
 	network := &rTypes.NetworkIdentifier{
 		Blockchain: "SomeBlockchain",
 		Network:    "SomeNetwork",
@@ -72,7 +96,8 @@ func getNetworkAPIService(abr interfaces.AddressBookEntryRepository, base BaseSe
 		Metadata:          nil,
 	}
 
-	return NewNetworkAPIService(base, abr, network, version)
+	networkService, _ := NewNetworkAPIService(base, abr, fdr, network, defaultNetworkParameters, 0, 0, version)
+	return networkService
 }
 
 func TestOfflineNetworkServiceSuite(t *testing.T) {
@@ -87,11 +112,16 @@ type offlineNetworkServiceSuite struct {
 
 func (suite *offlineNetworkServiceSuite) SetupSuite() {
 	suite.operationTypes = tools.GetStringValuesFromInt32StringMap(types.TransactionTypes)
-	suite.operationTypes = append(suite.operationTypes, types.OperationTypeFee)
+	suite.operationTypes = append(
+		suite.operationTypes,
+		types.OperationTypeApprovedTransfer,
+		types.OperationTypeFee,
+		types.OperationTypeHollowAccountCompletion,
+	)
 }
 
 func (suite *offlineNetworkServiceSuite) BeforeTest(_, _ string) {
-	suite.networkService = getNetworkAPIService(nil, NewOfflineBaseService())
+	suite.networkService = getNetworkAPIService(nil, nil, NewOfflineBaseService())
 }
 
 func (suite *offlineNetworkServiceSuite) TestNetworkList() {
@@ -159,6 +189,25 @@ func (suite *offlineNetworkServiceSuite) TestNetworkOptions() {
 		errors.ErrEndpointNotSupportedInOfflineMode,
 		errors.ErrInvalidCurveType,
 		errors.ErrInvalidOptions,
+		errors.ErrCallMethodUnsupported,
+		errors.ErrInvalidCallParameters,
+		errors.ErrTransactionSizeExceeded,
+		errors.ErrMaxTransactionFeeTooLow,
+		errors.ErrScheduleNotFound,
+		errors.ErrTooManyConcurrentRequests,
+		errors.ErrNftNotFound,
+		errors.ErrTopicMessageNotFound,
+		errors.ErrEndpointTimeout,
+		errors.ErrNodeStakeNotFound,
+		errors.ErrServerSigningFailed,
+		errors.ErrNodeCertificateVerificationFailed,
+		errors.ErrLedgerIdMismatch,
+		errors.ErrHistoryPruned,
+		errors.ErrBalanceSnapshotNotFound,
+		errors.ErrUnsupportedSpecVersion,
+		errors.ErrBalanceReplayBoundExceeded,
+		errors.ErrRecordFileNotAvailable,
+		errors.ErrServerSigningNotAllowed,
 		errors.ErrInternalServerError,
 	}
 
@@ -167,7 +216,7 @@ func (suite *offlineNetworkServiceSuite) TestNetworkOptions() {
 			RosettaVersion:    "1",
 			NodeVersion:       "1",
 			MiddlewareVersion: nil,
-			Metadata:          nil,
+			Metadata:          map[string]interface{}{metadataKeyNetworkParameters: defaultNetworkParametersMetadata},
 		},
 		Allow: &rTypes.Allow{
 			OperationStatuses: []*rTypes.OperationStatus{
@@ -198,6 +247,15 @@ func (suite *offlineNetworkServiceSuite) TestNetworkOptions() {
 	assert.Nil(suite.T(), e)
 }
 
+func (suite *offlineNetworkServiceSuite) TestNetworkOptionsCallMethods() {
+	// when:
+	res, e := suite.networkService.NetworkOptions(nil, nil)
+
+	// then:
+	assert.Nil(suite.T(), e)
+	assert.Empty(suite.T(), res.Allow.CallMethods)
+}
+
 func (suite *offlineNetworkServiceSuite) TestNetworkStatus() {
 	// given
 	// when
@@ -216,16 +274,144 @@ type onlineNetworkServiceSuite struct {
 	offlineNetworkServiceSuite
 	mockAddressBookEntryRepo *mocks.MockAddressBookEntryRepository
 	mockBlockRepo            *mocks.MockBlockRepository
+	mockFileDataRepo         *mocks.MockFileDataRepository
 	mockTransactionRepo      *mocks.MockTransactionRepository
 }
 
 func (suite *onlineNetworkServiceSuite) BeforeTest(_, _ string) {
 	suite.mockAddressBookEntryRepo = &mocks.MockAddressBookEntryRepository{}
 	suite.mockBlockRepo = &mocks.MockBlockRepository{}
+	suite.mockFileDataRepo = &mocks.MockFileDataRepository{}
 	suite.mockTransactionRepo = &mocks.MockTransactionRepository{}
 
 	baseService := NewOnlineBaseService(suite.mockBlockRepo, suite.mockTransactionRepo)
-	suite.networkService = getNetworkAPIService(suite.mockAddressBookEntryRepo, baseService)
+	suite.networkService = getNetworkAPIService(suite.mockAddressBookEntryRepo, suite.mockFileDataRepo, baseService)
+}
+
+func (suite *onlineNetworkServiceSuite) TestNetworkOptions() {
+	suite.mockFileDataRepo.On("GetLatestContent").Return([]byte{}, mocks.NilError)
+	suite.offlineNetworkServiceSuite.TestNetworkOptions()
+}
+
+func (suite *onlineNetworkServiceSuite) TestNetworkOptionsNetworkParameters() {
+	// given
+	data, _ := proto.Marshal(&services.ServicesConfigurationList{
+		NameValue: []*services.Setting{
+			{Name: "ledger.id", Value: "0x01"},
+			{Name: "ledger.tokenTransfers.maxLen", Value: "20"},
+		},
+	})
+	suite.mockFileDataRepo.On("GetLatestContent").Return(data, mocks.NilError)
+	expected := map[string]interface{}{
+		"ledger_id":            "0x01",
+		"max_memo_size":        int64(100),
+		"max_token_transfers":  int64(20),
+		"max_transaction_size": int64(maxTransactionSize),
+		"max_transfers":        int64(10),
+	}
+
+	// when
+	res, e := suite.networkService.NetworkOptions(defaultContext, nil)
+
+	// then
+	suite.mockFileDataRepo.AssertExpectations(suite.T())
+	assert.Nil(suite.T(), e)
+	assert.Equal(suite.T(), expected, res.Version.Metadata[metadataKeyNetworkParameters])
+}
+
+func (suite *onlineNetworkServiceSuite) TestNetworkOptionsLedgerIdMismatch() {
+	// given
+	data, _ := proto.Marshal(&services.ServicesConfigurationList{
+		NameValue: []*services.Setting{{Name: "ledger.id", Value: "0x00"}},
+	})
+	suite.mockFileDataRepo.On("GetLatestContent").Return(data, mocks.NilError)
+
+	// when
+	res, e := suite.networkService.NetworkOptions(defaultContext, nil)
+
+	// then
+	suite.mockFileDataRepo.AssertExpectations(suite.T())
+	assert.Equal(suite.T(), errors.ErrLedgerIdMismatch.Code, e.Code)
+	assert.Nil(suite.T(), res)
+}
+
+func (suite *onlineNetworkServiceSuite) TestNetworkOptionsNetworkParametersFallback() {
+	tests := []struct {
+		name string
+		data []byte
+		err  *rTypes.Error
+	}{
+		{name: "db error", data: []byte{}, err: errors.ErrDatabaseError},
+		{name: "invalid content", data: []byte{0x1, 0x2}, err: mocks.NilError},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			// given
+			mockFileDataRepo := &mocks.MockFileDataRepository{}
+			mockFileDataRepo.On("GetLatestContent").Return(tt.data, tt.err)
+			baseService := NewOnlineBaseService(suite.mockBlockRepo, suite.mockTransactionRepo)
+			networkService := getNetworkAPIService(suite.mockAddressBookEntryRepo, mockFileDataRepo, baseService)
+
+			// when
+			res, e := networkService.NetworkOptions(defaultContext, nil)
+
+			// then
+			mockFileDataRepo.AssertExpectations(t)
+			assert.Nil(t, e)
+			assert.Equal(t, defaultNetworkParametersMetadata, res.Version.Metadata[metadataKeyNetworkParameters])
+		})
+	}
+}
+
+func (suite *onlineNetworkServiceSuite) TestNetworkOptionsVersionMetadataNotModified() {
+	// given
+	suite.mockFileDataRepo.On("GetLatestContent").Return([]byte{}, mocks.NilError)
+	version := &rTypes.Version{Metadata: map[string]interface{}{"git_commit": "abc"}}
+	baseService := NewOnlineBaseService(suite.mockBlockRepo, suite.mockTransactionRepo)
+	networkService, _ := NewNetworkAPIService(
+		baseService,
+		suite.mockAddressBookEntryRepo,
+		suite.mockFileDataRepo,
+		&rTypes.NetworkIdentifier{},
+		defaultNetworkParameters,
+		0,
+		0,
+		version,
+	)
+
+	// when
+	res, e := networkService.NetworkOptions(defaultContext, nil)
+
+	// then
+	assert.Nil(suite.T(), e)
+	assert.Equal(suite.T(), "abc", res.Version.Metadata["git_commit"])
+	assert.Equal(suite.T(), map[string]interface{}{"git_commit": "abc"}, version.Metadata)
+}
+
+func (suite *onlineNetworkServiceSuite) TestNetworkOptionsSpecVersion() {
+	// given:
+	suite.mockFileDataRepo.On("GetLatestContent").Return([]byte{}, mocks.NilError)
+
+	// when:
+	res, e := suite.networkService.NetworkOptions(types.WithSpecVersion(defaultContext, "1.5.0"), nil)
+
+	// then:
+	assert.Nil(suite.T(), e)
+	assert.Equal(suite.T(), "1.5.0", res.Version.RosettaVersion)
+	assert.Equal(suite.T(), "1", res.Version.NodeVersion)
+}
+
+func (suite *onlineNetworkServiceSuite) TestNetworkOptionsCallMethods() {
+	// given:
+	suite.mockFileDataRepo.On("GetLatestContent").Return([]byte{}, mocks.NilError)
+
+	// when:
+	res, e := suite.networkService.NetworkOptions(nil, nil)
+
+	// then:
+	assert.Nil(suite.T(), e)
+	assert.ElementsMatch(suite.T(), types.SupportedCallMethods, res.Allow.CallMethods)
 }
 
 func (suite *onlineNetworkServiceSuite) TestNetworkStatus() {
@@ -247,6 +433,7 @@ func (suite *onlineNetworkServiceSuite) TestNetworkStatus() {
 
 	suite.mockBlockRepo.On("RetrieveGenesis").Return(dummyGenesisBlock(), mocks.NilError)
 	suite.mockBlockRepo.On("RetrieveLatest").Return(dummySecondLatestBlock(), mocks.NilError)
+	suite.mockBlockRepo.On("RetrieveOldest").Return(dummyGenesisBlock(), mocks.NilError)
 	suite.mockAddressBookEntryRepo.On("Entries").Return(exampleEntries, mocks.NilError)
 
 	// when:
@@ -282,10 +469,43 @@ func (suite *onlineNetworkServiceSuite) TestNetworkStatusThrowsWhenRetrieveSecon
 	assert.NotNil(suite.T(), e)
 }
 
+func (suite *onlineNetworkServiceSuite) TestNetworkStatusWithPrunedHistory() {
+	// given:
+	exampleEntries := &types.AddressBookEntries{Entries: []types.AddressBookEntry{}}
+	oldestBlock := &types.Block{Index: 5, Hash: "0x5a", ConsensusStartNanos: 30000000, ConsensusEndNanos: 35000000}
+	suite.mockBlockRepo.On("RetrieveGenesis").Return(dummyGenesisBlock(), mocks.NilError)
+	suite.mockBlockRepo.On("RetrieveLatest").Return(dummySecondLatestBlock(), mocks.NilError)
+	suite.mockBlockRepo.On("RetrieveOldest").Return(oldestBlock, mocks.NilError)
+	suite.mockAddressBookEntryRepo.On("Entries").Return(exampleEntries, mocks.NilError)
+
+	// when:
+	res, e := suite.networkService.NetworkStatus(defaultContext, nil)
+
+	// then:
+	assert.Nil(suite.T(), e)
+	assert.Equal(suite.T(), &rTypes.BlockIdentifier{Index: 5, Hash: "0x5a"}, res.OldestBlockIdentifier)
+	assert.Equal(suite.T(), int64(1), res.GenesisBlockIdentifier.Index)
+}
+
+func (suite *onlineNetworkServiceSuite) TestNetworkStatusThrowsWhenRetrieveOldestFails() {
+	// given:
+	suite.mockBlockRepo.On("RetrieveGenesis").Return(dummyGenesisBlock(), mocks.NilError)
+	suite.mockBlockRepo.On("RetrieveLatest").Return(dummySecondLatestBlock(), mocks.NilError)
+	suite.mockBlockRepo.On("RetrieveOldest").Return(mocks.NilBlock, errors.ErrDatabaseError)
+
+	// when:
+	res, e := suite.networkService.NetworkStatus(defaultContext, nil)
+
+	// then:
+	assert.Nil(suite.T(), res)
+	assert.Equal(suite.T(), errors.ErrDatabaseError, e)
+}
+
 func (suite *onlineNetworkServiceSuite) TestNetworkStatusThrowsWhenEntriesFail() {
 	// given:
 	suite.mockBlockRepo.On("RetrieveGenesis").Return(dummyGenesisBlock(), mocks.NilError)
 	suite.mockBlockRepo.On("RetrieveLatest").Return(dummySecondLatestBlock(), mocks.NilError)
+	suite.mockBlockRepo.On("RetrieveOldest").Return(dummyGenesisBlock(), mocks.NilError)
 	suite.mockAddressBookEntryRepo.On("Entries").Return(mocks.NilEntries, &rTypes.Error{})
 
 	// when:
@@ -295,3 +515,93 @@ func (suite *onlineNetworkServiceSuite) TestNetworkStatusThrowsWhenEntriesFail()
 	assert.Nil(suite.T(), res)
 	assert.NotNil(suite.T(), e)
 }
+
+func (suite *onlineNetworkServiceSuite) TestNetworkStatusStaleWhenDeadlineExceeded() {
+	// given:
+	exampleEntries := &types.AddressBookEntries{Entries: []types.AddressBookEntry{}}
+	suite.mockBlockRepo.On("RetrieveGenesis").Return(dummyGenesisBlock(), mocks.NilError)
+	suite.mockBlockRepo.On("RetrieveLatest").Return(dummySecondLatestBlock(), mocks.NilError).Once()
+	suite.mockBlockRepo.On("RetrieveOldest").Return(dummyGenesisBlock(), mocks.NilError)
+	suite.mockBlockRepo.On("RetrieveLatest").Return(mocks.NilBlock, errors.ErrDatabaseError)
+	suite.mockAddressBookEntryRepo.On("Entries").Return(exampleEntries, mocks.NilError)
+	expected, e := suite.networkService.NetworkStatus(defaultContext, nil)
+	assert.Nil(suite.T(), e)
+	ctx, cancel := context.WithTimeout(defaultContext, 0)
+	defer cancel()
+
+	// when:
+	res, e := suite.networkService.NetworkStatus(ctx, nil)
+
+	// then:
+	stage := "stale"
+	currentIndex := expected.CurrentBlockIdentifier.Index
+	stale := *expected
+	stale.SyncStatus = &rTypes.SyncStatus{CurrentIndex: &currentIndex, Stage: &stage}
+	assert.Nil(suite.T(), e)
+	assert.Equal(suite.T(), &stale, res)
+	assert.Nil(suite.T(), expected.SyncStatus)
+}
+
+func (suite *onlineNetworkServiceSuite) TestNetworkStatusDeadlineExceededWithoutLastStatus() {
+	// given:
+	suite.mockBlockRepo.On("RetrieveGenesis").Return(mocks.NilBlock, errors.ErrDatabaseError)
+	ctx, cancel := context.WithTimeout(defaultContext, 0)
+	defer cancel()
+
+	// when:
+	res, e := suite.networkService.NetworkStatus(ctx, nil)
+
+	// then:
+	assert.Equal(suite.T(), errors.ErrEndpointTimeout, e)
+	assert.Nil(suite.T(), res)
+}
+
+func (suite *onlineNetworkServiceSuite) TestNetworkStatusNotStaleWhenFailedWithinDeadline() {
+	// given:
+	exampleEntries := &types.AddressBookEntries{Entries: []types.AddressBookEntry{}}
+	suite.mockBlockRepo.On("RetrieveGenesis").Return(dummyGenesisBlock(), mocks.NilError)
+	suite.mockBlockRepo.On("RetrieveLatest").Return(dummySecondLatestBlock(), mocks.NilError).Once()
+	suite.mockBlockRepo.On("RetrieveOldest").Return(dummyGenesisBlock(), mocks.NilError)
+	suite.mockBlockRepo.On("RetrieveLatest").Return(mocks.NilBlock, errors.ErrDatabaseError)
+	suite.mockAddressBookEntryRepo.On("Entries").Return(exampleEntries, mocks.NilError)
+	_, e := suite.networkService.NetworkStatus(defaultContext, nil)
+	assert.Nil(suite.T(), e)
+
+	// when:
+	res, e := suite.networkService.NetworkStatus(defaultContext, nil)
+
+	// then:
+	assert.Equal(suite.T(), errors.ErrDatabaseError, e)
+	assert.Nil(suite.T(), res)
+}
+
+func TestNewNetworkAPIServiceInvalidLedgerId(t *testing.T) {
+	tests := []struct {
+		name     string
+		ledgerId string
+		network  string
+	}{
+		{name: "not hex", ledgerId: "0xzz", network: "demo"},
+		{name: "public network mismatch", ledgerId: "0x01", network: "mainnet"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			networkService, err := NewNetworkAPIService(
+				NewOfflineBaseService(),
+				nil,
+				nil,
+				&rTypes.NetworkIdentifier{Blockchain: types.Blockchain, Network: tt.network},
+				config.NetworkParameters{LedgerId: tt.ledgerId},
+				0,
+				0,
+				&rTypes.Version{},
+			)
+
+			// then
+			assert.Error(t, err)
+			assert.Nil(t, networkService)
+		})
+	}
+}
@@ -0,0 +1,387 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package services
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/hashgraph/hedera-protobufs-go/sdk"
+	"github.com/hashgraph/hedera-protobufs-go/services"
+	"github.com/hashgraph/hedera-sdk-go/v2"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/proxy"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const certificateVerificationTimeout = 5 * time.Second
+
+// submitMethods are the gRPC methods of the node services which accept the transactions of each hedera functionality
+// the construction service supports
+var submitMethods = map[services.HederaFunctionality]string{
+	services.HederaFunctionality_CryptoCreate:               "/proto.CryptoService/createAccount",
+	services.HederaFunctionality_CryptoTransfer:             "/proto.CryptoService/cryptoTransfer",
+	services.HederaFunctionality_TokenAccountWipe:           "/proto.TokenService/wipeTokenAccount",
+	services.HederaFunctionality_TokenAssociateToAccount:    "/proto.TokenService/associateTokens",
+	services.HederaFunctionality_TokenBurn:                  "/proto.TokenService/burnToken",
+	services.HederaFunctionality_TokenCreate:                "/proto.TokenService/createToken",
+	services.HederaFunctionality_TokenDelete:                "/proto.TokenService/deleteToken",
+	services.HederaFunctionality_TokenDissociateFromAccount: "/proto.TokenService/dissociateTokens",
+	services.HederaFunctionality_TokenFreezeAccount:         "/proto.TokenService/freezeTokenAccount",
+	services.HederaFunctionality_TokenGrantKycToAccount:     "/proto.TokenService/grantKycToTokenAccount",
+	services.HederaFunctionality_TokenMint:                  "/proto.TokenService/mintToken",
+	services.HederaFunctionality_TokenRevokeKycFromAccount:  "/proto.TokenService/revokeKycFromTokenAccount",
+	services.HederaFunctionality_TokenUnfreezeAccount:       "/proto.TokenService/unfreezeTokenAccount",
+	services.HederaFunctionality_TokenUpdate:                "/proto.TokenService/updateToken",
+}
+
+// nodeSubmitter submits the signed transactions to the network nodes over its own gRPC connections. The SDK client
+// dials the nodes without accepting a custom dialer, so the connections are created here to apply the proxy, the
+// connect timeout and the keepalive settings to them only, instead of to the whole process. With the certificate
+// verifier, the connections are over TLS and the node certificate is verified in the TLS handshake of the very
+// connection the transactions are submitted over
+type nodeSubmitter struct {
+	certificateVerifier interfaces.CertificateVerifier
+	connections         map[string]*grpc.ClientConn
+	dialOptions         []grpc.DialOption
+	mutex               sync.Mutex
+	network             map[string]hedera.AccountID
+	requestTimeout      time.Duration
+	verificationErrors  map[string]error
+}
+
+// certificateVerificationError is the error of a node connection whose certificate failed the verification
+type certificateVerificationError struct {
+	reason error
+}
+
+func (e *certificateVerificationError) Error() string {
+	return e.reason.Error()
+}
+
+func newNodeSubmitter(
+	network map[string]hedera.AccountID,
+	submitConfig config.Submit,
+	certificateVerifier interfaces.CertificateVerifier,
+) (*nodeSubmitter, error) {
+	dialer, err := newSubmitDialer(submitConfig.Proxy, submitConfig.ConnectTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	dialOptions := []grpc.DialOption{grpc.WithContextDialer(dialer)}
+	if submitConfig.KeepAliveTime > 0 {
+		dialOptions = append(dialOptions, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                submitConfig.KeepAliveTime,
+			Timeout:             submitConfig.KeepAliveTimeout,
+			PermitWithoutStream: true,
+		}))
+	}
+
+	return &nodeSubmitter{
+		certificateVerifier: certificateVerifier,
+		connections:         make(map[string]*grpc.ClientConn),
+		dialOptions:         dialOptions,
+		network:             network,
+		requestTimeout:      submitConfig.RequestTimeout,
+		verificationErrors:  make(map[string]error),
+	}, nil
+}
+
+// Submit submits the transaction to its node. The addresses of the node are tried in order until one is available
+func (s *nodeSubmitter) Submit(ctx context.Context, transaction interfaces.Transaction) error {
+	functionality, _ := getHederaFunctionality(transaction)
+	method, ok := submitMethods[functionality]
+	if !ok {
+		return fmt.Errorf("unsupported transaction type %T", transaction)
+	}
+
+	signedTransaction, err := getTransactionToSubmit(transaction)
+	if err != nil {
+		return err
+	}
+
+	nodeAccountId := transaction.GetNodeAccountIDs()[0]
+	addresses := s.getAddresses(nodeAccountId)
+	if len(addresses) == 0 {
+		return fmt.Errorf("node %s isn't in the network", nodeAccountId)
+	}
+
+	if s.requestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.requestTimeout)
+		defer cancel()
+	}
+
+	for _, address := range addresses {
+		var conn *grpc.ClientConn
+		if conn, err = s.getConnection(address, nodeAccountId); err != nil {
+			return err
+		}
+
+		response := &services.TransactionResponse{}
+		if err = conn.Invoke(ctx, method, signedTransaction, response); err != nil {
+			if status.Code(err) == codes.Unavailable {
+				log.Warnf("Node %s at %s is unavailable: %s", nodeAccountId, address, err)
+				if verificationErr := s.getVerificationError(address); verificationErr != nil {
+					err = &certificateVerificationError{reason: verificationErr}
+				}
+				continue
+			}
+			return err
+		}
+
+		if code := response.GetNodeTransactionPrecheckCode(); code != services.ResponseCodeEnum_OK {
+			return fmt.Errorf("exceptional precheck status %s", code)
+		}
+		return nil
+	}
+
+	return err
+}
+
+// getAddresses returns the sorted addresses of the node
+func (s *nodeSubmitter) getAddresses(nodeAccountId hedera.AccountID) []string {
+	addresses := make([]string, 0)
+	for address, accountId := range s.network {
+		if accountId == nodeAccountId {
+			addresses = append(addresses, address)
+		}
+	}
+	sort.Strings(addresses)
+	return addresses
+}
+
+// getConnection returns the connection to the address of the node, the connection is created when first used and then
+// reused
+func (s *nodeSubmitter) getConnection(address string, nodeAccountId hedera.AccountID) (*grpc.ClientConn, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if conn, ok := s.connections[address]; ok {
+		return conn, nil
+	}
+
+	transportCredentials := insecure.NewCredentials()
+	if s.certificateVerifier != nil {
+		transportCredentials = credentials.NewTLS(&tls.Config{
+			// the nodes present self-signed certificates, which are pinned to the address book in the handshake instead
+			InsecureSkipVerify: true, // nolint
+			VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+				return s.verifyCertificate(address, nodeAccountId, rawCerts)
+			},
+		})
+	}
+
+	dialOptions := append([]grpc.DialOption{grpc.WithTransportCredentials(transportCredentials)}, s.dialOptions...)
+	conn, err := grpc.Dial(address, dialOptions...)
+	if err != nil {
+		return nil, err
+	}
+	s.connections[address] = conn
+	return conn, nil
+}
+
+// verifyCertificate verifies the certificates the node presents in the TLS handshake and keeps the error of the last
+// verification of the address, so the submission fails with the reason instead of a generic connection error
+func (s *nodeSubmitter) verifyCertificate(address string, nodeAccountId hedera.AccountID, rawCerts [][]byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), certificateVerificationTimeout)
+	defer cancel()
+
+	err := s.certificateVerifier.Verify(ctx, nodeAccountId, rawCerts)
+	if err != nil {
+		log.Errorf("Failed to verify the certificate of node %s at %s: %s", nodeAccountId, address, err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.verificationErrors[address] = err
+	return err
+}
+
+func (s *nodeSubmitter) getVerificationError(address string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.verificationErrors[address]
+}
+
+// getTransactionToSubmit returns the transaction protobuf message to submit. The transaction is built for a single
+// node, so the serialized transaction list has exactly one transaction
+func getTransactionToSubmit(transaction interfaces.Transaction) (*services.Transaction, error) {
+	transactionBytes, err := transaction.ToBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	var transactionList sdk.TransactionList
+	if err = proto.Unmarshal(transactionBytes, &transactionList); err != nil {
+		return nil, err
+	}
+
+	if len(transactionList.GetTransactionList()) != 1 {
+		return nil, fmt.Errorf("expect 1 transaction, got %d", len(transactionList.GetTransactionList()))
+	}
+
+	return transactionList.GetTransactionList()[0], nil
+}
+
+// newSubmitDialer creates the dialer of the connections to the network nodes. The connections go through the proxy if
+// set, either an HTTP proxy with the CONNECT method or a SOCKS5 proxy. The connect timeout bounds the whole dial
+// including the proxy handshake
+func newSubmitDialer(proxyAddress string, connectTimeout time.Duration) (
+	func(context.Context, string) (net.Conn, error),
+	error,
+) {
+	netDialer := &net.Dialer{}
+	withTimeout := func(ctx context.Context) (context.Context, context.CancelFunc) {
+		if connectTimeout > 0 {
+			return context.WithTimeout(ctx, connectTimeout)
+		}
+		return context.WithCancel(ctx)
+	}
+
+	if proxyAddress == "" {
+		return func(ctx context.Context, address string) (net.Conn, error) {
+			ctx, cancel := withTimeout(ctx)
+			defer cancel()
+			return netDialer.DialContext(ctx, "tcp", address)
+		}, nil
+	}
+
+	proxyUrl, err := url.Parse(proxyAddress)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy url: %w", err)
+	}
+
+	if proxyUrl.Hostname() == "" || proxyUrl.Port() == "" {
+		return nil, fmt.Errorf("invalid proxy %s, the host and the port are required", proxyUrl.Redacted())
+	}
+
+	var dial func(context.Context, string) (net.Conn, error)
+	switch proxyUrl.Scheme {
+	case "http", "https":
+		dial = func(ctx context.Context, address string) (net.Conn, error) {
+			return dialHttpProxy(ctx, netDialer, proxyUrl, address)
+		}
+	case "socks5", "socks5h":
+		socksDialer, err := proxy.FromURL(proxyUrl, netDialer)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy %s: %w", proxyUrl.Redacted(), err)
+		}
+		dial = func(ctx context.Context, address string) (net.Conn, error) {
+			return socksDialer.(proxy.ContextDialer).DialContext(ctx, "tcp", address)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported proxy %s, only HTTP and SOCKS5 proxies are supported", proxyUrl.Redacted())
+	}
+
+	log.Infof("Submitting transactions through proxy %s", proxyUrl.Redacted())
+	return func(ctx context.Context, address string) (net.Conn, error) {
+		ctx, cancel := withTimeout(ctx)
+		defer cancel()
+		return dial(ctx, address)
+	}, nil
+}
+
+// dialHttpProxy connects to the address through the HTTP proxy with the CONNECT method
+func dialHttpProxy(ctx context.Context, netDialer *net.Dialer, proxyUrl *url.URL, address string) (net.Conn, error) {
+	conn, err := netDialer.DialContext(ctx, "tcp", proxyUrl.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if proxyUrl.Scheme == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: proxyUrl.Hostname()})
+		if err = tlsConn.HandshakeContext(ctx); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+		conn = tlsConn
+	}
+
+	request := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Host: address},
+		Host:   address,
+		Header: make(http.Header),
+	}
+	if user := proxyUrl.User; user != nil {
+		password, _ := user.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(user.Username() + ":" + password))
+		request.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+
+	if err = request.Write(conn); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, request)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	_ = response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		_ = conn.Close()
+		return nil, fmt.Errorf("proxy %s failed to connect to %s: %s", proxyUrl.Redacted(), address, response.Status)
+	}
+
+	_ = conn.SetDeadline(time.Time{})
+	if reader.Buffered() > 0 {
+		return &bufferedConn{Conn: conn, reader: reader}, nil
+	}
+	return conn, nil
+}
+
+// bufferedConn is a net.Conn which reads the data the proxy sent right after the CONNECT response from the reader first
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
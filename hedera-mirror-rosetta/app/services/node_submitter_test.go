@@ -0,0 +1,402 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/mocks"
+	"github.com/hashgraph/hedera-protobufs-go/services"
+	"github.com/hashgraph/hedera-sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+var (
+	proxyAuthorization  = "Basic " + base64.StdEncoding.EncodeToString([]byte("user:password"))
+	submitNodeAccountId = hedera.AccountID{Account: 4}
+)
+
+type cryptoServiceStub struct {
+	services.UnimplementedCryptoServiceServer
+	code         services.ResponseCodeEnum
+	transactions []*services.Transaction
+}
+
+func (s *cryptoServiceStub) CryptoTransfer(_ context.Context, transaction *services.Transaction) (
+	*services.TransactionResponse,
+	error,
+) {
+	s.transactions = append(s.transactions, transaction)
+	return &services.TransactionResponse{NodeTransactionPrecheckCode: s.code}, nil
+}
+
+func TestNodeSubmitterSubmit(t *testing.T) {
+	for _, code := range []services.ResponseCodeEnum{
+		services.ResponseCodeEnum_OK,
+		services.ResponseCodeEnum_INSUFFICIENT_PAYER_BALANCE,
+	} {
+		t.Run(code.String(), func(t *testing.T) {
+			// given
+			stub := &cryptoServiceStub{code: code}
+			address := serveNode(t, stub)
+			submitter := newTestNodeSubmitter(t, map[string]hedera.AccountID{address: submitNodeAccountId}, "", nil)
+			transaction := submitTransaction(t)
+
+			// when
+			err := submitter.Submit(defaultContext, transaction)
+
+			// then
+			if code == services.ResponseCodeEnum_OK {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, "exceptional precheck status INSUFFICIENT_PAYER_BALANCE")
+			}
+			require.Len(t, stub.transactions, 1)
+			expected, _ := getTransactionToSubmit(transaction)
+			assert.Equal(t, expected.GetSignedTransactionBytes(), stub.transactions[0].GetSignedTransactionBytes())
+		})
+	}
+}
+
+func TestNodeSubmitterSubmitNextAddressWhenUnavailable(t *testing.T) {
+	// given
+	stub := &cryptoServiceStub{}
+	_, port, _ := net.SplitHostPort(serveNode(t, stub))
+	// the unavailable address 127.0.0.1:x sorts before localhost:y, so it's tried first
+	network := map[string]hedera.AccountID{
+		net.JoinHostPort("localhost", port): submitNodeAccountId,
+		unusedAddress(t):                    submitNodeAccountId,
+	}
+	submitter := newTestNodeSubmitter(t, network, "", nil)
+
+	// when
+	err := submitter.Submit(defaultContext, submitTransaction(t))
+
+	// then
+	assert.NoError(t, err)
+	assert.Len(t, stub.transactions, 1)
+}
+
+func TestNodeSubmitterSubmitNodeUnavailable(t *testing.T) {
+	// given
+	submitter := newTestNodeSubmitter(t, map[string]hedera.AccountID{unusedAddress(t): submitNodeAccountId}, "", nil)
+
+	// when
+	err := submitter.Submit(defaultContext, submitTransaction(t))
+
+	// then
+	assert.Error(t, err)
+}
+
+func TestNodeSubmitterSubmitNodeNotInNetwork(t *testing.T) {
+	// given
+	submitter := newTestNodeSubmitter(t, map[string]hedera.AccountID{"10.0.0.1:50211": {Account: 3}}, "", nil)
+
+	// when
+	err := submitter.Submit(defaultContext, submitTransaction(t))
+
+	// then
+	assert.EqualError(t, err, "node 0.0.4 isn't in the network")
+}
+
+func TestNodeSubmitterSubmitThroughHttpProxy(t *testing.T) {
+	// given
+	stub := &cryptoServiceStub{}
+	address := serveNode(t, stub)
+	proxyAddress := serveProxy(t, httpConnect)
+	network := map[string]hedera.AccountID{address: submitNodeAccountId}
+	submitter := newTestNodeSubmitter(t, network, fmt.Sprintf("http://user:password@%s", proxyAddress), nil)
+
+	// when
+	err := submitter.Submit(defaultContext, submitTransaction(t))
+
+	// then
+	assert.NoError(t, err)
+	assert.Len(t, stub.transactions, 1)
+}
+
+func TestNodeSubmitterSubmitThroughHttpProxyUnauthorized(t *testing.T) {
+	// given
+	stub := &cryptoServiceStub{}
+	address := serveNode(t, stub)
+	proxyAddress := serveProxy(t, httpConnect)
+	network := map[string]hedera.AccountID{address: submitNodeAccountId}
+	submitter := newTestNodeSubmitter(t, network, fmt.Sprintf("http://%s", proxyAddress), nil)
+
+	// when
+	err := submitter.Submit(defaultContext, submitTransaction(t))
+
+	// then
+	assert.Error(t, err)
+	assert.Empty(t, stub.transactions)
+}
+
+func TestNodeSubmitterSubmitThroughSocksProxy(t *testing.T) {
+	// given
+	stub := &cryptoServiceStub{}
+	address := serveNode(t, stub)
+	proxyAddress := serveProxy(t, socks5Connect)
+	network := map[string]hedera.AccountID{address: submitNodeAccountId}
+	submitter := newTestNodeSubmitter(t, network, fmt.Sprintf("socks5://%s", proxyAddress), nil)
+
+	// when
+	err := submitter.Submit(defaultContext, submitTransaction(t))
+
+	// then
+	assert.NoError(t, err)
+	assert.Len(t, stub.transactions, 1)
+}
+
+func TestNodeSubmitterSubmitCertificateVerified(t *testing.T) {
+	// given
+	stub := &cryptoServiceStub{}
+	address, certificate := serveTlsNode(t, stub)
+	certificateVerifier := &mocks.MockCertificateVerifier{}
+	certificateVerifier.On("Verify", mock.Anything, submitNodeAccountId, [][]byte{certificate}).Return(nil)
+	network := map[string]hedera.AccountID{address: submitNodeAccountId}
+	submitter := newTestNodeSubmitter(t, network, "", certificateVerifier)
+
+	// when
+	err := submitter.Submit(defaultContext, submitTransaction(t))
+
+	// then
+	assert.NoError(t, err)
+	assert.Len(t, stub.transactions, 1)
+	certificateVerifier.AssertExpectations(t)
+}
+
+func TestNodeSubmitterSubmitCertificateVerificationFailed(t *testing.T) {
+	// given
+	stub := &cryptoServiceStub{}
+	address, certificate := serveTlsNode(t, stub)
+	certificateVerifier := &mocks.MockCertificateVerifier{}
+	certificateVerifier.On("Verify", mock.Anything, submitNodeAccountId, [][]byte{certificate}).
+		Return(fmt.Errorf("certificate mismatch"))
+	network := map[string]hedera.AccountID{address: submitNodeAccountId}
+	submitter := newTestNodeSubmitter(t, network, "", certificateVerifier)
+
+	// when
+	err := submitter.Submit(defaultContext, submitTransaction(t))
+
+	// then
+	assert.Equal(t, &certificateVerificationError{reason: fmt.Errorf("certificate mismatch")}, err)
+	assert.Empty(t, stub.transactions)
+	certificateVerifier.AssertExpectations(t)
+}
+
+func TestNewSubmitDialerConnectTimeout(t *testing.T) {
+	// given
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	// the proxy accepts the connection but never answers the CONNECT request
+	dialer, err := newSubmitDialer(fmt.Sprintf("http://%s", listener.Addr()), 100*time.Millisecond)
+	require.NoError(t, err)
+
+	// when
+	start := time.Now()
+	conn, err := dialer(defaultContext, "10.0.0.1:50211")
+
+	// then
+	assert.Error(t, err)
+	assert.Nil(t, conn)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func newTestNodeSubmitter(
+	t *testing.T,
+	network map[string]hedera.AccountID,
+	proxy string,
+	certificateVerifier interfaces.CertificateVerifier,
+) *nodeSubmitter {
+	submitConfig := config.Submit{ConnectTimeout: time.Second, Proxy: proxy, RequestTimeout: 5 * time.Second}
+	submitter, err := newNodeSubmitter(network, submitConfig, certificateVerifier)
+	require.NoError(t, err)
+	return submitter
+}
+
+func submitTransaction(t *testing.T) interfaces.Transaction {
+	transaction, rErr := unmarshallTransactionFromHexString(validSignedTransaction)
+	require.Nil(t, rErr)
+	require.Equal(t, []hedera.AccountID{submitNodeAccountId}, transaction.GetNodeAccountIDs())
+	return transaction
+}
+
+// serveNode serves the crypto service stub as a network node and returns its address
+func serveNode(t *testing.T, stub *cryptoServiceStub) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	services.RegisterCryptoServiceServer(server, stub)
+	go server.Serve(listener) // nolint
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String()
+}
+
+// serveTlsNode serves the crypto service stub as a network node over TLS and returns its address and its certificate
+func serveTlsNode(t *testing.T, stub *cryptoServiceStub) (string, []byte) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	// borrow the self-signed certificate of the httptest TLS server
+	tlsServer := httptest.NewTLSServer(http.NotFoundHandler())
+	tlsServer.Close()
+	certificate := tlsServer.TLS.Certificates[0]
+
+	server := grpc.NewServer(grpc.Creds(credentials.NewServerTLSFromCert(&certificate)))
+	services.RegisterCryptoServiceServer(server, stub)
+	go server.Serve(listener) // nolint
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String(), certificate.Certificate[0]
+}
+
+// unusedAddress returns a local address nothing listens on
+func unusedAddress(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	return address
+}
+
+// serveProxy serves a proxy which runs the handshake on each connection to get the target address, then relays the
+// data between the connection and the target
+func serveProxy(t *testing.T, handshake func(net.Conn, *bufio.Reader) (string, error)) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				address, err := handshake(conn, reader)
+				if err != nil {
+					return
+				}
+
+				target, err := net.Dial("tcp", address)
+				if err != nil {
+					return
+				}
+				defer target.Close()
+
+				go io.Copy(conn, target) // nolint
+				_, _ = io.Copy(target, reader)
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func httpConnect(conn net.Conn, reader *bufio.Reader) (string, error) {
+	request, err := http.ReadRequest(reader)
+	if err != nil {
+		return "", err
+	}
+
+	if request.Method != http.MethodConnect || request.Header.Get("Proxy-Authorization") != proxyAuthorization {
+		_, _ = conn.Write([]byte("HTTP/1.1 407 Proxy Authentication Required\r\n\r\n"))
+		return "", fmt.Errorf("unauthorized")
+	}
+
+	_, err = conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+	return request.Host, err
+}
+
+func socks5Connect(conn net.Conn, reader *bufio.Reader) (string, error) {
+	// greeting: version, number of methods, methods
+	greeting := make([]byte, 2)
+	if _, err := io.ReadFull(reader, greeting); err != nil {
+		return "", err
+	}
+	if _, err := io.ReadFull(reader, make([]byte, greeting[1])); err != nil {
+		return "", err
+	}
+	// no authentication required
+	if _, err := conn.Write([]byte{5, 0}); err != nil {
+		return "", err
+	}
+
+	// request: version, command, reserved, address type, address, port
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return "", err
+	}
+
+	var host string
+	switch header[3] {
+	case 1:
+		ip := make([]byte, 4)
+		if _, err := io.ReadFull(reader, ip); err != nil {
+			return "", err
+		}
+		host = net.IP(ip).String()
+	case 3:
+		length, err := reader.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		domain := make([]byte, length)
+		if _, err = io.ReadFull(reader, domain); err != nil {
+			return "", err
+		}
+		host = string(domain)
+	default:
+		return "", fmt.Errorf("unsupported address type %d", header[3])
+	}
+
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(reader, port); err != nil {
+		return "", err
+	}
+
+	// succeeded, bound to 0.0.0.0:0
+	_, err := conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+	return net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))), err
+}
@@ -0,0 +1,286 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/redaction"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	signatureHeader = "X-Rosetta-Signature"
+	signaturePrefix = "sha256="
+)
+
+// TransactionEvent is the payload posted to the webhooks for a transaction of the tracked accounts
+type TransactionEvent struct {
+	BlockIdentifier   *rTypes.BlockIdentifier   `json:"block_identifier"`
+	NetworkIdentifier *rTypes.NetworkIdentifier `json:"network_identifier"`
+	Timestamp         int64                     `json:"timestamp"`
+	Transaction       *rTypes.Transaction       `json:"transaction"`
+}
+
+// permanentError is a delivery error which won't go away if retried, e.g., the webhook rejected the request with 400
+type permanentError struct {
+	error
+}
+
+// Notifier polls the new blocks and posts the transactions of the tracked accounts as rosetta formatted events to the
+// webhooks, so clients don't need to poll /block. The body of each request is signed with HMAC-SHA256 using the
+// shared secret, and the hex encoded signature is sent in the X-Rosetta-Signature header
+type Notifier struct {
+	BaseService
+	accountRepo interfaces.AccountRepository
+	accounts    map[int64]bool
+	config      config.Notifier
+	httpClient  *http.Client
+	lastIndex   int64
+	network     *rTypes.NetworkIdentifier
+	redactor    *redaction.Redactor
+}
+
+// NewNotifier creates a Notifier. Invalid accounts are ignored. The metadata of the events is redacted with the same
+// redactor as the http responses
+func NewNotifier(
+	accountRepo interfaces.AccountRepository,
+	baseService BaseService,
+	notifierConfig config.Notifier,
+	network *rTypes.NetworkIdentifier,
+	redactor *redaction.Redactor,
+) *Notifier {
+	accounts := make(map[int64]bool)
+	for _, account := range notifierConfig.Accounts {
+		entityId, err := domain.EntityIdFromString(account)
+		if err != nil {
+			log.Warnf("Ignoring invalid notifier account %s: %s", account, err)
+			continue
+		}
+		accounts[entityId.EncodedId] = true
+	}
+
+	if notifierConfig.MaxAttempts < 1 {
+		notifierConfig.MaxAttempts = 1
+	}
+
+	return &Notifier{
+		BaseService: baseService,
+		accountRepo: accountRepo,
+		accounts:    accounts,
+		config:      notifierConfig,
+		httpClient:  &http.Client{Timeout: notifierConfig.Timeout},
+		lastIndex:   -1,
+		network:     network,
+		redactor:    redactor,
+	}
+}
+
+// Run polls the new blocks every poll interval until the context is done. The notifications start from the blocks
+// after the latest block at the time of the first successful poll
+func (n *Notifier) Run(ctx context.Context) {
+	log.Infof("Notifying %d webhooks of the transactions of %d accounts", len(n.config.Webhooks), len(n.accounts))
+	ticker := time.NewTicker(n.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := n.poll(ctx); err != nil {
+			log.Warnf("Failed to poll new blocks after block %d: %s", n.lastIndex, err.Message)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll processes the blocks after the last processed block up to the latest block. A block failed to process is
+// retried in the next poll
+func (n *Notifier) poll(ctx context.Context) *rTypes.Error {
+	latest, err := n.RetrieveLatest(ctx)
+	if err != nil {
+		return err
+	}
+
+	if n.lastIndex < 0 {
+		log.Infof("Start notifying from block %d", latest.Index+1)
+		n.lastIndex = latest.Index
+		return nil
+	}
+
+	for index := n.lastIndex + 1; index <= latest.Index; index++ {
+		if err = n.processBlock(ctx, index); err != nil {
+			return err
+		}
+		n.lastIndex = index
+	}
+
+	return nil
+}
+
+func (n *Notifier) processBlock(ctx context.Context, index int64) *rTypes.Error {
+	block, err := n.RetrieveBlock(ctx, &rTypes.PartialBlockIdentifier{Index: &index})
+	if err != nil {
+		return err
+	}
+
+	transactions, err := n.FindBetween(ctx, block.ConsensusStartNanos, block.ConsensusEndNanos)
+	if err != nil {
+		return err
+	}
+
+	for _, transaction := range transactions {
+		if !n.isTracked(transaction) {
+			continue
+		}
+
+		if err = n.updateOperationAccountAlias(ctx, transaction); err != nil {
+			return err
+		}
+
+		n.notify(ctx, TransactionEvent{
+			BlockIdentifier:   block.GetRosettaBlockIdentifier(),
+			NetworkIdentifier: n.network,
+			Timestamp:         block.GetTimestampMillis(),
+			Transaction:       transaction.ToRosetta(),
+		})
+	}
+
+	return nil
+}
+
+func (n *Notifier) isTracked(transaction *types.Transaction) bool {
+	for _, operation := range transaction.Operations {
+		if n.accounts[operation.AccountId.GetId()] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// updateOperationAccountAlias presents the accounts with the alias the same way as /block does
+func (n *Notifier) updateOperationAccountAlias(ctx context.Context, transaction *types.Transaction) *rTypes.Error {
+	for index := range transaction.Operations {
+		accountAlias, err := n.accountRepo.GetAccountAlias(ctx, transaction.Operations[index].AccountId)
+		if err != nil {
+			return err
+		}
+		transaction.Operations[index].AccountId = accountAlias
+	}
+
+	return nil
+}
+
+// notify posts the event to every webhook. An event which can't be delivered after the max attempts is dropped so a
+// failing webhook doesn't block the notifications of the following transactions. The body is redacted before it's
+// signed
+func (n *Notifier) notify(ctx context.Context, event TransactionEvent) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Errorf("Failed to marshal event of transaction %s: %s", event.Transaction.TransactionIdentifier.Hash, err)
+		return
+	}
+
+	body = n.redactor.Redact(body)
+
+	signature := sign(body, n.config.Secret)
+	for _, webhook := range n.config.Webhooks {
+		if err = n.deliver(ctx, webhook, body, signature); err != nil {
+			log.Errorf("Failed to notify %s of transaction %s: %s", webhook,
+				event.Transaction.TransactionIdentifier.Hash, err)
+		}
+	}
+}
+
+// deliver posts the body to the webhook, and retries with exponential backoff if the request fails with a network
+// error, 429, or 5xx
+func (n *Notifier) deliver(ctx context.Context, webhook string, body []byte, signature string) error {
+	backoff := n.config.Backoff
+	for attempt := 1; ; attempt++ {
+		err := n.post(ctx, webhook, body, signature)
+		if err == nil {
+			return nil
+		}
+
+		if _, ok := err.(permanentError); ok || attempt >= n.config.MaxAttempts {
+			return err
+		}
+
+		log.Warnf("Failed to notify %s, retrying in %s (attempt %d of %d): %s", webhook, backoff, attempt,
+			n.config.MaxAttempts, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (n *Notifier) post(ctx context.Context, webhook string, body []byte, signature string) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		return permanentError{err}
+	}
+	request.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	request.Header.Set(signatureHeader, signature)
+
+	response, err := n.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return nil
+	}
+
+	err = fmt.Errorf("unexpected status code %d", response.StatusCode)
+	if response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500 {
+		return err
+	}
+	return permanentError{err}
+}
+
+// sign returns the hex encoded HMAC-SHA256 of the body with the prefix "sha256="
+func sign(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return fmt.Sprintf("%s%s", signaturePrefix, hex.EncodeToString(mac.Sum(nil)))
+}
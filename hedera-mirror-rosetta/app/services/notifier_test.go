@@ -0,0 +1,272 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/redaction"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+const notifierSecret = "secret"
+
+// webhookRequest is a request received by the test webhook
+type webhookRequest struct {
+	body      []byte
+	signature string
+}
+
+func TestNotifierSuite(t *testing.T) {
+	suite.Run(t, new(notifierSuite))
+}
+
+type notifierSuite struct {
+	suite.Suite
+	mockAccountRepo     *mocks.MockAccountRepository
+	mockBlockRepo       *mocks.MockBlockRepository
+	mockTransactionRepo *mocks.MockTransactionRepository
+	requests            []webhookRequest
+	requestsLock        sync.Mutex
+	statusCodes         []int
+	webhook             *httptest.Server
+}
+
+func (suite *notifierSuite) SetupTest() {
+	suite.mockAccountRepo = &mocks.MockAccountRepository{}
+	suite.mockBlockRepo = &mocks.MockBlockRepository{}
+	suite.mockTransactionRepo = &mocks.MockTransactionRepository{}
+	suite.requests = nil
+	suite.statusCodes = nil
+	suite.webhook = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		suite.requestsLock.Lock()
+		defer suite.requestsLock.Unlock()
+
+		body, _ := io.ReadAll(r.Body)
+		suite.requests = append(suite.requests, webhookRequest{body: body, signature: r.Header.Get(signatureHeader)})
+		statusCode := http.StatusOK
+		if len(suite.statusCodes) != 0 {
+			statusCode = suite.statusCodes[0]
+			suite.statusCodes = suite.statusCodes[1:]
+		}
+		w.WriteHeader(statusCode)
+	}))
+}
+
+func (suite *notifierSuite) TearDownTest() {
+	suite.webhook.Close()
+}
+
+func (suite *notifierSuite) TestPollFirstTime() {
+	// given
+	suite.mockBlockRepo.On("RetrieveLatest").Return(block(), mocks.NilError)
+	notifier := suite.newNotifier()
+
+	// when
+	err := notifier.poll(defaultContext)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), block().Index, notifier.lastIndex)
+	suite.mockBlockRepo.AssertNotCalled(suite.T(), "FindByIndex")
+	assert.Empty(suite.T(), suite.requests)
+}
+
+func (suite *notifierSuite) TestPoll() {
+	// given
+	latest := block()
+	latest.Index = 3
+	suite.mockBlockRepo.On("RetrieveLatest").Return(latest, mocks.NilError)
+	suite.mockBlockRepo.On("FindByIndex").Return(block(), mocks.NilError)
+	suite.mockTransactionRepo.On("FindBetween").Return([]*types.Transaction{
+		makeTransaction(nil, "0x123"),
+		notifierTransaction(entityId, "0x246"),
+	}, mocks.NilError).Once()
+	suite.mockTransactionRepo.On("FindBetween").Return([]*types.Transaction{}, mocks.NilError)
+	suite.mockAccountRepo.On("GetAccountAlias").Return(accountAlias, mocks.NilError)
+	notifier := suite.newNotifier()
+	notifier.lastIndex = 1
+
+	// when
+	err := notifier.poll(defaultContext)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), int64(3), notifier.lastIndex)
+	suite.mockBlockRepo.AssertNumberOfCalls(suite.T(), "FindByIndex", 2)
+	suite.mockTransactionRepo.AssertNumberOfCalls(suite.T(), "FindBetween", 2)
+	suite.mockAccountRepo.AssertNumberOfCalls(suite.T(), "GetAccountAlias", 1)
+
+	assert.Len(suite.T(), suite.requests, 1)
+	request := suite.requests[0]
+	mac := hmac.New(sha256.New, []byte(notifierSecret))
+	mac.Write(request.body)
+	assert.Equal(suite.T(), "sha256="+hex.EncodeToString(mac.Sum(nil)), request.signature)
+
+	event := TransactionEvent{}
+	assert.NoError(suite.T(), json.Unmarshal(request.body, &event))
+	assert.Equal(suite.T(), block().GetRosettaBlockIdentifier(), event.BlockIdentifier)
+	assert.Equal(suite.T(), "testnet", event.NetworkIdentifier.Network)
+	assert.Equal(suite.T(), block().GetTimestampMillis(), event.Timestamp)
+	assert.Equal(suite.T(), "0x246", event.Transaction.TransactionIdentifier.Hash)
+	assert.Equal(suite.T(), accountAlias.String(), event.Transaction.Operations[0].Account.Address)
+}
+
+func (suite *notifierSuite) TestPollRetriesFailedBlock() {
+	// given
+	latest := block()
+	latest.Index = 3
+	suite.mockBlockRepo.On("RetrieveLatest").Return(latest, mocks.NilError)
+	suite.mockBlockRepo.On("FindByIndex").Return(block(), mocks.NilError)
+	suite.mockTransactionRepo.On("FindBetween").Return([]*types.Transaction{}, mocks.NilError).Once()
+	suite.mockTransactionRepo.On("FindBetween").Return([]*types.Transaction{}, errors.ErrDatabaseError)
+	notifier := suite.newNotifier()
+	notifier.lastIndex = 1
+
+	// when
+	err := notifier.poll(defaultContext)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Equal(suite.T(), int64(2), notifier.lastIndex)
+}
+
+func (suite *notifierSuite) TestPollRetrieveLatestFails() {
+	// given
+	suite.mockBlockRepo.On("RetrieveLatest").Return(mocks.NilBlock, errors.ErrNodeIsStarting)
+	notifier := suite.newNotifier()
+
+	// when
+	err := notifier.poll(defaultContext)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrNodeIsStarting, err)
+	assert.Equal(suite.T(), int64(-1), notifier.lastIndex)
+}
+
+func (suite *notifierSuite) TestNotifyRedacted() {
+	// given
+	notifier := suite.newNotifier()
+	notifier.redactor = redaction.NewRedactor(config.Redaction{Memos: true})
+	event := TransactionEvent{
+		BlockIdentifier: block().GetRosettaBlockIdentifier(),
+		Timestamp:       block().GetTimestampMillis(),
+		Transaction: &rTypes.Transaction{
+			TransactionIdentifier: &rTypes.TransactionIdentifier{Hash: "0x246"},
+			Metadata:              map[string]interface{}{"memo": "secret", "size": 10},
+		},
+	}
+
+	// when
+	notifier.notify(defaultContext, event)
+
+	// then
+	assert.Len(suite.T(), suite.requests, 1)
+	request := suite.requests[0]
+	mac := hmac.New(sha256.New, []byte(notifierSecret))
+	mac.Write(request.body)
+	assert.Equal(suite.T(), "sha256="+hex.EncodeToString(mac.Sum(nil)), request.signature)
+
+	actual := TransactionEvent{}
+	assert.NoError(suite.T(), json.Unmarshal(request.body, &actual))
+	assert.Equal(suite.T(), "0x246", actual.Transaction.TransactionIdentifier.Hash)
+	assert.Equal(suite.T(), map[string]interface{}{"size": float64(10)}, actual.Transaction.Metadata)
+}
+
+func (suite *notifierSuite) TestDeliverRetry() {
+	// given
+	suite.statusCodes = []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK}
+	notifier := suite.newNotifier()
+
+	// when
+	err := notifier.deliver(defaultContext, suite.webhook.URL, []byte("{}"), "sha256=00")
+
+	// then
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), suite.requests, 3)
+}
+
+func (suite *notifierSuite) TestDeliverMaxAttempts() {
+	// given
+	suite.statusCodes = []int{http.StatusInternalServerError, http.StatusInternalServerError,
+		http.StatusInternalServerError, http.StatusOK}
+	notifier := suite.newNotifier()
+
+	// when
+	err := notifier.deliver(defaultContext, suite.webhook.URL, []byte("{}"), "sha256=00")
+
+	// then
+	assert.Error(suite.T(), err)
+	assert.Len(suite.T(), suite.requests, 3)
+}
+
+func (suite *notifierSuite) TestDeliverNoRetryOnClientError() {
+	// given
+	suite.statusCodes = []int{http.StatusBadRequest}
+	notifier := suite.newNotifier()
+
+	// when
+	err := notifier.deliver(defaultContext, suite.webhook.URL, []byte("{}"), "sha256=00")
+
+	// then
+	assert.IsType(suite.T(), permanentError{}, err)
+	assert.Len(suite.T(), suite.requests, 1)
+}
+
+func (suite *notifierSuite) newNotifier() *Notifier {
+	baseService := NewOnlineBaseService(suite.mockBlockRepo, suite.mockTransactionRepo)
+	return NewNotifier(
+		suite.mockAccountRepo,
+		baseService,
+		config.Notifier{
+			Accounts:    []string{"0.0.600", "invalid"},
+			Backoff:     time.Millisecond,
+			MaxAttempts: 3,
+			Secret:      notifierSecret,
+			Timeout:     time.Second,
+			Webhooks:    []string{suite.webhook.URL},
+		},
+		&rTypes.NetworkIdentifier{Blockchain: types.Blockchain, Network: "testnet"},
+		nil,
+	)
+}
+
+func notifierTransaction(entityId domain.EntityId, hash string) *types.Transaction {
+	transaction := makeTransaction(nil, hash)
+	transaction.Operations[0].AccountId = types.NewAccountIdFromEntityId(entityId)
+	return transaction
+}
@@ -0,0 +1,57 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package services
+
+import (
+	"fmt"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+)
+
+// schemaRequirement is the minimum schema version a feature requires
+type schemaRequirement struct {
+	enabled func(rosettaConfig *config.Config) bool
+	feature string
+	version types.SchemaVersion
+}
+
+var schemaRequirements = []schemaRequirement{
+	{
+		// the transfer tables have the is_approval column since 1.54.3
+		enabled: func(rosettaConfig *config.Config) bool { return rosettaConfig.Online },
+		feature: "online data API",
+		version: types.MustParseSchemaVersion("1.54.3"),
+	},
+}
+
+// CheckSchemaVersion returns an error if the current schema version is older than the minimum version required by any
+// of the enabled features
+func CheckSchemaVersion(current types.SchemaVersion, rosettaConfig *config.Config) error {
+	for _, requirement := range schemaRequirements {
+		if requirement.enabled(rosettaConfig) && current.Compare(requirement.version) < 0 {
+			return fmt.Errorf("schema version %s is older than the minimum version %s required by the %s", current,
+				requirement.version, requirement.feature)
+		}
+	}
+
+	return nil
+}
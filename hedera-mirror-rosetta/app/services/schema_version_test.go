@@ -0,0 +1,60 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package services
+
+import (
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckSchemaVersion(t *testing.T) {
+	var tests = []struct {
+		name        string
+		online      bool
+		version     string
+		expectError bool
+	}{
+		{name: "online minimum", online: true, version: "1.54.3"},
+		{name: "online newer", online: true, version: "1.65.4"},
+		{name: "online older", online: true, version: "1.54.2", expectError: true},
+		{name: "offline older", version: "1.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			rosettaConfig := &config.Config{Online: tt.online}
+
+			// when
+			err := CheckSchemaVersion(types.MustParseSchemaVersion(tt.version), rosettaConfig)
+
+			// then
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package services
+
+import (
+	"context"
+	"encoding/hex"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/server"
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/tools"
+)
+
+const (
+	defaultSearchLimit = 25
+	maxSearchLimit     = 100
+)
+
+// SearchAPIService implements the /search/transactions endpoint. The transactions are listed in chronological order
+// and paginated with the opaque cursor instead of the offset
+type SearchAPIService struct {
+	BaseService
+	accountRepo     interfaces.AccountRepository
+	blockAPIService server.BlockAPIServicer
+	cursorTtl       time.Duration
+	systemShard     int64
+	systemRealm     int64
+}
+
+// NewSearchAPIService creates a new instance of a SearchAPIService. The transactions are fetched with the block api
+// service so they are built and cached the same way as for /block/transaction
+func NewSearchAPIService(
+	baseService BaseService,
+	accountRepo interfaces.AccountRepository,
+	blockAPIService server.BlockAPIServicer,
+	cursorTtl time.Duration,
+	systemShard int64,
+	systemRealm int64,
+) *SearchAPIService {
+	return &SearchAPIService{
+		BaseService:     baseService,
+		accountRepo:     accountRepo,
+		blockAPIService: blockAPIService,
+		cursorTtl:       cursorTtl,
+		systemShard:     systemShard,
+		systemRealm:     systemRealm,
+	}
+}
+
+// SearchTransactions implements the /search/transactions endpoint. The transactions can be filtered by the account
+// transferring hbar, fungible tokens, or nfts, and by the transaction hash, up to max_block or the latest block
+func (s *SearchAPIService) SearchTransactions(ctx context.Context, request *types.SearchTransactionsRequest) (
+	*types.SearchTransactionsResponse,
+	*rTypes.Error,
+) {
+	if !s.IsOnline() {
+		return nil, errors.ErrEndpointNotSupportedInOfflineMode
+	}
+
+	if rErr := validateSearchTransactionsRequest(request); rErr != nil {
+		return nil, rErr
+	}
+
+	search := types.TransactionSearch{Limit: defaultSearchLimit}
+	if request.Limit != nil {
+		search.Limit = int(*request.Limit)
+	}
+
+	var cursor *types.Cursor
+	if request.Cursor != nil {
+		var err error
+		if cursor, err = types.NewCursorFromString(*request.Cursor, s.cursorTtl); err != nil {
+			return nil, errors.AddErrorDetails(errors.ErrInvalidArgument, "reason", err.Error())
+		}
+		search.After = cursor.Position
+	}
+
+	if request.TransactionIdentifier != nil {
+		hash, err := hex.DecodeString(tools.SafeRemoveHexPrefix(request.TransactionIdentifier.Hash))
+		if err != nil {
+			return nil, errors.ErrInvalidTransactionIdentifier
+		}
+		search.Hash = hash
+	}
+
+	address := request.Address
+	if request.AccountIdentifier != nil {
+		address = &request.AccountIdentifier.Address
+	}
+	if address != nil {
+		accountId, rErr := s.getAccountId(ctx, *address)
+		if rErr != nil {
+			return nil, rErr
+		}
+		search.AccountId = accountId
+	}
+
+	var maxBlock *types.Block
+	var rErr *rTypes.Error
+	if request.MaxBlock != nil {
+		maxBlock, rErr = s.RetrieveBlock(ctx, &rTypes.PartialBlockIdentifier{Index: request.MaxBlock})
+	} else {
+		maxBlock, rErr = s.RetrieveLatest(ctx)
+	}
+	if rErr != nil {
+		return nil, rErr
+	}
+	search.End = maxBlock.ConsensusEndNanos
+
+	keys, totalCount, rErr := s.FindKeysBySearch(ctx, search)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	transactions, rErr := s.getBlockTransactions(ctx, keys, cursor)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	response := &types.SearchTransactionsResponse{
+		SearchTransactionsResponse: rTypes.SearchTransactionsResponse{
+			Transactions: transactions,
+			TotalCount:   totalCount,
+		},
+	}
+	if len(keys) == search.Limit {
+		last := keys[len(keys)-1]
+		nextCursor := types.NewCursor(last.ConsensusTimestamp, last.Hash).Encode()
+		response.NextCursor = &nextCursor
+	}
+
+	return response, nil
+}
+
+// getAccountId returns the encoded id of the account, resolving the alias to the account id
+func (s *SearchAPIService) getAccountId(ctx context.Context, address string) (int64, *rTypes.Error) {
+	accountId, err := types.NewAccountIdFromString(address, s.systemShard, s.systemRealm)
+	if err != nil {
+		return 0, errors.ErrInvalidAccount
+	}
+
+	if accountId.HasAlias() {
+		var rErr *rTypes.Error
+		if accountId, rErr = s.accountRepo.GetAccountId(ctx, accountId); rErr != nil {
+			return 0, rErr
+		}
+	}
+
+	return accountId.GetId(), nil
+}
+
+// getBlockTransactions fetches the transaction of each key with the identifier of its block. A transaction spanning
+// several consensus timestamps is fetched once, including when it continues from the transaction of the cursor
+func (s *SearchAPIService) getBlockTransactions(
+	ctx context.Context,
+	keys []types.TransactionKey,
+	cursor *types.Cursor,
+) ([]*rTypes.BlockTransaction, *rTypes.Error) {
+	seen := make(map[string]bool)
+	if cursor != nil && cursor.Hash != "" {
+		seen[cursor.Hash] = true
+	}
+
+	var block *types.Block
+	transactions := make([]*rTypes.BlockTransaction, 0, len(keys))
+	for _, key := range keys {
+		if seen[key.Hash] {
+			continue
+		}
+		seen[key.Hash] = true
+
+		if block == nil || key.ConsensusTimestamp > block.ConsensusEndNanos {
+			var rErr *rTypes.Error
+			if block, rErr = s.FindByTimestamp(ctx, key.ConsensusTimestamp); rErr != nil {
+				return nil, rErr
+			}
+		}
+
+		blockIdentifier := block.GetRosettaBlockIdentifier()
+		response, rErr := s.blockAPIService.BlockTransaction(ctx, &rTypes.BlockTransactionRequest{
+			BlockIdentifier:       blockIdentifier,
+			TransactionIdentifier: &rTypes.TransactionIdentifier{Hash: key.Hash},
+		})
+		if rErr != nil {
+			return nil, rErr
+		}
+
+		transactions = append(transactions, &rTypes.BlockTransaction{
+			BlockIdentifier: blockIdentifier,
+			Transaction:     response.Transaction,
+		})
+	}
+
+	return transactions, nil
+}
+
+// validateSearchTransactionsRequest rejects the filters which aren't supported
+func validateSearchTransactionsRequest(request *types.SearchTransactionsRequest) *rTypes.Error {
+	reason := ""
+	switch {
+	case request.Offset != nil:
+		reason = "offset is not supported, use the cursor"
+	case request.Operator != nil && *request.Operator == rTypes.OR:
+		reason = "operator or is not supported"
+	case request.CoinIdentifier != nil, request.Currency != nil, request.Status != nil, request.Type != nil,
+		request.Success != nil:
+		reason = "only the account, address, and transaction identifier filters are supported"
+	case request.AccountIdentifier != nil && request.Address != nil &&
+		request.AccountIdentifier.Address != *request.Address:
+		reason = "account identifier and address must match"
+	case request.Limit != nil && (*request.Limit < 1 || *request.Limit > maxSearchLimit):
+		reason = "limit must be between 1 and 100"
+	default:
+		return nil
+	}
+
+	return errors.AddErrorDetails(errors.ErrInvalidArgument, "reason", reason)
+}
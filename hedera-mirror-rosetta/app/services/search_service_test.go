@@ -0,0 +1,318 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package services
+
+import (
+	"context"
+	"testing"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	searchHash1 = "0x0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f202122232425262728292a2b2c2d2e2f30"
+	searchHash2 = "0x1102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f202122232425262728292a2b2c2d2e2f30"
+)
+
+// searchBlockAPIService records the /block/transaction requests and returns a transaction with the requested hash
+type searchBlockAPIService struct {
+	err      *rTypes.Error
+	requests []*rTypes.BlockTransactionRequest
+}
+
+func (s *searchBlockAPIService) Block(context.Context, *rTypes.BlockRequest) (*rTypes.BlockResponse, *rTypes.Error) {
+	return nil, errors.ErrNotImplemented
+}
+
+func (s *searchBlockAPIService) BlockTransaction(_ context.Context, request *rTypes.BlockTransactionRequest) (
+	*rTypes.BlockTransactionResponse,
+	*rTypes.Error,
+) {
+	s.requests = append(s.requests, request)
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &rTypes.BlockTransactionResponse{
+		Transaction: &rTypes.Transaction{TransactionIdentifier: request.TransactionIdentifier},
+	}, nil
+}
+
+func TestSearchServiceSuite(t *testing.T) {
+	suite.Run(t, new(searchServiceSuite))
+}
+
+type searchServiceSuite struct {
+	suite.Suite
+	blockAPIService     *searchBlockAPIService
+	mockAccountRepo     *mocks.MockAccountRepository
+	mockBlockRepo       *mocks.MockBlockRepository
+	mockTransactionRepo *mocks.MockTransactionRepository
+	searchService       *SearchAPIService
+}
+
+func (suite *searchServiceSuite) SetupTest() {
+	suite.blockAPIService = &searchBlockAPIService{}
+	suite.mockAccountRepo = &mocks.MockAccountRepository{}
+	suite.mockBlockRepo = &mocks.MockBlockRepository{}
+	suite.mockTransactionRepo = &mocks.MockTransactionRepository{}
+	suite.searchService = NewSearchAPIService(
+		NewOnlineBaseService(suite.mockBlockRepo, suite.mockTransactionRepo),
+		suite.mockAccountRepo,
+		suite.blockAPIService,
+		cursorTtl,
+		0,
+		0,
+	)
+}
+
+func (suite *searchServiceSuite) TestSearchTransactions() {
+	// given
+	keys := []types.TransactionKey{
+		{ConsensusTimestamp: 1000001, Hash: searchHash1},
+		{ConsensusTimestamp: 1000002, Hash: searchHash1},
+		{ConsensusTimestamp: 1000003, Hash: searchHash2},
+	}
+	suite.mockBlockRepo.On("RetrieveLatest").Return(block(), mocks.NilError)
+	suite.mockBlockRepo.On("FindByTimestamp", int64(1000001)).Return(block(), mocks.NilError)
+	suite.mockTransactionRepo.On("FindKeysBySearch").Return(keys, int64(5), mocks.NilError)
+	request := searchTransactionsRequest()
+	request.AccountIdentifier = &rTypes.AccountIdentifier{Address: "0.0.100"}
+	request.Limit = int64Ptr(3)
+	blockIdentifier := block().GetRosettaBlockIdentifier()
+
+	// when
+	actual, err := suite.searchService.SearchTransactions(defaultContext, request)
+
+	// then
+	assert.Nil(suite.T(), err)
+	require.NotNil(suite.T(), actual)
+	assert.Equal(suite.T(), int64(5), actual.TotalCount)
+	assert.Equal(suite.T(), []*rTypes.BlockTransaction{
+		{
+			BlockIdentifier: blockIdentifier,
+			Transaction:     &rTypes.Transaction{TransactionIdentifier: &rTypes.TransactionIdentifier{Hash: searchHash1}},
+		},
+		{
+			BlockIdentifier: blockIdentifier,
+			Transaction:     &rTypes.Transaction{TransactionIdentifier: &rTypes.TransactionIdentifier{Hash: searchHash2}},
+		},
+	}, actual.Transactions)
+	require.NotNil(suite.T(), actual.NextCursor)
+	cursor, cursorErr := types.NewCursorFromString(*actual.NextCursor, cursorTtl)
+	require.NoError(suite.T(), cursorErr)
+	assert.Equal(suite.T(), int64(1000003), cursor.Position)
+	assert.Equal(suite.T(), searchHash2, cursor.Hash)
+	suite.mockBlockRepo.AssertNumberOfCalls(suite.T(), "FindByTimestamp", 1)
+}
+
+func (suite *searchServiceSuite) TestSearchTransactionsWithCursorAndMaxBlock() {
+	// given
+	keys := []types.TransactionKey{
+		{ConsensusTimestamp: 1000002, Hash: searchHash1},
+		{ConsensusTimestamp: 1000003, Hash: searchHash2},
+	}
+	suite.mockBlockRepo.On("FindByIndex").Return(block(), mocks.NilError)
+	suite.mockBlockRepo.On("FindByTimestamp", int64(1000003)).Return(block(), mocks.NilError)
+	suite.mockTransactionRepo.On("FindKeysBySearch").Return(keys, int64(3), mocks.NilError)
+	request := searchTransactionsRequest()
+	cursor := types.NewCursor(1000001, searchHash1).Encode()
+	request.Cursor = &cursor
+	request.MaxBlock = int64Ptr(1)
+	request.TransactionIdentifier = &rTypes.TransactionIdentifier{Hash: searchHash2}
+
+	// when
+	actual, err := suite.searchService.SearchTransactions(defaultContext, request)
+
+	// then
+	assert.Nil(suite.T(), err)
+	require.NotNil(suite.T(), actual)
+	require.Len(suite.T(), actual.Transactions, 1)
+	assert.Equal(suite.T(), searchHash2, actual.Transactions[0].Transaction.TransactionIdentifier.Hash)
+	assert.Nil(suite.T(), actual.NextCursor)
+	suite.mockBlockRepo.AssertNotCalled(suite.T(), "RetrieveLatest")
+}
+
+func (suite *searchServiceSuite) TestSearchTransactionsByAlias() {
+	// given
+	alias := "0x1220d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a"
+	accountId, _ := types.NewAccountIdFromString(alias, 0, 0)
+	resolved, _ := types.NewAccountIdFromString("0.0.100", 0, 0)
+	suite.mockAccountRepo.On("GetAccountId", mock.Anything, accountId).Return(resolved, mocks.NilError)
+	suite.mockBlockRepo.On("RetrieveLatest").Return(block(), mocks.NilError)
+	suite.mockTransactionRepo.On("FindKeysBySearch").Return([]types.TransactionKey{}, int64(0), mocks.NilError)
+	request := searchTransactionsRequest()
+	request.Address = &alias
+
+	// when
+	actual, err := suite.searchService.SearchTransactions(defaultContext, request)
+
+	// then
+	assert.Nil(suite.T(), err)
+	assert.Empty(suite.T(), actual.Transactions)
+	suite.mockAccountRepo.AssertExpectations(suite.T())
+}
+
+func (suite *searchServiceSuite) TestSearchTransactionsInvalidRequest() {
+	invalidCursor := "abc"
+	otherAddress := "0.0.200"
+	tests := []struct {
+		name     string
+		update   func(request *types.SearchTransactionsRequest)
+		expected *rTypes.Error
+	}{
+		{
+			name:     "offset",
+			update:   func(request *types.SearchTransactionsRequest) { request.Offset = int64Ptr(1) },
+			expected: errors.ErrInvalidArgument,
+		},
+		{
+			name: "or operator",
+			update: func(request *types.SearchTransactionsRequest) {
+				operator := rTypes.OR
+				request.Operator = &operator
+			},
+			expected: errors.ErrInvalidArgument,
+		},
+		{
+			name: "status",
+			update: func(request *types.SearchTransactionsRequest) {
+				status := "SUCCESS"
+				request.Status = &status
+			},
+			expected: errors.ErrInvalidArgument,
+		},
+		{
+			name:     "zero limit",
+			update:   func(request *types.SearchTransactionsRequest) { request.Limit = int64Ptr(0) },
+			expected: errors.ErrInvalidArgument,
+		},
+		{
+			name:     "limit too large",
+			update:   func(request *types.SearchTransactionsRequest) { request.Limit = int64Ptr(101) },
+			expected: errors.ErrInvalidArgument,
+		},
+		{
+			name: "account identifier and address mismatch",
+			update: func(request *types.SearchTransactionsRequest) {
+				request.AccountIdentifier = &rTypes.AccountIdentifier{Address: "0.0.100"}
+				request.Address = &otherAddress
+			},
+			expected: errors.ErrInvalidArgument,
+		},
+		{
+			name:     "invalid cursor",
+			update:   func(request *types.SearchTransactionsRequest) { request.Cursor = &invalidCursor },
+			expected: errors.ErrInvalidArgument,
+		},
+		{
+			name: "invalid transaction identifier",
+			update: func(request *types.SearchTransactionsRequest) {
+				request.TransactionIdentifier = &rTypes.TransactionIdentifier{Hash: "0xzz"}
+			},
+			expected: errors.ErrInvalidTransactionIdentifier,
+		},
+		{
+			name: "invalid account",
+			update: func(request *types.SearchTransactionsRequest) {
+				request.AccountIdentifier = &rTypes.AccountIdentifier{Address: "abc"}
+			},
+			expected: errors.ErrInvalidAccount,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			// given
+			request := searchTransactionsRequest()
+			tt.update(request)
+
+			// when
+			actual, err := suite.searchService.SearchTransactions(defaultContext, request)
+
+			// then
+			require.NotNil(t, err)
+			assert.Equal(t, tt.expected.Code, err.Code)
+			assert.Nil(t, actual)
+		})
+	}
+	suite.mockTransactionRepo.AssertNotCalled(suite.T(), "FindKeysBySearch")
+}
+
+func (suite *searchServiceSuite) TestSearchTransactionsFindKeysBySearchFails() {
+	// given
+	suite.mockBlockRepo.On("RetrieveLatest").Return(block(), mocks.NilError)
+	suite.mockTransactionRepo.On("FindKeysBySearch").Return([]types.TransactionKey(nil), int64(0), errors.ErrDatabaseError)
+
+	// when
+	actual, err := suite.searchService.SearchTransactions(defaultContext, searchTransactionsRequest())
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *searchServiceSuite) TestSearchTransactionsBlockTransactionFails() {
+	// given
+	keys := []types.TransactionKey{{ConsensusTimestamp: 1000001, Hash: searchHash1}}
+	suite.blockAPIService.err = errors.ErrTransactionNotFound
+	suite.mockBlockRepo.On("RetrieveLatest").Return(block(), mocks.NilError)
+	suite.mockBlockRepo.On("FindByTimestamp", int64(1000001)).Return(block(), mocks.NilError)
+	suite.mockTransactionRepo.On("FindKeysBySearch").Return(keys, int64(1), mocks.NilError)
+
+	// when
+	actual, err := suite.searchService.SearchTransactions(defaultContext, searchTransactionsRequest())
+
+	// then
+	assert.Equal(suite.T(), errors.ErrTransactionNotFound, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func (suite *searchServiceSuite) TestSearchTransactionsOffline() {
+	// given
+	searchService := NewSearchAPIService(NewOfflineBaseService(), nil, nil, cursorTtl, 0, 0)
+
+	// when
+	actual, err := searchService.SearchTransactions(defaultContext, searchTransactionsRequest())
+
+	// then
+	assert.Equal(suite.T(), errors.ErrEndpointNotSupportedInOfflineMode, err)
+	assert.Nil(suite.T(), actual)
+}
+
+func searchTransactionsRequest() *types.SearchTransactionsRequest {
+	return &types.SearchTransactionsRequest{
+		SearchTransactionsRequest: rTypes.SearchTransactionsRequest{
+			NetworkIdentifier: &rTypes.NetworkIdentifier{Blockchain: types.Blockchain, Network: "testnet"},
+		},
+	}
+}
+
+func int64Ptr(value int64) *int64 {
+	return &value
+}
@@ -0,0 +1,207 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	SubmissionStatusExpired = "EXPIRED"
+	SubmissionStatusFailed  = "FAILED"
+	SubmissionStatusPending = "PENDING"
+	SubmissionStatusSuccess = "SUCCESS"
+)
+
+// Submission is the status of a tracked transaction. ConsensusTimestamp and Result are only set once the transaction
+// appears in a block
+type Submission struct {
+	ConsensusTimestamp int64
+	Hash               string
+	Result             string
+	Status             string
+	ValidEndNanos      int64
+	ValidStartNanos    int64
+	finalizedAt        time.Time
+}
+
+// IsFinal returns true if the status of the submission won't change
+func (s Submission) IsFinal() bool {
+	return s.Status != SubmissionStatusPending
+}
+
+// SubmissionWatcher tracks the hashes of the transactions submitted via /construction/submit until each appears in a
+// block or its valid duration expires. A transaction not found after the latest block is past the window in which it
+// can still reach consensus is expired. The final status is kept for the retention period
+type SubmissionWatcher struct {
+	BaseService
+	config      config.SubmissionWatcher
+	mutex       sync.RWMutex
+	submissions map[string]*Submission
+}
+
+// NewSubmissionWatcher creates a SubmissionWatcher
+func NewSubmissionWatcher(baseService BaseService, watcherConfig config.SubmissionWatcher) *SubmissionWatcher {
+	return &SubmissionWatcher{
+		BaseService: baseService,
+		config:      watcherConfig,
+		submissions: make(map[string]*Submission),
+	}
+}
+
+// Track starts tracking the transaction with the hash. The transaction is not tracked if the max number of tracked
+// transactions is reached
+func (w *SubmissionWatcher) Track(hash string, validStartNanos, validEndNanos int64) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if _, ok := w.submissions[hash]; ok {
+		return
+	}
+
+	if len(w.submissions) >= w.config.MaxSize {
+		log.Warnf("Not tracking transaction %s since the max number of %d tracked transactions is reached", hash,
+			w.config.MaxSize)
+		return
+	}
+
+	w.submissions[hash] = &Submission{
+		Hash:            hash,
+		Status:          SubmissionStatusPending,
+		ValidEndNanos:   validEndNanos,
+		ValidStartNanos: validStartNanos,
+	}
+}
+
+// Get returns the tracked transaction with the hash, false if it's not tracked
+func (w *SubmissionWatcher) Get(hash string) (Submission, bool) {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	submission, ok := w.submissions[hash]
+	if !ok {
+		return Submission{}, false
+	}
+	return *submission, true
+}
+
+// Run polls the status of the pending transactions every poll interval until the context is done
+func (w *SubmissionWatcher) Run(ctx context.Context) {
+	log.Infof("Tracking up to %d submitted transactions", w.config.MaxSize)
+	ticker := time.NewTicker(w.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.poll(ctx); err != nil {
+			log.Warnf("Failed to poll the status of the submitted transactions: %s", err.Message)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll looks up each pending transaction in its valid start window and evicts the final ones past the retention. A
+// transaction failed to look up is retried in the next poll
+func (w *SubmissionWatcher) poll(ctx context.Context) *rTypes.Error {
+	w.evict()
+
+	pending := w.getPending()
+	if len(pending) == 0 {
+		return nil
+	}
+
+	latest, err := w.RetrieveLatest(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, submission := range pending {
+		consensusEndNanos := submission.ValidEndNanos + maxConsensusDelay
+		raw, err := w.FindRawByHashInBlock(ctx, submission.Hash, submission.ValidStartNanos, consensusEndNanos)
+		if err == nil {
+			status := SubmissionStatusFailed
+			if persistence.IsTransactionResultSuccessful(int32(raw.Result)) {
+				status = SubmissionStatusSuccess
+			}
+			w.finalize(submission.Hash, status, raw.ConsensusTimestamp, types.TransactionResults[int32(raw.Result)])
+		} else if err != errors.ErrTransactionNotFound {
+			return err
+		} else if latest.ConsensusEndNanos > consensusEndNanos {
+			w.finalize(submission.Hash, SubmissionStatusExpired, 0, "")
+		}
+	}
+
+	return nil
+}
+
+func (w *SubmissionWatcher) evict() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	now := time.Now()
+	for hash, submission := range w.submissions {
+		if submission.IsFinal() && now.Sub(submission.finalizedAt) >= w.config.Retention {
+			delete(w.submissions, hash)
+		}
+	}
+}
+
+func (w *SubmissionWatcher) finalize(hash, status string, consensusTimestamp int64, result string) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	submission, ok := w.submissions[hash]
+	if !ok {
+		return
+	}
+
+	submission.ConsensusTimestamp = consensusTimestamp
+	submission.finalizedAt = time.Now()
+	submission.Result = result
+	submission.Status = status
+	log.Infof("Submitted transaction %s is %s", hash, status)
+}
+
+func (w *SubmissionWatcher) getPending() []Submission {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	pending := make([]Submission, 0)
+	for _, submission := range w.submissions {
+		if !submission.IsFinal() {
+			pending = append(pending, *submission)
+		}
+	}
+	return pending
+}
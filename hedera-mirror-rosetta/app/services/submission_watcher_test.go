@@ -0,0 +1,209 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	submissionHash       = "0x1234"
+	submissionValidEnd   = int64(2000)
+	submissionValidStart = int64(1000)
+)
+
+func TestSubmissionWatcherSuite(t *testing.T) {
+	suite.Run(t, new(submissionWatcherSuite))
+}
+
+type submissionWatcherSuite struct {
+	suite.Suite
+	mockBlockRepo       *mocks.MockBlockRepository
+	mockTransactionRepo *mocks.MockTransactionRepository
+	watcher             *SubmissionWatcher
+}
+
+func (suite *submissionWatcherSuite) SetupTest() {
+	suite.mockBlockRepo = &mocks.MockBlockRepository{}
+	suite.mockTransactionRepo = &mocks.MockTransactionRepository{}
+	suite.watcher = NewSubmissionWatcher(
+		NewOnlineBaseService(suite.mockBlockRepo, suite.mockTransactionRepo),
+		config.SubmissionWatcher{MaxSize: 2, Retention: time.Hour},
+	)
+}
+
+func (suite *submissionWatcherSuite) TestTrack() {
+	// when
+	suite.watcher.Track(submissionHash, submissionValidStart, submissionValidEnd)
+
+	// then
+	actual, ok := suite.watcher.Get(submissionHash)
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), Submission{
+		Hash:            submissionHash,
+		Status:          SubmissionStatusPending,
+		ValidEndNanos:   submissionValidEnd,
+		ValidStartNanos: submissionValidStart,
+	}, actual)
+	assert.False(suite.T(), actual.IsFinal())
+}
+
+func (suite *submissionWatcherSuite) TestTrackMaxSize() {
+	// when
+	suite.watcher.Track("0x01", submissionValidStart, submissionValidEnd)
+	suite.watcher.Track("0x01", submissionValidStart, submissionValidEnd)
+	suite.watcher.Track("0x02", submissionValidStart, submissionValidEnd)
+	suite.watcher.Track("0x03", submissionValidStart, submissionValidEnd)
+
+	// then
+	assert.Len(suite.T(), suite.watcher.submissions, 2)
+	_, ok := suite.watcher.Get("0x03")
+	assert.False(suite.T(), ok)
+}
+
+func (suite *submissionWatcherSuite) TestGetNotTracked() {
+	// when
+	_, ok := suite.watcher.Get(submissionHash)
+
+	// then
+	assert.False(suite.T(), ok)
+}
+
+func (suite *submissionWatcherSuite) TestPoll() {
+	tests := []struct {
+		name     string
+		result   int16
+		expected string
+	}{
+		{name: "success", result: 22, expected: SubmissionStatusSuccess},
+		{name: "failed", result: 11, expected: SubmissionStatusFailed},
+	}
+
+	for _, tt := range tests {
+		suite.T().Run(tt.name, func(t *testing.T) {
+			// given
+			suite.SetupTest()
+			rawTransaction := &types.RawTransaction{
+				Transaction: domain.Transaction{ConsensusTimestamp: 1500, Result: tt.result},
+			}
+			suite.mockBlockRepo.On("RetrieveLatest").Return(block(), mocks.NilError)
+			suite.mockTransactionRepo.On("FindRawByHashInBlock").Return(rawTransaction, mocks.NilError)
+			suite.watcher.Track(submissionHash, submissionValidStart, submissionValidEnd)
+
+			// when
+			err := suite.watcher.poll(defaultContext)
+
+			// then
+			assert.Nil(t, err)
+			actual, _ := suite.watcher.Get(submissionHash)
+			assert.Equal(t, tt.expected, actual.Status)
+			assert.Equal(t, int64(1500), actual.ConsensusTimestamp)
+			assert.Equal(t, types.TransactionResults[int32(tt.result)], actual.Result)
+			assert.True(t, actual.IsFinal())
+		})
+	}
+}
+
+func (suite *submissionWatcherSuite) TestPollPending() {
+	// given
+	suite.mockBlockRepo.On("RetrieveLatest").Return(block(), mocks.NilError)
+	suite.mockTransactionRepo.On("FindRawByHashInBlock").Return(mocks.NilRawTransaction, errors.ErrTransactionNotFound)
+	suite.watcher.Track(submissionHash, submissionValidStart, submissionValidEnd)
+
+	// when
+	err := suite.watcher.poll(defaultContext)
+
+	// then
+	assert.Nil(suite.T(), err)
+	actual, _ := suite.watcher.Get(submissionHash)
+	assert.Equal(suite.T(), SubmissionStatusPending, actual.Status)
+}
+
+func (suite *submissionWatcherSuite) TestPollExpired() {
+	// given
+	latest := block()
+	latest.ConsensusEndNanos = submissionValidEnd + maxConsensusDelay + 1
+	suite.mockBlockRepo.On("RetrieveLatest").Return(latest, mocks.NilError)
+	suite.mockTransactionRepo.On("FindRawByHashInBlock").Return(mocks.NilRawTransaction, errors.ErrTransactionNotFound)
+	suite.watcher.Track(submissionHash, submissionValidStart, submissionValidEnd)
+
+	// when
+	err := suite.watcher.poll(defaultContext)
+
+	// then
+	assert.Nil(suite.T(), err)
+	actual, _ := suite.watcher.Get(submissionHash)
+	assert.Equal(suite.T(), SubmissionStatusExpired, actual.Status)
+	assert.Empty(suite.T(), actual.Result)
+}
+
+func (suite *submissionWatcherSuite) TestPollNothingPending() {
+	// when
+	err := suite.watcher.poll(defaultContext)
+
+	// then
+	assert.Nil(suite.T(), err)
+	suite.mockBlockRepo.AssertNotCalled(suite.T(), "RetrieveLatest")
+}
+
+func (suite *submissionWatcherSuite) TestPollDbError() {
+	// given
+	suite.mockBlockRepo.On("RetrieveLatest").Return(block(), mocks.NilError)
+	suite.mockTransactionRepo.On("FindRawByHashInBlock").Return(mocks.NilRawTransaction, errors.ErrDatabaseError)
+	suite.watcher.Track(submissionHash, submissionValidStart, submissionValidEnd)
+
+	// when
+	err := suite.watcher.poll(defaultContext)
+
+	// then
+	assert.Equal(suite.T(), errors.ErrDatabaseError, err)
+	actual, _ := suite.watcher.Get(submissionHash)
+	assert.Equal(suite.T(), SubmissionStatusPending, actual.Status)
+}
+
+func (suite *submissionWatcherSuite) TestPollEvict() {
+	// given
+	suite.watcher.config.Retention = 0
+	suite.watcher.Track(submissionHash, submissionValidStart, submissionValidEnd)
+	suite.watcher.Track("0x5678", submissionValidStart, submissionValidEnd)
+	suite.watcher.finalize(submissionHash, SubmissionStatusExpired, 0, "")
+	suite.mockBlockRepo.On("RetrieveLatest").Return(block(), mocks.NilError)
+	suite.mockTransactionRepo.On("FindRawByHashInBlock").Return(mocks.NilRawTransaction, errors.ErrTransactionNotFound)
+
+	// when
+	err := suite.watcher.poll(defaultContext)
+
+	// then
+	assert.Nil(suite.T(), err)
+	_, ok := suite.watcher.Get(submissionHash)
+	assert.False(suite.T(), ok)
+	_, ok = suite.watcher.Get("0x5678")
+	assert.True(suite.T(), ok)
+}
@@ -0,0 +1,100 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package services
+
+import (
+	"context"
+	"sync"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	log "github.com/sirupsen/logrus"
+)
+
+// systemFileCache caches the value parsed from the latest content of a system file. The content is only read and
+// parsed again once a later transaction changes the file, so the cost of a lookup is a single timestamp query
+type systemFileCache[T any] struct {
+	fileDataRepo interfaces.FileDataRepository
+	fileId       int64
+	lock         sync.Mutex
+	name         string
+	parse        func([]byte) (T, error)
+	timestamp    int64
+	value        T
+	valid        bool
+}
+
+// get returns the value parsed from the latest content of the file, false if the file doesn't exist, is empty, or
+// fails to parse
+func (c *systemFileCache[T]) get(ctx context.Context) (T, bool, *rTypes.Error) {
+	var empty T
+	timestamp, rErr := c.fileDataRepo.GetLatestTimestamp(ctx, c.fileId)
+	if rErr != nil {
+		return empty, false, rErr
+	}
+
+	if timestamp == 0 {
+		return empty, false, nil
+	}
+
+	c.lock.Lock()
+	if c.timestamp == timestamp {
+		defer c.lock.Unlock()
+		return c.value, c.valid, nil
+	}
+	c.lock.Unlock()
+
+	data, rErr := c.fileDataRepo.GetLatestContent(ctx, c.fileId)
+	if rErr != nil {
+		return empty, false, rErr
+	}
+
+	value := empty
+	valid := false
+	if len(data) != 0 {
+		var err error
+		if value, err = c.parse(data); err != nil {
+			log.Warnf("Failed to parse %s: %s", c.name, err)
+			value = empty
+		} else {
+			valid = true
+		}
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	// a concurrent lookup may have already cached a later version of the file
+	if timestamp >= c.timestamp {
+		c.timestamp = timestamp
+		c.value = value
+		c.valid = valid
+	}
+
+	return value, valid, nil
+}
+
+func newSystemFileCache[T any](
+	fileDataRepo interfaces.FileDataRepository,
+	fileId int64,
+	name string,
+	parse func([]byte) (T, error),
+) *systemFileCache[T] {
+	return &systemFileCache[T]{fileDataRepo: fileDataRepo, fileId: fileId, name: name, parse: parse}
+}
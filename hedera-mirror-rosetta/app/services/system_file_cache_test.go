@@ -0,0 +1,144 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package services
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/errors"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+const systemFileId int64 = 123
+
+func TestSystemFileCacheGet(t *testing.T) {
+	tests := []struct {
+		name      string
+		timestamp int64
+		content   []byte
+		expected  int
+		valid     bool
+	}{
+		{name: "valid", timestamp: 10, content: []byte("42"), expected: 42, valid: true},
+		{name: "empty", timestamp: 10, content: []byte{}},
+		{name: "invalid", timestamp: 10, content: []byte("x")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			mockFileDataRepo := &mocks.MockFileDataRepository{}
+			mockFileDataRepo.On("GetLatestTimestamp").Return(tt.timestamp, mocks.NilError)
+			mockFileDataRepo.On("GetLatestContent").Return(tt.content, mocks.NilError)
+			cache := newTestSystemFileCache(mockFileDataRepo)
+
+			// when
+			actual, valid, err := cache.get(defaultContext)
+			cached, cachedValid, cachedErr := cache.get(defaultContext)
+
+			// then
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, actual)
+			assert.Equal(t, tt.valid, valid)
+			assert.Nil(t, cachedErr)
+			assert.Equal(t, tt.expected, cached)
+			assert.Equal(t, tt.valid, cachedValid)
+			mockFileDataRepo.AssertNumberOfCalls(t, "GetLatestTimestamp", 2)
+			mockFileDataRepo.AssertNumberOfCalls(t, "GetLatestContent", 1)
+		})
+	}
+}
+
+func TestSystemFileCacheGetFileChanged(t *testing.T) {
+	// given
+	mockFileDataRepo := &mocks.MockFileDataRepository{}
+	mockFileDataRepo.On("GetLatestTimestamp").Return(int64(10), mocks.NilError).Once()
+	mockFileDataRepo.On("GetLatestContent").Return([]byte("1"), mocks.NilError).Once()
+	mockFileDataRepo.On("GetLatestTimestamp").Return(int64(11), mocks.NilError).Once()
+	mockFileDataRepo.On("GetLatestContent").Return([]byte("2"), mocks.NilError).Once()
+	cache := newTestSystemFileCache(mockFileDataRepo)
+
+	// when
+	first, _, _ := cache.get(defaultContext)
+	second, valid, err := cache.get(defaultContext)
+
+	// then
+	assert.Nil(t, err)
+	assert.Equal(t, 1, first)
+	assert.Equal(t, 2, second)
+	assert.True(t, valid)
+	mockFileDataRepo.AssertExpectations(t)
+}
+
+func TestSystemFileCacheGetNoFile(t *testing.T) {
+	// given
+	mockFileDataRepo := &mocks.MockFileDataRepository{}
+	mockFileDataRepo.On("GetLatestTimestamp").Return(int64(0), mocks.NilError)
+	cache := newTestSystemFileCache(mockFileDataRepo)
+
+	// when
+	actual, valid, err := cache.get(defaultContext)
+
+	// then
+	assert.Nil(t, err)
+	assert.Zero(t, actual)
+	assert.False(t, valid)
+	mockFileDataRepo.AssertNotCalled(t, "GetLatestContent")
+}
+
+func TestSystemFileCacheGetDbError(t *testing.T) {
+	tests := []struct {
+		name         string
+		timestampErr bool
+	}{
+		{name: "timestamp", timestampErr: true},
+		{name: "content"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			mockFileDataRepo := &mocks.MockFileDataRepository{}
+			if tt.timestampErr {
+				mockFileDataRepo.On("GetLatestTimestamp").Return(int64(0), errors.ErrDatabaseError)
+			} else {
+				mockFileDataRepo.On("GetLatestTimestamp").Return(int64(10), mocks.NilError)
+				mockFileDataRepo.On("GetLatestContent").Return([]byte(nil), errors.ErrDatabaseError)
+			}
+			cache := newTestSystemFileCache(mockFileDataRepo)
+
+			// when
+			actual, valid, err := cache.get(defaultContext)
+
+			// then
+			assert.Equal(t, errors.ErrDatabaseError, err)
+			assert.Zero(t, actual)
+			assert.False(t, valid)
+		})
+	}
+}
+
+func newTestSystemFileCache(mockFileDataRepo *mocks.MockFileDataRepository) *systemFileCache[int] {
+	return newSystemFileCache(mockFileDataRepo, systemFileId, "test file", func(data []byte) (int, error) {
+		return strconv.Atoi(string(data))
+	})
+}
@@ -0,0 +1,131 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package signer
+
+import (
+	"context"
+
+	awsConfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmsTypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-sdk-go/v2"
+	"github.com/pkg/errors"
+)
+
+// awsKmsClient is the part of the AWS KMS client the signer uses
+type awsKmsClient interface {
+	GetPublicKey(ctx context.Context, params *kms.GetPublicKeyInput, optFns ...func(*kms.Options)) (
+		*kms.GetPublicKeyOutput,
+		error,
+	)
+	Sign(ctx context.Context, params *kms.SignInput, optFns ...func(*kms.Options)) (*kms.SignOutput, error)
+}
+
+// awsKmsKey is an ECC_SECG_P256K1 key in AWS KMS
+type awsKmsKey struct {
+	keyId     string
+	publicKey hedera.PublicKey
+}
+
+// awsKmsSigner signs with the ECDSA secp256k1 keys in AWS KMS, keyed by the account id in shard.realm.num format. The
+// credentials and the region are loaded from the default AWS configuration sources, e.g., the environment variables
+type awsKmsSigner struct {
+	client awsKmsClient
+	keys   map[string]awsKmsKey
+}
+
+func (a *awsKmsSigner) Sign(ctx context.Context, accountId types.AccountId, message []byte) (
+	hedera.PublicKey,
+	[]byte,
+	bool,
+	error,
+) {
+	key, ok := a.keys[accountId.String()]
+	if !ok {
+		return hedera.PublicKey{}, nil, false, nil
+	}
+
+	digest := ecdsaDigest(message)
+	output, err := a.client.Sign(ctx, &kms.SignInput{
+		KeyId:            &key.keyId,
+		Message:          digest,
+		MessageType:      kmsTypes.MessageTypeDigest,
+		SigningAlgorithm: kmsTypes.SigningAlgorithmSpecEcdsaSha256,
+	})
+	if err != nil {
+		return hedera.PublicKey{}, nil, false, errors.Wrapf(err, "Failed to sign with AWS KMS key %s", key.keyId)
+	}
+
+	signature, err := signatureFromDer(output.Signature)
+	if err != nil {
+		return hedera.PublicKey{}, nil, false, err
+	}
+
+	if err = verifyEcdsaSignature(key.publicKey, digest, signature); err != nil {
+		return hedera.PublicKey{}, nil, false, err
+	}
+
+	return key.publicKey, signature, true, nil
+}
+
+func newAwsKmsSigner(ctx context.Context, keys map[string]string) (*awsKmsSigner, error) {
+	cfg, err := awsConfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to load the AWS configuration")
+	}
+
+	return newAwsKmsSignerWithClient(ctx, kms.NewFromConfig(cfg), keys)
+}
+
+// newAwsKmsSignerWithClient creates the signer and gets the public key of each key, which also verifies the keys exist
+// and are supported
+func newAwsKmsSignerWithClient(ctx context.Context, client awsKmsClient, keys map[string]string) (
+	*awsKmsSigner,
+	error,
+) {
+	accountKeys, err := getAccountKeys(keys, TypeAws)
+	if err != nil {
+		return nil, err
+	}
+
+	kmsKeys := make(map[string]awsKmsKey, len(accountKeys))
+	for account, keyId := range accountKeys {
+		keyId := keyId
+		output, err := client.GetPublicKey(ctx, &kms.GetPublicKeyInput{KeyId: &keyId})
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to get the public key of AWS KMS key %s", keyId)
+		}
+
+		if output.KeySpec != kmsTypes.KeySpecEccSecgP256k1 || output.KeyUsage != kmsTypes.KeyUsageTypeSignVerify {
+			return nil, errors.Errorf("Unsupported AWS KMS key %s of spec %s and usage %s, only ECC_SECG_P256K1 "+
+				"signing key is supported", keyId, output.KeySpec, output.KeyUsage)
+		}
+
+		publicKey, err := publicKeyFromSubjectPublicKeyInfo(output.PublicKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid public key of AWS KMS key %s", keyId)
+		}
+
+		kmsKeys[account] = awsKmsKey{keyId: keyId, publicKey: publicKey}
+	}
+
+	return &awsKmsSigner{client: client, keys: kmsKeys}, nil
+}
@@ -0,0 +1,195 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmsTypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const awsKeyId = "arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+
+func TestAwsKmsSignerSign(t *testing.T) {
+	// given
+	privateKey, publicKey := newEcdsaKey(t)
+	client := &fakeAwsKmsClient{privateKey: privateKey, spkiDer: marshalSubjectPublicKeyInfo(t, privateKey)}
+	signer, err := newAwsKmsSignerWithClient(context.Background(), client, map[string]string{"0.0.1001": awsKeyId})
+	require.NoError(t, err)
+	message := []byte("message")
+
+	// when
+	actual, signature, ok, err := signer.Sign(context.Background(), newAccountId(1001), message)
+
+	// then
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, publicKey.String(), actual.String())
+	assert.NoError(t, verifyEcdsaSignature(actual, ecdsaDigest(message), signature))
+	assert.Equal(t, awsKeyId, client.signedKeyId)
+}
+
+func TestAwsKmsSignerSignUnknownAccount(t *testing.T) {
+	// given
+	privateKey, _ := newEcdsaKey(t)
+	client := &fakeAwsKmsClient{privateKey: privateKey, spkiDer: marshalSubjectPublicKeyInfo(t, privateKey)}
+	signer, err := newAwsKmsSignerWithClient(context.Background(), client, map[string]string{"0.0.1001": awsKeyId})
+	require.NoError(t, err)
+
+	// when
+	_, signature, ok, err := signer.Sign(context.Background(), newAccountId(1002), []byte("message"))
+
+	// then
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Nil(t, signature)
+	assert.Empty(t, client.signedKeyId)
+}
+
+func TestAwsKmsSignerSignError(t *testing.T) {
+	privateKey, _ := newEcdsaKey(t)
+	otherPrivateKey, _ := newEcdsaKey(t)
+	tests := []struct {
+		name   string
+		client *fakeAwsKmsClient
+	}{
+		{name: "sign error", client: &fakeAwsKmsClient{privateKey: privateKey, signErr: errors.New("error")}},
+		{name: "signed with another key", client: &fakeAwsKmsClient{privateKey: otherPrivateKey}},
+		{name: "invalid signature", client: &fakeAwsKmsClient{privateKey: privateKey, signature: []byte{0x1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			tt.client.spkiDer = marshalSubjectPublicKeyInfo(t, privateKey)
+			signer, err := newAwsKmsSignerWithClient(
+				context.Background(),
+				tt.client,
+				map[string]string{"0.0.1001": awsKeyId},
+			)
+			require.NoError(t, err)
+
+			// when
+			_, signature, ok, err := signer.Sign(context.Background(), newAccountId(1001), []byte("message"))
+
+			// then
+			assert.Error(t, err)
+			assert.False(t, ok)
+			assert.Nil(t, signature)
+		})
+	}
+}
+
+func TestNewAwsKmsSignerInvalid(t *testing.T) {
+	privateKey, _ := newEcdsaKey(t)
+	spkiDer := marshalSubjectPublicKeyInfo(t, privateKey)
+	keys := map[string]string{"0.0.1001": awsKeyId}
+	tests := []struct {
+		name   string
+		client *fakeAwsKmsClient
+		keys   map[string]string
+	}{
+		{name: "no keys", client: &fakeAwsKmsClient{spkiDer: spkiDer}},
+		{name: "invalid account", client: &fakeAwsKmsClient{spkiDer: spkiDer}, keys: map[string]string{"0.0": awsKeyId}},
+		{name: "get public key error", client: &fakeAwsKmsClient{getPublicKeyErr: errors.New("error")}, keys: keys},
+		{name: "invalid public key", client: &fakeAwsKmsClient{spkiDer: []byte{0x1}}, keys: keys},
+		{
+			name:   "unsupported key spec",
+			client: &fakeAwsKmsClient{keySpec: kmsTypes.KeySpecEccNistP256, spkiDer: spkiDer},
+			keys:   keys,
+		},
+		{
+			name:   "unsupported key usage",
+			client: &fakeAwsKmsClient{keyUsage: kmsTypes.KeyUsageTypeEncryptDecrypt, spkiDer: spkiDer},
+			keys:   keys,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			signer, err := newAwsKmsSignerWithClient(context.Background(), tt.client, tt.keys)
+
+			// then
+			assert.Error(t, err)
+			assert.Nil(t, signer)
+		})
+	}
+}
+
+// fakeAwsKmsClient serves the public key and signs the digest in DER format with the private key like AWS KMS
+type fakeAwsKmsClient struct {
+	getPublicKeyErr error
+	keySpec         kmsTypes.KeySpec
+	keyUsage        kmsTypes.KeyUsageType
+	privateKey      *ecdsa.PrivateKey
+	signature       []byte
+	signedKeyId     string
+	signErr         error
+	spkiDer         []byte
+}
+
+func (f *fakeAwsKmsClient) GetPublicKey(_ context.Context, _ *kms.GetPublicKeyInput, _ ...func(*kms.Options)) (
+	*kms.GetPublicKeyOutput,
+	error,
+) {
+	if f.getPublicKeyErr != nil {
+		return nil, f.getPublicKeyErr
+	}
+
+	output := &kms.GetPublicKeyOutput{
+		KeySpec:   kmsTypes.KeySpecEccSecgP256k1,
+		KeyUsage:  kmsTypes.KeyUsageTypeSignVerify,
+		PublicKey: f.spkiDer,
+	}
+	if f.keySpec != "" {
+		output.KeySpec = f.keySpec
+	}
+	if f.keyUsage != "" {
+		output.KeyUsage = f.keyUsage
+	}
+	return output, nil
+}
+
+func (f *fakeAwsKmsClient) Sign(_ context.Context, params *kms.SignInput, _ ...func(*kms.Options)) (
+	*kms.SignOutput,
+	error,
+) {
+	if f.signErr != nil {
+		return nil, f.signErr
+	}
+
+	f.signedKeyId = *params.KeyId
+	if f.signature != nil {
+		return &kms.SignOutput{Signature: f.signature}, nil
+	}
+
+	der, err := signDer(f.privateKey, params.Message)
+	if err != nil {
+		return nil, err
+	}
+	return &kms.SignOutput{Signature: der}, nil
+}
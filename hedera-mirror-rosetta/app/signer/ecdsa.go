@@ -0,0 +1,121 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package signer
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hashgraph/hedera-sdk-go/v2"
+	"github.com/pkg/errors"
+)
+
+const ecdsaSignatureLength = 64
+
+var (
+	oidEcPublicKey = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidSecp256k1   = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+	secp256k1N     = crypto.S256().Params().N
+	secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+)
+
+// subjectPublicKeyInfo is the X.509 SubjectPublicKeyInfo structure, which crypto/x509 can't parse with the secp256k1
+// curve
+type subjectPublicKeyInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+type derEcdsaSignature struct {
+	R *big.Int
+	S *big.Int
+}
+
+// ecdsaDigest returns the digest an ECDSA secp256k1 key signs of the message, which is its keccak256 hash, the same as
+// the SDK
+func ecdsaDigest(message []byte) []byte {
+	return crypto.Keccak256(message)
+}
+
+// publicKeyFromSubjectPublicKeyInfo parses the DER encoded SubjectPublicKeyInfo of an ECDSA secp256k1 public key
+func publicKeyFromSubjectPublicKeyInfo(der []byte) (hedera.PublicKey, error) {
+	var info subjectPublicKeyInfo
+	if rest, err := asn1.Unmarshal(der, &info); err != nil || len(rest) != 0 {
+		return hedera.PublicKey{}, errors.Errorf("Invalid public key")
+	}
+
+	var curve asn1.ObjectIdentifier
+	if !info.Algorithm.Algorithm.Equal(oidEcPublicKey) {
+		return hedera.PublicKey{}, errors.Errorf("Unsupported public key algorithm %s", info.Algorithm.Algorithm)
+	}
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &curve); err != nil || !curve.Equal(oidSecp256k1) {
+		return hedera.PublicKey{}, errors.Errorf("Unsupported public key curve, only secp256k1 is supported")
+	}
+
+	return publicKeyFromEcPoint(info.PublicKey.Bytes)
+}
+
+// publicKeyFromEcPoint converts the uncompressed secp256k1 point to the public key
+func publicKeyFromEcPoint(point []byte) (hedera.PublicKey, error) {
+	key, err := crypto.UnmarshalPubkey(point)
+	if err != nil {
+		return hedera.PublicKey{}, errors.Wrap(err, "Invalid secp256k1 public key")
+	}
+
+	return hedera.PublicKeyFromBytesECDSA(crypto.CompressPubkey(key))
+}
+
+// signatureFromDer converts the DER encoded ECDSA signature to the r || s format
+func signatureFromDer(der []byte) ([]byte, error) {
+	var signature derEcdsaSignature
+	if rest, err := asn1.Unmarshal(der, &signature); err != nil || len(rest) != 0 {
+		return nil, errors.Errorf("Invalid DER encoded ECDSA signature")
+	}
+
+	return toRawSignature(signature.R, signature.S)
+}
+
+// toRawSignature encodes the ECDSA signature in the 64-byte r || s format. The s value is normalized to the lower half
+// of the curve order, since the signatures with the high s value are rejected as malleable
+func toRawSignature(r, s *big.Int) ([]byte, error) {
+	if r.Sign() <= 0 || s.Sign() <= 0 || r.Cmp(secp256k1N) >= 0 || s.Cmp(secp256k1N) >= 0 {
+		return nil, errors.Errorf("Invalid ECDSA signature")
+	}
+
+	if s.Cmp(secp256k1HalfN) > 0 {
+		s = new(big.Int).Sub(secp256k1N, s)
+	}
+
+	signature := make([]byte, ecdsaSignatureLength)
+	r.FillBytes(signature[:ecdsaSignatureLength/2])
+	s.FillBytes(signature[ecdsaSignatureLength/2:])
+	return signature, nil
+}
+
+// verifyEcdsaSignature verifies the signature of the digest against the public key, so a key misconfigured in the KMS
+// or the HSM fails the signing instead of the transaction
+func verifyEcdsaSignature(publicKey hedera.PublicKey, digest, signature []byte) error {
+	if !crypto.VerifySignature(publicKey.BytesRaw(), digest, signature) {
+		return errors.Errorf("Signature doesn't verify against the public key %s", publicKey)
+	}
+	return nil
+}
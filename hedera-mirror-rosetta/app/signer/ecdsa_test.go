@@ -0,0 +1,165 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package signer
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hashgraph/hedera-sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublicKeyFromSubjectPublicKeyInfo(t *testing.T) {
+	// given
+	privateKey, publicKey := newEcdsaKey(t)
+
+	// when
+	actual, err := publicKeyFromSubjectPublicKeyInfo(marshalSubjectPublicKeyInfo(t, privateKey))
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, publicKey.String(), actual.String())
+}
+
+func TestPublicKeyFromSubjectPublicKeyInfoInvalid(t *testing.T) {
+	// given
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	p256Der, err := x509.MarshalPKIXPublicKey(&p256Key.PublicKey)
+	require.NoError(t, err)
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	ed25519Der, err := x509.MarshalPKIXPublicKey(ed25519Key.Public())
+	require.NoError(t, err)
+
+	for _, der := range [][]byte{nil, {0x1, 0x2}, p256Der, ed25519Der} {
+		// when
+		_, err = publicKeyFromSubjectPublicKeyInfo(der)
+
+		// then
+		assert.Error(t, err)
+	}
+}
+
+func TestSignatureFromDer(t *testing.T) {
+	// given
+	privateKey, publicKey := newEcdsaKey(t)
+	digest := ecdsaDigest([]byte("message"))
+	r, s := signDigest(t, privateKey, digest)
+	highS := new(big.Int).Sub(secp256k1N, s)
+
+	for _, der := range [][]byte{marshalDerSignature(t, r, s), marshalDerSignature(t, r, highS)} {
+		// when
+		signature, err := signatureFromDer(der)
+
+		// then
+		assert.NoError(t, err)
+		assert.Len(t, signature, ecdsaSignatureLength)
+		assert.Equal(t, s.Bytes(), new(big.Int).SetBytes(signature[ecdsaSignatureLength/2:]).Bytes())
+		assert.NoError(t, verifyEcdsaSignature(publicKey, digest, signature))
+	}
+}
+
+func TestSignatureFromDerInvalid(t *testing.T) {
+	for _, der := range [][]byte{
+		nil,
+		{0x1, 0x2},
+		marshalDerSignature(t, big.NewInt(0), big.NewInt(1)),
+		marshalDerSignature(t, big.NewInt(1), secp256k1N),
+	} {
+		// when
+		signature, err := signatureFromDer(der)
+
+		// then
+		assert.Error(t, err)
+		assert.Nil(t, signature)
+	}
+}
+
+func TestVerifyEcdsaSignatureWrongKey(t *testing.T) {
+	// given
+	privateKey, _ := newEcdsaKey(t)
+	_, otherPublicKey := newEcdsaKey(t)
+	digest := ecdsaDigest([]byte("message"))
+	signature, err := toRawSignature(signDigest(t, privateKey, digest))
+	require.NoError(t, err)
+
+	// when
+	err = verifyEcdsaSignature(otherPublicKey, digest, signature)
+
+	// then
+	assert.Error(t, err)
+}
+
+func marshalDerSignature(t *testing.T, r, s *big.Int) []byte {
+	der, err := asn1.Marshal(derEcdsaSignature{R: r, S: s})
+	require.NoError(t, err)
+	return der
+}
+
+func marshalSubjectPublicKeyInfo(t *testing.T, privateKey *ecdsa.PrivateKey) []byte {
+	curve, err := asn1.Marshal(oidSecp256k1)
+	require.NoError(t, err)
+	point := crypto.FromECDSAPub(&privateKey.PublicKey)
+	der, err := asn1.Marshal(subjectPublicKeyInfo{
+		Algorithm: pkix.AlgorithmIdentifier{Algorithm: oidEcPublicKey, Parameters: asn1.RawValue{FullBytes: curve}},
+		PublicKey: asn1.BitString{Bytes: point, BitLength: len(point) * 8},
+	})
+	require.NoError(t, err)
+	return der
+}
+
+func newEcdsaKey(t *testing.T) (*ecdsa.PrivateKey, hedera.PublicKey) {
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	publicKey, err := hedera.PublicKeyFromBytesECDSA(crypto.CompressPubkey(&privateKey.PublicKey))
+	require.NoError(t, err)
+	return privateKey, publicKey
+}
+
+// signDigest signs the digest and returns r and s of the signature, s is always in the lower half of the curve order
+func signDigest(t *testing.T, privateKey *ecdsa.PrivateKey, digest []byte) (*big.Int, *big.Int) {
+	signature, err := crypto.Sign(digest, privateKey)
+	require.NoError(t, err)
+	return new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:64])
+}
+
+// signDer signs the digest and returns the DER encoded signature like a KMS
+func signDer(privateKey *ecdsa.PrivateKey, digest []byte) ([]byte, error) {
+	signature, err := crypto.Sign(digest, privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return asn1.Marshal(derEcdsaSignature{
+		R: new(big.Int).SetBytes(signature[:32]),
+		S: new(big.Int).SetBytes(signature[32:64]),
+	})
+}
@@ -0,0 +1,150 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package signer
+
+import (
+	"context"
+	"encoding/pem"
+	"hash/crc32"
+
+	kms "cloud.google.com/go/kms/apiv1"
+	gax "github.com/googleapis/gax-go/v2"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-sdk-go/v2"
+	"github.com/pkg/errors"
+	kmspb "google.golang.org/genproto/googleapis/cloud/kms/v1"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// gcpKmsClient is the part of the Google Cloud KMS client the signer uses
+type gcpKmsClient interface {
+	GetPublicKey(ctx context.Context, req *kmspb.GetPublicKeyRequest, opts ...gax.CallOption) (*kmspb.PublicKey, error)
+	AsymmetricSign(ctx context.Context, req *kmspb.AsymmetricSignRequest, opts ...gax.CallOption) (
+		*kmspb.AsymmetricSignResponse,
+		error,
+	)
+}
+
+// gcpKmsKey is an EC_SIGN_SECP256K1_SHA256 crypto key version in Google Cloud KMS
+type gcpKmsKey struct {
+	name      string
+	publicKey hedera.PublicKey
+}
+
+// gcpKmsSigner signs with the ECDSA secp256k1 crypto key versions in Google Cloud KMS, keyed by the account id in
+// shard.realm.num format. The credentials are the application default credentials
+type gcpKmsSigner struct {
+	client gcpKmsClient
+	keys   map[string]gcpKmsKey
+}
+
+func (g *gcpKmsSigner) Sign(ctx context.Context, accountId types.AccountId, message []byte) (
+	hedera.PublicKey,
+	[]byte,
+	bool,
+	error,
+) {
+	key, ok := g.keys[accountId.String()]
+	if !ok {
+		return hedera.PublicKey{}, nil, false, nil
+	}
+
+	// the KMS signs the keccak256 digest as if it's the sha256 digest, since it only signs the digest as is
+	digest := ecdsaDigest(message)
+	response, err := g.client.AsymmetricSign(ctx, &kmspb.AsymmetricSignRequest{
+		Name:         key.name,
+		Digest:       &kmspb.Digest{Digest: &kmspb.Digest_Sha256{Sha256: digest}},
+		DigestCrc32C: wrapperspb.Int64(int64(crc32.Checksum(digest, crc32cTable))),
+	})
+	if err != nil {
+		return hedera.PublicKey{}, nil, false, errors.Wrapf(err, "Failed to sign with Google Cloud KMS key %s", key.name)
+	}
+
+	if !response.VerifiedDigestCrc32C || response.Name != key.name {
+		return hedera.PublicKey{}, nil, false, errors.Errorf("Corrupted sign request to Google Cloud KMS key %s",
+			key.name)
+	}
+
+	signature, err := signatureFromDer(response.Signature)
+	if err != nil {
+		return hedera.PublicKey{}, nil, false, err
+	}
+
+	if err = verifyEcdsaSignature(key.publicKey, digest, signature); err != nil {
+		return hedera.PublicKey{}, nil, false, err
+	}
+
+	return key.publicKey, signature, true, nil
+}
+
+func newGcpKmsSigner(ctx context.Context, keys map[string]string) (*gcpKmsSigner, error) {
+	client, err := kms.NewKeyManagementClient(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create the Google Cloud KMS client")
+	}
+
+	signer, err := newGcpKmsSignerWithClient(ctx, client, keys)
+	if err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+	return signer, nil
+}
+
+// newGcpKmsSignerWithClient creates the signer and gets the public key of each crypto key version, which also verifies
+// the keys exist and are supported
+func newGcpKmsSignerWithClient(ctx context.Context, client gcpKmsClient, keys map[string]string) (
+	*gcpKmsSigner,
+	error,
+) {
+	accountKeys, err := getAccountKeys(keys, TypeGcp)
+	if err != nil {
+		return nil, err
+	}
+
+	kmsKeys := make(map[string]gcpKmsKey, len(accountKeys))
+	for account, name := range accountKeys {
+		response, err := client.GetPublicKey(ctx, &kmspb.GetPublicKeyRequest{Name: name})
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to get the public key of Google Cloud KMS key %s", name)
+		}
+
+		if response.Algorithm != kmspb.CryptoKeyVersion_EC_SIGN_SECP256K1_SHA256 {
+			return nil, errors.Errorf("Unsupported Google Cloud KMS key %s of algorithm %s, only "+
+				"EC_SIGN_SECP256K1_SHA256 key is supported", name, response.Algorithm)
+		}
+
+		block, _ := pem.Decode([]byte(response.Pem))
+		if block == nil {
+			return nil, errors.Errorf("Invalid PEM public key of Google Cloud KMS key %s", name)
+		}
+
+		publicKey, err := publicKeyFromSubjectPublicKeyInfo(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid public key of Google Cloud KMS key %s", name)
+		}
+
+		kmsKeys[account] = gcpKmsKey{name: name, publicKey: publicKey}
+	}
+
+	return &gcpKmsSigner{client: client, keys: kmsKeys}, nil
+}
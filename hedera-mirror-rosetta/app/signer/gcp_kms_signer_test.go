@@ -0,0 +1,200 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/pem"
+	"hash/crc32"
+	"testing"
+
+	gax "github.com/googleapis/gax-go/v2"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	kmspb "google.golang.org/genproto/googleapis/cloud/kms/v1"
+)
+
+const gcpKeyName = "projects/test/locations/global/keyRings/rosetta/cryptoKeys/payer/cryptoKeyVersions/1"
+
+func TestGcpKmsSignerSign(t *testing.T) {
+	// given
+	privateKey, publicKey := newEcdsaKey(t)
+	client := &fakeGcpKmsClient{privateKey: privateKey, pem: marshalPem(t, privateKey)}
+	signer, err := newGcpKmsSignerWithClient(context.Background(), client, map[string]string{"0.0.1001": gcpKeyName})
+	require.NoError(t, err)
+	message := []byte("message")
+
+	// when
+	actual, signature, ok, err := signer.Sign(context.Background(), newAccountId(1001), message)
+
+	// then
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, publicKey.String(), actual.String())
+	assert.NoError(t, verifyEcdsaSignature(actual, ecdsaDigest(message), signature))
+	assert.Equal(t, gcpKeyName, client.signedName)
+}
+
+func TestGcpKmsSignerSignUnknownAccount(t *testing.T) {
+	// given
+	privateKey, _ := newEcdsaKey(t)
+	client := &fakeGcpKmsClient{privateKey: privateKey, pem: marshalPem(t, privateKey)}
+	signer, err := newGcpKmsSignerWithClient(context.Background(), client, map[string]string{"0.0.1001": gcpKeyName})
+	require.NoError(t, err)
+
+	// when
+	_, signature, ok, err := signer.Sign(context.Background(), newAccountId(1002), []byte("message"))
+
+	// then
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Nil(t, signature)
+	assert.Empty(t, client.signedName)
+}
+
+func TestGcpKmsSignerSignError(t *testing.T) {
+	privateKey, _ := newEcdsaKey(t)
+	otherPrivateKey, _ := newEcdsaKey(t)
+	tests := []struct {
+		name   string
+		client *fakeGcpKmsClient
+	}{
+		{name: "sign error", client: &fakeGcpKmsClient{privateKey: privateKey, signErr: errors.New("error")}},
+		{name: "corrupted request", client: &fakeGcpKmsClient{privateKey: privateKey, corrupted: true}},
+		{name: "signed with another key", client: &fakeGcpKmsClient{privateKey: otherPrivateKey}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			tt.client.pem = marshalPem(t, privateKey)
+			signer, err := newGcpKmsSignerWithClient(
+				context.Background(),
+				tt.client,
+				map[string]string{"0.0.1001": gcpKeyName},
+			)
+			require.NoError(t, err)
+
+			// when
+			_, signature, ok, err := signer.Sign(context.Background(), newAccountId(1001), []byte("message"))
+
+			// then
+			assert.Error(t, err)
+			assert.False(t, ok)
+			assert.Nil(t, signature)
+		})
+	}
+}
+
+func TestNewGcpKmsSignerInvalid(t *testing.T) {
+	privateKey, _ := newEcdsaKey(t)
+	publicKeyPem := marshalPem(t, privateKey)
+	keys := map[string]string{"0.0.1001": gcpKeyName}
+	tests := []struct {
+		name   string
+		client *fakeGcpKmsClient
+		keys   map[string]string
+	}{
+		{name: "no keys", client: &fakeGcpKmsClient{pem: publicKeyPem}},
+		{name: "invalid account", client: &fakeGcpKmsClient{pem: publicKeyPem}, keys: map[string]string{"0.0": gcpKeyName}},
+		{name: "get public key error", client: &fakeGcpKmsClient{getPublicKeyErr: errors.New("error")}, keys: keys},
+		{name: "invalid pem", client: &fakeGcpKmsClient{pem: "invalid"}, keys: keys},
+		{
+			name: "invalid public key",
+			client: &fakeGcpKmsClient{
+				pem: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: []byte{0x1}})),
+			},
+			keys: keys,
+		},
+		{
+			name: "unsupported algorithm",
+			client: &fakeGcpKmsClient{
+				algorithm: kmspb.CryptoKeyVersion_EC_SIGN_P256_SHA256,
+				pem:       publicKeyPem,
+			},
+			keys: keys,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			signer, err := newGcpKmsSignerWithClient(context.Background(), tt.client, tt.keys)
+
+			// then
+			assert.Error(t, err)
+			assert.Nil(t, signer)
+		})
+	}
+}
+
+func marshalPem(t *testing.T, privateKey *ecdsa.PrivateKey) string {
+	block := &pem.Block{Type: "PUBLIC KEY", Bytes: marshalSubjectPublicKeyInfo(t, privateKey)}
+	return string(pem.EncodeToMemory(block))
+}
+
+// fakeGcpKmsClient serves the public key in PEM format and signs the digest in DER format with the private key like
+// Google Cloud KMS
+type fakeGcpKmsClient struct {
+	algorithm       kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm
+	corrupted       bool
+	getPublicKeyErr error
+	pem             string
+	privateKey      *ecdsa.PrivateKey
+	signedName      string
+	signErr         error
+}
+
+func (f *fakeGcpKmsClient) GetPublicKey(_ context.Context, req *kmspb.GetPublicKeyRequest, _ ...gax.CallOption) (
+	*kmspb.PublicKey,
+	error,
+) {
+	if f.getPublicKeyErr != nil {
+		return nil, f.getPublicKeyErr
+	}
+
+	algorithm := kmspb.CryptoKeyVersion_EC_SIGN_SECP256K1_SHA256
+	if f.algorithm != kmspb.CryptoKeyVersion_CRYPTO_KEY_VERSION_ALGORITHM_UNSPECIFIED {
+		algorithm = f.algorithm
+	}
+	return &kmspb.PublicKey{Algorithm: algorithm, Name: req.Name, Pem: f.pem}, nil
+}
+
+func (f *fakeGcpKmsClient) AsymmetricSign(
+	_ context.Context,
+	req *kmspb.AsymmetricSignRequest,
+	_ ...gax.CallOption,
+) (*kmspb.AsymmetricSignResponse, error) {
+	if f.signErr != nil {
+		return nil, f.signErr
+	}
+
+	f.signedName = req.Name
+	digest := req.Digest.GetSha256()
+	verified := int64(crc32.Checksum(digest, crc32cTable)) == req.DigestCrc32C.GetValue() && !f.corrupted
+	signature, err := signDer(f.privateKey, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kmspb.AsymmetricSignResponse{Name: req.Name, Signature: signature, VerifiedDigestCrc32C: verified}, nil
+}
@@ -0,0 +1,66 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package signer
+
+import (
+	"context"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-sdk-go/v2"
+	"github.com/pkg/errors"
+)
+
+// localSigner signs with the ED25519 private keys in the configuration, keyed by the account id in shard.realm.num
+// format
+type localSigner struct {
+	keys map[string]hedera.PrivateKey
+}
+
+func (l *localSigner) Sign(_ context.Context, accountId types.AccountId, message []byte) (
+	hedera.PublicKey,
+	[]byte,
+	bool,
+	error,
+) {
+	key, ok := l.keys[accountId.String()]
+	if !ok {
+		return hedera.PublicKey{}, nil, false, nil
+	}
+
+	return key.PublicKey(), key.Sign(message), true, nil
+}
+
+func newLocalSigner(keys map[string]string) (*localSigner, error) {
+	accountKeys, err := getAccountKeys(keys, TypeLocal)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKeys := make(map[string]hedera.PrivateKey, len(accountKeys))
+	for account, keyString := range accountKeys {
+		key, err := hedera.PrivateKeyFromStringEd25519(keyString)
+		if err != nil {
+			return nil, errors.Errorf("Invalid ED25519 private key of account %s", account)
+		}
+		privateKeys[account] = key
+	}
+
+	return &localSigner{keys: privateKeys}, nil
+}
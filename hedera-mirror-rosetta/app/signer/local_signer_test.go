@@ -0,0 +1,88 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package signer
+
+import (
+	"context"
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalSignerSign(t *testing.T) {
+	// given
+	key, _ := hedera.PrivateKeyGenerateEd25519()
+	signer, err := newLocalSigner(map[string]string{"0.0.1001": key.String()})
+	assert.NoError(t, err)
+	message := []byte("message")
+
+	// when
+	publicKey, signature, ok, err := signer.Sign(
+		context.Background(),
+		types.NewAccountIdFromEntityId(domain.MustDecodeEntityId(1001)),
+		message,
+	)
+
+	// then
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, key.PublicKey(), publicKey)
+	assert.True(t, ed25519.Verify(publicKey.BytesRaw(), message, signature))
+}
+
+func TestLocalSignerSignUnknownAccount(t *testing.T) {
+	// given
+	key, _ := hedera.PrivateKeyGenerateEd25519()
+	signer, err := newLocalSigner(map[string]string{"0.0.1001": key.String()})
+	assert.NoError(t, err)
+
+	// when
+	_, signature, ok, err := signer.Sign(
+		context.Background(),
+		types.NewAccountIdFromEntityId(domain.MustDecodeEntityId(1002)),
+		[]byte("message"),
+	)
+
+	// then
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Nil(t, signature)
+}
+
+func TestNewLocalSignerInvalid(t *testing.T) {
+	key, _ := hedera.PrivateKeyGenerateEd25519()
+	for _, keys := range []map[string]string{
+		nil,
+		{},
+		{"0.0": key.String()},
+		{"0.0.1001": "invalid"},
+	} {
+		// when
+		signer, err := newLocalSigner(keys)
+
+		// then
+		assert.Error(t, err)
+		assert.Nil(t, signer)
+	}
+}
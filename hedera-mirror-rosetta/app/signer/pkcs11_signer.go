@@ -0,0 +1,292 @@
+//go:build cgo
+
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package signer
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/asn1"
+	"math/big"
+	"sync"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-sdk-go/v2"
+	"github.com/miekg/pkcs11"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ckmEddsa is the EdDSA mechanism added in PKCS#11 3.0, which the pkcs11 package doesn't define
+	ckmEddsa = 0x1057
+	// edwards25519 is the curve name some tokens set as the EC parameters of an ED25519 key instead of the oid
+	edwards25519 = "edwards25519"
+)
+
+var oidEd25519 = asn1.ObjectIdentifier{1, 3, 101, 112}
+
+// pkcs11Context is the part of the PKCS#11 context the signer uses
+type pkcs11Context interface {
+	FindObjectsInit(sh pkcs11.SessionHandle, temp []*pkcs11.Attribute) error
+	FindObjects(sh pkcs11.SessionHandle, max int) ([]pkcs11.ObjectHandle, bool, error)
+	FindObjectsFinal(sh pkcs11.SessionHandle) error
+	GetAttributeValue(sh pkcs11.SessionHandle, o pkcs11.ObjectHandle, a []*pkcs11.Attribute) ([]*pkcs11.Attribute, error)
+	SignInit(sh pkcs11.SessionHandle, m []*pkcs11.Mechanism, o pkcs11.ObjectHandle) error
+	Sign(sh pkcs11.SessionHandle, message []byte) ([]byte, error)
+}
+
+// pkcs11Key is a key pair in the token, either ECDSA secp256k1 or ED25519
+type pkcs11Key struct {
+	label      string
+	mechanism  uint
+	privateKey pkcs11.ObjectHandle
+	publicKey  hedera.PublicKey
+}
+
+// pkcs11Signer signs with the key pairs in the token of an HSM via its PKCS#11 module, keyed by the account id in
+// shard.realm.num format. The key pairs are found by their label
+type pkcs11Signer struct {
+	context pkcs11Context
+	keys    map[string]pkcs11Key
+	// mutex serializes the sign operations, since a session only runs one operation at a time
+	mutex   sync.Mutex
+	session pkcs11.SessionHandle
+}
+
+func (p *pkcs11Signer) Sign(_ context.Context, accountId types.AccountId, message []byte) (
+	hedera.PublicKey,
+	[]byte,
+	bool,
+	error,
+) {
+	key, ok := p.keys[accountId.String()]
+	if !ok {
+		return hedera.PublicKey{}, nil, false, nil
+	}
+
+	data := message
+	if key.mechanism == pkcs11.CKM_ECDSA {
+		data = ecdsaDigest(message)
+	}
+
+	signature, err := p.sign(key, data)
+	if err != nil {
+		return hedera.PublicKey{}, nil, false, errors.Wrapf(err, "Failed to sign with PKCS#11 key %s", key.label)
+	}
+
+	if key.mechanism == pkcs11.CKM_ECDSA {
+		if len(signature) != ecdsaSignatureLength {
+			return hedera.PublicKey{}, nil, false, errors.Errorf("Invalid ECDSA signature length %d", len(signature))
+		}
+
+		r := new(big.Int).SetBytes(signature[:ecdsaSignatureLength/2])
+		s := new(big.Int).SetBytes(signature[ecdsaSignatureLength/2:])
+		if signature, err = toRawSignature(r, s); err != nil {
+			return hedera.PublicKey{}, nil, false, err
+		}
+
+		if err = verifyEcdsaSignature(key.publicKey, data, signature); err != nil {
+			return hedera.PublicKey{}, nil, false, err
+		}
+	} else if !ed25519.Verify(key.publicKey.BytesRaw(), message, signature) {
+		return hedera.PublicKey{}, nil, false, errors.Errorf("Signature doesn't verify against the public key %s",
+			key.publicKey)
+	}
+
+	return key.publicKey, signature, true, nil
+}
+
+func (p *pkcs11Signer) sign(key pkcs11Key, data []byte) ([]byte, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	mechanism := []*pkcs11.Mechanism{pkcs11.NewMechanism(key.mechanism, nil)}
+	if err := p.context.SignInit(p.session, mechanism, key.privateKey); err != nil {
+		return nil, err
+	}
+
+	return p.context.Sign(p.session, data)
+}
+
+// newPkcs11Signer loads the PKCS#11 module, logs in to the token with the label as the user, and finds the key pairs
+func newPkcs11Signer(pkcs11Config config.Pkcs11, keys map[string]string) (*pkcs11Signer, error) {
+	ctx := pkcs11.New(pkcs11Config.Library)
+	if ctx == nil {
+		return nil, errors.Errorf("Failed to load the PKCS#11 module %s", pkcs11Config.Library)
+	}
+
+	if err := ctx.Initialize(); err != nil {
+		return nil, errors.Wrap(err, "Failed to initialize the PKCS#11 module")
+	}
+
+	session, err := openPkcs11Session(ctx, pkcs11Config)
+	if err != nil {
+		_ = ctx.Finalize()
+		return nil, err
+	}
+
+	signer, err := newPkcs11SignerWithContext(ctx, session, keys)
+	if err != nil {
+		_ = ctx.CloseSession(session)
+		_ = ctx.Finalize()
+		return nil, err
+	}
+
+	return signer, nil
+}
+
+func openPkcs11Session(ctx *pkcs11.Ctx, pkcs11Config config.Pkcs11) (pkcs11.SessionHandle, error) {
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		return 0, errors.Wrap(err, "Failed to get the PKCS#11 slots")
+	}
+
+	for _, slot := range slots {
+		tokenInfo, err := ctx.GetTokenInfo(slot)
+		if err != nil || tokenInfo.Label != pkcs11Config.TokenLabel {
+			continue
+		}
+
+		session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION)
+		if err != nil {
+			return 0, errors.Wrapf(err, "Failed to open a session to PKCS#11 token %s", pkcs11Config.TokenLabel)
+		}
+
+		if err = ctx.Login(session, pkcs11.CKU_USER, pkcs11Config.Pin); err != nil {
+			_ = ctx.CloseSession(session)
+			return 0, errors.Wrapf(err, "Failed to log in to PKCS#11 token %s", pkcs11Config.TokenLabel)
+		}
+
+		return session, nil
+	}
+
+	return 0, errors.Errorf("PKCS#11 token %s not found", pkcs11Config.TokenLabel)
+}
+
+func newPkcs11SignerWithContext(ctx pkcs11Context, session pkcs11.SessionHandle, keys map[string]string) (
+	*pkcs11Signer,
+	error,
+) {
+	accountKeys, err := getAccountKeys(keys, TypePkcs11)
+	if err != nil {
+		return nil, err
+	}
+
+	signer := &pkcs11Signer{context: ctx, keys: make(map[string]pkcs11Key, len(accountKeys)), session: session}
+	for account, label := range accountKeys {
+		key, err := signer.findKey(label)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid PKCS#11 key %s", label)
+		}
+		signer.keys[account] = key
+	}
+
+	return signer, nil
+}
+
+// findKey finds the private key and the public key with the label, and gets the key type from the EC parameters of
+// the public key
+func (p *pkcs11Signer) findKey(label string) (pkcs11Key, error) {
+	privateKey, err := p.findObject(pkcs11.CKO_PRIVATE_KEY, label)
+	if err != nil {
+		return pkcs11Key{}, err
+	}
+
+	publicKeyObject, err := p.findObject(pkcs11.CKO_PUBLIC_KEY, label)
+	if err != nil {
+		return pkcs11Key{}, err
+	}
+
+	attributes, err := p.context.GetAttributeValue(p.session, publicKeyObject, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, nil),
+		pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil),
+	})
+	if err != nil {
+		return pkcs11Key{}, err
+	}
+
+	var ecParams, ecPoint []byte
+	for _, attribute := range attributes {
+		switch attribute.Type {
+		case pkcs11.CKA_EC_PARAMS:
+			ecParams = attribute.Value
+		case pkcs11.CKA_EC_POINT:
+			ecPoint = attribute.Value
+		}
+	}
+
+	// the EC point is DER encoded as an octet string per the spec, though some tokens return the raw point
+	var point []byte
+	if rest, err := asn1.Unmarshal(ecPoint, &point); err != nil || len(rest) != 0 {
+		point = ecPoint
+	}
+
+	var oid asn1.ObjectIdentifier
+	var publicKey hedera.PublicKey
+	var mechanism uint
+	if _, err = asn1.Unmarshal(ecParams, &oid); err == nil && oid.Equal(oidSecp256k1) {
+		mechanism = pkcs11.CKM_ECDSA
+		publicKey, err = publicKeyFromEcPoint(point)
+	} else if oid.Equal(oidEd25519) || isEdwards25519(ecParams) {
+		mechanism = ckmEddsa
+		publicKey, err = hedera.PublicKeyFromBytesEd25519(point)
+	} else {
+		return pkcs11Key{}, errors.Errorf("Unsupported key, only ECDSA secp256k1 and ED25519 keys are supported")
+	}
+	if err != nil {
+		return pkcs11Key{}, err
+	}
+
+	return pkcs11Key{label: label, mechanism: mechanism, privateKey: privateKey, publicKey: publicKey}, nil
+}
+
+func (p *pkcs11Signer) findObject(class uint, label string) (pkcs11.ObjectHandle, error) {
+	template := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	}
+	if err := p.context.FindObjectsInit(p.session, template); err != nil {
+		return 0, err
+	}
+
+	objects, _, err := p.context.FindObjects(p.session, 2)
+	if finalErr := p.context.FindObjectsFinal(p.session); err == nil {
+		err = finalErr
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if len(objects) != 1 {
+		return 0, errors.Errorf("Expect exactly 1 object of class %d with label %s, found %d", class, label,
+			len(objects))
+	}
+
+	return objects[0], nil
+}
+
+// isEdwards25519 returns true if the EC parameters are the edwards25519 curve name as a printable string
+func isEdwards25519(ecParams []byte) bool {
+	var curveName string
+	_, err := asn1.Unmarshal(ecParams, &curveName)
+	return err == nil && curveName == edwards25519
+}
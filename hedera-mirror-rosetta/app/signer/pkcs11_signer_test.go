@@ -0,0 +1,312 @@
+//go:build cgo
+
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/asn1"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/miekg/pkcs11"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const pkcs11Session pkcs11.SessionHandle = 10
+
+func TestPkcs11SignerSignEcdsa(t *testing.T) {
+	// given
+	privateKey, publicKey := newEcdsaKey(t)
+	ctx := newFakePkcs11Context()
+	ctx.addEcdsaKey(t, "payer", privateKey)
+	signer, err := newPkcs11SignerWithContext(ctx, pkcs11Session, map[string]string{"0.0.1001": "payer"})
+	require.NoError(t, err)
+	message := []byte("message")
+
+	// when
+	actual, signature, ok, err := signer.Sign(context.Background(), newAccountId(1001), message)
+
+	// then
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, publicKey.String(), actual.String())
+	assert.NoError(t, verifyEcdsaSignature(actual, ecdsaDigest(message), signature))
+	assert.Equal(t, uint(pkcs11.CKM_ECDSA), ctx.mechanism)
+}
+
+func TestPkcs11SignerSignEd25519(t *testing.T) {
+	for _, ecParams := range [][]byte{mustMarshal(t, oidEd25519), mustMarshal(t, edwards25519)} {
+		// given
+		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		ctx := newFakePkcs11Context()
+		ctx.addEd25519Key(t, "payer", privateKey, ecParams)
+		signer, err := newPkcs11SignerWithContext(ctx, pkcs11Session, map[string]string{"0.0.1001": "payer"})
+		require.NoError(t, err)
+		message := []byte("message")
+
+		// when
+		actual, signature, ok, err := signer.Sign(context.Background(), newAccountId(1001), message)
+
+		// then
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, []byte(publicKey), actual.BytesRaw())
+		assert.True(t, ed25519.Verify(publicKey, message, signature))
+		assert.Equal(t, uint(ckmEddsa), ctx.mechanism)
+	}
+}
+
+func TestPkcs11SignerSignUnknownAccount(t *testing.T) {
+	// given
+	privateKey, _ := newEcdsaKey(t)
+	ctx := newFakePkcs11Context()
+	ctx.addEcdsaKey(t, "payer", privateKey)
+	signer, err := newPkcs11SignerWithContext(ctx, pkcs11Session, map[string]string{"0.0.1001": "payer"})
+	require.NoError(t, err)
+
+	// when
+	_, signature, ok, err := signer.Sign(context.Background(), newAccountId(1002), []byte("message"))
+
+	// then
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Nil(t, signature)
+	assert.Zero(t, ctx.mechanism)
+}
+
+func TestPkcs11SignerSignError(t *testing.T) {
+	// given
+	privateKey, _ := newEcdsaKey(t)
+	ctx := newFakePkcs11Context()
+	ctx.addEcdsaKey(t, "payer", privateKey)
+	signer, err := newPkcs11SignerWithContext(ctx, pkcs11Session, map[string]string{"0.0.1001": "payer"})
+	require.NoError(t, err)
+	ctx.signErr = errors.New("error")
+
+	// when
+	_, signature, ok, err := signer.Sign(context.Background(), newAccountId(1001), []byte("message"))
+
+	// then
+	assert.Error(t, err)
+	assert.False(t, ok)
+	assert.Nil(t, signature)
+}
+
+func TestNewPkcs11SignerWithContextInvalid(t *testing.T) {
+	privateKey, _ := newEcdsaKey(t)
+	tests := []struct {
+		name  string
+		setup func(ctx *fakePkcs11Context)
+		keys  map[string]string
+	}{
+		{name: "no keys"},
+		{name: "invalid account", keys: map[string]string{"0.0": "payer"}},
+		{name: "key not found", keys: map[string]string{"0.0.1001": "payer"}},
+		{
+			name: "duplicate keys",
+			setup: func(ctx *fakePkcs11Context) {
+				ctx.addEcdsaKey(t, "payer", privateKey)
+				ctx.addEcdsaKey(t, "payer", privateKey)
+			},
+			keys: map[string]string{"0.0.1001": "payer"},
+		},
+		{
+			name: "unsupported curve",
+			setup: func(ctx *fakePkcs11Context) {
+				ctx.addEcdsaKey(t, "payer", privateKey)
+				for _, object := range ctx.objects {
+					object.ecParams = mustMarshal(t, asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7})
+				}
+			},
+			keys: map[string]string{"0.0.1001": "payer"},
+		},
+		{
+			name: "find objects error",
+			setup: func(ctx *fakePkcs11Context) {
+				ctx.addEcdsaKey(t, "payer", privateKey)
+				ctx.findErr = errors.New("error")
+			},
+			keys: map[string]string{"0.0.1001": "payer"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctx := newFakePkcs11Context()
+			if tt.setup != nil {
+				tt.setup(ctx)
+			}
+
+			// when
+			signer, err := newPkcs11SignerWithContext(ctx, pkcs11Session, tt.keys)
+
+			// then
+			assert.Error(t, err)
+			assert.Nil(t, signer)
+			assert.False(t, ctx.finding)
+		})
+	}
+}
+
+func mustMarshal(t *testing.T, value interface{}) []byte {
+	data, err := asn1.Marshal(value)
+	require.NoError(t, err)
+	return data
+}
+
+type fakePkcs11Object struct {
+	class    uint
+	ecParams []byte
+	ecPoint  []byte
+	label    string
+	sign     func(data []byte) ([]byte, error)
+}
+
+// fakePkcs11Context is a token holding the key pairs in memory
+type fakePkcs11Context struct {
+	findErr   error
+	finding   bool
+	found     []pkcs11.ObjectHandle
+	mechanism uint
+	objects   map[pkcs11.ObjectHandle]*fakePkcs11Object
+	signErr   error
+	signing   *fakePkcs11Object
+}
+
+func newFakePkcs11Context() *fakePkcs11Context {
+	return &fakePkcs11Context{objects: make(map[pkcs11.ObjectHandle]*fakePkcs11Object)}
+}
+
+func (f *fakePkcs11Context) addEcdsaKey(t *testing.T, label string, privateKey *ecdsa.PrivateKey) {
+	f.addKey(t, label, mustMarshal(t, oidSecp256k1), crypto.FromECDSAPub(&privateKey.PublicKey),
+		func(digest []byte) ([]byte, error) {
+			signature, err := crypto.Sign(digest, privateKey)
+			if err != nil {
+				return nil, err
+			}
+			return signature[:ecdsaSignatureLength], nil
+		})
+}
+
+func (f *fakePkcs11Context) addEd25519Key(t *testing.T, label string, privateKey ed25519.PrivateKey, ecParams []byte) {
+	publicKey := privateKey.Public().(ed25519.PublicKey)
+	f.addKey(t, label, ecParams, publicKey, func(message []byte) ([]byte, error) {
+		return ed25519.Sign(privateKey, message), nil
+	})
+}
+
+func (f *fakePkcs11Context) addKey(
+	t *testing.T,
+	label string,
+	ecParams []byte,
+	point []byte,
+	sign func([]byte) ([]byte, error),
+) {
+	handle := pkcs11.ObjectHandle(len(f.objects) + 1)
+	f.objects[handle] = &fakePkcs11Object{class: pkcs11.CKO_PRIVATE_KEY, label: label, sign: sign}
+	f.objects[handle+1] = &fakePkcs11Object{
+		class:    pkcs11.CKO_PUBLIC_KEY,
+		ecParams: ecParams,
+		ecPoint:  mustMarshal(t, point),
+		label:    label,
+	}
+}
+
+func (f *fakePkcs11Context) FindObjectsInit(_ pkcs11.SessionHandle, template []*pkcs11.Attribute) error {
+	if f.findErr != nil {
+		return f.findErr
+	}
+
+	var class uint
+	var label string
+	for _, attribute := range template {
+		switch attribute.Type {
+		case pkcs11.CKA_CLASS:
+			class = uint(attribute.Value[0])
+		case pkcs11.CKA_LABEL:
+			label = string(attribute.Value)
+		}
+	}
+
+	f.finding = true
+	f.found = nil
+	for handle, object := range f.objects {
+		if object.class == class && object.label == label {
+			f.found = append(f.found, handle)
+		}
+	}
+	return nil
+}
+
+func (f *fakePkcs11Context) FindObjects(_ pkcs11.SessionHandle, max int) ([]pkcs11.ObjectHandle, bool, error) {
+	if len(f.found) > max {
+		return f.found[:max], false, nil
+	}
+	return f.found, false, nil
+}
+
+func (f *fakePkcs11Context) FindObjectsFinal(_ pkcs11.SessionHandle) error {
+	f.finding = false
+	return nil
+}
+
+func (f *fakePkcs11Context) GetAttributeValue(
+	_ pkcs11.SessionHandle,
+	handle pkcs11.ObjectHandle,
+	attributes []*pkcs11.Attribute,
+) ([]*pkcs11.Attribute, error) {
+	object := f.objects[handle]
+	values := make([]*pkcs11.Attribute, 0, len(attributes))
+	for _, attribute := range attributes {
+		switch attribute.Type {
+		case pkcs11.CKA_EC_PARAMS:
+			values = append(values, pkcs11.NewAttribute(attribute.Type, object.ecParams))
+		case pkcs11.CKA_EC_POINT:
+			values = append(values, pkcs11.NewAttribute(attribute.Type, object.ecPoint))
+		}
+	}
+	return values, nil
+}
+
+func (f *fakePkcs11Context) SignInit(
+	_ pkcs11.SessionHandle,
+	mechanisms []*pkcs11.Mechanism,
+	handle pkcs11.ObjectHandle,
+) error {
+	f.mechanism = mechanisms[0].Mechanism
+	f.signing = f.objects[handle]
+	return nil
+}
+
+func (f *fakePkcs11Context) Sign(_ pkcs11.SessionHandle, data []byte) ([]byte, error) {
+	if f.signErr != nil {
+		return nil, f.signErr
+	}
+	return f.signing.sign(data)
+}
@@ -0,0 +1,83 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package signer
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/pkg/errors"
+)
+
+const (
+	TypeAws    = "aws"
+	TypeGcp    = "gcp"
+	TypeLocal  = "local"
+	TypePkcs11 = "pkcs11"
+
+	// initTimeout bounds the calls to the KMS to get the public keys when creating the signer
+	initTimeout = 30 * time.Second
+)
+
+// NewSigner creates the server-side signer of the configured type, or returns nil if server-side signing is disabled
+func NewSigner(signerConfig config.Signer) (interfaces.Signer, error) {
+	if !signerConfig.Enabled {
+		return nil, nil
+	}
+
+	switch strings.ToLower(signerConfig.Type) {
+	case TypeAws:
+		ctx, cancel := context.WithTimeout(context.Background(), initTimeout)
+		defer cancel()
+		return newAwsKmsSigner(ctx, signerConfig.Keys)
+	case TypeGcp:
+		ctx, cancel := context.WithTimeout(context.Background(), initTimeout)
+		defer cancel()
+		return newGcpKmsSigner(ctx, signerConfig.Keys)
+	case TypeLocal:
+		return newLocalSigner(signerConfig.Keys)
+	case TypePkcs11:
+		return newPkcs11Signer(signerConfig.Pkcs11, signerConfig.Keys)
+	default:
+		return nil, errors.Errorf("Unsupported signer type %s", signerConfig.Type)
+	}
+}
+
+// getAccountKeys parses the accounts in shard.realm.num format of the configured keys
+func getAccountKeys(keys map[string]string, signerType string) (map[string]string, error) {
+	if len(keys) == 0 {
+		return nil, errors.Errorf("No keys configured for the %s signer", signerType)
+	}
+
+	accountKeys := make(map[string]string, len(keys))
+	for account, key := range keys {
+		accountId, err := domain.EntityIdFromString(account)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid account %s of the %s signer", account, signerType)
+		}
+		accountKeys[accountId.String()] = key
+	}
+
+	return accountKeys, nil
+}
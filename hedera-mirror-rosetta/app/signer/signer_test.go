@@ -0,0 +1,78 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package signer
+
+import (
+	"testing"
+
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence/domain"
+	"github.com/hashgraph/hedera-sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSigner(t *testing.T) {
+	key, _ := hedera.PrivateKeyGenerateEd25519()
+	tests := []struct {
+		name         string
+		signerConfig config.Signer
+		expectNil    bool
+		expectError  bool
+	}{
+		{name: "disabled", signerConfig: config.Signer{Type: TypeLocal}, expectNil: true},
+		{
+			name:         "local",
+			signerConfig: config.Signer{Enabled: true, Keys: map[string]string{"0.0.2": key.String()}, Type: TypeLocal},
+		},
+		{
+			name:         "local case insensitive",
+			signerConfig: config.Signer{Enabled: true, Keys: map[string]string{"0.0.2": key.String()}, Type: "LOCAL"},
+		},
+		{name: "local without keys", signerConfig: config.Signer{Enabled: true, Type: TypeLocal}, expectError: true},
+		{name: "aws without keys", signerConfig: config.Signer{Enabled: true, Type: TypeAws}, expectError: true},
+		{
+			name:         "pkcs11 without library",
+			signerConfig: config.Signer{Enabled: true, Keys: map[string]string{"0.0.2": "key"}, Type: TypePkcs11},
+			expectError:  true,
+		},
+		{name: "unsupported type", signerConfig: config.Signer{Enabled: true, Type: "vault"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			actual, err := NewSigner(tt.signerConfig)
+
+			// then
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, actual)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectNil, actual == nil)
+			}
+		})
+	}
+}
+
+func newAccountId(num int64) types.AccountId {
+	return types.NewAccountIdFromEntityId(domain.MustDecodeEntityId(num))
+}
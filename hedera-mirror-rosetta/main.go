@@ -21,111 +21,229 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
-	"runtime"
 	"strings"
+	"time"
 
 	rosettaAsserter "github.com/coinbase/rosetta-sdk-go/asserter"
 	"github.com/coinbase/rosetta-sdk-go/server"
 	rTypes "github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
-	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/db"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/logging"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/middleware"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/redaction"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/rpc"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/services"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/services/construction"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/signer"
 	log "github.com/sirupsen/logrus"
 )
 
-const moduleName = "hedera-mirror-rosetta"
-
-var Version = "development"
-
-func configLogger(level string) {
-	var err error
-	var logLevel log.Level
-
-	if logLevel, err = log.ParseLevel(strings.ToLower(level)); err != nil {
-		// if invalid, default to info
-		logLevel = log.InfoLevel
-	}
+const (
+	bootstrapBalancesCommand = "bootstrap-balances"
+	exemptAccountsCommand    = "exempt-accounts"
+	moduleName               = "hedera-mirror-rosetta"
+)
 
-	log.SetFormatter(&log.TextFormatter{ // Use logfmt for easy parsing by Loki
-		CallerPrettyfier: func(frame *runtime.Frame) (function string, file string) {
-			parts := strings.Split(frame.File, moduleName)
-			relativeFilepath := parts[len(parts)-1]
-			// remove function name, show file path relative to project root
-			return "", fmt.Sprintf("%s:%d", relativeFilepath, frame.Line)
-		},
-		DisableColors: true,
-		FullTimestamp: true,
-	})
-	log.SetLevel(logLevel)
-	log.SetOutput(os.Stdout)
-	log.SetReportCaller(logLevel >= log.DebugLevel)
-}
+// build info set via ldflags at build time
+var (
+	BuildTime = "unknown"
+	GitCommit = "unknown"
+	Version   = "development"
+)
 
 // newBlockchainOnlineRouter creates a Mux http.Handler from a collection
 // of server controllers, serving "online" mode.
 // ref: https://www.rosetta-api.org/docs/node_deployment.html#online-mode-endpoints
 func newBlockchainOnlineRouter(
 	asserter *rosettaAsserter.Asserter,
-	dbClient interfaces.DbClient,
+	backend interfaces.Backend,
 	network *rTypes.NetworkIdentifier,
 	rosettaConfig *config.Config,
 	version *rTypes.Version,
+	buildInfo middleware.BuildInfo,
+	errorBudget *middleware.ErrorBudget,
+	inFlight *middleware.InFlightRequests,
+	limiter *middleware.ConcurrencyLimiter,
+	redactor *redaction.Redactor,
 ) (http.Handler, error) {
-	accountRepo := persistence.NewAccountRepository(dbClient)
-	addressBookEntryRepo := persistence.NewAddressBookEntryRepository(dbClient)
-	blockRepo := persistence.NewBlockRepository(dbClient)
-	transactionRepo := persistence.NewTransactionRepository(dbClient)
+	accountRepo := backend.AccountRepository()
+	addressBookEntryRepo := backend.AddressBookEntryRepository()
+	blockRepo := backend.BlockRepository()
+	// warm up the cached genesis block, the requests fetch it lazily if the database isn't ready yet
+	if _, rErr := blockRepo.RetrieveGenesis(context.Background()); rErr != nil {
+		log.Warnf("Failed to warm up the genesis block: %s", rErr.Message)
+	}
+	fileDataRepo := backend.FileDataRepository()
+	scheduleRepo := backend.ScheduleRepository()
+	stakingRepo := backend.StakingRepository()
+	tokenRepo := backend.TokenRepository()
+	topicMessageRepo := backend.TopicMessageRepository()
+	transactionRepo := backend.TransactionRepository()
 
 	baseService := services.NewOnlineBaseService(blockRepo, transactionRepo)
 
-	networkAPIService := services.NewNetworkAPIService(baseService, addressBookEntryRepo, network, version)
+	networkAPIService, err := services.NewNetworkAPIService(
+		baseService,
+		addressBookEntryRepo,
+		fileDataRepo,
+		network,
+		rosettaConfig.NetworkParameters,
+		rosettaConfig.Shard,
+		rosettaConfig.Realm,
+		version,
+	)
+	if err != nil {
+		return nil, err
+	}
 	networkAPIController := server.NewNetworkAPIController(networkAPIService, asserter)
 
-	blockAPIService := services.NewBlockAPIService(accountRepo, baseService, rosettaConfig.Cache[config.EntityCacheKey])
-	blockAPIController := server.NewBlockAPIController(blockAPIService, asserter)
+	var blockCache interfaces.BlockCache
+	if rosettaConfig.Block.Cache.Enabled {
+		var err error
+		if blockCache, err = persistence.NewDiskBlockCache(rosettaConfig); err != nil {
+			return nil, err
+		}
+	}
+
+	blockAPIService := services.NewBlockAPIService(
+		accountRepo,
+		baseService,
+		blockCache,
+		rosettaConfig.Block,
+		rosettaConfig.Cache[config.EntityCacheKey],
+		rosettaConfig.Cache[config.TransactionCacheKey],
+	)
+	blockAPIController := middleware.NewBlockController(
+		blockAPIService,
+		asserter,
+		rosettaConfig.Block.FastEncoding,
+		redactor,
+	)
 
 	mempoolAPIService := services.NewMempoolAPIService()
 	mempoolAPIController := server.NewMempoolAPIController(mempoolAPIService, asserter)
 
+	serverSigner, err := signer.NewSigner(rosettaConfig.Signer)
+	if err != nil {
+		return nil, err
+	}
+
+	var submissionWatcher *services.SubmissionWatcher
+	if rosettaConfig.Construction.SubmissionWatcher.Enabled {
+		submissionWatcher = services.NewSubmissionWatcher(baseService, rosettaConfig.Construction.SubmissionWatcher)
+		go submissionWatcher.Run(context.Background())
+	}
+
+	var certificateVerifier interfaces.CertificateVerifier
+	if rosettaConfig.Submit.CertificatePinning {
+		certificateVerifier = services.NewCertificateVerifier(addressBookEntryRepo)
+	}
+
 	constructionAPIService, err := services.NewConstructionAPIService(
 		accountRepo,
 		baseService,
+		fileDataRepo,
 		network.Network,
 		rosettaConfig.Nodes,
+		rosettaConfig.Submit,
 		rosettaConfig.Shard,
 		rosettaConfig.Realm,
 		construction.NewTransactionConstructor(),
+		serverSigner,
+		submissionWatcher,
+		certificateVerifier,
 	)
 	if err != nil {
 		return nil, err
 	}
-	constructionAPIController := server.NewConstructionAPIController(constructionAPIService, asserter)
+	constructionAPIController := middleware.NewConstructionController(constructionAPIService, asserter)
 
 	accountAPIService := services.NewAccountAPIService(baseService, accountRepo, rosettaConfig.Shard, rosettaConfig.Realm)
-	accountAPIController := server.NewAccountAPIController(accountAPIService, asserter)
-	healthController, err := middleware.NewHealthController(rosettaConfig.Db)
+	accountAPIController := middleware.NewAccountController(accountAPIService, asserter, redactor)
+
+	if rosettaConfig.Grpc.Enabled {
+		if err = startGrpcServer(
+			accountAPIService,
+			blockAPIService,
+			network,
+			rosettaConfig.Grpc,
+			limiter,
+			rosettaConfig.Http.EndpointTimeouts,
+			redactor,
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	callAPIService := services.NewCallAPIService(
+		baseService,
+		accountRepo,
+		constructionAPIService,
+		scheduleRepo,
+		stakingRepo,
+		submissionWatcher,
+		tokenRepo,
+		topicMessageRepo,
+		rosettaConfig.Shard,
+		rosettaConfig.Realm,
+		rosettaConfig.Pagination.CursorTtl,
+	)
+	callAPIController := middleware.NewCallController(callAPIService, asserter, redactor)
+
+	searchAPIService := services.NewSearchAPIService(
+		baseService,
+		accountRepo,
+		blockAPIService,
+		rosettaConfig.Pagination.CursorTtl,
+		rosettaConfig.Shard,
+		rosettaConfig.Realm,
+	)
+	searchAPIController := middleware.NewSearchController(searchAPIService, asserter, redactor)
+
+	healthController, err := middleware.NewHealthController(rosettaConfig.Backend, rosettaConfig.Db)
 	metricsController := middleware.NewMetricsController()
 	if err != nil {
 		return nil, err
 	}
 
-	return server.NewRouter(
+	routers := []server.Router{
 		networkAPIController,
 		blockAPIController,
 		mempoolAPIController,
 		constructionAPIController,
 		accountAPIController,
+		callAPIController,
+		searchAPIController,
 		healthController,
 		metricsController,
-	), nil
+	}
+
+	if rosettaConfig.Stream.Enabled {
+		writeTimeout := rosettaConfig.Http.WriteTimeout
+		if streamConfig := rosettaConfig.Stream; writeTimeout > 0 &&
+			(streamConfig.MaxDuration <= 0 || streamConfig.MaxDuration >= writeTimeout) {
+			log.Warnf("Block stream max duration %s should be less than the http write timeout %s",
+				streamConfig.MaxDuration, writeTimeout)
+		}
+		routers = append(routers, middleware.NewBlockStreamController(blockRepo, rosettaConfig.Stream, redactor))
+	}
+
+	if rosettaConfig.Admin.Enabled {
+		infoHandler := middleware.NewInfoHandler(buildInfo, version, backend.SchemaVersionRepository())
+		adminController := middleware.NewAdminController(rosettaConfig.Admin, errorBudget, inFlight, infoHandler)
+		routers = append(routers, adminController)
+	}
+
+	return server.NewRouter(routers...), nil
 }
 
 // newBlockchainOfflineRouter creates a Mux http.Handler from a collection
@@ -136,45 +254,290 @@ func newBlockchainOfflineRouter(
 	network *rTypes.NetworkIdentifier,
 	rosettaConfig *config.Config,
 	version *rTypes.Version,
+	buildInfo middleware.BuildInfo,
+	errorBudget *middleware.ErrorBudget,
+	inFlight *middleware.InFlightRequests,
 ) (http.Handler, error) {
 	baseService := services.NewOfflineBaseService()
 
+	serverSigner, err := signer.NewSigner(rosettaConfig.Signer)
+	if err != nil {
+		return nil, err
+	}
+
 	constructionAPIService, err := services.NewConstructionAPIService(
 		nil,
 		baseService,
+		nil,
 		network.Network,
 		rosettaConfig.Nodes,
+		rosettaConfig.Submit,
 		rosettaConfig.Shard,
 		rosettaConfig.Realm,
 		construction.NewTransactionConstructor(),
+		serverSigner,
+		nil,
+		nil,
 	)
 	if err != nil {
 		return nil, err
 	}
-	constructionAPIController := server.NewConstructionAPIController(constructionAPIService, asserter)
-	healthController, err := middleware.NewHealthController(rosettaConfig.Db)
+	constructionAPIController := middleware.NewConstructionController(constructionAPIService, asserter)
+	healthController, err := middleware.NewHealthController(rosettaConfig.Backend, rosettaConfig.Db)
 	if err != nil {
 		return nil, err
 	}
 
 	metricsController := middleware.NewMetricsController()
-	networkAPIService := services.NewNetworkAPIService(baseService, nil, network, version)
+	networkAPIService, err := services.NewNetworkAPIService(
+		baseService,
+		nil,
+		nil,
+		network,
+		rosettaConfig.NetworkParameters,
+		rosettaConfig.Shard,
+		rosettaConfig.Realm,
+		version,
+	)
+	if err != nil {
+		return nil, err
+	}
 	networkAPIController := server.NewNetworkAPIController(networkAPIService, asserter)
 
-	return server.NewRouter(constructionAPIController, healthController, metricsController, networkAPIController), nil
+	routers := []server.Router{
+		constructionAPIController,
+		healthController,
+		metricsController,
+		networkAPIController,
+	}
+
+	if rosettaConfig.Admin.Enabled {
+		infoHandler := middleware.NewInfoHandler(buildInfo, version, nil)
+		adminController := middleware.NewAdminController(rosettaConfig.Admin, errorBudget, inFlight, infoHandler)
+		routers = append(routers, adminController)
+	}
+
+	return server.NewRouter(routers...), nil
+}
+
+// discoverNetwork replaces the configured network name and nodes with the ones discovered from the database. The
+// configured values are kept if the discovery fails, e.g., the importer hasn't ingested the address book yet
+func discoverNetwork(backend interfaces.Backend, rosettaConfig *config.Config) {
+	discovered, err := services.DiscoverNetwork(
+		context.Background(),
+		backend.AddressBookEntryRepository(),
+		backend.BlockRepository(),
+	)
+	if err != nil {
+		log.Warnf("Failed to discover network, use the configured network %s: %s %v", rosettaConfig.Network,
+			err.Message, err.Details)
+		return
+	}
+
+	rosettaConfig.Network = discovered.Network
+	rosettaConfig.Nodes = discovered.Nodes
+}
+
+// checkLedgerId exits if the ledger id of the network in the database doesn't match the configured ledger id, so the
+// data of one network is never served under the network identifier of another
+func checkLedgerId(backend interfaces.Backend, rosettaConfig *config.Config) {
+	err := services.CheckLedgerId(
+		context.Background(),
+		backend.FileDataRepository(),
+		strings.ToLower(rosettaConfig.Network),
+		rosettaConfig.NetworkParameters,
+		rosettaConfig.Shard,
+		rosettaConfig.Realm,
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// checkSchemaVersion exits if the database schema is older than the minimum version required by the enabled features.
+// The check is skipped if the schema version can't be read, e.g., the importer hasn't run the migrations yet
+func checkSchemaVersion(backend interfaces.Backend, rosettaConfig *config.Config) {
+	schemaVersion, rErr := backend.SchemaVersionRepository().RetrieveLatest(context.Background())
+	if rErr != nil {
+		log.Warnf("Failed to retrieve the schema version, skip the minimum schema version check: %s", rErr.Message)
+		return
+	}
+
+	if err := services.CheckSchemaVersion(schemaVersion, rosettaConfig); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Infof("Database schema version %s", schemaVersion)
+}
+
+// runBootstrapBalances exports the balances of all accounts at a block to a rosetta-cli bootstrap balances file
+func runBootstrapBalances(rosettaConfig *config.Config, args []string) error {
+	flags := flag.NewFlagSet(bootstrapBalancesCommand, flag.ExitOnError)
+	index := flags.Int64("index", -1, "The index of the block to export the balances at, the latest block if negative")
+	output := flags.String("output", "bootstrap_balances.json", "The bootstrap balances file to write")
+	_ = flags.Parse(args)
+
+	var blockIndex *int64
+	if *index >= 0 {
+		blockIndex = index
+	}
+
+	backend, err := persistence.NewBackend(context.Background(), rosettaConfig)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+
+	block, count, rErr := services.ExportBootstrapBalances(
+		context.Background(),
+		backend.AccountRepository(),
+		backend.BlockRepository(),
+		blockIndex,
+		file,
+	)
+	if closeErr := file.Close(); closeErr != nil && rErr == nil {
+		return closeErr
+	}
+	if rErr != nil {
+		return fmt.Errorf("failed to export bootstrap balances: %s %v", rErr.Message, rErr.Details)
+	}
+
+	log.Infof("Wrote %d balances at the end of block %d to %s, run check:data with it as bootstrap_balances and "+
+		"start_index %d", count, block.Index, *output, block.Index+1)
+	return nil
+}
+
+// runExemptAccounts writes the accounts with known non-transactional balance changes to a rosetta-cli exempt accounts
+// file
+func runExemptAccounts(rosettaConfig *config.Config, args []string) error {
+	flags := flag.NewFlagSet(exemptAccountsCommand, flag.ExitOnError)
+	genesis := flags.Bool("genesis", false, "Include all accounts with a balance in the genesis balance snapshot")
+	output := flags.String("output", "exempt_accounts.json", "The exempt accounts file to write")
+	_ = flags.Parse(args)
+
+	backend, err := persistence.NewBackend(context.Background(), rosettaConfig)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+
+	count, rErr := services.ExportExemptAccounts(
+		context.Background(),
+		backend.AccountRepository(),
+		rosettaConfig.Shard,
+		rosettaConfig.Realm,
+		rosettaConfig.SystemAccounts,
+		*genesis,
+		file,
+	)
+	if closeErr := file.Close(); closeErr != nil && rErr == nil {
+		return closeErr
+	}
+	if rErr != nil {
+		return fmt.Errorf("failed to export exempt accounts: %s %v", rErr.Message, rErr.Details)
+	}
+
+	log.Infof("Wrote %d exempt accounts to %s, run check:data with it as exempt_accounts", count, *output)
+	return nil
+}
+
+// startGrpcServer starts the gRPC server of the data API in the background
+func startGrpcServer(
+	accountAPIService server.AccountAPIServicer,
+	blockAPIService server.BlockAPIServicer,
+	network *rTypes.NetworkIdentifier,
+	grpcConfig config.Grpc,
+	limiter *middleware.ConcurrencyLimiter,
+	endpointTimeouts map[string]time.Duration,
+	redactor *redaction.Redactor,
+) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", grpcConfig.Port))
+	if err != nil {
+		return err
+	}
+
+	grpcServer := rpc.NewServer(accountAPIService, blockAPIService, network, limiter, endpointTimeouts, redactor)
+	go func() {
+		log.Fatal(grpcServer.Serve(listener))
+	}()
+
+	log.Infof("Serving gRPC data API on port %d", grpcConfig.Port)
+	return nil
+}
+
+// startNotifier starts the notifier of the transactions of the tracked accounts in the background
+func startNotifier(
+	backend interfaces.Backend,
+	network *rTypes.NetworkIdentifier,
+	rosettaConfig *config.Config,
+	redactor *redaction.Redactor,
+) {
+	baseService := services.NewOnlineBaseService(backend.BlockRepository(), backend.TransactionRepository())
+	notifier := services.NewNotifier(
+		backend.AccountRepository(),
+		baseService,
+		rosettaConfig.Notifier,
+		network,
+		redactor,
+	)
+	go notifier.Run(context.Background())
 }
 
 func main() {
-	configLogger("info")
+	logging.Configure(config.Log{Level: "info"})
 
 	rosettaConfig, err := config.LoadConfig()
 	if err != nil {
 		log.Fatalf("Failed to load config: %s", err)
 	}
 
-	log.Infof("%s version %s, rosetta api version %s", moduleName, Version, rTypes.RosettaAPIVersion)
+	buildInfo := middleware.BuildInfo{BuildTime: BuildTime, GitCommit: GitCommit, Version: Version}
+	log.Infof("%s version %s (commit %s, built at %s), rosetta api version %s", moduleName, Version, GitCommit,
+		BuildTime, rTypes.RosettaAPIVersion)
+
+	logging.Configure(rosettaConfig.Log)
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case bootstrapBalancesCommand:
+			err = runBootstrapBalances(rosettaConfig, os.Args[2:])
+		case exemptAccountsCommand:
+			err = runExemptAccounts(rosettaConfig, os.Args[2:])
+		default:
+			log.Fatalf("Unknown command %s", os.Args[1])
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	var backend interfaces.Backend
+	if rosettaConfig.Online {
+		if backend, err = persistence.NewBackend(context.Background(), rosettaConfig); err != nil {
+			log.Fatal(err)
+		}
+		checkSchemaVersion(backend, rosettaConfig)
 
-	configLogger(rosettaConfig.Log.Level)
+		if rosettaConfig.AutoDiscovery {
+			discoverNetwork(backend, rosettaConfig)
+		}
+
+		checkLedgerId(backend, rosettaConfig)
+
+		if indexRepo := backend.IndexRepository(); indexRepo != nil {
+			indexAdvisor := services.NewIndexAdvisor(indexRepo, rosettaConfig.Db.IndexCheckInterval)
+			go indexAdvisor.Run(context.Background())
+		}
+	}
 
 	network := &rTypes.NetworkIdentifier{
 		Blockchain: types.Blockchain,
@@ -191,13 +554,30 @@ func main() {
 		RosettaVersion:    rTypes.RosettaAPIVersion,
 		NodeVersion:       rosettaConfig.NodeVersion,
 		MiddlewareVersion: &Version,
+		Metadata:          buildInfo.ToMetadata(),
+	}
+
+	if err = types.SetOperationTypeNaming(rosettaConfig.OperationTypeNaming); err != nil {
+		log.Fatal(err)
+	}
+
+	if err = types.SetAccountIdentifierFormat(rosettaConfig.AccountIdentifierFormat); err != nil {
+		log.Fatal(err)
+	}
+
+	if err = types.SetHbarCurrency(rosettaConfig.HbarCurrency); err != nil {
+		log.Fatal(err)
+	}
+
+	if err = services.SetFrozenTime(rosettaConfig.Construction.FrozenTime); err != nil {
+		log.Fatal(err)
 	}
 
 	asserter, err := rosettaAsserter.NewServer(
-		types.SupportedOperationTypes,
+		types.ToOperationTypeNames(types.SupportedOperationTypes),
 		true,
 		[]*rTypes.NetworkIdentifier{network},
-		nil,
+		types.SupportedCallMethods,
 		false,
 		"",
 	)
@@ -205,19 +585,57 @@ func main() {
 		log.Fatal(err)
 	}
 
+	errorBudget := middleware.NewErrorBudget(rosettaConfig.Slo)
+	if errorBudget != nil {
+		go errorBudget.Run(context.Background())
+	}
+
+	usageTracker := middleware.NewUsageTracker(rosettaConfig.Usage)
+	if usageTracker != nil {
+		go usageTracker.Run(context.Background())
+	}
+
+	var inFlight *middleware.InFlightRequests
+	if rosettaConfig.Admin.Enabled {
+		inFlight = middleware.NewInFlightRequests()
+	}
+
+	limiter := middleware.NewConcurrencyLimiter(rosettaConfig.Http.MaxConcurrentRequests)
+	redactor := redaction.NewRedactor(rosettaConfig.Redaction)
 	var router http.Handler
 
 	if rosettaConfig.Online {
-		dbClient := db.ConnectToDb(rosettaConfig.Db)
-
-		router, err = newBlockchainOnlineRouter(asserter, dbClient, network, rosettaConfig, version)
+		router, err = newBlockchainOnlineRouter(
+			asserter,
+			backend,
+			network,
+			rosettaConfig,
+			version,
+			buildInfo,
+			errorBudget,
+			inFlight,
+			limiter,
+			redactor,
+		)
 		if err != nil {
 			log.Fatal(err)
 		}
 
+		if rosettaConfig.Notifier.Enabled {
+			startNotifier(backend, network, rosettaConfig, redactor)
+		}
+
 		log.Info("Serving Rosetta API in ONLINE mode")
 	} else {
-		router, err = newBlockchainOfflineRouter(asserter, network, rosettaConfig, version)
+		router, err = newBlockchainOfflineRouter(
+			asserter,
+			network,
+			rosettaConfig,
+			version,
+			buildInfo,
+			errorBudget,
+			inFlight,
+		)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -225,9 +643,23 @@ func main() {
 		log.Info("Serving Rosetta API in OFFLINE mode")
 	}
 
-	metricsMiddleware := middleware.MetricsMiddleware(router)
-	tracingMiddleware := middleware.TracingMiddleware(metricsMiddleware)
-	corsMiddleware := server.CorsMiddleware(tracingMiddleware)
+	timeoutMiddleware := middleware.EndpointTimeoutMiddleware(router, rosettaConfig.Http.EndpointTimeouts)
+	limitMiddleware := middleware.ConcurrencyLimitMiddleware(
+		timeoutMiddleware,
+		limiter,
+		rosettaConfig.Http.RetryAfter,
+	)
+	// the limiter is inside the metrics middleware so the rejected requests are counted in the metrics
+	metricsMiddleware := middleware.MetricsMiddleware(limitMiddleware, router)
+	errorBudgetMiddleware := middleware.ErrorBudgetMiddleware(metricsMiddleware, errorBudget)
+	inFlightMiddleware := middleware.InFlightMiddleware(errorBudgetMiddleware, inFlight)
+	sqlTraceMiddleware := middleware.SqlTraceMiddleware(inFlightMiddleware, rosettaConfig.Admin)
+	serverSigningMiddleware := middleware.ServerSigningMiddleware(sqlTraceMiddleware, rosettaConfig.Admin)
+	usageMiddleware := middleware.UsageMiddleware(serverSigningMiddleware, usageTracker)
+	tracingMiddleware := middleware.TracingMiddleware(usageMiddleware)
+	disconnectMiddleware := middleware.ClientDisconnectMiddleware(tracingMiddleware)
+	specVersionMiddleware := middleware.SpecVersionMiddleware(disconnectMiddleware, rosettaConfig.Http.SpecVersions)
+	corsMiddleware := server.CorsMiddleware(specVersionMiddleware)
 	httpServer := &http.Server{
 		Addr:              fmt.Sprintf(":%d", rosettaConfig.Port),
 		Handler:           corsMiddleware,
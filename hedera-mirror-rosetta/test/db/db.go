@@ -34,7 +34,7 @@ import (
 
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
 	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
-	_ "github.com/lib/pq"
+	_ "github.com/jackc/pgx/v4/stdlib"
 	"github.com/ory/dockertest/v3"
 	"github.com/ory/dockertest/v3/docker"
 	log "github.com/sirupsen/logrus"
@@ -163,7 +163,7 @@ func SetupDb(migrate bool) DbResource {
 
 	if err = pool.Retry(func() error {
 		var err error
-		db, err = sql.Open("postgres", dbParams.toDsn())
+		db, err = sql.Open("pgx", dbParams.toDsn())
 		if err != nil {
 			log.Errorf("%s", err)
 			return err
@@ -38,16 +38,41 @@ func (b *EntityBuilder) Alias(alias []byte) *EntityBuilder {
 	return b
 }
 
+func (b *EntityBuilder) AutoRenewPeriod(autoRenewPeriod int64) *EntityBuilder {
+	b.entity.AutoRenewPeriod = &autoRenewPeriod
+	return b
+}
+
+func (b *EntityBuilder) Balance(balance int64) *EntityBuilder {
+	b.entity.Balance = &balance
+	return b
+}
+
+func (b *EntityBuilder) DeclineReward(declineReward bool) *EntityBuilder {
+	b.entity.DeclineReward = declineReward
+	return b
+}
+
 func (b *EntityBuilder) Deleted(deleted bool) *EntityBuilder {
 	b.entity.Deleted = &deleted
 	return b
 }
 
+func (b *EntityBuilder) EvmAddress(evmAddress []byte) *EntityBuilder {
+	b.entity.EvmAddress = evmAddress
+	return b
+}
+
 func (b *EntityBuilder) Historical(historical bool) *EntityBuilder {
 	b.historical = historical
 	return b
 }
 
+func (b *EntityBuilder) Key(key []byte) *EntityBuilder {
+	b.entity.Key = key
+	return b
+}
+
 func (b *EntityBuilder) ModifiedAfter(delta int64) *EntityBuilder {
 	b.entity.TimestampRange = getTimestampRangeWithLower(*b.entity.CreatedTimestamp + delta)
 	return b
@@ -58,6 +83,17 @@ func (b *EntityBuilder) ModifiedTimestamp(timestamp int64) *EntityBuilder {
 	return b
 }
 
+func (b *EntityBuilder) StakedAccountId(accountId int64) *EntityBuilder {
+	b.entity.StakedAccountId = &accountId
+	return b
+}
+
+func (b *EntityBuilder) StakedNodeId(nodeId, stakePeriodStart int64) *EntityBuilder {
+	b.entity.StakedNodeId = &nodeId
+	b.entity.StakePeriodStart = &stakePeriodStart
+	return b
+}
+
 func (b *EntityBuilder) TimestampRange(lowerInclusive, upperExclusive int64) *EntityBuilder {
 	b.entity.TimestampRange = pgtype.Int8range{
 		Lower:     pgtype.Int8{Int: lowerInclusive, Status: pgtype.Present},
@@ -46,6 +46,17 @@ func (b *NftBuilder) ModifiedTimestamp(timestamp int64) *NftBuilder {
 	return b
 }
 
+func (b *NftBuilder) Metadata(metadata []byte) *NftBuilder {
+	b.nft.Metadata = metadata
+	return b
+}
+
+func (b *NftBuilder) Spender(spender int64) *NftBuilder {
+	account := domain.MustDecodeEntityId(spender)
+	b.nft.Spender = &account
+	return b
+}
+
 func (b *NftBuilder) Persist() domain.Nft {
 	b.dbClient.GetDb().Create(&b.nft)
 	return b.nft
@@ -47,6 +47,21 @@ func (b *TransactionBuilder) Result(result int16) *TransactionBuilder {
 	return b
 }
 
+func (b *TransactionBuilder) Scheduled(scheduled bool) *TransactionBuilder {
+	b.transaction.Scheduled = scheduled
+	return b
+}
+
+func (b *TransactionBuilder) TransactionBytes(transactionBytes []byte) *TransactionBuilder {
+	b.transaction.TransactionBytes = transactionBytes
+	return b
+}
+
+func (b *TransactionBuilder) TransactionHash(hash []byte) *TransactionBuilder {
+	b.transaction.TransactionHash = hash
+	return b
+}
+
 func (b *TransactionBuilder) Type(txnType int16) *TransactionBuilder {
 	b.transaction.Type = txnType
 	return b
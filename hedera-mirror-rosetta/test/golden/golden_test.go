@@ -0,0 +1,226 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package golden
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	rosettaAsserter "github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/server"
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/config"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/db"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/interfaces"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/persistence"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/services"
+	tdb "github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/test/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	casesFile     = "cases.json"
+	goldenSuffix  = ".golden.json"
+	seedFile      = "seed.sql"
+	testdataDir   = "testdata"
+	cacheMaxSize  = 100
+	systemRealm   = 0
+	systemShard   = 0
+	networkName   = "testnet"
+	nodeVersion   = "0.0.0"
+	middlewareVer = "0.0.0"
+)
+
+var (
+	update = flag.Bool("update", false, "update the golden files with the actual responses")
+
+	dbResource tdb.DbResource
+	dbClient   interfaces.DbClient
+	network    = &rTypes.NetworkIdentifier{Blockchain: types.Blockchain, Network: networkName}
+)
+
+// goldenCase is a request to replay against a fixture database, its response body is compared to the golden file
+// named after the case
+type goldenCase struct {
+	Name    string          `json:"name"`
+	Path    string          `json:"path"`
+	Request json.RawMessage `json:"request"`
+	Status  int             `json:"status"`
+}
+
+func (c goldenCase) getStatus() int {
+	if c.Status == 0 {
+		return http.StatusOK
+	}
+	return c.Status
+}
+
+// TestGoldenResponses seeds the database with each fixture under testdata, and compares the full response of each
+// case of the fixture to its golden file. Run with -update to regenerate the golden files after an intended change
+func TestGoldenResponses(t *testing.T) {
+	fixtures, err := ioutil.ReadDir(testdataDir)
+	require.NoError(t, err)
+
+	for _, fixture := range fixtures {
+		if !fixture.IsDir() {
+			continue
+		}
+
+		fixtureDir := filepath.Join(testdataDir, fixture.Name())
+		t.Run(fixture.Name(), func(t *testing.T) {
+			seedDb(t, fixtureDir)
+			router := newRouter(t)
+
+			for _, tc := range readCases(t, fixtureDir) {
+				tc := tc
+				t.Run(tc.Name, func(t *testing.T) {
+					// when
+					status, actual := post(router, tc.Path, tc.Request)
+
+					// then
+					assert.Equal(t, tc.getStatus(), status)
+					goldenFile := filepath.Join(fixtureDir, tc.Name+goldenSuffix)
+					if *update {
+						writeGoldenFile(t, goldenFile, actual)
+						return
+					}
+
+					expected, err := ioutil.ReadFile(goldenFile)
+					require.NoError(t, err, "golden file is missing, run the test with -update to create it")
+					assert.JSONEq(t, string(expected), string(actual))
+				})
+			}
+		})
+	}
+}
+
+// newRouter creates the online data API router backed by the fixture database. A new router is created for each
+// fixture, so the cached genesis block of one fixture doesn't leak into another
+func newRouter(t *testing.T) http.Handler {
+	asserter, err := rosettaAsserter.NewServer(
+		types.ToOperationTypeNames(types.SupportedOperationTypes),
+		true,
+		[]*rTypes.NetworkIdentifier{network},
+		types.SupportedCallMethods,
+		false,
+		"",
+	)
+	require.NoError(t, err)
+
+	middlewareVersion := middlewareVer
+	version := &rTypes.Version{
+		RosettaVersion:    rTypes.RosettaAPIVersion,
+		NodeVersion:       nodeVersion,
+		MiddlewareVersion: &middlewareVersion,
+	}
+
+	accountRepo := persistence.NewAccountRepository(dbClient, config.BalanceReplay{})
+	addressBookEntryRepo := persistence.NewAddressBookEntryRepository(dbClient)
+	blockRepo := persistence.NewBlockRepository(dbClient)
+	transactionRepo := persistence.NewTransactionRepository(
+		dbClient,
+		config.SystemAccounts{},
+		false,
+		false,
+		config.DbRangeSplit{},
+	)
+	baseService := services.NewOnlineBaseService(blockRepo, transactionRepo)
+	cacheConfig := config.Cache{MaxSize: cacheMaxSize}
+
+	accountAPIService := services.NewAccountAPIService(baseService, accountRepo, systemShard, systemRealm)
+	blockAPIService := services.NewBlockAPIService(
+		accountRepo,
+		baseService,
+		nil,
+		config.Block{},
+		cacheConfig,
+		cacheConfig,
+	)
+	networkAPIService, _ := services.NewNetworkAPIService(
+		baseService,
+		addressBookEntryRepo,
+		persistence.NewFileDataRepository(dbClient),
+		network,
+		config.NetworkParameters{},
+		systemShard,
+		systemRealm,
+		version,
+	)
+
+	return server.NewRouter(
+		server.NewAccountAPIController(accountAPIService, asserter),
+		server.NewBlockAPIController(blockAPIService, asserter),
+		server.NewNetworkAPIController(networkAPIService, asserter),
+	)
+}
+
+func post(router http.Handler, path string, request []byte) (int, []byte) {
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, path, bytes.NewReader(request)))
+	return recorder.Code, recorder.Body.Bytes()
+}
+
+func readCases(t *testing.T, fixtureDir string) []goldenCase {
+	data, err := ioutil.ReadFile(filepath.Join(fixtureDir, casesFile))
+	require.NoError(t, err)
+
+	cases := make([]goldenCase, 0)
+	require.NoError(t, json.Unmarshal(data, &cases))
+	return cases
+}
+
+// seedDb cleans up the database and loads the seed script of the fixture
+func seedDb(t *testing.T, fixtureDir string) {
+	script, err := ioutil.ReadFile(filepath.Join(fixtureDir, seedFile))
+	require.NoError(t, err)
+
+	tdb.CleanupDb(dbResource.GetDb())
+	_, err = dbResource.GetDb().Exec(string(script))
+	require.NoError(t, err)
+}
+
+func writeGoldenFile(t *testing.T, filename string, actual []byte) {
+	var indented bytes.Buffer
+	require.NoError(t, json.Indent(&indented, actual, "", "  "))
+	require.NoError(t, ioutil.WriteFile(filename, indented.Bytes(), 0600))
+}
+
+func TestMain(m *testing.M) {
+	code := 0
+
+	dbResource = tdb.SetupDb(true)
+	dbClient = db.NewDbClient(dbResource.GetGormDb(), 0, config.DbRetry{})
+	defer func() {
+		tdb.TearDownDb(dbResource)
+		os.Exit(code)
+	}()
+
+	code = m.Run()
+}
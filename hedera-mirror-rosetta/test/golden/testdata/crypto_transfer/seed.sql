@@ -0,0 +1,47 @@
+-- a network with the genesis account balance file in the middle of record file 1, a record file before the genesis,
+-- and a crypto transfer from 0.0.1001 to 0.0.1002 in record file 2
+insert into account_balance_file (consensus_timestamp, count, file_hash, load_end, load_start, name, node_account_id,
+                                  time_offset)
+values (1000000000, 4, 'genesis_balance_file_hash', 1, 1, '1970-01-01T00_00_01Z_Balances.pb.gz', 3, 0);
+
+insert into account_balance (account_id, balance, consensus_timestamp)
+values (3, 1000, 1000000000),
+       (98, 2000, 1000000000),
+       (1001, 500000, 1000000000),
+       (1002, 100000, 1000000000);
+
+insert into record_file (consensus_start, consensus_end, count, digest_algorithm, file_hash, hapi_version_major,
+                         hapi_version_minor, hapi_version_patch, hash, index, load_end, load_start, name,
+                         node_account_id, prev_hash, version)
+values (500000000, 900000000, 0, 0, 'record_file_0', 0, 25, 0, 'a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0', 0, 1, 1, '1970-01-01T00_00_00.5Z.rcd', 3,
+        '000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000', 5),
+       (950000000, 1500000000, 0, 0, 'record_file_1', 0, 25, 0, 'b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1', 1, 1, 1, '1970-01-01T00_00_00.95Z.rcd', 3,
+        'a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0', 5),
+       (1500000001, 2500000000, 1, 0, 'record_file_2', 0, 25, 0, 'c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2c2', 2, 1, 1, '1970-01-01T00_00_01.500000001Z.rcd',
+        3, 'b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1b1', 5);
+
+insert into address_book (start_consensus_timestamp, end_consensus_timestamp, file_id, node_count, file_data)
+values (1, null, 101, 1, ''::bytea);
+
+insert into address_book_entry (consensus_timestamp, memo, public_key, node_id, node_account_id, node_cert_hash,
+                                description, stake)
+values (1, '0.0.3', 'public_key', 0, 3, ''::bytea, 'node 0', 0);
+
+insert into address_book_service_endpoint (consensus_timestamp, ip_address_v4, node_id, port)
+values (1, '10.0.0.1', 0, 50211);
+
+insert into transaction (consensus_timestamp, charged_tx_fee, entity_id, errata, initial_balance, max_fee, memo,
+                         node_account_id, nonce, parent_consensus_timestamp, payer_account_id, result, scheduled,
+                         transaction_bytes, transaction_hash, type, valid_duration_seconds, valid_start_ns)
+values (2000000000, 10, null, null, 0, 100, null, 3, 0, 0, 1001, 22, false, null,
+        decode('c0ffeec0ffeec0ffeec0ffeec0ffeec0ffeec0ffeec0ffeec0ffeec0ffeec0ffeec0ffeec0ffeec0ffeec0ffeec0ffee', 'hex'), 14, 120, 1999999990);
+
+insert into crypto_transfer (amount, consensus_timestamp, entity_id, errata, payer_account_id)
+values (-110, 2000000000, 1001, null, 1001),
+       (100, 2000000000, 1002, null, 1001),
+       (2, 2000000000, 3, null, 1001),
+       (8, 2000000000, 98, null, 1001);
+
+insert into non_fee_transfer (amount, consensus_timestamp, entity_id, payer_account_id)
+values (-100, 2000000000, 1001, 1001),
+       (100, 2000000000, 1002, 1001);
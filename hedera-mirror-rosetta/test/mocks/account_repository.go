@@ -28,12 +28,23 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
-var NilError *rTypes.Error
+var (
+	NilAccountLifecycle *types.AccountLifecycle
+	NilError            *rTypes.Error
+)
 
 type MockAccountRepository struct {
 	mock.Mock
 }
 
+func (m *MockAccountRepository) FindAccountsByAlias(ctx context.Context, alias, evmAddress []byte) (
+	types.AliasAccounts,
+	*rTypes.Error,
+) {
+	args := m.Called(alias, evmAddress)
+	return args.Get(0).(types.AliasAccounts), args.Get(1).(*rTypes.Error)
+}
+
 func (m *MockAccountRepository) GetAccountAlias(ctx context.Context, accountId types.AccountId) (
 	types.AccountId,
 	*rTypes.Error,
@@ -50,11 +61,72 @@ func (m *MockAccountRepository) GetAccountId(ctx context.Context, accountId type
 	return args.Get(0).(types.AccountId), args.Get(1).(*rTypes.Error)
 }
 
+func (m *MockAccountRepository) GetAccountLifecycle(ctx context.Context, accountId types.AccountId) (
+	*types.AccountLifecycle,
+	*rTypes.Error,
+) {
+	args := m.Called()
+	return args.Get(0).(*types.AccountLifecycle), args.Get(1).(*rTypes.Error)
+}
+
+func (m *MockAccountRepository) GetAccountInfo(ctx context.Context, accountId types.AccountId) (
+	*types.AccountInfo,
+	*rTypes.Error,
+) {
+	args := m.Called()
+	return args.Get(0).(*types.AccountInfo), args.Get(1).(*rTypes.Error)
+}
+
 func (m *MockAccountRepository) RetrieveBalanceAtBlock(
 	ctx context.Context,
 	accountId types.AccountId,
 	consensusEnd int64,
-) (types.AmountSlice, string, *rTypes.Error) {
+) (types.AmountSlice, string, []byte, *rTypes.Error) {
+	args := m.Called()
+	return args.Get(0).(types.AmountSlice), args.Get(1).(string), args.Get(2).([]byte), args.Get(3).(*rTypes.Error)
+}
+
+func (m *MockAccountRepository) RetrieveBalanceReconciliation(
+	ctx context.Context,
+	accountId int64,
+	timestamp int64,
+	limit int,
+) (*types.BalanceReconciliation, *rTypes.Error) {
+	args := m.Called(accountId, timestamp, limit)
+	return args.Get(0).(*types.BalanceReconciliation), args.Get(1).(*rTypes.Error)
+}
+
+func (m *MockAccountRepository) RetrieveAllBalancesAtBlock(ctx context.Context, consensusEnd int64) (
+	map[int64]types.AmountSlice,
+	*rTypes.Error,
+) {
+	args := m.Called()
+	return args.Get(0).(map[int64]types.AmountSlice), args.Get(1).(*rTypes.Error)
+}
+
+func (m *MockAccountRepository) RetrieveGenesisAccounts(ctx context.Context, minAccountId, maxAccountId int64) (
+	[]int64,
+	*rTypes.Error,
+) {
+	args := m.Called()
+	return args.Get(0).([]int64), args.Get(1).(*rTypes.Error)
+}
+
+func (m *MockAccountRepository) RetrieveHbarBalancesAtBlock(
+	ctx context.Context,
+	accountIds []int64,
+	consensusEnd int64,
+) (map[int64]types.HbarAmount, *rTypes.Error) {
+	args := m.Called()
+	return args.Get(0).(map[int64]types.HbarAmount), args.Get(1).(*rTypes.Error)
+}
+
+func (m *MockAccountRepository) RetrieveTokenBalancesAtBlock(
+	ctx context.Context,
+	accountId int64,
+	tokenIds []int64,
+	consensusEnd int64,
+) (types.AmountSlice, *rTypes.Error) {
 	args := m.Called()
-	return args.Get(0).(types.AmountSlice), args.Get(1).(string), args.Get(2).(*rTypes.Error)
+	return args.Get(0).(types.AmountSlice), args.Get(1).(*rTypes.Error)
 }
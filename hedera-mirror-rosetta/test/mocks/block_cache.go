@@ -0,0 +1,46 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package mocks
+
+import (
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/stretchr/testify/mock"
+)
+
+var NilBlockResponse *rTypes.BlockResponse
+
+type MockBlockCache struct {
+	mock.Mock
+}
+
+func (m *MockBlockCache) Get(index int64) (*rTypes.BlockResponse, bool) {
+	args := m.Called(index)
+	return args.Get(0).(*rTypes.BlockResponse), args.Bool(1)
+}
+
+func (m *MockBlockCache) Set(index int64, response *rTypes.BlockResponse) {
+	m.Called(index, response)
+}
+
+func (m *MockBlockCache) Close() error {
+	args := m.Called()
+	return args.Error(0)
+}
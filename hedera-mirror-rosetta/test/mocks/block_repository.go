@@ -28,7 +28,10 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
-var NilBlock *types.Block
+var (
+	NilBlock      *types.Block
+	NilRecordFile *types.RecordFile
+)
 
 type MockBlockRepository struct {
 	mock.Mock
@@ -52,6 +55,18 @@ func (m *MockBlockRepository) FindByIndex(ctx context.Context, index int64) (*ty
 	return args.Get(0).(*types.Block), args.Get(1).(*rTypes.Error)
 }
 
+func (m *MockBlockRepository) FindByTimestamp(ctx context.Context, timestamp int64) (*types.Block, *rTypes.Error) {
+	return m.retrieveBlock(m.Called(timestamp))
+}
+
+func (m *MockBlockRepository) FindRecordFileByIndex(ctx context.Context, index int64) (
+	*types.RecordFile,
+	*rTypes.Error,
+) {
+	args := m.Called(index)
+	return args.Get(0).(*types.RecordFile), args.Get(1).(*rTypes.Error)
+}
+
 func (m *MockBlockRepository) RetrieveGenesis(ctx context.Context) (*types.Block, *rTypes.Error) {
 	return m.retrieveBlock(m.Called())
 }
@@ -60,6 +75,10 @@ func (m *MockBlockRepository) RetrieveLatest(ctx context.Context) (*types.Block,
 	return m.retrieveBlock(m.Called())
 }
 
+func (m *MockBlockRepository) RetrieveOldest(ctx context.Context) (*types.Block, *rTypes.Error) {
+	return m.retrieveBlock(m.Called())
+}
+
 func (m *MockBlockRepository) retrieveBlock(args mock.Arguments) (*types.Block, *rTypes.Error) {
 	return args.Get(0).(*types.Block), args.Get(1).(*rTypes.Error)
 }
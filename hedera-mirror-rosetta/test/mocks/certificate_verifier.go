@@ -0,0 +1,37 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package mocks
+
+import (
+	"context"
+
+	"github.com/hashgraph/hedera-sdk-go/v2"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCertificateVerifier struct {
+	mock.Mock
+}
+
+func (m *MockCertificateVerifier) Verify(ctx context.Context, nodeAccountId hedera.AccountID, rawCerts [][]byte) error {
+	args := m.Called(ctx, nodeAccountId, rawCerts)
+	return args.Error(0)
+}
@@ -0,0 +1,69 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package mocks
+
+import (
+	"context"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/stretchr/testify/mock"
+)
+
+var NilAccountBalanceResponse *rTypes.AccountBalanceResponse
+
+type MockAccountAPIService struct {
+	mock.Mock
+}
+
+func (m *MockAccountAPIService) AccountBalance(ctx context.Context, request *rTypes.AccountBalanceRequest) (
+	*rTypes.AccountBalanceResponse,
+	*rTypes.Error,
+) {
+	args := m.Called(ctx, request)
+	return args.Get(0).(*rTypes.AccountBalanceResponse), args.Get(1).(*rTypes.Error)
+}
+
+func (m *MockAccountAPIService) AccountCoins(ctx context.Context, request *rTypes.AccountCoinsRequest) (
+	*rTypes.AccountCoinsResponse,
+	*rTypes.Error,
+) {
+	args := m.Called(ctx, request)
+	return args.Get(0).(*rTypes.AccountCoinsResponse), args.Get(1).(*rTypes.Error)
+}
+
+type MockBlockAPIService struct {
+	mock.Mock
+}
+
+func (m *MockBlockAPIService) Block(ctx context.Context, request *rTypes.BlockRequest) (
+	*rTypes.BlockResponse,
+	*rTypes.Error,
+) {
+	args := m.Called(ctx, request)
+	return args.Get(0).(*rTypes.BlockResponse), args.Get(1).(*rTypes.Error)
+}
+
+func (m *MockBlockAPIService) BlockTransaction(ctx context.Context, request *rTypes.BlockTransactionRequest) (
+	*rTypes.BlockTransactionResponse,
+	*rTypes.Error,
+) {
+	args := m.Called(ctx, request)
+	return args.Get(0).(*rTypes.BlockTransactionResponse), args.Get(1).(*rTypes.Error)
+}
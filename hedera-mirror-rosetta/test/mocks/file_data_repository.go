@@ -0,0 +1,42 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package mocks
+
+import (
+	"context"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockFileDataRepository struct {
+	mock.Mock
+}
+
+func (m *MockFileDataRepository) GetLatestContent(ctx context.Context, fileId int64) ([]byte, *rTypes.Error) {
+	args := m.Called()
+	return args.Get(0).([]byte), args.Get(1).(*rTypes.Error)
+}
+
+func (m *MockFileDataRepository) GetLatestTimestamp(ctx context.Context, fileId int64) (int64, *rTypes.Error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Get(1).(*rTypes.Error)
+}
@@ -0,0 +1,62 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+package mocks
+
+import (
+	"context"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	NilEntityStake    *types.EntityStake
+	NilNodeStakes     *types.NodeStakes
+	NilStakingHistory *types.StakingHistory
+)
+
+type MockStakingRepository struct {
+	mock.Mock
+}
+
+func (m *MockStakingRepository) FindEntityStake(ctx context.Context, accountId int64) (
+	*types.EntityStake,
+	*rTypes.Error,
+) {
+	args := m.Called()
+	return args.Get(0).(*types.EntityStake), args.Get(1).(*rTypes.Error)
+}
+
+func (m *MockStakingRepository) FindNodeStakes(ctx context.Context, epochDay *int64) (
+	*types.NodeStakes,
+	*rTypes.Error,
+) {
+	args := m.Called()
+	return args.Get(0).(*types.NodeStakes), args.Get(1).(*rTypes.Error)
+}
+
+func (m *MockStakingRepository) FindStakingHistory(ctx context.Context, accountId int64, limit int) (
+	*types.StakingHistory,
+	*rTypes.Error,
+) {
+	args := m.Called()
+	return args.Get(0).(*types.StakingHistory), args.Get(1).(*rTypes.Error)
+}
@@ -0,0 +1,73 @@
+/*-
+ * ‌
+ * Hedera Mirror Node
+ * ​
+ * Copyright (C) 2019 - 2022 Hedera Hashgraph, LLC
+ * ​
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ‍
+ */
+
+package mocks
+
+import (
+	"context"
+
+	rTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/hashgraph/hedera-mirror-node/hedera-mirror-rosetta/app/domain/types"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	NilNft   *types.Nft
+	NilToken *types.Token
+)
+
+type MockTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockTokenRepository) Find(ctx context.Context, tokenId int64) (*types.Token, *rTypes.Error) {
+	args := m.Called()
+	return args.Get(0).(*types.Token), args.Get(1).(*rTypes.Error)
+}
+
+func (m *MockTokenRepository) FindNft(ctx context.Context, tokenId, serialNumber int64) (*types.Nft, *rTypes.Error) {
+	args := m.Called(tokenId, serialNumber)
+	return args.Get(0).(*types.Nft), args.Get(1).(*rTypes.Error)
+}
+
+func (m *MockTokenRepository) FindNfts(ctx context.Context, tokenId, afterSerialNumber int64, limit int) (
+	[]types.Nft,
+	*rTypes.Error,
+) {
+	args := m.Called(tokenId, afterSerialNumber, limit)
+	return args.Get(0).([]types.Nft), args.Get(1).(*rTypes.Error)
+}
+
+func (m *MockTokenRepository) FindPendingAirdrops(ctx context.Context, accountId int64, limit int) (
+	[]types.PendingAirdrop,
+	*rTypes.Error,
+) {
+	args := m.Called(accountId, limit)
+	return args.Get(0).([]types.PendingAirdrop), args.Get(1).(*rTypes.Error)
+}
+
+func (m *MockTokenRepository) FindHolders(
+	ctx context.Context,
+	tokenId, minBalance, afterAccountId int64,
+	limit int,
+) (int64, []types.TokenHolder, *rTypes.Error) {
+	args := m.Called(tokenId, minBalance, afterAccountId, limit)
+	return args.Get(0).(int64), args.Get(1).([]types.TokenHolder), args.Get(2).(*rTypes.Error)
+}
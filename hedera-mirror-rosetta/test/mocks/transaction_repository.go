@@ -28,12 +28,51 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
-var NilTransaction *types.Transaction
+var (
+	NilRawTransaction        *types.RawTransaction
+	NilTransaction           *types.Transaction
+	NilTransactionStatistics *types.TransactionStatistics
+)
 
 type MockTransactionRepository struct {
 	mock.Mock
 }
 
+func (m *MockTransactionRepository) CountBetween(ctx context.Context, start, end int64) (int64, int64, *rTypes.Error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Get(1).(int64), args.Get(2).(*rTypes.Error)
+}
+
+func (m *MockTransactionRepository) CountByTypeAndResult(ctx context.Context, start, end int64) (
+	*types.TransactionStatistics,
+	*rTypes.Error,
+) {
+	args := m.Called()
+	return args.Get(0).(*types.TransactionStatistics), args.Get(1).(*rTypes.Error)
+}
+
+func (m *MockTransactionRepository) CountOperationsBetween(ctx context.Context, start, end int64) (
+	int64,
+	*rTypes.Error,
+) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Get(1).(*rTypes.Error)
+}
+
+func (m *MockTransactionRepository) FindHashesBetween(ctx context.Context, start, end int64) ([]string, *rTypes.Error) {
+	args := m.Called()
+	return args.Get(0).([]string), args.Get(1).(*rTypes.Error)
+}
+
+func (m *MockTransactionRepository) FindKeysBySearch(ctx context.Context, search types.TransactionSearch) (
+	[]types.TransactionKey,
+	int64,
+	*rTypes.Error,
+) {
+	args := m.Called()
+	return args.Get(0).([]types.TransactionKey), args.Get(1).(int64), args.Get(2).(*rTypes.Error)
+}
+
 func (m *MockTransactionRepository) FindByHashInBlock(
 	ctx context.Context,
 	identifier string,
@@ -44,6 +83,16 @@ func (m *MockTransactionRepository) FindByHashInBlock(
 	return args.Get(0).(*types.Transaction), args.Get(1).(*rTypes.Error)
 }
 
+func (m *MockTransactionRepository) FindRawByHashInBlock(
+	ctx context.Context,
+	identifier string,
+	consensusStart int64,
+	consensusEnd int64,
+) (*types.RawTransaction, *rTypes.Error) {
+	args := m.Called()
+	return args.Get(0).(*types.RawTransaction), args.Get(1).(*rTypes.Error)
+}
+
 func (m *MockTransactionRepository) FindBetween(ctx context.Context, start, end int64) (
 	[]*types.Transaction,
 	*rTypes.Error,